@@ -0,0 +1,504 @@
+// cmd/admin/commands.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/backup"
+	"hashhedge/internal/config"
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/db"
+	"hashhedge/internal/db/migrations"
+	"hashhedge/internal/jobs"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/taproot"
+)
+
+// resyncBook asks the running server to rebuild its in-memory order book.
+// The book lives in the server process, so this goes through the admin API.
+func resyncBook(ctx context.Context, apiURL string) error {
+	url := strings.TrimRight(apiURL, "/") + "/api/v1/admin/orderbook/resync"
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build resync request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call resync endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode resync response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !body.Success {
+		return fmt.Errorf("resync failed with status %d: %s", resp.StatusCode, body.Error)
+	}
+
+	log.Info().Msg("Order book resynced")
+	return nil
+}
+
+// reverifySettlements recomputes the winner of settled contracts from chain
+// data and checks that the stored settlement transaction paid them
+func reverifySettlements(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("reverify-settlements", flag.ExitOnError)
+	sinceHeight := fs.Int64("since-height", 0, "Only check contracts ending at or above this block height")
+	fs.Parse(args)
+
+	if *sinceHeight <= 0 {
+		return errors.New("-since-height must be positive")
+	}
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	bitcoinClient, err := openBitcoin(cfg)
+	if err != nil {
+		return err
+	}
+	defer bitcoinClient.Close()
+
+	contractRepo := db.NewContractRepository(database)
+	payoutRepo := db.NewPayoutRepository(database)
+	contracts, err := contractRepo.ListSettledSince(ctx, *sinceHeight)
+	if err != nil {
+		return err
+	}
+
+	tipHeight, err := bitcoinClient.GetBlockCount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get block count: %w", err)
+	}
+
+	chainParams, err := cfg.Bitcoin.Params()
+	if err != nil {
+		return err
+	}
+
+	scriptBuilder := taproot.NewScriptBuilder().WithNetwork(chainParams)
+	mismatches := 0
+	for _, c := range contracts {
+		if err := verifySettlement(ctx, contractRepo, payoutRepo, bitcoinClient, scriptBuilder, c, tipHeight); err != nil {
+			mismatches++
+			log.Error().Err(err).Str("contractID", c.ID.String()).Msg("Settlement failed verification")
+		}
+	}
+
+	log.Info().
+		Int("checked", len(contracts)).
+		Int("mismatches", mismatches).
+		Msg("Settlement reverification finished")
+
+	if mismatches > 0 {
+		return fmt.Errorf("%d of %d settlements failed verification", mismatches, len(contracts))
+	}
+	return nil
+}
+
+// verifySettlement checks a single settled contract
+func verifySettlement(
+	ctx context.Context,
+	contractRepo *db.ContractRepository,
+	payoutRepo *db.PayoutRepository,
+	bitcoinClient *bitcoin.Client,
+	scriptBuilder *taproot.ScriptBuilder,
+	c *models.Contract,
+	tipHeight int64,
+) error {
+	if c.SettlementTxID == nil {
+		return errors.New("settled contract has no settlement transaction ID")
+	}
+
+	txs, err := contractRepo.GetTransactionsByContractID(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+
+	var settlementTx *models.ContractTransaction
+	for _, tx := range txs {
+		if tx.TxType == "settlement" && tx.TransactionID == *c.SettlementTxID {
+			settlementTx = tx
+			break
+		}
+	}
+	if settlementTx == nil {
+		return fmt.Errorf("settlement transaction %s not found", *c.SettlementTxID)
+	}
+
+	// The end height reached before the target time means high hash rate
+	endHeightFirst := false
+	if tipHeight >= c.EndBlockHeight {
+		blockHash, err := bitcoinClient.GetBlockHash(ctx, c.EndBlockHeight)
+		if err != nil {
+			return fmt.Errorf("failed to get block hash at height %d: %w", c.EndBlockHeight, err)
+		}
+		block, err := bitcoinClient.GetBlock(ctx, blockHash)
+		if err != nil {
+			return fmt.Errorf("failed to get block at height %d: %w", c.EndBlockHeight, err)
+		}
+		endHeightFirst = !block.Time.After(c.TargetTimestamp)
+	}
+
+	buyerWins := endHeightFirst == (c.ContractType == models.ContractTypeCall)
+	winnerPubKey := c.SellerPubKey
+	if buyerWins {
+		winnerPubKey = c.BuyerPubKey
+	}
+
+	// Settlements pay the address the winner chose, if any
+	payoutAddress, err := payoutRepo.GetContractAddress(ctx, c.ID, winnerPubKey)
+	if err != nil {
+		return fmt.Errorf("failed to get payout address: %w", err)
+	}
+
+	address, err := scriptBuilder.BuildSettlementScript(winnerPubKey, payoutAddress)
+	if err != nil {
+		return fmt.Errorf("failed to build settlement script: %w", err)
+	}
+	addr, err := btcutil.DecodeAddress(address, scriptBuilder.Params())
+	if err != nil {
+		return fmt.Errorf("failed to decode settlement address: %w", err)
+	}
+	expectedScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return fmt.Errorf("failed to create settlement output script: %w", err)
+	}
+
+	raw, err := hex.DecodeString(settlementTx.TxHex)
+	if err != nil {
+		return fmt.Errorf("failed to decode settlement transaction: %w", err)
+	}
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("failed to deserialize settlement transaction: %w", err)
+	}
+
+	for _, out := range msgTx.TxOut {
+		if bytes.Equal(out.PkScript, expectedScript) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("settlement does not pay the expected winner (buyer wins: %t)", buyerWins)
+}
+
+// requeueBroadcasts queues a broadcast job for every unconfirmed contract transaction
+func requeueBroadcasts(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("requeue-broadcasts", flag.ExitOnError)
+	txType := fs.String("type", "", "Only requeue transactions of this type (setup, final, settlement, swap)")
+	dryRun := fs.Bool("dry-run", false, "List the transactions without queueing jobs")
+	fs.Parse(args)
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	contractRepo := db.NewContractRepository(database)
+	runner := jobs.NewRunner(db.NewJobRepository(database), cfg.Jobs)
+
+	txs, err := contractRepo.ListUnconfirmedTransactions(ctx)
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, tx := range txs {
+		if *txType != "" && tx.TxType != *txType {
+			continue
+		}
+
+		if *dryRun {
+			fmt.Printf("%s\t%s\t%s\n", tx.ContractID, tx.TxType, tx.TransactionID)
+			continue
+		}
+
+		job, err := runner.Enqueue(ctx, jobs.TypeBroadcastTx, jobs.BroadcastPayload{
+			ContractID:    tx.ContractID,
+			TransactionID: tx.ID,
+		}, time.Time{})
+		if err != nil {
+			return err
+		}
+		queued++
+		log.Info().
+			Str("contractID", tx.ContractID.String()).
+			Str("txID", tx.TransactionID).
+			Str("jobID", job.ID.String()).
+			Msg("Queued broadcast job")
+	}
+
+	log.Info().Int("queued", queued).Bool("dry_run", *dryRun).Msg("Broadcast requeue finished")
+	return nil
+}
+
+// recomputeHashRateSamples recomputes hash rate samples over fixed block windows
+// and writes them to stdout as CSV
+func recomputeHashRateSamples(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("recompute-hashrate-samples", flag.ExitOnError)
+	from := fs.Int64("from", 0, "First block height")
+	to := fs.Int64("to", 0, "Last block height (defaults to the chain tip)")
+	window := fs.Int64("window", 144, "Blocks per sample")
+	fs.Parse(args)
+
+	if *from <= 0 {
+		return errors.New("-from must be positive")
+	}
+	if *window <= 0 {
+		return errors.New("-window must be positive")
+	}
+
+	bitcoinClient, err := openBitcoin(cfg)
+	if err != nil {
+		return err
+	}
+	defer bitcoinClient.Close()
+
+	if *to == 0 {
+		*to, err = bitcoinClient.GetBlockCount(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block count: %w", err)
+		}
+	}
+	if *to <= *from {
+		return errors.New("-to must be greater than -from")
+	}
+
+	calculator := hashrate.New(bitcoinClient)
+
+	fmt.Fprintln(os.Stdout, "start_height,end_height,hash_rate_ehs")
+	for start := *from; start < *to; start += *window {
+		end := start + *window
+		if end > *to {
+			end = *to
+		}
+
+		rate, err := calculator.CalculateHashRateForPeriod(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to calculate hash rate for %d-%d: %w", start, end, err)
+		}
+		fmt.Fprintf(os.Stdout, "%d,%d,%.4f\n", start, end, rate)
+	}
+
+	return nil
+}
+
+// exportState writes an encrypted recovery archive of the contract state
+func exportState(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export-state", flag.ExitOnError)
+	out := fs.String("out", "", "Archive path (defaults to a timestamped file in the configured backup directory)")
+	fs.Parse(args)
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	backupCfg := cfg.Backup
+	if *out == "" && backupCfg.Dir == "" {
+		return errors.New("-out is required when no backup directory is configured")
+	}
+
+	exporter, err := backup.NewExporter(db.NewSnapshotRepository(database), backupCfg)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		path, err := exporter.ExportToDir(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+
+	snapshot, err := exporter.Export(ctx, f)
+	if err != nil {
+		os.Remove(*out)
+		return err
+	}
+
+	log.Info().
+		Str("path", *out).
+		Int("contracts", len(snapshot.Contracts)).
+		Int("transactions", len(snapshot.Transactions)).
+		Msg("Recovery archive written")
+	return nil
+}
+
+// importState restores contract state from a recovery archive. Existing rows are kept.
+func importState(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import-state", flag.ExitOnError)
+	in := fs.String("in", "", "Archive path")
+	fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	key, err := backup.ParseKey(cfg.Backup.Key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	snapshot, counts, err := backup.Restore(ctx, db.NewSnapshotRepository(database), f, key)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Time("taken_at", snapshot.TakenAt).
+		Int64("contracts", counts.Contracts).
+		Int64("transactions", counts.Transactions).
+		Int64("funding", counts.Funding).
+		Msg("Recovery archive restored")
+	return nil
+}
+
+// showClaims decrypts a recovery archive and prints the per-contract claim data as JSON
+func showClaims(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("show-claims", flag.ExitOnError)
+	in := fs.String("in", "", "Archive path")
+	pubKey := fs.String("pub-key", "", "Only show contracts where this key is a party")
+	fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	key, err := backup.ParseKey(cfg.Backup.Key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	snapshot, err := backup.Open(f, key)
+	if err != nil {
+		return err
+	}
+
+	claims := snapshot.Claims
+	if *pubKey != "" {
+		claims = nil
+		for _, claim := range snapshot.Claims {
+			if claim.BuyerPubKey == *pubKey || claim.SellerPubKey == *pubKey {
+				claims = append(claims, claim)
+			}
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(claims)
+}
+
+// migrate applies or reverts the embedded schema migrations. Its first
+// argument is up, down, status or baseline.
+func migrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) < 1 {
+		return errors.New("migrate needs one of up, down, status or baseline")
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	to := fs.Int("to", 0, "Version to migrate up to, the latest by default (up)")
+	steps := fs.Int("steps", 1, "Number of migrations to revert (down)")
+	version := fs.Int("version", 0, "Version the existing schema is already at (baseline)")
+	fs.Parse(args[1:])
+
+	database, err := openDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	migrator, err := migrations.New(database.DB)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx, *to)
+		for _, m := range applied {
+			fmt.Printf("applied\t%06d\t%s\n", m.Version, m.Name)
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Printf("reverted\t%06d\t%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		current, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("current\t%06d\nlatest\t%06d\n", current, migrator.Latest())
+		return nil
+	case "baseline":
+		if err := migrator.Baseline(ctx, *version); err != nil {
+			return err
+		}
+		log.Info().Int("version", *version).Msg("Schema baselined")
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate action: %s", action)
+	}
+}
@@ -0,0 +1,113 @@
+// cmd/admin/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/config"
+	"hashhedge/internal/db"
+	"hashhedge/internal/netproxy"
+	"hashhedge/pkg/bitcoin"
+)
+
+const usageText = `Usage: admin [flags] <command> [command flags]
+
+Commands:
+  migrate up|down|status|baseline
+                              Apply (-to) or revert (-steps) schema migrations, or mark an
+                              existing schema as already at a version (-version)
+  resync-book                 Rebuild the running server's in-memory order book from the database
+  reverify-settlements        Re-check settled contracts against the chain (-since-height)
+  requeue-broadcasts          Queue broadcast jobs for unconfirmed contract transactions
+  recompute-hashrate-samples  Recompute hash rate samples over a block range (-from, -to, -window)
+  export-state                Write an encrypted recovery archive of the contract state (-out)
+  import-state                Restore contract state from a recovery archive (-in)
+  show-claims                 Print the claim data of a recovery archive (-in, -pub-key)
+
+Flags:
+`
+
+func main() {
+	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	apiURL := flag.String("api", "http://localhost:8080", "Base URL of the running server")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usageText)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339})
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load configuration")
+	}
+
+	ctx := context.Background()
+	command, args := flag.Arg(0), flag.Args()[1:]
+
+	switch command {
+	case "migrate":
+		err = migrate(ctx, cfg, args)
+	case "resync-book":
+		err = resyncBook(ctx, *apiURL)
+	case "reverify-settlements":
+		err = reverifySettlements(ctx, cfg, args)
+	case "requeue-broadcasts":
+		err = requeueBroadcasts(ctx, cfg, args)
+	case "recompute-hashrate-samples":
+		err = recomputeHashRateSamples(ctx, cfg, args)
+	case "export-state":
+		err = exportState(ctx, cfg, args)
+	case "import-state":
+		err = importState(ctx, cfg, args)
+	case "show-claims":
+		err = showClaims(cfg, args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal().Err(err).Str("command", command).Msg("Command failed")
+	}
+}
+
+// openDatabase connects to the database from the loaded configuration
+func openDatabase(cfg *config.Config) (*db.DB, error) {
+	return db.New(db.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DBName,
+		SSLMode:  cfg.Database.SSLMode,
+	})
+}
+
+// openBitcoin connects to the Bitcoin node from the loaded configuration
+func openBitcoin(cfg *config.Config) (*bitcoin.Client, error) {
+	client, err := bitcoin.NewPooledClient(
+		cfg.Bitcoin.Nodes(),
+		cfg.Bitcoin.Pool(),
+		cfg.Proxy.For(netproxy.Bitcoin),
+	)
+	if err != nil {
+		return nil, err
+	}
+	client.SetBroadcastRetry(cfg.Retry.BitcoinBroadcast)
+	return client, nil
+}
@@ -3,26 +3,66 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	
+	"hashhedge/internal/alerts"
+	"hashhedge/internal/auth"
+	"hashhedge/internal/backup"
 	"hashhedge/internal/config"
 	"hashhedge/internal/contract"
+	"hashhedge/internal/coordination"
 	"hashhedge/internal/contract/hashrate"
 	"hashhedge/internal/db"
+	"hashhedge/internal/db/migrations"
+	"hashhedge/internal/events"
+	"hashhedge/internal/feeds"
+	"hashhedge/internal/jobs"
+	"hashhedge/internal/ledger"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/margin"
+	"hashhedge/internal/marketdata"
+	"hashhedge/internal/marketplace"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+	"hashhedge/internal/netproxy"
+	"hashhedge/internal/notifications"
 	"hashhedge/internal/orderbook"
+	"hashhedge/internal/positions"
+	"hashhedge/internal/pricing"
+	"hashhedge/internal/privacy"
+	"hashhedge/internal/push"
+	"hashhedge/internal/ratelimit"
+	"hashhedge/internal/research"
+	"hashhedge/internal/rollover"
 	"hashhedge/internal/server"
+	"hashhedge/internal/settlement"
+	"hashhedge/internal/timestamping"
+	"hashhedge/internal/tracing"
+	"hashhedge/internal/tradereport"
+	"hashhedge/internal/usage"
+	"hashhedge/internal/wallet"
+	"hashhedge/internal/watchtower"
+	"hashhedge/internal/webhooks"
+	"hashhedge/internal/websocket"
+	"hashhedge/pkg/ark"
 	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/priceindex"
 	"hashhedge/pkg/taproot"
 )
 
 func main() {
 	// Parse command-line flags
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	migrate := flag.Bool("migrate", false, "Apply pending schema migrations before starting")
 	flag.Parse()
 
 	// Configure logging
@@ -34,39 +74,126 @@ func main() {
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 	
+	// Apply the default and per-component log levels
+	if err := logging.Setup(cfg.Logging, zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure logging")
+	}
+	
+	// Trace requests through matching, the database, the Bitcoin node and
+	// the ASP, exporting spans to the collector when one is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up tracing")
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Msg("Failed to flush traces")
+		}
+	}()
+	
 	// Create database connection
 	database, err := db.New(cfg.Database)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to connect to database")
 	}
 	
+	// Refuse to run against a schema older or newer than this build
+	migrator, err := migrations.New(database.DB)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load schema migrations")
+	}
+	if *migrate {
+		applied, err := migrator.Up(context.Background(), 0)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to apply schema migrations")
+		}
+		log.Info().Int("applied", len(applied)).Msg("Schema migrations applied")
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatal().Err(err).Msg("Database schema does not match this build; run admin migrate up or start with -migrate")
+	}
+	
+	// Route outbound connections through the configured proxies
+	log.Info().Str("proxies", cfg.Proxy.Describe()).Msg("Outbound connections")
+	
 	// Create Bitcoin client
-	bitcoinClient, err := bitcoin.NewClient(
-		cfg.Bitcoin.Host,
-		cfg.Bitcoin.User,
-		cfg.Bitcoin.Password,
-		cfg.Bitcoin.UseTLS,
+	bitcoinClient, err := bitcoin.NewPooledClient(
+		cfg.Bitcoin.Nodes(),
+		cfg.Bitcoin.Pool(),
+		cfg.Proxy.For(netproxy.Bitcoin),
 	)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create Bitcoin client")
 	}
 	defer bitcoinClient.Close()
+	bitcoinClient.SetBroadcastRetry(cfg.Retry.BitcoinBroadcast)
 	
 	// Create repositories
 	contractRepo := db.NewContractRepository(database)
 	orderRepo := db.NewOrderRepository(database)
 	tradeRepo := db.NewTradeRepository(database)
 	userRepo := db.NewUserRepository(database)
+	jobRepo := db.NewJobRepository(database)
+	watchlistRepo := db.NewWatchlistRepository(database)
+	priceAlertRepo := db.NewPriceAlertRepository(database)
+	fundingRepo := db.NewFundingRepository(database)
+	inputRepo := db.NewContractInputRepository(database)
+	feedRepo := db.NewFeedRepository(database)
+	deviceRepo := db.NewDeviceRepository(database)
+	apiKeyRepo := db.NewAPIKeyRepository(database)
+	usageRepo := db.NewUsageRepository(database)
+	rolloverRepo := db.NewRolloverRepository(database)
+	snapshotRepo := db.NewSnapshotRepository(database)
+	deferralRepo := db.NewSettlementDeferralRepository(database)
+	payoutRepo := db.NewPayoutRepository(database)
+	openInterestRepo := db.NewOpenInterestRepository(database)
+	hashRateRepo := db.NewHashRateRepository(database)
+	scheduledCloseRepo := db.NewScheduledCloseRepository(database)
+	evidenceRepo := db.NewSettlementEvidenceRepository(database)
+	watchtowerRepo := db.NewWatchtowerRepository(database)
+	settlementAttemptRepo := db.NewSettlementAttemptRepository(database)
+	protectionRepo := db.NewMarketMakerProtectionRepository(database)
+	positionRepo := db.NewPositionRepository(database)
+	vtxoRepo := db.NewVTXORepository(database)
+	auditRepo := db.NewAuditRepository(database)
+	oracleEventRepo := db.NewOracleEventRepository(database)
+	marginRepo := db.NewMarginRepository(database)
+	webhookRepo := db.NewWebhookRepository(database)
+	notificationRepo := db.NewNotificationRepository(database)
+	ledgerRepo := db.NewLedgerRepository(database)
+
+	// Report contracts by status to Prometheus
+	if err := metrics.RegisterContractCounter(contractRepo.CountByStatus); err != nil {
+		log.Fatal().Err(err).Msg("Failed to register contract metrics")
+	}
 	
 	// Create services
 	hashRateCalculator := hashrate.New(bitcoinClient)
-	taprootScriptBuilder := taproot.NewScriptBuilder()
+	chainParams, err := cfg.Bitcoin.Params()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid Bitcoin network")
+	}
+	taprootScriptBuilder := taproot.NewScriptBuilder().WithNetwork(chainParams)
+	
+	// Route ASP calls to the primary ASP, failing over to the next healthy
+	// one when it stops answering
+	aspManager, err := ark.NewManager(cfg.ArkASP.Endpoints(cfg.Proxy.For(netproxy.ArkASP)), ark.ManagerOptions{
+		CheckInterval: cfg.ArkASP.CheckInterval,
+		DownAfter:     cfg.ArkASP.MigrateAfter,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create ASP manager")
+	}
+	aspManager.SetRetryConfig(cfg.Retry.ASP, cfg.Retry.ASPStream)
 	
 	contractService := contract.NewService(
 		contractRepo,
 		hashRateCalculator,
 		bitcoinClient,
 		taprootScriptBuilder,
+		aspManager,
 	)
 	
 	orderBook := orderbook.NewOrderBook(
@@ -76,14 +203,407 @@ func main() {
 		contractRepo,
 		contractService,
 	)
+	orderBook.SetConfig(cfg.OrderBook)
 	
 	// Start the order book background tasks
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	
+	// Check the Bitcoin nodes, closing the circuit of any that recovered
+	bitcoinClient.StartHealthChecks(ctx, cfg.Bitcoin.HealthCheckInterval)
+	
+	// Connect to the ASPs, migrating contracts off a primary that is down
+	// for good
+	aspManager.WithMigrator(contractService).Start(ctx)
+	
+	// Reload the retry policies from the configuration file on SIGHUP,
+	// keeping the current ones if the file no longer validates
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(reload)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				reloaded, err := config.Load(*configPath)
+				if err != nil {
+					log.Error().Err(err).Msg("Failed to reload configuration, keeping the current retry policies")
+					continue
+				}
+				bitcoinClient.SetBroadcastRetry(reloaded.Retry.BitcoinBroadcast)
+				aspManager.SetRetryConfig(reloaded.Retry.ASP, reloaded.Retry.ASPStream)
+				log.Info().Msg("Retry policies reloaded")
+			}
+		}
+	}()
+	
+	// Instances sharing the database each match the markets whose advisory
+	// lock they hold and serve reads for the rest
+	if cfg.Coordination.Enabled() {
+		marketLocks := coordination.NewAdvisoryLocks(database, cfg.Coordination.Matcher)
+		marketLocks.Start(ctx, cfg.Coordination.CheckInterval)
+		orderBook.SetCoordinator(marketLocks)
+	}
 	orderBook.Start(ctx)
 	
+	// Services publish trades, order changes, contract lifecycle events,
+	// hash rate samples and ASP status on the event bus, and each consumer
+	// subscribes on its own
+	eventBus := events.NewBus()
+	defer eventBus.Close()
+	orderBook.SetEventBus(eventBus)
+	contractService.WithEventBus(eventBus)
+	
+	// Record every contract status change for audit
+	contractService.WithTransitionHistory(db.NewContractTransitionRepository(database))
+	
+	// Start the WebSocket server and feed it the events of the bus
+	wsServer := websocket.NewWebSocketServer()
+	go wsServer.Run(ctx)
+	websocket.SetupWebSocketIntegration(eventBus, wsServer)
+	
+	// Track per-API-key request and websocket usage
+	usageTracker := usage.NewTracker(usageRepo, cfg.Usage)
+	usageTracker.Start(ctx)
+	wsServer.SetBandwidthMeter(usageTracker)
+
+	// Limit each client's request rate so no one starves the order book
+	var rateLimiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = ratelimit.NewLimiter(cfg.RateLimit)
+		rateLimiter.Start(ctx)
+	}
+
+	// Pull the quotes of API keys past their fill limits and cancel flagged
+	// orders when an API key's last websocket session drops
+	protections, err := protectionRepo.ListActive(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load market maker protections")
+	}
+	for _, protection := range protections {
+		orderBook.SetProtection(protection)
+	}
+	wsServer.SetSessionObserver(orderBook)
+	
+	// Track funding progress and stream it to both parties
+	contractService.WithFundingStore(fundingRepo).WithPublisher(wsServer)
+
+	// Report contract transactions as they confirm
+	contractService.WithConfirmationWatcher(contractRepo, bitcoinClient, cfg.Confirmations)
+	contractService.StartConfirmationWatcher(ctx)
+
+	// Hold settled contracts in PENDING_SETTLEMENT until their payout
+	// confirms, rebroadcasting payouts the node loses
+	contractService.WithSettlementTracking(db.NewSettlementBroadcastRepository(database), cfg.SettlementTracking)
+	contractService.StartSettlementTracker(ctx)
+
+	// Check transactions before they are broadcast, refusing those with
+	// unexpected outputs, fees or scripts
+	contractService.WithBroadcastChecks(cfg.BroadcastChecks)
+
+	// Sweep contracts funded by several UTXOs or VTXOs in one transaction
+	contractService.WithInputStore(inputRepo, bitcoinClient)
+	
+	// Track the VTXO holding each contract's funds for exits and swaps
+	contractService.WithVTXOStore(vtxoRepo)
+
+	// Tell the parties to a contract apart by their registered keys
+	contractService.WithUserKeys(userRepo)
+	
+	// Pay winners at their chosen payout address
+	contractService.WithPayoutStore(payoutRepo)
+	
+	// Track open interest per market and enforce its risk limit
+	contractService.WithOpenInterestStore(openInterestRepo)
+	orderBook.SetOpenInterestSource(openInterestRepo)
+	
+	// Evaluate price alerts on every market change
+	webhookClient, err := netproxy.HTTPClient(cfg.Proxy.For(netproxy.Webhooks), 10*time.Second)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create webhook client")
+	}
+	alertService := alerts.NewService(priceAlertRepo, cfg.Alerts.MaxPerUser).
+		WithDeliverer(models.AlertChannelWebsocket, alerts.NewWebsocketDeliverer(wsServer)).
+		WithDeliverer(models.AlertChannelWebhook, alerts.NewWebhookDeliverer(10*time.Second).WithHTTPClient(webhookClient))
+	if cfg.Alerts.SMTP.Host != "" {
+		alertService.WithDeliverer(models.AlertChannelEmail, alerts.NewEmailDeliverer(cfg.Alerts.SMTP))
+	}
+	
+	// Measure spread, imbalance and depth on every market change and stream
+	// them on each market's liquidity channel
+	liquidityMonitor := marketdata.NewLiquidityMonitor(orderBook, wsServer, cfg.Market.Liquidity)
+
+	// Stream each market's order book as a snapshot on subscribe followed by
+	// price level deltas
+	bookStream := websocket.NewBookStream(orderBook, wsServer)
+	orderBook.SetMarketObserver(orderbook.MarketObservers{bookStream, liquidityMonitor, alertService})
+
+	// Keep each market's best bid and offer, last price and 24 hour trading
+	// in step with matching and stream them on the ticker channel
+	tickerBook := marketdata.NewTickerBook(wsServer)
+	orderBook.SetTickerRecorder(tickerBook)
+	wsServer.SetSnapshotter(websocket.Snapshotters{bookStream, tickerBook})
+	
+	// Let writers margin contracts instead of funding their full size,
+	// calling and liquidating positions the hash rate moves against
+	marginEngine := margin.NewEngine(marginRepo, contractService, hashRateCalculator, wsServer, cfg.Margin)
+	contractService.WithCollateralPolicy(marginEngine)
+	marginEngine.Start(ctx)
+	
+	// Push fill and settlement notifications to registered mobile devices
+	pushService := push.NewService(deviceRepo)
+	if cfg.Push.APNs.Enabled() {
+		apnsSender, err := push.NewAPNsSender(cfg.Push.APNs)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create APNs sender")
+		}
+		pushService.WithSender(models.PushPlatformAPNs, apnsSender)
+	}
+	if cfg.Push.FCM.Enabled() {
+		fcmSender, err := push.NewFCMSender(cfg.Push.FCM)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create FCM sender")
+		}
+		pushService.WithSender(models.PushPlatformFCM, fcmSender)
+	}
+	
+	// Deliver signed trade, fill and settlement events to users' webhooks
+	webhookService := webhooks.NewService(webhookRepo, cfg.Webhooks).WithHTTPClient(webhookClient)
+	webhookService.Start(ctx)
+	
+	// Keep fill, activation, settlement, ASP outage and margin call
+	// notifications until users read them, pushing each to the user's
+	// notifications channel
+	notificationService := notifications.NewService(notificationRepo).WithPublisher(wsServer)
+	notificationService.Subscribe(ctx, eventBus)
+	marginEngine.WithObserver(notificationService)
+
+	settlementObservers := contract.SettlementObservers{pushService, webhookService, marginEngine, notificationService}
+	
+	// Book premiums, collateral and payouts against users' deposits and
+	// reject orders their funds do not cover
+	var ledgerService *ledger.Service
+	if cfg.Ledger.Enabled {
+		ledgerService = ledger.NewService(ledgerRepo)
+		orderBook.SetLedger(ledgerService)
+		settlementObservers = append(settlementObservers, ledgerService)
+	}
+	
+	// Give users taproot deposit addresses and credit their funding to the
+	// ledger once confirmed
+	var walletService *wallet.Service
+	if cfg.Wallet.Enabled() {
+		walletService, err = wallet.NewService(db.NewDepositRepository(database), bitcoinClient, ledgerService, chainParams, cfg.Wallet)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create wallet service")
+		}
+		walletService.Start(ctx)
+	}
+	
+	// Pay out withdrawals from the node's wallet, or over Ark while the ASP
+	// is reachable
+	var withdrawals *wallet.Withdrawals
+	if cfg.Wallet.Withdrawals.Enabled {
+		withdrawals = wallet.NewWithdrawals(db.NewWithdrawalRepository(database), ledgerService, bitcoinClient, chainParams, cfg.Wallet.Withdrawals)
+		withdrawals.WithASP(aspManager)
+	}
+
+	orderBook.SetFillObserver(orderbook.FillObservers{pushService, webhookService, notificationService})
+	contractService.WithSettlementObserver(settlementObservers)
+	
+	// Price settlements with the node's fee estimates, asking the fee API
+	// when the node has none, and defer non-urgent settlements while chain
+	// fees are high
+	feeEstimator := bitcoin.FallbackFeeEstimator{bitcoinClient}
+	if cfg.FeePolicy.FeeAPIURL != "" {
+		feeAPIClient, err := netproxy.HTTPClient(cfg.Proxy.For(netproxy.FeeAPI), 10*time.Second)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create fee API client")
+		}
+		feeEstimator = append(feeEstimator, bitcoin.NewAPIFeeEstimator(cfg.FeePolicy.FeeAPIURL).WithHTTPClient(feeAPIClient))
+	}
+	contractService.WithFeePolicy(cfg.FeePolicy, feeEstimator, deferralRepo).
+		WithDeferralObserver(pushService)
+	contractService.StartDeferredSettlements(ctx)
+	
+	// Execute cooperative closes both parties scheduled in advance
+	contractService.WithScheduledCloseStore(scheduledCloseRepo, cfg.ScheduledClose)
+	contractService.StartScheduledCloses(ctx)
+	contractService.WithExitMonitor(cfg.ExitMonitor)
+	contractService.WithOracle(oracleEventRepo, cfg.Oracle)
+	contractService.StartExitMonitor(ctx)
+	
+	// Record the chain state each settlement was decided on and anchor it in
+	// Bitcoin so the decision can later be shown to be untampered
+	contractService.WithEvidenceStore(evidenceRepo)
+	var anchorer *timestamping.Anchorer
+	if cfg.Timestamping.Enabled() {
+		anchorer = timestamping.NewAnchorer(evidenceRepo, bitcoinClient, cfg.Timestamping)
+		anchorer.Start(ctx)
+	}
+	
+	// Settle contracts automatically once they reach their end height or
+	// target time, recording the outcome of every attempt
+	settlementOracle := settlement.NewOracle(contractService, settlementAttemptRepo, cfg.Settlement)
+	settlementOracle.Start(ctx)
+	
+	// Let participants delegate their emergency exits to third-party watchtowers
+	watchtowerService := watchtower.NewService(watchtowerRepo, contractRepo, userRepo)
+	
+	// Start the background job runner
+	jobRunner := jobs.NewRunner(jobRepo, cfg.Jobs)
+	jobRunner.Register(jobs.TypeSettleContract, func(ctx context.Context, payload json.RawMessage) error {
+		p, err := jobs.DecodeContractPayload(payload)
+		if err != nil {
+			return err
+		}
+		_, _, err = contractService.SettleContract(ctx, p.ContractID)
+		if errors.Is(err, contract.ErrSettlementDeferred) {
+			// Released by the fee policy once fees fall
+			return nil
+		}
+		return err
+	})
+	jobRunner.Register(jobs.TypeExpireContract, func(ctx context.Context, payload json.RawMessage) error {
+		p, err := jobs.DecodeContractPayload(payload)
+		if err != nil {
+			return err
+		}
+		return contractService.ExpireContract(ctx, p.ContractID)
+	})
+	jobRunner.Register(jobs.TypeBroadcastTx, func(ctx context.Context, payload json.RawMessage) error {
+		p, err := jobs.DecodeBroadcastPayload(payload)
+		if err != nil {
+			return err
+		}
+		_, err = contractService.BroadcastTransaction(ctx, p.ContractID, p.TransactionID)
+		return err
+	})
+	jobRunner.Start(ctx)
+	
+	// Roll auto-roll orders into the next expiry once their market settles
+	rolloverScheduler := rollover.NewScheduler(rolloverRepo, orderBook, contractService, cfg.Rollover)
+	rolloverScheduler.Start(ctx)
+	
+	// Periodically write encrypted recovery archives of the contract state
+	if cfg.Backup.Enabled() {
+		exporter, err := backup.NewExporter(snapshotRepo, cfg.Backup)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create recovery exporter")
+		}
+		exporter.Start(ctx)
+	}
+	
+	// Sample the configured external data feeds
+	feedSampler, err := feeds.NewSampler(feedRepo, cfg.Feeds, feeds.Deps{Bitcoin: bitcoinClient})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create data feeds")
+	}
+	feedSampler.Start(ctx)
+	
+	// Record the hash rate index at each new chain tip
+	hashrate.NewSampler(hashRateCalculator, hashRateRepo, cfg.HashRate).WithEventBus(eventBus).Start(ctx)
+	
+	// Hide counterparties in public market data
+	anonymizer, err := privacy.NewAnonymizer(cfg.Privacy)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create anonymizer")
+	}
+	
+	// Serve anonymized trades, book snapshots and settlements to researchers
+	researchRepo := db.NewResearchRepository(database)
+	researchFeed := research.NewService(researchRepo, anonymizer, cfg.Research)
+	if cfg.Research.SnapshotInterval > 0 {
+		research.NewSnapshotter(orderBook, researchRepo, cfg.Research).Start(ctx)
+	}
+	var researchDumps *research.Dumper
+	if cfg.Research.DumpsEnabled() {
+		researchDumps = research.NewDumper(researchFeed, cfg.Research)
+		researchDumps.Start(ctx)
+	}
+	
+	// Issue and verify user access tokens
+	authService, err := auth.NewService(userRepo, cfg.Auth)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create auth service")
+	}
+	
 	// Create HTTP handler
-	handler := server.NewHandler(contractService, orderBook)
+	handler := server.NewHandler(contractService, orderBook, userRepo).
+		WithJobRunner(jobRunner).
+		WithWatchlistRepo(watchlistRepo).
+		WithAlertService(alertService).
+		WithWebSocketServer(ctx, wsServer).
+		WithFeeds(feedSampler, feedRepo).
+		WithPushService(pushService).
+		WithWebhooks(webhookService).
+		WithUsageTracking(usageTracker, apiKeyRepo).
+		WithRateLimiter(rateLimiter).
+		WithRolloverScheduler(rolloverScheduler).
+		WithAnonymizer(anonymizer).
+		WithAuth(authService).
+		WithSchedule(cfg.Schedule()).
+		WithReconciliation(snapshotRepo).
+		WithMarketData(cfg.Market).
+		WithHashRateIndex(hashRateCalculator).
+		WithLiquidityMonitor(liquidityMonitor).
+		WithTickerBook(tickerBook).
+		WithTimestamping(anchorer).
+		WithWatchtowers(watchtowerService).
+		WithSettlementAttempts(settlementAttemptRepo).
+		WithAuditLog(auditRepo).
+		WithMargin(marginEngine).
+		WithMarketplace(marketplace.NewService(db.NewListingRepository(database), contractService, userRepo, marginRepo)).
+		WithMarketMakerProtection(protectionRepo).
+		WithResearchFeed(researchFeed, researchDumps).
+		WithTradeHistory(tradeRepo).
+		WithPositions(positions.NewService(positionRepo, hashRateCalculator)).
+		WithPricing(pricing.NewService(hashRateCalculator)).
+		WithNotifications(notificationService).
+		WithLedger(ledgerService).
+		WithWallet(walletService).
+		WithWithdrawals(withdrawals).
+		WithBitcoinHealth(bitcoinClient).
+		WithASPManager(aspManager)
+	
+	// Export users' trades and payouts for accounting, converted to fiat
+	// at the price API's daily prices when one is configured
+	tradeReports := tradereport.NewExporter(positionRepo)
+	if cfg.TradeReports.PriceAPIURL != "" {
+		priceAPIClient, err := netproxy.HTTPClient(cfg.Proxy.For(netproxy.PriceAPI), 10*time.Second)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create price API client")
+		}
+		tradeReports.WithPriceSource(
+			tradereport.NewAPIPriceSource(cfg.TradeReports.PriceAPIURL).WithHTTPClient(priceAPIClient),
+			cfg.TradeReports.Currency)
+	}
+	handler.WithTradeReports(tradeReports)
+	
+	// Show fiat notionals beside satoshi amounts, priced from the median of
+	// several exchanges
+	if cfg.PriceIndex.Enabled() {
+		priceIndexClient, err := netproxy.HTTPClient(cfg.Proxy.For(netproxy.PriceAPI), 10*time.Second)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create price index client")
+		}
+		var sources []priceindex.Source
+		for _, name := range cfg.PriceIndex.Sources {
+			source, err := priceindex.NewSource(name, priceIndexClient)
+			if err != nil {
+				log.Fatal().Err(err).Msg("Failed to create price source")
+			}
+			sources = append(sources, source)
+		}
+		pairs, err := cfg.PriceIndex.ParsePairs()
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid price index pairs")
+		}
+		handler.WithPriceIndex(priceindex.New(sources, cfg.PriceIndex.Options()), pairs)
+	}
+	
 	router := server.NewRouter(handler)
 	
 	// Create and start HTTP server
@@ -0,0 +1,126 @@
+// internal/alerts/deliverers.go
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// ChannelPublisher publishes a message to subscribers of a websocket channel
+type ChannelPublisher interface {
+	PublishToChannel(channel string, message interface{})
+}
+
+// WebsocketDeliverer pushes alerts to the user's "alerts:{userID}" websocket channel
+type WebsocketDeliverer struct {
+	publisher ChannelPublisher
+}
+
+// NewWebsocketDeliverer creates a new websocket deliverer
+func NewWebsocketDeliverer(publisher ChannelPublisher) *WebsocketDeliverer {
+	return &WebsocketDeliverer{publisher: publisher}
+}
+
+// Deliver publishes the triggered alert
+func (d *WebsocketDeliverer) Deliver(ctx context.Context, t Triggered) error {
+	d.publisher.PublishToChannel("alerts:"+t.Alert.UserID.String(), map[string]interface{}{
+		"type":    "price_alert",
+		"payload": t,
+	})
+	return nil
+}
+
+// WebhookDeliverer posts alerts as JSON to the alert's target URL
+type WebhookDeliverer struct {
+	client *http.Client
+}
+
+// NewWebhookDeliverer creates a new webhook deliverer
+func NewWebhookDeliverer(timeout time.Duration) *WebhookDeliverer {
+	return &WebhookDeliverer{client: &http.Client{Timeout: timeout}}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. with one connecting through
+// a proxy
+func (d *WebhookDeliverer) WithHTTPClient(client *http.Client) *WebhookDeliverer {
+	d.client = client
+	return d
+}
+
+// Deliver posts the triggered alert
+func (d *WebhookDeliverer) Deliver(ctx context.Context, t Triggered) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Alert.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SMTPConfig holds the outgoing mail server configuration
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// EmailDeliverer mails alerts to the alert's target address
+type EmailDeliverer struct {
+	cfg SMTPConfig
+}
+
+// NewEmailDeliverer creates a new email deliverer
+func NewEmailDeliverer(cfg SMTPConfig) *EmailDeliverer {
+	return &EmailDeliverer{cfg: cfg}
+}
+
+// Deliver mails the triggered alert
+func (d *EmailDeliverer) Deliver(ctx context.Context, t Triggered) error {
+	a := t.Alert
+	subject := fmt.Sprintf("HashHedge alert: %s %s %s %d", a.ContractType, a.Metric, a.Direction, a.Threshold)
+	body := fmt.Sprintf(
+		"Your price alert on %s %.2f EH/s (blocks %d-%d) triggered.\r\n\r\n%s is now %d sats (threshold %s %d sats) as of %s.\r\n",
+		a.ContractType, a.StrikeHashRate, a.StartBlockHeight, a.EndBlockHeight,
+		a.Metric, t.Value, a.Direction, a.Threshold, t.At.Format(time.RFC3339),
+	)
+	msg := []byte("From: " + d.cfg.From + "\r\n" +
+		"To: " + a.Target + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"\r\n" + body)
+
+	var auth smtp.Auth
+	if d.cfg.Username != "" {
+		auth = smtp.PlainAuth("", d.cfg.Username, d.cfg.Password, d.cfg.Host)
+	}
+
+	addr := d.cfg.Host + ":" + strconv.Itoa(d.cfg.Port)
+	if err := smtp.SendMail(addr, auth, d.cfg.From, []string{a.Target}, msg); err != nil {
+		return fmt.Errorf("failed to send alert email: %w", err)
+	}
+
+	return nil
+}
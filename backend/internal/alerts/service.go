@@ -0,0 +1,168 @@
+// internal/alerts/service.go
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var logger = logging.Component(logging.Alerts)
+
+// ErrLimitReached is returned when a user already has the maximum number of active alerts
+var ErrLimitReached = errors.New("active price alert limit reached")
+
+// Config holds the price alert configuration
+type Config struct {
+	MaxPerUser int        `yaml:"max_per_user"`
+	SMTP       SMTPConfig `yaml:"smtp"`
+}
+
+// Triggered is the notification sent when an alert fires
+type Triggered struct {
+	Alert *models.PriceAlert `json:"alert"`
+	Value int64              `json:"value"`
+	At    time.Time          `json:"at"`
+}
+
+// Deliverer sends triggered alerts over one channel
+type Deliverer interface {
+	Deliver(ctx context.Context, t Triggered) error
+}
+
+// Service manages price alert subscriptions and evaluates them on market updates
+type Service struct {
+	repo       *db.PriceAlertRepository
+	maxPerUser int
+	deliverers map[models.AlertChannel]Deliverer
+}
+
+// NewService creates a new price alert service
+func NewService(repo *db.PriceAlertRepository, maxPerUser int) *Service {
+	return &Service{
+		repo:       repo,
+		maxPerUser: maxPerUser,
+		deliverers: make(map[models.AlertChannel]Deliverer),
+	}
+}
+
+// WithDeliverer sets the deliverer for a channel
+func (s *Service) WithDeliverer(channel models.AlertChannel, d Deliverer) *Service {
+	s.deliverers[channel] = d
+	return s
+}
+
+// Create validates and stores a new alert, enforcing the per-user limit
+func (s *Service) Create(ctx context.Context, alert *models.PriceAlert) error {
+	if err := alert.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := s.deliverers[alert.Channel]; !ok {
+		return fmt.Errorf("alert channel %s is not available", alert.Channel)
+	}
+
+	count, err := s.repo.CountActiveByUser(ctx, alert.UserID)
+	if err != nil {
+		return err
+	}
+	if s.maxPerUser > 0 && count >= s.maxPerUser {
+		return ErrLimitReached
+	}
+
+	return s.repo.Create(ctx, alert)
+}
+
+// List retrieves the alerts of a user
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*models.PriceAlert, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Cancel cancels an active alert of a user
+func (s *Service) Cancel(ctx context.Context, userID, alertID uuid.UUID) error {
+	return s.repo.Cancel(ctx, userID, alertID)
+}
+
+// OnMarketUpdate evaluates the active alerts of the updated market.
+// It implements orderbook.MarketObserver.
+func (s *Service) OnMarketUpdate(ctx context.Context, snapshot orderbook.MarketSnapshot) {
+	alerts, err := s.repo.ListActiveByMarket(
+		ctx,
+		snapshot.Key.ContractType,
+		snapshot.Key.StrikeHashRate,
+		snapshot.Key.StartBlockHeight,
+		snapshot.Key.EndBlockHeight,
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load price alerts for market update")
+		return
+	}
+
+	for _, alert := range alerts {
+		value := metricValue(snapshot, alert.Metric)
+		if value == nil || !alert.IsTriggeredBy(*value) {
+			continue
+		}
+
+		// Only the evaluation that flips the status delivers, so concurrent updates cannot double-fire
+		ok, err := s.repo.MarkTriggered(ctx, alert.ID, *value)
+		if err != nil {
+			logger.Error().Err(err).Str("alert_id", alert.ID.String()).Msg("Failed to mark price alert triggered")
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		alert.Status = models.AlertStatusTriggered
+		s.deliver(ctx, Triggered{Alert: alert, Value: *value, At: snapshot.Time})
+	}
+}
+
+// deliver sends a triggered alert over its channel
+func (s *Service) deliver(ctx context.Context, t Triggered) {
+	d, ok := s.deliverers[t.Alert.Channel]
+	if !ok {
+		logger.Warn().
+			Str("alert_id", t.Alert.ID.String()).
+			Str("channel", string(t.Alert.Channel)).
+			Msg("No deliverer for price alert channel")
+		return
+	}
+
+	if err := d.Deliver(ctx, t); err != nil {
+		logger.Error().
+			Err(err).
+			Str("alert_id", t.Alert.ID.String()).
+			Str("channel", string(t.Alert.Channel)).
+			Msg("Failed to deliver price alert")
+		return
+	}
+
+	logger.Info().
+		Str("alert_id", t.Alert.ID.String()).
+		Str("user_id", t.Alert.UserID.String()).
+		Int64("value", t.Value).
+		Msg("Price alert delivered")
+}
+
+// metricValue returns the snapshot value watched by metric, or nil if the market has none
+func metricValue(snapshot orderbook.MarketSnapshot, metric models.AlertMetric) *int64 {
+	switch metric {
+	case models.AlertMetricBestBid:
+		return snapshot.BestBid
+	case models.AlertMetricBestAsk:
+		return snapshot.BestAsk
+	case models.AlertMetricLastTrade:
+		return snapshot.LastTrade
+	}
+	return nil
+}
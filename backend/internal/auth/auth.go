@@ -0,0 +1,75 @@
+// internal/auth/auth.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidCredentials is returned when a login does not match a user
+	ErrInvalidCredentials = errors.New("invalid username or password")
+
+	// ErrInvalidToken is returned for a malformed, expired or wrongly typed token
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrInvalidRegistration is returned when a registration fails validation
+	ErrInvalidRegistration = errors.New("invalid registration")
+)
+
+// minSecretLength is the shortest accepted token signing secret in bytes
+const minSecretLength = 32
+
+// Config holds the authentication configuration
+type Config struct {
+	// JWTSecret signs access and refresh tokens. When empty a random secret
+	// is generated at startup, so every session ends when the server restarts.
+	JWTSecret string `yaml:"jwt_secret"`
+	// AccessTTL is how long an access token is accepted
+	AccessTTL time.Duration `yaml:"access_ttl"`
+	// RefreshTTL is how long a refresh token can be exchanged for new tokens
+	RefreshTTL time.Duration `yaml:"refresh_ttl"`
+	// MinPasswordLength is the shortest password accepted at registration
+	MinPasswordLength int `yaml:"min_password_length"`
+}
+
+// DefaultConfig issues short-lived access tokens and month-long refresh tokens
+var DefaultConfig = Config{
+	AccessTTL:         15 * time.Minute,
+	RefreshTTL:        30 * 24 * time.Hour,
+	MinPasswordLength: 10,
+}
+
+// Validate checks that the token lifetimes and secret are usable
+func (c Config) Validate() error {
+	if c.JWTSecret != "" && len(c.JWTSecret) < minSecretLength {
+		return fmt.Errorf("auth JWT secret must be at least %d bytes", minSecretLength)
+	}
+	if c.AccessTTL <= 0 {
+		return fmt.Errorf("auth access token TTL must be positive")
+	}
+	if c.RefreshTTL <= c.AccessTTL {
+		return fmt.Errorf("auth refresh token TTL must be longer than the access token TTL")
+	}
+	if c.MinPasswordLength <= 0 {
+		return fmt.Errorf("auth minimum password length must be positive")
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithUserID returns a context carrying the user that authenticated a request
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserIDFromContext returns the user that authenticated a request, if any
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(contextKey{}).(uuid.UUID)
+	return userID, ok && userID != uuid.Nil
+}
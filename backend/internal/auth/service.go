@@ -0,0 +1,163 @@
+// internal/auth/service.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.HTTP)
+
+// UserStore is the subset of the user repository used for authentication
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+}
+
+// Registration is the account a new user signs up with
+type Registration struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Service registers users and exchanges credentials and refresh tokens for tokens
+type Service struct {
+	users             UserStore
+	tokens            *Tokens
+	minPasswordLength int
+	// dummyHash is compared against when a username is unknown, so failed
+	// logins take as long whether or not the user exists
+	dummyHash []byte
+}
+
+// NewService creates a new authentication service
+func NewService(users UserStore, cfg Config) (*Service, error) {
+	tokens, err := NewTokens(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.NewString()), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return &Service{
+		users:             users,
+		tokens:            tokens,
+		minPasswordLength: cfg.MinPasswordLength,
+		dummyHash:         dummyHash,
+	}, nil
+}
+
+// Register creates a user with a bcrypt hash of their password. A taken
+// username or email is reported as db.ErrConflict.
+func (s *Service) Register(ctx context.Context, reg Registration) (*models.User, error) {
+	if err := validateRegistration(&reg, s.minPasswordLength); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(reg.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Username:     reg.Username,
+		Email:        reg.Email,
+		PasswordHash: string(hash),
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// validateRegistration normalizes and checks a registration
+func validateRegistration(reg *Registration, minPasswordLength int) error {
+	reg.Username = strings.TrimSpace(reg.Username)
+	reg.Email = strings.ToLower(strings.TrimSpace(reg.Email))
+
+	if len(reg.Username) < 3 || len(reg.Username) > 100 {
+		return fmt.Errorf("%w: username must be between 3 and 100 characters", ErrInvalidRegistration)
+	}
+
+	address, err := mail.ParseAddress(reg.Email)
+	if err != nil || address.Address != reg.Email || len(reg.Email) > 255 {
+		return fmt.Errorf("%w: invalid email address", ErrInvalidRegistration)
+	}
+
+	if len(reg.Password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidRegistration, minPasswordLength)
+	}
+	// bcrypt ignores everything after the 72nd byte
+	if len(reg.Password) > 72 {
+		return fmt.Errorf("%w: password cannot be longer than 72 bytes", ErrInvalidRegistration)
+	}
+
+	return nil
+}
+
+// Login checks a username and password and issues tokens for the user
+func (s *Service) Login(ctx context.Context, username, password string) (*models.User, *TokenPair, error) {
+	user, err := s.users.GetByUsername(ctx, strings.TrimSpace(username))
+	if errors.Is(err, db.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return nil, nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	tokens, err := s.tokens.Issue(user.ID, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.users.UpdateLastLogin(ctx, user.ID); err != nil {
+		logger.Warn().Err(err).Str("userID", user.ID.String()).Msg("Failed to record last login")
+	}
+
+	return user, tokens, nil
+}
+
+// Refresh exchanges a refresh token for new tokens if its user still exists
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	userID, err := s.tokens.Verify(refreshToken, TokenTypeRefresh, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user no longer exists", ErrInvalidToken)
+		}
+		return nil, err
+	}
+
+	return s.tokens.Issue(userID, time.Now())
+}
+
+// Authenticate returns the user an access token was issued to
+func (s *Service) Authenticate(accessToken string) (uuid.UUID, error) {
+	return s.tokens.Verify(accessToken, TokenTypeAccess, time.Now())
+}
@@ -0,0 +1,138 @@
+// internal/auth/token.go
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenType distinguishes access tokens from refresh tokens so one cannot be
+// used in place of the other
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// issuer is the iss claim of every token
+const issuer = "hashhedge"
+
+// TokenPair is the access and refresh token issued at login or refresh
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	TokenType        string    `json:"token_type"`
+}
+
+// claims are the registered JWT claims plus the token type
+type claims struct {
+	Type TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// Tokens issues and verifies HMAC-SHA256 signed JWTs
+type Tokens struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokens creates a token issuer from the configuration
+func NewTokens(cfg Config) (*Tokens, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, minSecretLength)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate JWT secret: %w", err)
+		}
+	}
+
+	return &Tokens{
+		secret:     secret,
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+	}, nil
+}
+
+// Issue returns a new access and refresh token for a user
+func (t *Tokens) Issue(userID uuid.UUID, now time.Time) (*TokenPair, error) {
+	access, accessExpiresAt, err := t.sign(userID, TokenTypeAccess, now, t.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, refreshExpiresAt, err := t.sign(userID, TokenTypeRefresh, now, t.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExpiresAt,
+		TokenType:        "Bearer",
+	}, nil
+}
+
+// sign returns a signed token of the given type and its expiry
+func (t *Tokens) sign(userID uuid.UUID, tokenType TokenType, now time.Time, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := now.Add(ttl).Truncate(time.Second)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Type: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+
+	signed, err := token.SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign %s token: %w", tokenType, err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// Verify checks a token's signature, expiry and type at now and returns the
+// user it was issued to
+func (t *Tokens) Verify(raw string, tokenType TokenType, now time.Time) (uuid.UUID, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(raw, &c, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if c.Type != tokenType {
+		return uuid.Nil, fmt.Errorf("%w: expected a %s token", ErrInvalidToken, tokenType)
+	}
+
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid subject", ErrInvalidToken)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,105 @@
+// internal/auth/token_test.go
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.JWTSecret = strings.Repeat("s", minSecretLength)
+	tokens, err := NewTokens(cfg)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	pair, err := tokens.Issue(userID, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(cfg.AccessTTL), pair.AccessExpiresAt)
+	assert.Equal(t, now.Add(cfg.RefreshTTL), pair.RefreshExpiresAt)
+
+	t.Run("access token identifies the user", func(t *testing.T) {
+		got, err := tokens.Verify(pair.AccessToken, TokenTypeAccess, now.Add(time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, userID, got)
+	})
+
+	t.Run("refresh token outlives the access token", func(t *testing.T) {
+		later := now.Add(cfg.AccessTTL + time.Minute)
+		_, err := tokens.Verify(pair.AccessToken, TokenTypeAccess, later)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+
+		got, err := tokens.Verify(pair.RefreshToken, TokenTypeRefresh, later)
+		require.NoError(t, err)
+		assert.Equal(t, userID, got)
+	})
+
+	t.Run("token types are not interchangeable", func(t *testing.T) {
+		_, err := tokens.Verify(pair.RefreshToken, TokenTypeAccess, now)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = tokens.Verify(pair.AccessToken, TokenTypeRefresh, now)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("tokens signed with another secret are rejected", func(t *testing.T) {
+		other := cfg
+		other.JWTSecret = strings.Repeat("o", minSecretLength)
+		otherTokens, err := NewTokens(other)
+		require.NoError(t, err)
+
+		_, err = otherTokens.Verify(pair.AccessToken, TokenTypeAccess, now)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := tokens.Verify("not.a.token", TokenTypeAccess, now)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	short := DefaultConfig
+	short.JWTSecret = "too short"
+	assert.Error(t, short.Validate())
+
+	inverted := DefaultConfig
+	inverted.RefreshTTL = inverted.AccessTTL
+	assert.Error(t, inverted.Validate())
+}
+
+func TestValidateRegistration(t *testing.T) {
+	tests := []struct {
+		name  string
+		reg   Registration
+		valid bool
+	}{
+		{"valid", Registration{"satoshi", "Satoshi@Example.com ", "correct horse battery"}, true},
+		{"short username", Registration{"ab", "a@example.com", "correct horse battery"}, false},
+		{"invalid email", Registration{"satoshi", "not an email", "correct horse battery"}, false},
+		{"named email", Registration{"satoshi", "Satoshi <s@example.com>", "correct horse battery"}, false},
+		{"short password", Registration{"satoshi", "s@example.com", "short"}, false},
+		{"long password", Registration{"satoshi", "s@example.com", strings.Repeat("p", 73)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := tt.reg
+			err := validateRegistration(&reg, DefaultConfig.MinPasswordLength)
+			if !tt.valid {
+				assert.ErrorIs(t, err, ErrInvalidRegistration)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "satoshi@example.com", reg.Email)
+		})
+	}
+}
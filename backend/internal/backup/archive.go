@@ -0,0 +1,159 @@
+// internal/backup/archive.go
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// archiveMagic prefixes every archive so a wrong file fails before decryption
+const archiveMagic = "HHDR1"
+
+// SnapshotVersion is the version of the snapshot format written by this build
+const SnapshotVersion = 1
+
+// Snapshot is the decrypted content of a disaster recovery archive
+type Snapshot struct {
+	Version      int                           `json:"version"`
+	TakenAt      time.Time                     `json:"taken_at"`
+	Contracts    []*models.Contract            `json:"contracts"`
+	Transactions []*models.ContractTransaction `json:"transactions"`
+	Funding      []*FundingRecord              `json:"funding"`
+	Inputs       []*models.ContractInput       `json:"inputs,omitempty"`
+	// Claims summarise, per contract, what a user needs to recover funds
+	// on chain without the service
+	Claims []*Claim `json:"claims"`
+}
+
+// FundingRecord is models.ContractFunding including the PSBTs, which the API hides
+type FundingRecord struct {
+	ContractID   uuid.UUID `json:"contract_id"`
+	BuyerFunded  bool      `json:"buyer_funded"`
+	SellerFunded bool      `json:"seller_funded"`
+	BuyerSigned  bool      `json:"buyer_signed"`
+	SellerSigned bool      `json:"seller_signed"`
+	BuyerPSBT    *string   `json:"buyer_psbt,omitempty"`
+	SellerPSBT   *string   `json:"seller_psbt,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Claim lists the keys and latest signed transactions of a contract
+type Claim struct {
+	ContractID     uuid.UUID             `json:"contract_id"`
+	Status         models.ContractStatus `json:"status"`
+	BuyerPubKey    string                `json:"buyer_pub_key"`
+	SellerPubKey   string                `json:"seller_pub_key"`
+	EndBlockHeight int64                 `json:"end_block_height"`
+	// Transactions maps each transaction type to the hex of its latest transaction
+	Transactions map[string]string `json:"transactions"`
+}
+
+// ParseKey decodes a hex encoded 256-bit archive key
+func ParseKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("backup key is not hex: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("backup key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// Seal writes the snapshot as gzipped JSON encrypted with AES-256-GCM
+func Seal(w io.Writer, snapshot *Snapshot, key []byte) error {
+	var plain bytes.Buffer
+	gz := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The magic is authenticated so it cannot be swapped for another format
+	sealed := aead.Seal(nil, nonce, plain.Bytes(), []byte(archiveMagic))
+
+	for _, part := range [][]byte{[]byte(archiveMagic), nonce, sealed} {
+		if _, err := w.Write(part); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Open decrypts and decodes an archive written by Seal
+func Open(r io.Reader, key []byte) (*Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte(archiveMagic)) {
+		return nil, errors.New("not a HashHedge recovery archive")
+	}
+	data = data[len(archiveMagic):]
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("archive is truncated")
+	}
+
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(archiveMagic))
+	if err != nil {
+		return nil, errors.New("failed to decrypt archive: wrong key or corrupted file")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer gz.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(gz).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	if snapshot.Version > SnapshotVersion {
+		return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", snapshot.Version, SnapshotVersion)
+	}
+
+	return &snapshot, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
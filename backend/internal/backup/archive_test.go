@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	psbt := "cHNidP8BAA=="
+
+	snapshot := &Snapshot{
+		Version: SnapshotVersion,
+		TakenAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Contracts: []*models.Contract{
+			{ID: uuid.New(), ContractType: models.ContractTypeCall, BuyerPubKey: "02aa", SellerPubKey: "02bb"},
+		},
+		Funding: []*FundingRecord{
+			{ContractID: uuid.New(), BuyerFunded: true, BuyerPSBT: &psbt},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.Nil(t, Seal(&buf, snapshot, key))
+
+	tests := []struct {
+		name    string
+		archive []byte
+		key     []byte
+		wantErr bool
+	}{
+		{name: "round trip", archive: buf.Bytes(), key: key},
+		{name: "wrong key", archive: buf.Bytes(), key: bytes.Repeat([]byte{0x43}, 32), wantErr: true},
+		{name: "not an archive", archive: []byte("hello"), key: key, wantErr: true},
+		{name: "truncated", archive: buf.Bytes()[:len(archiveMagic)+4], key: key, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Open(bytes.NewReader(tt.archive), tt.key)
+			if tt.wantErr {
+				assert.Equal(t, true, err != nil)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, snapshot.TakenAt, got.TakenAt)
+			assert.Equal(t, snapshot.Contracts[0].ID, got.Contracts[0].ID)
+			assert.Equal(t, psbt, *got.Funding[0].BuyerPSBT)
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey("00")
+	assert.Equal(t, true, err != nil)
+
+	key, err := ParseKey("4242424242424242424242424242424242424242424242424242424242424242")
+	assert.Nil(t, err)
+	assert.Equal(t, 32, len(key))
+}
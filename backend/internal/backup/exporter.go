@@ -0,0 +1,245 @@
+// internal/backup/exporter.go
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Jobs)
+
+// archiveExt is the file extension of recovery archives
+const archiveExt = ".hhdr"
+
+// Config holds the disaster recovery export configuration. Periodic export
+// is enabled when Dir is set.
+type Config struct {
+	Dir      string        `yaml:"dir"`
+	Interval time.Duration `yaml:"interval"`
+	Retain   int           `yaml:"retain"`
+	Key      string        `yaml:"key"` // Hex encoded 256-bit key
+}
+
+// DefaultConfig provides sensible defaults for disaster recovery exports
+var DefaultConfig = Config{
+	Interval: time.Hour,
+	Retain:   48,
+}
+
+// Enabled reports whether periodic export is configured
+func (c Config) Enabled() bool {
+	return c.Dir != ""
+}
+
+// Exporter produces encrypted snapshots of the contract state
+type Exporter struct {
+	repo *db.SnapshotRepository
+	cfg  Config
+	key  []byte
+}
+
+// NewExporter creates a new exporter
+func NewExporter(repo *db.SnapshotRepository, cfg Config) (*Exporter, error) {
+	key, err := ParseKey(cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{repo: repo, cfg: cfg, key: key}, nil
+}
+
+// Export writes an encrypted snapshot of the current contract state to w
+func (e *Exporter) Export(ctx context.Context, w io.Writer) (*Snapshot, error) {
+	state, err := e.repo.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := newSnapshot(state, time.Now().UTC())
+	if err := Seal(w, snapshot, e.key); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// ExportToDir writes a new archive to the configured directory and removes
+// archives beyond the retention count
+func (e *Exporter) ExportToDir(ctx context.Context) (string, error) {
+	if err := os.MkdirAll(e.cfg.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(e.cfg.Dir, ".export-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	snapshot, err := e.Export(ctx, tmp)
+	if err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to sync archive: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	// Rename only once the archive is complete so a crash never leaves a partial archive
+	path := filepath.Join(e.cfg.Dir, "hashhedge-"+snapshot.TakenAt.Format("20060102T150405Z")+archiveExt)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to finalize archive: %w", err)
+	}
+
+	e.prune()
+
+	logger.Info().
+		Str("path", path).
+		Int("contracts", len(snapshot.Contracts)).
+		Int("transactions", len(snapshot.Transactions)).
+		Msg("Recovery archive written")
+
+	return path, nil
+}
+
+// Start begins exporting on the configured interval
+func (e *Exporter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := e.ExportToDir(ctx); err != nil {
+					logger.Error().Err(err).Msg("Failed to write recovery archive")
+				}
+			}
+		}
+	}()
+}
+
+// prune removes the oldest archives beyond the retention count
+func (e *Exporter) prune() {
+	if e.cfg.Retain <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(e.cfg.Dir)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to list recovery archives")
+		return
+	}
+
+	var archives []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), archiveExt) {
+			archives = append(archives, entry.Name())
+		}
+	}
+
+	// Names embed the timestamp, so lexical order is chronological
+	sort.Strings(archives)
+	for len(archives) > e.cfg.Retain {
+		if err := os.Remove(filepath.Join(e.cfg.Dir, archives[0])); err != nil {
+			logger.Warn().Err(err).Str("archive", archives[0]).Msg("Failed to remove old recovery archive")
+		}
+		archives = archives[1:]
+	}
+}
+
+// Restore decrypts an archive and inserts its state into the database
+func Restore(ctx context.Context, repo *db.SnapshotRepository, r io.Reader, key []byte) (*Snapshot, *db.RestoreCounts, error) {
+	snapshot, err := Open(r, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state := &db.ContractState{
+		Contracts:    snapshot.Contracts,
+		Transactions: snapshot.Transactions,
+		Inputs:       snapshot.Inputs,
+	}
+	for _, f := range snapshot.Funding {
+		state.Funding = append(state.Funding, &models.ContractFunding{
+			ContractID:   f.ContractID,
+			BuyerFunded:  f.BuyerFunded,
+			SellerFunded: f.SellerFunded,
+			BuyerSigned:  f.BuyerSigned,
+			SellerSigned: f.SellerSigned,
+			BuyerPSBT:    f.BuyerPSBT,
+			SellerPSBT:   f.SellerPSBT,
+			UpdatedAt:    f.UpdatedAt,
+		})
+	}
+
+	counts, err := repo.Restore(ctx, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot, counts, nil
+}
+
+// newSnapshot builds the archive content from the database state
+func newSnapshot(state *db.ContractState, takenAt time.Time) *Snapshot {
+	snapshot := &Snapshot{
+		Version:      SnapshotVersion,
+		TakenAt:      takenAt,
+		Contracts:    state.Contracts,
+		Transactions: state.Transactions,
+		Inputs:       state.Inputs,
+	}
+
+	for _, f := range state.Funding {
+		snapshot.Funding = append(snapshot.Funding, &FundingRecord{
+			ContractID:   f.ContractID,
+			BuyerFunded:  f.BuyerFunded,
+			SellerFunded: f.SellerFunded,
+			BuyerSigned:  f.BuyerSigned,
+			SellerSigned: f.SellerSigned,
+			BuyerPSBT:    f.BuyerPSBT,
+			SellerPSBT:   f.SellerPSBT,
+			UpdatedAt:    f.UpdatedAt,
+		})
+	}
+
+	claims := make(map[string]*Claim, len(state.Contracts))
+	for _, c := range state.Contracts {
+		claim := &Claim{
+			ContractID:     c.ID,
+			Status:         c.Status,
+			BuyerPubKey:    c.BuyerPubKey,
+			SellerPubKey:   c.SellerPubKey,
+			EndBlockHeight: c.EndBlockHeight,
+			Transactions:   make(map[string]string),
+		}
+		claims[c.ID.String()] = claim
+		snapshot.Claims = append(snapshot.Claims, claim)
+	}
+
+	// Transactions are ordered by creation, so later ones of a type replace earlier ones
+	for _, tx := range state.Transactions {
+		if claim, ok := claims[tx.ContractID.String()]; ok {
+			claim.Transactions[tx.TxType] = tx.TxHex
+		}
+	}
+
+	return snapshot
+}
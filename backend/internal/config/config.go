@@ -3,19 +3,87 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/rs/zerolog"
 	"gopkg.in/yaml.v3"
+
+	"hashhedge/internal/alerts"
+	"hashhedge/internal/auth"
+	"hashhedge/internal/backup"
+	"hashhedge/internal/contract"
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/coordination"
+	"hashhedge/internal/feeds"
+	"hashhedge/internal/jobs"
+	"hashhedge/internal/ledger"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/margin"
+	"hashhedge/internal/marketdata"
+	"hashhedge/internal/netproxy"
+	"hashhedge/internal/orderbook"
+	"hashhedge/internal/privacy"
+	"hashhedge/internal/push"
+	"hashhedge/internal/ratelimit"
+	"hashhedge/internal/research"
+	"hashhedge/internal/rollover"
+	"hashhedge/internal/settlement"
+	"hashhedge/internal/timestamping"
+	"hashhedge/internal/tracing"
+	"hashhedge/internal/tradereport"
+	"hashhedge/internal/usage"
+	"hashhedge/internal/wallet"
+	"hashhedge/internal/webhooks"
+	"hashhedge/pkg/ark"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/priceindex"
+	"hashhedge/pkg/taproot"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	Bitcoin  BitcoinConfig  `yaml:"bitcoin"`
-	ArkASP   ArkASPConfig   `yaml:"ark_asp"`
+	Server             ServerConfig                      `yaml:"server"`
+	Database           DatabaseConfig                    `yaml:"database"`
+	Bitcoin            BitcoinConfig                     `yaml:"bitcoin"`
+	ArkASP             ArkASPConfig                      `yaml:"ark_asp"`
+	Retry              RetryConfig                       `yaml:"retry"`
+	Proxy              netproxy.Config                   `yaml:"proxy"`
+	Logging            logging.Config                    `yaml:"logging"`
+	Tracing            tracing.Config                    `yaml:"tracing"`
+	Jobs               jobs.Config                       `yaml:"jobs"`
+	Alerts             alerts.Config                     `yaml:"alerts"`
+	Feeds              feeds.Config                      `yaml:"feeds"`
+	Push               push.Config                       `yaml:"push"`
+	Webhooks           webhooks.Config                   `yaml:"webhooks"`
+	Usage              usage.Config                      `yaml:"usage"`
+	RateLimit          ratelimit.Config                  `yaml:"rate_limit"`
+	Rollover           rollover.Config                   `yaml:"rollover"`
+	Backup             backup.Config                     `yaml:"backup"`
+	Privacy            privacy.Config                    `yaml:"privacy"`
+	Auth               auth.Config                       `yaml:"auth"`
+	OrderBook          orderbook.Config                  `yaml:"order_book"`
+	Coordination       coordination.Config               `yaml:"coordination"`
+	FeePolicy          contract.FeePolicyConfig          `yaml:"fee_policy"`
+	ScheduledClose     contract.ScheduledCloseConfig     `yaml:"scheduled_close"`
+	ExitMonitor        contract.ExitMonitorConfig        `yaml:"exit_monitor"`
+	Confirmations      contract.ConfirmationConfig       `yaml:"confirmations"`
+	SettlementTracking contract.SettlementTrackingConfig `yaml:"settlement_tracking"`
+	BroadcastChecks    contract.BroadcastCheckConfig     `yaml:"broadcast_checks"`
+	Oracle             contract.OracleConfig             `yaml:"attestation_oracle"`
+	Margin             margin.Config                     `yaml:"margin"`
+	Ledger             ledger.Config                     `yaml:"ledger"`
+	Wallet             wallet.Config                     `yaml:"wallet"`
+	Market             marketdata.Config                 `yaml:"market_data"`
+	HashRate           hashrate.SamplerConfig            `yaml:"hash_rate"`
+	Timestamping       timestamping.Config               `yaml:"timestamping"`
+	Settlement         settlement.Config                 `yaml:"settlement"`
+	Research           research.Config                   `yaml:"research"`
+	TradeReports       tradereport.Config                `yaml:"trade_reports"`
+	PriceIndex         priceindex.Config                 `yaml:"price_index"`
 }
 
 // ServerConfig holds the HTTP server configuration
@@ -43,6 +111,56 @@ type BitcoinConfig struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	UseTLS   bool   `yaml:"use_tls"`
+	// Network is mainnet, testnet, signet or regtest. Addresses are encoded
+	// for it and addresses from any other network are rejected.
+	Network string `yaml:"network"`
+	// Failover is a secondary node calls move to while the primary cannot be
+	// reached; an empty host disables it
+	Failover BitcoinNodeConfig `yaml:"failover"`
+	// Connections pooled to each node and the limits of each call
+	PoolSize         int           `yaml:"pool_size"`
+	CallTimeout      time.Duration `yaml:"call_timeout"`
+	FailureThreshold int           `yaml:"failure_threshold"`
+	Cooldown         time.Duration `yaml:"cooldown"`
+	// HealthCheckInterval is how often every node is checked
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+}
+
+// BitcoinNodeConfig holds the RPC endpoint of a failover Bitcoin node
+type BitcoinNodeConfig struct {
+	Host     string `yaml:"host"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	UseTLS   bool   `yaml:"use_tls"`
+}
+
+// Params returns the chain parameters of the configured network
+func (c BitcoinConfig) Params() (*chaincfg.Params, error) {
+	return taproot.ParseNetwork(c.Network)
+}
+
+// Nodes returns the primary node followed by the failover, if configured
+func (c BitcoinConfig) Nodes() []bitcoin.Node {
+	nodes := []bitcoin.Node{{Host: c.Host, User: c.User, Password: c.Password, UseTLS: c.UseTLS}}
+	if c.Failover.Host != "" {
+		nodes = append(nodes, bitcoin.Node{
+			Host:     c.Failover.Host,
+			User:     c.Failover.User,
+			Password: c.Failover.Password,
+			UseTLS:   c.Failover.UseTLS,
+		})
+	}
+	return nodes
+}
+
+// Pool returns the options of the connection pools
+func (c BitcoinConfig) Pool() bitcoin.PoolOptions {
+	return bitcoin.PoolOptions{
+		Size:             c.PoolSize,
+		CallTimeout:      c.CallTimeout,
+		FailureThreshold: c.FailureThreshold,
+		Cooldown:         c.Cooldown,
+	}
 }
 
 // ArkASPConfig holds the Ark Service Provider configuration
@@ -52,6 +170,103 @@ type ArkASPConfig struct {
 	PubKey          string        `yaml:"pub_key"`
 	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
 	RequestTimeout  time.Duration `yaml:"request_timeout"`
+	// TLS secures the connection to the ASP
+	TLS ark.TLSConfig `yaml:"tls"`
+	// AuthToken is sent with every call to ASPs requiring one
+	AuthToken string `yaml:"auth_token"`
+	// Name is what the VTXOs this ASP issues are recorded under
+	Name string `yaml:"name"`
+	// Failover lists ASPs calls move to when the primary stops answering
+	Failover []ArkEndpointConfig `yaml:"failover"`
+	// CheckInterval is how often every ASP is probed
+	CheckInterval time.Duration `yaml:"check_interval"`
+	// MigrateAfter is how long a former primary must stay down before its
+	// VTXOs are exited and re-entered through the new primary; zero never
+	// migrates them
+	MigrateAfter time.Duration `yaml:"migrate_after"`
+}
+
+// ArkEndpointConfig holds the connection to a failover ASP
+type ArkEndpointConfig struct {
+	Name      string        `yaml:"name"`
+	Host      string        `yaml:"host"`
+	Port      int           `yaml:"port"`
+	TLS       ark.TLSConfig `yaml:"tls"`
+	AuthToken string        `yaml:"auth_token"`
+}
+
+// Endpoints returns the primary ASP followed by the failovers, connecting
+// through proxy when it is not nil
+func (c ArkASPConfig) Endpoints(proxy *url.URL) []ark.Endpoint {
+	endpoints := []ark.Endpoint{{
+		Name: c.Name,
+		Config: ark.Config{
+			Host:           c.Host,
+			Port:           c.Port,
+			ConnectTimeout: c.ConnectTimeout,
+			RequestTimeout: c.RequestTimeout,
+			Proxy:          proxy,
+			TLS:            c.TLS,
+			AuthToken:      c.AuthToken,
+		},
+	}}
+	for _, failover := range c.Failover {
+		endpoints = append(endpoints, ark.Endpoint{
+			Name: failover.Name,
+			Config: ark.Config{
+				Host:           failover.Host,
+				Port:           failover.Port,
+				ConnectTimeout: c.ConnectTimeout,
+				RequestTimeout: c.RequestTimeout,
+				Proxy:          proxy,
+				TLS:            failover.TLS,
+				AuthToken:      failover.AuthToken,
+			},
+		})
+	}
+	return endpoints
+}
+
+// validateEndpoint checks the connection settings of one ASP
+func validateEndpoint(name string, port int, tls ark.TLSConfig, authToken string) error {
+	if name == "" {
+		return fmt.Errorf("ARK ASP name cannot be empty")
+	}
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid ARK port for ASP %s: %d", name, port)
+	}
+	if err := tls.Validate(); err != nil {
+		return fmt.Errorf("ASP %s: %w", name, err)
+	}
+	if authToken != "" && !tls.Enabled {
+		return fmt.Errorf("ARK ASP %s auth token requires TLS", name)
+	}
+	return nil
+}
+
+// RetryConfig holds the retry policies of calls to the Bitcoin node and the
+// ASP. They are reloaded from the file on SIGHUP.
+type RetryConfig struct {
+	// BitcoinBroadcast retries transactions the node fails to broadcast
+	BitcoinBroadcast bitcoin.BroadcastRetry `yaml:"bitcoin_broadcast"`
+	// ASP retries calls to the ASP that fail with a transient error
+	ASP ark.RetryConfig `yaml:"asp"`
+	// ASPStream paces reconnects of the ASP's transaction stream
+	ASPStream ark.RetryConfig `yaml:"asp_stream"`
+}
+
+// Validate checks every retry policy
+func (c RetryConfig) Validate() error {
+	if err := c.BitcoinBroadcast.Validate(); err != nil {
+		return err
+	}
+	if err := c.ASP.Validate(); err != nil {
+		return err
+	}
+	if err := c.ASPStream.Validate(); err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	return nil
 }
 
 // Load loads the configuration from a file
@@ -74,10 +289,16 @@ func Load(path string) (*Config, error) {
 			SSLMode:  "disable",
 		},
 		Bitcoin: BitcoinConfig{
-			Host:     "localhost:8332",
-			User:     "bitcoin",
-			Password: "password",
-			UseTLS:   false,
+			Host:                "localhost:8332",
+			User:                "bitcoin",
+			Password:            "password",
+			UseTLS:              false,
+			Network:             taproot.DefaultNetwork,
+			PoolSize:            bitcoin.DefaultPoolOptions.Size,
+			CallTimeout:         bitcoin.DefaultPoolOptions.CallTimeout,
+			FailureThreshold:    bitcoin.DefaultPoolOptions.FailureThreshold,
+			Cooldown:            bitcoin.DefaultPoolOptions.Cooldown,
+			HealthCheckInterval: 15 * time.Second,
 		},
 		ArkASP: ArkASPConfig{
 			Host:           "localhost",
@@ -85,6 +306,49 @@ func Load(path string) (*Config, error) {
 			PubKey:         "0250929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0",
 			ConnectTimeout: 10 * time.Second,
 			RequestTimeout: 30 * time.Second,
+			Name:           "primary",
+			CheckInterval:  30 * time.Second,
+			MigrateAfter:   6 * time.Hour,
+		},
+		Retry: RetryConfig{
+			BitcoinBroadcast: bitcoin.DefaultBroadcastRetry,
+			ASP:              ark.DefaultRetryConfig,
+			ASPStream:        ark.DefaultRetryConfig,
+		},
+		Logging: logging.Config{
+			Level: "info",
+		},
+		Tracing:            tracing.DefaultConfig,
+		Jobs:               jobs.DefaultConfig,
+		Webhooks:           webhooks.DefaultConfig,
+		Usage:              usage.DefaultConfig,
+		RateLimit:          ratelimit.DefaultConfig,
+		Rollover:           rollover.DefaultConfig,
+		Backup:             backup.DefaultConfig,
+		Auth:               auth.DefaultConfig,
+		OrderBook:          orderbook.DefaultConfig,
+		FeePolicy:          contract.DefaultFeePolicyConfig,
+		ScheduledClose:     contract.DefaultScheduledCloseConfig,
+		ExitMonitor:        contract.DefaultExitMonitorConfig,
+		Confirmations:      contract.DefaultConfirmationConfig,
+		SettlementTracking: contract.DefaultSettlementTrackingConfig,
+		BroadcastChecks:    contract.DefaultBroadcastCheckConfig,
+		Margin:             margin.DefaultConfig,
+		Ledger:             ledger.DefaultConfig,
+		Wallet:             wallet.DefaultConfig,
+		Market:             marketdata.DefaultConfig,
+		HashRate:           hashrate.DefaultSamplerConfig,
+		Timestamping:       timestamping.DefaultConfig,
+		Settlement:         settlement.DefaultConfig,
+		Research:           research.DefaultConfig,
+		TradeReports:       tradereport.DefaultConfig,
+		PriceIndex:         priceindex.DefaultConfig,
+		Coordination:       coordination.DefaultConfig,
+		Alerts: alerts.Config{
+			MaxPerUser: 20,
+			SMTP: alerts.SMTPConfig{
+				Port: 587,
+			},
 		},
 	}
 
@@ -153,6 +417,10 @@ func Load(path string) (*Config, error) {
 		cfg.Bitcoin.UseTLS = bitcoinUseTLS == "true" || bitcoinUseTLS == "1"
 	}
 	
+	if bitcoinNetwork := os.Getenv("BITCOIN_NETWORK"); bitcoinNetwork != "" {
+		cfg.Bitcoin.Network = bitcoinNetwork
+	}
+	
 	if arkHost := os.Getenv("ARK_HOST"); arkHost != "" {
 		cfg.ArkASP.Host = arkHost
 	}
@@ -166,6 +434,38 @@ func Load(path string) (*Config, error) {
 	if arkPubKey := os.Getenv("ARK_PUBKEY"); arkPubKey != "" {
 		cfg.ArkASP.PubKey = arkPubKey
 	}
+	
+	if arkAuthToken := os.Getenv("ARK_AUTH_TOKEN"); arkAuthToken != "" {
+		cfg.ArkASP.AuthToken = arkAuthToken
+	}
+	
+	if proxyURL := os.Getenv("PROXY_URL"); proxyURL != "" {
+		cfg.Proxy.URL = proxyURL
+	}
+	
+	if smtpPassword := os.Getenv("ALERTS_SMTP_PASSWORD"); smtpPassword != "" {
+		cfg.Alerts.SMTP.Password = smtpPassword
+	}
+	
+	if backupKey := os.Getenv("BACKUP_KEY"); backupKey != "" {
+		cfg.Backup.Key = backupKey
+	}
+	
+	if aliasSecret := os.Getenv("PRIVACY_ALIAS_SECRET"); aliasSecret != "" {
+		cfg.Privacy.AliasSecret = aliasSecret
+	}
+	
+	if jwtSecret := os.Getenv("AUTH_JWT_SECRET"); jwtSecret != "" {
+		cfg.Auth.JWTSecret = jwtSecret
+	}
+	
+	if logLevel := os.Getenv("LOG_LEVEL"); logLevel != "" {
+		cfg.Logging.Level = logLevel
+	}
+	
+	if tracingEndpoint := os.Getenv("TRACING_ENDPOINT"); tracingEndpoint != "" {
+		cfg.Tracing.Endpoint = tracingEndpoint
+	}
 
 	// Validate the configuration
 	if err := cfg.Validate(); err != nil {
@@ -204,14 +504,303 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("Bitcoin user cannot be empty")
 	}
 	
+	if _, err := c.Bitcoin.Params(); err != nil {
+		return err
+	}
+	
+	if c.Bitcoin.Failover.Host != "" && c.Bitcoin.Failover.User == "" {
+		return fmt.Errorf("Bitcoin failover user cannot be empty when a failover host is set")
+	}
+	
+	if c.Bitcoin.PoolSize <= 0 || c.Bitcoin.CallTimeout <= 0 || c.Bitcoin.FailureThreshold <= 0 || c.Bitcoin.HealthCheckInterval <= 0 {
+		return fmt.Errorf("Bitcoin pool size, call timeout, failure threshold and health check interval must be positive")
+	}
+	
+	if c.Bitcoin.Cooldown < 0 {
+		return fmt.Errorf("Bitcoin cooldown cannot be negative")
+	}
+	
 	// ARK validation
-	if c.ArkASP.Port <= 0 || c.ArkASP.Port > 65535 {
-		return fmt.Errorf("invalid ARK port: %d", c.ArkASP.Port)
+	if err := validateEndpoint(c.ArkASP.Name, c.ArkASP.Port, c.ArkASP.TLS, c.ArkASP.AuthToken); err != nil {
+		return err
 	}
 	
 	if c.ArkASP.PubKey == "" {
 		return fmt.Errorf("ARK ASP public key cannot be empty")
 	}
+	
+	aspNames := map[string]bool{c.ArkASP.Name: true}
+	for _, failover := range c.ArkASP.Failover {
+		if err := validateEndpoint(failover.Name, failover.Port, failover.TLS, failover.AuthToken); err != nil {
+			return err
+		}
+		if failover.Host == "" {
+			return fmt.Errorf("ARK ASP %s host cannot be empty", failover.Name)
+		}
+		if aspNames[failover.Name] {
+			return fmt.Errorf("duplicate ARK ASP name %q", failover.Name)
+		}
+		aspNames[failover.Name] = true
+	}
+	
+	if c.ArkASP.CheckInterval <= 0 {
+		return fmt.Errorf("ARK ASP check interval must be positive")
+	}
+	
+	if c.ArkASP.MigrateAfter < 0 {
+		return fmt.Errorf("ARK ASP migrate after cannot be negative")
+	}
+	
+	// Retry validation
+	if err := c.Retry.Validate(); err != nil {
+		return err
+	}
+	
+	// Proxy validation
+	if err := c.Proxy.Validate(); err != nil {
+		return err
+	}
+	
+	// Logging validation
+	if _, err := zerolog.ParseLevel(c.Logging.Level); err != nil {
+		return fmt.Errorf("invalid log level: %s", c.Logging.Level)
+	}
+	
+	for name, component := range c.Logging.Components {
+		if component.Level == "" {
+			continue
+		}
+		if _, err := zerolog.ParseLevel(component.Level); err != nil {
+			return fmt.Errorf("invalid log level for component %s: %s", name, component.Level)
+		}
+	}
+	
+	// Tracing validation
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+	
+	// Jobs validation
+	if c.Jobs.PollInterval <= 0 {
+		return fmt.Errorf("job poll interval must be positive")
+	}
+	
+	if c.Jobs.BatchSize <= 0 {
+		return fmt.Errorf("job batch size must be positive")
+	}
+	
+	if c.Jobs.MaxAttempts <= 0 {
+		return fmt.Errorf("job max attempts must be positive")
+	}
+	
+	// Alerts validation
+	if c.Alerts.MaxPerUser < 0 {
+		return fmt.Errorf("alert max per user cannot be negative")
+	}
+	
+	if c.Alerts.SMTP.Host != "" && c.Alerts.SMTP.From == "" {
+		return fmt.Errorf("alert SMTP sender address cannot be empty")
+	}
+	
+	// Usage validation
+	if c.Usage.FlushInterval <= 0 {
+		return fmt.Errorf("usage flush interval must be positive")
+	}
+	
+	// Rate limit validation
+	if err := c.RateLimit.Validate(); err != nil {
+		return err
+	}
+	
+	// Rollover validation
+	if c.Rollover.Interval <= 0 {
+		return fmt.Errorf("rollover interval must be positive")
+	}
+	
+	if c.Rollover.BatchSize <= 0 {
+		return fmt.Errorf("rollover batch size must be positive")
+	}
+	
+	// Hash rate sampler validation
+	if c.HashRate.Interval <= 0 {
+		return fmt.Errorf("hash rate sample interval must be positive")
+	}
+	
+	// Backup validation
+	if c.Backup.Enabled() {
+		if _, err := backup.ParseKey(c.Backup.Key); err != nil {
+			return err
+		}
+		
+		if c.Backup.Interval <= 0 {
+			return fmt.Errorf("backup interval must be positive")
+		}
+	}
+	
+	// Privacy validation
+	if err := c.Privacy.Validate(); err != nil {
+		return err
+	}
+	
+	// Auth validation
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+	
+	// Order book validation
+	if err := c.OrderBook.Validate(); err != nil {
+		return err
+	}
+	
+	// Fee policy validation
+	if err := c.FeePolicy.Validate(); err != nil {
+		return err
+	}
+	
+	// Scheduled close validation
+	if err := c.ScheduledClose.Validate(); err != nil {
+		return err
+	}
+	
+	// Exit monitor validation
+	if err := c.ExitMonitor.Validate(); err != nil {
+		return err
+	}
+	
+	// Confirmation watcher validation
+	if err := c.Confirmations.Validate(); err != nil {
+		return err
+	}
+	
+	// Settlement tracking validation
+	if err := c.SettlementTracking.Validate(); err != nil {
+		return err
+	}
+	
+	// Broadcast check validation
+	if err := c.BroadcastChecks.Validate(); err != nil {
+		return err
+	}
+	
+	// Attestation oracle validation
+	if err := c.Oracle.Validate(); err != nil {
+		return err
+	}
+	
+	// Margin validation
+	if err := c.Margin.Validate(); err != nil {
+		return err
+	}
+	
+	// Deposit and withdrawal validation; both move funds in the ledger
+	if err := c.Wallet.Validate(); err != nil {
+		return err
+	}
+	if c.Wallet.Enabled() && !c.Ledger.Enabled {
+		return fmt.Errorf("deposits require the ledger to be enabled")
+	}
+	if c.Wallet.Withdrawals.Enabled && !c.Ledger.Enabled {
+		return fmt.Errorf("withdrawals require the ledger to be enabled")
+	}
+	
+	// Market data validation
+	if err := c.Market.Validate(); err != nil {
+		return err
+	}
+	
+	// Timestamping validation
+	if err := c.Timestamping.Validate(); err != nil {
+		return err
+	}
+	
+	// Settlement oracle validation
+	if err := c.Settlement.Validate(); err != nil {
+		return err
+	}
+	
+	// Research feed validation
+	if err := c.Research.Validate(); err != nil {
+		return err
+	}
+	
+	// Trade report validation
+	if err := c.TradeReports.Validate(); err != nil {
+		return err
+	}
+	
+	// Price index validation
+	if err := c.PriceIndex.Validate(); err != nil {
+		return err
+	}
+	
+	// Coordination validation
+	if err := c.Coordination.Validate(); err != nil {
+		return err
+	}
+	
+	// Push validation
+	if c.Push.APNs.Enabled() && (c.Push.APNs.KeyID == "" || c.Push.APNs.TeamID == "" || c.Push.APNs.BundleID == "") {
+		return fmt.Errorf("APNs key ID, team ID and bundle ID are required when a key path is set")
+	}
+	
+	// Webhook validation
+	if err := c.Webhooks.Validate(); err != nil {
+		return err
+	}
+	
+	// Feeds validation
+	if err := c.Feeds.Validate(); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+// Schedule returns the interval of each background task by name. Tasks
+// that are disabled by the configuration have a zero interval.
+func (c *Config) Schedule() map[string]time.Duration {
+	schedule := map[string]time.Duration{
+		"order_book.expiry_sweep":    c.OrderBook.Schedule.ExpirySweep,
+		"order_book.reload":          c.OrderBook.Schedule.Reload,
+		"order_book.reconcile":       c.OrderBook.Schedule.Reconcile,
+		"jobs.poll":                  c.Jobs.PollInterval,
+		"usage.flush":                c.Usage.FlushInterval,
+		"webhooks.deliver":           c.Webhooks.Interval,
+		"http.rate_limit_sweep":      0,
+		"rollover":                   c.Rollover.Interval,
+		"settlement.oracle":          c.Settlement.Interval,
+		"settlement.release":         c.FeePolicy.ReleaseInterval,
+		"settlement.scheduled_close": c.ScheduledClose.Interval,
+		"ark.exit_monitor":           c.ExitMonitor.Interval,
+		"contract.confirmations":     c.Confirmations.Interval,
+		"settlement.rebroadcast":     c.SettlementTracking.RebroadcastInterval,
+		"margin.mark":                c.Margin.Interval,
+		"hash_rate.sample":           c.HashRate.Interval,
+		"backup.export":              0,
+		"timestamping.anchor":        0,
+		"research.snapshot":          c.Research.SnapshotInterval,
+		"research.dump":              0,
+	}
+
+	if c.RateLimit.Enabled {
+		schedule["http.rate_limit_sweep"] = c.RateLimit.IdleTTL
+	}
+
+	if c.Backup.Enabled() {
+		schedule["backup.export"] = c.Backup.Interval
+	}
+
+	if c.Timestamping.Enabled() {
+		schedule["timestamping.anchor"] = c.Timestamping.Interval
+	}
+
+	if c.Research.DumpsEnabled() {
+		schedule["research.dump"] = c.Research.DumpInterval
+	}
+
+	for _, feed := range c.Feeds.Feeds {
+		schedule["feeds."+feed.Name] = feed.Interval
+	}
+
+	return schedule
+}
@@ -0,0 +1,108 @@
+// internal/contract/asp_migration.go
+package contract
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	arkv1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// MigrateASP moves the contracts whose VTXOs were issued by an ASP that is
+// down for good onto ASP to. Each contract's emergency exit is broadcast and
+// its output registered in the next round of the new ASP, which re-enters
+// the exited funds as a new VTXO. A contract whose exit cannot be broadcast
+// stays on the old ASP and does not stop the others. It returns how many
+// contracts were migrated.
+func (s *Service) MigrateASP(ctx context.Context, from, to string) (int, error) {
+	if s.vtxoRepo == nil {
+		return 0, ErrVTXOsNotEnabled
+	}
+
+	vtxos, err := s.vtxoRepo.ListActiveByASP(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list VTXOs of ASP %s: %w", from, err)
+	}
+
+	migrated := 0
+	for _, vtxo := range vtxos {
+		if err := s.migrateVTXO(ctx, vtxo, to); err != nil {
+			logger.Error().
+				Err(err).
+				Str("contract_id", vtxo.ContractID.String()).
+				Str("vtxo_id", vtxo.VTXOID).
+				Str("from", from).
+				Msg("Failed to migrate VTXO to another ASP")
+			continue
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// migrateVTXO exits one contract's VTXO and re-enters its funds through ASP to
+func (s *Service) migrateVTXO(ctx context.Context, vtxo *models.VTXO, to string) error {
+	contract, err := s.contractRepo.GetByID(ctx, vtxo.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract.Status != models.ContractStatusActive {
+		return fmt.Errorf("contract is %s, not active", contract.Status)
+	}
+
+	if s.exitContract(ctx, contract) == 0 {
+		return fmt.Errorf("contract has no emergency exit to broadcast")
+	}
+
+	// Calls are routed to the new primary, which the re-entry output
+	// registers with
+	response, err := s.arkClient.RegisterOutputsForNextRound(ctx, []*arkv1.Output{{
+		Value:   vtxo.Amount,
+		Address: vtxo.Script,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to register re-entry with ASP %s: %w", to, err)
+	}
+
+	txRecord := &models.ContractTransaction{
+		ID:            uuid.New(),
+		ContractID:    contract.ID,
+		TransactionID: response.GetRoundId(),
+		TxType:        "asp_reentry",
+		Confirmed:     false,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.contractRepo.AddTransaction(ctx, txRecord); err != nil {
+		return fmt.Errorf("failed to add transaction: %w", err)
+	}
+
+	next := &models.VTXO{
+		ContractID: contract.ID,
+		VTXOID:     models.VTXOOutpoint(response.GetRoundId(), 0),
+		RoundID:    response.GetRoundId(),
+		Amount:     vtxo.Amount,
+		Script:     vtxo.Script,
+		ASP:        to,
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid VTXO: %w", err)
+	}
+	if err := s.vtxoRepo.Replace(ctx, vtxo.VTXOID, next); err != nil {
+		return fmt.Errorf("failed to replace VTXO: %w", err)
+	}
+
+	// The new VTXO has no emergency exit yet
+	s.emergencyExitReady.Store(false)
+
+	logger.Info().
+		Str("contract_id", contract.ID.String()).
+		Str("round_id", response.GetRoundId()).
+		Str("asp", to).
+		Msg("Migrated contract VTXO to another ASP")
+	return nil
+}
@@ -0,0 +1,230 @@
+// internal/contract/attestation.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+var (
+	// ErrOracleNotEnabled is returned when no attestation oracle is configured
+	ErrOracleNotEnabled = errors.New("oracle attestations are not enabled")
+	// ErrAttestationRequired is returned when settling a contract whose
+	// oracle has not yet attested the outcome
+	ErrAttestationRequired = errors.New("contract settles on an oracle attestation that has not been submitted")
+	// ErrInvalidAttestation is returned for an attestation that does not
+	// verify against the announced oracle event
+	ErrInvalidAttestation = errors.New("invalid oracle attestation")
+)
+
+// OracleConfig identifies the oracle trusted to attest contract outcomes
+type OracleConfig struct {
+	// PubKey is the oracle's hex encoded x-only public key. Empty disables
+	// oracle settlement.
+	PubKey string `yaml:"pub_key"`
+}
+
+// Enabled reports whether an oracle is configured
+func (c OracleConfig) Enabled() bool {
+	return c.PubKey != ""
+}
+
+// Validate checks the oracle key
+func (c OracleConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if len(c.PubKey) != 64 {
+		return fmt.Errorf("attestation oracle public key must be a 32 byte x-only key")
+	}
+	if _, err := taproot.ParsePubKey(c.PubKey); err != nil {
+		return fmt.Errorf("invalid attestation oracle public key: %w", err)
+	}
+	return nil
+}
+
+// Attestation is an oracle's signed outcome of a contract's event
+type Attestation struct {
+	Outcome          taproot.OracleOutcome `json:"outcome"`
+	ObservedHashRate float64               `json:"observed_hash_rate"` // In EH/s
+	Signature        string                `json:"signature"`
+}
+
+// WithOracle enables settlement on outcomes attested by an oracle
+func (s *Service) WithOracle(store OracleEventStore, cfg OracleConfig) *Service {
+	s.oracleRepo = store
+	s.oracle = cfg
+	return s
+}
+
+// oracleEvent returns the taproot event of a stored oracle event
+func oracleEvent(event *models.OracleEvent) taproot.OracleEvent {
+	return taproot.OracleEvent{
+		PubKey:  event.OraclePubKey,
+		Nonce:   event.Nonce,
+		EventID: event.EventID,
+	}
+}
+
+// verifyAttestation checks that an attestation is signed by the oracle with
+// the nonce it announced for the contract, and that the outcome follows
+// from the observed hash rate and the contract's strike
+func verifyAttestation(contract *models.Contract, event *models.OracleEvent, attestation Attestation) error {
+	if attestation.ObservedHashRate <= 0 {
+		return fmt.Errorf("%w: observed hash rate must be positive", ErrInvalidAttestation)
+	}
+
+	high := attestation.ObservedHashRate >= contract.StrikeHashRate
+	if high != (attestation.Outcome == taproot.OracleOutcomeHigh) {
+		return fmt.Errorf("%w: outcome %s does not follow from %.2f EH/s against a %.2f EH/s strike",
+			ErrInvalidAttestation, attestation.Outcome, attestation.ObservedHashRate, contract.StrikeHashRate)
+	}
+
+	if err := taproot.VerifyAttestation(oracleEvent(event), attestation.Outcome, attestation.Signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+	}
+
+	return nil
+}
+
+// attestedBuyerWins reports whether the buyer wins an attested outcome: a
+// call pays the buyer when the hash rate is high, a put when it is low
+func attestedBuyerWins(outcome string, contractType models.ContractType) bool {
+	high := outcome == string(taproot.OracleOutcomeHigh)
+	return high == (contractType == models.ContractTypeCall)
+}
+
+// AnnounceOracleEvent records the event a contract settles on, with the
+// nonce the oracle will sign its outcome with. The final output commits to
+// the event, so it must be announced before the final transaction is built.
+func (s *Service) AnnounceOracleEvent(ctx context.Context, contractID uuid.UUID, nonce, eventID string) (*models.OracleEvent, error) {
+	if s.oracleRepo == nil || !s.oracle.Enabled() {
+		return nil, ErrOracleNotEnabled
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract.FinalTxID != nil || contract.Status == models.ContractStatusSettled || contract.Status == models.ContractStatusPendingSettlement {
+		return nil, fmt.Errorf("oracle event must be announced before the final transaction is built")
+	}
+
+	event := &models.OracleEvent{
+		ContractID:   contractID,
+		OraclePubKey: s.oracle.PubKey,
+		Nonce:        nonce,
+		EventID:      eventID,
+	}
+	if err := oracleEvent(event).Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.oracleRepo.Create(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record oracle event: %w", err)
+	}
+
+	logger.Info().
+		Str("contract_id", contractID.String()).
+		Str("event_id", eventID).
+		Msg("Announced oracle event")
+
+	return event, nil
+}
+
+// GetOracleEvent returns the oracle event of a contract, or nil if it
+// settles without an oracle
+func (s *Service) GetOracleEvent(ctx context.Context, contractID uuid.UUID) (*models.OracleEvent, error) {
+	if s.oracleRepo == nil {
+		return nil, ErrOracleNotEnabled
+	}
+	return s.oracleRepo.GetByContract(ctx, contractID)
+}
+
+// SubmitAttestation verifies and records the oracle's attestation of a
+// contract's outcome, which its settlement then pays out on
+func (s *Service) SubmitAttestation(ctx context.Context, contractID uuid.UUID, attestation Attestation) (*models.OracleEvent, error) {
+	if s.oracleRepo == nil {
+		return nil, ErrOracleNotEnabled
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	event, err := s.oracleRepo.GetByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oracle event: %w", err)
+	}
+	if event == nil {
+		return nil, fmt.Errorf("%w: contract has no oracle event", ErrInvalidAttestation)
+	}
+
+	if err := verifyAttestation(contract, event, attestation); err != nil {
+		return nil, err
+	}
+
+	outcome := string(attestation.Outcome)
+	event.Outcome = &outcome
+	event.ObservedHashRate = &attestation.ObservedHashRate
+	event.Signature = &attestation.Signature
+
+	if err := s.oracleRepo.Attest(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record attestation: %w", err)
+	}
+
+	logger.Info().
+		Str("contract_id", contractID.String()).
+		Str("outcome", outcome).
+		Float64("observed_hash_rate", attestation.ObservedHashRate).
+		Msg("Recorded oracle attestation")
+
+	return event, nil
+}
+
+// contractOracleEvent returns the oracle event a contract settles on, or
+// nil if it settles on the chain alone
+func (s *Service) contractOracleEvent(ctx context.Context, contractID uuid.UUID) (*models.OracleEvent, error) {
+	if s.oracleRepo == nil {
+		return nil, nil
+	}
+
+	event, err := s.oracleRepo.GetByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oracle event: %w", err)
+	}
+	return event, nil
+}
+
+// finalLeaves returns the leaves of a contract's final output, including
+// the outcome leaves of its oracle event, if any
+func (s *Service) finalLeaves(contract *models.Contract, event *models.OracleEvent) ([][]byte, error) {
+	isCall := contract.ContractType == models.ContractTypeCall
+	if event == nil {
+		return s.taprootScriptBuilder.FinalLeaves(
+			contract.BuyerPubKey, contract.SellerPubKey, contract.EndBlockHeight, contract.TargetTimestamp, isCall)
+	}
+	return s.taprootScriptBuilder.OracleFinalLeaves(
+		contract.BuyerPubKey, contract.SellerPubKey, contract.EndBlockHeight, contract.TargetTimestamp, isCall,
+		oracleEvent(event))
+}
+
+// buildFinalScript returns the address of a contract's final output,
+// committing to the outcome leaves of its oracle event, if any
+func (s *Service) buildFinalScript(contract *models.Contract, event *models.OracleEvent) (string, error) {
+	isCall := contract.ContractType == models.ContractTypeCall
+	if event == nil {
+		return s.taprootScriptBuilder.BuildFinalScript(
+			contract.BuyerPubKey, contract.SellerPubKey, contract.EndBlockHeight, contract.TargetTimestamp, isCall)
+	}
+	return s.taprootScriptBuilder.BuildOracleFinalScript(
+		contract.BuyerPubKey, contract.SellerPubKey, contract.EndBlockHeight, contract.TargetTimestamp, isCall,
+		oracleEvent(event))
+}
@@ -0,0 +1,36 @@
+// internal/contract/attestation_test.go
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+func TestAttestedBuyerWins(t *testing.T) {
+	assert.True(t, attestedBuyerWins(string(taproot.OracleOutcomeHigh), models.ContractTypeCall))
+	assert.False(t, attestedBuyerWins(string(taproot.OracleOutcomeLow), models.ContractTypeCall))
+	assert.True(t, attestedBuyerWins(string(taproot.OracleOutcomeLow), models.ContractTypePut))
+	assert.False(t, attestedBuyerWins(string(taproot.OracleOutcomeHigh), models.ContractTypePut))
+}
+
+func TestVerifyAttestationOutcome(t *testing.T) {
+	contract := &models.Contract{StrikeHashRate: 500}
+	event := &models.OracleEvent{}
+
+	// An outcome that contradicts the observed rate is rejected before the
+	// signature is checked
+	err := verifyAttestation(contract, event, Attestation{Outcome: taproot.OracleOutcomeLow, ObservedHashRate: 510})
+	assert.ErrorIs(t, err, ErrInvalidAttestation)
+
+	err = verifyAttestation(contract, event, Attestation{Outcome: taproot.OracleOutcomeHigh, ObservedHashRate: 0})
+	assert.ErrorIs(t, err, ErrInvalidAttestation)
+}
+
+func TestOracleConfigValidate(t *testing.T) {
+	assert.NoError(t, OracleConfig{}.Validate())
+	assert.Error(t, OracleConfig{PubKey: "02abcd"}.Validate())
+}
@@ -0,0 +1,159 @@
+// internal/contract/batch_settlement.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// Worker limits of a batch settlement
+const (
+	DefaultSettlementWorkers = 4
+	MaxSettlementWorkers     = 16
+)
+
+// Outcomes of a contract in a batch settlement
+const (
+	BatchOutcomeSettled  = "SETTLED"
+	BatchOutcomeDeferred = "DEFERRED"
+	BatchOutcomeFailed   = "FAILED"
+)
+
+// BatchSettlementResult is the outcome of settling one contract in a batch
+type BatchSettlementResult struct {
+	ContractID     uuid.UUID `json:"contract_id"`
+	Outcome        string    `json:"outcome"`
+	SettlementTxID string    `json:"settlement_tx_id,omitempty"`
+	BuyerWins      *bool     `json:"buyer_wins,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// BatchSettlementReport summarizes a batch settlement
+type BatchSettlementReport struct {
+	BlockHeight int64                    `json:"block_height"`
+	Eligible    int                      `json:"eligible"`
+	Settled     int                      `json:"settled"`
+	Deferred    int                      `json:"deferred"`
+	Failed      int                      `json:"failed"`
+	StartedAt   time.Time                `json:"started_at"`
+	Duration    string                   `json:"duration"`
+	Results     []*BatchSettlementResult `json:"results"`
+}
+
+// settleBatch settles contracts on a bounded number of workers and returns
+// their results in the order of the IDs
+func settleBatch(ids []uuid.UUID, workers int, settle func(uuid.UUID) *BatchSettlementResult) []*BatchSettlementResult {
+	results := make([]*BatchSettlementResult, len(ids))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(ids); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = settle(ids[i])
+			}
+		}()
+	}
+
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// batchOutcome classifies the error of settling a contract in a batch
+func batchOutcome(err error) string {
+	switch {
+	case err == nil:
+		return BatchOutcomeSettled
+	case errors.Is(err, ErrSettlementDeferred), errors.Is(err, ErrAttestationRequired):
+		return BatchOutcomeDeferred
+	default:
+		return BatchOutcomeFailed
+	}
+}
+
+// SettleAllEligible settles every active contract whose end height or
+// target time has passed, on up to workers contracts at once. A contract
+// that fails to settle does not stop the others; its error is reported in
+// its result.
+func (s *Service) SettleAllEligible(ctx context.Context, workers int) (*BatchSettlementReport, error) {
+	if workers < 1 || workers > MaxSettlementWorkers {
+		return nil, fmt.Errorf("workers must be between 1 and %d", MaxSettlementWorkers)
+	}
+
+	report := &BatchSettlementReport{StartedAt: time.Now().UTC()}
+
+	tip, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.BlockHeight = tip
+
+	// List every active contract before settling any, since settled
+	// contracts drop out of the list and would shift the pages
+	var eligible []uuid.UUID
+	for offset := 0; ; offset += exitBatchSize {
+		page, err := s.contractRepo.ListByStatus(ctx, models.ContractStatusActive, exitBatchSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list active contracts: %w", err)
+		}
+		for _, c := range page {
+			if tip >= c.EndBlockHeight || report.StartedAt.After(c.TargetTimestamp) {
+				eligible = append(eligible, c.ID)
+			}
+		}
+		if len(page) < exitBatchSize {
+			break
+		}
+	}
+	report.Eligible = len(eligible)
+
+	report.Results = settleBatch(eligible, workers, func(id uuid.UUID) *BatchSettlementResult {
+		result := &BatchSettlementResult{ContractID: id}
+
+		tx, buyerWins, err := s.SettleContract(ctx, id)
+		result.Outcome = batchOutcome(err)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		result.SettlementTxID = tx.TransactionID
+		result.BuyerWins = &buyerWins
+		return result
+	})
+
+	for _, result := range report.Results {
+		switch result.Outcome {
+		case BatchOutcomeSettled:
+			report.Settled++
+		case BatchOutcomeDeferred:
+			report.Deferred++
+		default:
+			report.Failed++
+		}
+	}
+	report.Duration = time.Since(report.StartedAt).String()
+
+	logger.Info().
+		Int("eligible", report.Eligible).
+		Int("settled", report.Settled).
+		Int("deferred", report.Deferred).
+		Int("failed", report.Failed).
+		Msg("Batch settlement finished")
+
+	return report, nil
+}
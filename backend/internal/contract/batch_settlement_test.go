@@ -0,0 +1,48 @@
+// internal/contract/batch_settlement_test.go
+package contract
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettleBatch(t *testing.T) {
+	ids := make([]uuid.UUID, 20)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	var running, peak int32
+	results := settleBatch(ids, 3, func(id uuid.UUID) *BatchSettlementResult {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		return &BatchSettlementResult{ContractID: id}
+	})
+
+	require.Len(t, results, len(ids))
+	for i, result := range results {
+		assert.Equal(t, ids[i], result.ContractID)
+	}
+	assert.LessOrEqual(t, int(peak), 3)
+
+	assert.Empty(t, settleBatch(nil, 3, nil))
+}
+
+func TestBatchOutcome(t *testing.T) {
+	assert.Equal(t, BatchOutcomeSettled, batchOutcome(nil))
+	assert.Equal(t, BatchOutcomeDeferred, batchOutcome(fmt.Errorf("settle: %w", ErrSettlementDeferred)))
+	assert.Equal(t, BatchOutcomeDeferred, batchOutcome(ErrAttestationRequired))
+	assert.Equal(t, BatchOutcomeFailed, batchOutcome(errors.New("broadcast failed")))
+}
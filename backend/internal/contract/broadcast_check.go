@@ -0,0 +1,321 @@
+// internal/contract/broadcast_check.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/taproot"
+)
+
+// ErrTransactionRejected is returned when a transaction fails the checks
+// made before it is broadcast
+var ErrTransactionRejected = errors.New("transaction rejected before broadcast")
+
+// Reasons a transaction is rejected before broadcast, as counted in metrics
+const (
+	rejectMalformed = "malformed"
+	rejectOutputs   = "outputs"
+	rejectFee       = "fee"
+	rejectScript    = "script"
+)
+
+// BroadcastCheckConfig bounds the fees of transactions checked before they
+// are broadcast. A zero bound is not checked.
+type BroadcastCheckConfig struct {
+	// MinFeeRate is the lowest fee rate in sat/vB of a signed transaction
+	MinFeeRate float64 `yaml:"min_fee_rate"`
+	// MaxFeeRate is the highest fee rate in sat/vB of a signed transaction
+	MaxFeeRate float64 `yaml:"max_fee_rate"`
+	// MaxFeeShare is the largest fraction of the input value paid in fees
+	MaxFeeShare float64 `yaml:"max_fee_share"`
+}
+
+// DefaultBroadcastCheckConfig rejects fees below the relay minimum, above
+// 1000 sat/vB or above a tenth of the value spent
+var DefaultBroadcastCheckConfig = BroadcastCheckConfig{
+	MinFeeRate:  1,
+	MaxFeeRate:  1000,
+	MaxFeeShare: 0.1,
+}
+
+// Validate checks that the fee bounds are usable
+func (c BroadcastCheckConfig) Validate() error {
+	if c.MinFeeRate < 0 || c.MaxFeeRate < 0 {
+		return fmt.Errorf("broadcast check fee rates cannot be negative")
+	}
+	if c.MaxFeeRate > 0 && c.MinFeeRate > c.MaxFeeRate {
+		return fmt.Errorf("broadcast check minimum fee rate exceeds the maximum")
+	}
+	if c.MaxFeeShare < 0 || c.MaxFeeShare > 1 {
+		return fmt.Errorf("broadcast check fee share must be between 0 and 1")
+	}
+	return nil
+}
+
+// WithBroadcastChecks sets the fee bounds of transactions checked before
+// broadcast. Without it only their encoding, outputs and scripts are checked.
+func (s *Service) WithBroadcastChecks(cfg BroadcastCheckConfig) *Service {
+	s.broadcastCheck = cfg
+	return s
+}
+
+// checkBroadcast decodes txHex, the raw form of tx, and checks it before it
+// is broadcast: its outputs must pay the contract's taproot commitments, its
+// fee must be within bounds, and every signed input must pass the script
+// engine. A transaction that fails is counted, logged and announced, and
+// ErrTransactionRejected is returned so it is not broadcast.
+func (s *Service) checkBroadcast(ctx context.Context, tx *models.ContractTransaction, txHex string) error {
+	reason, err := s.checkTransaction(ctx, tx, txHex)
+	if err == nil {
+		return nil
+	}
+
+	metrics.BroadcastRejections.WithLabelValues(reason).Inc()
+	logger.Error().Err(err).
+		Str("contractID", tx.ContractID.String()).
+		Str("txid", tx.TransactionID).
+		Str("tx_type", tx.TxType).
+		Str("reason", reason).
+		Msg("Transaction rejected before broadcast")
+
+	if s.events != nil {
+		s.events.Publish(events.TopicContracts, events.ContractEvent{
+			Type:          events.ContractTransactionRejected,
+			ContractID:    tx.ContractID,
+			TransactionID: tx.TransactionID,
+			TxType:        tx.TxType,
+			Reason:        reason,
+			Time:          time.Now().UTC(),
+		})
+	}
+
+	return fmt.Errorf("%w: %v", ErrTransactionRejected, err)
+}
+
+// checkTransaction runs the checks of checkBroadcast and returns the reason
+// of the first that fails
+func (s *Service) checkTransaction(ctx context.Context, tx *models.ContractTransaction, txHex string) (string, error) {
+	msgTx, err := bitcoin.ParseTransactionHex(txHex, bitcoin.DefaultParseLimits)
+	if err != nil {
+		return rejectMalformed, err
+	}
+
+	prevOuts, err := spentOutputs(tx, msgTx)
+	if err != nil {
+		return rejectMalformed, err
+	}
+
+	spends, pays, err := s.contractScripts(ctx, tx)
+	if err != nil {
+		return rejectOutputs, err
+	}
+	if err := checkScripts(msgTx, prevOuts, spends, pays); err != nil {
+		return rejectOutputs, err
+	}
+	if tx.TxType == "settlement" && len(msgTx.TxOut) != 1 {
+		return rejectOutputs, fmt.Errorf("settlement has %d outputs, expected a single payout", len(msgTx.TxOut))
+	}
+
+	if err := checkFee(msgTx, prevOuts, s.broadcastCheck); err != nil {
+		return rejectFee, err
+	}
+	if err := verifyInputs(msgTx, prevOuts); err != nil {
+		return rejectScript, err
+	}
+
+	return "", nil
+}
+
+// spentOutputs returns the outputs spent by msgTx, taken from the spend
+// PSBT of tx, or nil if it has none. The PSBT must be of the same
+// transaction.
+func spentOutputs(tx *models.ContractTransaction, msgTx *wire.MsgTx) (txscript.PrevOutputFetcher, error) {
+	if tx.SpendPSBT == nil {
+		return nil, nil
+	}
+
+	packet, err := bitcoin.ParsePSBT(*tx.SpendPSBT, bitcoin.DefaultParseLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spend PSBT: %w", err)
+	}
+	if packet.UnsignedTx.TxHash() != msgTx.TxHash() {
+		return nil, fmt.Errorf("spend PSBT is of transaction %s, not %s", packet.UnsignedTx.TxHash(), msgTx.TxHash())
+	}
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range packet.Inputs {
+		if in.WitnessUtxo == nil {
+			return nil, fmt.Errorf("spend PSBT input %d has no spent output", i)
+		}
+		prevOuts.AddPrevOut(packet.UnsignedTx.TxIn[i].PreviousOutPoint, in.WitnessUtxo)
+	}
+	return prevOuts, nil
+}
+
+// contractScripts returns the output scripts a contract transaction must
+// spend and pay: a final transaction spends the setup output and pays the
+// final output, and a settlement spends the final output to the winner's
+// payout. Other transactions have none.
+func (s *Service) contractScripts(ctx context.Context, tx *models.ContractTransaction) (spends, pays []byte, err error) {
+	if tx.TxType != "final" && tx.TxType != "settlement" {
+		return nil, nil, nil
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, tx.ContractID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+	event, err := s.contractOracleEvent(ctx, contract.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	finalLeaves, err := s.finalLeaves(contract, event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build final leaves: %w", err)
+	}
+	finalScript, err := leavesScript(contract.BuyerPubKey, finalLeaves)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tx.TxType == "settlement" {
+		return finalScript, nil, nil
+	}
+
+	setupLeaves, err := s.setupLeaves(contract)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build setup leaves: %w", err)
+	}
+	setupScript, err := leavesScript(contract.BuyerPubKey, setupLeaves)
+	if err != nil {
+		return nil, nil, err
+	}
+	return setupScript, finalScript, nil
+}
+
+// leavesScript returns the P2TR output script committing to leaves
+func leavesScript(internalKey string, leaves [][]byte) ([]byte, error) {
+	spender, err := taproot.NewSpendBuilder(internalKey, leaves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build script tree: %w", err)
+	}
+	return spender.PkScript()
+}
+
+// checkScripts checks that every input of msgTx spends an output with the
+// script spends, and every output pays pays. A nil script is not checked.
+func checkScripts(msgTx *wire.MsgTx, prevOuts txscript.PrevOutputFetcher, spends, pays []byte) error {
+	if spends != nil {
+		if prevOuts == nil {
+			return errors.New("spent outputs are unknown")
+		}
+		for i, in := range msgTx.TxIn {
+			prevOut := prevOuts.FetchPrevOutput(in.PreviousOutPoint)
+			if prevOut == nil || !bytes.Equal(prevOut.PkScript, spends) {
+				return fmt.Errorf("input %d does not spend the contract output", i)
+			}
+		}
+	}
+
+	for i, out := range msgTx.TxOut {
+		if pays != nil && !bytes.Equal(out.PkScript, pays) {
+			return fmt.Errorf("output %d does not pay the contract output", i)
+		}
+		if out.Value == 0 && txscript.GetScriptClass(out.PkScript) != txscript.NullDataTy {
+			return fmt.Errorf("output %d pays nothing", i)
+		}
+		if out.Value > 0 && txscript.GetScriptClass(out.PkScript) == txscript.NullDataTy {
+			return fmt.Errorf("output %d burns %d sats", i, out.Value)
+		}
+	}
+
+	return nil
+}
+
+// checkFee checks the fee of msgTx against the bounds. It is only known
+// when the spent outputs are, and its rate only once every input is signed,
+// since unsigned inputs do not count their witness.
+func checkFee(msgTx *wire.MsgTx, prevOuts txscript.PrevOutputFetcher, cfg BroadcastCheckConfig) error {
+	if prevOuts == nil {
+		return nil
+	}
+
+	var in, out int64
+	signed := true
+	for i, txIn := range msgTx.TxIn {
+		prevOut := prevOuts.FetchPrevOutput(txIn.PreviousOutPoint)
+		if prevOut == nil {
+			return fmt.Errorf("output spent by input %d is unknown", i)
+		}
+		in += prevOut.Value
+		signed = signed && len(txIn.Witness) > 0
+	}
+	for _, txOut := range msgTx.TxOut {
+		out += txOut.Value
+	}
+
+	fee := in - out
+	if fee < 0 {
+		return fmt.Errorf("outputs of %d sats exceed inputs of %d sats", out, in)
+	}
+	if cfg.MaxFeeShare > 0 && float64(fee) > cfg.MaxFeeShare*float64(in) {
+		return fmt.Errorf("fee of %d sats exceeds %.0f%% of the %d sats spent", fee, cfg.MaxFeeShare*100, in)
+	}
+	if !signed {
+		return nil
+	}
+
+	weight := msgTx.SerializeSizeStripped()*3 + msgTx.SerializeSize()
+	vsize := (weight + 3) / 4
+	feeRate := float64(fee) / float64(vsize)
+	if cfg.MinFeeRate > 0 && feeRate < cfg.MinFeeRate {
+		return fmt.Errorf("fee rate of %.2f sat/vB is below %.2f sat/vB", feeRate, cfg.MinFeeRate)
+	}
+	if cfg.MaxFeeRate > 0 && feeRate > cfg.MaxFeeRate {
+		return fmt.Errorf("fee rate of %.2f sat/vB exceeds %.2f sat/vB", feeRate, cfg.MaxFeeRate)
+	}
+
+	return nil
+}
+
+// verifyInputs runs the script engine on every signed input of msgTx.
+// Unsigned inputs are left to the node, which rejects them, as are inputs
+// whose spent output is unknown.
+func verifyInputs(msgTx *wire.MsgTx, prevOuts txscript.PrevOutputFetcher) error {
+	if prevOuts == nil {
+		return nil
+	}
+
+	sigHashes := txscript.NewTxSigHashes(msgTx, prevOuts)
+	for i, in := range msgTx.TxIn {
+		if len(in.Witness) == 0 && len(in.SignatureScript) == 0 {
+			continue
+		}
+		prevOut := prevOuts.FetchPrevOutput(in.PreviousOutPoint)
+		if prevOut == nil {
+			continue
+		}
+
+		engine, err := txscript.NewEngine(prevOut.PkScript, msgTx, i, txscript.StandardVerifyFlags,
+			nil, sigHashes, prevOut.Value, prevOuts)
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+		if err := engine.Execute(); err != nil {
+			return fmt.Errorf("input %d does not satisfy its script: %w", i, err)
+		}
+	}
+
+	return nil
+}
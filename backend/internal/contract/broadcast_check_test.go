@@ -0,0 +1,125 @@
+// internal/contract/broadcast_check_test.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+func TestCheckBroadcast(t *testing.T) {
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	contract := &models.Contract{
+		ID:              uuid.New(),
+		ContractType:    models.ContractTypeCall,
+		Status:          models.ContractStatusActive,
+		BuyerPubKey:     hex.EncodeToString(buyer.PubKey().SerializeCompressed()),
+		SellerPubKey:    hex.EncodeToString(seller.PubKey().SerializeCompressed()),
+		EndBlockHeight:  900_000,
+		TargetTimestamp: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	published := &recordingPublisher{}
+	chain := &recordingChain{}
+	s := &Service{
+		contractRepo:         &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: contract}},
+		bitcoinClient:        chain,
+		taprootScriptBuilder: taproot.NewScriptBuilder(),
+		events:               published,
+		broadcastCheck:       DefaultBroadcastCheckConfig,
+	}
+
+	setupLeaves, err := s.setupLeaves(contract)
+	require.NoError(t, err)
+	setupSpender, err := taproot.NewSpendBuilder(contract.BuyerPubKey, setupLeaves)
+	require.NoError(t, err)
+	setupScript, err := setupSpender.PkScript()
+	require.NoError(t, err)
+	finalLeaves, err := s.finalLeaves(contract, nil)
+	require.NoError(t, err)
+	finalScript, err := leavesScript(contract.BuyerPubKey, finalLeaves)
+	require.NoError(t, err)
+
+	// finalTx builds a final transaction spending the setup output through
+	// the high hash rate path, as GenerateFinalTransaction does
+	finalTx := func(pkScript []byte, edit func(*wire.MsgTx)) *models.ContractTransaction {
+		inputs := []sweepInput{{
+			OutPoint: wire.OutPoint{Hash: chainhash.Hash{1}},
+			Value:    100_000,
+			Leaf:     1,
+			Spend:    taproot.LeafSpends(setupLeaves)[1],
+		}}
+		tx, err := buildSweepTx(inputs, pkScript, 2)
+		require.NoError(t, err)
+		if edit != nil {
+			edit(tx)
+		}
+		spendPSBT, err := prepareSpends(tx, inputs, setupSpender)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, tx.Serialize(&buf))
+		return &models.ContractTransaction{
+			ContractID:    contract.ID,
+			TransactionID: tx.TxHash().String(),
+			TxType:        "final",
+			TxHex:         hex.EncodeToString(buf.Bytes()),
+			SpendPSBT:     &spendPSBT,
+		}
+	}
+	rejected := func(tx *models.ContractTransaction, reason string) {
+		err := s.checkBroadcast(context.Background(), tx, tx.TxHex)
+		assert.ErrorIs(t, err, ErrTransactionRejected)
+		require.NotEmpty(t, *published)
+		last := (*published)[len(*published)-1]
+		assert.Equal(t, events.ContractTransactionRejected, last.Type)
+		assert.Equal(t, reason, last.Reason)
+	}
+
+	// An unsigned final transaction paying the final output passes, its
+	// missing witness left to the signers
+	valid := finalTx(finalScript, nil)
+	assert.NoError(t, s.checkBroadcast(context.Background(), valid, valid.TxHex))
+	assert.Empty(t, *published)
+
+	rejected(&models.ContractTransaction{ContractID: contract.ID, TxType: "close", TxHex: "00"}, rejectMalformed)
+	rejected(finalTx(setupScript, nil), rejectOutputs)
+	rejected(finalTx(finalScript, func(tx *wire.MsgTx) { tx.TxOut[0].Value = 50_000 }), rejectFee)
+
+	// An empty signature does not satisfy the leaf and fails the script engine
+	signed := finalTx(finalScript, nil)
+	msgTx := wire.NewMsgTx(2)
+	raw, err := hex.DecodeString(signed.TxHex)
+	require.NoError(t, err)
+	require.NoError(t, msgTx.Deserialize(bytes.NewReader(raw)))
+	path, err := setupSpender.Path(1)
+	require.NoError(t, err)
+	msgTx.TxIn[0].Witness = path.Witness([]byte{})
+	var buf bytes.Buffer
+	require.NoError(t, msgTx.Serialize(&buf))
+	signed.TxHex = hex.EncodeToString(buf.Bytes())
+	rejected(signed, rejectScript)
+
+	// A settlement must spend the final output, and is not broadcast when
+	// it does not
+	settlement := finalTx(finalScript, nil)
+	settlement.TxType = "settlement"
+	assert.ErrorIs(t, s.broadcastSettlement(context.Background(), settlement), ErrTransactionRejected)
+	assert.Empty(t, chain.broadcast)
+}
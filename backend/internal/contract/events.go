@@ -0,0 +1,214 @@
+// internal/contract/events.go
+package contract
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// Last ASP status published, so only changes are announced
+const (
+	aspStatusUnknown int32 = iota
+	aspStatusUp
+	aspStatusDown
+	aspStatusExited
+)
+
+// ConfirmationConfig controls how contract transactions are watched until
+// they confirm
+type ConfirmationConfig struct {
+	// Interval is how often unconfirmed transactions are checked; zero
+	// disables the watcher
+	Interval time.Duration `yaml:"interval"`
+	// Depth is the number of confirmations that count as confirmed
+	Depth int64 `yaml:"depth"`
+}
+
+// DefaultConfirmationConfig checks every minute and counts a transaction as
+// confirmed once it is in a block
+var DefaultConfirmationConfig = ConfirmationConfig{
+	Interval: time.Minute,
+	Depth:    1,
+}
+
+// Enabled reports whether transactions are watched
+func (c ConfirmationConfig) Enabled() bool {
+	return c.Interval > 0
+}
+
+// Validate checks that the watcher settings are usable
+func (c ConfirmationConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("confirmation interval cannot be negative")
+	}
+	if c.Enabled() && c.Depth < 1 {
+		return fmt.Errorf("confirmation depth must be at least one")
+	}
+	return nil
+}
+
+// WithEventBus publishes the lifecycle events of contracts and changes in
+// the ASP's availability to bus
+func (s *Service) WithEventBus(bus EventPublisher) *Service {
+	s.events = bus
+	return s
+}
+
+// publishASPStatus announces a ping of the ASP whose result differs from
+// the last one, or that tripped the dead man's switch
+func (s *Service) publishASPStatus(alive bool) {
+	if s.events == nil {
+		return
+	}
+
+	onChainOnly := s.onChainOnly.Load()
+	status := aspStatusDown
+	if alive {
+		status = aspStatusUp
+	}
+	if onChainOnly {
+		status = aspStatusExited
+	}
+	if s.aspStatus.Swap(status) == status {
+		return
+	}
+
+	failures := 0
+	if s.exitSwitch != nil {
+		failures, _, _ = s.exitSwitch.status()
+	}
+	s.events.Publish(events.TopicASPStatus, events.ASPStatusEvent{
+		Available:           alive,
+		ConsecutiveFailures: failures,
+		OnChainOnly:         onChainOnly,
+	})
+}
+
+// WithConfirmationWatcher enables marking contract transactions confirmed
+// once they are deep enough in the chain. Start it with
+// StartConfirmationWatcher.
+func (s *Service) WithConfirmationWatcher(store ConfirmationStore, source ConfirmationSource, cfg ConfirmationConfig) *Service {
+	s.confirmationRepo = store
+	s.confirmationSource = source
+	s.confirmations = cfg
+	return s
+}
+
+// publishStatus announces that a contract moved from one status to its
+// current one
+func (s *Service) publishStatus(contract *models.Contract, from models.ContractStatus) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(events.TopicContracts, events.ContractEvent{
+		Type:       events.ContractStatusChanged,
+		ContractID: contract.ID,
+		From:       from,
+		To:         contract.Status,
+		Time:       time.Now().UTC(),
+	})
+}
+
+// publishSettled announces the outcome of a settlement. buyerWins is nil
+// for a cooperative close.
+func (s *Service) publishSettled(contract *models.Contract, from models.ContractStatus, tx *models.ContractTransaction, buyerWins *bool) {
+	s.publishStatus(contract, from)
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(events.TopicContracts, events.ContractEvent{
+		Type:          events.ContractSettled,
+		ContractID:    contract.ID,
+		TransactionID: tx.TransactionID,
+		TxType:        tx.TxType,
+		BuyerWins:     buyerWins,
+		Time:          time.Now().UTC(),
+	})
+}
+
+// StartConfirmationWatcher checks unconfirmed contract transactions on the
+// configured interval until ctx is cancelled
+func (s *Service) StartConfirmationWatcher(ctx context.Context) {
+	if s.confirmationRepo == nil || !s.confirmations.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.confirmations.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				confirmed, err := s.checkConfirmations(ctx)
+				if err != nil {
+					logger.Error().Err(err).Msg("Failed to check transaction confirmations")
+				} else if confirmed > 0 {
+					logger.Info().Int("confirmed", confirmed).Msg("Contract transactions confirmed")
+				}
+			}
+		}
+	}()
+}
+
+// checkConfirmations marks the unconfirmed transactions that are deep
+// enough as confirmed and returns how many were. Transactions the node does
+// not know, such as Ark round and out-of-round transactions, are skipped.
+func (s *Service) checkConfirmations(ctx context.Context) (int, error) {
+	txs, err := s.confirmationRepo.ListUnconfirmedTransactions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list unconfirmed transactions: %w", err)
+	}
+
+	confirmed := 0
+	for _, tx := range txs {
+		hash, err := chainhash.NewHashFromStr(tx.TransactionID)
+		if err != nil {
+			continue
+		}
+
+		depth, err := s.confirmationSource.GetTransactionConfirmations(ctx, hash)
+		if err != nil {
+			logger.Debug().Err(err).Str("txid", tx.TransactionID).Msg("Transaction not found on chain")
+			continue
+		}
+		if depth < s.confirmations.Depth {
+			continue
+		}
+
+		// Settle the contract first, so a failure leaves the payout
+		// unconfirmed to be retried on the next check
+		if err := s.completeSettlement(ctx, tx, depth); err != nil {
+			return confirmed, fmt.Errorf("failed to complete settlement of %s: %w", tx.ContractID, err)
+		}
+		if err := s.anchorSetup(ctx, tx, depth); err != nil {
+			return confirmed, fmt.Errorf("failed to anchor schedule of %s: %w", tx.ContractID, err)
+		}
+
+		if err := s.confirmationRepo.ConfirmTransaction(ctx, tx.TransactionID); err != nil {
+			return confirmed, fmt.Errorf("failed to confirm transaction %s: %w", tx.TransactionID, err)
+		}
+		confirmed++
+
+		if s.events != nil {
+			s.events.Publish(events.TopicContracts, events.ContractEvent{
+				Type:          events.ContractTransactionConfirmed,
+				ContractID:    tx.ContractID,
+				TransactionID: tx.TransactionID,
+				TxType:        tx.TxType,
+				Confirmations: depth,
+				Time:          time.Now().UTC(),
+			})
+		}
+	}
+
+	return confirmed, nil
+}
@@ -0,0 +1,97 @@
+// internal/contract/events_test.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+type stubConfirmationStore struct {
+	txs       []*models.ContractTransaction
+	confirmed []string
+}
+
+func (s *stubConfirmationStore) ListUnconfirmedTransactions(ctx context.Context) ([]*models.ContractTransaction, error) {
+	return s.txs, nil
+}
+
+func (s *stubConfirmationStore) ConfirmTransaction(ctx context.Context, txID string) error {
+	s.confirmed = append(s.confirmed, txID)
+	return nil
+}
+
+type recordingPublisher []events.ContractEvent
+
+func (p *recordingPublisher) Publish(topic events.Topic, payload interface{}) {
+	if event, ok := payload.(events.ContractEvent); ok && topic == events.TopicContracts {
+		*p = append(*p, event)
+	}
+}
+
+type stubConfirmationSource map[string]int64
+
+func (s stubConfirmationSource) GetTransactionConfirmations(ctx context.Context, txHash *chainhash.Hash) (int64, error) {
+	depth, ok := s[txHash.String()]
+	if !ok {
+		return 0, errors.New("no such transaction")
+	}
+	return depth, nil
+}
+
+func TestCheckConfirmations(t *testing.T) {
+	deep := "1111111111111111111111111111111111111111111111111111111111111111"
+	shallow := "2222222222222222222222222222222222222222222222222222222222222222"
+	unknown := "3333333333333333333333333333333333333333333333333333333333333333"
+	contractID := uuid.New()
+
+	store := &stubConfirmationStore{txs: []*models.ContractTransaction{
+		{ContractID: contractID, TransactionID: deep, TxType: "settlement"},
+		{ContractID: contractID, TransactionID: shallow, TxType: "final"},
+		{ContractID: contractID, TransactionID: unknown, TxType: "swap"},
+		{ContractID: contractID, TransactionID: "round-42", TxType: "setup"},
+	}}
+	source := stubConfirmationSource{deep: 6, shallow: 2}
+
+	var published recordingPublisher
+
+	s := (&Service{}).
+		WithEventBus(&published).
+		WithConfirmationWatcher(store, source, ConfirmationConfig{Interval: 1, Depth: 3})
+
+	confirmed, err := s.checkConfirmations(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, confirmed)
+	assert.Equal(t, []string{deep}, store.confirmed)
+
+	require.Len(t, published, 1)
+	assert.Equal(t, events.ContractTransactionConfirmed, published[0].Type)
+	assert.Equal(t, contractID, published[0].ContractID)
+	assert.Equal(t, int64(6), published[0].Confirmations)
+}
+
+func TestPublishSettled(t *testing.T) {
+	var published recordingPublisher
+
+	s := (&Service{}).WithEventBus(&published)
+	contract := &models.Contract{ID: uuid.New(), Status: models.ContractStatusSettled}
+	buyerWins := true
+
+	s.publishSettled(contract, models.ContractStatusActive, &models.ContractTransaction{TransactionID: "abc", TxType: "settlement"}, &buyerWins)
+
+	require.Len(t, published, 2)
+	assert.Equal(t, events.ContractStatusChanged, published[0].Type)
+	assert.Equal(t, models.ContractStatusActive, published[0].From)
+	assert.Equal(t, models.ContractStatusSettled, published[0].To)
+	assert.Equal(t, events.ContractSettled, published[1].Type)
+	assert.Equal(t, "abc", published[1].TransactionID)
+	assert.True(t, *published[1].BuyerWins)
+}
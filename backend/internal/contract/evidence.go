@@ -0,0 +1,113 @@
+// internal/contract/evidence.go
+package contract
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+// ErrEvidenceNotEnabled is returned when no settlement evidence store is configured
+var ErrEvidenceNotEnabled = errors.New("settlement evidence is not enabled")
+
+// EvidenceBundle is the chain state a settlement decision was based on,
+// together with the contract terms it was measured against. Its JSON
+// encoding is what is hashed and timestamped.
+type EvidenceBundle struct {
+	ContractID       uuid.UUID           `json:"contract_id"`
+	ContractType     models.ContractType `json:"contract_type"`
+	StrikeHashRate   float64             `json:"strike_hash_rate"`
+	StartBlockHeight int64               `json:"start_block_height"`
+	EndBlockHeight   int64               `json:"end_block_height"`
+	TargetTimestamp  time.Time           `json:"target_timestamp"`
+	ContractSize     int64               `json:"contract_size"`
+	BuyerPubKey      string              `json:"buyer_pub_key"`
+	SellerPubKey     string              `json:"seller_pub_key"`
+	TipHeight        int64               `json:"tip_height"`
+	TipHash          string              `json:"tip_hash"`
+	TipTime          time.Time           `json:"tip_time"`
+	HeightReached    bool                `json:"height_reached"`
+	BuyerWins        bool                `json:"buyer_wins"`
+	SettlementTxID   string              `json:"settlement_tx_id"`
+	DecidedAt        time.Time           `json:"decided_at"`
+}
+
+// WithEvidenceStore enables recording the evidence of each settlement decision
+func (s *Service) WithEvidenceStore(store EvidenceStore) *Service {
+	s.evidenceRepo = store
+	return s
+}
+
+// newSettlementEvidence encodes a bundle and hashes the encoded bytes
+func newSettlementEvidence(bundle *EvidenceBundle) (*models.SettlementEvidence, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evidence bundle: %w", err)
+	}
+
+	digest := sha256.Sum256(data)
+	return &models.SettlementEvidence{
+		ContractID: bundle.ContractID,
+		Bundle:     data,
+		Digest:     hex.EncodeToString(digest[:]),
+	}, nil
+}
+
+// recordSettlementEvidence stores the chain state a settlement was decided
+// on. The settlement has already been stored, so a failure is logged rather
+// than returned.
+func (s *Service) recordSettlementEvidence(
+	ctx context.Context,
+	contract *models.Contract,
+	tip *bitcoin.Block,
+	buyerWins bool,
+	settlementTxID string,
+) {
+	if s.evidenceRepo == nil {
+		return
+	}
+
+	evidence, err := newSettlementEvidence(&EvidenceBundle{
+		ContractID:       contract.ID,
+		ContractType:     contract.ContractType,
+		StrikeHashRate:   contract.StrikeHashRate,
+		StartBlockHeight: contract.StartBlockHeight,
+		EndBlockHeight:   contract.EndBlockHeight,
+		TargetTimestamp:  contract.TargetTimestamp.UTC(),
+		ContractSize:     contract.ContractSize,
+		BuyerPubKey:      contract.BuyerPubKey,
+		SellerPubKey:     contract.SellerPubKey,
+		TipHeight:        tip.Height,
+		TipHash:          tip.Hash,
+		TipTime:          tip.Time.UTC(),
+		HeightReached:    tip.Height >= contract.EndBlockHeight,
+		BuyerWins:        buyerWins,
+		SettlementTxID:   settlementTxID,
+		DecidedAt:        time.Now().UTC(),
+	})
+	if err == nil {
+		err = s.evidenceRepo.Create(ctx, evidence)
+	}
+	if err != nil {
+		logger.Error().Err(err).
+			Str("contractID", contract.ID.String()).
+			Msg("Failed to record settlement evidence")
+	}
+}
+
+// GetSettlementEvidence returns the evidence recorded when a contract was settled
+func (s *Service) GetSettlementEvidence(ctx context.Context, contractID uuid.UUID) (*models.SettlementEvidence, error) {
+	if s.evidenceRepo == nil {
+		return nil, ErrEvidenceNotEnabled
+	}
+	return s.evidenceRepo.Get(ctx, contractID)
+}
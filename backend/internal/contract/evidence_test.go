@@ -0,0 +1,49 @@
+// internal/contract/evidence_test.go
+package contract
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func TestNewSettlementEvidence(t *testing.T) {
+	bundle := &EvidenceBundle{
+		ContractID:     uuid.New(),
+		ContractType:   models.ContractTypeCall,
+		EndBlockHeight: 850000,
+		TipHeight:      850002,
+		TipHash:        "00000000000000000001",
+		TipTime:        time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		HeightReached:  true,
+		BuyerWins:      true,
+		SettlementTxID: "settlement-txid",
+		DecidedAt:      time.Date(2024, 6, 1, 0, 5, 0, 0, time.UTC),
+	}
+
+	evidence, err := newSettlementEvidence(bundle)
+	require.NoError(t, err)
+	assert.Equal(t, bundle.ContractID, evidence.ContractID)
+
+	// The digest commits to the stored bytes, which decode to the bundle
+	digest := sha256.Sum256(evidence.Bundle)
+	assert.Equal(t, hex.EncodeToString(digest[:]), evidence.Digest)
+
+	var decoded EvidenceBundle
+	require.NoError(t, json.Unmarshal(evidence.Bundle, &decoded))
+	assert.Equal(t, *bundle, decoded)
+
+	// Any change to the decision changes the digest
+	bundle.BuyerWins = false
+	changed, err := newSettlementEvidence(bundle)
+	require.NoError(t, err)
+	assert.NotEqual(t, evidence.Digest, changed.Digest)
+}
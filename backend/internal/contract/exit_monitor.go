@@ -0,0 +1,344 @@
+// internal/contract/exit_monitor.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+// exitBatchSize is how many active contracts are exited per page
+const exitBatchSize = 100
+
+// ExitMonitorConfig controls the dead man's switch that exits every active
+// contract on chain once the ASP stops responding
+type ExitMonitorConfig struct {
+	// Interval is how often the ASP is pinged; zero disables the monitor
+	Interval time.Duration `yaml:"interval"`
+	// FailureThreshold is the number of consecutive failed pings that trips
+	// the switch
+	FailureThreshold int `yaml:"failure_threshold"`
+	// Window is how long the ASP must have been failing before the switch
+	// trips, so a burst of failures cannot exit every contract at once
+	Window time.Duration `yaml:"window"`
+}
+
+// DefaultExitMonitorConfig leaves the monitor disabled. Once enabled, the
+// ASP must fail ten pings in a row over at least half an hour.
+var DefaultExitMonitorConfig = ExitMonitorConfig{
+	FailureThreshold: 10,
+	Window:           30 * time.Minute,
+}
+
+// Enabled reports whether the ASP is monitored
+func (c ExitMonitorConfig) Enabled() bool {
+	return c.Interval > 0
+}
+
+// Validate checks that the monitor settings are usable
+func (c ExitMonitorConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("exit monitor interval cannot be negative")
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	if c.FailureThreshold < 1 {
+		return fmt.Errorf("exit monitor failure threshold must be at least one")
+	}
+	if c.Window < 0 {
+		return fmt.Errorf("exit monitor window cannot be negative")
+	}
+	return nil
+}
+
+// ExitMonitorStatus is the state of the dead man's switch as reported to operators
+type ExitMonitorStatus struct {
+	Enabled             bool       `json:"enabled"`
+	OnChainOnly         bool       `json:"on_chain_only"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailingSince        *time.Time `json:"failing_since,omitempty"`
+	TrippedAt           *time.Time `json:"tripped_at,omitempty"`
+}
+
+// deadManSwitch counts consecutive failed pings of the ASP and trips once
+// enough have failed over a long enough window
+type deadManSwitch struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	failures  int
+	since     time.Time
+	tripped   time.Time
+}
+
+// observe records the outcome of a ping and reports whether the switch
+// tripped with it. A tripped switch stays tripped until reset.
+func (d *deadManSwitch) observe(alive bool, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.tripped.IsZero() {
+		return false
+	}
+
+	if alive {
+		d.failures = 0
+		d.since = time.Time{}
+		return false
+	}
+
+	if d.failures == 0 {
+		d.since = now
+	}
+	d.failures++
+
+	if d.failures >= d.threshold && now.Sub(d.since) >= d.window {
+		d.tripped = now
+		return true
+	}
+	return false
+}
+
+// reset clears the failures and re-arms a tripped switch
+func (d *deadManSwitch) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.failures = 0
+	d.since = time.Time{}
+	d.tripped = time.Time{}
+}
+
+// status returns the failure count and times of the switch
+func (d *deadManSwitch) status() (int, *time.Time, *time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var since, tripped *time.Time
+	if d.failures > 0 {
+		t := d.since
+		since = &t
+	}
+	if !d.tripped.IsZero() {
+		t := d.tripped
+		tripped = &t
+	}
+	return d.failures, since, tripped
+}
+
+// WithExitMonitor enables the dead man's switch. Start it with StartExitMonitor.
+func (s *Service) WithExitMonitor(cfg ExitMonitorConfig) *Service {
+	s.exitMonitor = cfg
+	s.exitSwitch = &deadManSwitch{threshold: cfg.FailureThreshold, window: cfg.Window}
+	return s
+}
+
+// StartExitMonitor pings the ASP on the configured interval. While the ASP
+// responds, emergency exits are prepared for contracts that lack them; once
+// the switch trips, every active contract's exit is broadcast and the
+// service stops using the ASP.
+func (s *Service) StartExitMonitor(ctx context.Context) {
+	if s.exitSwitch == nil || !s.exitMonitor.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.exitMonitor.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkASP(ctx, time.Now().UTC())
+			}
+		}
+	}()
+}
+
+// checkASP pings the ASP once and trips the switch if it has failed for long enough
+func (s *Service) checkASP(ctx context.Context, now time.Time) {
+	alive, err := s.arkClient.CheckASPStatus(ctx)
+	alive = alive && err == nil
+
+	if alive && !s.onChainOnly.Load() {
+		if err := s.PrepareEmergencyExitPath(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to prepare emergency exit paths")
+		}
+	}
+
+	if !s.exitSwitch.observe(alive, now) {
+		s.publishASPStatus(alive)
+		if !alive {
+			failures, _, _ := s.exitSwitch.status()
+			logger.Warn().Err(err).Int("consecutive_failures", failures).Msg("ASP is not responding")
+		}
+		return
+	}
+
+	s.onChainOnly.Store(true)
+	s.publishASPStatus(alive)
+	logger.Error().
+		Int("failures", s.exitMonitor.FailureThreshold).
+		Dur("window", s.exitMonitor.Window).
+		Msg("ASP unresponsive, switching to on-chain only and exiting all active contracts")
+
+	broadcast, err := s.ExitAllContracts(ctx)
+	if err != nil {
+		logger.Error().Err(err).Int("broadcast", broadcast).Msg("Failed to exit active contracts")
+		return
+	}
+	logger.Info().Int("broadcast", broadcast).Msg("Broadcast emergency exits of active contracts")
+}
+
+// ExitAllContracts broadcasts the pre-signed emergency exit transactions of
+// every active contract and returns how many were broadcast. A contract
+// whose exit fails to broadcast does not stop the others.
+func (s *Service) ExitAllContracts(ctx context.Context) (int, error) {
+	// List every active contract before broadcasting, so the pages do not
+	// shift as contracts change state
+	var contracts []*models.Contract
+	for offset := 0; ; offset += exitBatchSize {
+		page, err := s.contractRepo.ListByStatus(ctx, models.ContractStatusActive, exitBatchSize, offset)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list active contracts: %w", err)
+		}
+		contracts = append(contracts, page...)
+		if len(page) < exitBatchSize {
+			break
+		}
+	}
+
+	broadcast := 0
+	for _, contract := range contracts {
+		broadcast += s.exitContract(ctx, contract)
+	}
+
+	return broadcast, nil
+}
+
+// ExitContract broadcasts the pre-signed emergency exit transactions of one
+// active contract and returns how many were broadcast
+func (s *Service) ExitContract(ctx context.Context, contractID uuid.UUID) (int, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contract: %w", err)
+	}
+	if contract.Status != models.ContractStatusActive {
+		return 0, fmt.Errorf("contract is not active")
+	}
+
+	broadcast := s.exitContract(ctx, contract)
+	if broadcast == 0 {
+		return 0, fmt.Errorf("contract has no emergency exit to broadcast")
+	}
+	return broadcast, nil
+}
+
+// exitContract broadcasts the unconfirmed emergency exits of a contract,
+// logging those that fail, and returns how many were broadcast
+func (s *Service) exitContract(ctx context.Context, contract *models.Contract) int {
+	txs, err := s.contractRepo.GetTransactionsByContractID(ctx, contract.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to get exit transactions")
+		return 0
+	}
+
+	broadcast := 0
+	for _, tx := range txs {
+		if tx.TxType != "emergency_exit" || tx.Confirmed {
+			continue
+		}
+
+		txHex, err := rawExitTransaction(tx.TxHex)
+		if err == nil {
+			err = s.checkBroadcast(ctx, tx, txHex)
+		}
+		if err == nil {
+			_, err = s.bitcoinClient.BroadcastTransactionWithRetry(ctx, txHex)
+		}
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Str("contract_id", contract.ID.String()).
+				Str("tx_id", tx.TransactionID).
+				Msg("Failed to broadcast emergency exit")
+			continue
+		}
+		broadcast++
+	}
+
+	return broadcast
+}
+
+// rawExitTransaction returns the raw transaction of an emergency exit. The
+// ASP returns exits as signed PSBTs, which are finalized and extracted;
+// anything else is taken to be a raw transaction already.
+func rawExitTransaction(stored string) (string, error) {
+	packet, err := bitcoin.ParsePSBT(stored, bitcoin.DefaultParseLimits)
+	if err != nil {
+		return stored, nil
+	}
+
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return "", fmt.Errorf("emergency exit is not fully signed: %w", err)
+	}
+	tx, err := psbt.Extract(packet)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract emergency exit: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return "", fmt.Errorf("failed to serialize emergency exit: %w", err)
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+// OnChainOnly reports whether the dead man's switch has tripped, in which
+// case setups and swaps no longer use the ASP
+func (s *Service) OnChainOnly() bool {
+	return s.onChainOnly.Load()
+}
+
+// ResumeOffChain re-arms a tripped switch and lets the service use the ASP
+// again, once the operator has confirmed it is back
+func (s *Service) ResumeOffChain() {
+	if s.exitSwitch != nil {
+		s.exitSwitch.reset()
+	}
+	s.onChainOnly.Store(false)
+}
+
+// ExitMonitorStatus returns the state of the dead man's switch
+func (s *Service) ExitMonitorStatus() ExitMonitorStatus {
+	status := ExitMonitorStatus{
+		Enabled:     s.exitSwitch != nil && s.exitMonitor.Enabled(),
+		OnChainOnly: s.onChainOnly.Load(),
+	}
+	if s.exitSwitch != nil {
+		status.ConsecutiveFailures, status.FailingSince, status.TrippedAt = s.exitSwitch.status()
+	}
+	return status
+}
+
+// aspAvailable reports whether the ASP should be used, which it no longer
+// is once the dead man's switch has tripped
+func (s *Service) aspAvailable(ctx context.Context) bool {
+	if s.onChainOnly.Load() {
+		return false
+	}
+	available, _ := s.arkClient.CheckASPStatus(ctx)
+	return available
+}
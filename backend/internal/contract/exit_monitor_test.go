@@ -0,0 +1,62 @@
+// internal/contract/exit_monitor_test.go
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadManSwitch(t *testing.T) {
+	d := &deadManSwitch{threshold: 3, window: 10 * time.Minute}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Enough failures but not for long enough
+	assert.False(t, d.observe(false, start))
+	assert.False(t, d.observe(false, start.Add(time.Minute)))
+	assert.False(t, d.observe(false, start.Add(2*time.Minute)))
+
+	// A success starts the count again
+	assert.False(t, d.observe(true, start.Add(3*time.Minute)))
+	failures, since, _ := d.status()
+	assert.Zero(t, failures)
+	assert.Nil(t, since)
+
+	assert.False(t, d.observe(false, start.Add(4*time.Minute)))
+	assert.False(t, d.observe(false, start.Add(9*time.Minute)))
+	assert.False(t, d.observe(false, start.Add(13*time.Minute)))
+	assert.True(t, d.observe(false, start.Add(14*time.Minute)))
+
+	// It trips once and stays tripped until reset
+	assert.False(t, d.observe(false, start.Add(15*time.Minute)))
+	assert.False(t, d.observe(true, start.Add(16*time.Minute)))
+	_, _, tripped := d.status()
+	assert.Equal(t, start.Add(14*time.Minute), *tripped)
+
+	d.reset()
+	failures, since, tripped = d.status()
+	assert.Zero(t, failures)
+	assert.Nil(t, since)
+	assert.Nil(t, tripped)
+}
+
+func TestExitMonitorConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultExitMonitorConfig.Validate())
+	assert.False(t, DefaultExitMonitorConfig.Enabled())
+
+	cfg := DefaultExitMonitorConfig
+	cfg.Interval = time.Minute
+	assert.NoError(t, cfg.Validate())
+
+	cfg.FailureThreshold = 0
+	assert.Error(t, cfg.Validate())
+
+	assert.Error(t, ExitMonitorConfig{Interval: -time.Second}.Validate())
+}
+
+func TestRawExitTransactionPassesRawHex(t *testing.T) {
+	raw, err := rawExitTransaction("0200000000")
+	assert.NoError(t, err)
+	assert.Equal(t, "0200000000", raw)
+}
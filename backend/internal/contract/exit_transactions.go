@@ -0,0 +1,156 @@
+// internal/contract/exit_transactions.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+var (
+	// ErrNotExitTransaction is returned for a transaction that is not an
+	// emergency exit
+	ErrNotExitTransaction = errors.New("transaction is not an emergency exit")
+	// ErrExitSpendsOtherFunds is returned for a submitted exit that spends
+	// none of the contract's outputs
+	ErrExitSpendsOtherFunds = errors.New("emergency exit does not spend the contract's funds")
+)
+
+// ExitTransaction retrieves an emergency exit of a contract the user is a
+// party to, with the raw transaction that broadcasts it
+func (s *Service) ExitTransaction(ctx context.Context, userID, txID uuid.UUID) (*models.ContractTransaction, string, error) {
+	tx, err := s.contractRepo.GetTransactionByID(ctx, txID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if tx.TxType != "emergency_exit" {
+		return nil, "", ErrNotExitTransaction
+	}
+	if _, err := s.CheckParty(ctx, tx.ContractID, userID); err != nil {
+		return nil, "", err
+	}
+
+	txHex, err := rawExitTransaction(tx.TxHex)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, txHex, nil
+}
+
+// BroadcastExitTransaction checks and broadcasts an emergency exit of a
+// contract the user is a party to, returning its transaction ID
+func (s *Service) BroadcastExitTransaction(ctx context.Context, userID, txID uuid.UUID) (string, error) {
+	tx, txHex, err := s.ExitTransaction(ctx, userID, txID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkBroadcast(ctx, tx, txHex); err != nil {
+		return "", err
+	}
+
+	txHash, err := s.bitcoinClient.BroadcastTransactionWithRetry(ctx, txHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast emergency exit: %w", err)
+	}
+
+	logger.Warn().
+		Str("contract_id", tx.ContractID.String()).
+		Str("user_id", userID.String()).
+		Str("txid", txHash).
+		Msg("Participant broadcast an emergency exit")
+	return txHash, nil
+}
+
+// SubmitExitTransaction records and broadcasts an emergency exit a party
+// signed themselves, for when the exits prepared through the ASP are missing
+// or stale. The transaction must spend an output of the contract's setup,
+// its VTXO or one of its recorded exits.
+func (s *Service) SubmitExitTransaction(ctx context.Context, userID, contractID uuid.UUID, txHex string) (*models.ContractTransaction, error) {
+	contract, err := s.CheckParty(ctx, contractID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status != models.ContractStatusActive {
+		return nil, fmt.Errorf("contract is not active")
+	}
+
+	txHex = strings.TrimSpace(txHex)
+	msgTx, err := bitcoin.ParseTransactionHex(txHex, bitcoin.DefaultParseLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	funds, err := s.contractFundingTxIDs(ctx, contract.ID)
+	if err != nil {
+		return nil, err
+	}
+	spendsContract := false
+	for _, in := range msgTx.TxIn {
+		if funds[in.PreviousOutPoint.Hash.String()] {
+			spendsContract = true
+			break
+		}
+	}
+	if !spendsContract {
+		return nil, ErrExitSpendsOtherFunds
+	}
+
+	exitTx := &models.ContractTransaction{
+		ID:            uuid.New(),
+		ContractID:    contract.ID,
+		TransactionID: msgTx.TxHash().String(),
+		TxType:        "emergency_exit",
+		TxHex:         txHex,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := s.checkBroadcast(ctx, exitTx, txHex); err != nil {
+		return nil, err
+	}
+	if _, err := s.bitcoinClient.BroadcastTransactionWithRetry(ctx, txHex); err != nil {
+		return nil, fmt.Errorf("failed to broadcast emergency exit: %w", err)
+	}
+	if err := s.contractRepo.AddTransaction(ctx, exitTx); err != nil {
+		return nil, fmt.Errorf("failed to save emergency exit transaction: %w", err)
+	}
+
+	logger.Warn().
+		Str("contract_id", contract.ID.String()).
+		Str("user_id", userID.String()).
+		Str("txid", exitTx.TransactionID).
+		Msg("Participant submitted an emergency exit")
+	return exitTx, nil
+}
+
+// contractFundingTxIDs returns the IDs of the transactions whose outputs
+// hold a contract's funds: its setups, the exits recorded for it, and the
+// transaction of its VTXO
+func (s *Service) contractFundingTxIDs(ctx context.Context, contractID uuid.UUID) (map[string]bool, error) {
+	txs, err := s.contractRepo.GetTransactionsByContractID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	funds := make(map[string]bool)
+	for _, tx := range txs {
+		switch tx.TxType {
+		case "setup", "setup_onchain", "emergency_exit":
+			funds[tx.TransactionID] = true
+		}
+	}
+
+	if s.vtxoRepo != nil {
+		vtxo, err := s.vtxoRepo.GetActiveByContract(ctx, contractID)
+		if err == nil {
+			txid, _, _ := strings.Cut(vtxo.VTXOID, ":")
+			funds[txid] = true
+		}
+	}
+	return funds, nil
+}
@@ -0,0 +1,85 @@
+// internal/contract/exit_transactions_test.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+// exitHex serializes a transaction spending an output of prevTxID to a
+// P2TR output
+func exitHex(t *testing.T, prevTxID string) string {
+	t.Helper()
+	hash, err := chainhash.NewHashFromStr(prevTxID)
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(hash, 0), nil, [][]byte{{0x01}}))
+	tx.AddTxOut(wire.NewTxOut(90_000, append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x01}, 32)...)))
+
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+	return hex.EncodeToString(buf.Bytes())
+}
+
+func TestSubmitExitTransaction(t *testing.T) {
+	setupTxID := "1111111111111111111111111111111111111111111111111111111111111111"
+	contract := &models.Contract{
+		ID:           uuid.New(),
+		Status:       models.ContractStatusActive,
+		BuyerPubKey:  "02aa",
+		SellerPubKey: "03bb",
+	}
+	buyer, stranger := uuid.New(), uuid.New()
+	contracts := &stubContractStore{
+		contracts: map[uuid.UUID]*models.Contract{contract.ID: contract},
+		txs:       []*models.ContractTransaction{{ID: uuid.New(), ContractID: contract.ID, TransactionID: setupTxID, TxType: "setup"}},
+	}
+	chain := &recordingChain{}
+	s := &Service{contractRepo: contracts, bitcoinClient: chain}
+	s.WithUserKeys(stubUserKeys{buyer: {{PubKey: "02aa"}}})
+	ctx := context.Background()
+
+	// Only a party may exit
+	_, err := s.SubmitExitTransaction(ctx, stranger, contract.ID, exitHex(t, setupTxID))
+	assert.ErrorIs(t, err, ErrNotParty)
+
+	// The transaction must decode within the parse limits
+	_, err = s.SubmitExitTransaction(ctx, buyer, contract.ID, "not hex")
+	assert.ErrorIs(t, err, bitcoin.ErrMalformedTransaction)
+
+	// and spend the contract's funds rather than some other output
+	other := "2222222222222222222222222222222222222222222222222222222222222222"
+	_, err = s.SubmitExitTransaction(ctx, buyer, contract.ID, exitHex(t, other))
+	assert.ErrorIs(t, err, ErrExitSpendsOtherFunds)
+	assert.Empty(t, chain.broadcast)
+
+	txHex := exitHex(t, setupTxID)
+	exitTx, err := s.SubmitExitTransaction(ctx, buyer, contract.ID, " "+txHex+"\n")
+	require.NoError(t, err)
+	assert.Equal(t, "emergency_exit", exitTx.TxType)
+	assert.Equal(t, txHex, exitTx.TxHex)
+	assert.Equal(t, []string{txHex}, chain.broadcast)
+
+	// The recorded exit can be fetched and broadcast again by a party only
+	_, raw, err := s.ExitTransaction(ctx, buyer, exitTx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, txHex, raw)
+
+	_, _, err = s.ExitTransaction(ctx, stranger, exitTx.ID)
+	assert.ErrorIs(t, err, ErrNotParty)
+
+	_, err = s.BroadcastExitTransaction(ctx, buyer, contracts.txs[0].ID)
+	assert.ErrorIs(t, err, ErrNotExitTransaction)
+}
@@ -0,0 +1,361 @@
+// internal/contract/fee_policy.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ErrFeePolicyNotEnabled is returned when fee stress mode is not configured
+var ErrFeePolicyNotEnabled = errors.New("fee stress mode is not enabled")
+
+// ErrSettlementDeferred is returned when a settlement is held back because
+// chain fees are above the stress threshold
+var ErrSettlementDeferred = errors.New("settlement deferred while chain fees are high")
+
+// ErrNotDeferred is returned when a priority fee is offered for a settlement
+// that is not deferred
+var ErrNotDeferred = errors.New("settlement is not deferred")
+
+// defaultSettlementFeeRate is the fee rate in sat/vB when no fee estimator
+// is configured
+const defaultSettlementFeeRate = float64(5)
+
+// minFeeRate is the lowest fee rate in sat/vB that relays by default
+const minFeeRate = float64(1)
+
+// FeePolicyConfig controls how settlements are broadcast while chain fees are high
+type FeePolicyConfig struct {
+	// StressFeeRate is the estimate in sat/vB above which non-urgent
+	// settlements are deferred; zero disables fee stress mode
+	StressFeeRate float64 `yaml:"stress_fee_rate"`
+	// ConfTarget is the confirmation target in blocks of fee estimates
+	ConfTarget int64 `yaml:"conf_target"`
+	// FallbackFeeRate is used when no estimator can estimate a fee rate
+	FallbackFeeRate float64 `yaml:"fallback_fee_rate"`
+	// FeeAPIURL is an external fee API in the mempool.space format asked
+	// when the node cannot estimate; empty uses the node alone
+	FeeAPIURL string `yaml:"fee_api_url"`
+	// UrgentWithin is how close to expiry a contract must be for its
+	// settlement to be broadcast regardless of fees
+	UrgentWithin time.Duration `yaml:"urgent_within"`
+	// MaxPriorityFeeRate is the highest fee rate a party may offer to
+	// release a deferred settlement
+	MaxPriorityFeeRate float64 `yaml:"max_priority_fee_rate"`
+	// ReleaseInterval is how often deferred settlements are re-evaluated
+	ReleaseInterval time.Duration `yaml:"release_interval"`
+	// BatchSize is the most deferred settlements released per interval
+	BatchSize int `yaml:"batch_size"`
+}
+
+// DefaultFeePolicyConfig leaves fee stress mode disabled
+var DefaultFeePolicyConfig = FeePolicyConfig{
+	ConfTarget:         6,
+	FallbackFeeRate:    defaultSettlementFeeRate,
+	UrgentWithin:       12 * time.Hour,
+	MaxPriorityFeeRate: 1000,
+	ReleaseInterval:    time.Minute,
+	BatchSize:          20,
+}
+
+// Enabled reports whether settlements are deferred while fees are high
+func (c FeePolicyConfig) Enabled() bool {
+	return c.StressFeeRate > 0
+}
+
+// Validate checks that the policy is consistent
+func (c FeePolicyConfig) Validate() error {
+	if c.StressFeeRate < 0 {
+		return fmt.Errorf("fee policy stress fee rate cannot be negative")
+	}
+	if c.ConfTarget <= 0 {
+		return fmt.Errorf("fee policy confirmation target must be positive")
+	}
+	if c.FallbackFeeRate <= 0 {
+		return fmt.Errorf("fee policy fallback fee rate must be positive")
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	if c.MaxPriorityFeeRate <= c.StressFeeRate {
+		return fmt.Errorf("fee policy maximum priority fee rate must be above the stress fee rate")
+	}
+	if c.ReleaseInterval <= 0 {
+		return fmt.Errorf("fee policy release interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("fee policy batch size must be positive")
+	}
+	return nil
+}
+
+// settlementFeeRate decides the fee rate of a settlement given the current
+// estimate. It reports false if the settlement should be deferred: the
+// estimate is above the stress threshold, no party offered a priority fee
+// and the contract is not close to expiry.
+func (c FeePolicyConfig) settlementFeeRate(
+	estimate float64,
+	deferral *models.SettlementDeferral,
+	expiresAt time.Time,
+	now time.Time,
+) (float64, bool) {
+	if estimate <= c.StressFeeRate {
+		return estimate, true
+	}
+	if deferral != nil && deferral.Pending() && deferral.PriorityFeeRate != nil {
+		return *deferral.PriorityFeeRate, true
+	}
+	if expiresAt.Sub(now) <= c.UrgentWithin {
+		return estimate, true
+	}
+	return 0, false
+}
+
+// FeeStatus reports whether fee stress mode is currently deferring settlements
+type FeeStatus struct {
+	FeeRate            float64 `json:"fee_rate"`
+	StressFeeRate      float64 `json:"stress_fee_rate"`
+	MaxPriorityFeeRate float64 `json:"max_priority_fee_rate"`
+	Stressed           bool    `json:"stressed"`
+	PendingDeferrals   int     `json:"pending_deferrals"`
+}
+
+// WithFeePolicy sets the estimator that prices settlement and final
+// transactions and enables deferring non-urgent settlements while fees are
+// high when the policy's stress fee rate is set
+func (s *Service) WithFeePolicy(cfg FeePolicyConfig, estimator FeeEstimator, store DeferralStore) *Service {
+	s.feePolicy = cfg
+	s.feeEstimator = estimator
+	s.deferralRepo = store
+	return s
+}
+
+// WithDeferralObserver sets the observer notified when a settlement is deferred
+func (s *Service) WithDeferralObserver(observer DeferralObserver) *Service {
+	s.deferralObserver = observer
+	return s
+}
+
+// feePolicyEnabled reports whether fee stress mode is configured
+func (s *Service) feePolicyEnabled() bool {
+	return s.feePolicy.Enabled() && s.feeEstimator != nil && s.deferralRepo != nil
+}
+
+// estimateFeeRate returns the estimated fee rate for the policy's
+// confirmation target, the fallback rate if it cannot be estimated, or the
+// default rate without an estimator. Estimates never go below the minimum
+// relay fee rate.
+func (s *Service) estimateFeeRate(ctx context.Context) float64 {
+	if s.feeEstimator == nil {
+		return defaultSettlementFeeRate
+	}
+
+	rate, err := s.feeEstimator.EstimateFeeRate(ctx, s.feePolicy.ConfTarget)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to estimate fee rate, using fallback")
+		return s.feePolicy.FallbackFeeRate
+	}
+	if rate < minFeeRate {
+		return minFeeRate
+	}
+	return rate
+}
+
+// settlementFeeRate returns the fee rate to settle a contract at, or
+// ErrSettlementDeferred after recording a deferral and notifying the parties
+func (s *Service) settlementFeeRate(ctx context.Context, contract *models.Contract) (float64, error) {
+	if !s.feePolicyEnabled() {
+		return s.estimateFeeRate(ctx), nil
+	}
+
+	deferral, err := s.deferralRepo.Get(ctx, contract.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	estimate := s.estimateFeeRate(ctx)
+	if rate, ok := s.feePolicy.settlementFeeRate(estimate, deferral, contract.ExpiresAt, time.Now()); ok {
+		return rate, nil
+	}
+
+	if deferral == nil || !deferral.Pending() {
+		deferral, err = s.deferralRepo.Defer(ctx, contract.ID, estimate)
+		if err != nil {
+			return 0, err
+		}
+
+		logger.Info().
+			Str("contractID", contract.ID.String()).
+			Float64("feeRate", estimate).
+			Float64("stressFeeRate", s.feePolicy.StressFeeRate).
+			Msg("Deferred settlement while fees are high")
+
+		if s.deferralObserver != nil {
+			go s.deferralObserver.OnSettlementDeferred(context.Background(), contract, deferral)
+		}
+	}
+
+	return 0, fmt.Errorf("%w: estimate of %.1f sat/vB is above %.1f sat/vB",
+		ErrSettlementDeferred, estimate, s.feePolicy.StressFeeRate)
+}
+
+// releaseDeferral marks a contract's settlement as no longer deferred
+func (s *Service) releaseDeferral(ctx context.Context, contractID uuid.UUID) {
+	if s.deferralRepo == nil {
+		return
+	}
+	if err := s.deferralRepo.Release(ctx, contractID); err != nil {
+		logger.Error().Err(err).Str("contractID", contractID.String()).Msg("Failed to release settlement deferral")
+	}
+}
+
+// GetFeeStatus returns the current fee estimate and the number of deferred settlements
+func (s *Service) GetFeeStatus(ctx context.Context) (*FeeStatus, error) {
+	if !s.feePolicyEnabled() {
+		return nil, ErrFeePolicyNotEnabled
+	}
+
+	pending, err := s.deferralRepo.CountPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := s.estimateFeeRate(ctx)
+	return &FeeStatus{
+		FeeRate:            estimate,
+		StressFeeRate:      s.feePolicy.StressFeeRate,
+		MaxPriorityFeeRate: s.feePolicy.MaxPriorityFeeRate,
+		Stressed:           estimate > s.feePolicy.StressFeeRate,
+		PendingDeferrals:   pending,
+	}, nil
+}
+
+// GetSettlementDeferral returns the deferral of a contract's settlement, or
+// nil if it was never deferred
+func (s *Service) GetSettlementDeferral(ctx context.Context, contractID uuid.UUID) (*models.SettlementDeferral, error) {
+	if !s.feePolicyEnabled() {
+		return nil, ErrFeePolicyNotEnabled
+	}
+	return s.deferralRepo.Get(ctx, contractID)
+}
+
+// BumpSettlementFee records that a party will pay feeRate to have its
+// deferred settlement broadcast without waiting for fees to fall. The
+// settlement is released on the next release interval. The fee comes out of
+// the payout, so pubKey must be the buyer's or seller's key and held by the
+// user.
+func (s *Service) BumpSettlementFee(
+	ctx context.Context,
+	userID uuid.UUID,
+	contractID uuid.UUID,
+	pubKey string,
+	feeRate float64,
+) (*models.SettlementDeferral, error) {
+	if !s.feePolicyEnabled() {
+		return nil, ErrFeePolicyNotEnabled
+	}
+
+	if feeRate <= s.feePolicy.StressFeeRate || feeRate > s.feePolicy.MaxPriorityFeeRate {
+		return nil, fmt.Errorf("priority fee rate must be above %.1f and at most %.1f sat/vB",
+			s.feePolicy.StressFeeRate, s.feePolicy.MaxPriorityFeeRate)
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if pubKey != contract.BuyerPubKey && pubKey != contract.SellerPubKey {
+		return nil, ErrNotParty
+	}
+	holds, err := s.HoldsKey(ctx, userID, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !holds {
+		return nil, ErrNotParty
+	}
+
+	deferral, err := s.deferralRepo.SetPriorityFeeRate(ctx, contractID, feeRate)
+	if err != nil {
+		return nil, err
+	}
+	if deferral == nil {
+		return nil, ErrNotDeferred
+	}
+
+	return deferral, nil
+}
+
+// ReleaseDeferredSettlements settles one batch of deferred contracts whose
+// settlement can now go ahead, oldest first, and returns how many settled.
+// Deferrals of contracts that are no longer active are released unsettled.
+func (s *Service) ReleaseDeferredSettlements(ctx context.Context) (int, error) {
+	if !s.feePolicyEnabled() {
+		return 0, ErrFeePolicyNotEnabled
+	}
+
+	deferrals, err := s.deferralRepo.ListPending(ctx, s.feePolicy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, deferral := range deferrals {
+		contract, err := s.contractRepo.GetByID(ctx, deferral.ContractID)
+		if err != nil {
+			logger.Error().Err(err).Str("contractID", deferral.ContractID.String()).Msg("Failed to get deferred contract")
+			continue
+		}
+		if contract.Status != models.ContractStatusActive {
+			s.releaseDeferral(ctx, contract.ID)
+			continue
+		}
+
+		_, _, err = s.SettleContract(ctx, contract.ID)
+		if errors.Is(err, ErrSettlementDeferred) {
+			continue
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("contractID", contract.ID.String()).Msg("Failed to settle deferred contract")
+			continue
+		}
+		settled++
+	}
+
+	return settled, nil
+}
+
+// StartDeferredSettlements periodically releases deferred settlements
+func (s *Service) StartDeferredSettlements(ctx context.Context) {
+	if !s.feePolicyEnabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.feePolicy.ReleaseInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				settled, err := s.ReleaseDeferredSettlements(ctx)
+				if err != nil {
+					logger.Error().Err(err).Msg("Failed to release deferred settlements")
+					continue
+				}
+				if settled > 0 {
+					logger.Info().Int("settled", settled).Msg("Released deferred settlements")
+				}
+			}
+		}
+	}()
+}
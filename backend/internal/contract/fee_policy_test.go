@@ -0,0 +1,133 @@
+// internal/contract/fee_policy_test.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"hashhedge/internal/contract/mocks"
+	"hashhedge/internal/models"
+)
+
+func TestSettlementFeeRate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	policy := DefaultFeePolicyConfig
+	policy.StressFeeRate = 50
+	policy.UrgentWithin = 6 * time.Hour
+
+	priority := 80.0
+	released := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		estimate  float64
+		deferral  *models.SettlementDeferral
+		expiresIn time.Duration
+		wantRate  float64
+		wantOK    bool
+	}{
+		{"fees below threshold", 20, nil, 48 * time.Hour, 20, true},
+		{"fees at threshold", 50, nil, 48 * time.Hour, 50, true},
+		{"high fees defer", 120, nil, 48 * time.Hour, 0, false},
+		{"pending deferral without priority stays deferred", 120, &models.SettlementDeferral{FeeRate: 100}, 48 * time.Hour, 0, false},
+		{"priority fee releases", 120, &models.SettlementDeferral{FeeRate: 100, PriorityFeeRate: &priority}, 48 * time.Hour, 80, true},
+		{"released deferral priority is ignored", 120, &models.SettlementDeferral{PriorityFeeRate: &priority, ReleasedAt: &released}, 48 * time.Hour, 0, false},
+		{"urgent contract settles at the estimate", 120, nil, 6 * time.Hour, 120, true},
+		{"expired contract settles at the estimate", 120, nil, -time.Hour, 120, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := policy.settlementFeeRate(tt.estimate, tt.deferral, now.Add(tt.expiresIn), now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRate, rate)
+		})
+	}
+}
+
+func TestFeePolicyConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultFeePolicyConfig.Validate())
+
+	enabled := DefaultFeePolicyConfig
+	enabled.StressFeeRate = 50
+	assert.NoError(t, enabled.Validate())
+
+	low := enabled
+	low.MaxPriorityFeeRate = 50
+	assert.Error(t, low.Validate())
+
+	noBatch := enabled
+	noBatch.BatchSize = 0
+	assert.Error(t, noBatch.Validate())
+
+	negative := DefaultFeePolicyConfig
+	negative.StressFeeRate = -1
+	assert.Error(t, negative.Validate())
+}
+
+type stubFeeEstimator struct {
+	rate float64
+	err  error
+}
+
+func (s stubFeeEstimator) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	return s.rate, s.err
+}
+
+func TestEstimateFeeRate(t *testing.T) {
+	ctx := context.Background()
+	policy := DefaultFeePolicyConfig
+	policy.FallbackFeeRate = 15
+
+	s := &Service{feePolicy: policy}
+	assert.Equal(t, defaultSettlementFeeRate, s.estimateFeeRate(ctx))
+
+	s.feeEstimator = stubFeeEstimator{rate: 23.5}
+	assert.Equal(t, 23.5, s.estimateFeeRate(ctx))
+
+	s.feeEstimator = stubFeeEstimator{rate: 0.4}
+	assert.Equal(t, minFeeRate, s.estimateFeeRate(ctx))
+
+	s.feeEstimator = stubFeeEstimator{err: errors.New("insufficient data")}
+	assert.Equal(t, 15.0, s.estimateFeeRate(ctx))
+}
+
+func TestBumpSettlementFee(t *testing.T) {
+	contract := &models.Contract{ID: uuid.New(), BuyerPubKey: "02aa", SellerPubKey: "03bb"}
+	buyer, stranger := uuid.New(), uuid.New()
+	// Only the last bump reaches the store
+	priority := 80.0
+	deferrals := mocks.NewMockDeferralStore(gomock.NewController(t))
+	deferrals.EXPECT().SetPriorityFeeRate(gomock.Any(), contract.ID, priority).
+		Return(&models.SettlementDeferral{ContractID: contract.ID, FeeRate: 120, PriorityFeeRate: &priority}, nil)
+
+	policy := DefaultFeePolicyConfig
+	policy.StressFeeRate = 50
+	s := &Service{contractRepo: &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: contract}}}
+	s.WithFeePolicy(policy, stubFeeEstimator{rate: 120}, deferrals).
+		WithUserKeys(stubUserKeys{buyer: {{PubKey: "02aa"}}, stranger: {{PubKey: "02cc"}}})
+	ctx := context.Background()
+
+	// The contract's keys are public, so naming one is not enough
+	_, err := s.BumpSettlementFee(ctx, stranger, contract.ID, "02aa", 80)
+	assert.ErrorIs(t, err, ErrNotParty)
+	_, err = s.BumpSettlementFee(ctx, stranger, contract.ID, "02cc", 80)
+	assert.ErrorIs(t, err, ErrNotParty)
+	_, err = s.BumpSettlementFee(ctx, buyer, contract.ID, "03bb", 80)
+	assert.ErrorIs(t, err, ErrNotParty)
+
+	_, err = s.BumpSettlementFee(ctx, buyer, contract.ID, "02aa", policy.MaxPriorityFeeRate+1)
+	assert.Error(t, err)
+
+	deferral, err := s.BumpSettlementFee(ctx, buyer, contract.ID, "02aa", 80)
+	require.NoError(t, err)
+	require.NotNil(t, deferral.PriorityFeeRate)
+	assert.Equal(t, 80.0, *deferral.PriorityFeeRate)
+}
@@ -0,0 +1,362 @@
+// internal/contract/funding.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/taproot"
+)
+
+var (
+	// ErrFundingNotEnabled is returned when no funding store is configured
+	ErrFundingNotEnabled = errors.New("funding tracking is not enabled")
+	// ErrInvalidFundingSignature is returned for a funding PSBT carrying a
+	// signature that does not verify against the output it spends
+	ErrInvalidFundingSignature = errors.New("invalid funding signature")
+)
+
+// FundingStatus is the funding progress of a contract as reported to clients
+type FundingStatus struct {
+	ContractID          uuid.UUID `json:"contract_id"`
+	BuyerFunded         bool      `json:"buyer_funded"`
+	SellerFunded        bool      `json:"seller_funded"`
+	BuyerSigned         bool      `json:"buyer_signed"`
+	SellerSigned        bool      `json:"seller_signed"`
+	SignaturesCollected int       `json:"signatures_collected"`
+	SignaturesRequired  int       `json:"signatures_required"`
+	Complete            bool      `json:"complete"`
+}
+
+// WithFundingStore enables funding progress tracking
+func (s *Service) WithFundingStore(store FundingStore) *Service {
+	s.fundingRepo = store
+	return s
+}
+
+// WithPublisher enables streaming of contract updates to websocket subscribers
+func (s *Service) WithPublisher(publisher ChannelPublisher) *Service {
+	s.publisher = publisher
+	return s
+}
+
+// GetFundingStatus returns the funding progress of a contract
+func (s *Service) GetFundingStatus(ctx context.Context, contractID uuid.UUID) (*FundingStatus, error) {
+	if s.fundingRepo == nil {
+		return nil, ErrFundingNotEnabled
+	}
+
+	if _, err := s.contractRepo.GetByID(ctx, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	funding, err := s.fundingRepo.GetByContractID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFundingStatus(funding), nil
+}
+
+// SubmitFundingPSBT records a party's funding PSBT for the setup transaction.
+// The party is identified by its contract public key, which the user must
+// hold. The PSBT counts as funded when it carries UTXO data for at least one
+// input, and as signed when one of its inputs is finalized or carries the
+// party's signature. Every signature is verified against the output it
+// spends before the PSBT replaces the party's previous submission.
+func (s *Service) SubmitFundingPSBT(
+	ctx context.Context,
+	userID uuid.UUID,
+	contractID uuid.UUID,
+	pubKey string,
+	serializedPsbt string,
+) (*FundingStatus, error) {
+	if s.fundingRepo == nil {
+		return nil, ErrFundingNotEnabled
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if contract.Status != models.ContractStatusCreated && contract.Status != models.ContractStatusActive {
+		return nil, errors.New("contract is not in setup")
+	}
+
+	var isBuyer bool
+	switch pubKey {
+	case contract.BuyerPubKey:
+		isBuyer = true
+	case contract.SellerPubKey:
+		isBuyer = false
+	default:
+		return nil, ErrNotParty
+	}
+	holds, err := s.HoldsKey(ctx, userID, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !holds {
+		return nil, ErrNotParty
+	}
+
+	packet, err := bitcoin.ParsePSBT(serializedPsbt, bitcoin.DefaultParseLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PSBT: %w", err)
+	}
+
+	funded, signed, err := inspectFundingPSBT(packet, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	funding, err := s.fundingRepo.RecordSubmission(ctx, contractID, isBuyer, funded, signed, serializedPsbt)
+	if err != nil {
+		return nil, err
+	}
+
+	status := newFundingStatus(funding)
+
+	logger.Info().
+		Str("contract_id", contractID.String()).
+		Bool("buyer", isBuyer).
+		Bool("funded", funded).
+		Bool("signed", signed).
+		Int("signatures_collected", status.SignaturesCollected).
+		Msg("Funding PSBT submitted")
+
+	if s.publisher != nil {
+		s.publisher.PublishToChannel("contract:"+contractID.String()+":funding", map[string]interface{}{
+			"type":    "funding",
+			"payload": status,
+		})
+	}
+
+	return status, nil
+}
+
+// inspectFundingPSBT reports whether the PSBT funds at least one input and
+// whether it is signed, by a finalized input or a signature of pubKey. A
+// signed PSBT must carry every spent output, and each signature must verify.
+func inspectFundingPSBT(packet *psbt.Packet, pubKey string) (bool, bool, error) {
+	if len(packet.Inputs) == 0 {
+		return false, false, errors.New("PSBT has no inputs")
+	}
+
+	key, err := taproot.ParsePubKey(pubKey)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid public key: %w", err)
+	}
+	pubKeyBytes, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid public key: %w", err)
+	}
+	xOnly := schnorr.SerializePubKey(key)
+
+	tx := packet.UnsignedTx.Copy()
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	funded, missing, hasSignatures := false, -1, false
+	for i, input := range packet.Inputs {
+		prevOut, err := fundingPrevOut(packet, i)
+		if err != nil {
+			return false, false, err
+		}
+		if prevOut != nil {
+			funded = true
+			prevOuts.AddPrevOut(tx.TxIn[i].PreviousOutPoint, prevOut)
+		} else if missing < 0 {
+			missing = i
+		}
+
+		if len(input.FinalScriptSig) > 0 || len(input.FinalScriptWitness) > 0 {
+			hasSignatures = true
+			tx.TxIn[i].SignatureScript = input.FinalScriptSig
+			if tx.TxIn[i].Witness, err = readWitness(input.FinalScriptWitness); err != nil {
+				return false, false, fmt.Errorf("%w: input %d witness: %v", ErrInvalidFundingSignature, i, err)
+			}
+		}
+		for _, sig := range input.PartialSigs {
+			hasSignatures = hasSignatures || bytes.Equal(sig.PubKey, pubKeyBytes)
+		}
+		for _, sig := range input.TaprootScriptSpendSig {
+			hasSignatures = hasSignatures || bytes.Equal(sig.XOnlyPubKey, xOnly)
+		}
+	}
+
+	if !hasSignatures {
+		return funded, false, nil
+	}
+	// Signature hashes commit to every spent output
+	if missing >= 0 {
+		return false, false, fmt.Errorf("%w: input %d has no spent output to verify against", ErrInvalidFundingSignature, missing)
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
+	signed := false
+	for i, input := range packet.Inputs {
+		prevOut := prevOuts.FetchPrevOutput(tx.TxIn[i].PreviousOutPoint)
+
+		if len(tx.TxIn[i].SignatureScript) > 0 || len(tx.TxIn[i].Witness) > 0 {
+			engine, err := txscript.NewEngine(prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+				nil, sigHashes, prevOut.Value, prevOuts)
+			if err == nil {
+				err = engine.Execute()
+			}
+			if err != nil {
+				return false, false, fmt.Errorf("%w: input %d: %v", ErrInvalidFundingSignature, i, err)
+			}
+			signed = true
+			continue
+		}
+
+		for _, sig := range input.PartialSigs {
+			if !bytes.Equal(sig.PubKey, pubKeyBytes) {
+				continue
+			}
+			if err := verifyPartialSig(tx, i, input, prevOut, sigHashes, sig.Signature, key); err != nil {
+				return false, false, fmt.Errorf("%w: input %d: %v", ErrInvalidFundingSignature, i, err)
+			}
+			signed = true
+		}
+
+		for _, sig := range input.TaprootScriptSpendSig {
+			if !bytes.Equal(sig.XOnlyPubKey, xOnly) {
+				continue
+			}
+			leaf, err := psbt.FindLeafScript(&packet.Inputs[i], sig.LeafHash)
+			if err != nil {
+				return false, false, fmt.Errorf("%w: input %d: %v", ErrInvalidFundingSignature, i, err)
+			}
+			hash, err := txscript.CalcTapscriptSignaturehash(sigHashes, sig.SigHash, tx, i, prevOuts,
+				txscript.NewTapLeaf(leaf.LeafVersion, leaf.Script))
+			if err != nil {
+				return false, false, fmt.Errorf("%w: input %d: %v", ErrInvalidFundingSignature, i, err)
+			}
+			signature, err := schnorr.ParseSignature(sig.Signature)
+			if err != nil || !signature.Verify(hash, key) {
+				return false, false, fmt.Errorf("%w: input %d: tapscript signature does not verify", ErrInvalidFundingSignature, i)
+			}
+			signed = true
+		}
+	}
+
+	return funded, signed, nil
+}
+
+// fundingPrevOut returns the output spent by input i of a PSBT, or nil if
+// the PSBT does not carry it. A full previous transaction must be the one
+// the input spends.
+func fundingPrevOut(packet *psbt.Packet, i int) (*wire.TxOut, error) {
+	input := packet.Inputs[i]
+	if input.WitnessUtxo != nil {
+		return input.WitnessUtxo, nil
+	}
+	if input.NonWitnessUtxo == nil {
+		return nil, nil
+	}
+
+	outPoint := packet.UnsignedTx.TxIn[i].PreviousOutPoint
+	if input.NonWitnessUtxo.TxHash() != outPoint.Hash || int(outPoint.Index) >= len(input.NonWitnessUtxo.TxOut) {
+		return nil, fmt.Errorf("input %d previous transaction is not the one it spends", i)
+	}
+	return input.NonWitnessUtxo.TxOut[outPoint.Index], nil
+}
+
+// readWitness decodes a PSBT final script witness
+func readWitness(serialized []byte) (wire.TxWitness, error) {
+	if len(serialized) == 0 {
+		return nil, nil
+	}
+
+	r := bytes.NewReader(serialized)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(len(serialized)) {
+		return nil, fmt.Errorf("witness has %d items in %d bytes", count, len(serialized))
+	}
+
+	witness := make(wire.TxWitness, count)
+	for i := range witness {
+		if witness[i], err = wire.ReadVarBytes(r, 0, txscript.MaxScriptSize, "witness"); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing witness bytes", r.Len())
+	}
+	return witness, nil
+}
+
+// verifyPartialSig verifies an ECDSA partial signature of key on input i,
+// which spends prevOut directly, through a P2SH redeem script, or through a
+// P2WSH witness script
+func verifyPartialSig(
+	tx *wire.MsgTx,
+	i int,
+	input psbt.PInput,
+	prevOut *wire.TxOut,
+	sigHashes *txscript.TxSigHashes,
+	sig []byte,
+	key *btcec.PublicKey,
+) error {
+	if len(sig) == 0 {
+		return errors.New("empty signature")
+	}
+	hashType := txscript.SigHashType(sig[len(sig)-1])
+	signature, err := ecdsa.ParseDERSignature(sig[:len(sig)-1])
+	if err != nil {
+		return err
+	}
+
+	script := prevOut.PkScript
+	if len(input.RedeemScript) > 0 {
+		script = input.RedeemScript
+	}
+
+	var hash []byte
+	switch {
+	case len(input.WitnessScript) > 0:
+		hash, err = txscript.CalcWitnessSigHash(input.WitnessScript, sigHashes, hashType, tx, i, prevOut.Value)
+	case txscript.IsPayToWitnessPubKeyHash(script):
+		hash, err = txscript.CalcWitnessSigHash(script, sigHashes, hashType, tx, i, prevOut.Value)
+	default:
+		hash, err = txscript.CalcSignatureHash(script, hashType, tx, i)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !signature.Verify(hash, key) {
+		return errors.New("signature does not verify")
+	}
+	return nil
+}
+
+func newFundingStatus(f *models.ContractFunding) *FundingStatus {
+	return &FundingStatus{
+		ContractID:          f.ContractID,
+		BuyerFunded:         f.BuyerFunded,
+		SellerFunded:        f.SellerFunded,
+		BuyerSigned:         f.BuyerSigned,
+		SellerSigned:        f.SellerSigned,
+		SignaturesCollected: f.SignaturesCollected(),
+		SignaturesRequired:  2,
+		Complete:            f.IsComplete(),
+	}
+}
@@ -0,0 +1,120 @@
+// internal/contract/funding_test.go
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fundingPacket builds a PSBT spending one P2WPKH output of key, with the
+// spent output attached
+func fundingPacket(t *testing.T, key *btcec.PrivateKey) *psbt.Packet {
+	t.Helper()
+
+	pkScript, err := txscript.PayToAddrScript(mustP2WPKH(t, key))
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, pkScript))
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	require.NoError(t, err)
+	packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, pkScript)
+	return packet
+}
+
+func mustP2WPKH(t *testing.T, key *btcec.PrivateKey) btcutil.Address {
+	t.Helper()
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	return addr
+}
+
+// fundingSignature signs the single input of a funding packet with key
+func fundingSignature(t *testing.T, packet *psbt.Packet, key *btcec.PrivateKey) []byte {
+	t.Helper()
+
+	prevOut := packet.Inputs[0].WitnessUtxo
+	fetcher := txscript.NewCannedPrevOutputFetcher(prevOut.PkScript, prevOut.Value)
+	sig, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx, txscript.NewTxSigHashes(packet.UnsignedTx, fetcher),
+		0, prevOut.Value, prevOut.PkScript, txscript.SigHashAll, key)
+	require.NoError(t, err)
+	return sig
+}
+
+func TestInspectFundingPSBT(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKeyBytes := key.PubKey().SerializeCompressed()
+	pubKey := hex.EncodeToString(pubKeyBytes)
+
+	t.Run("unsigned", func(t *testing.T) {
+		funded, signed, err := inspectFundingPSBT(fundingPacket(t, key), pubKey)
+		require.NoError(t, err)
+		assert.True(t, funded)
+		assert.False(t, signed)
+	})
+
+	t.Run("partial signature", func(t *testing.T) {
+		packet := fundingPacket(t, key)
+		packet.Inputs[0].PartialSigs = []*psbt.PartialSig{{PubKey: pubKeyBytes, Signature: fundingSignature(t, packet, key)}}
+
+		funded, signed, err := inspectFundingPSBT(packet, pubKey)
+		require.NoError(t, err)
+		assert.True(t, funded)
+		assert.True(t, signed)
+	})
+
+	t.Run("final witness", func(t *testing.T) {
+		packet := fundingPacket(t, key)
+		var witness bytes.Buffer
+		require.NoError(t, psbt.WriteTxWitness(&witness, wire.TxWitness{fundingSignature(t, packet, key), pubKeyBytes}))
+		packet.Inputs[0].FinalScriptWitness = witness.Bytes()
+
+		_, signed, err := inspectFundingPSBT(packet, pubKey)
+		require.NoError(t, err)
+		assert.True(t, signed)
+	})
+
+	t.Run("signature of another key", func(t *testing.T) {
+		other, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		packet := fundingPacket(t, key)
+		packet.Inputs[0].PartialSigs = []*psbt.PartialSig{{PubKey: pubKeyBytes, Signature: fundingSignature(t, packet, other)}}
+
+		_, _, err = inspectFundingPSBT(packet, pubKey)
+		assert.ErrorIs(t, err, ErrInvalidFundingSignature)
+	})
+
+	t.Run("forged final witness", func(t *testing.T) {
+		packet := fundingPacket(t, key)
+		var witness bytes.Buffer
+		require.NoError(t, psbt.WriteTxWitness(&witness, wire.TxWitness{[]byte{0x30, 0x01}, pubKeyBytes}))
+		packet.Inputs[0].FinalScriptWitness = witness.Bytes()
+
+		_, _, err := inspectFundingPSBT(packet, pubKey)
+		assert.ErrorIs(t, err, ErrInvalidFundingSignature)
+	})
+
+	t.Run("signature without spent output", func(t *testing.T) {
+		packet := fundingPacket(t, key)
+		packet.Inputs[0].PartialSigs = []*psbt.PartialSig{{PubKey: pubKeyBytes, Signature: fundingSignature(t, packet, key)}}
+		packet.Inputs[0].WitnessUtxo = nil
+
+		_, _, err := inspectFundingPSBT(packet, pubKey)
+		assert.ErrorIs(t, err, ErrInvalidFundingSignature)
+	})
+}
@@ -0,0 +1,184 @@
+// internal/contract/hashrate/index.go
+package hashrate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+// BlocksPerDay is the number of blocks mined per day at the target interval
+const BlocksPerDay = 144
+
+// AverageWindows are the rolling averages reported by the hash rate index, in blocks
+var AverageWindows = []struct {
+	Name   string
+	Blocks int64
+}{
+	{Name: "1d", Blocks: BlocksPerDay},
+	{Name: "7d", Blocks: 7 * BlocksPerDay},
+	{Name: "30d", Blocks: 30 * BlocksPerDay},
+}
+
+// Index is the network hash rate at the chain tip and its rolling averages in EH/s
+type Index struct {
+	BlockHeight int64              `json:"block_height"`
+	BlockTime   time.Time          `json:"block_time"`
+	Current     float64            `json:"current"`
+	Averages    map[string]float64 `json:"averages"`
+}
+
+// BlockHashRate is the hash rate implied by the trailing window of blocks
+// ending at a block, in EH/s
+type BlockHashRate struct {
+	Height     int64     `json:"height"`
+	Time       time.Time `json:"time"`
+	Difficulty float64   `json:"difficulty"`
+	HashRate   float64   `json:"hash_rate"`
+}
+
+// impliedHashRate converts a difficulty and mean block interval in seconds to EH/s
+func impliedHashRate(difficulty, interval float64) float64 {
+	return (difficulty * math.Pow(2, 32)) / (interval * 1e12)
+}
+
+// periodHashRate returns the hash rate implied by the blocks between start
+// and end: the mean of their difficulties over the mean block interval.
+// Difficulty only changes every 2016 blocks, so the endpoints bound it.
+func periodHashRate(start, end *bitcoin.Block) (float64, error) {
+	blocks := end.Height - start.Height
+	if blocks <= 0 {
+		return 0, fmt.Errorf("start height must be less than end height")
+	}
+
+	span := end.Time.Sub(start.Time).Seconds()
+	if span <= 0 {
+		return 0, fmt.Errorf("invalid time span between blocks %d and %d: %v", start.Height, end.Height, span)
+	}
+
+	return impliedHashRate((start.Difficulty+end.Difficulty)/2, span/float64(blocks)), nil
+}
+
+// blockSeries returns the hash rate at each block after the first window
+// blocks. Blocks must be consecutive and ordered by height; blocks whose
+// window has no positive time span are skipped.
+func blockSeries(blocks []*bitcoin.Block, window int) []BlockHashRate {
+	if window < 1 || len(blocks) <= window {
+		return []BlockHashRate{}
+	}
+
+	series := make([]BlockHashRate, 0, len(blocks)-window)
+	difficulty := 0.0
+	for i := 1; i <= window; i++ {
+		difficulty += blocks[i].Difficulty
+	}
+
+	for i := window; i < len(blocks); i++ {
+		if i > window {
+			difficulty += blocks[i].Difficulty - blocks[i-window].Difficulty
+		}
+
+		span := blocks[i].Time.Sub(blocks[i-window].Time).Seconds()
+		if span <= 0 {
+			continue
+		}
+
+		series = append(series, BlockHashRate{
+			Height:     blocks[i].Height,
+			Time:       blocks[i].Time,
+			Difficulty: blocks[i].Difficulty,
+			HashRate:   impliedHashRate(difficulty/float64(window), span/float64(window)),
+		})
+	}
+
+	return series
+}
+
+// blockAt fetches the block at a height
+func (c *HashRateCalculator) blockAt(ctx context.Context, height int64) (*bitcoin.Block, error) {
+	hash, err := c.client.GetBlockHash(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block hash at height %d: %w", height, err)
+	}
+
+	block, err := c.client.GetBlock(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block at height %d: %w", height, err)
+	}
+
+	return block, nil
+}
+
+// CurrentIndex returns the hash rate at the chain tip and its rolling averages
+func (c *HashRateCalculator) CurrentIndex(ctx context.Context) (*Index, error) {
+	bestBlockHash, err := c.client.GetBestBlockHash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best block hash: %w", err)
+	}
+
+	tip, err := c.client.GetBlock(ctx, bestBlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best block: %w", err)
+	}
+
+	current, err := c.CalculateCurrentHashRate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &Index{
+		BlockHeight: tip.Height,
+		BlockTime:   tip.Time,
+		Current:     current,
+		Averages:    make(map[string]float64, len(AverageWindows)),
+	}
+
+	for _, window := range AverageWindows {
+		if tip.Height < window.Blocks {
+			continue
+		}
+
+		start, err := c.blockAt(ctx, tip.Height-window.Blocks)
+		if err != nil {
+			return nil, err
+		}
+
+		average, err := periodHashRate(start, tip)
+		if err != nil {
+			return nil, err
+		}
+		index.Averages[window.Name] = average
+	}
+
+	return index, nil
+}
+
+// History returns the hash rate at each block from fromHeight to toHeight,
+// each measured over the trailing window blocks
+func (c *HashRateCalculator) History(ctx context.Context, fromHeight, toHeight int64, window int) ([]BlockHashRate, error) {
+	if fromHeight > toHeight {
+		return nil, fmt.Errorf("from height must not exceed to height")
+	}
+	if window < 1 {
+		return nil, fmt.Errorf("window must be at least one block")
+	}
+
+	first := fromHeight - int64(window)
+	if first < 0 {
+		first = 0
+	}
+
+	blocks := make([]*bitcoin.Block, 0, toHeight-first+1)
+	for height := first; height <= toHeight; height++ {
+		block, err := c.blockAt(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blockSeries(blocks, window), nil
+}
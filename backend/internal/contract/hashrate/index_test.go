@@ -0,0 +1,62 @@
+// internal/contract/hashrate/index_test.go
+package hashrate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+// chain returns consecutive blocks from height 100 mined interval apart at
+// the given difficulty
+func chain(n int, interval time.Duration, difficulty float64) []*bitcoin.Block {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	blocks := make([]*bitcoin.Block, n)
+	for i := range blocks {
+		blocks[i] = &bitcoin.Block{
+			Height:     int64(100 + i),
+			Time:       start.Add(time.Duration(i) * interval),
+			Difficulty: difficulty,
+		}
+	}
+	return blocks
+}
+
+func TestPeriodHashRate(t *testing.T) {
+	blocks := chain(11, 10*time.Minute, 1e14)
+	expected := 1e14 * math.Pow(2, 32) / (600 * 1e12)
+
+	rate, err := periodHashRate(blocks[0], blocks[10])
+	assert.NoError(t, err)
+	assert.InDelta(t, expected, rate, 1e-6)
+
+	_, err = periodHashRate(blocks[10], blocks[0])
+	assert.Error(t, err)
+
+	stalled := *blocks[10]
+	stalled.Time = blocks[0].Time
+	_, err = periodHashRate(blocks[0], &stalled)
+	assert.Error(t, err)
+}
+
+func TestBlockSeries(t *testing.T) {
+	blocks := chain(6, 10*time.Minute, 1e14)
+	// Halve the interval of the last two blocks, doubling the implied hash rate
+	blocks[4].Time = blocks[3].Time.Add(5 * time.Minute)
+	blocks[5].Time = blocks[4].Time.Add(5 * time.Minute)
+	base := 1e14 * math.Pow(2, 32) / (600 * 1e12)
+
+	series := blockSeries(blocks, 2)
+	assert.Len(t, series, 4)
+	assert.Equal(t, int64(102), series[0].Height)
+	assert.InDelta(t, base, series[0].HashRate, 1e-6)
+	assert.InDelta(t, base, series[1].HashRate, 1e-6)
+	assert.InDelta(t, base*2, series[3].HashRate, 1e-6)
+
+	assert.Empty(t, blockSeries(blocks, 6))
+	assert.Empty(t, blockSeries(blocks, 0))
+}
@@ -0,0 +1,120 @@
+// internal/contract/hashrate/ladder.go
+package hashrate
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+const (
+	// EpochBlocks is the number of blocks between difficulty adjustments
+	EpochBlocks = 2016
+	// StrikeStep is the spacing of the strike ladder as a fraction of the reference hash rate
+	StrikeStep = 0.05
+	// DefaultStrikeSteps is the number of strikes offered on each side of the reference
+	DefaultStrikeSteps = 4
+	// MaxStrikeSteps bounds the strikes requested on each side of the reference
+	MaxStrikeSteps = 10
+	// ladderReference is the rolling average the ladder is centred on, which
+	// moves less between blocks than the current hash rate
+	ladderReference = "7d"
+	// SeriesStarts is the number of upcoming epochs offered as series start heights
+	SeriesStarts = 2
+	// targetBlockInterval is the block interval used to estimate series dates
+	targetBlockInterval = 10 * time.Minute
+)
+
+// SeriesEpochs are the durations of the offered series, in difficulty epochs
+var SeriesEpochs = []int64{1, 2, 4}
+
+// Series is a standard contract block range aligned to difficulty epochs
+type Series struct {
+	StartBlockHeight int64     `json:"start_block_height"`
+	EndBlockHeight   int64     `json:"end_block_height"`
+	Epochs           int64     `json:"epochs"`
+	EstimatedStart   time.Time `json:"estimated_start"`
+	EstimatedEnd     time.Time `json:"estimated_end"`
+}
+
+// Ladder is the set of recommended strikes and block ranges for new
+// contracts, so that orders concentrate on a few standard markets
+type Ladder struct {
+	BlockHeight int64     `json:"block_height"`
+	BlockTime   time.Time `json:"block_time"`
+	Reference   float64   `json:"reference"`
+	Strikes     []float64 `json:"strikes"`
+	Series      []Series  `json:"series"`
+}
+
+// ladderStrikes returns strikes spaced StrikeStep of the reference apart,
+// steps on each side of it, rounded to whole EH/s so the ladder stays the
+// same while the reference drifts. Strikes that round to the same value or
+// to zero are dropped.
+func ladderStrikes(reference float64, steps int) []float64 {
+	strikes := make([]float64, 0, 2*steps+1)
+	for i := -steps; i <= steps; i++ {
+		strike := math.Round(reference * (1 + float64(i)*StrikeStep))
+		if strike <= 0 {
+			continue
+		}
+		if n := len(strikes); n > 0 && strikes[n-1] == strike {
+			continue
+		}
+		strikes = append(strikes, strike)
+	}
+	return strikes
+}
+
+// ladderSeries returns the series starting at each of the next SeriesStarts
+// difficulty adjustments after the tip, for each of the SeriesEpochs
+// durations, with dates estimated at the target block interval
+func ladderSeries(tip int64, tipTime time.Time) []Series {
+	next := (tip/EpochBlocks + 1) * EpochBlocks
+	estimate := func(height int64) time.Time {
+		return tipTime.Add(time.Duration(height-tip) * targetBlockInterval)
+	}
+
+	series := make([]Series, 0, SeriesStarts*len(SeriesEpochs))
+	for i := 0; i < SeriesStarts; i++ {
+		start := next + int64(i)*EpochBlocks
+		for _, epochs := range SeriesEpochs {
+			end := start + epochs*EpochBlocks
+			series = append(series, Series{
+				StartBlockHeight: start,
+				EndBlockHeight:   end,
+				Epochs:           epochs,
+				EstimatedStart:   estimate(start),
+				EstimatedEnd:     estimate(end),
+			})
+		}
+	}
+	return series
+}
+
+// buildLadder returns the ladder around an index, centred on its 7 day
+// average or, on a chain too short to have one, its current hash rate
+func buildLadder(index *Index, steps int) *Ladder {
+	reference, ok := index.Averages[ladderReference]
+	if !ok {
+		reference = index.Current
+	}
+
+	return &Ladder{
+		BlockHeight: index.BlockHeight,
+		BlockTime:   index.BlockTime,
+		Reference:   reference,
+		Strikes:     ladderStrikes(reference, steps),
+		Series:      ladderSeries(index.BlockHeight, index.BlockTime),
+	}
+}
+
+// StrikeLadder returns the recommended strikes, steps on each side of the
+// reference hash rate, and block ranges of new contracts
+func (c *HashRateCalculator) StrikeLadder(ctx context.Context, steps int) (*Ladder, error) {
+	index, err := c.CurrentIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildLadder(index, steps), nil
+}
@@ -0,0 +1,46 @@
+// internal/contract/hashrate/ladder_test.go
+package hashrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLadderStrikes(t *testing.T) {
+	assert.Equal(t, []float64{400, 425, 450, 475, 500, 525, 550, 575, 600}, ladderStrikes(500, 4))
+	assert.Equal(t, []float64{499}, ladderStrikes(499.4, 0))
+
+	// Strikes that round together or to zero are dropped
+	assert.Equal(t, []float64{1, 2}, ladderStrikes(1.6, 10))
+}
+
+func TestLadderSeries(t *testing.T) {
+	tipTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := ladderSeries(850000, tipTime)
+
+	assert.Len(t, series, SeriesStarts*len(SeriesEpochs))
+	assert.Equal(t, Series{
+		StartBlockHeight: 850752,
+		EndBlockHeight:   852768,
+		Epochs:           1,
+		EstimatedStart:   tipTime.Add(752 * 10 * time.Minute),
+		EstimatedEnd:     tipTime.Add(2768 * 10 * time.Minute),
+	}, series[0])
+	assert.Equal(t, int64(852768), series[3].StartBlockHeight)
+	assert.Equal(t, int64(852768+4*EpochBlocks), series[5].EndBlockHeight)
+
+	// A tip on an adjustment starts the ladder at the next one
+	assert.Equal(t, int64(852768), ladderSeries(850752, tipTime)[0].StartBlockHeight)
+}
+
+func TestBuildLadderReference(t *testing.T) {
+	index := &Index{BlockHeight: 1000, Current: 612.3, Averages: map[string]float64{"1d": 640}}
+	assert.Equal(t, 612.3, buildLadder(index, 1).Reference)
+
+	index.Averages[ladderReference] = 600
+	ladder := buildLadder(index, 1)
+	assert.Equal(t, 600.0, ladder.Reference)
+	assert.Equal(t, []float64{570, 600, 630}, ladder.Strikes)
+}
@@ -0,0 +1,120 @@
+// internal/contract/hashrate/sampler.go
+package hashrate
+
+import (
+	"context"
+	"time"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// SamplerConfig holds the hash rate sampler configuration
+type SamplerConfig struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+// DefaultSamplerConfig samples about once per block
+var DefaultSamplerConfig = SamplerConfig{
+	Interval: 10 * time.Minute,
+}
+
+// ObservationStore persists hash rate observations
+type ObservationStore interface {
+	Insert(ctx context.Context, obs *models.HashRateObservation) (bool, error)
+}
+
+// EventPublisher publishes events on the event bus
+type EventPublisher interface {
+	Publish(topic events.Topic, payload interface{})
+}
+
+// Sampler records the hash rate index at regular intervals so consumers can
+// read stored observations instead of recomputing them from the node
+type Sampler struct {
+	calculator *HashRateCalculator
+	store      ObservationStore
+	cfg        SamplerConfig
+	events     EventPublisher
+}
+
+// NewSampler creates a new hash rate sampler
+func NewSampler(calculator *HashRateCalculator, store ObservationStore, cfg SamplerConfig) *Sampler {
+	return &Sampler{
+		calculator: calculator,
+		store:      store,
+		cfg:        cfg,
+	}
+}
+
+// WithEventBus publishes each newly sampled block's observation to bus
+func (s *Sampler) WithEventBus(bus EventPublisher) *Sampler {
+	s.events = bus
+	return s
+}
+
+// Start begins sampling, taking the first sample immediately
+func (s *Sampler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		s.sample(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sample(ctx)
+			}
+		}
+	}()
+}
+
+// sample records the index at the current chain tip. A tip that was already
+// recorded is skipped, so sampling faster than blocks arrive is harmless.
+func (s *Sampler) sample(ctx context.Context) {
+	index, err := s.calculator.CurrentIndex(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to sample hash rate")
+		return
+	}
+
+	obs := newObservation(index, time.Now().UTC())
+	inserted, err := s.store.Insert(ctx, obs)
+	if err != nil {
+		logger.Error().Err(err).Int64("height", index.BlockHeight).Msg("Failed to store hash rate observation")
+		return
+	}
+
+	if inserted {
+		logger.Debug().Int64("height", index.BlockHeight).Float64("hash_rate", index.Current).Msg("Hash rate sampled")
+		if s.events != nil {
+			s.events.Publish(events.TopicHashRate, *obs)
+		}
+	}
+}
+
+// newObservation converts an index into an observation recorded at now
+func newObservation(index *Index, now time.Time) *models.HashRateObservation {
+	average := func(name string) *float64 {
+		value, ok := index.Averages[name]
+		if !ok {
+			return nil
+		}
+		return &value
+	}
+
+	return &models.HashRateObservation{
+		BlockHeight: index.BlockHeight,
+		BlockTime:   index.BlockTime,
+		HashRate:    index.Current,
+		Average1d:   average("1d"),
+		Average7d:   average("7d"),
+		Average30d:  average("30d"),
+		ObservedAt:  now,
+	}
+}
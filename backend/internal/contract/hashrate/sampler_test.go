@@ -0,0 +1,32 @@
+// internal/contract/hashrate/sampler_test.go
+package hashrate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewObservation(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	index := &Index{
+		BlockHeight: 5000,
+		BlockTime:   now.Add(-time.Minute),
+		Current:     610.5,
+		Averages:    map[string]float64{"1d": 600, "7d": 590},
+	}
+
+	obs := newObservation(index, now)
+
+	assert.Equal(t, int64(5000), obs.BlockHeight)
+	assert.Equal(t, index.BlockTime, obs.BlockTime)
+	assert.Equal(t, 610.5, obs.HashRate)
+	require.NotNil(t, obs.Average1d)
+	assert.Equal(t, 600.0, *obs.Average1d)
+	require.NotNil(t, obs.Average7d)
+	assert.Equal(t, 590.0, *obs.Average7d)
+	assert.Nil(t, obs.Average30d, "30d average is unavailable on a short chain")
+	assert.Equal(t, now, obs.ObservedAt)
+}
@@ -0,0 +1,274 @@
+// internal/contract/hashrate/statistics.go
+package hashrate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+const (
+	// DefaultStatisticsWindow is the blocks statistics are computed over by default
+	DefaultStatisticsWindow = EpochBlocks
+	// MaxStatisticsWindow bounds the blocks fetched for one set of statistics
+	MaxStatisticsWindow = 2 * EpochBlocks
+	// DefaultStatisticsEpochs is the difficulty adjustments reported by default
+	DefaultStatisticsEpochs = 6
+	// MaxStatisticsEpochs bounds the difficulty adjustments reported
+	MaxStatisticsEpochs = 26
+)
+
+// IntervalPercentiles are the percentiles of block intervals reported
+var IntervalPercentiles = []float64{5, 25, 50, 75, 95, 99}
+
+// intervalEdges are the upper bounds in seconds of the block interval
+// histogram buckets; the last bucket is open ended
+var intervalEdges = []float64{60, 120, 300, 600, 1200, 1800, 3600}
+
+// IntervalBucket counts the block intervals from Min up to, but excluding, Max seconds
+type IntervalBucket struct {
+	Min   float64  `json:"min_seconds"`
+	Max   *float64 `json:"max_seconds,omitempty"`
+	Count int      `json:"count"`
+}
+
+// IntervalDistribution describes the time between consecutive blocks.
+// Block timestamps may go backwards, and such intervals count as zero.
+type IntervalDistribution struct {
+	Intervals   int                `json:"intervals"`
+	Mean        float64            `json:"mean_seconds"`
+	StdDev      float64            `json:"std_dev_seconds"`
+	Percentiles map[string]float64 `json:"percentiles"` // In seconds
+	Histogram   []IntervalBucket   `json:"histogram"`
+}
+
+// DifficultyChange is a difficulty adjustment and the epoch that led to it
+type DifficultyChange struct {
+	Height             int64     `json:"height"`
+	Time               time.Time `json:"time"`
+	Difficulty         float64   `json:"difficulty"`
+	PreviousDifficulty float64   `json:"previous_difficulty"`
+	Change             float64   `json:"change"`               // Fractional change from the previous epoch
+	EpochDuration      float64   `json:"epoch_duration_hours"` // Time the previous epoch took
+	MeanInterval       float64   `json:"mean_interval_seconds"`
+}
+
+// RealizedHashRate is the realized volatility of the hash rate sampled over
+// consecutive, non-overlapping runs of blocks
+type RealizedHashRate struct {
+	Samples      int     `json:"samples"`
+	SampleBlocks int64   `json:"sample_blocks"`
+	Mean         float64 `json:"mean"`       // In EH/s
+	Volatility   float64 `json:"volatility"` // Annualized realized volatility of log changes
+}
+
+// Statistics are the realized hash rate volatility, block interval
+// distribution and recent difficulty adjustments of the chain, for pricing
+// premiums on realized variance
+type Statistics struct {
+	BlockHeight       int64                `json:"block_height"`
+	Window            int64                `json:"window"` // In blocks
+	From              time.Time            `json:"from"`
+	To                time.Time            `json:"to"`
+	HashRate          RealizedHashRate     `json:"hash_rate"`
+	Intervals         IntervalDistribution `json:"intervals"`
+	DifficultyChanges []DifficultyChange   `json:"difficulty_changes"`
+	// MeanDifficultyChange and DifficultyChangeStdDev summarize the
+	// fractional changes of the reported adjustments
+	MeanDifficultyChange   float64 `json:"mean_difficulty_change"`
+	DifficultyChangeStdDev float64 `json:"difficulty_change_std_dev"`
+}
+
+// meanStdDev returns the mean and sample standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)-1))
+}
+
+// intervalDistribution measures the intervals between consecutive blocks,
+// which must be ordered by height
+func intervalDistribution(blocks []*bitcoin.Block) IntervalDistribution {
+	dist := IntervalDistribution{
+		Percentiles: make(map[string]float64, len(IntervalPercentiles)),
+		Histogram:   make([]IntervalBucket, len(intervalEdges)+1),
+	}
+	for i := range dist.Histogram {
+		if i > 0 {
+			dist.Histogram[i].Min = intervalEdges[i-1]
+		}
+		if i < len(intervalEdges) {
+			upper := intervalEdges[i]
+			dist.Histogram[i].Max = &upper
+		}
+	}
+	if len(blocks) < 2 {
+		return dist
+	}
+
+	intervals := make([]float64, 0, len(blocks)-1)
+	for i := 1; i < len(blocks); i++ {
+		interval := math.Max(blocks[i].Time.Sub(blocks[i-1].Time).Seconds(), 0)
+		intervals = append(intervals, interval)
+		dist.Histogram[sort.SearchFloat64s(intervalEdges, math.Nextafter(interval, math.Inf(1)))].Count++
+	}
+
+	dist.Intervals = len(intervals)
+	dist.Mean, dist.StdDev = meanStdDev(intervals)
+
+	sort.Float64s(intervals)
+	for _, p := range IntervalPercentiles {
+		dist.Percentiles[percentileKey(p)] = Percentile(intervals, p)
+	}
+
+	return dist
+}
+
+// realizedHashRate samples the hash rate over each run of sampleBlocks
+// blocks and returns its realized volatility. Blocks must be consecutive
+// and ordered by height.
+func realizedHashRate(blocks []*bitcoin.Block, sampleBlocks int64) (RealizedHashRate, error) {
+	realized := RealizedHashRate{SampleBlocks: sampleBlocks}
+
+	var samples []Sample
+	var rates []float64
+	for end := sampleBlocks; end < int64(len(blocks)); end += sampleBlocks {
+		rate, err := periodHashRate(blocks[end-sampleBlocks], blocks[end])
+		if err != nil {
+			continue
+		}
+		samples = append(samples, Sample{Time: blocks[end].Time, Value: rate})
+		rates = append(rates, rate)
+	}
+
+	realized.Samples = len(samples)
+	realized.Mean, _ = meanStdDev(rates)
+
+	vol, err := RealizedVolatility(samples)
+	if err != nil {
+		return realized, err
+	}
+	realized.Volatility = vol
+	return realized, nil
+}
+
+// difficultyChanges returns the adjustment at each epoch boundary after the
+// first. boundaries are the first blocks of consecutive epochs, in order.
+func difficultyChanges(boundaries []*bitcoin.Block) []DifficultyChange {
+	changes := make([]DifficultyChange, 0, len(boundaries))
+	for i := 1; i < len(boundaries); i++ {
+		prev, next := boundaries[i-1], boundaries[i]
+		duration := next.Time.Sub(prev.Time)
+
+		change := DifficultyChange{
+			Height:             next.Height,
+			Time:               next.Time,
+			Difficulty:         next.Difficulty,
+			PreviousDifficulty: prev.Difficulty,
+			EpochDuration:      duration.Hours(),
+			MeanInterval:       duration.Seconds() / float64(next.Height-prev.Height),
+		}
+		if prev.Difficulty > 0 {
+			change.Change = next.Difficulty/prev.Difficulty - 1
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// Statistics computes the realized hash rate volatility and block interval
+// distribution over the window blocks ending at the chain tip, sampling the
+// hash rate every sampleBlocks blocks, and the last epochs difficulty
+// adjustments
+func (c *HashRateCalculator) Statistics(ctx context.Context, window, sampleBlocks int64, epochs int) (*Statistics, error) {
+	if window < 1 || window > MaxStatisticsWindow {
+		return nil, fmt.Errorf("window must be from 1 to %d blocks", MaxStatisticsWindow)
+	}
+	if sampleBlocks < 1 || sampleBlocks*3 > window {
+		return nil, fmt.Errorf("window must hold at least three samples of %d blocks", sampleBlocks)
+	}
+	if epochs < 0 || epochs > MaxStatisticsEpochs {
+		return nil, fmt.Errorf("epochs must be from 0 to %d", MaxStatisticsEpochs)
+	}
+
+	bestBlockHash, err := c.client.GetBestBlockHash(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best block hash: %w", err)
+	}
+	tip, err := c.client.GetBlock(ctx, bestBlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best block: %w", err)
+	}
+
+	first := tip.Height - window
+	if first < 0 {
+		first = 0
+	}
+	blocks := make([]*bitcoin.Block, 0, tip.Height-first+1)
+	for height := first; height < tip.Height; height++ {
+		block, err := c.blockAt(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	blocks = append(blocks, tip)
+
+	realized, err := realizedHashRate(blocks, sampleBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	// The boundaries of the last epochs completed adjustments, oldest first
+	var boundaries []*bitcoin.Block
+	if epochs > 0 {
+		last := tip.Height / EpochBlocks * EpochBlocks
+		for height := last - int64(epochs)*EpochBlocks; height <= last; height += EpochBlocks {
+			if height < 0 {
+				continue
+			}
+			block, err := c.blockAt(ctx, height)
+			if err != nil {
+				return nil, err
+			}
+			boundaries = append(boundaries, block)
+		}
+	}
+
+	stats := &Statistics{
+		BlockHeight:       tip.Height,
+		Window:            tip.Height - first,
+		From:              blocks[0].Time,
+		To:                tip.Time,
+		HashRate:          realized,
+		Intervals:         intervalDistribution(blocks),
+		DifficultyChanges: difficultyChanges(boundaries),
+	}
+
+	changes := make([]float64, len(stats.DifficultyChanges))
+	for i, change := range stats.DifficultyChanges {
+		changes[i] = change.Change
+	}
+	stats.MeanDifficultyChange, stats.DifficultyChangeStdDev = meanStdDev(changes)
+
+	return stats, nil
+}
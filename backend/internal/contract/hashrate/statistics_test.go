@@ -0,0 +1,86 @@
+// internal/contract/hashrate/statistics_test.go
+package hashrate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+func TestIntervalDistribution(t *testing.T) {
+	blocks := chain(5, 10*time.Minute, 1e14)
+	blocks[2].Time = blocks[1].Time.Add(-time.Minute)     // Goes backwards
+	blocks[3].Time = blocks[2].Time.Add(90 * time.Minute) // Slow block
+	blocks[4].Time = blocks[3].Time.Add(60 * time.Second) // On a bucket edge
+
+	dist := intervalDistribution(blocks)
+	assert.Equal(t, 4, dist.Intervals)
+	assert.InDelta(t, (600+0+5400+60)/4.0, dist.Mean, 1e-9)
+	assert.InDelta(t, 330, dist.Percentiles["p50"], 1e-9)
+	assert.InDelta(t, 5400, dist.Percentiles["p99"], 5400-600)
+
+	counts := make([]int, len(dist.Histogram))
+	for i, bucket := range dist.Histogram {
+		counts[i] = bucket.Count
+	}
+	assert.Equal(t, []int{1, 1, 0, 0, 1, 0, 0, 1}, counts)
+	assert.Nil(t, dist.Histogram[len(dist.Histogram)-1].Max)
+
+	empty := intervalDistribution(blocks[:1])
+	assert.Zero(t, empty.Intervals)
+	assert.Len(t, empty.Histogram, len(intervalEdges)+1)
+}
+
+func TestRealizedHashRate(t *testing.T) {
+	steady := chain(4*BlocksPerDay+1, 10*time.Minute, 1e14)
+	realized, err := realizedHashRate(steady, BlocksPerDay)
+	require.NoError(t, err)
+	assert.Equal(t, 4, realized.Samples)
+	assert.InDelta(t, 0, realized.Volatility, 1e-9)
+	assert.InDelta(t, 1e14*math.Pow(2, 32)/(600*1e12), realized.Mean, 1e-6)
+
+	// Alternate fast and slow days
+	blocks := chain(4*BlocksPerDay+1, 10*time.Minute, 1e14)
+	at := blocks[0].Time
+	for i := 1; i < len(blocks); i++ {
+		interval := 9 * time.Minute
+		if (i-1)/BlocksPerDay%2 == 1 {
+			interval = 11 * time.Minute
+		}
+		at = at.Add(interval)
+		blocks[i].Time = at
+	}
+	realized, err = realizedHashRate(blocks, BlocksPerDay)
+	require.NoError(t, err)
+	assert.Greater(t, realized.Volatility, 0.0)
+
+	_, err = realizedHashRate(steady[:2*BlocksPerDay+1], BlocksPerDay)
+	assert.ErrorIs(t, err, ErrNotEnoughSamples)
+}
+
+func TestDifficultyChanges(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	boundaries := []*bitcoin.Block{
+		{Height: 0, Time: start, Difficulty: 100},
+		{Height: EpochBlocks, Time: start.Add(12 * 24 * time.Hour), Difficulty: 110},
+		{Height: 2 * EpochBlocks, Time: start.Add(26 * 24 * time.Hour), Difficulty: 99},
+	}
+
+	changes := difficultyChanges(boundaries)
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, int64(EpochBlocks), changes[0].Height)
+	assert.Equal(t, 100.0, changes[0].PreviousDifficulty)
+	assert.InDelta(t, 0.1, changes[0].Change, 1e-9)
+	assert.InDelta(t, 12*24, changes[0].EpochDuration, 1e-9)
+	assert.InDelta(t, 12*24*3600/float64(EpochBlocks), changes[0].MeanInterval, 1e-9)
+
+	assert.InDelta(t, -0.1, changes[1].Change, 1e-9)
+
+	assert.Empty(t, difficultyChanges(boundaries[:1]))
+}
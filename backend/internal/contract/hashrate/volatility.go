@@ -0,0 +1,157 @@
+// internal/contract/hashrate/volatility.go
+package hashrate
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Year is the period realized volatility is annualized over
+const Year = 365 * 24 * time.Hour
+
+// Sample is a hash rate or block speed value observed at a point in time
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Band holds percentiles of the samples in a trailing window ending at Time
+type Band struct {
+	Time        time.Time          `json:"time"`
+	Volatility  float64            `json:"volatility"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// VolatilityReport summarizes the dispersion of a sample series
+type VolatilityReport struct {
+	Samples     int                `json:"samples"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Volatility  float64            `json:"volatility"` // Annualized realized volatility of log changes
+	Percentiles map[string]float64 `json:"percentiles"`
+	Bands       []Band             `json:"bands"`
+}
+
+// ErrNotEnoughSamples is returned when a series is too short to measure
+var ErrNotEnoughSamples = errors.New("at least three samples are required")
+
+// RealizedVolatility returns the annualized standard deviation of the log
+// changes between consecutive samples. Samples must be in time order and
+// positive; non-positive values are skipped. The per-step deviation is scaled
+// by the average sampling interval, so irregular sampling is tolerated.
+func RealizedVolatility(samples []Sample) (float64, error) {
+	var (
+		returns []float64
+		elapsed time.Duration
+		prev    *Sample
+	)
+	for i := range samples {
+		s := &samples[i]
+		if s.Value <= 0 {
+			continue
+		}
+		if prev != nil && s.Time.After(prev.Time) {
+			returns = append(returns, math.Log(s.Value/prev.Value))
+			elapsed += s.Time.Sub(prev.Time)
+		}
+		prev = s
+	}
+
+	if len(returns) < 2 {
+		return 0, ErrNotEnoughSamples
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	step := elapsed / time.Duration(len(returns))
+	return math.Sqrt(variance * float64(Year) / float64(step)), nil
+}
+
+// Percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks. values must be sorted ascending.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return values[0]
+	}
+	if p >= 100 {
+		return values[len(values)-1]
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	frac := rank - float64(lower)
+	return values[lower] + frac*(values[upper]-values[lower])
+}
+
+// percentiles computes the named percentiles of the sample values
+func percentiles(samples []Sample, ps []float64) map[string]float64 {
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		values[i] = s.Value
+	}
+	sort.Float64s(values)
+
+	result := make(map[string]float64, len(ps))
+	for _, p := range ps {
+		result[percentileKey(p)] = Percentile(values, p)
+	}
+	return result
+}
+
+// percentileKey formats a percentile as a map key such as "p5" or "p97.5"
+func percentileKey(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// Analyze computes the realized volatility and percentiles of the samples,
+// plus a band for every sample from the trailing window of the given length.
+// Samples must be in time order.
+func Analyze(samples []Sample, window int, ps []float64) (*VolatilityReport, error) {
+	vol, err := RealizedVolatility(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &VolatilityReport{
+		Samples:     len(samples),
+		From:        samples[0].Time,
+		To:          samples[len(samples)-1].Time,
+		Volatility:  vol,
+		Percentiles: percentiles(samples, ps),
+	}
+
+	if window < 3 {
+		window = 3
+	}
+	for end := window; end <= len(samples); end++ {
+		trailing := samples[end-window : end]
+		bandVol, err := RealizedVolatility(trailing)
+		if err != nil {
+			continue
+		}
+		report.Bands = append(report.Bands, Band{
+			Time:        trailing[len(trailing)-1].Time,
+			Volatility:  bandVol,
+			Percentiles: percentiles(trailing, ps),
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,102 @@
+// internal/contract/hashrate/volatility_test.go
+package hashrate
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func samplesFrom(start time.Time, step time.Duration, values ...float64) []Sample {
+	samples := make([]Sample, len(values))
+	for i, v := range values {
+		samples[i] = Sample{Time: start.Add(time.Duration(i) * step), Value: v}
+	}
+	return samples
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50}
+
+	tests := []struct {
+		name string
+		p    float64
+		want float64
+	}{
+		{"minimum", 0, 10},
+		{"median", 50, 30},
+		{"interpolated", 10, 14},
+		{"upper quartile", 75, 40},
+		{"maximum", 100, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Percentile(values, tt.p), 1e-9)
+		})
+	}
+
+	assert.Equal(t, 0.0, Percentile(nil, 50))
+}
+
+func TestRealizedVolatility(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("constant series has no volatility", func(t *testing.T) {
+		vol, err := RealizedVolatility(samplesFrom(start, time.Hour, 500, 500, 500, 500))
+		assert.NoError(t, err)
+		assert.InDelta(t, 0, vol, 1e-12)
+	})
+
+	t.Run("alternating daily changes", func(t *testing.T) {
+		// Log changes of +ln(1.1), -ln(1.1), +ln(1.1) sampled daily
+		vol, err := RealizedVolatility(samplesFrom(start, 24*time.Hour, 100, 110, 100, 110))
+		assert.NoError(t, err)
+
+		r := math.Log(1.1)
+		mean := r / 3
+		variance := (2*(r-mean)*(r-mean) + (-r-mean)*(-r-mean)) / 2
+		assert.InDelta(t, math.Sqrt(variance*365), vol, 1e-9)
+	})
+
+	t.Run("sampling interval scales annualization", func(t *testing.T) {
+		daily, err := RealizedVolatility(samplesFrom(start, 24*time.Hour, 100, 110, 100, 110))
+		assert.NoError(t, err)
+		hourly, err := RealizedVolatility(samplesFrom(start, time.Hour, 100, 110, 100, 110))
+		assert.NoError(t, err)
+		assert.InDelta(t, daily*math.Sqrt(24), hourly, 1e-9)
+	})
+
+	t.Run("non-positive values are skipped", func(t *testing.T) {
+		vol, err := RealizedVolatility(samplesFrom(start, time.Hour, 500, 0, 500, -1, 500, 500))
+		assert.NoError(t, err)
+		assert.InDelta(t, 0, vol, 1e-12)
+	})
+
+	t.Run("too few samples", func(t *testing.T) {
+		_, err := RealizedVolatility(samplesFrom(start, time.Hour, 500, 510))
+		assert.ErrorIs(t, err, ErrNotEnoughSamples)
+	})
+}
+
+func TestAnalyze(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := samplesFrom(start, time.Hour, 100, 105, 95, 110, 100, 102)
+
+	report, err := Analyze(samples, 4, []float64{5, 50, 97.5})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 6, report.Samples)
+	assert.Equal(t, start, report.From)
+	assert.Equal(t, start.Add(5*time.Hour), report.To)
+	assert.Greater(t, report.Volatility, 0.0)
+	assert.InDelta(t, 101, report.Percentiles["p50"], 1e-9)
+	assert.Contains(t, report.Percentiles, "p97.5")
+
+	// One band per sample once the trailing window is full
+	assert.Len(t, report.Bands, 3)
+	assert.Equal(t, start.Add(3*time.Hour), report.Bands[0].Time)
+	assert.InDelta(t, 102.5, report.Bands[0].Percentiles["p50"], 1e-9)
+}
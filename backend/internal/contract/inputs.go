@@ -0,0 +1,258 @@
+// internal/contract/inputs.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+var (
+	// ErrInputsNotEnabled is returned when no input store is configured
+	ErrInputsNotEnabled = errors.New("multi-input funding is not enabled")
+	// ErrInputNotFound is returned for an input that is not an unspent output
+	// or VTXO of the contract
+	ErrInputNotFound = errors.New("contract input not found")
+	// ErrInputMismatch is returned for an input whose value or script
+	// differs from the output it claims to be
+	ErrInputMismatch = errors.New("contract input does not match its output")
+)
+
+// sweepInput is a contract output being spent and the script path used to spend it
+type sweepInput struct {
+	OutPoint wire.OutPoint
+	Value    int64
+	Leaf     int
+	Spend    taproot.Spend
+}
+
+// WithInputStore enables contracts funded by several UTXOs or VTXOs. UTXOs
+// are looked up in utxos before they are recorded.
+func (s *Service) WithInputStore(store InputStore, utxos UTXOSource) *Service {
+	s.inputRepo = store
+	s.utxoSource = utxos
+	return s
+}
+
+// ListContractInputs returns the inputs recorded for a contract at a stage
+func (s *Service) ListContractInputs(
+	ctx context.Context,
+	contractID uuid.UUID,
+	stage models.InputStage,
+) ([]*models.ContractInput, error) {
+	if s.inputRepo == nil {
+		return nil, ErrInputsNotEnabled
+	}
+
+	if _, err := s.contractRepo.GetByID(ctx, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	return s.inputRepo.ListInputs(ctx, contractID, stage)
+}
+
+// AddContractInput records a UTXO or VTXO locked in a contract's setup
+// script. Once any input is recorded the final transaction sweeps the
+// recorded inputs instead of the setup transaction's first output, so every
+// separately funded fill must be added before the final transaction is built.
+// A UTXO must be unspent in the chain or mempool, and a VTXO must be the one
+// the ASP issued for the contract, with the claimed value.
+func (s *Service) AddContractInput(ctx context.Context, input *models.ContractInput) error {
+	if s.inputRepo == nil {
+		return ErrInputsNotEnabled
+	}
+
+	if input.Stage == "" {
+		input.Stage = models.InputStageSetup
+	}
+	if err := input.Validate(); err != nil {
+		return fmt.Errorf("invalid contract input: %w", err)
+	}
+	if input.Stage != models.InputStageSetup {
+		return errors.New("only setup inputs can be added; final inputs are recorded with the final transaction")
+	}
+	if _, err := chainhash.NewHashFromStr(input.TxID); err != nil {
+		return fmt.Errorf("invalid transaction ID: %w", err)
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, input.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if contract.Status != models.ContractStatusCreated && contract.Status != models.ContractStatusActive {
+		return errors.New("contract is not in setup")
+	}
+	if contract.FinalTxID != nil {
+		return errors.New("final transaction has already been built")
+	}
+
+	leaves, err := s.setupLeaves(contract)
+	if err != nil {
+		return fmt.Errorf("failed to build setup leaves: %w", err)
+	}
+	if input.LeafIndex != nil && *input.LeafIndex >= len(leaves) {
+		return fmt.Errorf("leaf index %d out of range, setup script has %d leaves", *input.LeafIndex, len(leaves))
+	}
+
+	if err := s.checkInputFunds(ctx, contract, leaves, input); err != nil {
+		return err
+	}
+
+	if err := s.inputRepo.AddInput(ctx, input); err != nil {
+		return err
+	}
+
+	logger.Info().
+		Str("contract_id", input.ContractID.String()).
+		Str("txid", input.TxID).
+		Uint32("vout", input.Vout).
+		Int64("value", input.Value).
+		Str("source", string(input.Source)).
+		Msg("Contract input added")
+
+	return nil
+}
+
+// checkInputFunds checks that an input exists with the claimed value and
+// locks funds in the contract's setup script
+func (s *Service) checkInputFunds(ctx context.Context, contract *models.Contract, leaves [][]byte, input *models.ContractInput) error {
+	if input.Source == models.InputSourceVTXO {
+		if s.vtxoRepo == nil {
+			return fmt.Errorf("%w: VTXO inputs cannot be checked", ErrVTXOsNotEnabled)
+		}
+		vtxo, err := s.vtxoRepo.GetActiveByContract(ctx, contract.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get contract VTXO: %w", err)
+		}
+		if vtxo.VTXOID != models.VTXOOutpoint(input.TxID, int(input.Vout)) {
+			return fmt.Errorf("%w: %s:%d is not the contract's VTXO", ErrInputNotFound, input.TxID, input.Vout)
+		}
+		if vtxo.Amount != input.Value {
+			return fmt.Errorf("%w: VTXO holds %d sats, not %d", ErrInputMismatch, vtxo.Amount, input.Value)
+		}
+		return nil
+	}
+
+	if s.utxoSource == nil {
+		return errors.New("UTXO inputs cannot be checked without a chain source")
+	}
+	hash, err := chainhash.NewHashFromStr(input.TxID)
+	if err != nil {
+		return fmt.Errorf("invalid transaction ID: %w", err)
+	}
+	out, err := s.utxoSource.GetTxOut(ctx, hash, input.Vout)
+	if err != nil {
+		return fmt.Errorf("failed to look up input: %w", err)
+	}
+	if out == nil {
+		return fmt.Errorf("%w: %s:%d is not an unspent output", ErrInputNotFound, input.TxID, input.Vout)
+	}
+	if out.Value != input.Value {
+		return fmt.Errorf("%w: output holds %d sats, not %d", ErrInputMismatch, out.Value, input.Value)
+	}
+
+	setupScript, err := leavesScript(contract.BuyerPubKey, leaves)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(out.PkScript, setupScript) {
+		return fmt.Errorf("%w: output does not pay the contract's setup script", ErrInputMismatch)
+	}
+
+	return nil
+}
+
+// sweepInputs returns the inputs to spend for a contract stage. Inputs
+// recorded in the input store are used when there are any; otherwise the
+// first output of fallback is the single input. Each input is spent through
+// its recorded leaf, or defaultLeaf when it has none.
+func (s *Service) sweepInputs(
+	ctx context.Context,
+	contractID uuid.UUID,
+	stage models.InputStage,
+	fallback *wire.MsgTx,
+	leafSpends []taproot.Spend,
+	defaultLeaf int,
+) ([]sweepInput, error) {
+	var stored []*models.ContractInput
+	if s.inputRepo != nil {
+		var err error
+		stored, err = s.inputRepo.ListInputs(ctx, contractID, stage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s inputs: %w", stage, err)
+		}
+	}
+
+	if len(stored) == 0 {
+		if len(fallback.TxOut) == 0 {
+			return nil, errors.New("transaction has no outputs to spend")
+		}
+		return []sweepInput{{
+			OutPoint: wire.OutPoint{Hash: fallback.TxHash(), Index: 0}, // Assuming contract output is first
+			Value:    fallback.TxOut[0].Value,
+			Leaf:     defaultLeaf,
+			Spend:    leafSpends[defaultLeaf],
+		}}, nil
+	}
+
+	inputs := make([]sweepInput, 0, len(stored))
+	for _, in := range stored {
+		hash, err := chainhash.NewHashFromStr(in.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input transaction ID %s: %w", in.TxID, err)
+		}
+
+		leaf := defaultLeaf
+		if in.LeafIndex != nil {
+			if *in.LeafIndex >= len(leafSpends) {
+				return nil, fmt.Errorf("input %s:%d has leaf index %d out of range", in.TxID, in.Vout, *in.LeafIndex)
+			}
+			leaf = *in.LeafIndex
+		}
+
+		inputs = append(inputs, sweepInput{
+			OutPoint: wire.OutPoint{Hash: *hash, Index: in.Vout},
+			Value:    in.Value,
+			Leaf:     leaf,
+			Spend:    leafSpends[leaf],
+		})
+	}
+	return inputs, nil
+}
+
+// buildSweepTx spends every input to a single output paying the total input
+// value less the fee for the transaction's estimated weight
+func buildSweepTx(inputs []sweepInput, pkScript []byte, feeRate float64) (*wire.MsgTx, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no inputs to sweep")
+	}
+
+	tx := wire.NewMsgTx(2) // Version 2 transaction
+
+	var total int64
+	spends := make([]taproot.Spend, 0, len(inputs))
+	for _, in := range inputs {
+		outPoint := in.OutPoint
+		tx.AddTxIn(wire.NewTxIn(&outPoint, nil, nil))
+		total += in.Value
+		spends = append(spends, in.Spend)
+	}
+
+	fee := taproot.Fee(taproot.TxWeight(spends, []int{len(pkScript)}), feeRate)
+	outputValue := total - fee
+	if outputValue <= 0 {
+		return nil, fmt.Errorf("fees exceed input value")
+	}
+
+	tx.AddTxOut(wire.NewTxOut(outputValue, pkScript))
+	return tx, nil
+}
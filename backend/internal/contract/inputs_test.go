@@ -0,0 +1,170 @@
+// internal/contract/inputs_test.go
+package contract
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+// stubInputStore records the inputs added to it
+type stubInputStore struct {
+	InputStore
+	added []*models.ContractInput
+}
+
+func (s *stubInputStore) AddInput(ctx context.Context, input *models.ContractInput) error {
+	s.added = append(s.added, input)
+	return nil
+}
+
+// stubUTXOs holds the unspent outputs of the chain
+type stubUTXOs map[wire.OutPoint]*wire.TxOut
+
+func (s stubUTXOs) GetTxOut(ctx context.Context, txHash *chainhash.Hash, vout uint32) (*wire.TxOut, error) {
+	return s[wire.OutPoint{Hash: *txHash, Index: vout}], nil
+}
+
+// stubVTXOStore holds the active VTXO of each contract
+type stubVTXOStore struct {
+	VTXOStore
+	active map[uuid.UUID]*models.VTXO
+}
+
+func (s stubVTXOStore) GetActiveByContract(ctx context.Context, contractID uuid.UUID) (*models.VTXO, error) {
+	vtxo, ok := s.active[contractID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return vtxo, nil
+}
+
+func TestAddContractInput(t *testing.T) {
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	contract := &models.Contract{
+		ID:              uuid.New(),
+		ContractType:    models.ContractTypeCall,
+		Status:          models.ContractStatusActive,
+		BuyerPubKey:     hex.EncodeToString(buyer.PubKey().SerializeCompressed()),
+		SellerPubKey:    hex.EncodeToString(seller.PubKey().SerializeCompressed()),
+		EndBlockHeight:  900_000,
+		TargetTimestamp: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	s := &Service{
+		contractRepo:         &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: contract}},
+		taprootScriptBuilder: taproot.NewScriptBuilder(),
+	}
+
+	setupLeaves, err := s.setupLeaves(contract)
+	require.NoError(t, err)
+	setupScript, err := leavesScript(contract.BuyerPubKey, setupLeaves)
+	require.NoError(t, err)
+
+	funded := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	elsewhere := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 0}
+	utxos := stubUTXOs{
+		funded:    wire.NewTxOut(50_000, setupScript),
+		elsewhere: wire.NewTxOut(50_000, append([]byte{0x51, 0x20}, make([]byte, 32)...)),
+	}
+	vtxoTxID := "3333333333333333333333333333333333333333333333333333333333333333"
+	vtxos := stubVTXOStore{active: map[uuid.UUID]*models.VTXO{
+		contract.ID: {ContractID: contract.ID, VTXOID: models.VTXOOutpoint(vtxoTxID, 1), Amount: 70_000},
+	}}
+	inputs := &stubInputStore{}
+	s.WithInputStore(inputs, utxos).WithVTXOStore(vtxos)
+	ctx := context.Background()
+
+	utxoInput := func(outPoint wire.OutPoint, value int64) *models.ContractInput {
+		return &models.ContractInput{
+			ContractID: contract.ID,
+			Source:     models.InputSourceUTXO,
+			TxID:       outPoint.Hash.String(),
+			Vout:       outPoint.Index,
+			Value:      value,
+		}
+	}
+	vtxoInput := func(vout uint32, value int64) *models.ContractInput {
+		return &models.ContractInput{
+			ContractID: contract.ID,
+			Source:     models.InputSourceVTXO,
+			TxID:       vtxoTxID,
+			Vout:       vout,
+			Value:      value,
+		}
+	}
+
+	// Inputs must exist, hold the claimed value and pay the setup script
+	missing := wire.OutPoint{Hash: chainhash.Hash{9}, Index: 0}
+	assert.ErrorIs(t, s.AddContractInput(ctx, utxoInput(missing, 50_000)), ErrInputNotFound)
+	assert.ErrorIs(t, s.AddContractInput(ctx, utxoInput(funded, 60_000)), ErrInputMismatch)
+	assert.ErrorIs(t, s.AddContractInput(ctx, utxoInput(elsewhere, 50_000)), ErrInputMismatch)
+	assert.ErrorIs(t, s.AddContractInput(ctx, vtxoInput(0, 70_000)), ErrInputNotFound)
+	assert.ErrorIs(t, s.AddContractInput(ctx, vtxoInput(1, 80_000)), ErrInputMismatch)
+	assert.Empty(t, inputs.added)
+
+	require.NoError(t, s.AddContractInput(ctx, utxoInput(funded, 50_000)))
+	require.NoError(t, s.AddContractInput(ctx, vtxoInput(1, 70_000)))
+	require.Len(t, inputs.added, 2)
+	assert.Equal(t, models.InputStageSetup, inputs.added[0].Stage)
+}
+
+func TestBuildSweepTx(t *testing.T) {
+	pkScript := make([]byte, taproot.P2TROutputScriptSize)
+	outPoint := func(b byte, index uint32) wire.OutPoint {
+		return wire.OutPoint{Hash: chainhash.Hash{b}, Index: index}
+	}
+
+	t.Run("sweeps every input into one output", func(t *testing.T) {
+		inputs := []sweepInput{
+			{OutPoint: outPoint(1, 0), Value: 60_000, Spend: taproot.KeyPathSpend()},
+			{OutPoint: outPoint(2, 3), Value: 40_000, Spend: taproot.KeyPathSpend()},
+		}
+
+		tx, err := buildSweepTx(inputs, pkScript, 1)
+		assert.NoError(t, err)
+		assert.Len(t, tx.TxIn, 2)
+		assert.Equal(t, inputs[1].OutPoint, tx.TxIn[1].PreviousOutPoint)
+		assert.Len(t, tx.TxOut, 1)
+
+		// Two key path inputs and one P2TR output are 169 vB
+		assert.Equal(t, int64(100_000-169), tx.TxOut[0].Value)
+	})
+
+	t.Run("fee grows with each input's spend path", func(t *testing.T) {
+		keyPath := []sweepInput{{OutPoint: outPoint(1, 0), Value: 50_000, Spend: taproot.KeyPathSpend()}}
+		scriptPath := []sweepInput{{OutPoint: outPoint(1, 0), Value: 50_000, Spend: taproot.ScriptPathSpend(make([]byte, 34), 2, 1)}}
+
+		keyTx, err := buildSweepTx(keyPath, pkScript, 2)
+		assert.NoError(t, err)
+		scriptTx, err := buildSweepTx(scriptPath, pkScript, 2)
+		assert.NoError(t, err)
+		assert.Greater(t, keyTx.TxOut[0].Value, scriptTx.TxOut[0].Value)
+	})
+
+	t.Run("fees exceed input value", func(t *testing.T) {
+		inputs := []sweepInput{{OutPoint: outPoint(1, 0), Value: 100, Spend: taproot.KeyPathSpend()}}
+		_, err := buildSweepTx(inputs, pkScript, 5)
+		assert.Error(t, err)
+	})
+
+	t.Run("no inputs", func(t *testing.T) {
+		_, err := buildSweepTx(nil, pkScript, 5)
+		assert.Error(t, err)
+	})
+}
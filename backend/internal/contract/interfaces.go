@@ -0,0 +1,220 @@
+// internal/contract/interfaces.go
+package contract
+
+import (
+	"context"
+	"time"
+
+	arkv1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+// Mocks of the interfaces below are generated into ./mocks; run
+// `make generate` (or `go generate ./...`) after changing them.
+//
+//go:generate mockgen -source=interfaces.go -destination=mocks/interfaces.go -package=mocks
+
+// ChainBackend is the subset of the Bitcoin node client used by the contract service
+type ChainBackend interface {
+	GetBestBlockHash(ctx context.Context) (string, error)
+	GetBlockHash(ctx context.Context, height int64) (string, error)
+	GetBlock(ctx context.Context, hash string) (*bitcoin.Block, error)
+	EstimateFee(ctx context.Context, numInputs, numOutputs int, feeRate float64) (int64, error)
+	BroadcastTransactionWithRetry(ctx context.Context, txHex string) (string, error)
+}
+
+// ArkService is the subset of the Ark Service Provider client used by the contract service
+type ArkService interface {
+	CheckASPStatus(ctx context.Context) (bool, error)
+	RegisterOutputsForNextRound(ctx context.Context, outputs []*arkv1.Output) (*arkv1.RegisterOutputsForNextRoundResponse, error)
+	CreateOutOfRoundTransaction(ctx context.Context, senderPSBT string, outputs []*arkv1.Output) (*arkv1.CreateOutOfRoundTransactionResponse, error)
+	GetExitPath(ctx context.Context, vtxoID string, destinationAddress string, feeRate int64) (*arkv1.GetExitPathResponse, error)
+}
+
+// FundingStore persists the funding progress of contracts during setup
+type FundingStore interface {
+	GetByContractID(ctx context.Context, contractID uuid.UUID) (*models.ContractFunding, error)
+	RecordSubmission(ctx context.Context, contractID uuid.UUID, isBuyer bool, funded, signed bool, psbt string) (*models.ContractFunding, error)
+}
+
+// InputStore persists the UTXOs and VTXOs funding each stage of a contract
+type InputStore interface {
+	AddInput(ctx context.Context, input *models.ContractInput) error
+	ListInputs(ctx context.Context, contractID uuid.UUID, stage models.InputStage) ([]*models.ContractInput, error)
+}
+
+// UTXOSource looks up unspent outputs in the chain and mempool, returning
+// nil for an output that does not exist or is spent
+type UTXOSource interface {
+	GetTxOut(ctx context.Context, txHash *chainhash.Hash, vout uint32) (*wire.TxOut, error)
+}
+
+// VTXOStore persists the Ark VTXOs holding contract funds
+type VTXOStore interface {
+	Create(ctx context.Context, vtxo *models.VTXO) error
+	GetActiveByContract(ctx context.Context, contractID uuid.UUID) (*models.VTXO, error)
+	ListActiveByASP(ctx context.Context, asp string) ([]*models.VTXO, error)
+	Replace(ctx context.Context, spentID string, next *models.VTXO) error
+}
+
+// OracleEventStore persists the oracle events contracts settle on
+type OracleEventStore interface {
+	Create(ctx context.Context, event *models.OracleEvent) error
+	GetByContract(ctx context.Context, contractID uuid.UUID) (*models.OracleEvent, error)
+	Attest(ctx context.Context, event *models.OracleEvent) error
+}
+
+// FeeEstimator estimates the fee rate in sat/vB needed to confirm within a
+// number of blocks
+type FeeEstimator interface {
+	EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error)
+}
+
+// DeferralStore persists settlements deferred while chain fees are high
+type DeferralStore interface {
+	Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementDeferral, error)
+	Defer(ctx context.Context, contractID uuid.UUID, feeRate float64) (*models.SettlementDeferral, error)
+	SetPriorityFeeRate(ctx context.Context, contractID uuid.UUID, feeRate float64) (*models.SettlementDeferral, error)
+	Release(ctx context.Context, contractID uuid.UUID) error
+	ListPending(ctx context.Context, limit int) ([]*models.SettlementDeferral, error)
+	CountPending(ctx context.Context) (int, error)
+}
+
+// PayoutStore persists the payout addresses of users and contract parties
+type PayoutStore interface {
+	GetUserAddress(ctx context.Context, userID uuid.UUID) (*models.PayoutAddress, error)
+	GetUserAddressByKeyID(ctx context.Context, keyID uuid.UUID) (string, error)
+	SetUserAddress(ctx context.Context, address *models.PayoutAddress) error
+	DeleteUserAddress(ctx context.Context, userID uuid.UUID) error
+	GetContractAddress(ctx context.Context, contractID uuid.UUID, pubKey string) (string, error)
+	SetContractAddress(ctx context.Context, address *models.ContractPayoutAddress) error
+}
+
+// OpenInterestStore persists the open interest of each market and its history
+type OpenInterestStore interface {
+	Adjust(ctx context.Context, contract *models.Contract, delta int64) (*models.OpenInterest, error)
+	List(ctx context.Context) ([]*models.OpenInterest, error)
+	ListHistory(
+		ctx context.Context,
+		contractType models.ContractType,
+		strikeHashRate float64,
+		startBlockHeight, endBlockHeight int64,
+		since time.Time,
+		limit int,
+	) ([]*models.OpenInterestPoint, error)
+}
+
+// ScheduledCloseStore persists cooperative closes scheduled by contract parties
+type ScheduledCloseStore interface {
+	Get(ctx context.Context, contractID uuid.UUID) (*models.ScheduledClose, error)
+	Propose(ctx context.Context, sc *models.ScheduledClose) error
+	AddSignature(ctx context.Context, terms *models.ScheduledClose, isBuyer bool, signature string) (*models.ScheduledClose, error)
+	Resolve(
+		ctx context.Context,
+		contractID uuid.UUID,
+		status models.ScheduledCloseStatus,
+		closeTxID *string,
+		lastError *string,
+	) error
+	ListDue(ctx context.Context, height int64, now time.Time, limit int) ([]*models.ScheduledClose, error)
+}
+
+// EvidenceStore persists the evidence of settlement decisions
+type EvidenceStore interface {
+	Create(ctx context.Context, evidence *models.SettlementEvidence) error
+	Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementEvidence, error)
+}
+
+// ChannelPublisher pushes messages to subscribers of a websocket channel
+type ChannelPublisher interface {
+	PublishToChannel(channel string, message interface{})
+}
+
+// SettlementObserver is notified after a contract has been settled
+type SettlementObserver interface {
+	OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool)
+}
+
+// SettlementObservers notifies each of several observers in turn
+type SettlementObservers []SettlementObserver
+
+// OnContractSettled implements SettlementObserver
+func (o SettlementObservers) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	for _, observer := range o {
+		observer.OnContractSettled(ctx, contract, buyerWins)
+	}
+}
+
+// EventPublisher publishes events on the event bus
+type EventPublisher interface {
+	Publish(topic events.Topic, payload interface{})
+}
+
+// ConfirmationStore persists whether contract transactions have confirmed
+type ConfirmationStore interface {
+	ListUnconfirmedTransactions(ctx context.Context) ([]*models.ContractTransaction, error)
+	ConfirmTransaction(ctx context.Context, txID string) error
+}
+
+// ConfirmationSource reports how deep a transaction is buried in the chain
+type ConfirmationSource interface {
+	GetTransactionConfirmations(ctx context.Context, txHash *chainhash.Hash) (int64, error)
+}
+
+// SettlementBroadcastStore persists the broadcasts of payout transactions
+// until they confirm
+type SettlementBroadcastStore interface {
+	Create(ctx context.Context, broadcast *models.SettlementBroadcast) error
+	Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementBroadcast, error)
+	RecordBroadcast(ctx context.Context, contractID uuid.UUID, broadcastErr error) error
+	RecordConfirmations(ctx context.Context, contractID uuid.UUID, confirmations int64) error
+	Confirm(ctx context.Context, contractID uuid.UUID, confirmations int64) (*models.SettlementBroadcast, error)
+	ListPending(ctx context.Context, limit int) ([]*models.SettlementBroadcast, error)
+	ListStuck(ctx context.Context, before time.Time, limit, offset int) ([]*models.SettlementBroadcast, error)
+}
+
+// CollateralPolicy decides how much of a contract's size must be funded up
+// front, which is less than the full size when its writer posted margin
+type CollateralPolicy interface {
+	RequiredCollateral(ctx context.Context, contract *models.Contract) (int64, error)
+}
+
+// DeferralObserver is notified when a contract's settlement is deferred
+type DeferralObserver interface {
+	OnSettlementDeferred(ctx context.Context, contract *models.Contract, deferral *models.SettlementDeferral)
+}
+
+// TransitionStore persists the status history of contracts
+type TransitionStore interface {
+	Create(ctx context.Context, transition *models.ContractTransition) error
+	ListByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransition, error)
+}
+
+// UserKeyStore looks up the public keys registered by users
+type UserKeyStore interface {
+	GetKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserKey, error)
+}
+
+// ContractStore is the persistence layer used by the contract service
+type ContractStore interface {
+	Create(ctx context.Context, contract *models.Contract) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error)
+	Update(ctx context.Context, contract *models.Contract) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.ContractStatus) error
+	ListByStatus(ctx context.Context, status models.ContractStatus, limit, offset int) ([]*models.Contract, error)
+	ListByStatusPage(ctx context.Context, status models.ContractStatus, after models.Cursor, limit int) ([]*models.Contract, error)
+	CountWithStatus(ctx context.Context, status models.ContractStatus) (int64, error)
+	AddTransaction(ctx context.Context, tx *models.ContractTransaction) error
+	GetTransactionsByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransaction, error)
+	ListTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string, after models.Cursor, limit int) ([]*models.ContractTransaction, error)
+	CountTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string) (int64, error)
+	GetTransactionByID(ctx context.Context, txID uuid.UUID) (*models.ContractTransaction, error)
+	ExecuteInTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error
+}
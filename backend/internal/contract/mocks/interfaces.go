@@ -0,0 +1,1693 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=mocks/interfaces.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	events "hashhedge/internal/events"
+	models "hashhedge/internal/models"
+	bitcoin "hashhedge/pkg/bitcoin"
+	reflect "reflect"
+	time "time"
+
+	v1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+	chainhash "github.com/btcsuite/btcd/chaincfg/chainhash"
+	wire "github.com/btcsuite/btcd/wire"
+	uuid "github.com/google/uuid"
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChainBackend is a mock of ChainBackend interface.
+type MockChainBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockChainBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockChainBackendMockRecorder is the mock recorder for MockChainBackend.
+type MockChainBackendMockRecorder struct {
+	mock *MockChainBackend
+}
+
+// NewMockChainBackend creates a new mock instance.
+func NewMockChainBackend(ctrl *gomock.Controller) *MockChainBackend {
+	mock := &MockChainBackend{ctrl: ctrl}
+	mock.recorder = &MockChainBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChainBackend) EXPECT() *MockChainBackendMockRecorder {
+	return m.recorder
+}
+
+// BroadcastTransactionWithRetry mocks base method.
+func (m *MockChainBackend) BroadcastTransactionWithRetry(ctx context.Context, txHex string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BroadcastTransactionWithRetry", ctx, txHex)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BroadcastTransactionWithRetry indicates an expected call of BroadcastTransactionWithRetry.
+func (mr *MockChainBackendMockRecorder) BroadcastTransactionWithRetry(ctx, txHex any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BroadcastTransactionWithRetry", reflect.TypeOf((*MockChainBackend)(nil).BroadcastTransactionWithRetry), ctx, txHex)
+}
+
+// EstimateFee mocks base method.
+func (m *MockChainBackend) EstimateFee(ctx context.Context, numInputs, numOutputs int, feeRate float64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateFee", ctx, numInputs, numOutputs, feeRate)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFee indicates an expected call of EstimateFee.
+func (mr *MockChainBackendMockRecorder) EstimateFee(ctx, numInputs, numOutputs, feeRate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFee", reflect.TypeOf((*MockChainBackend)(nil).EstimateFee), ctx, numInputs, numOutputs, feeRate)
+}
+
+// GetBestBlockHash mocks base method.
+func (m *MockChainBackend) GetBestBlockHash(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBestBlockHash", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBestBlockHash indicates an expected call of GetBestBlockHash.
+func (mr *MockChainBackendMockRecorder) GetBestBlockHash(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBestBlockHash", reflect.TypeOf((*MockChainBackend)(nil).GetBestBlockHash), ctx)
+}
+
+// GetBlock mocks base method.
+func (m *MockChainBackend) GetBlock(ctx context.Context, hash string) (*bitcoin.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlock", ctx, hash)
+	ret0, _ := ret[0].(*bitcoin.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlock indicates an expected call of GetBlock.
+func (mr *MockChainBackendMockRecorder) GetBlock(ctx, hash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlock", reflect.TypeOf((*MockChainBackend)(nil).GetBlock), ctx, hash)
+}
+
+// GetBlockHash mocks base method.
+func (m *MockChainBackend) GetBlockHash(ctx context.Context, height int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHash", ctx, height)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHash indicates an expected call of GetBlockHash.
+func (mr *MockChainBackendMockRecorder) GetBlockHash(ctx, height any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHash", reflect.TypeOf((*MockChainBackend)(nil).GetBlockHash), ctx, height)
+}
+
+// MockArkService is a mock of ArkService interface.
+type MockArkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockArkServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockArkServiceMockRecorder is the mock recorder for MockArkService.
+type MockArkServiceMockRecorder struct {
+	mock *MockArkService
+}
+
+// NewMockArkService creates a new mock instance.
+func NewMockArkService(ctrl *gomock.Controller) *MockArkService {
+	mock := &MockArkService{ctrl: ctrl}
+	mock.recorder = &MockArkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockArkService) EXPECT() *MockArkServiceMockRecorder {
+	return m.recorder
+}
+
+// CheckASPStatus mocks base method.
+func (m *MockArkService) CheckASPStatus(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckASPStatus", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckASPStatus indicates an expected call of CheckASPStatus.
+func (mr *MockArkServiceMockRecorder) CheckASPStatus(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckASPStatus", reflect.TypeOf((*MockArkService)(nil).CheckASPStatus), ctx)
+}
+
+// CreateOutOfRoundTransaction mocks base method.
+func (m *MockArkService) CreateOutOfRoundTransaction(ctx context.Context, senderPSBT string, outputs []*v1.Output) (*v1.CreateOutOfRoundTransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOutOfRoundTransaction", ctx, senderPSBT, outputs)
+	ret0, _ := ret[0].(*v1.CreateOutOfRoundTransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOutOfRoundTransaction indicates an expected call of CreateOutOfRoundTransaction.
+func (mr *MockArkServiceMockRecorder) CreateOutOfRoundTransaction(ctx, senderPSBT, outputs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOutOfRoundTransaction", reflect.TypeOf((*MockArkService)(nil).CreateOutOfRoundTransaction), ctx, senderPSBT, outputs)
+}
+
+// GetExitPath mocks base method.
+func (m *MockArkService) GetExitPath(ctx context.Context, vtxoID, destinationAddress string, feeRate int64) (*v1.GetExitPathResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExitPath", ctx, vtxoID, destinationAddress, feeRate)
+	ret0, _ := ret[0].(*v1.GetExitPathResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExitPath indicates an expected call of GetExitPath.
+func (mr *MockArkServiceMockRecorder) GetExitPath(ctx, vtxoID, destinationAddress, feeRate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExitPath", reflect.TypeOf((*MockArkService)(nil).GetExitPath), ctx, vtxoID, destinationAddress, feeRate)
+}
+
+// RegisterOutputsForNextRound mocks base method.
+func (m *MockArkService) RegisterOutputsForNextRound(ctx context.Context, outputs []*v1.Output) (*v1.RegisterOutputsForNextRoundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOutputsForNextRound", ctx, outputs)
+	ret0, _ := ret[0].(*v1.RegisterOutputsForNextRoundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterOutputsForNextRound indicates an expected call of RegisterOutputsForNextRound.
+func (mr *MockArkServiceMockRecorder) RegisterOutputsForNextRound(ctx, outputs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOutputsForNextRound", reflect.TypeOf((*MockArkService)(nil).RegisterOutputsForNextRound), ctx, outputs)
+}
+
+// MockFundingStore is a mock of FundingStore interface.
+type MockFundingStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockFundingStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockFundingStoreMockRecorder is the mock recorder for MockFundingStore.
+type MockFundingStoreMockRecorder struct {
+	mock *MockFundingStore
+}
+
+// NewMockFundingStore creates a new mock instance.
+func NewMockFundingStore(ctrl *gomock.Controller) *MockFundingStore {
+	mock := &MockFundingStore{ctrl: ctrl}
+	mock.recorder = &MockFundingStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFundingStore) EXPECT() *MockFundingStoreMockRecorder {
+	return m.recorder
+}
+
+// GetByContractID mocks base method.
+func (m *MockFundingStore) GetByContractID(ctx context.Context, contractID uuid.UUID) (*models.ContractFunding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByContractID", ctx, contractID)
+	ret0, _ := ret[0].(*models.ContractFunding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByContractID indicates an expected call of GetByContractID.
+func (mr *MockFundingStoreMockRecorder) GetByContractID(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByContractID", reflect.TypeOf((*MockFundingStore)(nil).GetByContractID), ctx, contractID)
+}
+
+// RecordSubmission mocks base method.
+func (m *MockFundingStore) RecordSubmission(ctx context.Context, contractID uuid.UUID, isBuyer, funded, signed bool, psbt string) (*models.ContractFunding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSubmission", ctx, contractID, isBuyer, funded, signed, psbt)
+	ret0, _ := ret[0].(*models.ContractFunding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordSubmission indicates an expected call of RecordSubmission.
+func (mr *MockFundingStoreMockRecorder) RecordSubmission(ctx, contractID, isBuyer, funded, signed, psbt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSubmission", reflect.TypeOf((*MockFundingStore)(nil).RecordSubmission), ctx, contractID, isBuyer, funded, signed, psbt)
+}
+
+// MockInputStore is a mock of InputStore interface.
+type MockInputStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockInputStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockInputStoreMockRecorder is the mock recorder for MockInputStore.
+type MockInputStoreMockRecorder struct {
+	mock *MockInputStore
+}
+
+// NewMockInputStore creates a new mock instance.
+func NewMockInputStore(ctrl *gomock.Controller) *MockInputStore {
+	mock := &MockInputStore{ctrl: ctrl}
+	mock.recorder = &MockInputStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInputStore) EXPECT() *MockInputStoreMockRecorder {
+	return m.recorder
+}
+
+// AddInput mocks base method.
+func (m *MockInputStore) AddInput(ctx context.Context, input *models.ContractInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddInput", ctx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddInput indicates an expected call of AddInput.
+func (mr *MockInputStoreMockRecorder) AddInput(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddInput", reflect.TypeOf((*MockInputStore)(nil).AddInput), ctx, input)
+}
+
+// ListInputs mocks base method.
+func (m *MockInputStore) ListInputs(ctx context.Context, contractID uuid.UUID, stage models.InputStage) ([]*models.ContractInput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInputs", ctx, contractID, stage)
+	ret0, _ := ret[0].([]*models.ContractInput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInputs indicates an expected call of ListInputs.
+func (mr *MockInputStoreMockRecorder) ListInputs(ctx, contractID, stage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInputs", reflect.TypeOf((*MockInputStore)(nil).ListInputs), ctx, contractID, stage)
+}
+
+// MockUTXOSource is a mock of UTXOSource interface.
+type MockUTXOSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockUTXOSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockUTXOSourceMockRecorder is the mock recorder for MockUTXOSource.
+type MockUTXOSourceMockRecorder struct {
+	mock *MockUTXOSource
+}
+
+// NewMockUTXOSource creates a new mock instance.
+func NewMockUTXOSource(ctrl *gomock.Controller) *MockUTXOSource {
+	mock := &MockUTXOSource{ctrl: ctrl}
+	mock.recorder = &MockUTXOSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUTXOSource) EXPECT() *MockUTXOSourceMockRecorder {
+	return m.recorder
+}
+
+// GetTxOut mocks base method.
+func (m *MockUTXOSource) GetTxOut(ctx context.Context, txHash *chainhash.Hash, vout uint32) (*wire.TxOut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxOut", ctx, txHash, vout)
+	ret0, _ := ret[0].(*wire.TxOut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTxOut indicates an expected call of GetTxOut.
+func (mr *MockUTXOSourceMockRecorder) GetTxOut(ctx, txHash, vout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxOut", reflect.TypeOf((*MockUTXOSource)(nil).GetTxOut), ctx, txHash, vout)
+}
+
+// MockVTXOStore is a mock of VTXOStore interface.
+type MockVTXOStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockVTXOStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockVTXOStoreMockRecorder is the mock recorder for MockVTXOStore.
+type MockVTXOStoreMockRecorder struct {
+	mock *MockVTXOStore
+}
+
+// NewMockVTXOStore creates a new mock instance.
+func NewMockVTXOStore(ctrl *gomock.Controller) *MockVTXOStore {
+	mock := &MockVTXOStore{ctrl: ctrl}
+	mock.recorder = &MockVTXOStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVTXOStore) EXPECT() *MockVTXOStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVTXOStore) Create(ctx context.Context, vtxo *models.VTXO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, vtxo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVTXOStoreMockRecorder) Create(ctx, vtxo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVTXOStore)(nil).Create), ctx, vtxo)
+}
+
+// GetActiveByContract mocks base method.
+func (m *MockVTXOStore) GetActiveByContract(ctx context.Context, contractID uuid.UUID) (*models.VTXO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByContract", ctx, contractID)
+	ret0, _ := ret[0].(*models.VTXO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByContract indicates an expected call of GetActiveByContract.
+func (mr *MockVTXOStoreMockRecorder) GetActiveByContract(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByContract", reflect.TypeOf((*MockVTXOStore)(nil).GetActiveByContract), ctx, contractID)
+}
+
+// ListActiveByASP mocks base method.
+func (m *MockVTXOStore) ListActiveByASP(ctx context.Context, asp string) ([]*models.VTXO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveByASP", ctx, asp)
+	ret0, _ := ret[0].([]*models.VTXO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveByASP indicates an expected call of ListActiveByASP.
+func (mr *MockVTXOStoreMockRecorder) ListActiveByASP(ctx, asp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveByASP", reflect.TypeOf((*MockVTXOStore)(nil).ListActiveByASP), ctx, asp)
+}
+
+// Replace mocks base method.
+func (m *MockVTXOStore) Replace(ctx context.Context, spentID string, next *models.VTXO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", ctx, spentID, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockVTXOStoreMockRecorder) Replace(ctx, spentID, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockVTXOStore)(nil).Replace), ctx, spentID, next)
+}
+
+// MockOracleEventStore is a mock of OracleEventStore interface.
+type MockOracleEventStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOracleEventStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOracleEventStoreMockRecorder is the mock recorder for MockOracleEventStore.
+type MockOracleEventStoreMockRecorder struct {
+	mock *MockOracleEventStore
+}
+
+// NewMockOracleEventStore creates a new mock instance.
+func NewMockOracleEventStore(ctrl *gomock.Controller) *MockOracleEventStore {
+	mock := &MockOracleEventStore{ctrl: ctrl}
+	mock.recorder = &MockOracleEventStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOracleEventStore) EXPECT() *MockOracleEventStoreMockRecorder {
+	return m.recorder
+}
+
+// Attest mocks base method.
+func (m *MockOracleEventStore) Attest(ctx context.Context, event *models.OracleEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attest", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Attest indicates an expected call of Attest.
+func (mr *MockOracleEventStoreMockRecorder) Attest(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attest", reflect.TypeOf((*MockOracleEventStore)(nil).Attest), ctx, event)
+}
+
+// Create mocks base method.
+func (m *MockOracleEventStore) Create(ctx context.Context, event *models.OracleEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOracleEventStoreMockRecorder) Create(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOracleEventStore)(nil).Create), ctx, event)
+}
+
+// GetByContract mocks base method.
+func (m *MockOracleEventStore) GetByContract(ctx context.Context, contractID uuid.UUID) (*models.OracleEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByContract", ctx, contractID)
+	ret0, _ := ret[0].(*models.OracleEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByContract indicates an expected call of GetByContract.
+func (mr *MockOracleEventStoreMockRecorder) GetByContract(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByContract", reflect.TypeOf((*MockOracleEventStore)(nil).GetByContract), ctx, contractID)
+}
+
+// MockFeeEstimator is a mock of FeeEstimator interface.
+type MockFeeEstimator struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeeEstimatorMockRecorder
+	isgomock struct{}
+}
+
+// MockFeeEstimatorMockRecorder is the mock recorder for MockFeeEstimator.
+type MockFeeEstimatorMockRecorder struct {
+	mock *MockFeeEstimator
+}
+
+// NewMockFeeEstimator creates a new mock instance.
+func NewMockFeeEstimator(ctrl *gomock.Controller) *MockFeeEstimator {
+	mock := &MockFeeEstimator{ctrl: ctrl}
+	mock.recorder = &MockFeeEstimatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeeEstimator) EXPECT() *MockFeeEstimatorMockRecorder {
+	return m.recorder
+}
+
+// EstimateFeeRate mocks base method.
+func (m *MockFeeEstimator) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateFeeRate", ctx, confTarget)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateFeeRate indicates an expected call of EstimateFeeRate.
+func (mr *MockFeeEstimatorMockRecorder) EstimateFeeRate(ctx, confTarget any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateFeeRate", reflect.TypeOf((*MockFeeEstimator)(nil).EstimateFeeRate), ctx, confTarget)
+}
+
+// MockDeferralStore is a mock of DeferralStore interface.
+type MockDeferralStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeferralStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDeferralStoreMockRecorder is the mock recorder for MockDeferralStore.
+type MockDeferralStoreMockRecorder struct {
+	mock *MockDeferralStore
+}
+
+// NewMockDeferralStore creates a new mock instance.
+func NewMockDeferralStore(ctrl *gomock.Controller) *MockDeferralStore {
+	mock := &MockDeferralStore{ctrl: ctrl}
+	mock.recorder = &MockDeferralStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeferralStore) EXPECT() *MockDeferralStoreMockRecorder {
+	return m.recorder
+}
+
+// CountPending mocks base method.
+func (m *MockDeferralStore) CountPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPending indicates an expected call of CountPending.
+func (mr *MockDeferralStoreMockRecorder) CountPending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPending", reflect.TypeOf((*MockDeferralStore)(nil).CountPending), ctx)
+}
+
+// Defer mocks base method.
+func (m *MockDeferralStore) Defer(ctx context.Context, contractID uuid.UUID, feeRate float64) (*models.SettlementDeferral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Defer", ctx, contractID, feeRate)
+	ret0, _ := ret[0].(*models.SettlementDeferral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Defer indicates an expected call of Defer.
+func (mr *MockDeferralStoreMockRecorder) Defer(ctx, contractID, feeRate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Defer", reflect.TypeOf((*MockDeferralStore)(nil).Defer), ctx, contractID, feeRate)
+}
+
+// Get mocks base method.
+func (m *MockDeferralStore) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementDeferral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, contractID)
+	ret0, _ := ret[0].(*models.SettlementDeferral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeferralStoreMockRecorder) Get(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeferralStore)(nil).Get), ctx, contractID)
+}
+
+// ListPending mocks base method.
+func (m *MockDeferralStore) ListPending(ctx context.Context, limit int) ([]*models.SettlementDeferral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, limit)
+	ret0, _ := ret[0].([]*models.SettlementDeferral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockDeferralStoreMockRecorder) ListPending(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockDeferralStore)(nil).ListPending), ctx, limit)
+}
+
+// Release mocks base method.
+func (m *MockDeferralStore) Release(ctx context.Context, contractID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, contractID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockDeferralStoreMockRecorder) Release(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockDeferralStore)(nil).Release), ctx, contractID)
+}
+
+// SetPriorityFeeRate mocks base method.
+func (m *MockDeferralStore) SetPriorityFeeRate(ctx context.Context, contractID uuid.UUID, feeRate float64) (*models.SettlementDeferral, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPriorityFeeRate", ctx, contractID, feeRate)
+	ret0, _ := ret[0].(*models.SettlementDeferral)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPriorityFeeRate indicates an expected call of SetPriorityFeeRate.
+func (mr *MockDeferralStoreMockRecorder) SetPriorityFeeRate(ctx, contractID, feeRate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriorityFeeRate", reflect.TypeOf((*MockDeferralStore)(nil).SetPriorityFeeRate), ctx, contractID, feeRate)
+}
+
+// MockPayoutStore is a mock of PayoutStore interface.
+type MockPayoutStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPayoutStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockPayoutStoreMockRecorder is the mock recorder for MockPayoutStore.
+type MockPayoutStoreMockRecorder struct {
+	mock *MockPayoutStore
+}
+
+// NewMockPayoutStore creates a new mock instance.
+func NewMockPayoutStore(ctrl *gomock.Controller) *MockPayoutStore {
+	mock := &MockPayoutStore{ctrl: ctrl}
+	mock.recorder = &MockPayoutStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPayoutStore) EXPECT() *MockPayoutStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteUserAddress mocks base method.
+func (m *MockPayoutStore) DeleteUserAddress(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAddress", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAddress indicates an expected call of DeleteUserAddress.
+func (mr *MockPayoutStoreMockRecorder) DeleteUserAddress(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAddress", reflect.TypeOf((*MockPayoutStore)(nil).DeleteUserAddress), ctx, userID)
+}
+
+// GetContractAddress mocks base method.
+func (m *MockPayoutStore) GetContractAddress(ctx context.Context, contractID uuid.UUID, pubKey string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContractAddress", ctx, contractID, pubKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContractAddress indicates an expected call of GetContractAddress.
+func (mr *MockPayoutStoreMockRecorder) GetContractAddress(ctx, contractID, pubKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContractAddress", reflect.TypeOf((*MockPayoutStore)(nil).GetContractAddress), ctx, contractID, pubKey)
+}
+
+// GetUserAddress mocks base method.
+func (m *MockPayoutStore) GetUserAddress(ctx context.Context, userID uuid.UUID) (*models.PayoutAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAddress", ctx, userID)
+	ret0, _ := ret[0].(*models.PayoutAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAddress indicates an expected call of GetUserAddress.
+func (mr *MockPayoutStoreMockRecorder) GetUserAddress(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAddress", reflect.TypeOf((*MockPayoutStore)(nil).GetUserAddress), ctx, userID)
+}
+
+// GetUserAddressByKeyID mocks base method.
+func (m *MockPayoutStore) GetUserAddressByKeyID(ctx context.Context, keyID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAddressByKeyID", ctx, keyID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAddressByKeyID indicates an expected call of GetUserAddressByKeyID.
+func (mr *MockPayoutStoreMockRecorder) GetUserAddressByKeyID(ctx, keyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAddressByKeyID", reflect.TypeOf((*MockPayoutStore)(nil).GetUserAddressByKeyID), ctx, keyID)
+}
+
+// SetContractAddress mocks base method.
+func (m *MockPayoutStore) SetContractAddress(ctx context.Context, address *models.ContractPayoutAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetContractAddress", ctx, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetContractAddress indicates an expected call of SetContractAddress.
+func (mr *MockPayoutStoreMockRecorder) SetContractAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContractAddress", reflect.TypeOf((*MockPayoutStore)(nil).SetContractAddress), ctx, address)
+}
+
+// SetUserAddress mocks base method.
+func (m *MockPayoutStore) SetUserAddress(ctx context.Context, address *models.PayoutAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserAddress", ctx, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserAddress indicates an expected call of SetUserAddress.
+func (mr *MockPayoutStoreMockRecorder) SetUserAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserAddress", reflect.TypeOf((*MockPayoutStore)(nil).SetUserAddress), ctx, address)
+}
+
+// MockOpenInterestStore is a mock of OpenInterestStore interface.
+type MockOpenInterestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenInterestStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOpenInterestStoreMockRecorder is the mock recorder for MockOpenInterestStore.
+type MockOpenInterestStoreMockRecorder struct {
+	mock *MockOpenInterestStore
+}
+
+// NewMockOpenInterestStore creates a new mock instance.
+func NewMockOpenInterestStore(ctrl *gomock.Controller) *MockOpenInterestStore {
+	mock := &MockOpenInterestStore{ctrl: ctrl}
+	mock.recorder = &MockOpenInterestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenInterestStore) EXPECT() *MockOpenInterestStoreMockRecorder {
+	return m.recorder
+}
+
+// Adjust mocks base method.
+func (m *MockOpenInterestStore) Adjust(ctx context.Context, contract *models.Contract, delta int64) (*models.OpenInterest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, contract, delta)
+	ret0, _ := ret[0].(*models.OpenInterest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockOpenInterestStoreMockRecorder) Adjust(ctx, contract, delta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockOpenInterestStore)(nil).Adjust), ctx, contract, delta)
+}
+
+// List mocks base method.
+func (m *MockOpenInterestStore) List(ctx context.Context) ([]*models.OpenInterest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*models.OpenInterest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOpenInterestStoreMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOpenInterestStore)(nil).List), ctx)
+}
+
+// ListHistory mocks base method.
+func (m *MockOpenInterestStore) ListHistory(ctx context.Context, contractType models.ContractType, strikeHashRate float64, startBlockHeight, endBlockHeight int64, since time.Time, limit int) ([]*models.OpenInterestPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHistory", ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, limit)
+	ret0, _ := ret[0].([]*models.OpenInterestPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHistory indicates an expected call of ListHistory.
+func (mr *MockOpenInterestStoreMockRecorder) ListHistory(ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHistory", reflect.TypeOf((*MockOpenInterestStore)(nil).ListHistory), ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, limit)
+}
+
+// MockScheduledCloseStore is a mock of ScheduledCloseStore interface.
+type MockScheduledCloseStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledCloseStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduledCloseStoreMockRecorder is the mock recorder for MockScheduledCloseStore.
+type MockScheduledCloseStoreMockRecorder struct {
+	mock *MockScheduledCloseStore
+}
+
+// NewMockScheduledCloseStore creates a new mock instance.
+func NewMockScheduledCloseStore(ctrl *gomock.Controller) *MockScheduledCloseStore {
+	mock := &MockScheduledCloseStore{ctrl: ctrl}
+	mock.recorder = &MockScheduledCloseStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledCloseStore) EXPECT() *MockScheduledCloseStoreMockRecorder {
+	return m.recorder
+}
+
+// AddSignature mocks base method.
+func (m *MockScheduledCloseStore) AddSignature(ctx context.Context, terms *models.ScheduledClose, isBuyer bool, signature string) (*models.ScheduledClose, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSignature", ctx, terms, isBuyer, signature)
+	ret0, _ := ret[0].(*models.ScheduledClose)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSignature indicates an expected call of AddSignature.
+func (mr *MockScheduledCloseStoreMockRecorder) AddSignature(ctx, terms, isBuyer, signature any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSignature", reflect.TypeOf((*MockScheduledCloseStore)(nil).AddSignature), ctx, terms, isBuyer, signature)
+}
+
+// Get mocks base method.
+func (m *MockScheduledCloseStore) Get(ctx context.Context, contractID uuid.UUID) (*models.ScheduledClose, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, contractID)
+	ret0, _ := ret[0].(*models.ScheduledClose)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockScheduledCloseStoreMockRecorder) Get(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockScheduledCloseStore)(nil).Get), ctx, contractID)
+}
+
+// ListDue mocks base method.
+func (m *MockScheduledCloseStore) ListDue(ctx context.Context, height int64, now time.Time, limit int) ([]*models.ScheduledClose, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDue", ctx, height, now, limit)
+	ret0, _ := ret[0].([]*models.ScheduledClose)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDue indicates an expected call of ListDue.
+func (mr *MockScheduledCloseStoreMockRecorder) ListDue(ctx, height, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDue", reflect.TypeOf((*MockScheduledCloseStore)(nil).ListDue), ctx, height, now, limit)
+}
+
+// Propose mocks base method.
+func (m *MockScheduledCloseStore) Propose(ctx context.Context, sc *models.ScheduledClose) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Propose", ctx, sc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Propose indicates an expected call of Propose.
+func (mr *MockScheduledCloseStoreMockRecorder) Propose(ctx, sc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Propose", reflect.TypeOf((*MockScheduledCloseStore)(nil).Propose), ctx, sc)
+}
+
+// Resolve mocks base method.
+func (m *MockScheduledCloseStore) Resolve(ctx context.Context, contractID uuid.UUID, status models.ScheduledCloseStatus, closeTxID, lastError *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, contractID, status, closeTxID, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockScheduledCloseStoreMockRecorder) Resolve(ctx, contractID, status, closeTxID, lastError any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockScheduledCloseStore)(nil).Resolve), ctx, contractID, status, closeTxID, lastError)
+}
+
+// MockEvidenceStore is a mock of EvidenceStore interface.
+type MockEvidenceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEvidenceStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEvidenceStoreMockRecorder is the mock recorder for MockEvidenceStore.
+type MockEvidenceStoreMockRecorder struct {
+	mock *MockEvidenceStore
+}
+
+// NewMockEvidenceStore creates a new mock instance.
+func NewMockEvidenceStore(ctrl *gomock.Controller) *MockEvidenceStore {
+	mock := &MockEvidenceStore{ctrl: ctrl}
+	mock.recorder = &MockEvidenceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEvidenceStore) EXPECT() *MockEvidenceStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockEvidenceStore) Create(ctx context.Context, evidence *models.SettlementEvidence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, evidence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEvidenceStoreMockRecorder) Create(ctx, evidence any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEvidenceStore)(nil).Create), ctx, evidence)
+}
+
+// Get mocks base method.
+func (m *MockEvidenceStore) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, contractID)
+	ret0, _ := ret[0].(*models.SettlementEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockEvidenceStoreMockRecorder) Get(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockEvidenceStore)(nil).Get), ctx, contractID)
+}
+
+// MockChannelPublisher is a mock of ChannelPublisher interface.
+type MockChannelPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockChannelPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockChannelPublisherMockRecorder is the mock recorder for MockChannelPublisher.
+type MockChannelPublisherMockRecorder struct {
+	mock *MockChannelPublisher
+}
+
+// NewMockChannelPublisher creates a new mock instance.
+func NewMockChannelPublisher(ctrl *gomock.Controller) *MockChannelPublisher {
+	mock := &MockChannelPublisher{ctrl: ctrl}
+	mock.recorder = &MockChannelPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChannelPublisher) EXPECT() *MockChannelPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishToChannel mocks base method.
+func (m *MockChannelPublisher) PublishToChannel(channel string, message any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PublishToChannel", channel, message)
+}
+
+// PublishToChannel indicates an expected call of PublishToChannel.
+func (mr *MockChannelPublisherMockRecorder) PublishToChannel(channel, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishToChannel", reflect.TypeOf((*MockChannelPublisher)(nil).PublishToChannel), channel, message)
+}
+
+// MockSettlementObserver is a mock of SettlementObserver interface.
+type MockSettlementObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettlementObserverMockRecorder
+	isgomock struct{}
+}
+
+// MockSettlementObserverMockRecorder is the mock recorder for MockSettlementObserver.
+type MockSettlementObserverMockRecorder struct {
+	mock *MockSettlementObserver
+}
+
+// NewMockSettlementObserver creates a new mock instance.
+func NewMockSettlementObserver(ctrl *gomock.Controller) *MockSettlementObserver {
+	mock := &MockSettlementObserver{ctrl: ctrl}
+	mock.recorder = &MockSettlementObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettlementObserver) EXPECT() *MockSettlementObserverMockRecorder {
+	return m.recorder
+}
+
+// OnContractSettled mocks base method.
+func (m *MockSettlementObserver) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnContractSettled", ctx, contract, buyerWins)
+}
+
+// OnContractSettled indicates an expected call of OnContractSettled.
+func (mr *MockSettlementObserverMockRecorder) OnContractSettled(ctx, contract, buyerWins any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnContractSettled", reflect.TypeOf((*MockSettlementObserver)(nil).OnContractSettled), ctx, contract, buyerWins)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(topic events.Topic, payload any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", topic, payload)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(topic, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), topic, payload)
+}
+
+// MockConfirmationStore is a mock of ConfirmationStore interface.
+type MockConfirmationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfirmationStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockConfirmationStoreMockRecorder is the mock recorder for MockConfirmationStore.
+type MockConfirmationStoreMockRecorder struct {
+	mock *MockConfirmationStore
+}
+
+// NewMockConfirmationStore creates a new mock instance.
+func NewMockConfirmationStore(ctrl *gomock.Controller) *MockConfirmationStore {
+	mock := &MockConfirmationStore{ctrl: ctrl}
+	mock.recorder = &MockConfirmationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfirmationStore) EXPECT() *MockConfirmationStoreMockRecorder {
+	return m.recorder
+}
+
+// ConfirmTransaction mocks base method.
+func (m *MockConfirmationStore) ConfirmTransaction(ctx context.Context, txID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTransaction", ctx, txID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTransaction indicates an expected call of ConfirmTransaction.
+func (mr *MockConfirmationStoreMockRecorder) ConfirmTransaction(ctx, txID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTransaction", reflect.TypeOf((*MockConfirmationStore)(nil).ConfirmTransaction), ctx, txID)
+}
+
+// ListUnconfirmedTransactions mocks base method.
+func (m *MockConfirmationStore) ListUnconfirmedTransactions(ctx context.Context) ([]*models.ContractTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnconfirmedTransactions", ctx)
+	ret0, _ := ret[0].([]*models.ContractTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnconfirmedTransactions indicates an expected call of ListUnconfirmedTransactions.
+func (mr *MockConfirmationStoreMockRecorder) ListUnconfirmedTransactions(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnconfirmedTransactions", reflect.TypeOf((*MockConfirmationStore)(nil).ListUnconfirmedTransactions), ctx)
+}
+
+// MockConfirmationSource is a mock of ConfirmationSource interface.
+type MockConfirmationSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfirmationSourceMockRecorder
+	isgomock struct{}
+}
+
+// MockConfirmationSourceMockRecorder is the mock recorder for MockConfirmationSource.
+type MockConfirmationSourceMockRecorder struct {
+	mock *MockConfirmationSource
+}
+
+// NewMockConfirmationSource creates a new mock instance.
+func NewMockConfirmationSource(ctrl *gomock.Controller) *MockConfirmationSource {
+	mock := &MockConfirmationSource{ctrl: ctrl}
+	mock.recorder = &MockConfirmationSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfirmationSource) EXPECT() *MockConfirmationSourceMockRecorder {
+	return m.recorder
+}
+
+// GetTransactionConfirmations mocks base method.
+func (m *MockConfirmationSource) GetTransactionConfirmations(ctx context.Context, txHash *chainhash.Hash) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionConfirmations", ctx, txHash)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionConfirmations indicates an expected call of GetTransactionConfirmations.
+func (mr *MockConfirmationSourceMockRecorder) GetTransactionConfirmations(ctx, txHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionConfirmations", reflect.TypeOf((*MockConfirmationSource)(nil).GetTransactionConfirmations), ctx, txHash)
+}
+
+// MockSettlementBroadcastStore is a mock of SettlementBroadcastStore interface.
+type MockSettlementBroadcastStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettlementBroadcastStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSettlementBroadcastStoreMockRecorder is the mock recorder for MockSettlementBroadcastStore.
+type MockSettlementBroadcastStoreMockRecorder struct {
+	mock *MockSettlementBroadcastStore
+}
+
+// NewMockSettlementBroadcastStore creates a new mock instance.
+func NewMockSettlementBroadcastStore(ctrl *gomock.Controller) *MockSettlementBroadcastStore {
+	mock := &MockSettlementBroadcastStore{ctrl: ctrl}
+	mock.recorder = &MockSettlementBroadcastStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettlementBroadcastStore) EXPECT() *MockSettlementBroadcastStoreMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockSettlementBroadcastStore) Confirm(ctx context.Context, contractID uuid.UUID, confirmations int64) (*models.SettlementBroadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, contractID, confirmations)
+	ret0, _ := ret[0].(*models.SettlementBroadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockSettlementBroadcastStoreMockRecorder) Confirm(ctx, contractID, confirmations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).Confirm), ctx, contractID, confirmations)
+}
+
+// Create mocks base method.
+func (m *MockSettlementBroadcastStore) Create(ctx context.Context, broadcast *models.SettlementBroadcast) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, broadcast)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSettlementBroadcastStoreMockRecorder) Create(ctx, broadcast any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).Create), ctx, broadcast)
+}
+
+// Get mocks base method.
+func (m *MockSettlementBroadcastStore) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementBroadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, contractID)
+	ret0, _ := ret[0].(*models.SettlementBroadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSettlementBroadcastStoreMockRecorder) Get(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).Get), ctx, contractID)
+}
+
+// ListPending mocks base method.
+func (m *MockSettlementBroadcastStore) ListPending(ctx context.Context, limit int) ([]*models.SettlementBroadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, limit)
+	ret0, _ := ret[0].([]*models.SettlementBroadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockSettlementBroadcastStoreMockRecorder) ListPending(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).ListPending), ctx, limit)
+}
+
+// ListStuck mocks base method.
+func (m *MockSettlementBroadcastStore) ListStuck(ctx context.Context, before time.Time, limit, offset int) ([]*models.SettlementBroadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStuck", ctx, before, limit, offset)
+	ret0, _ := ret[0].([]*models.SettlementBroadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStuck indicates an expected call of ListStuck.
+func (mr *MockSettlementBroadcastStoreMockRecorder) ListStuck(ctx, before, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStuck", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).ListStuck), ctx, before, limit, offset)
+}
+
+// RecordBroadcast mocks base method.
+func (m *MockSettlementBroadcastStore) RecordBroadcast(ctx context.Context, contractID uuid.UUID, broadcastErr error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBroadcast", ctx, contractID, broadcastErr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBroadcast indicates an expected call of RecordBroadcast.
+func (mr *MockSettlementBroadcastStoreMockRecorder) RecordBroadcast(ctx, contractID, broadcastErr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBroadcast", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).RecordBroadcast), ctx, contractID, broadcastErr)
+}
+
+// RecordConfirmations mocks base method.
+func (m *MockSettlementBroadcastStore) RecordConfirmations(ctx context.Context, contractID uuid.UUID, confirmations int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordConfirmations", ctx, contractID, confirmations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordConfirmations indicates an expected call of RecordConfirmations.
+func (mr *MockSettlementBroadcastStoreMockRecorder) RecordConfirmations(ctx, contractID, confirmations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordConfirmations", reflect.TypeOf((*MockSettlementBroadcastStore)(nil).RecordConfirmations), ctx, contractID, confirmations)
+}
+
+// MockCollateralPolicy is a mock of CollateralPolicy interface.
+type MockCollateralPolicy struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollateralPolicyMockRecorder
+	isgomock struct{}
+}
+
+// MockCollateralPolicyMockRecorder is the mock recorder for MockCollateralPolicy.
+type MockCollateralPolicyMockRecorder struct {
+	mock *MockCollateralPolicy
+}
+
+// NewMockCollateralPolicy creates a new mock instance.
+func NewMockCollateralPolicy(ctrl *gomock.Controller) *MockCollateralPolicy {
+	mock := &MockCollateralPolicy{ctrl: ctrl}
+	mock.recorder = &MockCollateralPolicyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollateralPolicy) EXPECT() *MockCollateralPolicyMockRecorder {
+	return m.recorder
+}
+
+// RequiredCollateral mocks base method.
+func (m *MockCollateralPolicy) RequiredCollateral(ctx context.Context, contract *models.Contract) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequiredCollateral", ctx, contract)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequiredCollateral indicates an expected call of RequiredCollateral.
+func (mr *MockCollateralPolicyMockRecorder) RequiredCollateral(ctx, contract any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequiredCollateral", reflect.TypeOf((*MockCollateralPolicy)(nil).RequiredCollateral), ctx, contract)
+}
+
+// MockDeferralObserver is a mock of DeferralObserver interface.
+type MockDeferralObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeferralObserverMockRecorder
+	isgomock struct{}
+}
+
+// MockDeferralObserverMockRecorder is the mock recorder for MockDeferralObserver.
+type MockDeferralObserverMockRecorder struct {
+	mock *MockDeferralObserver
+}
+
+// NewMockDeferralObserver creates a new mock instance.
+func NewMockDeferralObserver(ctrl *gomock.Controller) *MockDeferralObserver {
+	mock := &MockDeferralObserver{ctrl: ctrl}
+	mock.recorder = &MockDeferralObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeferralObserver) EXPECT() *MockDeferralObserverMockRecorder {
+	return m.recorder
+}
+
+// OnSettlementDeferred mocks base method.
+func (m *MockDeferralObserver) OnSettlementDeferred(ctx context.Context, contract *models.Contract, deferral *models.SettlementDeferral) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnSettlementDeferred", ctx, contract, deferral)
+}
+
+// OnSettlementDeferred indicates an expected call of OnSettlementDeferred.
+func (mr *MockDeferralObserverMockRecorder) OnSettlementDeferred(ctx, contract, deferral any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnSettlementDeferred", reflect.TypeOf((*MockDeferralObserver)(nil).OnSettlementDeferred), ctx, contract, deferral)
+}
+
+// MockTransitionStore is a mock of TransitionStore interface.
+type MockTransitionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTransitionStoreMockRecorder is the mock recorder for MockTransitionStore.
+type MockTransitionStoreMockRecorder struct {
+	mock *MockTransitionStore
+}
+
+// NewMockTransitionStore creates a new mock instance.
+func NewMockTransitionStore(ctrl *gomock.Controller) *MockTransitionStore {
+	mock := &MockTransitionStore{ctrl: ctrl}
+	mock.recorder = &MockTransitionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransitionStore) EXPECT() *MockTransitionStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransitionStore) Create(ctx context.Context, transition *models.ContractTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransitionStoreMockRecorder) Create(ctx, transition any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransitionStore)(nil).Create), ctx, transition)
+}
+
+// ListByContractID mocks base method.
+func (m *MockTransitionStore) ListByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByContractID", ctx, contractID)
+	ret0, _ := ret[0].([]*models.ContractTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByContractID indicates an expected call of ListByContractID.
+func (mr *MockTransitionStoreMockRecorder) ListByContractID(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByContractID", reflect.TypeOf((*MockTransitionStore)(nil).ListByContractID), ctx, contractID)
+}
+
+// MockUserKeyStore is a mock of UserKeyStore interface.
+type MockUserKeyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserKeyStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockUserKeyStoreMockRecorder is the mock recorder for MockUserKeyStore.
+type MockUserKeyStoreMockRecorder struct {
+	mock *MockUserKeyStore
+}
+
+// NewMockUserKeyStore creates a new mock instance.
+func NewMockUserKeyStore(ctrl *gomock.Controller) *MockUserKeyStore {
+	mock := &MockUserKeyStore{ctrl: ctrl}
+	mock.recorder = &MockUserKeyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserKeyStore) EXPECT() *MockUserKeyStoreMockRecorder {
+	return m.recorder
+}
+
+// GetKeysByUserID mocks base method.
+func (m *MockUserKeyStore) GetKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeysByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*models.UserKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeysByUserID indicates an expected call of GetKeysByUserID.
+func (mr *MockUserKeyStoreMockRecorder) GetKeysByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeysByUserID", reflect.TypeOf((*MockUserKeyStore)(nil).GetKeysByUserID), ctx, userID)
+}
+
+// MockContractStore is a mock of ContractStore interface.
+type MockContractStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockContractStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockContractStoreMockRecorder is the mock recorder for MockContractStore.
+type MockContractStoreMockRecorder struct {
+	mock *MockContractStore
+}
+
+// NewMockContractStore creates a new mock instance.
+func NewMockContractStore(ctrl *gomock.Controller) *MockContractStore {
+	mock := &MockContractStore{ctrl: ctrl}
+	mock.recorder = &MockContractStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContractStore) EXPECT() *MockContractStoreMockRecorder {
+	return m.recorder
+}
+
+// AddTransaction mocks base method.
+func (m *MockContractStore) AddTransaction(ctx context.Context, tx *models.ContractTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransaction", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTransaction indicates an expected call of AddTransaction.
+func (mr *MockContractStoreMockRecorder) AddTransaction(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockContractStore)(nil).AddTransaction), ctx, tx)
+}
+
+// CountTransactionsByType mocks base method.
+func (m *MockContractStore) CountTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTransactionsByType", ctx, contractID, txType)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTransactionsByType indicates an expected call of CountTransactionsByType.
+func (mr *MockContractStoreMockRecorder) CountTransactionsByType(ctx, contractID, txType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTransactionsByType", reflect.TypeOf((*MockContractStore)(nil).CountTransactionsByType), ctx, contractID, txType)
+}
+
+// CountWithStatus mocks base method.
+func (m *MockContractStore) CountWithStatus(ctx context.Context, status models.ContractStatus) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithStatus", ctx, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithStatus indicates an expected call of CountWithStatus.
+func (mr *MockContractStoreMockRecorder) CountWithStatus(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithStatus", reflect.TypeOf((*MockContractStore)(nil).CountWithStatus), ctx, status)
+}
+
+// Create mocks base method.
+func (m *MockContractStore) Create(ctx context.Context, contract *models.Contract) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, contract)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockContractStoreMockRecorder) Create(ctx, contract any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockContractStore)(nil).Create), ctx, contract)
+}
+
+// ExecuteInTransaction mocks base method.
+func (m *MockContractStore) ExecuteInTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteInTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExecuteInTransaction indicates an expected call of ExecuteInTransaction.
+func (mr *MockContractStoreMockRecorder) ExecuteInTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteInTransaction", reflect.TypeOf((*MockContractStore)(nil).ExecuteInTransaction), ctx, fn)
+}
+
+// GetByID mocks base method.
+func (m *MockContractStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockContractStoreMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockContractStore)(nil).GetByID), ctx, id)
+}
+
+// GetTransactionByID mocks base method.
+func (m *MockContractStore) GetTransactionByID(ctx context.Context, txID uuid.UUID) (*models.ContractTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionByID", ctx, txID)
+	ret0, _ := ret[0].(*models.ContractTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionByID indicates an expected call of GetTransactionByID.
+func (mr *MockContractStoreMockRecorder) GetTransactionByID(ctx, txID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionByID", reflect.TypeOf((*MockContractStore)(nil).GetTransactionByID), ctx, txID)
+}
+
+// GetTransactionsByContractID mocks base method.
+func (m *MockContractStore) GetTransactionsByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByContractID", ctx, contractID)
+	ret0, _ := ret[0].([]*models.ContractTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByContractID indicates an expected call of GetTransactionsByContractID.
+func (mr *MockContractStoreMockRecorder) GetTransactionsByContractID(ctx, contractID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByContractID", reflect.TypeOf((*MockContractStore)(nil).GetTransactionsByContractID), ctx, contractID)
+}
+
+// ListByStatus mocks base method.
+func (m *MockContractStore) ListByStatus(ctx context.Context, status models.ContractStatus, limit, offset int) ([]*models.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatus", ctx, status, limit, offset)
+	ret0, _ := ret[0].([]*models.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatus indicates an expected call of ListByStatus.
+func (mr *MockContractStoreMockRecorder) ListByStatus(ctx, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatus", reflect.TypeOf((*MockContractStore)(nil).ListByStatus), ctx, status, limit, offset)
+}
+
+// ListByStatusPage mocks base method.
+func (m *MockContractStore) ListByStatusPage(ctx context.Context, status models.ContractStatus, after models.Cursor, limit int) ([]*models.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByStatusPage", ctx, status, after, limit)
+	ret0, _ := ret[0].([]*models.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByStatusPage indicates an expected call of ListByStatusPage.
+func (mr *MockContractStoreMockRecorder) ListByStatusPage(ctx, status, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByStatusPage", reflect.TypeOf((*MockContractStore)(nil).ListByStatusPage), ctx, status, after, limit)
+}
+
+// ListTransactionsByType mocks base method.
+func (m *MockContractStore) ListTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string, after models.Cursor, limit int) ([]*models.ContractTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactionsByType", ctx, contractID, txType, after, limit)
+	ret0, _ := ret[0].([]*models.ContractTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactionsByType indicates an expected call of ListTransactionsByType.
+func (mr *MockContractStoreMockRecorder) ListTransactionsByType(ctx, contractID, txType, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactionsByType", reflect.TypeOf((*MockContractStore)(nil).ListTransactionsByType), ctx, contractID, txType, after, limit)
+}
+
+// Update mocks base method.
+func (m *MockContractStore) Update(ctx context.Context, contract *models.Contract) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, contract)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockContractStoreMockRecorder) Update(ctx, contract any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockContractStore)(nil).Update), ctx, contract)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockContractStore) UpdateStatus(ctx context.Context, id uuid.UUID, status models.ContractStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockContractStoreMockRecorder) UpdateStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockContractStore)(nil).UpdateStatus), ctx, id, status)
+}
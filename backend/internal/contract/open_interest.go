@@ -0,0 +1,74 @@
+// internal/contract/open_interest.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// ErrOpenInterestNotEnabled is returned when no open interest store is configured
+var ErrOpenInterestNotEnabled = errors.New("open interest tracking is not enabled")
+
+// WithOpenInterestStore enables open interest tracking per market
+func (s *Service) WithOpenInterestStore(store OpenInterestStore) *Service {
+	s.openInterestRepo = store
+	return s
+}
+
+// OpenInterestEnabled reports whether open interest is tracked
+func (s *Service) OpenInterestEnabled() bool {
+	return s.openInterestRepo != nil
+}
+
+// ListOpenInterest returns the open interest of every market with active contracts
+func (s *Service) ListOpenInterest(ctx context.Context) ([]*models.OpenInterest, error) {
+	if s.openInterestRepo == nil {
+		return nil, ErrOpenInterestNotEnabled
+	}
+	return s.openInterestRepo.List(ctx)
+}
+
+// OpenInterestHistory returns the recorded open interest of a market since
+// the given time, newest first
+func (s *Service) OpenInterestHistory(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+	since time.Time,
+	limit int,
+) ([]*models.OpenInterestPoint, error) {
+	if s.openInterestRepo == nil {
+		return nil, ErrOpenInterestNotEnabled
+	}
+	return s.openInterestRepo.ListHistory(ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, limit)
+}
+
+// adjustOpenInterest adds delta contracts to the open interest of a
+// contract's market. The contract's status change has already been stored,
+// so a failure is logged rather than returned.
+func (s *Service) adjustOpenInterest(ctx context.Context, contract *models.Contract, delta int64) {
+	if s.openInterestRepo == nil {
+		return
+	}
+
+	oi, err := s.openInterestRepo.Adjust(ctx, contract, delta)
+	if err != nil {
+		logger.Error().Err(err).
+			Str("contract_id", contract.ID.String()).
+			Int64("delta", delta).
+			Msg("Failed to adjust open interest")
+		return
+	}
+
+	logger.Debug().
+		Str("contract_id", contract.ID.String()).
+		Str("contract_type", string(contract.ContractType)).
+		Float64("strike_hash_rate", contract.StrikeHashRate).
+		Int64("end_block_height", contract.EndBlockHeight).
+		Int64("contracts", oi.Contracts).
+		Msg("Open interest updated")
+}
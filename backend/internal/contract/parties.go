@@ -0,0 +1,62 @@
+// internal/contract/parties.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ErrNotParty is returned when a user holds neither the buyer's nor the
+// seller's key of a contract
+var ErrNotParty = errors.New("user is not a party to the contract")
+
+// WithUserKeys sets the store of users' keys, which tells the parties to a
+// contract apart. Without it no user is a party to any contract.
+func (s *Service) WithUserKeys(store UserKeyStore) *Service {
+	s.userKeys = store
+	return s
+}
+
+// CheckParty retrieves a contract and checks that the user holds its buyer's
+// or seller's key
+func (s *Service) CheckParty(ctx context.Context, contractID, userID uuid.UUID) (*models.Contract, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	for _, pubKey := range []string{contract.BuyerPubKey, contract.SellerPubKey} {
+		holds, err := s.HoldsKey(ctx, userID, pubKey)
+		if err != nil {
+			return nil, err
+		}
+		if holds {
+			return contract, nil
+		}
+	}
+	return nil, ErrNotParty
+}
+
+// HoldsKey reports whether a public key is one the user registered
+func (s *Service) HoldsKey(ctx context.Context, userID uuid.UUID, pubKey string) (bool, error) {
+	if s.userKeys == nil || pubKey == "" {
+		return false, nil
+	}
+
+	keys, err := s.userKeys.GetKeysByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user keys: %w", err)
+	}
+
+	for _, key := range keys {
+		if key.PubKey == pubKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,56 @@
+// internal/contract/parties_test.go
+package contract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+// stubUserKeys holds the keys of each user in memory
+type stubUserKeys map[uuid.UUID][]*models.UserKey
+
+func (s stubUserKeys) GetKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserKey, error) {
+	return s[userID], nil
+}
+
+func TestCheckParty(t *testing.T) {
+	contract := &models.Contract{ID: uuid.New(), BuyerPubKey: "02aa", SellerPubKey: "03bb"}
+	buyer, seller, stranger := uuid.New(), uuid.New(), uuid.New()
+	s := &Service{contractRepo: &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: contract}}}
+
+	// Without a key store nobody is a party
+	_, err := s.CheckParty(context.Background(), contract.ID, buyer)
+	assert.ErrorIs(t, err, ErrNotParty)
+
+	s.WithUserKeys(stubUserKeys{
+		buyer:    {{PubKey: "02aa"}},
+		seller:   {{PubKey: "02cc"}, {PubKey: "03bb"}},
+		stranger: {{PubKey: "02cc"}, {PubKey: ""}},
+	})
+
+	got, err := s.CheckParty(context.Background(), contract.ID, buyer)
+	require.NoError(t, err)
+	assert.Equal(t, contract.ID, got.ID)
+
+	_, err = s.CheckParty(context.Background(), contract.ID, seller)
+	assert.NoError(t, err)
+
+	_, err = s.CheckParty(context.Background(), contract.ID, stranger)
+	assert.ErrorIs(t, err, ErrNotParty)
+
+	_, err = s.CheckParty(context.Background(), uuid.New(), buyer)
+	assert.Error(t, err)
+
+	holds, err := s.HoldsKey(context.Background(), seller, "03bb")
+	require.NoError(t, err)
+	assert.True(t, holds)
+	holds, err = s.HoldsKey(context.Background(), seller, "02aa")
+	require.NoError(t, err)
+	assert.False(t, holds)
+}
@@ -0,0 +1,228 @@
+// internal/contract/payout.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+// ErrPayoutsNotEnabled is returned when no payout store is configured
+var ErrPayoutsNotEnabled = errors.New("payout addresses are not enabled")
+
+// settlementPayout is the address a settlement pays the winner at. Record is
+// set when the address came from the winner's registered preference and must
+// be recorded on the contract once the settlement is stored.
+type settlementPayout struct {
+	ContractID uuid.UUID
+	PubKey     string
+	Address    string
+	Record     bool
+}
+
+// WithPayoutStore enables payout addresses other than the winner's contract key
+func (s *Service) WithPayoutStore(store PayoutStore) *Service {
+	s.payoutRepo = store
+	return s
+}
+
+// GetPayoutAddress returns a user's registered payout address, or nil if none is registered
+func (s *Service) GetPayoutAddress(ctx context.Context, userID uuid.UUID) (*models.PayoutAddress, error) {
+	if s.payoutRepo == nil {
+		return nil, ErrPayoutsNotEnabled
+	}
+	return s.payoutRepo.GetUserAddress(ctx, userID)
+}
+
+// SetPayoutAddress registers the address a user's winning settlements pay to
+func (s *Service) SetPayoutAddress(ctx context.Context, userID uuid.UUID, address string) (*models.PayoutAddress, error) {
+	if s.payoutRepo == nil {
+		return nil, ErrPayoutsNotEnabled
+	}
+
+	canonical, err := taproot.DecodePayoutAddress(address, s.taprootScriptBuilder.Params())
+	if err != nil {
+		return nil, err
+	}
+
+	payout := &models.PayoutAddress{UserID: userID, Address: canonical}
+	if err := s.payoutRepo.SetUserAddress(ctx, payout); err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+// DeletePayoutAddress removes a user's payout address so settlements pay
+// their contract key again
+func (s *Service) DeletePayoutAddress(ctx context.Context, userID uuid.UUID) error {
+	if s.payoutRepo == nil {
+		return ErrPayoutsNotEnabled
+	}
+	return s.payoutRepo.DeleteUserAddress(ctx, userID)
+}
+
+// SetContractPayoutAddress directs a party's payout from one contract to an
+// address. The party proves control of its contract key with a BIP-340
+// signature over taproot.PayoutMessageHash of the contract ID and the
+// address in canonical form.
+func (s *Service) SetContractPayoutAddress(
+	ctx context.Context,
+	contractID uuid.UUID,
+	pubKey string,
+	address string,
+	signature string,
+) (*models.ContractPayoutAddress, error) {
+	if s.payoutRepo == nil {
+		return nil, ErrPayoutsNotEnabled
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if pubKey != contract.BuyerPubKey && pubKey != contract.SellerPubKey {
+		return nil, errors.New("public key is not a party to the contract")
+	}
+
+	if contract.Status != models.ContractStatusCreated && contract.Status != models.ContractStatusActive {
+		return nil, errors.New("payout address can only be changed before settlement")
+	}
+
+	canonical, err := taproot.DecodePayoutAddress(address, s.taprootScriptBuilder.Params())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := taproot.VerifyPayoutSignature(pubKey, contractID.String(), canonical, signature); err != nil {
+		return nil, err
+	}
+
+	payout := &models.ContractPayoutAddress{
+		ContractID: contractID,
+		PubKey:     pubKey,
+		Address:    canonical,
+	}
+	if err := s.payoutRepo.SetContractAddress(ctx, payout); err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+// resolvePayoutAddress returns where a settlement pays the winner: the
+// address set for the contract, else the registered address of the user
+// owning the winning key, else an empty address for the contract key itself
+func (s *Service) resolvePayoutAddress(
+	ctx context.Context,
+	contractID uuid.UUID,
+	winnerPubKey string,
+	winnerKeyID *uuid.UUID,
+) (settlementPayout, error) {
+	payout := settlementPayout{ContractID: contractID, PubKey: winnerPubKey}
+	if s.payoutRepo == nil {
+		return payout, nil
+	}
+
+	address, err := s.payoutRepo.GetContractAddress(ctx, contractID, winnerPubKey)
+	if err != nil {
+		return payout, err
+	}
+	if address != "" || winnerKeyID == nil {
+		payout.Address = address
+		return payout, nil
+	}
+
+	address, err = s.payoutRepo.GetUserAddressByKeyID(ctx, *winnerKeyID)
+	if err != nil {
+		return payout, err
+	}
+	payout.Address = address
+	payout.Record = address != ""
+
+	return payout, nil
+}
+
+// recordPayoutAddress stores a payout taken from the winner's registered
+// address on the contract, so the settlement can be verified after the
+// winner changes their preference
+func (s *Service) recordPayoutAddress(ctx context.Context, payout settlementPayout) {
+	if !payout.Record {
+		return
+	}
+
+	err := s.payoutRepo.SetContractAddress(ctx, &models.ContractPayoutAddress{
+		ContractID: payout.ContractID,
+		PubKey:     payout.PubKey,
+		Address:    payout.Address,
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("contractID", payout.ContractID.String()).Msg("Failed to record payout address")
+	}
+}
+
+// SettlementOutput is where a settlement pays one party of a contract if it
+// wins, with a descriptor wallets can import to watch the output
+type SettlementOutput struct {
+	Party      string `json:"party"`
+	PubKey     string `json:"pub_key"`
+	Address    string `json:"address"`
+	Descriptor string `json:"descriptor"`
+}
+
+// GetSettlementOutputs returns the settlement output of each party of a
+// contract. Outputs paying a contract key are exported as tr() descriptors,
+// outputs paying a chosen payout address as addr() descriptors.
+func (s *Service) GetSettlementOutputs(ctx context.Context, contractID uuid.UUID) ([]SettlementOutput, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	parties := []struct {
+		name   string
+		pubKey string
+		keyID  *uuid.UUID
+	}{
+		{"buyer", contract.BuyerPubKey, contract.BuyerKeyID},
+		{"seller", contract.SellerPubKey, contract.SellerKeyID},
+	}
+
+	outputs := make([]SettlementOutput, 0, len(parties))
+	for _, party := range parties {
+		payout, err := s.resolvePayoutAddress(ctx, contract.ID, party.pubKey, party.keyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s payout address: %w", party.name, err)
+		}
+
+		address, err := s.taprootScriptBuilder.BuildSettlementScript(party.pubKey, payout.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s settlement address: %w", party.name, err)
+		}
+
+		var descriptor string
+		if payout.Address != "" {
+			descriptor, err = taproot.AddressDescriptor(address, s.taprootScriptBuilder.Params())
+		} else {
+			descriptor, err = taproot.KeyPathDescriptor(party.pubKey)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s descriptor: %w", party.name, err)
+		}
+
+		outputs = append(outputs, SettlementOutput{
+			Party:      party.name,
+			PubKey:     party.pubKey,
+			Address:    address,
+			Descriptor: descriptor,
+		})
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,537 @@
+// internal/contract/scheduled_close.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+// ErrScheduledCloseNotEnabled is returned when no scheduled close store is configured
+var ErrScheduledCloseNotEnabled = errors.New("scheduled cooperative closes are not enabled")
+
+// closeDustLimit is the smallest output in satoshis a close transaction pays
+const closeDustLimit = 546
+
+// ScheduledCloseConfig controls how often due cooperative closes are executed
+type ScheduledCloseConfig struct {
+	// Interval is how often due closes are checked
+	Interval time.Duration `yaml:"interval"`
+	// BatchSize is the most closes executed per interval
+	BatchSize int `yaml:"batch_size"`
+}
+
+// DefaultScheduledCloseConfig checks for due closes every minute
+var DefaultScheduledCloseConfig = ScheduledCloseConfig{
+	Interval:  time.Minute,
+	BatchSize: 20,
+}
+
+// Validate checks that the scheduler settings are usable
+func (c ScheduledCloseConfig) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("scheduled close interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("scheduled close batch size must be positive")
+	}
+	return nil
+}
+
+// CloseIntent is one party's signed agreement to close a contract
+// cooperatively at a block height or time with the given split. The
+// signature is a BIP-340 signature over taproot.CloseMessageHash.
+type CloseIntent struct {
+	PubKey       string     `json:"pub_key"`
+	CloseHeight  *int64     `json:"close_height,omitempty"`
+	CloseAt      *time.Time `json:"close_at,omitempty"`
+	BuyerAmount  int64      `json:"buyer_amount"`
+	SellerAmount int64      `json:"seller_amount"`
+	Signature    string     `json:"signature"`
+}
+
+// closeOutput is one party's payout from a close transaction and its share
+// of the contract size
+type closeOutput struct {
+	PkScript []byte
+	Share    int64
+}
+
+// WithScheduledCloseStore enables cooperative closes pre-agreed by both parties
+func (s *Service) WithScheduledCloseStore(store ScheduledCloseStore, cfg ScheduledCloseConfig) *Service {
+	s.scheduledCloseRepo = store
+	s.scheduledClose = cfg
+	return s
+}
+
+// GetScheduledClose returns the scheduled close of a contract, or nil if none was proposed
+func (s *Service) GetScheduledClose(ctx context.Context, contractID uuid.UUID) (*models.ScheduledClose, error) {
+	if s.scheduledCloseRepo == nil {
+		return nil, ErrScheduledCloseNotEnabled
+	}
+
+	if _, err := s.contractRepo.GetByID(ctx, contractID); err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	return s.scheduledCloseRepo.Get(ctx, contractID)
+}
+
+// SubmitCloseIntent records a party's signed close intent. An intent with
+// the same terms as the pending close adds the party's signature; an intent
+// with different terms replaces the pending close, discarding the other
+// party's signature until it signs the new terms.
+func (s *Service) SubmitCloseIntent(ctx context.Context, contractID uuid.UUID, intent CloseIntent) (*models.ScheduledClose, error) {
+	if s.scheduledCloseRepo == nil {
+		return nil, ErrScheduledCloseNotEnabled
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	if intent.PubKey != contract.BuyerPubKey && intent.PubKey != contract.SellerPubKey {
+		return nil, errors.New("public key is not a party to the contract")
+	}
+	isBuyer := intent.PubKey == contract.BuyerPubKey
+
+	if contract.Status != models.ContractStatusActive || contract.SetupTxID == nil {
+		return nil, errors.New("only active contracts can be closed cooperatively")
+	}
+	if contract.FinalTxID != nil {
+		return nil, errors.New("contract already has a final transaction")
+	}
+
+	height, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateCloseIntent(contract, intent, height, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var closeHeight, closeTime int64
+	if intent.CloseHeight != nil {
+		closeHeight = *intent.CloseHeight
+	} else {
+		closeTime = intent.CloseAt.Unix()
+	}
+	err = taproot.VerifyCloseSignature(
+		intent.PubKey, contractID.String(),
+		closeHeight, closeTime, intent.BuyerAmount, intent.SellerAmount,
+		intent.Signature,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := &models.ScheduledClose{
+		ContractID:   contractID,
+		CloseHeight:  intent.CloseHeight,
+		CloseAt:      intent.CloseAt,
+		BuyerAmount:  intent.BuyerAmount,
+		SellerAmount: intent.SellerAmount,
+	}
+	if terms.CloseAt != nil {
+		closeAt := time.Unix(closeTime, 0).UTC()
+		terms.CloseAt = &closeAt
+	}
+
+	existing, err := s.scheduledCloseRepo.Get(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == models.ScheduledCloseStatusExecuted {
+		return nil, errors.New("contract was already closed cooperatively")
+	}
+
+	if existing != nil && existing.Status == models.ScheduledCloseStatusPending && existing.SameTerms(terms) {
+		return s.scheduledCloseRepo.AddSignature(ctx, terms, isBuyer, intent.Signature)
+	}
+
+	signature := intent.Signature
+	if isBuyer {
+		terms.BuyerSignature = &signature
+	} else {
+		terms.SellerSignature = &signature
+	}
+	if err := s.scheduledCloseRepo.Propose(ctx, terms); err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Str("contractID", contractID.String()).
+		Bool("buyer", isBuyer).
+		Msg("Cooperative close proposed")
+
+	return terms, nil
+}
+
+// validateCloseIntent checks that an intent schedules exactly one future
+// close before the contract's own outcome and splits the contract size
+func validateCloseIntent(contract *models.Contract, intent CloseIntent, height int64, now time.Time) error {
+	if (intent.CloseHeight == nil) == (intent.CloseAt == nil) {
+		return errors.New("exactly one of close height and close time is required")
+	}
+
+	if intent.CloseHeight != nil {
+		if *intent.CloseHeight <= height {
+			return errors.New("close height must be in the future")
+		}
+		if *intent.CloseHeight >= contract.EndBlockHeight {
+			return errors.New("close height must be before the contract's end block height")
+		}
+	} else {
+		if !intent.CloseAt.After(now) {
+			return errors.New("close time must be in the future")
+		}
+		if !intent.CloseAt.Before(contract.TargetTimestamp) {
+			return errors.New("close time must be before the contract's target timestamp")
+		}
+	}
+
+	if intent.BuyerAmount < 0 || intent.SellerAmount < 0 {
+		return errors.New("close amounts cannot be negative")
+	}
+	if intent.BuyerAmount+intent.SellerAmount != contract.ContractSize {
+		return fmt.Errorf("close amounts must add up to the contract size of %d", contract.ContractSize)
+	}
+
+	return nil
+}
+
+// ExecuteScheduledCloses executes the cooperative closes that are due and
+// returns how many closed. Closes missing a signature, or whose contract is
+// no longer open to a cooperative close, lapse and leave the contract to
+// standard settlement.
+func (s *Service) ExecuteScheduledCloses(ctx context.Context) (int, error) {
+	if s.scheduledCloseRepo == nil {
+		return 0, ErrScheduledCloseNotEnabled
+	}
+
+	height, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	due, err := s.scheduledCloseRepo.ListDue(ctx, height, time.Now().UTC(), s.scheduledClose.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, sc := range due {
+		status, txID, reason := s.executeScheduledClose(ctx, sc)
+
+		var lastError *string
+		if reason != "" {
+			lastError = &reason
+			logger.Warn().
+				Str("contractID", sc.ContractID.String()).
+				Str("status", string(status)).
+				Str("reason", reason).
+				Msg("Scheduled close fell back to standard settlement")
+		}
+
+		if err := s.scheduledCloseRepo.Resolve(ctx, sc.ContractID, status, txID, lastError); err != nil {
+			logger.Error().Err(err).Str("contractID", sc.ContractID.String()).Msg("Failed to resolve scheduled close")
+			continue
+		}
+		if status == models.ScheduledCloseStatusExecuted {
+			closed++
+		}
+	}
+
+	return closed, nil
+}
+
+// executeScheduledClose closes one due contract, returning the status to
+// resolve the scheduled close with, the close transaction ID and, when the
+// close did not execute, the reason why
+func (s *Service) executeScheduledClose(
+	ctx context.Context,
+	sc *models.ScheduledClose,
+) (models.ScheduledCloseStatus, *string, string) {
+	if !sc.Signed() {
+		return models.ScheduledCloseStatusLapsed, nil, "close was not signed by both parties"
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, sc.ContractID)
+	if err != nil {
+		return models.ScheduledCloseStatusFailed, nil, fmt.Sprintf("failed to get contract: %v", err)
+	}
+	if contract.Status != models.ContractStatusActive {
+		return models.ScheduledCloseStatusLapsed, nil, "contract is no longer active"
+	}
+	if contract.FinalTxID != nil {
+		return models.ScheduledCloseStatusLapsed, nil, "contract already has a final transaction"
+	}
+
+	txRecord, err := s.closeContract(ctx, contract, sc)
+	if err != nil {
+		return models.ScheduledCloseStatusFailed, nil, err.Error()
+	}
+
+	return models.ScheduledCloseStatusExecuted, &txRecord.TransactionID, ""
+}
+
+// closeContract spends the setup outputs through their cooperative 2-of-2
+// leaf, paying each party its share at its payout address, and marks the
+// contract settled
+func (s *Service) closeContract(
+	ctx context.Context,
+	contract *models.Contract,
+	sc *models.ScheduledClose,
+) (*models.ContractTransaction, error) {
+	txs, err := s.contractRepo.GetTransactionsByContractID(ctx, contract.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	var setupTx *models.ContractTransaction
+	for _, tx := range txs {
+		if tx.TxType == "setup" {
+			setupTx = tx
+			break
+		}
+	}
+	if setupTx == nil {
+		return nil, errors.New("setup transaction not found")
+	}
+
+	setupTxBytes, err := hex.DecodeString(setupTx.TxHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode setup transaction: %w", err)
+	}
+
+	var setupMsgTx wire.MsgTx
+	if err := setupMsgTx.Deserialize(bytes.NewReader(setupTxBytes)); err != nil {
+		return nil, fmt.Errorf("failed to deserialize setup transaction: %w", err)
+	}
+
+	setupLeaves, err := s.setupLeaves(contract)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build setup leaves: %w", err)
+	}
+	leafSpends := taproot.LeafSpends(setupLeaves)
+	cooperative := leafSpends[0]
+
+	inputs, err := s.sweepInputs(ctx, contract.ID, models.InputStageSetup, &setupMsgTx, leafSpends, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range inputs {
+		inputs[i].Leaf = 0
+		inputs[i].Spend = cooperative
+	}
+
+	parties := []struct {
+		pubKey string
+		keyID  *uuid.UUID
+		share  int64
+	}{
+		{contract.BuyerPubKey, contract.BuyerKeyID, sc.BuyerAmount},
+		{contract.SellerPubKey, contract.SellerKeyID, sc.SellerAmount},
+	}
+
+	outputs := make([]closeOutput, 0, len(parties))
+	payouts := make([]settlementPayout, 0, len(parties))
+	for _, party := range parties {
+		if party.share == 0 {
+			continue
+		}
+
+		payout, err := s.resolvePayoutAddress(ctx, contract.ID, party.pubKey, party.keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		address, err := s.taprootScriptBuilder.BuildSettlementScript(party.pubKey, payout.Address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build close output: %w", err)
+		}
+		addr, err := btcutil.DecodeAddress(address, s.taprootScriptBuilder.Params())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode close address: %w", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create close output script: %w", err)
+		}
+
+		outputs = append(outputs, closeOutput{PkScript: pkScript, Share: party.share})
+		payouts = append(payouts, payout)
+	}
+
+	feeRate := s.estimateFeeRate(ctx)
+
+	tx, err := buildCloseTx(inputs, outputs, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+	txHex := hex.EncodeToString(buf.Bytes())
+
+	txRecord := &models.ContractTransaction{
+		ID:            uuid.New(),
+		ContractID:    contract.ID,
+		TransactionID: tx.TxHash().String(),
+		TxType:        "close",
+		TxHex:         txHex,
+		Confirmed:     false,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	err = s.contractRepo.ExecuteInTransaction(ctx, func(_ *sqlx.Tx) error {
+		if _, err := s.transition(ctx, contract, s.payoutStatus()); err != nil {
+			return err
+		}
+		contract.SettlementTxID = &txRecord.TransactionID
+		contract.UpdatedAt = time.Now().UTC()
+
+		if err := s.contractRepo.AddTransaction(ctx, txRecord); err != nil {
+			return fmt.Errorf("failed to add transaction: %w", err)
+		}
+		if err := s.contractRepo.Update(ctx, contract); err != nil {
+			return fmt.Errorf("failed to update contract: %w", err)
+		}
+		if err := s.trackSettlement(ctx, txRecord, nil); err != nil {
+			return fmt.Errorf("failed to track close: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to process close transaction: %w", err)
+	}
+	s.adjustOpenInterest(ctx, contract, -1)
+	s.entered(ctx, contract, models.ContractStatusActive, "cooperative close")
+
+	if err := s.broadcastSettlement(ctx, txRecord); err != nil {
+		// The transaction is stored so it can be broadcast manually
+		logger.Error().Err(err).
+			Str("contractID", contract.ID.String()).
+			Str("txid", txRecord.TransactionID).
+			Msg("Failed to broadcast close transaction")
+	}
+
+	s.releaseDeferral(ctx, contract.ID)
+	for _, payout := range payouts {
+		s.recordPayoutAddress(ctx, payout)
+	}
+	if contract.Status == models.ContractStatusPendingSettlement {
+		s.publishStatus(contract, models.ContractStatusActive)
+	} else {
+		s.publishSettled(contract, models.ContractStatusActive, txRecord, nil)
+	}
+
+	logger.Info().
+		Str("contractID", contract.ID.String()).
+		Str("txid", txRecord.TransactionID).
+		Int64("buyerAmount", sc.BuyerAmount).
+		Int64("sellerAmount", sc.SellerAmount).
+		Msg("Contract closed cooperatively")
+
+	return txRecord, nil
+}
+
+// buildCloseTx spends every input to one output per party. The total input
+// value less the fee is split in proportion to each party's share, with the
+// rounding remainder going to the last output.
+func buildCloseTx(inputs []sweepInput, outputs []closeOutput, feeRate float64) (*wire.MsgTx, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no inputs to close")
+	}
+	if len(outputs) == 0 {
+		return nil, errors.New("no outputs to close to")
+	}
+
+	tx := wire.NewMsgTx(2) // Version 2 transaction
+
+	var total int64
+	spends := make([]taproot.Spend, 0, len(inputs))
+	for _, in := range inputs {
+		outPoint := in.OutPoint
+		tx.AddTxIn(wire.NewTxIn(&outPoint, nil, nil))
+		total += in.Value
+		spends = append(spends, in.Spend)
+	}
+
+	var shares int64
+	scriptSizes := make([]int, 0, len(outputs))
+	for _, out := range outputs {
+		shares += out.Share
+		scriptSizes = append(scriptSizes, len(out.PkScript))
+	}
+	if shares <= 0 {
+		return nil, errors.New("close shares must be positive")
+	}
+
+	net := total - taproot.Fee(taproot.TxWeight(spends, scriptSizes), feeRate)
+	if net <= 0 {
+		return nil, fmt.Errorf("fees exceed input value")
+	}
+
+	remaining := net
+	for i, out := range outputs {
+		value := remaining
+		if i < len(outputs)-1 {
+			portion := new(big.Int).Mul(big.NewInt(net), big.NewInt(out.Share))
+			value = portion.Quo(portion, big.NewInt(shares)).Int64()
+		}
+		if value < closeDustLimit {
+			return nil, fmt.Errorf("close output of %d sats is below the dust limit", value)
+		}
+
+		tx.AddTxOut(wire.NewTxOut(value, out.PkScript))
+		remaining -= value
+	}
+
+	return tx, nil
+}
+
+// StartScheduledCloses periodically executes cooperative closes that are due
+func (s *Service) StartScheduledCloses(ctx context.Context) {
+	if s.scheduledCloseRepo == nil {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.scheduledClose.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				closed, err := s.ExecuteScheduledCloses(ctx)
+				if err != nil {
+					logger.Error().Err(err).Msg("Failed to execute scheduled closes")
+					continue
+				}
+				if closed > 0 {
+					logger.Info().Int("closed", closed).Msg("Executed scheduled closes")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+// internal/contract/scheduled_close_test.go
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+func TestBuildCloseTx(t *testing.T) {
+	pkScript := make([]byte, taproot.P2TROutputScriptSize)
+	inputs := []sweepInput{
+		{OutPoint: wire.OutPoint{Hash: chainhash.Hash{1}}, Value: 60_000, Spend: taproot.KeyPathSpend()},
+		{OutPoint: wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}, Value: 40_000, Spend: taproot.KeyPathSpend()},
+	}
+	fee := taproot.Fee(taproot.TxWeight(
+		[]taproot.Spend{taproot.KeyPathSpend(), taproot.KeyPathSpend()},
+		[]int{len(pkScript), len(pkScript)},
+	), 1)
+
+	t.Run("splits the value after fees by share", func(t *testing.T) {
+		tx, err := buildCloseTx(inputs, []closeOutput{
+			{PkScript: pkScript, Share: 3},
+			{PkScript: pkScript, Share: 1},
+		}, 1)
+		assert.NoError(t, err)
+		assert.Len(t, tx.TxIn, 2)
+		assert.Len(t, tx.TxOut, 2)
+
+		net := 100_000 - fee
+		assert.Equal(t, net*3/4, tx.TxOut[0].Value)
+		assert.Equal(t, net, tx.TxOut[0].Value+tx.TxOut[1].Value)
+	})
+
+	t.Run("single output takes everything", func(t *testing.T) {
+		tx, err := buildCloseTx(inputs, []closeOutput{{PkScript: pkScript, Share: 5}}, 1)
+		assert.NoError(t, err)
+		assert.Len(t, tx.TxOut, 1)
+		assert.Greater(t, tx.TxOut[0].Value, 100_000-fee)
+	})
+
+	t.Run("dust output is rejected", func(t *testing.T) {
+		_, err := buildCloseTx(inputs, []closeOutput{
+			{PkScript: pkScript, Share: 99_999},
+			{PkScript: pkScript, Share: 1},
+		}, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("no inputs or outputs", func(t *testing.T) {
+		_, err := buildCloseTx(nil, []closeOutput{{PkScript: pkScript, Share: 1}}, 1)
+		assert.Error(t, err)
+		_, err = buildCloseTx(inputs, nil, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateCloseIntent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	contract := &models.Contract{
+		EndBlockHeight:  850_000,
+		TargetTimestamp: now.Add(7 * 24 * time.Hour),
+		ContractSize:    100_000,
+	}
+	height := func(h int64) *int64 { return &h }
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	tests := []struct {
+		name   string
+		intent CloseIntent
+		valid  bool
+	}{
+		{"at height", CloseIntent{CloseHeight: height(849_500), BuyerAmount: 60_000, SellerAmount: 40_000}, true},
+		{"at time", CloseIntent{CloseAt: at(24 * time.Hour), BuyerAmount: 100_000}, true},
+		{"no schedule", CloseIntent{BuyerAmount: 60_000, SellerAmount: 40_000}, false},
+		{"both schedules", CloseIntent{CloseHeight: height(849_500), CloseAt: at(time.Hour), BuyerAmount: 100_000}, false},
+		{"past height", CloseIntent{CloseHeight: height(849_000), BuyerAmount: 100_000}, false},
+		{"at end height", CloseIntent{CloseHeight: height(850_000), BuyerAmount: 100_000}, false},
+		{"past time", CloseIntent{CloseAt: at(-time.Minute), BuyerAmount: 100_000}, false},
+		{"after target", CloseIntent{CloseAt: at(8 * 24 * time.Hour), BuyerAmount: 100_000}, false},
+		{"split short of size", CloseIntent{CloseHeight: height(849_500), BuyerAmount: 60_000, SellerAmount: 30_000}, false},
+		{"negative amount", CloseIntent{CloseHeight: height(849_500), BuyerAmount: 110_000, SellerAmount: -10_000}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCloseIntent(contract, tt.intent, 849_000, now)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
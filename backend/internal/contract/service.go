@@ -7,39 +7,80 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sync/atomic"
 	"time"
 
+	arkv1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
 	"github.com/btcsuite/btcd/btcutil"
-	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
 	
 	"hashhedge/internal/contract/hashrate"
-	"hashhedge/internal/db"
+	"hashhedge/internal/deadlines"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
 	"hashhedge/internal/models"
+	"hashhedge/internal/tracing"
 	"hashhedge/pkg/bitcoin"
 	"hashhedge/pkg/taproot"
-    "hashhedge/pkg/ark"
+)
+
+var (
+	logger = logging.Component(logging.Contract)
+	tracer = tracing.Tracer(logging.Contract)
 )
 
 // Service provides methods for managing contracts
 type Service struct {
-	contractRepo        *db.ContractRepository
-	hashRateCalculator  *hashrate.HashRateCalculator
-	bitcoinClient       *bitcoin.Client
+	contractRepo         ContractStore
+	hashRateCalculator   *hashrate.HashRateCalculator
+	bitcoinClient        ChainBackend
 	taprootScriptBuilder *taproot.ScriptBuilder
+	arkClient            ArkService
+	emergencyExitReady   atomic.Bool
+	fundingRepo          FundingStore
+	inputRepo            InputStore
+	utxoSource           UTXOSource
+	vtxoRepo             VTXOStore
+	userKeys             UserKeyStore
+	publisher            ChannelPublisher
+	settlementObserver   SettlementObserver
+	feePolicy            FeePolicyConfig
+	feeEstimator         FeeEstimator
+	deferralRepo         DeferralStore
+	deferralObserver     DeferralObserver
+	payoutRepo           PayoutStore
+	openInterestRepo     OpenInterestStore
+	scheduledCloseRepo   ScheduledCloseStore
+	scheduledClose       ScheduledCloseConfig
+	evidenceRepo         EvidenceStore
+	oracleRepo           OracleEventStore
+	oracle               OracleConfig
+	collateralPolicy     CollateralPolicy
+	events               EventPublisher
+	aspStatus            atomic.Int32
+	confirmationRepo     ConfirmationStore
+	confirmationSource   ConfirmationSource
+	confirmations        ConfirmationConfig
+	settlementRepo       SettlementBroadcastStore
+	settlementTracking   SettlementTrackingConfig
+	broadcastCheck       BroadcastCheckConfig
+	exitMonitor          ExitMonitorConfig
+	exitSwitch           *deadManSwitch
+	onChainOnly          atomic.Bool
+	states               stateMachine
 }
 
 // NewService creates a new contract service
 func NewService(
-    contractRepo *db.ContractRepository,
+    contractRepo ContractStore,
     hashRateCalculator *hashrate.HashRateCalculator,
-    bitcoinClient *bitcoin.Client,
+    bitcoinClient ChainBackend,
     taprootScriptBuilder *taproot.ScriptBuilder,
-    arkClient *ark.Client,
+    arkClient ArkService,
 ) *Service {
     return &Service{
         contractRepo:       contractRepo,
@@ -47,10 +88,30 @@ func NewService(
         bitcoinClient:      bitcoinClient,
         taprootScriptBuilder: taprootScriptBuilder,
         arkClient:         arkClient,
-        emergencyExitReady: false,
     }
 }
 
+// WithSettlementObserver sets the observer notified after a contract settles
+func (s *Service) WithSettlementObserver(observer SettlementObserver) *Service {
+	s.settlementObserver = observer
+	return s
+}
+
+// WithCollateralPolicy lets margined contracts be set up with less than
+// their full size, the operator funding the remainder
+func (s *Service) WithCollateralPolicy(policy CollateralPolicy) *Service {
+	s.collateralPolicy = policy
+	return s
+}
+
+// requiredCollateral returns the amount that must fund a contract's setup
+func (s *Service) requiredCollateral(ctx context.Context, contract *models.Contract) (int64, error) {
+	if s.collateralPolicy == nil {
+		return contract.ContractSize, nil
+	}
+	return s.collateralPolicy.RequiredCollateral(ctx, contract)
+}
+
 
 // CreateContract creates a new contract
 func (s *Service) CreateContract(
@@ -62,9 +123,13 @@ func (s *Service) CreateContract(
 	targetTimestamp time.Time,
 	contractSize int64,
 	premium int64,
+	notional models.Notional,
 	buyerPubKey string,
 	sellerPubKey string,
-) (*models.Contract, error) {
+) (_ *models.Contract, err error) {
+	ctx, span := tracer.Start(ctx, "contract.CreateContract")
+	defer func() { tracing.End(span, err) }()
+
 	// Create a new contract
 	contract := &models.Contract{
 		ID:               uuid.New(),
@@ -73,8 +138,10 @@ func (s *Service) CreateContract(
 		StartBlockHeight: startBlockHeight,
 		EndBlockHeight:   endBlockHeight,
 		TargetTimestamp:  targetTimestamp,
+		TimeMode:         models.ContractTimeModeAbsolute,
 		ContractSize:     contractSize,
 		Premium:          premium,
+		Notional:         notional,
 		BuyerPubKey:      buyerPubKey,
 		SellerPubKey:     sellerPubKey,
 		Status:           models.ContractStatusCreated,
@@ -82,6 +149,7 @@ func (s *Service) CreateContract(
 		UpdatedAt:        time.Now().UTC(),
 		ExpiresAt:        targetTimestamp.Add(24 * time.Hour), // Expire 24 hours after target timestamp
 	}
+	span.SetAttributes(attribute.String("contract.id", contract.ID.String()))
 
 	// Validate the contract
 	if err := contract.Validate(); err != nil {
@@ -89,19 +157,37 @@ func (s *Service) CreateContract(
 	}
 
 	// Save the contract to the database
-	err := s.contractRepo.Create(ctx, contract)
+	err = s.contractRepo.Create(ctx, contract)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create contract: %w", err)
 	}
+	s.entered(ctx, contract, "", "created")
+	s.publishStatus(contract, "")
 
 	return contract, nil
 }
 
+// RecordPartyKeys records which registered user keys were used for each side of a contract
+func (s *Service) RecordPartyKeys(ctx context.Context, contract *models.Contract, buyerKeyID, sellerKeyID *uuid.UUID) error {
+	if buyerKeyID == nil && sellerKeyID == nil {
+		return nil
+	}
+
+	contract.BuyerKeyID = buyerKeyID
+	contract.SellerKeyID = sellerKeyID
+
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		return fmt.Errorf("failed to record party keys: %w", err)
+	}
+
+	return nil
+}
+
 
 // New method: prepareEmergencyExitPath creates emergency exit transactions for all active contracts
 func (s *Service) PrepareEmergencyExitPath(ctx context.Context) error {
     // Skip if already prepared
-    if s.emergencyExitReady {
+    if s.emergencyExitReady.Load() {
         return nil
     }
 
@@ -111,11 +197,11 @@ func (s *Service) PrepareEmergencyExitPath(ctx context.Context) error {
         return fmt.Errorf("failed to list active contracts for emergency exit preparation: %w", err)
     }
 
-    log.Info().Int("contract_count", len(contracts)).Msg("Preparing emergency exit paths")
+    logger.Info().Int("contract_count", len(contracts)).Msg("Preparing emergency exit paths")
 
     for _, contract := range contracts {
         if err := s.prepareContractEmergencyExit(ctx, contract); err != nil {
-            log.Error().
+            logger.Error().
                 Err(err).
                 Str("contract_id", contract.ID.String()).
                 Msg("Failed to prepare emergency exit for contract")
@@ -124,8 +210,8 @@ func (s *Service) PrepareEmergencyExitPath(ctx context.Context) error {
         }
     }
 
-    s.emergencyExitReady = true
-    log.Info().Msg("Emergency exit paths prepared successfully")
+    s.emergencyExitReady.Store(true)
+    logger.Info().Msg("Emergency exit paths prepared successfully")
     return nil
 }
 
@@ -149,16 +235,18 @@ func (s *Service) prepareContractEmergencyExit(ctx context.Context, contract *mo
     exitScript, err := s.taprootScriptBuilder.BuildExitPathScript(
         contract.BuyerPubKey,
         contract.SellerPubKey,
-        144, // 1 day timelock (in blocks)
+        deadlines.ExitTimelockBlocks,
     )
     if err != nil {
         return fmt.Errorf("failed to build emergency exit script: %w", err)
     }
 
-    // Get VTXO information from ARK
-    // In practice, you'd need to know which VTXO corresponds to this contract
-    // This would typically be stored in the contract metadata
-    vtxoID := contract.ID.String() // Simplified; in reality, you'd need the actual VTXO ID
+    // Exit paths spend the VTXO holding the contract's funds
+    vtxo, err := s.ContractVTXO(ctx, contract.ID)
+    if err != nil {
+        return err
+    }
+    vtxoID := vtxo.VTXOID
 
     // For each participant, create an exit path
     for _, participant := range []string{"buyer", "seller"} {
@@ -171,19 +259,12 @@ func (s *Service) prepareContractEmergencyExit(ctx context.Context, contract *mo
             pubKey = contract.SellerPubKey
         }
 
-        // Create P2PKH address from public key for on-chain exit
-        pkBytes, err := hex.DecodeString(pubKey)
+        // Exit to a key-path P2TR output of the participant's contract key
+        destinationAddress, err = taproot.KeyPathAddress(pubKey, s.taprootScriptBuilder.Params())
         if err != nil {
             return fmt.Errorf("invalid public key for %s: %w", participant, err)
         }
 
-        pkHash := btcutil.Hash160(pkBytes)
-        addr, err := btcutil.NewAddressPubKeyHash(pkHash, &chaincfg.MainNetParams)
-        if err != nil {
-            return fmt.Errorf("failed to create address for %s: %w", participant, err)
-        }
-        destinationAddress = addr.String()
-
         // Request exit path from ASP
         exitResponse, err := s.arkClient.GetExitPath(
             ctx,
@@ -192,7 +273,7 @@ func (s *Service) prepareContractEmergencyExit(ctx context.Context, contract *mo
             5, // fee rate in sats/vbyte
         )
         if err != nil {
-            log.Warn().
+            logger.Warn().
                 Err(err).
                 Str("contract_id", contract.ID.String()).
                 Str("participant", participant).
@@ -220,7 +301,7 @@ func (s *Service) prepareContractEmergencyExit(ctx context.Context, contract *mo
             return fmt.Errorf("failed to save emergency exit transaction: %w", err)
         }
 
-        log.Info().
+        logger.Info().
             Str("contract_id", contract.ID.String()).
             Str("participant", participant).
             Str("tx_id", exitResponse.GetTxid()).
@@ -240,26 +321,24 @@ func (s *Service) GetContract(ctx context.Context, id uuid.UUID) (*models.Contra
 	return contract, nil
 }
 
-// parseTransactionInput parses and validates a transaction input
-func (s *Service) parseTransactionInput(ctx context.Context, txHex string) (*wire.MsgTx, error) {
-	// Decode transaction hex
-	txBytes, err := hex.DecodeString(txHex)
+// CurrentBlockHeight returns the height of the current best block
+func (s *Service) CurrentBlockHeight(ctx context.Context) (int64, error) {
+	bestBlockHash, err := s.bitcoinClient.GetBestBlockHash(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode transaction hex: %w", err)
+		return 0, fmt.Errorf("failed to get best block hash: %w", err)
 	}
 
-	// Parse the transaction
-	var tx wire.MsgTx
-	if err := tx.Deserialize(bytes.NewReader(txBytes)); err != nil {
-		return nil, fmt.Errorf("failed to deserialize transaction: %w", err)
+	bestBlock, err := s.bitcoinClient.GetBlock(ctx, bestBlockHash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get best block: %w", err)
 	}
 
-	// Basic validation
-	if len(tx.TxOut) == 0 {
-		return nil, errors.New("transaction has no outputs")
-	}
+	return bestBlock.Height, nil
+}
 
-	return &tx, nil
+// parseTransactionInput parses and validates a transaction input
+func (s *Service) parseTransactionInput(ctx context.Context, txHex string) (*wire.MsgTx, error) {
+	return bitcoin.ParseTransactionHex(txHex, bitcoin.DefaultParseLimits)
 }
 // Modified GenerateSetupTransaction to integrate with ASP
 func (s *Service) GenerateSetupTransaction(
@@ -278,26 +357,28 @@ func (s *Service) GenerateSetupTransaction(
         return nil, fmt.Errorf("contract is not in CREATED state")
     }
 
-    if amount < contract.ContractSize {
+    required, err := s.requiredCollateral(ctx, contract)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get required collateral: %w", err)
+    }
+    if amount < required {
         return nil, fmt.Errorf("insufficient amount for contract size: got %d, need %d", 
-            amount, contract.ContractSize)
+            amount, required)
     }
 
     // Create taproot script for the contract
-    setupScript, err := s.taprootScriptBuilder.BuildSetupScript(
-        contract.BuyerPubKey,
-        contract.SellerPubKey,
-        contract.StartBlockHeight,
-        contract.EndBlockHeight,
-        contract.TargetTimestamp,
-        contract.ContractType == models.ContractTypeCall,
-    )
+    setupScript, err := s.buildSetupScript(contract)
     if err != nil {
         return nil, fmt.Errorf("failed to build setup script: %w", err)
     }
     
+    // Relative contracts are scheduled from the setup's confirmation
+    if err := s.estimateAnchor(ctx, contract); err != nil {
+        return nil, err
+    }
+    
     // Check if ASP is available
-    aspAvailable, _ := s.arkClient.CheckASPStatus(ctx)
+    aspAvailable := s.aspAvailable(ctx)
     
     if aspAvailable {
         // Use ARK for off-chain transaction
@@ -331,7 +412,9 @@ func (s *Service) GenerateSetupTransaction(
         // Use transactions to update contract state and save transaction atomically
         err = s.contractRepo.ExecuteInTransaction(ctx, func(tx *sqlx.Tx) error {
             // Update contract status to active
-            contract.Status = models.ContractStatusActive
+            if _, err := s.transition(ctx, contract, models.ContractStatusActive); err != nil {
+                return err
+            }
             contract.SetupTxID = &txRecord.TransactionID
             contract.UpdatedAt = time.Now().UTC()
             
@@ -340,6 +423,11 @@ func (s *Service) GenerateSetupTransaction(
                 return fmt.Errorf("failed to add transaction: %w", err)
             }
             
+            // Record the VTXO the round creates for the setup output
+            if err := s.recordRoundVTXO(ctx, contract, response.GetRoundId(), 0, setupScript); err != nil {
+                return err
+            }
+            
             // Update contract
             if err := s.contractRepo.Update(ctx, contract); err != nil {
                 return fmt.Errorf("failed to update contract status: %w", err)
@@ -352,10 +440,17 @@ func (s *Service) GenerateSetupTransaction(
             return nil, fmt.Errorf("failed to process setup transaction: %w", err)
         }
         
+        // The new contract has no emergency exit yet
+        s.emergencyExitReady.Store(false)
+        
+        s.adjustOpenInterest(ctx, contract, 1)
+        s.entered(ctx, contract, models.ContractStatusCreated, "setup registered with the ASP")
+        s.publishStatus(contract, models.ContractStatusCreated)
+        
         return txRecord, nil
     } else {
         // Fallback to on-chain transaction if ASP is unavailable
-        log.Warn().
+        logger.Warn().
             Str("contract_id", contractID.String()).
             Msg("ASP unavailable, falling back to on-chain setup transaction")
             
@@ -375,7 +470,9 @@ func (s *Service) GenerateSetupTransaction(
         }
         
         // Update contract status
-        contract.Status = models.ContractStatusActive
+        if _, err := s.transition(ctx, contract, models.ContractStatusActive); err != nil {
+            return nil, err
+        }
         contract.SetupTxID = &txRecord.TransactionID
         contract.UpdatedAt = time.Now().UTC()
         
@@ -387,6 +484,9 @@ func (s *Service) GenerateSetupTransaction(
         if err := s.contractRepo.Update(ctx, contract); err != nil {
             return nil, fmt.Errorf("failed to update contract: %w", err)
         }
+        s.adjustOpenInterest(ctx, contract, 1)
+        s.entered(ctx, contract, models.ContractStatusCreated, "setup on chain")
+        s.publishStatus(contract, models.ContractStatusCreated)
         
         return txRecord, nil
     }
@@ -437,28 +537,19 @@ func (s *Service) GenerateFinalTransaction(
 		return nil, fmt.Errorf("failed to deserialize setup transaction: %w", err)
 	}
 
-	// Create taproot script for the final transaction
-	finalScript, err := s.taprootScriptBuilder.BuildFinalScript(
-		contract.BuyerPubKey,
-		contract.SellerPubKey,
-		contract.EndBlockHeight,
-		contract.TargetTimestamp,
-		contract.ContractType == models.ContractTypeCall,
-	)
+	// Create taproot script for the final transaction, committing to the
+	// outcomes of the contract's oracle event if it has one
+	event, err := s.contractOracleEvent(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	finalScript, err := s.buildFinalScript(contract, event)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build final script: %w", err)
 	}
 
-	// Create a new transaction
-	tx := wire.NewMsgTx(2) // Version 2 transaction
-	
-	// Add input from setup transaction
-	outPoint := wire.NewOutPoint(&setupMsgTx.TxHash(), 0) // Assuming contract output is first
-	txIn := wire.NewTxIn(outPoint, nil, nil)
-	tx.AddTxIn(txIn)
-	
-	// Add output for final transaction
-	finalAddr, err := btcutil.DecodeAddress(finalScript, &chaincfg.MainNetParams)
+	// Create output for final transaction
+	finalAddr, err := btcutil.DecodeAddress(finalScript, s.taprootScriptBuilder.Params())
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode final script address: %w", err)
 	}
@@ -468,21 +559,48 @@ func (s *Service) GenerateFinalTransaction(
 		return nil, fmt.Errorf("failed to create final output script: %w", err)
 	}
 	
-	// Calculate fee for the transaction
-	feeRate := float64(5) // sats per byte - in production use proper fee estimation
-	estimatedFee, err := s.bitcoinClient.EstimateFee(ctx, 1, 1, feeRate)
+	// The setup outputs are spent through one of their script paths. Inputs
+	// without a recorded leaf are sized for the heaviest one.
+	feeRate := s.estimateFeeRate(ctx)
+	setupLeaves, err := s.setupLeaves(contract)
 	if err != nil {
-		return nil, fmt.Errorf("failed to estimate fee: %w", err)
+		return nil, fmt.Errorf("failed to build setup leaves: %w", err)
+	}
+	leafSpends := taproot.LeafSpends(setupLeaves)
+	setupSpender, err := taproot.NewSpendBuilder(contract.BuyerPubKey, setupLeaves)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build setup script tree: %w", err)
+	}
+	
+	// Inputs without a recorded leaf are spent through the high hash rate
+	// path once the end block height is reached, and the low hash rate path
+	// otherwise, whose lock holds the transaction until the target time
+	height, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expiryLeaf := 2 // Low hash rate path
+	if height >= contract.EndBlockHeight {
+		expiryLeaf = 1 // High hash rate path
+	}
+	
+	// Sweep every setup input, or the setup transaction's contract output
+	// when the contract was funded in one piece
+	inputs, err := s.sweepInputs(ctx, contractID, models.InputStageSetup, &setupMsgTx, leafSpends, expiryLeaf)
+	if err != nil {
+		return nil, err
 	}
 	
-	// The output value is slightly less than input to account for fees
-	outputValue := setupMsgTx.TxOut[0].Value - estimatedFee
-	if outputValue < 0 {
-		return nil, fmt.Errorf("fees exceed input value")
+	tx, err := buildSweepTx(inputs, finalScriptPubKey, feeRate)
+	if err != nil {
+		return nil, err
 	}
+	outputValue := tx.TxOut[0].Value
 	
-	finalOutput := wire.NewTxOut(outputValue, finalScriptPubKey)
-	tx.AddTxOut(finalOutput)
+	spendPSBT, err := prepareSpends(tx, inputs, setupSpender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare setup spends: %w", err)
+	}
 	
 	// Serialize the final transaction
 	var buf bytes.Buffer
@@ -502,6 +620,7 @@ func (s *Service) GenerateFinalTransaction(
 			TransactionID: txid,
 			TxType:        "final",
 			TxHex:         txHex,
+			SpendPSBT:     &spendPSBT,
 			Confirmed:     false,
 			CreatedAt:     time.Now().UTC(),
 		}
@@ -520,6 +639,21 @@ func (s *Service) GenerateFinalTransaction(
 			return fmt.Errorf("failed to add transaction: %w", err)
 		}
 		
+		// The final output is the single input of the settlement transaction
+		if s.inputRepo != nil {
+			finalInput := &models.ContractInput{
+				ContractID: contractID,
+				Stage:      models.InputStageFinal,
+				Source:     models.InputSourceUTXO,
+				TxID:       txid,
+				Vout:       0,
+				Value:      outputValue,
+			}
+			if err := s.inputRepo.AddInput(ctx, finalInput); err != nil {
+				return fmt.Errorf("failed to record final input: %w", err)
+			}
+		}
+		
 		// Update contract
 		if err := s.contractRepo.Update(ctx, contract); err != nil {
 			return fmt.Errorf("failed to update contract: %w", err)
@@ -554,7 +688,9 @@ func (s *Service) GenerateFinalTransaction(
 }
 
 
-// SettleContract settles the contract based on the actual hash rate
+// SettleContract settles the contract based on the actual hash rate. With
+// settlement tracking the contract is left PENDING_SETTLEMENT until its
+// payout confirms.
 func (s *Service) SettleContract(
 	ctx context.Context,
 	contractID uuid.UUID,
@@ -580,6 +716,21 @@ func (s *Service) SettleContract(
 		return nil, false, fmt.Errorf("contract cannot be settled: %s", reason)
 	}
 
+	// Contracts settling on an oracle event pay out on its attestation alone
+	event, err := s.contractOracleEvent(ctx, contractID)
+	if err != nil {
+		return nil, false, err
+	}
+	if event != nil && !event.Attested() {
+		return nil, false, ErrAttestationRequired
+	}
+
+	// Hold back non-urgent settlements while chain fees are high
+	feeRate, err := s.settlementFeeRate(ctx, contract)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Get the current blockchain state
 	bestBlockHash, err := s.bitcoinClient.GetBestBlockHash(ctx)
 	if err != nil {
@@ -604,13 +755,19 @@ func (s *Service) SettleContract(
 		// For PUT options, this means low hash rate, so buyer wins
 		buyerWins = contract.ContractType == models.ContractTypePut
 	}
+	if event != nil {
+		buyerWins = attestedBuyerWins(*event.Outcome, contract.ContractType)
+	}
 
 	// Determine winner's public key
 	var winnerPubKey string
+	var winnerKeyID *uuid.UUID
 	if buyerWins {
 		winnerPubKey = contract.BuyerPubKey
+		winnerKeyID = contract.BuyerKeyID
 	} else {
 		winnerPubKey = contract.SellerPubKey
+		winnerKeyID = contract.SellerKeyID
 	}
 
 	// We need to get the final transaction
@@ -654,24 +811,23 @@ func (s *Service) SettleContract(
 		return nil, false, fmt.Errorf("failed to deserialize final transaction: %w", err)
 	}
 
+	// Pay the winner's chosen payout address, if any
+	payout, err := s.resolvePayoutAddress(ctx, contract.ID, winnerPubKey, winnerKeyID)
+	if err != nil {
+		return nil, false, err
+	}
+
 	// Create settlement script
 	settlementScript, err := s.taprootScriptBuilder.BuildSettlementScript(
 		winnerPubKey,
+		payout.Address,
 	)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to build settlement script: %w", err)
 	}
 
-	// Create a new transaction
-	tx := wire.NewMsgTx(2) // Version 2 transaction
-	
-	// Add input from final transaction
-	outPoint := wire.NewOutPoint(&finalMsgTx.TxHash(), 0) // Assuming contract output is first
-	txIn := wire.NewTxIn(outPoint, nil, nil)
-	tx.AddTxIn(txIn)
-	
-	// Add output to winner
-	settlementAddr, err := btcutil.DecodeAddress(settlementScript, &chaincfg.MainNetParams)
+	// Create output to winner
+	settlementAddr, err := btcutil.DecodeAddress(settlementScript, s.taprootScriptBuilder.Params())
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to decode settlement address: %w", err)
 	}
@@ -681,22 +837,44 @@ func (s *Service) SettleContract(
 		return nil, false, fmt.Errorf("failed to create settlement output script: %w", err)
 	}
 	
-	// Calculate fee for the transaction
-	feeRate := float64(5) // sats per byte - in production use proper fee estimation
-	estimatedFee, err := s.bitcoinClient.EstimateFee(ctx, 1, 1, feeRate)
+	// The winner spends the final outputs through the leaf of the outcome
+	// that was reached, or that the oracle attested
+	finalLeaves, err := s.finalLeaves(contract, event)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build final leaves: %w", err)
+	}
+	leafSpends := taproot.LeafSpends(finalLeaves)
+	finalSpender, err := taproot.NewSpendBuilder(contract.BuyerPubKey, finalLeaves)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build final script tree: %w", err)
+	}
+	leaf := 1 // Low hash rate path
+	if bestBlock.Height >= contract.EndBlockHeight {
+		leaf = 0 // High hash rate path
+	}
+	if event != nil {
+		leaf = 4 // Oracle attested low hash rate path
+		if *event.Outcome == string(taproot.OracleOutcomeHigh) {
+			leaf = 3 // Oracle attested high hash rate path
+		}
+	}
+	
+	// Sweep every final input into a single payout
+	inputs, err := s.sweepInputs(ctx, contractID, models.InputStageFinal, &finalMsgTx, leafSpends, leaf)
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to estimate fee: %w", err)
+		return nil, false, err
 	}
 	
-	// The output value is slightly less than input to account for fees
-	inputValue := finalMsgTx.TxOut[0].Value
-	outputValue := inputValue - estimatedFee
-	if outputValue < 0 {
-		return nil, false, fmt.Errorf("fees exceed input value")
+	tx, err := buildSweepTx(inputs, settlementScriptPubKey, feeRate)
+	if err != nil {
+		return nil, false, err
 	}
 	
-	settlementOutput := wire.NewTxOut(outputValue, settlementScriptPubKey)
-	tx.AddTxOut(settlementOutput)
+	// Every input is spent through the winner's leaf
+	spendPSBT, err := prepareSpends(tx, inputs, finalSpender)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to prepare final spends: %w", err)
+	}
 	
 	// Serialize the settlement transaction
 	var buf bytes.Buffer
@@ -716,12 +894,15 @@ func (s *Service) SettleContract(
 			TransactionID: txid,
 			TxType:        "settlement",
 			TxHex:         txHex,
+			SpendPSBT:     &spendPSBT,
 			Confirmed:     false,
 			CreatedAt:     time.Now().UTC(),
 		}
 
 		// Update contract status and set settlement tx ID
-		contract.Status = models.ContractStatusSettled
+		if _, err := s.transition(ctx, contract, s.payoutStatus()); err != nil {
+			return err
+		}
 		contract.SettlementTxID = &txRecord.TransactionID
 		contract.UpdatedAt = time.Now().UTC()
 		
@@ -735,12 +916,19 @@ func (s *Service) SettleContract(
 			return fmt.Errorf("failed to update contract: %w", err)
 		}
 		
+		// Follow the payout until it confirms
+		if err := s.trackSettlement(ctx, txRecord, &buyerWins); err != nil {
+			return fmt.Errorf("failed to track settlement: %w", err)
+		}
+		
 		return nil
 	})
 	
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to process settlement transaction: %w", err)
 	}
+	s.adjustOpenInterest(ctx, contract, -1)
+	s.entered(ctx, contract, models.ContractStatusActive, "settlement built")
 
 	// Get the saved transaction to return
 	transactions, err := s.contractRepo.GetTransactionsByContractID(ctx, contractID)
@@ -761,16 +949,33 @@ func (s *Service) SettleContract(
 	}
 	
 	// Try to broadcast the transaction
-	_, err = s.bitcoinClient.BroadcastTransactionWithRetry(ctx, txHex)
-	if err != nil {
-		// Just log the error - we still return the transaction
-		// so the user can broadcast it manually if needed
-		log.Error().Err(err).
+	if err := s.broadcastSettlement(ctx, settlementTx); err != nil {
+		// Just log the error - we still return the transaction so the
+		// user can broadcast it manually if needed, and a tracked
+		// settlement is rebroadcast until it confirms
+		logger.Error().Err(err).
 			Str("contractID", contractID.String()).
 			Str("txid", txid).
 			Msg("Failed to broadcast settlement transaction")
 	}
 
+	s.releaseDeferral(ctx, contractID)
+	s.recordPayoutAddress(ctx, payout)
+	s.recordSettlementEvidence(ctx, contract, bestBlock, buyerWins, txid)
+
+	// A tracked settlement is announced once its payout confirms
+	if contract.Status == models.ContractStatusPendingSettlement {
+		s.publishStatus(contract, models.ContractStatusActive)
+		return settlementTx, buyerWins, nil
+	}
+
+	metrics.Settlements.Inc()
+	s.publishSettled(contract, models.ContractStatusActive, settlementTx, &buyerWins)
+
+	if s.settlementObserver != nil {
+		go s.settlementObserver.OnContractSettled(context.Background(), contract, buyerWins)
+	}
+
 	return settlementTx, buyerWins, nil
 }
 
@@ -785,6 +990,60 @@ func (s *Service) ListActiveContracts(ctx context.Context, limit, offset int) ([
 	return contracts, nil
 }
 
+// ListActiveContractsPage retrieves a page of active contracts, newest
+// first, after the cursor
+func (s *Service) ListActiveContractsPage(ctx context.Context, after models.Cursor, limit int) ([]*models.Contract, *models.PageInfo, error) {
+	contracts, err := s.contractRepo.ListByStatusPage(ctx, models.ContractStatusActive, after, limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list active contracts: %w", err)
+	}
+
+	total, err := s.contractRepo.CountWithStatus(ctx, models.ContractStatusActive)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count active contracts: %w", err)
+	}
+
+	fetched := len(contracts)
+	if fetched > limit {
+		contracts = contracts[:limit]
+	}
+	var last models.Cursor
+	if len(contracts) > 0 {
+		last = models.Cursor{Time: contracts[len(contracts)-1].CreatedAt, ID: contracts[len(contracts)-1].ID}
+	}
+
+	return contracts, models.NewPageInfo(total, fetched, limit, last), nil
+}
+
+// ListExitTransactions retrieves a page of a contract's emergency exit
+// transactions, newest first, after the cursor
+func (s *Service) ListExitTransactions(ctx context.Context, contractID uuid.UUID, after models.Cursor, limit int) ([]*models.ContractTransaction, *models.PageInfo, error) {
+	if _, err := s.contractRepo.GetByID(ctx, contractID); err != nil {
+		return nil, nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	txs, err := s.contractRepo.ListTransactionsByType(ctx, contractID, "emergency_exit", after, limit+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list exit transactions: %w", err)
+	}
+
+	total, err := s.contractRepo.CountTransactionsByType(ctx, contractID, "emergency_exit")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count exit transactions: %w", err)
+	}
+
+	fetched := len(txs)
+	if fetched > limit {
+		txs = txs[:limit]
+	}
+	var last models.Cursor
+	if len(txs) > 0 {
+		last = models.Cursor{Time: txs[len(txs)-1].CreatedAt, ID: txs[len(txs)-1].ID}
+	}
+
+	return txs, models.NewPageInfo(total, fetched, limit, last), nil
+}
+
 // ListExpiredContracts retrieves all contracts that have expired but not been settled
 func (s *Service) ListExpiredContracts(ctx context.Context) ([]*models.Contract, error) {
 	contracts, err := s.contractRepo.ListByStatus(ctx, models.ContractStatusActive, 1000, 0)
@@ -815,10 +1074,11 @@ func (s *Service) CancelContract(ctx context.Context, contractID uuid.UUID) erro
 		return errors.New("contract cannot be cancelled")
 	}
 
-	err = s.contractRepo.UpdateStatus(ctx, contractID, models.ContractStatusCancelled)
-	if err != nil {
-		return fmt.Errorf("failed to update contract status: %w", err)
+	from := contract.Status
+	if err := s.setStatus(ctx, contract, models.ContractStatusCancelled, "cancelled"); err != nil {
+		return err
 	}
+	s.publishStatus(contract, from)
 
 	return nil
 }
@@ -874,6 +1134,11 @@ func (s *Service) BroadcastTransaction(ctx context.Context, contractID uuid.UUID
 		return "", fmt.Errorf("transaction does not belong to the specified contract")
 	}
 	
+	// Refuse to broadcast a transaction that fails its checks
+	if err := s.checkBroadcast(ctx, tx, tx.TxHex); err != nil {
+		return "", err
+	}
+	
 	// Broadcast the transaction
 	txHash, err := s.bitcoinClient.BroadcastTransactionWithRetry(ctx, tx.TxHex)
 	if err != nil {
@@ -886,7 +1151,7 @@ func (s *Service) BroadcastTransaction(ctx context.Context, contractID uuid.UUID
 		// Update the transaction in the database
 		err = s.contractRepo.AddTransaction(ctx, tx)
 		if err != nil {
-			log.Warn().Err(err).
+			logger.Warn().Err(err).
 				Str("contractID", contractID.String()).
 				Str("txID", txID.String()).
 				Msg("Failed to update transaction ID after broadcast")
@@ -935,7 +1200,7 @@ func (s *Service) SwapContractParticipant(
     }
     
     // Check if ASP is available
-    aspAvailable, _ := s.arkClient.CheckASPStatus(ctx)
+    aspAvailable := s.aspAvailable(ctx)
     
     if aspAvailable {
         // Use ARK for off-chain participant swap
@@ -955,9 +1220,11 @@ func (s *Service) SwapContractParticipant(
             return nil, fmt.Errorf("failed to build swap script: %w", err)
         }
         
-        // Get the VTXO ID for this contract
-        // In practice, you'd need to know which VTXO corresponds to this contract
-        vtxoID := contract.ID.String() // Simplified; in reality retrieve the actual VTXO ID
+        // The swap spends the VTXO holding the contract's funds
+        vtxo, err := s.ContractVTXO(ctx, contractID)
+        if err != nil {
+            return nil, err
+        }
         
         // Create out-of-round transaction for the swap
         // Note: This is a simplified example; you'd need to create an actual PSBT here
@@ -993,8 +1260,10 @@ func (s *Service) SwapContractParticipant(
         // Update contract with new participant
         if isBuyer {
             contract.BuyerPubKey = newPubKey
+            contract.BuyerKeyID = nil
         } else {
             contract.SellerPubKey = newPubKey
+            contract.SellerKeyID = nil
         }
         
         contract.UpdatedAt = time.Now().UTC()
@@ -1005,6 +1274,11 @@ func (s *Service) SwapContractParticipant(
                 return fmt.Errorf("failed to add transaction: %w", err)
             }
             
+            // The swap output is the contract's VTXO from now on
+            if err := s.replaceVTXO(ctx, vtxo, oorResponse.GetTxId(), 0, swapScript); err != nil {
+                return err
+            }
+            
             if err := s.contractRepo.Update(ctx, contract); err != nil {
                 return fmt.Errorf("failed to update contract: %w", err)
             }
@@ -1019,7 +1293,7 @@ func (s *Service) SwapContractParticipant(
         return txRecord, nil
     } else {
         // Fallback to on-chain participant swap if ASP is unavailable
-        log.Warn().
+        logger.Warn().
             Str("contract_id", contractID.String()).
             Msg("ASP unavailable, falling back to on-chain participant swap")
             
@@ -1054,8 +1328,10 @@ func (s *Service) SwapContractParticipant(
         // Update contract with new participant
         if isBuyer {
             contract.BuyerPubKey = newPubKey
+            contract.BuyerKeyID = nil
         } else {
             contract.SellerPubKey = newPubKey
+            contract.SellerKeyID = nil
         }
         
         contract.UpdatedAt = time.Now().UTC()
@@ -1075,8 +1351,7 @@ func (s *Service) SwapContractParticipant(
 
 // IsASPAvailable checks if the ASP is currently accessible
 func (s *Service) IsASPAvailable(ctx context.Context) bool {
-    available, _ := s.arkClient.CheckASPStatus(ctx)
-    return available
+    return s.aspAvailable(ctx)
 }
 
 // ExpireContract marks a contract as expired if it's past its expiration time
@@ -1094,10 +1369,11 @@ func (s *Service) ExpireContract(ctx context.Context, contractID uuid.UUID) erro
 		return errors.New("contract is not expired")
 	}
 
-	err = s.contractRepo.UpdateStatus(ctx, contractID, models.ContractStatusExpired)
-	if err != nil {
-		return fmt.Errorf("failed to update contract status: %w", err)
+	if err := s.setStatus(ctx, contract, models.ContractStatusExpired, "expired"); err != nil {
+		return err
 	}
+	s.adjustOpenInterest(ctx, contract, -1)
+	s.publishStatus(contract, models.ContractStatusActive)
 
 	return nil
 }
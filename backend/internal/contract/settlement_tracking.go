@@ -0,0 +1,279 @@
+// internal/contract/settlement_tracking.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+)
+
+// ErrSettlementTrackingNotEnabled is returned when settlements are marked
+// SETTLED as soon as they are built
+var ErrSettlementTrackingNotEnabled = errors.New("settlement tracking is not enabled")
+
+// SettlementTrackingConfig controls how payout transactions are followed
+// from broadcast to confirmation
+type SettlementTrackingConfig struct {
+	// RebroadcastInterval is how often payouts the node does not know are
+	// broadcast again; zero disables rebroadcasting
+	RebroadcastInterval time.Duration `yaml:"rebroadcast_interval"`
+	// StuckAfter is how long a payout may go unconfirmed before it is
+	// reported as stuck
+	StuckAfter time.Duration `yaml:"stuck_after"`
+	// BatchSize is the most pending payouts checked per interval
+	BatchSize int `yaml:"batch_size"`
+}
+
+// DefaultSettlementTrackingConfig rebroadcasts every ten minutes and
+// reports payouts unconfirmed after six hours
+var DefaultSettlementTrackingConfig = SettlementTrackingConfig{
+	RebroadcastInterval: 10 * time.Minute,
+	StuckAfter:          6 * time.Hour,
+	BatchSize:           50,
+}
+
+// Enabled reports whether pending payouts are rebroadcast
+func (c SettlementTrackingConfig) Enabled() bool {
+	return c.RebroadcastInterval > 0
+}
+
+// Validate checks that the tracking settings are usable
+func (c SettlementTrackingConfig) Validate() error {
+	if c.RebroadcastInterval < 0 {
+		return fmt.Errorf("settlement rebroadcast interval cannot be negative")
+	}
+	if c.StuckAfter <= 0 {
+		return fmt.Errorf("settlement stuck after must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("settlement tracking batch size must be positive")
+	}
+	return nil
+}
+
+// WithSettlementTracking settles contracts in two phases: a contract whose
+// payout is built becomes PENDING_SETTLEMENT, and SETTLED only once the
+// confirmation watcher sees the payout confirm. Start rebroadcasting with
+// StartSettlementTracker.
+func (s *Service) WithSettlementTracking(store SettlementBroadcastStore, cfg SettlementTrackingConfig) *Service {
+	s.settlementRepo = store
+	s.settlementTracking = cfg
+	return s
+}
+
+// payoutStatus is the status of a contract whose payout was just built
+func (s *Service) payoutStatus() models.ContractStatus {
+	if s.settlementRepo == nil {
+		return models.ContractStatusSettled
+	}
+	return models.ContractStatusPendingSettlement
+}
+
+// trackSettlement starts tracking the payout of a contract. buyerWins is
+// nil for a cooperative close.
+func (s *Service) trackSettlement(ctx context.Context, tx *models.ContractTransaction, buyerWins *bool) error {
+	if s.settlementRepo == nil {
+		return nil
+	}
+
+	return s.settlementRepo.Create(ctx, &models.SettlementBroadcast{
+		ContractID:    tx.ContractID,
+		TransactionID: tx.TransactionID,
+		TxType:        tx.TxType,
+		BuyerWins:     buyerWins,
+	})
+}
+
+// broadcastSettlement broadcasts a payout transaction and records the attempt
+func (s *Service) broadcastSettlement(ctx context.Context, tx *models.ContractTransaction) error {
+	err := s.checkBroadcast(ctx, tx, tx.TxHex)
+	if err == nil {
+		_, err = s.bitcoinClient.BroadcastTransactionWithRetry(ctx, tx.TxHex)
+	}
+
+	if s.settlementRepo != nil {
+		if recordErr := s.settlementRepo.RecordBroadcast(ctx, tx.ContractID, err); recordErr != nil {
+			logger.Error().Err(recordErr).
+				Str("contractID", tx.ContractID.String()).
+				Str("txid", tx.TransactionID).
+				Msg("Failed to record settlement broadcast")
+		}
+	}
+
+	return err
+}
+
+// completeSettlement moves a contract whose payout confirmed from
+// PENDING_SETTLEMENT to SETTLED and announces the settlement. It does
+// nothing for other transactions.
+func (s *Service) completeSettlement(ctx context.Context, tx *models.ContractTransaction, depth int64) error {
+	if s.settlementRepo == nil || (tx.TxType != "settlement" && tx.TxType != "close") {
+		return nil
+	}
+
+	broadcast, err := s.settlementRepo.Get(ctx, tx.ContractID)
+	if err != nil {
+		return err
+	}
+	if broadcast == nil || !broadcast.Pending() || broadcast.TransactionID != tx.TransactionID {
+		return nil
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, tx.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	settled := contract.Status == models.ContractStatusPendingSettlement
+	if settled {
+		if err := s.setStatus(ctx, contract, models.ContractStatusSettled, "payout confirmed"); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.settlementRepo.Confirm(ctx, contract.ID, depth); err != nil {
+		return err
+	}
+	if !settled {
+		return nil
+	}
+
+	if tx.TxType == "settlement" {
+		metrics.Settlements.Inc()
+	}
+	s.publishSettled(contract, models.ContractStatusPendingSettlement, tx, broadcast.BuyerWins)
+	if broadcast.BuyerWins != nil && s.settlementObserver != nil {
+		go s.settlementObserver.OnContractSettled(context.Background(), contract, *broadcast.BuyerWins)
+	}
+
+	logger.Info().
+		Str("contractID", contract.ID.String()).
+		Str("txid", tx.TransactionID).
+		Int64("confirmations", depth).
+		Msg("Contract settlement confirmed")
+
+	return nil
+}
+
+// StartSettlementTracker rebroadcasts pending payouts on the configured
+// interval until ctx is cancelled
+func (s *Service) StartSettlementTracker(ctx context.Context) {
+	if s.settlementRepo == nil || !s.settlementTracking.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.settlementTracking.RebroadcastInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rebroadcast, err := s.rebroadcastSettlements(ctx)
+				if err != nil {
+					logger.Error().Err(err).Msg("Failed to rebroadcast pending settlements")
+				} else if rebroadcast > 0 {
+					logger.Info().Int("rebroadcast", rebroadcast).Msg("Pending settlements rebroadcast")
+				}
+			}
+		}
+	}()
+}
+
+// rebroadcastSettlements broadcasts again the pending payouts that neither
+// the node's mempool nor the chain holds, such as those that failed to
+// broadcast or were evicted, and returns how many were
+func (s *Service) rebroadcastSettlements(ctx context.Context) (int, error) {
+	pending, err := s.settlementRepo.ListPending(ctx, s.settlementTracking.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending settlements: %w", err)
+	}
+
+	rebroadcast := 0
+	for _, broadcast := range pending {
+		if depth, known := s.payoutDepth(ctx, broadcast.TransactionID); known {
+			if err := s.settlementRepo.RecordConfirmations(ctx, broadcast.ContractID, depth); err != nil {
+				return rebroadcast, err
+			}
+			continue
+		}
+
+		tx, err := s.payoutTransaction(ctx, broadcast)
+		if err != nil {
+			logger.Error().Err(err).Str("contractID", broadcast.ContractID.String()).Msg("Failed to load pending settlement")
+			continue
+		}
+
+		if err := s.broadcastSettlement(ctx, tx); err != nil {
+			logger.Warn().Err(err).
+				Str("contractID", broadcast.ContractID.String()).
+				Str("txid", broadcast.TransactionID).
+				Msg("Failed to rebroadcast settlement transaction")
+		}
+		rebroadcast++
+	}
+
+	return rebroadcast, nil
+}
+
+// payoutDepth returns the confirmations of a payout, reporting false if
+// the node does not know it
+func (s *Service) payoutDepth(ctx context.Context, txid string) (int64, bool) {
+	if s.confirmationSource == nil {
+		return 0, false
+	}
+
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return 0, false
+	}
+
+	depth, err := s.confirmationSource.GetTransactionConfirmations(ctx, hash)
+	if err != nil {
+		return 0, false
+	}
+	return depth, true
+}
+
+// payoutTransaction returns the stored payout transaction of a broadcast
+func (s *Service) payoutTransaction(ctx context.Context, broadcast *models.SettlementBroadcast) (*models.ContractTransaction, error) {
+	txs, err := s.contractRepo.GetTransactionsByContractID(ctx, broadcast.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	for _, tx := range txs {
+		if tx.TransactionID == broadcast.TransactionID {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("payout transaction %s not found", broadcast.TransactionID)
+}
+
+// GetSettlementBroadcast returns the broadcasts of a contract's payout, or
+// nil if it has none
+func (s *Service) GetSettlementBroadcast(ctx context.Context, contractID uuid.UUID) (*models.SettlementBroadcast, error) {
+	if s.settlementRepo == nil {
+		return nil, ErrSettlementTrackingNotEnabled
+	}
+	return s.settlementRepo.Get(ctx, contractID)
+}
+
+// ListStuckSettlements returns a page of payouts that have gone unconfirmed
+// for longer than the configured time, oldest first
+func (s *Service) ListStuckSettlements(ctx context.Context, limit, offset int) ([]*models.SettlementBroadcast, error) {
+	if s.settlementRepo == nil {
+		return nil, ErrSettlementTrackingNotEnabled
+	}
+	before := time.Now().UTC().Add(-s.settlementTracking.StuckAfter)
+	return s.settlementRepo.ListStuck(ctx, before, limit, offset)
+}
@@ -0,0 +1,204 @@
+// internal/contract/settlement_tracking_test.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// stubBroadcastStore keeps settlement broadcasts in memory
+type stubBroadcastStore map[uuid.UUID]*models.SettlementBroadcast
+
+func (s stubBroadcastStore) Create(ctx context.Context, broadcast *models.SettlementBroadcast) error {
+	s[broadcast.ContractID] = broadcast
+	return nil
+}
+
+func (s stubBroadcastStore) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementBroadcast, error) {
+	return s[contractID], nil
+}
+
+func (s stubBroadcastStore) RecordBroadcast(ctx context.Context, contractID uuid.UUID, broadcastErr error) error {
+	b := s[contractID]
+	b.Broadcasts++
+	b.LastError = nil
+	if broadcastErr != nil {
+		msg := broadcastErr.Error()
+		b.LastError = &msg
+	}
+	return nil
+}
+
+func (s stubBroadcastStore) RecordConfirmations(ctx context.Context, contractID uuid.UUID, confirmations int64) error {
+	s[contractID].Confirmations = confirmations
+	return nil
+}
+
+func (s stubBroadcastStore) Confirm(ctx context.Context, contractID uuid.UUID, confirmations int64) (*models.SettlementBroadcast, error) {
+	b := s[contractID]
+	now := time.Now()
+	b.Confirmations = confirmations
+	b.ConfirmedAt = &now
+	return b, nil
+}
+
+func (s stubBroadcastStore) ListPending(ctx context.Context, limit int) ([]*models.SettlementBroadcast, error) {
+	var pending []*models.SettlementBroadcast
+	for _, b := range s {
+		if b.Pending() {
+			pending = append(pending, b)
+		}
+	}
+	return pending, nil
+}
+
+func (s stubBroadcastStore) ListStuck(ctx context.Context, before time.Time, limit, offset int) ([]*models.SettlementBroadcast, error) {
+	return nil, nil
+}
+
+// stubContractStore holds contracts and their transactions in memory
+type stubContractStore struct {
+	ContractStore
+	contracts map[uuid.UUID]*models.Contract
+	txs       []*models.ContractTransaction
+}
+
+func (s *stubContractStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error) {
+	contract, ok := s.contracts[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *contract
+	return &copied, nil
+}
+
+func (s *stubContractStore) UpdateStatus(ctx context.Context, id uuid.UUID, status models.ContractStatus) error {
+	s.contracts[id].Status = status
+	return nil
+}
+
+func (s *stubContractStore) GetTransactionsByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransaction, error) {
+	var txs []*models.ContractTransaction
+	for _, tx := range s.txs {
+		if tx.ContractID == contractID {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (s *stubContractStore) AddTransaction(ctx context.Context, tx *models.ContractTransaction) error {
+	s.txs = append(s.txs, tx)
+	return nil
+}
+
+func (s *stubContractStore) GetTransactionByID(ctx context.Context, txID uuid.UUID) (*models.ContractTransaction, error) {
+	for _, tx := range s.txs {
+		if tx.ID == txID {
+			return tx, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// recordingChain records the transactions it is asked to broadcast
+type recordingChain struct {
+	ChainBackend
+	broadcast []string
+}
+
+func (c *recordingChain) BroadcastTransactionWithRetry(ctx context.Context, txHex string) (string, error) {
+	c.broadcast = append(c.broadcast, txHex)
+	return "", nil
+}
+
+func TestCompleteSettlement(t *testing.T) {
+	txid := "1111111111111111111111111111111111111111111111111111111111111111"
+	contract := &models.Contract{ID: uuid.New(), Status: models.ContractStatusPendingSettlement}
+	tx := &models.ContractTransaction{ContractID: contract.ID, TransactionID: txid, TxType: "settlement"}
+	buyerWins := true
+
+	contracts := &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: contract}}
+	broadcasts := stubBroadcastStore{}
+	require.NoError(t, broadcasts.Create(context.Background(), &models.SettlementBroadcast{
+		ContractID: contract.ID, TransactionID: txid, TxType: "settlement", BuyerWins: &buyerWins,
+	}))
+
+	var published recordingPublisher
+	s := &Service{contractRepo: contracts}
+	s.WithEventBus(&published).
+		WithConfirmationWatcher(&stubConfirmationStore{txs: []*models.ContractTransaction{tx}}, stubConfirmationSource{txid: 2}, ConfirmationConfig{Interval: 1, Depth: 1}).
+		WithSettlementTracking(broadcasts, DefaultSettlementTrackingConfig)
+
+	confirmed, err := s.checkConfirmations(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, confirmed)
+
+	assert.Equal(t, models.ContractStatusSettled, contracts.contracts[contract.ID].Status)
+	assert.False(t, broadcasts[contract.ID].Pending())
+	assert.Equal(t, int64(2), broadcasts[contract.ID].Confirmations)
+
+	require.Len(t, published, 3)
+	assert.Equal(t, events.ContractStatusChanged, published[0].Type)
+	assert.Equal(t, models.ContractStatusPendingSettlement, published[0].From)
+	assert.Equal(t, models.ContractStatusSettled, published[0].To)
+	assert.Equal(t, events.ContractSettled, published[1].Type)
+	assert.True(t, *published[1].BuyerWins)
+	assert.Equal(t, events.ContractTransactionConfirmed, published[2].Type)
+
+	// A payout confirmed again does not settle the contract twice
+	require.NoError(t, s.completeSettlement(context.Background(), tx, 3))
+	assert.Len(t, published, 3)
+}
+
+func TestRebroadcastSettlements(t *testing.T) {
+	inMempool := "1111111111111111111111111111111111111111111111111111111111111111"
+	dropped := "2222222222222222222222222222222222222222222222222222222222222222"
+	known, lost := uuid.New(), uuid.New()
+	closeHex := exitHex(t, "3333333333333333333333333333333333333333333333333333333333333333")
+
+	contracts := &stubContractStore{txs: []*models.ContractTransaction{
+		{ContractID: known, TransactionID: inMempool, TxType: "settlement", TxHex: "aa"},
+		{ContractID: lost, TransactionID: "final", TxType: "final", TxHex: "bb"},
+		{ContractID: lost, TransactionID: dropped, TxType: "close", TxHex: closeHex},
+	}}
+	broadcasts := stubBroadcastStore{
+		known: {ContractID: known, TransactionID: inMempool, TxType: "settlement", Broadcasts: 1},
+		lost:  {ContractID: lost, TransactionID: dropped, TxType: "close", Broadcasts: 1},
+	}
+	chain := &recordingChain{}
+
+	s := &Service{contractRepo: contracts, bitcoinClient: chain}
+	s.WithConfirmationWatcher(&stubConfirmationStore{}, stubConfirmationSource{inMempool: 0}, DefaultConfirmationConfig).
+		WithSettlementTracking(broadcasts, DefaultSettlementTrackingConfig)
+
+	rebroadcast, err := s.rebroadcastSettlements(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, rebroadcast)
+	assert.Equal(t, []string{closeHex}, chain.broadcast)
+	assert.Equal(t, 2, broadcasts[lost].Broadcasts)
+	assert.Nil(t, broadcasts[lost].LastError)
+	assert.Equal(t, 1, broadcasts[known].Broadcasts, "transactions the node holds are not rebroadcast")
+}
+
+func TestSettlementTrackingConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultSettlementTrackingConfig.Validate())
+
+	disabled := DefaultSettlementTrackingConfig
+	disabled.RebroadcastInterval = 0
+	assert.NoError(t, disabled.Validate())
+	assert.False(t, disabled.Enabled())
+
+	invalid := DefaultSettlementTrackingConfig
+	invalid.StuckAfter = 0
+	assert.Error(t, invalid.Validate())
+}
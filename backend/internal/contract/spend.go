@@ -0,0 +1,67 @@
+// internal/contract/spend.go
+package contract
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+
+	"hashhedge/pkg/taproot"
+)
+
+// prepareSpends sets up tx to spend every input through its leaf of the
+// script tree built by spender, and returns the unsigned transaction as a
+// base64 PSBT. Each input of the PSBT carries the output it spends and its
+// leaf script and control block (BIP-371), from which the winner's wallet
+// computes the signature hash and completes the witness.
+func prepareSpends(tx *wire.MsgTx, inputs []sweepInput, spender *taproot.SpendBuilder) (string, error) {
+	if len(inputs) != len(tx.TxIn) {
+		return "", fmt.Errorf("transaction has %d inputs, expected %d", len(tx.TxIn), len(inputs))
+	}
+
+	pkScript, err := spender.PkScript()
+	if err != nil {
+		return "", fmt.Errorf("failed to build contract output script: %w", err)
+	}
+
+	// Lock times and sequences are committed to by every signature hash,
+	// so all inputs are prepared before the transaction is handed out
+	paths := make([]*taproot.SpendPath, len(inputs))
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range inputs {
+		path, err := spender.Path(in.Leaf)
+		if err != nil {
+			return "", err
+		}
+		if err := path.PrepareInput(tx, i); err != nil {
+			return "", fmt.Errorf("failed to prepare input %d: %w", i, err)
+		}
+		paths[i] = path
+		prevOuts.AddPrevOut(in.OutPoint, wire.NewTxOut(in.Value, pkScript))
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PSBT: %w", err)
+	}
+
+	for i, path := range paths {
+		// Fail here rather than at the signer if an input cannot be signed
+		if _, err := path.SigHash(tx, i, prevOuts); err != nil {
+			return "", fmt.Errorf("input %d: %w", i, err)
+		}
+
+		input := &packet.Inputs[i]
+		input.WitnessUtxo = prevOuts.FetchPrevOutput(inputs[i].OutPoint)
+		input.SighashType = txscript.SigHashDefault
+		input.TaprootLeafScript = []*psbt.TaprootTapLeafScript{{
+			ControlBlock: path.ControlBlock,
+			Script:       path.Script,
+			LeafVersion:  txscript.BaseLeafVersion,
+		}}
+	}
+
+	return packet.B64Encode()
+}
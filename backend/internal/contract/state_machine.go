@@ -0,0 +1,180 @@
+// internal/contract/state_machine.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ErrInvalidTransition is returned when a contract is moved to a status it
+// cannot reach from its current one, or a guard refuses the move
+var ErrInvalidTransition = errors.New("invalid contract status transition")
+
+// ErrTransitionHistoryNotEnabled is returned when listing the status history
+// of a contract without a transition store
+var ErrTransitionHistoryNotEnabled = errors.New("contract status history is not enabled")
+
+// transitions lists the statuses each status may move to. SETTLED, EXPIRED
+// and CANCELLED are final.
+var transitions = map[models.ContractStatus][]models.ContractStatus{
+	models.ContractStatusCreated: {
+		models.ContractStatusActive,
+		models.ContractStatusCancelled,
+	},
+	models.ContractStatusActive: {
+		models.ContractStatusPendingSettlement,
+		models.ContractStatusSettled,
+		models.ContractStatusExpired,
+	},
+	models.ContractStatusPendingSettlement: {
+		models.ContractStatusSettled,
+	},
+}
+
+// CanTransition reports whether a contract in status from may move to to
+func CanTransition(from, to models.ContractStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionGuard may refuse to move a contract to a status, returning why
+type TransitionGuard func(ctx context.Context, contract *models.Contract, to models.ContractStatus) error
+
+// TransitionHook is called after a contract has moved to a status, with a
+// copy of the contract and the status it left
+type TransitionHook func(ctx context.Context, contract models.Contract, from models.ContractStatus)
+
+// stateMachine enforces the allowed status transitions of contracts, runs
+// guards before and hooks after each one, and records it in the history
+type stateMachine struct {
+	mu      sync.RWMutex
+	guards  map[models.ContractStatus][]TransitionGuard
+	hooks   map[models.ContractStatus][]TransitionHook
+	history TransitionStore
+}
+
+// check returns an error unless a contract may move to status to
+func (m *stateMachine) check(ctx context.Context, contract *models.Contract, to models.ContractStatus) error {
+	if !CanTransition(contract.Status, to) {
+		return fmt.Errorf("%w: contract %s cannot move from %s to %s", ErrInvalidTransition, contract.ID, contract.Status, to)
+	}
+
+	m.mu.RLock()
+	guards := m.guards[to]
+	m.mu.RUnlock()
+
+	for _, guard := range guards {
+		if err := guard(ctx, contract, to); err != nil {
+			return fmt.Errorf("%w: contract %s cannot move to %s: %v", ErrInvalidTransition, contract.ID, to, err)
+		}
+	}
+	return nil
+}
+
+// WithTransitionHistory records every status change of a contract, from its
+// creation, for audit
+func (s *Service) WithTransitionHistory(store TransitionStore) *Service {
+	s.states.history = store
+	return s
+}
+
+// GuardTransition adds a guard that must pass before any contract moves to
+// status
+func (s *Service) GuardTransition(status models.ContractStatus, guard TransitionGuard) *Service {
+	s.states.mu.Lock()
+	defer s.states.mu.Unlock()
+	if s.states.guards == nil {
+		s.states.guards = make(map[models.ContractStatus][]TransitionGuard)
+	}
+	s.states.guards[status] = append(s.states.guards[status], guard)
+	return s
+}
+
+// OnTransition adds a hook run whenever a contract moves to status. Hooks
+// run in their own goroutine, so a slow hook never holds up the contract.
+func (s *Service) OnTransition(status models.ContractStatus, hook TransitionHook) *Service {
+	s.states.mu.Lock()
+	defer s.states.mu.Unlock()
+	if s.states.hooks == nil {
+		s.states.hooks = make(map[models.ContractStatus][]TransitionHook)
+	}
+	s.states.hooks[status] = append(s.states.hooks[status], hook)
+	return s
+}
+
+// transition checks that a contract may move to status to and sets it,
+// returning the status it left. Persist the contract, then call entered.
+func (s *Service) transition(ctx context.Context, contract *models.Contract, to models.ContractStatus) (models.ContractStatus, error) {
+	from := contract.Status
+	if err := s.states.check(ctx, contract, to); err != nil {
+		return from, err
+	}
+	contract.Status = to
+	contract.UpdatedAt = time.Now().UTC()
+	return from, nil
+}
+
+// entered records a persisted status change in the history and runs the
+// hooks of the new status. The change has already happened, so a history
+// that cannot be written is logged rather than returned.
+func (s *Service) entered(ctx context.Context, contract *models.Contract, from models.ContractStatus, reason string) {
+	if s.states.history != nil {
+		err := s.states.history.Create(ctx, &models.ContractTransition{
+			ContractID: contract.ID,
+			FromStatus: from,
+			ToStatus:   contract.Status,
+			Reason:     reason,
+			CreatedAt:  contract.UpdatedAt,
+		})
+		if err != nil {
+			logger.Error().Err(err).
+				Str("contractID", contract.ID.String()).
+				Str("from", string(from)).
+				Str("to", string(contract.Status)).
+				Msg("Failed to record contract status transition")
+		}
+	}
+
+	s.states.mu.RLock()
+	hooks := s.states.hooks[contract.Status]
+	s.states.mu.RUnlock()
+
+	for _, hook := range hooks {
+		go hook(context.Background(), *contract, from)
+	}
+}
+
+// setStatus moves a contract to status to, storing only its status
+func (s *Service) setStatus(ctx context.Context, contract *models.Contract, to models.ContractStatus, reason string) error {
+	from, err := s.transition(ctx, contract, to)
+	if err != nil {
+		return err
+	}
+
+	if err := s.contractRepo.UpdateStatus(ctx, contract.ID, to); err != nil {
+		contract.Status = from
+		return fmt.Errorf("failed to update contract status: %w", err)
+	}
+
+	s.entered(ctx, contract, from, reason)
+	return nil
+}
+
+// StatusHistory returns every status a contract moved through, oldest first
+func (s *Service) StatusHistory(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransition, error) {
+	if s.states.history == nil {
+		return nil, ErrTransitionHistoryNotEnabled
+	}
+	return s.states.history.ListByContractID(ctx, contractID)
+}
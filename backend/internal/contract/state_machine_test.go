@@ -0,0 +1,98 @@
+// internal/contract/state_machine_test.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+// stubTransitionStore holds transitions in memory
+type stubTransitionStore struct {
+	transitions []*models.ContractTransition
+}
+
+func (s *stubTransitionStore) Create(ctx context.Context, transition *models.ContractTransition) error {
+	s.transitions = append(s.transitions, transition)
+	return nil
+}
+
+func (s *stubTransitionStore) ListByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransition, error) {
+	var transitions []*models.ContractTransition
+	for _, transition := range s.transitions {
+		if transition.ContractID == contractID {
+			transitions = append(transitions, transition)
+		}
+	}
+	return transitions, nil
+}
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, CanTransition(models.ContractStatusCreated, models.ContractStatusActive))
+	assert.True(t, CanTransition(models.ContractStatusCreated, models.ContractStatusCancelled))
+	assert.True(t, CanTransition(models.ContractStatusActive, models.ContractStatusPendingSettlement))
+	assert.True(t, CanTransition(models.ContractStatusPendingSettlement, models.ContractStatusSettled))
+
+	assert.False(t, CanTransition(models.ContractStatusActive, models.ContractStatusCancelled))
+	assert.False(t, CanTransition(models.ContractStatusCreated, models.ContractStatusSettled))
+	assert.False(t, CanTransition(models.ContractStatusSettled, models.ContractStatusActive))
+	assert.False(t, CanTransition(models.ContractStatusExpired, models.ContractStatusSettled))
+}
+
+func TestSetStatus(t *testing.T) {
+	contract := &models.Contract{ID: uuid.New(), Status: models.ContractStatusActive}
+	contracts := &stubContractStore{contracts: map[uuid.UUID]*models.Contract{contract.ID: {ID: contract.ID, Status: contract.Status}}}
+	history := &stubTransitionStore{}
+
+	hooked := make(chan models.ContractStatus, 1)
+	s := &Service{contractRepo: contracts}
+	s.WithTransitionHistory(history).
+		OnTransition(models.ContractStatusExpired, func(ctx context.Context, c models.Contract, from models.ContractStatus) {
+			hooked <- from
+		})
+
+	// Moves the status can not make are refused before anything is stored
+	err := s.setStatus(context.Background(), contract, models.ContractStatusCancelled, "cancelled")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Equal(t, models.ContractStatusActive, contract.Status)
+	assert.Empty(t, history.transitions)
+
+	require.NoError(t, s.setStatus(context.Background(), contract, models.ContractStatusExpired, "expired"))
+	assert.Equal(t, models.ContractStatusExpired, contract.Status)
+	assert.Equal(t, models.ContractStatusExpired, contracts.contracts[contract.ID].Status)
+	assert.Equal(t, models.ContractStatusActive, <-hooked)
+
+	transitions, err := s.StatusHistory(context.Background(), contract.ID)
+	require.NoError(t, err)
+	require.Len(t, transitions, 1)
+	assert.Equal(t, models.ContractStatusActive, transitions[0].FromStatus)
+	assert.Equal(t, models.ContractStatusExpired, transitions[0].ToStatus)
+	assert.Equal(t, "expired", transitions[0].Reason)
+}
+
+func TestGuardTransition(t *testing.T) {
+	contract := &models.Contract{ID: uuid.New(), Status: models.ContractStatusCreated}
+	s := &Service{}
+	s.GuardTransition(models.ContractStatusActive, func(ctx context.Context, c *models.Contract, to models.ContractStatus) error {
+		return errors.New("setup not funded")
+	})
+
+	_, err := s.transition(context.Background(), contract, models.ContractStatusActive)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.ErrorContains(t, err, "setup not funded")
+	assert.Equal(t, models.ContractStatusCreated, contract.Status)
+
+	// Guards only apply to their status
+	_, err = s.transition(context.Background(), contract, models.ContractStatusCancelled)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ContractStatusCancelled, contract.Status)
+
+	_, err = (&Service{}).StatusHistory(context.Background(), contract.ID)
+	assert.ErrorIs(t, err, ErrTransitionHistoryNotEnabled)
+}
@@ -0,0 +1,164 @@
+// internal/contract/timeline.go
+package contract
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// TimelineEventType identifies a step in a contract's lifecycle
+type TimelineEventType string
+
+const (
+	TimelineCreated            TimelineEventType = "CREATED"
+	TimelineFundingComplete    TimelineEventType = "FUNDING_COMPLETE"
+	TimelineTxCreated          TimelineEventType = "TX_CREATED"
+	TimelineTxConfirmed        TimelineEventType = "TX_CONFIRMED"
+	TimelineTarget             TimelineEventType = "TARGET"
+	TimelineSettlementDeferred TimelineEventType = "SETTLEMENT_DEFERRED"
+	TimelineSettlementReleased TimelineEventType = "SETTLEMENT_RELEASED"
+	TimelineSettled            TimelineEventType = "SETTLED"
+	TimelineExpired            TimelineEventType = "EXPIRED"
+	TimelineCancelled          TimelineEventType = "CANCELLED"
+)
+
+// TimelineEvent is one step of a contract's lifecycle. Milestones that have
+// not been reached yet are included with Pending set so clients can render
+// the remaining progress.
+type TimelineEvent struct {
+	Type          TimelineEventType `json:"type"`
+	Time          time.Time         `json:"time"`
+	BlockHeight   *int64            `json:"block_height,omitempty"`
+	TxType        string            `json:"tx_type,omitempty"`
+	TxID          string            `json:"tx_id,omitempty"`          // On-chain transaction ID
+	TransactionID *uuid.UUID        `json:"transaction_id,omitempty"` // Contract transaction record, as used by the broadcast endpoint
+	Pending       bool              `json:"pending"`
+}
+
+// Timeline is the ordered lifecycle of a contract
+type Timeline struct {
+	ContractID  uuid.UUID             `json:"contract_id"`
+	Status      models.ContractStatus `json:"status"`
+	BlockHeight int64                 `json:"block_height"`
+	Events      []TimelineEvent       `json:"events"`
+}
+
+// GetContractTimeline assembles the lifecycle events of a contract from its
+// record, transactions, funding progress and settlement deferral
+func (s *Service) GetContractTimeline(ctx context.Context, contractID uuid.UUID) (*Timeline, error) {
+	contract, err := s.contractRepo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract: %w", err)
+	}
+
+	txs, err := s.contractRepo.GetTransactionsByContractID(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract transactions: %w", err)
+	}
+
+	var funding *models.ContractFunding
+	if s.fundingRepo != nil {
+		if funding, err = s.fundingRepo.GetByContractID(ctx, contractID); err != nil {
+			return nil, fmt.Errorf("failed to get contract funding: %w", err)
+		}
+	}
+
+	var deferral *models.SettlementDeferral
+	if s.deferralRepo != nil {
+		if deferral, err = s.deferralRepo.Get(ctx, contractID); err != nil {
+			return nil, fmt.Errorf("failed to get settlement deferral: %w", err)
+		}
+	}
+
+	height, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Timeline{
+		ContractID:  contract.ID,
+		Status:      contract.Status,
+		BlockHeight: height,
+		Events:      buildTimeline(contract, txs, funding, deferral, height),
+	}, nil
+}
+
+// buildTimeline orders the lifecycle events of a contract by time. The target
+// milestone stays pending until the chain reaches the end block height;
+// events at the same time keep the order in which they are added.
+func buildTimeline(
+	contract *models.Contract,
+	txs []*models.ContractTransaction,
+	funding *models.ContractFunding,
+	deferral *models.SettlementDeferral,
+	height int64,
+) []TimelineEvent {
+	events := []TimelineEvent{{Type: TimelineCreated, Time: contract.CreatedAt}}
+
+	if funding != nil && funding.IsComplete() {
+		events = append(events, TimelineEvent{Type: TimelineFundingComplete, Time: funding.UpdatedAt})
+	}
+
+	for _, tx := range txs {
+		id := tx.ID
+		txType := strings.ToUpper(tx.TxType)
+		events = append(events, TimelineEvent{
+			Type:          TimelineTxCreated,
+			Time:          tx.CreatedAt,
+			TxType:        txType,
+			TxID:          tx.TransactionID,
+			TransactionID: &id,
+		})
+
+		if tx.Confirmed && tx.ConfirmedAt != nil {
+			events = append(events, TimelineEvent{
+				Type:          TimelineTxConfirmed,
+				Time:          *tx.ConfirmedAt,
+				TxType:        txType,
+				TxID:          tx.TransactionID,
+				TransactionID: &id,
+			})
+		}
+	}
+
+	if deferral != nil {
+		events = append(events, TimelineEvent{Type: TimelineSettlementDeferred, Time: deferral.DeferredAt})
+		if deferral.ReleasedAt != nil {
+			events = append(events, TimelineEvent{Type: TimelineSettlementReleased, Time: *deferral.ReleasedAt})
+		}
+	}
+
+	terminal := map[models.ContractStatus]TimelineEventType{
+		models.ContractStatusSettled:   TimelineSettled,
+		models.ContractStatusExpired:   TimelineExpired,
+		models.ContractStatusCancelled: TimelineCancelled,
+	}
+	eventType, closed := terminal[contract.Status]
+
+	if contract.Status != models.ContractStatusCancelled {
+		end := contract.EndBlockHeight
+		events = append(events, TimelineEvent{
+			Type:        TimelineTarget,
+			Time:        contract.TargetTimestamp,
+			BlockHeight: &end,
+			Pending:     height < end,
+		})
+	}
+
+	if closed {
+		events = append(events, TimelineEvent{Type: eventType, Time: contract.UpdatedAt})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	return events
+}
@@ -0,0 +1,88 @@
+// internal/contract/timeline_test.go
+package contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func timelineTypes(events []TimelineEvent) []TimelineEventType {
+	types := make([]TimelineEventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestBuildTimeline(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	contract := &models.Contract{
+		ID:              uuid.New(),
+		EndBlockHeight:  850000,
+		TargetTimestamp: created.Add(7 * 24 * time.Hour),
+		Status:          models.ContractStatusActive,
+		CreatedAt:       created,
+	}
+
+	confirmed := created.Add(2 * time.Hour)
+	setup := &models.ContractTransaction{
+		ID:            uuid.New(),
+		TransactionID: "setup-txid",
+		TxType:        "setup",
+		Confirmed:     true,
+		CreatedAt:     created.Add(time.Hour),
+		ConfirmedAt:   &confirmed,
+	}
+	funding := &models.ContractFunding{
+		BuyerFunded: true, SellerFunded: true, BuyerSigned: true, SellerSigned: true,
+		UpdatedAt: created.Add(30 * time.Minute),
+	}
+
+	t.Run("active contract", func(t *testing.T) {
+		events := buildTimeline(contract, []*models.ContractTransaction{setup}, funding, nil, 849000)
+
+		assert.Equal(t, []TimelineEventType{
+			TimelineCreated, TimelineFundingComplete, TimelineTxCreated, TimelineTxConfirmed, TimelineTarget,
+		}, timelineTypes(events))
+
+		assert.Equal(t, "SETUP", events[3].TxType)
+		assert.Equal(t, "setup-txid", events[3].TxID)
+		assert.Equal(t, setup.ID, *events[3].TransactionID)
+		assert.True(t, events[4].Pending)
+		assert.Equal(t, int64(850000), *events[4].BlockHeight)
+	})
+
+	t.Run("settled after deferral", func(t *testing.T) {
+		settled := *contract
+		settled.Status = models.ContractStatusSettled
+		settled.UpdatedAt = contract.TargetTimestamp.Add(3 * time.Hour)
+
+		released := contract.TargetTimestamp.Add(2 * time.Hour)
+		deferral := &models.SettlementDeferral{
+			DeferredAt: contract.TargetTimestamp.Add(time.Hour),
+			ReleasedAt: &released,
+		}
+
+		events := buildTimeline(&settled, nil, nil, deferral, 850010)
+
+		assert.Equal(t, []TimelineEventType{
+			TimelineCreated, TimelineTarget, TimelineSettlementDeferred, TimelineSettlementReleased, TimelineSettled,
+		}, timelineTypes(events))
+		assert.False(t, events[1].Pending)
+	})
+
+	t.Run("cancelled contract has no target", func(t *testing.T) {
+		cancelled := *contract
+		cancelled.Status = models.ContractStatusCancelled
+		cancelled.UpdatedAt = created.Add(time.Hour)
+
+		events := buildTimeline(&cancelled, nil, &models.ContractFunding{}, nil, 849000)
+
+		assert.Equal(t, []TimelineEventType{TimelineCreated, TimelineCancelled}, timelineTypes(events))
+	})
+}
@@ -0,0 +1,122 @@
+// internal/contract/timelock.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// SetRelativeTimelock times a contract that has not been set up from the
+// confirmation of its setup transaction: the high hash rate path opens
+// blocks after it confirms and the low hash rate path seconds after
+func (s *Service) SetRelativeTimelock(ctx context.Context, contract *models.Contract, blocks, seconds int64) error {
+	if contract.Status != models.ContractStatusCreated {
+		return errors.New("contract is already set up")
+	}
+
+	contract.TimeMode = models.ContractTimeModeRelative
+	contract.RelativeBlocks = blocks
+	contract.RelativeSeconds = seconds
+	if err := contract.Validate(); err != nil {
+		return fmt.Errorf("invalid contract: %w", err)
+	}
+
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		return fmt.Errorf("failed to set relative timelock: %w", err)
+	}
+
+	return nil
+}
+
+// setupLeaves returns the leaves of a contract's setup output, with
+// relative or absolute locks according to its time mode
+func (s *Service) setupLeaves(contract *models.Contract) ([][]byte, error) {
+	if contract.IsRelative() {
+		return s.taprootScriptBuilder.RelativeSetupLeaves(
+			contract.BuyerPubKey, contract.SellerPubKey, contract.RelativeBlocks, contract.RelativeSeconds)
+	}
+	return s.taprootScriptBuilder.SetupLeaves(
+		contract.BuyerPubKey, contract.SellerPubKey, contract.EndBlockHeight, contract.TargetTimestamp)
+}
+
+// buildSetupScript returns the address of a contract's setup output
+func (s *Service) buildSetupScript(contract *models.Contract) (string, error) {
+	if contract.IsRelative() {
+		return s.taprootScriptBuilder.BuildRelativeSetupScript(
+			contract.BuyerPubKey, contract.SellerPubKey, contract.RelativeBlocks, contract.RelativeSeconds)
+	}
+	return s.taprootScriptBuilder.BuildSetupScript(
+		contract.BuyerPubKey,
+		contract.SellerPubKey,
+		contract.StartBlockHeight,
+		contract.EndBlockHeight,
+		contract.TargetTimestamp,
+		contract.ContractType == models.ContractTypeCall,
+	)
+}
+
+// anchorSetup sets the end block height and target time of a relative
+// contract from the block its setup transaction confirmed in. Until then the
+// schedule is the estimate made when the setup was created. The final
+// output is locked to the anchored schedule with absolute locks, since its
+// outcome paths cannot open later than the setup's did.
+func (s *Service) anchorSetup(ctx context.Context, tx *models.ContractTransaction, depth int64) error {
+	if tx.TxType != "setup" && tx.TxType != "setup_onchain" {
+		return nil
+	}
+
+	contract, err := s.contractRepo.GetByID(ctx, tx.ContractID)
+	if err != nil {
+		return fmt.Errorf("failed to get contract: %w", err)
+	}
+	if !contract.IsRelative() || contract.FinalTxID != nil {
+		return nil
+	}
+
+	tip, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+	height := tip - depth + 1
+
+	hash, err := s.bitcoinClient.GetBlockHash(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to get block hash at height %d: %w", height, err)
+	}
+	block, err := s.bitcoinClient.GetBlock(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to get block %s: %w", hash, err)
+	}
+
+	contract.Anchor(height, block.Time)
+	if err := s.contractRepo.Update(ctx, contract); err != nil {
+		return fmt.Errorf("failed to anchor contract schedule: %w", err)
+	}
+
+	logger.Info().
+		Str("contract_id", contract.ID.String()).
+		Int64("end_block_height", contract.EndBlockHeight).
+		Time("target_timestamp", contract.TargetTimestamp).
+		Msg("Relative contract schedule anchored to setup confirmation")
+
+	return nil
+}
+
+// estimateAnchor sets a provisional schedule for a relative contract whose
+// setup is being created, as if it confirmed in the next block
+func (s *Service) estimateAnchor(ctx context.Context, contract *models.Contract) error {
+	if !contract.IsRelative() {
+		return nil
+	}
+
+	tip, err := s.CurrentBlockHeight(ctx)
+	if err != nil {
+		return err
+	}
+	contract.Anchor(tip+1, time.Now())
+	return nil
+}
@@ -0,0 +1,89 @@
+// internal/contract/timelock_test.go
+package contract
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+)
+
+// updatingContractStore also saves contract updates
+type updatingContractStore struct {
+	*stubContractStore
+}
+
+func (s updatingContractStore) Update(ctx context.Context, contract *models.Contract) error {
+	copied := *contract
+	s.contracts[contract.ID] = &copied
+	return nil
+}
+
+// blockChain serves blocks at consecutive heights from a fixed tip
+type blockChain struct {
+	ChainBackend
+	tip   int64
+	times map[int64]time.Time
+}
+
+func (c blockChain) GetBestBlockHash(ctx context.Context) (string, error) {
+	return c.GetBlockHash(ctx, c.tip)
+}
+
+func (c blockChain) GetBlockHash(ctx context.Context, height int64) (string, error) {
+	return fmt.Sprintf("%064x", height), nil
+}
+
+func (c blockChain) GetBlock(ctx context.Context, hash string) (*bitcoin.Block, error) {
+	var height int64
+	if _, err := fmt.Sscanf(hash, "%x", &height); err != nil {
+		return nil, err
+	}
+	return &bitcoin.Block{Hash: hash, Height: height, Time: c.times[height]}, nil
+}
+
+func TestAnchorSetup(t *testing.T) {
+	confirmedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	relative := &models.Contract{
+		ID:              uuid.New(),
+		Status:          models.ContractStatusActive,
+		TimeMode:        models.ContractTimeModeRelative,
+		RelativeBlocks:  1_008,
+		RelativeSeconds: 7 * 86_400,
+	}
+	absolute := &models.Contract{ID: uuid.New(), Status: models.ContractStatusActive, EndBlockHeight: 900_000}
+
+	contracts := updatingContractStore{&stubContractStore{contracts: map[uuid.UUID]*models.Contract{
+		relative.ID: relative,
+		absolute.ID: absolute,
+	}}}
+	s := &Service{
+		contractRepo:  contracts,
+		bitcoinClient: blockChain{tip: 850_002, times: map[int64]time.Time{850_000: confirmedAt}},
+	}
+
+	// Three confirmations at a tip of 850,002 put the setup in block 850,000
+	setup := &models.ContractTransaction{ContractID: relative.ID, TxType: "setup_onchain"}
+	require.NoError(t, s.anchorSetup(context.Background(), setup, 3))
+
+	anchored := contracts.contracts[relative.ID]
+	assert.Equal(t, int64(850_000), anchored.StartBlockHeight)
+	assert.Equal(t, int64(851_008), anchored.EndBlockHeight)
+	// 7 days round up to 1,182 units of 512 seconds
+	assert.Equal(t, confirmedAt.Add(1_182*512*time.Second), anchored.TargetTimestamp)
+	assert.Equal(t, anchored.TargetTimestamp.Add(24*time.Hour), anchored.ExpiresAt)
+
+	// Absolute contracts and other transactions keep their schedule
+	require.NoError(t, s.anchorSetup(context.Background(), &models.ContractTransaction{ContractID: absolute.ID, TxType: "setup"}, 3))
+	assert.Equal(t, int64(900_000), contracts.contracts[absolute.ID].EndBlockHeight)
+
+	require.NoError(t, s.anchorSetup(context.Background(), &models.ContractTransaction{ContractID: relative.ID, TxType: "final"}, 1))
+	assert.Equal(t, int64(851_008), contracts.contracts[relative.ID].EndBlockHeight)
+}
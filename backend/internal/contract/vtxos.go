@@ -0,0 +1,107 @@
+// internal/contract/vtxos.go
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ErrVTXOsNotEnabled is returned when no VTXO store is configured
+var ErrVTXOsNotEnabled = errors.New("VTXO tracking is not enabled")
+
+// defaultASP names the ASP of a client connected to a single one
+const defaultASP = "primary"
+
+// ASPRouter is implemented by Ark clients routing to one of several ASPs
+type ASPRouter interface {
+	// Primary names the ASP calls are routed to, which issues new VTXOs
+	Primary() string
+}
+
+// issuingASP names the ASP new VTXOs are issued by
+func (s *Service) issuingASP() string {
+	if router, ok := s.arkClient.(ASPRouter); ok {
+		return router.Primary()
+	}
+	return defaultASP
+}
+
+// WithVTXOStore enables tracking of the Ark VTXOs holding contract funds,
+// which exits and swaps need to reference the contract's actual VTXO
+func (s *Service) WithVTXOStore(store VTXOStore) *Service {
+	s.vtxoRepo = store
+	return s
+}
+
+// ContractVTXO returns the VTXO currently holding a contract's funds
+func (s *Service) ContractVTXO(ctx context.Context, contractID uuid.UUID) (*models.VTXO, error) {
+	if s.vtxoRepo == nil {
+		return nil, ErrVTXOsNotEnabled
+	}
+
+	vtxo, err := s.vtxoRepo.GetActiveByContract(ctx, contractID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract VTXO: %w", err)
+	}
+
+	return vtxo, nil
+}
+
+// recordRoundVTXO records the VTXO a round creates for a contract output.
+// The ASP identifies an output registered for a round by the round and the
+// output's index in the registration.
+func (s *Service) recordRoundVTXO(ctx context.Context, contract *models.Contract, roundID string, vout int, output string) error {
+	if s.vtxoRepo == nil {
+		return nil
+	}
+
+	vtxo := &models.VTXO{
+		ContractID: contract.ID,
+		VTXOID:     models.VTXOOutpoint(roundID, vout),
+		RoundID:    roundID,
+		Amount:     contract.ContractSize,
+		Script:     output,
+		ASP:        s.issuingASP(),
+	}
+	if err := vtxo.Validate(); err != nil {
+		return fmt.Errorf("invalid VTXO: %w", err)
+	}
+
+	if err := s.vtxoRepo.Create(ctx, vtxo); err != nil {
+		return fmt.Errorf("failed to record VTXO: %w", err)
+	}
+
+	return nil
+}
+
+// replaceVTXO records that an out-of-round transaction spent a contract's
+// VTXO into output vout. The new VTXO stays in the tree of the spent VTXO's
+// round until it is refreshed.
+func (s *Service) replaceVTXO(ctx context.Context, spent *models.VTXO, txid string, vout int, output string) error {
+	if s.vtxoRepo == nil {
+		return nil
+	}
+
+	next := &models.VTXO{
+		ContractID: spent.ContractID,
+		VTXOID:     models.VTXOOutpoint(txid, vout),
+		RoundID:    spent.RoundID,
+		Amount:     spent.Amount,
+		Script:     output,
+		ASP:        spent.ASP,
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid VTXO: %w", err)
+	}
+
+	if err := s.vtxoRepo.Replace(ctx, spent.VTXOID, next); err != nil {
+		return fmt.Errorf("failed to replace VTXO: %w", err)
+	}
+
+	return nil
+}
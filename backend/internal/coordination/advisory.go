@@ -0,0 +1,173 @@
+// internal/coordination/advisory.go
+package coordination
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
+)
+
+var logger = logging.Component(logging.OrderBook)
+
+// Coordination modes
+const (
+	// ModeSingle runs one instance, which matches every market
+	ModeSingle = "single"
+	// ModeAdvisoryLock lets several instances share the database, each
+	// market matched by the instance holding its PostgreSQL advisory lock
+	ModeAdvisoryLock = "advisory_lock"
+)
+
+// Config selects how instances sharing a database divide the matching of
+// markets
+type Config struct {
+	Mode string `yaml:"mode"`
+	// Matcher lets this instance take over markets; false serves reads only
+	Matcher bool `yaml:"matcher"`
+	// CheckInterval is how often the connection holding the locks is
+	// checked. A lost connection releases every lock, so the instance stops
+	// matching until it claims its markets again.
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// DefaultConfig runs a single instance
+var DefaultConfig = Config{
+	Mode:          ModeSingle,
+	Matcher:       true,
+	CheckInterval: 5 * time.Second,
+}
+
+// Enabled reports whether markets are divided among instances
+func (c Config) Enabled() bool {
+	return c.Mode == ModeAdvisoryLock
+}
+
+// Validate checks the mode and that the lock connection is checked
+func (c Config) Validate() error {
+	switch c.Mode {
+	case ModeSingle:
+		return nil
+	case ModeAdvisoryLock:
+		if c.CheckInterval <= 0 {
+			return fmt.Errorf("coordination check interval must be positive")
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid coordination mode %q, expected %s or %s", c.Mode, ModeSingle, ModeAdvisoryLock)
+	}
+}
+
+// AdvisoryLocks claims markets with session-level PostgreSQL advisory locks
+// held on one dedicated connection. A market's lock is taken the first time
+// the instance receives an order for it and kept until the instance stops
+// or loses the connection, when another instance takes the market over.
+type AdvisoryLocks struct {
+	db      *db.DB
+	matcher bool
+
+	mu   sync.Mutex
+	conn *sql.Conn
+	held map[string]bool
+}
+
+// NewAdvisoryLocks creates the advisory locks of an instance. An instance
+// that is not a matcher never claims a market.
+func NewAdvisoryLocks(database *db.DB, matcher bool) *AdvisoryLocks {
+	return &AdvisoryLocks{
+		db:      database,
+		matcher: matcher,
+		held:    make(map[string]bool),
+	}
+}
+
+// Claim implements orderbook.Coordinator
+func (l *AdvisoryLocks) Claim(ctx context.Context, market string) (bool, bool, error) {
+	if !l.matcher {
+		return false, false, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[market] {
+		return true, false, nil
+	}
+
+	if l.conn == nil {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return false, false, fmt.Errorf("failed to open lock connection: %w", err)
+		}
+		l.conn = conn
+	}
+
+	var locked bool
+	err := l.conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, market).Scan(&locked)
+	if err != nil {
+		// The connection may be broken, and every lock with it
+		l.dropLocked()
+		return false, false, fmt.Errorf("failed to take market lock: %w", err)
+	}
+	if !locked {
+		return false, false, nil
+	}
+
+	l.held[market] = true
+	metrics.ClaimedMarkets.Set(float64(len(l.held)))
+	logger.Info().Str("market", market).Msg("Claimed market")
+	return true, true, nil
+}
+
+// Start checks the lock connection on interval until ctx is cancelled, then
+// closes it, releasing every lock
+func (l *AdvisoryLocks) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				l.mu.Lock()
+				l.dropLocked()
+				l.mu.Unlock()
+				return
+			case <-ticker.C:
+				l.check(ctx)
+			}
+		}
+	}()
+}
+
+// check drops every claim if the lock connection no longer answers
+func (l *AdvisoryLocks) check(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		logger.Error().Err(err).Int("markets", len(l.held)).Msg("Lost market lock connection; markets will be claimed again")
+		l.dropLocked()
+	}
+}
+
+// dropLocked closes the lock connection, which releases its locks, and
+// forgets every claim. The caller must hold l.mu.
+func (l *AdvisoryLocks) dropLocked() {
+	if l.conn != nil {
+		if err := l.conn.Close(); err != nil {
+			logger.Warn().Err(err).Msg("Failed to close market lock connection")
+		}
+		l.conn = nil
+	}
+	l.held = make(map[string]bool)
+	metrics.ClaimedMarkets.Set(0)
+}
@@ -0,0 +1,86 @@
+// internal/db/api_key_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// APIKeyRepository provides access to API keys
+type APIKeyRepository struct {
+	db *DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create inserts a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	key.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO api_keys (id, user_id, name, prefix, key_hash, created_at)
+		VALUES (:id, :user_id, :name, :prefix, :key_hash, :created_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, key); err != nil {
+		return wrapError("failed to create API key", err)
+	}
+
+	return nil
+}
+
+// GetActiveByHash retrieves an unrevoked API key by the hash of its value
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+
+	query := `SELECT * FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+	if err := r.db.GetContext(ctx, &key, query, keyHash); err != nil {
+		return nil, wrapError("failed to get API key", err)
+	}
+
+	return &key, nil
+}
+
+// ListByUser retrieves the API keys of a user, newest first
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+
+	query := `SELECT * FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, wrapError("failed to list API keys", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke disables an API key of a user
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to revoke API key", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("API key %s: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+// internal/db/audit_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// AuditRepository provides access to the audit log
+type AuditRepository struct {
+	db *DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AuditFilter selects audit log entries. Zero fields match every entry.
+type AuditFilter struct {
+	UserID       *uuid.UUID
+	ResourceType string
+	ResourceID   *uuid.UUID
+	From         time.Time
+	To           time.Time
+}
+
+// Create records an audit log entry
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO audit_log (
+			id, user_id, api_key_id, method, path, resource_type, resource_id,
+			request, before, after, status, remote_addr, request_id, created_at
+		) VALUES (
+			:id, :user_id, :api_key_id, :method, :path, :resource_type, :resource_id,
+			:request, :before, :after, :status, :remote_addr, :request_id, :created_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, entry); err != nil {
+		return wrapError("failed to create audit log entry", err)
+	}
+
+	return nil
+}
+
+// List retrieves the audit log entries matching a filter, oldest first, so
+// the history of a resource reads in the order it happened
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter, limit, offset int) ([]*models.AuditEntry, error) {
+	var entries []*models.AuditEntry
+
+	var from, to *time.Time
+	if !filter.From.IsZero() {
+		from = &filter.From
+	}
+	if !filter.To.IsZero() {
+		to = &filter.To
+	}
+
+	query := `
+		SELECT * FROM audit_log
+		WHERE ($1::uuid IS NULL OR user_id = $1)
+			AND ($2 = '' OR resource_type = $2)
+			AND ($3::uuid IS NULL OR resource_id = $3)
+			AND ($4::timestamptz IS NULL OR created_at >= $4)
+			AND ($5::timestamptz IS NULL OR created_at < $5)
+		ORDER BY created_at, id
+		LIMIT $6 OFFSET $7
+	`
+
+	err := r.db.SelectContext(ctx, &entries, query,
+		filter.UserID, filter.ResourceType, filter.ResourceID, from, to, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to list audit log entries", err)
+	}
+
+	return entries, nil
+}
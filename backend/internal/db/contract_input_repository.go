@@ -0,0 +1,64 @@
+// internal/db/contract_input_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ContractInputRepository provides access to the inputs funding contracts
+type ContractInputRepository struct {
+	db *DB
+}
+
+// NewContractInputRepository creates a new contract input repository
+func NewContractInputRepository(db *DB) *ContractInputRepository {
+	return &ContractInputRepository{db: db}
+}
+
+// AddInput records an input of a contract
+func (r *ContractInputRepository) AddInput(ctx context.Context, input *models.ContractInput) error {
+	if input.ID == uuid.Nil {
+		input.ID = uuid.New()
+	}
+	input.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO contract_inputs (
+			id, contract_id, stage, source, txid, vout, value, leaf_index, created_at
+		) VALUES (
+			:id, :contract_id, :stage, :source, :txid, :vout, :value, :leaf_index, :created_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, input); err != nil {
+		return wrapError("failed to add contract input", err)
+	}
+
+	return nil
+}
+
+// ListInputs retrieves the inputs of a contract at a stage, in the order they were added
+func (r *ContractInputRepository) ListInputs(
+	ctx context.Context,
+	contractID uuid.UUID,
+	stage models.InputStage,
+) ([]*models.ContractInput, error) {
+	var inputs []*models.ContractInput
+
+	query := `
+		SELECT * FROM contract_inputs
+		WHERE contract_id = $1 AND stage = $2
+		ORDER BY created_at, txid, vout
+	`
+
+	if err := r.db.SelectContext(ctx, &inputs, query, contractID, stage); err != nil {
+		return nil, wrapError("failed to list contract inputs", err)
+	}
+
+	return inputs, nil
+}
@@ -3,7 +3,6 @@ package db
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,18 +32,22 @@ func (r *ContractRepository) Create(ctx context.Context, contract *models.Contra
 	query := `
 		INSERT INTO contracts (
 			id, contract_type, strike_hash_rate, start_block_height, end_block_height,
-			target_timestamp, contract_size, premium, buyer_pub_key, seller_pub_key,
+			target_timestamp, time_mode, relative_blocks, relative_seconds,
+			contract_size, premium, buyer_pub_key, seller_pub_key,
+			buyer_key_id, seller_key_id, notional_unit, notional_quantity, settlement_currency,
 			status, created_at, updated_at, expires_at, setup_tx_id, final_tx_id, settlement_tx_id
 		) VALUES (
 			:id, :contract_type, :strike_hash_rate, :start_block_height, :end_block_height,
-			:target_timestamp, :contract_size, :premium, :buyer_pub_key, :seller_pub_key,
+			:target_timestamp, :time_mode, :relative_blocks, :relative_seconds,
+			:contract_size, :premium, :buyer_pub_key, :seller_pub_key,
+			:buyer_key_id, :seller_key_id, :notional_unit, :notional_quantity, :settlement_currency,
 			:status, :created_at, :updated_at, :expires_at, :setup_tx_id, :final_tx_id, :settlement_tx_id
 		)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, contract)
 	if err != nil {
-		return fmt.Errorf("failed to create contract: %w", err)
+		return wrapError("failed to create contract", err)
 	}
 
 	return nil
@@ -57,7 +60,7 @@ func (r *ContractRepository) GetByID(ctx context.Context, id uuid.UUID) (*models
 	query := `SELECT * FROM contracts WHERE id = $1`
 	err := r.db.GetContext(ctx, &contract, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get contract by ID: %w", err)
+		return nil, wrapError("failed to get contract by ID", err)
 	}
 
 	return &contract, nil
@@ -74,10 +77,15 @@ func (r *ContractRepository) Update(ctx context.Context, contract *models.Contra
 			start_block_height = :start_block_height,
 			end_block_height = :end_block_height,
 			target_timestamp = :target_timestamp,
+			time_mode = :time_mode,
+			relative_blocks = :relative_blocks,
+			relative_seconds = :relative_seconds,
 			contract_size = :contract_size,
 			premium = :premium,
 			buyer_pub_key = :buyer_pub_key,
 			seller_pub_key = :seller_pub_key,
+			buyer_key_id = :buyer_key_id,
+			seller_key_id = :seller_key_id,
 			status = :status,
 			updated_at = :updated_at,
 			expires_at = :expires_at,
@@ -89,7 +97,7 @@ func (r *ContractRepository) Update(ctx context.Context, contract *models.Contra
 
 	_, err := r.db.NamedExecContext(ctx, query, contract)
 	if err != nil {
-		return fmt.Errorf("failed to update contract: %w", err)
+		return wrapError("failed to update contract", err)
 	}
 
 	return nil
@@ -106,7 +114,7 @@ func (r *ContractRepository) UpdateStatus(ctx context.Context, id uuid.UUID, sta
 
 	_, err := r.db.ExecContext(ctx, query, status, time.Now().UTC(), id)
 	if err != nil {
-		return fmt.Errorf("failed to update contract status: %w", err)
+		return wrapError("failed to update contract status", err)
 	}
 
 	return nil
@@ -125,12 +133,46 @@ func (r *ContractRepository) ListByStatus(ctx context.Context, status models.Con
 
 	err := r.db.SelectContext(ctx, &contracts, query, status, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list contracts by status: %w", err)
+		return nil, wrapError("failed to list contracts by status", err)
 	}
 
 	return contracts, nil
 }
 
+// ListByStatusPage retrieves a page of contracts in a status, newest first,
+// after the cursor
+func (r *ContractRepository) ListByStatusPage(ctx context.Context, status models.ContractStatus, after models.Cursor, limit int) ([]*models.Contract, error) {
+	var contracts []*models.Contract
+
+	query := `
+		SELECT * FROM contracts
+		WHERE status = $1 AND (created_at, id) < ($2, $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
+	`
+
+	at, id := before(after)
+	err := r.db.SelectContext(ctx, &contracts, query, status, at, id, limit)
+	if err != nil {
+		return nil, wrapError("failed to list contracts by status", err)
+	}
+
+	return contracts, nil
+}
+
+// CountWithStatus counts the contracts in a status
+func (r *ContractRepository) CountWithStatus(ctx context.Context, status models.ContractStatus) (int64, error) {
+	var count int64
+
+	query := `SELECT COUNT(*) FROM contracts WHERE status = $1`
+	err := r.db.GetContext(ctx, &count, query, status)
+	if err != nil {
+		return 0, wrapError("failed to count contracts by status", err)
+	}
+
+	return count, nil
+}
+
 // AddTransaction adds a transaction associated with a contract
 func (r *ContractRepository) AddTransaction(ctx context.Context, tx *models.ContractTransaction) error {
 	if tx.ID == uuid.Nil {
@@ -140,15 +182,15 @@ func (r *ContractRepository) AddTransaction(ctx context.Context, tx *models.Cont
 
 	query := `
 		INSERT INTO contract_transactions (
-			id, contract_id, transaction_id, tx_type, tx_hex, confirmed, created_at, confirmed_at
+			id, contract_id, transaction_id, tx_type, tx_hex, spend_psbt, confirmed, created_at, confirmed_at
 		) VALUES (
-			:id, :contract_id, :transaction_id, :tx_type, :tx_hex, :confirmed, :created_at, :confirmed_at
+			:id, :contract_id, :transaction_id, :tx_type, :tx_hex, :spend_psbt, :confirmed, :created_at, :confirmed_at
 		)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, tx)
 	if err != nil {
-		return fmt.Errorf("failed to add contract transaction: %w", err)
+		return wrapError("failed to add contract transaction", err)
 	}
 
 	return nil
@@ -167,7 +209,7 @@ func (r *ContractRepository) ConfirmTransaction(ctx context.Context, txID string
 
 	_, err := r.db.ExecContext(ctx, query, now, txID)
 	if err != nil {
-		return fmt.Errorf("failed to confirm transaction: %w", err)
+		return wrapError("failed to confirm transaction", err)
 	}
 
 	return nil
@@ -191,12 +233,46 @@ func (r *ContractRepository) GetTransactionsByContractID(ctx context.Context, co
 
 	err := r.db.SelectContext(ctx, &transactions, query, contractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transactions for contract: %w", err)
+		return nil, wrapError("failed to get transactions for contract", err)
 	}
 
 	return transactions, nil
 }
 
+// ListTransactionsByType retrieves a page of a contract's transactions of
+// one type, newest first, after the cursor
+func (r *ContractRepository) ListTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string, after models.Cursor, limit int) ([]*models.ContractTransaction, error) {
+	var transactions []*models.ContractTransaction
+
+	query := `
+		SELECT * FROM contract_transactions
+		WHERE contract_id = $1 AND tx_type = $2 AND (created_at, id) < ($3, $4)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`
+
+	at, id := before(after)
+	err := r.db.SelectContext(ctx, &transactions, query, contractID, txType, at, id, limit)
+	if err != nil {
+		return nil, wrapError("failed to list transactions by type", err)
+	}
+
+	return transactions, nil
+}
+
+// CountTransactionsByType counts a contract's transactions of one type
+func (r *ContractRepository) CountTransactionsByType(ctx context.Context, contractID uuid.UUID, txType string) (int64, error) {
+	var count int64
+
+	query := `SELECT COUNT(*) FROM contract_transactions WHERE contract_id = $1 AND tx_type = $2`
+	err := r.db.GetContext(ctx, &count, query, contractID, txType)
+	if err != nil {
+		return 0, wrapError("failed to count transactions by type", err)
+	}
+
+	return count, nil
+}
+
 // GetTransactionByID retrieves a specific transaction by its ID
 func (r *ContractRepository) GetTransactionByID(ctx context.Context, txID uuid.UUID) (*models.ContractTransaction, error) {
 	var tx models.ContractTransaction
@@ -204,12 +280,48 @@ func (r *ContractRepository) GetTransactionByID(ctx context.Context, txID uuid.U
 	query := `SELECT * FROM contract_transactions WHERE id = $1`
 	err := r.db.GetContext(ctx, &tx, query, txID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transaction by ID: %w", err)
+		return nil, wrapError("failed to get transaction by ID", err)
 	}
 
 	return &tx, nil
 }
 
+// ListSettledSince retrieves settled contracts whose end block height is at or above sinceHeight
+func (r *ContractRepository) ListSettledSince(ctx context.Context, sinceHeight int64) ([]*models.Contract, error) {
+	var contracts []*models.Contract
+
+	query := `
+		SELECT * FROM contracts
+		WHERE status = $1 AND end_block_height >= $2
+		ORDER BY end_block_height ASC
+	`
+
+	err := r.db.SelectContext(ctx, &contracts, query, models.ContractStatusSettled, sinceHeight)
+	if err != nil {
+		return nil, wrapError("failed to list settled contracts", err)
+	}
+
+	return contracts, nil
+}
+
+// ListUnconfirmedTransactions retrieves contract transactions that have not been confirmed on-chain
+func (r *ContractRepository) ListUnconfirmedTransactions(ctx context.Context) ([]*models.ContractTransaction, error) {
+	var transactions []*models.ContractTransaction
+
+	query := `
+		SELECT * FROM contract_transactions
+		WHERE confirmed = FALSE
+		ORDER BY created_at ASC
+	`
+
+	err := r.db.SelectContext(ctx, &transactions, query)
+	if err != nil {
+		return nil, wrapError("failed to list unconfirmed transactions", err)
+	}
+
+	return transactions, nil
+}
+
 // CountActiveContracts counts the number of active contracts
 func (r *ContractRepository) CountActiveContracts(ctx context.Context) (int, error) {
 	var count int
@@ -221,12 +333,37 @@ func (r *ContractRepository) CountActiveContracts(ctx context.Context) (int, err
 
 	err := r.db.GetContext(ctx, &count, query, models.ContractStatusActive)
 	if err != nil {
-		return 0, fmt.Errorf("failed to count active contracts: %w", err)
+		return 0, wrapError("failed to count active contracts", err)
 	}
 
 	return count, nil
 }
 
+// CountByStatus counts the contracts in each status
+func (r *ContractRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	query := `
+		SELECT status, COUNT(*) AS count FROM contracts
+		GROUP BY status
+	`
+
+	err := r.db.SelectContext(ctx, &rows, query)
+	if err != nil {
+		return nil, wrapError("failed to count contracts by status", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
 // ExecuteInTransaction executes the given function within a database transaction
 func (r *ContractRepository) ExecuteInTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
 	return r.db.WithTransaction(ctx, fn)
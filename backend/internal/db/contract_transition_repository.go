@@ -0,0 +1,62 @@
+// internal/db/contract_transition_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ContractTransitionRepository records the status history of contracts
+type ContractTransitionRepository struct {
+	db *DB
+}
+
+// NewContractTransitionRepository creates a new contract transition repository
+func NewContractTransitionRepository(db *DB) *ContractTransitionRepository {
+	return &ContractTransitionRepository{db: db}
+}
+
+// Create inserts a status transition
+func (r *ContractTransitionRepository) Create(ctx context.Context, transition *models.ContractTransition) error {
+	if transition.ID == uuid.Nil {
+		transition.ID = uuid.New()
+	}
+	if transition.CreatedAt.IsZero() {
+		transition.CreatedAt = time.Now().UTC()
+	}
+
+	query := `
+		INSERT INTO contract_status_transitions (
+			id, contract_id, from_status, to_status, reason, created_at
+		) VALUES (
+			:id, :contract_id, :from_status, :to_status, :reason, :created_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, transition); err != nil {
+		return wrapError("failed to create contract transition", err)
+	}
+
+	return nil
+}
+
+// ListByContractID retrieves the transitions of a contract, oldest first
+func (r *ContractTransitionRepository) ListByContractID(ctx context.Context, contractID uuid.UUID) ([]*models.ContractTransition, error) {
+	var transitions []*models.ContractTransition
+
+	query := `
+		SELECT * FROM contract_status_transitions
+		WHERE contract_id = $1
+		ORDER BY created_at, id
+	`
+
+	if err := r.db.SelectContext(ctx, &transitions, query, contractID); err != nil {
+		return nil, wrapError("failed to list contract transitions", err)
+	}
+
+	return transitions, nil
+}
@@ -0,0 +1,207 @@
+// internal/db/deposit_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// DepositRepository stores users' deposit addresses and the deposits they
+// receive on chain
+type DepositRepository struct {
+	db *DB
+}
+
+// NewDepositRepository creates a new deposit repository
+func NewDepositRepository(db *DB) *DepositRepository {
+	return &DepositRepository{db: db}
+}
+
+// NextIndex reserves the next derivation index of a deposit address
+func (r *DepositRepository) NextIndex(ctx context.Context) (int64, error) {
+	var index int64
+	if err := r.db.GetContext(ctx, &index, `SELECT nextval('deposit_address_index')`); err != nil {
+		return 0, wrapError("failed to reserve deposit address index", err)
+	}
+	return index, nil
+}
+
+// CreateAddress stores a user's deposit address unless they already have
+// one, returning the address the user ends up with
+func (r *DepositRepository) CreateAddress(ctx context.Context, address *models.DepositAddress) (*models.DepositAddress, error) {
+	address.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO deposit_addresses (user_id, derivation_index, address, script_pub_key, created_at)
+		VALUES (:user_id, :derivation_index, :address, :script_pub_key, :created_at)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+	if _, err := r.db.NamedExecContext(ctx, query, address); err != nil {
+		return nil, wrapError("failed to create deposit address", err)
+	}
+
+	return r.GetAddress(ctx, address.UserID)
+}
+
+// GetAddress retrieves a user's deposit address
+func (r *DepositRepository) GetAddress(ctx context.Context, userID uuid.UUID) (*models.DepositAddress, error) {
+	var address models.DepositAddress
+
+	query := `SELECT * FROM deposit_addresses WHERE user_id = $1`
+	if err := r.db.GetContext(ctx, &address, query, userID); err != nil {
+		return nil, wrapError("failed to get deposit address", err)
+	}
+
+	return &address, nil
+}
+
+// ListAddresses retrieves every deposit address, to match outputs against
+func (r *DepositRepository) ListAddresses(ctx context.Context) ([]*models.DepositAddress, error) {
+	var addresses []*models.DepositAddress
+
+	query := `SELECT * FROM deposit_addresses ORDER BY derivation_index`
+	if err := r.db.SelectContext(ctx, &addresses, query); err != nil {
+		return nil, wrapError("failed to list deposit addresses", err)
+	}
+
+	return addresses, nil
+}
+
+// GetScanHeight returns the height of the last block searched for
+// deposits, and false if no block has been
+func (r *DepositRepository) GetScanHeight(ctx context.Context) (int64, bool, error) {
+	var height int64
+
+	err := r.db.GetContext(ctx, &height, `SELECT height FROM deposit_scan`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, wrapError("failed to get deposit scan height", err)
+	}
+
+	return height, true, nil
+}
+
+// SetScanHeight records the height of the last block searched for deposits
+func (r *DepositRepository) SetScanHeight(ctx context.Context, height int64) error {
+	query := `
+		INSERT INTO deposit_scan (id, height, updated_at) VALUES (TRUE, $1, $2)
+		ON CONFLICT (id) DO UPDATE SET height = EXCLUDED.height, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, height, time.Now().UTC()); err != nil {
+		return wrapError("failed to set deposit scan height", err)
+	}
+	return nil
+}
+
+// RewindScanHeight moves the scan back to height so the blocks after it are
+// searched again
+func (r *DepositRepository) RewindScanHeight(ctx context.Context, height int64) error {
+	query := `UPDATE deposit_scan SET height = LEAST(height, $1), updated_at = $2`
+	if _, err := r.db.ExecContext(ctx, query, height, time.Now().UTC()); err != nil {
+		return wrapError("failed to rewind deposit scan height", err)
+	}
+	return nil
+}
+
+// RecordDeposit stores a pending deposit, reporting false if the output was
+// already recorded
+func (r *DepositRepository) RecordDeposit(ctx context.Context, deposit *models.Deposit) (bool, error) {
+	if deposit.ID == uuid.Nil {
+		deposit.ID = uuid.New()
+	}
+	deposit.Status = models.DepositStatusPending
+	deposit.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO deposits (id, user_id, txid, vout, amount, block_hash, block_height, status, created_at)
+		VALUES (:id, :user_id, :txid, :vout, :amount, :block_hash, :block_height, :status, :created_at)
+		ON CONFLICT (txid, vout) DO NOTHING
+	`
+	result, err := r.db.NamedExecContext(ctx, query, deposit)
+	if err != nil {
+		return false, wrapError("failed to record deposit", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, wrapError("failed to record deposit", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ListPending retrieves the pending deposits in blocks at or below maxHeight
+func (r *DepositRepository) ListPending(ctx context.Context, maxHeight int64) ([]*models.Deposit, error) {
+	var deposits []*models.Deposit
+
+	query := `
+		SELECT * FROM deposits
+		WHERE status = $1 AND block_height <= $2
+		ORDER BY block_height, txid, vout
+	`
+	if err := r.db.SelectContext(ctx, &deposits, query, models.DepositStatusPending, maxHeight); err != nil {
+		return nil, wrapError("failed to list pending deposits", err)
+	}
+
+	return deposits, nil
+}
+
+// Credit marks a pending deposit credited and runs fn in the same
+// transaction, so the deposit is credited exactly once
+func (r *DepositRepository) Credit(ctx context.Context, id uuid.UUID, fn func(*sqlx.Tx) error) error {
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE deposits SET status = $2, credited_at = $3
+			WHERE id = $1 AND status = $4
+		`, id, models.DepositStatusCredited, time.Now().UTC(), models.DepositStatusPending)
+		if err != nil {
+			return wrapError("failed to credit deposit", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return wrapError("failed to credit deposit", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("pending deposit %s: %w", id, ErrNotFound)
+		}
+
+		return fn(tx)
+	})
+}
+
+// DeleteDeposit removes a pending deposit whose block left the chain
+func (r *DepositRepository) DeleteDeposit(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM deposits WHERE id = $1 AND status = $2`
+	if _, err := r.db.ExecContext(ctx, query, id, models.DepositStatusPending); err != nil {
+		return wrapError("failed to delete deposit", err)
+	}
+	return nil
+}
+
+// ListByUser retrieves a page of a user's deposits, newest first
+func (r *DepositRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Deposit, error) {
+	var deposits []*models.Deposit
+
+	query := `
+		SELECT * FROM deposits
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	if err := r.db.SelectContext(ctx, &deposits, query, userID, limit, offset); err != nil {
+		return nil, wrapError("failed to list deposits", err)
+	}
+
+	return deposits, nil
+}
@@ -0,0 +1,169 @@
+// internal/db/device_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// DeviceRepository provides access to push device tokens and preferences
+type DeviceRepository struct {
+	db *DB
+}
+
+// NewDeviceRepository creates a new device repository
+func NewDeviceRepository(db *DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+// Register stores a device token. Re-registering a known token moves it to
+// the given user, since a device changes hands when another user signs in.
+func (r *DeviceRepository) Register(ctx context.Context, device *models.DeviceToken) error {
+	now := time.Now().UTC()
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+	device.CreatedAt = now
+	device.UpdatedAt = now
+
+	query := `
+		INSERT INTO device_tokens (id, user_id, platform, token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (token) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, device, query,
+		device.ID, device.UserID, device.Platform, device.Token, device.CreatedAt, device.UpdatedAt)
+	if err != nil {
+		return wrapError("failed to register device", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves the devices of a user
+func (r *DeviceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.DeviceToken, error) {
+	var devices []*models.DeviceToken
+
+	query := `SELECT * FROM device_tokens WHERE user_id = $1 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &devices, query, userID); err != nil {
+		return nil, wrapError("failed to list devices", err)
+	}
+
+	return devices, nil
+}
+
+// Delete removes a device of a user
+func (r *DeviceRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return wrapError("failed to delete device", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("device %s: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
+
+// DeleteByToken removes a token the push service reported as no longer valid
+func (r *DeviceRepository) DeleteByToken(ctx context.Context, token string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM device_tokens WHERE token = $1`, token); err != nil {
+		return wrapError("failed to delete device token", err)
+	}
+	return nil
+}
+
+// ListPreferences retrieves a user's preference for every event type,
+// defaulting to enabled for types without a stored preference
+func (r *DeviceRepository) ListPreferences(ctx context.Context, userID uuid.UUID) ([]*models.PushPreference, error) {
+	var stored []*models.PushPreference
+
+	query := `SELECT * FROM push_preferences WHERE user_id = $1`
+	if err := r.db.SelectContext(ctx, &stored, query, userID); err != nil {
+		return nil, wrapError("failed to list push preferences", err)
+	}
+
+	byType := make(map[models.PushEventType]bool, len(stored))
+	for _, pref := range stored {
+		byType[pref.EventType] = pref.Enabled
+	}
+
+	prefs := make([]*models.PushPreference, 0, len(models.PushEventTypes))
+	for _, eventType := range models.PushEventTypes {
+		enabled, ok := byType[eventType]
+		prefs = append(prefs, &models.PushPreference{
+			UserID:    userID,
+			EventType: eventType,
+			Enabled:   enabled || !ok,
+		})
+	}
+
+	return prefs, nil
+}
+
+// SetPreference enables or disables an event type for a user
+func (r *DeviceRepository) SetPreference(ctx context.Context, pref *models.PushPreference) error {
+	query := `
+		INSERT INTO push_preferences (user_id, event_type, enabled)
+		VALUES (:user_id, :event_type, :enabled)
+		ON CONFLICT (user_id, event_type) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, pref); err != nil {
+		return wrapError("failed to set push preference", err)
+	}
+
+	return nil
+}
+
+// IsEnabled reports whether a user receives pushes for an event type
+func (r *DeviceRepository) IsEnabled(ctx context.Context, userID uuid.UUID, eventType models.PushEventType) (bool, error) {
+	var enabled bool
+
+	query := `SELECT enabled FROM push_preferences WHERE user_id = $1 AND event_type = $2`
+	err := r.db.GetContext(ctx, &enabled, query, userID, eventType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, wrapError("failed to get push preference", err)
+	}
+
+	return enabled, nil
+}
+
+// ListContractParties retrieves the users whose orders were filled into a contract
+func (r *DeviceRepository) ListContractParties(ctx context.Context, contractID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+
+	query := `
+		SELECT DISTINCT o.user_id
+		FROM trades t
+		JOIN orders o ON o.id = t.buy_order_id OR o.id = t.sell_order_id
+		WHERE t.contract_id = $1
+	`
+
+	if err := r.db.SelectContext(ctx, &userIDs, query, contractID); err != nil {
+		return nil, wrapError("failed to list contract parties", err)
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,37 @@
+// internal/db/errors.go
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrNotFound is returned when a query matches no row
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a write violates a uniqueness or state constraint
+	ErrConflict = errors.New("conflict")
+)
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// wrapError annotates a query error with msg, translating a missing row into
+// ErrNotFound and a unique constraint violation into ErrConflict so callers
+// can tell them apart from query failures with errors.Is
+func wrapError(msg string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", msg, ErrNotFound)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%s: %w: %w", msg, ErrConflict, err)
+	}
+
+	return fmt.Errorf("%s: %w", msg, err)
+}
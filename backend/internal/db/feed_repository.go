@@ -0,0 +1,80 @@
+// internal/db/feed_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// FeedRepository persists observations sampled from external data feeds
+type FeedRepository struct {
+	db *DB
+}
+
+// NewFeedRepository creates a new feed repository
+func NewFeedRepository(db *DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+// InsertObservations stores a batch of observations in a single transaction
+func (r *FeedRepository) InsertObservations(ctx context.Context, observations []*models.FeedObservation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO feed_observations (feed, series, value, observed_at)
+		VALUES (:feed, :series, :value, :observed_at)
+	`
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		for _, obs := range observations {
+			if _, err := tx.NamedExecContext(ctx, query, obs); err != nil {
+				return wrapError("failed to insert feed observation", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListObservations retrieves observations of a feed since the given time, newest first.
+// An empty series returns observations of every series in the feed.
+func (r *FeedRepository) ListObservations(
+	ctx context.Context,
+	feed string,
+	series string,
+	since time.Time,
+	limit int,
+) ([]*models.FeedObservation, error) {
+	var observations []*models.FeedObservation
+
+	query := `
+		SELECT * FROM feed_observations
+		WHERE feed = $1 AND ($2 = '' OR series = $2) AND observed_at >= $3
+		ORDER BY observed_at DESC
+		LIMIT $4
+	`
+
+	err := r.db.SelectContext(ctx, &observations, query, feed, series, since, limit)
+	if err != nil {
+		return nil, wrapError("failed to list feed observations", err)
+	}
+
+	return observations, nil
+}
+
+// ListSeries retrieves the distinct series recorded for a feed
+func (r *FeedRepository) ListSeries(ctx context.Context, feed string) ([]string, error) {
+	var series []string
+
+	query := `SELECT DISTINCT series FROM feed_observations WHERE feed = $1 ORDER BY series`
+	if err := r.db.SelectContext(ctx, &series, query, feed); err != nil {
+		return nil, wrapError("failed to list feed series", err)
+	}
+
+	return series, nil
+}
@@ -0,0 +1,83 @@
+// internal/db/funding_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// FundingRepository provides access to contract funding progress
+type FundingRepository struct {
+	db *DB
+}
+
+// NewFundingRepository creates a new funding repository
+func NewFundingRepository(db *DB) *FundingRepository {
+	return &FundingRepository{db: db}
+}
+
+// GetByContractID retrieves the funding progress of a contract,
+// returning an empty record if neither party has submitted anything yet
+func (r *FundingRepository) GetByContractID(ctx context.Context, contractID uuid.UUID) (*models.ContractFunding, error) {
+	var funding models.ContractFunding
+
+	query := `SELECT * FROM contract_funding WHERE contract_id = $1`
+	err := r.db.GetContext(ctx, &funding, query, contractID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.ContractFunding{ContractID: contractID}, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get contract funding", err)
+	}
+
+	return &funding, nil
+}
+
+// RecordSubmission stores one party's funding and signing state and returns the
+// updated progress. Only that party's columns are written, so concurrent
+// submissions by the buyer and seller cannot overwrite each other.
+func (r *FundingRepository) RecordSubmission(
+	ctx context.Context,
+	contractID uuid.UUID,
+	isBuyer bool,
+	funded, signed bool,
+	psbt string,
+) (*models.ContractFunding, error) {
+	var funding models.ContractFunding
+
+	query := `
+		INSERT INTO contract_funding (contract_id, seller_funded, seller_signed, seller_psbt, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (contract_id) DO UPDATE SET
+			seller_funded = EXCLUDED.seller_funded,
+			seller_signed = EXCLUDED.seller_signed,
+			seller_psbt = EXCLUDED.seller_psbt,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *
+	`
+	if isBuyer {
+		query = `
+			INSERT INTO contract_funding (contract_id, buyer_funded, buyer_signed, buyer_psbt, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (contract_id) DO UPDATE SET
+				buyer_funded = EXCLUDED.buyer_funded,
+				buyer_signed = EXCLUDED.buyer_signed,
+				buyer_psbt = EXCLUDED.buyer_psbt,
+				updated_at = EXCLUDED.updated_at
+			RETURNING *
+		`
+	}
+
+	err := r.db.GetContext(ctx, &funding, query, contractID, funded, signed, psbt, time.Now().UTC())
+	if err != nil {
+		return nil, wrapError("failed to record funding submission", err)
+	}
+
+	return &funding, nil
+}
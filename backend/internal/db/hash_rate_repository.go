@@ -0,0 +1,102 @@
+// internal/db/hash_rate_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// HashRateRepository persists network hash rate observations
+type HashRateRepository struct {
+	db *DB
+}
+
+// NewHashRateRepository creates a new hash rate repository
+func NewHashRateRepository(db *DB) *HashRateRepository {
+	return &HashRateRepository{db: db}
+}
+
+// Insert stores an observation, reporting false if its block height was already recorded
+func (r *HashRateRepository) Insert(ctx context.Context, obs *models.HashRateObservation) (bool, error) {
+	query := `
+		INSERT INTO hash_rate_observations (
+			block_height, block_time, hash_rate, average_1d, average_7d, average_30d, observed_at
+		) VALUES (
+			:block_height, :block_time, :hash_rate, :average_1d, :average_7d, :average_30d, :observed_at
+		)
+		ON CONFLICT (block_height) DO NOTHING
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, obs)
+	if err != nil {
+		return false, wrapError("failed to insert hash rate observation", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, wrapError("failed to get rows affected", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Latest retrieves the observation at the highest block, or nil if none was recorded
+func (r *HashRateRepository) Latest(ctx context.Context) (*models.HashRateObservation, error) {
+	var obs models.HashRateObservation
+
+	query := `SELECT * FROM hash_rate_observations ORDER BY block_height DESC LIMIT 1`
+	err := r.db.GetContext(ctx, &obs, query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get latest hash rate observation", err)
+	}
+
+	return &obs, nil
+}
+
+// GetAtHeight retrieves the latest observation at or below a block height,
+// or nil if none was recorded
+func (r *HashRateRepository) GetAtHeight(ctx context.Context, height int64) (*models.HashRateObservation, error) {
+	var obs models.HashRateObservation
+
+	query := `
+		SELECT * FROM hash_rate_observations
+		WHERE block_height <= $1
+		ORDER BY block_height DESC
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &obs, query, height)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get hash rate observation", err)
+	}
+
+	return &obs, nil
+}
+
+// ListSince retrieves observations recorded since the given time, newest first
+func (r *HashRateRepository) ListSince(ctx context.Context, since time.Time, limit int) ([]*models.HashRateObservation, error) {
+	var observations []*models.HashRateObservation
+
+	query := `
+		SELECT * FROM hash_rate_observations
+		WHERE observed_at >= $1
+		ORDER BY block_height DESC
+		LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &observations, query, since, limit); err != nil {
+		return nil, wrapError("failed to list hash rate observations", err)
+	}
+
+	return observations, nil
+}
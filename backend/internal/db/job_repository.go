@@ -0,0 +1,208 @@
+// internal/db/job_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// JobRepository provides access to background job records
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Create inserts a new pending job
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	now := time.Now().UTC()
+	job.Status = models.JobStatusPending
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	if len(job.Payload) == 0 {
+		job.Payload = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO jobs (
+			id, job_type, payload, status, attempts, max_attempts, run_at, created_at, updated_at
+		) VALUES (
+			:id, :job_type, :payload, :status, :attempts, :max_attempts, :run_at, :created_at, :updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, job)
+	if err != nil {
+		return wrapError("failed to create job", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	var job models.Job
+
+	query := `SELECT * FROM jobs WHERE id = $1`
+	err := r.db.GetContext(ctx, &job, query, id)
+	if err != nil {
+		return nil, wrapError("failed to get job by ID", err)
+	}
+
+	return &job, nil
+}
+
+// ClaimDue locks up to limit due jobs and marks them running.
+// Jobs stuck in RUNNING longer than staleAfter are treated as due again.
+func (r *JobRepository) ClaimDue(ctx context.Context, limit int, staleAfter time.Duration) ([]*models.Job, error) {
+	var jobs []*models.Job
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE jobs SET
+			status = 'RUNNING',
+			attempts = attempts + 1,
+			locked_at = $1,
+			updated_at = $1
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE (status = 'PENDING' AND run_at <= $1)
+			   OR (status = 'RUNNING' AND locked_at < $2)
+			ORDER BY run_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`
+
+	err := r.db.SelectContext(ctx, &jobs, query, now, now.Add(-staleAfter), limit)
+	if err != nil {
+		return nil, wrapError("failed to claim due jobs", err)
+	}
+
+	return jobs, nil
+}
+
+// MarkCompleted marks a job as successfully completed
+func (r *JobRepository) MarkCompleted(ctx context.Context, id uuid.UUID) error {
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE jobs SET
+			status = 'COMPLETED',
+			last_error = NULL,
+			locked_at = NULL,
+			completed_at = $1,
+			updated_at = $1
+		WHERE id = $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return wrapError("failed to mark job completed", err)
+	}
+
+	return nil
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *JobRepository) MarkRetry(ctx context.Context, id uuid.UUID, runAt time.Time, lastErr string) error {
+	query := `
+		UPDATE jobs SET
+			status = 'PENDING',
+			last_error = $1,
+			run_at = $2,
+			locked_at = NULL,
+			updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lastErr, runAt, time.Now().UTC(), id)
+	if err != nil {
+		return wrapError("failed to schedule job retry", err)
+	}
+
+	return nil
+}
+
+// MarkDead moves a job to the dead-letter state
+func (r *JobRepository) MarkDead(ctx context.Context, id uuid.UUID, lastErr string) error {
+	query := `
+		UPDATE jobs SET
+			status = 'DEAD',
+			last_error = $1,
+			locked_at = NULL,
+			updated_at = $2
+		WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, lastErr, time.Now().UTC(), id)
+	if err != nil {
+		return wrapError("failed to mark job dead", err)
+	}
+
+	return nil
+}
+
+// Replay resets a dead job so it runs again with a fresh retry budget
+func (r *JobRepository) Replay(ctx context.Context, id uuid.UUID) error {
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE jobs SET
+			status = 'PENDING',
+			attempts = 0,
+			run_at = $1,
+			locked_at = NULL,
+			updated_at = $1
+		WHERE id = $2 AND status = 'DEAD'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return wrapError("failed to replay job", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("job %s is not in the dead-letter state: %w", id, ErrConflict)
+	}
+
+	return nil
+}
+
+// ListByStatus retrieves jobs in the given status, most recently updated first
+func (r *JobRepository) ListByStatus(ctx context.Context, status models.JobStatus, limit, offset int) ([]*models.Job, error) {
+	var jobs []*models.Job
+
+	query := `
+		SELECT * FROM jobs
+		WHERE status = $1
+		ORDER BY updated_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &jobs, query, status, limit, offset)
+	if err != nil {
+		return nil, wrapError("failed to list jobs by status", err)
+	}
+
+	return jobs, nil
+}
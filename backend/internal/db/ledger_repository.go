@@ -0,0 +1,247 @@
+// internal/db/ledger_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// ErrInsufficientFunds is returned when a journal would take a user's
+// account below zero
+var ErrInsufficientFunds = fmt.Errorf("insufficient funds: %w", ErrConflict)
+
+// ErrUnbalanced is returned for a journal whose postings do not sum to zero
+var ErrUnbalanced = errors.New("journal postings do not sum to zero")
+
+// LedgerRepository provides access to the double-entry ledger of users' funds
+type LedgerRepository struct {
+	db *DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// Post records a journal and applies its postings to the account balances
+// in one transaction. It returns ErrInsufficientFunds if a user's account
+// would go negative, ErrUnbalanced if the postings do not sum to zero and
+// ErrConflict if a journal with the same reference was already posted.
+func (r *LedgerRepository) Post(ctx context.Context, journal *models.Journal) error {
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return r.PostTx(ctx, tx, journal)
+	})
+}
+
+// PostTx posts a journal within a transaction of the caller, so the funds
+// move only if the rest of the transaction commits
+func (r *LedgerRepository) PostTx(ctx context.Context, tx *sqlx.Tx, journal *models.Journal) error {
+	// Postings to the same account are combined, and accounts are updated
+	// in a fixed order so concurrent journals cannot deadlock
+	type accountKey struct {
+		userID      uuid.UUID
+		accountType models.LedgerAccountType
+	}
+	amounts := make(map[accountKey]int64)
+	var keys []accountKey
+	for _, posting := range journal.Postings {
+		key := accountKey{accountType: posting.AccountType}
+		if posting.UserID != nil {
+			key.userID = *posting.UserID
+		}
+		if _, ok := amounts[key]; !ok {
+			keys = append(keys, key)
+		}
+		amounts[key] += posting.Amount
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].userID != keys[j].userID {
+			return keys[i].userID.String() < keys[j].userID.String()
+		}
+		return keys[i].accountType < keys[j].accountType
+	})
+
+	now := time.Now().UTC()
+	if journal.ID == uuid.Nil {
+		journal.ID = uuid.New()
+	}
+	journal.CreatedAt = now
+
+	query := `
+		INSERT INTO ledger_journals (id, type, contract_id, reference, created_at)
+		VALUES (:id, :type, :contract_id, :reference, :created_at)
+	`
+	if _, err := tx.NamedExecContext(ctx, query, journal); err != nil {
+		return wrapError("failed to create journal", err)
+	}
+
+	for _, key := range keys {
+		amount := amounts[key]
+		if amount == 0 {
+			continue
+		}
+
+		var userID *uuid.UUID
+		if key.userID != uuid.Nil {
+			userID = &key.userID
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO ledger_accounts (id, user_id, type, balance, updated_at)
+				VALUES ($1, $2, $3, 0, $4)
+				ON CONFLICT (user_id, type) DO NOTHING
+			`, uuid.New(), userID, key.accountType, now)
+			if err != nil {
+				return wrapError("failed to create ledger account", err)
+			}
+		}
+
+		var accountID uuid.UUID
+		err := tx.GetContext(ctx, &accountID, `
+			UPDATE ledger_accounts SET balance = balance + $3, updated_at = $4
+			WHERE user_id IS NOT DISTINCT FROM $1 AND type = $2
+				AND (type = 'EXTERNAL' OR balance + $3 >= 0)
+			RETURNING id
+		`, userID, key.accountType, amount, now)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s account of %s: %w", key.accountType, key.userID, ErrInsufficientFunds)
+		}
+		if err != nil {
+			return wrapError("failed to update ledger account", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO ledger_postings (journal_id, account_id, amount) VALUES ($1, $2, $3)
+		`, journal.ID, accountID, amount)
+		if err != nil {
+			return wrapError("failed to create ledger posting", err)
+		}
+	}
+
+	// Check what was stored, so an unbalanced journal is never committed
+	// however its postings were combined
+	var sum int64
+	err := tx.GetContext(ctx, &sum, `SELECT COALESCE(SUM(amount), 0) FROM ledger_postings WHERE journal_id = $1`, journal.ID)
+	if err != nil {
+		return wrapError("failed to sum journal postings", err)
+	}
+	if sum != 0 {
+		return fmt.Errorf("journal %s is off by %d sats: %w", journal.ID, sum, ErrUnbalanced)
+	}
+
+	return nil
+}
+
+// HasJournal reports whether a journal with the reference has been posted
+func (r *LedgerRepository) HasJournal(ctx context.Context, reference string) (bool, error) {
+	var exists bool
+
+	query := `SELECT EXISTS (SELECT 1 FROM ledger_journals WHERE reference = $1)`
+	if err := r.db.GetContext(ctx, &exists, query, reference); err != nil {
+		return false, wrapError("failed to look up journal", err)
+	}
+
+	return exists, nil
+}
+
+// GetBalance retrieves a user's booked balances and the funds their resting
+// orders hold. Users without accounts have an empty balance.
+func (r *LedgerRepository) GetBalance(ctx context.Context, userID uuid.UUID) (*models.Balance, error) {
+	balance := models.Balance{UserID: userID}
+
+	query := `
+		SELECT
+			$1::uuid AS user_id,
+			COALESCE((SELECT balance FROM ledger_accounts WHERE user_id = $1 AND type = $2), 0) AS available,
+			COALESCE((SELECT balance FROM ledger_accounts WHERE user_id = $1 AND type = $3), 0) AS committed,
+			COALESCE((
+				SELECT SUM(price * remaining_quantity) FROM orders
+				WHERE user_id = $1 AND status IN ($4, $5)
+			), 0) AS held
+	`
+
+	err := r.db.GetContext(ctx, &balance, query, userID,
+		models.LedgerAccountAvailable, models.LedgerAccountCommitted,
+		models.OrderStatusOpen, models.OrderStatusPartial)
+	if err != nil {
+		return nil, wrapError("failed to get balance", err)
+	}
+
+	balance.Tradable = balance.Available - balance.Held
+	return &balance, nil
+}
+
+// TradableFunds returns a user's available funds less those held by their
+// resting orders other than excludeOrderID
+func (r *LedgerRepository) TradableFunds(ctx context.Context, userID, excludeOrderID uuid.UUID) (int64, error) {
+	var tradable int64
+
+	query := `
+		SELECT
+			COALESCE((SELECT balance FROM ledger_accounts WHERE user_id = $1 AND type = $2), 0) -
+			COALESCE((
+				SELECT SUM(price * remaining_quantity) FROM orders
+				WHERE user_id = $1 AND status IN ($3, $4) AND id <> $5
+			), 0)
+	`
+
+	err := r.db.GetContext(ctx, &tradable, query, userID, models.LedgerAccountAvailable,
+		models.OrderStatusOpen, models.OrderStatusPartial, excludeOrderID)
+	if err != nil {
+		return 0, wrapError("failed to get tradable funds", err)
+	}
+
+	return tradable, nil
+}
+
+// ListEntries retrieves a page of the postings to a user's accounts, newest first
+func (r *LedgerRepository) ListEntries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.LedgerEntry, error) {
+	var entries []*models.LedgerEntry
+
+	query := `
+		SELECT j.id AS journal_id, j.type, j.contract_id, a.type AS account_type, p.amount, j.created_at
+		FROM ledger_postings p
+		JOIN ledger_accounts a ON a.id = p.account_id
+		JOIN ledger_journals j ON j.id = p.journal_id
+		WHERE a.user_id = $1
+		ORDER BY j.created_at DESC, j.id, a.type
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, userID, limit, offset); err != nil {
+		return nil, wrapError("failed to list ledger entries", err)
+	}
+
+	return entries, nil
+}
+
+// TradeParties retrieves the buyer and seller whose orders traded into a
+// contract
+func (r *LedgerRepository) TradeParties(ctx context.Context, contractID uuid.UUID) (buyerID, sellerID uuid.UUID, err error) {
+	var parties struct {
+		BuyerID  uuid.UUID `db:"buyer_id"`
+		SellerID uuid.UUID `db:"seller_id"`
+	}
+
+	query := `
+		SELECT b.user_id AS buyer_id, s.user_id AS seller_id
+		FROM trades t
+		JOIN orders b ON b.id = t.buy_order_id
+		JOIN orders s ON s.id = t.sell_order_id
+		WHERE t.contract_id = $1
+		LIMIT 1
+	`
+
+	if err := r.db.GetContext(ctx, &parties, query, contractID); err != nil {
+		return uuid.Nil, uuid.Nil, wrapError("failed to get contract parties", err)
+	}
+
+	return parties.BuyerID, parties.SellerID, nil
+}
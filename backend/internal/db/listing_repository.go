@@ -0,0 +1,251 @@
+// internal/db/listing_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// ListingRepository persists contract positions listed on the secondary
+// market and moves their payments between margin accounts
+type ListingRepository struct {
+	db *DB
+}
+
+// NewListingRepository creates a new listing repository
+func NewListingRepository(db *DB) *ListingRepository {
+	return &ListingRepository{db: db}
+}
+
+// Create stores a new open listing. It returns ErrConflict if the position
+// is already listed.
+func (r *ListingRepository) Create(ctx context.Context, listing *models.ContractListing) error {
+	now := time.Now().UTC()
+	if listing.ID == uuid.Nil {
+		listing.ID = uuid.New()
+	}
+	listing.Status = models.ListingStatusOpen
+	listing.BuyerID = nil
+	listing.BuyerPubKey = nil
+	listing.SwapTxID = nil
+	listing.SoldAt = nil
+	listing.CreatedAt = now
+	listing.UpdatedAt = now
+
+	query := `
+		INSERT INTO contract_listings (
+			id, contract_id, seller_id, party, pub_key, price, status, created_at, updated_at
+		) VALUES (
+			:id, :contract_id, :seller_id, :party, :pub_key, :price, :status, :created_at, :updated_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, listing); err != nil {
+		return wrapError("failed to create listing", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a listing by ID
+func (r *ListingRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ContractListing, error) {
+	var listing models.ContractListing
+
+	query := `SELECT * FROM contract_listings WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &listing, query, id); err != nil {
+		return nil, wrapError("failed to get listing", err)
+	}
+
+	return &listing, nil
+}
+
+// ListOpen retrieves a page of open listings, oldest first. A non-nil
+// contractID restricts them to one contract.
+func (r *ListingRepository) ListOpen(ctx context.Context, contractID *uuid.UUID, limit, offset int) ([]*models.ContractListing, error) {
+	listings := []*models.ContractListing{}
+
+	query := `
+		SELECT * FROM contract_listings
+		WHERE status = $1 AND ($2::uuid IS NULL OR contract_id = $2)
+		ORDER BY created_at ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.SelectContext(ctx, &listings, query, models.ListingStatusOpen, contractID, limit, offset); err != nil {
+		return nil, wrapError("failed to list open listings", err)
+	}
+
+	return listings, nil
+}
+
+// ListBySeller retrieves a user's listings, newest first
+func (r *ListingRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID, limit, offset int) ([]*models.ContractListing, error) {
+	listings := []*models.ContractListing{}
+
+	query := `
+		SELECT * FROM contract_listings
+		WHERE seller_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &listings, query, sellerID, limit, offset); err != nil {
+		return nil, wrapError("failed to list seller listings", err)
+	}
+
+	return listings, nil
+}
+
+// Cancel withdraws an open listing of a seller. It returns ErrConflict if
+// the listing is no longer open.
+func (r *ListingRepository) Cancel(ctx context.Context, id, sellerID uuid.UUID) (*models.ContractListing, error) {
+	var listing models.ContractListing
+
+	query := `
+		UPDATE contract_listings
+		SET status = $3, updated_at = $4
+		WHERE id = $1 AND seller_id = $2 AND status = $5
+		RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, &listing, query,
+		id, sellerID, models.ListingStatusCancelled, time.Now().UTC(), models.ListingStatusOpen)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("listing is not open: %w", ErrConflict)
+		}
+		return nil, wrapError("failed to cancel listing", err)
+	}
+
+	return &listing, nil
+}
+
+// Claim marks an open listing as pending for a buyer and reserves its price
+// in the buyer's margin account, atomically. It returns ErrConflict if the
+// listing is no longer open or the buyer lacks the free margin.
+func (r *ListingRepository) Claim(ctx context.Context, id, buyerID uuid.UUID, buyerPubKey string) (*models.ContractListing, error) {
+	var listing models.ContractListing
+	now := time.Now().UTC()
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE contract_listings
+			SET status = $2, buyer_id = $3, buyer_pub_key = $4, updated_at = $5
+			WHERE id = $1 AND status = $6
+			RETURNING *
+		`
+		err := tx.GetContext(ctx, &listing, query,
+			id, models.ListingStatusPending, buyerID, buyerPubKey, now, models.ListingStatusOpen)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("listing is not open: %w", ErrConflict)
+			}
+			return wrapError("failed to claim listing", err)
+		}
+
+		return lockCollateral(ctx, tx, buyerID, listing.Price, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// Release reopens a pending listing whose swap failed and returns the
+// reserved price to the buyer's free margin, atomically
+func (r *ListingRepository) Release(ctx context.Context, listing *models.ContractListing) error {
+	if listing.BuyerID == nil {
+		return fmt.Errorf("listing %s has no buyer: %w", listing.ID, ErrConflict)
+	}
+	now := time.Now().UTC()
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := updatePending(ctx, tx, `
+			UPDATE contract_listings
+			SET status = $2, buyer_id = NULL, buyer_pub_key = NULL, updated_at = $3
+			WHERE id = $1 AND status = $4
+		`, listing.ID, models.ListingStatusOpen, now, models.ListingStatusPending)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE margin_accounts
+			SET locked = locked - $2, updated_at = $3
+			WHERE user_id = $1
+		`, *listing.BuyerID, listing.Price, now)
+		if err != nil {
+			return wrapError("failed to release reserved margin", err)
+		}
+		return nil
+	})
+}
+
+// Complete marks a pending listing as sold by the swap transaction and pays
+// the reserved price from the buyer's margin account to the seller's,
+// atomically
+func (r *ListingRepository) Complete(ctx context.Context, listing *models.ContractListing, swapTxID string) error {
+	if listing.BuyerID == nil {
+		return fmt.Errorf("listing %s has no buyer: %w", listing.ID, ErrConflict)
+	}
+	now := time.Now().UTC()
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := updatePending(ctx, tx, `
+			UPDATE contract_listings
+			SET status = $2, swap_tx_id = $3, sold_at = $4, updated_at = $4
+			WHERE id = $1 AND status = $5
+		`, listing.ID, models.ListingStatusSold, swapTxID, now, models.ListingStatusPending)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE margin_accounts
+			SET balance = balance - $2, locked = locked - $2, updated_at = $3
+			WHERE user_id = $1
+		`, *listing.BuyerID, listing.Price, now)
+		if err != nil {
+			return wrapError("failed to debit buyer margin", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO margin_accounts (user_id, balance, locked, updated_at)
+			VALUES ($1, $2, 0, $3)
+			ON CONFLICT (user_id) DO UPDATE
+			SET balance = margin_accounts.balance + EXCLUDED.balance, updated_at = EXCLUDED.updated_at
+		`, listing.SellerID, listing.Price, now)
+		if err != nil {
+			return wrapError("failed to credit seller margin", err)
+		}
+		return nil
+	})
+}
+
+// updatePending runs an update of a pending listing, returning ErrConflict
+// if the listing is no longer pending
+func updatePending(ctx context.Context, tx *sqlx.Tx, query string, args ...interface{}) error {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return wrapError("failed to update listing", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("listing is not pending: %w", ErrConflict)
+	}
+	return nil
+}
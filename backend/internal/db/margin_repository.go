@@ -0,0 +1,313 @@
+// internal/db/margin_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// MarginRepository provides access to margin accounts and positions
+type MarginRepository struct {
+	db *DB
+}
+
+// NewMarginRepository creates a new margin repository
+func NewMarginRepository(db *DB) *MarginRepository {
+	return &MarginRepository{db: db}
+}
+
+// GetAccount retrieves a user's margin account. Users who never deposited
+// have an empty account.
+func (r *MarginRepository) GetAccount(ctx context.Context, userID uuid.UUID) (*models.MarginAccount, error) {
+	var account models.MarginAccount
+
+	query := `SELECT * FROM margin_accounts WHERE user_id = $1`
+
+	if err := r.db.GetContext(ctx, &account, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.MarginAccount{UserID: userID}, nil
+		}
+		return nil, wrapError("failed to get margin account", err)
+	}
+
+	return &account, nil
+}
+
+// Deposit credits collateral to a user's margin account
+func (r *MarginRepository) Deposit(ctx context.Context, userID uuid.UUID, amount int64) (*models.MarginAccount, error) {
+	var account models.MarginAccount
+
+	query := `
+		INSERT INTO margin_accounts (user_id, balance, locked, updated_at)
+		VALUES ($1, $2, 0, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET balance = margin_accounts.balance + EXCLUDED.balance, updated_at = EXCLUDED.updated_at
+		RETURNING *
+	`
+
+	if err := r.db.GetContext(ctx, &account, query, userID, amount, time.Now().UTC()); err != nil {
+		return nil, wrapError("failed to deposit margin", err)
+	}
+
+	return &account, nil
+}
+
+// Withdraw debits free collateral from a user's margin account. It returns
+// ErrConflict if the account does not hold enough free collateral.
+func (r *MarginRepository) Withdraw(ctx context.Context, userID uuid.UUID, amount int64) (*models.MarginAccount, error) {
+	var account models.MarginAccount
+
+	query := `
+		UPDATE margin_accounts
+		SET balance = balance - $2, updated_at = $3
+		WHERE user_id = $1 AND balance - locked >= $2
+		RETURNING *
+	`
+
+	if err := r.db.GetContext(ctx, &account, query, userID, amount, time.Now().UTC()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("insufficient free margin: %w", ErrConflict)
+		}
+		return nil, wrapError("failed to withdraw margin", err)
+	}
+
+	return &account, nil
+}
+
+// lockCollateral moves collateral of a user's account from free to locked
+func lockCollateral(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, now time.Time) error {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE margin_accounts
+		SET locked = locked + $2, updated_at = $3
+		WHERE user_id = $1 AND balance - locked >= $2
+	`, userID, amount, now)
+	if err != nil {
+		return wrapError("failed to lock margin", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("insufficient free margin: %w", ErrConflict)
+	}
+	return nil
+}
+
+// OpenPosition locks a position's collateral in its user's account and
+// records the position, atomically. It returns ErrConflict if the account
+// lacks the collateral or the contract is already margined.
+func (r *MarginRepository) OpenPosition(ctx context.Context, position *models.MarginPosition) error {
+	now := time.Now().UTC()
+	if position.ID == uuid.Nil {
+		position.ID = uuid.New()
+	}
+	position.Status = models.MarginStatusOpen
+	position.CalledAt = nil
+	position.ClosedAt = nil
+	position.CreatedAt = now
+	position.UpdatedAt = now
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := lockCollateral(ctx, tx, position.UserID, position.Collateral, now); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO margin_positions (
+				id, contract_id, user_id, collateral, required_margin, status, created_at, updated_at
+			) VALUES (
+				:id, :contract_id, :user_id, :collateral, :required_margin, :status, :created_at, :updated_at
+			)
+		`
+		if _, err := tx.NamedExecContext(ctx, query, position); err != nil {
+			return wrapError("failed to create margin position", err)
+		}
+		return nil
+	})
+}
+
+// GetPosition retrieves a margin position by ID
+func (r *MarginRepository) GetPosition(ctx context.Context, id uuid.UUID) (*models.MarginPosition, error) {
+	var position models.MarginPosition
+
+	query := `SELECT * FROM margin_positions WHERE id = $1`
+
+	if err := r.db.GetContext(ctx, &position, query, id); err != nil {
+		return nil, wrapError("failed to get margin position", err)
+	}
+
+	return &position, nil
+}
+
+// GetByContract retrieves the margin position of a contract, or nil if the
+// contract is not margined
+func (r *MarginRepository) GetByContract(ctx context.Context, contractID uuid.UUID) (*models.MarginPosition, error) {
+	var position models.MarginPosition
+
+	query := `SELECT * FROM margin_positions WHERE contract_id = $1`
+
+	if err := r.db.GetContext(ctx, &position, query, contractID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrapError("failed to get contract margin position", err)
+	}
+
+	return &position, nil
+}
+
+// ListByUser retrieves a user's margin positions, newest first
+func (r *MarginRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.MarginPosition, error) {
+	var positions []*models.MarginPosition
+
+	query := `
+		SELECT * FROM margin_positions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &positions, query, userID); err != nil {
+		return nil, wrapError("failed to list margin positions", err)
+	}
+
+	return positions, nil
+}
+
+// ListActive retrieves a page of open and called positions, oldest first
+func (r *MarginRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.MarginPosition, error) {
+	var positions []*models.MarginPosition
+
+	query := `
+		SELECT * FROM margin_positions
+		WHERE status IN ('OPEN', 'CALLED')
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2
+	`
+
+	if err := r.db.SelectContext(ctx, &positions, query, limit, offset); err != nil {
+		return nil, wrapError("failed to list active margin positions", err)
+	}
+
+	return positions, nil
+}
+
+// UpdateRequirement records the margin a position requires and whether it
+// is called. A position that meets its requirement again is no longer called.
+func (r *MarginRepository) UpdateRequirement(ctx context.Context, id uuid.UUID, required int64, status models.MarginStatus, calledAt *time.Time) error {
+	query := `
+		UPDATE margin_positions
+		SET required_margin = $2, status = $3, called_at = $4, updated_at = $5
+		WHERE id = $1 AND status IN ('OPEN', 'CALLED')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, required, status, calledAt, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to update margin requirement", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("margin position %s is not active: %w", id, ErrConflict)
+	}
+
+	return nil
+}
+
+// TopUp locks more of its user's collateral in an active position. A called
+// position whose collateral meets its requirement is no longer called.
+func (r *MarginRepository) TopUp(ctx context.Context, id uuid.UUID, amount int64) (*models.MarginPosition, error) {
+	var position models.MarginPosition
+	now := time.Now().UTC()
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `SELECT * FROM margin_positions WHERE id = $1 FOR UPDATE`
+		if err := tx.GetContext(ctx, &position, query, id); err != nil {
+			return wrapError("failed to get margin position", err)
+		}
+		if !position.Active() {
+			return fmt.Errorf("margin position %s is not active: %w", id, ErrConflict)
+		}
+
+		if err := lockCollateral(ctx, tx, position.UserID, amount, now); err != nil {
+			return err
+		}
+
+		position.Collateral += amount
+		position.UpdatedAt = now
+		if position.Status == models.MarginStatusCalled && position.Collateral >= position.RequiredMargin {
+			position.Status = models.MarginStatusOpen
+			position.CalledAt = nil
+		}
+
+		query = `
+			UPDATE margin_positions
+			SET collateral = :collateral, status = :status, called_at = :called_at, updated_at = :updated_at
+			WHERE id = :id
+		`
+		if _, err := tx.NamedExecContext(ctx, query, &position); err != nil {
+			return wrapError("failed to top up margin position", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &position, nil
+}
+
+// Close ends an active position with the given status and unlocks its
+// collateral, debiting it from the account when forfeited. It returns
+// ErrConflict if the position was already closed.
+func (r *MarginRepository) Close(ctx context.Context, id uuid.UUID, status models.MarginStatus, forfeited bool) (*models.MarginPosition, error) {
+	var position models.MarginPosition
+	now := time.Now().UTC()
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE margin_positions
+			SET status = $2, closed_at = $3, updated_at = $3
+			WHERE id = $1 AND status IN ('OPEN', 'CALLED')
+			RETURNING *
+		`
+		if err := tx.GetContext(ctx, &position, query, id, status, now); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("margin position %s is not active: %w", id, ErrConflict)
+			}
+			return wrapError("failed to close margin position", err)
+		}
+
+		debit := int64(0)
+		if forfeited {
+			debit = position.Collateral
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			UPDATE margin_accounts
+			SET locked = locked - $2, balance = balance - $3, updated_at = $4
+			WHERE user_id = $1
+		`, position.UserID, position.Collateral, debit, now)
+		if err != nil {
+			return wrapError("failed to release margin", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &position, nil
+}
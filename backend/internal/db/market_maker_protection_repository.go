@@ -0,0 +1,113 @@
+// internal/db/market_maker_protection_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// MarketMakerProtectionRepository provides access to the fill limits of API keys
+type MarketMakerProtectionRepository struct {
+	db *DB
+}
+
+// NewMarketMakerProtectionRepository creates a new market maker protection repository
+func NewMarketMakerProtectionRepository(db *DB) *MarketMakerProtectionRepository {
+	return &MarketMakerProtectionRepository{db: db}
+}
+
+// Get retrieves the protection of one of a user's API keys
+func (r *MarketMakerProtectionRepository) Get(ctx context.Context, userID, apiKeyID uuid.UUID) (*models.MarketMakerProtection, error) {
+	var protection models.MarketMakerProtection
+
+	query := `
+		SELECT p.* FROM market_maker_protections p
+		JOIN api_keys k ON k.id = p.api_key_id
+		WHERE p.api_key_id = $1 AND k.user_id = $2
+	`
+	if err := r.db.GetContext(ctx, &protection, query, apiKeyID, userID); err != nil {
+		return nil, wrapError("failed to get market maker protection", err)
+	}
+
+	return &protection, nil
+}
+
+// ListActive retrieves the protections of every unrevoked API key
+func (r *MarketMakerProtectionRepository) ListActive(ctx context.Context) ([]*models.MarketMakerProtection, error) {
+	var protections []*models.MarketMakerProtection
+
+	query := `
+		SELECT p.* FROM market_maker_protections p
+		JOIN api_keys k ON k.id = p.api_key_id
+		WHERE k.revoked_at IS NULL
+	`
+	if err := r.db.SelectContext(ctx, &protections, query); err != nil {
+		return nil, wrapError("failed to list market maker protections", err)
+	}
+
+	return protections, nil
+}
+
+// Set registers or replaces the protection of one of a user's unrevoked API keys
+func (r *MarketMakerProtectionRepository) Set(ctx context.Context, userID uuid.UUID, protection *models.MarketMakerProtection) error {
+	protection.UpdatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO market_maker_protections (api_key_id, max_fills, window_seconds, freeze_seconds, updated_at)
+		SELECT id, $3, $4, $5, $6 FROM api_keys
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		ON CONFLICT (api_key_id) DO UPDATE SET
+			max_fills = EXCLUDED.max_fills,
+			window_seconds = EXCLUDED.window_seconds,
+			freeze_seconds = EXCLUDED.freeze_seconds,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		protection.APIKeyID, userID, protection.MaxFills, protection.WindowSeconds,
+		protection.FreezeSeconds, protection.UpdatedAt)
+	if err != nil {
+		return wrapError("failed to set market maker protection", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("API key %s: %w", protection.APIKeyID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// Delete removes the protection of one of a user's API keys
+func (r *MarketMakerProtectionRepository) Delete(ctx context.Context, userID, apiKeyID uuid.UUID) error {
+	query := `
+		DELETE FROM market_maker_protections p
+		USING api_keys k
+		WHERE k.id = p.api_key_id AND p.api_key_id = $1 AND k.user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, apiKeyID, userID)
+	if err != nil {
+		return wrapError("failed to delete market maker protection", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("market maker protection %s: %w", apiKeyID, ErrNotFound)
+	}
+
+	return nil
+}
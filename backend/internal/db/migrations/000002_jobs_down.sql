@@ -0,0 +1,3 @@
+-- internal/db/migrations/000002_jobs.down.sql
+
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000003_watchlists.down.sql
+
+DROP TABLE IF EXISTS watchlist_items;
@@ -0,0 +1,28 @@
+-- internal/db/migrations/000003_watchlists.up.sql
+
+-- Watchlist items table; each row follows either a single contract or a market
+CREATE TABLE watchlist_items (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_type VARCHAR(10) NOT NULL CHECK (item_type IN ('CONTRACT', 'MARKET')),
+    contract_id UUID REFERENCES contracts(id) ON DELETE CASCADE,
+    contract_type VARCHAR(10) CHECK (contract_type IN ('CALL', 'PUT')),
+    strike_hash_rate DOUBLE PRECISION,
+    start_block_height BIGINT,
+    end_block_height BIGINT,
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK (
+        (item_type = 'CONTRACT' AND contract_id IS NOT NULL) OR
+        (item_type = 'MARKET' AND contract_type IS NOT NULL AND strike_hash_rate IS NOT NULL
+            AND start_block_height IS NOT NULL AND end_block_height IS NOT NULL)
+    )
+);
+
+CREATE INDEX idx_watchlist_items_user_id ON watchlist_items(user_id);
+CREATE INDEX idx_watchlist_items_contract_id ON watchlist_items(contract_id);
+CREATE UNIQUE INDEX idx_watchlist_items_user_contract ON watchlist_items(user_id, contract_id)
+    WHERE item_type = 'CONTRACT';
+CREATE UNIQUE INDEX idx_watchlist_items_user_market ON watchlist_items(
+    user_id, contract_type, strike_hash_rate, start_block_height, end_block_height
+) WHERE item_type = 'MARKET';
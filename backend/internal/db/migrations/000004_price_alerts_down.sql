@@ -0,0 +1,3 @@
+-- internal/db/migrations/000004_price_alerts.down.sql
+
+DROP TABLE IF EXISTS price_alerts;
@@ -0,0 +1,25 @@
+-- internal/db/migrations/000004_price_alerts.up.sql
+
+-- Price alerts table
+CREATE TABLE price_alerts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contract_type VARCHAR(10) NOT NULL CHECK (contract_type IN ('CALL', 'PUT')),
+    strike_hash_rate DOUBLE PRECISION NOT NULL,
+    start_block_height BIGINT NOT NULL,
+    end_block_height BIGINT NOT NULL,
+    metric VARCHAR(20) NOT NULL CHECK (metric IN ('BEST_BID', 'BEST_ASK', 'LAST_TRADE')),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('ABOVE', 'BELOW')),
+    threshold BIGINT NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('WEBSOCKET', 'WEBHOOK', 'EMAIL')),
+    target VARCHAR(500) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('ACTIVE', 'TRIGGERED', 'CANCELLED')),
+    triggered_value BIGINT,
+    triggered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_price_alerts_user_id ON price_alerts(user_id);
+CREATE INDEX idx_price_alerts_market ON price_alerts(
+    contract_type, strike_hash_rate, start_block_height, end_block_height
+) WHERE status = 'ACTIVE';
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000005_contract_funding.down.sql
+
+DROP TABLE IF EXISTS contract_funding;
@@ -0,0 +1,13 @@
+-- internal/db/migrations/000005_contract_funding.up.sql
+
+-- Per-contract funding and signing progress during two-sided setup
+CREATE TABLE contract_funding (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    buyer_funded BOOLEAN NOT NULL DEFAULT FALSE,
+    seller_funded BOOLEAN NOT NULL DEFAULT FALSE,
+    buyer_signed BOOLEAN NOT NULL DEFAULT FALSE,
+    seller_signed BOOLEAN NOT NULL DEFAULT FALSE,
+    buyer_psbt TEXT,
+    seller_psbt TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
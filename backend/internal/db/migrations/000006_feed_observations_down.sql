@@ -0,0 +1,3 @@
+-- internal/db/migrations/000006_feed_observations.down.sql
+
+DROP TABLE IF EXISTS feed_observations;
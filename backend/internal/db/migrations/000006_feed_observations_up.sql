@@ -0,0 +1,13 @@
+-- internal/db/migrations/000006_feed_observations.up.sql
+
+-- Observations sampled from external data feed plugins
+CREATE TABLE feed_observations (
+    id BIGSERIAL PRIMARY KEY,
+    feed VARCHAR(100) NOT NULL,
+    series VARCHAR(200) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    observed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_feed_observations_feed_series ON feed_observations(feed, series, observed_at DESC);
+CREATE INDEX idx_feed_observations_feed_time ON feed_observations(feed, observed_at DESC);
@@ -0,0 +1,4 @@
+-- internal/db/migrations/000007_push_notifications.down.sql
+
+DROP TABLE IF EXISTS push_preferences;
+DROP TABLE IF EXISTS device_tokens;
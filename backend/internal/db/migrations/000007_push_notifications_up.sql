@@ -0,0 +1,21 @@
+-- internal/db/migrations/000007_push_notifications.up.sql
+
+-- Mobile devices registered for push notifications
+CREATE TABLE device_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform VARCHAR(10) NOT NULL CHECK (platform IN ('APNS', 'FCM')),
+    token TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_device_tokens_user_id ON device_tokens(user_id);
+
+-- Per-event-type push preferences; a missing row means enabled
+CREATE TABLE push_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL CHECK (event_type IN ('FILL', 'SETTLEMENT')),
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type)
+);
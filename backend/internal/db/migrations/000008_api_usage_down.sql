@@ -0,0 +1,4 @@
+-- internal/db/migrations/000008_api_usage.down.sql
+
+DROP TABLE IF EXISTS api_usage;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,29 @@
+-- internal/db/migrations/000008_api_usage.up.sql
+
+-- API keys identifying clients of a user; only the key hash is stored
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- Hourly usage counters per API key
+CREATE TABLE api_usage (
+    api_key_id UUID NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    ws_messages BIGINT NOT NULL DEFAULT 0,
+    ws_bytes BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, period_start)
+);
+
+CREATE INDEX idx_api_usage_user_period ON api_usage(user_id, period_start);
+CREATE INDEX idx_api_usage_period ON api_usage(period_start);
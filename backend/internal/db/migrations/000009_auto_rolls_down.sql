@@ -0,0 +1,3 @@
+-- internal/db/migrations/000009_auto_rolls.down.sql
+
+DROP TABLE IF EXISTS auto_rolls;
@@ -0,0 +1,17 @@
+-- internal/db/migrations/000009_auto_rolls.up.sql
+
+-- Auto-roll instructions placing an equivalent order in the next expiry
+CREATE TABLE auto_rolls (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    price_offset BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING', 'ROLLED', 'FAILED', 'CANCELLED')),
+    rolled_order_id UUID REFERENCES orders(id),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_auto_rolls_pending_order ON auto_rolls(order_id) WHERE status = 'PENDING';
+CREATE INDEX idx_auto_rolls_status ON auto_rolls(status);
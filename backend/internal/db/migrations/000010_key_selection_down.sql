@@ -0,0 +1,7 @@
+-- internal/db/migrations/000010_key_selection.down.sql
+
+ALTER TABLE contracts DROP COLUMN IF EXISTS seller_key_id;
+ALTER TABLE contracts DROP COLUMN IF EXISTS buyer_key_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS key_id;
+DROP INDEX IF EXISTS idx_user_keys_default;
+ALTER TABLE user_keys DROP COLUMN IF EXISTS is_default;
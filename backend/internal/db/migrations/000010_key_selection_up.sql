@@ -0,0 +1,10 @@
+-- internal/db/migrations/000010_key_selection.up.sql
+
+-- Default key per user, used when an order does not name a key
+ALTER TABLE user_keys ADD COLUMN is_default BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX idx_user_keys_default ON user_keys(user_id) WHERE is_default;
+
+-- Record which registered key was used for each order and contract side
+ALTER TABLE orders ADD COLUMN key_id UUID REFERENCES user_keys(id) ON DELETE SET NULL;
+ALTER TABLE contracts ADD COLUMN buyer_key_id UUID REFERENCES user_keys(id) ON DELETE SET NULL;
+ALTER TABLE contracts ADD COLUMN seller_key_id UUID REFERENCES user_keys(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000011_contract_inputs.down.sql
+
+DROP TABLE IF EXISTS contract_inputs;
@@ -0,0 +1,17 @@
+-- internal/db/migrations/000011_contract_inputs.up.sql
+
+-- UTXOs and VTXOs funding a contract, swept together by the final and settlement transactions
+CREATE TABLE contract_inputs (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    stage VARCHAR(10) NOT NULL CHECK (stage IN ('setup', 'final')),
+    source VARCHAR(10) NOT NULL CHECK (source IN ('utxo', 'vtxo')),
+    txid VARCHAR(64) NOT NULL,
+    vout INTEGER NOT NULL CHECK (vout >= 0),
+    value BIGINT NOT NULL CHECK (value > 0),
+    leaf_index INTEGER CHECK (leaf_index >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (contract_id, txid, vout)
+);
+
+CREATE INDEX idx_contract_inputs_contract_stage ON contract_inputs(contract_id, stage);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000012_trade_price_rule.down.sql
+
+ALTER TABLE trades DROP COLUMN IF EXISTS price_rule;
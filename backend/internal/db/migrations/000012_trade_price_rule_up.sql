@@ -0,0 +1,6 @@
+-- internal/db/migrations/000012_trade_price_rule.up.sql
+
+-- Price rule the matching engine applied to each trade. Trades before the
+-- rule was configurable all used the midpoint price.
+ALTER TABLE trades ADD COLUMN price_rule VARCHAR(10) NOT NULL DEFAULT 'mid'
+    CHECK (price_rule IN ('resting', 'mid', 'pro_rata'));
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000013_order_target_timestamp.down.sql
+
+ALTER TABLE orders DROP COLUMN IF EXISTS target_timestamp;
@@ -0,0 +1,5 @@
+-- internal/db/migrations/000013_order_target_timestamp.up.sql
+
+-- Target timestamp quoted by each order and carried into the matched contract.
+-- Orders placed before it was recorded have none.
+ALTER TABLE orders ADD COLUMN target_timestamp TIMESTAMP WITH TIME ZONE;
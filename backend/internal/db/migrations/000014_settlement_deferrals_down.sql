@@ -0,0 +1,8 @@
+-- internal/db/migrations/000014_settlement_deferrals.down.sql
+
+DELETE FROM push_preferences WHERE event_type = 'SETTLEMENT_DEFERRED';
+ALTER TABLE push_preferences DROP CONSTRAINT push_preferences_event_type_check;
+ALTER TABLE push_preferences ADD CONSTRAINT push_preferences_event_type_check
+    CHECK (event_type IN ('FILL', 'SETTLEMENT'));
+
+DROP TABLE IF EXISTS settlement_deferrals;
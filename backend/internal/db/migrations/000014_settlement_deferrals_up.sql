@@ -0,0 +1,17 @@
+-- internal/db/migrations/000014_settlement_deferrals.up.sql
+
+-- Settlements held back while chain fees are above the stress threshold
+CREATE TABLE settlement_deferrals (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    fee_rate DOUBLE PRECISION NOT NULL CHECK (fee_rate >= 0),
+    priority_fee_rate DOUBLE PRECISION CHECK (priority_fee_rate > 0),
+    deferred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_settlement_deferrals_pending ON settlement_deferrals(deferred_at) WHERE released_at IS NULL;
+
+-- Notify parties when their settlement is deferred
+ALTER TABLE push_preferences DROP CONSTRAINT push_preferences_event_type_check;
+ALTER TABLE push_preferences ADD CONSTRAINT push_preferences_event_type_check
+    CHECK (event_type IN ('FILL', 'SETTLEMENT', 'SETTLEMENT_DEFERRED'));
@@ -0,0 +1,4 @@
+-- internal/db/migrations/000015_payout_addresses.down.sql
+
+DROP TABLE IF EXISTS contract_payout_addresses;
+DROP TABLE IF EXISTS user_payout_addresses;
@@ -0,0 +1,17 @@
+-- internal/db/migrations/000015_payout_addresses.up.sql
+
+-- Address each user's winning settlements pay to
+CREATE TABLE user_payout_addresses (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    address VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Address a contract party is paid at, set by a signed request or recorded at settlement
+CREATE TABLE contract_payout_addresses (
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    pub_key VARCHAR(255) NOT NULL,
+    address VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (contract_id, pub_key)
+);
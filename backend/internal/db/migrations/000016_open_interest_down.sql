@@ -0,0 +1,4 @@
+-- internal/db/migrations/000016_open_interest.down.sql
+
+DROP TABLE IF EXISTS open_interest_history;
+DROP TABLE IF EXISTS open_interest;
@@ -0,0 +1,44 @@
+-- internal/db/migrations/000016_open_interest.up.sql
+
+-- Active contracts per market, updated on activation, settlement and expiry
+CREATE TABLE open_interest (
+    contract_type VARCHAR(10) NOT NULL,
+    strike_hash_rate DOUBLE PRECISION NOT NULL,
+    start_block_height BIGINT NOT NULL,
+    end_block_height BIGINT NOT NULL,
+    contracts BIGINT NOT NULL CHECK (contracts >= 0),
+    notional BIGINT NOT NULL CHECK (notional >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (contract_type, strike_hash_rate, start_block_height, end_block_height)
+);
+
+-- Open interest of a market after every change, for analytics
+CREATE TABLE open_interest_history (
+    id BIGSERIAL PRIMARY KEY,
+    contract_type VARCHAR(10) NOT NULL,
+    strike_hash_rate DOUBLE PRECISION NOT NULL,
+    start_block_height BIGINT NOT NULL,
+    end_block_height BIGINT NOT NULL,
+    contracts BIGINT NOT NULL,
+    notional BIGINT NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_open_interest_history_market ON open_interest_history(
+    contract_type, strike_hash_rate, start_block_height, end_block_height, recorded_at
+);
+
+-- Seed from the contracts already active
+INSERT INTO open_interest (
+    contract_type, strike_hash_rate, start_block_height, end_block_height, contracts, notional, updated_at
+)
+SELECT contract_type, strike_hash_rate, start_block_height, end_block_height, COUNT(*), SUM(contract_size), NOW()
+FROM contracts
+WHERE status = 'ACTIVE'
+GROUP BY contract_type, strike_hash_rate, start_block_height, end_block_height;
+
+INSERT INTO open_interest_history (
+    contract_type, strike_hash_rate, start_block_height, end_block_height, contracts, notional, recorded_at
+)
+SELECT contract_type, strike_hash_rate, start_block_height, end_block_height, contracts, notional, updated_at
+FROM open_interest;
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000017_hash_rate_observations.down.sql
+
+DROP TABLE IF EXISTS hash_rate_observations;
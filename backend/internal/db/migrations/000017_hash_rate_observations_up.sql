@@ -0,0 +1,15 @@
+-- internal/db/migrations/000017_hash_rate_observations.up.sql
+
+-- Network hash rate sampled at each new chain tip, in EH/s
+CREATE TABLE hash_rate_observations (
+    id BIGSERIAL PRIMARY KEY,
+    block_height BIGINT NOT NULL UNIQUE,
+    block_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    hash_rate DOUBLE PRECISION NOT NULL,
+    average_1d DOUBLE PRECISION,
+    average_7d DOUBLE PRECISION,
+    average_30d DOUBLE PRECISION,
+    observed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_hash_rate_observations_observed_at ON hash_rate_observations(observed_at);
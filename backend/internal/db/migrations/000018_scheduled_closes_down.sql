@@ -0,0 +1,3 @@
+-- internal/db/migrations/000018_scheduled_closes.down.sql
+
+DROP TABLE IF EXISTS scheduled_closes;
@@ -0,0 +1,20 @@
+-- internal/db/migrations/000018_scheduled_closes.up.sql
+
+-- Cooperative closes pre-agreed by both contract parties with signed intents
+CREATE TABLE scheduled_closes (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    close_height BIGINT,
+    close_at TIMESTAMP WITH TIME ZONE,
+    buyer_amount BIGINT NOT NULL CHECK (buyer_amount >= 0),
+    seller_amount BIGINT NOT NULL CHECK (seller_amount >= 0),
+    buyer_signature VARCHAR(128),
+    seller_signature VARCHAR(128),
+    status VARCHAR(20) NOT NULL,
+    close_tx_id VARCHAR(64),
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    CHECK ((close_height IS NULL) <> (close_at IS NULL))
+);
+
+CREATE INDEX idx_scheduled_closes_pending ON scheduled_closes(status, close_height, close_at);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000019_order_types.down.sql
+
+ALTER TABLE orders DROP COLUMN IF EXISTS order_type;
@@ -0,0 +1,6 @@
+-- internal/db/migrations/000019_order_types.up.sql
+
+-- Limit orders rest in the book; market orders fill immediately within the
+-- configured slippage and cancel the rest. Existing orders are all limits.
+ALTER TABLE orders ADD COLUMN order_type VARCHAR(10) NOT NULL DEFAULT 'LIMIT'
+    CHECK (order_type IN ('LIMIT', 'MARKET'));
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000020_order_time_in_force.down.sql
+
+ALTER TABLE orders DROP COLUMN IF EXISTS time_in_force;
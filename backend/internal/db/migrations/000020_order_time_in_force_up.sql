@@ -0,0 +1,6 @@
+-- internal/db/migrations/000020_order_time_in_force.up.sql
+
+-- How long an order stays in the book: good-til-cancelled, immediate-or-cancel
+-- or fill-or-kill. Existing orders all rest until filled, cancelled or expired.
+ALTER TABLE orders ADD COLUMN time_in_force VARCHAR(3) NOT NULL DEFAULT 'GTC'
+    CHECK (time_in_force IN ('GTC', 'IOC', 'FOK'));
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000021_settlement_evidence.down.sql
+
+DROP TABLE IF EXISTS settlement_evidence;
@@ -0,0 +1,19 @@
+-- internal/db/migrations/000021_settlement_evidence.up.sql
+
+-- The chain state each settlement decision was based on, timestamped in
+-- Bitcoin. The bundle is stored as the exact bytes that were hashed so the
+-- digest can be recomputed.
+CREATE TABLE settlement_evidence (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    bundle BYTEA NOT NULL,
+    digest VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    proof BYTEA,
+    anchor_height BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    anchored_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_settlement_evidence_status ON settlement_evidence(status, updated_at);
@@ -0,0 +1,4 @@
+-- internal/db/migrations/000022_watchtower_delegations.down.sql
+
+DROP TABLE IF EXISTS watchtower_delegations;
+DROP TABLE IF EXISTS watchtowers;
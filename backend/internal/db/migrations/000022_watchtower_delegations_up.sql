@@ -0,0 +1,36 @@
+-- internal/db/migrations/000022_watchtower_delegations.up.sql
+
+-- Third-party services trusted to broadcast emergency exits. Only a hash of
+-- each access token is stored.
+CREATE TABLE watchtowers (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    encryption_key VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Exit packages of contract participants, sealed to the watchtower they are
+-- delegated to
+CREATE TABLE watchtower_delegations (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    watchtower_id UUID NOT NULL REFERENCES watchtowers(id) ON DELETE CASCADE,
+    pub_key VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    package BYTEA NOT NULL,
+    package_digest VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A participant delegates a contract to a watchtower at most once at a time
+CREATE UNIQUE INDEX idx_watchtower_delegations_active
+    ON watchtower_delegations(contract_id, pub_key, watchtower_id)
+    WHERE status <> 'REVOKED';
+CREATE INDEX idx_watchtower_delegations_watchtower ON watchtower_delegations(watchtower_id, status);
+CREATE INDEX idx_watchtower_delegations_contract ON watchtower_delegations(contract_id);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000023_settlement_attempts.down.sql
+
+DROP TABLE IF EXISTS settlement_attempts;
@@ -0,0 +1,16 @@
+-- internal/db/migrations/000023_settlement_attempts.up.sql
+
+-- Every automatic settlement attempt of a matured contract and its outcome
+CREATE TABLE settlement_attempts (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    outcome VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    settlement_tx_id VARCHAR(64),
+    buyer_wins BOOLEAN,
+    block_height BIGINT NOT NULL,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_settlement_attempts_contract ON settlement_attempts(contract_id, attempted_at);
+CREATE INDEX idx_settlement_attempts_outcome ON settlement_attempts(outcome, attempted_at);
@@ -0,0 +1,5 @@
+-- internal/db/migrations/000024_contract_notional.down.sql
+
+ALTER TABLE contracts DROP COLUMN IF EXISTS settlement_currency;
+ALTER TABLE contracts DROP COLUMN IF EXISTS notional_quantity;
+ALTER TABLE contracts DROP COLUMN IF EXISTS notional_unit;
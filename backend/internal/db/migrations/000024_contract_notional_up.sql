@@ -0,0 +1,7 @@
+-- internal/db/migrations/000024_contract_notional.up.sql
+
+-- What one unit of a contract represents. Existing contracts are quoted per
+-- whole contract, settled in bitcoin.
+ALTER TABLE contracts ADD COLUMN notional_unit VARCHAR(20) NOT NULL DEFAULT 'CONTRACT';
+ALTER TABLE contracts ADD COLUMN notional_quantity DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (notional_quantity > 0);
+ALTER TABLE contracts ADD COLUMN settlement_currency VARCHAR(10) NOT NULL DEFAULT 'BTC';
@@ -0,0 +1,6 @@
+-- internal/db/migrations/000025_market_maker_protection.down.sql
+
+DROP TABLE IF EXISTS market_maker_protections;
+DROP INDEX IF EXISTS idx_orders_api_key;
+ALTER TABLE orders DROP COLUMN IF EXISTS cancel_on_disconnect;
+ALTER TABLE orders DROP COLUMN IF EXISTS api_key_id;
@@ -0,0 +1,16 @@
+-- internal/db/migrations/000025_market_maker_protection.up.sql
+
+-- The API key an order was placed with, and whether the order is cancelled
+-- when the key's last websocket session closes
+ALTER TABLE orders ADD COLUMN api_key_id UUID REFERENCES api_keys(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN cancel_on_disconnect BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_orders_api_key ON orders(api_key_id) WHERE api_key_id IS NOT NULL;
+
+-- Fill limits of API keys, past which the key's orders are pulled
+CREATE TABLE market_maker_protections (
+    api_key_id UUID PRIMARY KEY REFERENCES api_keys(id) ON DELETE CASCADE,
+    max_fills INTEGER NOT NULL CHECK (max_fills > 0),
+    window_seconds INTEGER NOT NULL CHECK (window_seconds > 0),
+    freeze_seconds INTEGER NOT NULL CHECK (freeze_seconds >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,6 @@
+-- internal/db/migrations/000026_research_feed.down.sql
+
+DROP INDEX IF EXISTS idx_settlement_evidence_created_at;
+DROP INDEX IF EXISTS idx_trades_executed_at;
+DROP TABLE IF EXISTS book_snapshots;
+DROP TABLE IF EXISTS research_access;
@@ -0,0 +1,25 @@
+-- internal/db/migrations/000026_research_feed.up.sql
+
+-- Users granted access to the research data feed and their tier
+CREATE TABLE research_access (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    tier VARCHAR(20) NOT NULL CHECK (tier IN ('BASIC', 'FULL')),
+    granted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Periodic aggregated order book of every market, for the research feed
+CREATE TABLE book_snapshots (
+    id UUID PRIMARY KEY,
+    contract_type VARCHAR(10) NOT NULL,
+    strike_hash_rate DOUBLE PRECISION NOT NULL,
+    start_block_height BIGINT NOT NULL,
+    end_block_height BIGINT NOT NULL,
+    bids BYTEA NOT NULL,
+    asks BYTEA NOT NULL,
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_book_snapshots_taken_at ON book_snapshots(taken_at, id);
+CREATE INDEX idx_trades_executed_at ON trades(executed_at, id);
+CREATE INDEX idx_settlement_evidence_created_at ON settlement_evidence(created_at, contract_id);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000027_order_priority.down.sql
+
+ALTER TABLE orders DROP COLUMN IF EXISTS priority_at;
@@ -0,0 +1,7 @@
+-- internal/db/migrations/000027_order_priority.up.sql
+
+-- Time priority of an order in its price level. It starts at the creation
+-- time and is reset when an amendment loses the order its place in the queue.
+ALTER TABLE orders ADD COLUMN priority_at TIMESTAMP WITH TIME ZONE;
+UPDATE orders SET priority_at = created_at;
+ALTER TABLE orders ALTER COLUMN priority_at SET NOT NULL;
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000028_vtxos.down.sql
+
+DROP TABLE IF EXISTS vtxos;
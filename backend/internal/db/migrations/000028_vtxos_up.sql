@@ -0,0 +1,18 @@
+-- internal/db/migrations/000028_vtxos.up.sql
+
+-- Ark virtual UTXOs holding contract funds. A contract's VTXO is replaced
+-- when an out-of-round transaction, such as a participant swap, spends it.
+CREATE TABLE vtxos (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    vtxo_id VARCHAR(100) NOT NULL UNIQUE,
+    round_id VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    script TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('ACTIVE', 'SPENT')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    spent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_vtxos_contract_id ON vtxos(contract_id, created_at);
+CREATE UNIQUE INDEX idx_vtxos_active_contract ON vtxos(contract_id) WHERE status = 'ACTIVE';
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000029_audit_log.down.sql
+
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,24 @@
+-- internal/db/migrations/000029_audit_log.up.sql
+
+-- Every state-changing API call on contracts and orders, with snapshots of
+-- the resource before and after, for resolving disputes over settled contracts
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    user_id UUID,
+    api_key_id UUID,
+    method VARCHAR(10) NOT NULL,
+    path TEXT NOT NULL,
+    resource_type VARCHAR(20) NOT NULL,
+    resource_id UUID,
+    request JSONB,
+    before JSONB,
+    after JSONB,
+    status INTEGER NOT NULL,
+    remote_addr VARCHAR(100) NOT NULL,
+    request_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_user_id ON audit_log(user_id, created_at);
+CREATE INDEX idx_audit_log_resource ON audit_log(resource_type, resource_id, created_at);
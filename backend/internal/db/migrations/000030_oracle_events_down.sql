@@ -0,0 +1,3 @@
+-- internal/db/migrations/000030_oracle_events.down.sql
+
+DROP TABLE IF EXISTS oracle_events;
@@ -0,0 +1,15 @@
+-- internal/db/migrations/000030_oracle_events.up.sql
+
+-- Oracle events that contracts settle on. The oracle announces its key and
+-- nonce before the final transaction is built, then attests the outcome.
+CREATE TABLE oracle_events (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    oracle_pub_key VARCHAR(64) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    event_id VARCHAR(100) NOT NULL UNIQUE,
+    outcome VARCHAR(10) CHECK (outcome IN ('HIGH', 'LOW')),
+    observed_hash_rate DOUBLE PRECISION,
+    signature VARCHAR(128),
+    announced_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attested_at TIMESTAMP WITH TIME ZONE
+);
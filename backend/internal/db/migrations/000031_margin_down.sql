@@ -0,0 +1,4 @@
+-- internal/db/migrations/000031_margin.down.sql
+
+DROP TABLE IF EXISTS margin_positions;
+DROP TABLE IF EXISTS margin_accounts;
@@ -0,0 +1,27 @@
+-- internal/db/migrations/000031_margin.up.sql
+
+-- Collateral users hold with the platform to margin the contracts they
+-- write. Locked collateral backs open margin positions.
+CREATE TABLE margin_accounts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    locked BIGINT NOT NULL DEFAULT 0 CHECK (locked >= 0 AND locked <= balance),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- A contract whose writer posted margin instead of the full contract size
+CREATE TABLE margin_positions (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL UNIQUE REFERENCES contracts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    collateral BIGINT NOT NULL CHECK (collateral > 0),
+    required_margin BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(12) NOT NULL CHECK (status IN ('OPEN', 'CALLED', 'LIQUIDATED', 'CLOSED')),
+    called_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_margin_positions_user_id ON margin_positions(user_id, created_at DESC);
+CREATE INDEX idx_margin_positions_open ON margin_positions(created_at) WHERE status IN ('OPEN', 'CALLED');
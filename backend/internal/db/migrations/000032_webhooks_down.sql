@@ -0,0 +1,5 @@
+-- internal/db/migrations/000032_webhooks.down.sql
+
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000033_contract_listings.down.sql
+
+DROP TABLE IF EXISTS contract_listings;
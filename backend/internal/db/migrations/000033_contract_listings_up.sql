@@ -0,0 +1,25 @@
+-- internal/db/migrations/000033_contract_listings.up.sql
+
+-- A side of an active contract offered for sale on the secondary market.
+-- A pending listing has reserved its price in the buyer's margin account.
+CREATE TABLE contract_listings (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    party VARCHAR(6) NOT NULL CHECK (party IN ('BUYER', 'SELLER')),
+    pub_key VARCHAR(255) NOT NULL,
+    price BIGINT NOT NULL CHECK (price > 0),
+    status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN', 'PENDING', 'SOLD', 'CANCELLED')),
+    buyer_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    buyer_pub_key VARCHAR(255),
+    swap_tx_id VARCHAR(128),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sold_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A position is listed at most once at a time
+CREATE UNIQUE INDEX idx_contract_listings_live ON contract_listings(contract_id, party)
+    WHERE status IN ('OPEN', 'PENDING');
+CREATE INDEX idx_contract_listings_open ON contract_listings(created_at) WHERE status = 'OPEN';
+CREATE INDEX idx_contract_listings_seller_id ON contract_listings(seller_id, created_at DESC);
@@ -0,0 +1,8 @@
+-- internal/db/migrations/000034_settlement_broadcasts.down.sql
+
+DROP TABLE IF EXISTS settlement_broadcasts;
+
+UPDATE contracts SET status = 'SETTLED' WHERE status = 'PENDING_SETTLEMENT';
+ALTER TABLE contracts DROP CONSTRAINT contracts_status_check;
+ALTER TABLE contracts ADD CONSTRAINT contracts_status_check
+    CHECK (status IN ('CREATED', 'ACTIVE', 'SETTLED', 'EXPIRED', 'CANCELLED'));
@@ -0,0 +1,22 @@
+-- internal/db/migrations/000034_settlement_broadcasts.up.sql
+
+-- Contracts wait in PENDING_SETTLEMENT until their payout confirms
+ALTER TABLE contracts DROP CONSTRAINT contracts_status_check;
+ALTER TABLE contracts ADD CONSTRAINT contracts_status_check
+    CHECK (status IN ('CREATED', 'ACTIVE', 'PENDING_SETTLEMENT', 'SETTLED', 'EXPIRED', 'CANCELLED'));
+
+-- Broadcasts of each contract's payout transaction until it confirms
+CREATE TABLE settlement_broadcasts (
+    contract_id UUID PRIMARY KEY REFERENCES contracts(id) ON DELETE CASCADE,
+    transaction_id VARCHAR(64) NOT NULL,
+    tx_type VARCHAR(20) NOT NULL CHECK (tx_type IN ('settlement', 'close')),
+    buyer_wins BOOLEAN,
+    broadcasts INTEGER NOT NULL DEFAULT 0 CHECK (broadcasts >= 0),
+    last_error TEXT,
+    last_broadcast_at TIMESTAMP WITH TIME ZONE,
+    confirmations BIGINT NOT NULL DEFAULT 0 CHECK (confirmations >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_settlement_broadcasts_pending ON settlement_broadcasts(created_at) WHERE confirmed_at IS NULL;
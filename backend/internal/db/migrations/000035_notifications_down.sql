@@ -0,0 +1,3 @@
+-- internal/db/migrations/000035_notifications.down.sql
+
+DROP TABLE IF EXISTS notifications;
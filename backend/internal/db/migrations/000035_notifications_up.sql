@@ -0,0 +1,17 @@
+-- internal/db/migrations/000035_notifications.up.sql
+
+-- Notifications kept for each user until they mark them read
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL CHECK (type IN ('ORDER_FILL', 'CONTRACT_ACTIVATED', 'SETTLEMENT', 'ASP_OUTAGE', 'MARGIN_CALL')),
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    contract_id UUID REFERENCES contracts(id) ON DELETE CASCADE,
+    data JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
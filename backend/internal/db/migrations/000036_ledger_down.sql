@@ -0,0 +1,5 @@
+-- internal/db/migrations/000036_ledger.down.sql
+
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS ledger_journals;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,35 @@
+-- internal/db/migrations/000036_ledger.up.sql
+
+-- Double-entry ledger of users' funds. Each journal's postings sum to zero,
+-- and only the platform's external account may go negative.
+CREATE TABLE ledger_accounts (
+    id UUID PRIMARY KEY,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(12) NOT NULL CHECK (type IN ('AVAILABLE', 'COMMITTED', 'EXTERNAL')),
+    balance BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (user_id, type),
+    CHECK ((user_id IS NULL) = (type = 'EXTERNAL')),
+    CHECK (type = 'EXTERNAL' OR balance >= 0)
+);
+
+INSERT INTO ledger_accounts (id, user_id, type, balance, updated_at)
+VALUES (gen_random_uuid(), NULL, 'EXTERNAL', 0, NOW());
+
+CREATE TABLE ledger_journals (
+    id UUID PRIMARY KEY,
+    type VARCHAR(12) NOT NULL CHECK (type IN ('DEPOSIT', 'WITHDRAWAL', 'PREMIUM', 'COLLATERAL', 'PAYOUT')),
+    contract_id UUID REFERENCES contracts(id) ON DELETE SET NULL,
+    reference VARCHAR(100) UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE ledger_postings (
+    journal_id UUID NOT NULL REFERENCES ledger_journals(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    PRIMARY KEY (journal_id, account_id)
+);
+
+CREATE INDEX idx_ledger_postings_account ON ledger_postings(account_id);
+CREATE INDEX idx_ledger_journals_contract ON ledger_journals(contract_id) WHERE contract_id IS NOT NULL;
@@ -0,0 +1,6 @@
+-- internal/db/migrations/000037_deposits.down.sql
+
+DROP TABLE IF EXISTS deposit_scan;
+DROP TABLE IF EXISTS deposits;
+DROP TABLE IF EXISTS deposit_addresses;
+DROP SEQUENCE IF EXISTS deposit_address_index;
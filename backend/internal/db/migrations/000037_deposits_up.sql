@@ -0,0 +1,37 @@
+-- internal/db/migrations/000037_deposits.up.sql
+
+-- One taproot deposit address per user, derived from the configured
+-- extended public key at the next index of the external chain
+CREATE SEQUENCE deposit_address_index MINVALUE 0 START WITH 0;
+
+CREATE TABLE deposit_addresses (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    derivation_index BIGINT NOT NULL UNIQUE,
+    address VARCHAR(100) NOT NULL UNIQUE,
+    script_pub_key VARCHAR(68) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE deposits (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    txid VARCHAR(64) NOT NULL,
+    vout BIGINT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    block_hash VARCHAR(64) NOT NULL,
+    block_height BIGINT NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('PENDING', 'CREDITED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    credited_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (txid, vout)
+);
+
+CREATE INDEX idx_deposits_user ON deposits(user_id, created_at DESC);
+CREATE INDEX idx_deposits_pending ON deposits(block_height) WHERE status = 'PENDING';
+
+-- Height of the last block searched for deposits
+CREATE TABLE deposit_scan (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    height BIGINT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
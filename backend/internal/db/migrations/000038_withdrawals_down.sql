@@ -0,0 +1,8 @@
+-- internal/db/migrations/000038_withdrawals.down.sql
+
+DROP TABLE IF EXISTS withdrawals;
+
+-- Reversals already booked are kept so balances still add up
+ALTER TABLE ledger_journals DROP CONSTRAINT ledger_journals_type_check;
+ALTER TABLE ledger_journals ADD CONSTRAINT ledger_journals_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAWAL', 'PREMIUM', 'COLLATERAL', 'PAYOUT')) NOT VALID;
@@ -0,0 +1,24 @@
+-- internal/db/migrations/000038_withdrawals.up.sql
+
+-- Withdrawals that are not paid out are refunded with a reversal journal
+ALTER TABLE ledger_journals DROP CONSTRAINT ledger_journals_type_check;
+ALTER TABLE ledger_journals ADD CONSTRAINT ledger_journals_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAWAL', 'PREMIUM', 'COLLATERAL', 'PAYOUT', 'REVERSAL'));
+
+CREATE TABLE withdrawals (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    address VARCHAR(100) NOT NULL,
+    ark_address VARCHAR(200),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('PENDING_APPROVAL', 'APPROVED', 'PROCESSING', 'SENT', 'FAILED', 'REJECTED')),
+    rail VARCHAR(10) CHECK (rail IN ('ONCHAIN', 'ARK')),
+    txid VARCHAR(64),
+    reason TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_withdrawals_user ON withdrawals(user_id, created_at DESC);
+CREATE INDEX idx_withdrawals_status ON withdrawals(status, created_at);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000039_transaction_spend_psbt.down.sql
+
+ALTER TABLE contract_transactions DROP COLUMN IF EXISTS spend_psbt;
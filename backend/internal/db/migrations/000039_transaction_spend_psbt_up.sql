@@ -0,0 +1,5 @@
+-- internal/db/migrations/000039_transaction_spend_psbt.up.sql
+
+-- Unsigned final and settlement transactions with the leaf script and
+-- control block of each input, for the parties to sign
+ALTER TABLE contract_transactions ADD COLUMN spend_psbt TEXT;
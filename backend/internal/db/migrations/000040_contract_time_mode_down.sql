@@ -0,0 +1,6 @@
+-- internal/db/migrations/000040_contract_time_mode.down.sql
+
+ALTER TABLE contracts
+    DROP COLUMN IF EXISTS relative_seconds,
+    DROP COLUMN IF EXISTS relative_blocks,
+    DROP COLUMN IF EXISTS time_mode;
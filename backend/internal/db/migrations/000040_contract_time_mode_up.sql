@@ -0,0 +1,9 @@
+-- internal/db/migrations/000040_contract_time_mode.up.sql
+
+-- Relative contracts lock their outcome paths to a number of blocks and
+-- seconds after the setup transaction confirms
+ALTER TABLE contracts
+    ADD COLUMN time_mode VARCHAR(10) NOT NULL DEFAULT 'ABSOLUTE'
+        CHECK (time_mode IN ('ABSOLUTE', 'RELATIVE')),
+    ADD COLUMN relative_blocks BIGINT NOT NULL DEFAULT 0 CHECK (relative_blocks >= 0),
+    ADD COLUMN relative_seconds BIGINT NOT NULL DEFAULT 0 CHECK (relative_seconds >= 0);
@@ -0,0 +1,3 @@
+-- internal/db/migrations/000041_contract_status_transitions.down.sql
+
+DROP TABLE IF EXISTS contract_status_transitions;
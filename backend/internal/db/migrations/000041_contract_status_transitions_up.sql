@@ -0,0 +1,14 @@
+-- internal/db/migrations/000041_contract_status_transitions.up.sql
+
+-- Every status a contract moved through, for audit. from_status is empty
+-- for the contract's creation.
+CREATE TABLE contract_status_transitions (
+    id UUID PRIMARY KEY,
+    contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_contract_status_transitions_contract ON contract_status_transitions(contract_id, created_at);
@@ -0,0 +1,4 @@
+-- internal/db/migrations/000042_vtxo_asp.down.sql
+
+DROP INDEX IF EXISTS idx_vtxos_active_asp;
+ALTER TABLE vtxos DROP COLUMN IF EXISTS asp;
@@ -0,0 +1,8 @@
+-- internal/db/migrations/000042_vtxo_asp.up.sql
+
+-- The ASP holding each VTXO, by its configured name, so the VTXOs of an ASP
+-- that is down for good can be migrated to another. VTXOs issued before
+-- several ASPs were configured belong to the primary.
+ALTER TABLE vtxos ADD COLUMN asp VARCHAR(100) NOT NULL DEFAULT 'primary';
+
+CREATE INDEX idx_vtxos_active_asp ON vtxos(asp) WHERE status = 'ACTIVE';
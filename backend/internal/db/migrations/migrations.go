@@ -0,0 +1,280 @@
+// internal/db/migrations/migrations.go
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// ErrOutdated is returned when the database schema lags the migrations
+// built into the binary
+var ErrOutdated = errors.New("database schema is outdated")
+
+// lockID keys the advisory lock that keeps two processes from migrating
+// the same database at once
+const lockID = 727_113_001
+
+// fileName matches migration files, e.g. 000001_init_schema_up.sql
+var fileName = regexp.MustCompile(`^(\d{6})_([a-z0-9_]+)_(up|down)\.sql$`)
+
+// Migration is one versioned schema change and its reversal
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// parse reads the migrations of a directory. Every version needs an up and
+// a down file, and versions must run from 1 without gaps.
+func parse(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files named %s and %s", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d (%s) needs both an up and a down file", m.Version, m.Name)
+		}
+	}
+
+	return migrations, nil
+}
+
+// All returns the migrations built into the binary, oldest first
+func All() ([]Migration, error) {
+	return parse(files)
+}
+
+const createTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		applied_at TIMESTAMP WITH TIME ZONE NOT NULL
+	)
+`
+
+// Migrator applies the built in migrations to a database, recording each
+// applied version in the schema_migrations table
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// New creates a migrator for a database
+func New(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest is the version the built in migrations bring a database to
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version returns the latest migration applied to the database, zero for a
+// database that was never migrated
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	if _, err := m.db.ExecContext(ctx, createTable); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int
+	if err := m.db.GetContext(ctx, &version, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`); err != nil {
+		return 0, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Check returns ErrOutdated unless the database is at the latest version.
+// A database migrated by a newer build is an error too, since this build
+// may not understand its schema.
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case version < m.Latest():
+		return fmt.Errorf("%w: at version %d, expected %d", ErrOutdated, version, m.Latest())
+	case version > m.Latest():
+		return fmt.Errorf("database schema version %d is newer than this build's %d", version, m.Latest())
+	}
+	return nil
+}
+
+// Up applies the pending migrations up to and including target, or all of
+// them when target is zero, each in its own transaction. It returns the
+// migrations applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	if target == 0 {
+		target = m.Latest()
+	}
+	if target < 0 || target > m.Latest() {
+		return nil, fmt.Errorf("target version must be between 1 and %d", m.Latest())
+	}
+
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sqlx.Conn, version int) error {
+		for _, migration := range m.migrations[version:target] {
+			if err := apply(ctx, conn, migration.Up, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+					migration.Version, migration.Name, time.Now().UTC())
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and
+// returns the migrations reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least one")
+	}
+
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sqlx.Conn, version int) error {
+		if version > m.Latest() {
+			return fmt.Errorf("database schema version %d is newer than this build's %d", version, m.Latest())
+		}
+		for i := version - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if err := apply(ctx, conn, migration.Down, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases whose schema was created before versions were tracked
+func (m *Migrator) Baseline(ctx context.Context, version int) error {
+	if version < 1 || version > m.Latest() {
+		return fmt.Errorf("baseline version must be between 1 and %d", m.Latest())
+	}
+
+	return m.locked(ctx, func(conn *sqlx.Conn, current int) error {
+		if current != 0 {
+			return fmt.Errorf("database is already at version %d", current)
+		}
+		for _, migration := range m.migrations[:version] {
+			if _, err := conn.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, time.Now().UTC()); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// locked runs fn on a single connection holding the migration lock, with
+// the database's current version
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sqlx.Conn, version int) error) error {
+	if _, err := m.Version(ctx); err != nil {
+		return err
+	}
+
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	var version int
+	if err := conn.GetContext(ctx, &version, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`); err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	return fn(conn, version)
+}
+
+// apply runs a migration's SQL and records it in one transaction
+func apply(ctx context.Context, conn *sqlx.Conn, statements string, record func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := record(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
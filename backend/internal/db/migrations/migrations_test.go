@@ -0,0 +1,51 @@
+// internal/db/migrations/migrations_test.go
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	migrations, err := All()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	assert.Equal(t, 1, migrations[0].Version)
+	assert.Equal(t, "init_schema", migrations[0].Name)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func TestParse(t *testing.T) {
+	migrations, err := parse(fstest.MapFS{
+		"000002_orders_up.sql":   {Data: []byte("CREATE TABLE orders ();")},
+		"000002_orders_down.sql": {Data: []byte("DROP TABLE orders;")},
+		"000001_users_up.sql":    {Data: []byte("CREATE TABLE users ();")},
+		"000001_users_down.sql":  {Data: []byte("DROP TABLE users;")},
+		"README.md":              {Data: []byte("ignored")},
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "users", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE orders;", migrations[1].Down)
+
+	// A gap in the versions
+	_, err = parse(fstest.MapFS{
+		"000002_orders_up.sql":   {Data: []byte("CREATE TABLE orders ();")},
+		"000002_orders_down.sql": {Data: []byte("DROP TABLE orders;")},
+	})
+	assert.Error(t, err)
+
+	// A migration without its down file
+	_, err = parse(fstest.MapFS{
+		"000001_users_up.sql": {Data: []byte("CREATE TABLE users ();")},
+	})
+	assert.Error(t, err)
+}
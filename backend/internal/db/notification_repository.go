@@ -0,0 +1,161 @@
+// internal/db/notification_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ContractParty is a user who traded into a contract and the side they took
+type ContractParty struct {
+	UserID uuid.UUID        `db:"user_id"`
+	Side   models.OrderSide `db:"side"`
+}
+
+// NotificationRepository provides access to users' notifications
+type NotificationRepository struct {
+	db *DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create stores a new notification
+func (r *NotificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	n.CreatedAt = time.Now().UTC()
+	n.ReadAt = nil
+
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, body, contract_id, data, created_at)
+		VALUES (:id, :user_id, :type, :title, :body, :contract_id, :data, :created_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, n); err != nil {
+		return wrapError("failed to create notification", err)
+	}
+
+	return nil
+}
+
+// CreateForOpenContracts stores a copy of a notification for every user who
+// traded into a contract that is not yet settled, and returns the copies
+func (r *NotificationRepository) CreateForOpenContracts(ctx context.Context, n *models.Notification) ([]*models.Notification, error) {
+	var created []*models.Notification
+
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, body, data, created_at)
+		SELECT gen_random_uuid(), parties.user_id, $1, $2, $3, $4, $5
+		FROM (
+			SELECT DISTINCT o.user_id
+			FROM contracts c
+			JOIN trades t ON t.contract_id = c.id
+			JOIN orders o ON o.id = t.buy_order_id OR o.id = t.sell_order_id
+			WHERE c.status IN ($6, $7)
+		) parties
+		RETURNING *
+	`
+
+	err := r.db.SelectContext(ctx, &created, query,
+		n.Type, n.Title, n.Body, n.Data, time.Now().UTC(),
+		models.ContractStatusCreated, models.ContractStatusActive)
+	if err != nil {
+		return nil, wrapError("failed to create notifications", err)
+	}
+
+	return created, nil
+}
+
+// ListContractParties retrieves the users whose orders were filled into a
+// contract, with the side of their orders
+func (r *NotificationRepository) ListContractParties(ctx context.Context, contractID uuid.UUID) ([]ContractParty, error) {
+	var parties []ContractParty
+
+	query := `
+		SELECT DISTINCT o.user_id, o.side
+		FROM trades t
+		JOIN orders o ON o.id = t.buy_order_id OR o.id = t.sell_order_id
+		WHERE t.contract_id = $1
+	`
+
+	if err := r.db.SelectContext(ctx, &parties, query, contractID); err != nil {
+		return nil, wrapError("failed to list contract parties", err)
+	}
+
+	return parties, nil
+}
+
+// ListByUser retrieves a page of a user's notifications, newest first
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+
+	query := `
+		SELECT * FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.SelectContext(ctx, &notifications, query, userID, unreadOnly, limit, offset); err != nil {
+		return nil, wrapError("failed to list notifications", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread counts the notifications a user has not read
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		return 0, wrapError("failed to count unread notifications", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a notification of a user read, keeping the time it was
+// first read
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error) {
+	var n models.Notification
+
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, $3)
+		WHERE id = $1 AND user_id = $2
+		RETURNING *
+	`
+
+	if err := r.db.GetContext(ctx, &n, query, id, userID, time.Now().UTC()); err != nil {
+		return nil, wrapError(fmt.Sprintf("failed to mark notification %s read", id), err)
+	}
+
+	return &n, nil
+}
+
+// MarkAllRead marks every unread notification of a user read and returns
+// how many were
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `UPDATE notifications SET read_at = $2 WHERE user_id = $1 AND read_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, userID, time.Now().UTC())
+	if err != nil {
+		return 0, wrapError("failed to mark notifications read", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, wrapError("failed to get rows affected", err)
+	}
+
+	return rows, nil
+}
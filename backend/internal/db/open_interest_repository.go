@@ -0,0 +1,145 @@
+// internal/db/open_interest_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// OpenInterestRepository provides access to the open interest of each market and its history
+type OpenInterestRepository struct {
+	db *DB
+}
+
+// NewOpenInterestRepository creates a new open interest repository
+func NewOpenInterestRepository(db *DB) *OpenInterestRepository {
+	return &OpenInterestRepository{db: db}
+}
+
+// Adjust adds delta contracts of the contract's size to the open interest of
+// its market and records the result in the history
+func (r *OpenInterestRepository) Adjust(ctx context.Context, contract *models.Contract, delta int64) (*models.OpenInterest, error) {
+	var oi models.OpenInterest
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO open_interest (
+				contract_type, strike_hash_rate, start_block_height, end_block_height,
+				contracts, notional, updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (contract_type, strike_hash_rate, start_block_height, end_block_height) DO UPDATE SET
+				contracts = open_interest.contracts + EXCLUDED.contracts,
+				notional = open_interest.notional + EXCLUDED.notional,
+				updated_at = EXCLUDED.updated_at
+			RETURNING *
+		`
+
+		err := tx.GetContext(ctx, &oi, query,
+			contract.ContractType, contract.StrikeHashRate, contract.StartBlockHeight, contract.EndBlockHeight,
+			delta, delta*contract.ContractSize, time.Now().UTC())
+		if err != nil {
+			return wrapError("failed to adjust open interest", err)
+		}
+
+		query = `
+			INSERT INTO open_interest_history (
+				contract_type, strike_hash_rate, start_block_height, end_block_height,
+				contracts, notional, recorded_at
+			) VALUES (
+				:contract_type, :strike_hash_rate, :start_block_height, :end_block_height,
+				:contracts, :notional, :updated_at
+			)
+		`
+
+		if _, err := tx.NamedExecContext(ctx, query, &oi); err != nil {
+			return wrapError("failed to record open interest history", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oi, nil
+}
+
+// Get retrieves the open interest of a market, or nil if it never had an active contract
+func (r *OpenInterestRepository) Get(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+) (*models.OpenInterest, error) {
+	var oi models.OpenInterest
+
+	query := `
+		SELECT * FROM open_interest
+		WHERE contract_type = $1 AND strike_hash_rate = $2
+		AND start_block_height = $3 AND end_block_height = $4
+	`
+
+	err := r.db.GetContext(ctx, &oi, query, contractType, strikeHashRate, startBlockHeight, endBlockHeight)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get open interest", err)
+	}
+
+	return &oi, nil
+}
+
+// List retrieves the markets with open interest, ordered by contract type,
+// strike and end block height
+func (r *OpenInterestRepository) List(ctx context.Context) ([]*models.OpenInterest, error) {
+	var markets []*models.OpenInterest
+
+	query := `
+		SELECT * FROM open_interest
+		WHERE contracts > 0
+		ORDER BY contract_type, strike_hash_rate, end_block_height, start_block_height
+	`
+
+	if err := r.db.SelectContext(ctx, &markets, query); err != nil {
+		return nil, wrapError("failed to list open interest", err)
+	}
+
+	return markets, nil
+}
+
+// ListHistory retrieves the recorded open interest of a market since the
+// given time, newest first
+func (r *OpenInterestRepository) ListHistory(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+	since time.Time,
+	limit int,
+) ([]*models.OpenInterestPoint, error) {
+	var points []*models.OpenInterestPoint
+
+	query := `
+		SELECT * FROM open_interest_history
+		WHERE contract_type = $1 AND strike_hash_rate = $2
+		AND start_block_height = $3 AND end_block_height = $4
+		AND recorded_at >= $5
+		ORDER BY recorded_at DESC, id DESC
+		LIMIT $6
+	`
+
+	err := r.db.SelectContext(ctx, &points, query,
+		contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, limit)
+	if err != nil {
+		return nil, wrapError("failed to list open interest history", err)
+	}
+
+	return points, nil
+}
@@ -0,0 +1,87 @@
+// internal/db/oracle_event_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// OracleEventRepository provides access to the oracle events contracts settle on
+type OracleEventRepository struct {
+	db *DB
+}
+
+// NewOracleEventRepository creates a new oracle event repository
+func NewOracleEventRepository(db *DB) *OracleEventRepository {
+	return &OracleEventRepository{db: db}
+}
+
+// Create records the oracle event of a contract. A contract settles on a
+// single event, so announcing a second returns ErrConflict.
+func (r *OracleEventRepository) Create(ctx context.Context, event *models.OracleEvent) error {
+	event.AnnouncedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO oracle_events (
+			contract_id, oracle_pub_key, nonce, event_id, announced_at
+		) VALUES (
+			:contract_id, :oracle_pub_key, :nonce, :event_id, :announced_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, event); err != nil {
+		return wrapError("failed to create oracle event", err)
+	}
+
+	return nil
+}
+
+// GetByContract retrieves the oracle event of a contract, or nil if the
+// contract settles without an oracle
+func (r *OracleEventRepository) GetByContract(ctx context.Context, contractID uuid.UUID) (*models.OracleEvent, error) {
+	var event models.OracleEvent
+
+	query := `SELECT * FROM oracle_events WHERE contract_id = $1`
+	err := r.db.GetContext(ctx, &event, query, contractID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get oracle event", err)
+	}
+
+	return &event, nil
+}
+
+// Attest records the oracle's attestation of an event. An event is attested
+// once, so attesting it again returns ErrConflict.
+func (r *OracleEventRepository) Attest(ctx context.Context, event *models.OracleEvent) error {
+	now := time.Now().UTC()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE oracle_events
+		SET outcome = $1, observed_hash_rate = $2, signature = $3, attested_at = $4
+		WHERE contract_id = $5 AND signature IS NULL
+	`, event.Outcome, event.ObservedHashRate, event.Signature, now, event.ContractID)
+	if err != nil {
+		return wrapError("failed to attest oracle event", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("oracle event of contract %s is already attested: %w", event.ContractID, ErrConflict)
+	}
+
+	event.AttestedAt = &now
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"hashhedge/internal/models"
 )
 
@@ -28,23 +27,24 @@ func (r *OrderRepository) Create(ctx context.Context, order *models.Order) error
 	}
 	order.CreatedAt = time.Now().UTC()
 	order.UpdatedAt = order.CreatedAt
+	order.PriorityAt = order.CreatedAt
 	order.RemainingQuantity = order.Quantity
 
 	query := `
 		INSERT INTO orders (
-			id, user_id, side, contract_type, strike_hash_rate, start_block_height,
+			id, user_id, side, order_type, time_in_force, contract_type, strike_hash_rate, start_block_height,
 			end_block_height, price, quantity, remaining_quantity, status,
-			pub_key, created_at, updated_at, expires_at
+			pub_key, key_id, api_key_id, cancel_on_disconnect, created_at, updated_at, priority_at, expires_at, target_timestamp
 		) VALUES (
-			:id, :user_id, :side, :contract_type, :strike_hash_rate, :start_block_height,
+			:id, :user_id, :side, :order_type, :time_in_force, :contract_type, :strike_hash_rate, :start_block_height,
 			:end_block_height, :price, :quantity, :remaining_quantity, :status,
-			:pub_key, :created_at, :updated_at, :expires_at
+			:pub_key, :key_id, :api_key_id, :cancel_on_disconnect, :created_at, :updated_at, :priority_at, :expires_at, :target_timestamp
 		)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, order)
 	if err != nil {
-		return fmt.Errorf("failed to create order: %w", err)
+		return wrapError("failed to create order", err)
 	}
 
 	return nil
@@ -57,7 +57,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Or
 	query := `SELECT * FROM orders WHERE id = $1`
 	err := r.db.GetContext(ctx, &order, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order by ID: %w", err)
+		return nil, wrapError("failed to get order by ID", err)
 	}
 
 	return &order, nil
@@ -86,7 +86,38 @@ func (r *OrderRepository) Update(ctx context.Context, order *models.Order) error
 
 	_, err := r.db.NamedExecContext(ctx, query, order)
 	if err != nil {
-		return fmt.Errorf("failed to update order: %w", err)
+		return wrapError("failed to update order", err)
+	}
+
+	return nil
+}
+
+// Amend updates the price, quantity and time priority of an order only if it
+// is still open or partially filled, returning ErrConflict otherwise
+func (r *OrderRepository) Amend(ctx context.Context, order *models.Order) error {
+	order.UpdatedAt = time.Now().UTC()
+
+	query := `
+		UPDATE orders
+		SET price = :price,
+		    quantity = :quantity,
+		    remaining_quantity = :remaining_quantity,
+		    priority_at = :priority_at,
+		    updated_at = :updated_at
+		WHERE id = :id AND status IN ('OPEN', 'PARTIAL')
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, order)
+	if err != nil {
+		return wrapError("failed to amend order", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("order %s is no longer open: %w", order.ID, ErrConflict)
 	}
 
 	return nil
@@ -103,7 +134,7 @@ func (r *OrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status
 
 	_, err := r.db.ExecContext(ctx, query, status, time.Now().UTC(), id)
 	if err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
+		return wrapError("failed to update order status", err)
 	}
 
 	return nil
@@ -119,17 +150,71 @@ func (r *OrderRepository) DecrementRemainingQuantity(ctx context.Context, id uui
 		        WHEN remaining_quantity - $1 <= 0 THEN 'FILLED'
 		        ELSE 'PARTIAL'
 		    END
-		WHERE id = $3
+		WHERE id = $3 AND status IN ('OPEN', 'PARTIAL')
 	`
 
-	_, err := r.db.ExecContext(ctx, query, amount, time.Now().UTC(), id)
+	result, err := r.db.ExecContext(ctx, query, amount, time.Now().UTC(), id)
+	if err != nil {
+		return wrapError("failed to decrement remaining quantity", err)
+	}
+
+	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to decrement remaining quantity: %w", err)
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("order %s is no longer open: %w", id, ErrConflict)
 	}
 
 	return nil
 }
 
+// CancelIfOpen cancels an order only if it is still open or partially filled,
+// reporting whether the cancellation took effect
+func (r *OrderRepository) CancelIfOpen(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE orders
+		SET status = 'CANCELLED',
+		    updated_at = $1
+		WHERE id = $2 AND status IN ('OPEN', 'PARTIAL')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return false, wrapError("failed to cancel order", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, wrapError("failed to get rows affected", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ExpireIfOpen marks an order expired if it is still open or partially
+// filled, reporting whether it was
+func (r *OrderRepository) ExpireIfOpen(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE orders
+		SET status = 'EXPIRED',
+		    updated_at = $1
+		WHERE id = $2 AND status IN ('OPEN', 'PARTIAL')
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id)
+	if err != nil {
+		return false, wrapError("failed to expire order", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, wrapError("failed to get rows affected", err)
+	}
+
+	return rows > 0, nil
+}
+
 // ListOpenOrders retrieves open orders that match the given criteria
 func (r *OrderRepository) ListOpenOrders(
 	ctx context.Context,
@@ -165,7 +250,7 @@ func (r *OrderRepository) ListOpenOrders(
 		offset,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list open orders: %w", err)
+		return nil, wrapError("failed to list open orders", err)
 	}
 
 	return orders, nil
@@ -179,36 +264,51 @@ func (r *OrderRepository) ListAllOpenOrders(ctx context.Context) ([]*models.Orde
 		SELECT * FROM orders
 		WHERE (status = 'OPEN' OR status = 'PARTIAL')
 		AND (expires_at IS NULL OR expires_at > NOW())
-		ORDER BY created_at
+		ORDER BY priority_at
 	`
 
 	err := r.db.SelectContext(ctx, &orders, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list all open orders: %w", err)
+		return nil, wrapError("failed to list all open orders", err)
 	}
 
 	return orders, nil
 }
 
-// ListUserOrders retrieves orders for a specific user
-func (r *OrderRepository) ListUserOrders(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Order, error) {
+// ListUserOrdersPage retrieves a page of a user's orders, newest first,
+// after the cursor
+func (r *OrderRepository) ListUserOrdersPage(ctx context.Context, userID uuid.UUID, after models.Cursor, limit int) ([]*models.Order, error) {
 	var orders []*models.Order
 
 	query := `
 		SELECT * FROM orders
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE user_id = $1 AND (created_at, id) < ($2, $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4
 	`
 
-	err := r.db.SelectContext(ctx, &orders, query, userID, limit, offset)
+	at, id := before(after)
+	err := r.db.SelectContext(ctx, &orders, query, userID, at, id, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list user orders: %w", err)
+		return nil, wrapError("failed to list user orders", err)
 	}
 
 	return orders, nil
 }
 
+// CountUserOrders counts a user's orders
+func (r *OrderRepository) CountUserOrders(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	query := `SELECT COUNT(*) FROM orders WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &count, query, userID)
+	if err != nil {
+		return 0, wrapError("failed to count user orders", err)
+	}
+
+	return count, nil
+}
+
 // CancelExpiredOrders cancels orders that have expired
 func (r *OrderRepository) CancelExpiredOrders(ctx context.Context) (int64, error) {
 	query := `
@@ -222,13 +322,13 @@ func (r *OrderRepository) CancelExpiredOrders(ctx context.Context) (int64, error
 
 	result, err := r.db.ExecContext(ctx, query, time.Now().UTC())
 	if err != nil {
-		return 0, fmt.Errorf("failed to cancel expired orders: %w", err)
+		return 0, wrapError("failed to cancel expired orders", err)
 	}
 
 	affected, err := result.RowsAffected()
 	if err != nil {
-		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+		return 0, wrapError("failed to get affected rows", err)
 	}
 
 	return affected, nil
-}height = :start_block_
+}
@@ -0,0 +1,26 @@
+// internal/db/page.go
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+var (
+	// lastTime and lastID sort after every stored row, so the zero cursor
+	// starts a newest-first listing at its newest row
+	lastTime = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+	lastID   = uuid.UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+)
+
+// before returns the keyset bound of a newest-first listing: rows strictly
+// before (time, id) in (time DESC, id DESC) order come after the cursor
+func before(cursor models.Cursor) (time.Time, uuid.UUID) {
+	if cursor.IsZero() {
+		return lastTime, lastID
+	}
+	return cursor.Time, cursor.ID
+}
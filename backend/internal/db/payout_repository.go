@@ -0,0 +1,126 @@
+// internal/db/payout_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// PayoutRepository provides access to the payout addresses of users and contract parties
+type PayoutRepository struct {
+	db *DB
+}
+
+// NewPayoutRepository creates a new payout repository
+func NewPayoutRepository(db *DB) *PayoutRepository {
+	return &PayoutRepository{db: db}
+}
+
+// GetUserAddress retrieves a user's payout address, or nil if none is registered
+func (r *PayoutRepository) GetUserAddress(ctx context.Context, userID uuid.UUID) (*models.PayoutAddress, error) {
+	var address models.PayoutAddress
+
+	query := `SELECT * FROM user_payout_addresses WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &address, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get payout address", err)
+	}
+
+	return &address, nil
+}
+
+// GetUserAddressByKeyID retrieves the payout address of the user owning a
+// registered key, or an empty string if none is registered
+func (r *PayoutRepository) GetUserAddressByKeyID(ctx context.Context, keyID uuid.UUID) (string, error) {
+	var address string
+
+	query := `
+		SELECT a.address FROM user_payout_addresses a
+		JOIN user_keys k ON k.user_id = a.user_id
+		WHERE k.id = $1
+	`
+
+	err := r.db.GetContext(ctx, &address, query, keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", wrapError("failed to get payout address by key", err)
+	}
+
+	return address, nil
+}
+
+// SetUserAddress registers or replaces a user's payout address
+func (r *PayoutRepository) SetUserAddress(ctx context.Context, address *models.PayoutAddress) error {
+	address.UpdatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO user_payout_addresses (user_id, address, updated_at)
+		VALUES (:user_id, :address, :updated_at)
+		ON CONFLICT (user_id) DO UPDATE SET
+			address = EXCLUDED.address,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, address); err != nil {
+		return wrapError("failed to set payout address", err)
+	}
+
+	return nil
+}
+
+// DeleteUserAddress removes a user's payout address
+func (r *PayoutRepository) DeleteUserAddress(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM user_payout_addresses WHERE user_id = $1`
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return wrapError("failed to delete payout address", err)
+	}
+
+	return nil
+}
+
+// GetContractAddress retrieves the payout address of a contract party, or an
+// empty string if the party is paid at its contract key
+func (r *PayoutRepository) GetContractAddress(ctx context.Context, contractID uuid.UUID, pubKey string) (string, error) {
+	var address string
+
+	query := `SELECT address FROM contract_payout_addresses WHERE contract_id = $1 AND pub_key = $2`
+	err := r.db.GetContext(ctx, &address, query, contractID, pubKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", wrapError("failed to get contract payout address", err)
+	}
+
+	return address, nil
+}
+
+// SetContractAddress records or replaces the payout address of a contract party
+func (r *PayoutRepository) SetContractAddress(ctx context.Context, address *models.ContractPayoutAddress) error {
+	address.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO contract_payout_addresses (contract_id, pub_key, address, created_at)
+		VALUES (:contract_id, :pub_key, :address, :created_at)
+		ON CONFLICT (contract_id, pub_key) DO UPDATE SET
+			address = EXCLUDED.address,
+			created_at = EXCLUDED.created_at
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, address); err != nil {
+		return wrapError("failed to set contract payout address", err)
+	}
+
+	return nil
+}
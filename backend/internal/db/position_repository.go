@@ -0,0 +1,93 @@
+// internal/db/position_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// PositionRepository reads the contracts users hold through their trades
+type PositionRepository struct {
+	db *DB
+}
+
+// NewPositionRepository creates a new position repository
+func NewPositionRepository(db *DB) *PositionRepository {
+	return &PositionRepository{db: db}
+}
+
+// ListByUser retrieves every side of a trade a user took part in, newest
+// first. A user who traded with themselves has an entry for each side.
+func (r *PositionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.PositionEntry, error) {
+	var entries []*models.PositionEntry
+
+	query := `
+		WITH sides AS (
+			SELECT t.id AS trade_id, 'BUY' AS side FROM trades t
+			JOIN orders o ON o.id = t.buy_order_id
+			WHERE o.user_id = $1
+			UNION ALL
+			SELECT t.id AS trade_id, 'SELL' AS side FROM trades t
+			JOIN orders o ON o.id = t.sell_order_id
+			WHERE o.user_id = $1
+		)
+		SELECT c.*, s.side, t.price AS entry_price, t.quantity, t.executed_at AS opened_at,
+			e.bundle AS evidence
+		FROM sides s
+		JOIN trades t ON t.id = s.trade_id
+		JOIN contracts c ON c.id = t.contract_id
+		LEFT JOIN settlement_evidence e ON e.contract_id = c.id
+		ORDER BY t.executed_at DESC, s.side
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, userID); err != nil {
+		return nil, wrapError("failed to list positions", err)
+	}
+
+	return entries, nil
+}
+
+// ListReportEntries retrieves every side of a trade a user took part in
+// whose trade executed, or whose contract's payout settled, in [from, to),
+// oldest trade first. A payout settles when it confirms, or when it is
+// built if settlements are not tracked.
+func (r *PositionRepository) ListReportEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.TradeReportEntry, error) {
+	var entries []*models.TradeReportEntry
+
+	query := `
+		WITH sides AS (
+			SELECT t.id AS trade_id, 'BUY' AS side FROM trades t
+			JOIN orders o ON o.id = t.buy_order_id
+			WHERE o.user_id = $1
+			UNION ALL
+			SELECT t.id AS trade_id, 'SELL' AS side FROM trades t
+			JOIN orders o ON o.id = t.sell_order_id
+			WHERE o.user_id = $1
+		), entries AS (
+			SELECT c.*, s.trade_id, s.side, t.price AS entry_price, t.quantity, t.executed_at AS opened_at,
+				e.bundle AS evidence,
+				CASE WHEN c.status = 'SETTLED' THEN COALESCE(sb.confirmed_at, st.created_at) END AS settled_at,
+				COALESCE(sb.buyer_wins, (e.bundle->>'buyer_wins')::boolean) AS buyer_wins
+			FROM sides s
+			JOIN trades t ON t.id = s.trade_id
+			JOIN contracts c ON c.id = t.contract_id
+			LEFT JOIN settlement_evidence e ON e.contract_id = c.id
+			LEFT JOIN settlement_broadcasts sb ON sb.contract_id = c.id
+			LEFT JOIN contract_transactions st ON st.contract_id = c.id AND st.transaction_id = c.settlement_tx_id
+		)
+		SELECT * FROM entries
+		WHERE (opened_at >= $2 AND opened_at < $3)
+			OR (settled_at >= $2 AND settled_at < $3)
+		ORDER BY opened_at, side
+	`
+
+	if err := r.db.SelectContext(ctx, &entries, query, userID, from, to); err != nil {
+		return nil, wrapError("failed to list trade report entries", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,154 @@
+// internal/db/price_alert_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// PriceAlertRepository provides access to price alert subscriptions
+type PriceAlertRepository struct {
+	db *DB
+}
+
+// NewPriceAlertRepository creates a new price alert repository
+func NewPriceAlertRepository(db *DB) *PriceAlertRepository {
+	return &PriceAlertRepository{db: db}
+}
+
+// Create inserts a new active price alert
+func (r *PriceAlertRepository) Create(ctx context.Context, alert *models.PriceAlert) error {
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+	alert.Status = models.AlertStatusActive
+	alert.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO price_alerts (
+			id, user_id, contract_type, strike_hash_rate, start_block_height, end_block_height,
+			metric, direction, threshold, channel, target, status, created_at
+		) VALUES (
+			:id, :user_id, :contract_type, :strike_hash_rate, :start_block_height, :end_block_height,
+			:metric, :direction, :threshold, :channel, :target, :status, :created_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, alert)
+	if err != nil {
+		return wrapError("failed to create price alert", err)
+	}
+
+	return nil
+}
+
+// CountActiveByUser counts the active alerts of a user
+func (r *PriceAlertRepository) CountActiveByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM price_alerts WHERE user_id = $1 AND status = $2`
+	err := r.db.GetContext(ctx, &count, query, userID, models.AlertStatusActive)
+	if err != nil {
+		return 0, wrapError("failed to count active price alerts", err)
+	}
+
+	return count, nil
+}
+
+// ListByUser retrieves all alerts of a user, newest first
+func (r *PriceAlertRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.PriceAlert, error) {
+	var alerts []*models.PriceAlert
+
+	query := `
+		SELECT * FROM price_alerts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &alerts, query, userID)
+	if err != nil {
+		return nil, wrapError("failed to list price alerts", err)
+	}
+
+	return alerts, nil
+}
+
+// ListActiveByMarket retrieves the active alerts of a market
+func (r *PriceAlertRepository) ListActiveByMarket(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+) ([]*models.PriceAlert, error) {
+	var alerts []*models.PriceAlert
+
+	query := `
+		SELECT * FROM price_alerts
+		WHERE status = $1
+		  AND contract_type = $2
+		  AND strike_hash_rate = $3
+		  AND start_block_height = $4
+		  AND end_block_height = $5
+	`
+
+	err := r.db.SelectContext(ctx, &alerts, query,
+		models.AlertStatusActive, contractType, strikeHashRate, startBlockHeight, endBlockHeight)
+	if err != nil {
+		return nil, wrapError("failed to list active price alerts", err)
+	}
+
+	return alerts, nil
+}
+
+// MarkTriggered moves an active alert to triggered, returning false if another
+// evaluation already triggered or cancelled it
+func (r *PriceAlertRepository) MarkTriggered(ctx context.Context, id uuid.UUID, value int64) (bool, error) {
+	query := `
+		UPDATE price_alerts
+		SET status = $1, triggered_value = $2, triggered_at = $3
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		models.AlertStatusTriggered, value, time.Now().UTC(), id, models.AlertStatusActive)
+	if err != nil {
+		return false, wrapError("failed to mark price alert triggered", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, wrapError("failed to get rows affected", err)
+	}
+
+	return rows > 0, nil
+}
+
+// Cancel cancels an active alert owned by the user
+func (r *PriceAlertRepository) Cancel(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		UPDATE price_alerts
+		SET status = $1
+		WHERE id = $2 AND user_id = $3 AND status = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		models.AlertStatusCancelled, id, userID, models.AlertStatusActive)
+	if err != nil {
+		return wrapError("failed to cancel price alert", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("active price alert %s: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
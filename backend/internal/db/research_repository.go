@@ -0,0 +1,185 @@
+// internal/db/research_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ResearchRepository provides access to research feed grants and to the
+// market data the feed serves. Every list is ordered by time then ID and
+// pages with a keyset cursor: rows strictly after (after, afterID) and at or
+// before until.
+type ResearchRepository struct {
+	db *DB
+}
+
+// NewResearchRepository creates a new research repository
+func NewResearchRepository(db *DB) *ResearchRepository {
+	return &ResearchRepository{db: db}
+}
+
+// GetActiveAccess retrieves a user's unexpired research feed grant
+func (r *ResearchRepository) GetActiveAccess(ctx context.Context, userID uuid.UUID) (*models.ResearchAccess, error) {
+	var access models.ResearchAccess
+
+	query := `SELECT * FROM research_access WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())`
+	if err := r.db.GetContext(ctx, &access, query, userID); err != nil {
+		return nil, wrapError("failed to get research access", err)
+	}
+
+	return &access, nil
+}
+
+// ListAccess retrieves every research feed grant, newest first
+func (r *ResearchRepository) ListAccess(ctx context.Context) ([]*models.ResearchAccess, error) {
+	var grants []*models.ResearchAccess
+
+	query := `SELECT * FROM research_access ORDER BY granted_at DESC`
+	if err := r.db.SelectContext(ctx, &grants, query); err != nil {
+		return nil, wrapError("failed to list research access", err)
+	}
+
+	return grants, nil
+}
+
+// GrantAccess gives a user research feed access or replaces their grant
+func (r *ResearchRepository) GrantAccess(ctx context.Context, access *models.ResearchAccess) error {
+	access.GrantedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO research_access (user_id, tier, granted_at, expires_at)
+		SELECT id, $2, $3, $4 FROM users WHERE id = $1
+		ON CONFLICT (user_id) DO UPDATE SET
+			tier = EXCLUDED.tier,
+			granted_at = EXCLUDED.granted_at,
+			expires_at = EXCLUDED.expires_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query, access.UserID, access.Tier, access.GrantedAt, access.ExpiresAt)
+	if err != nil {
+		return wrapError("failed to grant research access", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("user %s: %w", access.UserID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// RevokeAccess removes a user's research feed grant
+func (r *ResearchRepository) RevokeAccess(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM research_access WHERE user_id = $1`, userID)
+	if err != nil {
+		return wrapError("failed to revoke research access", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("research access of user %s: %w", userID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// CreateBookSnapshots stores the book snapshots of one sampling run
+func (r *ResearchRepository) CreateBookSnapshots(ctx context.Context, snapshots []*models.BookSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	for _, s := range snapshots {
+		if s.ID == uuid.Nil {
+			s.ID = uuid.New()
+		}
+	}
+
+	query := `
+		INSERT INTO book_snapshots (
+			id, contract_type, strike_hash_rate, start_block_height, end_block_height, bids, asks, taken_at
+		) VALUES (
+			:id, :contract_type, :strike_hash_rate, :start_block_height, :end_block_height, :bids, :asks, :taken_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, snapshots); err != nil {
+		return wrapError("failed to create book snapshots", err)
+	}
+
+	return nil
+}
+
+// ListBookSnapshots retrieves a page of book snapshots
+func (r *ResearchRepository) ListBookSnapshots(ctx context.Context, after time.Time, afterID uuid.UUID, until time.Time, limit int) ([]*models.BookSnapshot, error) {
+	var snapshots []*models.BookSnapshot
+
+	query := `
+		SELECT * FROM book_snapshots
+		WHERE (taken_at, id) > ($1, $2) AND taken_at <= $3
+		ORDER BY taken_at, id
+		LIMIT $4
+	`
+	if err := r.db.SelectContext(ctx, &snapshots, query, after, afterID, until, limit); err != nil {
+		return nil, wrapError("failed to list book snapshots", err)
+	}
+
+	return snapshots, nil
+}
+
+// ListTrades retrieves a page of trades with their market and participants
+func (r *ResearchRepository) ListTrades(ctx context.Context, after time.Time, afterID uuid.UUID, until time.Time, limit int) ([]*models.ResearchTrade, error) {
+	var trades []*models.ResearchTrade
+
+	query := `
+		SELECT t.id, t.contract_id, c.contract_type, c.strike_hash_rate, c.start_block_height,
+			c.end_block_height, t.price, t.quantity, t.price_rule, b.pub_key AS buyer_pub_key,
+			s.pub_key AS seller_pub_key, t.executed_at
+		FROM trades t
+		JOIN contracts c ON c.id = t.contract_id
+		JOIN orders b ON b.id = t.buy_order_id
+		JOIN orders s ON s.id = t.sell_order_id
+		WHERE (t.executed_at, t.id) > ($1, $2) AND t.executed_at <= $3
+		ORDER BY t.executed_at, t.id
+		LIMIT $4
+	`
+	if err := r.db.SelectContext(ctx, &trades, query, after, afterID, until, limit); err != nil {
+		return nil, wrapError("failed to list research trades", err)
+	}
+
+	return trades, nil
+}
+
+// ListSettlements retrieves a page of settled contracts with the evidence
+// their outcome was decided on, ordered by decision time
+func (r *ResearchRepository) ListSettlements(ctx context.Context, after time.Time, afterID uuid.UUID, until time.Time, limit int) ([]*models.ResearchSettlement, error) {
+	var settlements []*models.ResearchSettlement
+
+	query := `
+		SELECT c.*, e.bundle, e.digest, e.status AS evidence_status, e.created_at AS decided_at
+		FROM settlement_evidence e
+		JOIN contracts c ON c.id = e.contract_id
+		WHERE (e.created_at, e.contract_id) > ($1, $2) AND e.created_at <= $3
+		ORDER BY e.created_at, e.contract_id
+		LIMIT $4
+	`
+	if err := r.db.SelectContext(ctx, &settlements, query, after, afterID, until, limit); err != nil {
+		return nil, wrapError("failed to list research settlements", err)
+	}
+
+	return settlements, nil
+}
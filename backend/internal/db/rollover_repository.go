@@ -0,0 +1,136 @@
+// internal/db/rollover_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// RolloverRepository provides access to auto-roll instructions
+type RolloverRepository struct {
+	db *DB
+}
+
+// NewRolloverRepository creates a new rollover repository
+func NewRolloverRepository(db *DB) *RolloverRepository {
+	return &RolloverRepository{db: db}
+}
+
+// Create inserts a new pending auto-roll instruction
+func (r *RolloverRepository) Create(ctx context.Context, roll *models.AutoRoll) error {
+	if roll.ID == uuid.Nil {
+		roll.ID = uuid.New()
+	}
+	roll.Status = models.RolloverStatusPending
+	roll.CreatedAt = time.Now().UTC()
+	roll.UpdatedAt = roll.CreatedAt
+
+	query := `
+		INSERT INTO auto_rolls (id, user_id, order_id, price_offset, status, created_at, updated_at)
+		VALUES (:id, :user_id, :order_id, :price_offset, :status, :created_at, :updated_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, roll); err != nil {
+		return wrapError("failed to create auto-roll", err)
+	}
+
+	return nil
+}
+
+// GetPendingByOrder retrieves the pending auto-roll of an order
+func (r *RolloverRepository) GetPendingByOrder(ctx context.Context, orderID uuid.UUID) (*models.AutoRoll, error) {
+	var roll models.AutoRoll
+
+	query := `SELECT * FROM auto_rolls WHERE order_id = $1 AND status = $2`
+	if err := r.db.GetContext(ctx, &roll, query, orderID, models.RolloverStatusPending); err != nil {
+		return nil, wrapError("failed to get auto-roll", err)
+	}
+
+	return &roll, nil
+}
+
+// ListPending retrieves pending auto-rolls, oldest first
+func (r *RolloverRepository) ListPending(ctx context.Context, limit int) ([]*models.AutoRoll, error) {
+	var rolls []*models.AutoRoll
+
+	query := `SELECT * FROM auto_rolls WHERE status = $1 ORDER BY created_at LIMIT $2`
+	if err := r.db.SelectContext(ctx, &rolls, query, models.RolloverStatusPending, limit); err != nil {
+		return nil, wrapError("failed to list pending auto-rolls", err)
+	}
+
+	return rolls, nil
+}
+
+// MarkRolled records the order placed in the next expiry
+func (r *RolloverRepository) MarkRolled(ctx context.Context, id, rolledOrderID uuid.UUID) error {
+	query := `UPDATE auto_rolls SET status = $2, rolled_order_id = $3, updated_at = $4 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, models.RolloverStatusRolled, rolledOrderID, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to mark auto-roll rolled", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records why an auto-roll could not be placed
+func (r *RolloverRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `UPDATE auto_rolls SET status = $2, last_error = $3, updated_at = $4 WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id, models.RolloverStatusFailed, lastError, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to mark auto-roll failed", err)
+	}
+
+	return nil
+}
+
+// CancelByOrder cancels the pending auto-roll of a user's order
+func (r *RolloverRepository) CancelByOrder(ctx context.Context, userID, orderID uuid.UUID) error {
+	query := `
+		UPDATE auto_rolls SET status = $3, updated_at = $4
+		WHERE order_id = $1 AND user_id = $2 AND status = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		orderID, userID, models.RolloverStatusCancelled, time.Now().UTC(), models.RolloverStatusPending)
+	if err != nil {
+		return wrapError("failed to cancel auto-roll", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("auto-roll for order %s: %w", orderID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// CountUnsettledContracts counts the contracts filled from an order that have not settled yet
+func (r *RolloverRepository) CountUnsettledContracts(ctx context.Context, orderID uuid.UUID) (int, error) {
+	var count int
+
+	query := `
+		SELECT COUNT(DISTINCT c.id)
+		FROM trades t
+		JOIN contracts c ON c.id = t.contract_id
+		WHERE (t.buy_order_id = $1 OR t.sell_order_id = $1)
+		AND c.status IN ($2, $3)
+	`
+
+	err := r.db.GetContext(ctx, &count, query, orderID, models.ContractStatusCreated, models.ContractStatusActive)
+	if err != nil {
+		return 0, wrapError("failed to count unsettled contracts", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,165 @@
+// internal/db/scheduled_close_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ScheduledCloseRepository persists cooperative closes scheduled by contract parties
+type ScheduledCloseRepository struct {
+	db *DB
+}
+
+// NewScheduledCloseRepository creates a new scheduled close repository
+func NewScheduledCloseRepository(db *DB) *ScheduledCloseRepository {
+	return &ScheduledCloseRepository{db: db}
+}
+
+// Get retrieves the scheduled close of a contract, or nil if none was proposed
+func (r *ScheduledCloseRepository) Get(ctx context.Context, contractID uuid.UUID) (*models.ScheduledClose, error) {
+	var sc models.ScheduledClose
+
+	query := `SELECT * FROM scheduled_closes WHERE contract_id = $1`
+	err := r.db.GetContext(ctx, &sc, query, contractID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get scheduled close", err)
+	}
+
+	return &sc, nil
+}
+
+// Propose stores a pending close with new terms, replacing any earlier close
+// of the contract along with its signatures
+func (r *ScheduledCloseRepository) Propose(ctx context.Context, sc *models.ScheduledClose) error {
+	now := time.Now().UTC()
+	sc.Status = models.ScheduledCloseStatusPending
+	sc.CloseTxID = nil
+	sc.LastError = nil
+	sc.CreatedAt = now
+	sc.UpdatedAt = now
+
+	query := `
+		INSERT INTO scheduled_closes (
+			contract_id, close_height, close_at, buyer_amount, seller_amount,
+			buyer_signature, seller_signature, status, created_at, updated_at
+		) VALUES (
+			:contract_id, :close_height, :close_at, :buyer_amount, :seller_amount,
+			:buyer_signature, :seller_signature, :status, :created_at, :updated_at
+		)
+		ON CONFLICT (contract_id) DO UPDATE SET
+			close_height = EXCLUDED.close_height,
+			close_at = EXCLUDED.close_at,
+			buyer_amount = EXCLUDED.buyer_amount,
+			seller_amount = EXCLUDED.seller_amount,
+			buyer_signature = EXCLUDED.buyer_signature,
+			seller_signature = EXCLUDED.seller_signature,
+			status = EXCLUDED.status,
+			close_tx_id = NULL,
+			last_error = NULL,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, sc); err != nil {
+		return wrapError("failed to propose scheduled close", err)
+	}
+
+	return nil
+}
+
+// AddSignature records one party's signature on a pending close with the
+// given terms. Only that party's column is written, so both parties can sign
+// concurrently; ErrConflict is returned if the terms changed in the meantime.
+func (r *ScheduledCloseRepository) AddSignature(
+	ctx context.Context,
+	terms *models.ScheduledClose,
+	isBuyer bool,
+	signature string,
+) (*models.ScheduledClose, error) {
+	column := "seller_signature"
+	if isBuyer {
+		column = "buyer_signature"
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE scheduled_closes SET %s = $1, updated_at = $2
+		WHERE contract_id = $3 AND status = $4
+		AND close_height IS NOT DISTINCT FROM $5 AND close_at IS NOT DISTINCT FROM $6
+		AND buyer_amount = $7 AND seller_amount = $8
+		RETURNING *
+	`, column)
+
+	var sc models.ScheduledClose
+	err := r.db.GetContext(ctx, &sc, query,
+		signature, time.Now().UTC(), terms.ContractID, models.ScheduledCloseStatusPending,
+		terms.CloseHeight, terms.CloseAt, terms.BuyerAmount, terms.SellerAmount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scheduled close terms changed: %w", ErrConflict)
+	}
+	if err != nil {
+		return nil, wrapError("failed to sign scheduled close", err)
+	}
+
+	return &sc, nil
+}
+
+// Resolve moves a pending close to its final status
+func (r *ScheduledCloseRepository) Resolve(
+	ctx context.Context,
+	contractID uuid.UUID,
+	status models.ScheduledCloseStatus,
+	closeTxID *string,
+	lastError *string,
+) error {
+	query := `
+		UPDATE scheduled_closes SET status = $1, close_tx_id = $2, last_error = $3, updated_at = $4
+		WHERE contract_id = $5 AND status = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		status, closeTxID, lastError, time.Now().UTC(), contractID, models.ScheduledCloseStatusPending)
+	if err != nil {
+		return wrapError("failed to resolve scheduled close", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no pending scheduled close for contract %s: %w", contractID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListDue retrieves pending closes that have reached their block height or
+// time, earliest first
+func (r *ScheduledCloseRepository) ListDue(ctx context.Context, height int64, now time.Time, limit int) ([]*models.ScheduledClose, error) {
+	var closes []*models.ScheduledClose
+
+	query := `
+		SELECT * FROM scheduled_closes
+		WHERE status = $1 AND (close_height <= $2 OR close_at <= $3)
+		ORDER BY updated_at
+		LIMIT $4
+	`
+
+	err := r.db.SelectContext(ctx, &closes, query, models.ScheduledCloseStatusPending, height, now, limit)
+	if err != nil {
+		return nil, wrapError("failed to list due scheduled closes", err)
+	}
+
+	return closes, nil
+}
@@ -0,0 +1,70 @@
+// internal/db/settlement_attempt_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// SettlementAttemptRepository records the outcomes of automatic settlements
+type SettlementAttemptRepository struct {
+	db *DB
+}
+
+// NewSettlementAttemptRepository creates a new settlement attempt repository
+func NewSettlementAttemptRepository(db *DB) *SettlementAttemptRepository {
+	return &SettlementAttemptRepository{db: db}
+}
+
+// Create inserts a settlement attempt
+func (r *SettlementAttemptRepository) Create(ctx context.Context, attempt *models.SettlementAttempt) error {
+	if attempt.ID == uuid.Nil {
+		attempt.ID = uuid.New()
+	}
+	attempt.AttemptedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO settlement_attempts (
+			id, contract_id, outcome, reason, settlement_tx_id, buyer_wins,
+			block_height, attempted_at
+		) VALUES (
+			:id, :contract_id, :outcome, :reason, :settlement_tx_id, :buyer_wins,
+			:block_height, :attempted_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, attempt); err != nil {
+		return wrapError("failed to create settlement attempt", err)
+	}
+
+	return nil
+}
+
+// List retrieves the most recent attempts, newest first, optionally only
+// those with an outcome or of a contract
+func (r *SettlementAttemptRepository) List(
+	ctx context.Context,
+	outcome models.SettlementOutcome,
+	contractID *uuid.UUID,
+	limit, offset int,
+) ([]*models.SettlementAttempt, error) {
+	var attempts []*models.SettlementAttempt
+
+	query := `
+		SELECT * FROM settlement_attempts
+		WHERE ($1 = '' OR outcome = $1)
+			AND ($2::uuid IS NULL OR contract_id = $2)
+		ORDER BY attempted_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.SelectContext(ctx, &attempts, query, string(outcome), contractID, limit, offset); err != nil {
+		return nil, wrapError("failed to list settlement attempts", err)
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,159 @@
+// internal/db/settlement_broadcast_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// SettlementBroadcastRepository provides access to the broadcasts of
+// settlement transactions awaiting confirmation
+type SettlementBroadcastRepository struct {
+	db *DB
+}
+
+// NewSettlementBroadcastRepository creates a new settlement broadcast repository
+func NewSettlementBroadcastRepository(db *DB) *SettlementBroadcastRepository {
+	return &SettlementBroadcastRepository{db: db}
+}
+
+// Create starts tracking a contract's payout transaction
+func (r *SettlementBroadcastRepository) Create(ctx context.Context, broadcast *models.SettlementBroadcast) error {
+	broadcast.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO settlement_broadcasts (contract_id, transaction_id, tx_type, buyer_wins, created_at)
+		VALUES (:contract_id, :transaction_id, :tx_type, :buyer_wins, :created_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, broadcast); err != nil {
+		return wrapError("failed to create settlement broadcast", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the broadcast of a contract's payout, or nil if it has none
+func (r *SettlementBroadcastRepository) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementBroadcast, error) {
+	var broadcast models.SettlementBroadcast
+
+	query := `SELECT * FROM settlement_broadcasts WHERE contract_id = $1`
+	err := r.db.GetContext(ctx, &broadcast, query, contractID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get settlement broadcast", err)
+	}
+
+	return &broadcast, nil
+}
+
+// RecordBroadcast counts an attempt to broadcast a contract's payout,
+// keeping its error if it failed
+func (r *SettlementBroadcastRepository) RecordBroadcast(ctx context.Context, contractID uuid.UUID, broadcastErr error) error {
+	var lastError *string
+	if broadcastErr != nil {
+		msg := broadcastErr.Error()
+		lastError = &msg
+	}
+
+	query := `
+		UPDATE settlement_broadcasts
+		SET broadcasts = broadcasts + 1, last_error = $2, last_broadcast_at = $3
+		WHERE contract_id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, contractID, lastError, time.Now().UTC()); err != nil {
+		return wrapError("failed to record settlement broadcast", err)
+	}
+
+	return nil
+}
+
+// RecordConfirmations stores how deep a pending payout is in the chain
+func (r *SettlementBroadcastRepository) RecordConfirmations(ctx context.Context, contractID uuid.UUID, confirmations int64) error {
+	query := `
+		UPDATE settlement_broadcasts SET confirmations = $2
+		WHERE contract_id = $1 AND confirmed_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, contractID, confirmations); err != nil {
+		return wrapError("failed to record settlement confirmations", err)
+	}
+
+	return nil
+}
+
+// Confirm marks a contract's payout as confirmed. It returns nil if the
+// payout was already confirmed or is not tracked.
+func (r *SettlementBroadcastRepository) Confirm(
+	ctx context.Context,
+	contractID uuid.UUID,
+	confirmations int64,
+) (*models.SettlementBroadcast, error) {
+	var broadcast models.SettlementBroadcast
+
+	query := `
+		UPDATE settlement_broadcasts SET confirmations = $2, confirmed_at = $3
+		WHERE contract_id = $1 AND confirmed_at IS NULL
+		RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, &broadcast, query, contractID, confirmations, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to confirm settlement broadcast", err)
+	}
+
+	return &broadcast, nil
+}
+
+// ListPending retrieves payouts yet to confirm, oldest first
+func (r *SettlementBroadcastRepository) ListPending(ctx context.Context, limit int) ([]*models.SettlementBroadcast, error) {
+	var broadcasts []*models.SettlementBroadcast
+
+	query := `
+		SELECT * FROM settlement_broadcasts
+		WHERE confirmed_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`
+
+	if err := r.db.SelectContext(ctx, &broadcasts, query, limit); err != nil {
+		return nil, wrapError("failed to list pending settlement broadcasts", err)
+	}
+
+	return broadcasts, nil
+}
+
+// ListStuck retrieves a page of payouts first broadcast before the given
+// time that have yet to confirm, oldest first
+func (r *SettlementBroadcastRepository) ListStuck(
+	ctx context.Context,
+	before time.Time,
+	limit, offset int,
+) ([]*models.SettlementBroadcast, error) {
+	broadcasts := []*models.SettlementBroadcast{}
+
+	query := `
+		SELECT * FROM settlement_broadcasts
+		WHERE confirmed_at IS NULL AND created_at < $1
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &broadcasts, query, before, limit, offset); err != nil {
+		return nil, wrapError("failed to list stuck settlement broadcasts", err)
+	}
+
+	return broadcasts, nil
+}
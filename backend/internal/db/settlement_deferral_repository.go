@@ -0,0 +1,137 @@
+// internal/db/settlement_deferral_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// SettlementDeferralRepository provides access to settlements deferred by the fee policy
+type SettlementDeferralRepository struct {
+	db *DB
+}
+
+// NewSettlementDeferralRepository creates a new settlement deferral repository
+func NewSettlementDeferralRepository(db *DB) *SettlementDeferralRepository {
+	return &SettlementDeferralRepository{db: db}
+}
+
+// Get retrieves the deferral of a contract's settlement, or nil if it was never deferred
+func (r *SettlementDeferralRepository) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementDeferral, error) {
+	var deferral models.SettlementDeferral
+
+	query := `SELECT * FROM settlement_deferrals WHERE contract_id = $1`
+	err := r.db.GetContext(ctx, &deferral, query, contractID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to get settlement deferral", err)
+	}
+
+	return &deferral, nil
+}
+
+// Defer records that a contract's settlement is held back at the given fee
+// rate, replacing any earlier deferral that was already released
+func (r *SettlementDeferralRepository) Defer(
+	ctx context.Context,
+	contractID uuid.UUID,
+	feeRate float64,
+) (*models.SettlementDeferral, error) {
+	var deferral models.SettlementDeferral
+
+	query := `
+		INSERT INTO settlement_deferrals (contract_id, fee_rate, deferred_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (contract_id) DO UPDATE SET
+			fee_rate = EXCLUDED.fee_rate,
+			priority_fee_rate = NULL,
+			deferred_at = EXCLUDED.deferred_at,
+			released_at = NULL
+		RETURNING *
+	`
+
+	if err := r.db.GetContext(ctx, &deferral, query, contractID, feeRate, time.Now().UTC()); err != nil {
+		return nil, wrapError("failed to defer settlement", err)
+	}
+
+	return &deferral, nil
+}
+
+// SetPriorityFeeRate records the fee rate a party agreed to pay to release a
+// pending deferral. It returns nil if the contract has no pending deferral.
+func (r *SettlementDeferralRepository) SetPriorityFeeRate(
+	ctx context.Context,
+	contractID uuid.UUID,
+	feeRate float64,
+) (*models.SettlementDeferral, error) {
+	var deferral models.SettlementDeferral
+
+	query := `
+		UPDATE settlement_deferrals SET priority_fee_rate = $2
+		WHERE contract_id = $1 AND released_at IS NULL
+		RETURNING *
+	`
+
+	err := r.db.GetContext(ctx, &deferral, query, contractID, feeRate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, wrapError("failed to set priority fee rate", err)
+	}
+
+	return &deferral, nil
+}
+
+// Release marks a contract's deferral as released; it does nothing if the
+// settlement was never deferred
+func (r *SettlementDeferralRepository) Release(ctx context.Context, contractID uuid.UUID) error {
+	query := `
+		UPDATE settlement_deferrals SET released_at = $2
+		WHERE contract_id = $1 AND released_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, contractID, time.Now().UTC()); err != nil {
+		return wrapError("failed to release settlement deferral", err)
+	}
+
+	return nil
+}
+
+// ListPending retrieves pending deferrals, oldest first
+func (r *SettlementDeferralRepository) ListPending(ctx context.Context, limit int) ([]*models.SettlementDeferral, error) {
+	var deferrals []*models.SettlementDeferral
+
+	query := `
+		SELECT * FROM settlement_deferrals
+		WHERE released_at IS NULL
+		ORDER BY deferred_at
+		LIMIT $1
+	`
+
+	if err := r.db.SelectContext(ctx, &deferrals, query, limit); err != nil {
+		return nil, wrapError("failed to list pending settlement deferrals", err)
+	}
+
+	return deferrals, nil
+}
+
+// CountPending returns the number of pending deferrals
+func (r *SettlementDeferralRepository) CountPending(ctx context.Context) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM settlement_deferrals WHERE released_at IS NULL`
+	if err := r.db.GetContext(ctx, &count, query); err != nil {
+		return 0, wrapError("failed to count pending settlement deferrals", err)
+	}
+
+	return count, nil
+}
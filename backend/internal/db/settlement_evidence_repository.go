@@ -0,0 +1,121 @@
+// internal/db/settlement_evidence_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// SettlementEvidenceRepository persists settlement evidence bundles and
+// their timestamp proofs
+type SettlementEvidenceRepository struct {
+	db *DB
+}
+
+// NewSettlementEvidenceRepository creates a new settlement evidence repository
+func NewSettlementEvidenceRepository(db *DB) *SettlementEvidenceRepository {
+	return &SettlementEvidenceRepository{db: db}
+}
+
+// Create stores the evidence of a settlement. Evidence is written once per
+// contract; a second bundle for the same contract is ignored so the record
+// that was timestamped is never replaced.
+func (r *SettlementEvidenceRepository) Create(ctx context.Context, evidence *models.SettlementEvidence) error {
+	now := time.Now().UTC()
+	evidence.Status = models.EvidenceStatusRecorded
+	evidence.CreatedAt = now
+	evidence.UpdatedAt = now
+
+	query := `
+		INSERT INTO settlement_evidence (
+			contract_id, bundle, digest, status, created_at, updated_at
+		) VALUES (
+			:contract_id, :bundle, :digest, :status, :created_at, :updated_at
+		)
+		ON CONFLICT (contract_id) DO NOTHING
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, evidence); err != nil {
+		return wrapError("failed to create settlement evidence", err)
+	}
+
+	return nil
+}
+
+// Get retrieves the settlement evidence of a contract
+func (r *SettlementEvidenceRepository) Get(ctx context.Context, contractID uuid.UUID) (*models.SettlementEvidence, error) {
+	var evidence models.SettlementEvidence
+
+	query := `SELECT * FROM settlement_evidence WHERE contract_id = $1`
+	if err := r.db.GetContext(ctx, &evidence, query, contractID); err != nil {
+		return nil, wrapError("failed to get settlement evidence", err)
+	}
+
+	return &evidence, nil
+}
+
+// ListByStatus retrieves evidence in a status, least recently updated first
+func (r *SettlementEvidenceRepository) ListByStatus(ctx context.Context, status models.EvidenceStatus, limit int) ([]*models.SettlementEvidence, error) {
+	var evidence []*models.SettlementEvidence
+
+	query := `
+		SELECT * FROM settlement_evidence
+		WHERE status = $1
+		ORDER BY updated_at
+		LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &evidence, query, status, limit); err != nil {
+		return nil, wrapError("failed to list settlement evidence", err)
+	}
+
+	return evidence, nil
+}
+
+// UpdateProof stores a new timestamp proof for the evidence and moves it to
+// the given status, setting the submission or anchoring time on first entry
+func (r *SettlementEvidenceRepository) UpdateProof(
+	ctx context.Context,
+	contractID uuid.UUID,
+	status models.EvidenceStatus,
+	proof []byte,
+	anchorHeight *int64,
+) error {
+	now := time.Now().UTC()
+	var anchoredAt *time.Time
+	if status == models.EvidenceStatusAnchored {
+		anchoredAt = &now
+	}
+
+	query := `
+		UPDATE settlement_evidence SET
+			status = $1,
+			proof = $2,
+			anchor_height = $3,
+			submitted_at = COALESCE(submitted_at, $4),
+			anchored_at = COALESCE(anchored_at, $5),
+			updated_at = $4
+		WHERE contract_id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		status, proof, anchorHeight, now, anchoredAt, contractID)
+	if err != nil {
+		return wrapError("failed to update settlement evidence proof", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no settlement evidence for contract %s: %w", contractID, ErrNotFound)
+	}
+
+	return nil
+}
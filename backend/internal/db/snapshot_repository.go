@@ -0,0 +1,171 @@
+// internal/db/snapshot_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// SnapshotRepository reads and restores the contract state needed for disaster recovery
+type SnapshotRepository struct {
+	db *DB
+}
+
+// NewSnapshotRepository creates a new snapshot repository
+func NewSnapshotRepository(db *DB) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+// ContractState is the contract data captured by a disaster recovery snapshot
+type ContractState struct {
+	Contracts    []*models.Contract
+	Transactions []*models.ContractTransaction
+	Funding      []*models.ContractFunding
+	Inputs       []*models.ContractInput
+}
+
+// RestoreCounts reports how many rows a restore inserted per table
+type RestoreCounts struct {
+	Contracts    int64 `json:"contracts"`
+	Transactions int64 `json:"transactions"`
+	Funding      int64 `json:"funding"`
+	Inputs       int64 `json:"inputs"`
+}
+
+// Load reads all contract state in a single read-only repeatable read
+// transaction so the tables are consistent with each other
+func (r *SnapshotRepository) Load(ctx context.Context) (*ContractState, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, wrapError("failed to begin snapshot transaction", err)
+	}
+	defer tx.Rollback()
+
+	state := &ContractState{}
+
+	if err := tx.SelectContext(ctx, &state.Contracts, `SELECT * FROM contracts ORDER BY created_at`); err != nil {
+		return nil, wrapError("failed to read contracts", err)
+	}
+
+	if err := tx.SelectContext(ctx, &state.Transactions, `SELECT * FROM contract_transactions ORDER BY created_at`); err != nil {
+		return nil, wrapError("failed to read contract transactions", err)
+	}
+
+	if err := tx.SelectContext(ctx, &state.Funding, `SELECT * FROM contract_funding`); err != nil {
+		return nil, wrapError("failed to read contract funding", err)
+	}
+
+	if err := tx.SelectContext(ctx, &state.Inputs, `SELECT * FROM contract_inputs ORDER BY created_at`); err != nil {
+		return nil, wrapError("failed to read contract inputs", err)
+	}
+
+	return state, nil
+}
+
+// Restore inserts the snapshot state in one transaction. Rows that already
+// exist are left untouched, so restoring into a partially recovered database is safe.
+func (r *SnapshotRepository) Restore(ctx context.Context, state *ContractState) (*RestoreCounts, error) {
+	counts := &RestoreCounts{}
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		contractQuery := `
+			INSERT INTO contracts (
+				id, contract_type, strike_hash_rate, start_block_height, end_block_height,
+				target_timestamp, time_mode, relative_blocks, relative_seconds,
+				contract_size, premium, buyer_pub_key, seller_pub_key,
+				buyer_key_id, seller_key_id, notional_unit, notional_quantity, settlement_currency,
+				status, created_at, updated_at, expires_at, setup_tx_id, final_tx_id, settlement_tx_id
+			) VALUES (
+				:id, :contract_type, :strike_hash_rate, :start_block_height, :end_block_height,
+				:target_timestamp, :time_mode, :relative_blocks, :relative_seconds,
+				:contract_size, :premium, :buyer_pub_key, :seller_pub_key,
+				:buyer_key_id, :seller_key_id, :notional_unit, :notional_quantity, :settlement_currency,
+				:status, :created_at, :updated_at, :expires_at, :setup_tx_id, :final_tx_id, :settlement_tx_id
+			) ON CONFLICT (id) DO NOTHING
+		`
+		for _, c := range state.Contracts {
+			// Snapshots taken before contracts carried notional metadata
+			// or a time mode restore with the legacy per-contract quoting
+			// and absolute locks
+			if c.Notional == (models.Notional{}) {
+				c.Notional = models.DefaultNotional
+			}
+			if c.TimeMode == "" {
+				c.TimeMode = models.ContractTimeModeAbsolute
+			}
+			n, err := namedExecCount(ctx, tx, contractQuery, c)
+			if err != nil {
+				return fmt.Errorf("failed to restore contract %s: %w", c.ID, err)
+			}
+			counts.Contracts += n
+		}
+
+		txQuery := `
+			INSERT INTO contract_transactions (
+				id, contract_id, transaction_id, tx_type, tx_hex, spend_psbt, confirmed, created_at, confirmed_at
+			) VALUES (
+				:id, :contract_id, :transaction_id, :tx_type, :tx_hex, :spend_psbt, :confirmed, :created_at, :confirmed_at
+			) ON CONFLICT (id) DO NOTHING
+		`
+		for _, t := range state.Transactions {
+			n, err := namedExecCount(ctx, tx, txQuery, t)
+			if err != nil {
+				return fmt.Errorf("failed to restore transaction %s: %w", t.ID, err)
+			}
+			counts.Transactions += n
+		}
+
+		fundingQuery := `
+			INSERT INTO contract_funding (
+				contract_id, buyer_funded, seller_funded, buyer_signed, seller_signed,
+				buyer_psbt, seller_psbt, updated_at
+			) VALUES (
+				:contract_id, :buyer_funded, :seller_funded, :buyer_signed, :seller_signed,
+				:buyer_psbt, :seller_psbt, :updated_at
+			) ON CONFLICT (contract_id) DO NOTHING
+		`
+		for _, f := range state.Funding {
+			n, err := namedExecCount(ctx, tx, fundingQuery, f)
+			if err != nil {
+				return fmt.Errorf("failed to restore funding for contract %s: %w", f.ContractID, err)
+			}
+			counts.Funding += n
+		}
+
+		inputQuery := `
+			INSERT INTO contract_inputs (
+				id, contract_id, stage, source, txid, vout, value, leaf_index, created_at
+			) VALUES (
+				:id, :contract_id, :stage, :source, :txid, :vout, :value, :leaf_index, :created_at
+			) ON CONFLICT DO NOTHING
+		`
+		for _, in := range state.Inputs {
+			n, err := namedExecCount(ctx, tx, inputQuery, in)
+			if err != nil {
+				return fmt.Errorf("failed to restore input %s: %w", in.ID, err)
+			}
+			counts.Inputs += n
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// namedExecCount runs a named statement and returns the number of affected rows
+func namedExecCount(ctx context.Context, tx *sqlx.Tx, query string, arg interface{}) (int64, error) {
+	result, err := tx.NamedExecContext(ctx, query, arg)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
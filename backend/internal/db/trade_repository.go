@@ -4,7 +4,6 @@ package db
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,9 +30,9 @@ func (r *TradeRepository) Create(ctx context.Context, tx *sqlx.Tx, trade *models
 
 	query := `
 		INSERT INTO trades (
-			id, buy_order_id, sell_order_id, contract_id, price, quantity, executed_at
+			id, buy_order_id, sell_order_id, contract_id, price, quantity, price_rule, executed_at
 		) VALUES (
-			:id, :buy_order_id, :sell_order_id, :contract_id, :price, :quantity, :executed_at
+			:id, :buy_order_id, :sell_order_id, :contract_id, :price, :quantity, :price_rule, :executed_at
 		)
 	`
 
@@ -45,7 +44,7 @@ func (r *TradeRepository) Create(ctx context.Context, tx *sqlx.Tx, trade *models
 	}
 
 	if err != nil {
-		return fmt.Errorf("failed to create trade: %w", err)
+		return wrapError("failed to create trade", err)
 	}
 
 	return nil
@@ -58,7 +57,7 @@ func (r *TradeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tr
 	query := `SELECT * FROM trades WHERE id = $1`
 	err := r.db.GetContext(ctx, &trade, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get trade by ID: %w", err)
+		return nil, wrapError("failed to get trade by ID", err)
 	}
 
 	return &trade, nil
@@ -76,7 +75,25 @@ func (r *TradeRepository) ListByContractID(ctx context.Context, contractID uuid.
 
 	err := r.db.SelectContext(ctx, &trades, query, contractID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list trades by contract ID: %w", err)
+		return nil, wrapError("failed to list trades by contract ID", err)
+	}
+
+	return trades, nil
+}
+
+// ListByOrderID retrieves the trades an order took part in, oldest first
+func (r *TradeRepository) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*models.Trade, error) {
+	var trades []*models.Trade
+
+	query := `
+		SELECT * FROM trades
+		WHERE buy_order_id = $1 OR sell_order_id = $1
+		ORDER BY executed_at
+	`
+
+	err := r.db.SelectContext(ctx, &trades, query, orderID)
+	if err != nil {
+		return nil, wrapError("failed to list trades by order ID", err)
 	}
 
 	return trades, nil
@@ -97,7 +114,7 @@ func (r *TradeRepository) ListByUserID(ctx context.Context, userID uuid.UUID, li
 
 	err := r.db.SelectContext(ctx, &trades, query, userID, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list trades by user ID: %w", err)
+		return nil, wrapError("failed to list trades by user ID", err)
 	}
 
 	return trades, nil
@@ -115,8 +132,111 @@ func (r *TradeRepository) GetRecentTrades(ctx context.Context, limit int) ([]*mo
 
 	err := r.db.SelectContext(ctx, &trades, query, limit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent trades: %w", err)
+		return nil, wrapError("failed to get recent trades", err)
+	}
+
+	return trades, nil
+}
+
+// ListByMarket retrieves a page of the trades of a market since the given
+// time, newest first, after the cursor
+func (r *TradeRepository) ListByMarket(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+	since time.Time,
+	after models.Cursor,
+	limit int,
+) ([]*models.MarketTrade, error) {
+	var trades []*models.MarketTrade
+
+	query := `
+		SELECT t.id, t.contract_id, c.contract_type, c.strike_hash_rate, c.start_block_height,
+			c.end_block_height, t.price, t.quantity, t.price_rule, t.executed_at
+		FROM trades t
+		JOIN contracts c ON c.id = t.contract_id
+		WHERE c.contract_type = $1 AND c.strike_hash_rate = $2
+		AND c.start_block_height = $3 AND c.end_block_height = $4
+		AND t.executed_at >= $5 AND (t.executed_at, t.id) < ($6, $7)
+		ORDER BY t.executed_at DESC, t.id DESC
+		LIMIT $8
+	`
+
+	at, id := before(after)
+	err := r.db.SelectContext(ctx, &trades, query,
+		contractType, strikeHashRate, startBlockHeight, endBlockHeight, since, at, id, limit)
+	if err != nil {
+		return nil, wrapError("failed to list trades by market", err)
 	}
 
 	return trades, nil
 }
+
+// CountByMarket counts the trades of a market since the given time
+func (r *TradeRepository) CountByMarket(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+	since time.Time,
+) (int64, error) {
+	var count int64
+
+	query := `
+		SELECT COUNT(*)
+		FROM trades t
+		JOIN contracts c ON c.id = t.contract_id
+		WHERE c.contract_type = $1 AND c.strike_hash_rate = $2
+		AND c.start_block_height = $3 AND c.end_block_height = $4
+		AND t.executed_at >= $5
+	`
+
+	err := r.db.GetContext(ctx, &count, query,
+		contractType, strikeHashRate, startBlockHeight, endBlockHeight, since)
+	if err != nil {
+		return 0, wrapError("failed to count trades by market", err)
+	}
+
+	return count, nil
+}
+
+// ListCandles aggregates the trades of a market between since and until
+// into candles of the given interval, oldest first. Buckets are aligned to
+// the Unix epoch, so every client sees the same boundaries.
+func (r *TradeRepository) ListCandles(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight, endBlockHeight int64,
+	interval time.Duration,
+	since, until time.Time,
+) ([]*models.Candle, error) {
+	var candles []*models.Candle
+
+	query := `
+		SELECT
+			to_timestamp(floor(extract(epoch FROM t.executed_at) / $5) * $5) AS bucket_start,
+			(array_agg(t.price ORDER BY t.executed_at, t.id))[1] AS open,
+			MAX(t.price) AS high,
+			MIN(t.price) AS low,
+			(array_agg(t.price ORDER BY t.executed_at DESC, t.id DESC))[1] AS close,
+			SUM(t.quantity) AS volume,
+			COUNT(*) AS trades
+		FROM trades t
+		JOIN contracts c ON c.id = t.contract_id
+		WHERE c.contract_type = $1 AND c.strike_hash_rate = $2
+		AND c.start_block_height = $3 AND c.end_block_height = $4
+		AND t.executed_at >= $6 AND t.executed_at < $7
+		GROUP BY bucket_start
+		ORDER BY bucket_start
+	`
+
+	err := r.db.SelectContext(ctx, &candles, query,
+		contractType, strikeHashRate, startBlockHeight, endBlockHeight, interval.Seconds(), since, until)
+	if err != nil {
+		return nil, wrapError("failed to list candles", err)
+	}
+
+	return candles, nil
+}
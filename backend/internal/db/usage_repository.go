@@ -0,0 +1,95 @@
+// internal/db/usage_repository.go
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// UsageRepository persists hourly API usage counters
+type UsageRepository struct {
+	db *DB
+}
+
+// NewUsageRepository creates a new usage repository
+func NewUsageRepository(db *DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Add adds the given counters to the stored hourly rows, creating them as needed
+func (r *UsageRepository) Add(ctx context.Context, rows []*models.APIUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO api_usage (api_key_id, user_id, period_start, requests, errors, ws_messages, ws_bytes)
+		VALUES (:api_key_id, :user_id, :period_start, :requests, :errors, :ws_messages, :ws_bytes)
+		ON CONFLICT (api_key_id, period_start) DO UPDATE SET
+			requests = api_usage.requests + EXCLUDED.requests,
+			errors = api_usage.errors + EXCLUDED.errors,
+			ws_messages = api_usage.ws_messages + EXCLUDED.ws_messages,
+			ws_bytes = api_usage.ws_bytes + EXCLUDED.ws_bytes
+	`
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		for _, row := range rows {
+			if _, err := tx.NamedExecContext(ctx, query, row); err != nil {
+				return wrapError("failed to add API usage", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListByUser retrieves the hourly usage of a user's keys in [from, to), oldest first
+func (r *UsageRepository) ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.APIUsage, error) {
+	var rows []*models.APIUsage
+
+	query := `
+		SELECT * FROM api_usage
+		WHERE user_id = $1 AND period_start >= $2 AND period_start < $3
+		ORDER BY period_start, api_key_id
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, userID, from, to); err != nil {
+		return nil, wrapError("failed to list API usage", err)
+	}
+
+	return rows, nil
+}
+
+// Aggregate sums usage per API key in [from, to), busiest keys first
+func (r *UsageRepository) Aggregate(ctx context.Context, from, to time.Time, limit int) ([]*models.UsageAggregate, error) {
+	var aggregates []*models.UsageAggregate
+
+	query := `
+		SELECT api_key_id, user_id,
+			SUM(requests) AS requests,
+			SUM(errors) AS errors,
+			SUM(ws_messages) AS ws_messages,
+			SUM(ws_bytes) AS ws_bytes
+		FROM api_usage
+		WHERE period_start >= $1 AND period_start < $2
+		GROUP BY api_key_id, user_id
+		ORDER BY requests DESC
+		LIMIT $3
+	`
+
+	if err := r.db.SelectContext(ctx, &aggregates, query, from, to, limit); err != nil {
+		return nil, wrapError("failed to aggregate API usage", err)
+	}
+
+	for _, agg := range aggregates {
+		if agg.Requests > 0 {
+			agg.ErrorRate = float64(agg.Errors) / float64(agg.Requests)
+		}
+	}
+
+	return aggregates, nil
+}
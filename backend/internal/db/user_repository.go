@@ -3,10 +3,13 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"hashhedge/internal/models"
 )
 
@@ -38,7 +41,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return wrapError("failed to create user", err)
 	}
 
 	return nil
@@ -51,7 +54,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 	query := `SELECT * FROM users WHERE id = $1`
 	err := r.db.GetContext(ctx, &user, query, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by ID: %w", err)
+		return nil, wrapError("failed to get user by ID", err)
 	}
 
 	return &user, nil
@@ -64,7 +67,7 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	query := `SELECT * FROM users WHERE username = $1`
 	err := r.db.GetContext(ctx, &user, query, username)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by username: %w", err)
+		return nil, wrapError("failed to get user by username", err)
 	}
 
 	return &user, nil
@@ -77,7 +80,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	query := `SELECT * FROM users WHERE email = $1`
 	err := r.db.GetContext(ctx, &user, query, email)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
+		return nil, wrapError("failed to get user by email", err)
 	}
 
 	return &user, nil
@@ -99,7 +102,7 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 
 	_, err := r.db.NamedExecContext(ctx, query, user)
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return wrapError("failed to update user", err)
 	}
 
 	return nil
@@ -118,7 +121,7 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) erro
 
 	_, err := r.db.ExecContext(ctx, query, now, id)
 	if err != nil {
-		return fmt.Errorf("failed to update last login: %w", err)
+		return wrapError("failed to update last login", err)
 	}
 
 	return nil
@@ -133,15 +136,15 @@ func (r *UserRepository) AddKey(ctx context.Context, key *models.UserKey) error
 
 	query := `
 		INSERT INTO user_keys (
-			id, user_id, pub_key, key_type, label, created_at
+			id, user_id, pub_key, key_type, label, is_default, created_at
 		) VALUES (
-			:id, :user_id, :pub_key, :key_type, :label, :created_at
+			:id, :user_id, :pub_key, :key_type, :label, :is_default, :created_at
 		)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, key)
 	if err != nil {
-		return fmt.Errorf("failed to add user key: %w", err)
+		return wrapError("failed to add user key", err)
 	}
 
 	return nil
@@ -154,12 +157,12 @@ func (r *UserRepository) GetKeysByUserID(ctx context.Context, userID uuid.UUID)
 	query := `
 		SELECT * FROM user_keys
 		WHERE user_id = $1
-		ORDER BY created_at DESC
+		ORDER BY is_default DESC, created_at DESC
 	`
 
 	err := r.db.SelectContext(ctx, &keys, query, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get keys by user ID: %w", err)
+		return nil, wrapError("failed to get keys by user ID", err)
 	}
 
 	return keys, nil
@@ -170,8 +173,63 @@ func (r *UserRepository) DeleteKey(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM user_keys WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("failed to delete key: %w", err)
+		return wrapError("failed to delete key", err)
 	}
 
 	return nil
 }
+
+// GetKey retrieves a key by its ID, scoped to the owning user
+func (r *UserRepository) GetKey(ctx context.Context, userID, keyID uuid.UUID) (*models.UserKey, error) {
+	var key models.UserKey
+
+	query := `SELECT * FROM user_keys WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &key, query, keyID, userID)
+	if err != nil {
+		return nil, wrapError("failed to get key", err)
+	}
+
+	return &key, nil
+}
+
+// GetDefaultKey retrieves the user's default key, or nil if none is set
+func (r *UserRepository) GetDefaultKey(ctx context.Context, userID uuid.UUID) (*models.UserKey, error) {
+	var key models.UserKey
+
+	query := `SELECT * FROM user_keys WHERE user_id = $1 AND is_default`
+	err := r.db.GetContext(ctx, &key, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wrapError("failed to get default key", err)
+	}
+
+	return &key, nil
+}
+
+// SetDefaultKey makes the given key the user's default, clearing any previous default
+func (r *UserRepository) SetDefaultKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE user_keys SET is_default = FALSE WHERE user_id = $1 AND is_default`, userID); err != nil {
+			return wrapError("failed to clear default key", err)
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE user_keys SET is_default = TRUE WHERE id = $1 AND user_id = $2`, keyID, userID)
+		if err != nil {
+			return wrapError("failed to set default key", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return wrapError("failed to get rows affected", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("key %s: %w", keyID, ErrNotFound)
+		}
+
+		return nil
+	})
+}
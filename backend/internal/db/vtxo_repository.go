@@ -0,0 +1,130 @@
+// internal/db/vtxo_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// VTXORepository provides access to the Ark VTXOs holding contract funds
+type VTXORepository struct {
+	db *DB
+}
+
+// NewVTXORepository creates a new VTXO repository
+func NewVTXORepository(db *DB) *VTXORepository {
+	return &VTXORepository{db: db}
+}
+
+const insertVTXO = `
+	INSERT INTO vtxos (
+		id, contract_id, vtxo_id, round_id, amount, script, asp, status, created_at
+	) VALUES (
+		:id, :contract_id, :vtxo_id, :round_id, :amount, :script, :asp, :status, :created_at
+	)
+`
+
+// prepareVTXO fills in the generated fields of a new active VTXO
+func prepareVTXO(vtxo *models.VTXO) {
+	if vtxo.ID == uuid.Nil {
+		vtxo.ID = uuid.New()
+	}
+	vtxo.Status = models.VTXOStatusActive
+	vtxo.CreatedAt = time.Now().UTC()
+	vtxo.SpentAt = nil
+}
+
+// Create records the active VTXO of a contract. A contract has at most one
+// active VTXO, so recording a second returns ErrConflict.
+func (r *VTXORepository) Create(ctx context.Context, vtxo *models.VTXO) error {
+	prepareVTXO(vtxo)
+
+	if _, err := r.db.NamedExecContext(ctx, insertVTXO, vtxo); err != nil {
+		return wrapError("failed to create VTXO", err)
+	}
+
+	return nil
+}
+
+// GetActiveByContract retrieves the VTXO currently holding a contract's funds
+func (r *VTXORepository) GetActiveByContract(ctx context.Context, contractID uuid.UUID) (*models.VTXO, error) {
+	var vtxo models.VTXO
+
+	query := `SELECT * FROM vtxos WHERE contract_id = $1 AND status = 'ACTIVE'`
+
+	if err := r.db.GetContext(ctx, &vtxo, query, contractID); err != nil {
+		return nil, wrapError("failed to get contract VTXO", err)
+	}
+
+	return &vtxo, nil
+}
+
+// ListByContract retrieves every VTXO that has held a contract's funds, oldest first
+func (r *VTXORepository) ListByContract(ctx context.Context, contractID uuid.UUID) ([]*models.VTXO, error) {
+	var vtxos []*models.VTXO
+
+	query := `
+		SELECT * FROM vtxos
+		WHERE contract_id = $1
+		ORDER BY created_at
+	`
+
+	if err := r.db.SelectContext(ctx, &vtxos, query, contractID); err != nil {
+		return nil, wrapError("failed to list contract VTXOs", err)
+	}
+
+	return vtxos, nil
+}
+
+// ListActiveByASP retrieves the active VTXOs issued by an ASP, oldest first
+func (r *VTXORepository) ListActiveByASP(ctx context.Context, asp string) ([]*models.VTXO, error) {
+	var vtxos []*models.VTXO
+
+	query := `
+		SELECT * FROM vtxos
+		WHERE asp = $1 AND status = 'ACTIVE'
+		ORDER BY created_at
+	`
+
+	if err := r.db.SelectContext(ctx, &vtxos, query, asp); err != nil {
+		return nil, wrapError("failed to list ASP VTXOs", err)
+	}
+
+	return vtxos, nil
+}
+
+// Replace marks a contract's VTXO spent and records the VTXO that spent it,
+// atomically. It returns ErrConflict if the VTXO was already spent.
+func (r *VTXORepository) Replace(ctx context.Context, spentID string, next *models.VTXO) error {
+	prepareVTXO(next)
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE vtxos
+			SET status = 'SPENT', spent_at = $1
+			WHERE vtxo_id = $2 AND contract_id = $3 AND status = 'ACTIVE'
+		`, next.CreatedAt, spentID, next.ContractID)
+		if err != nil {
+			return wrapError("failed to spend VTXO", err)
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return wrapError("failed to get rows affected", err)
+		}
+		if rows == 0 {
+			return fmt.Errorf("VTXO %s is not active: %w", spentID, ErrConflict)
+		}
+
+		if _, err := tx.NamedExecContext(ctx, insertVTXO, next); err != nil {
+			return wrapError("failed to create VTXO", err)
+		}
+		return nil
+	})
+}
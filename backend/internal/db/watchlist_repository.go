@@ -0,0 +1,113 @@
+// internal/db/watchlist_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// WatchlistRepository provides access to user watchlists
+type WatchlistRepository struct {
+	db *DB
+}
+
+// NewWatchlistRepository creates a new watchlist repository
+func NewWatchlistRepository(db *DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+// Create inserts a new watchlist item
+func (r *WatchlistRepository) Create(ctx context.Context, item *models.WatchlistItem) error {
+	if item.ID == uuid.Nil {
+		item.ID = uuid.New()
+	}
+	item.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO watchlist_items (
+			id, user_id, item_type, contract_id, contract_type, strike_hash_rate,
+			start_block_height, end_block_height, notify, created_at
+		) VALUES (
+			:id, :user_id, :item_type, :contract_id, :contract_type, :strike_hash_rate,
+			:start_block_height, :end_block_height, :notify, :created_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, item)
+	if err != nil {
+		return wrapError("failed to create watchlist item", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves all watchlist items for a user
+func (r *WatchlistRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.WatchlistItem, error) {
+	var items []*models.WatchlistItem
+
+	query := `
+		SELECT * FROM watchlist_items
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &items, query, userID)
+	if err != nil {
+		return nil, wrapError("failed to list watchlist items", err)
+	}
+
+	return items, nil
+}
+
+// Delete removes a watchlist item owned by the user
+func (r *WatchlistRepository) Delete(ctx context.Context, userID, itemID uuid.UUID) error {
+	query := `DELETE FROM watchlist_items WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, itemID, userID)
+	if err != nil {
+		return wrapError("failed to delete watchlist item", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("watchlist item %s: %w", itemID, ErrNotFound)
+	}
+
+	return nil
+}
+
+// ListNotifiedWatchers returns the users who asked to be notified about events
+// of the contract, either directly or through its market
+func (r *WatchlistRepository) ListNotifiedWatchers(ctx context.Context, contract *models.Contract) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+
+	query := `
+		SELECT DISTINCT user_id FROM watchlist_items
+		WHERE notify = TRUE AND (
+			(item_type = 'CONTRACT' AND contract_id = $1) OR
+			(item_type = 'MARKET' AND contract_type = $2 AND strike_hash_rate = $3
+				AND start_block_height = $4 AND end_block_height = $5)
+		)
+	`
+
+	err := r.db.SelectContext(ctx, &userIDs, query,
+		contract.ID,
+		contract.ContractType,
+		contract.StrikeHashRate,
+		contract.StartBlockHeight,
+		contract.EndBlockHeight,
+	)
+	if err != nil {
+		return nil, wrapError("failed to list contract watchers", err)
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,246 @@
+// internal/db/watchtower_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// WatchtowerRepository provides access to watchtowers and the exit
+// delegations handed to them
+type WatchtowerRepository struct {
+	db *DB
+}
+
+// NewWatchtowerRepository creates a new watchtower repository
+func NewWatchtowerRepository(db *DB) *WatchtowerRepository {
+	return &WatchtowerRepository{db: db}
+}
+
+// Create inserts a new watchtower
+func (r *WatchtowerRepository) Create(ctx context.Context, tower *models.Watchtower) error {
+	if tower.ID == uuid.Nil {
+		tower.ID = uuid.New()
+	}
+	tower.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO watchtowers (id, name, encryption_key, prefix, token_hash, created_at)
+		VALUES (:id, :name, :encryption_key, :prefix, :token_hash, :created_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, tower); err != nil {
+		return wrapError("failed to create watchtower", err)
+	}
+
+	return nil
+}
+
+// GetActive retrieves an unrevoked watchtower
+func (r *WatchtowerRepository) GetActive(ctx context.Context, id uuid.UUID) (*models.Watchtower, error) {
+	var tower models.Watchtower
+
+	query := `SELECT * FROM watchtowers WHERE id = $1 AND revoked_at IS NULL`
+	if err := r.db.GetContext(ctx, &tower, query, id); err != nil {
+		return nil, wrapError("failed to get watchtower", err)
+	}
+
+	return &tower, nil
+}
+
+// GetActiveByHash retrieves an unrevoked watchtower by the hash of its token
+func (r *WatchtowerRepository) GetActiveByHash(ctx context.Context, tokenHash string) (*models.Watchtower, error) {
+	var tower models.Watchtower
+
+	query := `SELECT * FROM watchtowers WHERE token_hash = $1 AND revoked_at IS NULL`
+	if err := r.db.GetContext(ctx, &tower, query, tokenHash); err != nil {
+		return nil, wrapError("failed to get watchtower", err)
+	}
+
+	return &tower, nil
+}
+
+// List retrieves every watchtower, newest first
+func (r *WatchtowerRepository) List(ctx context.Context) ([]*models.Watchtower, error) {
+	var towers []*models.Watchtower
+
+	query := `SELECT * FROM watchtowers ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &towers, query); err != nil {
+		return nil, wrapError("failed to list watchtowers", err)
+	}
+
+	return towers, nil
+}
+
+// Revoke disables a watchtower and revokes its outstanding delegations
+func (r *WatchtowerRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return wrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx,
+		`UPDATE watchtowers SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, now)
+	if err != nil {
+		return wrapError("failed to revoke watchtower", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("watchtower %s: %w", id, ErrNotFound)
+	}
+
+	query := `
+		UPDATE watchtower_delegations SET status = $2, revoked_at = $3
+		WHERE watchtower_id = $1 AND status <> $2
+	`
+	if _, err := tx.ExecContext(ctx, query, id, models.DelegationStatusRevoked, now); err != nil {
+		return wrapError("failed to revoke watchtower delegations", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// CreateDelegation inserts a new pending delegation. A participant with an
+// outstanding delegation of the contract to the same watchtower gets
+// ErrConflict.
+func (r *WatchtowerRepository) CreateDelegation(ctx context.Context, delegation *models.WatchtowerDelegation) error {
+	if delegation.ID == uuid.Nil {
+		delegation.ID = uuid.New()
+	}
+	delegation.Status = models.DelegationStatusPending
+	delegation.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO watchtower_delegations (
+			id, contract_id, user_id, watchtower_id, pub_key, status,
+			package, package_digest, created_at
+		) VALUES (
+			:id, :contract_id, :user_id, :watchtower_id, :pub_key, :status,
+			:package, :package_digest, :created_at
+		)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, delegation); err != nil {
+		return wrapError("failed to create watchtower delegation", err)
+	}
+
+	return nil
+}
+
+// GetDelegation retrieves a delegation
+func (r *WatchtowerRepository) GetDelegation(ctx context.Context, id uuid.UUID) (*models.WatchtowerDelegation, error) {
+	var delegation models.WatchtowerDelegation
+
+	query := `SELECT * FROM watchtower_delegations WHERE id = $1`
+	if err := r.db.GetContext(ctx, &delegation, query, id); err != nil {
+		return nil, wrapError("failed to get watchtower delegation", err)
+	}
+
+	return &delegation, nil
+}
+
+// ListDelegationsByContract retrieves a user's delegations of a contract,
+// newest first
+func (r *WatchtowerRepository) ListDelegationsByContract(ctx context.Context, contractID, userID uuid.UUID) ([]*models.WatchtowerDelegation, error) {
+	var delegations []*models.WatchtowerDelegation
+
+	query := `
+		SELECT * FROM watchtower_delegations
+		WHERE contract_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+	`
+	if err := r.db.SelectContext(ctx, &delegations, query, contractID, userID); err != nil {
+		return nil, wrapError("failed to list watchtower delegations", err)
+	}
+
+	return delegations, nil
+}
+
+// ListDelegationsByWatchtower retrieves the delegations handed to a
+// watchtower in a status, oldest first
+func (r *WatchtowerRepository) ListDelegationsByWatchtower(
+	ctx context.Context,
+	watchtowerID uuid.UUID,
+	status models.DelegationStatus,
+	limit int,
+) ([]*models.WatchtowerDelegation, error) {
+	var delegations []*models.WatchtowerDelegation
+
+	query := `
+		SELECT * FROM watchtower_delegations
+		WHERE watchtower_id = $1 AND status = $2
+		ORDER BY created_at
+		LIMIT $3
+	`
+	if err := r.db.SelectContext(ctx, &delegations, query, watchtowerID, status, limit); err != nil {
+		return nil, wrapError("failed to list watchtower delegations", err)
+	}
+
+	return delegations, nil
+}
+
+// AcknowledgeDelegation marks a pending delegation of a watchtower as
+// stored. A delegation that is no longer pending gets ErrConflict.
+func (r *WatchtowerRepository) AcknowledgeDelegation(ctx context.Context, watchtowerID, id uuid.UUID) error {
+	query := `
+		UPDATE watchtower_delegations SET status = $3, acknowledged_at = $4
+		WHERE id = $1 AND watchtower_id = $2 AND status = $5
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, watchtowerID,
+		models.DelegationStatusAcknowledged, time.Now().UTC(), models.DelegationStatusPending)
+	if err != nil {
+		return wrapError("failed to acknowledge watchtower delegation", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("watchtower delegation %s is not pending: %w", id, ErrConflict)
+	}
+
+	return nil
+}
+
+// RevokeDelegation revokes an outstanding delegation of a user
+func (r *WatchtowerRepository) RevokeDelegation(ctx context.Context, userID, id uuid.UUID) error {
+	query := `
+		UPDATE watchtower_delegations SET status = $3, revoked_at = $4
+		WHERE id = $1 AND user_id = $2 AND status <> $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID, models.DelegationStatusRevoked, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to revoke watchtower delegation", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("watchtower delegation %s: %w", id, ErrNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+// internal/db/webhook_repository.go
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// WebhookRepository provides access to webhooks and their delivery queue
+type WebhookRepository struct {
+	db *DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Create stores a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	webhook.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, created_at)
+		VALUES (:id, :user_id, :url, :secret, :events, :created_at)
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, webhook); err != nil {
+		return wrapError("failed to create webhook", err)
+	}
+
+	return nil
+}
+
+// ListByUser retrieves the active webhooks of a user
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+
+	query := `SELECT * FROM webhooks WHERE user_id = $1 AND disabled_at IS NULL ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &webhooks, query, userID); err != nil {
+		return nil, wrapError("failed to list webhooks", err)
+	}
+
+	return webhooks, nil
+}
+
+// CountByUser counts the active webhooks of a user
+func (r *WebhookRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT COUNT(*) FROM webhooks WHERE user_id = $1 AND disabled_at IS NULL`
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		return 0, wrapError("failed to count webhooks", err)
+	}
+
+	return count, nil
+}
+
+// Disable stops deliveries to a webhook of a user and drops its queued
+// deliveries. Dead letters are kept so they can still be inspected.
+func (r *WebhookRepository) Disable(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return wrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE webhooks SET disabled_at = $1
+		WHERE id = $2 AND user_id = $3 AND disabled_at IS NULL
+	`, time.Now().UTC(), id, userID)
+	if err != nil {
+		return wrapError("failed to disable webhook", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("webhook %s: %w", id, ErrNotFound)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE webhook_id = $1`, id); err != nil {
+		return wrapError("failed to drop webhook deliveries", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// Enqueue queues an event for every active webhook of a user subscribed to
+// its type
+func (r *WebhookRepository) Enqueue(
+	ctx context.Context,
+	userID uuid.UUID,
+	eventType models.WebhookEventType,
+	payload []byte,
+) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), w.id, $2, $3, $4, $4
+		FROM webhooks w
+		WHERE w.user_id = $1 AND w.disabled_at IS NULL AND $2 = ANY(w.events)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, eventType, payload, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to enqueue webhook deliveries", err)
+	}
+
+	return nil
+}
+
+// EnqueueForContract queues an event for every active webhook, subscribed to
+// its type, of the users whose orders were filled into a contract
+func (r *WebhookRepository) EnqueueForContract(
+	ctx context.Context,
+	contractID uuid.UUID,
+	eventType models.WebhookEventType,
+	payload []byte,
+) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), w.id, $2, $3, $4, $4
+		FROM webhooks w
+		WHERE w.disabled_at IS NULL AND $2 = ANY(w.events) AND w.user_id IN (
+			SELECT o.user_id
+			FROM trades t
+			JOIN orders o ON o.id = t.buy_order_id OR o.id = t.sell_order_id
+			WHERE t.contract_id = $1
+		)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, contractID, eventType, payload, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to enqueue webhook deliveries", err)
+	}
+
+	return nil
+}
+
+// ClaimDue locks up to limit deliveries that are due and pushes their next
+// attempt out by lease, so that another instance does not send them while
+// they are in flight
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE webhook_deliveries d SET
+			attempts = d.attempts + 1,
+			next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.*, w.url, w.secret
+	`
+
+	err := r.db.SelectContext(ctx, &deliveries, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, wrapError("failed to claim due webhook deliveries", err)
+	}
+
+	return deliveries, nil
+}
+
+// Delivered removes a delivery the webhook accepted
+func (r *WebhookRepository) Delivered(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		return wrapError("failed to remove webhook delivery", err)
+	}
+	return nil
+}
+
+// Retry records a failed attempt and schedules the next one
+func (r *WebhookRepository) Retry(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `UPDATE webhook_deliveries SET last_error = $1, next_attempt_at = $2 WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, lastError, nextAttemptAt, id); err != nil {
+		return wrapError("failed to reschedule webhook delivery", err)
+	}
+	return nil
+}
+
+// DeadLetter moves a delivery that failed its last attempt to the dead
+// letter table
+func (r *WebhookRepository) DeadLetter(ctx context.Context, id uuid.UUID, lastError string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return wrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO webhook_dead_letters (
+			id, webhook_id, event_type, payload, attempts, last_error, created_at, failed_at
+		)
+		SELECT id, webhook_id, event_type, payload, attempts, $2, created_at, $3
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, id, lastError, time.Now().UTC()); err != nil {
+		return wrapError("failed to dead-letter webhook delivery", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		return wrapError("failed to remove webhook delivery", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", err)
+	}
+
+	return nil
+}
+
+// ListDeadLetters retrieves the dead letters of a user's webhooks, newest first
+func (r *WebhookRepository) ListDeadLetters(
+	ctx context.Context,
+	userID uuid.UUID,
+	limit, offset int,
+) ([]*models.WebhookDeadLetter, error) {
+	var letters []*models.WebhookDeadLetter
+
+	query := `
+		SELECT d.* FROM webhook_dead_letters d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE w.user_id = $1
+		ORDER BY d.failed_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &letters, query, userID, limit, offset); err != nil {
+		return nil, wrapError("failed to list webhook dead letters", err)
+	}
+
+	return letters, nil
+}
+
+// Redeliver moves a dead letter of a user's active webhook back onto the
+// delivery queue with a fresh set of attempts
+func (r *WebhookRepository) Redeliver(ctx context.Context, userID, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return wrapError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload, next_attempt_at, created_at)
+		SELECT d.id, d.webhook_id, d.event_type, d.payload, $3, d.created_at
+		FROM webhook_dead_letters d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.id = $1 AND w.user_id = $2 AND w.disabled_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, id, userID, time.Now().UTC())
+	if err != nil {
+		return wrapError("failed to requeue webhook dead letter", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return wrapError("failed to get rows affected", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("webhook dead letter %s: %w", id, ErrNotFound)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_dead_letters WHERE id = $1`, id); err != nil {
+		return wrapError("failed to remove webhook dead letter", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return wrapError("failed to commit transaction", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// internal/db/withdrawal_repository.go
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// WithdrawalRepository stores users' withdrawals
+type WithdrawalRepository struct {
+	db *DB
+}
+
+// NewWithdrawalRepository creates a new withdrawal repository
+func NewWithdrawalRepository(db *DB) *WithdrawalRepository {
+	return &WithdrawalRepository{db: db}
+}
+
+// Create stores a withdrawal and runs fn in the same transaction, so the
+// withdrawal exists exactly when its amount was debited
+func (r *WithdrawalRepository) Create(ctx context.Context, withdrawal *models.Withdrawal, fn func(*sqlx.Tx) error) error {
+	if withdrawal.ID == uuid.Nil {
+		withdrawal.ID = uuid.New()
+	}
+	withdrawal.CreatedAt = time.Now().UTC()
+	withdrawal.UpdatedAt = withdrawal.CreatedAt
+
+	return r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO withdrawals (id, user_id, amount, address, ark_address, status, created_at, updated_at)
+			VALUES (:id, :user_id, :amount, :address, :ark_address, :status, :created_at, :updated_at)
+		`
+		if _, err := tx.NamedExecContext(ctx, query, withdrawal); err != nil {
+			return wrapError("failed to create withdrawal", err)
+		}
+		return fn(tx)
+	})
+}
+
+// GetByID retrieves a withdrawal
+func (r *WithdrawalRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+
+	query := `SELECT * FROM withdrawals WHERE id = $1`
+	if err := r.db.GetContext(ctx, &withdrawal, query, id); err != nil {
+		return nil, wrapError("failed to get withdrawal", err)
+	}
+
+	return &withdrawal, nil
+}
+
+// ListByUser retrieves a page of a user's withdrawals, newest first
+func (r *WithdrawalRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Withdrawal, error) {
+	var withdrawals []*models.Withdrawal
+
+	query := `
+		SELECT * FROM withdrawals
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	if err := r.db.SelectContext(ctx, &withdrawals, query, userID, limit, offset); err != nil {
+		return nil, wrapError("failed to list withdrawals", err)
+	}
+
+	return withdrawals, nil
+}
+
+// ListByStatus retrieves a page of the withdrawals in a status, oldest first
+func (r *WithdrawalRepository) ListByStatus(ctx context.Context, status models.WithdrawalStatus, limit, offset int) ([]*models.Withdrawal, error) {
+	var withdrawals []*models.Withdrawal
+
+	query := `
+		SELECT * FROM withdrawals
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2 OFFSET $3
+	`
+	if err := r.db.SelectContext(ctx, &withdrawals, query, status, limit, offset); err != nil {
+		return nil, wrapError("failed to list withdrawals", err)
+	}
+
+	return withdrawals, nil
+}
+
+// Transition moves a withdrawal from one status to another, recording the
+// operator who reviewed it if set. It returns ErrConflict if the withdrawal
+// is no longer in the from status.
+func (r *WithdrawalRepository) Transition(ctx context.Context, id uuid.UUID, from, to models.WithdrawalStatus, reviewer *uuid.UUID) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+
+	query := `
+		UPDATE withdrawals
+		SET status = $3, reviewed_by = COALESCE($4, reviewed_by), updated_at = $5
+		WHERE id = $1 AND status = $2
+		RETURNING *
+	`
+	err := r.db.GetContext(ctx, &withdrawal, query, id, from, to, reviewer, time.Now().UTC())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("withdrawal %s is not %s: %w", id, from, ErrConflict)
+	}
+	if err != nil {
+		return nil, wrapError("failed to update withdrawal", err)
+	}
+
+	return &withdrawal, nil
+}
+
+// MarkSent records how a processing withdrawal was paid out
+func (r *WithdrawalRepository) MarkSent(ctx context.Context, id uuid.UUID, rail models.WithdrawalRail, txID string) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+
+	query := `
+		UPDATE withdrawals
+		SET status = $2, rail = $3, txid = $4, updated_at = $5
+		WHERE id = $1 AND status = $6
+		RETURNING *
+	`
+	err := r.db.GetContext(ctx, &withdrawal, query, id, models.WithdrawalStatusSent, rail, txID,
+		time.Now().UTC(), models.WithdrawalStatusProcessing)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("withdrawal %s is not %s: %w", id, models.WithdrawalStatusProcessing, ErrConflict)
+	}
+	if err != nil {
+		return nil, wrapError("failed to update withdrawal", err)
+	}
+
+	return &withdrawal, nil
+}
+
+// Refund moves a withdrawal that will not be paid out from one status to
+// a final one with the reason, running fn in the same transaction so the
+// amount is refunded exactly once
+func (r *WithdrawalRepository) Refund(
+	ctx context.Context,
+	id uuid.UUID,
+	from, to models.WithdrawalStatus,
+	reason string,
+	reviewer *uuid.UUID,
+	fn func(*sqlx.Tx) error,
+) (*models.Withdrawal, error) {
+	var withdrawal models.Withdrawal
+
+	err := r.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		query := `
+			UPDATE withdrawals
+			SET status = $3, reason = $4, reviewed_by = COALESCE($5, reviewed_by), updated_at = $6
+			WHERE id = $1 AND status = $2
+			RETURNING *
+		`
+		err := tx.GetContext(ctx, &withdrawal, query, id, from, to, reason, reviewer, time.Now().UTC())
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("withdrawal %s is not %s: %w", id, from, ErrConflict)
+		}
+		if err != nil {
+			return wrapError("failed to update withdrawal", err)
+		}
+		return fn(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &withdrawal, nil
+}
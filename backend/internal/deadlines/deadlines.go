@@ -0,0 +1,101 @@
+// internal/deadlines/deadlines.go
+package deadlines
+
+import (
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+const (
+	// BlockInterval is the target spacing between Bitcoin blocks
+	BlockInterval = 10 * time.Minute
+
+	// ExitTimelockBlocks is the relative timelock on the emergency exit path
+	ExitTimelockBlocks = 144
+)
+
+// Contract holds the machine-readable deadlines of a contract.
+// Fields are omitted when they no longer apply to the contract's status.
+type Contract struct {
+	SettleableAtHeight    *int64     `json:"settleable_at_height,omitempty"`
+	SettleableAt          *time.Time `json:"settleable_at,omitempty"`
+	ExitAvailableAtHeight *int64     `json:"exit_available_at_height,omitempty"`
+	ExitAvailableAt       *time.Time `json:"exit_available_at,omitempty"`
+	ExpiresInSeconds      *int64     `json:"expires_in_seconds,omitempty"`
+}
+
+// Order holds the machine-readable deadlines of an order
+type Order struct {
+	ExpiresInSeconds *int64 `json:"expires_in_seconds,omitempty"`
+}
+
+// ForContract computes the deadlines of a contract.
+// tipHeight is the current chain height; pass 0 if it is unknown, in which
+// case only the height-based fields are filled for chain-dependent deadlines.
+func ForContract(c *models.Contract, tipHeight int64, now time.Time) Contract {
+	var d Contract
+
+	if c.Status != models.ContractStatusCreated && c.Status != models.ContractStatusActive {
+		return d
+	}
+
+	// Settlement is allowed at the end height or the target timestamp, whichever comes first
+	settleHeight := c.EndBlockHeight
+	d.SettleableAtHeight = &settleHeight
+	settleAt := c.TargetTimestamp
+	if tipHeight > 0 {
+		if byHeight := EstimateHeightTime(settleHeight, tipHeight, now); byHeight.Before(settleAt) {
+			settleAt = byHeight
+		}
+	}
+	d.SettleableAt = &settleAt
+
+	if c.Status == models.ContractStatusActive {
+		exitHeight := c.StartBlockHeight + ExitTimelockBlocks
+		d.ExitAvailableAtHeight = &exitHeight
+		if tipHeight > 0 {
+			exitAt := EstimateHeightTime(exitHeight, tipHeight, now)
+			d.ExitAvailableAt = &exitAt
+		}
+	}
+
+	expiresIn := SecondsUntil(c.ExpiresAt, now)
+	d.ExpiresInSeconds = &expiresIn
+
+	return d
+}
+
+// ForOrder computes the deadlines of an order
+func ForOrder(o *models.Order, now time.Time) Order {
+	var d Order
+
+	if o.ExpiresAt == nil {
+		return d
+	}
+	if o.Status != models.OrderStatusOpen && o.Status != models.OrderStatusPartial {
+		return d
+	}
+
+	expiresIn := SecondsUntil(*o.ExpiresAt, now)
+	d.ExpiresInSeconds = &expiresIn
+
+	return d
+}
+
+// EstimateHeightTime estimates when the chain reaches height given the current tip.
+// Heights at or below the tip map to now.
+func EstimateHeightTime(height, tipHeight int64, now time.Time) time.Time {
+	if height <= tipHeight {
+		return now
+	}
+	return now.Add(time.Duration(height-tipHeight) * BlockInterval)
+}
+
+// SecondsUntil returns the whole seconds from now until t, or 0 if t has passed
+func SecondsUntil(t, now time.Time) int64 {
+	if !t.After(now) {
+		return 0
+	}
+	return int64(t.Sub(now) / time.Second)
+}
@@ -0,0 +1,95 @@
+package deadlines
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestForContract(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		contract        *models.Contract
+		tipHeight       int64
+		wantSettleAt    time.Time
+		wantExitHeight  *int64
+		wantExpiresIn   *int64
+		wantNoDeadlines bool
+	}{
+		{
+			name: "Active contract settles at end height before target timestamp",
+			contract: &models.Contract{
+				Status:           models.ContractStatusActive,
+				StartBlockHeight: 1000,
+				EndBlockHeight:   1006,
+				TargetTimestamp:  now.Add(24 * time.Hour),
+				ExpiresAt:        now.Add(48 * time.Hour),
+			},
+			tipHeight:      1000,
+			wantSettleAt:   now.Add(time.Hour),
+			wantExitHeight: int64Ptr(1000 + ExitTimelockBlocks),
+			wantExpiresIn:  int64Ptr(48 * 3600),
+		},
+		{
+			name: "Created contract settles at target timestamp before end height",
+			contract: &models.Contract{
+				Status:           models.ContractStatusCreated,
+				StartBlockHeight: 1000,
+				EndBlockHeight:   2000,
+				TargetTimestamp:  now.Add(time.Hour),
+				ExpiresAt:        now.Add(-time.Hour),
+			},
+			tipHeight:     1000,
+			wantSettleAt:  now.Add(time.Hour),
+			wantExpiresIn: int64Ptr(0),
+		},
+		{
+			name: "Settled contract has no deadlines",
+			contract: &models.Contract{
+				Status:         models.ContractStatusSettled,
+				EndBlockHeight: 1006,
+			},
+			tipHeight:       1000,
+			wantNoDeadlines: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := ForContract(tc.contract, tc.tipHeight, now)
+
+			if tc.wantNoDeadlines {
+				assert.Equal(t, Contract{}, d)
+				return
+			}
+
+			assert.Equal(t, tc.contract.EndBlockHeight, *d.SettleableAtHeight)
+			assert.Equal(t, tc.wantSettleAt, *d.SettleableAt)
+			assert.Equal(t, tc.wantExitHeight, d.ExitAvailableAtHeight)
+			assert.Equal(t, tc.wantExpiresIn, d.ExpiresInSeconds)
+		})
+	}
+}
+
+func TestForOrder(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(90 * time.Second)
+
+	open := ForOrder(&models.Order{Status: models.OrderStatusOpen, ExpiresAt: &expiresAt}, now)
+	assert.Equal(t, int64Ptr(90), open.ExpiresInSeconds)
+
+	filled := ForOrder(&models.Order{Status: models.OrderStatusFilled, ExpiresAt: &expiresAt}, now)
+	assert.Nil(t, filled.ExpiresInSeconds)
+
+	noExpiry := ForOrder(&models.Order{Status: models.OrderStatusOpen}, now)
+	assert.Nil(t, noExpiry.ExpiresInSeconds)
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
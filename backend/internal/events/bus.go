@@ -0,0 +1,124 @@
+// internal/events/bus.go
+package events
+
+import (
+	"sync"
+	"time"
+
+	"hashhedge/internal/metrics"
+)
+
+// Topic names a stream of events on the bus
+type Topic string
+
+const (
+	// TopicTrades carries a TradeEvent for every executed trade
+	TopicTrades Topic = "trades"
+	// TopicOrders carries an OrderEvent when an order is placed, amended
+	// or cancelled
+	TopicOrders Topic = "orders"
+	// TopicContracts carries the ContractEvents of every contract
+	TopicContracts Topic = "contracts"
+	// TopicHashRate carries a models.HashRateObservation for each newly
+	// sampled block
+	TopicHashRate Topic = "hashrate"
+	// TopicASPStatus carries an ASPStatusEvent when the ASP stops or
+	// starts responding
+	TopicASPStatus Topic = "asp_status"
+)
+
+// queueSize is how many events a subscriber may fall behind by before
+// further events to it are dropped
+const queueSize = 256
+
+// Event is a payload published on a topic
+type Event struct {
+	Topic   Topic       `json:"topic"`
+	Time    time.Time   `json:"time"`
+	Payload interface{} `json:"payload"`
+}
+
+// Handler receives the events of a topic, one at a time and in the order
+// they were published
+type Handler func(event Event)
+
+// subscription delivers the events queued for one handler
+type subscription struct {
+	queue   chan Event
+	handler Handler
+}
+
+// Bus fans events out to in-process subscribers. Each subscriber has its
+// own queue drained by its own goroutine, so publishers never wait on a
+// slow consumer; events to a subscriber whose queue is full are dropped.
+type Bus struct {
+	mu     sync.RWMutex
+	topics map[Topic][]*subscription
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewBus creates an event bus without subscribers
+func NewBus() *Bus {
+	return &Bus{topics: make(map[Topic][]*subscription)}
+}
+
+// Subscribe calls handler with every event published on topic from now on
+func (b *Bus) Subscribe(topic Topic, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	sub := &subscription{queue: make(chan Event, queueSize), handler: handler}
+	b.topics[topic] = append(b.topics[topic], sub)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for event := range sub.queue {
+			sub.handler(event)
+		}
+	}()
+}
+
+// Publish queues payload for every subscriber of topic without blocking
+func (b *Bus) Publish(topic Topic, payload interface{}) {
+	event := Event{Topic: topic, Time: time.Now().UTC(), Payload: payload}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.topics[topic] {
+		select {
+		case sub.queue <- event:
+		default:
+			metrics.EventsDropped.WithLabelValues(string(topic)).Inc()
+		}
+	}
+}
+
+// Close stops accepting events and waits for subscribers to handle the
+// events already queued
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, subs := range b.topics {
+		for _, sub := range subs {
+			close(sub.queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
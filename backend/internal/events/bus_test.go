@@ -0,0 +1,62 @@
+// internal/events/bus_test.go
+package events
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusPublish(t *testing.T) {
+	bus := NewBus()
+
+	// Publishing without subscribers is a no-op
+	bus.Publish(TopicTrades, TradeEvent{})
+
+	var trades, contracts []Event
+	bus.Subscribe(TopicTrades, func(event Event) { trades = append(trades, event) })
+	bus.Subscribe(TopicContracts, func(event Event) { contracts = append(contracts, event) })
+
+	first, second := uuid.New(), uuid.New()
+	bus.Publish(TopicTrades, TradeEvent{ID: first})
+	bus.Publish(TopicTrades, TradeEvent{ID: second})
+	bus.Close()
+
+	// Each subscriber only sees its topic, in publication order
+	require.Len(t, trades, 2)
+	assert.Empty(t, contracts)
+	assert.Equal(t, TopicTrades, trades[0].Topic)
+	assert.Equal(t, first, trades[0].Payload.(TradeEvent).ID)
+	assert.Equal(t, second, trades[1].Payload.(TradeEvent).ID)
+	assert.False(t, trades[0].Time.IsZero())
+
+	// A closed bus drops events and subscriptions
+	bus.Publish(TopicTrades, TradeEvent{})
+	bus.Subscribe(TopicTrades, func(Event) { t.Fatal("subscribed to a closed bus") })
+	bus.Close()
+	assert.Len(t, trades, 2)
+}
+
+func TestBusDropsForSlowSubscriber(t *testing.T) {
+	bus := NewBus()
+
+	release := make(chan struct{})
+	handled := 0
+	bus.Subscribe(TopicOrders, func(Event) {
+		<-release
+		handled++
+	})
+
+	// One event is taken by the blocked handler and queueSize more are
+	// queued; the rest are dropped rather than blocking the publisher
+	for i := 0; i < queueSize+10; i++ {
+		bus.Publish(TopicOrders, OrderEvent{Action: OrderPlaced})
+	}
+	close(release)
+	bus.Close()
+
+	assert.LessOrEqual(t, handled, queueSize+1)
+	assert.GreaterOrEqual(t, handled, queueSize)
+}
@@ -0,0 +1,81 @@
+// internal/events/types.go
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// TradeEvent is a trade executed by the order book
+type TradeEvent struct {
+	ID             uuid.UUID           `json:"id"`
+	ContractID     uuid.UUID           `json:"contract_id"`
+	ContractType   models.ContractType `json:"contract_type"`
+	StrikeHashRate float64             `json:"strike_hash_rate"`
+	Price          int64               `json:"price"`
+	Quantity       int                 `json:"quantity"`
+	ExecutedAt     time.Time           `json:"executed_at"`
+}
+
+// OrderAction is what happened to an order
+type OrderAction string
+
+const (
+	OrderPlaced    OrderAction = "placed"
+	OrderAmended   OrderAction = "amended"
+	OrderCancelled OrderAction = "cancelled"
+	OrderExpired   OrderAction = "expired"
+)
+
+// OrderEvent is a change to an order, with the order as it was right after
+type OrderEvent struct {
+	Action OrderAction  `json:"action"`
+	Order  models.Order `json:"order"`
+}
+
+// ContractEventType is the kind of a contract lifecycle event
+type ContractEventType string
+
+const (
+	// ContractStatusChanged is published when a contract moves to a new status
+	ContractStatusChanged ContractEventType = "status_changed"
+	// ContractTransactionConfirmed is published when a contract transaction
+	// reaches the required depth on chain
+	ContractTransactionConfirmed ContractEventType = "transaction_confirmed"
+	// ContractSettled is published with the outcome of a settled contract
+	ContractSettled ContractEventType = "settled"
+	// ContractTransactionRejected is published when a contract transaction
+	// fails its checks and is not broadcast
+	ContractTransactionRejected ContractEventType = "transaction_rejected"
+)
+
+// ContractEvent is a change in the lifecycle of a contract
+type ContractEvent struct {
+	Type       ContractEventType     `json:"type"`
+	ContractID uuid.UUID             `json:"contract_id"`
+	From       models.ContractStatus `json:"from,omitempty"`
+	To         models.ContractStatus `json:"to,omitempty"`
+	// TransactionID and TxType name the transaction of a confirmation or
+	// settlement
+	TransactionID string `json:"transaction_id,omitempty"`
+	TxType        string `json:"tx_type,omitempty"`
+	Confirmations int64  `json:"confirmations,omitempty"`
+	// BuyerWins is the outcome of a settlement; it is absent when the
+	// parties closed the contract cooperatively
+	BuyerWins *bool `json:"buyer_wins,omitempty"`
+	// Reason is the check a rejected transaction failed
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// ASPStatusEvent is a change in the availability of the ASP
+type ASPStatusEvent struct {
+	Available           bool `json:"available"`
+	ConsecutiveFailures int  `json:"consecutive_failures"`
+	// OnChainOnly is set once the dead man's switch has tripped and
+	// contracts no longer use the ASP
+	OnChainOnly bool `json:"on_chain_only"`
+}
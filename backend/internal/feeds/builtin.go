@@ -0,0 +1,222 @@
+// internal/feeds/builtin.go
+package feeds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+// Built-in feed kinds
+const (
+	KindMempoolFees = "mempool_fees"
+	KindHTTPJSON    = "http_json"
+	KindBlockSpeed  = "block_speed"
+)
+
+func init() {
+	Register(KindMempoolFees, newMempoolFeeFeed)
+	Register(KindHTTPJSON, newHTTPJSONFeed)
+	Register(KindBlockSpeed, newBlockSpeedFeed)
+}
+
+// mempoolFeeFeed samples the node's fee rate estimates for a set of confirmation targets
+type mempoolFeeFeed struct {
+	client  *bitcoin.Client
+	targets []int64
+}
+
+// newMempoolFeeFeed accepts a comma separated "targets" option, defaulting to 1,6,144 blocks
+func newMempoolFeeFeed(options map[string]string, deps Deps) (Feed, error) {
+	if deps.Bitcoin == nil {
+		return nil, errors.New("mempool fee feed requires a Bitcoin client")
+	}
+
+	targets := []int64{1, 6, 144}
+	if raw := options["targets"]; raw != "" {
+		targets = targets[:0]
+		for _, part := range strings.Split(raw, ",") {
+			target, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil || target <= 0 {
+				return nil, fmt.Errorf("invalid confirmation target: %s", part)
+			}
+			targets = append(targets, target)
+		}
+	}
+
+	return &mempoolFeeFeed{client: deps.Bitcoin, targets: targets}, nil
+}
+
+// Sample returns one series per confirmation target in sat/vB
+func (f *mempoolFeeFeed) Sample(ctx context.Context) ([]Observation, error) {
+	observations := make([]Observation, 0, len(f.targets))
+	for _, target := range f.targets {
+		rate, err := f.client.EstimateSmartFee(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, Observation{
+			Series: fmt.Sprintf("target_%d", target),
+			Value:  rate,
+		})
+	}
+	return observations, nil
+}
+
+// httpJSONFeed polls a URL returning a flat JSON object of numbers, e.g. a
+// mining pool distribution of {"Foundry USA": 0.31, "AntPool": 0.24}. Each
+// key becomes a series.
+type httpJSONFeed struct {
+	url    string
+	field  string
+	client *http.Client
+}
+
+// newHTTPJSONFeed requires a "url" option. An optional "field" selects a
+// nested object of the response to read the values from.
+func newHTTPJSONFeed(options map[string]string, deps Deps) (Feed, error) {
+	url := options["url"]
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return nil, errors.New("http_json feed requires an http(s) url option")
+	}
+
+	timeout := 10 * time.Second
+	if raw := options["timeout"]; raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout: %s", raw)
+		}
+		timeout = d
+	}
+
+	return &httpJSONFeed{
+		url:    url,
+		field:  options["field"],
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Sample fetches the URL and returns one series per numeric key
+func (f *httpJSONFeed) Sample(ctx context.Context) ([]Observation, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode feed response: %w", err)
+	}
+
+	if f.field != "" {
+		nested, ok := body[f.field]
+		if !ok {
+			return nil, fmt.Errorf("feed response has no field %s", f.field)
+		}
+		body = nil
+		if err := json.Unmarshal(nested, &body); err != nil {
+			return nil, fmt.Errorf("feed field %s is not an object: %w", f.field, err)
+		}
+	}
+
+	observations := make([]Observation, 0, len(body))
+	for key, raw := range body {
+		var value float64
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// Skip metadata such as timestamps or labels
+			continue
+		}
+		observations = append(observations, Observation{Series: key, Value: value})
+	}
+
+	return observations, nil
+}
+
+// Block speed series, used by the hash rate volatility analytics
+const (
+	SeriesHashRate      = "hash_rate"
+	SeriesBlockSpeed    = "block_speed"
+	SeriesBlockInterval = "block_interval"
+)
+
+// blockSpeedFeed measures block production over the trailing window of blocks
+type blockSpeedFeed struct {
+	client *bitcoin.Client
+	window int64
+}
+
+// newBlockSpeedFeed accepts a "window" option in blocks, defaulting to 144
+func newBlockSpeedFeed(options map[string]string, deps Deps) (Feed, error) {
+	if deps.Bitcoin == nil {
+		return nil, errors.New("block speed feed requires a Bitcoin client")
+	}
+
+	window := int64(144)
+	if raw := options["window"]; raw != "" {
+		w, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid window: %s", raw)
+		}
+		window = w
+	}
+
+	return &blockSpeedFeed{client: deps.Bitcoin, window: window}, nil
+}
+
+// Sample returns the implied hash rate in EH/s, the block speed relative to
+// the 10 minute target and the mean block interval in seconds
+func (f *blockSpeedFeed) Sample(ctx context.Context) ([]Observation, error) {
+	tip, err := f.client.GetBlockCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tip <= f.window {
+		return nil, fmt.Errorf("chain height %d is below the window of %d blocks", tip, f.window)
+	}
+
+	last, err := f.blockAt(ctx, tip)
+	if err != nil {
+		return nil, err
+	}
+	first, err := f.blockAt(ctx, tip-f.window)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := last.Time.Sub(first.Time).Seconds() / float64(f.window)
+	if interval <= 0 {
+		return nil, fmt.Errorf("non-increasing block times between heights %d and %d", tip-f.window, tip)
+	}
+
+	return []Observation{
+		{Series: SeriesHashRate, Value: last.Difficulty * math.Pow(2, 32) / (interval * 1e18)},
+		{Series: SeriesBlockSpeed, Value: 600 / interval},
+		{Series: SeriesBlockInterval, Value: interval},
+	}, nil
+}
+
+func (f *blockSpeedFeed) blockAt(ctx context.Context, height int64) (*bitcoin.Block, error) {
+	hash, err := f.client.GetBlockHash(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	return f.client.GetBlock(ctx, hash)
+}
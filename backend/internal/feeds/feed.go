@@ -0,0 +1,124 @@
+// internal/feeds/feed.go
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+// Observation is a single value produced by a feed. A feed may produce
+// several series per sample, e.g. one per fee target or mining pool.
+type Observation struct {
+	Series string
+	Value  float64
+}
+
+// Feed is an external data source sampled on a fixed interval
+type Feed interface {
+	// Sample fetches the current values of the feed
+	Sample(ctx context.Context) ([]Observation, error)
+}
+
+// Deps holds the shared clients available to feed plugins
+type Deps struct {
+	Bitcoin *bitcoin.Client
+}
+
+// Factory builds a feed from its configured options
+type Factory func(options map[string]string, deps Deps) (Feed, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a feed kind available to the configuration. It panics if
+// the kind is registered twice.
+func Register(kind string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[kind]; exists {
+		panic(fmt.Sprintf("feeds: kind %s registered twice", kind))
+	}
+	registry[kind] = factory
+}
+
+// IsRegistered reports whether a feed kind is available
+func IsRegistered(kind string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	_, ok := registry[kind]
+	return ok
+}
+
+// Kinds returns the registered feed kinds in sorted order
+func Kinds() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// FeedConfig configures a single feed instance
+type FeedConfig struct {
+	Name     string            `yaml:"name" json:"name"`
+	Kind     string            `yaml:"kind" json:"kind"`
+	Interval time.Duration     `yaml:"interval" json:"interval"`
+	Options  map[string]string `yaml:"options" json:"-"`
+}
+
+// Config holds the data feed configuration
+type Config struct {
+	Feeds []FeedConfig `yaml:"feeds"`
+}
+
+// Validate checks that every feed has a unique name, a registered kind and a positive interval
+func (c Config) Validate() error {
+	seen := make(map[string]bool, len(c.Feeds))
+	for _, feed := range c.Feeds {
+		if feed.Name == "" {
+			return fmt.Errorf("feed name cannot be empty")
+		}
+		if seen[feed.Name] {
+			return fmt.Errorf("duplicate feed name: %s", feed.Name)
+		}
+		seen[feed.Name] = true
+
+		if !IsRegistered(feed.Kind) {
+			return fmt.Errorf("unknown kind %q for feed %s", feed.Kind, feed.Name)
+		}
+		if feed.Interval <= 0 {
+			return fmt.Errorf("interval for feed %s must be positive", feed.Name)
+		}
+	}
+	return nil
+}
+
+// build instantiates a configured feed from the registry
+func build(cfg FeedConfig, deps Deps) (Feed, error) {
+	registryMu.RLock()
+	factory, ok := registry[cfg.Kind]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown feed kind: %s", cfg.Kind)
+	}
+
+	feed, err := factory(cfg.Options, deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feed %s: %w", cfg.Name, err)
+	}
+	return feed, nil
+}
@@ -0,0 +1,99 @@
+// internal/feeds/sampler.go
+package feeds
+
+import (
+	"context"
+	"time"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Feeds)
+
+// Sampler polls every configured feed on its own interval and persists the observations
+type Sampler struct {
+	repo    *db.FeedRepository
+	configs []FeedConfig
+	feeds   []Feed
+}
+
+// NewSampler builds the configured feeds
+func NewSampler(repo *db.FeedRepository, cfg Config, deps Deps) (*Sampler, error) {
+	s := &Sampler{repo: repo}
+
+	for _, feedCfg := range cfg.Feeds {
+		feed, err := build(feedCfg, deps)
+		if err != nil {
+			return nil, err
+		}
+		s.configs = append(s.configs, feedCfg)
+		s.feeds = append(s.feeds, feed)
+	}
+
+	return s, nil
+}
+
+// Feeds returns the configuration of the running feeds
+func (s *Sampler) Feeds() []FeedConfig {
+	return s.configs
+}
+
+// HasFeed reports whether a feed with the given name is configured
+func (s *Sampler) HasFeed(name string) bool {
+	for _, cfg := range s.configs {
+		if cfg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Start begins sampling each feed in its own goroutine
+func (s *Sampler) Start(ctx context.Context) {
+	for i := range s.feeds {
+		cfg, feed := s.configs[i], s.feeds[i]
+		go func() {
+			ticker := time.NewTicker(cfg.Interval)
+			defer ticker.Stop()
+
+			s.sample(ctx, cfg.Name, feed)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					s.sample(ctx, cfg.Name, feed)
+				}
+			}
+		}()
+	}
+}
+
+// sample takes one sample from a feed and stores it
+func (s *Sampler) sample(ctx context.Context, name string, feed Feed) {
+	values, err := feed.Sample(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Str("feed", name).Msg("Failed to sample feed")
+		return
+	}
+
+	now := time.Now().UTC()
+	observations := make([]*models.FeedObservation, 0, len(values))
+	for _, v := range values {
+		observations = append(observations, &models.FeedObservation{
+			Feed:       name,
+			Series:     v.Series,
+			Value:      v.Value,
+			ObservedAt: now,
+		})
+	}
+
+	if err := s.repo.InsertObservations(ctx, observations); err != nil {
+		logger.Error().Err(err).Str("feed", name).Msg("Failed to store feed observations")
+		return
+	}
+
+	logger.Debug().Str("feed", name).Int("observations", len(observations)).Msg("Feed sampled")
+}
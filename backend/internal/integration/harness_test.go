@@ -0,0 +1,405 @@
+//go:build integration
+
+// internal/integration/harness_test.go
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	arkv1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/taproot"
+)
+
+// harness wires the real services against a regtest bitcoind and a Postgres
+// database, with an in-process stub standing in for the ASP. The node must
+// have a loaded wallet, which funds the stub's rounds and receives the
+// coinbase of mined blocks.
+type harness struct {
+	t            *testing.T
+	database     *db.DB
+	rpc          *rpcclient.Client
+	bitcoin      *bitcoin.Client
+	asp          *stubASP
+	scripts      *taproot.ScriptBuilder
+	contractRepo *db.ContractRepository
+	userRepo     *db.UserRepository
+	contractSvc  *contract.Service
+	orderBook    *orderbook.OrderBook
+}
+
+// newHarness builds a harness from HASHHEDGE_IT_* environment variables and
+// skips the test when they are not set.
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+
+	rpcHost := os.Getenv("HASHHEDGE_IT_BITCOIN_HOST")
+	dbHost := os.Getenv("HASHHEDGE_IT_DB_HOST")
+	if rpcHost == "" || dbHost == "" {
+		t.Skip("HASHHEDGE_IT_BITCOIN_HOST and HASHHEDGE_IT_DB_HOST must be set to run integration tests")
+	}
+
+	dbPort := 5432
+	if p := os.Getenv("HASHHEDGE_IT_DB_PORT"); p != "" {
+		if port, err := strconv.Atoi(p); err == nil {
+			dbPort = port
+		}
+	}
+
+	database, err := db.New(db.Config{
+		Host:     dbHost,
+		Port:     dbPort,
+		User:     envOr("HASHHEDGE_IT_DB_USER", "hashhedge"),
+		Password: envOr("HASHHEDGE_IT_DB_PASSWORD", "hashhedge"),
+		DBName:   envOr("HASHHEDGE_IT_DB_NAME", "hashhedge_test"),
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	rpcUser := envOr("HASHHEDGE_IT_BITCOIN_USER", "bitcoinrpc")
+	rpcPass := envOr("HASHHEDGE_IT_BITCOIN_PASSWORD", "rpcpassword")
+
+	// Raw RPC client for regtest-only calls (mining) that bitcoin.Client does not expose
+	rpc, err := rpcclient.New(&rpcclient.ConnConfig{
+		Host:         rpcHost,
+		User:         rpcUser,
+		Pass:         rpcPass,
+		HTTPPostMode: true,
+		DisableTLS:   true,
+		Params:       chaincfg.RegressionNetParams.Name,
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create regtest RPC client: %v", err)
+	}
+
+	bitcoinClient, err := bitcoin.NewClient(rpcHost, rpcUser, rpcPass, false)
+	if err != nil {
+		t.Fatalf("failed to create bitcoin client: %v", err)
+	}
+
+	contractRepo := db.NewContractRepository(database)
+	orderRepo := db.NewOrderRepository(database)
+	tradeRepo := db.NewTradeRepository(database)
+	userRepo := db.NewUserRepository(database)
+
+	// Payouts are SETTLED only once the confirmation watcher sees them confirm
+	scripts := taproot.NewScriptBuilder().WithNetwork(&chaincfg.RegressionNetParams)
+	asp := newStubASP(rpc)
+	contractSvc := contract.NewService(
+		contractRepo,
+		hashrate.New(bitcoinClient),
+		bitcoinClient,
+		scripts,
+		asp,
+	)
+	contractSvc.WithSettlementTracking(db.NewSettlementBroadcastRepository(database), contract.DefaultSettlementTrackingConfig)
+	contractSvc.WithConfirmationWatcher(contractRepo, bitcoinClient, contract.ConfirmationConfig{
+		Interval: 100 * time.Millisecond,
+		Depth:    1,
+	})
+
+	h := &harness{
+		t:            t,
+		database:     database,
+		rpc:          rpc,
+		bitcoin:      bitcoinClient,
+		asp:          asp,
+		scripts:      scripts,
+		contractRepo: contractRepo,
+		userRepo:     userRepo,
+		contractSvc:  contractSvc,
+		orderBook:    orderbook.NewOrderBook(database, orderRepo, tradeRepo, contractRepo, contractSvc),
+	}
+
+	t.Cleanup(func() {
+		rpc.Shutdown()
+		bitcoinClient.Close()
+		database.Close()
+	})
+
+	h.ensureFunds()
+	return h
+}
+
+// ensureFunds mines mature coinbase outputs to the wallet when it cannot
+// fund a round
+func (h *harness) ensureFunds() {
+	h.t.Helper()
+
+	balance, err := h.rpc.GetBalance("*")
+	if err != nil {
+		h.t.Fatalf("failed to get wallet balance: %v", err)
+	}
+	if balance < btcutil.SatoshiPerBitcoin {
+		// Coinbase outputs mature after 100 blocks
+		h.mineBlocks(101)
+	}
+}
+
+// tipHeight returns the current regtest chain height
+func (h *harness) tipHeight(ctx context.Context) int64 {
+	h.t.Helper()
+
+	height, err := h.bitcoin.GetBlockCount(ctx)
+	if err != nil {
+		h.t.Fatalf("failed to get block count: %v", err)
+	}
+	return height
+}
+
+// mineBlocks mines n regtest blocks to a fresh wallet address
+func (h *harness) mineBlocks(n int64) {
+	h.t.Helper()
+
+	addr, err := h.rpc.GetNewAddress("")
+	if err != nil {
+		h.t.Fatalf("failed to get mining address: %v", err)
+	}
+
+	if _, err := h.rpc.GenerateToAddress(n, addr, nil); err != nil {
+		h.t.Fatalf("failed to mine %d blocks: %v", n, err)
+	}
+}
+
+// createUser inserts a throwaway user so orders satisfy the users foreign key
+func (h *harness) createUser(ctx context.Context) *models.User {
+	h.t.Helper()
+
+	suffix := uuid.New().String()[:8]
+	user := &models.User{
+		Username:     "it_" + suffix,
+		PasswordHash: "unused",
+		Email:        "it_" + suffix + "@example.com",
+	}
+	if err := h.userRepo.Create(ctx, user); err != nil {
+		h.t.Fatalf("failed to create user: %v", err)
+	}
+	return user
+}
+
+// recordRoundTransaction stores the transaction of the stub ASP's round
+// that created a contract's setup output in its setup record. The backend
+// saves the round ID with an empty transaction until the round is
+// processed, which the stub's rounds never are, so this stands in for it.
+func (h *harness) recordRoundTransaction(ctx context.Context, c *models.Contract) *wire.MsgTx {
+	h.t.Helper()
+
+	if c.SetupTxID == nil {
+		h.t.Fatalf("contract %s has no setup transaction", c.ID)
+	}
+	tx := h.asp.round(*c.SetupTxID)
+	if tx == nil {
+		h.t.Fatalf("stub ASP has no round %s", *c.SetupTxID)
+	}
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		h.t.Fatalf("failed to serialize round transaction: %v", err)
+	}
+
+	_, err := h.database.ExecContext(ctx, `
+		UPDATE contract_transactions
+		SET tx_hex = $1
+		WHERE contract_id = $2 AND tx_type = 'setup' AND transaction_id = $3
+	`, hex.EncodeToString(buf.Bytes()), c.ID, *c.SetupTxID)
+	if err != nil {
+		h.t.Fatalf("failed to record round transaction: %v", err)
+	}
+
+	return tx
+}
+
+// transaction returns the stored contract transaction of a type and txid
+func (h *harness) transaction(ctx context.Context, contractID uuid.UUID, txType, txid string) *models.ContractTransaction {
+	h.t.Helper()
+
+	txs, err := h.contractRepo.GetTransactionsByContractID(ctx, contractID)
+	if err != nil {
+		h.t.Fatalf("failed to get contract transactions: %v", err)
+	}
+	for _, tx := range txs {
+		if tx.TxType == txType && tx.TransactionID == txid {
+			return tx
+		}
+	}
+	h.t.Fatalf("%s transaction %s not found", txType, txid)
+	return nil
+}
+
+// sign signs every input of a contract transaction through the script path
+// in its spend PSBT, as the holder of key would
+func (h *harness) sign(tx *models.ContractTransaction, key *btcec.PrivateKey) *wire.MsgTx {
+	h.t.Helper()
+
+	if tx.SpendPSBT == nil {
+		h.t.Fatalf("%s transaction %s has no spend PSBT", tx.TxType, tx.TransactionID)
+	}
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(*tx.SpendPSBT), true)
+	if err != nil {
+		h.t.Fatalf("failed to decode spend PSBT: %v", err)
+	}
+
+	msgTx := packet.UnsignedTx
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for i, in := range msgTx.TxIn {
+		prevOuts.AddPrevOut(in.PreviousOutPoint, packet.Inputs[i].WitnessUtxo)
+	}
+	sigHashes := txscript.NewTxSigHashes(msgTx, prevOuts)
+
+	for i, in := range packet.Inputs {
+		leaf := in.TaprootLeafScript[0]
+		sigHash, err := txscript.CalcTapscriptSignaturehash(sigHashes, txscript.SigHashDefault,
+			msgTx, i, prevOuts, txscript.NewBaseTapLeaf(leaf.Script))
+		if err != nil {
+			h.t.Fatalf("failed to compute signature hash of input %d: %v", i, err)
+		}
+		sig, err := schnorr.Sign(key, sigHash)
+		if err != nil {
+			h.t.Fatalf("failed to sign input %d: %v", i, err)
+		}
+		msgTx.TxIn[i].Witness = wire.TxWitness{sig.Serialize(), leaf.Script, leaf.ControlBlock}
+	}
+
+	return msgTx
+}
+
+// broadcast sends a signed transaction to the node's mempool
+func (h *harness) broadcast(tx *wire.MsgTx) {
+	h.t.Helper()
+
+	if _, err := h.rpc.SendRawTransaction(tx, false); err != nil {
+		h.t.Fatalf("failed to broadcast transaction %s: %v", tx.TxHash(), err)
+	}
+}
+
+// confirmations returns how many blocks deep a transaction is, zero while
+// it is in the mempool
+func (h *harness) confirmations(txid string) uint64 {
+	h.t.Helper()
+
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		h.t.Fatalf("invalid transaction ID %s: %v", txid, err)
+	}
+	tx, err := h.rpc.GetRawTransactionVerbose(hash)
+	if err != nil {
+		h.t.Fatalf("failed to get transaction %s: %v", txid, err)
+	}
+	return tx.Confirmations
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// stubASP is an in-process contract.ArkService that accepts every round
+// registration. Each round is a regtest transaction paying the registered
+// outputs in order, funded and broadcast by the node's wallet, and its
+// txid is the round ID.
+type stubASP struct {
+	mu        sync.Mutex
+	rpc       *rpcclient.Client
+	available bool
+	outputs   []*arkv1.Output
+	rounds    map[string]*wire.MsgTx
+}
+
+func newStubASP(rpc *rpcclient.Client) *stubASP {
+	return &stubASP{rpc: rpc, available: true, rounds: make(map[string]*wire.MsgTx)}
+}
+
+// round returns the transaction of a round, or nil if there is none
+func (s *stubASP) round(roundID string) *wire.MsgTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rounds[roundID]
+}
+
+func (s *stubASP) CheckASPStatus(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.available {
+		return false, fmt.Errorf("stub ASP unavailable")
+	}
+	return true, nil
+}
+
+func (s *stubASP) RegisterOutputsForNextRound(ctx context.Context, outputs []*arkv1.Output) (*arkv1.RegisterOutputsForNextRoundResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := wire.NewMsgTx(2)
+	for _, output := range outputs {
+		addr, err := btcutil.DecodeAddress(output.GetAddress(), &chaincfg.RegressionNetParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid output address: %w", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build output script: %w", err)
+		}
+		tx.AddTxOut(wire.NewTxOut(output.GetValue(), pkScript))
+	}
+
+	// Change goes last, so the registered outputs keep their indexes
+	changePosition := len(tx.TxOut)
+	isWitness := false
+	funded, err := s.rpc.FundRawTransaction(tx, btcjson.FundRawTransactionOpts{ChangePosition: &changePosition}, &isWitness)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fund round: %w", err)
+	}
+	signed, complete, err := s.rpc.SignRawTransactionWithWallet(funded.Transaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign round: %w", err)
+	}
+	if !complete {
+		return nil, fmt.Errorf("wallet could not sign every round input")
+	}
+	txid, err := s.rpc.SendRawTransaction(signed, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast round: %w", err)
+	}
+
+	s.outputs = append(s.outputs, outputs...)
+	s.rounds[txid.String()] = signed
+	return &arkv1.RegisterOutputsForNextRoundResponse{RoundId: txid.String()}, nil
+}
+
+func (s *stubASP) CreateOutOfRoundTransaction(ctx context.Context, senderPSBT string, outputs []*arkv1.Output) (*arkv1.CreateOutOfRoundTransactionResponse, error) {
+	return &arkv1.CreateOutOfRoundTransactionResponse{TxId: uuid.New().String(), SerializedPsbt: senderPSBT}, nil
+}
+
+func (s *stubASP) GetExitPath(ctx context.Context, vtxoID string, destinationAddress string, feeRate int64) (*arkv1.GetExitPathResponse, error) {
+	return &arkv1.GetExitPathResponse{Txid: uuid.New().String()}, nil
+}
@@ -0,0 +1,211 @@
+//go:build integration
+
+// internal/integration/lifecycle_test.go
+package integration
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+// TestContractLifecycle drives a contract from order placement to settlement
+// for both contract types. The setup, final and settlement transactions are
+// broadcast to regtest and confirmed, and the contract is SETTLED only once
+// the confirmation watcher sees its payout confirm.
+func TestContractLifecycle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		contractType   models.ContractType
+		expectBuyerWin bool
+	}{
+		{
+			name:           "CALL settles to buyer when end height is reached",
+			contractType:   models.ContractTypeCall,
+			expectBuyerWin: true,
+		},
+		{
+			name:           "PUT settles to seller when end height is reached",
+			contractType:   models.ContractTypePut,
+			expectBuyerWin: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t)
+			ctx := context.Background()
+
+			buyer := h.createUser(ctx)
+			seller := h.createUser(ctx)
+			buyerKey, buyerPubKey := newKey(t)
+			sellerKey, sellerPubKey := newKey(t)
+
+			tip := h.tipHeight(ctx)
+			startHeight := tip + 1
+			endHeight := tip + 6
+
+			// Create: a resting sell order
+			sellOrder, err := h.orderBook.PlaceOrder(ctx, &models.Order{
+				UserID:           seller.ID,
+				Side:             models.OrderSideSell,
+				ContractType:     tc.contractType,
+				StrikeHashRate:   350.0,
+				StartBlockHeight: startHeight,
+				EndBlockHeight:   endHeight,
+				Price:            100000,
+				Quantity:         1,
+				PubKey:           sellerPubKey,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, models.OrderStatusOpen, sellOrder.Status)
+
+			// Match: a crossing buy order creates the contract
+			buyOrder, err := h.orderBook.PlaceOrder(ctx, &models.Order{
+				UserID:           buyer.ID,
+				Side:             models.OrderSideBuy,
+				ContractType:     tc.contractType,
+				StrikeHashRate:   350.0,
+				StartBlockHeight: startHeight,
+				EndBlockHeight:   endHeight,
+				Price:            100000,
+				Quantity:         1,
+				PubKey:           buyerPubKey,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, models.OrderStatusFilled, buyOrder.Status)
+
+			var contractID uuid.UUID
+			err = h.database.GetContext(ctx, &contractID, `SELECT contract_id FROM trades WHERE buy_order_id = $1`, buyOrder.ID)
+			require.NoError(t, err)
+
+			c, err := h.contractSvc.GetContract(ctx, contractID)
+			require.NoError(t, err)
+			assert.Equal(t, models.ContractStatusCreated, c.Status)
+			assert.Equal(t, buyerPubKey, c.BuyerPubKey)
+			assert.Equal(t, sellerPubKey, c.SellerPubKey)
+
+			// Setup: registered with the stub ASP, whose round pays the
+			// setup output on chain
+			_, err = h.contractSvc.GenerateSetupTransaction(ctx, contractID, c.ContractSize)
+			require.NoError(t, err)
+			require.NotEmpty(t, h.asp.outputs)
+			setupOutput := h.asp.outputs[len(h.asp.outputs)-1]
+			assert.Equal(t, c.ContractSize, setupOutput.Value)
+
+			c, err = h.contractSvc.GetContract(ctx, contractID)
+			require.NoError(t, err)
+			assert.Equal(t, models.ContractStatusActive, c.Status)
+
+			setupTx := h.recordRoundTransaction(ctx, c)
+			assert.Equal(t, addressScript(t, setupOutput.Address), setupTx.TxOut[0].PkScript)
+			assert.Equal(t, c.ContractSize, setupTx.TxOut[0].Value)
+
+			// Advance the chain to the end height, confirming the setup
+			h.mineBlocks(endHeight - h.tipHeight(ctx))
+			assert.GreaterOrEqual(t, h.confirmations(setupTx.TxHash().String()), uint64(1))
+
+			canSettle, reason, err := h.contractSvc.CheckSettlementConditions(ctx, contractID)
+			require.NoError(t, err)
+			require.True(t, canSettle, reason)
+
+			// Settle: the payout waits for its confirmation
+			settlementTx, buyerWins, err := h.contractSvc.SettleContract(ctx, contractID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectBuyerWin, buyerWins)
+
+			c, err = h.contractSvc.GetContract(ctx, contractID)
+			require.NoError(t, err)
+			assert.Equal(t, models.ContractStatusPendingSettlement, c.Status)
+			require.NotNil(t, c.FinalTxID)
+
+			// The buyer spends the setup output through the high hash rate
+			// path to the final output
+			finalTx := h.transaction(ctx, contractID, "final", *c.FinalTxID)
+			finalMsgTx := h.sign(finalTx, buyerKey)
+			require.Len(t, finalMsgTx.TxOut, 1)
+			finalAddress, err := h.scripts.BuildFinalScript(buyerPubKey, sellerPubKey, c.EndBlockHeight, c.TargetTimestamp,
+				tc.contractType == models.ContractTypeCall)
+			require.NoError(t, err)
+			assert.Equal(t, addressScript(t, finalAddress), finalMsgTx.TxOut[0].PkScript)
+			assert.Less(t, finalMsgTx.TxOut[0].Value, setupTx.TxOut[0].Value)
+
+			h.broadcast(finalMsgTx)
+			h.mineBlocks(1)
+			assert.Equal(t, uint64(1), h.confirmations(finalTx.TransactionID))
+
+			// The winner spends the final output to their payout
+			winnerKey, winnerPubKey := sellerKey, sellerPubKey
+			if tc.expectBuyerWin {
+				winnerKey, winnerPubKey = buyerKey, buyerPubKey
+			}
+
+			settlementMsgTx := h.sign(settlementTx, winnerKey)
+			require.Len(t, settlementMsgTx.TxOut, 1)
+			assert.Equal(t, payoutScript(t, h.scripts, winnerPubKey), settlementMsgTx.TxOut[0].PkScript)
+			assert.Less(t, settlementMsgTx.TxOut[0].Value, finalMsgTx.TxOut[0].Value)
+
+			h.broadcast(settlementMsgTx)
+			h.mineBlocks(1)
+			assert.Equal(t, uint64(1), h.confirmations(settlementTx.TransactionID))
+
+			// Stored state reflects the confirmed settlement
+			watchCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			h.contractSvc.StartConfirmationWatcher(watchCtx)
+
+			require.Eventually(t, func() bool {
+				c, err = h.contractSvc.GetContract(ctx, contractID)
+				return err == nil && c.Status == models.ContractStatusSettled
+			}, 10*time.Second, 100*time.Millisecond)
+			require.NotNil(t, c.SettlementTxID)
+			assert.Equal(t, settlementTx.TransactionID, *c.SettlementTxID)
+
+			broadcast, err := h.contractSvc.GetSettlementBroadcast(ctx, contractID)
+			require.NoError(t, err)
+			require.NotNil(t, broadcast)
+			assert.False(t, broadcast.Pending())
+		})
+	}
+}
+
+// newKey returns a private key and its hex encoded compressed public key
+func newKey(t *testing.T) (*btcec.PrivateKey, string) {
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	return priv, hex.EncodeToString(priv.PubKey().SerializeCompressed())
+}
+
+// addressScript returns the output script of a regtest address
+func addressScript(t *testing.T, address string) []byte {
+	t.Helper()
+
+	addr, err := btcutil.DecodeAddress(address, &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	script, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+	return script
+}
+
+func payoutScript(t *testing.T, scripts *taproot.ScriptBuilder, winnerPubKey string) []byte {
+	t.Helper()
+
+	address, err := scripts.BuildSettlementScript(winnerPubKey, "")
+	require.NoError(t, err)
+	return addressScript(t, address)
+}
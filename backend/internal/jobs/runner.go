@@ -0,0 +1,199 @@
+// internal/jobs/runner.go
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Jobs)
+
+// Handler processes the payload of a job; a returned error triggers a retry
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Config holds the job runner configuration
+type Config struct {
+	PollInterval   time.Duration `yaml:"poll_interval"`
+	BatchSize      int           `yaml:"batch_size"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	// StaleAfter is how long a running job may hold its lock before it is picked up again
+	StaleAfter time.Duration `yaml:"stale_after"`
+}
+
+// DefaultConfig provides sensible defaults for the job runner
+var DefaultConfig = Config{
+	PollInterval:   5 * time.Second,
+	BatchSize:      10,
+	MaxAttempts:    5,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     30 * time.Minute,
+	StaleAfter:     10 * time.Minute,
+}
+
+// Runner executes persisted jobs with retries and moves exhausted jobs to the dead-letter state
+type Runner struct {
+	repo     *db.JobRepository
+	cfg      Config
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRunner creates a new job runner
+func NewRunner(repo *db.JobRepository, cfg Config) *Runner {
+	return &Runner{
+		repo:     repo,
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register sets the handler for a job type
+func (r *Runner) Register(jobType string, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[jobType] = h
+}
+
+// Enqueue persists a job to run at runAt, or immediately if runAt is zero
+func (r *Runner) Enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (*models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	job := &models.Job{
+		JobType:     jobType,
+		Payload:     data,
+		MaxAttempts: r.cfg.MaxAttempts,
+		RunAt:       runAt,
+	}
+	if err := r.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Get retrieves a job by ID
+func (r *Runner) Get(ctx context.Context, id uuid.UUID) (*models.Job, error) {
+	return r.repo.GetByID(ctx, id)
+}
+
+// List retrieves jobs in the given status
+func (r *Runner) List(ctx context.Context, status models.JobStatus, limit, offset int) ([]*models.Job, error) {
+	return r.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+// Replay requeues a dead job with a fresh retry budget
+func (r *Runner) Replay(ctx context.Context, id uuid.UUID) error {
+	return r.repo.Replay(ctx, id)
+}
+
+// Start begins polling for due jobs
+func (r *Runner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.runDue(ctx)
+			}
+		}
+	}()
+}
+
+// runDue claims and processes one batch of due jobs
+func (r *Runner) runDue(ctx context.Context) {
+	jobs, err := r.repo.ClaimDue(ctx, r.cfg.BatchSize, r.cfg.StaleAfter)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to claim due jobs")
+		return
+	}
+
+	for _, job := range jobs {
+		r.process(ctx, job)
+	}
+}
+
+// process runs a single claimed job and records the outcome
+func (r *Runner) process(ctx context.Context, job *models.Job) {
+	r.mu.RLock()
+	handler, ok := r.handlers[job.JobType]
+	r.mu.RUnlock()
+
+	var runErr error
+	if !ok {
+		runErr = fmt.Errorf("no handler registered for job type %s", job.JobType)
+	} else {
+		runErr = safeRun(ctx, handler, job.Payload)
+	}
+
+	if runErr == nil {
+		if err := r.repo.MarkCompleted(ctx, job.ID); err != nil {
+			logger.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to mark job completed")
+		}
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		logger.Error().
+			Err(runErr).
+			Str("job_id", job.ID.String()).
+			Str("job_type", job.JobType).
+			Int("attempts", job.Attempts).
+			Msg("Job moved to dead-letter queue")
+		if err := r.repo.MarkDead(ctx, job.ID, runErr.Error()); err != nil {
+			logger.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to mark job dead")
+		}
+		return
+	}
+
+	backoff := r.backoff(job.Attempts)
+	logger.Warn().
+		Err(runErr).
+		Str("job_id", job.ID.String()).
+		Str("job_type", job.JobType).
+		Int("attempt", job.Attempts).
+		Dur("backoff", backoff).
+		Msg("Job failed, scheduling retry")
+	if err := r.repo.MarkRetry(ctx, job.ID, time.Now().UTC().Add(backoff), runErr.Error()); err != nil {
+		logger.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to schedule job retry")
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling per attempt up to MaxBackoff
+func (r *Runner) backoff(attempts int) time.Duration {
+	backoff := r.cfg.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// safeRun calls the handler and turns a panic into an error so one bad job cannot stop the runner
+func safeRun(ctx context.Context, handler Handler, payload json.RawMessage) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job handler panicked: %v", p)
+		}
+	}()
+	return handler(ctx, payload)
+}
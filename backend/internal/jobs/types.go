@@ -0,0 +1,51 @@
+// internal/jobs/types.go
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Job types handled by the runner
+const (
+	TypeSettleContract = "contract.settle"
+	TypeExpireContract = "contract.expire"
+	TypeBroadcastTx    = "transaction.broadcast"
+)
+
+// ContractPayload is the payload of jobs that act on a single contract
+type ContractPayload struct {
+	ContractID uuid.UUID `json:"contract_id"`
+}
+
+// BroadcastPayload is the payload of jobs that broadcast a stored contract transaction
+type BroadcastPayload struct {
+	ContractID    uuid.UUID `json:"contract_id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// DecodeContractPayload decodes and validates a contract job payload
+func DecodeContractPayload(payload json.RawMessage) (ContractPayload, error) {
+	var p ContractPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return p, fmt.Errorf("failed to decode contract job payload: %w", err)
+	}
+	if p.ContractID == uuid.Nil {
+		return p, fmt.Errorf("contract job payload is missing contract_id")
+	}
+	return p, nil
+}
+
+// DecodeBroadcastPayload decodes and validates a broadcast job payload
+func DecodeBroadcastPayload(payload json.RawMessage) (BroadcastPayload, error) {
+	var p BroadcastPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return p, fmt.Errorf("failed to decode broadcast job payload: %w", err)
+	}
+	if p.ContractID == uuid.Nil || p.TransactionID == uuid.Nil {
+		return p, fmt.Errorf("broadcast job payload is missing contract_id or transaction_id")
+	}
+	return p, nil
+}
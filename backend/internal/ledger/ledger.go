@@ -0,0 +1,120 @@
+// internal/ledger/ledger.go
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// Config controls the ledger of users' funds
+type Config struct {
+	// Enabled books premiums, collateral and payouts against users'
+	// deposits and rejects orders their funds do not cover
+	Enabled bool `yaml:"enabled"`
+}
+
+// DefaultConfig leaves the ledger disabled, so orders are not limited by
+// deposits
+var DefaultConfig = Config{}
+
+// Reference of the journal committing a contract's collateral
+func collateralReference(contractID uuid.UUID) string {
+	return "collateral:" + contractID.String()
+}
+
+// Reference of the journal paying out a contract's collateral
+func payoutReference(contractID uuid.UUID) string {
+	return "payout:" + contractID.String()
+}
+
+// transfer builds a journal moving amount from one account to another
+func transfer(journalType models.JournalType, from, to models.LedgerPosting, amount int64) *models.Journal {
+	from.Amount = -amount
+	to.Amount = amount
+	return &models.Journal{
+		Type:     journalType,
+		Postings: []models.LedgerPosting{from, to},
+	}
+}
+
+// account is the posting template of one of a user's accounts
+func account(userID uuid.UUID, accountType models.LedgerAccountType) models.LedgerPosting {
+	return models.LedgerPosting{UserID: &userID, AccountType: accountType}
+}
+
+// external is the posting template of the platform's external account
+func external() models.LedgerPosting {
+	return models.LedgerPosting{AccountType: models.LedgerAccountExternal}
+}
+
+// depositJournal credits funds paid in by a user
+func depositJournal(userID uuid.UUID, amount int64) *models.Journal {
+	return transfer(models.JournalDeposit, external(), account(userID, models.LedgerAccountAvailable), amount)
+}
+
+// withdrawalJournal debits funds paid out to a user
+func withdrawalJournal(userID uuid.UUID, amount int64) *models.Journal {
+	return transfer(models.JournalWithdrawal, account(userID, models.LedgerAccountAvailable), external(), amount)
+}
+
+// reversalJournal refunds a withdrawal that was not paid out
+func reversalJournal(userID uuid.UUID, amount int64) *models.Journal {
+	return transfer(models.JournalReversal, external(), account(userID, models.LedgerAccountAvailable), amount)
+}
+
+// tradeJournals books the premium the buyer of a new contract pays its
+// seller, if any, and the seller's commitment of the contract's size
+func tradeJournals(contract *models.Contract, buyerID, sellerID uuid.UUID) []*models.Journal {
+	var journals []*models.Journal
+
+	if contract.Premium > 0 {
+		premium := transfer(models.JournalPremium,
+			account(buyerID, models.LedgerAccountAvailable),
+			account(sellerID, models.LedgerAccountAvailable),
+			contract.Premium)
+		premium.ContractID = &contract.ID
+		journals = append(journals, premium)
+	}
+
+	if contract.ContractSize > 0 {
+		reference := collateralReference(contract.ID)
+		collateral := transfer(models.JournalCollateral,
+			account(sellerID, models.LedgerAccountAvailable),
+			account(sellerID, models.LedgerAccountCommitted),
+			contract.ContractSize)
+		collateral.ContractID = &contract.ID
+		collateral.Reference = &reference
+		journals = append(journals, collateral)
+	}
+
+	return journals
+}
+
+// payoutJournal pays the seller's committed collateral to the winner of a
+// settled contract, which is the seller themselves if the buyer lost
+func payoutJournal(contract *models.Contract, buyerID, sellerID uuid.UUID, buyerWins bool) *models.Journal {
+	winner := sellerID
+	if buyerWins {
+		winner = buyerID
+	}
+
+	reference := payoutReference(contract.ID)
+	payout := transfer(models.JournalPayout,
+		account(sellerID, models.LedgerAccountCommitted),
+		account(winner, models.LedgerAccountAvailable),
+		contract.ContractSize)
+	payout.ContractID = &contract.ID
+	payout.Reference = &reference
+	return payout
+}
+
+// validateAmount checks that a deposit or withdrawal moves some funds
+func validateAmount(kind string, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("%s amount must be positive", kind)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// internal/ledger/ledger_test.go
+package ledger
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+// balanced sums a journal's postings, which must come to zero
+func balanced(t *testing.T, journal *models.Journal) {
+	t.Helper()
+	var sum int64
+	for _, posting := range journal.Postings {
+		sum += posting.Amount
+	}
+	assert.Zero(t, sum, "%s journal does not balance", journal.Type)
+}
+
+func TestDepositAndWithdrawalJournals(t *testing.T) {
+	userID := uuid.New()
+
+	deposit := depositJournal(userID, 5000)
+	balanced(t, deposit)
+	assert.Equal(t, models.JournalDeposit, deposit.Type)
+	assert.Nil(t, deposit.Postings[0].UserID)
+	assert.Equal(t, models.LedgerAccountExternal, deposit.Postings[0].AccountType)
+	assert.Equal(t, userID, *deposit.Postings[1].UserID)
+	assert.Equal(t, int64(5000), deposit.Postings[1].Amount)
+
+	withdrawal := withdrawalJournal(userID, 2000)
+	balanced(t, withdrawal)
+	assert.Equal(t, int64(-2000), withdrawal.Postings[0].Amount)
+	assert.Equal(t, models.LedgerAccountAvailable, withdrawal.Postings[0].AccountType)
+
+	reversal := reversalJournal(userID, 2000)
+	balanced(t, reversal)
+	assert.Equal(t, models.JournalReversal, reversal.Type)
+	assert.Equal(t, int64(2000), reversal.Postings[1].Amount)
+
+	assert.Error(t, validateAmount("deposit", 0))
+	assert.Error(t, validateAmount("withdrawal", -1))
+	assert.NoError(t, validateAmount("deposit", 1))
+}
+
+func TestTradeJournals(t *testing.T) {
+	buyerID, sellerID := uuid.New(), uuid.New()
+	contract := &models.Contract{ID: uuid.New(), ContractSize: 100_000}
+
+	// Without a premium only the collateral is booked
+	journals := tradeJournals(contract, buyerID, sellerID)
+	require.Len(t, journals, 1)
+	collateral := journals[0]
+	balanced(t, collateral)
+	assert.Equal(t, models.JournalCollateral, collateral.Type)
+	assert.Equal(t, "collateral:"+contract.ID.String(), *collateral.Reference)
+	assert.Equal(t, sellerID, *collateral.Postings[0].UserID)
+	assert.Equal(t, models.LedgerAccountAvailable, collateral.Postings[0].AccountType)
+	assert.Equal(t, sellerID, *collateral.Postings[1].UserID)
+	assert.Equal(t, models.LedgerAccountCommitted, collateral.Postings[1].AccountType)
+	assert.Equal(t, int64(100_000), collateral.Postings[1].Amount)
+
+	contract.Premium = 4000
+	journals = tradeJournals(contract, buyerID, sellerID)
+	require.Len(t, journals, 2)
+	premium := journals[0]
+	balanced(t, premium)
+	assert.Equal(t, models.JournalPremium, premium.Type)
+	assert.Nil(t, premium.Reference)
+	assert.Equal(t, buyerID, *premium.Postings[0].UserID)
+	assert.Equal(t, int64(-4000), premium.Postings[0].Amount)
+	assert.Equal(t, sellerID, *premium.Postings[1].UserID)
+}
+
+func TestPayoutJournal(t *testing.T) {
+	buyerID, sellerID := uuid.New(), uuid.New()
+	contract := &models.Contract{ID: uuid.New(), ContractSize: 100_000}
+
+	payout := payoutJournal(contract, buyerID, sellerID, true)
+	balanced(t, payout)
+	assert.Equal(t, "payout:"+contract.ID.String(), *payout.Reference)
+	assert.Equal(t, sellerID, *payout.Postings[0].UserID)
+	assert.Equal(t, models.LedgerAccountCommitted, payout.Postings[0].AccountType)
+	assert.Equal(t, buyerID, *payout.Postings[1].UserID)
+	assert.Equal(t, models.LedgerAccountAvailable, payout.Postings[1].AccountType)
+
+	// A losing buyer leaves the seller their collateral back
+	payout = payoutJournal(contract, buyerID, sellerID, false)
+	assert.Equal(t, sellerID, *payout.Postings[1].UserID)
+}
@@ -0,0 +1,148 @@
+// internal/ledger/service.go
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// Service keeps a double-entry ledger of users' funds. Deposits and
+// withdrawals move funds between a user's available account and the
+// platform, each trade moves the premium to the seller and commits the
+// contract's size from the seller's available funds, and settlement pays
+// the committed collateral to the winner. Funds held by resting orders are
+// not booked but count against what a user may trade or withdraw.
+type Service struct {
+	repo *db.LedgerRepository
+}
+
+// NewService creates a new ledger service
+func NewService(repo *db.LedgerRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Balance returns a user's funds
+func (s *Service) Balance(ctx context.Context, userID uuid.UUID) (*models.Balance, error) {
+	return s.repo.GetBalance(ctx, userID)
+}
+
+// Entries retrieves a page of the postings to a user's accounts, newest first
+func (s *Service) Entries(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.LedgerEntry, error) {
+	return s.repo.ListEntries(ctx, userID, limit, offset)
+}
+
+// Deposit credits funds a user has paid in
+func (s *Service) Deposit(ctx context.Context, userID uuid.UUID, amount int64) (*models.Balance, error) {
+	if err := validateAmount("deposit", amount); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Post(ctx, depositJournal(userID, amount)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBalance(ctx, userID)
+}
+
+// CreditDeposit implements wallet.Ledger by crediting funds a user sent on
+// chain in the transaction marking the deposit credited. The reference
+// identifies the deposit's output.
+func (s *Service) CreditDeposit(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error {
+	if err := validateAmount("deposit", amount); err != nil {
+		return err
+	}
+	journal := depositJournal(userID, amount)
+	journal.Reference = &reference
+	return s.repo.PostTx(ctx, tx, journal)
+}
+
+// DebitWithdrawal implements wallet.Ledger by debiting a withdrawal in
+// the transaction recording it. Funds held by the user's resting orders
+// cannot be withdrawn.
+func (s *Service) DebitWithdrawal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error {
+	if err := validateAmount("withdrawal", amount); err != nil {
+		return err
+	}
+
+	tradable, err := s.repo.TradableFunds(ctx, userID, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	if amount > tradable {
+		return fmt.Errorf("%d sats are not held by orders: %w", tradable, db.ErrInsufficientFunds)
+	}
+
+	journal := withdrawalJournal(userID, amount)
+	journal.Reference = &reference
+	return s.repo.PostTx(ctx, tx, journal)
+}
+
+// RefundWithdrawal implements wallet.Ledger by crediting back a withdrawal
+// that was not paid out, in the transaction recording why
+func (s *Service) RefundWithdrawal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error {
+	journal := reversalJournal(userID, amount)
+	journal.Reference = &reference
+	return s.repo.PostTx(ctx, tx, journal)
+}
+
+// AvailableToTrade implements orderbook.Ledger
+func (s *Service) AvailableToTrade(ctx context.Context, userID, excludeOrderID uuid.UUID) (int64, error) {
+	return s.repo.TradableFunds(ctx, userID, excludeOrderID)
+}
+
+// RecordTrade implements orderbook.Ledger by booking the premium and
+// collateral of the contract a trade created in the trade's transaction
+func (s *Service) RecordTrade(ctx context.Context, tx *sqlx.Tx, buyOrder, sellOrder *models.Order, contract *models.Contract) error {
+	for _, journal := range tradeJournals(contract, buyOrder.UserID, sellOrder.UserID) {
+		if err := s.repo.PostTx(ctx, tx, journal); err != nil {
+			return fmt.Errorf("failed to book %s of contract %s: %w", journal.Type, contract.ID, err)
+		}
+	}
+	return nil
+}
+
+// OnContractSettled implements contract.SettlementObserver by paying the
+// contract's committed collateral to the winner. Contracts traded before
+// the ledger was enabled have no collateral booked and are skipped.
+func (s *Service) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	log := logger.With().Str("contract_id", contract.ID.String()).Logger()
+
+	booked, err := s.repo.HasJournal(ctx, collateralReference(contract.ID))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to look up contract collateral")
+		return
+	}
+	if !booked {
+		return
+	}
+
+	buyerID, sellerID, err := s.repo.TradeParties(ctx, contract.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get contract parties for payout")
+		return
+	}
+
+	payout := payoutJournal(contract, buyerID, sellerID, buyerWins)
+	err = s.repo.Post(ctx, payout)
+	if errors.Is(err, db.ErrConflict) && !errors.Is(err, db.ErrInsufficientFunds) {
+		// Already paid out by an earlier notification of the settlement
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to book contract payout")
+		return
+	}
+
+	log.Info().
+		Bool("buyer_wins", buyerWins).
+		Int64("amount", contract.ContractSize).
+		Msg("Booked contract payout")
+}
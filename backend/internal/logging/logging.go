@@ -0,0 +1,246 @@
+// internal/logging/logging.go
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Component names with independently configurable log levels
+const (
+	OrderBook     = "orderbook"
+	Contract      = "contract"
+	Ark           = "ark"
+	Bitcoin       = "bitcoin"
+	HTTP          = "http"
+	Jobs          = "jobs"
+	Alerts        = "alerts"
+	Feeds         = "feeds"
+	Push          = "push"
+	Webhooks      = "webhooks"
+	Notifications = "notifications"
+)
+
+// Config holds the logging configuration
+type Config struct {
+	Level      string                     `yaml:"level"`
+	Components map[string]ComponentConfig `yaml:"components"`
+}
+
+// ComponentConfig holds the logging configuration for a single component
+type ComponentConfig struct {
+	Level string `yaml:"level"`
+	// SampleEvery keeps one in every N debug/info events; 0 or 1 disables sampling
+	SampleEvery uint32 `yaml:"sample_every"`
+}
+
+// ComponentStatus describes the active settings of a component logger
+type ComponentStatus struct {
+	Component   string `json:"component"`
+	Level       string `json:"level"`
+	SampleEvery uint32 `json:"sample_every"`
+}
+
+// component holds the runtime-adjustable state of a component logger.
+// It acts as both the level filter hook and the sampler for its logger.
+type component struct {
+	name        string
+	level       atomic.Int32
+	sampleEvery atomic.Uint32
+	counter     atomic.Uint32
+	logger      zerolog.Logger
+}
+
+// Run discards events below the component's current level
+func (c *component) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level < zerolog.Level(c.level.Load()) {
+		e.Discard()
+	}
+}
+
+// Sample keeps one in every N debug/info events; warnings and errors are never sampled
+func (c *component) Sample(level zerolog.Level) bool {
+	n := c.sampleEvery.Load()
+	if n <= 1 || level > zerolog.InfoLevel {
+		return true
+	}
+	return c.counter.Add(1)%n == 1
+}
+
+// traceHook adds the trace and span IDs of the context an event is logged
+// with, joining the event to its trace
+type traceHook struct{}
+
+// Run adds the IDs when the event's context carries a span
+func (traceHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	span := trace.SpanContextFromContext(e.GetCtx())
+	if !span.IsValid() {
+		return
+	}
+	e.Str("trace_id", span.TraceID().String()).Str("span_id", span.SpanID().String())
+}
+
+// switchWriter lets the output be replaced after component loggers are created
+type switchWriter struct {
+	mu sync.RWMutex
+	w  io.Writer
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.w.Write(p)
+}
+
+func (s *switchWriter) set(w io.Writer) {
+	s.mu.Lock()
+	s.w = w
+	s.mu.Unlock()
+}
+
+var (
+	output       = &switchWriter{w: os.Stderr}
+	defaultLevel atomic.Int32
+	registryMu   sync.RWMutex
+	registry     = make(map[string]*component)
+)
+
+func init() {
+	defaultLevel.Store(int32(zerolog.InfoLevel))
+}
+
+// Component returns the logger for the named component, creating it on first use.
+// The returned pointer stays valid across Setup and SetLevel calls.
+func Component(name string) *zerolog.Logger {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if c, ok := registry[name]; ok {
+		return &c.logger
+	}
+
+	c := &component{name: name}
+	c.level.Store(defaultLevel.Load())
+	c.logger = zerolog.New(output).
+		With().
+		Timestamp().
+		Str("component", name).
+		Logger().
+		Hook(traceHook{}).
+		Hook(c).
+		Sample(c)
+	registry[name] = c
+
+	return &c.logger
+}
+
+// Setup configures the log output, the default level, and per-component overrides
+func Setup(cfg Config, w io.Writer) error {
+	level := zerolog.InfoLevel
+	if cfg.Level != "" {
+		parsed, err := zerolog.ParseLevel(cfg.Level)
+		if err != nil {
+			return fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+		}
+		level = parsed
+	}
+
+	output.set(w)
+	defaultLevel.Store(int32(level))
+
+	// Filtering happens per component, so the global gate must let everything through
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+	log.Logger = zerolog.New(output).Level(level).With().Timestamp().Logger().Hook(traceHook{})
+
+	// Ensure the known components exist so they are listed by Status
+	for _, name := range []string{OrderBook, Contract, Ark, Bitcoin, HTTP, Jobs, Alerts, Feeds, Push, Notifications} {
+		Component(name)
+	}
+
+	registryMu.RLock()
+	for _, c := range registry {
+		c.level.Store(int32(level))
+	}
+	registryMu.RUnlock()
+
+	for name, compCfg := range cfg.Components {
+		Component(name)
+		if compCfg.Level != "" {
+			if err := SetLevel(name, compCfg.Level); err != nil {
+				return err
+			}
+		}
+		if err := SetSampling(name, compCfg.SampleEvery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetLevel changes the level of a component at runtime
+func SetLevel(name, level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	c, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	c.level.Store(int32(parsed))
+	return nil
+}
+
+// SetSampling changes how many debug/info events of a component are kept at runtime
+func SetSampling(name string, every uint32) error {
+	c, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	c.sampleEvery.Store(every)
+	return nil
+}
+
+// Status returns the active settings of all component loggers
+func Status() []ComponentStatus {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	statuses := make([]ComponentStatus, 0, len(registry))
+	for name, c := range registry {
+		statuses = append(statuses, ComponentStatus{
+			Component:   name,
+			Level:       zerolog.Level(c.level.Load()).String(),
+			SampleEvery: c.sampleEvery.Load(),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Component < statuses[j].Component
+	})
+
+	return statuses
+}
+
+func lookup(name string) (*component, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	c, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown log component: %s", name)
+	}
+	return c, nil
+}
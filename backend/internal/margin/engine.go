@@ -0,0 +1,370 @@
+// internal/margin/engine.go
+package margin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// pageSize is how many active positions are marked per page
+const pageSize = 100
+
+var (
+	// ErrNotEnabled is returned when margin is disabled
+	ErrNotEnabled = errors.New("margin is not enabled")
+	// ErrNotMarginable is returned for a contract that cannot be margined
+	ErrNotMarginable = errors.New("contract cannot be margined")
+)
+
+// Event types published on a user's margin channel
+const (
+	EventMarginCall  = "margin_call"
+	EventLiquidation = "liquidation"
+)
+
+// Event is a margin call or liquidation of a user's position
+type Event struct {
+	PositionID     uuid.UUID  `json:"position_id"`
+	ContractID     uuid.UUID  `json:"contract_id"`
+	Collateral     int64      `json:"collateral"`
+	RequiredMargin int64      `json:"required_margin"`
+	LiquidateAt    *time.Time `json:"liquidate_at,omitempty"`
+}
+
+// Channel is the websocket channel carrying a user's margin events
+func Channel(userID uuid.UUID) string {
+	return "margin:" + userID.String()
+}
+
+// Contracts is the contract service as used by the margin engine
+type Contracts interface {
+	GetContract(ctx context.Context, id uuid.UUID) (*models.Contract, error)
+	ExitContract(ctx context.Context, contractID uuid.UUID) (int, error)
+}
+
+// HashRateSource reports the current network hash rate in EH/s
+type HashRateSource interface {
+	CalculateCurrentHashRate(ctx context.Context) (float64, error)
+}
+
+// Publisher pushes messages to subscribers of a websocket channel
+type Publisher interface {
+	PublishToChannel(channel string, message interface{})
+}
+
+// Observer is told of the margin calls and liquidations of users' positions
+type Observer interface {
+	OnMarginEvent(ctx context.Context, userID uuid.UUID, eventType string, event Event)
+}
+
+// Summary is a user's margin account with their positions
+type Summary struct {
+	Account   *models.MarginAccount    `json:"account"`
+	Positions []*models.MarginPosition `json:"positions"`
+}
+
+// Engine lets contract writers post part of the contract size as margin.
+// Each run marks the active positions against the hash rate, calls those
+// that fall below their maintenance margin and liquidates calls left unmet
+// past the grace period by exiting the contract on chain.
+type Engine struct {
+	repo      *db.MarginRepository
+	contracts Contracts
+	hashRate  HashRateSource
+	publisher Publisher
+	observer  Observer
+	cfg       Config
+}
+
+// NewEngine creates a new margin engine
+func NewEngine(repo *db.MarginRepository, contracts Contracts, hashRate HashRateSource, publisher Publisher, cfg Config) *Engine {
+	return &Engine{
+		repo:      repo,
+		contracts: contracts,
+		hashRate:  hashRate,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// WithObserver tells observer of every margin call and liquidation
+func (e *Engine) WithObserver(observer Observer) *Engine {
+	e.observer = observer
+	return e
+}
+
+// Summary returns a user's margin account and positions
+func (e *Engine) Summary(ctx context.Context, userID uuid.UUID) (*Summary, error) {
+	account, err := e.repo.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := e.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Summary{Account: account, Positions: positions}, nil
+}
+
+// Deposit credits collateral to a user's margin account
+func (e *Engine) Deposit(ctx context.Context, userID uuid.UUID, amount int64) (*models.MarginAccount, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("deposit amount must be positive")
+	}
+	return e.repo.Deposit(ctx, userID, amount)
+}
+
+// Withdraw debits free collateral from a user's margin account
+func (e *Engine) Withdraw(ctx context.Context, userID uuid.UUID, amount int64) (*models.MarginAccount, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("withdrawal amount must be positive")
+	}
+	return e.repo.Withdraw(ctx, userID, amount)
+}
+
+// Open margins a contract its writer has not yet funded, locking the
+// initial margin from the writer's account
+func (e *Engine) Open(ctx context.Context, userID uuid.UUID, contract *models.Contract) (*models.MarginPosition, error) {
+	if !e.cfg.Enabled() {
+		return nil, ErrNotEnabled
+	}
+	if contract.Status != models.ContractStatusCreated {
+		return nil, fmt.Errorf("%w: contract has already been set up", ErrNotMarginable)
+	}
+
+	requirement, ok := e.cfg.Requirements[contract.ContractType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s contracts are not margined", ErrNotMarginable, contract.ContractType)
+	}
+
+	position := &models.MarginPosition{
+		ContractID:     contract.ID,
+		UserID:         userID,
+		Collateral:     initialMargin(contract.ContractSize, requirement),
+		RequiredMargin: maintenanceMargin(contract.ContractSize, requirement, 0.5),
+	}
+	if err := e.repo.OpenPosition(ctx, position); err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Str("contract_id", contract.ID.String()).
+		Str("user_id", userID.String()).
+		Int64("collateral", position.Collateral).
+		Msg("Opened margin position")
+
+	return position, nil
+}
+
+// TopUp locks more of a user's collateral in one of their positions,
+// meeting a margin call once the collateral covers the requirement
+func (e *Engine) TopUp(ctx context.Context, userID, positionID uuid.UUID, amount int64) (*models.MarginPosition, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("top up amount must be positive")
+	}
+
+	position, err := e.repo.GetPosition(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if position.UserID != userID {
+		return nil, fmt.Errorf("margin position %s: %w", positionID, db.ErrNotFound)
+	}
+
+	return e.repo.TopUp(ctx, positionID, amount)
+}
+
+// RequiredCollateral implements contract.CollateralPolicy. A margined
+// contract is funded with its writer's collateral, the operator funding
+// the remainder; any other contract needs its full size.
+func (e *Engine) RequiredCollateral(ctx context.Context, contract *models.Contract) (int64, error) {
+	position, err := e.repo.GetByContract(ctx, contract.ID)
+	if err != nil {
+		return 0, err
+	}
+	if position == nil || !position.Active() {
+		return contract.ContractSize, nil
+	}
+	return position.Collateral, nil
+}
+
+// OnContractSettled implements contract.SettlementObserver, releasing the
+// writer's collateral or forfeiting it when the buyer won
+func (e *Engine) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	position, err := e.repo.GetByContract(ctx, contract.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to get margin position")
+		return
+	}
+	if position == nil || !position.Active() {
+		return
+	}
+
+	if _, err := e.repo.Close(ctx, position.ID, models.MarginStatusClosed, buyerWins); err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to close margin position")
+	}
+}
+
+// Start begins marking positions on the configured interval
+func (e *Engine) Start(ctx context.Context) {
+	if !e.cfg.Enabled() {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(e.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := e.run(ctx, time.Now().UTC()); err != nil {
+					logger.Error().Err(err).Msg("Margin run failed")
+				}
+			}
+		}
+	}()
+}
+
+// run marks every active position against the current hash rate
+func (e *Engine) run(ctx context.Context, now time.Time) error {
+	hashRate, err := e.hashRate.CalculateCurrentHashRate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get hash rate: %w", err)
+	}
+
+	// List every active position before marking any, since liquidated
+	// positions drop out of the list and would shift the pages
+	var positions []*models.MarginPosition
+	for offset := 0; ; offset += pageSize {
+		page, err := e.repo.ListActive(ctx, pageSize, offset)
+		if err != nil {
+			return err
+		}
+		positions = append(positions, page...)
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	for _, position := range positions {
+		if err := e.mark(ctx, position, hashRate, now); err != nil {
+			logger.Error().Err(err).Str("position_id", position.ID.String()).Msg("Failed to mark margin position")
+		}
+	}
+	return nil
+}
+
+// mark updates a position's requirement, calling it when its collateral
+// falls short and liquidating it when a call has gone unmet too long
+func (e *Engine) mark(ctx context.Context, position *models.MarginPosition, hashRate float64, now time.Time) error {
+	contract, err := e.contracts.GetContract(ctx, position.ContractID)
+	if err != nil {
+		return err
+	}
+	// Positions are marked from setup until settlement closes them
+	if contract.Status != models.ContractStatusActive {
+		return nil
+	}
+
+	requirement, ok := e.cfg.Requirements[contract.ContractType]
+	if !ok {
+		return nil
+	}
+	loss := writerLossProbability(contract.ContractType, contract.StrikeHashRate, hashRate, e.cfg.Band)
+	required := maintenanceMargin(contract.ContractSize, requirement, loss)
+
+	if position.Collateral >= required {
+		if position.Status == models.MarginStatusOpen && position.RequiredMargin == required {
+			return nil
+		}
+		return e.repo.UpdateRequirement(ctx, position.ID, required, models.MarginStatusOpen, nil)
+	}
+
+	if position.Status == models.MarginStatusOpen {
+		if err := e.repo.UpdateRequirement(ctx, position.ID, required, models.MarginStatusCalled, &now); err != nil {
+			return err
+		}
+		position.RequiredMargin = required
+		position.CalledAt = &now
+		e.publish(ctx, EventMarginCall, position)
+
+		logger.Warn().
+			Str("contract_id", contract.ID.String()).
+			Int64("collateral", position.Collateral).
+			Int64("required", required).
+			Msg("Margin call")
+		return nil
+	}
+
+	if position.CalledAt != nil && now.Sub(*position.CalledAt) < e.cfg.GracePeriod {
+		if position.RequiredMargin == required {
+			return nil
+		}
+		return e.repo.UpdateRequirement(ctx, position.ID, required, models.MarginStatusCalled, position.CalledAt)
+	}
+
+	return e.liquidate(ctx, position)
+}
+
+// liquidate exits a contract whose margin call went unmet on chain with its
+// emergency exit and forfeits the writer's collateral
+func (e *Engine) liquidate(ctx context.Context, position *models.MarginPosition) error {
+	if _, err := e.contracts.ExitContract(ctx, position.ContractID); err != nil {
+		return fmt.Errorf("failed to exit contract: %w", err)
+	}
+
+	closed, err := e.repo.Close(ctx, position.ID, models.MarginStatusLiquidated, true)
+	if err != nil {
+		return err
+	}
+	e.publish(ctx, EventLiquidation, closed)
+
+	logger.Warn().
+		Str("contract_id", position.ContractID.String()).
+		Int64("collateral", position.Collateral).
+		Msg("Liquidated margin position")
+	return nil
+}
+
+// publish sends a margin event to the position's user
+func (e *Engine) publish(ctx context.Context, eventType string, position *models.MarginPosition) {
+	if e.publisher == nil && e.observer == nil {
+		return
+	}
+
+	event := Event{
+		PositionID:     position.ID,
+		ContractID:     position.ContractID,
+		Collateral:     position.Collateral,
+		RequiredMargin: position.RequiredMargin,
+	}
+	if eventType == EventMarginCall && position.CalledAt != nil {
+		deadline := position.CalledAt.Add(e.cfg.GracePeriod)
+		event.LiquidateAt = &deadline
+	}
+
+	if e.publisher != nil {
+		e.publisher.PublishToChannel(Channel(position.UserID), map[string]interface{}{
+			"type":    eventType,
+			"payload": event,
+		})
+	}
+	if e.observer != nil {
+		e.observer.OnMarginEvent(ctx, position.UserID, eventType, event)
+	}
+}
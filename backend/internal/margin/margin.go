@@ -0,0 +1,119 @@
+// internal/margin/margin.go
+package margin
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// Requirement is the margin of a contract type as fractions of the
+// contract size
+type Requirement struct {
+	// Initial is the collateral posted when a position is opened
+	Initial float64 `yaml:"initial"`
+	// Maintenance is the collateral an at the money position must keep.
+	// It grows as the hash rate moves against the writer.
+	Maintenance float64 `yaml:"maintenance"`
+}
+
+// Validate checks that the requirement is a usable fraction
+func (r Requirement) Validate() error {
+	if r.Maintenance <= 0 {
+		return fmt.Errorf("maintenance margin must be positive")
+	}
+	if r.Initial < r.Maintenance {
+		return fmt.Errorf("initial margin must be at least the maintenance margin")
+	}
+	if r.Initial > 1 {
+		return fmt.Errorf("initial margin cannot exceed the contract size")
+	}
+	return nil
+}
+
+// Config controls partial collateralization of contracts
+type Config struct {
+	// Interval is how often positions are marked; zero disables margin
+	Interval time.Duration `yaml:"interval"`
+	// Requirements holds the margin of each contract type
+	Requirements map[models.ContractType]Requirement `yaml:"requirements"`
+	// Band is the distance of the hash rate from the strike, as a fraction
+	// of the strike, over which the writer goes from even odds to a
+	// certain loss
+	Band float64 `yaml:"band"`
+	// GracePeriod is how long a margin call may go unmet before the
+	// contract is liquidated
+	GracePeriod time.Duration `yaml:"grace_period"`
+}
+
+// DefaultConfig leaves margin disabled. Once enabled, writers post half the
+// contract size and are called below 30% at the money.
+var DefaultConfig = Config{
+	Requirements: map[models.ContractType]Requirement{
+		models.ContractTypeCall: {Initial: 0.5, Maintenance: 0.3},
+		models.ContractTypePut:  {Initial: 0.5, Maintenance: 0.3},
+	},
+	Band:        0.1,
+	GracePeriod: time.Hour,
+}
+
+// Enabled reports whether contracts may be margined
+func (c Config) Enabled() bool {
+	return c.Interval > 0
+}
+
+// Validate checks that the margin settings are usable
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("margin interval cannot be negative")
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	for contractType, requirement := range c.Requirements {
+		if contractType != models.ContractTypeCall && contractType != models.ContractTypePut {
+			return fmt.Errorf("unknown margin contract type %q", contractType)
+		}
+		if err := requirement.Validate(); err != nil {
+			return fmt.Errorf("%s %w", contractType, err)
+		}
+	}
+	if c.Band <= 0 {
+		return fmt.Errorf("margin band must be positive")
+	}
+	if c.GracePeriod < 0 {
+		return fmt.Errorf("margin grace period cannot be negative")
+	}
+	return nil
+}
+
+// writerLossProbability estimates the chance that the writer of a contract
+// loses it, from how far the hash rate is from the strike. The writer of a
+// call loses when the hash rate ends high, the writer of a put when it ends
+// low; at the strike the odds are even.
+func writerLossProbability(contractType models.ContractType, strike, hashRate, band float64) float64 {
+	distance := (hashRate - strike) / strike
+	if contractType == models.ContractTypePut {
+		distance = -distance
+	}
+	return math.Max(0, math.Min(1, 0.5+distance/(2*band)))
+}
+
+// initialMargin is the collateral posted when a position is opened
+func initialMargin(size int64, requirement Requirement) int64 {
+	return int64(math.Ceil(requirement.Initial * float64(size)))
+}
+
+// maintenanceMargin is the collateral a position must keep given the odds
+// of the writer losing: the maintenance fraction at even odds, half of it
+// for a certain win and one and a half times it for a certain loss, never
+// more than the contract size
+func maintenanceMargin(size int64, requirement Requirement, lossProbability float64) int64 {
+	required := int64(math.Ceil(requirement.Maintenance * (0.5 + lossProbability) * float64(size)))
+	if required > size {
+		return size
+	}
+	return required
+}
@@ -0,0 +1,50 @@
+// internal/margin/margin_test.go
+package margin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestWriterLossProbability(t *testing.T) {
+	assert.InDelta(t, 0.5, writerLossProbability(models.ContractTypeCall, 500, 500, 0.1), 1e-9)
+	assert.InDelta(t, 0.75, writerLossProbability(models.ContractTypeCall, 500, 525, 0.1), 1e-9)
+	assert.InDelta(t, 0.25, writerLossProbability(models.ContractTypePut, 500, 525, 0.1), 1e-9)
+	assert.Equal(t, 1.0, writerLossProbability(models.ContractTypeCall, 500, 600, 0.1))
+	assert.Equal(t, 0.0, writerLossProbability(models.ContractTypeCall, 500, 400, 0.1))
+}
+
+func TestMargins(t *testing.T) {
+	requirement := Requirement{Initial: 0.5, Maintenance: 0.3}
+
+	assert.Equal(t, int64(50_000), initialMargin(100_000, requirement))
+	assert.Equal(t, int64(30_000), maintenanceMargin(100_000, requirement, 0.5))
+	assert.Equal(t, int64(15_000), maintenanceMargin(100_000, requirement, 0))
+	assert.Equal(t, int64(45_000), maintenanceMargin(100_000, requirement, 1))
+
+	// The requirement never exceeds the contract size
+	assert.Equal(t, int64(100_000), maintenanceMargin(100_000, Requirement{Initial: 1, Maintenance: 0.9}, 1))
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	cfg := DefaultConfig
+	cfg.Interval = time.Minute
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Requirements = map[models.ContractType]Requirement{models.ContractTypeCall: {Initial: 0.2, Maintenance: 0.3}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Requirements = map[models.ContractType]Requirement{"SWAP": {Initial: 0.5, Maintenance: 0.3}}
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig
+	cfg.Interval = time.Minute
+	cfg.Band = 0
+	assert.Error(t, cfg.Validate())
+}
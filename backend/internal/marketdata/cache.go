@@ -0,0 +1,148 @@
+// internal/marketdata/cache.go
+package marketdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Config holds the public market data cache configuration
+type Config struct {
+	// TTL is how long order book depth, tickers and stats are served from cache
+	TTL time.Duration `yaml:"ttl"`
+	// HashRateTTL is how long the network hash rate is served from cache
+	HashRateTTL time.Duration `yaml:"hash_rate_ttl"`
+	// MaxEntries bounds the number of cached responses
+	MaxEntries int `yaml:"max_entries"`
+	// Liquidity controls the order book liquidity metrics
+	Liquidity LiquidityConfig `yaml:"liquidity"`
+}
+
+// DefaultConfig provides sensible defaults for the market data cache
+var DefaultConfig = Config{
+	TTL:         2 * time.Second,
+	HashRateTTL: time.Minute,
+	MaxEntries:  1000,
+	Liquidity:   DefaultLiquidityConfig,
+}
+
+// Validate checks that the configuration is usable
+func (c Config) Validate() error {
+	if c.TTL <= 0 || c.HashRateTTL <= 0 {
+		return fmt.Errorf("market data cache TTLs must be positive")
+	}
+	if c.MaxEntries <= 0 {
+		return fmt.Errorf("market data cache max entries must be positive")
+	}
+	return c.Liquidity.Validate()
+}
+
+// Entry is an encoded response and the validator clients revalidate it with
+type Entry struct {
+	Body    []byte
+	ETag    string
+	Expires time.Time
+}
+
+// slot holds the entry of one key; its lock is held while the entry is
+// loaded so concurrent misses for a key load it only once
+type slot struct {
+	mu    sync.Mutex
+	entry *Entry
+}
+
+// Cache is an in-process cache of encoded public market data responses
+type Cache struct {
+	mu         sync.Mutex
+	slots      map[string]*slot
+	maxEntries int
+	now        func() time.Time
+}
+
+// NewCache creates an empty cache holding at most maxEntries responses
+func NewCache(maxEntries int) *Cache {
+	return &Cache{
+		slots:      make(map[string]*slot),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached entry for key, calling load and encoding its result
+// as JSON if the entry is missing or older than ttl. Load errors are not cached.
+func (c *Cache) Get(key string, ttl time.Duration, load func() (interface{}, error)) (*Entry, error) {
+	s := c.slot(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := c.now()
+	if s.entry != nil && now.Before(s.entry.Expires) {
+		return s.entry, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode market data: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	s.entry = &Entry{
+		Body:    body,
+		ETag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Expires: now.Add(ttl),
+	}
+	return s.entry, nil
+}
+
+// Len returns the number of cached keys
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.slots)
+}
+
+// slot returns the slot of key, evicting expired entries when the cache is
+// full. If nothing can be evicted the returned slot is not retained, so the
+// response is still served but not cached.
+func (c *Cache) slot(key string) *slot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.slots[key]; ok {
+		return s
+	}
+
+	if len(c.slots) >= c.maxEntries {
+		c.evictExpired()
+	}
+
+	s := &slot{}
+	if len(c.slots) < c.maxEntries {
+		c.slots[key] = s
+	}
+	return s
+}
+
+// evictExpired removes slots whose entry has expired. Slots being loaded are
+// skipped. The caller must hold c.mu.
+func (c *Cache) evictExpired() {
+	now := c.now()
+	for key, s := range c.slots {
+		if !s.mu.TryLock() {
+			continue
+		}
+		if s.entry == nil || !now.Before(s.entry.Expires) {
+			delete(c.slots, key)
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,73 @@
+// internal/marketdata/cache_test.go
+package marketdata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheGet(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCache(10)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return map[string]int{"loads": loads}, nil
+	}
+
+	first, err := cache.Get("stats", time.Second, load)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"loads":1}`, string(first.Body))
+
+	t.Run("fresh entry is served from cache", func(t *testing.T) {
+		entry, err := cache.Get("stats", time.Second, load)
+		assert.NoError(t, err)
+		assert.Equal(t, first, entry)
+		assert.Equal(t, 1, loads)
+	})
+
+	t.Run("expired entry is reloaded", func(t *testing.T) {
+		now = now.Add(time.Second)
+		entry, err := cache.Get("stats", time.Second, load)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"loads":2}`, string(entry.Body))
+		assert.NotEqual(t, first.ETag, entry.ETag)
+	})
+
+	t.Run("load errors are not cached", func(t *testing.T) {
+		_, err := cache.Get("hashrate", time.Second, func() (interface{}, error) {
+			return nil, errors.New("node unavailable")
+		})
+		assert.Error(t, err)
+
+		entry, err := cache.Get("hashrate", time.Second, load)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"loads":3}`, string(entry.Body))
+	})
+}
+
+func TestCacheEviction(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewCache(2)
+	cache.now = func() time.Time { return now }
+	load := func() (interface{}, error) { return "ok", nil }
+
+	_, _ = cache.Get("a", time.Second, load)
+	_, _ = cache.Get("b", 10*time.Second, load)
+
+	// Full of fresh entries: served but not retained
+	_, err := cache.Get("c", time.Second, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+
+	// Expired entries make room
+	now = now.Add(2 * time.Second)
+	_, err = cache.Get("c", time.Second, load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cache.Len())
+}
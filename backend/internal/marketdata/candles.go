@@ -0,0 +1,45 @@
+// internal/marketdata/candles.go
+package marketdata
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxCandles bounds the candles returned by one request
+const MaxCandles = 1000
+
+// CandleIntervals are the supported candle widths by name
+var CandleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// ParseCandleInterval returns the width of a named candle interval
+func ParseCandleInterval(name string) (time.Duration, error) {
+	interval, ok := CandleIntervals[name]
+	if !ok {
+		return 0, fmt.Errorf("invalid candle interval %q, expected 1m, 5m, 15m, 1h, 4h or 1d", name)
+	}
+	return interval, nil
+}
+
+// CandleRange returns the bucket-aligned range of a candle request: from the
+// bucket containing since to the end of the bucket containing until. It
+// fails if the range would hold more than MaxCandles buckets.
+func CandleRange(interval time.Duration, since, until time.Time) (time.Time, time.Time, error) {
+	if !since.Before(until) {
+		return time.Time{}, time.Time{}, fmt.Errorf("since must be before until")
+	}
+
+	from := since.Truncate(interval)
+	to := until.Truncate(interval).Add(interval)
+	if to.Sub(from)/interval > MaxCandles {
+		return time.Time{}, time.Time{}, fmt.Errorf("range spans more than %d candles", MaxCandles)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,35 @@
+// internal/marketdata/candles_test.go
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCandleInterval(t *testing.T) {
+	interval, err := ParseCandleInterval("15m")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, interval)
+
+	_, err = ParseCandleInterval("2m")
+	assert.Error(t, err)
+}
+
+func TestCandleRange(t *testing.T) {
+	since := time.Date(2024, 6, 1, 10, 7, 30, 0, time.UTC)
+	until := time.Date(2024, 6, 1, 11, 52, 0, 0, time.UTC)
+
+	from, to, err := CandleRange(time.Hour, since, until)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), to)
+
+	_, _, err = CandleRange(time.Hour, until, since)
+	assert.Error(t, err)
+
+	_, _, err = CandleRange(time.Minute, since, since.Add(MaxCandles*time.Minute))
+	assert.Error(t, err)
+}
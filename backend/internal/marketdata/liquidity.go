@@ -0,0 +1,306 @@
+// internal/marketdata/liquidity.go
+package marketdata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// LiquidityConfig controls the order book liquidity metrics
+type LiquidityConfig struct {
+	// Window is how far back the rolling averages reach
+	Window time.Duration `yaml:"window"`
+	// DepthBands are the distances from the mid price, in percent, within
+	// which resting quantity is summed
+	DepthBands []float64 `yaml:"depth_bands"`
+}
+
+// DefaultLiquidityConfig averages over five minutes and reports depth
+// within 1%, 5% and 10% of the mid price
+var DefaultLiquidityConfig = LiquidityConfig{
+	Window:     5 * time.Minute,
+	DepthBands: []float64{1, 5, 10},
+}
+
+// Validate checks that the liquidity settings are usable
+func (c LiquidityConfig) Validate() error {
+	if c.Window <= 0 {
+		return fmt.Errorf("liquidity window must be positive")
+	}
+	for _, band := range c.DepthBands {
+		if band <= 0 || band > 100 {
+			return fmt.Errorf("liquidity depth band %g must be between 0 and 100 percent", band)
+		}
+	}
+	return nil
+}
+
+// DepthBand is the resting quantity within a distance of the mid price
+type DepthBand struct {
+	Percent     float64  `json:"percent"`
+	BidQuantity int      `json:"bid_quantity"`
+	AskQuantity int      `json:"ask_quantity"`
+	Imbalance   *float64 `json:"imbalance,omitempty"`
+}
+
+// RollingLiquidity averages the metrics sampled at each change of a market
+// over the configured window
+type RollingLiquidity struct {
+	Window    string   `json:"window"`
+	Samples   int      `json:"samples"`
+	Imbalance *float64 `json:"imbalance,omitempty"`
+	SpreadBps *float64 `json:"spread_bps,omitempty"`
+}
+
+// Liquidity is the spread, top of book imbalance and banded depth of a
+// market. Imbalance is (bid - ask) / (bid + ask) quantity, from -1 when
+// only asks rest to 1 when only bids rest.
+type Liquidity struct {
+	ContractType     models.ContractType `json:"contract_type"`
+	StrikeHashRate   float64             `json:"strike_hash_rate"`
+	StartBlockHeight int64               `json:"start_block_height"`
+	EndBlockHeight   int64               `json:"end_block_height"`
+	Time             time.Time           `json:"time"`
+	BestBid          *int64              `json:"best_bid,omitempty"`
+	BestAsk          *int64              `json:"best_ask,omitempty"`
+	Mid              *float64            `json:"mid,omitempty"`
+	Spread           *int64              `json:"spread,omitempty"`
+	SpreadBps        *float64            `json:"spread_bps,omitempty"`
+	Imbalance        *float64            `json:"imbalance,omitempty"`
+	Bands            []DepthBand         `json:"bands"`
+	Rolling          RollingLiquidity    `json:"rolling"`
+}
+
+// imbalance returns the quantity imbalance of two sides, or nil if both are empty
+func imbalance(bid, ask int) *float64 {
+	if bid+ask == 0 {
+		return nil
+	}
+	value := float64(bid-ask) / float64(bid+ask)
+	return &value
+}
+
+// measureLiquidity computes the liquidity of a market from its depth, best
+// price first on each side. Bands are measured from the mid price, or from
+// the only side's best price when the other side is empty.
+func measureLiquidity(key orderbook.OrderKey, depth orderbook.Depth, bands []float64, now time.Time) *Liquidity {
+	l := &Liquidity{
+		ContractType:     key.ContractType,
+		StrikeHashRate:   key.StrikeHashRate,
+		StartBlockHeight: key.StartBlockHeight,
+		EndBlockHeight:   key.EndBlockHeight,
+		Time:             now,
+		Bands:            []DepthBand{},
+	}
+
+	var reference float64
+	var bidTop, askTop int
+	if len(depth.Bids) > 0 {
+		l.BestBid = &depth.Bids[0].Price
+		bidTop = depth.Bids[0].Quantity
+		reference = float64(depth.Bids[0].Price)
+	}
+	if len(depth.Asks) > 0 {
+		l.BestAsk = &depth.Asks[0].Price
+		askTop = depth.Asks[0].Quantity
+		reference = float64(depth.Asks[0].Price)
+	}
+	l.Imbalance = imbalance(bidTop, askTop)
+
+	if l.BestBid != nil && l.BestAsk != nil {
+		mid := float64(*l.BestBid+*l.BestAsk) / 2
+		spread := *l.BestAsk - *l.BestBid
+		l.Mid = &mid
+		l.Spread = &spread
+		if mid > 0 {
+			spreadBps := float64(spread) / mid * 10000
+			l.SpreadBps = &spreadBps
+		}
+		reference = mid
+	}
+	if reference <= 0 {
+		return l
+	}
+
+	for _, percent := range bands {
+		band := DepthBand{Percent: percent}
+		low := reference * (1 - percent/100)
+		high := reference * (1 + percent/100)
+		for _, level := range depth.Bids {
+			if float64(level.Price) >= low {
+				band.BidQuantity += level.Quantity
+			}
+		}
+		for _, level := range depth.Asks {
+			if float64(level.Price) <= high {
+				band.AskQuantity += level.Quantity
+			}
+		}
+		band.Imbalance = imbalance(band.BidQuantity, band.AskQuantity)
+		l.Bands = append(l.Bands, band)
+	}
+
+	return l
+}
+
+// liquiditySample is the part of a measurement averaged over the window
+type liquiditySample struct {
+	Time      time.Time
+	Imbalance *float64
+	SpreadBps *float64
+}
+
+// rolling averages the samples, skipping metrics a sample lacks
+func rolling(samples []liquiditySample, window time.Duration) RollingLiquidity {
+	r := RollingLiquidity{Window: window.String(), Samples: len(samples)}
+
+	var imbalanceSum, spreadSum float64
+	var imbalanceCount, spreadCount int
+	for _, s := range samples {
+		if s.Imbalance != nil {
+			imbalanceSum += *s.Imbalance
+			imbalanceCount++
+		}
+		if s.SpreadBps != nil {
+			spreadSum += *s.SpreadBps
+			spreadCount++
+		}
+	}
+	if imbalanceCount > 0 {
+		avg := imbalanceSum / float64(imbalanceCount)
+		r.Imbalance = &avg
+	}
+	if spreadCount > 0 {
+		avg := spreadSum / float64(spreadCount)
+		r.SpreadBps = &avg
+	}
+
+	return r
+}
+
+// LiquidityChannel is the websocket channel carrying a market's liquidity
+func LiquidityChannel(key orderbook.OrderKey) string {
+	return strings.Join([]string{
+		"liquidity",
+		strings.ToLower(string(key.ContractType)),
+		strconv.FormatFloat(key.StrikeHashRate, 'f', -1, 64),
+		strconv.FormatInt(key.StartBlockHeight, 10),
+		strconv.FormatInt(key.EndBlockHeight, 10),
+	}, ":")
+}
+
+// DepthSource is the order book the liquidity monitor measures
+type DepthSource interface {
+	Depth(key orderbook.OrderKey, levels int) orderbook.Depth
+}
+
+// Publisher pushes messages to subscribers of a websocket channel
+type Publisher interface {
+	PublishToChannel(channel string, message interface{})
+}
+
+// marketLiquidity is the latest measurement of a market and its samples
+type marketLiquidity struct {
+	latest  *Liquidity
+	samples []liquiditySample
+}
+
+// LiquidityMonitor measures the liquidity of each market as it changes,
+// keeps rolling averages and publishes each measurement on the market's
+// liquidity channel
+type LiquidityMonitor struct {
+	book      DepthSource
+	publisher Publisher
+	cfg       LiquidityConfig
+	now       func() time.Time
+
+	mu      sync.Mutex
+	markets map[orderbook.OrderKey]*marketLiquidity
+}
+
+// NewLiquidityMonitor creates a liquidity monitor. The publisher may be nil.
+func NewLiquidityMonitor(book DepthSource, publisher Publisher, cfg LiquidityConfig) *LiquidityMonitor {
+	return &LiquidityMonitor{
+		book:      book,
+		publisher: publisher,
+		cfg:       cfg,
+		now:       time.Now,
+		markets:   make(map[orderbook.OrderKey]*marketLiquidity),
+	}
+}
+
+// OnMarketUpdate measures the updated market. It implements orderbook.MarketObserver.
+func (m *LiquidityMonitor) OnMarketUpdate(ctx context.Context, snapshot orderbook.MarketSnapshot) {
+	now := m.now().UTC()
+	l := measureLiquidity(snapshot.Key, m.book.Depth(snapshot.Key, 0), m.cfg.DepthBands, now)
+
+	m.mu.Lock()
+	market, ok := m.markets[snapshot.Key]
+	if !ok {
+		market = &marketLiquidity{}
+		m.markets[snapshot.Key] = market
+	}
+
+	cutoff := now.Add(-m.cfg.Window)
+	kept := market.samples[:0]
+	for _, s := range market.samples {
+		if s.Time.After(cutoff) {
+			kept = append(kept, s)
+		}
+	}
+	market.samples = append(kept, liquiditySample{Time: now, Imbalance: l.Imbalance, SpreadBps: l.SpreadBps})
+	l.Rolling = rolling(market.samples, m.cfg.Window)
+	market.latest = l
+	m.mu.Unlock()
+
+	if m.publisher != nil {
+		m.publisher.PublishToChannel(LiquidityChannel(snapshot.Key), l)
+	}
+}
+
+// Market returns the latest liquidity of a market, if it has been measured
+func (m *LiquidityMonitor) Market(key orderbook.OrderKey) (*Liquidity, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	market, ok := m.markets[key]
+	if !ok {
+		return nil, false
+	}
+	return market.latest, true
+}
+
+// Markets returns the latest liquidity of every measured market, ordered by
+// contract type, strike and end block height
+func (m *LiquidityMonitor) Markets() []*Liquidity {
+	m.mu.Lock()
+	result := make([]*Liquidity, 0, len(m.markets))
+	for _, market := range m.markets {
+		result = append(result, market.latest)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ContractType != b.ContractType {
+			return a.ContractType < b.ContractType
+		}
+		if a.StrikeHashRate != b.StrikeHashRate {
+			return a.StrikeHashRate < b.StrikeHashRate
+		}
+		if a.EndBlockHeight != b.EndBlockHeight {
+			return a.EndBlockHeight < b.EndBlockHeight
+		}
+		return a.StartBlockHeight < b.StartBlockHeight
+	})
+
+	return result
+}
@@ -0,0 +1,110 @@
+// internal/marketdata/liquidity_test.go
+package marketdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var liquidityKey = orderbook.OrderKey{
+	ContractType:     models.ContractTypeCall,
+	StrikeHashRate:   500,
+	StartBlockHeight: 850000,
+	EndBlockHeight:   852016,
+}
+
+// fixedDepth serves the same depth for every market
+type fixedDepth struct {
+	depth orderbook.Depth
+}
+
+func (f *fixedDepth) Depth(key orderbook.OrderKey, levels int) orderbook.Depth {
+	return f.depth
+}
+
+type recordingPublisher struct {
+	channels []string
+}
+
+func (p *recordingPublisher) PublishToChannel(channel string, message interface{}) {
+	p.channels = append(p.channels, channel)
+}
+
+func TestMeasureLiquidity(t *testing.T) {
+	depth := orderbook.Depth{
+		Bids: []orderbook.PriceLevel{{Price: 990, Quantity: 3}, {Price: 950, Quantity: 5}, {Price: 800, Quantity: 9}},
+		Asks: []orderbook.PriceLevel{{Price: 1010, Quantity: 1}, {Price: 1040, Quantity: 2}},
+	}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	l := measureLiquidity(liquidityKey, depth, []float64{1, 5}, now)
+	require.NotNil(t, l.Mid)
+	assert.Equal(t, 1000.0, *l.Mid)
+	assert.Equal(t, int64(20), *l.Spread)
+	assert.InDelta(t, 200, *l.SpreadBps, 1e-9)
+	assert.InDelta(t, 0.5, *l.Imbalance, 1e-9)
+
+	require.Len(t, l.Bands, 2)
+	assert.Equal(t, 3, l.Bands[0].BidQuantity)
+	assert.Equal(t, 1, l.Bands[0].AskQuantity)
+	assert.Equal(t, 8, l.Bands[1].BidQuantity)
+	assert.Equal(t, 3, l.Bands[1].AskQuantity)
+
+	// With one side empty there is no spread and bands are measured from the best price
+	l = measureLiquidity(liquidityKey, orderbook.Depth{Asks: depth.Asks}, []float64{5}, now)
+	assert.Nil(t, l.Mid)
+	assert.Nil(t, l.SpreadBps)
+	assert.InDelta(t, -1, *l.Imbalance, 1e-9)
+	require.Len(t, l.Bands, 1)
+	assert.Equal(t, 3, l.Bands[0].AskQuantity)
+
+	l = measureLiquidity(liquidityKey, orderbook.Depth{}, []float64{5}, now)
+	assert.Nil(t, l.Imbalance)
+	assert.Empty(t, l.Bands)
+}
+
+func TestLiquidityMonitor(t *testing.T) {
+	book := &fixedDepth{depth: orderbook.Depth{
+		Bids: []orderbook.PriceLevel{{Price: 990, Quantity: 3}},
+		Asks: []orderbook.PriceLevel{{Price: 1010, Quantity: 1}},
+	}}
+	publisher := &recordingPublisher{}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	monitor := NewLiquidityMonitor(book, publisher, LiquidityConfig{Window: time.Minute})
+	monitor.now = func() time.Time { return now }
+
+	_, ok := monitor.Market(liquidityKey)
+	assert.False(t, ok)
+
+	monitor.OnMarketUpdate(context.Background(), orderbook.MarketSnapshot{Key: liquidityKey})
+	book.depth.Asks[0].Quantity = 3
+	now = now.Add(30 * time.Second)
+	monitor.OnMarketUpdate(context.Background(), orderbook.MarketSnapshot{Key: liquidityKey})
+
+	l, ok := monitor.Market(liquidityKey)
+	require.True(t, ok)
+	assert.InDelta(t, 0, *l.Imbalance, 1e-9)
+	assert.Equal(t, 2, l.Rolling.Samples)
+	assert.InDelta(t, 0.25, *l.Rolling.Imbalance, 1e-9)
+
+	// Samples older than the window drop out of the averages
+	now = now.Add(45 * time.Second)
+	monitor.OnMarketUpdate(context.Background(), orderbook.MarketSnapshot{Key: liquidityKey})
+	l, _ = monitor.Market(liquidityKey)
+	assert.Equal(t, 2, l.Rolling.Samples)
+	assert.InDelta(t, 0, *l.Rolling.Imbalance, 1e-9)
+
+	assert.Equal(t, []string{
+		"liquidity:call:500:850000:852016",
+		"liquidity:call:500:850000:852016",
+		"liquidity:call:500:850000:852016",
+	}, publisher.channels)
+	assert.Len(t, monitor.Markets(), 1)
+}
@@ -0,0 +1,259 @@
+// internal/marketdata/ticker.go
+package marketdata
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// TickerChannel is the websocket channel carrying the ticker of every market
+const TickerChannel = "ticker"
+
+// TickerWindow is how far back a ticker's volume, high and low reach. Trades
+// are counted in one minute buckets, so the window moves a minute at a time.
+const TickerWindow = 24 * time.Hour
+
+// tickerBucketWidth is the width of the buckets trades are counted in
+const tickerBucketWidth = time.Minute
+
+// Ticker is the best bid and offer, last trade price and 24 hour volume,
+// high and low of a market
+type Ticker struct {
+	ContractType     models.ContractType `json:"contract_type"`
+	StrikeHashRate   float64             `json:"strike_hash_rate"`
+	StartBlockHeight int64               `json:"start_block_height"`
+	EndBlockHeight   int64               `json:"end_block_height"`
+	BestBid          *int64              `json:"best_bid,omitempty"`
+	BestAsk          *int64              `json:"best_ask,omitempty"`
+	LastPrice        *int64              `json:"last_price,omitempty"`
+	Volume24h        int                 `json:"volume_24h"`
+	Trades24h        int                 `json:"trades_24h"`
+	High24h          *int64              `json:"high_24h,omitempty"`
+	Low24h           *int64              `json:"low_24h,omitempty"`
+	Time             time.Time           `json:"time"`
+}
+
+// tickerBucket sums the trades of a market in one minute
+type tickerBucket struct {
+	start  time.Time
+	volume int
+	trades int
+	high   int64
+	low    int64
+}
+
+// marketTicker is the running state of one market's ticker
+type marketTicker struct {
+	bestBid   *int64
+	bestAsk   *int64
+	lastPrice *int64
+	// buckets holds the trades of the window, oldest first
+	buckets []tickerBucket
+	// dirty is set by trades not yet published with a quote
+	dirty bool
+}
+
+// recordTrade counts a trade in the bucket of its minute. Trades arrive in
+// order per market, so a late timestamp joins the newest bucket.
+func (m *marketTicker) recordTrade(price int64, quantity int, at time.Time) {
+	m.lastPrice = copyPrice(&price)
+	m.dirty = true
+
+	start := at.UTC().Truncate(tickerBucketWidth)
+	if n := len(m.buckets); n > 0 && !start.After(m.buckets[n-1].start) {
+		b := &m.buckets[n-1]
+		b.volume += quantity
+		b.trades++
+		if price > b.high {
+			b.high = price
+		}
+		if price < b.low {
+			b.low = price
+		}
+		return
+	}
+
+	m.buckets = append(m.buckets, tickerBucket{start: start, volume: quantity, trades: 1, high: price, low: price})
+}
+
+// prune drops the buckets that have left the window ending at now
+func (m *marketTicker) prune(now time.Time) {
+	cutoff := now.Add(-TickerWindow)
+	i := 0
+	for i < len(m.buckets) && !m.buckets[i].start.Add(tickerBucketWidth).After(cutoff) {
+		i++
+	}
+	m.buckets = m.buckets[i:]
+}
+
+// ticker sums the window ending at now into the ticker of key
+func (m *marketTicker) ticker(key orderbook.OrderKey, now time.Time) Ticker {
+	m.prune(now)
+
+	t := Ticker{
+		ContractType:     key.ContractType,
+		StrikeHashRate:   key.StrikeHashRate,
+		StartBlockHeight: key.StartBlockHeight,
+		EndBlockHeight:   key.EndBlockHeight,
+		BestBid:          copyPrice(m.bestBid),
+		BestAsk:          copyPrice(m.bestAsk),
+		LastPrice:        copyPrice(m.lastPrice),
+		Time:             now,
+	}
+
+	for _, b := range m.buckets {
+		t.Volume24h += b.volume
+		t.Trades24h += b.trades
+		if t.High24h == nil || b.high > *t.High24h {
+			t.High24h = copyPrice(&b.high)
+		}
+		if t.Low24h == nil || b.low < *t.Low24h {
+			t.Low24h = copyPrice(&b.low)
+		}
+	}
+
+	return t
+}
+
+// copyPrice returns a copy of an optional price
+func copyPrice(price *int64) *int64 {
+	if price == nil {
+		return nil
+	}
+	value := *price
+	return &value
+}
+
+// samePrice reports whether two optional prices are equal
+func samePrice(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// TickerBook keeps the ticker of every market, updated synchronously from
+// the matching path, and publishes each change on the ticker channel
+type TickerBook struct {
+	publisher Publisher
+	now       func() time.Time
+
+	mu      sync.Mutex
+	markets map[orderbook.OrderKey]*marketTicker
+}
+
+// NewTickerBook creates a ticker book. The publisher may be nil.
+func NewTickerBook(publisher Publisher) *TickerBook {
+	return &TickerBook{
+		publisher: publisher,
+		now:       time.Now,
+		markets:   make(map[orderbook.OrderKey]*marketTicker),
+	}
+}
+
+// market returns the ticker state of a market, creating it on first use.
+// The caller must hold b.mu.
+func (b *TickerBook) market(key orderbook.OrderKey) *marketTicker {
+	m, ok := b.markets[key]
+	if !ok {
+		m = &marketTicker{}
+		b.markets[key] = m
+	}
+	return m
+}
+
+// RecordTrade counts a trade. It is published with the quote that follows
+// it. It implements orderbook.TickerRecorder.
+func (b *TickerBook) RecordTrade(key orderbook.OrderKey, price int64, quantity int, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.market(key).recordTrade(price, quantity, at)
+}
+
+// RecordQuote updates the best bid and offer of a market, publishing its
+// ticker if they or its trades changed. It implements orderbook.TickerRecorder.
+func (b *TickerBook) RecordQuote(snapshot orderbook.MarketSnapshot) {
+	b.mu.Lock()
+	m := b.market(snapshot.Key)
+	if !m.dirty && samePrice(m.bestBid, snapshot.BestBid) && samePrice(m.bestAsk, snapshot.BestAsk) {
+		b.mu.Unlock()
+		return
+	}
+
+	m.bestBid = copyPrice(snapshot.BestBid)
+	m.bestAsk = copyPrice(snapshot.BestAsk)
+	m.dirty = false
+	ticker := m.ticker(snapshot.Key, b.now().UTC())
+	b.mu.Unlock()
+
+	// The matching path calls in order per market and holds the market's
+	// lock, so each market's tickers are published in order
+	if b.publisher != nil {
+		b.publisher.PublishToChannel(TickerChannel, tickerMessage(ticker))
+	}
+}
+
+// Ticker returns the ticker of a market, if it has been quoted or traded
+func (b *TickerBook) Ticker(key orderbook.OrderKey) (Ticker, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m, ok := b.markets[key]
+	if !ok {
+		return Ticker{}, false
+	}
+	return m.ticker(key, b.now().UTC()), true
+}
+
+// Tickers returns the ticker of every market, ordered by contract type,
+// strike and end block height
+func (b *TickerBook) Tickers() []Ticker {
+	b.mu.Lock()
+	now := b.now().UTC()
+	tickers := make([]Ticker, 0, len(b.markets))
+	for key, m := range b.markets {
+		tickers = append(tickers, m.ticker(key, now))
+	}
+	b.mu.Unlock()
+
+	sort.Slice(tickers, func(i, j int) bool {
+		a, b := tickers[i], tickers[j]
+		if a.ContractType != b.ContractType {
+			return a.ContractType < b.ContractType
+		}
+		if a.StrikeHashRate != b.StrikeHashRate {
+			return a.StrikeHashRate < b.StrikeHashRate
+		}
+		if a.EndBlockHeight != b.EndBlockHeight {
+			return a.EndBlockHeight < b.EndBlockHeight
+		}
+		return a.StartBlockHeight < b.StartBlockHeight
+	})
+
+	return tickers
+}
+
+// SendSnapshots sends the ticker of every market to a client subscribing to
+// the ticker channel. It implements websocket.Snapshotter.
+func (b *TickerBook) SendSnapshots(channel string, send func(message interface{})) {
+	if channel != TickerChannel {
+		return
+	}
+
+	for _, ticker := range b.Tickers() {
+		send(tickerMessage(ticker))
+	}
+}
+
+// tickerMessage wraps a ticker for the ticker channel
+func tickerMessage(ticker Ticker) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "ticker",
+		"channel": TickerChannel,
+		"payload": ticker,
+	}
+}
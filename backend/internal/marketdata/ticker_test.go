@@ -0,0 +1,85 @@
+// internal/marketdata/ticker_test.go
+package marketdata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+func price(p int64) *int64 {
+	return &p
+}
+
+func TestTickerBook(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 0, 0, 0, time.UTC)
+	publisher := &recordingPublisher{}
+	book := NewTickerBook(publisher)
+	book.now = func() time.Time { return now }
+
+	// Trades older than the window are dropped from the 24 hour figures
+	book.RecordTrade(liquidityKey, 900, 4, now.Add(-25*time.Hour))
+	book.RecordTrade(liquidityKey, 1200, 2, now.Add(-2*time.Hour))
+	book.RecordTrade(liquidityKey, 1000, 1, now.Add(-2*time.Hour))
+	book.RecordTrade(liquidityKey, 1100, 3, now.Add(-time.Minute))
+	assert.Empty(t, publisher.channels, "trades are published with the quote that follows them")
+
+	book.RecordQuote(orderbook.MarketSnapshot{Key: liquidityKey, BestBid: price(1050), BestAsk: price(1150)})
+	assert.Equal(t, []string{TickerChannel}, publisher.channels)
+
+	ticker, ok := book.Ticker(liquidityKey)
+	require.True(t, ok)
+	assert.Equal(t, int64(1050), *ticker.BestBid)
+	assert.Equal(t, int64(1150), *ticker.BestAsk)
+	assert.Equal(t, int64(1100), *ticker.LastPrice)
+	assert.Equal(t, 6, ticker.Volume24h)
+	assert.Equal(t, 3, ticker.Trades24h)
+	assert.Equal(t, int64(1200), *ticker.High24h)
+	assert.Equal(t, int64(1000), *ticker.Low24h)
+
+	// An unchanged quote with no new trades is not published again
+	book.RecordQuote(orderbook.MarketSnapshot{Key: liquidityKey, BestBid: price(1050), BestAsk: price(1150)})
+	assert.Len(t, publisher.channels, 1)
+
+	book.RecordQuote(orderbook.MarketSnapshot{Key: liquidityKey, BestBid: price(1050)})
+	assert.Len(t, publisher.channels, 2)
+
+	// Once every trade has left the window only the last price remains
+	now = now.Add(TickerWindow)
+	ticker, ok = book.Ticker(liquidityKey)
+	require.True(t, ok)
+	assert.Nil(t, ticker.BestAsk)
+	assert.Equal(t, int64(1100), *ticker.LastPrice)
+	assert.Zero(t, ticker.Volume24h)
+	assert.Nil(t, ticker.High24h)
+	assert.Nil(t, ticker.Low24h)
+
+	_, ok = book.Ticker(orderbook.OrderKey{})
+	assert.False(t, ok)
+}
+
+func TestTickerBookSnapshots(t *testing.T) {
+	book := NewTickerBook(nil)
+	put := liquidityKey
+	put.ContractType = models.ContractTypePut
+	book.RecordQuote(orderbook.MarketSnapshot{Key: put, BestBid: price(10)})
+	book.RecordQuote(orderbook.MarketSnapshot{Key: liquidityKey, BestAsk: price(20)})
+
+	var messages []interface{}
+	book.SendSnapshots("orderbook:call:500:850000:852016", func(message interface{}) {
+		messages = append(messages, message)
+	})
+	assert.Empty(t, messages)
+
+	book.SendSnapshots(TickerChannel, func(message interface{}) {
+		messages = append(messages, message)
+	})
+	require.Len(t, messages, 2)
+	first := messages[0].(map[string]interface{})["payload"].(Ticker)
+	assert.Equal(t, liquidityKey.ContractType, first.ContractType, "calls sort before puts")
+}
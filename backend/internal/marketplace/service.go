@@ -0,0 +1,232 @@
+// internal/marketplace/service.go
+package marketplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+var (
+	// ErrNotListable is returned for a position that cannot be sold
+	ErrNotListable = errors.New("position cannot be listed")
+	// ErrNotOwner is returned when a user does not hold the key of a position
+	ErrNotOwner = errors.New("user does not hold the position's key")
+	// ErrOwnListing is returned when a seller tries to buy their own listing
+	ErrOwnListing = errors.New("cannot buy your own listing")
+)
+
+// Contracts is the contract service as used by the marketplace
+type Contracts interface {
+	GetContract(ctx context.Context, id uuid.UUID) (*models.Contract, error)
+	SwapContractParticipant(ctx context.Context, contractID uuid.UUID, currentPubKey, newPubKey, newParticipantInput string) (*models.ContractTransaction, error)
+}
+
+// Keys looks up the public keys registered to a user
+type Keys interface {
+	GetKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserKey, error)
+}
+
+// MarginPositions looks up the margin posted for a contract
+type MarginPositions interface {
+	GetByContract(ctx context.Context, contractID uuid.UUID) (*models.MarginPosition, error)
+}
+
+// Service is the secondary market for contract positions. A holder lists
+// their side of an active contract at a price; a buyer accepting it has the
+// price reserved from their margin account, the contract's participant
+// swapped to the buyer's key out of round, and the price paid to the seller.
+type Service struct {
+	repo      *db.ListingRepository
+	contracts Contracts
+	keys      Keys
+	margins   MarginPositions
+}
+
+// NewService creates a new marketplace service. Margins may be nil.
+func NewService(repo *db.ListingRepository, contracts Contracts, keys Keys, margins MarginPositions) *Service {
+	return &Service{
+		repo:      repo,
+		contracts: contracts,
+		keys:      keys,
+		margins:   margins,
+	}
+}
+
+// partyOf returns the side of a contract held by one of pubKeys
+func partyOf(contract *models.Contract, pubKeys []string) (models.ContractParty, string, bool) {
+	for _, key := range pubKeys {
+		switch key {
+		case contract.BuyerPubKey:
+			return models.ContractPartyBuyer, key, true
+		case contract.SellerPubKey:
+			return models.ContractPartySeller, key, true
+		}
+	}
+	return "", "", false
+}
+
+// pubKeys returns the public keys registered to a user
+func (s *Service) pubKeys(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	keys, err := s.keys.GetKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user keys: %w", err)
+	}
+
+	pubKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pubKeys = append(pubKeys, key.PubKey)
+	}
+	return pubKeys, nil
+}
+
+// List puts a user's side of an active contract up for sale at a price
+func (s *Service) List(ctx context.Context, userID, contractID uuid.UUID, price int64) (*models.ContractListing, error) {
+	if price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive", ErrNotListable)
+	}
+
+	contract, err := s.contracts.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Status != models.ContractStatusActive {
+		return nil, fmt.Errorf("%w: contract is not active", ErrNotListable)
+	}
+
+	pubKeys, err := s.pubKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	party, pubKey, ok := partyOf(contract, pubKeys)
+	if !ok {
+		return nil, ErrNotOwner
+	}
+
+	// Margin is posted by the writer's account, so a margined side cannot
+	// change hands until its position is closed
+	if party == models.ContractPartySeller && s.margins != nil {
+		position, err := s.margins.GetByContract(ctx, contractID)
+		switch {
+		case err == nil && (position.Status == models.MarginStatusOpen || position.Status == models.MarginStatusCalled):
+			return nil, fmt.Errorf("%w: contract is margined", ErrNotListable)
+		case err != nil && !errors.Is(err, db.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	listing := &models.ContractListing{
+		ContractID: contractID,
+		SellerID:   userID,
+		Party:      party,
+		PubKey:     pubKey,
+		Price:      price,
+	}
+	if err := s.repo.Create(ctx, listing); err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Str("listingID", listing.ID.String()).
+		Str("contractID", contractID.String()).
+		Str("party", string(party)).
+		Int64("price", price).
+		Msg("Contract position listed")
+
+	return listing, nil
+}
+
+// Browse returns a page of open listings, optionally of one contract
+func (s *Service) Browse(ctx context.Context, contractID *uuid.UUID, limit, offset int) ([]*models.ContractListing, error) {
+	return s.repo.ListOpen(ctx, contractID, limit, offset)
+}
+
+// Mine returns a page of a user's listings
+func (s *Service) Mine(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.ContractListing, error) {
+	return s.repo.ListBySeller(ctx, userID, limit, offset)
+}
+
+// Get returns a listing
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*models.ContractListing, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// Cancel withdraws a user's open listing
+func (s *Service) Cancel(ctx context.Context, userID, id uuid.UUID) (*models.ContractListing, error) {
+	return s.repo.Cancel(ctx, id, userID)
+}
+
+// Buy accepts a listing for a user, who takes over the position with
+// pubKey. The price is reserved from the buyer's margin account before the
+// participant swap and paid to the seller once it succeeds; a failed swap
+// reopens the listing and returns the reservation.
+func (s *Service) Buy(ctx context.Context, userID, id uuid.UUID, pubKey string) (*models.ContractListing, error) {
+	listing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if listing.SellerID == userID {
+		return nil, ErrOwnListing
+	}
+
+	pubKeys, err := s.pubKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	owned := false
+	for _, key := range pubKeys {
+		if key == pubKey {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return nil, ErrNotOwner
+	}
+
+	listing, err = s.repo.Claim(ctx, id, userID, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.contracts.SwapContractParticipant(ctx, listing.ContractID, listing.PubKey, pubKey, "")
+	if err != nil {
+		if releaseErr := s.repo.Release(ctx, listing); releaseErr != nil {
+			logger.Error().Err(releaseErr).
+				Str("listingID", listing.ID.String()).
+				Msg("Failed to release listing after failed swap")
+		}
+		return nil, fmt.Errorf("failed to swap contract participant: %w", err)
+	}
+
+	// The swap is on the ARK and cannot be undone, so a failure to record
+	// the payment leaves the listing pending for reconciliation
+	if err := s.repo.Complete(ctx, listing, tx.TransactionID); err != nil {
+		logger.Error().Err(err).
+			Str("listingID", listing.ID.String()).
+			Str("swapTxID", tx.TransactionID).
+			Msg("Contract swapped but listing payment was not recorded")
+		return nil, fmt.Errorf("failed to complete listing: %w", err)
+	}
+
+	listing, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Str("listingID", listing.ID.String()).
+		Str("contractID", listing.ContractID.String()).
+		Str("swapTxID", tx.TransactionID).
+		Msg("Contract position sold")
+
+	return listing, nil
+}
@@ -0,0 +1,30 @@
+// internal/marketplace/service_test.go
+package marketplace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestPartyOf(t *testing.T) {
+	contract := &models.Contract{BuyerPubKey: "buyer", SellerPubKey: "seller"}
+
+	party, key, ok := partyOf(contract, []string{"other", "seller"})
+	assert.True(t, ok)
+	assert.Equal(t, models.ContractPartySeller, party)
+	assert.Equal(t, "seller", key)
+
+	party, key, ok = partyOf(contract, []string{"buyer"})
+	assert.True(t, ok)
+	assert.Equal(t, models.ContractPartyBuyer, party)
+	assert.Equal(t, "buyer", key)
+
+	_, _, ok = partyOf(contract, []string{"other"})
+	assert.False(t, ok)
+
+	_, _, ok = partyOf(contract, nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,223 @@
+// internal/metrics/metrics.go
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+// namespace prefixes every metric name
+const namespace = "hashhedge"
+
+// collectTimeout bounds the database queries made while scraping
+const collectTimeout = 5 * time.Second
+
+// Order book metrics
+var (
+	OrdersPlaced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "orders_placed_total",
+		Help:      "Orders accepted into the order book, by side and type.",
+	}, []string{"side", "type"})
+
+	OrdersMatched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "orders_matched_total",
+		Help:      "Placed orders that matched at least once, by side and type.",
+	}, []string{"side", "type"})
+
+	Trades = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "trades_total",
+		Help:      "Trades executed, each creating a contract.",
+	})
+
+	PlaceOrderLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "place_order_duration_seconds",
+		Help:      "Time to accept and match a placed order, by type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type"})
+
+	BookDivergences = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "divergences_total",
+		Help:      "Orders repaired by reconciling the in-memory book with the database, by kind.",
+	}, []string{"kind"})
+
+	ClaimedMarkets = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "orderbook",
+		Name:      "claimed_markets",
+		Help:      "Markets this instance matches while coordinating with other instances.",
+	})
+)
+
+// HTTP metrics
+var (
+	RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Requests rejected by the per-client rate limits, by class.",
+	}, []string{"class"})
+)
+
+// Event bus metrics
+var (
+	EventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "dropped_total",
+		Help:      "Events dropped because a subscriber fell behind, by topic.",
+	}, []string{"topic"})
+)
+
+// Webhook metrics
+var (
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "deliveries_total",
+		Help:      "Webhook delivery attempts, by outcome.",
+	}, []string{"outcome"})
+)
+
+// Contract metrics
+var (
+	Settlements = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "contract",
+		Name:      "settlements_total",
+		Help:      "Contracts settled, manually or by the settlement oracle.",
+	})
+
+	SettlementAttempts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "contract",
+		Name:      "settlement_attempts_total",
+		Help:      "Automatic settlement attempts, by outcome.",
+	}, []string{"outcome"})
+
+	BroadcastRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "contract",
+		Name:      "broadcast_rejections_total",
+		Help:      "Transactions rejected before broadcast, by failed check.",
+	}, []string{"reason"})
+)
+
+// ASP client metrics
+var (
+	ArkRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ark",
+		Name:      "retries_total",
+		Help:      "Retried calls to the ASP, by operation.",
+	}, []string{"operation"})
+
+	ArkStreamReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ark",
+		Name:      "stream_reconnects_total",
+		Help:      "Reconnections of the ASP transaction stream, by whether the stream was restored.",
+	}, []string{"result"})
+
+	ASPUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ark",
+		Name:      "asp_up",
+		Help:      "Whether the last probe of an ASP succeeded, by ASP.",
+	}, []string{"asp"})
+
+	ASPFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ark",
+		Name:      "failovers_total",
+		Help:      "Failovers away from an unreachable primary ASP, by the ASP left.",
+	}, []string{"asp"})
+)
+
+// Bitcoin client metrics
+var (
+	BitcoinRPCErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "bitcoin",
+		Name:      "rpc_errors_total",
+		Help:      "Failed Bitcoin Core RPC calls, by method.",
+	}, []string{"method"})
+
+	BitcoinNodeUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "bitcoin",
+		Name:      "node_up",
+		Help:      "Whether the last health check of a Bitcoin Core node succeeded, by node.",
+	}, []string{"node"})
+
+	BitcoinRPCFailovers = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "bitcoin",
+		Name:      "rpc_failovers_total",
+		Help:      "Bitcoin Core RPC calls moved off a node that could not be reached, by the node left.",
+	}, []string{"node"})
+)
+
+// StatusCounter counts records by status, such as the contracts in each state
+type StatusCounter func(ctx context.Context) (map[string]int, error)
+
+// statusCollector reports the counts of a StatusCounter as a gauge when scraped
+type statusCollector struct {
+	desc  *prometheus.Desc
+	count StatusCounter
+}
+
+// Describe implements prometheus.Collector
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector. A failed count is logged and
+// leaves the gauge out of the scrape rather than reporting zeros.
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("metric", c.desc.String()).Msg("Failed to collect status counts")
+		return
+	}
+
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), status)
+	}
+}
+
+// RegisterContractCounter reports the number of contracts in each status,
+// counted when the metrics are scraped
+func RegisterContractCounter(count StatusCounter) error {
+	return prometheus.Register(&statusCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "contract", "contracts"),
+			"Contracts by status.",
+			[]string{"status"}, nil,
+		),
+		count: count,
+	})
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
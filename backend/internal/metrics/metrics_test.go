@@ -0,0 +1,33 @@
+// internal/metrics/metrics_test.go
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCollector(t *testing.T) {
+	counts := map[string]int{"ACTIVE": 3, "SETTLED": 7}
+	var countErr error
+
+	collector := &statusCollector{
+		desc: prometheus.NewDesc("test_contracts", "Contracts by status.", []string{"status"}, nil),
+		count: func(ctx context.Context) (map[string]int, error) {
+			return counts, countErr
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	assert.Equal(t, 2, testutil.CollectAndCount(collector))
+
+	// A failed count reports nothing rather than zeros
+	countErr = errors.New("database unavailable")
+	assert.Equal(t, 0, testutil.CollectAndCount(collector))
+}
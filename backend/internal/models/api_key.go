@@ -0,0 +1,57 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix marks HashHedge API keys so they are recognisable in logs and secret scanners
+const APIKeyPrefix = "hh_"
+
+// APIKey identifies an API client of a user. Only a hash of the key is stored.
+type APIKey struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Name      string     `json:"name" db:"name"`
+	Prefix    string     `json:"prefix" db:"prefix"` // First characters of the key, for display
+	KeyHash   string     `json:"-" db:"key_hash"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Validate checks if the API key is valid
+func (k *APIKey) Validate() error {
+	if k.UserID == uuid.Nil {
+		return errors.New("user ID cannot be empty")
+	}
+
+	if k.Name == "" || len(k.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+
+	return nil
+}
+
+// GenerateAPIKey returns a new random key and sets the hash and display prefix on k
+func (k *APIKey) GenerateAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	key := APIKeyPrefix + hex.EncodeToString(secret)
+	k.KeyHash = HashAPIKey(key)
+	k.Prefix = key[:len(APIKeyPrefix)+8]
+	return key, nil
+}
+
+// HashAPIKey returns the stored form of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audited resource types
+const (
+	AuditResourceContract = "contract"
+	AuditResourceOrder    = "order"
+)
+
+// AuditEntry records a state-changing API call: who made it, what they
+// sent, and the resource before and after the call
+type AuditEntry struct {
+	ID           uuid.UUID       `json:"id" db:"id"`
+	UserID       *uuid.UUID      `json:"user_id,omitempty" db:"user_id"`
+	APIKeyID     *uuid.UUID      `json:"api_key_id,omitempty" db:"api_key_id"`
+	Method       string          `json:"method" db:"method"`
+	Path         string          `json:"path" db:"path"`
+	ResourceType string          `json:"resource_type" db:"resource_type"`
+	ResourceID   *uuid.UUID      `json:"resource_id,omitempty" db:"resource_id"`
+	Request      json.RawMessage `json:"request,omitempty" db:"request"`
+	Before       json.RawMessage `json:"before,omitempty" db:"before"`
+	After        json.RawMessage `json:"after,omitempty" db:"after"`
+	Status       int             `json:"status" db:"status"`
+	RemoteAddr   string          `json:"remote_addr" db:"remote_addr"`
+	RequestID    string          `json:"request_id" db:"request_id"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+}
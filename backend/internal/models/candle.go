@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MarketTrade is a trade with the market it traded in
+type MarketTrade struct {
+	ID               uuid.UUID    `json:"id" db:"id"`
+	ContractID       uuid.UUID    `json:"contract_id" db:"contract_id"`
+	ContractType     ContractType `json:"contract_type" db:"contract_type"`
+	StrikeHashRate   float64      `json:"strike_hash_rate" db:"strike_hash_rate"`
+	StartBlockHeight int64        `json:"start_block_height" db:"start_block_height"`
+	EndBlockHeight   int64        `json:"end_block_height" db:"end_block_height"`
+	Price            int64        `json:"price" db:"price"`
+	Quantity         int          `json:"quantity" db:"quantity"`
+	PriceRule        PriceRule    `json:"price_rule" db:"price_rule"`
+	ExecutedAt       time.Time    `json:"executed_at" db:"executed_at"`
+}
+
+// Candle is the open, high, low and close price and the traded volume of a
+// market over one time bucket. Buckets without trades have no candle.
+type Candle struct {
+	Start  time.Time `json:"start" db:"bucket_start"`
+	Open   int64     `json:"open" db:"open"`
+	High   int64     `json:"high" db:"high"`
+	Low    int64     `json:"low" db:"low"`
+	Close  int64     `json:"close" db:"close"`
+	Volume int64     `json:"volume" db:"volume"` // In contracts
+	Trades int       `json:"trades" db:"trades"`
+}
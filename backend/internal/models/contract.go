@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,11 +22,37 @@ const (
 type ContractStatus string
 
 const (
-	ContractStatusCreated    ContractStatus = "CREATED"
-	ContractStatusActive     ContractStatus = "ACTIVE"
-	ContractStatusSettled    ContractStatus = "SETTLED"
-	ContractStatusExpired    ContractStatus = "EXPIRED"
-	ContractStatusCancelled  ContractStatus = "CANCELLED"
+	ContractStatusCreated           ContractStatus = "CREATED"
+	ContractStatusActive            ContractStatus = "ACTIVE"
+	ContractStatusPendingSettlement ContractStatus = "PENDING_SETTLEMENT" // Payout broadcast, awaiting confirmation
+	ContractStatusSettled           ContractStatus = "SETTLED"
+	ContractStatusExpired           ContractStatus = "EXPIRED"
+	ContractStatusCancelled         ContractStatus = "CANCELLED"
+)
+
+// ContractTimeMode is how the outcome paths of a contract are timed
+type ContractTimeMode string
+
+const (
+	// ContractTimeModeAbsolute locks the outcome paths to the end block
+	// height and target timestamp (OP_CHECKLOCKTIMEVERIFY)
+	ContractTimeModeAbsolute ContractTimeMode = "ABSOLUTE"
+	// ContractTimeModeRelative locks the outcome paths to RelativeBlocks and
+	// RelativeSeconds after the setup transaction confirms
+	// (OP_CHECKSEQUENCEVERIFY), for contracts that start on activation. The
+	// end block height and target time are set once the setup confirms.
+	ContractTimeModeRelative ContractTimeMode = "RELATIVE"
+)
+
+// Limits of BIP-68 relative lock times
+const (
+	// MaxRelativeBlocks is the longest relative lock in blocks
+	MaxRelativeBlocks = 0xffff
+	// RelativeTimeGranularity is the unit in seconds that relative time
+	// locks are rounded up to
+	RelativeTimeGranularity = 512
+	// MaxRelativeSeconds is the longest relative lock in seconds
+	MaxRelativeSeconds = MaxRelativeBlocks * RelativeTimeGranularity
 )
 
 // Contract represents a hash rate binary option contract
@@ -36,10 +63,16 @@ type Contract struct {
 	StartBlockHeight int64           `json:"start_block_height" db:"start_block_height"`
 	EndBlockHeight   int64           `json:"end_block_height" db:"end_block_height"`
 	TargetTimestamp  time.Time       `json:"target_timestamp" db:"target_timestamp"`
+	TimeMode         ContractTimeMode `json:"time_mode" db:"time_mode"`
+	RelativeBlocks   int64           `json:"relative_blocks,omitempty" db:"relative_blocks"`
+	RelativeSeconds  int64           `json:"relative_seconds,omitempty" db:"relative_seconds"`
 	ContractSize     int64           `json:"contract_size" db:"contract_size"` // In satoshis
 	Premium          int64           `json:"premium" db:"premium"`             // In satoshis
 	BuyerPubKey      string          `json:"buyer_pub_key" db:"buyer_pub_key"`
 	SellerPubKey     string          `json:"seller_pub_key" db:"seller_pub_key"`
+	BuyerKeyID       *uuid.UUID      `json:"buyer_key_id,omitempty" db:"buyer_key_id"`
+	SellerKeyID      *uuid.UUID      `json:"seller_key_id,omitempty" db:"seller_key_id"`
+	Notional         `json:"notional"`
 	Status           ContractStatus  `json:"status" db:"status"`
 	CreatedAt        time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time       `json:"updated_at" db:"updated_at"`
@@ -87,9 +120,68 @@ func (c *Contract) Validate() error {
 		return errors.New("seller public key cannot be empty")
 	}
 
+	if err := c.Notional.Validate(); err != nil {
+		return err
+	}
+
+	switch c.TimeMode {
+	case "", ContractTimeModeAbsolute:
+	case ContractTimeModeRelative:
+		if err := ValidateRelativeTimelock(c.RelativeBlocks, c.RelativeSeconds); err != nil {
+			return err
+		}
+	default:
+		return errors.New("invalid time mode")
+	}
+
+	return nil
+}
+
+// ValidateRelativeTimelock checks the block count and seconds of a relative
+// contract fit in a BIP-68 relative lock
+func ValidateRelativeTimelock(blocks, seconds int64) error {
+	if blocks <= 0 || blocks > MaxRelativeBlocks {
+		return fmt.Errorf("relative blocks must be between 1 and %d", MaxRelativeBlocks)
+	}
+	if seconds <= 0 || seconds > MaxRelativeSeconds {
+		return fmt.Errorf("relative seconds must be between 1 and %d", MaxRelativeSeconds)
+	}
 	return nil
 }
 
+// IsRelative reports whether the contract's outcome paths are timed from
+// the confirmation of its setup transaction
+func (c *Contract) IsRelative() bool {
+	return c.TimeMode == ContractTimeModeRelative
+}
+
+// RelativeDuration is the relative time lock of the contract, rounded up to
+// the granularity the lock is encoded with
+func (c *Contract) RelativeDuration() time.Duration {
+	units := (c.RelativeSeconds + RelativeTimeGranularity - 1) / RelativeTimeGranularity
+	return time.Duration(units*RelativeTimeGranularity) * time.Second
+}
+
+// Anchor sets the schedule of a relative contract whose setup confirmed at
+// height and time: it ends RelativeBlocks after the setup block, and its
+// target time and expiry follow the relative time lock
+func (c *Contract) Anchor(height int64, confirmedAt time.Time) {
+	c.StartBlockHeight = height
+	c.EndBlockHeight = height + c.RelativeBlocks
+	c.TargetTimestamp = confirmedAt.Add(c.RelativeDuration()).UTC()
+	c.ExpiresAt = c.TargetTimestamp.Add(24 * time.Hour)
+}
+
+// SizePerUnit is the contract size in satoshis per notional unit
+func (c *Contract) SizePerUnit() float64 {
+	return c.Notional.PerUnit(c.ContractSize)
+}
+
+// PremiumPerUnit is the premium in satoshis per notional unit
+func (c *Contract) PremiumPerUnit() float64 {
+	return c.Notional.PerUnit(c.Premium)
+}
+
 // CanBeActivated checks if a contract can be activated
 func (c *Contract) CanBeActivated() bool {
 	return c.Status == ContractStatusCreated
@@ -116,8 +208,11 @@ type ContractTransaction struct {
 	ID            uuid.UUID   `json:"id" db:"id"`
 	ContractID    uuid.UUID   `json:"contract_id" db:"contract_id"`
 	TransactionID string      `json:"transaction_id" db:"transaction_id"`
-	TxType        string      `json:"tx_type" db:"tx_type"` // setup, final, settlement
+	TxType        string      `json:"tx_type" db:"tx_type"` // setup, final, settlement, swap, close
 	TxHex         string      `json:"tx_hex" db:"tx_hex"`
+	// SpendPSBT is the unsigned transaction with the script path spend of
+	// each input, for the parties to sign; nil for transactions signed elsewhere
+	SpendPSBT     *string     `json:"spend_psbt,omitempty" db:"spend_psbt"`
 	Confirmed     bool        `json:"confirmed" db:"confirmed"`
 	CreatedAt     time.Time   `json:"created_at" db:"created_at"`
 	ConfirmedAt   *time.Time  `json:"confirmed_at,omitempty" db:"confirmed_at"`
@@ -137,7 +232,7 @@ func (tx *ContractTransaction) Validate() error {
 		return errors.New("transaction type cannot be empty")
 	}
 
-	if tx.TxType != "setup" && tx.TxType != "final" && tx.TxType != "settlement" && tx.TxType != "swap" {
+	if tx.TxType != "setup" && tx.TxType != "final" && tx.TxType != "settlement" && tx.TxType != "swap" && tx.TxType != "close" {
 		return errors.New("invalid transaction type")
 	}
 
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InputStage is the contract output an input locks funds into
+type InputStage string
+
+const (
+	// InputStageSetup inputs are locked in the setup script and spent by the final transaction
+	InputStageSetup InputStage = "setup"
+	// InputStageFinal inputs are locked in the final script and spent by the settlement transaction
+	InputStageFinal InputStage = "final"
+)
+
+// InputSource is where a contract input comes from
+type InputSource string
+
+const (
+	InputSourceUTXO InputSource = "utxo"
+	InputSourceVTXO InputSource = "vtxo"
+)
+
+// ContractInput is one UTXO or VTXO funding a contract. A contract funded by
+// several partial fills has one input per fill, all swept together.
+type ContractInput struct {
+	ID         uuid.UUID   `json:"id" db:"id"`
+	ContractID uuid.UUID   `json:"contract_id" db:"contract_id"`
+	Stage      InputStage  `json:"stage" db:"stage"`
+	Source     InputSource `json:"source" db:"source"`
+	TxID       string      `json:"txid" db:"txid"`
+	Vout       uint32      `json:"vout" db:"vout"`
+	Value      int64       `json:"value" db:"value"` // In satoshis
+	// LeafIndex selects the script path the input is spent through. When nil,
+	// the fee is sized for the heaviest leaf of the input's script tree.
+	LeafIndex *int      `json:"leaf_index,omitempty" db:"leaf_index"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate checks if the contract input is valid
+func (i *ContractInput) Validate() error {
+	if i.ContractID == uuid.Nil {
+		return errors.New("contract ID cannot be empty")
+	}
+
+	if i.Stage != InputStageSetup && i.Stage != InputStageFinal {
+		return errors.New("invalid input stage")
+	}
+
+	if i.Source != InputSourceUTXO && i.Source != InputSourceVTXO {
+		return errors.New("invalid input source")
+	}
+
+	if len(i.TxID) != 64 {
+		return errors.New("transaction ID must be 64 hex characters")
+	}
+
+	if i.Value <= 0 {
+		return errors.New("value must be positive")
+	}
+
+	if i.LeafIndex != nil && *i.LeafIndex < 0 {
+		return errors.New("leaf index cannot be negative")
+	}
+
+	return nil
+}
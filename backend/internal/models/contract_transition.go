@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContractTransition records a contract moving from one status to another.
+// FromStatus is empty for the contract's creation.
+type ContractTransition struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	ContractID uuid.UUID      `json:"contract_id" db:"contract_id"`
+	FromStatus ContractStatus `json:"from_status" db:"from_status"`
+	ToStatus   ContractStatus `json:"to_status" db:"to_status"`
+	Reason     string         `json:"reason" db:"reason"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
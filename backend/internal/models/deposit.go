@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DepositAddress is the taproot address a user funds their balance through,
+// derived from the platform's extended public key
+type DepositAddress struct {
+	UserID          uuid.UUID `json:"user_id" db:"user_id"`
+	DerivationIndex int64     `json:"derivation_index" db:"derivation_index"` // Child of the external chain
+	Address         string    `json:"address" db:"address"`
+	ScriptPubKey    string    `json:"-" db:"script_pub_key"` // Hex encoded output script
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// DepositStatus is the progress of an on-chain deposit
+type DepositStatus string
+
+const (
+	DepositStatusPending  DepositStatus = "PENDING"  // Seen in a block, awaiting confirmations
+	DepositStatusCredited DepositStatus = "CREDITED" // Credited to the user's balance
+)
+
+// Deposit is an output paying a user's deposit address
+type Deposit struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	UserID      uuid.UUID     `json:"user_id" db:"user_id"`
+	TxID        string        `json:"txid" db:"txid"`
+	Vout        int64         `json:"vout" db:"vout"`
+	Amount      int64         `json:"amount" db:"amount"` // In satoshis
+	BlockHash   string        `json:"block_hash" db:"block_hash"`
+	BlockHeight int64         `json:"block_height" db:"block_height"`
+	Status      DepositStatus `json:"status" db:"status"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	CreditedAt  *time.Time    `json:"credited_at,omitempty" db:"credited_at"`
+}
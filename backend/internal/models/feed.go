@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// FeedObservation is a single value sampled from an external data feed
+type FeedObservation struct {
+	ID         int64     `json:"id" db:"id"`
+	Feed       string    `json:"feed" db:"feed"`
+	Series     string    `json:"series" db:"series"`
+	Value      float64   `json:"value" db:"value"`
+	ObservedAt time.Time `json:"observed_at" db:"observed_at"`
+}
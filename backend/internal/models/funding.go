@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContractFunding tracks which parties have contributed and signed their setup inputs
+type ContractFunding struct {
+	ContractID   uuid.UUID `json:"contract_id" db:"contract_id"`
+	BuyerFunded  bool      `json:"buyer_funded" db:"buyer_funded"`
+	SellerFunded bool      `json:"seller_funded" db:"seller_funded"`
+	BuyerSigned  bool      `json:"buyer_signed" db:"buyer_signed"`
+	SellerSigned bool      `json:"seller_signed" db:"seller_signed"`
+	BuyerPSBT    *string   `json:"-" db:"buyer_psbt"`
+	SellerPSBT   *string   `json:"-" db:"seller_psbt"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SignaturesCollected returns how many of the two parties have signed
+func (f *ContractFunding) SignaturesCollected() int {
+	count := 0
+	if f.BuyerSigned {
+		count++
+	}
+	if f.SellerSigned {
+		count++
+	}
+	return count
+}
+
+// IsComplete reports whether both parties have funded and signed
+func (f *ContractFunding) IsComplete() bool {
+	return f.BuyerFunded && f.SellerFunded && f.BuyerSigned && f.SellerSigned
+}
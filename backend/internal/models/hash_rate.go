@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// HashRateObservation is the network hash rate recorded at a block, in EH/s.
+// Averages are nil when the chain was shorter than their window.
+type HashRateObservation struct {
+	ID          int64     `json:"id" db:"id"`
+	BlockHeight int64     `json:"block_height" db:"block_height"`
+	BlockTime   time.Time `json:"block_time" db:"block_time"`
+	HashRate    float64   `json:"hash_rate" db:"hash_rate"`
+	Average1d   *float64  `json:"average_1d,omitempty" db:"average_1d"`
+	Average7d   *float64  `json:"average_7d,omitempty" db:"average_7d"`
+	Average30d  *float64  `json:"average_30d,omitempty" db:"average_30d"`
+	ObservedAt  time.Time `json:"observed_at" db:"observed_at"`
+}
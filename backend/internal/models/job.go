@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobStatus represents the current state of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusCompleted JobStatus = "COMPLETED"
+	// JobStatusDead marks a job that exhausted its retries and waits for a manual replay
+	JobStatusDead JobStatus = "DEAD"
+)
+
+// Job represents a persisted background job
+type Job struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	JobType     string          `json:"job_type" db:"job_type"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Status      JobStatus       `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"`
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	LastError   *string         `json:"last_error,omitempty" db:"last_error"`
+	RunAt       time.Time       `json:"run_at" db:"run_at"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty" db:"locked_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty" db:"completed_at"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerAccountType is the purpose of a ledger account
+type LedgerAccountType string
+
+const (
+	// Funds a user may trade or withdraw
+	LedgerAccountAvailable LedgerAccountType = "AVAILABLE"
+	// Collateral a user has committed to contracts they wrote
+	LedgerAccountCommitted LedgerAccountType = "COMMITTED"
+	// The platform's side of deposits and withdrawals; the only account
+	// whose balance may go negative
+	LedgerAccountExternal LedgerAccountType = "EXTERNAL"
+)
+
+// JournalType is the kind of movement a journal entry records
+type JournalType string
+
+const (
+	JournalDeposit    JournalType = "DEPOSIT"
+	JournalWithdrawal JournalType = "WITHDRAWAL"
+	JournalPremium    JournalType = "PREMIUM"    // Buyer pays the seller
+	JournalCollateral JournalType = "COLLATERAL" // Seller commits a contract's size
+	JournalPayout     JournalType = "PAYOUT"     // Settlement pays the committed collateral to the winner
+	JournalReversal   JournalType = "REVERSAL"   // Refunds a withdrawal that was not paid out
+)
+
+// LedgerAccount is a user's or the platform's account in the ledger
+type LedgerAccount struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	UserID    *uuid.UUID        `json:"user_id,omitempty" db:"user_id"`
+	Type      LedgerAccountType `json:"type" db:"type"`
+	Balance   int64             `json:"balance" db:"balance"` // In satoshis
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// LedgerPosting moves amount into an account; negative amounts move it out.
+// UserID is nil for the platform's accounts.
+type LedgerPosting struct {
+	UserID      *uuid.UUID        `json:"user_id,omitempty" db:"user_id"`
+	AccountType LedgerAccountType `json:"account_type" db:"account_type"`
+	Amount      int64             `json:"amount" db:"amount"`
+}
+
+// Journal is a balanced set of postings recorded together. Reference, when
+// set, is unique, so an event is never booked twice.
+type Journal struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	Type       JournalType     `json:"type" db:"type"`
+	ContractID *uuid.UUID      `json:"contract_id,omitempty" db:"contract_id"`
+	Reference  *string         `json:"reference,omitempty" db:"reference"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	Postings   []LedgerPosting `json:"postings" db:"-"`
+}
+
+// LedgerEntry is one posting to a user's account, with its journal
+type LedgerEntry struct {
+	JournalID   uuid.UUID         `json:"journal_id" db:"journal_id"`
+	Type        JournalType       `json:"type" db:"type"`
+	ContractID  *uuid.UUID        `json:"contract_id,omitempty" db:"contract_id"`
+	AccountType LedgerAccountType `json:"account_type" db:"account_type"`
+	Amount      int64             `json:"amount" db:"amount"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+}
+
+// Balance is a user's funds. Held is reserved by the user's resting orders
+// and is not booked; Tradable is what new orders may still use.
+type Balance struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Available int64     `json:"available" db:"available"`
+	Held      int64     `json:"held" db:"held"`
+	Tradable  int64     `json:"tradable" db:"-"`
+	Committed int64     `json:"committed" db:"committed"`
+}
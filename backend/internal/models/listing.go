@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ContractParty is one side of a contract
+type ContractParty string
+
+const (
+	// ContractPartyBuyer holds the contract's long side
+	ContractPartyBuyer ContractParty = "BUYER"
+	// ContractPartySeller wrote the contract
+	ContractPartySeller ContractParty = "SELLER"
+)
+
+// ListingStatus represents the state of a contract position listed for sale
+type ListingStatus string
+
+const (
+	// ListingStatusOpen listings can be bought
+	ListingStatusOpen ListingStatus = "OPEN"
+	// ListingStatusPending listings were accepted by a buyer whose payment is
+	// reserved while the participant swap executes
+	ListingStatusPending ListingStatus = "PENDING"
+	// ListingStatusSold listings swapped the position to the buyer and paid the seller
+	ListingStatusSold ListingStatus = "SOLD"
+	// ListingStatusCancelled listings were withdrawn by the seller
+	ListingStatusCancelled ListingStatus = "CANCELLED"
+)
+
+// ContractListing offers one side of an active contract for sale on the
+// secondary market. The position is held under PubKey; a sale swaps that
+// key for the buyer's and pays Price from the buyer's margin account to
+// the seller's.
+type ContractListing struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	ContractID  uuid.UUID     `json:"contract_id" db:"contract_id"`
+	SellerID    uuid.UUID     `json:"seller_id" db:"seller_id"`
+	Party       ContractParty `json:"party" db:"party"`
+	PubKey      string        `json:"pub_key" db:"pub_key"`
+	Price       int64         `json:"price" db:"price"` // In satoshis
+	Status      ListingStatus `json:"status" db:"status"`
+	BuyerID     *uuid.UUID    `json:"buyer_id,omitempty" db:"buyer_id"`
+	BuyerPubKey *string       `json:"buyer_pub_key,omitempty" db:"buyer_pub_key"`
+	SwapTxID    *string       `json:"swap_tx_id,omitempty" db:"swap_tx_id"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+	SoldAt      *time.Time    `json:"sold_at,omitempty" db:"sold_at"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MarginAccount is the collateral a user holds with the platform to margin
+// the contracts they write. Locked collateral backs open margin positions
+// and cannot be withdrawn.
+type MarginAccount struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Balance   int64     `json:"balance" db:"balance"` // In satoshis
+	Locked    int64     `json:"locked" db:"locked"`   // In satoshis
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Free is the collateral available to open positions or withdraw
+func (a *MarginAccount) Free() int64 {
+	return a.Balance - a.Locked
+}
+
+// MarginStatus is the state of a margin position
+type MarginStatus string
+
+const (
+	// The position is above its maintenance margin
+	MarginStatusOpen MarginStatus = "OPEN"
+	// The position fell below its maintenance margin and must be topped up
+	MarginStatusCalled MarginStatus = "CALLED"
+	// The margin call was not met and the contract was exited on chain
+	MarginStatusLiquidated MarginStatus = "LIQUIDATED"
+	// The contract settled and the collateral was released or forfeited
+	MarginStatusClosed MarginStatus = "CLOSED"
+)
+
+// MarginPosition is a contract whose writer posted collateral from their
+// margin account instead of funding the full contract size
+type MarginPosition struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	ContractID     uuid.UUID    `json:"contract_id" db:"contract_id"`
+	UserID         uuid.UUID    `json:"user_id" db:"user_id"`
+	Collateral     int64        `json:"collateral" db:"collateral"`           // In satoshis
+	RequiredMargin int64        `json:"required_margin" db:"required_margin"` // In satoshis
+	Status         MarginStatus `json:"status" db:"status"`
+	CalledAt       *time.Time   `json:"called_at,omitempty" db:"called_at"`
+	ClosedAt       *time.Time   `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Active reports whether the position still backs a live contract
+func (p *MarginPosition) Active() bool {
+	return p.Status == MarginStatusOpen || p.Status == MarginStatusCalled
+}
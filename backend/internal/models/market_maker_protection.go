@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxProtectionWindowSeconds is the longest rolling window a protection may count fills over
+const MaxProtectionWindowSeconds = 3600
+
+// MarketMakerProtection limits how many fills the orders of an API key may
+// receive within a rolling window. Once the limit is reached the key's
+// remaining orders are pulled from the book and further orders from the key
+// are rejected until the freeze ends or the protection is reset.
+type MarketMakerProtection struct {
+	APIKeyID      uuid.UUID `json:"api_key_id" db:"api_key_id"`
+	MaxFills      int       `json:"max_fills" db:"max_fills"`
+	WindowSeconds int       `json:"window_seconds" db:"window_seconds"`
+	// FreezeSeconds is how long the key stays frozen once triggered. Zero
+	// freezes it until the protection is reset.
+	FreezeSeconds int       `json:"freeze_seconds" db:"freeze_seconds"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks if the protection settings are valid
+func (p *MarketMakerProtection) Validate() error {
+	if p.APIKeyID == uuid.Nil {
+		return errors.New("API key ID cannot be empty")
+	}
+
+	if p.MaxFills <= 0 {
+		return errors.New("max fills must be positive")
+	}
+
+	if p.WindowSeconds <= 0 || p.WindowSeconds > MaxProtectionWindowSeconds {
+		return errors.New("window must be between 1 second and 1 hour")
+	}
+
+	if p.FreezeSeconds < 0 {
+		return errors.New("freeze cannot be negative")
+	}
+
+	return nil
+}
+
+// Window is the rolling window fills are counted over
+func (p *MarketMakerProtection) Window() time.Duration {
+	return time.Duration(p.WindowSeconds) * time.Second
+}
+
+// Freeze is how long the key stays frozen once triggered, zero until reset
+func (p *MarketMakerProtection) Freeze() time.Duration {
+	return time.Duration(p.FreezeSeconds) * time.Second
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType is a kind of event users are notified of
+type NotificationType string
+
+const (
+	// One of the user's orders was partially or completely filled
+	NotificationOrderFill NotificationType = "ORDER_FILL"
+	// A contract the user is a party to was set up and is now active
+	NotificationContractActivated NotificationType = "CONTRACT_ACTIVATED"
+	// A contract the user is a party to settled
+	NotificationSettlement NotificationType = "SETTLEMENT"
+	// The ASP stopped responding while the user had open contracts
+	NotificationASPOutage NotificationType = "ASP_OUTAGE"
+	// One of the user's margin positions was called or liquidated
+	NotificationMarginCall NotificationType = "MARGIN_CALL"
+)
+
+// Notification is a message to a user kept until they dismiss it, so events
+// that happened while they were away are still shown when they return
+type Notification struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	UserID     uuid.UUID        `json:"user_id" db:"user_id"`
+	Type       NotificationType `json:"type" db:"type"`
+	Title      string           `json:"title" db:"title"`
+	Body       string           `json:"body" db:"body"`
+	ContractID *uuid.UUID       `json:"contract_id,omitempty" db:"contract_id"`
+	Data       json.RawMessage  `json:"data,omitempty" db:"data"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	ReadAt     *time.Time       `json:"read_at,omitempty" db:"read_at"`
+}
+
+// Read reports whether the user has marked the notification read
+func (n *Notification) Read() bool {
+	return n.ReadAt != nil
+}
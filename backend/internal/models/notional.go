@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotionalUnit is what one unit of a contract represents
+type NotionalUnit string
+
+const (
+	// NotionalUnitContract quotes a contract as a whole: one unit pays out the
+	// full contract size
+	NotionalUnitContract NotionalUnit = "CONTRACT"
+	// NotionalUnitEHsDay quotes a contract per EH/s-day of hash rate exposure,
+	// so contract size and premium divide into sats per EH/s-day
+	NotionalUnitEHsDay NotionalUnit = "EH_S_DAY"
+)
+
+// SettlementCurrencyBTC is the only currency contracts settle in
+const SettlementCurrencyBTC = "BTC"
+
+// Notional describes the units a contract is quoted in, so prices of
+// contracts with different strikes and sizes can be compared
+type Notional struct {
+	Unit NotionalUnit `json:"unit" db:"notional_unit"`
+	// Quantity is how many units the contract covers
+	Quantity           float64 `json:"quantity" db:"notional_quantity"`
+	SettlementCurrency string  `json:"settlement_currency" db:"settlement_currency"`
+}
+
+// DefaultNotional quotes a contract as one whole unit settled in bitcoin
+var DefaultNotional = Notional{
+	Unit:               NotionalUnitContract,
+	Quantity:           1,
+	SettlementCurrency: SettlementCurrencyBTC,
+}
+
+// Validate checks if the notional metadata is valid
+func (n Notional) Validate() error {
+	switch n.Unit {
+	case NotionalUnitContract:
+		if n.Quantity != 1 {
+			return errors.New("a contract quoted per contract covers exactly one unit")
+		}
+	case NotionalUnitEHsDay:
+		if n.Quantity <= 0 {
+			return errors.New("notional quantity must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid notional unit %q", n.Unit)
+	}
+
+	if n.SettlementCurrency != SettlementCurrencyBTC {
+		return fmt.Errorf("unsupported settlement currency %q", n.SettlementCurrency)
+	}
+
+	return nil
+}
+
+// PerUnit divides a satoshi amount of the contract into satoshis per unit
+func (n Notional) PerUnit(sats int64) float64 {
+	return float64(sats) / n.Quantity
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// OpenInterest is the number and notional of the active contracts of a
+// market. Contracts count toward it from activation until they settle or
+// expire.
+type OpenInterest struct {
+	ContractType     ContractType `json:"contract_type" db:"contract_type"`
+	StrikeHashRate   float64      `json:"strike_hash_rate" db:"strike_hash_rate"`
+	StartBlockHeight int64        `json:"start_block_height" db:"start_block_height"`
+	EndBlockHeight   int64        `json:"end_block_height" db:"end_block_height"`
+	Contracts        int64        `json:"contracts" db:"contracts"`
+	Notional         int64        `json:"notional" db:"notional"` // In satoshis
+	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// OpenInterestPoint is the open interest of a market recorded after a change
+type OpenInterestPoint struct {
+	ID               int64        `json:"-" db:"id"`
+	ContractType     ContractType `json:"contract_type" db:"contract_type"`
+	StrikeHashRate   float64      `json:"strike_hash_rate" db:"strike_hash_rate"`
+	StartBlockHeight int64        `json:"start_block_height" db:"start_block_height"`
+	EndBlockHeight   int64        `json:"end_block_height" db:"end_block_height"`
+	Contracts        int64        `json:"contracts" db:"contracts"`
+	Notional         int64        `json:"notional" db:"notional"` // In satoshis
+	RecordedAt       time.Time    `json:"recorded_at" db:"recorded_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OracleEvent is the oracle event a contract settles on. The oracle's key
+// and nonce are announced before the final transaction is built; the
+// outcome, observed hash rate and signature are filled in once it attests.
+type OracleEvent struct {
+	ContractID       uuid.UUID  `json:"contract_id" db:"contract_id"`
+	OraclePubKey     string     `json:"oracle_pub_key" db:"oracle_pub_key"`
+	Nonce            string     `json:"nonce" db:"nonce"`
+	EventID          string     `json:"event_id" db:"event_id"`
+	Outcome          *string    `json:"outcome,omitempty" db:"outcome"`
+	ObservedHashRate *float64   `json:"observed_hash_rate,omitempty" db:"observed_hash_rate"` // In EH/s
+	Signature        *string    `json:"signature,omitempty" db:"signature"`
+	AnnouncedAt      time.Time  `json:"announced_at" db:"announced_at"`
+	AttestedAt       *time.Time `json:"attested_at,omitempty" db:"attested_at"`
+}
+
+// Attested reports whether the oracle has attested the outcome
+func (e *OracleEvent) Attested() bool {
+	return e.Signature != nil
+}
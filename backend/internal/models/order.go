@@ -15,6 +15,30 @@ const (
 	OrderSideSell OrderSide = "SELL"
 )
 
+// OrderType determines how an order is priced
+type OrderType string
+
+const (
+	// OrderTypeLimit rests in the book at its price until filled, cancelled or expired
+	OrderTypeLimit OrderType = "LIMIT"
+	// OrderTypeMarket takes the best prices available when it is placed,
+	// within the configured slippage. It never rests, so its time in force
+	// is immediate-or-cancel or fill-or-kill.
+	OrderTypeMarket OrderType = "MARKET"
+)
+
+// TimeInForce determines how long an order remains in the book
+type TimeInForce string
+
+const (
+	// TimeInForceGTC rests in the book until filled, cancelled or expired
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can when placed and cancels the remainder
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills completely when placed or is cancelled unfilled
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
 // OrderStatus represents the current state of an order
 type OrderStatus string
 
@@ -31,6 +55,8 @@ type Order struct {
 	ID                 uuid.UUID    `json:"id" db:"id"`
 	UserID             uuid.UUID    `json:"user_id" db:"user_id"`
 	Side               OrderSide    `json:"side" db:"side"`
+	Type               OrderType    `json:"type" db:"order_type"`
+	TimeInForce        TimeInForce  `json:"time_in_force" db:"time_in_force"`
 	ContractType       ContractType `json:"contract_type" db:"contract_type"`
 	StrikeHashRate     float64      `json:"strike_hash_rate" db:"strike_hash_rate"`
 	StartBlockHeight   int64        `json:"start_block_height" db:"start_block_height"`
@@ -40,9 +66,20 @@ type Order struct {
 	RemainingQuantity  int          `json:"remaining_quantity" db:"remaining_quantity"`
 	Status             OrderStatus  `json:"status" db:"status"`
 	PubKey             string       `json:"pub_key" db:"pub_key"`
+	KeyID              *uuid.UUID   `json:"key_id,omitempty" db:"key_id"` // Registered key the pub key was resolved from
+	APIKeyID           *uuid.UUID   `json:"api_key_id,omitempty" db:"api_key_id"` // API key the order was placed with
+	// CancelOnDisconnect cancels the order when the last websocket session
+	// of its API key closes
+	CancelOnDisconnect bool         `json:"cancel_on_disconnect" db:"cancel_on_disconnect"`
 	CreatedAt          time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time    `json:"updated_at" db:"updated_at"`
+	// PriorityAt orders the queue at a price level. It is the creation time
+	// until an amendment gives up the order's place in the queue.
+	PriorityAt         time.Time    `json:"priority_at" db:"priority_at"`
 	ExpiresAt          *time.Time   `json:"expires_at,omitempty" db:"expires_at"`
+	// TargetTimestamp is the time the end height is measured against. Orders
+	// only match orders quoting the same target timestamp.
+	TargetTimestamp    *time.Time   `json:"target_timestamp,omitempty" db:"target_timestamp"`
 }
 
 // Validate checks if the order is valid
@@ -55,6 +92,18 @@ func (o *Order) Validate() error {
 		return errors.New("invalid order side")
 	}
 
+	if o.Type != OrderTypeLimit && o.Type != OrderTypeMarket {
+		return errors.New("invalid order type")
+	}
+
+	if o.TimeInForce != TimeInForceGTC && o.TimeInForce != TimeInForceIOC && o.TimeInForce != TimeInForceFOK {
+		return errors.New("invalid time in force")
+	}
+
+	if o.Type == OrderTypeMarket && o.TimeInForce == TimeInForceGTC {
+		return errors.New("market orders cannot rest in the book")
+	}
+
 	if o.ContractType != ContractTypeCall && o.ContractType != ContractTypePut {
 		return errors.New("invalid contract type")
 	}
@@ -71,7 +120,12 @@ func (o *Order) Validate() error {
 		return errors.New("end block height must be greater than start block height")
 	}
 
-	if o.Price <= 0 {
+	// The book prices market orders from the opposite side
+	if o.Type == OrderTypeMarket && o.Price != 0 {
+		return errors.New("market orders cannot have a price")
+	}
+
+	if o.Type == OrderTypeLimit && o.Price <= 0 {
 		return errors.New("price must be positive")
 	}
 
@@ -86,11 +140,38 @@ func (o *Order) Validate() error {
 	return nil
 }
 
+// Rests reports whether the unfilled remainder of an order stays in the book
+func (o *Order) Rests() bool {
+	return o.TimeInForce == TimeInForceGTC
+}
+
 // CanBeCancelled checks if an order can be cancelled
 func (o *Order) CanBeCancelled() bool {
 	return o.Status == OrderStatusOpen || o.Status == OrderStatusPartial
 }
 
+// PriceRule determines the price of a trade and how an incoming order is
+// allocated among the resting orders it crosses
+type PriceRule string
+
+const (
+	// PriceRuleResting trades at the resting order's price, filling resting
+	// orders in price-time priority
+	PriceRuleResting PriceRule = "resting"
+	// PriceRuleMid trades at the midpoint of the two orders' prices, filling
+	// resting orders in price-time priority
+	PriceRuleMid PriceRule = "mid"
+	// PriceRuleProRata trades at the resting order's price, splitting the
+	// incoming quantity across every resting order at a price level in
+	// proportion to its remaining quantity
+	PriceRuleProRata PriceRule = "pro_rata"
+)
+
+// IsValid reports whether the price rule is known
+func (r PriceRule) IsValid() bool {
+	return r == PriceRuleResting || r == PriceRuleMid || r == PriceRuleProRata
+}
+
 // Trade represents a matched order that resulted in a contract
 type Trade struct {
 	ID           uuid.UUID `json:"id" db:"id"`
@@ -99,6 +180,7 @@ type Trade struct {
 	ContractID   uuid.UUID `json:"contract_id" db:"contract_id"`
 	Price        int64     `json:"price" db:"price"`
 	Quantity     int       `json:"quantity" db:"quantity"`
+	PriceRule    PriceRule `json:"price_rule" db:"price_rule"`
 	ExecutedAt   time.Time `json:"executed_at" db:"executed_at"`
 }
 
@@ -124,5 +206,9 @@ func (t *Trade) Validate() error {
 		return errors.New("quantity must be positive")
 	}
 
+	if !t.PriceRule.IsValid() {
+		return errors.New("invalid price rule")
+	}
+
 	return nil
 }
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for a malformed page cursor
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// Cursor is a position in a newest-first listing: the time and ID of the
+// last record read. The zero cursor starts at the newest record.
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// IsZero reports whether the cursor starts at the newest record
+func (c Cursor) IsZero() bool {
+	return c.Time.IsZero() && c.ID == uuid.Nil
+}
+
+// Encode returns the opaque form of a cursor handed to clients
+func (c Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor from Encode. An empty cursor starts at the
+// newest record.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if c.Time, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// PageInfo describes where a page sits in its listing
+type PageInfo struct {
+	// Total is the number of records in the listing across all pages
+	Total int64 `json:"total"`
+	// HasMore is set when records remain after this page
+	HasMore bool `json:"has_more"`
+	// NextCursor fetches the next page; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPageInfo describes a page read with limit+1 rows: fetched is the number
+// of rows read and last the cursor of the last row kept on the page
+func NewPageInfo(total int64, fetched, limit int, last Cursor) *PageInfo {
+	page := &PageInfo{Total: total, HasMore: fetched > limit}
+	if page.HasMore {
+		page.NextCursor = last.Encode()
+	}
+	return page
+}
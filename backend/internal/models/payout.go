@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayoutAddress is the address a user's winning settlements pay to instead of
+// a taproot output of the contract key
+type PayoutAddress struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Address   string    `json:"address" db:"address"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ContractPayoutAddress is the address one party of a contract is paid at if
+// it wins. It is either set by the party with a signed request or recorded
+// from the party's registered payout address when the contract settles.
+type ContractPayoutAddress struct {
+	ContractID uuid.UUID `json:"contract_id" db:"contract_id"`
+	PubKey     string    `json:"pub_key" db:"pub_key"`
+	Address    string    `json:"address" db:"address"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
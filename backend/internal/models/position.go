@@ -0,0 +1,29 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PositionEntry is one side of a trade a user took part in, with the
+// contract the trade opened and the evidence of its settlement if any
+type PositionEntry struct {
+	Contract
+	Side       OrderSide       `db:"side"`
+	EntryPrice int64           `db:"entry_price"`
+	Quantity   int             `db:"quantity"`
+	OpenedAt   time.Time       `db:"opened_at"`
+	Evidence   json.RawMessage `db:"evidence"`
+}
+
+// TradeReportEntry is a position entry with the trade that opened it and,
+// once its contract's payout settled, when it did and who won. BuyerWins
+// is nil for contracts the parties closed cooperatively.
+type TradeReportEntry struct {
+	PositionEntry
+	TradeID   uuid.UUID  `db:"trade_id"`
+	SettledAt *time.Time `db:"settled_at"`
+	BuyerWins *bool      `db:"buyer_wins"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertMetric is the market value a price alert watches
+type AlertMetric string
+
+const (
+	AlertMetricBestBid   AlertMetric = "BEST_BID"
+	AlertMetricBestAsk   AlertMetric = "BEST_ASK"
+	AlertMetricLastTrade AlertMetric = "LAST_TRADE"
+)
+
+// AlertDirection is the side of the threshold that triggers a price alert
+type AlertDirection string
+
+const (
+	AlertDirectionAbove AlertDirection = "ABOVE"
+	AlertDirectionBelow AlertDirection = "BELOW"
+)
+
+// AlertChannel is how a triggered price alert is delivered
+type AlertChannel string
+
+const (
+	AlertChannelWebsocket AlertChannel = "WEBSOCKET"
+	AlertChannelWebhook   AlertChannel = "WEBHOOK"
+	AlertChannelEmail     AlertChannel = "EMAIL"
+)
+
+// AlertStatus represents the current state of a price alert
+type AlertStatus string
+
+const (
+	AlertStatusActive    AlertStatus = "ACTIVE"
+	AlertStatusTriggered AlertStatus = "TRIGGERED"
+	AlertStatusCancelled AlertStatus = "CANCELLED"
+)
+
+// PriceAlert fires once when a market value reaches a threshold
+type PriceAlert struct {
+	ID               uuid.UUID      `json:"id" db:"id"`
+	UserID           uuid.UUID      `json:"user_id" db:"user_id"`
+	ContractType     ContractType   `json:"contract_type" db:"contract_type"`
+	StrikeHashRate   float64        `json:"strike_hash_rate" db:"strike_hash_rate"`
+	StartBlockHeight int64          `json:"start_block_height" db:"start_block_height"`
+	EndBlockHeight   int64          `json:"end_block_height" db:"end_block_height"`
+	Metric           AlertMetric    `json:"metric" db:"metric"`
+	Direction        AlertDirection `json:"direction" db:"direction"`
+	Threshold        int64          `json:"threshold" db:"threshold"` // In satoshis
+	Channel          AlertChannel   `json:"channel" db:"channel"`
+	Target           string         `json:"target,omitempty" db:"target"` // Webhook URL or email address
+	Status           AlertStatus    `json:"status" db:"status"`
+	TriggeredValue   *int64         `json:"triggered_value,omitempty" db:"triggered_value"`
+	TriggeredAt      *time.Time     `json:"triggered_at,omitempty" db:"triggered_at"`
+	CreatedAt        time.Time      `json:"created_at" db:"created_at"`
+}
+
+// Validate checks if the price alert is valid
+func (a *PriceAlert) Validate() error {
+	if a.UserID == uuid.Nil {
+		return errors.New("user ID cannot be empty")
+	}
+
+	if a.ContractType != ContractTypeCall && a.ContractType != ContractTypePut {
+		return errors.New("invalid contract type")
+	}
+
+	if a.StrikeHashRate <= 0 {
+		return errors.New("strike hash rate must be positive")
+	}
+
+	if a.StartBlockHeight <= 0 {
+		return errors.New("start block height must be positive")
+	}
+
+	if a.EndBlockHeight <= a.StartBlockHeight {
+		return errors.New("end block height must be greater than start block height")
+	}
+
+	if a.Metric != AlertMetricBestBid && a.Metric != AlertMetricBestAsk && a.Metric != AlertMetricLastTrade {
+		return errors.New("invalid alert metric")
+	}
+
+	if a.Direction != AlertDirectionAbove && a.Direction != AlertDirectionBelow {
+		return errors.New("invalid alert direction")
+	}
+
+	if a.Threshold <= 0 {
+		return errors.New("threshold must be positive")
+	}
+
+	switch a.Channel {
+	case AlertChannelWebsocket:
+	case AlertChannelWebhook:
+		u, err := url.Parse(a.Target)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("webhook target must be an https URL")
+		}
+	case AlertChannelEmail:
+		addr, err := mail.ParseAddress(a.Target)
+		if err != nil || addr.Address != a.Target {
+			return errors.New("email target must be a plain email address")
+		}
+	default:
+		return errors.New("invalid alert channel")
+	}
+
+	return nil
+}
+
+// IsTriggeredBy reports whether value is on the triggering side of the threshold
+func (a *PriceAlert) IsTriggeredBy(value int64) bool {
+	if a.Direction == AlertDirectionAbove {
+		return value >= a.Threshold
+	}
+	return value <= a.Threshold
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushPlatform is the mobile push service a device token belongs to
+type PushPlatform string
+
+const (
+	PushPlatformAPNs PushPlatform = "APNS"
+	PushPlatformFCM  PushPlatform = "FCM"
+)
+
+// PushEventType is a kind of event a user can receive push notifications for
+type PushEventType string
+
+const (
+	PushEventFill               PushEventType = "FILL"
+	PushEventSettlement         PushEventType = "SETTLEMENT"
+	PushEventSettlementDeferred PushEventType = "SETTLEMENT_DEFERRED"
+)
+
+// PushEventTypes lists every push event type
+var PushEventTypes = []PushEventType{PushEventFill, PushEventSettlement, PushEventSettlementDeferred}
+
+// DeviceToken is a mobile device registered to receive push notifications
+type DeviceToken struct {
+	ID        uuid.UUID    `json:"id" db:"id"`
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`
+	Platform  PushPlatform `json:"platform" db:"platform"`
+	Token     string       `json:"token" db:"token"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// Validate checks if the device token is valid
+func (d *DeviceToken) Validate() error {
+	if d.UserID == uuid.Nil {
+		return errors.New("user ID cannot be empty")
+	}
+
+	if d.Platform != PushPlatformAPNs && d.Platform != PushPlatformFCM {
+		return errors.New("invalid push platform")
+	}
+
+	if d.Token == "" || len(d.Token) > 4096 {
+		return errors.New("invalid device token")
+	}
+
+	return nil
+}
+
+// PushPreference enables or disables push notifications for one event type
+type PushPreference struct {
+	UserID    uuid.UUID     `json:"user_id" db:"user_id"`
+	EventType PushEventType `json:"event_type" db:"event_type"`
+	Enabled   bool          `json:"enabled" db:"enabled"`
+}
+
+// IsValidPushEventType reports whether t is a known push event type
+func IsValidPushEventType(t PushEventType) bool {
+	for _, known := range PushEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
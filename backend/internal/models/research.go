@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResearchTier is the level of access a researcher has to the bulk data feed
+type ResearchTier string
+
+const (
+	// ResearchTierBasic sees delayed trades and settlement outcomes over a
+	// limited history
+	ResearchTierBasic ResearchTier = "BASIC"
+	// ResearchTierFull sees every dataset, including book snapshots and the
+	// daily dump files, over the full history
+	ResearchTierFull ResearchTier = "FULL"
+)
+
+// ResearchAccess grants a user access to the research data feed
+type ResearchAccess struct {
+	UserID    uuid.UUID    `json:"user_id" db:"user_id"`
+	Tier      ResearchTier `json:"tier" db:"tier"`
+	GrantedAt time.Time    `json:"granted_at" db:"granted_at"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty" db:"expires_at"`
+}
+
+// BookSnapshot is the aggregated order book of one market at a point in
+// time. Bids and asks are the JSON encoded price levels, best price first.
+type BookSnapshot struct {
+	ID               uuid.UUID       `json:"id" db:"id"`
+	ContractType     ContractType    `json:"contract_type" db:"contract_type"`
+	StrikeHashRate   float64         `json:"strike_hash_rate" db:"strike_hash_rate"`
+	StartBlockHeight int64           `json:"start_block_height" db:"start_block_height"`
+	EndBlockHeight   int64           `json:"end_block_height" db:"end_block_height"`
+	Bids             json.RawMessage `json:"bids" db:"bids"`
+	Asks             json.RawMessage `json:"asks" db:"asks"`
+	TakenAt          time.Time       `json:"taken_at" db:"taken_at"`
+}
+
+// ResearchTrade is a trade with the market it traded in and the public keys
+// of both sides, before anonymization
+type ResearchTrade struct {
+	ID               uuid.UUID    `db:"id"`
+	ContractID       uuid.UUID    `db:"contract_id"`
+	ContractType     ContractType `db:"contract_type"`
+	StrikeHashRate   float64      `db:"strike_hash_rate"`
+	StartBlockHeight int64        `db:"start_block_height"`
+	EndBlockHeight   int64        `db:"end_block_height"`
+	Price            int64        `db:"price"`
+	Quantity         int          `db:"quantity"`
+	PriceRule        PriceRule    `db:"price_rule"`
+	BuyerPubKey      string       `db:"buyer_pub_key"`
+	SellerPubKey     string       `db:"seller_pub_key"`
+	ExecutedAt       time.Time    `db:"executed_at"`
+}
+
+// ResearchSettlement is a settled contract with the evidence its outcome
+// was decided on
+type ResearchSettlement struct {
+	Contract
+	Bundle         json.RawMessage `db:"bundle"`
+	EvidenceDigest string          `db:"digest"`
+	EvidenceStatus EvidenceStatus  `db:"evidence_status"`
+	DecidedAt      time.Time       `db:"decided_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RolloverStatus represents the current state of an auto-roll instruction
+type RolloverStatus string
+
+const (
+	RolloverStatusPending   RolloverStatus = "PENDING"
+	RolloverStatusRolled    RolloverStatus = "ROLLED"
+	RolloverStatusFailed    RolloverStatus = "FAILED"
+	RolloverStatusCancelled RolloverStatus = "CANCELLED"
+)
+
+// AutoRoll places an equivalent order in the next expiry once the market of
+// the source order has settled
+type AutoRoll struct {
+	ID            uuid.UUID      `json:"id" db:"id"`
+	UserID        uuid.UUID      `json:"user_id" db:"user_id"`
+	OrderID       uuid.UUID      `json:"order_id" db:"order_id"`
+	PriceOffset   int64          `json:"price_offset" db:"price_offset"` // In satoshis, added to the source order price
+	Status        RolloverStatus `json:"status" db:"status"`
+	RolledOrderID *uuid.UUID     `json:"rolled_order_id,omitempty" db:"rolled_order_id"`
+	LastError     *string        `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
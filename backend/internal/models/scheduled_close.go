@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledCloseStatus represents the state of a scheduled cooperative close
+type ScheduledCloseStatus string
+
+const (
+	// ScheduledCloseStatusPending closes are waiting for their block height or time
+	ScheduledCloseStatusPending ScheduledCloseStatus = "PENDING"
+	// ScheduledCloseStatusExecuted closes paid out the agreed split
+	ScheduledCloseStatusExecuted ScheduledCloseStatus = "EXECUTED"
+	// ScheduledCloseStatusLapsed closes fell due without both signatures or
+	// after the contract left the active state; standard settlement applies
+	ScheduledCloseStatusLapsed ScheduledCloseStatus = "LAPSED"
+	// ScheduledCloseStatusFailed closes could not build their transaction;
+	// standard settlement applies
+	ScheduledCloseStatusFailed ScheduledCloseStatus = "FAILED"
+)
+
+// ScheduledClose is a cooperative close both parties of a contract agree to
+// in advance with signed intents. It falls due at CloseHeight or CloseAt,
+// whichever is set, and pays the contract out in the agreed split.
+type ScheduledClose struct {
+	ContractID      uuid.UUID            `json:"contract_id" db:"contract_id"`
+	CloseHeight     *int64               `json:"close_height,omitempty" db:"close_height"`
+	CloseAt         *time.Time           `json:"close_at,omitempty" db:"close_at"`
+	BuyerAmount     int64                `json:"buyer_amount" db:"buyer_amount"`   // In satoshis
+	SellerAmount    int64                `json:"seller_amount" db:"seller_amount"` // In satoshis
+	BuyerSignature  *string              `json:"buyer_signature,omitempty" db:"buyer_signature"`
+	SellerSignature *string              `json:"seller_signature,omitempty" db:"seller_signature"`
+	Status          ScheduledCloseStatus `json:"status" db:"status"`
+	CloseTxID       *string              `json:"close_tx_id,omitempty" db:"close_tx_id"`
+	LastError       *string              `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// Signed reports whether both parties have signed the close
+func (c *ScheduledClose) Signed() bool {
+	return c.BuyerSignature != nil && c.SellerSignature != nil
+}
+
+// Due reports whether a pending close has reached its block height or time
+func (c *ScheduledClose) Due(height int64, now time.Time) bool {
+	if c.Status != ScheduledCloseStatusPending {
+		return false
+	}
+	if c.CloseHeight != nil {
+		return height >= *c.CloseHeight
+	}
+	return c.CloseAt != nil && !now.Before(*c.CloseAt)
+}
+
+// SameTerms reports whether two closes agree on the schedule and split
+func (c *ScheduledClose) SameTerms(other *ScheduledClose) bool {
+	sameHeight := (c.CloseHeight == nil) == (other.CloseHeight == nil) &&
+		(c.CloseHeight == nil || *c.CloseHeight == *other.CloseHeight)
+	sameTime := (c.CloseAt == nil) == (other.CloseAt == nil) &&
+		(c.CloseAt == nil || c.CloseAt.Equal(*other.CloseAt))
+
+	return sameHeight && sameTime &&
+		c.BuyerAmount == other.BuyerAmount &&
+		c.SellerAmount == other.SellerAmount
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementOutcome is the result of an automatic settlement attempt
+type SettlementOutcome string
+
+const (
+	// SettlementOutcomeSettled attempts produced and broadcast a settlement transaction
+	SettlementOutcomeSettled SettlementOutcome = "SETTLED"
+	// SettlementOutcomeDeferred attempts were held back by the fee policy
+	SettlementOutcomeDeferred SettlementOutcome = "DEFERRED"
+	// SettlementOutcomeFailed attempts returned an error and are retried with backoff
+	SettlementOutcomeFailed SettlementOutcome = "FAILED"
+)
+
+// SettlementAttempt records the settlement oracle acting on a matured contract
+type SettlementAttempt struct {
+	ID             uuid.UUID         `json:"id" db:"id"`
+	ContractID     uuid.UUID         `json:"contract_id" db:"contract_id"`
+	Outcome        SettlementOutcome `json:"outcome" db:"outcome"`
+	Reason         string            `json:"reason" db:"reason"`
+	SettlementTxID *string           `json:"settlement_tx_id,omitempty" db:"settlement_tx_id"`
+	BuyerWins      *bool             `json:"buyer_wins,omitempty" db:"buyer_wins"`
+	BlockHeight    int64             `json:"block_height" db:"block_height"`
+	AttemptedAt    time.Time         `json:"attempted_at" db:"attempted_at"`
+}
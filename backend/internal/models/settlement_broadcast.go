@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementBroadcast tracks the transaction paying out a contract from its
+// first broadcast until it confirms. The contract stays PENDING_SETTLEMENT
+// meanwhile, and the transaction is rebroadcast while the node does not know it.
+type SettlementBroadcast struct {
+	ContractID      uuid.UUID  `json:"contract_id" db:"contract_id"`
+	TransactionID   string     `json:"transaction_id" db:"transaction_id"`
+	TxType          string     `json:"tx_type" db:"tx_type"`                 // settlement or close
+	BuyerWins       *bool      `json:"buyer_wins,omitempty" db:"buyer_wins"` // Nil for a cooperative close
+	Broadcasts      int        `json:"broadcasts" db:"broadcasts"`           // Attempts so far, including failed ones
+	LastError       *string    `json:"last_error,omitempty" db:"last_error"` // Error of the latest attempt, if it failed
+	LastBroadcastAt *time.Time `json:"last_broadcast_at,omitempty" db:"last_broadcast_at"`
+	Confirmations   int64      `json:"confirmations" db:"confirmations"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+}
+
+// Pending reports whether the transaction has yet to confirm
+func (b *SettlementBroadcast) Pending() bool {
+	return b.ConfirmedAt == nil
+}
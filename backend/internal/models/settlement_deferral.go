@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SettlementDeferral records a settlement held back while chain fees are
+// above the stress threshold. It is released once fees fall, the contract
+// becomes urgent, or a party agrees to pay a priority fee rate.
+type SettlementDeferral struct {
+	ContractID      uuid.UUID  `json:"contract_id" db:"contract_id"`
+	FeeRate         float64    `json:"fee_rate" db:"fee_rate"`                             // Estimate in sat/vB when deferred
+	PriorityFeeRate *float64   `json:"priority_fee_rate,omitempty" db:"priority_fee_rate"` // Rate in sat/vB a party agreed to pay
+	DeferredAt      time.Time  `json:"deferred_at" db:"deferred_at"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty" db:"released_at"`
+}
+
+// Pending reports whether the settlement is still held back
+func (d *SettlementDeferral) Pending() bool {
+	return d.ReleasedAt == nil
+}
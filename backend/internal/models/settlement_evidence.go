@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EvidenceStatus represents how far a settlement evidence bundle has been
+// anchored in Bitcoin
+type EvidenceStatus string
+
+const (
+	// EvidenceStatusRecorded bundles have not been submitted to a calendar yet
+	EvidenceStatusRecorded EvidenceStatus = "RECORDED"
+	// EvidenceStatusPending bundles are committed to by a calendar and wait
+	// for the calendar's commitment to be mined
+	EvidenceStatusPending EvidenceStatus = "PENDING"
+	// EvidenceStatusAnchored bundles have a proof ending in a Bitcoin block
+	EvidenceStatusAnchored EvidenceStatus = "ANCHORED"
+)
+
+// SettlementEvidence is the record of the chain state a settlement decision
+// was based on. Digest is the hex SHA-256 of Bundle, the exact bytes that
+// are timestamped; Proof is the serialized OpenTimestamps proof of Digest.
+type SettlementEvidence struct {
+	ContractID   uuid.UUID       `json:"contract_id" db:"contract_id"`
+	Bundle       json.RawMessage `json:"bundle" db:"bundle"`
+	Digest       string          `json:"digest" db:"digest"`
+	Status       EvidenceStatus  `json:"status" db:"status"`
+	Proof        []byte          `json:"-" db:"proof"`
+	AnchorHeight *int64          `json:"anchor_height,omitempty" db:"anchor_height"`
+	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
+	SubmittedAt  *time.Time      `json:"submitted_at,omitempty" db:"submitted_at"`
+	AnchoredAt   *time.Time      `json:"anchored_at,omitempty" db:"anchored_at"`
+	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsage holds the request and websocket counters of an API key for one hour
+type APIUsage struct {
+	APIKeyID    uuid.UUID `json:"api_key_id" db:"api_key_id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	PeriodStart time.Time `json:"period_start" db:"period_start"`
+	Requests    int64     `json:"requests" db:"requests"`
+	Errors      int64     `json:"errors" db:"errors"`
+	WSMessages  int64     `json:"ws_messages" db:"ws_messages"`
+	WSBytes     int64     `json:"ws_bytes" db:"ws_bytes"`
+}
+
+// UsageAggregate sums the usage of an API key over a time range
+type UsageAggregate struct {
+	APIKeyID   uuid.UUID `json:"api_key_id" db:"api_key_id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	Requests   int64     `json:"requests" db:"requests"`
+	Errors     int64     `json:"errors" db:"errors"`
+	ErrorRate  float64   `json:"error_rate" db:"-"`
+	WSMessages int64     `json:"ws_messages" db:"ws_messages"`
+	WSBytes    int64     `json:"ws_bytes" db:"ws_bytes"`
+}
@@ -25,5 +25,6 @@ type UserKey struct {
 	PubKey    string    `json:"pub_key" db:"pub_key"`
 	KeyType   string    `json:"key_type" db:"key_type"` // e.g., "taproot", "secp256k1"
 	Label     string    `json:"label" db:"label"`
+	IsDefault bool      `json:"is_default" db:"is_default"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VTXOStatus is whether a VTXO still holds a contract's funds
+type VTXOStatus string
+
+const (
+	VTXOStatusActive VTXOStatus = "ACTIVE"
+	VTXOStatusSpent  VTXOStatus = "SPENT"
+)
+
+// VTXO is an Ark virtual UTXO holding a contract's funds. Exits and swaps
+// spend the contract's active VTXO.
+type VTXO struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	ContractID uuid.UUID `json:"contract_id" db:"contract_id"`
+	// VTXOID is the outpoint the ASP identifies the VTXO by, txid:vout
+	VTXOID string `json:"vtxo_id" db:"vtxo_id"`
+	// RoundID is the round whose tree the VTXO belongs to. Out-of-round
+	// VTXOs keep the round of the VTXO they spent.
+	RoundID string `json:"round_id" db:"round_id"`
+	Amount  int64  `json:"amount" db:"amount"` // In satoshis
+	Script  string `json:"script" db:"script"`
+	// ASP is the configured name of the ASP that issued the VTXO
+	ASP       string     `json:"asp" db:"asp"`
+	Status    VTXOStatus `json:"status" db:"status"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	SpentAt   *time.Time `json:"spent_at,omitempty" db:"spent_at"`
+}
+
+// VTXOOutpoint returns the identifier of output vout of an Ark transaction
+func VTXOOutpoint(txid string, vout int) string {
+	return fmt.Sprintf("%s:%d", txid, vout)
+}
+
+// Validate checks if the VTXO is valid
+func (v *VTXO) Validate() error {
+	if v.ContractID == uuid.Nil {
+		return errors.New("contract ID cannot be empty")
+	}
+
+	if v.VTXOID == "" {
+		return errors.New("VTXO ID cannot be empty")
+	}
+
+	if v.RoundID == "" {
+		return errors.New("round ID cannot be empty")
+	}
+
+	if v.Amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+
+	if v.Script == "" {
+		return errors.New("script cannot be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchlistItemType represents what a watchlist item follows
+type WatchlistItemType string
+
+const (
+	WatchlistItemContract WatchlistItemType = "CONTRACT"
+	WatchlistItemMarket   WatchlistItemType = "MARKET"
+)
+
+// WatchlistItem is a contract or market followed by a user
+type WatchlistItem struct {
+	ID               uuid.UUID         `json:"id" db:"id"`
+	UserID           uuid.UUID         `json:"user_id" db:"user_id"`
+	ItemType         WatchlistItemType `json:"item_type" db:"item_type"`
+	ContractID       *uuid.UUID        `json:"contract_id,omitempty" db:"contract_id"`
+	ContractType     *ContractType     `json:"contract_type,omitempty" db:"contract_type"`
+	StrikeHashRate   *float64          `json:"strike_hash_rate,omitempty" db:"strike_hash_rate"`
+	StartBlockHeight *int64            `json:"start_block_height,omitempty" db:"start_block_height"`
+	EndBlockHeight   *int64            `json:"end_block_height,omitempty" db:"end_block_height"`
+	Notify           bool              `json:"notify" db:"notify"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+}
+
+// Validate checks if the watchlist item is valid
+func (w *WatchlistItem) Validate() error {
+	if w.UserID == uuid.Nil {
+		return errors.New("user ID cannot be empty")
+	}
+
+	switch w.ItemType {
+	case WatchlistItemContract:
+		if w.ContractID == nil || *w.ContractID == uuid.Nil {
+			return errors.New("contract ID cannot be empty")
+		}
+	case WatchlistItemMarket:
+		if w.ContractType == nil || (*w.ContractType != ContractTypeCall && *w.ContractType != ContractTypePut) {
+			return errors.New("invalid contract type")
+		}
+		if w.StrikeHashRate == nil || *w.StrikeHashRate <= 0 {
+			return errors.New("strike hash rate must be positive")
+		}
+		if w.StartBlockHeight == nil || *w.StartBlockHeight <= 0 {
+			return errors.New("start block height must be positive")
+		}
+		if w.EndBlockHeight == nil || *w.EndBlockHeight <= *w.StartBlockHeight {
+			return errors.New("end block height must be greater than start block height")
+		}
+	default:
+		return errors.New("invalid watchlist item type")
+	}
+
+	return nil
+}
+
+// MatchesContract reports whether the item follows the contract, directly or through its market
+func (w *WatchlistItem) MatchesContract(c *Contract) bool {
+	switch w.ItemType {
+	case WatchlistItemContract:
+		return w.ContractID != nil && *w.ContractID == c.ID
+	case WatchlistItemMarket:
+		return w.ContractType != nil && *w.ContractType == c.ContractType &&
+			w.StrikeHashRate != nil && *w.StrikeHashRate == c.StrikeHashRate &&
+			w.StartBlockHeight != nil && *w.StartBlockHeight == c.StartBlockHeight &&
+			w.EndBlockHeight != nil && *w.EndBlockHeight == c.EndBlockHeight
+	}
+	return false
+}
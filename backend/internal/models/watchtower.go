@@ -0,0 +1,93 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchtowerTokenPrefix marks watchtower access tokens so they are
+// recognisable in logs and secret scanners
+const WatchtowerTokenPrefix = "wt_"
+
+// Watchtower is a third-party service trusted to broadcast emergency exits on
+// behalf of users. Exit packages delegated to it are encrypted to its
+// EncryptionKey, a hex X25519 public key. Only a hash of its token is stored.
+type Watchtower struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	Name          string     `json:"name" db:"name"`
+	EncryptionKey string     `json:"encryption_key" db:"encryption_key"`
+	Prefix        string     `json:"prefix" db:"prefix"` // First characters of the token, for display
+	TokenHash     string     `json:"-" db:"token_hash"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Validate checks if the watchtower is valid
+func (w *Watchtower) Validate() error {
+	if w.Name == "" || len(w.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+
+	key, err := hex.DecodeString(w.EncryptionKey)
+	if err != nil || len(key) != 32 {
+		return errors.New("encryption key must be a hex X25519 public key")
+	}
+
+	return nil
+}
+
+// GenerateToken returns a new random access token and sets the hash and
+// display prefix on w
+func (w *Watchtower) GenerateToken() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	token := WatchtowerTokenPrefix + hex.EncodeToString(secret)
+	w.TokenHash = HashWatchtowerToken(token)
+	w.Prefix = token[:len(WatchtowerTokenPrefix)+8]
+	return token, nil
+}
+
+// HashWatchtowerToken returns the stored form of a watchtower token
+func HashWatchtowerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// DelegationStatus represents the state of an exit delegation
+type DelegationStatus string
+
+const (
+	// DelegationStatusPending delegations have not been picked up by the watchtower
+	DelegationStatusPending DelegationStatus = "PENDING"
+	// DelegationStatusAcknowledged delegations are stored by the watchtower,
+	// which is watching for their trigger conditions
+	DelegationStatusAcknowledged DelegationStatus = "ACKNOWLEDGED"
+	// DelegationStatusRevoked delegations must no longer be acted on
+	DelegationStatusRevoked DelegationStatus = "REVOKED"
+)
+
+// WatchtowerDelegation hands the emergency exit of a contract participant to
+// a watchtower. Package is the exit package sealed to the watchtower's key;
+// PackageDigest is the hex SHA-256 of Package, which the watchtower echoes
+// back when it acknowledges.
+type WatchtowerDelegation struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	ContractID     uuid.UUID        `json:"contract_id" db:"contract_id"`
+	UserID         uuid.UUID        `json:"user_id" db:"user_id"`
+	WatchtowerID   uuid.UUID        `json:"watchtower_id" db:"watchtower_id"`
+	PubKey         string           `json:"pub_key" db:"pub_key"`
+	Status         DelegationStatus `json:"status" db:"status"`
+	Package        []byte           `json:"-" db:"package"`
+	PackageDigest  string           `json:"package_digest" db:"package_digest"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	AcknowledgedAt *time.Time       `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	RevokedAt      *time.Time       `json:"revoked_at,omitempty" db:"revoked_at"`
+}
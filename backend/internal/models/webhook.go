@@ -0,0 +1,117 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// WebhookEventType is a kind of event delivered to webhooks
+type WebhookEventType string
+
+const (
+	// A trade the user was a party to was executed
+	WebhookEventTradeExecuted WebhookEventType = "trade.executed"
+	// One of the user's orders was completely filled
+	WebhookEventOrderFilled WebhookEventType = "order.filled"
+	// A contract the user was a party to settled
+	WebhookEventContractSettled WebhookEventType = "contract.settled"
+)
+
+// WebhookEventTypes lists every event type a webhook may subscribe to
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventTradeExecuted,
+	WebhookEventOrderFilled,
+	WebhookEventContractSettled,
+}
+
+// Webhook is an HTTPS endpoint a user registered to be notified of events.
+// Deliveries are signed with Secret, which is only shown on creation.
+type Webhook struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	UserID     uuid.UUID      `json:"user_id" db:"user_id"`
+	URL        string         `json:"url" db:"url"`
+	Secret     string         `json:"-" db:"secret"`
+	Events     pq.StringArray `json:"events" db:"events"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+	DisabledAt *time.Time     `json:"disabled_at,omitempty" db:"disabled_at"`
+}
+
+// Validate checks if the webhook is valid
+func (w *Webhook) Validate() error {
+	if w.UserID == uuid.Nil {
+		return errors.New("user ID cannot be empty")
+	}
+
+	u, err := url.Parse(w.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("url must be an absolute https URL")
+	}
+	if len(w.URL) > 2048 {
+		return errors.New("url cannot exceed 2048 characters")
+	}
+
+	if len(w.Events) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	for _, event := range w.Events {
+		if !validWebhookEvent(WebhookEventType(event)) {
+			return errors.New("unknown event type: " + event)
+		}
+	}
+
+	return nil
+}
+
+func validWebhookEvent(event WebhookEventType) bool {
+	for _, known := range WebhookEventTypes {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateSecret sets a new random signing secret on w and returns it
+func (w *Webhook) GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	w.Secret = hex.EncodeToString(secret)
+	return w.Secret, nil
+}
+
+// WebhookDelivery is an event queued for delivery to a webhook. URL and
+// Secret are those of its webhook, loaded when it is claimed for sending.
+type WebhookDelivery struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	WebhookID     uuid.UUID        `json:"webhook_id" db:"webhook_id"`
+	EventType     WebhookEventType `json:"event_type" db:"event_type"`
+	Payload       json.RawMessage  `json:"payload" db:"payload"`
+	Attempts      int              `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	URL           string           `json:"-" db:"url"`
+	Secret        string           `json:"-" db:"secret"`
+}
+
+// WebhookDeadLetter is a delivery that failed every attempt
+type WebhookDeadLetter struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	WebhookID uuid.UUID        `json:"webhook_id" db:"webhook_id"`
+	EventType WebhookEventType `json:"event_type" db:"event_type"`
+	Payload   json.RawMessage  `json:"payload" db:"payload"`
+	Attempts  int              `json:"attempts" db:"attempts"`
+	LastError *string          `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	FailedAt  time.Time        `json:"failed_at" db:"failed_at"`
+}
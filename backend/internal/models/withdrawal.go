@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WithdrawalStatus is the progress of a withdrawal
+type WithdrawalStatus string
+
+const (
+	WithdrawalStatusPendingApproval WithdrawalStatus = "PENDING_APPROVAL" // Above the approval threshold, awaiting an operator
+	WithdrawalStatusApproved        WithdrawalStatus = "APPROVED"         // Ready to be paid out
+	WithdrawalStatusProcessing      WithdrawalStatus = "PROCESSING"       // Payout in progress
+	WithdrawalStatusSent            WithdrawalStatus = "SENT"             // Paid out
+	WithdrawalStatusFailed          WithdrawalStatus = "FAILED"           // Payout rejected; the amount was refunded
+	WithdrawalStatusRejected        WithdrawalStatus = "REJECTED"         // Refused by an operator; the amount was refunded
+)
+
+// WithdrawalRail is how a withdrawal is paid out
+type WithdrawalRail string
+
+const (
+	WithdrawalRailOnChain WithdrawalRail = "ONCHAIN" // Sent from the hot wallet
+	WithdrawalRailArk     WithdrawalRail = "ARK"     // Paid in an Ark out-of-round transaction
+)
+
+// Withdrawal is a user's request to be paid part of their balance. The
+// amount is debited when it is requested and refunded if it is not paid.
+type Withdrawal struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	UserID     uuid.UUID        `json:"user_id" db:"user_id"`
+	Amount     int64            `json:"amount" db:"amount"` // In satoshis
+	Address    string           `json:"address" db:"address"`
+	ArkAddress *string          `json:"ark_address,omitempty" db:"ark_address"`
+	Status     WithdrawalStatus `json:"status" db:"status"`
+	Rail       *WithdrawalRail  `json:"rail,omitempty" db:"rail"`
+	TxID       *string          `json:"txid,omitempty" db:"txid"`
+	Reason     *string          `json:"reason,omitempty" db:"reason"` // Why the withdrawal failed or was rejected
+	ReviewedBy *uuid.UUID       `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,172 @@
+// internal/netproxy/netproxy.go
+package netproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// Outbound backends that can be routed through a proxy
+const (
+	Bitcoin  = "bitcoin"
+	FeeAPI   = "fee_api"
+	PriceAPI = "price_api"
+	ArkASP   = "ark_asp"
+	Webhooks = "webhooks"
+)
+
+// Direct is the override that bypasses the default proxy for a backend
+const Direct = "direct"
+
+var backends = map[string]bool{Bitcoin: true, FeeAPI: true, PriceAPI: true, ArkASP: true, Webhooks: true}
+
+// Config routes outbound connections through SOCKS5 proxies, such as the
+// SocksPort of a Tor daemon. Host names are resolved by the proxy, so onion
+// addresses can be used and no DNS query leaves the host.
+type Config struct {
+	// URL is the proxy of every backend, e.g. socks5://127.0.0.1:9050.
+	// Empty connects directly.
+	URL string `yaml:"url"`
+	// Backends overrides the proxy of individual backends with another URL
+	// or "direct". Tor isolates streams with different SOCKS credentials on
+	// separate circuits, so giving each backend its own user keeps them from
+	// being linked.
+	Backends map[string]string `yaml:"backends"`
+}
+
+// Validate checks the proxy URLs and backend names
+func (c Config) Validate() error {
+	if _, err := parse(c.URL); err != nil {
+		return err
+	}
+	for backend, override := range c.Backends {
+		if !backends[backend] {
+			return fmt.Errorf("unknown proxy backend %q", backend)
+		}
+		if _, err := parse(override); err != nil {
+			return fmt.Errorf("proxy of %s: %w", backend, err)
+		}
+	}
+	return nil
+}
+
+// For returns the proxy of a backend, or nil to connect directly
+func (c Config) For(backend string) *url.URL {
+	raw := c.URL
+	if override, ok := c.Backends[backend]; ok {
+		raw = override
+	}
+	u, _ := parse(raw)
+	return u
+}
+
+// parse parses a proxy URL, returning nil for a direct connection
+func parse(raw string) (*url.URL, error) {
+	if raw == "" || raw == Direct {
+		return nil, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy URL %q must use the socks5 or socks5h scheme", Redact(u))
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return nil, fmt.Errorf("proxy URL %q must name a host and port", Redact(u))
+	}
+	return u, nil
+}
+
+// Auth returns the SOCKS credentials of a proxy, if any
+func Auth(u *url.URL) (string, string) {
+	if u == nil || u.User == nil {
+		return "", ""
+	}
+	pass, _ := u.User.Password()
+	return u.User.Username(), pass
+}
+
+// Redact returns a proxy URL without its password, for logs and errors
+func Redact(u *url.URL) string {
+	if u == nil {
+		return Direct
+	}
+	return u.Redacted()
+}
+
+// Dialer returns a dialer that connects through a proxy, or directly when
+// the proxy is nil
+func Dialer(u *url.URL) (proxy.ContextDialer, error) {
+	direct := &net.Dialer{Timeout: 30 * time.Second}
+	if u == nil {
+		return direct, nil
+	}
+
+	var auth *proxy.Auth
+	if user, pass := Auth(u); user != "" {
+		auth = &proxy.Auth{User: user, Password: pass}
+	}
+
+	dialer, err := proxy.SOCKS5("tcp", u.Host, auth, direct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create proxy dialer: %w", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("proxy dialer does not support contexts")
+	}
+	return contextDialer, nil
+}
+
+// DialContext returns a dial function for clients that take one, such as
+// gRPC, connecting through a proxy
+func DialContext(u *url.URL) (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	dialer, err := Dialer(u)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}, nil
+}
+
+// HTTPClient returns an HTTP client connecting through a proxy
+func HTTPClient(u *url.URL, timeout time.Duration) (*http.Client, error) {
+	dialer, err := Dialer(u)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// Describe lists the proxy of every backend, for the startup log
+func (c Config) Describe() string {
+	names := make([]string, 0, len(backends))
+	for backend := range backends {
+		names = append(names, backend)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, backend := range names {
+		parts = append(parts, backend+"="+Redact(c.For(backend)))
+	}
+	return strings.Join(parts, " ")
+}
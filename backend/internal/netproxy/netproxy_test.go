@@ -0,0 +1,40 @@
+// internal/netproxy/netproxy_test.go
+package netproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor(t *testing.T) {
+	cfg := Config{
+		URL: "socks5h://127.0.0.1:9050",
+		Backends: map[string]string{
+			Bitcoin:  Direct,
+			Webhooks: "socks5://webhooks:x@127.0.0.1:9050",
+		},
+	}
+	require.NoError(t, cfg.Validate())
+
+	assert.Nil(t, cfg.For(Bitcoin))
+	assert.Equal(t, "127.0.0.1:9050", cfg.For(FeeAPI).Host)
+
+	webhooks := cfg.For(Webhooks)
+	user, pass := Auth(webhooks)
+	assert.Equal(t, "webhooks", user)
+	assert.Equal(t, "x", pass)
+	assert.NotContains(t, Redact(webhooks), ":x@")
+
+	assert.Nil(t, Config{}.For(ArkASP))
+	assert.Equal(t, "ark_asp=direct bitcoin=direct fee_api=direct price_api=direct webhooks=direct", Config{}.Describe())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.Error(t, Config{URL: "http://127.0.0.1:8080"}.Validate())
+	assert.Error(t, Config{URL: "socks5://127.0.0.1"}.Validate())
+	assert.Error(t, Config{Backends: map[string]string{"smtp": Direct}}.Validate())
+	assert.Error(t, Config{Backends: map[string]string{FeeAPI: "tor"}}.Validate())
+}
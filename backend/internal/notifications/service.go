@@ -0,0 +1,251 @@
+// internal/notifications/service.go
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/events"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/margin"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var logger = logging.Component(logging.Notifications)
+
+// Channel is the websocket channel carrying a user's new notifications
+func Channel(userID uuid.UUID) string {
+	return "notifications:" + userID.String()
+}
+
+// Publisher pushes messages to subscribers of a websocket channel
+type Publisher interface {
+	PublishToChannel(channel string, message interface{})
+}
+
+// Service keeps a notification for each fill, contract activation,
+// settlement, ASP outage and margin call that concerns a user until they
+// mark it read, and optionally pushes each one to the user's websocket
+// channel as it is created
+type Service struct {
+	repo      *db.NotificationRepository
+	publisher Publisher
+}
+
+// NewService creates a new notification service
+func NewService(repo *db.NotificationRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// WithPublisher pushes new notifications to each user's notifications channel
+func (s *Service) WithPublisher(publisher Publisher) *Service {
+	s.publisher = publisher
+	return s
+}
+
+// List retrieves a page of a user's notifications, newest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*models.Notification, error) {
+	return s.repo.ListByUser(ctx, userID, unreadOnly, limit, offset)
+}
+
+// UnreadCount counts the notifications a user has not read
+func (s *Service) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks a notification of a user read
+func (s *Service) MarkRead(ctx context.Context, userID, id uuid.UUID) (*models.Notification, error) {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead marks every notification of a user read and returns how many
+// were unread
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// Subscribe notifies the parties of contracts that become active, and users
+// with open contracts when the ASP goes down
+func (s *Service) Subscribe(ctx context.Context, bus *events.Bus) {
+	bus.Subscribe(events.TopicContracts, func(event events.Event) {
+		e, ok := event.Payload.(events.ContractEvent)
+		if !ok || e.Type != events.ContractStatusChanged || e.To != models.ContractStatusActive {
+			return
+		}
+		s.notifyParties(ctx, e.ContractID, func(db.ContractParty) *models.Notification {
+			return &models.Notification{
+				Type:  models.NotificationContractActivated,
+				Title: "Contract active",
+				Body:  fmt.Sprintf("Contract %s is set up and active", e.ContractID.String()[:8]),
+			}
+		})
+	})
+
+	bus.Subscribe(events.TopicASPStatus, func(event events.Event) {
+		if e, ok := event.Payload.(events.ASPStatusEvent); ok {
+			s.onASPStatus(ctx, e)
+		}
+	})
+}
+
+// onASPStatus notifies users with open contracts that the ASP stopped
+// responding, or that contracts fell back to on-chain settlement
+func (s *Service) onASPStatus(ctx context.Context, e events.ASPStatusEvent) {
+	if e.Available && !e.OnChainOnly {
+		return
+	}
+
+	n := &models.Notification{
+		Type:  models.NotificationASPOutage,
+		Title: "ASP unavailable",
+		Body:  "The Ark service provider is not responding. Open contracts are safe and settle on chain if it does not return.",
+	}
+	if e.OnChainOnly {
+		n.Title = "Contracts moved on chain"
+		n.Body = "The Ark service provider has been unreachable for too long. Open contracts will settle on chain."
+	}
+	n.Data = encode(e)
+
+	created, err := s.repo.CreateForOpenContracts(ctx, n)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to create ASP outage notifications")
+		return
+	}
+	for _, n := range created {
+		s.publish(n)
+	}
+}
+
+// OnFill implements orderbook.FillObserver by notifying both sides of a trade
+func (s *Service) OnFill(ctx context.Context, fill orderbook.Fill) {
+	for _, order := range []models.Order{fill.BuyOrder, fill.SellOrder} {
+		title := "Order partially filled"
+		if order.Status == models.OrderStatusFilled {
+			title = "Order filled"
+		}
+
+		contractID := fill.Contract.ID
+		s.notify(ctx, &models.Notification{
+			UserID:     order.UserID,
+			Type:       models.NotificationOrderFill,
+			Title:      title,
+			Body:       fmt.Sprintf("%s %d %s @ %d sats", order.Side, fill.Trade.Quantity, fill.Contract.ContractType, fill.Trade.Price),
+			ContractID: &contractID,
+			Data: encode(map[string]interface{}{
+				"order_id": order.ID,
+				"trade_id": fill.Trade.ID,
+				"price":    fill.Trade.Price,
+				"quantity": fill.Trade.Quantity,
+			}),
+		})
+	}
+}
+
+// OnContractSettled implements contract.SettlementObserver by telling each
+// party whether they won
+func (s *Service) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	s.notifyParties(ctx, contract.ID, func(party db.ContractParty) *models.Notification {
+		return settlementNotification(contract, party.Side, buyerWins)
+	})
+}
+
+// OnMarginEvent implements margin.Observer by notifying the position's writer
+func (s *Service) OnMarginEvent(ctx context.Context, userID uuid.UUID, eventType string, event margin.Event) {
+	n := marginNotification(eventType, event)
+	n.UserID = userID
+	s.notify(ctx, n)
+}
+
+// settlementNotification tells the party on side of a settled contract
+// whether they won
+func settlementNotification(contract *models.Contract, side models.OrderSide, buyerWins bool) *models.Notification {
+	won := (side == models.OrderSideBuy) == buyerWins
+	n := &models.Notification{
+		Type:  models.NotificationSettlement,
+		Title: "Contract settled: you lost",
+		Body:  fmt.Sprintf("%s contract %s settled in the other party's favour", contract.ContractType, contract.ID.String()[:8]),
+		Data:  encode(map[string]interface{}{"buyer_wins": buyerWins, "won": won}),
+	}
+	if won {
+		n.Title = "Contract settled: you won"
+		n.Body = fmt.Sprintf("%s contract %s settled in your favour", contract.ContractType, contract.ID.String()[:8])
+	}
+	return n
+}
+
+// marginNotification describes a margin call or liquidation to the
+// position's writer
+func marginNotification(eventType string, event margin.Event) *models.Notification {
+	contractID := event.ContractID
+	n := &models.Notification{
+		Type:       models.NotificationMarginCall,
+		Title:      "Margin call",
+		Body:       fmt.Sprintf("Collateral of %d sats is below the %d sats required", event.Collateral, event.RequiredMargin),
+		ContractID: &contractID,
+		Data:       encode(map[string]interface{}{"event": eventType, "margin": event}),
+	}
+	if event.LiquidateAt != nil {
+		n.Body += fmt.Sprintf("; top up before %s to avoid liquidation", event.LiquidateAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	if eventType == margin.EventLiquidation {
+		n.Title = "Position liquidated"
+		n.Body = fmt.Sprintf("The margin call went unmet and the contract was exited on chain, forfeiting %d sats of collateral", event.Collateral)
+	}
+	return n
+}
+
+// notifyParties notifies each party of a contract with the notification
+// build returns for them
+func (s *Service) notifyParties(ctx context.Context, contractID uuid.UUID, build func(party db.ContractParty) *models.Notification) {
+	parties, err := s.repo.ListContractParties(ctx, contractID)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contractID.String()).Msg("Failed to load contract parties")
+		return
+	}
+
+	for _, party := range parties {
+		n := build(party)
+		n.UserID = party.UserID
+		n.ContractID = &contractID
+		s.notify(ctx, n)
+	}
+}
+
+// notify stores a notification and pushes it to the user
+func (s *Service) notify(ctx context.Context, n *models.Notification) {
+	if err := s.repo.Create(ctx, n); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", n.UserID.String()).
+			Str("type", string(n.Type)).
+			Msg("Failed to create notification")
+		return
+	}
+	s.publish(n)
+}
+
+// publish pushes a stored notification to its user's channel
+func (s *Service) publish(n *models.Notification) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.PublishToChannel(Channel(n.UserID), map[string]interface{}{
+		"type":    "notification",
+		"payload": n,
+	})
+}
+
+// encode marshals notification data, which is informational, so a value
+// that cannot be encoded is left out rather than failing the notification
+func encode(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
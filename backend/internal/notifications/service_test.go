@@ -0,0 +1,67 @@
+// internal/notifications/service_test.go
+package notifications
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/margin"
+	"hashhedge/internal/models"
+)
+
+func TestSettlementNotification(t *testing.T) {
+	contract := &models.Contract{ID: uuid.New(), ContractType: models.ContractTypeCall}
+
+	for _, tc := range []struct {
+		side      models.OrderSide
+		buyerWins bool
+		won       bool
+	}{
+		{models.OrderSideBuy, true, true},
+		{models.OrderSideBuy, false, false},
+		{models.OrderSideSell, true, false},
+		{models.OrderSideSell, false, true},
+	} {
+		n := settlementNotification(contract, tc.side, tc.buyerWins)
+		assert.Equal(t, models.NotificationSettlement, n.Type)
+
+		var data map[string]bool
+		require.NoError(t, json.Unmarshal(n.Data, &data))
+		assert.Equal(t, tc.won, data["won"], "%s with buyer wins %v", tc.side, tc.buyerWins)
+		assert.Equal(t, tc.buyerWins, data["buyer_wins"])
+
+		if tc.won {
+			assert.Contains(t, n.Title, "you won")
+		} else {
+			assert.Contains(t, n.Title, "you lost")
+		}
+	}
+}
+
+func TestMarginNotification(t *testing.T) {
+	deadline := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := margin.Event{
+		PositionID:     uuid.New(),
+		ContractID:     uuid.New(),
+		Collateral:     40_000,
+		RequiredMargin: 50_000,
+		LiquidateAt:    &deadline,
+	}
+
+	call := marginNotification(margin.EventMarginCall, event)
+	assert.Equal(t, models.NotificationMarginCall, call.Type)
+	assert.Equal(t, "Margin call", call.Title)
+	assert.Contains(t, call.Body, "2024-05-01 12:00 UTC")
+	require.NotNil(t, call.ContractID)
+	assert.Equal(t, event.ContractID, *call.ContractID)
+
+	event.LiquidateAt = nil
+	liquidation := marginNotification(margin.EventLiquidation, event)
+	assert.Equal(t, "Position liquidated", liquidation.Title)
+	assert.Contains(t, liquidation.Body, "40000 sats")
+}
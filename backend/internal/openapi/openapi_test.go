@@ -0,0 +1,247 @@
+// internal/openapi/openapi_test.go
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refPattern matches the component names of refs in a rendered document
+var refPattern = regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`)
+
+func fieldErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var validation *ValidationError
+	require.True(t, errors.As(err, &validation), "expected a validation error, got %v", err)
+
+	fields := make(map[string]string)
+	for _, fe := range validation.Errors {
+		fields[fe.Field] = fe.Message
+	}
+	return fields
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema := Object(map[string]*Schema{
+		"side":     String().OneOf("buy", "sell"),
+		"quantity": Integer().Positive(),
+		"price":    Number().Min(0).Max(100),
+		"key_id":   UUID().OrNull(),
+		"at":       DateTime(),
+		"name":     String().Length(1, 5),
+		"hex":      String().Matching("^[0-9a-f]+$"),
+		"inputs":   ArrayOf(String().NonEmpty()).Count(1, 2),
+		"nested":   Object(map[string]*Schema{"offset": Integer()}, "offset"),
+	}, "side", "quantity")
+
+	t.Run("valid", func(t *testing.T) {
+		body := `{"side":"buy","quantity":3,"price":99.5,"key_id":null,"at":"2024-01-02T03:04:05Z",
+			"name":"abc","hex":"00ff","inputs":["aa"],"nested":{"offset":-1},"unknown":true}`
+		assert.NoError(t, schema.ValidateJSON([]byte(body)))
+	})
+
+	t.Run("every mismatch is reported", func(t *testing.T) {
+		body := `{"side":"hold","price":101,"key_id":"nope","at":"yesterday","name":"",
+			"hex":"xyz","inputs":[],"nested":{}}`
+		fields := fieldErrors(t, schema.ValidateJSON([]byte(body)))
+
+		assert.Equal(t, map[string]string{
+			"quantity":      "is required",
+			"side":          "must be one of buy, sell",
+			"price":         "must be at most 100",
+			"key_id":        "must be a UUID",
+			"at":            "must be an RFC 3339 time",
+			"name":          "must not be empty",
+			"hex":           "must match ^[0-9a-f]+$",
+			"inputs":        "must have at least 1 items",
+			"nested.offset": "is required",
+		}, fields)
+	})
+
+	t.Run("types", func(t *testing.T) {
+		body := `{"side":1,"quantity":1.5,"inputs":["a",""],"nested":[]}`
+		fields := fieldErrors(t, schema.ValidateJSON([]byte(body)))
+
+		assert.Equal(t, "must be a string", fields["side"])
+		assert.Equal(t, "must be an integer", fields["quantity"])
+		assert.Equal(t, "must not be empty", fields["inputs[1]"])
+		assert.Equal(t, "must be an object", fields["nested"])
+	})
+
+	t.Run("exclusive minimum", func(t *testing.T) {
+		fields := fieldErrors(t, schema.ValidateJSON([]byte(`{"side":"sell","quantity":0}`)))
+		assert.Equal(t, map[string]string{"quantity": "must be greater than 0"}, fields)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, body := range []string{``, `{`, `{} {}`} {
+			fields := fieldErrors(t, schema.ValidateJSON([]byte(body)))
+			assert.Contains(t, fields, rootField, body)
+		}
+
+		fields := fieldErrors(t, schema.ValidateJSON([]byte(`[]`)))
+		assert.Equal(t, "must be an object", fields[rootField])
+	})
+
+	t.Run("error message", func(t *testing.T) {
+		err := schema.ValidateJSON([]byte(`{"side":"buy"}`))
+		assert.EqualError(t, err, "quantity: is required")
+	})
+}
+
+func TestFind(t *testing.T) {
+	spec := &Spec{Operations: []Operation{
+		{Method: http.MethodGet, Path: "/users/{id}/webhooks/{webhookId}"},
+		{Method: http.MethodGet, Path: "/users/{id}/webhooks/dead-letters"},
+		{Method: http.MethodDelete, Path: "/users/{id}/webhooks/{webhookId}"},
+		{Method: http.MethodGet, Path: "/hashrate"},
+	}}
+
+	op := spec.Find(http.MethodGet, "/users/42/webhooks/dead-letters")
+	require.NotNil(t, op)
+	assert.Equal(t, "/users/{id}/webhooks/dead-letters", op.Path, "literal segments win")
+
+	op = spec.Find(http.MethodGet, "/users/42/webhooks/7")
+	require.NotNil(t, op)
+	assert.Equal(t, "/users/{id}/webhooks/{webhookId}", op.Path)
+
+	op = spec.Find(http.MethodDelete, "/users/42/webhooks/7")
+	require.NotNil(t, op)
+	assert.Equal(t, http.MethodDelete, op.Method)
+
+	assert.NotNil(t, spec.Find(http.MethodGet, "/hashrate/"), "trailing slash")
+	assert.Nil(t, spec.Find(http.MethodPost, "/hashrate"))
+	assert.Nil(t, spec.Find(http.MethodGet, "/users//webhooks/7"))
+	assert.Nil(t, spec.Find(http.MethodGet, "/users/42"))
+}
+
+func TestSchemaOf(t *testing.T) {
+	type base struct {
+		ID uuid.UUID `json:"id"`
+	}
+	type sample struct {
+		base
+		Name      string          `json:"name"`
+		Count     int             `json:"count,omitempty"`
+		Big       int64           `json:"big,string"`
+		Rate      float64         `json:"rate"`
+		At        time.Time       `json:"at"`
+		Ends      *time.Time      `json:"ends"`
+		Tags      []string        `json:"tags"`
+		Raw       json.RawMessage `json:"raw"`
+		Blob      []byte          `json:"blob"`
+		Flags     map[string]bool `json:"flags"`
+		Secret    string          `json:"-"`
+		Untagged  bool
+		unexposed string
+	}
+
+	schema := SchemaOf(sample{})
+	require.Equal(t, "object", schema.Type)
+
+	props := schema.Properties
+	assert.Equal(t, UUID(), props["id"], "embedded fields are flattened")
+	assert.Equal(t, String(), props["name"])
+	assert.Equal(t, Integer(), props["count"])
+	assert.Equal(t, String(), props["big"])
+	assert.Equal(t, Number(), props["rate"])
+	assert.Equal(t, DateTime(), props["at"])
+	assert.Equal(t, DateTime().OrNull(), props["ends"])
+	assert.Equal(t, ArrayOf(String()), props["tags"])
+	assert.Equal(t, Any(), props["raw"])
+	assert.Equal(t, "byte", props["blob"].Format)
+	assert.Equal(t, MapOf(Boolean()), props["flags"])
+	assert.Equal(t, Boolean(), props["Untagged"])
+	assert.NotContains(t, props, "Secret")
+	assert.NotContains(t, props, "unexposed")
+}
+
+func TestOperationID(t *testing.T) {
+	assert.Equal(t, "postContractsIdSetup",
+		operationID(Operation{Method: http.MethodPost, Path: "/contracts/{id}/setup"}))
+	assert.Equal(t, "getUsersIdWebhooksDeadLetters",
+		operationID(Operation{Method: http.MethodGet, Path: "/users/{id}/webhooks/dead-letters"}))
+}
+
+func TestV1(t *testing.T) {
+	ids := make(map[string]bool)
+	routes := make(map[string]bool)
+	for _, op := range V1.Operations {
+		route := op.Method + " " + op.Path
+		assert.False(t, routes[route], "duplicate route %s", route)
+		routes[route] = true
+
+		id := operationID(op)
+		assert.False(t, ids[id], "duplicate operation ID %s", id)
+		ids[id] = true
+
+		assert.NotEmpty(t, op.Summary, route)
+		assert.NotEmpty(t, op.Tag, route)
+		if op.Body != nil {
+			assert.Contains(t, []string{http.MethodPost, http.MethodPut, http.MethodPatch}, op.Method, route)
+		}
+	}
+
+	// Every referenced component is defined
+	document, err := json.Marshal(V1.Document())
+	require.NoError(t, err)
+
+	var parsed struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(document, &parsed))
+	for _, match := range refPattern.FindAllStringSubmatch(string(document), -1) {
+		assert.Contains(t, parsed.Components.Schemas, match[1])
+	}
+}
+
+func TestV1PlaceOrder(t *testing.T) {
+	op := V1.Find(http.MethodPost, "/orders")
+	require.NotNil(t, op)
+
+	valid := `{"side":"BUY","contract_type":"call","strike_hash_rate":350,
+		"start_block_height":800000,"end_block_height":802016,"price":1000,"quantity":1}`
+	assert.NoError(t, op.Body.ValidateJSON([]byte(valid)))
+
+	fields := fieldErrors(t, op.Body.ValidateJSON([]byte(`{"side":"hold","quantity":0,"time_in_force":"day"}`)))
+	assert.Equal(t, "must be one of buy, sell, BUY, SELL", fields["side"])
+	assert.Equal(t, "must be greater than 0", fields["quantity"])
+	assert.Contains(t, fields, "time_in_force")
+	assert.Equal(t, "is required", fields["strike_hash_rate"])
+}
+
+func TestV1Paged(t *testing.T) {
+	document, err := json.Marshal(V1.Document())
+	require.NoError(t, err)
+
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]json.RawMessage `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(document, &parsed))
+
+	envelope := func(path string) map[string]json.RawMessage {
+		return parsed.Paths[path]["get"].Responses["200"].Content["application/json"].Schema.Properties
+	}
+	assert.Contains(t, envelope("/contracts"), "page")
+	assert.Contains(t, envelope("/contracts/{id}/exit-transactions"), "page")
+	assert.NotContains(t, envelope("/contracts/{id}/timeline"), "page")
+}
@@ -0,0 +1,102 @@
+// internal/openapi/reflect.go
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// SchemaOf derives the schema of the JSON encoding of a Go value from its
+// type and json tags. It describes responses; request schemas are written
+// out by hand since they carry validation rules a type cannot express.
+func SchemaOf(v interface{}) *Schema {
+	return schemaOfType(reflect.TypeOf(v))
+}
+
+func schemaOfType(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return DateTime()
+	case uuidType:
+		return UUID()
+	case rawMessageType:
+		return Any()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaOfType(t.Elem()).OrNull()
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return ArrayOf(schemaOfType(t.Elem()))
+	case reflect.Map:
+		return MapOf(schemaOfType(t.Elem()))
+	case reflect.String:
+		return String()
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer()
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	default:
+		return Any()
+	}
+}
+
+// structSchema describes the exported, JSON encoded fields of a struct,
+// flattening embedded structs without a json name as encoding/json does
+func structSchema(t reflect.Type) *Schema {
+	schema := Object(make(map[string]*Schema))
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for property, s := range structSchema(embedded).Properties {
+					schema.Properties[property] = s
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaOfType(field.Type)
+		if options == "string" {
+			property = String()
+		}
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
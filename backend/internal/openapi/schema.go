@@ -0,0 +1,147 @@
+// internal/openapi/schema.go
+package openapi
+
+// Schema is an OpenAPI 3.0 schema object. Request body schemas are inline
+// so they can be validated on their own; response schemas may refer to the
+// components of the spec with Ref.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// Object is an object schema with the given properties, of which the named
+// ones are required
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// MapOf is an object schema whose every property matches values
+func MapOf(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+// ArrayOf is an array schema of items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// String is a string schema
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+// Integer is a 64-bit integer schema
+func Integer() *Schema {
+	return &Schema{Type: "integer", Format: "int64"}
+}
+
+// Number is a floating point schema
+func Number() *Schema {
+	return &Schema{Type: "number", Format: "double"}
+}
+
+// Boolean is a boolean schema
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// UUID is a string schema holding a UUID
+func UUID() *Schema {
+	return &Schema{Type: "string", Format: "uuid"}
+}
+
+// DateTime is a string schema holding an RFC 3339 time
+func DateTime() *Schema {
+	return &Schema{Type: "string", Format: "date-time"}
+}
+
+// Any is a schema accepting any value
+func Any() *Schema {
+	return &Schema{}
+}
+
+// Ref refers to the named component schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// Describe sets the description of s
+func (s *Schema) Describe(description string) *Schema {
+	s.Description = description
+	return s
+}
+
+// OneOf restricts s to the given values
+func (s *Schema) OneOf(values ...interface{}) *Schema {
+	s.Enum = values
+	return s
+}
+
+// Min sets the inclusive minimum of a numeric schema
+func (s *Schema) Min(min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+// Positive restricts a numeric schema to values above zero
+func (s *Schema) Positive() *Schema {
+	s.ExclusiveMinimum = true
+	return s.Min(0)
+}
+
+// Max sets the inclusive maximum of a numeric schema
+func (s *Schema) Max(max float64) *Schema {
+	s.Maximum = &max
+	return s
+}
+
+// Length bounds the length of a string schema; a negative max leaves it unbounded
+func (s *Schema) Length(min, max int) *Schema {
+	s.MinLength = &min
+	if max >= 0 {
+		s.MaxLength = &max
+	}
+	return s
+}
+
+// NonEmpty requires a string schema to have at least one character
+func (s *Schema) NonEmpty() *Schema {
+	return s.Length(1, -1)
+}
+
+// Matching restricts a string schema to values matching an ECMA-262 pattern
+func (s *Schema) Matching(pattern string) *Schema {
+	s.Pattern = pattern
+	return s
+}
+
+// Count bounds the items of an array schema; a negative max leaves it unbounded
+func (s *Schema) Count(min, max int) *Schema {
+	s.MinItems = &min
+	if max >= 0 {
+		s.MaxItems = &max
+	}
+	return s
+}
+
+// OrNull allows null in place of a value of s
+func (s *Schema) OrNull() *Schema {
+	s.Nullable = true
+	return s
+}
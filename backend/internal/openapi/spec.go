@@ -0,0 +1,297 @@
+// internal/openapi/spec.go
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Auth is how the caller of an operation authenticates
+type Auth int
+
+const (
+	// AuthUser operations take a bearer access token or an API key
+	AuthUser Auth = iota
+	// AuthNone operations are public
+	AuthNone
+	// AuthWatchtower operations take a watchtower token
+	AuthWatchtower
+)
+
+// Parameter is a query parameter of an operation. Path parameters are
+// derived from the path template.
+type Parameter struct {
+	Name        string
+	Description string
+	Schema      *Schema
+}
+
+// Operation is an endpoint of the API
+type Operation struct {
+	Method string
+	// Path is relative to the version root, with parameters in braces as
+	// in the router
+	Path    string
+	Summary string
+	Tag     string
+	Auth    Auth
+	Query   []Parameter
+	// Body is the schema request bodies are validated against; nil when
+	// the operation takes no body
+	Body *Schema
+	// Status is the status of a successful response, 200 when zero
+	Status int
+	// Response is the schema of the data of a successful response
+	Response *Schema
+	// Paged marks a listing paged by cursor, whose successful response
+	// carries a PageInfo next to the data
+	Paged bool
+}
+
+// Spec is the definition of a version of the API
+type Spec struct {
+	Title       string
+	Version     string
+	Description string
+	// BasePath is where the version is mounted, such as /api/v1
+	BasePath   string
+	Operations []Operation
+	Components map[string]*Schema
+}
+
+// pathParam matches the parameters of a path template
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Find returns the operation serving a request path relative to the version
+// root, or nil. Literal path segments take precedence over parameters, as
+// in the router.
+func (s *Spec) Find(method, path string) *Operation {
+	segments := splitPath(path)
+
+	var best *Operation
+	bestLiterals := -1
+	for i := range s.Operations {
+		op := &s.Operations[i]
+		if op.Method != method {
+			continue
+		}
+
+		literals, ok := matchPath(splitPath(op.Path), segments)
+		if ok && literals > bestLiterals {
+			best, bestLiterals = op, literals
+		}
+	}
+
+	return best
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// matchPath reports whether segments match a path template and how many
+// of the template's segments are literal
+func matchPath(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+
+	literals := 0
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") {
+			if segments[i] == "" {
+				return 0, false
+			}
+			continue
+		}
+		if part != segments[i] {
+			return 0, false
+		}
+		literals++
+	}
+
+	return literals, true
+}
+
+// Document renders the spec as an OpenAPI 3.0 document
+func (s *Spec) Document() map[string]interface{} {
+	paths := make(map[string]map[string]interface{})
+	for _, op := range s.Operations {
+		item, ok := paths[op.Path]
+		if !ok {
+			item = make(map[string]interface{})
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = s.operation(op)
+	}
+
+	tags := make(map[string]bool)
+	for _, op := range s.Operations {
+		if op.Tag != "" {
+			tags[op.Tag] = true
+		}
+	}
+	tagNames := make([]string, 0, len(tags))
+	for tag := range tags {
+		tagNames = append(tagNames, tag)
+	}
+	sort.Strings(tagNames)
+	tagList := make([]map[string]string, 0, len(tagNames))
+	for _, tag := range tagNames {
+		tagList = append(tagList, map[string]string{"name": tag})
+	}
+
+	components := map[string]*Schema{
+		"Error": Object(map[string]*Schema{
+			"success": Boolean(),
+			"error":   String(),
+			"data":    Any(),
+		}, "success", "error"),
+		"PageInfo": Object(map[string]*Schema{
+			"total":       Integer().Min(0).Describe("Items across every page"),
+			"has_more":    Boolean(),
+			"next_cursor": String().Describe("Pass as cursor to fetch the next page; absent on the last page"),
+		}, "total", "has_more"),
+		"ValidationErrors": Object(map[string]*Schema{
+			"errors": ArrayOf(Object(map[string]*Schema{
+				"field":   String(),
+				"message": String(),
+			}, "field", "message")),
+		}, "errors"),
+	}
+	for name, schema := range s.Components {
+		components[name] = schema
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":       s.Title,
+			"version":     s.Version,
+			"description": s.Description,
+		},
+		"servers": []map[string]string{{"url": s.BasePath}},
+		"tags":    tagList,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]string{"type": "http", "scheme": "bearer"},
+				"apiKey":     map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"watchtowerToken": map[string]string{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-Watchtower-Token",
+				},
+			},
+		},
+	}
+}
+
+// operation renders an operation object
+func (s *Spec) operation(op Operation) map[string]interface{} {
+	rendered := map[string]interface{}{
+		"operationId": operationID(op),
+		"summary":     op.Summary,
+	}
+	if op.Tag != "" {
+		rendered["tags"] = []string{op.Tag}
+	}
+	switch op.Auth {
+	case AuthUser:
+		rendered["security"] = []map[string][]string{{"bearerAuth": {}}, {"apiKey": {}}}
+	case AuthWatchtower:
+		rendered["security"] = []map[string][]string{{"watchtowerToken": {}}}
+	}
+
+	var params []map[string]interface{}
+	for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   String(),
+		})
+	}
+	for _, param := range op.Query {
+		params = append(params, map[string]interface{}{
+			"name":        param.Name,
+			"in":          "query",
+			"description": param.Description,
+			"schema":      param.Schema,
+		})
+	}
+	if len(params) > 0 {
+		rendered["parameters"] = params
+	}
+
+	if op.Body != nil {
+		rendered["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  map[string]interface{}{"application/json": map[string]*Schema{"schema": op.Body}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	data := op.Response
+	if data == nil {
+		data = Any()
+	}
+
+	envelope := map[string]*Schema{
+		"success": Boolean(),
+		"data":    data,
+	}
+	if op.Paged {
+		envelope["page"] = Ref("PageInfo")
+	}
+
+	errorResponse := map[string]interface{}{
+		"description": "Error",
+		"content":     map[string]interface{}{"application/json": map[string]*Schema{"schema": Ref("Error")}},
+	}
+	responses := map[string]interface{}{
+		strconv.Itoa(status): map[string]interface{}{
+			"description": http.StatusText(status),
+			"content":     map[string]interface{}{"application/json": map[string]*Schema{"schema": Object(envelope, "success")}},
+		},
+		"default": errorResponse,
+	}
+	if op.Body != nil {
+		responses["400"] = map[string]interface{}{
+			"description": "The request body does not match its schema",
+			"content": map[string]interface{}{"application/json": map[string]*Schema{"schema": Object(map[string]*Schema{
+				"success": Boolean(),
+				"error":   String(),
+				"data":    Ref("ValidationErrors"),
+			}, "success", "error")}},
+		}
+	}
+	rendered["responses"] = responses
+
+	return rendered
+}
+
+// operationID derives a stable operation ID from the method and path, such
+// as postContractsIdSetup
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, segment := range splitPath(op.Path) {
+		segment = strings.Trim(segment, "{}")
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
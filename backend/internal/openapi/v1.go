@@ -0,0 +1,873 @@
+// internal/openapi/v1.go
+package openapi
+
+import (
+	"net/http"
+
+	"hashhedge/internal/models"
+)
+
+// Shared request schemas
+var (
+	contractTypes = []interface{}{"CALL", "PUT"}
+	// Orders accept their enumerations in either case
+	orderSides        = []interface{}{"buy", "sell", "BUY", "SELL"}
+	orderTypes        = []interface{}{"limit", "market", "LIMIT", "MARKET"}
+	orderTimeInForces = []interface{}{"gtc", "ioc", "fok", "GTC", "IOC", "FOK"}
+	orderContractType = []interface{}{"call", "put", "CALL", "PUT"}
+)
+
+func pubKey() *Schema {
+	return String().NonEmpty().Describe("Hex encoded public key")
+}
+
+func blockHeight() *Schema {
+	return Integer().Positive()
+}
+
+func satoshis() *Schema {
+	return Integer().Positive().Describe("In satoshis")
+}
+
+func message() *Schema {
+	return String().Describe("Confirmation message")
+}
+
+func limitParam(max float64) Parameter {
+	return Parameter{Name: "limit", Description: "Page size", Schema: Integer().Positive().Max(max)}
+}
+
+func offsetParam() Parameter {
+	return Parameter{Name: "offset", Description: "Items to skip", Schema: Integer().Min(0)}
+}
+
+func cursorParam() Parameter {
+	return Parameter{Name: "cursor", Description: "next_cursor of the previous page; omit for the first page", Schema: String()}
+}
+
+func sinceParam() Parameter {
+	return Parameter{Name: "since", Description: "Only items at or after this time", Schema: DateTime()}
+}
+
+func marketParams() []Parameter {
+	return []Parameter{
+		{Name: "type", Schema: String().OneOf(contractTypes...)},
+		{Name: "strike_hash_rate", Description: "In EH/s", Schema: Number().Positive()},
+		{Name: "start_block_height", Schema: blockHeight()},
+		{Name: "end_block_height", Schema: blockHeight()},
+	}
+}
+
+// V1 is version 1 of the API
+var V1 = &Spec{
+	Title:       "HashHedge API",
+	Version:     "v1",
+	Description: "Hash rate derivatives settled on Bitcoin and Ark.",
+	BasePath:    "/api/v1",
+	Components: map[string]*Schema{
+		"User":                  SchemaOf(models.User{}),
+		"UserKey":               SchemaOf(models.UserKey{}),
+		"Contract":              SchemaOf(models.Contract{}),
+		"ContractTransaction":   SchemaOf(models.ContractTransaction{}),
+		"ContractInput":         SchemaOf(models.ContractInput{}),
+		"ContractFunding":       SchemaOf(models.ContractFunding{}),
+		"ContractPayoutAddress": SchemaOf(models.ContractPayoutAddress{}),
+		"Order":                 SchemaOf(models.Order{}),
+		"MarketTrade":           SchemaOf(models.MarketTrade{}),
+		"Candle":                SchemaOf(models.Candle{}),
+		"AutoRoll":              SchemaOf(models.AutoRoll{}),
+		"PriceAlert":            SchemaOf(models.PriceAlert{}),
+		"WatchlistItem":         SchemaOf(models.WatchlistItem{}),
+		"DeviceToken":           SchemaOf(models.DeviceToken{}),
+		"PushPreference":        SchemaOf(models.PushPreference{}),
+		"PayoutAddress":         SchemaOf(models.PayoutAddress{}),
+		"APIKey":                SchemaOf(models.APIKey{}),
+		"APIUsage":              SchemaOf(models.APIUsage{}),
+		"UsageAggregate":        SchemaOf(models.UsageAggregate{}),
+		"MarketMakerProtection": SchemaOf(models.MarketMakerProtection{}),
+		"MarginAccount":         SchemaOf(models.MarginAccount{}),
+		"MarginPosition":        SchemaOf(models.MarginPosition{}),
+		"Balance":               SchemaOf(models.Balance{}),
+		"LedgerEntry":           SchemaOf(models.LedgerEntry{}),
+		"DepositAddress":        SchemaOf(models.DepositAddress{}),
+		"Deposit":               SchemaOf(models.Deposit{}),
+		"Withdrawal":            SchemaOf(models.Withdrawal{}),
+		"ContractListing":       SchemaOf(models.ContractListing{}),
+		"OracleEvent":           SchemaOf(models.OracleEvent{}),
+		"ScheduledClose":        SchemaOf(models.ScheduledClose{}),
+		"SettlementAttempt":     SchemaOf(models.SettlementAttempt{}),
+		"SettlementDeferral":    SchemaOf(models.SettlementDeferral{}),
+		"SettlementBroadcast":   SchemaOf(models.SettlementBroadcast{}),
+		"ContractTransition":    SchemaOf(models.ContractTransition{}),
+		"SettlementEvidence":    SchemaOf(models.SettlementEvidence{}),
+		"HashRateObservation":   SchemaOf(models.HashRateObservation{}),
+		"FeedObservation":       SchemaOf(models.FeedObservation{}),
+		"OpenInterestPoint":     SchemaOf(models.OpenInterestPoint{}),
+		"ResearchAccess":        SchemaOf(models.ResearchAccess{}),
+		"Watchtower":            SchemaOf(models.Watchtower{}),
+		"WatchtowerDelegation":  SchemaOf(models.WatchtowerDelegation{}),
+		"Webhook":               SchemaOf(models.Webhook{}),
+		"WebhookDeadLetter":     SchemaOf(models.WebhookDeadLetter{}),
+		"Job":                   SchemaOf(models.Job{}),
+		"AuditEntry":            SchemaOf(models.AuditEntry{}),
+		"Notification":          SchemaOf(models.Notification{}),
+	},
+	Operations: v1Operations(),
+}
+
+func v1Operations() []Operation {
+	var ops []Operation
+	add := func(tag string, group ...Operation) {
+		for _, op := range group {
+			op.Tag = tag
+			ops = append(ops, op)
+		}
+	}
+
+	add("Auth",
+		Operation{
+			Method: http.MethodPost, Path: "/auth/register", Summary: "Register a user account", Auth: AuthNone,
+			Body: Object(map[string]*Schema{
+				"username": String().NonEmpty().Describe("3 to 100 characters"),
+				"email":    String().Length(1, 255),
+				"password": String().NonEmpty(),
+			}, "username", "email", "password"),
+			Status: http.StatusCreated, Response: Ref("User"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/auth/login", Summary: "Exchange a username and password for tokens", Auth: AuthNone,
+			Body: Object(map[string]*Schema{
+				"username": String().NonEmpty(),
+				"password": String().NonEmpty(),
+			}, "username", "password"),
+			Response: Object(map[string]*Schema{"user": Ref("User"), "tokens": Any()}),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/auth/refresh", Summary: "Exchange a refresh token for new tokens", Auth: AuthNone,
+			Body: Object(map[string]*Schema{"refresh_token": String().NonEmpty()}, "refresh_token"),
+		},
+		Operation{Method: http.MethodGet, Path: "/auth/me", Summary: "Get the authenticated user", Response: Ref("User")},
+	)
+
+	add("Contracts",
+		Operation{
+			Method: http.MethodGet, Path: "/contracts", Summary: "List active contracts, newest first with watched ones pinned to the top of each page",
+			Query:    []Parameter{limitParam(500), cursorParam()},
+			Response: ArrayOf(Ref("Contract")), Paged: true,
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts", Summary: "Create a contract directly, without order matching",
+			Body: Object(map[string]*Schema{
+				"contract_type":      String().OneOf(contractTypes...),
+				"strike_hash_rate":   Number().Positive().Describe("In EH/s"),
+				"start_block_height": blockHeight(),
+				"end_block_height":   blockHeight().Describe("Must be above start_block_height"),
+				"target_timestamp":   DateTime().Describe("Must be in the future"),
+				"contract_size":      satoshis(),
+				"premium":            Integer().Min(0).Describe("In satoshis"),
+				"buyer_pub_key":      pubKey(),
+				"seller_pub_key":     pubKey(),
+				"notional": Object(map[string]*Schema{
+					"unit":                String().OneOf(string(models.NotionalUnitContract), string(models.NotionalUnitEHsDay)),
+					"quantity":            Number().Positive(),
+					"settlement_currency": String().Describe("Defaults to " + models.SettlementCurrencyBTC),
+				}, "unit", "quantity").Describe("Defaults to quoting the whole contract as one unit"),
+				"time_mode": String().OneOf(string(models.ContractTimeModeAbsolute), string(models.ContractTimeModeRelative)).
+					Describe("Defaults to ABSOLUTE. RELATIVE contracts run from their setup confirmation, and their heights and target timestamp are estimates until then"),
+				"relative_blocks":  Integer().Positive().Max(models.MaxRelativeBlocks).Describe("Blocks after setup confirmation until the high hash rate path opens; required for RELATIVE"),
+				"relative_seconds": Integer().Positive().Max(models.MaxRelativeSeconds).Describe("Seconds after setup confirmation until the low hash rate path opens, rounded up to 512 second units; required for RELATIVE"),
+			}, "contract_type", "strike_hash_rate", "start_block_height", "end_block_height",
+				"target_timestamp", "contract_size", "buyer_pub_key", "seller_pub_key"),
+			Status: http.StatusCreated, Response: Ref("Contract"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}", Summary: "Get a contract", Response: Ref("Contract")},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/setup", Summary: "Create the setup transaction of a contract",
+			Body: Object(map[string]*Schema{
+				"buyer_inputs":  ArrayOf(String().NonEmpty().Describe("Hex encoded transaction")).Count(1, 49),
+				"seller_inputs": ArrayOf(String().NonEmpty().Describe("Hex encoded transaction")).Count(1, 49),
+			}, "buyer_inputs", "seller_inputs").Describe("At most 50 inputs in all"),
+			Response: Ref("ContractTransaction"),
+		},
+		Operation{Method: http.MethodPost, Path: "/contracts/{id}/final", Summary: "Create the final transaction of a contract", Response: Ref("ContractTransaction")},
+		Operation{Method: http.MethodPost, Path: "/contracts/{id}/settle", Summary: "Settle a contract whose settlement conditions are met"},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/settlement-deferral", Summary: "Get the fee deferral of a contract's settlement", Response: Ref("SettlementDeferral")},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/settlement-broadcast", Summary: "Get the broadcasts and confirmations of a contract's payout", Response: Ref("SettlementBroadcast")},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/fee-bump", Summary: "Offer a priority fee to settle a deferred contract sooner",
+			Body: Object(map[string]*Schema{
+				"pub_key":  pubKey(),
+				"fee_rate": Number().Positive().Describe("In sat/vB"),
+			}, "pub_key", "fee_rate"),
+			Response: Ref("SettlementDeferral"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/payout-address", Summary: "Direct a party's payout from a contract",
+			Body: Object(map[string]*Schema{
+				"pub_key":   pubKey(),
+				"address":   String().NonEmpty(),
+				"signature": String().NonEmpty().Describe("BIP-340 signature over the payout message, hex encoded"),
+			}, "pub_key", "address", "signature"),
+			Response: Ref("ContractPayoutAddress"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/settlement-outputs", Summary: "Get where a contract's settlement pays out"},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/broadcast", Summary: "Broadcast a contract transaction",
+			Body:     Object(map[string]*Schema{"tx_id": UUID()}, "tx_id"),
+			Response: Object(map[string]*Schema{"broadcast_tx_id": String()}),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/swap", Summary: "Swap a participant of a contract",
+			Body: Object(map[string]*Schema{
+				"current_pub_key":       pubKey(),
+				"new_pub_key":           pubKey(),
+				"new_participant_input": String().NonEmpty(),
+			}, "current_pub_key", "new_pub_key", "new_participant_input"),
+			Response: Ref("ContractTransaction"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/funding", Summary: "Get the funding progress of a contract", Response: Ref("ContractFunding")},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/funding", Summary: "Submit a party's funding PSBT",
+			Body: Object(map[string]*Schema{
+				"pub_key": pubKey(),
+				"psbt":    String().NonEmpty().Describe("Base64 encoded PSBT"),
+			}, "pub_key", "psbt"),
+			Response: Ref("ContractFunding"),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/contracts/{id}/inputs", Summary: "List the inputs funding a contract stage",
+			Query:    []Parameter{{Name: "stage", Schema: String().OneOf("setup", "final")}},
+			Response: ArrayOf(Ref("ContractInput")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/inputs", Summary: "Record a separately funded input of a contract",
+			Body: Object(map[string]*Schema{
+				"source":     String().OneOf(string(models.InputSourceUTXO), string(models.InputSourceVTXO)),
+				"txid":       String().Length(64, 64).Matching("^[0-9a-fA-F]+$"),
+				"vout":       Integer().Min(0).Max(4294967295),
+				"value":      satoshis(),
+				"leaf_index": Integer().Min(0).OrNull(),
+			}, "txid", "vout", "value"),
+			Status: http.StatusCreated, Response: Ref("ContractInput"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/timeline", Summary: "Get the lifecycle timeline of a contract"},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/status-history", Summary: "List every status a contract moved through, oldest first", Response: ArrayOf(Ref("ContractTransition"))},
+		Operation{
+			Method: http.MethodGet, Path: "/contracts/{id}/exit-transactions", Summary: "List the emergency exit transactions of a contract, newest first",
+			Query:    []Parameter{limitParam(500), cursorParam()},
+			Response: ArrayOf(Ref("ContractTransaction")), Paged: true,
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/scheduled-close", Summary: "Get the scheduled cooperative close of a contract", Response: Ref("ScheduledClose")},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/scheduled-close", Summary: "Sign a cooperative close at a block height or time",
+			Body: Object(map[string]*Schema{
+				"pub_key":       pubKey(),
+				"close_height":  blockHeight().OrNull(),
+				"close_at":      DateTime().OrNull(),
+				"buyer_amount":  Integer().Min(0),
+				"seller_amount": Integer().Min(0),
+				"signature":     String().NonEmpty(),
+			}, "pub_key", "signature"),
+			Response: Ref("ScheduledClose"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/evidence", Summary: "Get the evidence a contract's settlement was decided on", Response: Ref("SettlementEvidence")},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/evidence/verify", Summary: "Verify the settlement evidence of a contract"},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/evidence/proof", Summary: "Download the timestamp proof of a contract's settlement evidence"},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/oracle-event", Summary: "Get the oracle event a contract settles on", Response: Ref("OracleEvent")},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/attestation", Summary: "Submit the oracle's signed outcome of a contract",
+			Body: Object(map[string]*Schema{
+				"outcome":            String().OneOf("HIGH", "LOW"),
+				"observed_hash_rate": Number().Positive().Describe("In EH/s"),
+				"signature":          String().NonEmpty(),
+			}, "outcome", "signature"),
+			Response: Ref("OracleEvent"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/delegations", Summary: "List the watchtower delegations of a contract", Response: ArrayOf(Ref("WatchtowerDelegation"))},
+		Operation{
+			Method: http.MethodPost, Path: "/contracts/{id}/delegations", Summary: "Delegate a participant's emergency exit to a watchtower",
+			Body: Object(map[string]*Schema{
+				"watchtower_id": UUID(),
+				"pub_key":       pubKey(),
+			}, "watchtower_id", "pub_key"),
+			Response: Ref("WatchtowerDelegation"),
+		},
+		Operation{Method: http.MethodGet, Path: "/contracts/{id}/delegations/{delegationId}/export", Summary: "Export a delegation's exit package"},
+		Operation{Method: http.MethodDelete, Path: "/contracts/{id}/delegations/{delegationId}", Summary: "Revoke a watchtower delegation", Response: message()},
+		Operation{Method: http.MethodDelete, Path: "/contracts/{id}", Summary: "Cancel a contract", Response: message()},
+	)
+
+	add("Orders",
+		Operation{
+			Method: http.MethodPost, Path: "/orders", Summary: "Place an order",
+			Body: Object(map[string]*Schema{
+				"user_id":            UUID().Describe("Defaults to the authenticated user"),
+				"side":               String().OneOf(orderSides...),
+				"type":               String().OneOf(orderTypes...).Describe("Defaults to limit"),
+				"time_in_force":      String().OneOf(orderTimeInForces...).Describe("Defaults to gtc, or ioc for market orders"),
+				"contract_type":      String().OneOf(orderContractType...),
+				"strike_hash_rate":   Number().Positive().Describe("In EH/s"),
+				"start_block_height": blockHeight(),
+				"end_block_height":   blockHeight().Describe("Must be above start_block_height"),
+				"price":              Integer().Min(0).Describe("In satoshis; positive for limit orders, omitted for market orders"),
+				"quantity":           Integer().Positive(),
+				"pub_key":            String(),
+				"key_id":             UUID().OrNull().Describe("Registered key to use instead of pub_key"),
+				"expires_in":         Integer().OrNull().Describe("Minutes until expiration"),
+				"target_timestamp":   DateTime().OrNull().Describe("Defaults to the market's listed target"),
+				"auto_roll": Object(map[string]*Schema{
+					"price_offset": Integer().Describe("In satoshis, added to the order price"),
+				}).OrNull(),
+				"cancel_on_disconnect": Boolean(),
+			}, "side", "contract_type", "strike_hash_rate", "start_block_height", "end_block_height", "quantity"),
+			Status: http.StatusCreated, Response: Ref("Order"),
+		},
+		Operation{
+			Method: http.MethodPatch, Path: "/orders/{id}", Summary: "Amend the price or quantity of an open order",
+			Body: Object(map[string]*Schema{
+				"price":    Integer().Positive().OrNull(),
+				"quantity": Integer().Positive().OrNull(),
+			}),
+			Response: Ref("Order"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/orders/{id}", Summary: "Cancel an open order"},
+		Operation{
+			Method: http.MethodGet, Path: "/orders/user/{id}", Summary: "List a user's orders, newest first",
+			Query:    []Parameter{limitParam(500), cursorParam()},
+			Response: ArrayOf(Ref("Order")), Paged: true,
+		},
+		Operation{Method: http.MethodGet, Path: "/orders/{id}/auto-roll", Summary: "Get the pending auto-roll of an order", Response: Ref("AutoRoll")},
+		Operation{
+			Method: http.MethodPut, Path: "/orders/{id}/auto-roll", Summary: "Roll an order into the next expiry once settled",
+			Body:   Object(map[string]*Schema{"price_offset": Integer().Describe("In satoshis, added to the order price")}),
+			Status: http.StatusCreated, Response: Ref("AutoRoll"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/orders/{id}/auto-roll", Summary: "Disable the auto-roll of an order", Response: message()},
+	)
+
+	add("Keys",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/keys", Summary: "List a user's keys, default first", Response: ArrayOf(Ref("UserKey"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/keys", Summary: "Register a key",
+			Body: Object(map[string]*Schema{
+				"pub_key":    pubKey(),
+				"key_type":   String().Length(0, 20).Describe("Defaults to taproot"),
+				"label":      String().Length(0, 100),
+				"is_default": Boolean(),
+			}, "pub_key"),
+			Status: http.StatusCreated, Response: Ref("UserKey"),
+		},
+		Operation{Method: http.MethodPut, Path: "/users/{id}/keys/{keyId}/default", Summary: "Make a key the default", Response: Ref("UserKey")},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/keys/{keyId}", Summary: "Delete a key"},
+	)
+
+	add("Watchlist",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/watchlist", Summary: "Get a user's watchlist", Response: ArrayOf(Ref("WatchlistItem"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/watchlist", Summary: "Follow a contract or market",
+			Body: Object(map[string]*Schema{
+				"contract_id": UUID().OrNull(),
+				"market": Object(map[string]*Schema{
+					"contract_type":      String().OneOf(contractTypes...),
+					"strike_hash_rate":   Number().Positive(),
+					"start_block_height": blockHeight(),
+					"end_block_height":   blockHeight(),
+				}, "contract_type", "strike_hash_rate", "start_block_height", "end_block_height").OrNull(),
+				"notify": Boolean(),
+			}).Describe("Exactly one of contract_id or market"),
+			Status: http.StatusCreated, Response: Ref("WatchlistItem"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/watchlist/{itemId}", Summary: "Unfollow a contract or market", Response: message()},
+	)
+
+	add("Alerts",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/alerts", Summary: "List a user's price alerts", Response: ArrayOf(Ref("PriceAlert"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/alerts", Summary: "Create a price alert",
+			Body: Object(map[string]*Schema{
+				"contract_type":      String().OneOf(orderContractType...),
+				"strike_hash_rate":   Number().Positive(),
+				"start_block_height": blockHeight(),
+				"end_block_height":   blockHeight(),
+				"metric":             String().NonEmpty().Describe("BEST_BID, BEST_ASK or LAST_TRADE"),
+				"direction":          String().NonEmpty().Describe("ABOVE or BELOW"),
+				"threshold":          satoshis(),
+				"channel":            String().NonEmpty().Describe("WEBSOCKET, WEBHOOK or EMAIL"),
+				"target":             String().Describe("URL or email address for webhook and email alerts"),
+			}, "contract_type", "strike_hash_rate", "start_block_height", "end_block_height",
+				"metric", "direction", "threshold", "channel"),
+			Status: http.StatusCreated, Response: Ref("PriceAlert"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/alerts/{alertId}", Summary: "Cancel a price alert", Response: message()},
+	)
+
+	add("Push",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/devices", Summary: "List a user's devices", Response: ArrayOf(Ref("DeviceToken"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/devices", Summary: "Register a device for push notifications",
+			Body: Object(map[string]*Schema{
+				"platform": String().OneOf("apns", "fcm", "APNS", "FCM"),
+				"token":    String().Length(1, 4096),
+			}, "platform", "token"),
+			Status: http.StatusCreated, Response: Ref("DeviceToken"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/devices/{deviceId}", Summary: "Unregister a device", Response: message()},
+		Operation{Method: http.MethodGet, Path: "/users/{id}/push-preferences", Summary: "Get a user's push preferences", Response: ArrayOf(Ref("PushPreference"))},
+		Operation{
+			Method: http.MethodPut, Path: "/users/{id}/push-preferences", Summary: "Enable or disable push event types",
+			Body: Object(map[string]*Schema{
+				string(models.PushEventFill):               Boolean(),
+				string(models.PushEventSettlement):         Boolean(),
+				string(models.PushEventSettlementDeferred): Boolean(),
+			}).Describe("Maps event types to whether they are pushed"),
+			Response: ArrayOf(Ref("PushPreference")),
+		},
+	)
+
+	add("Notifications",
+		Operation{
+			Method: http.MethodGet, Path: "/users/{id}/notifications", Summary: "List a user's notifications, newest first",
+			Query: []Parameter{
+				{Name: "unread", Description: "Only unread notifications", Schema: Boolean()},
+				limitParam(500), offsetParam(),
+			},
+			Response: Object(map[string]*Schema{
+				"notifications": ArrayOf(Ref("Notification")),
+				"unread":        Integer().Min(0),
+			}),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/notifications/read", Summary: "Mark all of a user's notifications read",
+			Response: Object(map[string]*Schema{"marked": Integer().Min(0)}),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/notifications/{notificationId}/read", Summary: "Mark a notification read",
+			Response: Ref("Notification"),
+		},
+	)
+
+	add("Webhooks",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/webhooks", Summary: "List a user's webhooks", Response: ArrayOf(Ref("Webhook"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/webhooks", Summary: "Register an HTTPS callback; the signing secret is only returned here",
+			Body: Object(map[string]*Schema{
+				"url": String().Length(1, 2048).Matching("^https://"),
+				"events": ArrayOf(String().OneOf(
+					string(models.WebhookEventTradeExecuted),
+					string(models.WebhookEventOrderFilled),
+					string(models.WebhookEventContractSettled),
+				)).Count(1, -1),
+			}, "url", "events"),
+			Status: http.StatusCreated, Response: Ref("Webhook"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/webhooks/{webhookId}", Summary: "Remove a webhook", Response: message()},
+		Operation{
+			Method: http.MethodGet, Path: "/users/{id}/webhooks/dead-letters", Summary: "List deliveries that failed every attempt",
+			Query:    []Parameter{limitParam(500), offsetParam()},
+			Response: ArrayOf(Ref("WebhookDeadLetter")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/webhooks/dead-letters/{deliveryId}/redeliver", Summary: "Queue a failed delivery again",
+			Status: http.StatusAccepted, Response: message(),
+		},
+	)
+
+	add("Payouts",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/payout-address", Summary: "Get a user's payout address", Response: Ref("PayoutAddress")},
+		Operation{
+			Method: http.MethodPut, Path: "/users/{id}/payout-address", Summary: "Set a user's payout address",
+			Body:     Object(map[string]*Schema{"address": String().NonEmpty()}, "address"),
+			Response: Ref("PayoutAddress"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/payout-address", Summary: "Remove a user's payout address", Response: message()},
+	)
+
+	add("API keys",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/api-keys", Summary: "List a user's API keys", Response: ArrayOf(Ref("APIKey"))},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/api-keys", Summary: "Create an API key; the key is only returned here",
+			Body:   Object(map[string]*Schema{"name": String().Length(1, 100)}, "name"),
+			Status: http.StatusCreated, Response: Ref("APIKey"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/api-keys/{keyId}", Summary: "Revoke an API key", Response: message()},
+		Operation{Method: http.MethodGet, Path: "/users/{id}/api-keys/{keyId}/protection", Summary: "Get an API key's market maker protection", Response: Ref("MarketMakerProtection")},
+		Operation{
+			Method: http.MethodPut, Path: "/users/{id}/api-keys/{keyId}/protection", Summary: "Set an API key's market maker protection",
+			Body: Object(map[string]*Schema{
+				"max_fills":      Integer().Positive(),
+				"window_seconds": Integer().Positive().Max(models.MaxProtectionWindowSeconds),
+				"freeze_seconds": Integer().Min(0).Describe("Frozen until reset when zero"),
+			}, "max_fills", "window_seconds"),
+			Response: Ref("MarketMakerProtection"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/users/{id}/api-keys/{keyId}/protection", Summary: "Disable an API key's market maker protection", Response: message()},
+		Operation{Method: http.MethodPost, Path: "/users/{id}/api-keys/{keyId}/protection/reset", Summary: "Unfreeze an API key's market maker protection", Response: message()},
+		Operation{
+			Method: http.MethodGet, Path: "/users/{id}/usage", Summary: "Get a user's API usage",
+			Query: []Parameter{
+				{Name: "from", Schema: DateTime()},
+				{Name: "to", Schema: DateTime()},
+			},
+			Response: ArrayOf(Ref("APIUsage")),
+		},
+	)
+
+	add("Positions",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/positions", Summary: "Get a user's positions and profit and loss"},
+		Operation{
+			Method: http.MethodGet, Path: "/users/{id}/trade-report", Summary: "Download a user's trades, contracts and payouts for accounting",
+			Query: []Parameter{
+				{Name: "from", Description: "YYYY-MM-DD", Schema: String()},
+				{Name: "to", Description: "YYYY-MM-DD", Schema: String()},
+				{Name: "format", Schema: String().OneOf("csv", "json")},
+			},
+		},
+	)
+
+	add("Margin",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/margin", Summary: "Get a user's margin account and positions"},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/margin/withdraw", Summary: "Withdraw free collateral",
+			Body:     Object(map[string]*Schema{"amount": satoshis()}, "amount"),
+			Response: Ref("MarginAccount"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/margin/positions", Summary: "Margin a contract instead of funding its full size",
+			Body:   Object(map[string]*Schema{"contract_id": UUID()}, "contract_id"),
+			Status: http.StatusCreated, Response: Ref("MarginPosition"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/users/{id}/margin/positions/{positionId}/top-up", Summary: "Add collateral to a margin position",
+			Body:     Object(map[string]*Schema{"amount": satoshis()}, "amount"),
+			Response: Ref("MarginPosition"),
+		},
+	)
+
+	add("Balances",
+		Operation{Method: http.MethodGet, Path: "/users/{id}/balance", Summary: "Get a user's available, held and committed funds", Response: Ref("Balance")},
+		Operation{
+			Method: http.MethodGet, Path: "/users/{id}/balance/entries", Summary: "List the postings to a user's accounts, newest first",
+			Query:    []Parameter{limitParam(500), offsetParam()},
+			Response: ArrayOf(Ref("LedgerEntry")),
+		},
+	)
+
+	add("Wallet",
+		Operation{Method: http.MethodGet, Path: "/wallet/deposit-address", Summary: "Get your taproot deposit address", Response: Ref("DepositAddress")},
+		Operation{
+			Method: http.MethodGet, Path: "/wallet/deposits", Summary: "List your on-chain deposits, newest first",
+			Query:    []Parameter{limitParam(500), offsetParam()},
+			Response: ArrayOf(Ref("Deposit")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/wallet/withdrawals", Summary: "Withdraw part of your balance on chain, or over Ark to an Ark address",
+			Body: Object(map[string]*Schema{
+				"amount":      satoshis(),
+				"address":     String().NonEmpty().Describe("On-chain address, used when the withdrawal cannot go over Ark"),
+				"ark_address": String().Length(1, 200).Describe("Ark address for small withdrawals while the ASP is up"),
+			}, "amount", "address"),
+			Status: http.StatusCreated, Response: Ref("Withdrawal"),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/wallet/withdrawals", Summary: "List your withdrawals, newest first",
+			Query:    []Parameter{limitParam(500), offsetParam()},
+			Response: ArrayOf(Ref("Withdrawal")),
+		},
+		Operation{Method: http.MethodGet, Path: "/wallet/exit-info", Summary: "Get how contracts are exited on chain"},
+		Operation{
+			Method: http.MethodPost, Path: "/wallet/emergency-exit", Summary: "Broadcast an emergency exit you signed for one of your contracts",
+			Body: Object(map[string]*Schema{
+				"contract_id": UUID(),
+				"tx_hex":      String().Length(1, 200_000).Describe("Hex encoded signed transaction"),
+			}, "contract_id", "tx_hex"),
+			Status: http.StatusCreated, Response: Ref("ContractTransaction"),
+		},
+		Operation{Method: http.MethodGet, Path: "/wallet/exit-transactions/{txId}/download", Summary: "Download the raw transaction of one of your contracts' emergency exits"},
+		Operation{Method: http.MethodPost, Path: "/wallet/exit-transactions/{txId}/broadcast", Summary: "Broadcast one of your contracts' emergency exits"},
+	)
+
+	add("Marketplace",
+		Operation{
+			Method: http.MethodGet, Path: "/marketplace/listings", Summary: "Browse the open listings of contract positions",
+			Query: []Parameter{
+				{Name: "contract_id", Description: "Only listings of this contract", Schema: UUID()},
+				limitParam(500),
+				offsetParam(),
+			},
+			Response: ArrayOf(Ref("ContractListing")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/marketplace/listings", Summary: "List your side of an active contract for sale",
+			Body: Object(map[string]*Schema{
+				"contract_id": UUID(),
+				"price":       satoshis(),
+			}, "contract_id", "price"),
+			Status: http.StatusCreated, Response: Ref("ContractListing"),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/marketplace/listings/mine", Summary: "List your listings",
+			Query:    []Parameter{limitParam(500), offsetParam()},
+			Response: ArrayOf(Ref("ContractListing")),
+		},
+		Operation{Method: http.MethodGet, Path: "/marketplace/listings/{id}", Summary: "Get a listing", Response: Ref("ContractListing")},
+		Operation{Method: http.MethodDelete, Path: "/marketplace/listings/{id}", Summary: "Withdraw an open listing", Response: Ref("ContractListing")},
+		Operation{
+			Method: http.MethodPost, Path: "/marketplace/listings/{id}/buy", Summary: "Buy a listing, paying from your margin account and taking the position with your key",
+			Body:     Object(map[string]*Schema{"pub_key": pubKey()}, "pub_key"),
+			Response: Ref("ContractListing"),
+		},
+	)
+
+	add("Research",
+		Operation{Method: http.MethodGet, Path: "/research/dumps", Summary: "List the daily research dumps"},
+		Operation{Method: http.MethodGet, Path: "/research/dumps/{name}", Summary: "Download a daily research dump"},
+		Operation{
+			Method: http.MethodGet, Path: "/research/{dataset}", Summary: "Page through a research dataset",
+			Query: []Parameter{{Name: "cursor", Description: "Cursor of the next page", Schema: String()}},
+		},
+	)
+
+	add("Watchtowers",
+		Operation{
+			Method: http.MethodGet, Path: "/watchtower/delegations", Summary: "Poll the delegations of the calling watchtower", Auth: AuthWatchtower,
+			Query: []Parameter{
+				{Name: "status", Schema: String()},
+				limitParam(1000),
+			},
+			Response: ArrayOf(Ref("WatchtowerDelegation")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/watchtower/delegations/{id}/ack", Summary: "Confirm a delegation's package was stored", Auth: AuthWatchtower,
+			Body:     Object(map[string]*Schema{"package_digest": String().NonEmpty()}, "package_digest"),
+			Response: message(),
+		},
+	)
+
+	add("Market data",
+		Operation{
+			Method: http.MethodGet, Path: "/orderbook", Summary: "Get the open orders of a market", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "type", Schema: String().OneOf(contractTypes...)},
+				{Name: "strike_hash_rate", Schema: Number().Positive()},
+				limitParam(1000),
+			},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/market/depth", Summary: "Get the aggregated depth of a market", Auth: AuthNone,
+			Query: append(marketParams(), Parameter{Name: "levels", Schema: Integer().Positive()}),
+		},
+		Operation{Method: http.MethodGet, Path: "/market/tickers", Summary: "Get the ticker of every market", Auth: AuthNone},
+		Operation{
+			Method: http.MethodGet, Path: "/ticker", Summary: "Get the best bid and offer and 24 hour trading of one or every market", Auth: AuthNone,
+			Query: marketParams(),
+		},
+		Operation{Method: http.MethodGet, Path: "/market/hashrate", Summary: "Get the current hash rate", Auth: AuthNone},
+		Operation{Method: http.MethodGet, Path: "/market/stats", Summary: "Get 24 hour market statistics", Auth: AuthNone},
+		Operation{Method: http.MethodGet, Path: "/market/open-interest", Summary: "Get the open interest of every market", Auth: AuthNone},
+		Operation{
+			Method: http.MethodGet, Path: "/trades", Summary: "List recent trades of a market", Auth: AuthNone,
+			Query:    append(marketParams(), limitParam(1000), cursorParam(), sinceParam()),
+			Response: ArrayOf(Ref("MarketTrade")), Paged: true,
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/candles", Summary: "Get price candles of a market", Auth: AuthNone,
+			Query:    append(marketParams(), Parameter{Name: "interval", Schema: String()}),
+			Response: ArrayOf(Ref("Candle")),
+		},
+	)
+
+	add("Hash rate",
+		Operation{Method: http.MethodGet, Path: "/hashrate", Summary: "Get the hash rate index", Auth: AuthNone},
+		Operation{
+			Method: http.MethodGet, Path: "/hashrate/history", Summary: "Get the recorded hash rate observations", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "from", Description: "First block height", Schema: blockHeight()},
+				{Name: "to", Description: "Last block height", Schema: blockHeight()},
+			},
+			Response: ArrayOf(Ref("HashRateObservation")),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/hashrate/statistics", Summary: "Get realized hash rate volatility, block intervals and difficulty changes", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "window", Description: "Trailing blocks to measure over", Schema: Integer().Positive().Max(4032)},
+				{Name: "sample", Description: "Blocks per hash rate sample", Schema: Integer().Positive()},
+				{Name: "epochs", Description: "Difficulty adjustments to report", Schema: Integer().Min(0).Max(26)},
+			},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/hashrate/ladder", Summary: "Get the ladder of listed strikes", Auth: AuthNone,
+			Query: []Parameter{{Name: "steps", Schema: Integer().Positive()}},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/pricing/quote", Summary: "Quote an indicative premium from the hash rate and its realized volatility", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "type", Schema: String().OneOf(contractTypes...)},
+				{Name: "strike_hash_rate", Description: "In EH/s", Schema: Number().Positive()},
+				{Name: "end_block_height", Schema: blockHeight()},
+				{Name: "contract_size", Schema: satoshis()},
+				{Name: "hash_rate", Description: "Overrides the 1d average hash rate, in EH/s", Schema: Number().Positive()},
+				{Name: "volatility", Description: "Overrides the realized annualized volatility", Schema: Number().Min(0)},
+			},
+		},
+		Operation{Method: http.MethodGet, Path: "/fees", Summary: "Get the fee estimate and whether settlements are deferred", Auth: AuthNone},
+	)
+
+	add("Analytics",
+		Operation{Method: http.MethodGet, Path: "/analytics/feeds", Summary: "List the external data feeds", Auth: AuthNone},
+		Operation{
+			Method: http.MethodGet, Path: "/analytics/feeds/{name}", Summary: "List the observations of a feed", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "series", Schema: String()},
+				sinceParam(),
+				limitParam(5000),
+			},
+			Response: ArrayOf(Ref("FeedObservation")),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/analytics/feeds/{name}/volatility", Summary: "Get the volatility of a feed", Auth: AuthNone,
+			Query: []Parameter{
+				{Name: "series", Schema: String()},
+				sinceParam(),
+				{Name: "window", Description: "Samples per window", Schema: Integer().Min(3).Max(1000)},
+				{Name: "percentiles", Description: "Comma separated values from 0 to 100", Schema: String()},
+			},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/analytics/open-interest", Summary: "Get the open interest history of a market", Auth: AuthNone,
+			Query:    append(marketParams(), sinceParam(), limitParam(5000)),
+			Response: ArrayOf(Ref("OpenInterestPoint")),
+		},
+		Operation{Method: http.MethodGet, Path: "/analytics/liquidity", Summary: "Get the liquidity of every market", Auth: AuthNone},
+		Operation{
+			Method: http.MethodGet, Path: "/analytics/liquidity/market", Summary: "Get the liquidity of a market", Auth: AuthNone,
+			Query: marketParams(),
+		},
+	)
+
+	add("Admin",
+		Operation{Method: http.MethodGet, Path: "/admin/logging", Summary: "Get the level and sampling of each component logger"},
+		Operation{
+			Method: http.MethodPut, Path: "/admin/logging", Summary: "Adjust a component logger",
+			Body: Object(map[string]*Schema{
+				"component":    String().NonEmpty(),
+				"level":        String().Describe("trace, debug, info, warn, error, fatal, panic or disabled"),
+				"sample_every": Integer().Min(0).OrNull(),
+			}, "component").Describe("At least one of level or sample_every"),
+		},
+		Operation{Method: http.MethodPost, Path: "/admin/orderbook/resync", Summary: "Reload the order book from the database", Response: message()},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/usage", Summary: "Get API usage aggregated per key",
+			Query: []Parameter{
+				{Name: "from", Schema: DateTime()},
+				{Name: "to", Schema: DateTime()},
+				limitParam(1000),
+			},
+			Response: ArrayOf(Ref("UsageAggregate")),
+		},
+		Operation{Method: http.MethodGet, Path: "/admin/websocket", Summary: "Get websocket connection statistics"},
+		Operation{Method: http.MethodGet, Path: "/admin/schedule", Summary: "Get the intervals of the background tasks"},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/reconciliation", Summary: "Reconcile balances over a date range",
+			Query: []Parameter{
+				{Name: "from", Description: "YYYY-MM-DD", Schema: String()},
+				{Name: "to", Description: "YYYY-MM-DD", Schema: String()},
+				{Name: "format", Schema: String().OneOf("json", "csv")},
+			},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/settlements", Summary: "List automatic settlement attempts",
+			Query: []Parameter{
+				{Name: "outcome", Schema: String().OneOf(
+					string(models.SettlementOutcomeSettled),
+					string(models.SettlementOutcomeDeferred),
+					string(models.SettlementOutcomeFailed),
+				)},
+				{Name: "contract_id", Schema: UUID()},
+				limitParam(1000),
+				offsetParam(),
+			},
+			Response: ArrayOf(Ref("SettlementAttempt")),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/settlements/batch", Summary: "Settle every eligible contract",
+			Query: []Parameter{{Name: "workers", Schema: Integer().Positive()}},
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/settlements/stuck", Summary: "List payouts that have gone unconfirmed for too long",
+			Query:    []Parameter{limitParam(1000), offsetParam()},
+			Response: ArrayOf(Ref("SettlementBroadcast")),
+		},
+		Operation{Method: http.MethodGet, Path: "/admin/audit", Summary: "List the audit log", Response: ArrayOf(Ref("AuditEntry"))},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/contracts/{id}/oracle-event", Summary: "Announce the oracle event a contract settles on",
+			Body: Object(map[string]*Schema{
+				"nonce":    String().NonEmpty(),
+				"event_id": String().NonEmpty(),
+			}, "nonce", "event_id"),
+			Status: http.StatusCreated, Response: Ref("OracleEvent"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/users/{id}/margin/deposit", Summary: "Credit collateral to a margin account",
+			Body:     Object(map[string]*Schema{"amount": satoshis()}, "amount"),
+			Response: Ref("MarginAccount"),
+		},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/users/{id}/balance/deposit", Summary: "Credit funds a user sent to the platform to their balance",
+			Body:     Object(map[string]*Schema{"amount": satoshis()}, "amount"),
+			Response: Ref("Balance"),
+		},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/withdrawals", Summary: "List withdrawals by status, oldest first",
+			Query: []Parameter{
+				{Name: "status", Description: "Defaults to withdrawals awaiting approval", Schema: String().OneOf("PENDING_APPROVAL", "APPROVED", "PROCESSING", "SENT", "FAILED", "REJECTED")},
+				limitParam(500), offsetParam(),
+			},
+			Response: ArrayOf(Ref("Withdrawal")),
+		},
+		Operation{Method: http.MethodPost, Path: "/admin/withdrawals/{id}/approve", Summary: "Approve and pay out a withdrawal", Response: Ref("Withdrawal")},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/withdrawals/{id}/reject", Summary: "Reject and refund a withdrawal",
+			Body:     Object(map[string]*Schema{"reason": String()}),
+			Response: Ref("Withdrawal"),
+		},
+		Operation{Method: http.MethodGet, Path: "/admin/exit-monitor", Summary: "Get the ASP exit monitor status"},
+		Operation{Method: http.MethodPost, Path: "/admin/exit-monitor/resume", Summary: "Resume off-chain operation after an ASP outage"},
+		Operation{Method: http.MethodGet, Path: "/admin/watchtowers", Summary: "List watchtowers", Response: ArrayOf(Ref("Watchtower"))},
+		Operation{
+			Method: http.MethodPost, Path: "/admin/watchtowers", Summary: "Register a watchtower; the token is only returned here",
+			Body: Object(map[string]*Schema{
+				"name":           String().Length(1, 100),
+				"encryption_key": String().Length(64, 64).Matching("^[0-9a-fA-F]+$").Describe("Hex X25519 public key"),
+			}, "name", "encryption_key"),
+			Status: http.StatusCreated, Response: Ref("Watchtower"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/admin/watchtowers/{id}", Summary: "Revoke a watchtower", Response: message()},
+		Operation{Method: http.MethodGet, Path: "/admin/research/access", Summary: "List research access grants", Response: ArrayOf(Ref("ResearchAccess"))},
+		Operation{
+			Method: http.MethodPut, Path: "/admin/research/access/{userId}", Summary: "Grant a user research access",
+			Body: Object(map[string]*Schema{
+				"tier":       String().OneOf(string(models.ResearchTierBasic), string(models.ResearchTierFull)),
+				"expires_at": DateTime().OrNull(),
+			}, "tier"),
+			Response: Ref("ResearchAccess"),
+		},
+		Operation{Method: http.MethodDelete, Path: "/admin/research/access/{userId}", Summary: "Revoke a user's research access", Response: message()},
+		Operation{
+			Method: http.MethodGet, Path: "/admin/jobs", Summary: "List background jobs",
+			Query: []Parameter{
+				{Name: "status", Schema: String()},
+				limitParam(1000),
+				offsetParam(),
+			},
+			Response: ArrayOf(Ref("Job")),
+		},
+		Operation{Method: http.MethodGet, Path: "/admin/jobs/{id}", Summary: "Get a background job", Response: Ref("Job")},
+		Operation{Method: http.MethodPost, Path: "/admin/jobs/{id}/replay", Summary: "Requeue a failed job", Response: message()},
+	)
+
+	add("Meta",
+		Operation{Method: http.MethodGet, Path: "/openapi.json", Summary: "Get this document", Auth: AuthNone},
+	)
+
+	return ops
+}
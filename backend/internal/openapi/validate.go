@@ -0,0 +1,242 @@
+// internal/openapi/validate.go
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// FieldError is a value of a request body that does not match its schema
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every mismatch of a request body with its schema
+type ValidationError struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		messages = append(messages, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// rootField names the body itself in field errors
+const rootField = "body"
+
+// patterns caches compiled schema patterns
+var patterns sync.Map
+
+// ValidateJSON checks a JSON document against s, returning a
+// *ValidationError listing every mismatch
+func (s *Schema) ValidateJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Errors: []FieldError{{Field: rootField, Message: "must be valid JSON"}}}
+	}
+	if decoder.More() {
+		return &ValidationError{Errors: []FieldError{{Field: rootField, Message: "must be a single JSON value"}}}
+	}
+
+	var errs []FieldError
+	s.validate(rootField, value, &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validate appends the mismatches of value with s to errs. Refs are not
+// followed; request schemas are inline.
+func (s *Schema) validate(field string, value interface{}, errs *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s == nil || s.Ref != "" {
+		return
+	}
+
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			fail("must not be null")
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		s.validateObject(field, object, errs)
+
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if s.MinItems != nil && len(array) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(array) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		for i, item := range array {
+			s.Items.validate(fmt.Sprintf("%s[%d]", field, i), item, errs)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		s.validateString(str, fail)
+
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		s.validateNumber(number, fail)
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 && !s.allows(value) {
+		fail("must be one of %s", s.enumList())
+	}
+}
+
+func (s *Schema) validateObject(field string, object map[string]interface{}, errs *[]FieldError) {
+	prefix := field + "."
+	if field == rootField {
+		prefix = ""
+	}
+
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, FieldError{Field: prefix + name, Message: "is required"})
+		}
+	}
+
+	// Visit properties in order so errors are reported deterministically
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			property.validate(prefix+name, object[name], errs)
+		} else if s.AdditionalProperties != nil {
+			s.AdditionalProperties.validate(prefix+name, object[name], errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, fail func(string, ...interface{})) {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		if *s.MinLength == 1 {
+			fail("must not be empty")
+		} else {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		fail("must be at most %d characters", *s.MaxLength)
+	}
+
+	if s.Pattern != "" {
+		re, ok := patterns.Load(s.Pattern)
+		if !ok {
+			re, _ = patterns.LoadOrStore(s.Pattern, regexp.MustCompile(s.Pattern))
+		}
+		if !re.(*regexp.Regexp).MatchString(str) {
+			fail("must match %s", s.Pattern)
+		}
+	}
+
+	switch s.Format {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			fail("must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			fail("must be an RFC 3339 time")
+		}
+	}
+}
+
+func (s *Schema) validateNumber(number json.Number, fail func(string, ...interface{})) {
+	if s.Type == "integer" {
+		if _, err := number.Int64(); err != nil {
+			fail("must be an integer")
+			return
+		}
+	}
+
+	value, err := number.Float64()
+	if err != nil {
+		fail("must be a number")
+		return
+	}
+
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum && value <= *s.Minimum {
+			fail("must be greater than %v", *s.Minimum)
+		} else if value < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+	}
+	if s.Maximum != nil && value > *s.Maximum {
+		fail("must be at most %v", *s.Maximum)
+	}
+}
+
+// allows reports whether value is one of the enum values of s
+func (s *Schema) allows(value interface{}) bool {
+	for _, allowed := range s.Enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schema) enumList() string {
+	values := make([]string, 0, len(s.Enum))
+	for _, allowed := range s.Enum {
+		values = append(values, fmt.Sprint(allowed))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,151 @@
+// internal/orderbook/amend.go
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// ErrNotAmendable is returned when amending an order that cannot rest in the book
+var ErrNotAmendable = fmt.Errorf("%w: only good-til-cancelled limit orders can be amended", ErrOrderRejected)
+
+// OrderAmendment changes the price or total quantity of a resting order.
+// Nil fields are left unchanged.
+type OrderAmendment struct {
+	Price    *int64
+	Quantity *int
+}
+
+// amend returns a copy of order with the amendment applied. Reducing the
+// quantity at the same price keeps the order's place in the queue; any
+// price change or quantity increase moves it to the back of its new level.
+func amend(order *models.Order, amendment OrderAmendment, now time.Time) (*models.Order, error) {
+	if amendment.Price == nil && amendment.Quantity == nil {
+		return nil, errors.New("amendment changes neither price nor quantity")
+	}
+
+	amended := *order
+	if amendment.Price != nil {
+		if *amendment.Price <= 0 {
+			return nil, errors.New("price must be positive")
+		}
+		amended.Price = *amendment.Price
+	}
+
+	if amendment.Quantity != nil {
+		filled := order.Quantity - order.RemainingQuantity
+		if *amendment.Quantity <= filled {
+			return nil, fmt.Errorf("quantity must exceed the %d contracts already filled; cancel the order instead", filled)
+		}
+		amended.Quantity = *amendment.Quantity
+		amended.RemainingQuantity = amended.Quantity - filled
+	}
+
+	if amended.Price != order.Price || amended.Quantity > order.Quantity {
+		amended.PriorityAt = now
+	}
+
+	return &amended, nil
+}
+
+// AmendOrder changes the price or quantity of a resting order. Like a cancel,
+// the amendment runs as a command of the matching engine: the order is taken
+// off the book, updated in the database and matched again at its new terms,
+// so a repriced order that crosses the spread fills immediately.
+func (ob *OrderBook) AmendOrder(ctx context.Context, orderID uuid.UUID, amendment OrderAmendment) (*models.Order, error) {
+	tip, err := ob.contractSvc.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain tip: %w", err)
+	}
+
+	order, err := ob.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Type != models.OrderTypeLimit || !order.Rests() {
+		return nil, ErrNotAmendable
+	}
+
+	// An order never changes market, so its market can be locked from the
+	// stored copy
+	m, unlock := ob.lockMarket(orderKey(order))
+	if err = ob.claimMarket(ctx, m); err == nil {
+		order, err = ob.amendOrder(ctx, m, order, amendment, tip)
+	}
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	ob.pullTriggeredQuotes(ctx)
+
+	return order, nil
+}
+
+// amendOrder amends an order of a market. The caller must hold the market's lock.
+func (ob *OrderBook) amendOrder(
+	ctx context.Context,
+	m *market,
+	order *models.Order,
+	amendment OrderAmendment,
+	tip int64,
+) (*models.Order, error) {
+	now := time.Now().UTC()
+	if err := ob.checkProtection(order, now); err != nil {
+		return nil, err
+	}
+
+	// The resting copy is the one the matcher updates, so it is the
+	// authoritative state while the order is on the book
+	resting := m.remove(order.Side, order.ID)
+	if resting == nil {
+		return nil, fmt.Errorf("order %s is not in the book: %w", order.ID, db.ErrConflict)
+	}
+
+	amended, err := amend(resting, amendment, now)
+	if err != nil {
+		m.add(resting)
+		return nil, fmt.Errorf("%w: %v", ErrOrderRejected, err)
+	}
+
+	if err := ob.checkFunds(ctx, amended); err != nil {
+		m.add(resting)
+		return nil, err
+	}
+
+	if err := ob.orderRepo.Amend(ctx, amended); err != nil {
+		m.add(resting)
+		return nil, fmt.Errorf("failed to amend order: %w", err)
+	}
+
+	// Keep the resting pointer, which protections and observers may hold
+	*resting = *amended
+	order = resting
+
+	matched, err := ob.tryMatchOrder(ctx, m, order, tip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match order: %w", err)
+	}
+
+	if matched {
+		order.Status = models.OrderStatusPartial
+		if order.RemainingQuantity == 0 {
+			order.Status = models.OrderStatusFilled
+		}
+		if err := ob.orderRepo.Update(ctx, order); err != nil {
+			return nil, fmt.Errorf("failed to update order status: %w", err)
+		}
+	}
+
+	ob.publishOrderEvent(events.OrderAmended, order)
+
+	return order, nil
+}
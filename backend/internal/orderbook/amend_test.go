@@ -0,0 +1,50 @@
+// internal/orderbook/amend_test.go
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func TestAmend(t *testing.T) {
+	placed := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	now := placed.Add(time.Hour)
+	order := &models.Order{Price: 100, Quantity: 10, RemainingQuantity: 6, PriorityAt: placed}
+
+	price := func(p int64) *int64 { return &p }
+	quantity := func(q int) *int { return &q }
+
+	// Reducing the quantity keeps the place in the queue
+	amended, err := amend(order, OrderAmendment{Quantity: quantity(8)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 8, amended.Quantity)
+	assert.Equal(t, 4, amended.RemainingQuantity)
+	assert.Equal(t, placed, amended.PriorityAt)
+
+	// Increasing the quantity or changing the price loses it
+	amended, err = amend(order, OrderAmendment{Quantity: quantity(12)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 8, amended.RemainingQuantity)
+	assert.Equal(t, now, amended.PriorityAt)
+
+	amended, err = amend(order, OrderAmendment{Price: price(90)}, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), amended.Price)
+	assert.Equal(t, now, amended.PriorityAt)
+
+	// The original order is left untouched
+	assert.Equal(t, int64(100), order.Price)
+	assert.Equal(t, placed, order.PriorityAt)
+
+	_, err = amend(order, OrderAmendment{}, now)
+	assert.Error(t, err)
+	_, err = amend(order, OrderAmendment{Price: price(0)}, now)
+	assert.Error(t, err)
+	_, err = amend(order, OrderAmendment{Quantity: quantity(4)}, now)
+	assert.Error(t, err)
+}
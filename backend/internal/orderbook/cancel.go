@@ -0,0 +1,84 @@
+// internal/orderbook/cancel.go
+package orderbook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// CancelResult is the definitive state of an order once a cancel request has
+// been processed: CANCELLED when the cancel took effect, otherwise the state
+// the order reached first, with the fills it received either way
+type CancelResult struct {
+	Order *models.Order
+	Fills []*models.Trade
+}
+
+// Cancelled reports whether the cancel request took effect
+func (r *CancelResult) Cancelled() bool {
+	return r.Order.Status == models.OrderStatusCancelled
+}
+
+// CancelOrder cancels an open order. The cancel runs as a command of the
+// matching engine, holding the same lock as matching, so an order is either
+// cancelled before any further fill or reported as filled to the canceller.
+func (ob *OrderBook) CancelOrder(ctx context.Context, orderID uuid.UUID) (*CancelResult, error) {
+	order, err := ob.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	// An order never changes market, so its market can be locked from the
+	// stored copy
+	m, unlock := ob.lockMarket(orderKey(order))
+	defer unlock()
+
+	if err := ob.claimMarket(ctx, m); err != nil {
+		return nil, err
+	}
+
+	// The resting copy is the one the matcher updates, so it is the
+	// authoritative state while the order is on the book
+	resting := m.remove(order.Side, orderID)
+	if resting != nil {
+		order = resting
+	}
+
+	if order.CanBeCancelled() {
+		cancelled, err := ob.orderRepo.CancelIfOpen(ctx, orderID)
+		if err != nil {
+			if resting != nil {
+				m.add(resting)
+			}
+			return nil, fmt.Errorf("failed to cancel order: %w", err)
+		}
+
+		if cancelled {
+			order.Status = models.OrderStatusCancelled
+			ob.publishOrderEvent(events.OrderCancelled, order)
+		} else {
+			// The order left the book outside the engine, e.g. by expiry,
+			// so report the state that was stored first
+			order, err = ob.orderRepo.GetByID(ctx, orderID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get order: %w", err)
+			}
+		}
+	}
+
+	if resting != nil {
+		ob.notifyMarketUpdate(m)
+	}
+
+	fills, err := ob.tradeRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order fills: %w", err)
+	}
+
+	return &CancelResult{Order: order, Fills: fills}, nil
+}
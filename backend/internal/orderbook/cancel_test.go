@@ -0,0 +1,36 @@
+// internal/orderbook/cancel_test.go
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestMarketRemove(t *testing.T) {
+	first := &models.Order{ID: uuid.New(), Side: models.OrderSideBuy, Price: 110}
+	second := &models.Order{ID: uuid.New(), Side: models.OrderSideBuy, Price: 100}
+
+	m := &market{bids: []*models.Order{first, second}}
+
+	assert.Nil(t, m.remove(models.OrderSideSell, first.ID))
+	assert.Len(t, m.bids, 2)
+
+	assert.Same(t, first, m.remove(models.OrderSideBuy, first.ID))
+	assert.Equal(t, []*models.Order{second}, m.bids)
+
+	assert.Same(t, second, m.remove(models.OrderSideBuy, second.ID))
+	assert.Nil(t, m.bids)
+
+	m.add(first)
+	assert.Equal(t, []*models.Order{first}, m.bids)
+	assert.Nil(t, m.asks)
+}
+
+func TestCancelResultCancelled(t *testing.T) {
+	assert.True(t, (&CancelResult{Order: &models.Order{Status: models.OrderStatusCancelled}}).Cancelled())
+	assert.False(t, (&CancelResult{Order: &models.Order{Status: models.OrderStatusFilled}}).Cancelled())
+}
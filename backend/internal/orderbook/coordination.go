@@ -0,0 +1,83 @@
+// internal/orderbook/coordination.go
+package orderbook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//go:generate mockgen -source=coordination.go -destination=mocks/coordination.go -package=mocks
+
+// ErrNotMatcher is returned for orders of a market another instance
+// matches. Every instance serves reads; only the market's matcher places,
+// cancels and amends its orders.
+var ErrNotMatcher = errors.New("another instance matches this market")
+
+// Coordinator decides which of several instances sharing a database matches
+// each market
+type Coordinator interface {
+	// Claim reports whether this instance matches a market, taking it over
+	// if no instance does. fresh is true when the market was just taken
+	// over, so its book must be rebuilt from the database.
+	Claim(ctx context.Context, market string) (claimed bool, fresh bool, err error)
+}
+
+// SetCoordinator lets several instances share the database, each market
+// being matched by the one instance holding its claim. Without a coordinator
+// this instance matches every market.
+func (ob *OrderBook) SetCoordinator(coordinator Coordinator) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.coordinator = coordinator
+}
+
+// String names a market for coordination, e.g. CALL/350/700000-702016
+func (k OrderKey) String() string {
+	return fmt.Sprintf("%s/%g/%d-%d", k.ContractType, k.StrikeHashRate, k.StartBlockHeight, k.EndBlockHeight)
+}
+
+// claimMarket returns ErrNotMatcher unless this instance matches a market,
+// rebuilding the market's book when it was just taken over from another
+// instance. The caller must hold the market's lock.
+func (ob *OrderBook) claimMarket(ctx context.Context, m *market) error {
+	if ob.coordinator == nil {
+		return nil
+	}
+
+	claimed, fresh, err := ob.coordinator.Claim(ctx, m.key.String())
+	if err != nil {
+		return fmt.Errorf("failed to claim market: %w", err)
+	}
+	if !claimed {
+		return ErrNotMatcher
+	}
+	if fresh {
+		logger.Info().Str("market", m.key.String()).Msg("Took over matching of market")
+		return ob.reloadMarket(ctx, m)
+	}
+	return nil
+}
+
+// reloadMarket rebuilds the book of one market from the open orders in the
+// database, which the previous matcher may have changed. The caller must
+// hold the market's lock.
+func (ob *OrderBook) reloadMarket(ctx context.Context, m *market) error {
+	openOrders, err := ob.orderRepo.ListAllOpenOrders(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list all open orders: %w", err)
+	}
+
+	m.bids, m.asks = nil, nil
+	for _, order := range openOrders {
+		if orderKey(order) != m.key {
+			continue
+		}
+		m.add(order)
+		ob.expiries.schedule(m.key, order)
+	}
+
+	m.sort()
+	ob.notifyMarketUpdate(m)
+	return nil
+}
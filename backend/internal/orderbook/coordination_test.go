@@ -0,0 +1,65 @@
+// internal/orderbook/coordination_test.go
+package orderbook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook/mocks"
+)
+
+func TestOrderKeyString(t *testing.T) {
+	key := OrderKey{
+		ContractType:     models.ContractTypeCall,
+		StrikeHashRate:   350.5,
+		StartBlockHeight: 700000,
+		EndBlockHeight:   702016,
+	}
+	assert.Equal(t, "CALL/350.5/700000-702016", key.String())
+}
+
+func TestClaimMarket(t *testing.T) {
+	orderBook, mockOrderRepo, _ := newMockOrderBook(t)
+
+	ours := OrderKey{ContractType: models.ContractTypeCall, StrikeHashRate: 350, StartBlockHeight: 700000, EndBlockHeight: 702016}
+	theirs := OrderKey{ContractType: models.ContractTypePut, StrikeHashRate: 350, StartBlockHeight: 700000, EndBlockHeight: 702016}
+
+	// Without a coordinator every market is matched here
+	assert.NoError(t, orderBook.claimMarket(context.Background(), orderBook.market(theirs)))
+
+	coordinator := mocks.NewMockCoordinator(gomock.NewController(t))
+	coordinator.EXPECT().Claim(gomock.Any(), theirs.String()).Return(false, false, nil).AnyTimes()
+	gomock.InOrder(
+		coordinator.EXPECT().Claim(gomock.Any(), ours.String()).Return(true, true, nil),
+		coordinator.EXPECT().Claim(gomock.Any(), ours.String()).Return(true, false, nil).AnyTimes(),
+	)
+	orderBook.SetCoordinator(coordinator)
+
+	assert.ErrorIs(t, orderBook.claimMarket(context.Background(), orderBook.market(theirs)), ErrNotMatcher)
+
+	// Taking a market over replaces its book with the database's, which the
+	// previous matcher kept
+	stale := &models.Order{ID: uuid.New(), Side: models.OrderSideBuy, ContractType: ours.ContractType, StrikeHashRate: ours.StrikeHashRate, StartBlockHeight: ours.StartBlockHeight, EndBlockHeight: ours.EndBlockHeight, Price: 90, Quantity: 1, RemainingQuantity: 1, Status: models.OrderStatusOpen}
+	stored := *stale
+	stored.ID = uuid.New()
+	stored.Price = 100
+	other := stored
+	other.ID = uuid.New()
+	other.ContractType = theirs.ContractType
+
+	m := orderBook.market(ours)
+	m.add(stale)
+	mockOrderRepo.EXPECT().ListAllOpenOrders(gomock.Any()).Return([]*models.Order{&stored, &other}, nil)
+
+	assert.NoError(t, orderBook.claimMarket(context.Background(), m))
+	assert.Equal(t, []*models.Order{&stored}, m.bids)
+	assert.Empty(t, m.asks)
+
+	// Later claims keep the book
+	assert.NoError(t, orderBook.claimMarket(context.Background(), m))
+}
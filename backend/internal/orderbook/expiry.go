@@ -0,0 +1,169 @@
+// internal/orderbook/expiry.go
+package orderbook
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// expired reports whether an order's expiry has passed at now
+func expired(order *models.Order, now time.Time) bool {
+	return order.ExpiresAt != nil && !order.ExpiresAt.After(now)
+}
+
+// expiry is when a resting order leaves the book
+type expiry struct {
+	at      time.Time
+	key     OrderKey
+	side    models.OrderSide
+	orderID uuid.UUID
+}
+
+// expiryHeap is a min-heap of expiries, soonest first
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int            { return len(h) }
+func (h expiryHeap) Less(i, j int) bool  { return h[i].at.Before(h[j].at) }
+func (h expiryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x interface{}) { *h = append(*h, x.(expiry)) }
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// expiryQueue schedules resting orders to leave the book at their exact
+// expiry. Entries are not removed when an order is filled, cancelled or
+// rescheduled; an entry whose order is no longer resting is dropped when it
+// comes due.
+type expiryQueue struct {
+	mu      sync.Mutex
+	pending expiryHeap
+	// wake is signalled when an entry becomes the soonest
+	wake chan struct{}
+}
+
+// newExpiryQueue creates an empty expiry queue
+func newExpiryQueue() *expiryQueue {
+	return &expiryQueue{wake: make(chan struct{}, 1)}
+}
+
+// schedule queues an order's expiry, if it has one
+func (q *expiryQueue) schedule(key OrderKey, order *models.Order) {
+	if order.ExpiresAt == nil {
+		return
+	}
+
+	q.mu.Lock()
+	heap.Push(&q.pending, expiry{at: *order.ExpiresAt, key: key, side: order.Side, orderID: order.ID})
+	soonest := q.pending[0].orderID == order.ID
+	q.mu.Unlock()
+
+	if soonest {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// next returns the soonest expiry, if any
+func (q *expiryQueue) next() (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return time.Time{}, false
+	}
+	return q.pending[0].at, true
+}
+
+// due removes and returns every expiry at or before now
+func (q *expiryQueue) due(now time.Time) []expiry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var due []expiry
+	for len(q.pending) > 0 && !q.pending[0].at.After(now) {
+		due = append(due, heap.Pop(&q.pending).(expiry))
+	}
+	return due
+}
+
+// idleExpiryWait is how long the expiry scheduler sleeps with nothing queued;
+// new expiries wake it sooner
+const idleExpiryWait = time.Hour
+
+// runExpiries removes orders from the book as they expire until ctx is done.
+// The periodic sweep still expires orders in the database that never rested
+// in memory.
+func (ob *OrderBook) runExpiries(ctx context.Context) {
+	for {
+		wait := idleExpiryWait
+		if at, ok := ob.expiries.next(); ok {
+			wait = time.Until(at)
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-ob.expiries.wake:
+				timer.Stop()
+				continue
+			case <-timer.C:
+			}
+		}
+
+		for _, e := range ob.expiries.due(time.Now()) {
+			ob.expireOrder(ctx, e)
+		}
+	}
+}
+
+// expireOrder takes an expired order off its market and marks it expired.
+// It runs as a command of the matching engine, so the order cannot fill
+// while it is being expired.
+func (ob *OrderBook) expireOrder(ctx context.Context, e expiry) {
+	m, unlock := ob.lockMarket(e.key)
+	defer unlock()
+
+	var order *models.Order
+	for _, o := range *m.orders(e.side) {
+		if o.ID == e.orderID {
+			order = o
+			break
+		}
+	}
+	// Filled, cancelled or no longer due, e.g. after a reload
+	if order == nil || !expired(order, time.Now()) {
+		return
+	}
+
+	m.remove(e.side, e.orderID)
+	if order.CanBeCancelled() {
+		ok, err := ob.orderRepo.ExpireIfOpen(ctx, e.orderID)
+		if err != nil {
+			// Leave the order for the periodic sweep; it can no longer match
+			m.add(order)
+			logger.Error().Err(err).Str("order_id", e.orderID.String()).Msg("Failed to expire order")
+			return
+		}
+		if ok {
+			order.Status = models.OrderStatusExpired
+			ob.publishOrderEvent(events.OrderExpired, order)
+		}
+	}
+
+	logger.Debug().Str("order_id", e.orderID.String()).Msg("Expired order")
+	ob.notifyMarketUpdate(m)
+}
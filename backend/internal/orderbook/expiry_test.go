@@ -0,0 +1,93 @@
+// internal/orderbook/expiry_test.go
+package orderbook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"hashhedge/internal/models"
+)
+
+func TestExpiryQueue(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(d)
+		return &ts
+	}
+	key := OrderKey{ContractType: models.ContractTypeCall}
+	late := &models.Order{ID: uuid.New(), Side: models.OrderSideBuy, ExpiresAt: at(time.Minute)}
+	soon := &models.Order{ID: uuid.New(), Side: models.OrderSideSell, ExpiresAt: at(time.Second)}
+
+	q := newExpiryQueue()
+	q.schedule(key, &models.Order{ID: uuid.New()})
+	_, ok := q.next()
+	assert.False(t, ok, "orders without an expiry are not queued")
+
+	q.schedule(key, late)
+	<-q.wake
+	q.schedule(key, soon)
+	<-q.wake
+
+	next, ok := q.next()
+	assert.True(t, ok)
+	assert.Equal(t, *soon.ExpiresAt, next)
+
+	assert.Empty(t, q.due(now))
+	due := q.due(now.Add(2 * time.Second))
+	assert.Len(t, due, 1)
+	assert.Equal(t, soon.ID, due[0].orderID)
+	assert.Equal(t, models.OrderSideSell, due[0].side)
+
+	due = q.due(now.Add(time.Hour))
+	assert.Len(t, due, 1)
+	assert.Equal(t, late.ID, due[0].orderID)
+}
+
+func TestExpiredOrdersDoNotMatch(t *testing.T) {
+	past := time.Now().Add(-time.Second)
+	future := time.Now().Add(time.Hour)
+	stale := &models.Order{Price: 100, RemainingQuantity: 2, Status: models.OrderStatusOpen, ExpiresAt: &past}
+	fresh := &models.Order{Price: 100, RemainingQuantity: 2, Status: models.OrderStatusOpen, ExpiresAt: &future}
+
+	assert.False(t, isLive(stale))
+	assert.True(t, isLive(fresh))
+
+	fills := fillQuantities(models.PriceRuleResting, []*models.Order{stale, fresh}, 3,
+		func(*models.Order) bool { return true },
+		func(*models.Order) bool { return true })
+	assert.Equal(t, []int{0, 2}, fills)
+}
+
+func TestExpireOrder(t *testing.T) {
+	orderBook, mockOrderRepo, _ := newMockOrderBook(t)
+
+	past := time.Now().Add(-time.Second)
+	order := &models.Order{
+		ID:                uuid.New(),
+		Side:              models.OrderSideBuy,
+		ContractType:      models.ContractTypeCall,
+		Price:             100,
+		Quantity:          1,
+		RemainingQuantity: 1,
+		Status:            models.OrderStatusOpen,
+		ExpiresAt:         &past,
+	}
+	key := orderKey(order)
+	m := orderBook.market(key)
+	m.add(order)
+
+	mockOrderRepo.EXPECT().ExpireIfOpen(gomock.Any(), order.ID).Return(true, nil)
+
+	e := expiry{at: past, key: key, side: order.Side, orderID: order.ID}
+	orderBook.expireOrder(context.Background(), e)
+	assert.Nil(t, m.bids)
+	assert.Equal(t, models.OrderStatusExpired, order.Status)
+
+	// A second entry for an order already gone does nothing
+	orderBook.expireOrder(context.Background(), e)
+}
@@ -0,0 +1,54 @@
+// internal/orderbook/fill_observer.go
+package orderbook
+
+import (
+	"context"
+
+	"hashhedge/internal/models"
+)
+
+// Fill describes a trade together with the two orders it filled
+type Fill struct {
+	Trade     models.Trade
+	Contract  models.Contract
+	BuyOrder  models.Order
+	SellOrder models.Order
+}
+
+// FillObserver is notified whenever a trade fills a pair of orders
+type FillObserver interface {
+	OnFill(ctx context.Context, fill Fill)
+}
+
+// FillObservers notifies each of several observers in turn
+type FillObservers []FillObserver
+
+// OnFill implements FillObserver
+func (o FillObservers) OnFill(ctx context.Context, fill Fill) {
+	for _, observer := range o {
+		observer.OnFill(ctx, fill)
+	}
+}
+
+// SetFillObserver sets the observer notified of order fills
+func (ob *OrderBook) SetFillObserver(observer FillObserver) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.fillObserver = observer
+}
+
+// notifyFill copies the fill and hands it to the observer without blocking
+// the matching path. The caller must hold ob.mu.
+func (ob *OrderBook) notifyFill(trade *models.Trade, contract *models.Contract, buyOrder, sellOrder *models.Order) {
+	if ob.fillObserver == nil {
+		return
+	}
+
+	fill := Fill{
+		Trade:     *trade,
+		Contract:  *contract,
+		BuyOrder:  *buyOrder,
+		SellOrder: *sellOrder,
+	}
+	go ob.fillObserver.OnFill(context.Background(), fill)
+}
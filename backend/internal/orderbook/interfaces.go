@@ -0,0 +1,77 @@
+// internal/orderbook/interfaces.go
+package orderbook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/events"
+	"hashhedge/internal/models"
+)
+
+// Mocks of the interfaces below are generated into ./mocks; run
+// `make generate` (or `go generate ./...`) after changing them.
+//
+//go:generate mockgen -source=interfaces.go -destination=mocks/interfaces.go -package=mocks
+
+// OrderStore is the persistence layer of orders used by the order book
+type OrderStore interface {
+	Create(ctx context.Context, order *models.Order) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error)
+	Update(ctx context.Context, order *models.Order) error
+	Amend(ctx context.Context, order *models.Order) error
+	DecrementRemainingQuantity(ctx context.Context, id uuid.UUID, amount int) error
+	CancelIfOpen(ctx context.Context, id uuid.UUID) (bool, error)
+	ExpireIfOpen(ctx context.Context, id uuid.UUID) (bool, error)
+	ListOpenOrders(
+		ctx context.Context,
+		contractType models.ContractType,
+		strikeHashRate float64,
+		side models.OrderSide,
+		limit, offset int,
+	) ([]*models.Order, error)
+	ListAllOpenOrders(ctx context.Context) ([]*models.Order, error)
+	ListUserOrdersPage(ctx context.Context, userID uuid.UUID, after models.Cursor, limit int) ([]*models.Order, error)
+	CountUserOrders(ctx context.Context, userID uuid.UUID) (int64, error)
+	CancelExpiredOrders(ctx context.Context) (int64, error)
+}
+
+// TradeStore is the persistence layer of trades used by the order book
+type TradeStore interface {
+	Create(ctx context.Context, tx *sqlx.Tx, trade *models.Trade) error
+	ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*models.Trade, error)
+}
+
+// Transactor runs a function in a database transaction, committing it if
+// the function succeeds
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error
+}
+
+// ContractService is the contract service as used by the order book, which
+// creates a contract for every trade
+type ContractService interface {
+	CreateContract(
+		ctx context.Context,
+		contractType models.ContractType,
+		strikeHashRate float64,
+		startBlockHeight int64,
+		endBlockHeight int64,
+		targetTimestamp time.Time,
+		contractSize int64,
+		premium int64,
+		notional models.Notional,
+		buyerPubKey string,
+		sellerPubKey string,
+	) (*models.Contract, error)
+	RecordPartyKeys(ctx context.Context, contract *models.Contract, buyerKeyID, sellerKeyID *uuid.UUID) error
+	CurrentBlockHeight(ctx context.Context) (int64, error)
+}
+
+// EventPublisher publishes events on the event bus
+type EventPublisher interface {
+	Publish(topic events.Topic, payload interface{})
+}
@@ -0,0 +1,51 @@
+// internal/orderbook/ledger.go
+package orderbook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/models"
+)
+
+// ErrInsufficientFunds is returned for an order its user's funds do not cover
+var ErrInsufficientFunds = fmt.Errorf("%w: insufficient funds", ErrOrderRejected)
+
+// Ledger books the funds of users' trades
+type Ledger interface {
+	// AvailableToTrade returns a user's funds not held by their resting
+	// orders other than excludeOrderID
+	AvailableToTrade(ctx context.Context, userID, excludeOrderID uuid.UUID) (int64, error)
+	// RecordTrade books the premium and collateral of the contract a trade
+	// created, in the trade's transaction
+	RecordTrade(ctx context.Context, tx *sqlx.Tx, buyOrder, sellOrder *models.Order, contract *models.Contract) error
+}
+
+// SetLedger limits orders to their users' funds and books every trade
+func (ob *OrderBook) SetLedger(ledger Ledger) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.ledger = ledger
+}
+
+// checkFunds rejects an order whose value, the price of its remaining
+// quantity, exceeds its user's funds not held by their other orders. The
+// caller must hold the market's lock.
+func (ob *OrderBook) checkFunds(ctx context.Context, order *models.Order) error {
+	if ob.ledger == nil {
+		return nil
+	}
+
+	available, err := ob.ledger.AvailableToTrade(ctx, order.UserID, order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get available funds: %w", err)
+	}
+
+	if required := order.Price * int64(order.RemainingQuantity); required > available {
+		return fmt.Errorf("%w: order needs %d sats, %d are available", ErrInsufficientFunds, required, available)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// internal/orderbook/ledger_test.go
+package orderbook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+// fundsLedger reports the same funds for every user
+type fundsLedger struct {
+	available int64
+	excluded  uuid.UUID
+}
+
+func (l *fundsLedger) AvailableToTrade(ctx context.Context, userID, excludeOrderID uuid.UUID) (int64, error) {
+	l.excluded = excludeOrderID
+	return l.available, nil
+}
+
+func (l *fundsLedger) RecordTrade(ctx context.Context, tx *sqlx.Tx, buyOrder, sellOrder *models.Order, contract *models.Contract) error {
+	return nil
+}
+
+func TestCheckFunds(t *testing.T) {
+	ob := &OrderBook{}
+	order := &models.Order{ID: uuid.New(), Price: 1000, Quantity: 5, RemainingQuantity: 5}
+
+	// Without a ledger orders are not limited
+	assert.NoError(t, ob.checkFunds(context.Background(), order))
+
+	ledger := &fundsLedger{available: 5000}
+	ob.SetLedger(ledger)
+	assert.NoError(t, ob.checkFunds(context.Background(), order))
+	assert.Equal(t, order.ID, ledger.excluded)
+
+	ledger.available = 4999
+	err := ob.checkFunds(context.Background(), order)
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.ErrorIs(t, err, ErrOrderRejected)
+
+	// Only the unfilled quantity needs funds
+	order.RemainingQuantity = 4
+	assert.NoError(t, ob.checkFunds(context.Background(), order))
+}
@@ -0,0 +1,168 @@
+// internal/orderbook/market.go
+package orderbook
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// market is the in-memory book of one market. Each market has its own lock,
+// so orders in one market are matched while another is busy matching.
+type market struct {
+	mu   sync.Mutex
+	key  OrderKey
+	bids []*models.Order // Buy orders
+	asks []*models.Order // Sell orders
+
+	// lastTrade is the price of the market's last trade, nil before the first
+	lastTrade *int64
+}
+
+// orders returns the side of the market an order of side rests on
+func (m *market) orders(side models.OrderSide) *[]*models.Order {
+	if side == models.OrderSideBuy {
+		return &m.bids
+	}
+	return &m.asks
+}
+
+// add rests an order on its side of the market
+func (m *market) add(order *models.Order) {
+	orders := m.orders(order.Side)
+	*orders = append(*orders, order)
+}
+
+// remove takes an order off its side of the market, returning the resting
+// copy or nil if it was not on the book
+func (m *market) remove(side models.OrderSide, orderID uuid.UUID) *models.Order {
+	orders := m.orders(side)
+	for i, o := range *orders {
+		if o.ID != orderID {
+			continue
+		}
+
+		*orders = append((*orders)[:i:i], (*orders)[i+1:]...)
+		if len(*orders) == 0 {
+			*orders = nil
+		}
+		return o
+	}
+
+	return nil
+}
+
+// live reports whether the market has an order that can still be matched
+func (m *market) live() bool {
+	for _, orders := range [][]*models.Order{m.bids, m.asks} {
+		for _, order := range orders {
+			if isLive(order) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sort orders both sides by price and time priority, best price first
+func (m *market) sort() {
+	sortByPriority(m.bids, func(a, b int64) bool { return a > b })
+	sortByPriority(m.asks, func(a, b int64) bool { return a < b })
+}
+
+// sortByPriority orders one side of a market best price first according to
+// better, and by time within a price
+func sortByPriority(orders []*models.Order, better func(a, b int64) bool) {
+	sort.SliceStable(orders, func(i, j int) bool {
+		if orders[i].Price == orders[j].Price {
+			return orders[i].PriorityAt.Before(orders[j].PriorityAt)
+		}
+		return better(orders[i].Price, orders[j].Price)
+	})
+}
+
+// snapshot computes the top of book and last trade of the market
+func (m *market) snapshot() MarketSnapshot {
+	snapshot := MarketSnapshot{
+		Key:  m.key,
+		Time: time.Now().UTC(),
+	}
+
+	for _, order := range m.bids {
+		if !isLive(order) {
+			continue
+		}
+		if snapshot.BestBid == nil || order.Price > *snapshot.BestBid {
+			price := order.Price
+			snapshot.BestBid = &price
+		}
+	}
+
+	for _, order := range m.asks {
+		if !isLive(order) {
+			continue
+		}
+		if snapshot.BestAsk == nil || order.Price < *snapshot.BestAsk {
+			price := order.Price
+			snapshot.BestAsk = &price
+		}
+	}
+
+	if m.lastTrade != nil {
+		price := *m.lastTrade
+		snapshot.LastTrade = &price
+	}
+
+	return snapshot
+}
+
+// market returns the book of a market, creating it on first use
+func (ob *OrderBook) market(key OrderKey) *market {
+	ob.marketsMu.Lock()
+	defer ob.marketsMu.Unlock()
+
+	m, ok := ob.markets[key]
+	if !ok {
+		m = &market{key: key}
+		ob.markets[key] = m
+	}
+	return m
+}
+
+// lookupMarket returns the book of a market, or nil if it has never had an order
+func (ob *OrderBook) lookupMarket(key OrderKey) *market {
+	ob.marketsMu.Lock()
+	defer ob.marketsMu.Unlock()
+	return ob.markets[key]
+}
+
+// allMarkets returns the book of every market
+func (ob *OrderBook) allMarkets() []*market {
+	ob.marketsMu.Lock()
+	defer ob.marketsMu.Unlock()
+
+	markets := make([]*market, 0, len(ob.markets))
+	for _, m := range ob.markets {
+		markets = append(markets, m)
+	}
+	return markets
+}
+
+// lockMarket holds ob.mu shared and locks the book of a market, returning a
+// function that releases both. Commands on a single market run under it, so
+// they only wait on commands in the same market and on whole-book commands
+// such as reloads, which hold ob.mu exclusively.
+func (ob *OrderBook) lockMarket(key OrderKey) (*market, func()) {
+	ob.mu.RLock()
+	m := ob.market(key)
+	m.mu.Lock()
+
+	return m, func() {
+		m.mu.Unlock()
+		ob.mu.RUnlock()
+	}
+}
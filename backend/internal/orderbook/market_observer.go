@@ -0,0 +1,65 @@
+// internal/orderbook/market_observer.go
+package orderbook
+
+import (
+	"context"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// MarketSnapshot is the top of book and last trade of a market after a change
+type MarketSnapshot struct {
+	Key       OrderKey
+	BestBid   *int64
+	BestAsk   *int64
+	LastTrade *int64
+	Time      time.Time
+}
+
+// MarketObserver is notified whenever an order placement, cancellation or
+// match changes a market
+type MarketObserver interface {
+	OnMarketUpdate(ctx context.Context, snapshot MarketSnapshot)
+}
+
+// MarketObservers notifies each of several observers in turn
+type MarketObservers []MarketObserver
+
+// OnMarketUpdate implements MarketObserver
+func (o MarketObservers) OnMarketUpdate(ctx context.Context, snapshot MarketSnapshot) {
+	for _, observer := range o {
+		observer.OnMarketUpdate(ctx, snapshot)
+	}
+}
+
+// SetMarketObserver sets the observer notified of market changes
+func (ob *OrderBook) SetMarketObserver(observer MarketObserver) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.observer = observer
+}
+
+// notifyMarketUpdate snapshots the market, records it on the ticker and
+// hands it to the observer without blocking the matching path. The caller
+// must hold ob.mu and the market's lock.
+func (ob *OrderBook) notifyMarketUpdate(m *market) {
+	if ob.observer == nil && ob.ticker == nil {
+		return
+	}
+
+	snapshot := m.snapshot()
+	if ob.ticker != nil {
+		ob.ticker.RecordQuote(snapshot)
+	}
+	if ob.observer != nil {
+		go ob.observer.OnMarketUpdate(context.Background(), snapshot)
+	}
+}
+
+// isLive reports whether an order can still be matched
+func isLive(order *models.Order) bool {
+	return order.RemainingQuantity > 0 &&
+		(order.Status == models.OrderStatusOpen || order.Status == models.OrderStatusPartial) &&
+		!expired(order, time.Now())
+}
@@ -0,0 +1,69 @@
+// internal/orderbook/market_order.go
+package orderbook
+
+import (
+	"fmt"
+
+	"hashhedge/internal/models"
+)
+
+// ErrNoLiquidity is returned when a market order has no resting order to trade against
+var ErrNoLiquidity = fmt.Errorf("%w: no liquidity for market order", ErrOrderRejected)
+
+// bpsDenominator is the number of basis points in one
+const bpsDenominator = 10000
+
+// bestPrice returns the best price among the live resting orders an incoming
+// order may trade against: the lowest ask for a buy, the highest bid for a sell
+func bestPrice(incoming *models.Order, resting []*models.Order) (int64, bool) {
+	var best int64
+	found := false
+
+	for _, o := range resting {
+		if !isLive(o) || !sameTerms(o, incoming) {
+			continue
+		}
+		better := o.Price < best
+		if incoming.Side == models.OrderSideSell {
+			better = o.Price > best
+		}
+		if !found || better {
+			best, found = o.Price, true
+		}
+	}
+
+	return best, found
+}
+
+// slippageLimit returns the worst price a market order may trade at: the best
+// opposite price moved against the order by maxSlippageBps basis points
+func slippageLimit(side models.OrderSide, best int64, maxSlippageBps int) int64 {
+	if side == models.OrderSideBuy {
+		return best * (bpsDenominator + int64(maxSlippageBps)) / bpsDenominator
+	}
+
+	// Round up so a sell never accepts more slippage than configured
+	limit := (best*(bpsDenominator-int64(maxSlippageBps)) + bpsDenominator - 1) / bpsDenominator
+	if limit < 1 {
+		return 1
+	}
+	return limit
+}
+
+// priceMarketOrder sets the price of a market order to its slippage limit,
+// so matching sweeps the opposite side of its market up to that price. It
+// must be called with the market's lock held.
+func (ob *OrderBook) priceMarketOrder(m *market, order *models.Order) error {
+	resting := m.asks
+	if order.Side == models.OrderSideSell {
+		resting = m.bids
+	}
+
+	best, ok := bestPrice(order, resting)
+	if !ok {
+		return ErrNoLiquidity
+	}
+
+	order.Price = slippageLimit(order.Side, best, ob.cfg.MaxSlippageBps)
+	return nil
+}
@@ -0,0 +1,57 @@
+// internal/orderbook/market_order_test.go
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestBestPrice(t *testing.T) {
+	asks := []*models.Order{restingOrder(110, 1), restingOrder(100, 2), restingOrder(105, 3)}
+	buy := &models.Order{Side: models.OrderSideBuy, Type: models.OrderTypeMarket}
+
+	best, ok := bestPrice(buy, asks)
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), best)
+
+	sell := &models.Order{Side: models.OrderSideSell, Type: models.OrderTypeMarket}
+	best, ok = bestPrice(sell, asks)
+	assert.True(t, ok)
+	assert.Equal(t, int64(110), best)
+
+	t.Run("skips orders that cannot trade", func(t *testing.T) {
+		target := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+		other := target.Add(time.Hour)
+
+		orders := []*models.Order{restingOrder(100, 2), restingOrder(105, 3), restingOrder(108, 1)}
+		orders[0].Status = models.OrderStatusCancelled
+		orders[1].TargetTimestamp = &other
+		incoming := &models.Order{Side: models.OrderSideBuy, TargetTimestamp: &target}
+
+		best, ok := bestPrice(incoming, orders)
+		assert.True(t, ok)
+		assert.Equal(t, int64(108), best)
+	})
+
+	t.Run("empty side has no price", func(t *testing.T) {
+		_, ok := bestPrice(buy, nil)
+		assert.False(t, ok)
+	})
+}
+
+func TestSlippageLimit(t *testing.T) {
+	assert.Equal(t, int64(105000), slippageLimit(models.OrderSideBuy, 100000, 500))
+	assert.Equal(t, int64(95000), slippageLimit(models.OrderSideSell, 100000, 500))
+	assert.Equal(t, int64(100), slippageLimit(models.OrderSideBuy, 100, 0))
+
+	// Rounding never widens the slippage
+	assert.Equal(t, int64(1010), slippageLimit(models.OrderSideBuy, 1001, 99))
+	assert.Equal(t, int64(992), slippageLimit(models.OrderSideSell, 1001, 99))
+
+	// A sell never goes below one satoshi
+	assert.Equal(t, int64(1), slippageLimit(models.OrderSideSell, 100, bpsDenominator))
+}
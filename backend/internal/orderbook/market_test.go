@@ -0,0 +1,217 @@
+// internal/orderbook/market_test.go
+package orderbook
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+// dbLatency simulates the round trip of each database write, which is what
+// a market's lock is held across while matching
+const dbLatency = 50 * time.Microsecond
+
+// fakeTransactor runs functions without a transaction
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	return fn(nil)
+}
+
+// fakeOrderStore accepts every write after dbLatency. Unlike the mocks it
+// takes no lock, so concurrent callers are not serialized by the test.
+type fakeOrderStore struct {
+	OrderStore
+}
+
+func (fakeOrderStore) Create(ctx context.Context, order *models.Order) error {
+	time.Sleep(dbLatency)
+	return nil
+}
+
+func (fakeOrderStore) Update(ctx context.Context, order *models.Order) error {
+	time.Sleep(dbLatency)
+	return nil
+}
+
+func (fakeOrderStore) DecrementRemainingQuantity(ctx context.Context, id uuid.UUID, amount int) error {
+	time.Sleep(dbLatency)
+	return nil
+}
+
+// fakeTradeStore accepts every trade after dbLatency
+type fakeTradeStore struct {
+	TradeStore
+}
+
+func (fakeTradeStore) Create(ctx context.Context, tx *sqlx.Tx, trade *models.Trade) error {
+	time.Sleep(dbLatency)
+	return nil
+}
+
+// fakeContractService creates a contract for every trade at a fixed tip
+type fakeContractService struct {
+	ContractService
+}
+
+func (fakeContractService) CreateContract(
+	ctx context.Context,
+	contractType models.ContractType,
+	strikeHashRate float64,
+	startBlockHeight int64,
+	endBlockHeight int64,
+	targetTimestamp time.Time,
+	contractSize int64,
+	premium int64,
+	notional models.Notional,
+	buyerPubKey string,
+	sellerPubKey string,
+) (*models.Contract, error) {
+	time.Sleep(dbLatency)
+	return &models.Contract{ID: uuid.New()}, nil
+}
+
+func (fakeContractService) RecordPartyKeys(ctx context.Context, contract *models.Contract, buyerKeyID, sellerKeyID *uuid.UUID) error {
+	return nil
+}
+
+func (fakeContractService) CurrentBlockHeight(ctx context.Context) (int64, error) {
+	return 700000, nil
+}
+
+func newFakeOrderBook(tb testing.TB) *OrderBook {
+	tb.Helper()
+
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	tb.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+
+	return NewOrderBook(fakeTransactor{}, fakeOrderStore{}, fakeTradeStore{}, nil, fakeContractService{})
+}
+
+// benchmarkKey returns the key of the i-th market, alternating between calls
+// and puts across strikes
+func benchmarkKey(i int) OrderKey {
+	contractType := models.ContractTypeCall
+	if i%2 == 1 {
+		contractType = models.ContractTypePut
+	}
+
+	return OrderKey{
+		ContractType:     contractType,
+		StrikeHashRate:   float64(300 + 10*(i/2)),
+		StartBlockHeight: 700000,
+		EndBlockHeight:   702016,
+	}
+}
+
+// orderPubKey is the key every test order is placed with
+const orderPubKey = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+
+func newLimitOrder(key OrderKey, side models.OrderSide) *models.Order {
+	return &models.Order{
+		UserID:           uuid.New(),
+		PubKey:           orderPubKey,
+		Side:             side,
+		Type:             models.OrderTypeLimit,
+		TimeInForce:      models.TimeInForceGTC,
+		ContractType:     key.ContractType,
+		StrikeHashRate:   key.StrikeHashRate,
+		StartBlockHeight: key.StartBlockHeight,
+		EndBlockHeight:   key.EndBlockHeight,
+		Price:            100000,
+		Quantity:         1,
+	}
+}
+
+func TestPlaceOrderConcurrentMarkets(t *testing.T) {
+	ob := newFakeOrderBook(t)
+	ctx := context.Background()
+
+	const markets, pairs = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, markets*2)
+	for i := 0; i < markets; i++ {
+		key := benchmarkKey(i)
+		for _, side := range []models.OrderSide{models.OrderSideBuy, models.OrderSideSell} {
+			wg.Add(1)
+			go func(key OrderKey, side models.OrderSide) {
+				defer wg.Done()
+				for n := 0; n < pairs; n++ {
+					if _, err := ob.PlaceOrder(ctx, newLimitOrder(key, side)); err != nil {
+						errs <- err
+						return
+					}
+				}
+			}(key, side)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// Every buy crossed a sell at the same price, so nothing is left resting
+	assert.Equal(t, BookStats{}, ob.Stats())
+
+	tickers := ob.Tickers()
+	require.Len(t, tickers, markets)
+	for _, ticker := range tickers {
+		require.NotNil(t, ticker.LastTrade)
+		assert.Equal(t, int64(100000), *ticker.LastTrade)
+	}
+}
+
+// BenchmarkPlaceOrder places crossing orders from parallel clients, either
+// all in one market or each in its own, contrasting contention on a single
+// market's lock with matching across contract types and strikes
+func BenchmarkPlaceOrder(b *testing.B) {
+	for _, bm := range []struct {
+		name    string
+		markets bool
+	}{
+		{name: "single market", markets: false},
+		{name: "across markets", markets: true},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			ob := newFakeOrderBook(b)
+			ctx := context.Background()
+			var clients int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				client := int(atomic.AddInt64(&clients, 1))
+				key := benchmarkKey(0)
+				if bm.markets {
+					key = benchmarkKey(client)
+				}
+
+				side := models.OrderSideBuy
+				for pb.Next() {
+					if _, err := ob.PlaceOrder(ctx, newLimitOrder(key, side)); err != nil {
+						b.Errorf("client %d: %v", client, err)
+						return
+					}
+
+					if side == models.OrderSideBuy {
+						side = models.OrderSideSell
+					} else {
+						side = models.OrderSideBuy
+					}
+				}
+			})
+		})
+	}
+}
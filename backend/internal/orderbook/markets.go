@@ -0,0 +1,177 @@
+// internal/orderbook/markets.go
+package orderbook
+
+import (
+	"sort"
+
+	"hashhedge/internal/models"
+)
+
+// ListMarkets returns the markets of a contract type and strike that have
+// live orders, ordered by end block height
+func (ob *OrderBook) ListMarkets(contractType models.ContractType, strikeHashRate float64) []OrderKey {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	markets := []OrderKey{}
+	for _, m := range ob.allMarkets() {
+		if m.key.ContractType != contractType || m.key.StrikeHashRate != strikeHashRate {
+			continue
+		}
+
+		m.mu.Lock()
+		live := m.live()
+		m.mu.Unlock()
+
+		if live {
+			markets = append(markets, m.key)
+		}
+	}
+	sort.Slice(markets, func(i, j int) bool {
+		if markets[i].EndBlockHeight != markets[j].EndBlockHeight {
+			return markets[i].EndBlockHeight < markets[j].EndBlockHeight
+		}
+		return markets[i].StartBlockHeight < markets[j].StartBlockHeight
+	})
+
+	return markets
+}
+
+// PriceLevel is the resting quantity of a market at one price. Cumulative
+// is the quantity at this and every better price, as plotted by a depth chart.
+type PriceLevel struct {
+	Price      int64 `json:"price"`
+	Quantity   int   `json:"quantity"`
+	Cumulative int   `json:"cumulative"`
+	Orders     int   `json:"orders"`
+}
+
+// Depth is the resting quantity of a market aggregated by price, best price first
+type Depth struct {
+	Bids []PriceLevel `json:"bids"`
+	Asks []PriceLevel `json:"asks"`
+}
+
+// BookStats summarizes the live orders across every market
+type BookStats struct {
+	Markets     int `json:"markets"`
+	Bids        int `json:"bids"`
+	Asks        int `json:"asks"`
+	BidQuantity int `json:"bid_quantity"`
+	AskQuantity int `json:"ask_quantity"`
+}
+
+// Depth returns up to levels price levels on each side of a market from the
+// in-memory book; a non-positive levels returns every level
+func (ob *OrderBook) Depth(key OrderKey, levels int) Depth {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	m := ob.lookupMarket(key)
+	if m == nil {
+		return Depth{Bids: []PriceLevel{}, Asks: []PriceLevel{}}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return Depth{
+		Bids: aggregateLevels(m.bids, func(a, b int64) bool { return a > b }, levels),
+		Asks: aggregateLevels(m.asks, func(a, b int64) bool { return a < b }, levels),
+	}
+}
+
+// Tickers returns the snapshot of every market with live orders or a last
+// trade, ordered by contract type, strike and end block height
+func (ob *OrderBook) Tickers() []MarketSnapshot {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	tickers := []MarketSnapshot{}
+	for _, m := range ob.allMarkets() {
+		m.mu.Lock()
+		if m.lastTrade != nil || m.live() {
+			tickers = append(tickers, m.snapshot())
+		}
+		m.mu.Unlock()
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		a, b := tickers[i].Key, tickers[j].Key
+		if a.ContractType != b.ContractType {
+			return a.ContractType < b.ContractType
+		}
+		if a.StrikeHashRate != b.StrikeHashRate {
+			return a.StrikeHashRate < b.StrikeHashRate
+		}
+		if a.EndBlockHeight != b.EndBlockHeight {
+			return a.EndBlockHeight < b.EndBlockHeight
+		}
+		return a.StartBlockHeight < b.StartBlockHeight
+	})
+
+	return tickers
+}
+
+// Stats counts the live orders and resting quantity of the in-memory book
+func (ob *OrderBook) Stats() BookStats {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	var stats BookStats
+	for _, m := range ob.allMarkets() {
+		m.mu.Lock()
+		for _, order := range m.bids {
+			if isLive(order) {
+				stats.Bids++
+				stats.BidQuantity += order.RemainingQuantity
+			}
+		}
+		for _, order := range m.asks {
+			if isLive(order) {
+				stats.Asks++
+				stats.AskQuantity += order.RemainingQuantity
+			}
+		}
+		if m.live() {
+			stats.Markets++
+		}
+		m.mu.Unlock()
+	}
+
+	return stats
+}
+
+// aggregateLevels sums the live orders of one side of a market by price,
+// best price first according to better
+func aggregateLevels(orders []*models.Order, better func(a, b int64) bool, levels int) []PriceLevel {
+	byPrice := make(map[int64]*PriceLevel)
+	for _, order := range orders {
+		if !isLive(order) {
+			continue
+		}
+		level, ok := byPrice[order.Price]
+		if !ok {
+			level = &PriceLevel{Price: order.Price}
+			byPrice[order.Price] = level
+		}
+		level.Quantity += order.RemainingQuantity
+		level.Orders++
+	}
+
+	result := make([]PriceLevel, 0, len(byPrice))
+	for _, level := range byPrice {
+		result = append(result, *level)
+	}
+	sort.Slice(result, func(i, j int) bool { return better(result[i].Price, result[j].Price) })
+
+	if levels > 0 && len(result) > levels {
+		result = result[:levels]
+	}
+
+	cumulative := 0
+	for i := range result {
+		cumulative += result[i].Quantity
+		result[i].Cumulative = cumulative
+	}
+	return result
+}
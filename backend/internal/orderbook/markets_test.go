@@ -0,0 +1,37 @@
+// internal/orderbook/markets_test.go
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestAggregateLevels(t *testing.T) {
+	cancelled := restingOrder(103, 7)
+	cancelled.Status = models.OrderStatusCancelled
+	orders := []*models.Order{
+		restingOrder(100, 3),
+		restingOrder(105, 2),
+		restingOrder(100, 4),
+		cancelled,
+		restingOrder(110, 1),
+	}
+	asc := func(a, b int64) bool { return a < b }
+	desc := func(a, b int64) bool { return a > b }
+
+	assert.Equal(t, []PriceLevel{
+		{Price: 100, Quantity: 7, Cumulative: 7, Orders: 2},
+		{Price: 105, Quantity: 2, Cumulative: 9, Orders: 1},
+		{Price: 110, Quantity: 1, Cumulative: 10, Orders: 1},
+	}, aggregateLevels(orders, asc, 0))
+
+	assert.Equal(t, []PriceLevel{
+		{Price: 110, Quantity: 1, Cumulative: 1, Orders: 1},
+		{Price: 105, Quantity: 2, Cumulative: 3, Orders: 1},
+	}, aggregateLevels(orders, desc, 2))
+
+	assert.Empty(t, aggregateLevels(nil, asc, 5))
+}
@@ -0,0 +1,417 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interfaces.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interfaces.go -destination=mocks/interfaces.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	events "hashhedge/internal/events"
+	models "hashhedge/internal/models"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	sqlx "github.com/jmoiron/sqlx"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrderStore is a mock of OrderStore interface.
+type MockOrderStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderStoreMockRecorder is the mock recorder for MockOrderStore.
+type MockOrderStoreMockRecorder struct {
+	mock *MockOrderStore
+}
+
+// NewMockOrderStore creates a new mock instance.
+func NewMockOrderStore(ctrl *gomock.Controller) *MockOrderStore {
+	mock := &MockOrderStore{ctrl: ctrl}
+	mock.recorder = &MockOrderStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderStore) EXPECT() *MockOrderStoreMockRecorder {
+	return m.recorder
+}
+
+// Amend mocks base method.
+func (m *MockOrderStore) Amend(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Amend", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Amend indicates an expected call of Amend.
+func (mr *MockOrderStoreMockRecorder) Amend(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Amend", reflect.TypeOf((*MockOrderStore)(nil).Amend), ctx, order)
+}
+
+// CancelExpiredOrders mocks base method.
+func (m *MockOrderStore) CancelExpiredOrders(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelExpiredOrders", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelExpiredOrders indicates an expected call of CancelExpiredOrders.
+func (mr *MockOrderStoreMockRecorder) CancelExpiredOrders(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelExpiredOrders", reflect.TypeOf((*MockOrderStore)(nil).CancelExpiredOrders), ctx)
+}
+
+// CancelIfOpen mocks base method.
+func (m *MockOrderStore) CancelIfOpen(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelIfOpen", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelIfOpen indicates an expected call of CancelIfOpen.
+func (mr *MockOrderStoreMockRecorder) CancelIfOpen(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelIfOpen", reflect.TypeOf((*MockOrderStore)(nil).CancelIfOpen), ctx, id)
+}
+
+// CountUserOrders mocks base method.
+func (m *MockOrderStore) CountUserOrders(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserOrders", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserOrders indicates an expected call of CountUserOrders.
+func (mr *MockOrderStoreMockRecorder) CountUserOrders(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserOrders", reflect.TypeOf((*MockOrderStore)(nil).CountUserOrders), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockOrderStore) Create(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrderStoreMockRecorder) Create(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrderStore)(nil).Create), ctx, order)
+}
+
+// DecrementRemainingQuantity mocks base method.
+func (m *MockOrderStore) DecrementRemainingQuantity(ctx context.Context, id uuid.UUID, amount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementRemainingQuantity", ctx, id, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecrementRemainingQuantity indicates an expected call of DecrementRemainingQuantity.
+func (mr *MockOrderStoreMockRecorder) DecrementRemainingQuantity(ctx, id, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementRemainingQuantity", reflect.TypeOf((*MockOrderStore)(nil).DecrementRemainingQuantity), ctx, id, amount)
+}
+
+// ExpireIfOpen mocks base method.
+func (m *MockOrderStore) ExpireIfOpen(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireIfOpen", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireIfOpen indicates an expected call of ExpireIfOpen.
+func (mr *MockOrderStoreMockRecorder) ExpireIfOpen(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireIfOpen", reflect.TypeOf((*MockOrderStore)(nil).ExpireIfOpen), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockOrderStore) GetByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOrderStoreMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrderStore)(nil).GetByID), ctx, id)
+}
+
+// ListAllOpenOrders mocks base method.
+func (m *MockOrderStore) ListAllOpenOrders(ctx context.Context) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllOpenOrders", ctx)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllOpenOrders indicates an expected call of ListAllOpenOrders.
+func (mr *MockOrderStoreMockRecorder) ListAllOpenOrders(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllOpenOrders", reflect.TypeOf((*MockOrderStore)(nil).ListAllOpenOrders), ctx)
+}
+
+// ListOpenOrders mocks base method.
+func (m *MockOrderStore) ListOpenOrders(ctx context.Context, contractType models.ContractType, strikeHashRate float64, side models.OrderSide, limit, offset int) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenOrders", ctx, contractType, strikeHashRate, side, limit, offset)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenOrders indicates an expected call of ListOpenOrders.
+func (mr *MockOrderStoreMockRecorder) ListOpenOrders(ctx, contractType, strikeHashRate, side, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenOrders", reflect.TypeOf((*MockOrderStore)(nil).ListOpenOrders), ctx, contractType, strikeHashRate, side, limit, offset)
+}
+
+// ListUserOrdersPage mocks base method.
+func (m *MockOrderStore) ListUserOrdersPage(ctx context.Context, userID uuid.UUID, after models.Cursor, limit int) ([]*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserOrdersPage", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserOrdersPage indicates an expected call of ListUserOrdersPage.
+func (mr *MockOrderStoreMockRecorder) ListUserOrdersPage(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserOrdersPage", reflect.TypeOf((*MockOrderStore)(nil).ListUserOrdersPage), ctx, userID, after, limit)
+}
+
+// Update mocks base method.
+func (m *MockOrderStore) Update(ctx context.Context, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrderStoreMockRecorder) Update(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrderStore)(nil).Update), ctx, order)
+}
+
+// MockTradeStore is a mock of TradeStore interface.
+type MockTradeStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTradeStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTradeStoreMockRecorder is the mock recorder for MockTradeStore.
+type MockTradeStoreMockRecorder struct {
+	mock *MockTradeStore
+}
+
+// NewMockTradeStore creates a new mock instance.
+func NewMockTradeStore(ctrl *gomock.Controller) *MockTradeStore {
+	mock := &MockTradeStore{ctrl: ctrl}
+	mock.recorder = &MockTradeStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTradeStore) EXPECT() *MockTradeStoreMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTradeStore) Create(ctx context.Context, tx *sqlx.Tx, trade *models.Trade) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tx, trade)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTradeStoreMockRecorder) Create(ctx, tx, trade any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTradeStore)(nil).Create), ctx, tx, trade)
+}
+
+// ListByOrderID mocks base method.
+func (m *MockTradeStore) ListByOrderID(ctx context.Context, orderID uuid.UUID) ([]*models.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByOrderID", ctx, orderID)
+	ret0, _ := ret[0].([]*models.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByOrderID indicates an expected call of ListByOrderID.
+func (mr *MockTradeStoreMockRecorder) ListByOrderID(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByOrderID", reflect.TypeOf((*MockTradeStore)(nil).ListByOrderID), ctx, orderID)
+}
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithTransaction mocks base method.
+func (m *MockTransactor) WithTransaction(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockTransactorMockRecorder) WithTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockTransactor)(nil).WithTransaction), ctx, fn)
+}
+
+// MockContractService is a mock of ContractService interface.
+type MockContractService struct {
+	ctrl     *gomock.Controller
+	recorder *MockContractServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockContractServiceMockRecorder is the mock recorder for MockContractService.
+type MockContractServiceMockRecorder struct {
+	mock *MockContractService
+}
+
+// NewMockContractService creates a new mock instance.
+func NewMockContractService(ctrl *gomock.Controller) *MockContractService {
+	mock := &MockContractService{ctrl: ctrl}
+	mock.recorder = &MockContractServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContractService) EXPECT() *MockContractServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateContract mocks base method.
+func (m *MockContractService) CreateContract(ctx context.Context, contractType models.ContractType, strikeHashRate float64, startBlockHeight, endBlockHeight int64, targetTimestamp time.Time, contractSize, premium int64, notional models.Notional, buyerPubKey, sellerPubKey string) (*models.Contract, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContract", ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, targetTimestamp, contractSize, premium, notional, buyerPubKey, sellerPubKey)
+	ret0, _ := ret[0].(*models.Contract)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContract indicates an expected call of CreateContract.
+func (mr *MockContractServiceMockRecorder) CreateContract(ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, targetTimestamp, contractSize, premium, notional, buyerPubKey, sellerPubKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContract", reflect.TypeOf((*MockContractService)(nil).CreateContract), ctx, contractType, strikeHashRate, startBlockHeight, endBlockHeight, targetTimestamp, contractSize, premium, notional, buyerPubKey, sellerPubKey)
+}
+
+// CurrentBlockHeight mocks base method.
+func (m *MockContractService) CurrentBlockHeight(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentBlockHeight", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentBlockHeight indicates an expected call of CurrentBlockHeight.
+func (mr *MockContractServiceMockRecorder) CurrentBlockHeight(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentBlockHeight", reflect.TypeOf((*MockContractService)(nil).CurrentBlockHeight), ctx)
+}
+
+// RecordPartyKeys mocks base method.
+func (m *MockContractService) RecordPartyKeys(ctx context.Context, contract *models.Contract, buyerKeyID, sellerKeyID *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPartyKeys", ctx, contract, buyerKeyID, sellerKeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPartyKeys indicates an expected call of RecordPartyKeys.
+func (mr *MockContractServiceMockRecorder) RecordPartyKeys(ctx, contract, buyerKeyID, sellerKeyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPartyKeys", reflect.TypeOf((*MockContractService)(nil).RecordPartyKeys), ctx, contract, buyerKeyID, sellerKeyID)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(topic events.Topic, payload any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", topic, payload)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(topic, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), topic, payload)
+}
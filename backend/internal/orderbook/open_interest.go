@@ -0,0 +1,47 @@
+// internal/orderbook/open_interest.go
+package orderbook
+
+import (
+	"context"
+	"fmt"
+
+	"hashhedge/internal/models"
+)
+
+// OpenInterestSource reports the open interest of a market
+type OpenInterestSource interface {
+	Get(
+		ctx context.Context,
+		contractType models.ContractType,
+		strikeHashRate float64,
+		startBlockHeight, endBlockHeight int64,
+	) (*models.OpenInterest, error)
+}
+
+// SetOpenInterestSource enables the open interest risk limit
+func (ob *OrderBook) SetOpenInterestSource(source OpenInterestSource) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.openInterest = source
+}
+
+// checkOpenInterest rejects orders and matches in a market that has reached
+// its open interest limit. Contracts count once activated, so matches still
+// awaiting setup are not included. The caller must hold ob.mu.
+func (ob *OrderBook) checkOpenInterest(ctx context.Context, key OrderKey) error {
+	if ob.openInterest == nil || ob.cfg.Risk.MaxOpenInterest == 0 {
+		return nil
+	}
+
+	oi, err := ob.openInterest.Get(ctx, key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to get open interest: %w", err)
+	}
+
+	var contracts int64
+	if oi != nil {
+		contracts = oi.Contracts
+	}
+
+	return ob.cfg.Risk.CheckOpenInterest(contracts)
+}
@@ -4,17 +4,25 @@ package orderbook
 import (
 	"context"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	
 	"hashhedge/internal/contract"
-	"hashhedge/internal/db"
+	"hashhedge/internal/events"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
 	"hashhedge/internal/models"
+	"hashhedge/internal/tracing"
+)
+
+var (
+	logger = logging.Component(logging.OrderBook)
+	tracer = tracing.Tracer(logging.OrderBook)
 )
 
 type OrderKey struct {
@@ -25,25 +33,58 @@ type OrderKey struct {
 }
 
 type OrderBook struct {
-	orderRepo    *db.OrderRepository
-	tradeRepo    *db.TradeRepository
-	contractRepo *db.ContractRepository
-	contractSvc  *contract.Service
-	db           *db.DB
-	mu           sync.RWMutex
-
-	// In-memory order books for fast matching
-	bids         map[OrderKey][]*models.Order // Buy orders
-	asks         map[OrderKey][]*models.Order // Sell orders
-	eventPublisher  chan<- models.TradeEvent
+	orderRepo    OrderStore
+	tradeRepo    TradeStore
+	contractRepo contract.ContractStore
+	contractSvc  ContractService
+	db           Transactor
+	events       EventPublisher // Bus receiving trades and order changes
+
+	// mu is held shared by commands on a single market, which also lock that
+	// market, and exclusively by commands spanning the whole book. It guards
+	// the configuration and observers below.
+	mu sync.RWMutex
+
+	// In-memory book of each market for fast matching, created on first use
+	marketsMu sync.Mutex
+	markets   map[OrderKey]*market
+
+	// Resting orders with an expiry, removed from the book as they expire
+	expiries *expiryQueue
+
+	// Observers notified of market changes and fills
+	observer     MarketObserver
+	fillObserver FillObserver
+
+	// Ticker updated synchronously with every trade and top of book change
+	ticker TickerRecorder
+
+	// Source of the open interest checked against the risk limits
+	openInterest OpenInterestSource
+
+	// Ledger of users' funds limiting orders and booking trades
+	ledger Ledger
+
+	// Coordinator deciding which instance matches each market, nil when
+	// this instance matches them all
+	coordinator Coordinator
+
+	// Market maker protection of API keys, counting fills from every market
+	protectionsMu sync.Mutex
+	protections   map[uuid.UUID]*protection
+
+	// Matching configuration, including the price rule of each market
+	cfg Config
 }
 
+// NewOrderBook creates a new order book. The database and repositories are
+// taken as interfaces so the book can be tested without PostgreSQL.
 func NewOrderBook(
-	db *db.DB,
-	orderRepo *db.OrderRepository,
-	tradeRepo *db.TradeRepository,
-	contractRepo *db.ContractRepository,
-	contractSvc *contract.Service,
+	db Transactor,
+	orderRepo OrderStore,
+	tradeRepo TradeStore,
+	contractRepo contract.ContractStore,
+	contractSvc ContractService,
 ) *OrderBook {
 	return &OrderBook{
 		db:           db,
@@ -51,21 +92,89 @@ func NewOrderBook(
 		tradeRepo:    tradeRepo,
 		contractRepo: contractRepo,
 		contractSvc:  contractSvc,
-		bids:         make(map[OrderKey][]*models.Order),
-		asks:         make(map[OrderKey][]*models.Order),
-		mu:           sync.RWMutex{},
+		markets:      make(map[OrderKey]*market),
+		expiries:     newExpiryQueue(),
+		protections:  make(map[uuid.UUID]*protection),
+		cfg:          DefaultConfig,
 	}
 }
 
 // PlaceOrder adds a new order to the order book
-func (ob *OrderBook) PlaceOrder(ctx context.Context, order *models.Order) (*models.Order, error) {
+func (ob *OrderBook) PlaceOrder(ctx context.Context, order *models.Order) (_ *models.Order, err error) {
+	started := time.Now()
+
+	if order.Type == "" {
+		order.Type = models.OrderTypeLimit
+	}
+	if order.TimeInForce == "" {
+		order.TimeInForce = models.TimeInForceGTC
+		if order.Type == models.OrderTypeMarket {
+			order.TimeInForce = models.TimeInForceIOC
+		}
+	}
+
+	// Placement, matching and the contracts of any fills share one trace
+	ctx, span := tracer.Start(ctx, "orderbook.PlaceOrder", trace.WithAttributes(
+		attribute.String("order.side", string(order.Side)),
+		attribute.String("order.type", string(order.Type)),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	// Validate order
 	if err := order.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid order: %w", err)
 	}
 
-	ob.mu.Lock()
-	defer ob.mu.Unlock()
+	// Reject block ranges that could not produce a settleable contract
+	tip, err := ob.contractSvc.CurrentBlockHeight(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain tip: %w", err)
+	}
+
+	m, unlock := ob.lockMarket(orderKey(order))
+	if err = ob.claimMarket(ctx, m); err == nil {
+		err = ob.placeOrder(ctx, m, order, tip, started)
+	}
+	unlock()
+	span.SetAttributes(attribute.String("order.id", order.ID.String()))
+
+	// Pull the remaining quotes of any API key the fills pushed past its
+	// protection, including this order if it rests. They may rest in any
+	// market, so this runs once the market is released.
+	ob.pullTriggeredQuotes(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// placeOrder checks, stores and matches an order in its market. The caller
+// must hold the market's lock.
+func (ob *OrderBook) placeOrder(ctx context.Context, m *market, order *models.Order, tip int64, started time.Time) error {
+	if err := ob.checkProtection(order, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	if err := ob.cfg.Risk.CheckOrder(order, tip); err != nil {
+		return err
+	}
+
+	if err := ob.checkOpenInterest(ctx, m.key); err != nil {
+		return err
+	}
+
+	if err := m.assignTargetTimestamp(order, tip, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	// Market orders are rejected outright when the opposite side is empty
+	if order.Type == models.OrderTypeMarket {
+		if err := ob.priceMarketOrder(m, order); err != nil {
+			return err
+		}
+	}
 
 	// Ensure the order ID is set
 	if order.ID == uuid.Nil {
@@ -76,18 +185,37 @@ func (ob *OrderBook) PlaceOrder(ctx context.Context, order *models.Order) (*mode
 	order.Status = models.OrderStatusOpen
 	order.CreatedAt = time.Now().UTC()
 	order.UpdatedAt = order.CreatedAt
+	order.PriorityAt = order.CreatedAt
 	order.RemainingQuantity = order.Quantity
 
+	if err := ob.checkFunds(ctx, order); err != nil {
+		return err
+	}
+
 	// Save the order to the database
-	err := ob.orderRepo.Create(ctx, order)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create order: %w", err)
+	if err := ob.orderRepo.Create(ctx, order); err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
 	}
+	metrics.OrdersPlaced.WithLabelValues(string(order.Side), string(order.Type)).Inc()
 
 	// Try to match the order
-	matched, err := ob.tryMatchOrder(ctx, order)
+	matched, err := ob.tryMatchOrder(ctx, m, order, tip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to match order: %w", err)
+		return fmt.Errorf("failed to match order: %w", err)
+	}
+	metrics.PlaceOrderLatency.WithLabelValues(string(order.Type)).Observe(time.Since(started).Seconds())
+	if matched {
+		metrics.OrdersMatched.WithLabelValues(string(order.Side), string(order.Type)).Inc()
+	}
+
+	// Immediate-or-cancel and fill-or-kill orders cancel whatever did not fill
+	if !order.Rests() && order.RemainingQuantity > 0 {
+		order.Status = models.OrderStatusCancelled
+		if err := ob.orderRepo.Update(ctx, order); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+		ob.publishOrderEvent(events.OrderPlaced, order)
+		return nil
 	}
 
 	// If order was fully matched, update its status
@@ -95,84 +223,17 @@ func (ob *OrderBook) PlaceOrder(ctx context.Context, order *models.Order) (*mode
 		order.Status = models.OrderStatusFilled
 		err = ob.orderRepo.Update(ctx, order)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update order status: %w", err)
+			return fmt.Errorf("failed to update order status: %w", err)
 		}
 	} else if matched {
 		order.Status = models.OrderStatusPartial
 		err = ob.orderRepo.Update(ctx, order)
 		if err != nil {
-			return nil, fmt.Errorf("failed to update order status: %w", err)
+			return fmt.Errorf("failed to update order status: %w", err)
 		}
 	}
 
-	return order, nil
-}
-
-// CancelOrder cancels an open order
-func (ob *OrderBook) CancelOrder(ctx context.Context, orderID uuid.UUID) error {
-	ob.mu.Lock()
-	defer ob.mu.Unlock()
-
-	// Get the order
-	order, err := ob.orderRepo.GetByID(ctx, orderID)
-	if err != nil {
-		return fmt.Errorf("failed to get order: %w", err)
-	}
-
-	// Check if the order can be cancelled
-	if order.Status != models.OrderStatusOpen && order.Status != models.OrderStatusPartial {
-		return fmt.Errorf("order is not in a cancellable state")
-	}
-
-	// Update order status
-	err = ob.orderRepo.UpdateStatus(ctx, orderID, models.OrderStatusCancelled)
-	if err != nil {
-		return fmt.Errorf("failed to cancel order: %w", err)
-	}
-
-	// Also remove from in-memory order book
-	key := OrderKey{
-		ContractType:     order.ContractType,
-		StrikeHashRate:   order.StrikeHashRate,
-		StartBlockHeight: order.StartBlockHeight,
-		EndBlockHeight:   order.EndBlockHeight,
-	}
-
-	if order.Side == models.OrderSideBuy {
-		orders, ok := ob.bids[key]
-		if ok {
-			for i, o := range orders {
-				if o.ID == orderID {
-					// Remove this order
-					if i < len(orders)-1 {
-						orders[i] = orders[len(orders)-1]
-					}
-					ob.bids[key] = orders[:len(orders)-1]
-					if len(ob.bids[key]) == 0 {
-						delete(ob.bids, key)
-					}
-					break
-				}
-			}
-		}
-	} else {
-		orders, ok := ob.asks[key]
-		if ok {
-			for i, o := range orders {
-				if o.ID == orderID {
-					// Remove this order
-					if i < len(orders)-1 {
-						orders[i] = orders[len(orders)-1]
-					}
-					ob.asks[key] = orders[:len(orders)-1]
-					if len(ob.asks[key]) == 0 {
-						delete(ob.asks, key)
-					}
-					break
-				}
-			}
-		}
-	}
+	ob.publishOrderEvent(events.OrderPlaced, order)
 
 	return nil
 }
@@ -187,14 +248,29 @@ func (ob *OrderBook) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*mode
 	return order, nil
 }
 
-// ListUserOrders retrieves all orders for a user
-func (ob *OrderBook) ListUserOrders(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Order, error) {
-	orders, err := ob.orderRepo.ListUserOrders(ctx, userID, limit, offset)
+// ListUserOrders retrieves a page of a user's orders, newest first, after
+// the cursor
+func (ob *OrderBook) ListUserOrders(ctx context.Context, userID uuid.UUID, after models.Cursor, limit int) ([]*models.Order, *models.PageInfo, error) {
+	orders, err := ob.orderRepo.ListUserOrdersPage(ctx, userID, after, limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list user orders: %w", err)
+		return nil, nil, fmt.Errorf("failed to list user orders: %w", err)
 	}
 
-	return orders, nil
+	total, err := ob.orderRepo.CountUserOrders(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count user orders: %w", err)
+	}
+
+	fetched := len(orders)
+	if fetched > limit {
+		orders = orders[:limit]
+	}
+	var last models.Cursor
+	if len(orders) > 0 {
+		last = models.Cursor{Time: orders[len(orders)-1].CreatedAt, ID: orders[len(orders)-1].ID}
+	}
+
+	return orders, models.NewPageInfo(total, fetched, limit, last), nil
 }
 
 // ListOpenOrders retrieves open orders that match the given criteria
@@ -265,120 +341,138 @@ func (ob *OrderBook) GetOrderBook(
 	return orderBook, nil
 }
 
-// Start begins periodic tasks like cancelling expired orders
+// Start begins periodic tasks like cancelling expired orders, on the
+// schedule configured when it is called. Resting orders are also removed
+// from the book at their exact expiry, between sweeps.
 func (ob *OrderBook) Start(ctx context.Context) {
+	ob.mu.RLock()
+	schedule := ob.cfg.Schedule
+	ob.mu.RUnlock()
+
+	go ob.runExpiries(ctx)
+
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
-		defer ticker.Stop()
+		sweep := time.NewTicker(schedule.ExpirySweep)
+		defer sweep.Stop()
+
+		// A nil channel never fires, leaving periodic reloads off
+		var reload <-chan time.Time
+		if schedule.Reload > 0 {
+			ticker := time.NewTicker(schedule.Reload)
+			defer ticker.Stop()
+			reload = ticker.C
+		}
+
+		var reconcile <-chan time.Time
+		if schedule.Reconcile > 0 {
+			ticker := time.NewTicker(schedule.Reconcile)
+			defer ticker.Stop()
+			reconcile = ticker.C
+		}
 
 		// Initial load of open orders
 		if err := ob.loadOpenOrders(ctx); err != nil {
-			log.Error().Err(err).Msg("Failed to load open orders")
+			logger.Error().Err(err).Msg("Failed to load open orders")
 		}
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-ticker.C:
+			case <-sweep.C:
 				// Cancel expired orders
 				count, err := ob.orderRepo.CancelExpiredOrders(ctx)
 				if err != nil {
-					log.Error().Err(err).Msg("Failed to cancel expired orders")
+					logger.Error().Err(err).Msg("Failed to cancel expired orders")
 				} else if count > 0 {
-					log.Info().Int64("count", count).Msg("Cancelled expired orders")
+					logger.Info().Int64("count", count).Msg("Cancelled expired orders")
 					
 					// Reload the order book after cancelling orders
 					if err := ob.loadOpenOrders(ctx); err != nil {
-						log.Error().Err(err).Msg("Failed to reload open orders")
+						logger.Error().Err(err).Msg("Failed to reload open orders")
 					}
 				}
+			case <-reload:
+				if err := ob.loadOpenOrders(ctx); err != nil {
+					logger.Error().Err(err).Msg("Failed to reload open orders")
+				}
+			case <-reconcile:
+				if _, err := ob.Reconcile(ctx); err != nil {
+					logger.Error().Err(err).Msg("Failed to reconcile order book")
+				}
 			}
 		}
 	}()
 }
 
-// SetEventPublisher sets the channel for publishing trade events
-func (ob *OrderBook) SetEventPublisher(eventChan chan<- models.TradeEvent) {
-	ob.eventPublisher = eventChan
+// SetEventBus sets the bus that trades and order changes are published to
+func (ob *OrderBook) SetEventBus(bus EventPublisher) {
+	ob.events = bus
+}
+
+// Reload rebuilds the in-memory order book from the open orders in the database
+func (ob *OrderBook) Reload(ctx context.Context) error {
+	return ob.loadOpenOrders(ctx)
 }
 
-// loadOpenOrders loads all open orders into memory
+// loadOpenOrders loads all open orders into memory. It holds ob.mu
+// exclusively, so the book is rebuilt between commands rather than under
+// any of them.
 func (ob *OrderBook) loadOpenOrders(ctx context.Context) error {
 	ob.mu.Lock()
 	defer ob.mu.Unlock()
 
-	// Clear existing orders
-	ob.bids = make(map[OrderKey][]*models.Order)
-	ob.asks = make(map[OrderKey][]*models.Order)
-
 	// Load open and partial orders
 	openOrders, err := ob.orderRepo.ListAllOpenOrders(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list all open orders: %w", err)
 	}
 
-	// Process each order
-	for _, order := range openOrders {
-		key := OrderKey{
-			ContractType:     order.ContractType,
-			StrikeHashRate:   order.StrikeHashRate,
-			StartBlockHeight: order.StartBlockHeight,
-			EndBlockHeight:   order.EndBlockHeight,
-		}
-
-		if order.Side == models.OrderSideBuy {
-			ob.bids[key] = append(ob.bids[key], order)
-		} else {
-			ob.asks[key] = append(ob.asks[key], order)
+	// Markets in the book before the reload may have lost orders. Their
+	// last trades are kept.
+	changed := make(map[*market]bool)
+	for _, m := range ob.allMarkets() {
+		if len(m.bids) > 0 || len(m.asks) > 0 {
+			changed[m] = true
 		}
+		m.bids, m.asks = nil, nil
 	}
 
-	// Sort orders by price and time priority
-	for key, orders := range ob.bids {
-		sort.SliceStable(orders, func(i, j int) bool {
-			if orders[i].Price == orders[j].Price {
-				return orders[i].CreatedAt.Before(orders[j].CreatedAt)
-			}
-			return orders[i].Price > orders[j].Price // Descending for buys
-		})
-		ob.bids[key] = orders
+	// Process each order
+	for _, order := range openOrders {
+		m := ob.market(orderKey(order))
+		m.add(order)
+		ob.expiries.schedule(m.key, order)
+		changed[m] = true
 	}
 
-	for key, orders := range ob.asks {
-		sort.SliceStable(orders, func(i, j int) bool {
-			if orders[i].Price == orders[j].Price {
-				return orders[i].CreatedAt.Before(orders[j].CreatedAt)
-			}
-			return orders[i].Price < orders[j].Price // Ascending for sells
-		})
-		ob.asks[key] = orders
+	// Sort orders by price and time priority
+	for m := range changed {
+		m.sort()
+		ob.notifyMarketUpdate(m)
 	}
 
 	return nil
 }
 
-// matchBuyOrder matches a buy order against the order book
-func (ob *OrderBook) matchBuyOrder(ctx context.Context, buyOrder *models.Order) (bool, error) {
-	key := OrderKey{
-		ContractType:     buyOrder.ContractType,
-		StrikeHashRate:   buyOrder.StrikeHashRate,
-		StartBlockHeight: buyOrder.StartBlockHeight,
-		EndBlockHeight:   buyOrder.EndBlockHeight,
-	}
-
+// matchBuyOrder matches a buy order against its market. The caller must hold
+// the market's lock.
+func (ob *OrderBook) matchBuyOrder(ctx context.Context, m *market, buyOrder *models.Order, tip int64) (bool, error) {
 	// Find matching sell orders
-	sellOrders, ok := ob.asks[key]
-	if !ok || len(sellOrders) == 0 {
+	sellOrders := m.asks
+	if len(sellOrders) == 0 {
 		return false, nil // No matching orders found
 	}
 
 	// Sort sells by price (ascending) and time priority
-	sort.SliceStable(sellOrders, func(i, j int) bool {
-		if sellOrders[i].Price == sellOrders[j].Price {
-			return sellOrders[i].CreatedAt.Before(sellOrders[j].CreatedAt)
-		}
-		return sellOrders[i].Price < sellOrders[j].Price
+	sortByPriority(sellOrders, func(a, b int64) bool { return a < b })
+
+	// Decide how much of each resting order is filled under the market's price rule
+	rule := ob.cfg.PriceRuleFor(m.key)
+	fills := fillQuantities(rule, sellOrders, buyOrder.RemainingQuantity, func(o *models.Order) bool {
+		return o.Price <= buyOrder.Price
+	}, func(o *models.Order) bool {
+		return sameTerms(o, buyOrder)
 	})
 
 	matched := false
@@ -387,6 +481,11 @@ func (ob *OrderBook) matchBuyOrder(ctx context.Context, buyOrder *models.Order)
 
 	// Transaction for atomic execution of all matches
 	err := ob.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		// A fill-or-kill order executes nothing unless it fills completely
+		if !fillsCompletely(buyOrder, fills) {
+			return nil
+		}
+
 		// Try to match with existing sell orders
 		for i, sellOrder := range sellOrders {
 			// Break if buy order is fully filled
@@ -406,7 +505,7 @@ func (ob *OrderBook) matchBuyOrder(ctx context.Context, buyOrder *models.Order)
 			}
 
 			// Determine match quantity
-			matchQty := min(buyOrder.RemainingQuantity, sellOrder.RemainingQuantity)
+			matchQty := min(buyOrder.RemainingQuantity, fills[i])
 
 			if matchQty <= 0 {
 				continue
@@ -415,27 +514,18 @@ func (ob *OrderBook) matchBuyOrder(ctx context.Context, buyOrder *models.Order)
 			matched = true
 
 			// Execute the trade
-			err := ob.executeTrade(ctx, tx, buyOrder, sellOrder, matchQty)
+			price := tradePrice(rule, buyOrder, sellOrder)
+			err := ob.executeTrade(ctx, tx, m, buyOrder, sellOrder, matchQty, rule, price, tip)
 			if err != nil {
 				return fmt.Errorf("failed to execute trade: %w", err)
 			}
 
-			// Update remaining quantities
-			buyOrder.RemainingQuantity -= matchQty
-			sellOrder.RemainingQuantity -= matchQty
-
-			// Update order statuses
-			if buyOrder.RemainingQuantity == 0 {
-				buyOrder.Status = models.OrderStatusFilled
-			} else {
-				buyOrder.Status = models.OrderStatusPartial
-			}
-
+			// executeTrade has already reduced both remaining quantities and
+			// updated the statuses, so a second decrement here would end the
+			// sweep early and leave a fill-or-kill order short
 			if sellOrder.RemainingQuantity == 0 {
-				sellOrder.Status = models.OrderStatusFilled
 				ordersToRemove = append(ordersToRemove, i)
 			} else {
-				sellOrder.Status = models.OrderStatusPartial
 				ordersToUpdate = append(ordersToUpdate, sellOrder)
 			}
 		}
@@ -471,37 +561,30 @@ func (ob *OrderBook) matchBuyOrder(ctx context.Context, buyOrder *models.Order)
 		sellOrders = sellOrders[:len(sellOrders)-1]
 	}
 
-	// Update the asks map with the modified orders
-	if len(sellOrders) > 0 {
-		ob.asks[key] = sellOrders
-	} else {
-		delete(ob.asks, key)
-	}
+	// Update the asks with the modified orders
+	m.asks = sellOrders
 
 	return matched, nil
 }
 
-// matchSellOrder matches a sell order against the order book
-func (ob *OrderBook) matchSellOrder(ctx context.Context, sellOrder *models.Order) (bool, error) {
-	key := OrderKey{
-		ContractType:     sellOrder.ContractType,
-		StrikeHashRate:   sellOrder.StrikeHashRate,
-		StartBlockHeight: sellOrder.StartBlockHeight,
-		EndBlockHeight:   sellOrder.EndBlockHeight,
-	}
-
+// matchSellOrder matches a sell order against its market. The caller must
+// hold the market's lock.
+func (ob *OrderBook) matchSellOrder(ctx context.Context, m *market, sellOrder *models.Order, tip int64) (bool, error) {
 	// Find matching buy orders
-	buyOrders, ok := ob.bids[key]
-	if !ok || len(buyOrders) == 0 {
+	buyOrders := m.bids
+	if len(buyOrders) == 0 {
 		return false, nil // No matching orders found
 	}
 
 	// Sort buys by price (descending) and time priority
-	sort.SliceStable(buyOrders, func(i, j int) bool {
-		if buyOrders[i].Price == buyOrders[j].Price {
-			return buyOrders[i].CreatedAt.Before(buyOrders[j].CreatedAt)
-		}
-		return buyOrders[i].Price > buyOrders[j].Price
+	sortByPriority(buyOrders, func(a, b int64) bool { return a > b })
+
+	// Decide how much of each resting order is filled under the market's price rule
+	rule := ob.cfg.PriceRuleFor(m.key)
+	fills := fillQuantities(rule, buyOrders, sellOrder.RemainingQuantity, func(o *models.Order) bool {
+		return o.Price >= sellOrder.Price
+	}, func(o *models.Order) bool {
+		return sameTerms(o, sellOrder)
 	})
 
 	matched := false
@@ -510,6 +593,11 @@ func (ob *OrderBook) matchSellOrder(ctx context.Context, sellOrder *models.Order
 
 	// Transaction for atomic execution of all matches
 	err := ob.db.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		// A fill-or-kill order executes nothing unless it fills completely
+		if !fillsCompletely(sellOrder, fills) {
+			return nil
+		}
+
 		// Try to match with existing buy orders
 		for i, buyOrder := range buyOrders {
 			// Break if sell order is fully filled
@@ -529,7 +617,7 @@ func (ob *OrderBook) matchSellOrder(ctx context.Context, sellOrder *models.Order
 			}
 
 			// Determine match quantity
-			matchQty := min(sellOrder.RemainingQuantity, buyOrder.RemainingQuantity)
+			matchQty := min(sellOrder.RemainingQuantity, fills[i])
 
 			if matchQty <= 0 {
 				continue
@@ -538,27 +626,17 @@ func (ob *OrderBook) matchSellOrder(ctx context.Context, sellOrder *models.Order
 			matched = true
 
 			// Execute the trade
-			err := ob.executeTrade(ctx, tx, buyOrder, sellOrder, matchQty)
+			price := tradePrice(rule, sellOrder, buyOrder)
+			err := ob.executeTrade(ctx, tx, m, buyOrder, sellOrder, matchQty, rule, price, tip)
 			if err != nil {
 				return fmt.Errorf("failed to execute trade: %w", err)
 			}
 
-			// Update remaining quantities
-			sellOrder.RemainingQuantity -= matchQty
-			buyOrder.RemainingQuantity -= matchQty
-
-			// Update order statuses
-			if sellOrder.RemainingQuantity == 0 {
-				sellOrder.Status = models.OrderStatusFilled
-			} else {
-				sellOrder.Status = models.OrderStatusPartial
-			}
-
+			// executeTrade has already reduced both remaining quantities and
+			// updated the statuses
 			if buyOrder.RemainingQuantity == 0 {
-				buyOrder.Status = models.OrderStatusFilled
 				ordersToRemove = append(ordersToRemove, i)
 			} else {
-				buyOrder.Status = models.OrderStatusPartial
 				ordersToUpdate = append(ordersToUpdate, buyOrder)
 			}
 		}
@@ -594,24 +672,33 @@ func (ob *OrderBook) matchSellOrder(ctx context.Context, sellOrder *models.Order
 		buyOrders = buyOrders[:len(buyOrders)-1]
 	}
 
-	// Update the bids map with the modified orders
-	if len(buyOrders) > 0 {
-		ob.bids[key] = buyOrders
-	} else {
-		delete(ob.bids, key)
-	}
+	// Update the bids with the modified orders
+	m.bids = buyOrders
 
 	return matched, nil
 }
 
-// executeTrade handles the execution of a trade between a buy and sell order with extensive error handling
+// executeTrade handles the execution of a trade between a buy and sell order
+// of a market with extensive error handling. The caller must hold the
+// market's lock.
 func (ob *OrderBook) executeTrade(
 	ctx context.Context,
 	tx *sqlx.Tx,
+	m *market,
 	buyOrder *models.Order,
 	sellOrder *models.Order,
 	quantity int,
-) error {
+	rule models.PriceRule,
+	price int64,
+	tip int64,
+) (err error) {
+	ctx, span := tracer.Start(ctx, "orderbook.executeTrade", trace.WithAttributes(
+		attribute.String("order.buy_id", buyOrder.ID.String()),
+		attribute.String("order.sell_id", sellOrder.ID.String()),
+		attribute.Int("trade.quantity", quantity),
+	))
+	defer func() { tracing.End(span, err) }()
+
 	// Validate the trade parameters
 	if quantity <= 0 {
 		return fmt.Errorf("invalid trade quantity: %d", quantity)
@@ -635,18 +722,21 @@ func (ob *OrderBook) executeTrade(
 		return fmt.Errorf("order parameters mismatch between buy and sell orders")
 	}
 
-	// Use mid price for the trade (average of buy and sell prices)
-	// Ensure we don't overflow by using int64 arithmetic
-	midPrice := (int64(buyOrder.Price) + int64(sellOrder.Price)) / 2
+	// Re-check the terms against the tip before committing both sides to a contract
+	if err := ob.cfg.Risk.CheckMatch(m.key, tip); err != nil {
+		return err
+	}
+
+	if err := ob.checkOpenInterest(ctx, m.key); err != nil {
+		return err
+	}
 
 	// Create trade timestamp
 	tradeTime := time.Now().UTC()
 
-	// Calculate target timestamp based on start height and estimated time to end height
-	// Use average Bitcoin block time of 10 minutes
-	blocksToTarget := buyOrder.EndBlockHeight - buyOrder.StartBlockHeight
-	estimatedTimeToTarget := time.Duration(blocksToTarget) * 10 * time.Minute
-	targetTimestamp := tradeTime.Add(estimatedTimeToTarget)
+	// Both orders quoted the same target timestamp, so the contract carries
+	// exactly the terms each party saw before the trade
+	targetTimestamp := contractTargetTimestamp(buyOrder, sellOrder, tradeTime)
 
 	// Create a contract for this trade
 	contract, err := ob.contractSvc.CreateContract(
@@ -656,8 +746,9 @@ func (ob *OrderBook) executeTrade(
 		buyOrder.StartBlockHeight,
 		buyOrder.EndBlockHeight,
 		targetTimestamp,
-		midPrice,
+		price,
 		0, // No premium in simple model
+		models.DefaultNotional,
 		buyOrder.PubKey,
 		sellOrder.PubKey,
 	)
@@ -665,14 +756,21 @@ func (ob *OrderBook) executeTrade(
 		return fmt.Errorf("failed to create contract for trade: %w", err)
 	}
 
+	if err := ob.contractSvc.RecordPartyKeys(ctx, contract, buyOrder.KeyID, sellOrder.KeyID); err != nil {
+		return fmt.Errorf("failed to record contract keys: %w", err)
+	}
+
+	span.SetAttributes(attribute.String("contract.id", contract.ID.String()))
+
 	// Create a trade record
 	trade := &models.Trade{
 		ID:          uuid.New(),
 		BuyOrderID:  buyOrder.ID,
 		SellOrderID: sellOrder.ID,
 		ContractID:  contract.ID,
-		Price:       midPrice,
+		Price:       price,
 		Quantity:    quantity,
+		PriceRule:   rule,
 		ExecutedAt:  tradeTime,
 	}
 
@@ -686,6 +784,12 @@ func (ob *OrderBook) executeTrade(
 		return fmt.Errorf("failed to create trade record: %w", err)
 	}
 
+	if ob.ledger != nil {
+		if err := ob.ledger.RecordTrade(ctx, tx, buyOrder, sellOrder, contract); err != nil {
+			return fmt.Errorf("failed to book trade: %w", err)
+		}
+	}
+
 	// Update order quantities and status in database
 	// We use custom SQL to ensure this is atomic
 	if err := ob.orderRepo.DecrementRemainingQuantity(ctx, buyOrder.ID, quantity); err != nil {
@@ -712,25 +816,42 @@ func (ob *OrderBook) executeTrade(
 		sellOrder.Status = models.OrderStatusPartial
 	}
 
+	m.lastTrade = &price
+	if ob.ticker != nil {
+		ob.ticker.RecordTrade(m.key, price, quantity, tradeTime)
+	}
+
 	// Log the trade
-	log.Info().
+	logger.Info().
+		Ctx(ctx).
 		Str("trade_id", trade.ID.String()).
 		Str("contract_id", contract.ID.String()).
 		Str("buy_order_id", buyOrder.ID.String()).
 		Str("sell_order_id", sellOrder.ID.String()).
-		Int64("price", midPrice).
+		Int64("price", price).
+		Str("price_rule", string(rule)).
 		Int("quantity", quantity).
 		Msg("Trade executed")
 
+	metrics.Trades.Inc()
+
 	// Send trade execution event for websocket clients
 	ob.publishTradeEvent(trade, contract)
+	ob.notifyFill(trade, contract, buyOrder, sellOrder)
+
+	ob.recordProtectedFill(buyOrder, tradeTime)
+	ob.recordProtectedFill(sellOrder, tradeTime)
 
 	return nil
 }
 
-// publishTradeEvent publishes a trade event to any subscribers
+// publishTradeEvent publishes an executed trade to the event bus
 func (ob *OrderBook) publishTradeEvent(trade *models.Trade, contract *models.Contract) {
-	event := models.TradeEvent{
+	if ob.events == nil {
+		return
+	}
+
+	ob.events.Publish(events.TopicTrades, events.TradeEvent{
 		ID:             trade.ID,
 		ContractID:     contract.ID,
 		ContractType:   contract.ContractType,
@@ -738,55 +859,48 @@ func (ob *OrderBook) publishTradeEvent(trade *models.Trade, contract *models.Con
 		Price:          trade.Price,
 		Quantity:       trade.Quantity,
 		ExecutedAt:     trade.ExecutedAt,
-	}
-
-	if ob.eventPublisher != nil {
-		// Non-blocking publish - if the channel is full, we'll drop the event
-		select {
-		case ob.eventPublisher <- event:
-			// Event published successfully
-		default:
-			// Channel full, log and continue
-			log.Warn().
-				Str("trade_id", trade.ID.String()).
-				Msg("Failed to publish trade event - channel full")
-		}
-	}
+	})
 }
 
-// tryMatchOrder attempts to match a new order with existing orders
-func (ob *OrderBook) tryMatchOrder(ctx context.Context, order *models.Order) (bool, error) {
-	// Add the order to the appropriate in-memory book first
-	key := OrderKey{
-		ContractType:     order.ContractType,
-		StrikeHashRate:   order.StrikeHashRate,
-		StartBlockHeight: order.StartBlockHeight,
-		EndBlockHeight:   order.EndBlockHeight,
+// publishOrderEvent publishes a copy of an order as it is after action, so
+// subscribers never see later changes made by the matcher
+func (ob *OrderBook) publishOrderEvent(action events.OrderAction, order *models.Order) {
+	if ob.events == nil {
+		return
 	}
 
-	// Add the order to the appropriate side of the order book
-	if order.Side == models.OrderSideBuy {
-		// Add to bids
-		ob.bids[key] = append(ob.bids[key], order)
-	} else {
-		// Add to asks
-		ob.asks[key] = append(ob.asks[key], order)
-	}
+	ob.events.Publish(events.TopicOrders, events.OrderEvent{Action: action, Order: *order})
+}
+
+// tryMatchOrder attempts to match a new order with the resting orders of its
+// market. The caller must hold the market's lock.
+func (ob *OrderBook) tryMatchOrder(ctx context.Context, m *market, order *models.Order, tip int64) (bool, error) {
+	// Add the order to the appropriate side of the market first
+	m.add(order)
 
 	// Try to match the order based on its side
 	var matched bool
 	var err error
 
 	if order.Side == models.OrderSideBuy {
-		matched, err = ob.matchBuyOrder(ctx, order)
+		matched, err = ob.matchBuyOrder(ctx, m, order, tip)
 	} else {
-		matched, err = ob.matchSellOrder(ctx, order)
+		matched, err = ob.matchSellOrder(ctx, m, order, tip)
 	}
 
 	if err != nil {
 		return false, err
 	}
 
+	// Only good-til-cancelled orders rest, so any other remainder leaves the book
+	if !order.Rests() {
+		m.remove(order.Side, order.ID)
+	} else if order.RemainingQuantity > 0 {
+		ob.expiries.schedule(m.key, order)
+	}
+
+	ob.notifyMarketUpdate(m)
+
 	return matched, nil
 }
 
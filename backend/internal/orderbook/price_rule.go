@@ -0,0 +1,197 @@
+// internal/orderbook/price_rule.go
+package orderbook
+
+import (
+	"fmt"
+
+	"hashhedge/internal/models"
+)
+
+// Config holds the matching engine configuration
+type Config struct {
+	// PriceRule applies to every market without a matching override
+	PriceRule models.PriceRule `yaml:"price_rule"`
+	// Markets override the price rule of specific markets. The first
+	// override matching a market wins.
+	Markets []MarketConfig `yaml:"markets"`
+	// Risk bounds the block range of orders
+	Risk RiskLimits `yaml:"risk"`
+	// Schedule sets the cadence of the expiry sweep, book reloads and
+	// reconciliation
+	Schedule Schedule `yaml:"schedule"`
+	// MaxSlippageBps is how far past the best opposite price, in basis
+	// points, a market order may sweep
+	MaxSlippageBps int `yaml:"max_slippage_bps"`
+}
+
+// MarketConfig overrides the price rule of the markets it matches. Fields
+// left empty or zero match any market.
+type MarketConfig struct {
+	ContractType     models.ContractType `yaml:"contract_type"`
+	StrikeHashRate   float64             `yaml:"strike_hash_rate"`
+	StartBlockHeight int64               `yaml:"start_block_height"`
+	EndBlockHeight   int64               `yaml:"end_block_height"`
+	PriceRule        models.PriceRule    `yaml:"price_rule"`
+}
+
+// DefaultConfig trades at the midpoint, matching the original engine, and
+// lets market orders sweep up to 5% past the best price
+var DefaultConfig = Config{
+	PriceRule:      models.PriceRuleMid,
+	Risk:           DefaultRiskLimits,
+	Schedule:       DefaultSchedule,
+	MaxSlippageBps: 500,
+}
+
+// Validate checks that every configured price rule is known and the risk
+// limits, schedule and slippage are consistent
+func (c Config) Validate() error {
+	if !c.PriceRule.IsValid() {
+		return fmt.Errorf("invalid order book price rule: %q", c.PriceRule)
+	}
+
+	if c.MaxSlippageBps < 0 || c.MaxSlippageBps > bpsDenominator {
+		return fmt.Errorf("order book max slippage must be between 0 and %d basis points", bpsDenominator)
+	}
+
+	for i, m := range c.Markets {
+		if !m.PriceRule.IsValid() {
+			return fmt.Errorf("invalid price rule %q for order book market %d", m.PriceRule, i)
+		}
+		if m.ContractType != "" && m.ContractType != models.ContractTypeCall && m.ContractType != models.ContractTypePut {
+			return fmt.Errorf("invalid contract type %q for order book market %d", m.ContractType, i)
+		}
+	}
+
+	if err := c.Risk.Validate(); err != nil {
+		return err
+	}
+
+	return c.Schedule.Validate()
+}
+
+// PriceRuleFor returns the price rule of a market
+func (c Config) PriceRuleFor(key OrderKey) models.PriceRule {
+	for _, m := range c.Markets {
+		if m.matches(key) {
+			return m.PriceRule
+		}
+	}
+	return c.PriceRule
+}
+
+func (m MarketConfig) matches(key OrderKey) bool {
+	return (m.ContractType == "" || m.ContractType == key.ContractType) &&
+		(m.StrikeHashRate == 0 || m.StrikeHashRate == key.StrikeHashRate) &&
+		(m.StartBlockHeight == 0 || m.StartBlockHeight == key.StartBlockHeight) &&
+		(m.EndBlockHeight == 0 || m.EndBlockHeight == key.EndBlockHeight)
+}
+
+// SetConfig replaces the matching engine configuration
+func (ob *OrderBook) SetConfig(cfg Config) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.cfg = cfg
+}
+
+// tradePrice returns the price an incoming order trades at against a
+// resting order
+func tradePrice(rule models.PriceRule, incoming, resting *models.Order) int64 {
+	// A market order's price is only its slippage limit, so it takes the resting price
+	if rule == models.PriceRuleMid && incoming.Type != models.OrderTypeMarket {
+		return (incoming.Price + resting.Price) / 2
+	}
+	return resting.Price
+}
+
+// fillQuantities returns how much of each resting order an incoming order of
+// the given quantity fills. The resting orders must be sorted best price
+// first and by time within a price; crosses reports whether a resting order's
+// price is acceptable to the incoming order and eligible whether its other
+// terms are.
+func fillQuantities(
+	rule models.PriceRule,
+	resting []*models.Order,
+	quantity int,
+	crosses func(*models.Order) bool,
+	eligible func(*models.Order) bool,
+) []int {
+	fills := make([]int, len(resting))
+	live := func(o *models.Order) bool {
+		return isLive(o) && eligible(o)
+	}
+
+	for i := 0; i < len(resting) && quantity > 0; {
+		if !crosses(resting[i]) {
+			break
+		}
+
+		// A price level is the run of resting orders sharing a price
+		end := i + 1
+		for end < len(resting) && resting[end].Price == resting[i].Price {
+			end++
+		}
+
+		if rule == models.PriceRuleProRata {
+			quantity -= allocateProRata(resting[i:end], quantity, fills[i:end], live)
+		} else {
+			for k := i; k < end && quantity > 0; k++ {
+				if live(resting[k]) {
+					fills[k] = min(quantity, resting[k].RemainingQuantity)
+					quantity -= fills[k]
+				}
+			}
+		}
+
+		i = end
+	}
+
+	return fills
+}
+
+// allocateProRata splits quantity across the live orders of a price level in
+// proportion to their remaining quantity. Rounding leftovers go one contract
+// each to the earliest orders. It returns the quantity allocated.
+func allocateProRata(level []*models.Order, quantity int, fills []int, live func(*models.Order) bool) int {
+	total := 0
+	for _, o := range level {
+		if live(o) {
+			total += o.RemainingQuantity
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	// The whole level is taken
+	if quantity >= total {
+		for k, o := range level {
+			if live(o) {
+				fills[k] = o.RemainingQuantity
+			}
+		}
+		return total
+	}
+
+	allocated := 0
+	for k, o := range level {
+		if live(o) {
+			fills[k] = int(int64(quantity) * int64(o.RemainingQuantity) / int64(total))
+			allocated += fills[k]
+		}
+	}
+
+	// Each share was rounded down by less than one contract, so there are
+	// fewer leftovers than live orders and no share is a full fill
+	for k, o := range level {
+		if allocated == quantity {
+			break
+		}
+		if live(o) && fills[k] < o.RemainingQuantity {
+			fills[k]++
+			allocated++
+		}
+	}
+
+	return allocated
+}
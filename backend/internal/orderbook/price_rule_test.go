@@ -0,0 +1,105 @@
+// internal/orderbook/price_rule_test.go
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func restingOrder(price int64, remaining int) *models.Order {
+	return &models.Order{Price: price, RemainingQuantity: remaining, Status: models.OrderStatusOpen}
+}
+
+func TestFillQuantities(t *testing.T) {
+	// Asks sorted best price first, by time within a price
+	asks := func() []*models.Order {
+		return []*models.Order{
+			restingOrder(100, 3),
+			restingOrder(100, 6),
+			restingOrder(100, 1),
+			restingOrder(105, 4),
+			restingOrder(110, 5),
+		}
+	}
+	upTo := func(limit int64) func(*models.Order) bool {
+		return func(o *models.Order) bool { return o.Price <= limit }
+	}
+	all := func(*models.Order) bool { return true }
+
+	tests := []struct {
+		name     string
+		rule     models.PriceRule
+		quantity int
+		limit    int64
+		want     []int
+	}{
+		{"price-time fills the earliest order first", models.PriceRuleResting, 5, 110, []int{3, 2, 0, 0, 0}},
+		{"mid uses price-time allocation", models.PriceRuleMid, 5, 110, []int{3, 2, 0, 0, 0}},
+		{"price-time sweeps levels", models.PriceRuleResting, 12, 110, []int{3, 6, 1, 2, 0}},
+		{"pro-rata splits a level by size", models.PriceRuleProRata, 5, 110, []int{2, 3, 0, 0, 0}},
+		{"pro-rata leftovers go to the earliest orders", models.PriceRuleProRata, 7, 110, []int{3, 4, 0, 0, 0}},
+		{"pro-rata takes whole levels before the next", models.PriceRuleProRata, 12, 110, []int{3, 6, 1, 2, 0}},
+		{"limit stops at the crossing price", models.PriceRuleProRata, 20, 105, []int{3, 6, 1, 4, 0}},
+		{"nothing crosses", models.PriceRuleResting, 5, 99, []int{0, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, fillQuantities(tt.rule, asks(), tt.quantity, upTo(tt.limit), all))
+		})
+	}
+
+	t.Run("cancelled orders are skipped", func(t *testing.T) {
+		orders := asks()
+		orders[0].Status = models.OrderStatusCancelled
+		assert.Equal(t, []int{0, 5, 0, 0, 0}, fillQuantities(models.PriceRuleProRata, orders, 5, upTo(110), all))
+	})
+
+	t.Run("ineligible orders are skipped", func(t *testing.T) {
+		orders := asks()
+		notSecond := func(o *models.Order) bool { return o != orders[1] }
+		assert.Equal(t, []int{3, 0, 1, 1, 0}, fillQuantities(models.PriceRuleResting, orders, 5, upTo(110), notSecond))
+	})
+}
+
+func TestTradePrice(t *testing.T) {
+	incoming := restingOrder(110, 1)
+	resting := restingOrder(100, 1)
+
+	assert.Equal(t, int64(100), tradePrice(models.PriceRuleResting, incoming, resting))
+	assert.Equal(t, int64(105), tradePrice(models.PriceRuleMid, incoming, resting))
+	assert.Equal(t, int64(100), tradePrice(models.PriceRuleProRata, incoming, resting))
+
+	// A market order's price is its slippage limit, not a quote to split
+	incoming.Type = models.OrderTypeMarket
+	assert.Equal(t, int64(100), tradePrice(models.PriceRuleMid, incoming, resting))
+}
+
+func TestPriceRuleFor(t *testing.T) {
+	cfg := Config{
+		PriceRule: models.PriceRuleResting,
+		Markets: []MarketConfig{
+			{ContractType: models.ContractTypeCall, StrikeHashRate: 500, EndBlockHeight: 900000, PriceRule: models.PriceRuleProRata},
+			{StrikeHashRate: 500, PriceRule: models.PriceRuleMid},
+		},
+		Risk:           DefaultRiskLimits,
+		Schedule:       DefaultSchedule,
+		MaxSlippageBps: 100,
+	}
+	assert.NoError(t, cfg.Validate())
+
+	key := OrderKey{ContractType: models.ContractTypeCall, StrikeHashRate: 500, StartBlockHeight: 800000, EndBlockHeight: 900000}
+	assert.Equal(t, models.PriceRuleProRata, cfg.PriceRuleFor(key))
+
+	key.ContractType = models.ContractTypePut
+	assert.Equal(t, models.PriceRuleMid, cfg.PriceRuleFor(key))
+
+	key.StrikeHashRate = 600
+	assert.Equal(t, models.PriceRuleResting, cfg.PriceRuleFor(key))
+
+	cfg.Markets[1].PriceRule = "best"
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,280 @@
+// internal/orderbook/protection.go
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// ProtectionState is the live state of an API key's market maker protection
+type ProtectionState struct {
+	Frozen bool `json:"frozen"`
+	// FrozenUntil is when the freeze ends, or nil while frozen until reset
+	FrozenUntil *time.Time `json:"frozen_until,omitempty"`
+	// RecentFills is the number of fills counted in the current window
+	RecentFills int `json:"recent_fills"`
+}
+
+// protection counts the fills of an API key's orders over its rolling window
+type protection struct {
+	settings    models.MarketMakerProtection
+	fills       []time.Time
+	frozen      bool
+	frozenUntil time.Time
+	// pending is set when the protection triggers and cleared once the
+	// key's orders have been pulled
+	pending bool
+}
+
+// recordFill counts a fill at now, reporting whether it triggered the protection
+func (p *protection) recordFill(now time.Time) bool {
+	if p.isFrozen(now) {
+		return false
+	}
+
+	p.prune(now)
+	p.fills = append(p.fills, now)
+	if len(p.fills) < p.settings.MaxFills {
+		return false
+	}
+
+	p.frozen = true
+	p.pending = true
+	p.fills = nil
+	if freeze := p.settings.Freeze(); freeze > 0 {
+		p.frozenUntil = now.Add(freeze)
+	}
+	return true
+}
+
+// prune drops the fills that have left the window ending at now
+func (p *protection) prune(now time.Time) {
+	cutoff := now.Add(-p.settings.Window())
+	i := 0
+	for i < len(p.fills) && !p.fills[i].After(cutoff) {
+		i++
+	}
+	p.fills = p.fills[i:]
+}
+
+// isFrozen reports whether the key is frozen at now, thawing it once a timed
+// freeze has run out
+func (p *protection) isFrozen(now time.Time) bool {
+	if p.frozen && !p.frozenUntil.IsZero() && !now.Before(p.frozenUntil) {
+		p.reset()
+	}
+	return p.frozen
+}
+
+// reset unfreezes the key and clears its fill count
+func (p *protection) reset() {
+	p.frozen = false
+	p.frozenUntil = time.Time{}
+	p.pending = false
+	p.fills = nil
+}
+
+// state reports the protection as seen at now
+func (p *protection) state(now time.Time) ProtectionState {
+	state := ProtectionState{Frozen: p.isFrozen(now)}
+	if state.Frozen && !p.frozenUntil.IsZero() {
+		until := p.frozenUntil
+		state.FrozenUntil = &until
+	}
+	p.prune(now)
+	state.RecentFills = len(p.fills)
+	return state
+}
+
+// SetProtection enables or updates the market maker protection of an API
+// key. A key that is already frozen stays frozen.
+func (ob *OrderBook) SetProtection(settings *models.MarketMakerProtection) {
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+
+	if p, ok := ob.protections[settings.APIKeyID]; ok {
+		p.settings = *settings
+		return
+	}
+	ob.protections[settings.APIKeyID] = &protection{settings: *settings}
+}
+
+// RemoveProtection disables the market maker protection of an API key,
+// unfreezing it
+func (ob *OrderBook) RemoveProtection(apiKeyID uuid.UUID) {
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+	delete(ob.protections, apiKeyID)
+}
+
+// ResetProtection unfreezes an API key and clears its fill count
+func (ob *OrderBook) ResetProtection(apiKeyID uuid.UUID) {
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+
+	if p, ok := ob.protections[apiKeyID]; ok {
+		p.reset()
+	}
+}
+
+// Protection reports the live state of an API key's protection
+func (ob *OrderBook) Protection(apiKeyID uuid.UUID) (ProtectionState, bool) {
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+
+	p, ok := ob.protections[apiKeyID]
+	if !ok {
+		return ProtectionState{}, false
+	}
+	return p.state(time.Now().UTC()), true
+}
+
+// checkProtection rejects orders from an API key whose protection has
+// triggered
+func (ob *OrderBook) checkProtection(order *models.Order, now time.Time) error {
+	if order.APIKeyID == nil {
+		return nil
+	}
+
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+
+	p, ok := ob.protections[*order.APIKeyID]
+	if ok && p.isFrozen(now) {
+		return fmt.Errorf("%w: market maker protection of the API key has been triggered", ErrOrderRejected)
+	}
+	return nil
+}
+
+// recordProtectedFill counts a fill of an order against the protection of
+// its API key
+func (ob *OrderBook) recordProtectedFill(order *models.Order, now time.Time) {
+	if order.APIKeyID == nil {
+		return
+	}
+
+	ob.protectionsMu.Lock()
+	defer ob.protectionsMu.Unlock()
+
+	p, ok := ob.protections[*order.APIKeyID]
+	if ok && p.recordFill(now) {
+		logger.Warn().
+			Str("api_key_id", order.APIKeyID.String()).
+			Int("max_fills", p.settings.MaxFills).
+			Int("window_seconds", p.settings.WindowSeconds).
+			Msg("Market maker protection triggered")
+	}
+}
+
+// pullTriggeredQuotes cancels the resting orders of every API key whose
+// protection triggered during the last match. Failures are logged, since the
+// order that caused the fills has already been placed. A key's quotes may
+// rest in any market, so they are pulled holding ob.mu exclusively; the
+// caller must not hold ob.mu or a market's lock.
+func (ob *OrderBook) pullTriggeredQuotes(ctx context.Context) {
+	ob.protectionsMu.Lock()
+	var pending []uuid.UUID
+	for apiKeyID, p := range ob.protections {
+		if p.pending {
+			pending = append(pending, apiKeyID)
+		}
+	}
+	ob.protectionsMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	for _, keyID := range pending {
+		keyID := keyID
+		cancelled, err := ob.cancelResting(ctx, func(o *models.Order) bool {
+			return o.APIKeyID != nil && *o.APIKeyID == keyID
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("api_key_id", keyID.String()).Msg("Failed to pull quotes of protected API key")
+			continue
+		}
+
+		ob.protectionsMu.Lock()
+		if p, ok := ob.protections[keyID]; ok {
+			p.pending = false
+		}
+		ob.protectionsMu.Unlock()
+
+		logger.Info().Str("api_key_id", keyID.String()).Int("cancelled", cancelled).Msg("Pulled quotes of protected API key")
+	}
+}
+
+// OnSessionClosed cancels the cancel-on-disconnect orders of an API key once
+// its last websocket session has closed
+func (ob *OrderBook) OnSessionClosed(ctx context.Context, apiKeyID uuid.UUID) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	cancelled, err := ob.cancelResting(ctx, func(o *models.Order) bool {
+		return o.CancelOnDisconnect && o.APIKeyID != nil && *o.APIKeyID == apiKeyID
+	})
+	if err != nil {
+		logger.Error().Err(err).Str("api_key_id", apiKeyID.String()).Msg("Failed to cancel orders on disconnect")
+		return
+	}
+
+	if cancelled > 0 {
+		logger.Info().Str("api_key_id", apiKeyID.String()).Int("cancelled", cancelled).Msg("Cancelled orders on disconnect")
+	}
+}
+
+// cancelResting cancels every resting order matching match and returns how
+// many were cancelled. The caller must hold ob.mu exclusively, which keeps
+// every market still without locking each.
+func (ob *OrderBook) cancelResting(ctx context.Context, match func(*models.Order) bool) (int, error) {
+	type restingOrder struct {
+		market *market
+		order  *models.Order
+	}
+
+	var orders []restingOrder
+	for _, m := range ob.allMarkets() {
+		for _, resting := range [][]*models.Order{m.bids, m.asks} {
+			for _, o := range resting {
+				if match(o) {
+					orders = append(orders, restingOrder{market: m, order: o})
+				}
+			}
+		}
+	}
+
+	cancelled := 0
+	updated := make(map[*market]bool)
+	defer func() {
+		for m := range updated {
+			ob.notifyMarketUpdate(m)
+		}
+	}()
+
+	for _, r := range orders {
+		m, o := r.market, r.order
+		m.remove(o.Side, o.ID)
+		updated[m] = true
+
+		ok, err := ob.orderRepo.CancelIfOpen(ctx, o.ID)
+		if err != nil {
+			m.add(o)
+			return cancelled, fmt.Errorf("failed to cancel order %s: %w", o.ID, err)
+		}
+		if ok {
+			o.Status = models.OrderStatusCancelled
+			cancelled++
+		}
+	}
+
+	return cancelled, nil
+}
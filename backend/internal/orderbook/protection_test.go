@@ -0,0 +1,69 @@
+// internal/orderbook/protection_test.go
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestProtectionRollingWindow(t *testing.T) {
+	p := &protection{settings: models.MarketMakerProtection{MaxFills: 3, WindowSeconds: 10}}
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.False(t, p.recordFill(start))
+	assert.False(t, p.recordFill(start.Add(5*time.Second)))
+	// The first fill has left the window
+	assert.False(t, p.recordFill(start.Add(10*time.Second)))
+	assert.Equal(t, 2, p.state(start.Add(10*time.Second)).RecentFills)
+
+	assert.True(t, p.recordFill(start.Add(11*time.Second)))
+	assert.True(t, p.pending)
+
+	state := p.state(start.Add(time.Hour))
+	assert.True(t, state.Frozen)
+	assert.Nil(t, state.FrozenUntil)
+
+	// Fills while frozen neither count nor trigger again
+	assert.False(t, p.recordFill(start.Add(time.Hour)))
+	assert.Zero(t, p.state(start.Add(time.Hour)).RecentFills)
+
+	p.reset()
+	assert.False(t, p.state(start.Add(time.Hour)).Frozen)
+}
+
+func TestProtectionTimedFreeze(t *testing.T) {
+	p := &protection{settings: models.MarketMakerProtection{MaxFills: 1, WindowSeconds: 10, FreezeSeconds: 60}}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, p.recordFill(now))
+
+	state := p.state(now.Add(59 * time.Second))
+	assert.True(t, state.Frozen)
+	assert.Equal(t, now.Add(time.Minute), *state.FrozenUntil)
+
+	assert.False(t, p.isFrozen(now.Add(time.Minute)))
+	assert.False(t, p.pending)
+}
+
+func TestCheckProtection(t *testing.T) {
+	keyID := uuid.New()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ob := &OrderBook{protections: map[uuid.UUID]*protection{
+		keyID: {settings: models.MarketMakerProtection{APIKeyID: keyID, MaxFills: 1, WindowSeconds: 10}},
+	}}
+
+	order := &models.Order{APIKeyID: &keyID}
+	assert.NoError(t, ob.checkProtection(order, now))
+	assert.NoError(t, ob.checkProtection(&models.Order{}, now))
+
+	ob.recordProtectedFill(order, now)
+	assert.ErrorIs(t, ob.checkProtection(order, now), ErrOrderRejected)
+
+	other := uuid.New()
+	assert.NoError(t, ob.checkProtection(&models.Order{APIKeyID: &other}, now))
+}
@@ -0,0 +1,149 @@
+// internal/orderbook/reconcile.go
+package orderbook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+)
+
+// Kinds of divergence between the in-memory book and the database
+const (
+	// divergenceMissing is an open order in the database absent from the book
+	divergenceMissing = "missing"
+	// divergenceStale is an order in the book that is no longer open in the
+	// database
+	divergenceStale = "stale"
+	// divergenceMismatch is an order whose quantity, status or price differs
+	divergenceMismatch = "mismatch"
+)
+
+// ReconcileResult counts the orders repaired by a reconciliation
+type ReconcileResult struct {
+	Missing    int
+	Stale      int
+	Mismatched int
+}
+
+// Diverged reports whether the book differed from the database
+func (r *ReconcileResult) Diverged() bool {
+	return r.Missing+r.Stale+r.Mismatched > 0
+}
+
+// Reconcile compares the in-memory book with the open orders in the database
+// and repairs the book where they differ. The database is authoritative:
+// a crash or failed transaction mid-match can leave the book ahead of what
+// was committed. It holds ob.mu exclusively, so no command runs while the
+// two are compared.
+func (ob *OrderBook) Reconcile(ctx context.Context) (*ReconcileResult, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	openOrders, err := ob.orderRepo.ListAllOpenOrders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list all open orders: %w", err)
+	}
+
+	stored := make(map[uuid.UUID]*models.Order, len(openOrders))
+	for _, order := range openOrders {
+		stored[order.ID] = order
+	}
+
+	result := &ReconcileResult{}
+	changed := make(map[*market]bool)
+	resting := make(map[uuid.UUID]bool)
+
+	for _, m := range ob.allMarkets() {
+		for _, side := range []models.OrderSide{models.OrderSideBuy, models.OrderSideSell} {
+			orders := m.orders(side)
+			kept := (*orders)[:0]
+			for _, order := range *orders {
+				want, ok := stored[order.ID]
+				if !ok {
+					result.Stale++
+					changed[m] = true
+					logDivergence(divergenceStale, order.ID, order, nil)
+					continue
+				}
+
+				resting[order.ID] = true
+				if diverges(order, want) {
+					result.Mismatched++
+					changed[m] = true
+					logDivergence(divergenceMismatch, order.ID, order, want)
+					// Keep the resting pointer, which protections and
+					// observers may hold
+					*order = *want
+				}
+				kept = append(kept, order)
+			}
+			if len(kept) == 0 {
+				kept = nil
+			}
+			*orders = kept
+		}
+	}
+
+	// Expired orders left the book on time and wait for the sweep to mark
+	// them in the database
+	now := time.Now()
+	for _, order := range openOrders {
+		if resting[order.ID] || !order.Rests() || expired(order, now) {
+			continue
+		}
+		result.Missing++
+		m := ob.market(orderKey(order))
+		m.add(order)
+		ob.expiries.schedule(m.key, order)
+		changed[m] = true
+		logDivergence(divergenceMissing, order.ID, nil, order)
+	}
+
+	for m := range changed {
+		m.sort()
+		ob.notifyMarketUpdate(m)
+	}
+
+	metrics.BookDivergences.WithLabelValues(divergenceMissing).Add(float64(result.Missing))
+	metrics.BookDivergences.WithLabelValues(divergenceStale).Add(float64(result.Stale))
+	metrics.BookDivergences.WithLabelValues(divergenceMismatch).Add(float64(result.Mismatched))
+
+	if result.Diverged() {
+		logger.Error().
+			Int("missing", result.Missing).
+			Int("stale", result.Stale).
+			Int("mismatched", result.Mismatched).
+			Msg("Order book diverged from the database and was repaired")
+	}
+
+	return result, nil
+}
+
+// diverges reports whether the resting copy of an order differs from the
+// stored one in anything the matcher relies on
+func diverges(resting, stored *models.Order) bool {
+	return resting.RemainingQuantity != stored.RemainingQuantity ||
+		resting.Quantity != stored.Quantity ||
+		resting.Status != stored.Status ||
+		resting.Price != stored.Price
+}
+
+// logDivergence logs one repaired order, with the book's and the database's
+// copies where each exists
+func logDivergence(kind string, orderID uuid.UUID, resting, stored *models.Order) {
+	event := logger.Warn().Str("kind", kind).Str("order_id", orderID.String())
+	if resting != nil {
+		event = event.Str("book_status", string(resting.Status)).
+			Int("book_remaining", resting.RemainingQuantity)
+	}
+	if stored != nil {
+		event = event.Str("db_status", string(stored.Status)).
+			Int("db_remaining", stored.RemainingQuantity)
+	}
+	event.Msg("Repairing order book divergence")
+}
@@ -0,0 +1,69 @@
+// internal/orderbook/reconcile_test.go
+package orderbook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"hashhedge/internal/models"
+)
+
+func TestReconcile(t *testing.T) {
+	orderBook, mockOrderRepo, _ := newMockOrderBook(t)
+
+	newOrder := func(side models.OrderSide, price int64, remaining int) *models.Order {
+		return &models.Order{
+			ID:                uuid.New(),
+			Side:              side,
+			ContractType:      models.ContractTypeCall,
+			StrikeHashRate:    350,
+			Price:             price,
+			Quantity:          5,
+			RemainingQuantity: remaining,
+			Status:            models.OrderStatusOpen,
+			TimeInForce:       models.TimeInForceGTC,
+		}
+	}
+
+	// In sync, ahead of the database after a failed match, and filled or
+	// cancelled in the database
+	synced := newOrder(models.OrderSideBuy, 100, 5)
+	ahead := newOrder(models.OrderSideSell, 120, 2)
+	gone := newOrder(models.OrderSideSell, 130, 5)
+
+	m := orderBook.market(orderKey(synced))
+	m.add(synced)
+	m.add(ahead)
+	m.add(gone)
+
+	storedSynced := *synced
+	storedAhead := *ahead
+	storedAhead.RemainingQuantity = 5
+	missing := newOrder(models.OrderSideBuy, 110, 5)
+	past := time.Now().Add(-time.Minute)
+	expiredOrder := newOrder(models.OrderSideBuy, 90, 5)
+	expiredOrder.ExpiresAt = &past
+
+	mockOrderRepo.EXPECT().ListAllOpenOrders(gomock.Any()).
+		Return([]*models.Order{&storedSynced, &storedAhead, missing, expiredOrder}, nil).
+		Times(2)
+
+	result, err := orderBook.Reconcile(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &ReconcileResult{Missing: 1, Stale: 1, Mismatched: 1}, result)
+	assert.True(t, result.Diverged())
+
+	assert.Equal(t, []*models.Order{missing, synced}, m.bids)
+	assert.Equal(t, []*models.Order{ahead}, m.asks)
+	assert.Equal(t, 5, ahead.RemainingQuantity)
+
+	// A second pass finds nothing to repair
+	result, err = orderBook.Reconcile(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, result.Diverged())
+}
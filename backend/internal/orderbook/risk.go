@@ -0,0 +1,107 @@
+// internal/orderbook/risk.go
+package orderbook
+
+import (
+	"errors"
+	"fmt"
+
+	"hashhedge/internal/models"
+)
+
+// ErrOrderRejected is returned when an order's contract terms fail the risk checks
+var ErrOrderRejected = errors.New("order rejected")
+
+// RiskLimits bound the block range of an order relative to the chain tip so
+// that every match produces a contract that can be settled. A zero maximum
+// disables that bound.
+type RiskLimits struct {
+	// MinDurationBlocks is the shortest contract an order may quote
+	MinDurationBlocks int64 `yaml:"min_duration_blocks"`
+	// MaxDurationBlocks is the longest contract an order may quote
+	MaxDurationBlocks int64 `yaml:"max_duration_blocks"`
+	// MaxStartAheadBlocks is how far past the tip a contract may start
+	MaxStartAheadBlocks int64 `yaml:"max_start_ahead_blocks"`
+	// MaxStartBehindBlocks is how long ago a contract may have started, so
+	// rolled orders can continue from the end of the previous contract
+	MaxStartBehindBlocks int64 `yaml:"max_start_behind_blocks"`
+	// MinBlocksToEnd is how many blocks must remain before the end height
+	// for an order to be placed or matched
+	MinBlocksToEnd int64 `yaml:"min_blocks_to_end"`
+	// MaxOpenInterest is how many active contracts a market may hold before
+	// further orders and matches in it are rejected
+	MaxOpenInterest int64 `yaml:"max_open_interest"`
+}
+
+// DefaultRiskLimits allow contracts of up to a year starting within the next year
+var DefaultRiskLimits = RiskLimits{
+	MinDurationBlocks:    1,
+	MaxDurationBlocks:    52560,
+	MaxStartAheadBlocks:  52560,
+	MaxStartBehindBlocks: 2016,
+	MinBlocksToEnd:       1,
+}
+
+// Validate checks that the limits are consistent
+func (l RiskLimits) Validate() error {
+	if l.MinDurationBlocks < 1 {
+		return fmt.Errorf("order book minimum duration must be at least one block")
+	}
+	if l.MaxDurationBlocks < 0 || l.MaxStartAheadBlocks < 0 || l.MaxStartBehindBlocks < 0 || l.MinBlocksToEnd < 0 || l.MaxOpenInterest < 0 {
+		return fmt.Errorf("order book risk limits cannot be negative")
+	}
+	if l.MaxDurationBlocks > 0 && l.MaxDurationBlocks < l.MinDurationBlocks {
+		return fmt.Errorf("order book maximum duration is below the minimum duration")
+	}
+	return nil
+}
+
+// CheckOrder validates the block range of an order being placed
+func (l RiskLimits) CheckOrder(order *models.Order, tip int64) error {
+	duration := order.EndBlockHeight - order.StartBlockHeight
+	if duration < l.MinDurationBlocks {
+		return fmt.Errorf("%w: contract must cover at least %d blocks", ErrOrderRejected, l.MinDurationBlocks)
+	}
+	if l.MaxDurationBlocks > 0 && duration > l.MaxDurationBlocks {
+		return fmt.Errorf("%w: contract cannot cover more than %d blocks", ErrOrderRejected, l.MaxDurationBlocks)
+	}
+
+	if l.MaxStartAheadBlocks > 0 && order.StartBlockHeight > tip+l.MaxStartAheadBlocks {
+		return fmt.Errorf("%w: start height %d is more than %d blocks past the tip %d",
+			ErrOrderRejected, order.StartBlockHeight, l.MaxStartAheadBlocks, tip)
+	}
+	if order.StartBlockHeight < tip-l.MaxStartBehindBlocks {
+		return fmt.Errorf("%w: start height %d is more than %d blocks before the tip %d",
+			ErrOrderRejected, order.StartBlockHeight, l.MaxStartBehindBlocks, tip)
+	}
+
+	return l.CheckMatch(orderKey(order), tip)
+}
+
+// CheckMatch validates the block range of a market before a trade creates a contract
+func (l RiskLimits) CheckMatch(key OrderKey, tip int64) error {
+	if key.EndBlockHeight < tip+l.MinBlocksToEnd {
+		return fmt.Errorf("%w: end height %d is less than %d blocks past the tip %d",
+			ErrOrderRejected, key.EndBlockHeight, l.MinBlocksToEnd, tip)
+	}
+	return nil
+}
+
+// CheckOpenInterest validates that a market holding the given number of
+// active contracts has room for another
+func (l RiskLimits) CheckOpenInterest(contracts int64) error {
+	if l.MaxOpenInterest > 0 && contracts >= l.MaxOpenInterest {
+		return fmt.Errorf("%w: market has reached its open interest limit of %d contracts",
+			ErrOrderRejected, l.MaxOpenInterest)
+	}
+	return nil
+}
+
+// orderKey returns the market of an order
+func orderKey(order *models.Order) OrderKey {
+	return OrderKey{
+		ContractType:     order.ContractType,
+		StrikeHashRate:   order.StrikeHashRate,
+		StartBlockHeight: order.StartBlockHeight,
+		EndBlockHeight:   order.EndBlockHeight,
+	}
+}
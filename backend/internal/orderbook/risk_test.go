@@ -0,0 +1,71 @@
+// internal/orderbook/risk_test.go
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestCheckOrder(t *testing.T) {
+	const tip = 800000
+	limits := RiskLimits{
+		MinDurationBlocks:    6,
+		MaxDurationBlocks:    4032,
+		MaxStartAheadBlocks:  2016,
+		MaxStartBehindBlocks: 144,
+		MinBlocksToEnd:       3,
+	}
+
+	tests := []struct {
+		name   string
+		start  int64
+		end    int64
+		reject bool
+	}{
+		{"starts at the next block", tip + 1, tip + 2017, false},
+		{"too short", tip + 1, tip + 6, true},
+		{"too long", tip + 1, tip + 4034, true},
+		{"starts too far ahead", tip + 2017, tip + 2100, true},
+		{"started recently", tip - 144, tip + 100, false},
+		{"started too long ago", tip - 145, tip + 100, true},
+		{"already ended", tip - 100, tip - 1, true},
+		{"ends too soon", tip - 10, tip + 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &models.Order{StartBlockHeight: tt.start, EndBlockHeight: tt.end}
+			err := limits.CheckOrder(order, tip)
+			if tt.reject {
+				assert.ErrorIs(t, err, ErrOrderRejected)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("zero maximums are unbounded", func(t *testing.T) {
+		order := &models.Order{StartBlockHeight: tip + 100000, EndBlockHeight: tip + 300000}
+		assert.NoError(t, RiskLimits{MinDurationBlocks: 1}.CheckOrder(order, tip))
+	})
+}
+
+func TestRiskLimitsValidate(t *testing.T) {
+	assert.NoError(t, DefaultRiskLimits.Validate())
+	assert.Error(t, RiskLimits{}.Validate())
+	assert.Error(t, RiskLimits{MinDurationBlocks: 10, MaxDurationBlocks: 5}.Validate())
+	assert.Error(t, RiskLimits{MinDurationBlocks: 1, MinBlocksToEnd: -1}.Validate())
+	assert.Error(t, RiskLimits{MinDurationBlocks: 1, MaxOpenInterest: -1}.Validate())
+}
+
+func TestCheckOpenInterest(t *testing.T) {
+	limits := RiskLimits{MaxOpenInterest: 3}
+
+	assert.NoError(t, limits.CheckOpenInterest(0))
+	assert.NoError(t, limits.CheckOpenInterest(2))
+	assert.ErrorIs(t, limits.CheckOpenInterest(3), ErrOrderRejected)
+	assert.NoError(t, RiskLimits{}.CheckOpenInterest(1000))
+}
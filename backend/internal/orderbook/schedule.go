@@ -0,0 +1,45 @@
+// internal/orderbook/schedule.go
+package orderbook
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule holds the cadence of the order book's background tasks
+type Schedule struct {
+	// ExpirySweep is how often expired orders are cancelled in the
+	// database. Resting orders leave the in-memory book at their exact
+	// expiry; the sweep catches the rest and reloads the book after a sweep
+	// that cancels any order.
+	ExpirySweep time.Duration `yaml:"expiry_sweep"`
+	// Reload is how often the in-memory book is rebuilt from the database
+	// regardless of expiries; zero disables periodic reloads
+	Reload time.Duration `yaml:"reload"`
+	// Reconcile is how often the in-memory book is compared with the open
+	// orders in the database and repaired where they differ; zero disables
+	// reconciliation
+	Reconcile time.Duration `yaml:"reconcile"`
+}
+
+// DefaultSchedule sweeps expired orders every five minutes, reconciles the
+// book every minute and only reloads it after a sweep cancels orders
+var DefaultSchedule = Schedule{
+	ExpirySweep: 5 * time.Minute,
+	Reconcile:   time.Minute,
+}
+
+// Validate checks that the expiry sweep runs and the reload and reconcile
+// cadences are not negative
+func (s Schedule) Validate() error {
+	if s.ExpirySweep <= 0 {
+		return fmt.Errorf("order book expiry sweep interval must be positive")
+	}
+	if s.Reload < 0 {
+		return fmt.Errorf("order book reload interval cannot be negative")
+	}
+	if s.Reconcile < 0 {
+		return fmt.Errorf("order book reconcile interval cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// internal/orderbook/schedule_test.go
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	assert.NoError(t, DefaultSchedule.Validate())
+	assert.NoError(t, DefaultConfig.Validate())
+
+	reloading := DefaultSchedule
+	reloading.Reload = time.Hour
+	assert.NoError(t, reloading.Validate())
+
+	noSweep := DefaultSchedule
+	noSweep.ExpirySweep = 0
+	assert.Error(t, noSweep.Validate())
+
+	negativeReload := DefaultSchedule
+	negativeReload.Reload = -time.Minute
+	assert.Error(t, negativeReload.Validate())
+
+	negativeReconcile := DefaultSchedule
+	negativeReconcile.Reconcile = -time.Minute
+	assert.Error(t, negativeReconcile.Validate())
+
+	cfg := DefaultConfig
+	cfg.Schedule.ExpirySweep = 0
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,93 @@
+// internal/orderbook/target.go
+package orderbook
+
+import (
+	"fmt"
+	"time"
+
+	"hashhedge/internal/models"
+)
+
+// averageBlockInterval is the expected time between blocks
+const averageBlockInterval = 10 * time.Minute
+
+// deriveTargetTimestamp estimates when the end height will be reached at the
+// average block interval. It is truncated to the minute so orders listed
+// moments apart quote the same terms.
+func deriveTargetTimestamp(now time.Time, tip, endBlockHeight int64) time.Time {
+	remaining := endBlockHeight - tip
+	if remaining < 0 {
+		remaining = 0
+	}
+	return now.Add(time.Duration(remaining) * averageBlockInterval).UTC().Truncate(time.Minute)
+}
+
+// assignTargetTimestamp fixes the target timestamp of an order being placed.
+// An order that quotes none takes the one already listed in its market, or a
+// newly derived one if it is the first. The caller must hold the market's lock.
+func (m *market) assignTargetTimestamp(order *models.Order, tip int64, now time.Time) error {
+	if order.TargetTimestamp != nil {
+		if !order.TargetTimestamp.After(now) {
+			return fmt.Errorf("%w: target timestamp %s has already passed",
+				ErrOrderRejected, order.TargetTimestamp.Format(time.RFC3339))
+		}
+		target := order.TargetTimestamp.UTC()
+		order.TargetTimestamp = &target
+		return nil
+	}
+
+	if listed := m.listedTargetTimestamp(); listed != nil {
+		target := *listed
+		order.TargetTimestamp = &target
+		return nil
+	}
+
+	target := deriveTargetTimestamp(now, tip, order.EndBlockHeight)
+	order.TargetTimestamp = &target
+	return nil
+}
+
+// listedTargetTimestamp returns the target timestamp quoted by the earliest
+// live order of the market, if any. The caller must hold the market's lock.
+func (m *market) listedTargetTimestamp() *time.Time {
+	var earliest *models.Order
+	for _, orders := range [][]*models.Order{m.bids, m.asks} {
+		for _, o := range orders {
+			if !isLive(o) || o.TargetTimestamp == nil {
+				continue
+			}
+			if earliest == nil || o.CreatedAt.Before(earliest.CreatedAt) {
+				earliest = o
+			}
+		}
+	}
+
+	if earliest == nil {
+		return nil
+	}
+	return earliest.TargetTimestamp
+}
+
+// sameTerms reports whether two orders of a market quote the same target
+// timestamp. Orders placed before target timestamps were recorded quote none
+// and match any order.
+func sameTerms(a, b *models.Order) bool {
+	if a.TargetTimestamp == nil || b.TargetTimestamp == nil {
+		return true
+	}
+	return a.TargetTimestamp.Equal(*b.TargetTimestamp)
+}
+
+// contractTargetTimestamp returns the target timestamp of the contract
+// created by a trade: the one both orders quoted, or for orders that quoted
+// none an estimate from the contract's duration.
+func contractTargetTimestamp(buyOrder, sellOrder *models.Order, tradeTime time.Time) time.Time {
+	for _, o := range []*models.Order{buyOrder, sellOrder} {
+		if o.TargetTimestamp != nil {
+			return *o.TargetTimestamp
+		}
+	}
+
+	blocksToTarget := buyOrder.EndBlockHeight - buyOrder.StartBlockHeight
+	return tradeTime.Add(time.Duration(blocksToTarget) * averageBlockInterval)
+}
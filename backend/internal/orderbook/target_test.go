@@ -0,0 +1,84 @@
+// internal/orderbook/target_test.go
+package orderbook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestDeriveTargetTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 30, 45, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC), deriveTargetTimestamp(now, 800000, 800144))
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), deriveTargetTimestamp(now, 800000, 799990))
+}
+
+func TestAssignTargetTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	listed := now.Add(48 * time.Hour)
+	newOrder := func() *models.Order {
+		return &models.Order{
+			ContractType:      models.ContractTypeCall,
+			StrikeHashRate:    500,
+			StartBlockHeight:  800000,
+			EndBlockHeight:    800144,
+			Status:            models.OrderStatusOpen,
+			RemainingQuantity: 1,
+		}
+	}
+
+	m := &market{}
+
+	t.Run("first order derives a target", func(t *testing.T) {
+		order := newOrder()
+		assert.NoError(t, m.assignTargetTimestamp(order, 800000, now))
+		assert.Equal(t, now.Add(24*time.Hour), *order.TargetTimestamp)
+	})
+
+	t.Run("later orders inherit the listed target", func(t *testing.T) {
+		resting := newOrder()
+		resting.TargetTimestamp = &listed
+		m.asks = []*models.Order{resting}
+
+		order := newOrder()
+		assert.NoError(t, m.assignTargetTimestamp(order, 800010, now.Add(time.Hour)))
+		assert.Equal(t, listed, *order.TargetTimestamp)
+	})
+
+	t.Run("explicit target is kept", func(t *testing.T) {
+		quoted := now.Add(72 * time.Hour)
+		order := newOrder()
+		order.TargetTimestamp = &quoted
+		assert.NoError(t, m.assignTargetTimestamp(order, 800000, now))
+		assert.Equal(t, quoted, *order.TargetTimestamp)
+	})
+
+	t.Run("past target is rejected", func(t *testing.T) {
+		past := now.Add(-time.Minute)
+		order := newOrder()
+		order.TargetTimestamp = &past
+		assert.ErrorIs(t, m.assignTargetTimestamp(order, 800000, now), ErrOrderRejected)
+	})
+}
+
+func TestContractTargetTimestamp(t *testing.T) {
+	tradeTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	quoted := tradeTime.Add(36 * time.Hour)
+	other := quoted.Add(time.Hour)
+
+	buy := &models.Order{StartBlockHeight: 800000, EndBlockHeight: 800144}
+	sell := &models.Order{StartBlockHeight: 800000, EndBlockHeight: 800144}
+	assert.Equal(t, tradeTime.Add(24*time.Hour), contractTargetTimestamp(buy, sell, tradeTime))
+	assert.True(t, sameTerms(buy, sell))
+
+	sell.TargetTimestamp = &quoted
+	assert.Equal(t, quoted, contractTargetTimestamp(buy, sell, tradeTime))
+	assert.True(t, sameTerms(buy, sell))
+
+	buy.TargetTimestamp = &other
+	assert.False(t, sameTerms(buy, sell))
+}
@@ -0,0 +1,24 @@
+// internal/orderbook/ticker.go
+package orderbook
+
+import "time"
+
+// TickerRecorder keeps the ticker of each market. Unlike observers it is
+// called synchronously from the matching path while the market's lock is
+// held, so it sees every trade and top of book change of a market in order.
+// It must not block.
+type TickerRecorder interface {
+	// RecordTrade is called for each trade as it executes
+	RecordTrade(key OrderKey, price int64, quantity int, at time.Time)
+	// RecordQuote is called with the top of book after every change to a
+	// market, including the trades recorded before it
+	RecordQuote(snapshot MarketSnapshot)
+}
+
+// SetTickerRecorder sets the recorder kept up to date with every market's
+// trades and top of book
+func (ob *OrderBook) SetTickerRecorder(recorder TickerRecorder) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.ticker = recorder
+}
@@ -0,0 +1,19 @@
+// internal/orderbook/time_in_force.go
+package orderbook
+
+import "hashhedge/internal/models"
+
+// fillsCompletely reports whether an incoming order may execute against the
+// planned fills of the resting orders. Only a fill-or-kill order can be
+// refused: it executes when the fills cover its whole remaining quantity.
+func fillsCompletely(incoming *models.Order, fills []int) bool {
+	if incoming.TimeInForce != models.TimeInForceFOK {
+		return true
+	}
+
+	total := 0
+	for _, fill := range fills {
+		total += fill
+	}
+	return total >= incoming.RemainingQuantity
+}
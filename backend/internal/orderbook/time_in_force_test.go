@@ -0,0 +1,41 @@
+// internal/orderbook/time_in_force_test.go
+package orderbook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestFillsCompletely(t *testing.T) {
+	asks := []*models.Order{restingOrder(100, 3), restingOrder(100, 2), restingOrder(105, 4)}
+	upTo := func(limit int64) func(*models.Order) bool {
+		return func(o *models.Order) bool { return o.Price <= limit }
+	}
+	all := func(*models.Order) bool { return true }
+
+	tests := []struct {
+		name        string
+		timeInForce models.TimeInForce
+		quantity    int
+		limit       int64
+		want        bool
+	}{
+		{"fill-or-kill within one level", models.TimeInForceFOK, 5, 100, true},
+		{"fill-or-kill across levels", models.TimeInForceFOK, 9, 105, true},
+		{"fill-or-kill short of liquidity", models.TimeInForceFOK, 10, 105, false},
+		{"fill-or-kill short within its limit", models.TimeInForceFOK, 6, 100, false},
+		{"immediate-or-cancel takes a partial fill", models.TimeInForceIOC, 10, 105, true},
+		{"good-til-cancelled takes a partial fill", models.TimeInForceGTC, 10, 100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incoming := &models.Order{TimeInForce: tt.timeInForce, RemainingQuantity: tt.quantity}
+			fills := fillQuantities(models.PriceRuleResting, asks, tt.quantity, upTo(tt.limit), all)
+			assert.Equal(t, tt.want, fillsCompletely(incoming, fills))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// internal/positions/positions.go
+package positions
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// Position is a user's side of a contract they traded into. Each party
+// commits the contract size and the winner takes the loser's, while the
+// buyer pays the seller the premium. Open positions are marked to the
+// outcome the current hash rate implies; settled positions are realized
+// from the settlement evidence.
+type Position struct {
+	ContractID       uuid.UUID             `json:"contract_id"`
+	ContractType     models.ContractType   `json:"contract_type"`
+	StrikeHashRate   float64               `json:"strike_hash_rate"`
+	StartBlockHeight int64                 `json:"start_block_height"`
+	EndBlockHeight   int64                 `json:"end_block_height"`
+	Side             models.OrderSide      `json:"side"`
+	Status           models.ContractStatus `json:"status"`
+	Quantity         int                   `json:"quantity"`
+	EntryPrice       int64                 `json:"entry_price"`
+	ContractSize     int64                 `json:"contract_size"`
+	Premium          int64                 `json:"premium"`
+	NotionalAtRisk   int64                 `json:"notional_at_risk"`
+	InTheMoney       *bool                 `json:"in_the_money,omitempty"`
+	UnrealizedPnL    *int64                `json:"unrealized_pnl,omitempty"`
+	RealizedPnL      *int64                `json:"realized_pnl,omitempty"`
+	OpenedAt         time.Time             `json:"opened_at"`
+}
+
+// Summary is every position of a user with their totals. HashRate is the
+// network hash rate open positions were marked at, omitted when it could
+// not be calculated.
+type Summary struct {
+	HashRate       *float64    `json:"hash_rate,omitempty"`
+	OpenPositions  int         `json:"open_positions"`
+	NotionalAtRisk int64       `json:"notional_at_risk"`
+	UnrealizedPnL  int64       `json:"unrealized_pnl"`
+	RealizedPnL    int64       `json:"realized_pnl"`
+	Positions      []*Position `json:"positions"`
+}
+
+// HashRateSource calculates the current network hash rate in EH/s
+type HashRateSource interface {
+	CalculateCurrentHashRate(ctx context.Context) (float64, error)
+}
+
+// Service computes users' positions and P&L
+type Service struct {
+	repo     *db.PositionRepository
+	hashRate HashRateSource
+}
+
+// NewService creates a position service
+func NewService(repo *db.PositionRepository, hashRate HashRateSource) *Service {
+	return &Service{repo: repo, hashRate: hashRate}
+}
+
+// ForUser returns the positions of a user. Open positions are left
+// unmarked when the current hash rate cannot be calculated.
+func (s *Service) ForUser(ctx context.Context, userID uuid.UUID) (*Summary, error) {
+	entries, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashRate *float64
+	if rate, err := s.hashRate.CalculateCurrentHashRate(ctx); err != nil {
+		logger.Warn().Err(err).Msg("Failed to calculate hash rate, leaving positions unmarked")
+	} else {
+		hashRate = &rate
+	}
+
+	return Summarize(entries, hashRate), nil
+}
+
+// Summarize builds the positions and totals of a user's trade entries
+func Summarize(entries []*models.PositionEntry, hashRate *float64) *Summary {
+	summary := &Summary{HashRate: hashRate, Positions: make([]*Position, 0, len(entries))}
+
+	for _, entry := range entries {
+		p := &Position{
+			ContractID:       entry.ID,
+			ContractType:     entry.ContractType,
+			StrikeHashRate:   entry.StrikeHashRate,
+			StartBlockHeight: entry.StartBlockHeight,
+			EndBlockHeight:   entry.EndBlockHeight,
+			Side:             entry.Side,
+			Status:           entry.Status,
+			Quantity:         entry.Quantity,
+			EntryPrice:       entry.EntryPrice,
+			ContractSize:     entry.ContractSize,
+			Premium:          entry.Premium,
+			OpenedAt:         entry.OpenedAt,
+		}
+
+		switch entry.Status {
+		case models.ContractStatusCreated, models.ContractStatusActive:
+			p.NotionalAtRisk = entry.ContractSize
+			summary.OpenPositions++
+			summary.NotionalAtRisk += p.NotionalAtRisk
+			if hashRate != nil {
+				wins := buyerWinsAt(&entry.Contract, *hashRate) == (entry.Side == models.OrderSideBuy)
+				pnl := profit(&entry.Contract, entry.Side, wins)
+				p.InTheMoney = &wins
+				p.UnrealizedPnL = &pnl
+				summary.UnrealizedPnL += pnl
+			}
+
+		case models.ContractStatusSettled, models.ContractStatusPendingSettlement:
+			buyerWins, ok := settledBuyerWins(entry)
+			if ok {
+				pnl := profit(&entry.Contract, entry.Side, buyerWins == (entry.Side == models.OrderSideBuy))
+				p.RealizedPnL = &pnl
+				summary.RealizedPnL += pnl
+			}
+
+		default:
+			// Expired and cancelled contracts never moved funds
+			var zero int64
+			p.RealizedPnL = &zero
+		}
+
+		summary.Positions = append(summary.Positions, p)
+	}
+
+	return summary
+}
+
+// buyerWinsAt reports whether the buyer would win if the network kept its
+// current hash rate: a CALL buyer wins when the end height arrives before
+// the target time, which it does when the hash rate is at or above strike
+func buyerWinsAt(c *models.Contract, hashRate float64) bool {
+	return (hashRate >= c.StrikeHashRate) == (c.ContractType == models.ContractTypeCall)
+}
+
+// profit returns the P&L of one side of a contract given whether it won
+func profit(c *models.Contract, side models.OrderSide, wins bool) int64 {
+	pnl := c.ContractSize
+	if !wins {
+		pnl = -pnl
+	}
+	if side == models.OrderSideBuy {
+		return pnl - c.Premium
+	}
+	return pnl + c.Premium
+}
+
+// settledBuyerWins reads the outcome of a settled contract from its
+// settlement evidence
+func settledBuyerWins(entry *models.PositionEntry) (bool, bool) {
+	if len(entry.Evidence) == 0 {
+		return false, false
+	}
+
+	var outcome struct {
+		BuyerWins bool `json:"buyer_wins"`
+	}
+	if err := json.Unmarshal(entry.Evidence, &outcome); err != nil {
+		logger.Warn().Err(err).Str("contractID", entry.ID.String()).Msg("Failed to decode settlement evidence")
+		return false, false
+	}
+	return outcome.BuyerWins, true
+}
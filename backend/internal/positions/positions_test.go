@@ -0,0 +1,79 @@
+// internal/positions/positions_test.go
+package positions
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func entry(contractType models.ContractType, side models.OrderSide, status models.ContractStatus, evidence string) *models.PositionEntry {
+	e := &models.PositionEntry{
+		Contract: models.Contract{
+			ID:             uuid.New(),
+			ContractType:   contractType,
+			StrikeHashRate: 500,
+			ContractSize:   100000,
+			Premium:        2000,
+			Status:         status,
+		},
+		Side:       side,
+		EntryPrice: 100000,
+		Quantity:   1,
+	}
+	if evidence != "" {
+		e.Evidence = []byte(evidence)
+	}
+	return e
+}
+
+func TestBuyerWinsAt(t *testing.T) {
+	call := &models.Contract{ContractType: models.ContractTypeCall, StrikeHashRate: 500}
+	put := &models.Contract{ContractType: models.ContractTypePut, StrikeHashRate: 500}
+
+	assert.True(t, buyerWinsAt(call, 520))
+	assert.True(t, buyerWinsAt(call, 500))
+	assert.False(t, buyerWinsAt(call, 480))
+	assert.False(t, buyerWinsAt(put, 520))
+	assert.True(t, buyerWinsAt(put, 480))
+}
+
+func TestProfit(t *testing.T) {
+	c := &models.Contract{ContractSize: 100000, Premium: 2000}
+
+	assert.Equal(t, int64(98000), profit(c, models.OrderSideBuy, true))
+	assert.Equal(t, int64(-102000), profit(c, models.OrderSideBuy, false))
+	assert.Equal(t, int64(102000), profit(c, models.OrderSideSell, true))
+	assert.Equal(t, int64(-98000), profit(c, models.OrderSideSell, false))
+}
+
+func TestSummarize(t *testing.T) {
+	hashRate := 520.0
+	entries := []*models.PositionEntry{
+		entry(models.ContractTypeCall, models.OrderSideBuy, models.ContractStatusActive, ""),
+		entry(models.ContractTypePut, models.OrderSideBuy, models.ContractStatusActive, ""),
+		entry(models.ContractTypeCall, models.OrderSideSell, models.ContractStatusSettled, `{"buyer_wins":false}`),
+		entry(models.ContractTypeCall, models.OrderSideBuy, models.ContractStatusSettled, ""),
+		entry(models.ContractTypeCall, models.OrderSideBuy, models.ContractStatusCancelled, ""),
+	}
+
+	summary := Summarize(entries, &hashRate)
+	require.Len(t, summary.Positions, 5)
+	assert.Equal(t, 2, summary.OpenPositions)
+	assert.Equal(t, int64(200000), summary.NotionalAtRisk)
+	assert.Equal(t, int64(98000-102000), summary.UnrealizedPnL)
+	assert.Equal(t, int64(102000), summary.RealizedPnL)
+
+	assert.True(t, *summary.Positions[0].InTheMoney)
+	assert.False(t, *summary.Positions[1].InTheMoney)
+	assert.Nil(t, summary.Positions[3].RealizedPnL, "settled without evidence")
+	assert.Equal(t, int64(0), *summary.Positions[4].RealizedPnL)
+
+	unmarked := Summarize(entries[:1], nil)
+	assert.Nil(t, unmarked.Positions[0].UnrealizedPnL)
+	assert.Equal(t, int64(100000), unmarked.NotionalAtRisk)
+}
@@ -0,0 +1,116 @@
+// internal/pricing/quote.go
+package pricing
+
+import (
+	"errors"
+	"math"
+
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/models"
+)
+
+// BlocksPerYear converts blocks to expiry into the years volatility is annualized over
+const BlocksPerYear = hashrate.BlocksPerDay * 365
+
+// Params are the inputs to pricing a contract
+type Params struct {
+	ContractType   models.ContractType
+	StrikeHashRate float64 // In EH/s
+	HashRate       float64 // Current hash rate in EH/s
+	Volatility     float64 // Annualized
+	Blocks         int64   // Blocks to expiry
+	ContractSize   int64   // In satoshis
+}
+
+// Validate checks that the parameters can be priced
+func (p Params) Validate() error {
+	if p.ContractType != models.ContractTypeCall && p.ContractType != models.ContractTypePut {
+		return errors.New("invalid contract type")
+	}
+	if p.StrikeHashRate <= 0 {
+		return errors.New("strike hash rate must be positive")
+	}
+	if p.HashRate <= 0 {
+		return errors.New("hash rate must be positive")
+	}
+	if p.Volatility < 0 {
+		return errors.New("volatility cannot be negative")
+	}
+	if p.Blocks <= 0 {
+		return errors.New("contract must expire after the chain tip")
+	}
+	if p.ContractSize <= 0 {
+		return errors.New("contract size must be positive")
+	}
+	return nil
+}
+
+// Quote is an indicative premium for a contract. It is a reference for
+// sanity checking orders, not a price the exchange trades at.
+type Quote struct {
+	ContractType   models.ContractType `json:"contract_type"`
+	StrikeHashRate float64             `json:"strike_hash_rate"`
+	HashRate       float64             `json:"hash_rate"`
+	Volatility     float64             `json:"volatility"`
+	BlockHeight    int64               `json:"block_height"`
+	Blocks         int64               `json:"blocks"` // To expiry
+	Years          float64             `json:"years"`  // To expiry
+	ContractSize   int64               `json:"contract_size"`
+	// Probability is the risk neutral probability the buyer wins
+	Probability float64 `json:"probability"`
+	Premium     int64   `json:"premium"` // In satoshis
+}
+
+// normCDF is the standard normal cumulative distribution function
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// callProbability is the probability the hash rate ends above the strike
+// when it follows a driftless geometric Brownian motion: N(d2) of the
+// Black-Scholes price of a cash-or-nothing call at a zero rate. Without
+// volatility or time the outcome is already known, and a hash rate at the
+// strike is a coin toss.
+func callProbability(hashRate, strike, volatility, years float64) float64 {
+	spread := volatility * math.Sqrt(years)
+	if spread == 0 {
+		switch {
+		case hashRate > strike:
+			return 1
+		case hashRate < strike:
+			return 0
+		default:
+			return 0.5
+		}
+	}
+
+	d2 := (math.Log(hashRate/strike) - spread*spread/2) / spread
+	return normCDF(d2)
+}
+
+// Price quotes the premium of a binary option paying the contract size if
+// it finishes in the money, discounting nothing since it settles in the
+// bitcoin it is collateralized with
+func Price(p Params) (*Quote, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	years := float64(p.Blocks) / BlocksPerYear
+	probability := callProbability(p.HashRate, p.StrikeHashRate, p.Volatility, years)
+	if p.ContractType == models.ContractTypePut {
+		probability = 1 - probability
+	}
+
+	return &Quote{
+		ContractType:   p.ContractType,
+		StrikeHashRate: p.StrikeHashRate,
+		HashRate:       p.HashRate,
+		Volatility:     p.Volatility,
+		Blocks:         p.Blocks,
+		Years:          years,
+		ContractSize:   p.ContractSize,
+		Probability:    probability,
+		Premium:        int64(math.Round(probability * float64(p.ContractSize))),
+	}, nil
+}
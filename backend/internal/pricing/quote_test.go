@@ -0,0 +1,112 @@
+// internal/pricing/quote_test.go
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func TestPrice(t *testing.T) {
+	params := Params{
+		ContractType:   models.ContractTypeCall,
+		StrikeHashRate: 600,
+		HashRate:       600,
+		Volatility:     0.5,
+		Blocks:         BlocksPerYear / 4,
+		ContractSize:   1_000_000,
+	}
+
+	call, err := Price(params)
+	require.NoError(t, err)
+	// At the money d2 = -σ√T/2 = -0.125, so N(d2) ≈ 0.4503
+	assert.InDelta(t, 0.4503, call.Probability, 1e-4)
+	assert.InDelta(t, 450_262, call.Premium, 100)
+	assert.InDelta(t, 0.25, call.Years, 1e-9)
+
+	params.ContractType = models.ContractTypePut
+	put, err := Price(params)
+	require.NoError(t, err)
+	assert.InDelta(t, 1, call.Probability+put.Probability, 1e-12)
+	assert.InDelta(t, params.ContractSize, call.Premium+put.Premium, 1)
+
+	// Further out of the money is cheaper
+	params.StrikeHashRate = 500
+	deeper, err := Price(params)
+	require.NoError(t, err)
+	assert.Less(t, deeper.Premium, put.Premium)
+}
+
+func TestPriceWithoutVolatility(t *testing.T) {
+	params := Params{
+		ContractType:   models.ContractTypeCall,
+		StrikeHashRate: 500,
+		HashRate:       600,
+		Blocks:         BlocksPerYear,
+		ContractSize:   1000,
+	}
+
+	quote, err := Price(params)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), quote.Premium)
+
+	params.StrikeHashRate = 600
+	quote, err = Price(params)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), quote.Premium)
+}
+
+func TestPriceValidation(t *testing.T) {
+	valid := Params{
+		ContractType:   models.ContractTypeCall,
+		StrikeHashRate: 500,
+		HashRate:       600,
+		Volatility:     0.4,
+		Blocks:         144,
+		ContractSize:   1000,
+	}
+	require.NoError(t, valid.Validate())
+
+	for name, mutate := range map[string]func(p *Params){
+		"type":       func(p *Params) { p.ContractType = "STRADDLE" },
+		"strike":     func(p *Params) { p.StrikeHashRate = 0 },
+		"hash rate":  func(p *Params) { p.HashRate = -1 },
+		"volatility": func(p *Params) { p.Volatility = -0.1 },
+		"expired":    func(p *Params) { p.Blocks = 0 },
+		"size":       func(p *Params) { p.ContractSize = 0 },
+	} {
+		p := valid
+		mutate(&p)
+		_, err := Price(p)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMarketQuote(t *testing.T) {
+	market := &Market{BlockHeight: 1000, HashRate: 600, Volatility: 0.5}
+	override := 0.0
+
+	quote, err := market.Quote(Request{
+		ContractType:   models.ContractTypePut,
+		StrikeHashRate: 700,
+		EndBlockHeight: 2008,
+		ContractSize:   1000,
+		Volatility:     &override,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), quote.BlockHeight)
+	assert.Equal(t, int64(1008), quote.Blocks)
+	assert.Equal(t, 600.0, quote.HashRate)
+	assert.Equal(t, int64(1000), quote.Premium)
+
+	_, err = market.Quote(Request{
+		ContractType:   models.ContractTypeCall,
+		StrikeHashRate: 700,
+		EndBlockHeight: 1000,
+		ContractSize:   1000,
+	})
+	assert.Error(t, err)
+}
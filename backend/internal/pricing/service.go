@@ -0,0 +1,133 @@
+// internal/pricing/service.go
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/models"
+)
+
+// HashRates is the hash rate index the market inputs come from
+type HashRates interface {
+	CurrentIndex(ctx context.Context) (*hashrate.Index, error)
+	Statistics(ctx context.Context, window, sampleBlocks int64, epochs int) (*hashrate.Statistics, error)
+}
+
+// Market are the inputs of a quote taken from the chain
+type Market struct {
+	BlockHeight int64     `json:"block_height"`
+	HashRate    float64   `json:"hash_rate"`  // 1d average in EH/s
+	Volatility  float64   `json:"volatility"` // Realized over the last epoch of daily samples
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Request asks for a quote. HashRate and Volatility override the market
+// inputs when set, so a seller can price their own view.
+type Request struct {
+	ContractType   models.ContractType
+	StrikeHashRate float64
+	EndBlockHeight int64
+	ContractSize   int64
+	HashRate       *float64
+	Volatility     *float64
+}
+
+// Service quotes indicative premiums from the hash rate and its realized
+// volatility
+type Service struct {
+	hashRates HashRates
+	// Cache the market inputs, since realized volatility reads an epoch of blocks
+	cacheMutex    sync.RWMutex
+	market        *Market
+	cacheDuration time.Duration
+}
+
+// NewService creates a new pricing service
+func NewService(hashRates HashRates) *Service {
+	return &Service{
+		hashRates:     hashRates,
+		cacheDuration: time.Minute * 5, // Default 5 minute cache
+	}
+}
+
+// WithCacheDuration sets how long the market inputs are reused
+func (s *Service) WithCacheDuration(duration time.Duration) *Service {
+	s.cacheDuration = duration
+	return s
+}
+
+// Market returns the current hash rate and realized volatility
+func (s *Service) Market(ctx context.Context) (*Market, error) {
+	s.cacheMutex.RLock()
+	if s.market != nil && time.Since(s.market.UpdatedAt) < s.cacheDuration {
+		market := *s.market
+		s.cacheMutex.RUnlock()
+		return &market, nil
+	}
+	s.cacheMutex.RUnlock()
+
+	index, err := s.hashRates.CurrentIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash rate index: %w", err)
+	}
+	stats, err := s.hashRates.Statistics(ctx, hashrate.DefaultStatisticsWindow, hashrate.BlocksPerDay, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hash rate statistics: %w", err)
+	}
+
+	hashRate, ok := index.Averages["1d"]
+	if !ok {
+		hashRate = index.Current
+	}
+	market := &Market{
+		BlockHeight: index.BlockHeight,
+		HashRate:    hashRate,
+		Volatility:  stats.HashRate.Volatility,
+		UpdatedAt:   time.Now(),
+	}
+
+	s.cacheMutex.Lock()
+	s.market = market
+	s.cacheMutex.Unlock()
+
+	result := *market
+	return &result, nil
+}
+
+// Quote prices a contract at the market inputs, or the overrides in req
+func (s *Service) Quote(ctx context.Context, req Request) (*Quote, error) {
+	market, err := s.Market(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return market.Quote(req)
+}
+
+// Quote prices req at the market inputs. Its errors are all invalid requests.
+func (market *Market) Quote(req Request) (*Quote, error) {
+	params := Params{
+		ContractType:   req.ContractType,
+		StrikeHashRate: req.StrikeHashRate,
+		HashRate:       market.HashRate,
+		Volatility:     market.Volatility,
+		Blocks:         req.EndBlockHeight - market.BlockHeight,
+		ContractSize:   req.ContractSize,
+	}
+	if req.HashRate != nil {
+		params.HashRate = *req.HashRate
+	}
+	if req.Volatility != nil {
+		params.Volatility = *req.Volatility
+	}
+
+	q, err := Price(params)
+	if err != nil {
+		return nil, err
+	}
+	q.BlockHeight = market.BlockHeight
+	return q, nil
+}
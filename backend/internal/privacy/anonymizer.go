@@ -0,0 +1,139 @@
+// internal/privacy/anonymizer.go
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// aliasPrefix marks a participant alias in public responses
+const aliasPrefix = "anon-"
+
+// Config holds the counterparty anonymization configuration
+type Config struct {
+	// AliasSecret keys the participant aliases. When empty a random secret is
+	// generated at startup, so aliases change whenever the server restarts.
+	AliasSecret string `yaml:"alias_secret"`
+	// AdminUserIDs may see every participant's public keys
+	AdminUserIDs []string `yaml:"admin_user_ids"`
+}
+
+// Validate checks that every admin user ID is a valid UUID
+func (c Config) Validate() error {
+	for _, id := range c.AdminUserIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return fmt.Errorf("invalid admin user ID %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Anonymizer replaces participant public keys with opaque aliases. Aliases
+// are stable within a market but unlinkable across markets, so a participant
+// can be followed through one order book without revealing their activity
+// elsewhere.
+type Anonymizer struct {
+	secret []byte
+	admins map[uuid.UUID]bool
+}
+
+// NewAnonymizer creates an anonymizer from its configuration
+func NewAnonymizer(cfg Config) (*Anonymizer, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret := []byte(cfg.AliasSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate alias secret: %w", err)
+		}
+	}
+
+	admins := make(map[uuid.UUID]bool, len(cfg.AdminUserIDs))
+	for _, id := range cfg.AdminUserIDs {
+		admins[uuid.MustParse(id)] = true
+	}
+
+	return &Anonymizer{secret: secret, admins: admins}, nil
+}
+
+// IsAdmin reports whether a user may see full participant details
+func (a *Anonymizer) IsAdmin(userID uuid.UUID) bool {
+	return a.admins[userID]
+}
+
+// MarketKey identifies the market of a contract or order for aliasing
+func MarketKey(contractType models.ContractType, strikeHashRate float64, startBlockHeight, endBlockHeight int64) string {
+	return fmt.Sprintf("%s:%g:%d:%d", contractType, strikeHashRate, startBlockHeight, endBlockHeight)
+}
+
+// Alias returns the opaque alias of a public key within a market
+func (a *Anonymizer) Alias(market, pubKey string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(market))
+	mac.Write([]byte{0})
+	mac.Write([]byte(pubKey))
+	return aliasPrefix + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// PublicOrder is an order as shown to users other than its owner
+type PublicOrder struct {
+	ID                uuid.UUID           `json:"id"`
+	Participant       string              `json:"participant"`
+	Side              models.OrderSide    `json:"side"`
+	ContractType      models.ContractType `json:"contract_type"`
+	StrikeHashRate    float64             `json:"strike_hash_rate"`
+	StartBlockHeight  int64               `json:"start_block_height"`
+	EndBlockHeight    int64               `json:"end_block_height"`
+	Price             int64               `json:"price"`
+	Quantity          int                 `json:"quantity"`
+	RemainingQuantity int                 `json:"remaining_quantity"`
+	Status            models.OrderStatus  `json:"status"`
+	CreatedAt         time.Time           `json:"created_at"`
+	ExpiresAt         *time.Time          `json:"expires_at,omitempty"`
+	TargetTimestamp   *time.Time          `json:"target_timestamp,omitempty"`
+}
+
+// Order returns the public view of an order, with its owner replaced by an alias
+func (a *Anonymizer) Order(o *models.Order) *PublicOrder {
+	market := MarketKey(o.ContractType, o.StrikeHashRate, o.StartBlockHeight, o.EndBlockHeight)
+	return &PublicOrder{
+		ID:                o.ID,
+		Participant:       a.Alias(market, o.PubKey),
+		Side:              o.Side,
+		ContractType:      o.ContractType,
+		StrikeHashRate:    o.StrikeHashRate,
+		StartBlockHeight:  o.StartBlockHeight,
+		EndBlockHeight:    o.EndBlockHeight,
+		Price:             o.Price,
+		Quantity:          o.Quantity,
+		RemainingQuantity: o.RemainingQuantity,
+		Status:            o.Status,
+		CreatedAt:         o.CreatedAt,
+		ExpiresAt:         o.ExpiresAt,
+		TargetTimestamp:   o.TargetTimestamp,
+	}
+}
+
+// Contract returns a copy of a contract with the public keys of both sides
+// replaced by aliases and the registered key references removed
+func (a *Anonymizer) Contract(c *models.Contract) *models.Contract {
+	market := MarketKey(c.ContractType, c.StrikeHashRate, c.StartBlockHeight, c.EndBlockHeight)
+
+	public := *c
+	public.BuyerPubKey = a.Alias(market, c.BuyerPubKey)
+	public.SellerPubKey = a.Alias(market, c.SellerPubKey)
+	public.BuyerKeyID = nil
+	public.SellerKeyID = nil
+	return &public
+}
@@ -0,0 +1,82 @@
+// internal/privacy/anonymizer_test.go
+package privacy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/models"
+)
+
+func TestAlias(t *testing.T) {
+	a, err := NewAnonymizer(Config{AliasSecret: "secret"})
+	assert.NoError(t, err)
+
+	marketA := MarketKey(models.ContractTypeCall, 500, 800000, 802016)
+	marketB := MarketKey(models.ContractTypeCall, 500, 802016, 804032)
+
+	alias := a.Alias(marketA, "02aa")
+	assert.True(t, strings.HasPrefix(alias, aliasPrefix))
+	assert.Equal(t, alias, a.Alias(marketA, "02aa"), "aliases are stable within a market")
+	assert.False(t, alias == a.Alias(marketB, "02aa"), "aliases are unlinkable across markets")
+	assert.False(t, alias == a.Alias(marketA, "02bb"), "participants get distinct aliases")
+
+	other, err := NewAnonymizer(Config{AliasSecret: "other"})
+	assert.NoError(t, err)
+	assert.False(t, alias == other.Alias(marketA, "02aa"), "aliases depend on the secret")
+}
+
+func TestNewAnonymizer(t *testing.T) {
+	admin := uuid.New()
+
+	a, err := NewAnonymizer(Config{AdminUserIDs: []string{admin.String()}})
+	assert.NoError(t, err)
+	assert.True(t, a.IsAdmin(admin))
+	assert.False(t, a.IsAdmin(uuid.New()))
+
+	_, err = NewAnonymizer(Config{AdminUserIDs: []string{"not-a-uuid"}})
+	assert.Error(t, err)
+}
+
+func TestRedaction(t *testing.T) {
+	a, err := NewAnonymizer(Config{AliasSecret: "secret"})
+	assert.NoError(t, err)
+
+	keyID := uuid.New()
+	contract := &models.Contract{
+		ID:               uuid.New(),
+		ContractType:     models.ContractTypePut,
+		StrikeHashRate:   450,
+		StartBlockHeight: 800000,
+		EndBlockHeight:   802016,
+		BuyerPubKey:      "02aa",
+		SellerPubKey:     "02bb",
+		BuyerKeyID:       &keyID,
+	}
+
+	public := a.Contract(contract)
+	market := MarketKey(contract.ContractType, contract.StrikeHashRate, contract.StartBlockHeight, contract.EndBlockHeight)
+	assert.Equal(t, a.Alias(market, "02aa"), public.BuyerPubKey)
+	assert.Equal(t, a.Alias(market, "02bb"), public.SellerPubKey)
+	assert.Nil(t, public.BuyerKeyID)
+	assert.Equal(t, "02aa", contract.BuyerPubKey, "the original contract is not modified")
+
+	order := &models.Order{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		Side:             models.OrderSideBuy,
+		ContractType:     models.ContractTypePut,
+		StrikeHashRate:   450,
+		StartBlockHeight: 800000,
+		EndBlockHeight:   802016,
+		PubKey:           "02aa",
+		CreatedAt:        time.Now(),
+	}
+
+	// The buyer's order and contract share an alias within the market
+	assert.Equal(t, public.BuyerPubKey, a.Order(order).Participant)
+}
@@ -0,0 +1,184 @@
+// internal/push/apns.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// APNs rejects provider tokens older than an hour
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig holds the Apple Push Notification service token credentials
+type APNsConfig struct {
+	KeyID      string `yaml:"key_id"`
+	TeamID     string `yaml:"team_id"`
+	BundleID   string `yaml:"bundle_id"`
+	KeyPath    string `yaml:"key_path"` // .p8 signing key
+	Production bool   `yaml:"production"`
+}
+
+// Enabled reports whether APNs credentials are configured
+func (c APNsConfig) Enabled() bool {
+	return c.KeyPath != ""
+}
+
+// APNsSender delivers notifications through APNs using token based authentication
+type APNsSender struct {
+	cfg     APNsConfig
+	key     *ecdsa.PrivateKey
+	baseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender loads the signing key and creates an APNs sender
+func NewAPNsSender(cfg APNsConfig) (*APNsSender, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.BundleID == "" {
+		return nil, errors.New("APNs key ID, team ID and bundle ID are required")
+	}
+
+	data, err := os.ReadFile(cfg.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNs key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("APNs key is not PEM encoded")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse APNs key: %w", err)
+	}
+
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("APNs key is not an ECDSA key")
+	}
+
+	baseURL := apnsSandboxURL
+	if cfg.Production {
+		baseURL = apnsProductionURL
+	}
+
+	return &APNsSender{
+		cfg:     cfg,
+		key:     key,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Send delivers a notification to a device
+func (s *APNsSender) Send(ctx context.Context, token string, n Notification) error {
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": n.Title,
+				"body":  n.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range n.Data {
+		payload[k] = v
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode APNs payload: %w", err)
+	}
+
+	authToken, err := s.authToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build APNs request: %w", err)
+	}
+	req.Header.Set("authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", s.cfg.BundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send APNs request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&apnsErr)
+
+	if resp.StatusCode == http.StatusGone || apnsErr.Reason == "BadDeviceToken" || apnsErr.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+
+	return fmt.Errorf("APNs returned status %d: %s", resp.StatusCode, apnsErr.Reason)
+}
+
+// authToken returns the cached provider token, issuing a new one when it is about to expire
+func (s *APNsSender) authToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Since(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	now := time.Now()
+	token, err := signJWT(
+		map[string]interface{}{"alg": "ES256", "kid": s.cfg.KeyID},
+		map[string]interface{}{"iss": s.cfg.TeamID, "iat": now.Unix()},
+		s.signES256,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.issuedAt = now
+	return token, nil
+}
+
+// signES256 produces the fixed width r||s signature JWS requires
+func (s *APNsSender) signES256(digest []byte) ([]byte, error) {
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 64)
+	r.FillBytes(out[:32])
+	sig.FillBytes(out[32:])
+	return out, nil
+}
@@ -0,0 +1,67 @@
+// internal/push/push.go
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnregistered is returned by a sender when the push service reports that
+// a device token is no longer valid and should be forgotten
+var ErrUnregistered = errors.New("device token is no longer registered")
+
+// Config holds the mobile push configuration. A platform is enabled when its
+// credentials are set.
+type Config struct {
+	APNs APNsConfig `yaml:"apns"`
+	FCM  FCMConfig  `yaml:"fcm"`
+}
+
+// Notification is a platform independent push message
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers notifications through one push platform
+type Sender interface {
+	Send(ctx context.Context, token string, n Notification) error
+}
+
+// signJWT encodes header and claims and signs them with sign, which receives
+// the SHA-256 digest of the signing input
+func signJWT(header, claims map[string]interface{}, sign func(digest []byte) ([]byte, error)) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT header: %w", err)
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JWT claims: %w", err)
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(headerJSON) + "." + enc.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := sign(digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// signerFunc adapts a crypto.Signer to signJWT
+func signerFunc(signer crypto.Signer) func([]byte) ([]byte, error) {
+	return func(digest []byte) ([]byte, error) {
+		return signer.Sign(rand.Reader, digest, crypto.SHA256)
+	}
+}
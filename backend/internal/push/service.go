@@ -0,0 +1,185 @@
+// internal/push/service.go
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var logger = logging.Component(logging.Push)
+
+// Service manages device registrations and delivers fill and settlement
+// notifications to the registered devices of the affected users
+type Service struct {
+	repo    *db.DeviceRepository
+	senders map[models.PushPlatform]Sender
+}
+
+// NewService creates a push service with no platforms enabled
+func NewService(repo *db.DeviceRepository) *Service {
+	return &Service{
+		repo:    repo,
+		senders: make(map[models.PushPlatform]Sender),
+	}
+}
+
+// WithSender enables delivery to devices of a platform
+func (s *Service) WithSender(platform models.PushPlatform, sender Sender) *Service {
+	s.senders[platform] = sender
+	return s
+}
+
+// RegisterDevice stores a device token for a user
+func (s *Service) RegisterDevice(ctx context.Context, device *models.DeviceToken) error {
+	if err := device.Validate(); err != nil {
+		return err
+	}
+
+	if _, ok := s.senders[device.Platform]; !ok {
+		return fmt.Errorf("push platform %s is not enabled", device.Platform)
+	}
+
+	return s.repo.Register(ctx, device)
+}
+
+// ListDevices retrieves the devices of a user
+func (s *Service) ListDevices(ctx context.Context, userID uuid.UUID) ([]*models.DeviceToken, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// RemoveDevice unregisters a device of a user
+func (s *Service) RemoveDevice(ctx context.Context, userID, deviceID uuid.UUID) error {
+	return s.repo.Delete(ctx, userID, deviceID)
+}
+
+// ListPreferences retrieves a user's push preferences for every event type
+func (s *Service) ListPreferences(ctx context.Context, userID uuid.UUID) ([]*models.PushPreference, error) {
+	return s.repo.ListPreferences(ctx, userID)
+}
+
+// SetPreference enables or disables an event type for a user
+func (s *Service) SetPreference(ctx context.Context, pref *models.PushPreference) error {
+	if !models.IsValidPushEventType(pref.EventType) {
+		return errors.New("invalid push event type")
+	}
+	return s.repo.SetPreference(ctx, pref)
+}
+
+// Notify sends a notification to every device of a user who has the event type enabled
+func (s *Service) Notify(ctx context.Context, userID uuid.UUID, eventType models.PushEventType, n Notification) {
+	enabled, err := s.repo.IsEnabled(ctx, userID, eventType)
+	if err != nil {
+		logger.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to load push preference")
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	devices, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to load push devices")
+		return
+	}
+
+	for _, device := range devices {
+		sender, ok := s.senders[device.Platform]
+		if !ok {
+			continue
+		}
+
+		err := sender.Send(ctx, device.Token, n)
+		if errors.Is(err, ErrUnregistered) {
+			logger.Info().
+				Str("user_id", userID.String()).
+				Str("device_id", device.ID.String()).
+				Msg("Removing unregistered push device")
+			if err := s.repo.DeleteByToken(ctx, device.Token); err != nil {
+				logger.Error().Err(err).Str("device_id", device.ID.String()).Msg("Failed to remove push device")
+			}
+			continue
+		}
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("user_id", userID.String()).
+				Str("device_id", device.ID.String()).
+				Str("platform", string(device.Platform)).
+				Msg("Failed to deliver push notification")
+		}
+	}
+}
+
+// OnFill implements orderbook.FillObserver by notifying both sides of a trade
+func (s *Service) OnFill(ctx context.Context, fill orderbook.Fill) {
+	for _, order := range []models.Order{fill.BuyOrder, fill.SellOrder} {
+		title := "Order partially filled"
+		if order.Status == models.OrderStatusFilled {
+			title = "Order filled"
+		}
+
+		s.Notify(ctx, order.UserID, models.PushEventFill, Notification{
+			Title: title,
+			Body: fmt.Sprintf("%s %d %s @ %d sats",
+				order.Side, fill.Trade.Quantity, fill.Contract.ContractType, fill.Trade.Price),
+			Data: map[string]string{
+				"event":       string(models.PushEventFill),
+				"order_id":    order.ID.String(),
+				"trade_id":    fill.Trade.ID.String(),
+				"contract_id": fill.Contract.ID.String(),
+			},
+		})
+	}
+}
+
+// OnContractSettled implements contract.SettlementObserver by notifying both parties
+func (s *Service) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	userIDs, err := s.repo.ListContractParties(ctx, contract.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to load contract parties")
+		return
+	}
+
+	for _, userID := range userIDs {
+		s.Notify(ctx, userID, models.PushEventSettlement, Notification{
+			Title: "Contract settled",
+			Body:  fmt.Sprintf("%s contract %s has settled", contract.ContractType, contract.ID.String()[:8]),
+			Data: map[string]string{
+				"event":       string(models.PushEventSettlement),
+				"contract_id": contract.ID.String(),
+				"buyer_wins":  strconv.FormatBool(buyerWins),
+			},
+		})
+	}
+}
+
+// OnSettlementDeferred implements contract.DeferralObserver by notifying both parties
+func (s *Service) OnSettlementDeferred(ctx context.Context, contract *models.Contract, deferral *models.SettlementDeferral) {
+	userIDs, err := s.repo.ListContractParties(ctx, contract.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to load contract parties")
+		return
+	}
+
+	for _, userID := range userIDs {
+		s.Notify(ctx, userID, models.PushEventSettlementDeferred, Notification{
+			Title: "Settlement deferred",
+			Body: fmt.Sprintf("%s contract %s will settle once fees fall from %.0f sat/vB",
+				contract.ContractType, contract.ID.String()[:8], deferral.FeeRate),
+			Data: map[string]string{
+				"event":       string(models.PushEventSettlementDeferred),
+				"contract_id": contract.ID.String(),
+				"fee_rate":    strconv.FormatFloat(deferral.FeeRate, 'f', 1, 64),
+			},
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// internal/ratelimit/ratelimit.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Class is a group of endpoints sharing a bucket
+type Class string
+
+const (
+	// ClassOrders covers placing and amending orders
+	ClassOrders Class = "orders"
+	// ClassCancels covers cancelling orders
+	ClassCancels Class = "cancels"
+	// ClassExits covers submitting and broadcasting emergency exits, which
+	// reach the Bitcoin node
+	ClassExits Class = "exits"
+	// ClassReads covers every other request
+	ClassReads Class = "reads"
+)
+
+var (
+	ordersPath = regexp.MustCompile(`^/orders/?$`)
+	orderPath  = regexp.MustCompile(`^/orders/[^/]+/?$`)
+	exitPath   = regexp.MustCompile(`^/wallet/(emergency-exit|exit-transactions/[^/]+/broadcast)/?$`)
+)
+
+// Classify returns the class of a request to path, relative to the root
+// of an API version
+func Classify(method, path string) Class {
+	switch {
+	case method == http.MethodPost && ordersPath.MatchString(path):
+		return ClassOrders
+	case method == http.MethodPatch && orderPath.MatchString(path):
+		return ClassOrders
+	case method == http.MethodDelete && orderPath.MatchString(path):
+		return ClassCancels
+	case method == http.MethodPost && exitPath.MatchString(path):
+		return ClassExits
+	}
+	return ClassReads
+}
+
+// Bucket is the sustained rate and burst of a class
+type Bucket struct {
+	// Rate is the number of requests per second a client may sustain
+	Rate float64 `yaml:"rate"`
+	// Burst is the number of requests a client may make at once
+	Burst int `yaml:"burst"`
+}
+
+// Validate checks that the bucket lets requests through
+func (b Bucket) Validate() error {
+	if b.Rate <= 0 {
+		return fmt.Errorf("rate must be positive")
+	}
+	if b.Burst < 1 {
+		return fmt.Errorf("burst must be at least one")
+	}
+	return nil
+}
+
+// Config holds the per-client rate limits
+type Config struct {
+	// Enabled turns rate limiting on
+	Enabled bool `yaml:"enabled"`
+	// Orders limits order placement and amendment
+	Orders Bucket `yaml:"orders"`
+	// Cancels limits order cancellation
+	Cancels Bucket `yaml:"cancels"`
+	// Exits limits emergency exit submissions and broadcasts
+	Exits Bucket `yaml:"exits"`
+	// Reads limits every other request
+	Reads Bucket `yaml:"reads"`
+	// IdleTTL is how long the bucket of a quiet client is kept. A client
+	// that returns after it is dropped starts with a full bucket.
+	IdleTTL time.Duration `yaml:"idle_ttl"`
+}
+
+// DefaultConfig lets each client place 5 orders a second with bursts of
+// 20, cancel twice as fast, submit an emergency exit a minute with bursts
+// of 5, and make 20 other requests a second
+var DefaultConfig = Config{
+	Enabled: true,
+	Orders:  Bucket{Rate: 5, Burst: 20},
+	Cancels: Bucket{Rate: 10, Burst: 40},
+	Exits:   Bucket{Rate: 1.0 / 60, Burst: 5},
+	Reads:   Bucket{Rate: 20, Burst: 60},
+	IdleTTL: 10 * time.Minute,
+}
+
+// Validate checks that the rate limits are usable
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	for class, bucket := range c.buckets() {
+		if err := bucket.Validate(); err != nil {
+			return fmt.Errorf("%s rate limit %w", class, err)
+		}
+	}
+	if c.IdleTTL <= 0 {
+		return fmt.Errorf("rate limit idle TTL must be positive")
+	}
+	return nil
+}
+
+func (c Config) buckets() map[Class]Bucket {
+	return map[Class]Bucket{
+		ClassOrders:  c.Orders,
+		ClassCancels: c.Cancels,
+		ClassExits:   c.Exits,
+		ClassReads:   c.Reads,
+	}
+}
+
+type bucketKey struct {
+	class  Class
+	client string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter holds a token bucket for each client and class
+type Limiter struct {
+	cfg    Config
+	limits map[Class]Bucket
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+// NewLimiter creates a rate limiter
+func NewLimiter(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		limits:  cfg.buckets(),
+		now:     time.Now,
+		buckets: make(map[bucketKey]*bucket),
+	}
+}
+
+// Allow takes a token from the client's bucket of a class. When the bucket
+// is empty it returns false and how long until a token is available.
+func (l *Limiter) Allow(class Class, client string) (bool, time.Duration) {
+	limit, ok := l.limits[class]
+	if !ok {
+		return true, 0
+	}
+
+	now := l.now()
+	key := bucketKey{class: class, client: client}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+// Start periodically drops the buckets of clients idle for longer than the
+// idle TTL, until ctx is cancelled
+func (l *Limiter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(l.cfg.IdleTTL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.sweep()
+			}
+		}
+	}()
+}
+
+// sweep drops the buckets of idle clients
+func (l *Limiter) sweep() {
+	cutoff := l.now().Add(-l.cfg.IdleTTL)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if b.last.Before(cutoff) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// internal/ratelimit/ratelimit_test.go
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   Class
+	}{
+		{http.MethodPost, "/orders", ClassOrders},
+		{http.MethodPost, "/orders/", ClassOrders},
+		{http.MethodPatch, "/orders/abc", ClassOrders},
+		{http.MethodDelete, "/orders/abc", ClassCancels},
+		{http.MethodGet, "/orders/user/abc", ClassReads},
+		{http.MethodPut, "/orders/abc/auto-roll", ClassReads},
+		{http.MethodGet, "/market/depth", ClassReads},
+		{http.MethodPost, "/contracts", ClassReads},
+		{http.MethodPost, "/wallet/emergency-exit", ClassExits},
+		{http.MethodPost, "/wallet/exit-transactions/abc/broadcast", ClassExits},
+		{http.MethodGet, "/wallet/exit-transactions/abc/download", ClassReads},
+		{http.MethodPost, "/wallet/withdrawals", ClassReads},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Classify(tt.method, tt.path), "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(Config{
+		Enabled: true,
+		Orders:  Bucket{Rate: 2, Burst: 3},
+		Cancels: Bucket{Rate: 1, Burst: 1},
+		Reads:   Bucket{Rate: 1, Burst: 1},
+		IdleTTL: time.Minute,
+	})
+	l.now = func() time.Time { return now }
+
+	// The burst is available at once
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow(ClassOrders, "user:a")
+		assert.True(t, ok)
+	}
+	ok, wait := l.Allow(ClassOrders, "user:a")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Other clients and classes have their own buckets
+	ok, _ = l.Allow(ClassOrders, "user:b")
+	assert.True(t, ok)
+	ok, _ = l.Allow(ClassCancels, "user:a")
+	assert.True(t, ok)
+
+	// Tokens refill at the rate
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.Allow(ClassOrders, "user:a")
+	assert.True(t, ok)
+	ok, _ = l.Allow(ClassOrders, "user:a")
+	assert.False(t, ok)
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(DefaultConfig)
+	l.now = func() time.Time { return now }
+
+	l.Allow(ClassReads, "ip:1.2.3.4")
+	now = now.Add(time.Minute)
+	l.Allow(ClassReads, "ip:5.6.7.8")
+
+	now = now.Add(DefaultConfig.IdleTTL - time.Second)
+	l.sweep()
+
+	assert.Len(t, l.buckets, 1)
+	assert.Contains(t, l.buckets, bucketKey{class: ClassReads, client: "ip:5.6.7.8"})
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+	assert.NoError(t, Config{}.Validate())
+
+	cfg := DefaultConfig
+	cfg.Cancels.Burst = 0
+	assert.Error(t, cfg.Validate())
+}
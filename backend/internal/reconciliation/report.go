@@ -0,0 +1,402 @@
+// internal/reconciliation/report.go
+package reconciliation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+)
+
+// dateLayout is the day an entry is booked on, in UTC
+const dateLayout = "2006-01-02"
+
+// EntryType is the kind of movement an entry records
+type EntryType string
+
+const (
+	// EntryPremium is the premium the buyer pays the seller
+	EntryPremium EntryType = "premium"
+	// EntryFunding is collateral locked in the contract's setup output
+	EntryFunding EntryType = "funding"
+	// EntryChainFee is the miner fee of a contract transaction
+	EntryChainFee EntryType = "chain_fee"
+	// EntryPayout is collateral paid out of the contract
+	EntryPayout EntryType = "payout"
+)
+
+// Accounts money moves between. Inputs are not attributed to a party, so
+// funding comes from the parties jointly; settlement pays the winner.
+const (
+	AccountBuyer   = "buyer"
+	AccountSeller  = "seller"
+	AccountParties = "parties"
+	AccountWinner  = "winner"
+	AccountEscrow  = "escrow"
+	AccountMiners  = "miners"
+)
+
+// payoutAccounts maps the transactions that pay collateral out of a
+// contract to the account they pay
+var payoutAccounts = map[string]string{
+	"settlement":     AccountWinner,
+	"close":          AccountParties,
+	"emergency_exit": AccountParties,
+}
+
+// feeTxTypes are the transactions whose miner fee is paid from the contract
+var feeTxTypes = map[string]bool{
+	"final":          true,
+	"settlement":     true,
+	"close":          true,
+	"emergency_exit": true,
+}
+
+// Entry is one movement of satoshis between two accounts
+type Entry struct {
+	Date       string    `json:"date"`
+	Time       time.Time `json:"time"`
+	ContractID uuid.UUID `json:"contract_id"`
+	Type       EntryType `json:"type"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Amount     int64     `json:"amount"` // In satoshis
+	TxID       string    `json:"txid,omitempty"`
+	Confirmed  bool      `json:"confirmed"`
+}
+
+// Totals sums the entries of a contract or day by type. Escrow is the
+// collateral still held: funding less payouts and chain fees.
+type Totals struct {
+	Premiums  int64 `json:"premiums"`
+	Funding   int64 `json:"funding"`
+	Payouts   int64 `json:"payouts"`
+	ChainFees int64 `json:"chain_fees"`
+	Escrow    int64 `json:"escrow"`
+}
+
+func (t *Totals) add(e *Entry) {
+	switch e.Type {
+	case EntryPremium:
+		t.Premiums += e.Amount
+	case EntryFunding:
+		t.Funding += e.Amount
+		t.Escrow += e.Amount
+	case EntryPayout:
+		t.Payouts += e.Amount
+		t.Escrow -= e.Amount
+	case EntryChainFee:
+		t.ChainFees += e.Amount
+		t.Escrow -= e.Amount
+	}
+}
+
+// ContractTotals are the totals of one contract
+type ContractTotals struct {
+	ContractID uuid.UUID             `json:"contract_id"`
+	Status     models.ContractStatus `json:"status"`
+	Totals
+}
+
+// DayTotals are the totals of one UTC day
+type DayTotals struct {
+	Date string `json:"date"`
+	Totals
+}
+
+// Unresolved is a contract transaction whose amounts could not be derived,
+// such as an Ark PSBT or a placeholder, and so needs manual reconciliation
+type Unresolved struct {
+	ContractID uuid.UUID `json:"contract_id"`
+	TxID       string    `json:"txid"`
+	TxType     string    `json:"tx_type"`
+	Reason     string    `json:"reason"`
+}
+
+// Report maps every satoshi in and out of the contracts between From and To
+type Report struct {
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	Entries     []*Entry          `json:"entries"`
+	Contracts   []*ContractTotals `json:"contracts"`
+	Days        []*DayTotals      `json:"days"`
+	Unresolved  []*Unresolved     `json:"unresolved"`
+}
+
+// Build derives the entries booked in [from, to) from the contracts and
+// their transaction graph. Chain fees are the difference between a
+// transaction's outputs and the inputs it spends, which are known when they
+// are recorded contract inputs or outputs of another contract transaction.
+func Build(state *db.ContractState, from, to time.Time) *Report {
+	report := &Report{
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now().UTC(),
+		Entries:     []*Entry{},
+		Contracts:   []*ContractTotals{},
+		Days:        []*DayTotals{},
+		Unresolved:  []*Unresolved{},
+	}
+
+	var entries []*Entry
+	prevouts := make(map[wire.OutPoint]int64)
+	setupInputs := make(map[uuid.UUID][]*models.ContractInput)
+	for _, input := range state.Inputs {
+		if op, err := outPoint(input.TxID, input.Vout); err == nil {
+			prevouts[op] = input.Value
+		}
+		if input.Stage == models.InputStageSetup {
+			setupInputs[input.ContractID] = append(setupInputs[input.ContractID], input)
+		}
+	}
+
+	// Decode every transaction first so each one's inputs can be valued
+	// from the outputs of the others
+	decoded := make(map[uuid.UUID]*wire.MsgTx)
+	for _, tx := range state.Transactions {
+		if !feeTxTypes[tx.TxType] {
+			continue
+		}
+		msgTx, err := decodeTx(tx.TxHex)
+		if err != nil {
+			report.unresolve(tx, err)
+			continue
+		}
+		decoded[tx.ID] = msgTx
+		hash := msgTx.TxHash()
+		for i, out := range msgTx.TxOut {
+			prevouts[wire.OutPoint{Hash: hash, Index: uint32(i)}] = out.Value
+		}
+	}
+
+	setupTxs := make(map[uuid.UUID]*models.ContractTransaction)
+	for _, tx := range state.Transactions {
+		if tx.TxType == "setup" || tx.TxType == "setup_onchain" {
+			setupTxs[tx.ContractID] = tx
+		}
+	}
+
+	for _, c := range state.Contracts {
+		if c.Premium > 0 {
+			entries = append(entries, &Entry{
+				Time:       c.CreatedAt,
+				ContractID: c.ID,
+				Type:       EntryPremium,
+				From:       AccountBuyer,
+				To:         AccountSeller,
+				Amount:     c.Premium,
+				Confirmed:  true,
+			})
+		}
+
+		// Separately funded fills are recorded as inputs; otherwise the
+		// setup transaction locks the contract size
+		if inputs := setupInputs[c.ID]; len(inputs) > 0 {
+			for _, input := range inputs {
+				entries = append(entries, &Entry{
+					Time:       input.CreatedAt,
+					ContractID: c.ID,
+					Type:       EntryFunding,
+					From:       AccountParties,
+					To:         AccountEscrow,
+					Amount:     input.Value,
+					TxID:       input.TxID,
+					Confirmed:  true,
+				})
+			}
+		} else if setup, ok := setupTxs[c.ID]; ok {
+			entries = append(entries, &Entry{
+				Time:       txTime(setup),
+				ContractID: c.ID,
+				Type:       EntryFunding,
+				From:       AccountParties,
+				To:         AccountEscrow,
+				Amount:     c.ContractSize,
+				TxID:       setup.TransactionID,
+				Confirmed:  setup.Confirmed,
+			})
+		}
+	}
+
+	for _, tx := range state.Transactions {
+		msgTx, ok := decoded[tx.ID]
+		if !ok {
+			continue
+		}
+
+		var out int64
+		for _, txOut := range msgTx.TxOut {
+			out += txOut.Value
+		}
+
+		if account, ok := payoutAccounts[tx.TxType]; ok {
+			entries = append(entries, &Entry{
+				Time:       txTime(tx),
+				ContractID: tx.ContractID,
+				Type:       EntryPayout,
+				From:       AccountEscrow,
+				To:         account,
+				Amount:     out,
+				TxID:       tx.TransactionID,
+				Confirmed:  tx.Confirmed,
+			})
+		}
+
+		in, err := inputValue(msgTx, prevouts)
+		if err != nil {
+			report.unresolve(tx, err)
+			continue
+		}
+		entries = append(entries, &Entry{
+			Time:       txTime(tx),
+			ContractID: tx.ContractID,
+			Type:       EntryChainFee,
+			From:       AccountEscrow,
+			To:         AccountMiners,
+			Amount:     in - out,
+			TxID:       tx.TransactionID,
+			Confirmed:  tx.Confirmed,
+		})
+	}
+
+	statuses := make(map[uuid.UUID]models.ContractStatus, len(state.Contracts))
+	for _, c := range state.Contracts {
+		statuses[c.ID] = c.Status
+	}
+
+	contracts := make(map[uuid.UUID]*ContractTotals)
+	days := make(map[string]*DayTotals)
+	for _, e := range entries {
+		e.Time = e.Time.UTC()
+		if !report.covers(e.Time) {
+			continue
+		}
+		e.Date = e.Time.Format(dateLayout)
+		report.Entries = append(report.Entries, e)
+
+		ct, ok := contracts[e.ContractID]
+		if !ok {
+			ct = &ContractTotals{ContractID: e.ContractID, Status: statuses[e.ContractID]}
+			contracts[e.ContractID] = ct
+			report.Contracts = append(report.Contracts, ct)
+		}
+		ct.add(e)
+
+		day, ok := days[e.Date]
+		if !ok {
+			day = &DayTotals{Date: e.Date}
+			days[e.Date] = day
+			report.Days = append(report.Days, day)
+		}
+		day.add(e)
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool { return report.Entries[i].Time.Before(report.Entries[j].Time) })
+	sort.Slice(report.Days, func(i, j int) bool { return report.Days[i].Date < report.Days[j].Date })
+
+	return report
+}
+
+// covers reports whether t falls in the report's period
+func (r *Report) covers(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// unresolve records a transaction of the report's period whose amounts
+// could not be derived
+func (r *Report) unresolve(tx *models.ContractTransaction, err error) {
+	if !r.covers(txTime(tx)) {
+		return
+	}
+	r.Unresolved = append(r.Unresolved, &Unresolved{
+		ContractID: tx.ContractID,
+		TxID:       tx.TransactionID,
+		TxType:     tx.TxType,
+		Reason:     err.Error(),
+	})
+}
+
+// txTime is when a transaction is booked: its confirmation if known,
+// otherwise its creation
+func txTime(tx *models.ContractTransaction) time.Time {
+	if tx.ConfirmedAt != nil {
+		return *tx.ConfirmedAt
+	}
+	return tx.CreatedAt
+}
+
+func outPoint(txid string, vout uint32) (wire.OutPoint, error) {
+	op, err := wire.NewOutPointFromString(fmt.Sprintf("%s:%d", txid, vout))
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+	return *op, nil
+}
+
+func decodeTx(txHex string) (*wire.MsgTx, error) {
+	if txHex == "" {
+		return nil, fmt.Errorf("transaction hex is not recorded")
+	}
+	raw, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("transaction is not hex encoded")
+	}
+	var msgTx wire.MsgTx
+	if err := msgTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("transaction is not a serialized Bitcoin transaction")
+	}
+	return &msgTx, nil
+}
+
+// inputValue sums the values of the outputs a transaction spends
+func inputValue(msgTx *wire.MsgTx, prevouts map[wire.OutPoint]int64) (int64, error) {
+	var in int64
+	for _, txIn := range msgTx.TxIn {
+		value, ok := prevouts[txIn.PreviousOutPoint]
+		if !ok {
+			return 0, fmt.Errorf("value of input %s is unknown", txIn.PreviousOutPoint)
+		}
+		in += value
+	}
+	return in, nil
+}
+
+// csvHeader is the column order of WriteCSV
+var csvHeader = []string{"date", "time", "contract_id", "type", "from", "to", "amount_sats", "txid", "confirmed"}
+
+// WriteCSV writes the entries of a report as CSV for import into accounting software
+func WriteCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range report.Entries {
+		record := []string{
+			e.Date,
+			e.Time.Format(time.RFC3339),
+			e.ContractID.String(),
+			string(e.Type),
+			e.From,
+			e.To,
+			strconv.FormatInt(e.Amount, 10),
+			e.TxID,
+			strconv.FormatBool(e.Confirmed),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
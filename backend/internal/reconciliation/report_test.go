@@ -0,0 +1,96 @@
+// internal/reconciliation/report_test.go
+package reconciliation
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+)
+
+// spendTx builds a transaction spending prevout into a single output
+func spendTx(t *testing.T, prevout wire.OutPoint, value int64) (*wire.MsgTx, string) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&prevout, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value, []byte{0x51}))
+
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+	return tx, hex.EncodeToString(buf.Bytes())
+}
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := &models.Contract{
+		ID:           uuid.New(),
+		ContractSize: 100000,
+		Premium:      1000,
+		Status:       models.ContractStatusSettled,
+		CreatedAt:    day,
+	}
+
+	fundingHash := chainhash.HashH([]byte("funding"))
+	finalTx, finalHex := spendTx(t, wire.OutPoint{Hash: fundingHash}, 99000)
+	_, settlementHex := spendTx(t, wire.OutPoint{Hash: finalTx.TxHash()}, 98500)
+	settled := day.Add(48 * time.Hour)
+
+	state := &db.ContractState{
+		Contracts: []*models.Contract{c},
+		Inputs: []*models.ContractInput{{
+			ContractID: c.ID,
+			Stage:      models.InputStageSetup,
+			TxID:       fundingHash.String(),
+			Value:      100000,
+			CreatedAt:  day,
+		}},
+		Transactions: []*models.ContractTransaction{
+			{ID: uuid.New(), ContractID: c.ID, TransactionID: "final", TxType: "final", TxHex: finalHex, CreatedAt: day.Add(24 * time.Hour)},
+			{ID: uuid.New(), ContractID: c.ID, TransactionID: "swap", TxType: "swap", TxHex: "cHNidP8B", CreatedAt: day},
+			{ID: uuid.New(), ContractID: c.ID, TransactionID: "settlement", TxType: "settlement", TxHex: settlementHex,
+				Confirmed: true, CreatedAt: settled.Add(-time.Hour), ConfirmedAt: &settled},
+			{ID: uuid.New(), ContractID: c.ID, TransactionID: "exit", TxType: "emergency_exit", TxHex: "cHNidP8B", CreatedAt: settled},
+		},
+	}
+
+	report := Build(state, day.Add(-time.Hour), day.Add(72*time.Hour))
+
+	require.Len(t, report.Entries, 5)
+	types := make([]EntryType, len(report.Entries))
+	for i, e := range report.Entries {
+		types[i] = e.Type
+	}
+	assert.ElementsMatch(t, []EntryType{EntryPremium, EntryFunding, EntryChainFee, EntryPayout, EntryChainFee}, types)
+
+	require.Len(t, report.Contracts, 1)
+	assert.Equal(t, Totals{Premiums: 1000, Funding: 100000, Payouts: 98500, ChainFees: 1500, Escrow: 0}, report.Contracts[0].Totals)
+
+	require.Len(t, report.Days, 3)
+	assert.Equal(t, "2024-05-01", report.Days[0].Date)
+	assert.Equal(t, int64(1000), report.Days[1].ChainFees)
+	assert.Equal(t, int64(98500), report.Days[2].Payouts)
+
+	// The swap is not a contract spend; the exit cannot be decoded
+	require.Len(t, report.Unresolved, 1)
+	assert.Equal(t, "exit", report.Unresolved[0].TxID)
+
+	// Entries outside the period are left out
+	report = Build(state, day.Add(24*time.Hour), day.Add(72*time.Hour))
+	assert.Len(t, report.Entries, 3)
+	assert.Equal(t, int64(-100000), report.Contracts[0].Escrow)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, report))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, strings.Join(csvHeader, ","), lines[0])
+}
@@ -0,0 +1,239 @@
+// internal/research/dump.go
+package research
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dumpExt is the file extension of daily dumps: gzipped JSON lines
+const dumpExt = ".jsonl.gz"
+
+// dumpDateLayout is the date in dump file names
+const dumpDateLayout = "2006-01-02"
+
+// dumpBatchSize is how many records are read at a time while dumping
+const dumpBatchSize = 1000
+
+var (
+	// ErrDumpsDisabled is returned when no dump directory is configured
+	ErrDumpsDisabled = errors.New("research dumps are not enabled")
+
+	// ErrDumpNotFound is returned for a dump file that does not exist
+	ErrDumpNotFound = errors.New("research dump not found")
+)
+
+// DumpFile describes a daily dump
+type DumpFile struct {
+	Name    string    `json:"name"`
+	Dataset Dataset   `json:"dataset"`
+	Date    string    `json:"date"`
+	Size    int64     `json:"size"`
+	Written time.Time `json:"written"`
+}
+
+// dumpName returns the file name of a dataset's dump for a UTC day
+func dumpName(dataset Dataset, day time.Time) string {
+	return string(dataset) + "-" + day.UTC().Format(dumpDateLayout) + dumpExt
+}
+
+// parseDumpName validates a dump file name, returning its dataset and day
+func parseDumpName(name string) (Dataset, time.Time, error) {
+	base := strings.TrimSuffix(name, dumpExt)
+	if base == name || len(base) <= len(dumpDateLayout) {
+		return "", time.Time{}, ErrDumpNotFound
+	}
+
+	split := len(base) - len(dumpDateLayout)
+	if base[split-1] != '-' {
+		return "", time.Time{}, ErrDumpNotFound
+	}
+	dataset, err := ParseDataset(base[:split-1])
+	if err != nil {
+		return "", time.Time{}, ErrDumpNotFound
+	}
+	day, err := time.Parse(dumpDateLayout, base[split:])
+	if err != nil {
+		return "", time.Time{}, ErrDumpNotFound
+	}
+	return dataset, day, nil
+}
+
+// Dumper writes each dataset's records of every completed UTC day to a
+// dump file. Dumps are only offered to the full tier and hold the same
+// anonymized records as the paginated feed.
+type Dumper struct {
+	feed *Service
+	cfg  Config
+}
+
+// NewDumper creates a daily dump writer
+func NewDumper(feed *Service, cfg Config) *Dumper {
+	return &Dumper{feed: feed, cfg: cfg}
+}
+
+// Start begins writing the dumps of each day once it has ended
+func (d *Dumper) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.cfg.DumpInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.dumpPreviousDay(ctx, time.Now().UTC())
+			}
+		}
+	}()
+}
+
+// dumpPreviousDay writes any missing dump of the day before now
+func (d *Dumper) dumpPreviousDay(ctx context.Context, now time.Time) {
+	day := now.Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for _, dataset := range Datasets {
+		if _, err := os.Stat(filepath.Join(d.cfg.DumpDir, dumpName(dataset, day))); err == nil {
+			continue
+		}
+
+		path, err := d.Dump(ctx, dataset, day)
+		if err != nil {
+			logger.Error().Err(err).Str("dataset", string(dataset)).Msg("Failed to write research dump")
+			continue
+		}
+		logger.Info().Str("path", path).Msg("Research dump written")
+	}
+}
+
+// Dump writes the records of a dataset from a UTC day to its dump file
+func (d *Dumper) Dump(ctx context.Context, dataset Dataset, day time.Time) (string, error) {
+	if !d.cfg.DumpsEnabled() {
+		return "", ErrDumpsDisabled
+	}
+	if err := os.MkdirAll(d.cfg.DumpDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(d.cfg.DumpDir, ".dump-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create dump file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := d.write(ctx, tmp, dataset, day); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to close dump file: %w", err)
+	}
+
+	// Rename only once the dump is complete so readers never see a partial file
+	path := filepath.Join(d.cfg.DumpDir, dumpName(dataset, day))
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to finalize dump file: %w", err)
+	}
+	return path, nil
+}
+
+// write encodes every record of a dataset from a UTC day as gzipped JSON lines
+func (d *Dumper) write(ctx context.Context, f *os.File, dataset Dataset, day time.Time) error {
+	start := day.UTC().Truncate(24 * time.Hour)
+	until := start.Add(24*time.Hour - time.Microsecond)
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+
+	cursor := Cursor{Time: start}
+	for {
+		records, last, err := d.feed.fetch(ctx, dataset, cursor, until, dumpBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return fmt.Errorf("failed to encode dump record: %w", err)
+			}
+		}
+		if len(records) < dumpBatchSize {
+			break
+		}
+		cursor = last
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress dump: %w", err)
+	}
+	return f.Sync()
+}
+
+// List returns the dump files, newest first
+func (d *Dumper) List() ([]DumpFile, error) {
+	if !d.cfg.DumpsEnabled() {
+		return nil, ErrDumpsDisabled
+	}
+
+	entries, err := os.ReadDir(d.cfg.DumpDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []DumpFile{}, nil
+		}
+		return nil, fmt.Errorf("failed to list dumps: %w", err)
+	}
+
+	files := []DumpFile{}
+	for _, entry := range entries {
+		dataset, day, err := parseDumpName(entry.Name())
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, DumpFile{
+			Name:    entry.Name(),
+			Dataset: dataset,
+			Date:    day.Format(dumpDateLayout),
+			Size:    info.Size(),
+			Written: info.ModTime().UTC(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Date != files[j].Date {
+			return files[i].Date > files[j].Date
+		}
+		return files[i].Dataset < files[j].Dataset
+	})
+	return files, nil
+}
+
+// Open opens a dump file by name. Only names of the dump format are
+// accepted, so a name cannot reach outside the dump directory.
+func (d *Dumper) Open(name string) (*os.File, error) {
+	if !d.cfg.DumpsEnabled() {
+		return nil, ErrDumpsDisabled
+	}
+	if _, _, err := parseDumpName(name); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(d.cfg.DumpDir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrDumpNotFound
+		}
+		return nil, fmt.Errorf("failed to open dump: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,405 @@
+// internal/research/feed.go
+package research
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+	"hashhedge/internal/privacy"
+)
+
+var logger = logging.Component(logging.Feeds)
+
+// Dataset names a table of the research feed
+type Dataset string
+
+const (
+	// DatasetTrades are executed trades with both sides aliased
+	DatasetTrades Dataset = "trades"
+	// DatasetBookSnapshots are periodic samples of the aggregated order books
+	DatasetBookSnapshots Dataset = "book_snapshots"
+	// DatasetSettlements are settlement outcomes with their chain evidence
+	DatasetSettlements Dataset = "settlements"
+)
+
+// Datasets lists every dataset of the feed
+var Datasets = []Dataset{DatasetTrades, DatasetBookSnapshots, DatasetSettlements}
+
+var (
+	// ErrUnknownDataset is returned for a dataset the feed does not serve
+	ErrUnknownDataset = errors.New("unknown research dataset")
+
+	// ErrDatasetNotInTier is returned when a tier does not include a dataset
+	ErrDatasetNotInTier = errors.New("dataset is not included in the research tier")
+
+	// ErrInvalidCursor is returned for a malformed page cursor
+	ErrInvalidCursor = errors.New("invalid research feed cursor")
+)
+
+// TierConfig holds the limits of one access tier
+type TierConfig struct {
+	// Delay holds back records until they are this old
+	Delay time.Duration `yaml:"delay"`
+	// History is how far back records are served; zero serves everything
+	History  time.Duration `yaml:"history"`
+	PageSize int           `yaml:"page_size"`
+}
+
+// Config holds the research feed configuration
+type Config struct {
+	// SnapshotInterval is how often the order books are sampled; zero
+	// disables book snapshots
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
+	SnapshotLevels   int           `yaml:"snapshot_levels"`
+	// DumpDir holds the daily dump files; empty disables dumps
+	DumpDir      string        `yaml:"dump_dir"`
+	DumpInterval time.Duration `yaml:"dump_interval"`
+	Basic        TierConfig    `yaml:"basic"`
+	Full         TierConfig    `yaml:"full"`
+}
+
+// DefaultConfig delays the basic tier by a day over a 90 day history and
+// serves the full tier in real time
+var DefaultConfig = Config{
+	SnapshotInterval: 5 * time.Minute,
+	SnapshotLevels:   20,
+	DumpInterval:     time.Hour,
+	Basic: TierConfig{
+		Delay:    24 * time.Hour,
+		History:  90 * 24 * time.Hour,
+		PageSize: 100,
+	},
+	Full: TierConfig{
+		PageSize: 1000,
+	},
+}
+
+// DumpsEnabled reports whether daily dump files are written
+func (c Config) DumpsEnabled() bool {
+	return c.DumpDir != ""
+}
+
+// Validate checks that the research feed settings are usable
+func (c Config) Validate() error {
+	if c.SnapshotInterval < 0 {
+		return fmt.Errorf("research snapshot interval cannot be negative")
+	}
+	if c.SnapshotInterval > 0 && c.SnapshotLevels <= 0 {
+		return fmt.Errorf("research snapshot levels must be positive")
+	}
+	if c.DumpsEnabled() && c.DumpInterval <= 0 {
+		return fmt.Errorf("research dump interval must be positive")
+	}
+	for name, tier := range map[string]TierConfig{"basic": c.Basic, "full": c.Full} {
+		if tier.Delay < 0 || tier.History < 0 {
+			return fmt.Errorf("research %s tier delay and history cannot be negative", name)
+		}
+		if tier.PageSize <= 0 {
+			return fmt.Errorf("research %s tier page size must be positive", name)
+		}
+	}
+	return nil
+}
+
+// Tier returns the limits of an access tier
+func (c Config) Tier(tier models.ResearchTier) TierConfig {
+	if tier == models.ResearchTierFull {
+		return c.Full
+	}
+	return c.Basic
+}
+
+// Includes reports whether a tier may read a dataset. Book snapshots are
+// only offered to the full tier.
+func Includes(tier models.ResearchTier, dataset Dataset) bool {
+	switch dataset {
+	case DatasetTrades, DatasetSettlements:
+		return true
+	case DatasetBookSnapshots:
+		return tier == models.ResearchTierFull
+	default:
+		return false
+	}
+}
+
+// ParseDataset validates a dataset name
+func ParseDataset(name string) (Dataset, error) {
+	for _, dataset := range Datasets {
+		if string(dataset) == name {
+			return dataset, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownDataset, name)
+}
+
+// Cursor is a position in a dataset: the time and ID of the last record read
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+// Encode returns the opaque form of a cursor handed to clients
+func (c Cursor) Encode() string {
+	raw := c.Time.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor from Encode. An empty cursor starts at the
+// beginning of the dataset.
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if c.Time, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// window returns the range of record times a tier may read at now: from
+// the start of its history up to its delay
+func window(tier TierConfig, now time.Time) (from, until time.Time) {
+	until = now.Add(-tier.Delay)
+	if tier.History > 0 {
+		from = now.Add(-tier.History)
+	}
+	return from, until
+}
+
+// Trade is a trade as published to researchers
+type Trade struct {
+	ID               uuid.UUID           `json:"id"`
+	ContractID       uuid.UUID           `json:"contract_id"`
+	ContractType     models.ContractType `json:"contract_type"`
+	StrikeHashRate   float64             `json:"strike_hash_rate"`
+	StartBlockHeight int64               `json:"start_block_height"`
+	EndBlockHeight   int64               `json:"end_block_height"`
+	Price            int64               `json:"price"`
+	Quantity         int                 `json:"quantity"`
+	PriceRule        models.PriceRule    `json:"price_rule"`
+	Buyer            string              `json:"buyer"`
+	Seller           string              `json:"seller"`
+	ExecutedAt       time.Time           `json:"executed_at"`
+}
+
+// Settlement is a settlement outcome as published to researchers
+type Settlement struct {
+	ContractID       uuid.UUID             `json:"contract_id"`
+	ContractType     models.ContractType   `json:"contract_type"`
+	StrikeHashRate   float64               `json:"strike_hash_rate"`
+	StartBlockHeight int64                 `json:"start_block_height"`
+	EndBlockHeight   int64                 `json:"end_block_height"`
+	ContractSize     int64                 `json:"contract_size"`
+	Premium          int64                 `json:"premium"`
+	Notional         models.Notional       `json:"notional"`
+	Buyer            string                `json:"buyer"`
+	Seller           string                `json:"seller"`
+	BuyerWins        bool                  `json:"buyer_wins"`
+	HeightReached    bool                  `json:"height_reached"`
+	TipHeight        int64                 `json:"tip_height"`
+	TipTime          time.Time             `json:"tip_time"`
+	SettlementTxID   string                `json:"settlement_tx_id"`
+	EvidenceDigest   string                `json:"evidence_digest"`
+	EvidenceStatus   models.EvidenceStatus `json:"evidence_status"`
+	DecidedAt        time.Time             `json:"decided_at"`
+}
+
+// Page is one page of a dataset. NextCursor is set when more records may
+// follow; Until is the latest record time the tier could see.
+type Page struct {
+	Dataset    Dataset       `json:"dataset"`
+	Records    []interface{} `json:"records"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Until      time.Time     `json:"until"`
+}
+
+// Service serves the research feed. Participants appear only as the same
+// per-market aliases used in public market data.
+type Service struct {
+	repo       *db.ResearchRepository
+	anonymizer *privacy.Anonymizer
+	cfg        Config
+}
+
+// NewService creates a research feed service
+func NewService(repo *db.ResearchRepository, anonymizer *privacy.Anonymizer, cfg Config) *Service {
+	return &Service{
+		repo:       repo,
+		anonymizer: anonymizer,
+		cfg:        cfg,
+	}
+}
+
+// Access retrieves the active research grant of a user
+func (s *Service) Access(ctx context.Context, userID uuid.UUID) (*models.ResearchAccess, error) {
+	return s.repo.GetActiveAccess(ctx, userID)
+}
+
+// ListAccess retrieves every research grant
+func (s *Service) ListAccess(ctx context.Context) ([]*models.ResearchAccess, error) {
+	return s.repo.ListAccess(ctx)
+}
+
+// GrantAccess gives a user access to the feed at a tier
+func (s *Service) GrantAccess(ctx context.Context, access *models.ResearchAccess) error {
+	if access.Tier != models.ResearchTierBasic && access.Tier != models.ResearchTierFull {
+		return fmt.Errorf("invalid research tier %q", access.Tier)
+	}
+	return s.repo.GrantAccess(ctx, access)
+}
+
+// RevokeAccess removes a user's access to the feed
+func (s *Service) RevokeAccess(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.RevokeAccess(ctx, userID)
+}
+
+// Page returns the records of a dataset after a cursor that a tier may see
+func (s *Service) Page(ctx context.Context, tier models.ResearchTier, dataset Dataset, cursor string, now time.Time) (*Page, error) {
+	if !Includes(tier, dataset) {
+		return nil, ErrDatasetNotInTier
+	}
+
+	after, err := ParseCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := s.cfg.Tier(tier)
+	from, until := window(limits, now)
+	if after.Time.Before(from) {
+		after = Cursor{Time: from}
+	}
+
+	records, last, err := s.fetch(ctx, dataset, after, until, limits.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Dataset: dataset, Records: records, Until: until}
+	if len(records) == limits.PageSize {
+		page.NextCursor = last.Encode()
+	}
+	return page, nil
+}
+
+// fetch reads up to limit records of a dataset after a cursor and at or
+// before until, returning the cursor of the last record
+func (s *Service) fetch(ctx context.Context, dataset Dataset, after Cursor, until time.Time, limit int) ([]interface{}, Cursor, error) {
+	records := make([]interface{}, 0, limit)
+	last := after
+
+	switch dataset {
+	case DatasetTrades:
+		trades, err := s.repo.ListTrades(ctx, after.Time, after.ID, until, limit)
+		if err != nil {
+			return nil, last, err
+		}
+		for _, t := range trades {
+			records = append(records, s.trade(t))
+			last = Cursor{Time: t.ExecutedAt, ID: t.ID}
+		}
+
+	case DatasetBookSnapshots:
+		snapshots, err := s.repo.ListBookSnapshots(ctx, after.Time, after.ID, until, limit)
+		if err != nil {
+			return nil, last, err
+		}
+		for _, snapshot := range snapshots {
+			records = append(records, snapshot)
+			last = Cursor{Time: snapshot.TakenAt, ID: snapshot.ID}
+		}
+
+	case DatasetSettlements:
+		settlements, err := s.repo.ListSettlements(ctx, after.Time, after.ID, until, limit)
+		if err != nil {
+			return nil, last, err
+		}
+		for _, settlement := range settlements {
+			records = append(records, s.settlement(settlement))
+			last = Cursor{Time: settlement.DecidedAt, ID: settlement.ID}
+		}
+
+	default:
+		return nil, last, fmt.Errorf("%w: %q", ErrUnknownDataset, dataset)
+	}
+
+	return records, last, nil
+}
+
+// trade returns the published form of a trade
+func (s *Service) trade(t *models.ResearchTrade) *Trade {
+	market := privacy.MarketKey(t.ContractType, t.StrikeHashRate, t.StartBlockHeight, t.EndBlockHeight)
+	return &Trade{
+		ID:               t.ID,
+		ContractID:       t.ContractID,
+		ContractType:     t.ContractType,
+		StrikeHashRate:   t.StrikeHashRate,
+		StartBlockHeight: t.StartBlockHeight,
+		EndBlockHeight:   t.EndBlockHeight,
+		Price:            t.Price,
+		Quantity:         t.Quantity,
+		PriceRule:        t.PriceRule,
+		Buyer:            s.anonymizer.Alias(market, t.BuyerPubKey),
+		Seller:           s.anonymizer.Alias(market, t.SellerPubKey),
+		ExecutedAt:       t.ExecutedAt,
+	}
+}
+
+// settlement returns the published form of a settlement. The outcome comes
+// from the evidence bundle, which records the chain state it was decided on.
+func (s *Service) settlement(rs *models.ResearchSettlement) *Settlement {
+	c := s.anonymizer.Contract(&rs.Contract)
+
+	published := &Settlement{
+		ContractID:       c.ID,
+		ContractType:     c.ContractType,
+		StrikeHashRate:   c.StrikeHashRate,
+		StartBlockHeight: c.StartBlockHeight,
+		EndBlockHeight:   c.EndBlockHeight,
+		ContractSize:     c.ContractSize,
+		Premium:          c.Premium,
+		Notional:         c.Notional,
+		Buyer:            c.BuyerPubKey,
+		Seller:           c.SellerPubKey,
+		EvidenceDigest:   rs.EvidenceDigest,
+		EvidenceStatus:   rs.EvidenceStatus,
+		DecidedAt:        rs.DecidedAt,
+	}
+
+	var bundle contract.EvidenceBundle
+	if err := json.Unmarshal(rs.Bundle, &bundle); err != nil {
+		logger.Warn().Err(err).Str("contractID", c.ID.String()).Msg("Failed to decode settlement evidence")
+		return published
+	}
+	published.BuyerWins = bundle.BuyerWins
+	published.HeightReached = bundle.HeightReached
+	published.TipHeight = bundle.TipHeight
+	published.TipTime = bundle.TipTime
+	published.SettlementTxID = bundle.SettlementTxID
+	return published
+}
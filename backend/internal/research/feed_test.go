@@ -0,0 +1,121 @@
+// internal/research/feed_test.go
+package research
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+func TestCursor(t *testing.T) {
+	c := Cursor{Time: time.Date(2024, 6, 1, 12, 0, 0, 123456000, time.UTC), ID: uuid.New()}
+
+	parsed, err := ParseCursor(c.Encode())
+	require.NoError(t, err)
+	assert.True(t, c.Time.Equal(parsed.Time))
+	assert.Equal(t, c.ID, parsed.ID)
+
+	empty, err := ParseCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{}, empty)
+
+	for _, bad := range []string{"!!!", "bm90IGEgY3Vyc29y", "MjAyNC0wNi0wMVQxMjowMDowMFp8bm90LWEtdXVpZA"} {
+		_, err := ParseCursor(bad)
+		assert.ErrorIs(t, err, ErrInvalidCursor, bad)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	from, until := window(DefaultConfig.Basic, now)
+	assert.Equal(t, now.AddDate(0, 0, -90), from)
+	assert.Equal(t, now.AddDate(0, 0, -1), until)
+
+	from, until = window(DefaultConfig.Full, now)
+	assert.True(t, from.IsZero())
+	assert.Equal(t, now, until)
+}
+
+func TestIncludes(t *testing.T) {
+	assert.True(t, Includes(models.ResearchTierBasic, DatasetTrades))
+	assert.True(t, Includes(models.ResearchTierBasic, DatasetSettlements))
+	assert.False(t, Includes(models.ResearchTierBasic, DatasetBookSnapshots))
+	assert.True(t, Includes(models.ResearchTierFull, DatasetBookSnapshots))
+	assert.False(t, Includes(models.ResearchTierFull, Dataset("orders")))
+}
+
+func TestDumpName(t *testing.T) {
+	day := time.Date(2024, 6, 1, 15, 0, 0, 0, time.UTC)
+	name := dumpName(DatasetBookSnapshots, day)
+	assert.Equal(t, "book_snapshots-2024-06-01.jsonl.gz", name)
+
+	dataset, parsed, err := parseDumpName(name)
+	require.NoError(t, err)
+	assert.Equal(t, DatasetBookSnapshots, dataset)
+	assert.Equal(t, "2024-06-01", parsed.Format(dumpDateLayout))
+
+	for _, bad := range []string{
+		"trades-2024-06-01.jsonl",
+		"orders-2024-06-01.jsonl.gz",
+		"../trades-2024-06-01.jsonl.gz",
+		"trades-2024-13-01.jsonl.gz",
+		".jsonl.gz",
+	} {
+		_, _, err := parseDumpName(bad)
+		assert.ErrorIs(t, err, ErrDumpNotFound, bad)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	cfg := DefaultConfig
+	cfg.Basic.PageSize = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig
+	cfg.DumpDir = "/tmp/research"
+	cfg.DumpInterval = 0
+	assert.Error(t, cfg.Validate())
+}
+
+type testBook struct {
+	depth map[orderbook.OrderKey]orderbook.Depth
+}
+
+func (b testBook) Tickers() []orderbook.MarketSnapshot {
+	var markets []orderbook.MarketSnapshot
+	for key := range b.depth {
+		markets = append(markets, orderbook.MarketSnapshot{Key: key})
+	}
+	return markets
+}
+
+func (b testBook) Depth(key orderbook.OrderKey, levels int) orderbook.Depth {
+	return b.depth[key]
+}
+
+func TestSnapshotsOf(t *testing.T) {
+	live := orderbook.OrderKey{ContractType: models.ContractTypeCall, StrikeHashRate: 500, StartBlockHeight: 850000, EndBlockHeight: 852016}
+	empty := orderbook.OrderKey{ContractType: models.ContractTypePut, StrikeHashRate: 500, StartBlockHeight: 850000, EndBlockHeight: 852016}
+	book := testBook{depth: map[orderbook.OrderKey]orderbook.Depth{
+		live:  {Bids: []orderbook.PriceLevel{{Price: 1000, Quantity: 2, Cumulative: 2, Orders: 1}}},
+		empty: {},
+	}}
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	snapshots, err := snapshotsOf(book, 10, now)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, models.ContractTypeCall, snapshots[0].ContractType)
+	assert.JSONEq(t, `[{"price":1000,"quantity":2,"cumulative":2,"orders":1}]`, string(snapshots[0].Bids))
+	assert.JSONEq(t, `[]`, string(snapshots[0].Asks))
+	assert.Equal(t, now, snapshots[0].TakenAt)
+}
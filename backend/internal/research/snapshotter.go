@@ -0,0 +1,99 @@
+// internal/research/snapshotter.go
+package research
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// BookSource is the order book as sampled by the snapshotter
+type BookSource interface {
+	Tickers() []orderbook.MarketSnapshot
+	Depth(key orderbook.OrderKey, levels int) orderbook.Depth
+}
+
+// Snapshotter periodically records the aggregated depth of every market
+// with resting orders for the book snapshot dataset
+type Snapshotter struct {
+	book BookSource
+	repo *db.ResearchRepository
+	cfg  Config
+}
+
+// NewSnapshotter creates a book snapshotter
+func NewSnapshotter(book BookSource, repo *db.ResearchRepository, cfg Config) *Snapshotter {
+	return &Snapshotter{book: book, repo: repo, cfg: cfg}
+}
+
+// Start begins sampling the order books
+func (s *Snapshotter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.SnapshotInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Snapshot(ctx, time.Now().UTC()); err != nil {
+					logger.Error().Err(err).Msg("Failed to record book snapshots")
+				}
+			}
+		}
+	}()
+}
+
+// Snapshot records the current depth of every market with resting orders
+func (s *Snapshotter) Snapshot(ctx context.Context, now time.Time) error {
+	snapshots, err := snapshotsOf(s.book, s.cfg.SnapshotLevels, now)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateBookSnapshots(ctx, snapshots)
+}
+
+// snapshotsOf builds the snapshots of the markets of a book, skipping
+// markets with nothing resting
+func snapshotsOf(book BookSource, levels int, now time.Time) ([]*models.BookSnapshot, error) {
+	var snapshots []*models.BookSnapshot
+	for _, market := range book.Tickers() {
+		depth := book.Depth(market.Key, levels)
+		if len(depth.Bids) == 0 && len(depth.Asks) == 0 {
+			continue
+		}
+		// Encode an empty side as an empty list rather than null
+		if depth.Bids == nil {
+			depth.Bids = []orderbook.PriceLevel{}
+		}
+		if depth.Asks == nil {
+			depth.Asks = []orderbook.PriceLevel{}
+		}
+
+		bids, err := json.Marshal(depth.Bids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode bids: %w", err)
+		}
+		asks, err := json.Marshal(depth.Asks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode asks: %w", err)
+		}
+
+		snapshots = append(snapshots, &models.BookSnapshot{
+			ContractType:     market.Key.ContractType,
+			StrikeHashRate:   market.Key.StrikeHashRate,
+			StartBlockHeight: market.Key.StartBlockHeight,
+			EndBlockHeight:   market.Key.EndBlockHeight,
+			Bids:             bids,
+			Asks:             asks,
+			TakenAt:          now,
+		})
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,214 @@
+// internal/rollover/scheduler.go
+package rollover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var logger = logging.Component(logging.OrderBook)
+
+// Config holds the rollover scheduler configuration
+type Config struct {
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+}
+
+// DefaultConfig provides sensible defaults for the rollover scheduler
+var DefaultConfig = Config{
+	Interval:  time.Minute,
+	BatchSize: 100,
+}
+
+// ChainTip reports the current block height
+type ChainTip interface {
+	CurrentBlockHeight(ctx context.Context) (int64, error)
+}
+
+// Scheduler places an equivalent order in the next expiry for every
+// auto-roll order whose market has expired and settled
+type Scheduler struct {
+	repo      *db.RolloverRepository
+	orderBook *orderbook.OrderBook
+	chain     ChainTip
+	cfg       Config
+}
+
+// NewScheduler creates a new rollover scheduler
+func NewScheduler(repo *db.RolloverRepository, orderBook *orderbook.OrderBook, chain ChainTip, cfg Config) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		orderBook: orderBook,
+		chain:     chain,
+		cfg:       cfg,
+	}
+}
+
+// Enable flags an order for auto-roll at the given price offset
+func (s *Scheduler) Enable(ctx context.Context, order *models.Order, priceOffset int64) (*models.AutoRoll, error) {
+	if order.Status == models.OrderStatusCancelled || order.Status == models.OrderStatusExpired {
+		return nil, errors.New("cancelled or expired orders cannot be rolled")
+	}
+
+	if order.Price+priceOffset <= 0 {
+		return nil, errors.New("price offset would make the rolled price non-positive")
+	}
+
+	roll := &models.AutoRoll{
+		UserID:      order.UserID,
+		OrderID:     order.ID,
+		PriceOffset: priceOffset,
+	}
+	if err := s.repo.Create(ctx, roll); err != nil {
+		return nil, err
+	}
+
+	return roll, nil
+}
+
+// Disable cancels the pending auto-roll of an order
+func (s *Scheduler) Disable(ctx context.Context, userID, orderID uuid.UUID) error {
+	return s.repo.CancelByOrder(ctx, userID, orderID)
+}
+
+// Get retrieves the pending auto-roll of an order
+func (s *Scheduler) Get(ctx context.Context, orderID uuid.UUID) (*models.AutoRoll, error) {
+	return s.repo.GetPendingByOrder(ctx, orderID)
+}
+
+// Start begins checking for auto-rolls that are due
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.runDue(ctx)
+			}
+		}
+	}()
+}
+
+// runDue rolls every pending instruction whose source market has settled
+func (s *Scheduler) runDue(ctx context.Context) {
+	tip, err := s.chain.CurrentBlockHeight(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to get block height for rollovers")
+		return
+	}
+
+	rolls, err := s.repo.ListPending(ctx, s.cfg.BatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list pending rollovers")
+		return
+	}
+
+	for _, roll := range rolls {
+		if err := s.process(ctx, roll, tip); err != nil {
+			logger.Warn().
+				Err(err).
+				Str("roll_id", roll.ID.String()).
+				Str("order_id", roll.OrderID.String()).
+				Msg("Rollover failed")
+			if err := s.repo.MarkFailed(ctx, roll.ID, err.Error()); err != nil {
+				logger.Error().Err(err).Str("roll_id", roll.ID.String()).Msg("Failed to mark rollover failed")
+			}
+		}
+	}
+}
+
+// process rolls a single instruction if its market has expired and every
+// contract filled from the order has settled. Returning nil without rolling
+// leaves the instruction pending for the next run.
+func (s *Scheduler) process(ctx context.Context, roll *models.AutoRoll, tip int64) error {
+	order, err := s.orderBook.GetOrderByID(ctx, roll.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to load source order: %w", err)
+	}
+
+	if tip < order.EndBlockHeight {
+		return nil
+	}
+
+	if order.Status == models.OrderStatusCancelled {
+		return errors.New("source order was cancelled")
+	}
+
+	unsettled, err := s.repo.CountUnsettledContracts(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if unsettled > 0 {
+		return nil
+	}
+
+	start, end := NextExpiry(s.orderBook.ListMarkets(order.ContractType, order.StrikeHashRate), order, tip)
+
+	rolled := &models.Order{
+		UserID:           order.UserID,
+		Side:             order.Side,
+		Type:             models.OrderTypeLimit,
+		ContractType:     order.ContractType,
+		StrikeHashRate:   order.StrikeHashRate,
+		StartBlockHeight: start,
+		EndBlockHeight:   end,
+		Price:            order.Price + roll.PriceOffset,
+		Quantity:         order.Quantity,
+		PubKey:           order.PubKey,
+		KeyID:            order.KeyID,
+	}
+
+	placed, err := s.orderBook.PlaceOrder(ctx, rolled)
+	if err != nil {
+		return fmt.Errorf("failed to place rolled order: %w", err)
+	}
+
+	if err := s.repo.MarkRolled(ctx, roll.ID, placed.ID); err != nil {
+		return err
+	}
+
+	// Keep rolling the new order until the user disables it
+	if _, err := s.Enable(ctx, placed, roll.PriceOffset); err != nil {
+		logger.Warn().Err(err).Str("order_id", placed.ID.String()).Msg("Failed to carry auto-roll to rolled order")
+	}
+
+	logger.Info().
+		Str("roll_id", roll.ID.String()).
+		Str("from_order_id", order.ID.String()).
+		Str("to_order_id", placed.ID.String()).
+		Int64("end_block_height", end).
+		Int64("price", placed.Price).
+		Msg("Order rolled to next expiry")
+
+	return nil
+}
+
+// NextExpiry picks the listed market with the nearest end height after the
+// order's that has not yet expired. Without one, the order's window is
+// shifted forward by its own length from the later of its end and the tip.
+func NextExpiry(markets []orderbook.OrderKey, order *models.Order, tip int64) (int64, int64) {
+	for _, market := range markets {
+		if market.EndBlockHeight > order.EndBlockHeight && market.EndBlockHeight > tip {
+			return market.StartBlockHeight, market.EndBlockHeight
+		}
+	}
+
+	start := order.EndBlockHeight
+	if tip > start {
+		start = tip
+	}
+	return start, start + (order.EndBlockHeight - order.StartBlockHeight)
+}
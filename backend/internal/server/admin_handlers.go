@@ -0,0 +1,58 @@
+// internal/server/admin_handlers.go
+package server
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// scheduledTask is the active cadence of a background task
+type scheduledTask struct {
+	Name            string  `json:"name"`
+	Enabled         bool    `json:"enabled"`
+	Interval        string  `json:"interval,omitempty"`
+	IntervalSeconds float64 `json:"interval_seconds,omitempty"`
+}
+
+// WithSchedule reports the interval of each background task, keyed by name,
+// from the admin schedule endpoint. A zero interval marks a disabled task.
+func (h *Handler) WithSchedule(schedule map[string]time.Duration) *Handler {
+	h.schedule = schedule
+	return h
+}
+
+// ResyncOrderBook handles rebuilding the in-memory order book from the database
+func (h *Handler) ResyncOrderBook(w http.ResponseWriter, r *http.Request) {
+	if err := h.orderBook.Reload(r.Context()); err != nil {
+		log.Error().Err(err).Msg("Failed to resync order book")
+		errorResponse(w, http.StatusInternalServerError, "Failed to resync order book")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Order book resynced successfully",
+	})
+}
+
+// GetSchedule handles listing the cadence of the background tasks
+func (h *Handler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	tasks := make([]scheduledTask, 0, len(h.schedule))
+	for name, interval := range h.schedule {
+		task := scheduledTask{Name: name, Enabled: interval > 0}
+		if task.Enabled {
+			task.Interval = interval.String()
+			task.IntervalSeconds = interval.Seconds()
+		}
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    tasks,
+	})
+}
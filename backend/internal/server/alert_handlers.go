@@ -0,0 +1,156 @@
+// internal/server/alert_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/alerts"
+	"hashhedge/internal/models"
+)
+
+// WithAlertService enables the price alert endpoints
+func (h *Handler) WithAlertService(svc *alerts.Service) *Handler {
+	h.alertService = svc
+	return h
+}
+
+// CreatePriceAlertRequest represents the request to register a price alert
+type CreatePriceAlertRequest struct {
+	ContractType     string  `json:"contract_type"`
+	StrikeHashRate   float64 `json:"strike_hash_rate"`
+	StartBlockHeight int64   `json:"start_block_height"`
+	EndBlockHeight   int64   `json:"end_block_height"`
+	Metric           string  `json:"metric"`
+	Direction        string  `json:"direction"`
+	Threshold        int64   `json:"threshold"`
+	Channel          string  `json:"channel"`
+	Target           string  `json:"target,omitempty"`
+}
+
+// ListPriceAlerts handles listing a user's price alerts
+func (h *Handler) ListPriceAlerts(w http.ResponseWriter, r *http.Request) {
+	if h.alertService == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Price alerts are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	alertList, err := h.alertService.List(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to list price alerts")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list price alerts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    alertList,
+	})
+}
+
+// CreatePriceAlert handles registering a price alert
+func (h *Handler) CreatePriceAlert(w http.ResponseWriter, r *http.Request) {
+	if h.alertService == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Price alerts are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	var req CreatePriceAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	alert := &models.PriceAlert{
+		UserID:           userID,
+		ContractType:     models.ContractType(strings.ToUpper(req.ContractType)),
+		StrikeHashRate:   req.StrikeHashRate,
+		StartBlockHeight: req.StartBlockHeight,
+		EndBlockHeight:   req.EndBlockHeight,
+		Metric:           models.AlertMetric(strings.ToUpper(req.Metric)),
+		Direction:        models.AlertDirection(strings.ToUpper(req.Direction)),
+		Threshold:        req.Threshold,
+		Channel:          models.AlertChannel(strings.ToUpper(req.Channel)),
+		Target:           strings.TrimSpace(req.Target),
+	}
+
+	if err := h.alertService.Create(r.Context(), alert); err != nil {
+		if errors.Is(err, alerts.ErrLimitReached) {
+			errorResponse(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    alert,
+	})
+}
+
+// CancelPriceAlert handles cancelling an active price alert
+func (h *Handler) CancelPriceAlert(w http.ResponseWriter, r *http.Request) {
+	if h.alertService == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Price alerts are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	alertID, err := uuid.Parse(chi.URLParam(r, "alertId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid alert ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	if err := h.alertService.Cancel(r.Context(), userID, alertID); err != nil {
+		storeErrorResponse(w, err, "Active price alert not found", "Failed to cancel price alert")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Price alert cancelled successfully",
+	})
+}
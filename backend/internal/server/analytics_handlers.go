@@ -0,0 +1,240 @@
+// internal/server/analytics_handlers.go
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract/hashrate"
+	"hashhedge/internal/db"
+	"hashhedge/internal/feeds"
+)
+
+// defaultVolatilityPercentiles are the bands returned when none are requested
+var defaultVolatilityPercentiles = []float64{5, 25, 50, 75, 95}
+
+// feedInfo describes a configured data feed and the series it has recorded
+type feedInfo struct {
+	feeds.FeedConfig
+	Series []string `json:"series"`
+}
+
+// WithFeeds enables the data feed analytics endpoints
+func (h *Handler) WithFeeds(sampler *feeds.Sampler, repo *db.FeedRepository) *Handler {
+	h.feedSampler = sampler
+	h.feedRepo = repo
+	return h
+}
+
+// ListFeeds handles listing the configured data feeds
+func (h *Handler) ListFeeds(w http.ResponseWriter, r *http.Request) {
+	if h.feedSampler == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Data feeds are not enabled")
+		return
+	}
+
+	configs := h.feedSampler.Feeds()
+	infos := make([]feedInfo, 0, len(configs))
+	for _, cfg := range configs {
+		series, err := h.feedRepo.ListSeries(r.Context(), cfg.Name)
+		if err != nil {
+			log.Error().Err(err).Str("feed", cfg.Name).Msg("Failed to list feed series")
+			errorResponse(w, http.StatusInternalServerError, "Failed to list feeds")
+			return
+		}
+		infos = append(infos, feedInfo{FeedConfig: cfg, Series: series})
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    infos,
+	})
+}
+
+// GetFeedObservations handles retrieving the recorded observations of a feed
+func (h *Handler) GetFeedObservations(w http.ResponseWriter, r *http.Request) {
+	if h.feedSampler == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Data feeds are not enabled")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if !h.feedSampler.HasFeed(name) {
+		errorResponse(w, http.StatusNotFound, "Feed not found")
+		return
+	}
+
+	since := time.Now().UTC().Add(-24 * time.Hour)
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid since, expected RFC3339")
+			return
+		}
+	}
+
+	limit := 500
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 5000 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	observations, err := h.feedRepo.ListObservations(r.Context(), name, r.URL.Query().Get("series"), since, limit)
+	if err != nil {
+		log.Error().Err(err).Str("feed", name).Msg("Failed to get feed observations")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get feed observations")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    observations,
+	})
+}
+
+// GetFeedVolatility handles computing realized volatility and percentile bands
+// of a feed series, e.g. the hash rate or block speed of a block_speed feed
+func (h *Handler) GetFeedVolatility(w http.ResponseWriter, r *http.Request) {
+	if h.feedSampler == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Data feeds are not enabled")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if !h.feedSampler.HasFeed(name) {
+		errorResponse(w, http.StatusNotFound, "Feed not found")
+		return
+	}
+
+	query := r.URL.Query()
+	series := query.Get("series")
+	if series == "" {
+		series = feeds.SeriesHashRate
+	}
+
+	since := time.Now().UTC().Add(-30 * 24 * time.Hour)
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid since, expected RFC3339")
+			return
+		}
+	}
+
+	window := 24
+	if windowStr := query.Get("window"); windowStr != "" {
+		var err error
+		window, err = strconv.Atoi(windowStr)
+		if err != nil || window < 3 || window > 1000 {
+			errorResponse(w, http.StatusBadRequest, "Invalid window, expected 3 to 1000 samples")
+			return
+		}
+	}
+
+	ps := defaultVolatilityPercentiles
+	if psStr := query.Get("percentiles"); psStr != "" {
+		ps = nil
+		for _, part := range strings.Split(psStr, ",") {
+			p, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || p < 0 || p > 100 {
+				errorResponse(w, http.StatusBadRequest, "Invalid percentiles, expected values from 0 to 100")
+				return
+			}
+			ps = append(ps, p)
+		}
+	}
+
+	observations, err := h.feedRepo.ListObservations(r.Context(), name, series, since, 5000)
+	if err != nil {
+		log.Error().Err(err).Str("feed", name).Msg("Failed to get feed observations")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get feed observations")
+		return
+	}
+
+	// Observations are returned newest first
+	samples := make([]hashrate.Sample, len(observations))
+	for i, o := range observations {
+		samples[len(observations)-1-i] = hashrate.Sample{Time: o.ObservedAt, Value: o.Value}
+	}
+
+	report, err := hashrate.Analyze(samples, window, ps)
+	if err != nil {
+		if errors.Is(err, hashrate.ErrNotEnoughSamples) {
+			errorResponse(w, http.StatusUnprocessableEntity, "Not enough samples to compute volatility")
+			return
+		}
+		log.Error().Err(err).Str("feed", name).Msg("Failed to compute volatility")
+		errorResponse(w, http.StatusInternalServerError, "Failed to compute volatility")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// GetOpenInterestHistory handles retrieving the recorded open interest of a market
+func (h *Handler) GetOpenInterestHistory(w http.ResponseWriter, r *http.Request) {
+	if !h.contractService.OpenInterestEnabled() {
+		errorResponse(w, http.StatusServiceUnavailable, "Open interest tracking is not enabled")
+		return
+	}
+
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	since := time.Now().UTC().Add(-30 * 24 * time.Hour)
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		var err error
+		since, err = time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid since, expected RFC3339")
+			return
+		}
+	}
+
+	limit := 500
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 5000 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	points, err := h.contractService.OpenInterestHistory(
+		r.Context(),
+		key.ContractType,
+		key.StrikeHashRate,
+		key.StartBlockHeight,
+		key.EndBlockHeight,
+		since,
+		limit,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get open interest history")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get open interest history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    points,
+	})
+}
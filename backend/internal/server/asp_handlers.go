@@ -0,0 +1,28 @@
+// internal/server/asp_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"hashhedge/pkg/ark"
+)
+
+// WithASPManager enables reporting the health of every configured ASP
+func (h *Handler) WithASPManager(manager *ark.Manager) *Handler {
+	h.aspManager = manager
+	return h
+}
+
+// ListASPs handles reporting which ASP is primary and the health and
+// latency of each
+func (h *Handler) ListASPs(w http.ResponseWriter, r *http.Request) {
+	if h.aspManager == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "ASP manager is not enabled")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.aspManager.Status(),
+	})
+}
@@ -0,0 +1,269 @@
+// internal/server/audit_handlers.go
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/auth"
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/usage"
+)
+
+// maxAuditBody bounds the request and response bodies kept in the audit log
+const maxAuditBody = 64 << 10
+
+// auditSnapshot loads the current state of an audited resource
+type auditSnapshot func(ctx context.Context, id uuid.UUID) (interface{}, error)
+
+// WithAuditLog enables recording contract and order mutations and the audit log endpoint
+func (h *Handler) WithAuditLog(repo *db.AuditRepository) *Handler {
+	h.auditLog = repo
+	return h
+}
+
+// cappedBuffer keeps the first maxAuditBody bytes written to it and drops
+// the rest, so large responses are not held in memory
+type cappedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+// Write implements io.Writer, always reporting the full length as written
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := maxAuditBody - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// auditJSON returns a body as JSON for the audit log: JSON bodies as they
+// are and anything else, including truncated JSON, as a string
+func auditJSON(body []byte, truncated bool) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if !truncated && json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// auditResourceID returns the resource ID at the start of the path below
+// the audited route, e.g. the order ID of /orders/{id}. Route parameters
+// are not resolved until after the middleware runs, so the path is read.
+func auditResourceID(r *http.Request) (uuid.UUID, bool) {
+	path := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePath != "" {
+		path = rctx.RoutePath
+	}
+
+	segment := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)[0]
+	id, err := uuid.Parse(segment)
+	return id, err == nil
+}
+
+// responseData returns the data of a successful JSON response and the ID
+// it contains, if any, for calls that create the resource
+func responseData(body []byte) (json.RawMessage, *uuid.UUID) {
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Data) == 0 {
+		return nil, nil
+	}
+
+	var created struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.Unmarshal(resp.Data, &created); err != nil || created.ID == uuid.Nil {
+		return resp.Data, nil
+	}
+	return resp.Data, &created.ID
+}
+
+// audited records every state-changing request to the routes it wraps in
+// the audit log, with snapshots of the resource before and after. Requests
+// that only read pass straight through.
+func (h *Handler) audited(resourceType string, snapshot auditSnapshot) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if h.auditLog == nil || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entry := &models.AuditEntry{
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				ResourceType: resourceType,
+				RemoteAddr:   r.RemoteAddr,
+				RequestID:    middleware.GetReqID(r.Context()),
+			}
+			if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+				entry.UserID = &userID
+			}
+			if key, ok := usage.APIKeyFromContext(r.Context()); ok {
+				entry.APIKeyID = &key.ID
+			}
+
+			if r.Body != nil {
+				var body cappedBuffer
+				if _, err := io.Copy(&body, r.Body); err == nil {
+					entry.Request = auditJSON(body.Bytes(), body.truncated)
+				}
+				r.Body.Close()
+				r.Body = io.NopCloser(bytes.NewReader(body.Bytes()))
+			}
+
+			id, hasID := auditResourceID(r)
+			if hasID {
+				entry.ResourceID = &id
+				entry.Before = h.snapshotJSON(r.Context(), snapshot, id)
+			}
+
+			var body cappedBuffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&body)
+
+			next.ServeHTTP(ww, r)
+
+			entry.Status = ww.Status()
+			if hasID {
+				entry.After = h.snapshotJSON(r.Context(), snapshot, id)
+			} else if entry.Status < http.StatusBadRequest && !body.truncated {
+				entry.After, entry.ResourceID = responseData(body.Bytes())
+			}
+
+			if err := h.auditLog.Create(r.Context(), entry); err != nil {
+				log.Error().
+					Err(err).
+					Str("method", entry.Method).
+					Str("path", entry.Path).
+					Msg("Failed to record audit log entry")
+			}
+		})
+	}
+}
+
+// snapshotJSON serializes the current state of a resource, or nothing if
+// it does not exist
+func (h *Handler) snapshotJSON(ctx context.Context, snapshot auditSnapshot, id uuid.UUID) json.RawMessage {
+	resource, err := snapshot(ctx, id)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// contractSnapshot loads a contract for the audit log
+func (h *Handler) contractSnapshot(ctx context.Context, id uuid.UUID) (interface{}, error) {
+	return h.contractService.GetContract(ctx, id)
+}
+
+// orderSnapshot loads an order for the audit log
+func (h *Handler) orderSnapshot(ctx context.Context, id uuid.UUID) (interface{}, error) {
+	return h.orderBook.GetOrderByID(ctx, id)
+}
+
+// ListAuditLog handles listing the audit log, oldest first, optionally by
+// user, resource and time range
+func (h *Handler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	if h.auditLog == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Audit log is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+	var filter db.AuditFilter
+
+	if s := query.Get("user_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		filter.UserID = &id
+	}
+
+	filter.ResourceType = query.Get("resource_type")
+	switch filter.ResourceType {
+	case "", models.AuditResourceContract, models.AuditResourceOrder:
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid resource type, expected contract or order")
+		return
+	}
+
+	if s := query.Get("resource_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid resource ID")
+			return
+		}
+		filter.ResourceID = &id
+	}
+
+	for name, t := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if s := query.Get(name); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				errorResponse(w, http.StatusBadRequest, "Invalid "+name+" time, expected RFC 3339")
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	entries, err := h.auditLog.List(r.Context(), filter, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list audit log")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list audit log")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    entries,
+	})
+}
@@ -0,0 +1,205 @@
+// internal/server/auth_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/auth"
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/usage"
+)
+
+// WithAuth enables registration, login and bearer token authentication
+func (h *Handler) WithAuth(service *auth.Service) *Handler {
+	h.auth = service
+	return h
+}
+
+// LoginRequest represents the credentials of a login
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest represents the refresh token exchanged for new tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// loginResponse is the user who logged in and their tokens
+type loginResponse struct {
+	User   *models.User    `json:"user"`
+	Tokens *auth.TokenPair `json:"tokens"`
+}
+
+// authenticate identifies the user of a request from a bearer access token,
+// falling back to the owner of the request's API key. Requests with neither
+// pass through anonymously; an invalid token is rejected.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || h.auth == nil {
+				errorResponse(w, http.StatusUnauthorized, "Unsupported authorization")
+				return
+			}
+
+			userID, err := h.auth.Authenticate(token)
+			if err != nil {
+				errorResponse(w, http.StatusUnauthorized, "Invalid or expired access token")
+				return
+			}
+			ctx = auth.WithUserID(ctx, userID)
+		} else if key, ok := usage.APIKeyFromContext(ctx); ok {
+			ctx = auth.WithUserID(ctx, key.UserID)
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireUser rejects requests that are not authenticated
+func (h *Handler) requireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := h.viewer(r); !ok {
+			errorResponse(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdmin rejects requests that are not authenticated, and those of
+// users who are not configured as admins
+func (h *Handler) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := h.viewer(r); !ok {
+			errorResponse(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		if !h.isAdmin(r) {
+			errorResponse(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Register handles creating a user account
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	if h.auth == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Authentication is not enabled")
+		return
+	}
+
+	var req auth.Registration
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.auth.Register(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, db.ErrConflict) {
+			errorResponse(w, http.StatusConflict, "Username or email is already registered")
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidRegistration) {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Error().Err(err).Msg("Failed to register user")
+		errorResponse(w, http.StatusInternalServerError, "Failed to register user")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    user,
+	})
+}
+
+// Login handles exchanging a username and password for tokens
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	if h.auth == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Authentication is not enabled")
+		return
+	}
+
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, tokens, err := h.auth.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			errorResponse(w, http.StatusUnauthorized, "Invalid username or password")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to log in")
+		errorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    loginResponse{User: user, Tokens: tokens},
+	})
+}
+
+// RefreshTokens handles exchanging a refresh token for new tokens
+func (h *Handler) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	if h.auth == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Authentication is not enabled")
+		return
+	}
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tokens, err := h.auth.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			errorResponse(w, http.StatusUnauthorized, "Invalid or expired refresh token")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to refresh tokens")
+		errorResponse(w, http.StatusInternalServerError, "Failed to refresh tokens")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    tokens,
+	})
+}
+
+// GetCurrentUser handles retrieving the authenticated user
+func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, _ := h.viewer(r)
+
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get current user")
+		storeErrorResponse(w, err, "User not found", "Failed to get user")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    user,
+	})
+}
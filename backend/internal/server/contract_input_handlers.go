@@ -0,0 +1,112 @@
+// internal/server/contract_input_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/models"
+)
+
+// AddContractInputRequest represents a UTXO or VTXO locked in a contract's setup script
+type AddContractInputRequest struct {
+	Source    models.InputSource `json:"source"`
+	TxID      string             `json:"txid"`
+	Vout      uint32             `json:"vout"`
+	Value     int64              `json:"value"`
+	LeafIndex *int               `json:"leaf_index,omitempty"`
+}
+
+// ListContractInputs handles retrieving the inputs funding a contract stage
+func (h *Handler) ListContractInputs(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	stage := models.InputStage(r.URL.Query().Get("stage"))
+	if stage == "" {
+		stage = models.InputStageSetup
+	}
+	if stage != models.InputStageSetup && stage != models.InputStageFinal {
+		errorResponse(w, http.StatusBadRequest, "stage must be setup or final")
+		return
+	}
+
+	inputs, err := h.contractService.ListContractInputs(r.Context(), contractID, stage)
+	if err != nil {
+		if errors.Is(err, contract.ErrInputsNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Multi-input funding is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to list contract inputs")
+		storeErrorResponse(w, err, "Contract not found", "Failed to list contract inputs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    inputs,
+	})
+}
+
+// AddContractInput handles recording a separately funded input of a contract
+func (h *Handler) AddContractInput(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	var req AddContractInputRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Source == "" {
+		req.Source = models.InputSourceUTXO
+	}
+
+	input := &models.ContractInput{
+		ContractID: contractID,
+		Stage:      models.InputStageSetup,
+		Source:     req.Source,
+		TxID:       req.TxID,
+		Vout:       req.Vout,
+		Value:      req.Value,
+		LeafIndex:  req.LeafIndex,
+	}
+
+	if err := h.contractService.AddContractInput(r.Context(), input); err != nil {
+		switch {
+		case errors.Is(err, contract.ErrInputsNotEnabled):
+			errorResponse(w, http.StatusServiceUnavailable, "Multi-input funding is not enabled")
+			return
+		case errors.Is(err, contract.ErrInputNotFound), errors.Is(err, contract.ErrInputMismatch):
+			errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to add contract input")
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    input,
+	})
+}
@@ -0,0 +1,73 @@
+// internal/server/deadlines.go
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/deadlines"
+	"hashhedge/internal/models"
+)
+
+// contractResponse is a contract with its deadline metadata and its
+// amounts in fiat, keyed by currency
+type contractResponse struct {
+	*models.Contract
+	Deadlines deadlines.Contract      `json:"deadlines"`
+	Pinned    bool                    `json:"pinned,omitempty"`
+	Fiat      map[string]contractFiat `json:"fiat,omitempty"`
+}
+
+// orderResponse is an order with its deadline metadata and its amounts in
+// fiat, keyed by currency
+type orderResponse struct {
+	*models.Order
+	Deadlines deadlines.Order      `json:"deadlines"`
+	Fiat      map[string]orderFiat `json:"fiat,omitempty"`
+}
+
+// tipHeight returns the current chain height, or 0 if it cannot be fetched
+func (h *Handler) tipHeight(ctx context.Context) int64 {
+	height, err := h.contractService.CurrentBlockHeight(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to get current block height for deadlines")
+		return 0
+	}
+	return height
+}
+
+// withContractDeadlines attaches deadline metadata and fiat amounts to
+// contracts
+func (h *Handler) withContractDeadlines(ctx context.Context, contracts ...*models.Contract) []contractResponse {
+	tip := h.tipHeight(ctx)
+	now := time.Now().UTC()
+	prices := h.fiatPrices(ctx)
+
+	responses := make([]contractResponse, 0, len(contracts))
+	for _, c := range contracts {
+		responses = append(responses, contractResponse{
+			Contract:  c,
+			Deadlines: deadlines.ForContract(c, tip, now),
+			Fiat:      contractFiatAmounts(c, prices),
+		})
+	}
+	return responses
+}
+
+// withOrderDeadlines attaches deadline metadata and fiat amounts to orders
+func (h *Handler) withOrderDeadlines(ctx context.Context, orders ...*models.Order) []orderResponse {
+	now := time.Now().UTC()
+	prices := h.fiatPrices(ctx)
+
+	responses := make([]orderResponse, 0, len(orders))
+	for _, o := range orders {
+		responses = append(responses, orderResponse{
+			Order:     o,
+			Deadlines: deadlines.ForOrder(o, now),
+			Fiat:      orderFiatAmounts(o.Price, o.Quantity, prices),
+		})
+	}
+	return responses
+}
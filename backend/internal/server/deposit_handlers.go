@@ -0,0 +1,92 @@
+// internal/server/deposit_handlers.go
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/wallet"
+)
+
+// WithWallet enables deposit addresses and crediting of on-chain deposits
+func (h *Handler) WithWallet(service *wallet.Service) *Handler {
+	h.wallet = service
+	return h
+}
+
+// depositUser returns the requester of a deposit request, checking that
+// deposits are enabled
+func (h *Handler) depositUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.wallet == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Deposits are not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.requester(w, r)
+}
+
+// GetDepositAddress handles retrieving the requester's deposit address,
+// deriving one on first use
+func (h *Handler) GetDepositAddress(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.depositUser(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := h.wallet.DepositAddress(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get deposit address")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get deposit address")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    address,
+	})
+}
+
+// ListDeposits handles listing the requester's deposits, newest first
+func (h *Handler) ListDeposits(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.depositUser(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	deposits, err := h.wallet.Deposits(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list deposits")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list deposits")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    deposits,
+	})
+}
@@ -0,0 +1,109 @@
+// internal/server/evidence_handlers.go
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/timestamping"
+)
+
+// WithTimestamping enables verifying and downloading the Bitcoin timestamp
+// proofs of settlement evidence
+func (h *Handler) WithTimestamping(anchorer *timestamping.Anchorer) *Handler {
+	h.anchorer = anchorer
+	return h
+}
+
+// GetSettlementEvidence handles retrieving the chain state a contract's
+// settlement was decided on and its anchoring status
+func (h *Handler) GetSettlementEvidence(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	evidence, err := h.contractService.GetSettlementEvidence(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrEvidenceNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Settlement evidence is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get settlement evidence")
+		storeErrorResponse(w, err, "Settlement evidence not found", "Failed to get settlement evidence")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    evidence,
+	})
+}
+
+// VerifySettlementEvidence handles checking a contract's settlement evidence
+// against its digest and the Bitcoin blocks its proof commits to
+func (h *Handler) VerifySettlementEvidence(w http.ResponseWriter, r *http.Request) {
+	if h.anchorer == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Timestamping is not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	verification, err := h.anchorer.Verify(r.Context(), contractID)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to verify settlement evidence")
+		storeErrorResponse(w, err, "Settlement evidence not found", "Failed to verify settlement evidence")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    verification,
+	})
+}
+
+// DownloadEvidenceProof handles downloading a contract's settlement evidence
+// proof as an .ots file for verification with the OpenTimestamps client
+func (h *Handler) DownloadEvidenceProof(w http.ResponseWriter, r *http.Request) {
+	if h.anchorer == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Timestamping is not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	proof, err := h.anchorer.ProofFile(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, timestamping.ErrNoProof) {
+			errorResponse(w, http.StatusNotFound, "Settlement evidence has not been timestamped yet")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get settlement evidence proof")
+		storeErrorResponse(w, err, "Settlement evidence not found", "Failed to get settlement evidence proof")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-evidence.ots", contractID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(proof)
+}
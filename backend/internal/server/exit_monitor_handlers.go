@@ -0,0 +1,65 @@
+// internal/server/exit_monitor_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// GetExitMonitor handles reporting the state of the dead man's switch that
+// exits every active contract on chain when the ASP stops responding
+func (h *Handler) GetExitMonitor(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.contractService.ExitMonitorStatus(),
+	})
+}
+
+// ResumeOffChain handles an operator re-arming a tripped switch once the ASP
+// is back, so that setups and swaps use it again
+func (h *Handler) ResumeOffChain(w http.ResponseWriter, r *http.Request) {
+	if !h.contractService.OnChainOnly() {
+		errorResponse(w, http.StatusConflict, "The service is not in on-chain only mode")
+		return
+	}
+
+	h.contractService.ResumeOffChain()
+	log.Warn().Msg("Operator resumed off-chain operation through the ASP")
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.contractService.ExitMonitorStatus(),
+	})
+}
+
+// ListExitTransactions handles listing a page of the emergency exit
+// transactions prepared for a contract, newest first
+func (h *Handler) ListExitTransactions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	after, limit, ok := parsePage(w, r, 50, maxPageSize)
+	if !ok {
+		return
+	}
+
+	txs, page, err := h.contractService.ListExitTransactions(r.Context(), contractID, after, limit)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to list exit transactions")
+		storeErrorResponse(w, err, "Contract not found", "Failed to list exit transactions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    txs,
+		Page:    page,
+	})
+}
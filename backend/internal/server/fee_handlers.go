@@ -0,0 +1,113 @@
+// internal/server/fee_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+)
+
+// BumpSettlementFeeRequest represents a party's offer to pay a priority fee rate
+type BumpSettlementFeeRequest struct {
+	PubKey  string  `json:"pub_key"`
+	FeeRate float64 `json:"fee_rate"` // sat/vB
+}
+
+// GetFeeStatus handles retrieving the fee estimate and whether settlements are being deferred
+func (h *Handler) GetFeeStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.contractService.GetFeeStatus(r.Context())
+	if err != nil {
+		if errors.Is(err, contract.ErrFeePolicyNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Fee stress mode is not enabled")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to get fee status")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get fee status")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// GetSettlementDeferral handles retrieving the deferral of a contract's settlement
+func (h *Handler) GetSettlementDeferral(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	deferral, err := h.contractService.GetSettlementDeferral(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrFeePolicyNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Fee stress mode is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get settlement deferral")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get settlement deferral")
+		return
+	}
+
+	if deferral == nil {
+		errorResponse(w, http.StatusNotFound, "Settlement has not been deferred")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    deferral,
+	})
+}
+
+// BumpSettlementFee handles a party offering a priority fee rate to release
+// its deferred settlement, paid from the payout of the key they hold
+func (h *Handler) BumpSettlementFee(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+	userID, _ := h.viewer(r)
+
+	var req BumpSettlementFeeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	deferral, err := h.contractService.BumpSettlementFee(r.Context(), userID, contractID, req.PubKey, req.FeeRate)
+	if err != nil {
+		switch {
+		case errors.Is(err, contract.ErrFeePolicyNotEnabled):
+			errorResponse(w, http.StatusServiceUnavailable, "Fee stress mode is not enabled")
+		case errors.Is(err, contract.ErrNotDeferred):
+			errorResponse(w, http.StatusConflict, "Settlement is not deferred")
+		case errors.Is(err, contract.ErrNotParty):
+			errorResponse(w, http.StatusForbidden, "Access denied")
+		default:
+			log.Error().Err(err).Str("contractID", id).Msg("Failed to bump settlement fee")
+			errorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    deferral,
+	})
+}
@@ -0,0 +1,102 @@
+// internal/server/fiat.go
+package server
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+	"hashhedge/pkg/priceindex"
+)
+
+// satsPerBTC converts satoshi amounts to bitcoin
+const satsPerBTC = 100_000_000
+
+// contractFiat is a contract's satoshi amounts in one fiat currency
+type contractFiat struct {
+	BTCPrice     float64 `json:"btc_price"`
+	ContractSize float64 `json:"contract_size"`
+	Premium      float64 `json:"premium"`
+}
+
+// orderFiat is an order's price, and its price times its quantity, in one
+// fiat currency
+type orderFiat struct {
+	BTCPrice float64 `json:"btc_price"`
+	Price    float64 `json:"price"`
+	Notional float64 `json:"notional"`
+}
+
+// WithPriceIndex shows contract and order amounts in the quote currencies of
+// pairs alongside satoshis. Pairs not priced in BTC are ignored.
+func (h *Handler) WithPriceIndex(index *priceindex.Index, pairs []priceindex.Pair) *Handler {
+	h.priceIndex = index
+	h.pricePairs = nil
+	for _, pair := range pairs {
+		if pair.Base == "BTC" {
+			h.pricePairs = append(h.pricePairs, pair)
+		}
+	}
+	return h
+}
+
+// fiatPrices returns the BTC price in each configured currency. Currencies
+// whose exchanges cannot be priced are left out rather than failing the
+// request.
+func (h *Handler) fiatPrices(ctx context.Context) map[string]float64 {
+	if h.priceIndex == nil {
+		return nil
+	}
+
+	prices := make(map[string]float64, len(h.pricePairs))
+	for _, pair := range h.pricePairs {
+		quote, err := h.priceIndex.Price(ctx, pair)
+		if err != nil {
+			log.Warn().Err(err).Str("pair", pair.String()).Msg("Failed to get fiat price")
+			continue
+		}
+		prices[pair.Quote] = quote.Price
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+	return prices
+}
+
+// satsToFiat converts a satoshi amount at a BTC price, to the cent
+func satsToFiat(sats int64, btcPrice float64) float64 {
+	return float64(int64(float64(sats)*btcPrice/satsPerBTC*100+0.5)) / 100
+}
+
+// contractFiatAmounts converts a contract's amounts at each price
+func contractFiatAmounts(c *models.Contract, prices map[string]float64) map[string]contractFiat {
+	if len(prices) == 0 {
+		return nil
+	}
+	amounts := make(map[string]contractFiat, len(prices))
+	for currency, price := range prices {
+		amounts[currency] = contractFiat{
+			BTCPrice:     price,
+			ContractSize: satsToFiat(c.ContractSize, price),
+			Premium:      satsToFiat(c.Premium, price),
+		}
+	}
+	return amounts
+}
+
+// orderFiatAmounts converts an order's price and notional at each price
+func orderFiatAmounts(price int64, quantity int, prices map[string]float64) map[string]orderFiat {
+	if len(prices) == 0 {
+		return nil
+	}
+	amounts := make(map[string]orderFiat, len(prices))
+	for currency, btcPrice := range prices {
+		amounts[currency] = orderFiat{
+			BTCPrice: btcPrice,
+			Price:    satsToFiat(price, btcPrice),
+			Notional: satsToFiat(price*int64(quantity), btcPrice),
+		}
+	}
+	return amounts
+}
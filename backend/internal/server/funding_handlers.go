@@ -0,0 +1,137 @@
+// internal/server/funding_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+)
+
+// SubmitFundingRequest represents a party's funding PSBT for contract setup
+type SubmitFundingRequest struct {
+	PubKey string `json:"pub_key"`
+	PSBT   string `json:"psbt"`
+}
+
+// GetContractFunding handles retrieving the funding progress of a contract
+func (h *Handler) GetContractFunding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	status, err := h.contractService.GetFundingStatus(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrFundingNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Funding tracking is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get contract funding")
+		storeErrorResponse(w, err, "Contract not found", "Failed to get contract funding")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// SubmitContractFunding handles a party submitting its funding PSBT
+func (h *Handler) SubmitContractFunding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+	userID, _ := h.viewer(r)
+
+	var req SubmitFundingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	status, err := h.contractService.SubmitFundingPSBT(r.Context(), userID, contractID, req.PubKey, req.PSBT)
+	if err != nil {
+		switch {
+		case errors.Is(err, contract.ErrFundingNotEnabled):
+			errorResponse(w, http.StatusServiceUnavailable, "Funding tracking is not enabled")
+		case errors.Is(err, contract.ErrNotParty):
+			errorResponse(w, http.StatusForbidden, "Access denied")
+		case errors.Is(err, contract.ErrInvalidFundingSignature):
+			errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			log.Error().Err(err).Str("contractID", id).Msg("Failed to submit funding PSBT")
+			errorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// GetContractTimeline handles retrieving the ordered lifecycle events of a contract
+func (h *Handler) GetContractTimeline(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	timeline, err := h.contractService.GetContractTimeline(r.Context(), contractID)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get contract timeline")
+		storeErrorResponse(w, err, "Contract not found", "Failed to get contract timeline")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    timeline,
+	})
+}
+
+// GetContractStatusHistory handles listing every status a contract moved
+// through, oldest first, for audit
+func (h *Handler) GetContractStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	history, err := h.contractService.StatusHistory(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrTransitionHistoryNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Contract status history is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get contract status history")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get contract status history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    history,
+	})
+}
@@ -2,8 +2,10 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,10 +15,37 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	
+	"hashhedge/internal/alerts"
+	"hashhedge/internal/auth"
 	"hashhedge/internal/contract"
+	"hashhedge/internal/contract/hashrate"
 	"hashhedge/internal/db"
+	"hashhedge/internal/feeds"
+	"hashhedge/internal/jobs"
+	"hashhedge/internal/ledger"
+	"hashhedge/internal/margin"
+	"hashhedge/internal/marketdata"
+	"hashhedge/internal/marketplace"
 	"hashhedge/internal/models"
+	"hashhedge/internal/notifications"
 	"hashhedge/internal/orderbook"
+	"hashhedge/internal/positions"
+	"hashhedge/internal/pricing"
+	"hashhedge/internal/privacy"
+	"hashhedge/internal/push"
+	"hashhedge/internal/ratelimit"
+	"hashhedge/internal/research"
+	"hashhedge/internal/rollover"
+	"hashhedge/internal/timestamping"
+	"hashhedge/internal/tradereport"
+	"hashhedge/internal/usage"
+	"hashhedge/internal/wallet"
+	"hashhedge/internal/watchtower"
+	"hashhedge/internal/webhooks"
+	"hashhedge/internal/websocket"
+	"hashhedge/pkg/ark"
+	"hashhedge/pkg/bitcoin"
+	"hashhedge/pkg/priceindex"
 )
 
 // Handler contains all HTTP handlers
@@ -24,6 +53,49 @@ type Handler struct {
 	contractService *contract.Service
 	orderBook       *orderbook.OrderBook
 	userRepo        *db.UserRepository
+	jobRunner       *jobs.Runner
+	watchlistRepo   *db.WatchlistRepository
+	alertService    *alerts.Service
+	wsServer        *websocket.Server
+	wsCtx           context.Context
+	feedSampler     *feeds.Sampler
+	feedRepo        *db.FeedRepository
+	pushService     *push.Service
+	usageTracker    *usage.Tracker
+	apiKeyRepo      *db.APIKeyRepository
+	rollover        *rollover.Scheduler
+	anonymizer      *privacy.Anonymizer
+	marketCache     *marketdata.Cache
+	marketCfg       marketdata.Config
+	hashRateIndex   *hashrate.HashRateCalculator
+	auth            *auth.Service
+	schedule        map[string]time.Duration
+	snapshotRepo    *db.SnapshotRepository
+	liquidity       *marketdata.LiquidityMonitor
+	tickers         *marketdata.TickerBook
+	anchorer        *timestamping.Anchorer
+	watchtowers     *watchtower.Service
+	settlements     *db.SettlementAttemptRepository
+	protections     *db.MarketMakerProtectionRepository
+	research        *research.Service
+	researchDumps   *research.Dumper
+	trades          *db.TradeRepository
+	positions       *positions.Service
+	tradeReports    *tradereport.Exporter
+	priceIndex      *priceindex.Index
+	pricePairs      []priceindex.Pair
+	auditLog        *db.AuditRepository
+	margin          *margin.Engine
+	ledger          *ledger.Service
+	wallet          *wallet.Service
+	withdrawals     *wallet.Withdrawals
+	marketplace     *marketplace.Service
+	pricing         *pricing.Service
+	notifications   *notifications.Service
+	rateLimiter     *ratelimit.Limiter
+	webhooks        *webhooks.Service
+	bitcoinHealth   *bitcoin.Client
+	aspManager      *ark.Manager
 }
 
 // NewHandler creates a new Handler
@@ -40,6 +112,32 @@ type response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Page is set on paged listings
+	Page *models.PageInfo `json:"page,omitempty"`
+}
+
+// maxPageSize bounds the records returned by one page of a listing
+const maxPageSize = 500
+
+// parsePage parses the cursor and limit query parameters of a paged listing,
+// responding with an error when either is invalid
+func parsePage(w http.ResponseWriter, r *http.Request, defaultLimit, maxLimit int) (models.Cursor, int, bool) {
+	after, err := models.ParseCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid cursor")
+		return models.Cursor{}, 0, false
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid limit, expected 1 to %d", maxLimit))
+			return models.Cursor{}, 0, false
+		}
+	}
+
+	return after, limit, true
 }
 
 // respondJSON sends a JSON response
@@ -61,18 +159,100 @@ func errorResponse(w http.ResponseWriter, statusCode int, message string) {
 	})
 }
 
-// validateUserPermissions validates if the user has permissions to access a resource
-// For MVP, we'll do simple validation, but this should be expanded for production
-func (h *Handler) validateUserPermissions(r *http.Request, resourceUserID uuid.UUID) bool {
-	// In a real implementation, this would check JWT tokens, session data, etc.
-	// For MVP, we'll assume the user ID in the request is valid
-	// and compare it with the resource's user ID if applicable
-	
-	// Get user ID from request context/headers/etc.
-	// For MVP, we'll just return true
+// storeErrorResponse sends the error response for a failed repository call:
+// 404 with notFound when nothing matched, 409 on a conflicting write, and 500
+// with failed otherwise so database outages are not reported as missing resources
+func storeErrorResponse(w http.ResponseWriter, err error, notFound, failed string) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, notFound)
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, "Request conflicts with the current state")
+	default:
+		errorResponse(w, http.StatusInternalServerError, failed)
+	}
+}
+
+// notMatcherResponse sends 503 for an order of a market another instance
+// matches, so the client retries through the load balancer, and reports
+// whether it did
+func notMatcherResponse(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, orderbook.ErrNotMatcher) {
+		return false
+	}
+	w.Header().Set("Retry-After", "1")
+	errorResponse(w, http.StatusServiceUnavailable, "This market is matched by another instance, retry shortly")
 	return true
 }
 
+// validateUserPermissions reports whether the authenticated user of a request
+// owns a resource
+func (h *Handler) validateUserPermissions(r *http.Request, resourceUserID uuid.UUID) bool {
+	userID, ok := h.viewer(r)
+	return ok && userID == resourceUserID
+}
+
+// pathUser parses the user ID route parameter of a request, responding with
+// an error unless the requester is that user or, when admin is set, an admin
+func (h *Handler) pathUser(w http.ResponseWriter, r *http.Request, admin bool) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return uuid.Nil, false
+	}
+
+	allowed := h.validateUserPermissions(r, userID)
+	if admin {
+		allowed = h.isAdmin(r)
+	}
+	if !allowed {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// requester returns the authenticated user of a request, responding with an
+// error when there is none
+func (h *Handler) requester(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// requireParty retrieves a contract the authenticated user of a request is a
+// party to, responding with an error when they are not. Admins act on every
+// contract.
+func (h *Handler) requireParty(w http.ResponseWriter, r *http.Request, contractID uuid.UUID) (*models.Contract, bool) {
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return nil, false
+	}
+
+	var c *models.Contract
+	var err error
+	if h.isAdmin(r) {
+		c, err = h.contractService.GetContract(r.Context(), contractID)
+	} else {
+		c, err = h.contractService.CheckParty(r.Context(), contractID, userID)
+	}
+	if errors.Is(err, contract.ErrNotParty) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return nil, false
+	}
+	if err != nil {
+		storeErrorResponse(w, err, "Contract not found", "Failed to get contract")
+		return nil, false
+	}
+
+	return c, true
+}
+
 // sanitizeInput performs basic input sanitization
 func sanitizeInput(input string) string {
 	// Simple sanitization for MVP - should be expanded for production
@@ -94,43 +274,24 @@ func (h *Handler) GetContract(w http.ResponseWriter, r *http.Request) {
 	contract, err := h.contractService.GetContract(r.Context(), contractID)
 	if err != nil {
 		log.Error().Err(err).Str("contractID", id).Msg("Failed to get contract")
-		errorResponse(w, http.StatusNotFound, "Contract not found")
+		storeErrorResponse(w, err, "Contract not found", "Failed to get contract")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, response{
 		Success: true,
-		Data:    contract,
+		Data:    h.publicContracts(r, h.withContractDeadlines(r.Context(), contract))[0],
 	})
 }
 
-// ListActiveContracts handles listing all active contracts
+// ListActiveContracts handles listing a page of active contracts, newest first
 func (h *Handler) ListActiveContracts(w http.ResponseWriter, r *http.Request) {
-	// Parse pagination parameters
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	limit := 50
-	if limitStr != "" {
-		var err error
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit <= 0 {
-			errorResponse(w, http.StatusBadRequest, "Invalid limit")
-			return
-		}
-	}
-
-	offset := 0
-	if offsetStr != "" {
-		var err error
-		offset, err = strconv.Atoi(offsetStr)
-		if err != nil || offset < 0 {
-			errorResponse(w, http.StatusBadRequest, "Invalid offset")
-			return
-		}
+	after, limit, ok := parsePage(w, r, 50, maxPageSize)
+	if !ok {
+		return
 	}
 
-	contracts, err := h.contractService.ListActiveContracts(r.Context(), limit, offset)
+	contracts, page, err := h.contractService.ListActiveContractsPage(r.Context(), after, limit)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to list active contracts")
 		errorResponse(w, http.StatusInternalServerError, "Failed to list active contracts")
@@ -139,7 +300,8 @@ func (h *Handler) ListActiveContracts(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, response{
 		Success: true,
-		Data:    contracts,
+		Data:    h.pinWatchedContracts(r, h.publicContracts(r, h.withContractDeadlines(r.Context(), contracts...))),
+		Page:    page,
 	})
 }
 
@@ -154,6 +316,14 @@ type CreateContractRequest struct {
 	Premium          int64     `json:"premium"`
 	BuyerPubKey      string    `json:"buyer_pub_key"`
 	SellerPubKey     string    `json:"seller_pub_key"`
+	// Notional defaults to quoting the whole contract as one unit
+	Notional *models.Notional `json:"notional,omitempty"`
+	// TimeMode defaults to ABSOLUTE. RELATIVE contracts run for
+	// RelativeBlocks and RelativeSeconds from their setup confirmation, and
+	// the heights and target timestamp above are only an estimate until then.
+	TimeMode        models.ContractTimeMode `json:"time_mode,omitempty"`
+	RelativeBlocks  int64                   `json:"relative_blocks,omitempty"`
+	RelativeSeconds int64                   `json:"relative_seconds,omitempty"`
 }
 
 // CreateContract handles creating a new contract directly (not through order matching)
@@ -164,22 +334,8 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate request
-	if req.ContractType != "CALL" && req.ContractType != "PUT" {
-		errorResponse(w, http.StatusBadRequest, "Invalid contract type")
-		return
-	}
-
-	if req.StrikeHashRate <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Strike hash rate must be positive")
-		return
-	}
-
-	if req.StartBlockHeight <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Start block height must be positive")
-		return
-	}
-
+	// The schema checks each field; these rules relate fields to each other
+	// and to the clock
 	if req.EndBlockHeight <= req.StartBlockHeight {
 		errorResponse(w, http.StatusBadRequest, "End block height must be greater than start block height")
 		return
@@ -190,13 +346,27 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.ContractSize <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Contract size must be positive")
-		return
+	notional := models.DefaultNotional
+	if req.Notional != nil {
+		notional = *req.Notional
+		if notional.SettlementCurrency == "" {
+			notional.SettlementCurrency = models.SettlementCurrencyBTC
+		}
+		if err := notional.Validate(); err != nil {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
-	if req.Premium < 0 {
-		errorResponse(w, http.StatusBadRequest, "Premium cannot be negative")
+	switch req.TimeMode {
+	case "", models.ContractTimeModeAbsolute:
+	case models.ContractTimeModeRelative:
+		if err := models.ValidateRelativeTimelock(req.RelativeBlocks, req.RelativeSeconds); err != nil {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid time mode")
 		return
 	}
 
@@ -227,6 +397,7 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 		req.TargetTimestamp,
 		req.ContractSize,
 		req.Premium,
+		notional,
 		req.BuyerPubKey,
 		req.SellerPubKey,
 	)
@@ -236,6 +407,15 @@ func (h *Handler) CreateContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.TimeMode == models.ContractTimeModeRelative {
+		err := h.contractService.SetRelativeTimelock(r.Context(), contract, req.RelativeBlocks, req.RelativeSeconds)
+		if err != nil {
+			log.Error().Err(err).Str("contractID", contract.ID.String()).Msg("Failed to set relative timelock")
+			errorResponse(w, http.StatusInternalServerError, "Failed to create contract")
+			return
+		}
+	}
+
 	respondJSON(w, http.StatusCreated, response{
 		Success: true,
 		Data:    contract,
@@ -251,22 +431,21 @@ func (h *Handler) CancelContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the contract first to check permissions
-	contract, err := h.contractService.GetContract(r.Context(), contractID)
-	if err != nil {
-		errorResponse(w, http.StatusNotFound, "Contract not found")
+	// Only a party to the contract may cancel it
+	if _, ok := h.requireParty(w, r, contractID); !ok {
 		return
 	}
 
-	// In a real implementation, check if the user has permission to cancel this contract
-	// For MVP, we'll skip detailed permission checks
-
 	err = h.contractService.CancelContract(r.Context(), contractID)
 	if err != nil {
 		if errors.Is(err, errors.New("contract cannot be cancelled")) {
 			errorResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.Is(err, contract.ErrInvalidTransition) {
+			errorResponse(w, http.StatusConflict, err.Error())
+			return
+		}
 		
 		log.Error().Err(err).Str("contractID", id).Msg("Failed to cancel contract")
 		errorResponse(w, http.StatusInternalServerError, "Failed to cancel contract")
@@ -285,6 +464,9 @@ type SetupContractRequest struct {
 	SellerInputs  []string `json:"seller_inputs"`
 }
 
+// maxSetupInputs caps the funding transactions of one setup request
+const maxSetupInputs = 50
+
 // SetupContract handles creating the setup transaction for a contract
 func (h *Handler) SetupContract(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -294,6 +476,10 @@ func (h *Handler) SetupContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
 	var req SetupContractRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -301,10 +487,16 @@ func (h *Handler) SetupContract(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate inputs
-	if len(req.BuyerInputs) == 0 || len(req.SellerInputs) == 0 {
-		errorResponse(w, http.StatusBadRequest, "Both buyer and seller inputs are required")
+	if len(req.BuyerInputs)+len(req.SellerInputs) > maxSetupInputs {
+		errorResponse(w, http.StatusBadRequest, "Too many setup inputs")
 		return
 	}
+	for _, input := range append(append([]string{}, req.BuyerInputs...), req.SellerInputs...) {
+		if _, err := bitcoin.ParseTransactionHex(input, bitcoin.DefaultParseLimits); err != nil {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
 
 	// Generate setup transaction
 	tx, err := h.contractService.GenerateSetupTransaction(
@@ -334,6 +526,10 @@ func (h *Handler) GenerateFinalTx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
 	// Generate final transaction
 	tx, err := h.contractService.GenerateFinalTransaction(r.Context(), contractID)
 	if err != nil {
@@ -357,6 +553,10 @@ func (h *Handler) SettleContract(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
 	// Check if contract can be settled
 	canSettle, reason, err := h.contractService.CheckSettlementConditions(r.Context(), contractID)
 	if err != nil {
@@ -372,6 +572,17 @@ func (h *Handler) SettleContract(w http.ResponseWriter, r *http.Request) {
 
 	// Settle the contract
 	tx, buyerWins, err := h.contractService.SettleContract(r.Context(), contractID)
+	if errors.Is(err, contract.ErrSettlementDeferred) {
+		respondJSON(w, http.StatusAccepted, response{
+			Success: true,
+			Data:    "Settlement deferred until fees fall; offer a priority fee to settle sooner",
+		})
+		return
+	}
+	if errors.Is(err, contract.ErrAttestationRequired) {
+		errorResponse(w, http.StatusConflict, "Contract settles on an oracle attestation, submit it first")
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("contractID", id).Msg("Failed to settle contract")
 		errorResponse(w, http.StatusInternalServerError, "Failed to settle contract")
@@ -401,27 +612,28 @@ func (h *Handler) BroadcastTx(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
 	var req BroadcastTxRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		errorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	// Validate request
-	if req.TxID == "" {
-		errorResponse(w, http.StatusBadRequest, "Transaction ID is required")
-		return
-	}
-	
-	// Parse the transaction ID
 	txID, err := uuid.Parse(req.TxID)
 	if err != nil {
-		errorResponse(w, http.StatusBadRequest, "Invalid transaction ID format")
+		errorResponse(w, http.StatusBadRequest, "Invalid transaction ID")
 		return
 	}
 
 	// Broadcast the transaction
 	broadcastTxID, err := h.contractService.BroadcastTransaction(r.Context(), contractID, txID)
+	if errors.Is(err, bitcoin.ErrMalformedTransaction) {
+		errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("contractID", id).Str("txID", req.TxID).Msg("Failed to broadcast transaction")
 		errorResponse(w, http.StatusInternalServerError, "Failed to broadcast transaction")
@@ -458,7 +670,7 @@ func (h *Handler) SwapContractParticipant(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Validate and sanitize request
+	// Sanitize request; sanitizing may leave a key of only whitespace empty
 	req.CurrentPubKey = sanitizeInput(req.CurrentPubKey)
 	req.NewPubKey = sanitizeInput(req.NewPubKey)
 	
@@ -466,25 +678,33 @@ func (h *Handler) SwapContractParticipant(w http.ResponseWriter, r *http.Request
 		errorResponse(w, http.StatusBadRequest, "Both current and new public keys are required")
 		return
 	}
-	
-	if req.NewParticipantInput == "" {
-		errorResponse(w, http.StatusBadRequest, "New participant input is required")
-		return
-	}
 
-	// Get the contract to check permissions
-	contract, err := h.contractService.GetContract(r.Context(), contractID)
-	if err != nil {
-		errorResponse(w, http.StatusNotFound, "Contract not found")
+	c, ok := h.requireParty(w, r, contractID)
+	if !ok {
 		return
 	}
 	
 	// Verify that the current public key belongs to one of the participants
-	if contract.BuyerPubKey != req.CurrentPubKey && contract.SellerPubKey != req.CurrentPubKey {
+	if c.BuyerPubKey != req.CurrentPubKey && c.SellerPubKey != req.CurrentPubKey {
 		errorResponse(w, http.StatusBadRequest, "Current public key does not match any participant")
 		return
 	}
 
+	// A party may only swap themselves out, not their counterparty
+	if !h.isAdmin(r) {
+		userID, _ := h.viewer(r)
+		holds, err := h.contractService.HoldsKey(r.Context(), userID, req.CurrentPubKey)
+		if err != nil {
+			log.Error().Err(err).Str("contractID", id).Msg("Failed to get user keys")
+			errorResponse(w, http.StatusInternalServerError, "Failed to swap contract participant")
+			return
+		}
+		if !holds {
+			errorResponse(w, http.StatusForbidden, "Access denied")
+			return
+		}
+	}
+
 	// Swap the participant
 	tx, err := h.contractService.SwapContractParticipant(
 		r.Context(), 
@@ -558,22 +778,33 @@ func (h *Handler) GetOrderBook(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, response{
 		Success: true,
-		Data:    orders,
+		Data: map[string][]interface{}{
+			"buys":  h.publicOrders(r, orders["buys"]),
+			"sells": h.publicOrders(r, orders["sells"]),
+		},
 	})
 }
 
 // PlaceOrderRequest represents the request to place a new order
 type PlaceOrderRequest struct {
-	UserID           string  `json:"user_id"`
-	Side             string  `json:"side"`
-	ContractType     string  `json:"contract_type"`
-	StrikeHashRate   float64 `json:"strike_hash_rate"`
-	StartBlockHeight int64   `json:"start_block_height"`
-	EndBlockHeight   int64   `json:"end_block_height"`
-	Price            int64   `json:"price"`
-	Quantity         int     `json:"quantity"`
-	PubKey           string  `json:"pub_key"`
-	ExpiresIn        *int    `json:"expires_in,omitempty"` // Optional: minutes until expiration
+	UserID           string           `json:"user_id"`
+	Side             string           `json:"side"`
+	Type             string           `json:"type,omitempty"`          // Optional: limit (default) or market
+	TimeInForce      string           `json:"time_in_force,omitempty"` // Optional: gtc (default), ioc or fok; market orders default to ioc
+	ContractType     string           `json:"contract_type"`
+	StrikeHashRate   float64          `json:"strike_hash_rate"`
+	StartBlockHeight int64            `json:"start_block_height"`
+	EndBlockHeight   int64            `json:"end_block_height"`
+	Price            int64            `json:"price"`
+	Quantity         int              `json:"quantity"`
+	PubKey           string           `json:"pub_key"`
+	KeyID            *string          `json:"key_id,omitempty"`           // Optional: registered key to use instead of pub_key
+	ExpiresIn        *int             `json:"expires_in,omitempty"`       // Optional: minutes until expiration
+	TargetTimestamp  *time.Time       `json:"target_timestamp,omitempty"` // Optional: defaults to the market's listed target
+	AutoRoll         *AutoRollRequest `json:"auto_roll,omitempty"`        // Optional: roll into the next expiry once settled
+	// CancelOnDisconnect cancels the order when the last websocket session of
+	// the API key it is placed with closes
+	CancelOnDisconnect bool `json:"cancel_on_disconnect,omitempty"`
 }
 
 // PlaceOrder handles creating a new order
@@ -584,40 +815,66 @@ func (h *Handler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate and sanitize request
+	// Orders are placed for the authenticated user unless another user is named
+	if req.UserID == "" {
+		if viewerID, ok := h.viewer(r); ok {
+			req.UserID = viewerID.String()
+		}
+	}
+
 	if req.UserID == "" {
 		errorResponse(w, http.StatusBadRequest, "User ID is required")
 		return
 	}
 
 	req.PubKey = sanitizeInput(req.PubKey)
-	if req.PubKey == "" {
-		errorResponse(w, http.StatusBadRequest, "Public key is required")
+
+	// The schema checks each field and its enumerations; these rules relate
+	// fields to each other
+	if req.EndBlockHeight <= req.StartBlockHeight {
+		errorResponse(w, http.StatusBadRequest, "End block height must be greater than start block height")
 		return
 	}
 
-	if req.StrikeHashRate <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Strike hash rate must be positive")
+	// Determine order type
+	orderType := models.OrderTypeLimit
+	if strings.EqualFold(req.Type, "market") {
+		orderType = models.OrderTypeMarket
+	}
+
+	if orderType == models.OrderTypeLimit && req.Price <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Price must be positive")
 		return
 	}
 
-	if req.StartBlockHeight <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Start block height must be positive")
+	// Market orders are priced by the book from the opposite side
+	if orderType == models.OrderTypeMarket && req.Price != 0 {
+		errorResponse(w, http.StatusBadRequest, "Market orders cannot have a price")
 		return
 	}
 
-	if req.EndBlockHeight <= req.StartBlockHeight {
-		errorResponse(w, http.StatusBadRequest, "End block height must be greater than start block height")
+	// Market orders never rest, so there is no order to roll
+	if orderType == models.OrderTypeMarket && req.AutoRoll != nil {
+		errorResponse(w, http.StatusBadRequest, "Market orders cannot auto-roll")
 		return
 	}
 
-	if req.Price <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Price must be positive")
+	// Determine time in force, left empty for the order book's default
+	timeInForce := models.TimeInForce(strings.ToUpper(req.TimeInForce))
+	if timeInForce == models.TimeInForceGTC && orderType == models.OrderTypeMarket {
+		errorResponse(w, http.StatusBadRequest, "Market orders cannot be good-til-cancelled")
 		return
 	}
 
-	if req.Quantity <= 0 {
-		errorResponse(w, http.StatusBadRequest, "Quantity must be positive")
+	// Orders placed with an API key are subject to its market maker
+	// protection and can be tied to its websocket sessions
+	var apiKeyID *uuid.UUID
+	if key, ok := usage.APIKeyFromContext(r.Context()); ok {
+		apiKeyID = &key.ID
+	}
+
+	if req.CancelOnDisconnect && apiKeyID == nil {
+		errorResponse(w, http.StatusBadRequest, "Cancel on disconnect requires an API key")
 		return
 	}
 
@@ -627,41 +884,53 @@ func (h *Handler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Cannot place orders for another user")
+		return
+	}
+
+	// Resolve the key used for this order
+	pubKey, keyID, err := h.resolveOrderKey(r.Context(), userID, req.KeyID, req.PubKey)
+	if err != nil {
+		var keyErr orderKeyError
+		if errors.As(err, &keyErr) {
+			errorResponse(w, http.StatusBadRequest, keyErr.Error())
+			return
+		}
+		log.Error().Err(err).Str("userID", req.UserID).Msg("Failed to resolve order key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to place order")
+		return
+	}
+
 	// Determine side
-	var side models.OrderSide
-	switch strings.ToLower(req.Side) {
-	case "buy":
-		side = models.OrderSideBuy
-	case "sell":
+	side := models.OrderSideBuy
+	if strings.EqualFold(req.Side, "sell") {
 		side = models.OrderSideSell
-	default:
-		errorResponse(w, http.StatusBadRequest, "Invalid side")
-		return
 	}
 
 	// Determine contract type
-	var contractType models.ContractType
-	switch strings.ToLower(req.ContractType) {
-	case "call":
-		contractType = models.ContractTypeCall
-	case "put":
+	contractType := models.ContractTypeCall
+	if strings.EqualFold(req.ContractType, "put") {
 		contractType = models.ContractTypePut
-	default:
-		errorResponse(w, http.StatusBadRequest, "Invalid contract type")
-		return
 	}
 
 	// Create order object
 	order := &models.Order{
-		UserID:           userID,
-		Side:             side,
-		ContractType:     contractType,
-		StrikeHashRate:   req.StrikeHashRate,
-		StartBlockHeight: req.StartBlockHeight,
-		EndBlockHeight:   req.EndBlockHeight,
-		Price:            req.Price,
-		Quantity:         req.Quantity,
-		PubKey:           req.PubKey,
+		UserID:             userID,
+		Side:               side,
+		Type:               orderType,
+		TimeInForce:        timeInForce,
+		ContractType:       contractType,
+		StrikeHashRate:     req.StrikeHashRate,
+		StartBlockHeight:   req.StartBlockHeight,
+		EndBlockHeight:     req.EndBlockHeight,
+		Price:              req.Price,
+		Quantity:           req.Quantity,
+		PubKey:             pubKey,
+		KeyID:              keyID,
+		TargetTimestamp:    req.TargetTimestamp,
+		APIKeyID:           apiKeyID,
+		CancelOnDisconnect: req.CancelOnDisconnect,
 	}
 
 	// Set expiration if provided
@@ -673,14 +942,27 @@ func (h *Handler) PlaceOrder(w http.ResponseWriter, r *http.Request) {
 	// Place the order
 	placedOrder, err := h.orderBook.PlaceOrder(r.Context(), order)
 	if err != nil {
+		if errors.Is(err, orderbook.ErrOrderRejected) {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if notMatcherResponse(w, err) {
+			return
+		}
 		log.Error().Err(err).Msg("Failed to place order")
 		errorResponse(w, http.StatusInternalServerError, "Failed to place order")
 		return
 	}
 
+	if req.AutoRoll != nil && h.rollover != nil {
+		if _, err := h.rollover.Enable(r.Context(), placedOrder, req.AutoRoll.PriceOffset); err != nil {
+			log.Warn().Err(err).Str("orderID", placedOrder.ID.String()).Msg("Failed to enable auto-roll")
+		}
+	}
+
 	respondJSON(w, http.StatusCreated, response{
 		Success: true,
-		Data:    placedOrder,
+		Data:    h.withOrderDeadlines(r.Context(), placedOrder)[0],
 	})
 }
 
@@ -696,58 +978,122 @@ func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
 	// Get the order to check permissions
 	order, err := h.orderBook.GetOrderByID(r.Context(), orderID)
 	if err != nil {
-		errorResponse(w, http.StatusNotFound, "Order not found")
+		storeErrorResponse(w, err, "Order not found", "Failed to get order")
 		return
 	}
 
-	// In a real implementation, check if the user has permission to cancel this order
-	// For MVP, we'll skip detailed permission checks
+	if !h.validateUserPermissions(r, order.UserID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
 
-	err = h.orderBook.CancelOrder(r.Context(), orderID)
+	result, err := h.orderBook.CancelOrder(r.Context(), orderID)
 	if err != nil {
+		if notMatcherResponse(w, err) {
+			return
+		}
 		log.Error().Err(err).Str("orderID", id).Msg("Failed to cancel order")
-		errorResponse(w, http.StatusInternalServerError, "Failed to cancel order")
+		storeErrorResponse(w, err, "Order not found", "Failed to cancel order")
 		return
 	}
 
+	// An order that filled or expired before the cancel was processed is
+	// reported in its final state, so the caller always learns the outcome
 	respondJSON(w, http.StatusOK, response{
 		Success: true,
-		Data:    "Order cancelled successfully",
+		Data: cancelOrderResponse{
+			Cancelled: result.Cancelled(),
+			Order:     h.withOrderDeadlines(r.Context(), result.Order)[0],
+			Fills:     result.Fills,
+		},
 	})
 }
 
-// GetUserOrders handles retrieving all orders for a user
-func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
+// cancelOrderResponse is the final state of an order returned to its canceller
+type cancelOrderResponse struct {
+	Cancelled bool            `json:"cancelled"`
+	Order     orderResponse   `json:"order"`
+	Fills     []*models.Trade `json:"fills"`
+}
+
+// AmendOrderRequest represents the request to amend an open order. Omitted
+// fields are left unchanged.
+type AmendOrderRequest struct {
+	Price    *int64 `json:"price"`
+	Quantity *int   `json:"quantity"`
+}
+
+// AmendOrder handles changing the price or quantity of an open order. The
+// order keeps its place in the queue only when its quantity is reduced at
+// the same price.
+func (h *Handler) AmendOrder(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	userID, err := uuid.Parse(id)
+	orderID, err := uuid.Parse(id)
 	if err != nil {
-		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		errorResponse(w, http.StatusBadRequest, "Invalid order ID")
 		return
 	}
 
-	// Parse pagination parameters
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	var req AmendOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
 
-	limit := 50
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit <= 0 {
-			errorResponse(w, http.StatusBadRequest, "Invalid limit")
-			return
-		}
+	order, err := h.orderBook.GetOrderByID(r.Context(), orderID)
+	if err != nil {
+		storeErrorResponse(w, err, "Order not found", "Failed to get order")
+		return
 	}
 
-	offset := 0
-	if offsetStr != "" {
-		offset, err = strconv.Atoi(offsetStr)
-		if err != nil || offset < 0 {
-			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+	if !h.validateUserPermissions(r, order.UserID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	amended, err := h.orderBook.AmendOrder(r.Context(), orderID, orderbook.OrderAmendment{
+		Price:    req.Price,
+		Quantity: req.Quantity,
+	})
+	if err != nil {
+		if errors.Is(err, orderbook.ErrOrderRejected) {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if notMatcherResponse(w, err) {
 			return
 		}
+		log.Error().Err(err).Str("orderID", id).Msg("Failed to amend order")
+		storeErrorResponse(w, err, "Order not found", "Failed to amend order")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.withOrderDeadlines(r.Context(), amended)[0],
+	})
+}
+
+// GetUserOrders handles retrieving a page of a user's orders, newest first
+func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	after, limit, ok := parsePage(w, r, 50, maxPageSize)
+	if !ok {
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
 	}
 
-	orders, err := h.orderBook.ListUserOrders(r.Context(), userID, limit, offset)
+	orders, page, err := h.orderBook.ListUserOrders(r.Context(), userID, after, limit)
 	if err != nil {
 		log.Error().Err(err).Str("userID", id).Msg("Failed to get user orders")
 		errorResponse(w, http.StatusInternalServerError, "Failed to get user orders")
@@ -756,6 +1102,7 @@ func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, response{
 		Success: true,
-		Data:    orders,
+		Data:    h.withOrderDeadlines(r.Context(), orders...),
+		Page:    page,
 	})
 }
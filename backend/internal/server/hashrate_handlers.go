@@ -0,0 +1,169 @@
+// internal/server/hashrate_handlers.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hashhedge/internal/contract/hashrate"
+)
+
+const (
+	// maxHashRateHistoryBlocks bounds the blocks returned by one history request
+	maxHashRateHistoryBlocks = 1008
+	// maxHashRateWindow bounds the trailing window each history point is measured over
+	maxHashRateWindow = 2016
+)
+
+// WithHashRateIndex enables the hash rate index endpoints
+func (h *Handler) WithHashRateIndex(calculator *hashrate.HashRateCalculator) *Handler {
+	h.hashRateIndex = calculator
+	return h
+}
+
+// GetHashRateIndex handles retrieving the hash rate at the chain tip and its
+// 1d, 7d and 30d rolling averages
+func (h *Handler) GetHashRateIndex(w http.ResponseWriter, r *http.Request) {
+	if h.hashRateIndex == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Hash rate index is not enabled")
+		return
+	}
+
+	h.serveCached(w, r, "hashrate:index", h.marketCfg.HashRateTTL, func() (interface{}, error) {
+		return h.hashRateIndex.CurrentIndex(r.Context())
+	})
+}
+
+// GetHashRateHistory handles retrieving the per-block hash rate between two
+// heights, each measured over a trailing window of blocks
+func (h *Handler) GetHashRateHistory(w http.ResponseWriter, r *http.Request) {
+	if h.hashRateIndex == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Hash rate index is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+
+	window := hashrate.BlocksPerDay
+	if windowStr := query.Get("window"); windowStr != "" {
+		var err error
+		window, err = strconv.Atoi(windowStr)
+		if err != nil || window <= 0 || window > maxHashRateWindow {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid window, expected 1 to %d", maxHashRateWindow))
+			return
+		}
+	}
+
+	tip, err := h.contractService.CurrentBlockHeight(r.Context())
+	if err != nil {
+		errorResponse(w, http.StatusInternalServerError, "Failed to get chain tip")
+		return
+	}
+
+	to := tip
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = strconv.ParseInt(toStr, 10, 64)
+		if err != nil || to <= 0 || to > tip {
+			errorResponse(w, http.StatusBadRequest, "Invalid to height")
+			return
+		}
+	}
+
+	from := to - hashrate.BlocksPerDay + 1
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err = strconv.ParseInt(fromStr, 10, 64)
+		if err != nil || from <= 0 || from > to {
+			errorResponse(w, http.StatusBadRequest, "Invalid from height")
+			return
+		}
+	}
+	if from < 1 {
+		from = 1
+	}
+	if to-from+1 > maxHashRateHistoryBlocks {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Range cannot exceed %d blocks", maxHashRateHistoryBlocks))
+		return
+	}
+
+	cacheKey := fmt.Sprintf("hashrate:history:%d:%d:%d", from, to, window)
+	h.serveCached(w, r, cacheKey, h.marketCfg.HashRateTTL, func() (interface{}, error) {
+		return h.hashRateIndex.History(r.Context(), from, to, window)
+	})
+}
+
+// GetHashRateStatistics handles retrieving the realized hash rate
+// volatility and block interval distribution over a trailing window of
+// blocks, and the recent difficulty adjustments, for quoting premiums
+func (h *Handler) GetHashRateStatistics(w http.ResponseWriter, r *http.Request) {
+	if h.hashRateIndex == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Hash rate index is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+
+	window := int64(hashrate.DefaultStatisticsWindow)
+	if windowStr := query.Get("window"); windowStr != "" {
+		var err error
+		window, err = strconv.ParseInt(windowStr, 10, 64)
+		if err != nil || window <= 0 || window > hashrate.MaxStatisticsWindow {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid window, expected 1 to %d", hashrate.MaxStatisticsWindow))
+			return
+		}
+	}
+
+	sample := int64(hashrate.BlocksPerDay)
+	if sampleStr := query.Get("sample"); sampleStr != "" {
+		var err error
+		sample, err = strconv.ParseInt(sampleStr, 10, 64)
+		if err != nil || sample <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid sample")
+			return
+		}
+	}
+	if sample*3 > window {
+		errorResponse(w, http.StatusBadRequest, "Window must hold at least three samples")
+		return
+	}
+
+	epochs := hashrate.DefaultStatisticsEpochs
+	if epochsStr := query.Get("epochs"); epochsStr != "" {
+		var err error
+		epochs, err = strconv.Atoi(epochsStr)
+		if err != nil || epochs < 0 || epochs > hashrate.MaxStatisticsEpochs {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid epochs, expected 0 to %d", hashrate.MaxStatisticsEpochs))
+			return
+		}
+	}
+
+	cacheKey := fmt.Sprintf("hashrate:statistics:%d:%d:%d", window, sample, epochs)
+	h.serveCached(w, r, cacheKey, h.marketCfg.HashRateTTL, func() (interface{}, error) {
+		return h.hashRateIndex.Statistics(r.Context(), window, sample, epochs)
+	})
+}
+
+// GetStrikeLadder handles retrieving the recommended strikes around the
+// hash rate and the block ranges of standard series aligned to difficulty
+// epochs, so clients offer the same few markets instead of arbitrary terms
+func (h *Handler) GetStrikeLadder(w http.ResponseWriter, r *http.Request) {
+	if h.hashRateIndex == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Hash rate index is not enabled")
+		return
+	}
+
+	steps := hashrate.DefaultStrikeSteps
+	if stepsStr := r.URL.Query().Get("steps"); stepsStr != "" {
+		var err error
+		steps, err = strconv.Atoi(stepsStr)
+		if err != nil || steps < 0 || steps > hashrate.MaxStrikeSteps {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid steps, expected 0 to %d", hashrate.MaxStrikeSteps))
+			return
+		}
+	}
+
+	cacheKey := fmt.Sprintf("hashrate:ladder:%d", steps)
+	h.serveCached(w, r, cacheKey, h.marketCfg.HashRateTTL, func() (interface{}, error) {
+		return h.hashRateIndex.StrikeLadder(r.Context(), steps)
+	})
+}
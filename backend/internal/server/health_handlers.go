@@ -0,0 +1,34 @@
+// internal/server/health_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"hashhedge/pkg/bitcoin"
+)
+
+// WithBitcoinHealth enables the health endpoint of the Bitcoin nodes
+func (h *Handler) WithBitcoinHealth(client *bitcoin.Client) *Handler {
+	h.bitcoinHealth = client
+	return h
+}
+
+// GetBitcoinHealth handles retrieving the last health check and circuit of
+// each Bitcoin node, answering 503 when none is reachable
+func (h *Handler) GetBitcoinHealth(w http.ResponseWriter, r *http.Request) {
+	if h.bitcoinHealth == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Bitcoin health checks are not enabled")
+		return
+	}
+
+	status := http.StatusOK
+	healthy := h.bitcoinHealth.Healthy()
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	respondJSON(w, status, response{
+		Success: healthy,
+		Data:    h.bitcoinHealth.Health(),
+	})
+}
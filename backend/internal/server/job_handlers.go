@@ -0,0 +1,130 @@
+// internal/server/job_handlers.go
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/jobs"
+	"hashhedge/internal/models"
+)
+
+// WithJobRunner enables the background job admin endpoints
+func (h *Handler) WithJobRunner(runner *jobs.Runner) *Handler {
+	h.jobRunner = runner
+	return h
+}
+
+// ListJobs handles listing background jobs by status, defaulting to the dead-letter queue
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	if h.jobRunner == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Job runner is not enabled")
+		return
+	}
+
+	status := models.JobStatusDead
+	if s := r.URL.Query().Get("status"); s != "" {
+		status = models.JobStatus(s)
+		switch status {
+		case models.JobStatusPending, models.JobStatusRunning, models.JobStatusCompleted, models.JobStatusDead:
+		default:
+			errorResponse(w, http.StatusBadRequest, "Invalid job status")
+			return
+		}
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	jobList, err := h.jobRunner.List(r.Context(), status, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list jobs")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list jobs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    jobList,
+	})
+}
+
+// GetJob handles retrieving a single background job
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	if h.jobRunner == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Job runner is not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	job, err := h.jobRunner.Get(r.Context(), jobID)
+	if err != nil {
+		log.Error().Err(err).Str("jobID", id).Msg("Failed to get job")
+		storeErrorResponse(w, err, "Job not found", "Failed to get job")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    job,
+	})
+}
+
+// ReplayJob handles requeueing a job from the dead-letter queue
+func (h *Handler) ReplayJob(w http.ResponseWriter, r *http.Request) {
+	if h.jobRunner == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Job runner is not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid job ID")
+		return
+	}
+
+	if err := h.jobRunner.Replay(r.Context(), jobID); err != nil {
+		log.Error().Err(err).Str("jobID", id).Msg("Failed to replay job")
+		if errors.Is(err, db.ErrConflict) {
+			errorResponse(w, http.StatusConflict, "Job cannot be replayed")
+			return
+		}
+		errorResponse(w, http.StatusInternalServerError, "Failed to replay job")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Job requeued successfully",
+	})
+}
@@ -0,0 +1,250 @@
+// internal/server/key_handlers.go
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+)
+
+// AddUserKeyRequest represents the request to register a public key
+type AddUserKeyRequest struct {
+	PubKey    string `json:"pub_key"`
+	KeyType   string `json:"key_type"`
+	Label     string `json:"label"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// ListUserKeys handles listing a user's registered keys, default first
+func (h *Handler) ListUserKeys(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	keys, err := h.userRepo.GetKeysByUserID(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to list keys")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list keys")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    keys,
+	})
+}
+
+// AddUserKey handles registering a new public key for a user
+func (h *Handler) AddUserKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	var req AddUserKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	req.PubKey = sanitizeInput(req.PubKey)
+	if err := validatePubKeyHex(req.PubKey); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key := &models.UserKey{
+		UserID:  userID,
+		PubKey:  req.PubKey,
+		KeyType: sanitizeInput(req.KeyType),
+		Label:   sanitizeInput(req.Label),
+	}
+	if key.KeyType == "" {
+		key.KeyType = "taproot"
+	}
+
+	existing, err := h.userRepo.GetKeysByUserID(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to list keys")
+		errorResponse(w, http.StatusInternalServerError, "Failed to add key")
+		return
+	}
+
+	if err := h.userRepo.AddKey(r.Context(), key); err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to add key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to add key")
+		return
+	}
+
+	// The first key a user registers becomes their default
+	if req.IsDefault || len(existing) == 0 {
+		if err := h.userRepo.SetDefaultKey(r.Context(), userID, key.ID); err != nil {
+			log.Error().Err(err).Str("keyID", key.ID.String()).Msg("Failed to set default key")
+			errorResponse(w, http.StatusInternalServerError, "Failed to set default key")
+			return
+		}
+		key.IsDefault = true
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    key,
+	})
+}
+
+// SetDefaultUserKey handles choosing the key used when an order does not name one
+func (h *Handler) SetDefaultUserKey(w http.ResponseWriter, r *http.Request) {
+	userID, key, ok := h.userKeyFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userRepo.SetDefaultKey(r.Context(), userID, key.ID); err != nil {
+		log.Error().Err(err).Str("keyID", key.ID.String()).Msg("Failed to set default key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to set default key")
+		return
+	}
+	key.IsDefault = true
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    key,
+	})
+}
+
+// DeleteUserKey handles removing a registered key
+func (h *Handler) DeleteUserKey(w http.ResponseWriter, r *http.Request) {
+	_, key, ok := h.userKeyFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userRepo.DeleteKey(r.Context(), key.ID); err != nil {
+		log.Error().Err(err).Str("keyID", key.ID.String()).Msg("Failed to delete key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to delete key")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    map[string]string{"message": "Key deleted"},
+	})
+}
+
+// userKeyFromRequest resolves the {id} and {keyId} URL parameters to a key owned by the user
+func (h *Handler) userKeyFromRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, *models.UserKey, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return uuid.Nil, nil, false
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return uuid.Nil, nil, false
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid key ID")
+		return uuid.Nil, nil, false
+	}
+
+	key, err := h.userRepo.GetKey(r.Context(), userID, keyID)
+	if err != nil {
+		storeErrorResponse(w, err, "Key not found", "Failed to get key")
+		return uuid.Nil, nil, false
+	}
+
+	return userID, key, true
+}
+
+// orderKeyError is a client error resolving the key for an order
+type orderKeyError string
+
+func (e orderKeyError) Error() string { return string(e) }
+
+// resolveOrderKey determines the public key for an order and the registered key
+// it came from, if any. An explicit key ID takes precedence, then an explicit
+// public key, then the user's default key.
+func (h *Handler) resolveOrderKey(ctx context.Context, userID uuid.UUID, keyID *string, pubKey string) (string, *uuid.UUID, error) {
+	if keyID != nil && *keyID != "" {
+		id, err := uuid.Parse(*keyID)
+		if err != nil {
+			return "", nil, orderKeyError("Invalid key ID")
+		}
+		key, err := h.userRepo.GetKey(ctx, userID, id)
+		if err != nil {
+			return "", nil, orderKeyError("Key not found")
+		}
+		if pubKey != "" && pubKey != key.PubKey {
+			return "", nil, orderKeyError("Public key does not match key ID")
+		}
+		return key.PubKey, &key.ID, nil
+	}
+
+	if pubKey != "" {
+		// Record the key ID when the public key is one the user has registered
+		keys, err := h.userRepo.GetKeysByUserID(ctx, userID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get keys: %w", err)
+		}
+		for _, key := range keys {
+			if key.PubKey == pubKey {
+				return pubKey, &key.ID, nil
+			}
+		}
+		return pubKey, nil, nil
+	}
+
+	key, err := h.userRepo.GetDefaultKey(ctx, userID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get default key: %w", err)
+	}
+	if key == nil {
+		return "", nil, orderKeyError("Public key is required: pass pub_key or key_id, or set a default key")
+	}
+
+	return key.PubKey, &key.ID, nil
+}
+
+// validatePubKeyHex checks that a key is a hex encoded x-only or compressed public key
+func validatePubKeyHex(pubKey string) error {
+	if pubKey == "" {
+		return errors.New("public key is required")
+	}
+
+	raw, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return errors.New("public key must be hex encoded")
+	}
+	if len(raw) != 32 && len(raw) != 33 {
+		return errors.New("public key must be 32 or 33 bytes")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+// internal/server/ledger_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/ledger"
+)
+
+// WithLedger enables users' balances, deposits and withdrawals
+func (h *Handler) WithLedger(service *ledger.Service) *Handler {
+	h.ledger = service
+	return h
+}
+
+// BalanceAmountRequest represents a deposit of funds
+type BalanceAmountRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+// balanceUser parses the user of a balance request, checking that the
+// ledger is enabled and that the requester is the user or, when admin is
+// set, an admin
+func (h *Handler) balanceUser(w http.ResponseWriter, r *http.Request, admin bool) (uuid.UUID, bool) {
+	if h.ledger == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Balances are not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, admin)
+}
+
+// GetBalance handles retrieving a user's funds
+func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.balanceUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	balance, err := h.ledger.Balance(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get balance")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get balance")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    balance,
+	})
+}
+
+// ListBalanceEntries handles listing the postings to a user's accounts,
+// newest first
+func (h *Handler) ListBalanceEntries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.balanceUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	entries, err := h.ledger.Entries(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list balance entries")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list balance entries")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    entries,
+	})
+}
+
+// DepositBalance handles an operator crediting funds a user sent to the
+// platform to their balance
+func (h *Handler) DepositBalance(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.balanceUser(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req BalanceAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	balance, err := h.ledger.Deposit(r.Context(), userID, req.Amount)
+	if err != nil {
+		balanceErrorResponse(w, err, "Failed to deposit funds")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    balance,
+	})
+}
+
+// balanceErrorResponse sends the error response for a failed deposit
+func balanceErrorResponse(w http.ResponseWriter, err error, msg string) {
+	if errors.Is(err, db.ErrConflict) {
+		errorResponse(w, http.StatusConflict, err.Error())
+		return
+	}
+	log.Error().Err(err).Msg(msg)
+	errorResponse(w, http.StatusBadRequest, err.Error())
+}
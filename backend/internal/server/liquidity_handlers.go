@@ -0,0 +1,53 @@
+// internal/server/liquidity_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"hashhedge/internal/marketdata"
+)
+
+// WithLiquidityMonitor enables the order book liquidity analytics endpoints
+func (h *Handler) WithLiquidityMonitor(monitor *marketdata.LiquidityMonitor) *Handler {
+	h.liquidity = monitor
+	return h
+}
+
+// ListLiquidity handles retrieving the spread, imbalance and banded depth of
+// every market measured since startup
+func (h *Handler) ListLiquidity(w http.ResponseWriter, r *http.Request) {
+	if h.liquidity == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Liquidity metrics are not enabled")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.liquidity.Markets(),
+	})
+}
+
+// GetMarketLiquidity handles retrieving the spread, imbalance and banded
+// depth of one market with their rolling averages
+func (h *Handler) GetMarketLiquidity(w http.ResponseWriter, r *http.Request) {
+	if h.liquidity == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Liquidity metrics are not enabled")
+		return
+	}
+
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	liquidity, ok := h.liquidity.Market(key)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "No liquidity has been measured for this market")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    liquidity,
+	})
+}
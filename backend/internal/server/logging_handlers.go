@@ -0,0 +1,57 @@
+// internal/server/logging_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"hashhedge/internal/logging"
+)
+
+// updateLoggingRequest changes the level and/or sampling of a component logger
+type updateLoggingRequest struct {
+	Component   string  `json:"component"`
+	Level       string  `json:"level,omitempty"`
+	SampleEvery *uint32 `json:"sample_every,omitempty"`
+}
+
+// GetLoggingStatus returns the active level and sampling of each component logger
+func (h *Handler) GetLoggingStatus(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    logging.Status(),
+	})
+}
+
+// UpdateLogging adjusts a component logger at runtime
+func (h *Handler) UpdateLogging(w http.ResponseWriter, r *http.Request) {
+	var req updateLoggingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Level == "" && req.SampleEvery == nil {
+		errorResponse(w, http.StatusBadRequest, "Level or sample_every is required")
+		return
+	}
+
+	if req.Level != "" {
+		if err := logging.SetLevel(req.Component, req.Level); err != nil {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if req.SampleEvery != nil {
+		if err := logging.SetSampling(req.Component, *req.SampleEvery); err != nil {
+			errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    logging.Status(),
+	})
+}
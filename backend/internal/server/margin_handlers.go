@@ -0,0 +1,219 @@
+// internal/server/margin_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/margin"
+)
+
+// WithMargin enables margin accounts and margined contracts
+func (h *Handler) WithMargin(engine *margin.Engine) *Handler {
+	h.margin = engine
+	return h
+}
+
+// MarginAmountRequest represents a deposit, withdrawal or top up of margin
+type MarginAmountRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+// OpenMarginPositionRequest represents the request to margin a contract
+type OpenMarginPositionRequest struct {
+	ContractID string `json:"contract_id"`
+}
+
+// marginUser parses the user of a margin request, checking that margin is
+// enabled and that the requester is the user or, when admin is set, an admin
+func (h *Handler) marginUser(w http.ResponseWriter, r *http.Request, admin bool) (uuid.UUID, bool) {
+	if h.margin == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Margin is not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, admin)
+}
+
+// marginErrorResponse sends the error response for a failed margin call
+func marginErrorResponse(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, margin.ErrNotEnabled):
+		errorResponse(w, http.StatusServiceUnavailable, "Margin is not enabled")
+	case errors.Is(err, margin.ErrNotMarginable):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, db.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, "Margin position not found")
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// GetMarginSummary handles retrieving a user's margin account and positions
+func (h *Handler) GetMarginSummary(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marginUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	summary, err := h.margin.Summary(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get margin summary")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get margin summary")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    summary,
+	})
+}
+
+// DepositMargin handles an operator crediting collateral a user sent to
+// the platform to their margin account
+func (h *Handler) DepositMargin(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marginUser(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req MarginAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := h.margin.Deposit(r.Context(), userID, req.Amount)
+	if err != nil {
+		marginErrorResponse(w, err, "Failed to deposit margin")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    account,
+	})
+}
+
+// WithdrawMargin handles withdrawing free collateral from a margin account
+func (h *Handler) WithdrawMargin(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marginUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	var req MarginAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	account, err := h.margin.Withdraw(r.Context(), userID, req.Amount)
+	if err != nil {
+		marginErrorResponse(w, err, "Failed to withdraw margin")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    account,
+	})
+}
+
+// OpenMarginPosition handles a contract's writer posting margin for it
+// instead of funding its full size
+func (h *Handler) OpenMarginPosition(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marginUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	var req OpenMarginPositionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	contractID, err := uuid.Parse(req.ContractID)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	c, err := h.contractService.GetContract(r.Context(), contractID)
+	if err != nil {
+		errorResponse(w, http.StatusNotFound, "Contract not found")
+		return
+	}
+
+	// Only the writer of a contract funds its size
+	keys, err := h.userRepo.GetKeysByUserID(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get user keys")
+		errorResponse(w, http.StatusInternalServerError, "Failed to open margin position")
+		return
+	}
+	writer := false
+	for _, key := range keys {
+		if key.PubKey == c.SellerPubKey {
+			writer = true
+			break
+		}
+	}
+	if !writer {
+		errorResponse(w, http.StatusForbidden, "Only the contract's seller can margin it")
+		return
+	}
+
+	position, err := h.margin.Open(r.Context(), userID, c)
+	if err != nil {
+		marginErrorResponse(w, err, "Failed to open margin position")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    position,
+	})
+}
+
+// TopUpMarginPosition handles adding collateral to a margin position, which
+// meets its margin call once the requirement is covered
+func (h *Handler) TopUpMarginPosition(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marginUser(w, r, false)
+	if !ok {
+		return
+	}
+
+	positionID, err := uuid.Parse(chi.URLParam(r, "positionId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid position ID")
+		return
+	}
+
+	var req MarginAmountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	position, err := h.margin.TopUp(r.Context(), userID, positionID, req.Amount)
+	if err != nil {
+		marginErrorResponse(w, err, "Failed to top up margin position")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    position,
+	})
+}
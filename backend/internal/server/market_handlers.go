@@ -0,0 +1,389 @@
+// internal/server/market_handlers.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/marketdata"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// maxDepthLevels bounds the price levels returned per side of a market
+const maxDepthLevels = 100
+
+// maxTradeHistory bounds the trades returned by one request
+const maxTradeHistory = 1000
+
+// tickerResponse is the top of book and last trade of a market
+type tickerResponse struct {
+	ContractType     models.ContractType `json:"contract_type"`
+	StrikeHashRate   float64             `json:"strike_hash_rate"`
+	StartBlockHeight int64               `json:"start_block_height"`
+	EndBlockHeight   int64               `json:"end_block_height"`
+	BestBid          *int64              `json:"best_bid,omitempty"`
+	BestAsk          *int64              `json:"best_ask,omitempty"`
+	LastTrade        *int64              `json:"last_trade,omitempty"`
+}
+
+// marketStatsResponse is the live order counts of the order book and the
+// open interest across every market, omitted when it is not tracked
+type marketStatsResponse struct {
+	orderbook.BookStats
+	OpenInterest *openInterestTotals `json:"open_interest,omitempty"`
+}
+
+// openInterestTotals sums the open interest of every market
+type openInterestTotals struct {
+	Markets   int   `json:"markets"`
+	Contracts int64 `json:"contracts"`
+	Notional  int64 `json:"notional"`
+}
+
+// hashRateResponse is the network hash rate at the chain tip
+type hashRateResponse struct {
+	BlockHeight int64     `json:"block_height"`
+	HashRate    float64   `json:"hash_rate"`
+	ObservedAt  time.Time `json:"observed_at"`
+}
+
+// WithMarketData enables the cached public market data endpoints
+func (h *Handler) WithMarketData(cfg marketdata.Config) *Handler {
+	h.marketCfg = cfg
+	h.marketCache = marketdata.NewCache(cfg.MaxEntries)
+	return h
+}
+
+// WithTradeHistory enables the public trade history and candle endpoints
+func (h *Handler) WithTradeHistory(trades *db.TradeRepository) *Handler {
+	h.trades = trades
+	return h
+}
+
+// serveCached writes a cached market data response, answering a matching
+// If-None-Match with 304 Not Modified
+func (h *Handler) serveCached(w http.ResponseWriter, r *http.Request, key string, ttl time.Duration, load func() (interface{}, error)) {
+	if h.marketCache == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Market data is not enabled")
+		return
+	}
+
+	entry, err := h.marketCache.Get(key, ttl, func() (interface{}, error) {
+		data, err := load()
+		if err != nil {
+			return nil, err
+		}
+		// Paged listings build their own envelope to carry the page
+		if resp, ok := data.(response); ok {
+			return resp, nil
+		}
+		return response{Success: true, Data: data}, nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("key", key).Msg("Failed to load market data")
+		errorResponse(w, http.StatusInternalServerError, "Failed to load market data")
+		return
+	}
+
+	maxAge := int(time.Until(entry.Expires).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("ETag", entry.ETag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), entry.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(entry.Body); err != nil {
+		log.Debug().Err(err).Msg("Failed to write market data response")
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMarketKey reads the market identified by the type, strike_hash_rate,
+// start_block_height and end_block_height query parameters, writing a 400
+// response and returning false if any is invalid
+func parseMarketKey(w http.ResponseWriter, r *http.Request) (orderbook.OrderKey, bool) {
+	query := r.URL.Query()
+
+	var contractType models.ContractType
+	switch strings.ToLower(query.Get("type")) {
+	case "call":
+		contractType = models.ContractTypeCall
+	case "put":
+		contractType = models.ContractTypePut
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid contract type")
+		return orderbook.OrderKey{}, false
+	}
+
+	strikeHashRate, err := strconv.ParseFloat(query.Get("strike_hash_rate"), 64)
+	if err != nil || strikeHashRate <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Invalid strike hash rate")
+		return orderbook.OrderKey{}, false
+	}
+
+	startBlockHeight, err := strconv.ParseInt(query.Get("start_block_height"), 10, 64)
+	if err != nil || startBlockHeight <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Invalid start block height")
+		return orderbook.OrderKey{}, false
+	}
+
+	endBlockHeight, err := strconv.ParseInt(query.Get("end_block_height"), 10, 64)
+	if err != nil || endBlockHeight <= startBlockHeight {
+		errorResponse(w, http.StatusBadRequest, "Invalid end block height")
+		return orderbook.OrderKey{}, false
+	}
+
+	return orderbook.OrderKey{
+		ContractType:     contractType,
+		StrikeHashRate:   strikeHashRate,
+		StartBlockHeight: startBlockHeight,
+		EndBlockHeight:   endBlockHeight,
+	}, true
+}
+
+// GetMarketDepth handles retrieving the aggregated price levels of a market
+// with the cumulative quantity at each level, best price first
+func (h *Handler) GetMarketDepth(w http.ResponseWriter, r *http.Request) {
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	levels := 20
+	if levelsStr := r.URL.Query().Get("levels"); levelsStr != "" {
+		var err error
+		levels, err = strconv.Atoi(levelsStr)
+		if err != nil || levels <= 0 || levels > maxDepthLevels {
+			errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid levels, expected 1 to %d", maxDepthLevels))
+			return
+		}
+	}
+
+	cacheKey := fmt.Sprintf("depth:%s:%g:%d:%d:%d",
+		key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight, levels)
+
+	h.serveCached(w, r, cacheKey, h.marketCfg.TTL, func() (interface{}, error) {
+		return h.orderBook.Depth(key, levels), nil
+	})
+}
+
+// GetMarketTickers handles retrieving the top of book and last trade of every market
+func (h *Handler) GetMarketTickers(w http.ResponseWriter, r *http.Request) {
+	h.serveCached(w, r, "tickers", h.marketCfg.TTL, func() (interface{}, error) {
+		snapshots := h.orderBook.Tickers()
+		tickers := make([]tickerResponse, 0, len(snapshots))
+		for _, s := range snapshots {
+			tickers = append(tickers, tickerResponse{
+				ContractType:     s.Key.ContractType,
+				StrikeHashRate:   s.Key.StrikeHashRate,
+				StartBlockHeight: s.Key.StartBlockHeight,
+				EndBlockHeight:   s.Key.EndBlockHeight,
+				BestBid:          s.BestBid,
+				BestAsk:          s.BestAsk,
+				LastTrade:        s.LastTrade,
+			})
+		}
+		return tickers, nil
+	})
+}
+
+// GetMarketHashRate handles retrieving the network hash rate at the chain tip
+func (h *Handler) GetMarketHashRate(w http.ResponseWriter, r *http.Request) {
+	h.serveCached(w, r, "hashrate", h.marketCfg.HashRateTTL, func() (interface{}, error) {
+		height, err := h.contractService.CurrentBlockHeight(r.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		hashRate, err := h.contractService.GetHashRateAtHeight(r.Context(), height)
+		if err != nil {
+			return nil, err
+		}
+
+		return hashRateResponse{
+			BlockHeight: height,
+			HashRate:    hashRate,
+			ObservedAt:  time.Now().UTC(),
+		}, nil
+	})
+}
+
+// GetMarketStats handles retrieving the live order counts of the order book
+// and, when tracked, the open interest across every market
+func (h *Handler) GetMarketStats(w http.ResponseWriter, r *http.Request) {
+	h.serveCached(w, r, "stats", h.marketCfg.TTL, func() (interface{}, error) {
+		stats := marketStatsResponse{BookStats: h.orderBook.Stats()}
+		if !h.contractService.OpenInterestEnabled() {
+			return stats, nil
+		}
+
+		markets, err := h.contractService.ListOpenInterest(r.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		stats.OpenInterest = &openInterestTotals{}
+		for _, oi := range markets {
+			stats.OpenInterest.Markets++
+			stats.OpenInterest.Contracts += oi.Contracts
+			stats.OpenInterest.Notional += oi.Notional
+		}
+		return stats, nil
+	})
+}
+
+// GetMarketOpenInterest handles retrieving the open interest of every market
+// with active contracts
+func (h *Handler) GetMarketOpenInterest(w http.ResponseWriter, r *http.Request) {
+	if !h.contractService.OpenInterestEnabled() {
+		errorResponse(w, http.StatusServiceUnavailable, "Open interest tracking is not enabled")
+		return
+	}
+
+	h.serveCached(w, r, "open-interest", h.marketCfg.TTL, func() (interface{}, error) {
+		return h.contractService.ListOpenInterest(r.Context())
+	})
+}
+
+// parseTime reads an optional RFC3339 query parameter, writing a 400
+// response and returning false if it is invalid
+func parseTime(w http.ResponseWriter, r *http.Request, name string, fallback time.Time) (time.Time, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, true
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid %s, expected RFC3339", name))
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// GetMarketTrades handles retrieving a page of the recent trades of a
+// market, newest first
+func (h *Handler) GetMarketTrades(w http.ResponseWriter, r *http.Request) {
+	if h.trades == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Trade history is not enabled")
+		return
+	}
+
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	since, ok := parseTime(w, r, "since", time.Now().UTC().Add(-24*time.Hour))
+	if !ok {
+		return
+	}
+
+	after, limit, ok := parsePage(w, r, 100, maxTradeHistory)
+	if !ok {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("trades:%s:%g:%d:%d:%s:%s:%d",
+		key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight,
+		r.URL.Query().Get("since"), r.URL.Query().Get("cursor"), limit)
+
+	h.serveCached(w, r, cacheKey, h.marketCfg.TTL, func() (interface{}, error) {
+		trades, err := h.trades.ListByMarket(r.Context(),
+			key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight, since, after, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		total, err := h.trades.CountByMarket(r.Context(),
+			key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight, since)
+		if err != nil {
+			return nil, err
+		}
+
+		fetched := len(trades)
+		if fetched > limit {
+			trades = trades[:limit]
+		}
+		var last models.Cursor
+		if len(trades) > 0 {
+			last = models.Cursor{Time: trades[len(trades)-1].ExecutedAt, ID: trades[len(trades)-1].ID}
+		}
+		return response{Success: true, Data: trades, Page: models.NewPageInfo(total, fetched, limit, last)}, nil
+	})
+}
+
+// GetMarketCandles handles retrieving the OHLCV candles of a market over a
+// time range, oldest first
+func (h *Handler) GetMarketCandles(w http.ResponseWriter, r *http.Request) {
+	if h.trades == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Trade history is not enabled")
+		return
+	}
+
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	intervalName := r.URL.Query().Get("interval")
+	if intervalName == "" {
+		intervalName = "1h"
+	}
+	interval, err := marketdata.ParseCandleInterval(intervalName)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	until, ok := parseTime(w, r, "until", now)
+	if !ok {
+		return
+	}
+	since, ok := parseTime(w, r, "since", until.Add(-100*interval))
+	if !ok {
+		return
+	}
+
+	from, to, err := marketdata.CandleRange(interval, since, until)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cacheKey := fmt.Sprintf("candles:%s:%g:%d:%d:%s:%d:%d",
+		key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight,
+		intervalName, from.Unix(), to.Unix())
+
+	h.serveCached(w, r, cacheKey, h.marketCfg.TTL, func() (interface{}, error) {
+		return h.trades.ListCandles(r.Context(),
+			key.ContractType, key.StrikeHashRate, key.StartBlockHeight, key.EndBlockHeight, interval, from, to)
+	})
+}
@@ -0,0 +1,261 @@
+// internal/server/marketplace_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/marketplace"
+)
+
+// WithMarketplace enables the secondary market for contract positions
+func (h *Handler) WithMarketplace(service *marketplace.Service) *Handler {
+	h.marketplace = service
+	return h
+}
+
+// CreateListingRequest represents the request to list a contract position
+type CreateListingRequest struct {
+	ContractID string `json:"contract_id"`
+	Price      int64  `json:"price"`
+}
+
+// BuyListingRequest represents the request to buy a listed position
+type BuyListingRequest struct {
+	PubKey string `json:"pub_key"`
+}
+
+// marketplaceUser returns the requester of a marketplace request, checking
+// that the marketplace is enabled
+func (h *Handler) marketplaceUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.marketplace == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Marketplace is not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.requester(w, r)
+}
+
+// listingPage parses the limit and offset of a listings request
+func listingPage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return 0, 0, false
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return 0, 0, false
+		}
+	}
+
+	return limit, offset, true
+}
+
+// marketplaceErrorResponse sends the error response for a failed
+// marketplace call
+func marketplaceErrorResponse(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, marketplace.ErrNotListable), errors.Is(err, marketplace.ErrOwnListing):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, marketplace.ErrNotOwner):
+		errorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, db.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, "Listing not found")
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		errorResponse(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// ListListings handles browsing the open listings, optionally of one contract
+func (h *Handler) ListListings(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.marketplaceUser(w, r); !ok {
+		return
+	}
+
+	limit, offset, ok := listingPage(w, r)
+	if !ok {
+		return
+	}
+
+	var contractID *uuid.UUID
+	if idStr := r.URL.Query().Get("contract_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+			return
+		}
+		contractID = &id
+	}
+
+	listings, err := h.marketplace.Browse(r.Context(), contractID, limit, offset)
+	if err != nil {
+		marketplaceErrorResponse(w, err, "Failed to list listings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    listings,
+	})
+}
+
+// ListMyListings handles listing the requester's own listings
+func (h *Handler) ListMyListings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marketplaceUser(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset, ok := listingPage(w, r)
+	if !ok {
+		return
+	}
+
+	listings, err := h.marketplace.Mine(r.Context(), userID, limit, offset)
+	if err != nil {
+		marketplaceErrorResponse(w, err, "Failed to list listings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    listings,
+	})
+}
+
+// GetListing handles retrieving a listing
+func (h *Handler) GetListing(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.marketplaceUser(w, r); !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid listing ID")
+		return
+	}
+
+	listing, err := h.marketplace.Get(r.Context(), id)
+	if err != nil {
+		marketplaceErrorResponse(w, err, "Failed to get listing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    listing,
+	})
+}
+
+// CreateListing handles listing the requester's side of an active contract
+// for sale
+func (h *Handler) CreateListing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marketplaceUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateListingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	contractID, err := uuid.Parse(req.ContractID)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	listing, err := h.marketplace.List(r.Context(), userID, contractID, req.Price)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			errorResponse(w, http.StatusNotFound, "Contract not found")
+			return
+		}
+		marketplaceErrorResponse(w, err, "Failed to create listing")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    listing,
+	})
+}
+
+// CancelListing handles a seller withdrawing their open listing
+func (h *Handler) CancelListing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marketplaceUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid listing ID")
+		return
+	}
+
+	listing, err := h.marketplace.Cancel(r.Context(), userID, id)
+	if err != nil {
+		marketplaceErrorResponse(w, err, "Failed to cancel listing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    listing,
+	})
+}
+
+// BuyListing handles accepting a listing, which swaps the position to the
+// buyer's key and pays the seller from the buyer's margin account
+func (h *Handler) BuyListing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.marketplaceUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid listing ID")
+		return
+	}
+
+	var req BuyListingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	listing, err := h.marketplace.Buy(r.Context(), userID, id, req.PubKey)
+	if err != nil {
+		marketplaceErrorResponse(w, err, "Failed to buy listing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    listing,
+	})
+}
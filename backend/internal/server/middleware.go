@@ -0,0 +1,89 @@
+// internal/server/middleware.go
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"hashhedge/internal/logging"
+	"hashhedge/internal/tracing"
+)
+
+var (
+	httpLogger = logging.Component(logging.HTTP)
+	httpTracer = tracing.Tracer(logging.HTTP)
+)
+
+// traceIDHeader carries the ID of the request's trace back to the caller,
+// for operators to look the request up by
+const traceIDHeader = "X-Trace-Id"
+
+// traceRequests starts a span for each request, continuing the caller's
+// trace when the request carries a traceparent header
+func traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := httpTracer.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.target", r.URL.Path),
+				attribute.String("http.request_id", middleware.GetReqID(ctx)),
+			))
+		defer span.End()
+
+		if span.SpanContext().HasTraceID() {
+			w.Header().Set(traceIDHeader, span.SpanContext().TraceID().String())
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		// Name the span after the matched route so requests to one
+		// endpoint group together
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(r.Method + " " + rctx.RoutePattern())
+			span.SetAttributes(attribute.String("http.route", rctx.RoutePattern()))
+		}
+		span.SetAttributes(attribute.Int("http.status_code", ww.Status()))
+		if ww.Status() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(ww.Status()))
+		}
+	})
+}
+
+// requestLogger logs each request through the http component logger
+func requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+
+		next.ServeHTTP(ww, r)
+
+		event := httpLogger.Info()
+		if ww.Status() >= http.StatusInternalServerError {
+			event = httpLogger.Error()
+		} else if ww.Status() >= http.StatusBadRequest {
+			event = httpLogger.Warn()
+		}
+
+		event.
+			Ctx(r.Context()).
+			Str("request_id", middleware.GetReqID(r.Context())).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("remote_addr", r.RemoteAddr).
+			Int("status", ww.Status()).
+			Int("bytes", ww.BytesWritten()).
+			Dur("duration", time.Since(start)).
+			Msg("HTTP request")
+	})
+}
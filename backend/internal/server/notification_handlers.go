@@ -0,0 +1,141 @@
+// internal/server/notification_handlers.go
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/notifications"
+)
+
+// WithNotifications enables the notification endpoints
+func (h *Handler) WithNotifications(service *notifications.Service) *Handler {
+	h.notifications = service
+	return h
+}
+
+// NotificationsResponse is a page of a user's notifications with the number
+// they have not read
+type NotificationsResponse struct {
+	Notifications []*models.Notification `json:"notifications"`
+	Unread        int                    `json:"unread"`
+}
+
+// notificationUserID parses the user ID route parameter and checks access to it
+func (h *Handler) notificationUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.notifications == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Notifications are not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, false)
+}
+
+// ListNotifications handles listing a user's notifications, newest first
+func (h *Handler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.notificationUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	unreadOnly := false
+	if unreadStr := query.Get("unread"); unreadStr != "" {
+		var err error
+		unreadOnly, err = strconv.ParseBool(unreadStr)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid unread")
+			return
+		}
+	}
+
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	list, err := h.notifications.List(r.Context(), userID, unreadOnly, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list notifications")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list notifications")
+		return
+	}
+
+	unread, err := h.notifications.UnreadCount(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to count unread notifications")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list notifications")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    NotificationsResponse{Notifications: list, Unread: unread},
+	})
+}
+
+// MarkNotificationRead handles marking one of a user's notifications read
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.notificationUserID(w, r)
+	if !ok {
+		return
+	}
+
+	notificationID, err := uuid.Parse(chi.URLParam(r, "notificationId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	notification, err := h.notifications.MarkRead(r.Context(), userID, notificationID)
+	if err != nil {
+		storeErrorResponse(w, err, "Notification not found", "Failed to mark notification read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    notification,
+	})
+}
+
+// MarkAllNotificationsRead handles marking every notification of a user read
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.notificationUserID(w, r)
+	if !ok {
+		return
+	}
+
+	marked, err := h.notifications.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to mark notifications read")
+		errorResponse(w, http.StatusInternalServerError, "Failed to mark notifications read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    map[string]int64{"marked": marked},
+	})
+}
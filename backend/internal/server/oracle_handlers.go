@@ -0,0 +1,125 @@
+// internal/server/oracle_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+)
+
+// AnnounceOracleEventRequest represents an oracle's announcement of the
+// nonce it will sign a contract's outcome with
+type AnnounceOracleEventRequest struct {
+	Nonce   string `json:"nonce"`
+	EventID string `json:"event_id"`
+}
+
+// oracleErrorResponse sends the error response for a failed oracle call
+func oracleErrorResponse(w http.ResponseWriter, err error, id, action string) {
+	switch {
+	case errors.Is(err, contract.ErrOracleNotEnabled):
+		errorResponse(w, http.StatusServiceUnavailable, "Oracle attestations are not enabled")
+	case errors.Is(err, contract.ErrInvalidAttestation):
+		errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, db.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, "Contract not found")
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, "Oracle event is already "+action)
+	default:
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to handle oracle event")
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// AnnounceOracleEvent handles an operator recording the oracle event a
+// contract settles on, before its final transaction is built
+func (h *Handler) AnnounceOracleEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	var req AnnounceOracleEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	event, err := h.contractService.AnnounceOracleEvent(r.Context(), contractID, req.Nonce, req.EventID)
+	if err != nil {
+		oracleErrorResponse(w, err, id, "announced")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    event,
+	})
+}
+
+// GetOracleEvent handles retrieving the oracle event a contract settles on
+// and its attestation, if the oracle has signed one
+func (h *Handler) GetOracleEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	event, err := h.contractService.GetOracleEvent(r.Context(), contractID)
+	if err != nil {
+		oracleErrorResponse(w, err, id, "announced")
+		return
+	}
+	if event == nil {
+		errorResponse(w, http.StatusNotFound, "Contract does not settle on an oracle event")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    event,
+	})
+}
+
+// SubmitAttestation handles a party relaying the oracle's signed outcome of
+// a contract, which is verified against the announced event
+func (h *Handler) SubmitAttestation(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	var attestation contract.Attestation
+	if err := json.NewDecoder(r.Body).Decode(&attestation); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	event, err := h.contractService.SubmitAttestation(r.Context(), contractID, attestation)
+	if err != nil {
+		oracleErrorResponse(w, err, id, "attested")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    event,
+	})
+}
@@ -0,0 +1,192 @@
+// internal/server/payout_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+)
+
+// SetPayoutAddressRequest represents a user's registered payout address
+type SetPayoutAddressRequest struct {
+	Address string `json:"address"`
+}
+
+// SetContractPayoutRequest represents a party directing its payout from one contract
+type SetContractPayoutRequest struct {
+	PubKey    string `json:"pub_key"`
+	Address   string `json:"address"`
+	Signature string `json:"signature"` // BIP-340 signature over the payout message, hex encoded
+}
+
+// GetPayoutAddress handles retrieving a user's payout address
+func (h *Handler) GetPayoutAddress(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	address, err := h.contractService.GetPayoutAddress(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, contract.ErrPayoutsNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Payout addresses are not enabled")
+			return
+		}
+		log.Error().Err(err).Str("userID", id).Msg("Failed to get payout address")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get payout address")
+		return
+	}
+
+	if address == nil {
+		errorResponse(w, http.StatusNotFound, "No payout address registered")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    address,
+	})
+}
+
+// SetPayoutAddress handles registering the address a user's winning settlements pay to
+func (h *Handler) SetPayoutAddress(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	var req SetPayoutAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	address, err := h.contractService.SetPayoutAddress(r.Context(), userID, req.Address)
+	if err != nil {
+		if errors.Is(err, contract.ErrPayoutsNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Payout addresses are not enabled")
+			return
+		}
+		log.Error().Err(err).Str("userID", id).Msg("Failed to set payout address")
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    address,
+	})
+}
+
+// DeletePayoutAddress handles removing a user's payout address
+func (h *Handler) DeletePayoutAddress(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	if err := h.contractService.DeletePayoutAddress(r.Context(), userID); err != nil {
+		if errors.Is(err, contract.ErrPayoutsNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Payout addresses are not enabled")
+			return
+		}
+		log.Error().Err(err).Str("userID", id).Msg("Failed to delete payout address")
+		errorResponse(w, http.StatusInternalServerError, "Failed to delete payout address")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Payout address removed",
+	})
+}
+
+// SetContractPayoutAddress handles a party directing its payout from a contract to an address
+func (h *Handler) SetContractPayoutAddress(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	var req SetContractPayoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	payout, err := h.contractService.SetContractPayoutAddress(r.Context(), contractID, req.PubKey, req.Address, req.Signature)
+	if err != nil {
+		if errors.Is(err, contract.ErrPayoutsNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Payout addresses are not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to set contract payout address")
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    payout,
+	})
+}
+
+// GetSettlementOutputs handles retrieving the settlement output and descriptor of each party
+func (h *Handler) GetSettlementOutputs(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	// Payout addresses identify the parties, so only they may see them
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	outputs, err := h.contractService.GetSettlementOutputs(r.Context(), contractID)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get settlement outputs")
+		storeErrorResponse(w, err, "Contract not found", "Failed to get settlement outputs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    outputs,
+	})
+}
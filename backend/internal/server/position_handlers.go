@@ -0,0 +1,51 @@
+// internal/server/position_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/positions"
+)
+
+// WithPositions enables the position and P&L endpoint
+func (h *Handler) WithPositions(service *positions.Service) *Handler {
+	h.positions = service
+	return h
+}
+
+// GetUserPositions handles retrieving a user's positions with their
+// notional at risk and realized and unrealized P&L
+func (h *Handler) GetUserPositions(w http.ResponseWriter, r *http.Request) {
+	if h.positions == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Positions are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	summary, err := h.positions.ForUser(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to get positions")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get positions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    summary,
+	})
+}
@@ -0,0 +1,97 @@
+// internal/server/pricing_handlers.go
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/pricing"
+)
+
+// WithPricing enables indicative premium quotes
+func (h *Handler) WithPricing(service *pricing.Service) *Handler {
+	h.pricing = service
+	return h
+}
+
+// GetPricingQuote handles quoting an indicative premium for a contract from
+// the hash rate and its realized volatility, or the hash rate and
+// volatility given, so order forms can flag prices far from fair value
+func (h *Handler) GetPricingQuote(w http.ResponseWriter, r *http.Request) {
+	if h.pricing == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Pricing is not enabled")
+		return
+	}
+
+	query := r.URL.Query()
+
+	var req pricing.Request
+	switch strings.ToLower(query.Get("type")) {
+	case "call":
+		req.ContractType = models.ContractTypeCall
+	case "put":
+		req.ContractType = models.ContractTypePut
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid contract type")
+		return
+	}
+
+	var err error
+	req.StrikeHashRate, err = strconv.ParseFloat(query.Get("strike_hash_rate"), 64)
+	if err != nil || req.StrikeHashRate <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Invalid strike hash rate")
+		return
+	}
+
+	req.EndBlockHeight, err = strconv.ParseInt(query.Get("end_block_height"), 10, 64)
+	if err != nil || req.EndBlockHeight <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Invalid end block height")
+		return
+	}
+
+	req.ContractSize, err = strconv.ParseInt(query.Get("contract_size"), 10, 64)
+	if err != nil || req.ContractSize <= 0 {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract size")
+		return
+	}
+
+	if hashRateStr := query.Get("hash_rate"); hashRateStr != "" {
+		hashRate, err := strconv.ParseFloat(hashRateStr, 64)
+		if err != nil || hashRate <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid hash rate")
+			return
+		}
+		req.HashRate = &hashRate
+	}
+
+	if volatilityStr := query.Get("volatility"); volatilityStr != "" {
+		volatility, err := strconv.ParseFloat(volatilityStr, 64)
+		if err != nil || volatility < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid volatility")
+			return
+		}
+		req.Volatility = &volatility
+	}
+
+	market, err := h.pricing.Market(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get pricing inputs")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get pricing inputs")
+		return
+	}
+
+	quote, err := market.Quote(req)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    quote,
+	})
+}
@@ -0,0 +1,112 @@
+// internal/server/privacy.go
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/auth"
+	"hashhedge/internal/deadlines"
+	"hashhedge/internal/models"
+	"hashhedge/internal/privacy"
+)
+
+// publicOrderResponse is an order of another participant with its deadline
+// metadata and fiat amounts
+type publicOrderResponse struct {
+	*privacy.PublicOrder
+	Deadlines deadlines.Order      `json:"deadlines"`
+	Fiat      map[string]orderFiat `json:"fiat,omitempty"`
+}
+
+// WithAnonymizer hides participant public keys in public order book and contract responses
+func (h *Handler) WithAnonymizer(anonymizer *privacy.Anonymizer) *Handler {
+	h.anonymizer = anonymizer
+	return h
+}
+
+// viewer identifies the user making a request from their access token or API key
+func (h *Handler) viewer(r *http.Request) (uuid.UUID, bool) {
+	return auth.UserIDFromContext(r.Context())
+}
+
+// isAdmin reports whether the authenticated user of a request is an
+// operator. Without an anonymizer no admins are configured, so nobody is one.
+func (h *Handler) isAdmin(r *http.Request) bool {
+	if h.anonymizer == nil {
+		return false
+	}
+	userID, ok := h.viewer(r)
+	return ok && h.anonymizer.IsAdmin(userID)
+}
+
+// seesEverything reports whether a request may see every participant's details
+func (h *Handler) seesEverything(r *http.Request) bool {
+	if h.anonymizer == nil {
+		return true
+	}
+	userID, ok := h.viewer(r)
+	return ok && h.anonymizer.IsAdmin(userID)
+}
+
+// publicOrders returns the orders as seen by the requester: their own orders
+// in full and everyone else's with the owner replaced by a per-market alias
+func (h *Handler) publicOrders(r *http.Request, orders []*models.Order) []interface{} {
+	responses := make([]interface{}, 0, len(orders))
+	if h.seesEverything(r) {
+		for _, o := range h.withOrderDeadlines(r.Context(), orders...) {
+			responses = append(responses, o)
+		}
+		return responses
+	}
+
+	userID, _ := h.viewer(r)
+	now := time.Now().UTC()
+	prices := h.fiatPrices(r.Context())
+	for _, o := range orders {
+		if userID != uuid.Nil && o.UserID == userID {
+			responses = append(responses, orderResponse{
+				Order:     o,
+				Deadlines: deadlines.ForOrder(o, now),
+				Fiat:      orderFiatAmounts(o.Price, o.Quantity, prices),
+			})
+			continue
+		}
+		responses = append(responses, publicOrderResponse{
+			PublicOrder: h.anonymizer.Order(o),
+			Deadlines:   deadlines.ForOrder(o, now),
+			Fiat:        orderFiatAmounts(o.Price, o.Quantity, prices),
+		})
+	}
+	return responses
+}
+
+// publicContracts replaces the public keys of contracts the requester is not
+// a party to with per-market aliases
+func (h *Handler) publicContracts(r *http.Request, contracts []contractResponse) []contractResponse {
+	if h.seesEverything(r) {
+		return contracts
+	}
+
+	ownKeys := make(map[string]bool)
+	if userID, ok := h.viewer(r); ok {
+		keys, err := h.userRepo.GetKeysByUserID(r.Context(), userID)
+		if err != nil {
+			log.Warn().Err(err).Str("userID", userID.String()).Msg("Failed to get keys for contract visibility")
+		}
+		for _, key := range keys {
+			ownKeys[key.PubKey] = true
+		}
+	}
+
+	for i, c := range contracts {
+		if ownKeys[c.BuyerPubKey] || ownKeys[c.SellerPubKey] {
+			continue
+		}
+		contracts[i].Contract = h.anonymizer.Contract(c.Contract)
+	}
+	return contracts
+}
@@ -0,0 +1,153 @@
+// internal/server/protection_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// WithMarketMakerProtection enables the market maker protection endpoints
+func (h *Handler) WithMarketMakerProtection(repo *db.MarketMakerProtectionRepository) *Handler {
+	h.protections = repo
+	return h
+}
+
+// SetProtectionRequest represents the request to set the market maker
+// protection of an API key
+type SetProtectionRequest struct {
+	MaxFills      int `json:"max_fills"`
+	WindowSeconds int `json:"window_seconds"`
+	FreezeSeconds int `json:"freeze_seconds"` // Optional: frozen until reset when zero
+}
+
+// protectionResponse is a protection's settings with its live state
+type protectionResponse struct {
+	*models.MarketMakerProtection
+	orderbook.ProtectionState
+}
+
+// protectionKey parses the user and API key route parameters and checks
+// access to them
+func (h *Handler) protectionKey(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	if h.protections == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Market maker protection is not enabled")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, ok := h.usageUserID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid API key ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, keyID, true
+}
+
+// GetProtection handles retrieving the market maker protection of an API key
+func (h *Handler) GetProtection(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := h.protectionKey(w, r)
+	if !ok {
+		return
+	}
+
+	protection, err := h.protections.Get(r.Context(), userID, keyID)
+	if err != nil {
+		storeErrorResponse(w, err, "Market maker protection not found", "Failed to get market maker protection")
+		return
+	}
+
+	state, _ := h.orderBook.Protection(keyID)
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    protectionResponse{MarketMakerProtection: protection, ProtectionState: state},
+	})
+}
+
+// SetProtection handles enabling or updating the market maker protection of an API key
+func (h *Handler) SetProtection(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := h.protectionKey(w, r)
+	if !ok {
+		return
+	}
+
+	var req SetProtectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	protection := &models.MarketMakerProtection{
+		APIKeyID:      keyID,
+		MaxFills:      req.MaxFills,
+		WindowSeconds: req.WindowSeconds,
+		FreezeSeconds: req.FreezeSeconds,
+	}
+	if err := protection.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.protections.Set(r.Context(), userID, protection); err != nil {
+		storeErrorResponse(w, err, "API key not found", "Failed to set market maker protection")
+		return
+	}
+	h.orderBook.SetProtection(protection)
+
+	state, _ := h.orderBook.Protection(keyID)
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    protectionResponse{MarketMakerProtection: protection, ProtectionState: state},
+	})
+}
+
+// DeleteProtection handles disabling the market maker protection of an API key
+func (h *Handler) DeleteProtection(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := h.protectionKey(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.protections.Delete(r.Context(), userID, keyID); err != nil {
+		storeErrorResponse(w, err, "Market maker protection not found", "Failed to delete market maker protection")
+		return
+	}
+	h.orderBook.RemoveProtection(keyID)
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Market maker protection disabled",
+	})
+}
+
+// ResetProtection handles unfreezing an API key whose protection has triggered
+func (h *Handler) ResetProtection(w http.ResponseWriter, r *http.Request) {
+	userID, keyID, ok := h.protectionKey(w, r)
+	if !ok {
+		return
+	}
+
+	// Check ownership before touching the engine state
+	if _, err := h.protections.Get(r.Context(), userID, keyID); err != nil {
+		storeErrorResponse(w, err, "Market maker protection not found", "Failed to reset market maker protection")
+		return
+	}
+
+	h.orderBook.ResetProtection(keyID)
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Market maker protection reset",
+	})
+}
@@ -0,0 +1,170 @@
+// internal/server/push_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/push"
+)
+
+// WithPushService enables the device registration and push preference endpoints
+func (h *Handler) WithPushService(svc *push.Service) *Handler {
+	h.pushService = svc
+	return h
+}
+
+// RegisterDeviceRequest represents the request to register a mobile device for push
+type RegisterDeviceRequest struct {
+	Platform string `json:"platform"`
+	Token    string `json:"token"`
+}
+
+// UpdatePushPreferencesRequest maps event types to whether they are pushed
+type UpdatePushPreferencesRequest map[string]bool
+
+// pushUserID parses the user ID route parameter and checks access to it
+func (h *Handler) pushUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.pushService == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Push notifications are not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, false)
+}
+
+// ListDevices handles listing a user's registered devices
+func (h *Handler) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pushUserID(w, r)
+	if !ok {
+		return
+	}
+
+	devices, err := h.pushService.ListDevices(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list devices")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list devices")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    devices,
+	})
+}
+
+// RegisterDevice handles registering a device token for push notifications
+func (h *Handler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pushUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RegisterDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	device := &models.DeviceToken{
+		UserID:   userID,
+		Platform: models.PushPlatform(strings.ToUpper(req.Platform)),
+		Token:    req.Token,
+	}
+
+	if err := h.pushService.RegisterDevice(r.Context(), device); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    device,
+	})
+}
+
+// RemoveDevice handles unregistering a device
+func (h *Handler) RemoveDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pushUserID(w, r)
+	if !ok {
+		return
+	}
+
+	deviceID, err := uuid.Parse(chi.URLParam(r, "deviceId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid device ID")
+		return
+	}
+
+	if err := h.pushService.RemoveDevice(r.Context(), userID, deviceID); err != nil {
+		storeErrorResponse(w, err, "Device not found", "Failed to remove device")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Device removed successfully",
+	})
+}
+
+// GetPushPreferences handles retrieving a user's push preferences
+func (h *Handler) GetPushPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pushUserID(w, r)
+	if !ok {
+		return
+	}
+
+	prefs, err := h.pushService.ListPreferences(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get push preferences")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get push preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    prefs,
+	})
+}
+
+// UpdatePushPreferences handles enabling or disabling push event types
+func (h *Handler) UpdatePushPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.pushUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdatePushPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	for eventType := range req {
+		if !models.IsValidPushEventType(models.PushEventType(eventType)) {
+			errorResponse(w, http.StatusBadRequest, "Invalid push event type: "+eventType)
+			return
+		}
+	}
+
+	for eventType, enabled := range req {
+		pref := &models.PushPreference{
+			UserID:    userID,
+			EventType: models.PushEventType(eventType),
+			Enabled:   enabled,
+		}
+		if err := h.pushService.SetPreference(r.Context(), pref); err != nil {
+			log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to update push preference")
+			errorResponse(w, http.StatusInternalServerError, "Failed to update push preferences")
+			return
+		}
+	}
+
+	h.GetPushPreferences(w, r)
+}
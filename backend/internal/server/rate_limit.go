@@ -0,0 +1,62 @@
+// internal/server/rate_limit.go
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"hashhedge/internal/auth"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/ratelimit"
+	"hashhedge/internal/usage"
+)
+
+// WithRateLimiter enables per-client rate limits on the API
+func (h *Handler) WithRateLimiter(limiter *ratelimit.Limiter) *Handler {
+	h.rateLimiter = limiter
+	return h
+}
+
+// rateLimit rejects requests to the API version mounted at root once their
+// client has used up its bucket for the request's class. It runs after
+// authentication, so clients are told apart by API key, then user, then IP.
+func (h *Handler) rateLimit(root string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if h.rateLimiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			class := ratelimit.Classify(r.Method, strings.TrimPrefix(r.URL.Path, root))
+			ok, wait := h.rateLimiter.Allow(class, rateLimitClient(r))
+			if !ok {
+				metrics.RateLimited.WithLabelValues(string(class)).Inc()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				errorResponse(w, http.StatusTooManyRequests, "Rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the client a request is counted against
+func rateLimitClient(r *http.Request) string {
+	if key, ok := usage.APIKeyFromContext(r.Context()); ok {
+		return "key:" + key.ID.String()
+	}
+	if userID, ok := auth.UserIDFromContext(r.Context()); ok {
+		return "user:" + userID.String()
+	}
+	// RealIP leaves the port on addresses not taken from a proxy header
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
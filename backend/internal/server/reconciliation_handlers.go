@@ -0,0 +1,94 @@
+// internal/server/reconciliation_handlers.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/reconciliation"
+)
+
+// defaultReconciliationDays is the period exported when no from date is given
+const defaultReconciliationDays = 30
+
+// WithReconciliation enables the accounting reconciliation export
+func (h *Handler) WithReconciliation(repo *db.SnapshotRepository) *Handler {
+	h.snapshotRepo = repo
+	return h
+}
+
+// parseReconciliationRange reads the from and to query parameters as UTC
+// dates, from inclusive and to exclusive, defaulting to the last 30 days
+// up to and including today
+func parseReconciliationRange(r *http.Request) (time.Time, time.Time, bool) {
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -defaultReconciliationDays)
+
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return from, to, false
+		}
+		from = t
+	}
+	if s := r.URL.Query().Get("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return from, to, false
+		}
+		to = t
+	}
+
+	return from, to, from.Before(to)
+}
+
+// GetReconciliation handles exporting every satoshi moved in and out of the
+// contracts over a period, per contract and per day. With format=csv the
+// entries are returned as a CSV file for accounting software.
+func (h *Handler) GetReconciliation(w http.ResponseWriter, r *http.Request) {
+	if h.snapshotRepo == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Reconciliation export is not enabled")
+		return
+	}
+
+	from, to, ok := parseReconciliationRange(r)
+	if !ok {
+		errorResponse(w, http.StatusBadRequest, "Invalid from/to range, expected YYYY-MM-DD dates with from before to")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		errorResponse(w, http.StatusBadRequest, "Invalid format, expected json or csv")
+		return
+	}
+
+	state, err := h.snapshotRepo.Load(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load contract state for reconciliation")
+		errorResponse(w, http.StatusInternalServerError, "Failed to build reconciliation")
+		return
+	}
+
+	report := reconciliation.Build(state, from, to)
+
+	if format == "csv" {
+		filename := fmt.Sprintf("reconciliation-%s-%s.csv", from.Format("20060102"), to.Format("20060102"))
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+		w.WriteHeader(http.StatusOK)
+		if err := reconciliation.WriteCSV(w, report); err != nil {
+			log.Error().Err(err).Msg("Failed to write reconciliation CSV")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    report,
+	})
+}
@@ -0,0 +1,231 @@
+// internal/server/research_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/research"
+)
+
+// WithResearchFeed enables the research data feed. Dumps may be nil when
+// no dump directory is configured.
+func (h *Handler) WithResearchFeed(service *research.Service, dumps *research.Dumper) *Handler {
+	h.research = service
+	h.researchDumps = dumps
+	return h
+}
+
+// GrantResearchAccessRequest represents the request to grant a user access
+// to the research feed
+type GrantResearchAccessRequest struct {
+	Tier      models.ResearchTier `json:"tier"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+}
+
+// researchEnabled reports whether the research feed is enabled, responding if not
+func (h *Handler) researchEnabled(w http.ResponseWriter) bool {
+	if h.research == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Research feed is not enabled")
+		return false
+	}
+	return true
+}
+
+// researchAccess returns the caller's active research grant, responding if
+// they have none
+func (h *Handler) researchAccess(w http.ResponseWriter, r *http.Request) (*models.ResearchAccess, bool) {
+	if !h.researchEnabled(w) {
+		return nil, false
+	}
+
+	userID, _ := h.viewer(r)
+	access, err := h.research.Access(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			errorResponse(w, http.StatusForbidden, "Research feed access required")
+			return nil, false
+		}
+		log.Error().Err(err).Msg("Failed to get research access")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get research access")
+		return nil, false
+	}
+	return access, true
+}
+
+// GetResearchDataset handles reading a page of a research dataset
+func (h *Handler) GetResearchDataset(w http.ResponseWriter, r *http.Request) {
+	access, ok := h.researchAccess(w, r)
+	if !ok {
+		return
+	}
+
+	dataset, err := research.ParseDataset(chi.URLParam(r, "dataset"))
+	if err != nil {
+		errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	page, err := h.research.Page(r.Context(), access.Tier, dataset, r.URL.Query().Get("cursor"), time.Now().UTC())
+	if err != nil {
+		switch {
+		case errors.Is(err, research.ErrInvalidCursor):
+			errorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, research.ErrDatasetNotInTier):
+			errorResponse(w, http.StatusForbidden, err.Error())
+		default:
+			log.Error().Err(err).Str("dataset", string(dataset)).Msg("Failed to read research dataset")
+			errorResponse(w, http.StatusInternalServerError, "Failed to read research dataset")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    page,
+	})
+}
+
+// researchDumpsAccess checks that the caller may download dump files
+func (h *Handler) researchDumpsAccess(w http.ResponseWriter, r *http.Request) bool {
+	access, ok := h.researchAccess(w, r)
+	if !ok {
+		return false
+	}
+	if access.Tier != models.ResearchTierFull {
+		errorResponse(w, http.StatusForbidden, "Dump files require the full research tier")
+		return false
+	}
+	if h.researchDumps == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Research dumps are not enabled")
+		return false
+	}
+	return true
+}
+
+// ListResearchDumps handles listing the daily dump files
+func (h *Handler) ListResearchDumps(w http.ResponseWriter, r *http.Request) {
+	if !h.researchDumpsAccess(w, r) {
+		return
+	}
+
+	files, err := h.researchDumps.List()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list research dumps")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list research dumps")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    files,
+	})
+}
+
+// DownloadResearchDump handles downloading a daily dump file
+func (h *Handler) DownloadResearchDump(w http.ResponseWriter, r *http.Request) {
+	if !h.researchDumpsAccess(w, r) {
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	f, err := h.researchDumps.Open(name)
+	if err != nil {
+		if errors.Is(err, research.ErrDumpNotFound) {
+			errorResponse(w, http.StatusNotFound, "Research dump not found")
+			return
+		}
+		log.Error().Err(err).Str("name", name).Msg("Failed to open research dump")
+		errorResponse(w, http.StatusInternalServerError, "Failed to open research dump")
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", name))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, f)
+}
+
+// ListResearchAccess handles listing the research feed grants
+func (h *Handler) ListResearchAccess(w http.ResponseWriter, r *http.Request) {
+	if !h.researchEnabled(w) {
+		return
+	}
+
+	grants, err := h.research.ListAccess(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list research access")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list research access")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    grants,
+	})
+}
+
+// GrantResearchAccess handles granting a user research feed access
+func (h *Handler) GrantResearchAccess(w http.ResponseWriter, r *http.Request) {
+	if !h.researchEnabled(w) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req GrantResearchAccessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	access := &models.ResearchAccess{UserID: userID, Tier: req.Tier, ExpiresAt: req.ExpiresAt}
+	if err := h.research.GrantAccess(r.Context(), access); err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to grant research access")
+		storeErrorResponse(w, err, "User not found", "Failed to grant research access")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    access,
+	})
+}
+
+// RevokeResearchAccess handles revoking a user's research feed access
+func (h *Handler) RevokeResearchAccess(w http.ResponseWriter, r *http.Request) {
+	if !h.researchEnabled(w) {
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.research.RevokeAccess(r.Context(), userID); err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to revoke research access")
+		storeErrorResponse(w, err, "Research access not found", "Failed to revoke research access")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+	})
+}
@@ -0,0 +1,128 @@
+// internal/server/rollover_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/rollover"
+)
+
+// WithRolloverScheduler enables auto-roll orders
+func (h *Handler) WithRolloverScheduler(scheduler *rollover.Scheduler) *Handler {
+	h.rollover = scheduler
+	return h
+}
+
+// AutoRollRequest represents the request to flag an order for auto-roll
+type AutoRollRequest struct {
+	PriceOffset int64 `json:"price_offset"` // In satoshis, added to the order price
+}
+
+// GetOrderAutoRoll handles retrieving the pending auto-roll of an order
+func (h *Handler) GetOrderAutoRoll(w http.ResponseWriter, r *http.Request) {
+	if h.rollover == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Auto-roll is not enabled")
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	roll, err := h.rollover.Get(r.Context(), orderID)
+	if err != nil {
+		storeErrorResponse(w, err, "Order is not set to auto-roll", "Failed to get auto-roll")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    roll,
+	})
+}
+
+// EnableOrderAutoRoll handles flagging an order to roll into the next expiry
+func (h *Handler) EnableOrderAutoRoll(w http.ResponseWriter, r *http.Request) {
+	if h.rollover == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Auto-roll is not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	var req AutoRollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	order, err := h.orderBook.GetOrderByID(r.Context(), orderID)
+	if err != nil {
+		storeErrorResponse(w, err, "Order not found", "Failed to get order")
+		return
+	}
+
+	if !h.validateUserPermissions(r, order.UserID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	roll, err := h.rollover.Enable(r.Context(), order, req.PriceOffset)
+	if err != nil {
+		log.Warn().Err(err).Str("orderID", id).Msg("Failed to enable auto-roll")
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    roll,
+	})
+}
+
+// DisableOrderAutoRoll handles removing the auto-roll flag from an order
+func (h *Handler) DisableOrderAutoRoll(w http.ResponseWriter, r *http.Request) {
+	if h.rollover == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Auto-roll is not enabled")
+		return
+	}
+
+	orderID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid order ID")
+		return
+	}
+
+	order, err := h.orderBook.GetOrderByID(r.Context(), orderID)
+	if err != nil {
+		storeErrorResponse(w, err, "Order not found", "Failed to get order")
+		return
+	}
+
+	if !h.validateUserPermissions(r, order.UserID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	if err := h.rollover.Disable(r.Context(), order.UserID, orderID); err != nil {
+		storeErrorResponse(w, err, "Order is not set to auto-roll", "Failed to disable auto-roll")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Auto-roll disabled successfully",
+	})
+}
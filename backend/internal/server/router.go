@@ -8,6 +8,9 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
 )
 
 // NewRouter creates a new HTTP router
@@ -17,56 +20,313 @@ func NewRouter(h *Handler) http.Handler {
 	// Basic middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(traceRequests)
+	r.Use(requestLogger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	// CORS middleware
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", apiKeyHeader, watchtowerTokenHeader, acceptVersionHeader, "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"Link", apiVersionHeader, deprecationHeader, sunsetHeader, "Retry-After", traceIDHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	// API routes
-	r.Route("/api/v1", func(r chi.Router) {
+	// Per-API-key usage tracking
+	r.Use(h.trackUsage)
+
+	// Bearer token or API key authentication
+	r.Use(h.authenticate)
+
+	// Versioned API routes. Requests without a version are redirected to
+	// the version they negotiate.
+	mountAPIVersions(r, h)
+	r.Handle("/api/*", negotiateAPIVersion())
+
+	// WebSocket endpoint
+	r.Get("/ws", h.ServeWebSocket)
+
+	// Health check endpoint
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+	r.Get("/health/bitcoin", h.GetBitcoinHealth)
+
+	// Prometheus metrics for operators
+	r.Handle("/metrics", metrics.Handler())
+
+	return r
+}
+
+// v1Routes registers the routes of version 1 of the API
+func (h *Handler) v1Routes(r chi.Router) {
+	// Account routes
+	r.Route("/auth", func(r chi.Router) {
+		r.Post("/register", h.Register)
+		r.Post("/login", h.Login)
+		r.Post("/refresh", h.RefreshTokens)
+		r.With(h.requireUser).Get("/me", h.GetCurrentUser)
+	})
+
+	// Routes acting on behalf of a user require authentication
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireUser)
+
 		// Contract routes
 		r.Route("/contracts", func(r chi.Router) {
+			r.Use(h.audited(models.AuditResourceContract, h.contractSnapshot))
+
 			r.Get("/", h.ListActiveContracts)
 			r.Post("/", h.CreateContract)
 			r.Get("/{id}", h.GetContract)
 			r.Post("/{id}/setup", h.SetupContract)
 			r.Post("/{id}/final", h.GenerateFinalTx)
 			r.Post("/{id}/settle", h.SettleContract)
+			r.Get("/{id}/settlement-deferral", h.GetSettlementDeferral)
+			r.Get("/{id}/settlement-broadcast", h.GetSettlementBroadcast)
+			r.Post("/{id}/fee-bump", h.BumpSettlementFee)
+			r.Post("/{id}/payout-address", h.SetContractPayoutAddress)
+			r.Get("/{id}/settlement-outputs", h.GetSettlementOutputs)
 			r.Post("/{id}/broadcast", h.BroadcastTx)
 			r.Post("/{id}/swap", h.SwapContractParticipant)
+			r.Get("/{id}/funding", h.GetContractFunding)
+			r.Post("/{id}/funding", h.SubmitContractFunding)
+			r.Get("/{id}/inputs", h.ListContractInputs)
+			r.Post("/{id}/inputs", h.AddContractInput)
+			r.Get("/{id}/timeline", h.GetContractTimeline)
+			r.Get("/{id}/status-history", h.GetContractStatusHistory)
+			r.Get("/{id}/exit-transactions", h.ListExitTransactions)
+			r.Get("/{id}/scheduled-close", h.GetScheduledClose)
+			r.Post("/{id}/scheduled-close", h.SubmitCloseIntent)
+			r.Get("/{id}/evidence", h.GetSettlementEvidence)
+			r.Get("/{id}/evidence/verify", h.VerifySettlementEvidence)
+			r.Get("/{id}/evidence/proof", h.DownloadEvidenceProof)
+			r.Get("/{id}/oracle-event", h.GetOracleEvent)
+			r.Post("/{id}/attestation", h.SubmitAttestation)
+			r.Get("/{id}/delegations", h.ListContractDelegations)
+			r.Post("/{id}/delegations", h.DelegateContractExit)
+			r.Get("/{id}/delegations/{delegationId}/export", h.ExportContractDelegation)
+			r.Delete("/{id}/delegations/{delegationId}", h.RevokeContractDelegation)
 			r.Delete("/{id}", h.CancelContract)
 		})
 
 		// Order routes
 		r.Route("/orders", func(r chi.Router) {
+			r.Use(h.audited(models.AuditResourceOrder, h.orderSnapshot))
+
 			r.Post("/", h.PlaceOrder)
+			r.Patch("/{id}", h.AmendOrder)
 			r.Delete("/{id}", h.CancelOrder)
 			r.Get("/user/{id}", h.GetUserOrders)
+			r.Get("/{id}/auto-roll", h.GetOrderAutoRoll)
+			r.Put("/{id}/auto-roll", h.EnableOrderAutoRoll)
+			r.Delete("/{id}/auto-roll", h.DisableOrderAutoRoll)
+		})
+
+		r.Route("/wallet", func(r chi.Router) {
+			r.Get("/deposit-address", h.GetDepositAddress)
+			r.Get("/deposits", h.ListDeposits)
+			r.Post("/withdrawals", h.RequestWithdrawal)
+			r.Get("/withdrawals", h.ListWithdrawals)
+			h.setupWalletRoutes(r)
+		})
+
+		// Key routes
+		r.Route("/users/{id}/keys", func(r chi.Router) {
+			r.Get("/", h.ListUserKeys)
+			r.Post("/", h.AddUserKey)
+			r.Put("/{keyId}/default", h.SetDefaultUserKey)
+			r.Delete("/{keyId}", h.DeleteUserKey)
+		})
+
+		// Watchlist routes
+		r.Route("/users/{id}/watchlist", func(r chi.Router) {
+			r.Get("/", h.GetWatchlist)
+			r.Post("/", h.AddWatchlistItem)
+			r.Delete("/{itemId}", h.RemoveWatchlistItem)
+		})
+
+		// Price alert routes
+		r.Route("/users/{id}/alerts", func(r chi.Router) {
+			r.Get("/", h.ListPriceAlerts)
+			r.Post("/", h.CreatePriceAlert)
+			r.Delete("/{alertId}", h.CancelPriceAlert)
+		})
+
+		// Push notification routes
+		r.Route("/users/{id}/devices", func(r chi.Router) {
+			r.Get("/", h.ListDevices)
+			r.Post("/", h.RegisterDevice)
+			r.Delete("/{deviceId}", h.RemoveDevice)
+		})
+		r.Get("/users/{id}/push-preferences", h.GetPushPreferences)
+		r.Put("/users/{id}/push-preferences", h.UpdatePushPreferences)
+
+		// Notification routes
+		r.Route("/users/{id}/notifications", func(r chi.Router) {
+			r.Get("/", h.ListNotifications)
+			r.Post("/read", h.MarkAllNotificationsRead)
+			r.Post("/{notificationId}/read", h.MarkNotificationRead)
+		})
+
+		// Webhook routes
+		r.Route("/users/{id}/webhooks", func(r chi.Router) {
+			r.Get("/", h.ListWebhooks)
+			r.Post("/", h.RegisterWebhook)
+			r.Delete("/{webhookId}", h.RemoveWebhook)
+			r.Get("/dead-letters", h.ListWebhookDeadLetters)
+			r.Post("/dead-letters/{deliveryId}/redeliver", h.RedeliverWebhook)
 		})
 
-        r.Route("/wallet", func(r chi.Router) {
-        })
+		// Payout address routes
+		r.Route("/users/{id}/payout-address", func(r chi.Router) {
+			r.Get("/", h.GetPayoutAddress)
+			r.Put("/", h.SetPayoutAddress)
+			r.Delete("/", h.DeletePayoutAddress)
+		})
 
-        h.setupWalletRoutes(r)
+		// API key and usage routes
+		r.Route("/users/{id}/api-keys", func(r chi.Router) {
+			r.Get("/", h.ListAPIKeys)
+			r.Post("/", h.CreateAPIKey)
+			r.Delete("/{keyId}", h.RevokeAPIKey)
+			r.Get("/{keyId}/protection", h.GetProtection)
+			r.Put("/{keyId}/protection", h.SetProtection)
+			r.Delete("/{keyId}/protection", h.DeleteProtection)
+			r.Post("/{keyId}/protection/reset", h.ResetProtection)
+		})
+		r.Get("/users/{id}/usage", h.GetUserUsage)
+		r.Get("/users/{id}/positions", h.GetUserPositions)
+		r.Get("/users/{id}/trade-report", h.ExportTradeReport)
+
+		// Margin routes
+		r.Route("/users/{id}/margin", func(r chi.Router) {
+			r.Get("/", h.GetMarginSummary)
+			r.Post("/withdraw", h.WithdrawMargin)
+			r.Post("/positions", h.OpenMarginPosition)
+			r.Post("/positions/{positionId}/top-up", h.TopUpMarginPosition)
+		})
+
+		// Balance routes
+		r.Route("/users/{id}/balance", func(r chi.Router) {
+			r.Get("/", h.GetBalance)
+			r.Get("/entries", h.ListBalanceEntries)
+		})
+
+		// Secondary market routes
+		r.Route("/marketplace/listings", func(r chi.Router) {
+			r.Get("/", h.ListListings)
+			r.Post("/", h.CreateListing)
+			r.Get("/mine", h.ListMyListings)
+			r.Get("/{id}", h.GetListing)
+			r.Delete("/{id}", h.CancelListing)
+			r.Post("/{id}/buy", h.BuyListing)
+		})
 
-		// Order book routes
-		r.Get("/orderbook", h.GetOrderBook)
+		// Research feed routes, for users granted research access
+		r.Route("/research", func(r chi.Router) {
+			r.Get("/dumps", h.ListResearchDumps)
+			r.Get("/dumps/{name}", h.DownloadResearchDump)
+			r.Get("/{dataset}", h.GetResearchDataset)
+		})
 	})
 
-	// Health check endpoint
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
+	// Watchtower routes, authenticated by watchtower token
+	r.Route("/watchtower/delegations", func(r chi.Router) {
+		r.Use(h.requireWatchtower)
+		r.Get("/", h.PollDelegations)
+		r.Post("/{id}/ack", h.AcknowledgeDelegation)
 	})
 
-	return r
+	// Order book routes
+	r.Get("/orderbook", h.GetOrderBook)
+
+	// Best bid and offer with 24 hour trading, live from the matching path
+	r.Get("/ticker", h.GetTicker)
+
+	// Public market data, cached and safe for anonymous traffic
+	r.Route("/market", func(r chi.Router) {
+		r.Get("/depth", h.GetMarketDepth)
+		r.Get("/tickers", h.GetMarketTickers)
+		r.Get("/hashrate", h.GetMarketHashRate)
+		r.Get("/stats", h.GetMarketStats)
+		r.Get("/open-interest", h.GetMarketOpenInterest)
+	})
+
+	// Trade history and candles, cached like the public market data
+	r.Get("/trades", h.GetMarketTrades)
+	r.Get("/candles", h.GetMarketCandles)
+
+	// Hash rate index, cached like the public market data
+	r.Route("/hashrate", func(r chi.Router) {
+		r.Get("/", h.GetHashRateIndex)
+		r.Get("/history", h.GetHashRateHistory)
+		r.Get("/statistics", h.GetHashRateStatistics)
+		r.Get("/ladder", h.GetStrikeLadder)
+	})
+
+	// Indicative premiums from the hash rate index
+	r.Get("/pricing/quote", h.GetPricingQuote)
+
+	// Chain fee routes
+	r.Get("/fees", h.GetFeeStatus)
+
+	// Analytics routes
+	r.Route("/analytics/feeds", func(r chi.Router) {
+		r.Get("/", h.ListFeeds)
+		r.Get("/{name}", h.GetFeedObservations)
+		r.Get("/{name}/volatility", h.GetFeedVolatility)
+	})
+	r.Get("/analytics/open-interest", h.GetOpenInterestHistory)
+	r.Get("/analytics/liquidity", h.ListLiquidity)
+	r.Get("/analytics/liquidity/market", h.GetMarketLiquidity)
+
+	// Admin routes, for the users configured as admins
+	r.Group(func(r chi.Router) {
+		r.Use(h.requireAdmin)
+
+		r.Route("/admin/logging", func(r chi.Router) {
+			r.Get("/", h.GetLoggingStatus)
+			r.Put("/", h.UpdateLogging)
+		})
+		r.Post("/admin/orderbook/resync", h.ResyncOrderBook)
+		r.Get("/admin/usage", h.GetUsageAggregates)
+		r.Get("/admin/websocket", h.GetWebSocketStats)
+		r.Get("/admin/schedule", h.GetSchedule)
+		r.Get("/admin/reconciliation", h.GetReconciliation)
+		r.Get("/admin/settlements", h.ListSettlementAttempts)
+		r.Post("/admin/settlements/batch", h.SettleAllEligible)
+		r.Get("/admin/settlements/stuck", h.ListStuckSettlements)
+		r.Get("/admin/audit", h.ListAuditLog)
+		r.Post("/admin/contracts/{id}/oracle-event", h.AnnounceOracleEvent)
+		r.Post("/admin/users/{id}/margin/deposit", h.DepositMargin)
+		r.Post("/admin/users/{id}/balance/deposit", h.DepositBalance)
+		r.Route("/admin/withdrawals", func(r chi.Router) {
+			r.Get("/", h.ListWithdrawalsByStatus)
+			r.Post("/{id}/approve", h.ApproveWithdrawal)
+			r.Post("/{id}/reject", h.RejectWithdrawal)
+		})
+		r.Get("/admin/exit-monitor", h.GetExitMonitor)
+		r.Post("/admin/exit-monitor/resume", h.ResumeOffChain)
+		r.Get("/admin/asps", h.ListASPs)
+		r.Route("/admin/watchtowers", func(r chi.Router) {
+			r.Get("/", h.ListWatchtowers)
+			r.Post("/", h.RegisterWatchtower)
+			r.Delete("/{id}", h.RevokeWatchtower)
+		})
+		r.Route("/admin/research/access", func(r chi.Router) {
+			r.Get("/", h.ListResearchAccess)
+			r.Put("/{userId}", h.GrantResearchAccess)
+			r.Delete("/{userId}", h.RevokeResearchAccess)
+		})
+		r.Route("/admin/jobs", func(r chi.Router) {
+			r.Get("/", h.ListJobs)
+			r.Get("/{id}", h.GetJob)
+			r.Post("/{id}/replay", h.ReplayJob)
+		})
+	})
 }
@@ -0,0 +1,179 @@
+// internal/server/router_test.go
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/auth"
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/privacy"
+)
+
+// memoryContracts holds contracts in memory
+type memoryContracts struct {
+	contract.ContractStore
+	contracts map[uuid.UUID]*models.Contract
+}
+
+func (s memoryContracts) GetByID(ctx context.Context, id uuid.UUID) (*models.Contract, error) {
+	c, ok := s.contracts[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+// memoryKeys holds the keys of each user in memory
+type memoryKeys map[uuid.UUID][]*models.UserKey
+
+func (s memoryKeys) GetKeysByUserID(ctx context.Context, userID uuid.UUID) ([]*models.UserKey, error) {
+	return s[userID], nil
+}
+
+// testAPI serves version 1 of the API with bearer token authentication,
+// one admin, and a contract between a buyer and a seller
+type testAPI struct {
+	router   http.Handler
+	tokens   *auth.Tokens
+	admin    uuid.UUID
+	buyer    uuid.UUID
+	stranger uuid.UUID
+	contract *models.Contract
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+
+	api := &testAPI{admin: uuid.New(), buyer: uuid.New(), stranger: uuid.New()}
+	api.contract = &models.Contract{
+		ID:           uuid.New(),
+		Status:       models.ContractStatusActive,
+		BuyerPubKey:  "02" + strings.Repeat("aa", 32),
+		SellerPubKey: "03" + strings.Repeat("bb", 32),
+	}
+
+	cfg := auth.DefaultConfig
+	cfg.JWTSecret = strings.Repeat("s", 64)
+	service, err := auth.NewService(nil, cfg)
+	require.NoError(t, err)
+	api.tokens, err = auth.NewTokens(cfg)
+	require.NoError(t, err)
+
+	anonymizer, err := privacy.NewAnonymizer(privacy.Config{AdminUserIDs: []string{api.admin.String()}})
+	require.NoError(t, err)
+
+	contracts := contract.NewService(memoryContracts{contracts: map[uuid.UUID]*models.Contract{api.contract.ID: api.contract}}, nil, nil, nil, nil)
+	contracts.WithUserKeys(memoryKeys{
+		api.buyer:    {{PubKey: api.contract.BuyerPubKey}},
+		api.stranger: {{PubKey: "02" + strings.Repeat("cc", 32)}},
+	})
+
+	h := NewHandler(contracts, nil, nil).WithAuth(service).WithAnonymizer(anonymizer)
+	api.router = NewRouter(h)
+	return api
+}
+
+// do sends a request as a user, or anonymously for uuid.Nil, and returns
+// the response status
+func (api *testAPI) do(t *testing.T, userID uuid.UUID, method, path, body string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/api/v1"+path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if userID != uuid.Nil {
+		pair, err := api.tokens.Issue(userID, time.Now())
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+	}
+
+	rec := httptest.NewRecorder()
+	api.router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	api := newTestAPI(t)
+
+	for _, path := range []string{"/admin/schedule", "/admin/exit-monitor", "/admin/asps"} {
+		assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodGet, path, ""), path)
+		assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodGet, path, ""), path)
+	}
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, "/admin/exit-monitor/resume", ""))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPut, "/admin/logging", `{"component":"api","level":"debug"}`))
+	assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodPost, "/admin/jobs/"+uuid.NewString()+"/replay", ""))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, "/admin/jobs/"+uuid.NewString()+"/replay", ""))
+
+	// Operators credit deposits to any user; users cannot credit themselves
+	marginDeposit := "/admin/users/" + api.buyer.String() + "/margin/deposit"
+	assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodPost, marginDeposit, `{"amount":1000}`))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, marginDeposit, `{"amount":1000}`))
+	balanceDeposit := "/admin/users/" + api.buyer.String() + "/balance/deposit"
+	assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodPost, balanceDeposit, `{"amount":1000}`))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, balanceDeposit, `{"amount":1000}`))
+	approve := "/admin/withdrawals/" + uuid.NewString() + "/approve"
+	assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodPost, approve, ""))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, approve, ""))
+
+	assert.Equal(t, http.StatusOK, api.do(t, api.admin, http.MethodGet, "/admin/schedule", ""))
+}
+
+func TestContractMutationsRequireParty(t *testing.T) {
+	api := newTestAPI(t)
+	c := api.contract
+
+	mutations := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/contracts/%s/settle", ""},
+		{http.MethodDelete, "/contracts/%s", ""},
+		{http.MethodPost, "/contracts/%s/setup", `{"buyer_inputs":["00"],"seller_inputs":["00"]}`},
+		{http.MethodPost, "/contracts/%s/broadcast", fmt.Sprintf(`{"tx_id":%q}`, uuid.New())},
+		{http.MethodPost, "/contracts/%s/swap", fmt.Sprintf(`{"current_pub_key":%q,"new_pub_key":"02dd","new_participant_input":"00"}`, c.BuyerPubKey)},
+		{http.MethodPost, "/contracts/%s/attestation", `{"outcome":"HIGH","signature":"00"}`},
+		{http.MethodPost, "/contracts/%s/final", ""},
+		{http.MethodPost, "/contracts/%s/fee-bump", fmt.Sprintf(`{"pub_key":%q,"fee_rate":20}`, c.BuyerPubKey)},
+		{http.MethodPost, "/contracts/%s/inputs", `{"source":"utxo","txid":"00","vout":0,"value":1000}`},
+		{http.MethodPost, "/contracts/%s/funding", fmt.Sprintf(`{"pub_key":%q,"psbt":"00"}`, c.BuyerPubKey)},
+		{http.MethodPost, "/contracts/%s/payout-address", fmt.Sprintf(`{"pub_key":%q,"address":"bcrt1q","signature":"00"}`, c.BuyerPubKey)},
+		{http.MethodPost, "/contracts/%s/scheduled-close", `{}`},
+	}
+
+	for _, m := range mutations {
+		route := m.method + " " + m.path
+		assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, m.method, fmt.Sprintf(m.path, c.ID), m.body), route)
+		assert.Equal(t, http.StatusForbidden, api.do(t, api.stranger, m.method, fmt.Sprintf(m.path, c.ID), m.body), route)
+		assert.Equal(t, http.StatusNotFound, api.do(t, api.buyer, m.method, fmt.Sprintf(m.path, uuid.New()), m.body), route)
+		assert.Equal(t, http.StatusNotFound, api.do(t, api.admin, m.method, fmt.Sprintf(m.path, uuid.New()), m.body), route)
+	}
+
+	// A party may not swap out their counterparty
+	body := fmt.Sprintf(`{"current_pub_key":%q,"new_pub_key":"02dd","new_participant_input":"00"}`, c.SellerPubKey)
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.buyer, http.MethodPost, fmt.Sprintf("/contracts/%s/swap", c.ID), body))
+
+	// Malformed IDs in a body are rejected rather than crashing the handler
+	assert.Equal(t, http.StatusBadRequest, api.do(t, api.buyer, http.MethodPost, fmt.Sprintf("/contracts/%s/broadcast", c.ID), `{"tx_id":"not-a-uuid"}`))
+}
+
+func TestSettlementOutputsRequireParty(t *testing.T) {
+	api := newTestAPI(t)
+	path := fmt.Sprintf("/contracts/%s/settlement-outputs", api.contract.ID)
+
+	assert.Equal(t, http.StatusUnauthorized, api.do(t, uuid.Nil, http.MethodGet, path, ""))
+	assert.Equal(t, http.StatusForbidden, api.do(t, api.stranger, http.MethodGet, path, ""))
+	assert.Equal(t, http.StatusNotFound, api.do(t, api.admin, http.MethodGet, fmt.Sprintf("/contracts/%s/settlement-outputs", uuid.New()), ""))
+}
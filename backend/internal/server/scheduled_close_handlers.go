@@ -0,0 +1,86 @@
+// internal/server/scheduled_close_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+)
+
+// GetScheduledClose handles retrieving the cooperative close scheduled for a contract
+func (h *Handler) GetScheduledClose(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	sc, err := h.contractService.GetScheduledClose(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrScheduledCloseNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Scheduled closes are not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get scheduled close")
+		storeErrorResponse(w, err, "Contract not found", "Failed to get scheduled close")
+		return
+	}
+	if sc == nil {
+		errorResponse(w, http.StatusNotFound, "No close is scheduled for this contract")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    sc,
+	})
+}
+
+// SubmitCloseIntent handles a party signing a cooperative close at a block height or time
+func (h *Handler) SubmitCloseIntent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	var intent contract.CloseIntent
+	if err := json.NewDecoder(r.Body).Decode(&intent); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sc, err := h.contractService.SubmitCloseIntent(r.Context(), contractID, intent)
+	if err != nil {
+		switch {
+		case errors.Is(err, contract.ErrScheduledCloseNotEnabled):
+			errorResponse(w, http.StatusServiceUnavailable, "Scheduled closes are not enabled")
+		case errors.Is(err, db.ErrNotFound):
+			errorResponse(w, http.StatusNotFound, "Contract not found")
+		case errors.Is(err, db.ErrConflict):
+			errorResponse(w, http.StatusConflict, "Close terms changed, sign the current terms")
+		default:
+			log.Error().Err(err).Str("contractID", id).Msg("Failed to submit close intent")
+			errorResponse(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    sc,
+	})
+}
@@ -0,0 +1,179 @@
+// internal/server/settlement_handlers.go
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+)
+
+// WithSettlementAttempts enables the settlement oracle admin endpoint
+func (h *Handler) WithSettlementAttempts(repo *db.SettlementAttemptRepository) *Handler {
+	h.settlements = repo
+	return h
+}
+
+// ListSettlementAttempts handles listing the automatic settlement attempts,
+// newest first, optionally by outcome or contract
+func (h *Handler) ListSettlementAttempts(w http.ResponseWriter, r *http.Request) {
+	if h.settlements == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Settlement oracle is not enabled")
+		return
+	}
+
+	outcome := models.SettlementOutcome(r.URL.Query().Get("outcome"))
+	switch outcome {
+	case "", models.SettlementOutcomeSettled, models.SettlementOutcomeDeferred, models.SettlementOutcomeFailed:
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid settlement outcome")
+		return
+	}
+
+	var contractID *uuid.UUID
+	if s := r.URL.Query().Get("contract_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+			return
+		}
+		contractID = &id
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	attempts, err := h.settlements.List(r.Context(), outcome, contractID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list settlement attempts")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list settlement attempts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    attempts,
+	})
+}
+
+// SettleAllEligible handles settling every contract whose settlement
+// conditions are met in one batch, on an optional number of workers
+func (h *Handler) SettleAllEligible(w http.ResponseWriter, r *http.Request) {
+	workers := contract.DefaultSettlementWorkers
+	if workersStr := r.URL.Query().Get("workers"); workersStr != "" {
+		var err error
+		workers, err = strconv.Atoi(workersStr)
+		if err != nil || workers < 1 || workers > contract.MaxSettlementWorkers {
+			errorResponse(w, http.StatusBadRequest, "Invalid number of workers")
+			return
+		}
+	}
+
+	report, err := h.contractService.SettleAllEligible(r.Context(), workers)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to settle eligible contracts")
+		errorResponse(w, http.StatusInternalServerError, "Failed to settle eligible contracts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    report,
+	})
+}
+
+// GetSettlementBroadcast handles retrieving the broadcasts of a contract's
+// payout while it awaits confirmation
+func (h *Handler) GetSettlementBroadcast(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	broadcast, err := h.contractService.GetSettlementBroadcast(r.Context(), contractID)
+	if err != nil {
+		if errors.Is(err, contract.ErrSettlementTrackingNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Settlement tracking is not enabled")
+			return
+		}
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to get settlement broadcast")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get settlement broadcast")
+		return
+	}
+
+	if broadcast == nil {
+		errorResponse(w, http.StatusNotFound, "Contract has no settlement broadcast")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    broadcast,
+	})
+}
+
+// ListStuckSettlements handles listing the payouts that have gone
+// unconfirmed for too long, oldest first, for an operator to inspect
+func (h *Handler) ListStuckSettlements(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	broadcasts, err := h.contractService.ListStuckSettlements(r.Context(), limit, offset)
+	if err != nil {
+		if errors.Is(err, contract.ErrSettlementTrackingNotEnabled) {
+			errorResponse(w, http.StatusServiceUnavailable, "Settlement tracking is not enabled")
+			return
+		}
+		log.Error().Err(err).Msg("Failed to list stuck settlements")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list stuck settlements")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    broadcasts,
+	})
+}
@@ -0,0 +1,48 @@
+// internal/server/ticker_handlers.go
+package server
+
+import (
+	"net/http"
+
+	"hashhedge/internal/marketdata"
+)
+
+// WithTickerBook enables the ticker endpoint
+func (h *Handler) WithTickerBook(tickers *marketdata.TickerBook) *Handler {
+	h.tickers = tickers
+	return h
+}
+
+// GetTicker handles retrieving the best bid and offer, last price and 24
+// hour volume, high and low of the market named by the query, or of every
+// market when none is named
+func (h *Handler) GetTicker(w http.ResponseWriter, r *http.Request) {
+	if h.tickers == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Tickers are not enabled")
+		return
+	}
+
+	if !r.URL.Query().Has("type") {
+		respondJSON(w, http.StatusOK, response{
+			Success: true,
+			Data:    h.tickers.Tickers(),
+		})
+		return
+	}
+
+	key, ok := parseMarketKey(w, r)
+	if !ok {
+		return
+	}
+
+	ticker, ok := h.tickers.Ticker(key)
+	if !ok {
+		errorResponse(w, http.StatusNotFound, "No ticker for this market")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    ticker,
+	})
+}
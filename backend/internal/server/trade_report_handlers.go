@@ -0,0 +1,77 @@
+// internal/server/trade_report_handlers.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/tradereport"
+)
+
+// WithTradeReports enables the trade report export
+func (h *Handler) WithTradeReports(exporter *tradereport.Exporter) *Handler {
+	h.tradeReports = exporter
+	return h
+}
+
+// ExportTradeReport handles downloading a user's trades, contracts and
+// settlement payouts over a date range, as CSV or JSON, for tax and
+// accounting. The range defaults to the last 30 days.
+func (h *Handler) ExportTradeReport(w http.ResponseWriter, r *http.Request) {
+	if h.tradeReports == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Trade reports are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	from, to, ok := parseReconciliationRange(r)
+	if !ok {
+		errorResponse(w, http.StatusBadRequest, "Invalid from/to range, expected YYYY-MM-DD dates with from before to")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "json" && format != "csv" {
+		errorResponse(w, http.StatusBadRequest, "Invalid format, expected json or csv")
+		return
+	}
+
+	report, err := h.tradeReports.Build(r.Context(), userID, from, to)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to build trade report")
+		errorResponse(w, http.StatusInternalServerError, "Failed to build trade report")
+		return
+	}
+
+	filename := fmt.Sprintf("trades-%s-%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	write := tradereport.WriteJSON
+	w.Header().Set("Content-Type", "application/json")
+	if format == "csv" {
+		write = tradereport.WriteCSV
+		w.Header().Set("Content-Type", "text/csv")
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := write(w, report); err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to write trade report")
+	}
+}
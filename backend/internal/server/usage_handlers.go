@@ -0,0 +1,254 @@
+// internal/server/usage_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/usage"
+)
+
+// apiKeyHeader carries the API key of a client
+const apiKeyHeader = "X-API-Key"
+
+// WithUsageTracking enables API keys, per-key usage tracking and the usage endpoints
+func (h *Handler) WithUsageTracking(tracker *usage.Tracker, apiKeyRepo *db.APIKeyRepository) *Handler {
+	h.usageTracker = tracker
+	h.apiKeyRepo = apiKeyRepo
+	return h
+}
+
+// CreateAPIKeyRequest represents the request to create an API key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// createAPIKeyResponse includes the key value, which is only ever returned once
+type createAPIKeyResponse struct {
+	*models.APIKey
+	Key string `json:"key"`
+}
+
+// trackUsage resolves the API key of a request and counts the request against it.
+// Requests without a key pass through untracked; an unknown or revoked key is rejected.
+func (h *Handler) trackUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.usageTracker == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		raw := r.Header.Get(apiKeyHeader)
+		if raw == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key, err := h.apiKeyRepo.GetActiveByHash(r.Context(), models.HashAPIKey(raw))
+		if err != nil {
+			errorResponse(w, http.StatusUnauthorized, "Invalid API key")
+			return
+		}
+
+		ctx := usage.WithAPIKey(r.Context(), key)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			// Hijacked websocket connections never write a status
+			status = http.StatusSwitchingProtocols
+		}
+		h.usageTracker.RecordRequest(ctx, status)
+	})
+}
+
+// usageUserID parses the user ID route parameter and checks access to it
+func (h *Handler) usageUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.usageTracker == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Usage tracking is not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, false)
+}
+
+// parseUsageRange reads the from/to query parameters, defaulting to the last 24 hours
+func parseUsageRange(r *http.Request) (time.Time, time.Time, bool) {
+	to := time.Now().UTC()
+	from := to.Add(-24 * time.Hour)
+
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return from, to, false
+		}
+		from = t
+	}
+	if s := r.URL.Query().Get("to"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return from, to, false
+		}
+		to = t
+	}
+
+	return from, to, from.Before(to)
+}
+
+// ListAPIKeys handles listing a user's API keys
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.usageUserID(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.apiKeyRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list API keys")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list API keys")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    keys,
+	})
+}
+
+// CreateAPIKey handles creating an API key for a user
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.usageUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	key := &models.APIKey{
+		UserID: userID,
+		Name:   sanitizeInput(req.Name),
+	}
+	if err := key.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	value, err := key.GenerateAPIKey()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate API key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	if err := h.apiKeyRepo.Create(r.Context(), key); err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to create API key")
+		errorResponse(w, http.StatusInternalServerError, "Failed to create API key")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    createAPIKeyResponse{APIKey: key, Key: value},
+	})
+}
+
+// RevokeAPIKey handles revoking one of a user's API keys
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.usageUserID(w, r)
+	if !ok {
+		return
+	}
+
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := h.apiKeyRepo.Revoke(r.Context(), userID, keyID); err != nil {
+		storeErrorResponse(w, err, "API key not found", "Failed to revoke API key")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "API key revoked successfully",
+	})
+}
+
+// GetUserUsage handles retrieving the hourly usage of a user's API keys
+func (h *Handler) GetUserUsage(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.usageUserID(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseUsageRange(r)
+	if !ok {
+		errorResponse(w, http.StatusBadRequest, "Invalid time range")
+		return
+	}
+
+	rows, err := h.usageTracker.ListByUser(r.Context(), userID, from, to)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to get usage")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get usage")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    rows,
+	})
+}
+
+// GetUsageAggregates handles retrieving usage summed per API key across all users
+func (h *Handler) GetUsageAggregates(w http.ResponseWriter, r *http.Request) {
+	if h.usageTracker == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Usage tracking is not enabled")
+		return
+	}
+
+	from, to, ok := parseUsageRange(r)
+	if !ok {
+		errorResponse(w, http.StatusBadRequest, "Invalid time range")
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	aggregates, err := h.usageTracker.Aggregate(r.Context(), from, to, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to aggregate usage")
+		errorResponse(w, http.StatusInternalServerError, "Failed to aggregate usage")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    aggregates,
+	})
+}
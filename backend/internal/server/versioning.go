@@ -0,0 +1,159 @@
+// internal/server/versioning.go
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hashhedge/internal/openapi"
+)
+
+// Headers of API versioning. Clients ask for a version with Accept-Version
+// or a vendor media type in Accept; responses name the version that served
+// them and, once it is being retired, when it was deprecated and when it
+// stops working (RFC 9745 and RFC 8594).
+const (
+	acceptVersionHeader = "Accept-Version"
+	apiVersionHeader    = "API-Version"
+	deprecationHeader   = "Deprecation"
+	sunsetHeader        = "Sunset"
+)
+
+// vendorMediaTypePrefix starts the vendor media type naming a version, as
+// in application/vnd.hashhedge.v1+json
+const vendorMediaTypePrefix = "application/vnd.hashhedge."
+
+// Deprecation schedules the retirement of an API version or endpoint.
+// Responses carry Deprecation and Sunset headers from the deprecation date
+// and requests fail with 410 Gone once the sunset date has passed.
+type Deprecation struct {
+	// Deprecated is when clients were told to migrate
+	Deprecated time.Time
+	// Sunset is when the endpoint stops responding; zero never
+	Sunset time.Time
+	// Successor documents what to use instead, sent as a Link header
+	Successor string
+}
+
+// apiVersion is a mounted version of the API
+type apiVersion struct {
+	Name        string
+	Routes      func(h *Handler, r chi.Router)
+	Deprecation *Deprecation
+	// Spec documents the version and validates its request bodies
+	Spec *openapi.Spec
+}
+
+// apiVersions are the versions of the API, oldest first. A breaking change
+// ships as a new version while older ones keep serving until their sunset.
+var apiVersions = []apiVersion{
+	{Name: "v1", Routes: (*Handler).v1Routes, Spec: openapi.V1},
+}
+
+// defaultAPIVersion serves unversioned requests that ask for no version
+const defaultAPIVersion = "v1"
+
+// mountAPIVersions registers every API version under /api/{version}
+func mountAPIVersions(r chi.Router, h *Handler) {
+	for _, version := range apiVersions {
+		version := version
+		r.Route("/api/"+version.Name, func(r chi.Router) {
+			r.Use(versionHeader(version.Name))
+			r.Use(h.rateLimit("/api/" + version.Name))
+			if version.Deprecation != nil {
+				r.Use(deprecated(*version.Deprecation))
+			}
+			if version.Spec != nil {
+				r.Use(validateRequest(version.Spec, "/api/"+version.Name))
+				r.Get("/openapi.json", serveSpec(version.Spec))
+			}
+			version.Routes(h, r)
+		})
+	}
+}
+
+// versionHeader names the version that served each response
+func versionHeader(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(apiVersionHeader, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// deprecated announces the retirement of the routes it wraps and rejects
+// requests after their sunset. Wrap a single endpoint with
+// r.With(deprecated(...)) or a whole version through its Deprecation.
+func deprecated(d Deprecation) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			if !now.Before(d.Deprecated) {
+				w.Header().Set(deprecationHeader, fmt.Sprintf("@%d", d.Deprecated.Unix()))
+				if !d.Sunset.IsZero() {
+					w.Header().Set(sunsetHeader, d.Sunset.UTC().Format(http.TimeFormat))
+				}
+				if d.Successor != "" {
+					w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", d.Successor))
+				}
+			}
+
+			if !d.Sunset.IsZero() && !now.Before(d.Sunset) {
+				errorResponse(w, http.StatusGone, "This endpoint has been retired")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestedAPIVersion returns the version a request asks for in its
+// Accept-Version or Accept header, or the default version
+func requestedAPIVersion(r *http.Request) string {
+	if version := strings.TrimSpace(r.Header.Get(acceptVersionHeader)); version != "" {
+		return strings.ToLower(version)
+	}
+
+	for _, mediaType := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+		if strings.HasPrefix(mediaType, vendorMediaTypePrefix) {
+			version := strings.TrimPrefix(mediaType, vendorMediaTypePrefix)
+			return strings.ToLower(strings.TrimSuffix(version, "+json"))
+		}
+	}
+
+	return defaultAPIVersion
+}
+
+// negotiateAPIVersion redirects unversioned API requests to the version
+// they ask for, keeping the method and body with a 307
+func negotiateAPIVersion() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested := requestedAPIVersion(r)
+
+		supported := make([]string, 0, len(apiVersions))
+		for _, version := range apiVersions {
+			supported = append(supported, version.Name)
+			if version.Name != requested {
+				continue
+			}
+
+			target := "/api/" + version.Name + strings.TrimPrefix(r.URL.Path, "/api")
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			w.Header().Set("Vary", acceptVersionHeader+", Accept")
+			http.Redirect(w, r, target, http.StatusTemporaryRedirect)
+			return
+		}
+
+		errorResponse(w, http.StatusNotAcceptable,
+			fmt.Sprintf("Unsupported API version %q, supported versions are %s", requested, strings.Join(supported, ", ")))
+	})
+}
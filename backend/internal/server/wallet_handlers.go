@@ -1,189 +1,165 @@
 // internal/server/wallet_handlers.go
-
-// ExitInfoResponse represents the emergency exit information
-type ExitInfoResponse struct {
-    AllowedNetworks   []string `json:"allowed_networks"`
-    MaxRetryAttempts  int      `json:"max_retry_attempts"`
-    ExitTransactions []*ExitTransactionInfo `json:"exit_transactions"`
-}
-
-type ExitTransactionInfo struct {
-    ID             uuid.UUID `json:"id"`
-    Type           string    `json:"type"`
-    CreatedAt      time.Time `json:"created_at"`
-    Status         string    `json:"status"`
-    InputAmount    int64     `json:"input_amount"`
-    OutputAmount   int64     `json:"output_amount"`
-    DestinationAddress string `json:"destination_address"`
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/deadlines"
+	"hashhedge/pkg/bitcoin"
+)
+
+// ExitInfo describes how participants exit their contracts on chain
+type ExitInfo struct {
+	// ExitTimelockBlocks is the relative timelock on the emergency exit path
+	ExitTimelockBlocks int `json:"exit_timelock_blocks"`
+	// MaxTxSize is the largest exit transaction accepted, in bytes
+	MaxTxSize int `json:"max_tx_size"`
+	// OnChainOnly is set once the ASP is down and exits have been broadcast
+	OnChainOnly bool `json:"on_chain_only"`
 }
 
+// EmergencyExitRequest represents a participant submitting an emergency
+// exit they signed themselves
 type EmergencyExitRequest struct {
-    TransactionHex     string `json:"exit_tx_hex"`
-    DestinationAddress string `json:"destination_address"`
-    Network           string `json:"network"`
-    FeeRate           int    `json:"fee_rate"`
-}
-
-type EmergencyExitResponse struct {
-    PSBT          string `json:"psbt"`
-    TransactionID string `json:"transaction_id"`
-    InputAmount   int64  `json:"input_amount"`
-    OutputAmount  int64  `json:"output_amount"`
-    Fee           int64  `json:"fee"`
-}
-
-type WalletService interface {
-    GetExitTransactions(ctx context.Context, userID uuid.UUID) ([]*ExitTransactionInfo, error)
-    CreateEmergencyExit(
-        ctx context.Context,
-        userID uuid.UUID,
-        txHex,
-        destinationAddress,
-        network string,
-        feeRate int,
-    ) (*EmergencyExitResponse, error)
-    ListExitTransactions(ctx context.Context, userID uuid.UUID, page, limit int) ([]*ExitTransactionInfo, int, error)
-    DownloadExitTransaction(ctx context.Context, userID uuid.UUID, txID uuid.UUID) ([]byte, string, error)
-    BroadcastExitTransaction(ctx context.Context, userID uuid.UUID, txID uuid.UUID) (*BroadcastResult, error)
+	ContractID string `json:"contract_id"`
+	TxHex      string `json:"tx_hex"`
 }
 
-// HandleGetExitInfo retrieves emergency exit configuration and transaction history
-func (h *Handler) HandleGetExitInfo(w http.ResponseWriter, r *http.Request) {
-    // Retrieve user context (would come from authentication middleware)
-    userID := getUserIDFromContext(r.Context())
-
-    // Fetch exit transactions for the user
-    exitTransactions, err := h.walletService.GetExitTransactions(r.Context(), userID)
-    if err != nil {
-        http.Error(w, "Failed to retrieve exit transactions", http.StatusInternalServerError)
-        return
-    }
-
-    response := ExitInfoResponse{
-        AllowedNetworks:  []string{"mainnet", "testnet"},
-        MaxRetryAttempts: 3,
-        ExitTransactions: exitTransactions,
-    }
-
-    respondJSON(w, http.StatusOK, response)
+// setupWalletRoutes registers the emergency exit routes under /wallet
+func (h *Handler) setupWalletRoutes(r chi.Router) {
+	r.Get("/exit-info", h.GetExitInfo)
+	r.Post("/emergency-exit", h.SubmitEmergencyExit)
+	r.Get("/exit-transactions/{txId}/download", h.DownloadExitTransaction)
+	r.Post("/exit-transactions/{txId}/broadcast", h.BroadcastExitTransaction)
 }
 
-// HandleCreateEmergencyExit processes an emergency exit transaction
-func (h *Handler) HandleCreateEmergencyExit(w http.ResponseWriter, r *http.Request) {
-    var request EmergencyExitRequest
-    if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-        http.Error(w, "Invalid request body", http.StatusBadRequest)
-        return
-    }
-
-    // Validate input
-    if err := validateEmergencyExitRequest(request); err != nil {
-        http.Error(w, err.Error(), http.StatusBadRequest)
-        return
-    }
-
-    // Get user context
-    userID := getUserIDFromContext(r.Context())
-
-    // Generate emergency exit PSBT
-    exitTransaction, err := h.walletService.CreateEmergencyExit(
-        r.Context(),
-        userID,
-        request.TransactionHex,
-        request.DestinationAddress,
-        request.Network,
-        request.FeeRate,
-    )
-    if err != nil {
-        http.Error(w, "Failed to create emergency exit", http.StatusInternalServerError)
-        return
-    }
-
-    respondJSON(w, http.StatusCreated, exitTransaction)
+// exitErrorResponse sends the error response for a failed emergency exit call
+func exitErrorResponse(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, contract.ErrNotParty):
+		errorResponse(w, http.StatusForbidden, "Access denied")
+	case errors.Is(err, contract.ErrNotExitTransaction):
+		errorResponse(w, http.StatusNotFound, "Exit transaction not found")
+	case errors.Is(err, bitcoin.ErrMalformedTransaction):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, contract.ErrExitSpendsOtherFunds), errors.Is(err, contract.ErrTransactionRejected):
+		errorResponse(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		storeErrorResponse(w, err, "Not found", msg)
+	}
 }
 
-// HandleListExitTransactions retrieves user's exit transaction history
-func (h *Handler) HandleListExitTransactions(w http.ResponseWriter, r *http.Request) {
-    userID := getUserIDFromContext(r.Context())
-
-    // Pagination parameters
-    page := getIntQueryParam(r, "page", 1)
-    limit := getIntQueryParam(r, "limit", 10)
-
-    transactions, total, err := h.walletService.ListExitTransactions(r.Context(), userID, page, limit)
-    if err != nil {
-        http.Error(w, "Failed to retrieve exit transactions", http.StatusInternalServerError)
-        return
-    }
-
-    response := struct {
-        Transactions []*ExitTransactionInfo `json:"transactions"`
-        Total       int                    `json:"total"`
-        Page        int                    `json:"page"`
-        Limit       int                    `json:"limit"`
-    }{
-        Transactions: transactions,
-        Total:       total,
-        Page:        page,
-        Limit:       limit,
-    }
-
-    respondJSON(w, http.StatusOK, response)
+// GetExitInfo handles reporting how participants exit their contracts on chain
+func (h *Handler) GetExitInfo(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data: ExitInfo{
+			ExitTimelockBlocks: deadlines.ExitTimelockBlocks,
+			MaxTxSize:          bitcoin.DefaultParseLimits.MaxTxSize,
+			OnChainOnly:        h.contractService.OnChainOnly(),
+		},
+	})
 }
 
-// HandleDownloadExitTransaction allows downloading a specific exit transaction
-func (h *Handler) HandleDownloadExitTransaction(w http.ResponseWriter, r *http.Request) {
-    txID := chi.URLParam(r, "txId")
-    parsedTxID, err := uuid.Parse(txID)
-    if err != nil {
-        http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
-        return
-    }
-
-    userID := getUserIDFromContext(r.Context())
-
-    // Retrieve and download transaction file
-    txFile, filename, err := h.walletService.DownloadExitTransaction(r.Context(), userID, parsedTxID)
-    if err != nil {
-        http.Error(w, "Failed to download transaction", http.StatusInternalServerError)
-        return
-    }
-
-    // Set headers for file download
-    w.Header().Set("Content-Type", "application/octet-stream")
-    w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-    w.WriteHeader(http.StatusOK)
-    w.Write(txFile)
+// SubmitEmergencyExit handles a party submitting an emergency exit they
+// signed themselves, which is checked, broadcast and recorded
+func (h *Handler) SubmitEmergencyExit(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req EmergencyExitRequest
+	body := http.MaxBytesReader(w, r.Body, int64(2*bitcoin.DefaultParseLimits.MaxTxSize+1024))
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	contractID, err := uuid.Parse(req.ContractID)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+	if req.TxHex == "" {
+		errorResponse(w, http.StatusBadRequest, "Transaction is required")
+		return
+	}
+
+	exitTx, err := h.contractService.SubmitExitTransaction(r.Context(), userID, contractID, req.TxHex)
+	if err != nil {
+		exitErrorResponse(w, err, "Failed to submit emergency exit")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    exitTx,
+	})
 }
 
-// HandleBroadcastExitTransaction broadcasts a signed exit transaction
-func (h *Handler) HandleBroadcastExitTransaction(w http.ResponseWriter, r *http.Request) {
-    txID := chi.URLParam(r, "txId")
-    parsedTxID, err := uuid.Parse(txID)
-    if err != nil {
-        http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
-        return
-    }
-
-    userID := getUserIDFromContext(r.Context())
-
-    // Broadcast the transaction
-    broadcastResult, err := h.walletService.BroadcastExitTransaction(r.Context(), userID, parsedTxID)
-    if err != nil {
-        http.Error(w, "Failed to broadcast transaction", http.StatusInternalServerError)
-        return
-    }
-
-    respondJSON(w, http.StatusOK, broadcastResult)
+// DownloadExitTransaction handles a party downloading the raw transaction
+// of an emergency exit, to broadcast it through a node of their own
+func (h *Handler) DownloadExitTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	txID, err := uuid.Parse(chi.URLParam(r, "txId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid transaction ID")
+		return
+	}
+
+	tx, txHex, err := h.contractService.ExitTransaction(r.Context(), userID, txID)
+	if err != nil {
+		exitErrorResponse(w, err, "Failed to get exit transaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", tx.TransactionID+".hex"))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(txHex)); err != nil {
+		log.Error().Err(err).Str("txID", txID.String()).Msg("Failed to write exit transaction")
+	}
 }
 
-// Router configuration
-func (s *Server) setupWalletRoutes() {
-    s.router.Route("/api/v1/wallet", func(r chi.Router) {
-        r.Get("/exit-info", h.HandleGetExitInfo)
-        r.Post("/emergency-exit", h.HandleCreateEmergencyExit)
-        r.Get("/exit-transactions", h.HandleListExitTransactions)
-        r.Get("/exit-transactions/{txId}/download", h.HandleDownloadExitTransaction)
-        r.Post("/exit-transactions/{txId}/broadcast", h.HandleBroadcastExitTransaction)
-    })
+// BroadcastExitTransaction handles a party broadcasting one of their
+// contract's emergency exits
+func (h *Handler) BroadcastExitTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	txID, err := uuid.Parse(chi.URLParam(r, "txId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid transaction ID")
+		return
+	}
+
+	txHash, err := h.contractService.BroadcastExitTransaction(r.Context(), userID, txID)
+	if err != nil {
+		exitErrorResponse(w, err, "Failed to broadcast exit transaction")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    map[string]string{"transaction_id": txHash},
+	})
 }
@@ -0,0 +1,207 @@
+// internal/server/watchlist_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+)
+
+// WithWatchlistRepo enables watchlist endpoints and pinning in list endpoints
+func (h *Handler) WithWatchlistRepo(repo *db.WatchlistRepository) *Handler {
+	h.watchlistRepo = repo
+	return h
+}
+
+// AddWatchlistItemRequest represents the request to follow a contract or market
+type AddWatchlistItemRequest struct {
+	ContractID *string `json:"contract_id,omitempty"`
+	Market     *struct {
+		ContractType     string  `json:"contract_type"`
+		StrikeHashRate   float64 `json:"strike_hash_rate"`
+		StartBlockHeight int64   `json:"start_block_height"`
+		EndBlockHeight   int64   `json:"end_block_height"`
+	} `json:"market,omitempty"`
+	Notify bool `json:"notify"`
+}
+
+// GetWatchlist handles retrieving a user's watchlist
+func (h *Handler) GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	if h.watchlistRepo == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Watchlists are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	items, err := h.watchlistRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to get watchlist")
+		errorResponse(w, http.StatusInternalServerError, "Failed to get watchlist")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    items,
+	})
+}
+
+// AddWatchlistItem handles following a contract or market
+func (h *Handler) AddWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	if h.watchlistRepo == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Watchlists are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	var req AddWatchlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if (req.ContractID == nil) == (req.Market == nil) {
+		errorResponse(w, http.StatusBadRequest, "Exactly one of contract_id or market is required")
+		return
+	}
+
+	item := &models.WatchlistItem{
+		UserID: userID,
+		Notify: req.Notify,
+	}
+
+	if req.ContractID != nil {
+		contractID, err := uuid.Parse(*req.ContractID)
+		if err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+			return
+		}
+		if _, err := h.contractService.GetContract(r.Context(), contractID); err != nil {
+			storeErrorResponse(w, err, "Contract not found", "Failed to get contract")
+			return
+		}
+		item.ItemType = models.WatchlistItemContract
+		item.ContractID = &contractID
+	} else {
+		contractType := models.ContractType(req.Market.ContractType)
+		item.ItemType = models.WatchlistItemMarket
+		item.ContractType = &contractType
+		item.StrikeHashRate = &req.Market.StrikeHashRate
+		item.StartBlockHeight = &req.Market.StartBlockHeight
+		item.EndBlockHeight = &req.Market.EndBlockHeight
+	}
+
+	if err := item.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.watchlistRepo.Create(r.Context(), item); err != nil {
+		log.Error().Err(err).Str("userID", id).Msg("Failed to add watchlist item")
+		errorResponse(w, http.StatusInternalServerError, "Failed to add watchlist item")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    item,
+	})
+}
+
+// RemoveWatchlistItem handles unfollowing a contract or market
+func (h *Handler) RemoveWatchlistItem(w http.ResponseWriter, r *http.Request) {
+	if h.watchlistRepo == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Watchlists are not enabled")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	itemID, err := uuid.Parse(chi.URLParam(r, "itemId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid watchlist item ID")
+		return
+	}
+
+	if !h.validateUserPermissions(r, userID) {
+		errorResponse(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	if err := h.watchlistRepo.Delete(r.Context(), userID, itemID); err != nil {
+		storeErrorResponse(w, err, "Watchlist item not found", "Failed to delete watchlist item")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Watchlist item removed successfully",
+	})
+}
+
+// pinWatchedContracts marks contracts on the user's watchlist and moves them to the front
+func (h *Handler) pinWatchedContracts(r *http.Request, contracts []contractResponse) []contractResponse {
+	if h.watchlistRepo == nil {
+		return contracts
+	}
+
+	userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+	if err != nil {
+		return contracts
+	}
+
+	items, err := h.watchlistRepo.ListByUser(r.Context(), userID)
+	if err != nil {
+		log.Warn().Err(err).Str("userID", userID.String()).Msg("Failed to load watchlist for pinning")
+		return contracts
+	}
+
+	for i := range contracts {
+		for _, item := range items {
+			if item.MatchesContract(contracts[i].Contract) {
+				contracts[i].Pinned = true
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(contracts, func(i, j int) bool {
+		return contracts[i].Pinned && !contracts[j].Pinned
+	})
+
+	return contracts
+}
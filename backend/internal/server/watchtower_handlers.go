@@ -0,0 +1,372 @@
+// internal/server/watchtower_handlers.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/watchtower"
+)
+
+// watchtowerTokenHeader carries the access token of a watchtower
+const watchtowerTokenHeader = "X-Watchtower-Token"
+
+// watchtowerKey is the context key of the authenticated watchtower
+type watchtowerKey struct{}
+
+// WithWatchtowers enables delegating emergency exits to watchtowers
+func (h *Handler) WithWatchtowers(service *watchtower.Service) *Handler {
+	h.watchtowers = service
+	return h
+}
+
+// RegisterWatchtowerRequest represents the request to register a watchtower
+type RegisterWatchtowerRequest struct {
+	Name          string `json:"name"`
+	EncryptionKey string `json:"encryption_key"`
+}
+
+// registerWatchtowerResponse includes the access token, which is only ever
+// returned once
+type registerWatchtowerResponse struct {
+	*models.Watchtower
+	Token string `json:"token"`
+}
+
+// DelegateExitRequest represents the request to delegate a participant's
+// emergency exit to a watchtower
+type DelegateExitRequest struct {
+	WatchtowerID uuid.UUID `json:"watchtower_id"`
+	PubKey       string    `json:"pub_key"`
+}
+
+// AcknowledgeDelegationRequest represents a watchtower confirming it stored
+// the package with the given digest
+type AcknowledgeDelegationRequest struct {
+	PackageDigest string `json:"package_digest"`
+}
+
+// watchtowersEnabled reports whether watchtowers are enabled, responding if not
+func (h *Handler) watchtowersEnabled(w http.ResponseWriter) bool {
+	if h.watchtowers == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Watchtower delegation is not enabled")
+		return false
+	}
+	return true
+}
+
+// requireWatchtower rejects requests without a valid watchtower token
+func (h *Handler) requireWatchtower(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.watchtowersEnabled(w) {
+			return
+		}
+
+		token := r.Header.Get(watchtowerTokenHeader)
+		if token == "" {
+			errorResponse(w, http.StatusUnauthorized, "Watchtower token required")
+			return
+		}
+
+		tower, err := h.watchtowers.Authenticate(r.Context(), token)
+		if err != nil {
+			if !errors.Is(err, db.ErrNotFound) {
+				log.Error().Err(err).Msg("Failed to authenticate watchtower")
+			}
+			errorResponse(w, http.StatusUnauthorized, "Invalid watchtower token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), watchtowerKey{}, tower)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// currentWatchtower returns the watchtower authenticated by requireWatchtower
+func currentWatchtower(r *http.Request) *models.Watchtower {
+	tower, _ := r.Context().Value(watchtowerKey{}).(*models.Watchtower)
+	return tower
+}
+
+// delegationError responds to a failed delegation operation
+func delegationError(w http.ResponseWriter, err error, notFound, failed string) {
+	switch {
+	case errors.Is(err, watchtower.ErrNotParticipant):
+		errorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, watchtower.ErrContractNotActive),
+		errors.Is(err, watchtower.ErrNoExitTransactions),
+		errors.Is(err, watchtower.ErrDigestMismatch):
+		errorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, "Delegation already exists or is no longer pending")
+	default:
+		storeErrorResponse(w, err, notFound, failed)
+	}
+}
+
+// RegisterWatchtower handles registering a watchtower
+func (h *Handler) RegisterWatchtower(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	var req RegisterWatchtowerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tower := &models.Watchtower{Name: req.Name, EncryptionKey: req.EncryptionKey}
+	if err := tower.Validate(); err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := h.watchtowers.RegisterWatchtower(r.Context(), tower)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to register watchtower")
+		errorResponse(w, http.StatusInternalServerError, "Failed to register watchtower")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    registerWatchtowerResponse{Watchtower: tower, Token: token},
+	})
+}
+
+// ListWatchtowers handles listing the registered watchtowers
+func (h *Handler) ListWatchtowers(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	towers, err := h.watchtowers.ListWatchtowers(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list watchtowers")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list watchtowers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    towers,
+	})
+}
+
+// RevokeWatchtower handles revoking a watchtower and its delegations
+func (h *Handler) RevokeWatchtower(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid watchtower ID")
+		return
+	}
+
+	if err := h.watchtowers.RevokeWatchtower(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("watchtowerID", id.String()).Msg("Failed to revoke watchtower")
+		storeErrorResponse(w, err, "Watchtower not found", "Failed to revoke watchtower")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+	})
+}
+
+// DelegateContractExit handles delegating a participant's emergency exit of
+// a contract to a watchtower
+func (h *Handler) DelegateContractExit(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	if _, ok := h.requireParty(w, r, contractID); !ok {
+		return
+	}
+
+	var req DelegateExitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	userID, _ := h.viewer(r)
+	delegation, err := h.watchtowers.Delegate(r.Context(), userID, contractID, req.WatchtowerID, req.PubKey)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to delegate contract exit")
+		delegationError(w, err, "Contract or watchtower not found", "Failed to delegate contract exit")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    delegation,
+	})
+}
+
+// ListContractDelegations handles listing the caller's delegations of a contract
+func (h *Handler) ListContractDelegations(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	contractID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid contract ID")
+		return
+	}
+
+	userID, _ := h.viewer(r)
+	delegations, err := h.watchtowers.ListDelegations(r.Context(), userID, contractID)
+	if err != nil {
+		log.Error().Err(err).Str("contractID", id).Msg("Failed to list contract delegations")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list contract delegations")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    delegations,
+	})
+}
+
+// ExportContractDelegation handles downloading the sealed exit package of a
+// delegation, for handing to the watchtower out of band
+func (h *Handler) ExportContractDelegation(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	id := chi.URLParam(r, "delegationId")
+	delegationID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid delegation ID")
+		return
+	}
+
+	userID, _ := h.viewer(r)
+	delegation, err := h.watchtowers.GetDelegation(r.Context(), userID, delegationID)
+	if err != nil {
+		log.Error().Err(err).Str("delegationID", id).Msg("Failed to get delegation")
+		storeErrorResponse(w, err, "Delegation not found", "Failed to get delegation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+delegation.ID.String()+`.exit"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(delegation.Package)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(delegation.Package)
+}
+
+// RevokeContractDelegation handles revoking one of the caller's delegations
+func (h *Handler) RevokeContractDelegation(w http.ResponseWriter, r *http.Request) {
+	if !h.watchtowersEnabled(w) {
+		return
+	}
+
+	id := chi.URLParam(r, "delegationId")
+	delegationID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid delegation ID")
+		return
+	}
+
+	userID, _ := h.viewer(r)
+	if err := h.watchtowers.RevokeDelegation(r.Context(), userID, delegationID); err != nil {
+		log.Error().Err(err).Str("delegationID", id).Msg("Failed to revoke delegation")
+		storeErrorResponse(w, err, "Delegation not found", "Failed to revoke delegation")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+	})
+}
+
+// PollDelegations handles a watchtower fetching its delegations in a status,
+// pending by default
+func (h *Handler) PollDelegations(w http.ResponseWriter, r *http.Request) {
+	tower := currentWatchtower(r)
+
+	status := models.DelegationStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = models.DelegationStatusPending
+	case models.DelegationStatusPending, models.DelegationStatusAcknowledged, models.DelegationStatusRevoked:
+	default:
+		errorResponse(w, http.StatusBadRequest, "Invalid delegation status")
+		return
+	}
+
+	limit := watchtower.DefaultPollLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	delegations, err := h.watchtowers.Poll(r.Context(), tower.ID, status, limit)
+	if err != nil {
+		log.Error().Err(err).Str("watchtowerID", tower.ID.String()).Msg("Failed to poll delegations")
+		errorResponse(w, http.StatusInternalServerError, "Failed to poll delegations")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    delegations,
+	})
+}
+
+// AcknowledgeDelegation handles a watchtower confirming it stored a package
+func (h *Handler) AcknowledgeDelegation(w http.ResponseWriter, r *http.Request) {
+	tower := currentWatchtower(r)
+
+	id := chi.URLParam(r, "id")
+	delegationID, err := uuid.Parse(id)
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid delegation ID")
+		return
+	}
+
+	var req AcknowledgeDelegationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.watchtowers.Acknowledge(r.Context(), tower.ID, delegationID, req.PackageDigest); err != nil {
+		log.Error().Err(err).Str("delegationID", id).Msg("Failed to acknowledge delegation")
+		delegationError(w, err, "Delegation not found", "Failed to acknowledge delegation")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+	})
+}
@@ -0,0 +1,190 @@
+// internal/server/webhook_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/webhooks"
+)
+
+// WithWebhooks enables the webhook registration and dead letter endpoints
+func (h *Handler) WithWebhooks(svc *webhooks.Service) *Handler {
+	h.webhooks = svc
+	return h
+}
+
+// RegisterWebhookRequest represents the request to register a webhook
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// registerWebhookResponse includes the signing secret, which is only ever
+// returned once
+type registerWebhookResponse struct {
+	*models.Webhook
+	Secret string `json:"secret"`
+}
+
+// webhookUserID parses the user ID route parameter and checks access to it
+func (h *Handler) webhookUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	if h.webhooks == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Webhooks are not enabled")
+		return uuid.Nil, false
+	}
+
+	return h.pathUser(w, r, false)
+}
+
+// ListWebhooks handles listing a user's active webhooks
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.webhookUserID(w, r)
+	if !ok {
+		return
+	}
+
+	hooks, err := h.webhooks.List(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list webhooks")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list webhooks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    hooks,
+	})
+}
+
+// RegisterWebhook handles registering an HTTPS callback for a user's events
+func (h *Handler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.webhookUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RegisterWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	webhook := &models.Webhook{
+		UserID: userID,
+		URL:    req.URL,
+		Events: pq.StringArray(req.Events),
+	}
+
+	secret, err := h.webhooks.Register(r.Context(), webhook)
+	if errors.Is(err, webhooks.ErrLimitReached) {
+		errorResponse(w, http.StatusConflict, "Webhook limit reached")
+		return
+	}
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    registerWebhookResponse{Webhook: webhook, Secret: secret},
+	})
+}
+
+// RemoveWebhook handles disabling one of a user's webhooks
+func (h *Handler) RemoveWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.webhookUserID(w, r)
+	if !ok {
+		return
+	}
+
+	webhookID, err := uuid.Parse(chi.URLParam(r, "webhookId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhooks.Remove(r.Context(), userID, webhookID); err != nil {
+		storeErrorResponse(w, err, "Webhook not found", "Failed to remove webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    "Webhook removed successfully",
+	})
+}
+
+// ListWebhookDeadLetters handles listing the deliveries to a user's webhooks
+// that failed every attempt, newest first
+func (h *Handler) ListWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.webhookUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	letters, err := h.webhooks.ListDeadLetters(r.Context(), userID, limit, offset)
+	if err != nil {
+		log.Error().Err(err).Str("userID", userID.String()).Msg("Failed to list webhook dead letters")
+		errorResponse(w, http.StatusInternalServerError, "Failed to list webhook dead letters")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    letters,
+	})
+}
+
+// RedeliverWebhook handles queueing a dead letter to be delivered again
+func (h *Handler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.webhookUserID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	if err := h.webhooks.Redeliver(r.Context(), userID, deliveryID); err != nil {
+		storeErrorResponse(w, err, "Dead letter not found", "Failed to redeliver webhook")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, response{
+		Success: true,
+		Data:    "Delivery queued",
+	})
+}
@@ -0,0 +1,81 @@
+// internal/server/websocket_handlers.go
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/websocket"
+)
+
+// WithWebSocketServer enables the websocket endpoint. Connections live until
+// ctx is cancelled rather than for the duration of the upgrade request.
+func (h *Handler) WithWebSocketServer(ctx context.Context, ws *websocket.Server) *Handler {
+	h.wsServer = ws
+	h.wsCtx = ctx
+	ws.SetAuthorizer(h)
+	return h
+}
+
+// GetWebSocketStats handles retrieving the websocket connection counters
+func (h *Handler) GetWebSocketStats(w http.ResponseWriter, r *http.Request) {
+	if h.wsServer == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "WebSocket is not enabled")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    h.wsServer.Stats(),
+	})
+}
+
+// CanSubscribe allows a user to follow the funding of contracts they are a
+// party to. Anonymization admins may follow every contract.
+func (h *Handler) CanSubscribe(ctx context.Context, userID uuid.UUID, channel string) bool {
+	if h.anonymizer != nil && h.anonymizer.IsAdmin(userID) {
+		return true
+	}
+
+	parts := strings.Split(channel, ":")
+	if len(parts) != 3 || parts[0] != "contract" || parts[2] != "funding" {
+		return false
+	}
+
+	contractID, err := uuid.Parse(parts[1])
+	if err != nil {
+		return false
+	}
+
+	c, err := h.contractService.GetContract(ctx, contractID)
+	if err != nil {
+		return false
+	}
+
+	keys, err := h.userRepo.GetKeysByUserID(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Str("userID", userID.String()).Msg("Failed to get keys for channel authorization")
+		return false
+	}
+
+	for _, key := range keys {
+		if key.PubKey == c.BuyerPubKey || key.PubKey == c.SellerPubKey {
+			return true
+		}
+	}
+	return false
+}
+
+// ServeWebSocket upgrades the request to a websocket connection
+func (h *Handler) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	if h.wsServer == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "WebSocket is not enabled")
+		return
+	}
+
+	h.wsServer.Upgrade(h.wsCtx, w, r)
+}
@@ -0,0 +1,239 @@
+// internal/server/withdrawal_handlers.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/internal/wallet"
+)
+
+// WithWithdrawals enables withdrawing balances on chain or over Ark
+func (h *Handler) WithWithdrawals(withdrawals *wallet.Withdrawals) *Handler {
+	h.withdrawals = withdrawals
+	return h
+}
+
+// RejectWithdrawalRequest represents an operator refusing a withdrawal
+type RejectWithdrawalRequest struct {
+	Reason string `json:"reason"`
+}
+
+// withdrawalsEnabled checks that withdrawals are enabled
+func (h *Handler) withdrawalsEnabled(w http.ResponseWriter) bool {
+	if h.withdrawals == nil {
+		errorResponse(w, http.StatusServiceUnavailable, "Withdrawals are not enabled")
+		return false
+	}
+	return true
+}
+
+// withdrawalPage parses the limit and offset of a withdrawals request
+func withdrawalPage(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > 500 {
+			errorResponse(w, http.StatusBadRequest, "Invalid limit")
+			return 0, 0, false
+		}
+	}
+
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			errorResponse(w, http.StatusBadRequest, "Invalid offset")
+			return 0, 0, false
+		}
+	}
+
+	return limit, offset, true
+}
+
+// withdrawalErrorResponse sends the error response for a failed withdrawal call
+func withdrawalErrorResponse(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, wallet.ErrInvalidWithdrawal):
+		errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, db.ErrInsufficientFunds):
+		errorResponse(w, http.StatusConflict, "Insufficient funds")
+	case errors.Is(err, db.ErrNotFound):
+		errorResponse(w, http.StatusNotFound, "Withdrawal not found")
+	case errors.Is(err, db.ErrConflict):
+		errorResponse(w, http.StatusConflict, err.Error())
+	default:
+		log.Error().Err(err).Msg(msg)
+		errorResponse(w, http.StatusInternalServerError, msg)
+	}
+}
+
+// RequestWithdrawal handles the requester withdrawing part of their
+// balance. Large withdrawals are returned awaiting approval and failed
+// payouts are returned refunded.
+func (h *Handler) RequestWithdrawal(w http.ResponseWriter, r *http.Request) {
+	if !h.withdrawalsEnabled(w) {
+		return
+	}
+
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req wallet.WithdrawalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	withdrawal, err := h.withdrawals.Request(r.Context(), userID, req)
+	if err != nil {
+		withdrawalErrorResponse(w, err, "Failed to request withdrawal")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, response{
+		Success: true,
+		Data:    withdrawal,
+	})
+}
+
+// ListWithdrawals handles listing the requester's withdrawals, newest first
+func (h *Handler) ListWithdrawals(w http.ResponseWriter, r *http.Request) {
+	if !h.withdrawalsEnabled(w) {
+		return
+	}
+
+	userID, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	limit, offset, ok := withdrawalPage(w, r)
+	if !ok {
+		return
+	}
+
+	withdrawals, err := h.withdrawals.List(r.Context(), userID, limit, offset)
+	if err != nil {
+		withdrawalErrorResponse(w, err, "Failed to list withdrawals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    withdrawals,
+	})
+}
+
+// ListWithdrawalsByStatus handles an operator listing withdrawals by
+// status, oldest first. It defaults to those awaiting approval.
+func (h *Handler) ListWithdrawalsByStatus(w http.ResponseWriter, r *http.Request) {
+	if !h.withdrawalsEnabled(w) {
+		return
+	}
+
+	status := models.WithdrawalStatusPendingApproval
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status = models.WithdrawalStatus(statusStr)
+	}
+
+	limit, offset, ok := withdrawalPage(w, r)
+	if !ok {
+		return
+	}
+
+	withdrawals, err := h.withdrawals.ListByStatus(r.Context(), status, limit, offset)
+	if err != nil {
+		withdrawalErrorResponse(w, err, "Failed to list withdrawals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    withdrawals,
+	})
+}
+
+// ApproveWithdrawal handles an admin approving a withdrawal, which is then
+// paid out. The admin is recorded as its reviewer.
+func (h *Handler) ApproveWithdrawal(w http.ResponseWriter, r *http.Request) {
+	if !h.withdrawalsEnabled(w) {
+		return
+	}
+
+	reviewer, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid withdrawal ID")
+		return
+	}
+
+	withdrawal, err := h.withdrawals.Approve(r.Context(), id, reviewer)
+	if err != nil {
+		withdrawalErrorResponse(w, err, "Failed to approve withdrawal")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    withdrawal,
+	})
+}
+
+// RejectWithdrawal handles an admin refusing a withdrawal, which is
+// refunded. The admin is recorded as its reviewer.
+func (h *Handler) RejectWithdrawal(w http.ResponseWriter, r *http.Request) {
+	if !h.withdrawalsEnabled(w) {
+		return
+	}
+
+	reviewer, ok := h.viewer(r)
+	if !ok {
+		errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse(w, http.StatusBadRequest, "Invalid withdrawal ID")
+		return
+	}
+
+	var req RejectWithdrawalRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	withdrawal, err := h.withdrawals.Reject(r.Context(), id, reviewer, req.Reason)
+	if err != nil {
+		withdrawalErrorResponse(w, err, "Failed to reject withdrawal")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response{
+		Success: true,
+		Data:    withdrawal,
+	})
+}
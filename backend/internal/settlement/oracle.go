@@ -0,0 +1,250 @@
+// internal/settlement/oracle.go
+package settlement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// Config holds the settlement oracle configuration
+type Config struct {
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+	// MaxBackoff caps the wait before retrying a contract whose settlement failed
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// DefaultConfig scans for matured contracts every minute
+var DefaultConfig = Config{
+	Interval:   time.Minute,
+	BatchSize:  100,
+	MaxBackoff: time.Hour,
+}
+
+// Validate checks that the oracle settings are usable
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("settlement oracle interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("settlement oracle batch size must be positive")
+	}
+	if c.MaxBackoff < c.Interval {
+		return fmt.Errorf("settlement oracle max backoff must be at least the interval")
+	}
+	return nil
+}
+
+// Settler is the contract service as used by the oracle
+type Settler interface {
+	ListActiveContracts(ctx context.Context, limit, offset int) ([]*models.Contract, error)
+	CheckSettlementConditions(ctx context.Context, contractID uuid.UUID) (bool, string, error)
+	SettleContract(ctx context.Context, contractID uuid.UUID) (*models.ContractTransaction, bool, error)
+	CurrentBlockHeight(ctx context.Context) (int64, error)
+}
+
+// retry is the failure count of a contract and when it may be tried again
+type retry struct {
+	failures int
+	next     time.Time
+}
+
+// Oracle settles active contracts once they mature. Each run pages through
+// the active contracts, settles those whose end height or target time has
+// passed and records the outcome of every attempt. Failed contracts are
+// retried with exponential backoff; deferred contracts are left to the fee
+// policy, which releases them once fees fall.
+type Oracle struct {
+	settler  Settler
+	attempts *db.SettlementAttemptRepository
+	cfg      Config
+
+	retries  map[uuid.UUID]retry
+	deferred map[uuid.UUID]bool
+}
+
+// NewOracle creates a new settlement oracle
+func NewOracle(settler Settler, attempts *db.SettlementAttemptRepository, cfg Config) *Oracle {
+	return &Oracle{
+		settler:  settler,
+		attempts: attempts,
+		cfg:      cfg,
+		retries:  make(map[uuid.UUID]retry),
+		deferred: make(map[uuid.UUID]bool),
+	}
+}
+
+// Start begins settling matured contracts
+func (o *Oracle) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(o.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				settled, err := o.run(ctx, time.Now())
+				if err != nil {
+					logger.Error().Err(err).Msg("Settlement oracle run failed")
+					continue
+				}
+				if settled > 0 {
+					logger.Info().Int("settled", settled).Msg("Settled matured contracts")
+				}
+			}
+		}
+	}()
+}
+
+// run settles every matured active contract that is not waiting out a
+// backoff or a fee deferral, and returns how many settled
+func (o *Oracle) run(ctx context.Context, now time.Time) (int, error) {
+	tip, err := o.settler.CurrentBlockHeight(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block height: %w", err)
+	}
+
+	// List every active contract before settling any, since settled
+	// contracts drop out of the list and would shift the pages
+	var contracts []*models.Contract
+	for offset := 0; ; offset += o.cfg.BatchSize {
+		page, err := o.settler.ListActiveContracts(ctx, o.cfg.BatchSize, offset)
+		if err != nil {
+			return 0, err
+		}
+		contracts = append(contracts, page...)
+		if len(page) < o.cfg.BatchSize {
+			break
+		}
+	}
+
+	active := make(map[uuid.UUID]bool, len(contracts))
+	settled := 0
+	for _, c := range contracts {
+		active[c.ID] = true
+		if !matured(c, tip, now) || o.deferred[c.ID] {
+			continue
+		}
+		if r, ok := o.retries[c.ID]; ok && now.Before(r.next) {
+			continue
+		}
+		if o.settle(ctx, c, tip, now) {
+			settled++
+		}
+	}
+
+	// Forget contracts that are no longer active, whoever settled them
+	for id := range o.retries {
+		if !active[id] {
+			delete(o.retries, id)
+		}
+	}
+	for id := range o.deferred {
+		if !active[id] {
+			delete(o.deferred, id)
+		}
+	}
+
+	return settled, nil
+}
+
+// matured reports whether a contract has reached its end height or target
+// time. It saves asking the chain about every contract that has not.
+func matured(c *models.Contract, tip int64, now time.Time) bool {
+	return tip >= c.EndBlockHeight || now.After(c.TargetTimestamp)
+}
+
+// settle checks and settles one contract, recording the outcome. Settled
+// contracts drop out of the active list, so a settled contract is never
+// tried twice.
+func (o *Oracle) settle(ctx context.Context, c *models.Contract, tip int64, now time.Time) bool {
+	canSettle, reason, err := o.settler.CheckSettlementConditions(ctx, c.ID)
+	if err == nil && !canSettle {
+		return false
+	}
+
+	attempt := &models.SettlementAttempt{
+		ContractID:  c.ID,
+		BlockHeight: tip,
+		Reason:      reason,
+	}
+
+	if err == nil {
+		var tx *models.ContractTransaction
+		var buyerWins bool
+		tx, buyerWins, err = o.settler.SettleContract(ctx, c.ID)
+		if err == nil {
+			attempt.SettlementTxID = &tx.TransactionID
+			attempt.BuyerWins = &buyerWins
+		}
+	}
+
+	attempt.Outcome = outcomeOf(err)
+	metrics.SettlementAttempts.WithLabelValues(string(attempt.Outcome)).Inc()
+	switch attempt.Outcome {
+	case models.SettlementOutcomeSettled:
+		delete(o.retries, c.ID)
+	case models.SettlementOutcomeDeferred:
+		attempt.Reason = err.Error()
+		o.deferred[c.ID] = true
+		delete(o.retries, c.ID)
+	default:
+		attempt.Reason = err.Error()
+		r := o.retries[c.ID]
+		r.failures++
+		r.next = now.Add(backoff(o.cfg.Interval, o.cfg.MaxBackoff, r.failures))
+		o.retries[c.ID] = r
+
+		logger.Warn().
+			Err(err).
+			Str("contractID", c.ID.String()).
+			Int("failures", r.failures).
+			Time("retry_at", r.next).
+			Msg("Automatic settlement failed")
+	}
+
+	if err := o.attempts.Create(ctx, attempt); err != nil {
+		logger.Error().Err(err).Str("contractID", c.ID.String()).Msg("Failed to record settlement attempt")
+	}
+
+	return attempt.Outcome == models.SettlementOutcomeSettled
+}
+
+// outcomeOf classifies the error of a settlement attempt
+func outcomeOf(err error) models.SettlementOutcome {
+	switch {
+	case err == nil:
+		return models.SettlementOutcomeSettled
+	case errors.Is(err, contract.ErrSettlementDeferred):
+		return models.SettlementOutcomeDeferred
+	default:
+		return models.SettlementOutcomeFailed
+	}
+}
+
+// backoff returns the wait after a contract's nth consecutive failure: the
+// interval, doubling with each failure up to max
+func backoff(interval, max time.Duration, failures int) time.Duration {
+	wait := interval
+	for i := 1; i < failures && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	return wait
+}
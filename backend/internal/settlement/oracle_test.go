@@ -0,0 +1,63 @@
+// internal/settlement/oracle_test.go
+package settlement
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"hashhedge/internal/contract"
+	"hashhedge/internal/models"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{6, 32 * time.Minute},
+		{7, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, backoff(time.Minute, time.Hour, tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestOutcomeOf(t *testing.T) {
+	assert.Equal(t, models.SettlementOutcomeSettled, outcomeOf(nil))
+	assert.Equal(t, models.SettlementOutcomeDeferred,
+		outcomeOf(fmt.Errorf("%w: estimate 80 sat/vB", contract.ErrSettlementDeferred)))
+	assert.Equal(t, models.SettlementOutcomeFailed, outcomeOf(errors.New("broadcast failed")))
+}
+
+func TestMatured(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	c := &models.Contract{
+		EndBlockHeight:  852016,
+		TargetTimestamp: now.Add(time.Hour),
+	}
+
+	assert.False(t, matured(c, 852015, now))
+	assert.True(t, matured(c, 852016, now))
+	assert.True(t, matured(c, 850000, now.Add(2*time.Hour)))
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	cfg := DefaultConfig
+	cfg.MaxBackoff = time.Second
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig
+	cfg.BatchSize = 0
+	assert.Error(t, cfg.Validate())
+}
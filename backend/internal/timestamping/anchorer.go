@@ -0,0 +1,351 @@
+// internal/timestamping/anchorer.go
+package timestamping
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/bitcoin"
+	ots "hashhedge/pkg/opentimestamps"
+)
+
+var logger = logging.Component(logging.Jobs)
+
+// ErrNoProof is returned when evidence has not been submitted to a calendar yet
+var ErrNoProof = errors.New("settlement evidence has no timestamp proof yet")
+
+// Config holds the OpenTimestamps configuration. Anchoring is enabled when
+// at least one calendar is set.
+type Config struct {
+	Calendars []string      `yaml:"calendars"`
+	Interval  time.Duration `yaml:"interval"`
+	BatchSize int           `yaml:"batch_size"`
+}
+
+// DefaultConfig checks for evidence to submit or upgrade about once per block
+var DefaultConfig = Config{
+	Interval:  10 * time.Minute,
+	BatchSize: 50,
+}
+
+// Enabled reports whether anchoring is configured
+func (c Config) Enabled() bool {
+	return len(c.Calendars) > 0
+}
+
+// Validate checks that the anchoring settings are usable
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("timestamping interval must be positive")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("timestamping batch size must be positive")
+	}
+	for _, calendar := range c.Calendars {
+		u, err := url.Parse(calendar)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid timestamping calendar URL %q", calendar)
+		}
+	}
+	return nil
+}
+
+// Chain is the subset of the Bitcoin node client used to check attestations
+type Chain interface {
+	GetBlockHash(ctx context.Context, height int64) (string, error)
+	GetBlock(ctx context.Context, hash string) (*bitcoin.Block, error)
+}
+
+// Anchorer submits settlement evidence digests to OpenTimestamps calendars
+// and upgrades the proofs once the calendars' commitments are mined, so a
+// settlement decision can later be shown to predate a Bitcoin block
+type Anchorer struct {
+	repo      *db.SettlementEvidenceRepository
+	chain     Chain
+	calendars []*ots.Calendar
+	cfg       Config
+}
+
+// NewAnchorer creates a new anchorer using the configured calendars
+func NewAnchorer(repo *db.SettlementEvidenceRepository, chain Chain, cfg Config) *Anchorer {
+	calendars := make([]*ots.Calendar, 0, len(cfg.Calendars))
+	for _, u := range cfg.Calendars {
+		calendars = append(calendars, ots.NewCalendar(u))
+	}
+
+	return &Anchorer{
+		repo:      repo,
+		chain:     chain,
+		calendars: calendars,
+		cfg:       cfg,
+	}
+}
+
+// Start begins submitting and upgrading evidence at the configured interval
+func (a *Anchorer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(a.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.run(ctx)
+			}
+		}
+	}()
+}
+
+// run submits newly recorded evidence and upgrades pending proofs
+func (a *Anchorer) run(ctx context.Context) {
+	recorded, err := a.repo.ListByStatus(ctx, models.EvidenceStatusRecorded, a.cfg.BatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list recorded settlement evidence")
+	}
+	for _, evidence := range recorded {
+		if err := a.submit(ctx, evidence); err != nil {
+			logger.Error().Err(err).
+				Str("contractID", evidence.ContractID.String()).
+				Msg("Failed to submit settlement evidence")
+		}
+	}
+
+	pending, err := a.repo.ListByStatus(ctx, models.EvidenceStatusPending, a.cfg.BatchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to list pending settlement evidence")
+	}
+	for _, evidence := range pending {
+		if err := a.upgrade(ctx, evidence); err != nil {
+			logger.Error().Err(err).
+				Str("contractID", evidence.ContractID.String()).
+				Msg("Failed to upgrade settlement evidence")
+		}
+	}
+}
+
+// submit sends the evidence digest to every calendar and stores the merged
+// proof. One calendar accepting the digest is enough.
+func (a *Anchorer) submit(ctx context.Context, evidence *models.SettlementEvidence) error {
+	digest, err := hex.DecodeString(evidence.Digest)
+	if err != nil {
+		return fmt.Errorf("invalid evidence digest: %w", err)
+	}
+
+	ts := ots.NewTimestamp(digest)
+	submitted := 0
+	for _, calendar := range a.calendars {
+		result, err := calendar.Submit(ctx, digest)
+		if err == nil {
+			err = ts.Merge(result)
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("calendar", calendar.URL()).Msg("Calendar rejected settlement evidence")
+			continue
+		}
+		submitted++
+	}
+	if submitted == 0 {
+		return fmt.Errorf("no calendar accepted the digest")
+	}
+
+	return a.repo.UpdateProof(ctx, evidence.ContractID, models.EvidenceStatusPending, ts.Serialize(), nil)
+}
+
+// upgrade asks the calendars for the Bitcoin commitments of a pending proof
+// and marks the evidence anchored once an attestation matches the chain
+func (a *Anchorer) upgrade(ctx context.Context, evidence *models.SettlementEvidence) error {
+	digest, err := hex.DecodeString(evidence.Digest)
+	if err != nil {
+		return fmt.Errorf("invalid evidence digest: %w", err)
+	}
+
+	ts, err := ots.Deserialize(digest, evidence.Proof)
+	if err != nil {
+		return fmt.Errorf("failed to decode proof: %w", err)
+	}
+
+	upgraded := false
+	for _, pending := range ts.PendingAttestations() {
+		// Only contact calendars we submitted to, whatever the proof says
+		calendar := a.calendar(pending.URI)
+		if calendar == nil {
+			continue
+		}
+
+		result, err := calendar.Upgrade(ctx, pending.Timestamp.Msg)
+		if errors.Is(err, ots.ErrNotUpgraded) {
+			continue
+		}
+		if err == nil {
+			err = pending.Timestamp.Merge(result)
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("calendar", calendar.URL()).Msg("Failed to upgrade settlement evidence proof")
+			continue
+		}
+		upgraded = true
+	}
+	if !upgraded {
+		return nil
+	}
+
+	status := models.EvidenceStatusPending
+	var anchorHeight *int64
+	for _, check := range a.checkBlocks(ctx, ts) {
+		if check.Matches && (anchorHeight == nil || check.Height < *anchorHeight) {
+			height := check.Height
+			anchorHeight = &height
+			status = models.EvidenceStatusAnchored
+		}
+	}
+
+	return a.repo.UpdateProof(ctx, evidence.ContractID, status, ts.Serialize(), anchorHeight)
+}
+
+// calendar returns the configured calendar at uri, if any
+func (a *Anchorer) calendar(uri string) *ots.Calendar {
+	target := ots.NewCalendar(uri).URL()
+	for _, calendar := range a.calendars {
+		if calendar.URL() == target {
+			return calendar
+		}
+	}
+	return nil
+}
+
+// BlockCheck is the result of checking one Bitcoin attestation against the chain
+type BlockCheck struct {
+	Height    int64  `json:"height"`
+	BlockHash string `json:"block_hash,omitempty"`
+	Matches   bool   `json:"matches"`
+	Error     string `json:"error,omitempty"`
+}
+
+// checkBlocks compares each Bitcoin attestation of a proof with the merkle
+// root of the block at its height
+func (a *Anchorer) checkBlocks(ctx context.Context, ts *ots.Timestamp) []BlockCheck {
+	attestations := ts.BitcoinAttestations()
+	checks := make([]BlockCheck, 0, len(attestations))
+	for _, attestation := range attestations {
+		check := BlockCheck{Height: attestation.Height}
+		if err := a.checkBlock(ctx, attestation, &check); err != nil {
+			check.Error = err.Error()
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func (a *Anchorer) checkBlock(ctx context.Context, attestation ots.BlockAttestation, check *BlockCheck) error {
+	hash, err := a.chain.GetBlockHash(ctx, attestation.Height)
+	if err != nil {
+		return fmt.Errorf("failed to get block hash at height %d: %w", attestation.Height, err)
+	}
+	check.BlockHash = hash
+
+	block, err := a.chain.GetBlock(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to get block at height %d: %w", attestation.Height, err)
+	}
+
+	merkleRoot, err := chainhash.NewHashFromStr(block.MerkleRoot)
+	if err != nil {
+		return fmt.Errorf("invalid merkle root of block %s: %w", hash, err)
+	}
+
+	check.Matches = attestation.Matches(merkleRoot)
+	return nil
+}
+
+// Verification is the result of checking settlement evidence end to end:
+// the stored bundle still hashes to the recorded digest, and the proof of
+// that hash ends in the merkle root of a block on the node's chain
+type Verification struct {
+	ContractID    uuid.UUID             `json:"contract_id"`
+	Digest        string                `json:"digest"`
+	DigestMatches bool                  `json:"digest_matches"`
+	Status        models.EvidenceStatus `json:"status"`
+	Blocks        []BlockCheck          `json:"blocks"`
+	Pending       []string              `json:"pending_calendars"`
+	Verified      bool                  `json:"verified"`
+}
+
+// Verify checks the evidence of a contract against its proof and the chain
+func (a *Anchorer) Verify(ctx context.Context, contractID uuid.UUID) (*Verification, error) {
+	evidence, err := a.repo.Get(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(evidence.Bundle)
+	v := &Verification{
+		ContractID:    contractID,
+		Digest:        evidence.Digest,
+		DigestMatches: hex.EncodeToString(digest[:]) == evidence.Digest,
+		Status:        evidence.Status,
+		Blocks:        []BlockCheck{},
+		Pending:       []string{},
+	}
+	if len(evidence.Proof) == 0 {
+		return v, nil
+	}
+
+	// The proof is replayed from the recomputed digest, so a match shows the
+	// stored bundle itself was committed to the block
+	ts, err := ots.Deserialize(digest[:], evidence.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode proof: %w", err)
+	}
+
+	v.Blocks = a.checkBlocks(ctx, ts)
+	for _, pending := range ts.PendingAttestations() {
+		v.Pending = append(v.Pending, pending.URI)
+	}
+	for _, check := range v.Blocks {
+		if check.Matches {
+			v.Verified = v.DigestMatches
+		}
+	}
+
+	return v, nil
+}
+
+// ProofFile returns the evidence proof as a detached .ots file that the
+// standard OpenTimestamps client can verify against the bundle
+func (a *Anchorer) ProofFile(ctx context.Context, contractID uuid.UUID) ([]byte, error) {
+	evidence, err := a.repo.Get(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if len(evidence.Proof) == 0 {
+		return nil, ErrNoProof
+	}
+
+	digest, err := hex.DecodeString(evidence.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid evidence digest: %w", err)
+	}
+
+	ts, err := ots.Deserialize(digest, evidence.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode proof: %w", err)
+	}
+
+	return ots.WriteDetached(ts)
+}
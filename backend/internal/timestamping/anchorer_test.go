@@ -0,0 +1,89 @@
+// internal/timestamping/anchorer_test.go
+package timestamping
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/pkg/bitcoin"
+	ots "hashhedge/pkg/opentimestamps"
+)
+
+// fakeChain serves blocks whose merkle roots are set by the test
+type fakeChain struct {
+	roots map[int64]string
+}
+
+func (c *fakeChain) GetBlockHash(ctx context.Context, height int64) (string, error) {
+	if _, ok := c.roots[height]; !ok {
+		return "", fmt.Errorf("no block at height %d", height)
+	}
+	return fmt.Sprintf("hash-%d", height), nil
+}
+
+func (c *fakeChain) GetBlock(ctx context.Context, hash string) (*bitcoin.Block, error) {
+	var height int64
+	if _, err := fmt.Sscanf(hash, "hash-%d", &height); err != nil {
+		return nil, err
+	}
+	return &bitcoin.Block{Hash: hash, Height: height, MerkleRoot: c.roots[height]}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+
+	cfg := DefaultConfig
+	cfg.Calendars = []string{"https://alice.btc.calendar.opentimestamps.org"}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Calendars = []string{"alice.btc.calendar.opentimestamps.org"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Calendars = []string{"https://alice.btc.calendar.opentimestamps.org"}
+	cfg.BatchSize = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestCheckBlocks(t *testing.T) {
+	digest := sha256.Sum256([]byte("settlement evidence"))
+	ts := ots.NewTimestamp(digest[:])
+	root, err := ts.Add(ots.SHA256())
+	require.NoError(t, err)
+	root.Attest(ots.BitcoinAttestation(850000))
+	ts.Attest(ots.BitcoinAttestation(850001))
+
+	merkleRoot, err := chainhash.NewHash(root.Msg)
+	require.NoError(t, err)
+
+	a := &Anchorer{chain: &fakeChain{roots: map[int64]string{
+		850000: merkleRoot.String(),
+		850001: chainhash.Hash{}.String(),
+	}}}
+
+	checks := a.checkBlocks(context.Background(), ts)
+	require.Len(t, checks, 2)
+	byHeight := map[int64]BlockCheck{checks[0].Height: checks[0], checks[1].Height: checks[1]}
+	assert.True(t, byHeight[850000].Matches)
+	assert.Equal(t, "hash-850000", byHeight[850000].BlockHash)
+	assert.False(t, byHeight[850001].Matches)
+	assert.Empty(t, byHeight[850001].Error)
+
+	// Attestations to blocks the node does not have are reported, not fatal
+	a.chain = &fakeChain{roots: map[int64]string{}}
+	checks = a.checkBlocks(context.Background(), ts)
+	require.Len(t, checks, 2)
+	assert.False(t, checks[0].Matches)
+	assert.NotEmpty(t, checks[0].Error)
+}
+
+func TestCalendarLookup(t *testing.T) {
+	a := NewAnchorer(nil, nil, Config{Calendars: []string{"https://a.example/"}})
+	assert.NotNil(t, a.calendar("https://a.example"))
+	assert.Nil(t, a.calendar("https://evil.example"))
+}
@@ -0,0 +1,112 @@
+// internal/tracing/tracing.go
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config selects where spans are exported
+type Config struct {
+	// Endpoint is the host:port of an OTLP/HTTP collector; empty disables
+	// exporting, though trace context is still propagated
+	Endpoint string `yaml:"endpoint"`
+	// Insecure sends spans to the collector over plain HTTP
+	Insecure bool `yaml:"insecure"`
+	// ServiceName identifies this service in the collector
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio is the share of traces started here that are recorded.
+	// Traces started by a caller follow the caller's decision.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// DefaultConfig records every trace once a collector is configured
+var DefaultConfig = Config{
+	ServiceName: "hashhedge",
+	SampleRatio: 1,
+}
+
+// Enabled reports whether spans are exported
+func (c Config) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// Validate checks the service name and sample ratio
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.ServiceName == "" {
+		return fmt.Errorf("tracing service name cannot be empty")
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// Setup installs the W3C trace context propagator and, when enabled, a
+// tracer provider exporting to the collector. The returned function flushes
+// buffered spans and stops the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of a component. It follows the provider
+// installed by Setup, so package-level tracers may be created before it.
+func Tracer(component string) trace.Tracer {
+	return otel.Tracer("hashhedge/" + component)
+}
+
+// StartChild starts a span only when ctx already carries one, so calls made
+// by a traced request or task show up in its trace without background polls
+// starting traces of their own. Otherwise the span returned does nothing.
+func StartChild(ctx context.Context, tracer trace.Tracer, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
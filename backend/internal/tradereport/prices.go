@@ -0,0 +1,75 @@
+// internal/tradereport/prices.go
+package tradereport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIPriceSource reads historical bitcoin prices from a mempool.space
+// style API, e.g. https://mempool.space/api/v1/historical-price
+type APIPriceSource struct {
+	url    string
+	client *http.Client
+}
+
+// NewAPIPriceSource creates a price source backed by an external API
+func NewAPIPriceSource(url string) *APIPriceSource {
+	return &APIPriceSource{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. with one connecting through
+// a proxy
+func (s *APIPriceSource) WithHTTPClient(client *http.Client) *APIPriceSource {
+	s.client = client
+	return s
+}
+
+// historicalPrices is the response of a mempool.space style historical
+// price API: the prices nearest the requested time, keyed by currency
+type historicalPrices struct {
+	Prices []map[string]float64 `json:"prices"`
+}
+
+// BTCPrice returns the price of one bitcoin in currency at the start of day
+func (s *APIPriceSource) BTCPrice(ctx context.Context, currency string, day time.Time) (float64, error) {
+	currency = strings.ToUpper(currency)
+	query := url.Values{
+		"currency":  {currency},
+		"timestamp": {strconv.FormatInt(day.Unix(), 10)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create price API request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query price API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price API returned status %d", resp.StatusCode)
+	}
+
+	var prices historicalPrices
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, fmt.Errorf("failed to decode price API response: %w", err)
+	}
+
+	if len(prices.Prices) == 0 || prices.Prices[0][currency] <= 0 {
+		return 0, fmt.Errorf("price API has no %s price for %s", currency, day.Format("2006-01-02"))
+	}
+	return prices.Prices[0][currency], nil
+}
@@ -0,0 +1,276 @@
+// internal/tradereport/report.go
+package tradereport
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Contract)
+
+// satoshisPerBitcoin converts satoshi amounts to fiat at a bitcoin price
+const satoshisPerBitcoin = 100_000_000
+
+// RecordType is the kind of movement a record reports
+type RecordType string
+
+const (
+	// RecordTrade is the premium of a trade: paid by the buyer and
+	// received by the seller
+	RecordTrade RecordType = "trade"
+	// RecordContract is the contract size each party commits to the
+	// contract the trade opened
+	RecordContract RecordType = "contract"
+	// RecordPayout is what a party received when the contract settled:
+	// both parties' commitments to the winner and nothing to the loser
+	RecordPayout RecordType = "payout"
+)
+
+// Record is one movement of a user's satoshis. Amounts are signed from the
+// user's side, so the amounts of a contract's records add up to its
+// realized P&L.
+type Record struct {
+	Type           RecordType            `json:"type"`
+	Time           time.Time             `json:"time"`
+	ContractID     uuid.UUID             `json:"contract_id"`
+	TradeID        uuid.UUID             `json:"trade_id"`
+	ContractType   models.ContractType   `json:"contract_type"`
+	StrikeHashRate float64               `json:"strike_hash_rate"`
+	Side           models.OrderSide      `json:"side"`
+	Status         models.ContractStatus `json:"status"`
+	Price          int64                 `json:"price"`
+	Quantity       int                   `json:"quantity"`
+	Amount         int64                 `json:"amount"` // In satoshis
+	// BTCPrice and FiatAmount are the price of a bitcoin on the day of
+	// the record and the amount converted at it, absent when no price
+	// source is configured or it has no price for the day
+	BTCPrice   *float64 `json:"btc_price,omitempty"`
+	FiatAmount *float64 `json:"fiat_amount,omitempty"`
+	TxID       string   `json:"txid,omitempty"`
+}
+
+// Report is the records of a user over [From, To), oldest first
+type Report struct {
+	UserID   uuid.UUID `json:"user_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Currency string    `json:"currency,omitempty"`
+	Records  []*Record `json:"records"`
+}
+
+// EntryStore lists the trade entries of a user
+type EntryStore interface {
+	ListReportEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.TradeReportEntry, error)
+}
+
+// PriceSource returns the price of one bitcoin in a fiat currency on the
+// UTC day of a time
+type PriceSource interface {
+	BTCPrice(ctx context.Context, currency string, day time.Time) (float64, error)
+}
+
+// Config selects the fiat currency of trade reports and where prices come from
+type Config struct {
+	// Currency is the fiat currency amounts are converted to, e.g. USD
+	Currency string `yaml:"currency"`
+	// PriceAPIURL is a historical price API in the mempool.space format;
+	// empty reports satoshi amounts alone
+	PriceAPIURL string `yaml:"price_api_url"`
+}
+
+// DefaultConfig reports in satoshis alone, converting to USD once a price
+// API is configured
+var DefaultConfig = Config{Currency: "USD"}
+
+// Validate checks that the currency is set when prices are
+func (c Config) Validate() error {
+	if c.PriceAPIURL != "" && c.Currency == "" {
+		return fmt.Errorf("trade report currency is required with a price API")
+	}
+	return nil
+}
+
+// Exporter builds users' trade reports for tax and accounting
+type Exporter struct {
+	entries  EntryStore
+	prices   PriceSource
+	currency string
+}
+
+// NewExporter creates a trade report exporter reporting satoshi amounts
+func NewExporter(entries EntryStore) *Exporter {
+	return &Exporter{entries: entries}
+}
+
+// WithPriceSource converts amounts to currency at the prices of a source
+func (e *Exporter) WithPriceSource(prices PriceSource, currency string) *Exporter {
+	e.prices = prices
+	e.currency = currency
+	return e
+}
+
+// Build returns the report of a user's trades, contracts and payouts over
+// [from, to)
+func (e *Exporter) Build(ctx context.Context, userID uuid.UUID, from, to time.Time) (*Report, error) {
+	entries, err := e.entries.ListReportEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trade entries: %w", err)
+	}
+
+	report := &Report{UserID: userID, From: from, To: to, Currency: e.currency, Records: Records(entries, from, to)}
+	e.convert(ctx, report.Records)
+	return report, nil
+}
+
+// Records returns the records of trade entries that fall in [from, to),
+// oldest first. A trade's premium and contract are recorded when it
+// executed and its payout when the contract settled. Contracts the
+// parties closed cooperatively have no payout record, as their split is
+// agreed between the parties rather than set by the outcome.
+func Records(entries []*models.TradeReportEntry, from, to time.Time) []*Record {
+	in := func(t time.Time) bool {
+		return !t.Before(from) && t.Before(to)
+	}
+
+	records := make([]*Record, 0, 2*len(entries))
+	for _, entry := range entries {
+		record := func(recordType RecordType, at time.Time, amount int64) *Record {
+			return &Record{
+				Type:           recordType,
+				Time:           at.UTC(),
+				ContractID:     entry.ID,
+				TradeID:        entry.TradeID,
+				ContractType:   entry.ContractType,
+				StrikeHashRate: entry.StrikeHashRate,
+				Side:           entry.Side,
+				Status:         entry.Status,
+				Price:          entry.EntryPrice,
+				Quantity:       entry.Quantity,
+				Amount:         amount,
+			}
+		}
+		buyer := entry.Side == models.OrderSideBuy
+
+		if in(entry.OpenedAt) {
+			premium := entry.Premium
+			if buyer {
+				premium = -premium
+			}
+			records = append(records,
+				record(RecordTrade, entry.OpenedAt, premium),
+				record(RecordContract, entry.OpenedAt, -entry.ContractSize))
+		}
+
+		if entry.SettledAt != nil && entry.BuyerWins != nil && in(*entry.SettledAt) {
+			var payout int64
+			if *entry.BuyerWins == buyer {
+				payout = 2 * entry.ContractSize
+			}
+			r := record(RecordPayout, *entry.SettledAt, payout)
+			if entry.SettlementTxID != nil {
+				r.TxID = *entry.SettlementTxID
+			}
+			records = append(records, r)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records
+}
+
+// convert sets the fiat amounts of records, asking the price source once
+// per day. Records of days without a price are left in satoshis.
+func (e *Exporter) convert(ctx context.Context, records []*Record) {
+	if e.prices == nil {
+		return
+	}
+
+	prices := make(map[string]*float64)
+	for _, r := range records {
+		day := r.Time.Truncate(24 * time.Hour)
+		key := day.Format("2006-01-02")
+
+		price, ok := prices[key]
+		if !ok {
+			p, err := e.prices.BTCPrice(ctx, e.currency, day)
+			if err != nil {
+				logger.Warn().Err(err).Str("day", key).Str("currency", e.currency).
+					Msg("Failed to get bitcoin price, leaving trade report amounts in satoshis")
+			} else {
+				price = &p
+			}
+			prices[key] = price
+		}
+		if price == nil {
+			continue
+		}
+
+		fiat := float64(r.Amount) * *price / satoshisPerBitcoin
+		r.BTCPrice = price
+		r.FiatAmount = &fiat
+	}
+}
+
+// csvHeader names the columns written by WriteCSV
+var csvHeader = []string{
+	"time", "type", "contract_id", "trade_id", "contract_type", "strike_hash_rate", "side", "status",
+	"price", "quantity", "amount_sats", "currency", "btc_price", "fiat_amount", "txid",
+}
+
+// WriteCSV writes the records of a report as CSV, flushing after each one
+// so a large report streams to the client
+func WriteCSV(w io.Writer, report *Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range report.Records {
+		var currency, price, fiat string
+		if r.FiatAmount != nil {
+			currency = report.Currency
+			price = strconv.FormatFloat(*r.BTCPrice, 'f', 2, 64)
+			fiat = strconv.FormatFloat(*r.FiatAmount, 'f', 2, 64)
+		}
+		record := []string{
+			r.Time.Format(time.RFC3339),
+			string(r.Type),
+			r.ContractID.String(),
+			r.TradeID.String(),
+			string(r.ContractType),
+			strconv.FormatFloat(r.StrikeHashRate, 'f', -1, 64),
+			string(r.Side),
+			string(r.Status),
+			strconv.FormatInt(r.Price, 10),
+			strconv.Itoa(r.Quantity),
+			strconv.FormatInt(r.Amount, 10),
+			currency,
+			price,
+			fiat,
+			r.TxID,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+		cw.Flush()
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes a report as a JSON document
+func WriteJSON(w io.Writer, report *Report) error {
+	return json.NewEncoder(w).Encode(report)
+}
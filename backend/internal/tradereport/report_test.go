@@ -0,0 +1,126 @@
+// internal/tradereport/report_test.go
+package tradereport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+type stubEntries []*models.TradeReportEntry
+
+func (s stubEntries) ListReportEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.TradeReportEntry, error) {
+	return s, nil
+}
+
+// dailyPrices prices bitcoin by day, failing for days it has no price for
+type dailyPrices map[string]float64
+
+func (p dailyPrices) BTCPrice(ctx context.Context, currency string, day time.Time) (float64, error) {
+	price, ok := p[day.Format("2006-01-02")]
+	if !ok {
+		return 0, errors.New("no price")
+	}
+	return price, nil
+}
+
+func entry(side models.OrderSide, openedAt time.Time, settledAt *time.Time, buyerWins *bool) *models.TradeReportEntry {
+	txid := "ab"
+	e := &models.TradeReportEntry{TradeID: uuid.New(), SettledAt: settledAt, BuyerWins: buyerWins}
+	e.ID = uuid.New()
+	e.ContractType = models.ContractTypeCall
+	e.ContractSize = 1_000_000
+	e.Premium = 50_000
+	e.SettlementTxID = &txid
+	e.Side = side
+	e.OpenedAt = openedAt
+	e.Quantity = 1
+	return e
+}
+
+func TestRecords(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	yes, no := true, false
+	settled := from.AddDate(0, 0, 20)
+
+	// A buyer who won and a seller who lost the same contract
+	won := entry(models.OrderSideBuy, from.AddDate(0, 0, 1), &settled, &yes)
+	lost := entry(models.OrderSideSell, from.AddDate(0, 0, 2), &settled, &yes)
+	records := Records([]*models.TradeReportEntry{won, lost}, from, to)
+	require.Len(t, records, 6)
+
+	sums := map[uuid.UUID]int64{}
+	for _, r := range records {
+		sums[r.ContractID] += r.Amount
+	}
+	// The records of a side add up to its P&L
+	assert.Equal(t, int64(1_000_000-50_000), sums[won.ID])
+	assert.Equal(t, int64(-1_000_000+50_000), sums[lost.ID])
+	assert.Equal(t, RecordPayout, records[5].Type)
+	assert.Equal(t, "ab", records[5].TxID)
+
+	// Only the events in range are recorded: a trade before the range
+	// that settled in it has just its payout, and a cooperative close none
+	earlier := entry(models.OrderSideSell, from.AddDate(0, 0, -5), &settled, &no)
+	closed := entry(models.OrderSideBuy, from.AddDate(0, 0, -5), &settled, nil)
+	records = Records([]*models.TradeReportEntry{earlier, closed}, from, to)
+	require.Len(t, records, 1)
+	assert.Equal(t, RecordPayout, records[0].Type)
+	assert.Equal(t, int64(2_000_000), records[0].Amount)
+}
+
+func TestBuildConvertsAndWritesCSV(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := stubEntries{
+		entry(models.OrderSideBuy, from.Add(36*time.Hour), nil, nil),
+		entry(models.OrderSideBuy, from.Add(60*time.Hour), nil, nil),
+	}
+	exporter := NewExporter(entries).WithPriceSource(dailyPrices{"2026-01-02": 100_000}, "USD")
+
+	report, err := exporter.Build(context.Background(), uuid.New(), from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	require.Len(t, report.Records, 4)
+
+	// 50,000 sats of premium paid at 100,000 USD per bitcoin
+	require.NotNil(t, report.Records[0].FiatAmount)
+	assert.InDelta(t, -50.0, *report.Records[0].FiatAmount, 1e-9)
+	// A day without a price is left in satoshis
+	assert.Nil(t, report.Records[2].FiatAmount)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, report))
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"-50000", "USD", "100000.00", "-50.00"}, rows[1][10:14])
+	assert.Equal(t, []string{"-50000", "", "", ""}, rows[3][10:14])
+}
+
+func TestAPIPriceSource(t *testing.T) {
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1767312000", r.URL.Query().Get("timestamp"))
+		w.Write([]byte(`{"prices":[{"time":1767312000,"USD":101000,"EUR":93000.5}],"exchangeRates":{}}`))
+	}))
+	defer server.Close()
+
+	price, err := NewAPIPriceSource(server.URL).BTCPrice(context.Background(), "eur", day)
+	require.NoError(t, err)
+	assert.Equal(t, 93000.5, price)
+
+	_, err = NewAPIPriceSource(server.URL).BTCPrice(context.Background(), "JPY", day)
+	assert.Error(t, err)
+}
@@ -0,0 +1,234 @@
+// internal/usage/tracker.go
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.HTTP)
+
+// Config holds the usage tracking configuration
+type Config struct {
+	// FlushInterval is how often buffered counters are written to the usage table
+	FlushInterval time.Duration `yaml:"flush_interval"`
+}
+
+// DefaultConfig provides sensible defaults for usage tracking
+var DefaultConfig = Config{
+	FlushInterval: 30 * time.Second,
+}
+
+type contextKey struct{}
+
+// WithAPIKey returns a context carrying the API key that authenticated a request
+func WithAPIKey(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// APIKeyFromContext returns the API key that authenticated a request, if any
+func APIKeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(*models.APIKey)
+	return key, ok
+}
+
+// Counters are the usage of one API key in the current hour
+type Counters struct {
+	Requests   int64 `json:"requests"`
+	Errors     int64 `json:"errors"`
+	WSMessages int64 `json:"ws_messages"`
+	WSBytes    int64 `json:"ws_bytes"`
+}
+
+// ErrorRate returns the share of requests that failed
+func (c Counters) ErrorRate() float64 {
+	if c.Requests == 0 {
+		return 0
+	}
+	return float64(c.Errors) / float64(c.Requests)
+}
+
+type bucketKey struct {
+	keyID       uuid.UUID
+	periodStart time.Time
+}
+
+type bucket struct {
+	userID uuid.UUID
+	Counters
+}
+
+// Tracker buffers per-key usage in memory and periodically adds it to the usage table
+type Tracker struct {
+	repo *db.UsageRepository
+	cfg  Config
+
+	mu      sync.Mutex
+	pending map[bucketKey]*bucket
+	// current holds the full counters of the current hour, including flushed ones,
+	// so quota decisions do not need a database round trip
+	current     map[uuid.UUID]*Counters
+	currentHour time.Time
+}
+
+// NewTracker creates a new usage tracker
+func NewTracker(repo *db.UsageRepository, cfg Config) *Tracker {
+	return &Tracker{
+		repo:    repo,
+		cfg:     cfg,
+		pending: make(map[bucketKey]*bucket),
+		current: make(map[uuid.UUID]*Counters),
+	}
+}
+
+// RecordRequest counts an HTTP request made with the API key in ctx
+func (t *Tracker) RecordRequest(ctx context.Context, status int) {
+	t.record(ctx, func(c *Counters) {
+		c.Requests++
+		if status >= 400 {
+			c.Errors++
+		}
+	})
+}
+
+// RecordWebsocketBytes counts a websocket message sent to a client that
+// connected with the API key in ctx
+func (t *Tracker) RecordWebsocketBytes(ctx context.Context, bytes int) {
+	t.record(ctx, func(c *Counters) {
+		c.WSMessages++
+		c.WSBytes += int64(bytes)
+	})
+}
+
+// Current returns the usage of an API key in the current hour. The rate
+// limiter uses this to adjust quotas for clients with high error rates.
+func (t *Tracker) Current(keyID uuid.UUID) Counters {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollHour(time.Now().UTC())
+	if c, ok := t.current[keyID]; ok {
+		return *c
+	}
+	return Counters{}
+}
+
+// ListByUser retrieves the stored hourly usage of a user's keys
+func (t *Tracker) ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*models.APIUsage, error) {
+	return t.repo.ListByUser(ctx, userID, from, to)
+}
+
+// Aggregate retrieves the stored usage summed per key, busiest first
+func (t *Tracker) Aggregate(ctx context.Context, from, to time.Time, limit int) ([]*models.UsageAggregate, error) {
+	return t.repo.Aggregate(ctx, from, to, limit)
+}
+
+// Start begins flushing buffered counters. Remaining counters are flushed when ctx is cancelled.
+func (t *Tracker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(t.cfg.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				t.flush(flushCtx)
+				cancel()
+				return
+			case <-ticker.C:
+				t.flush(ctx)
+			}
+		}
+	}()
+}
+
+// record applies update to the pending and current counters of the key in ctx
+func (t *Tracker) record(ctx context.Context, update func(*Counters)) {
+	key, ok := APIKeyFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.rollHour(now)
+
+	bk := bucketKey{keyID: key.ID, periodStart: now.Truncate(time.Hour)}
+	b, ok := t.pending[bk]
+	if !ok {
+		b = &bucket{userID: key.UserID}
+		t.pending[bk] = b
+	}
+	update(&b.Counters)
+
+	c, ok := t.current[key.ID]
+	if !ok {
+		c = &Counters{}
+		t.current[key.ID] = c
+	}
+	update(c)
+}
+
+// rollHour resets the current counters when the hour changes. The caller must hold t.mu.
+func (t *Tracker) rollHour(now time.Time) {
+	hour := now.Truncate(time.Hour)
+	if hour.Equal(t.currentHour) {
+		return
+	}
+	t.currentHour = hour
+	t.current = make(map[uuid.UUID]*Counters)
+}
+
+// flush writes the pending counters to the usage table, keeping them for the
+// next flush if the write fails
+func (t *Tracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[bucketKey]*bucket)
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	rows := make([]*models.APIUsage, 0, len(pending))
+	for bk, b := range pending {
+		rows = append(rows, &models.APIUsage{
+			APIKeyID:    bk.keyID,
+			UserID:      b.userID,
+			PeriodStart: bk.periodStart,
+			Requests:    b.Requests,
+			Errors:      b.Errors,
+			WSMessages:  b.WSMessages,
+			WSBytes:     b.WSBytes,
+		})
+	}
+
+	if err := t.repo.Add(ctx, rows); err != nil {
+		logger.Error().Err(err).Int("rows", len(rows)).Msg("Failed to flush API usage")
+
+		t.mu.Lock()
+		for bk, b := range pending {
+			if existing, ok := t.pending[bk]; ok {
+				existing.Requests += b.Requests
+				existing.Errors += b.Errors
+				existing.WSMessages += b.WSMessages
+				existing.WSBytes += b.WSBytes
+			} else {
+				t.pending[bk] = b
+			}
+		}
+		t.mu.Unlock()
+	}
+}
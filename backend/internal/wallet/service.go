@@ -0,0 +1,240 @@
+// internal/wallet/service.go
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/models"
+)
+
+var logger = logging.Component(logging.Bitcoin)
+
+// Chain reads blocks from the Bitcoin node
+type Chain interface {
+	GetBlockCount(ctx context.Context) (int64, error)
+	GetBlockHash(ctx context.Context, height int64) (string, error)
+	GetRawBlock(ctx context.Context, hash string) (*wire.MsgBlock, error)
+}
+
+// Ledger credits confirmed deposits to users' balances
+type Ledger interface {
+	CreditDeposit(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error
+}
+
+// Service gives each user a taproot deposit address derived from the
+// configured xpub, searches new blocks for outputs paying them and credits
+// each to its user's balance once it has enough confirmations. Deposits
+// whose block leaves the chain before then are dropped and the blocks
+// searched again.
+type Service struct {
+	repo   *db.DepositRepository
+	chain  Chain
+	ledger Ledger
+	keys   *hdkeychain.ExtendedKey
+	params *chaincfg.Params
+	cfg    Config
+}
+
+// NewService creates a new wallet service deriving addresses for a network
+func NewService(repo *db.DepositRepository, chain Chain, ledger Ledger, params *chaincfg.Params, cfg Config) (*Service, error) {
+	account, err := parseXPub(cfg.XPub)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := externalChain(account, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		repo:   repo,
+		chain:  chain,
+		ledger: ledger,
+		keys:   keys,
+		params: params,
+		cfg:    cfg,
+	}, nil
+}
+
+// DepositAddress returns a user's deposit address, deriving one at the next
+// index on first use
+func (s *Service) DepositAddress(ctx context.Context, userID uuid.UUID) (*models.DepositAddress, error) {
+	address, err := s.repo.GetAddress(ctx, userID)
+	if err == nil {
+		return address, nil
+	}
+	if !errors.Is(err, db.ErrNotFound) {
+		return nil, err
+	}
+
+	index, err := s.repo.NextIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	address, err = deriveAddress(s.keys, uint32(index), s.params)
+	if err != nil {
+		return nil, err
+	}
+	address.UserID = userID
+
+	return s.repo.CreateAddress(ctx, address)
+}
+
+// Deposits retrieves a page of a user's deposits, newest first
+func (s *Service) Deposits(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Deposit, error) {
+	return s.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// Start searches new blocks and credits confirmed deposits on the
+// configured interval until ctx is cancelled
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.run(ctx); err != nil {
+					logger.Error().Err(err).Msg("Failed to process deposits")
+				}
+			}
+		}
+	}()
+}
+
+// run searches the blocks since the last run and credits the deposits that
+// are now confirmed
+func (s *Service) run(ctx context.Context) error {
+	tip, err := s.chain.GetBlockCount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain tip: %w", err)
+	}
+
+	if err := s.scan(ctx, tip); err != nil {
+		return err
+	}
+
+	credited, err := s.credit(ctx, tip)
+	if credited > 0 {
+		logger.Info().Int("credited", credited).Msg("Credited deposits")
+	}
+	return err
+}
+
+// scan records the outputs paying deposit addresses in the blocks after the
+// last one searched, up to the tip. The first scan starts at the tip.
+func (s *Service) scan(ctx context.Context, tip int64) error {
+	height, ok, err := s.repo.GetScanHeight(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.repo.SetScanHeight(ctx, tip)
+	}
+	if height >= tip {
+		return nil
+	}
+
+	addresses, err := s.repo.ListAddresses(ctx)
+	if err != nil {
+		return err
+	}
+	scripts := make(map[string]uuid.UUID, len(addresses))
+	for _, address := range addresses {
+		scripts[address.ScriptPubKey] = address.UserID
+	}
+
+	last := height + s.cfg.MaxBlocksPerScan
+	if last > tip {
+		last = tip
+	}
+	for h := height + 1; h <= last; h++ {
+		hash, err := s.chain.GetBlockHash(ctx, h)
+		if err != nil {
+			return err
+		}
+		block, err := s.chain.GetRawBlock(ctx, hash)
+		if err != nil {
+			return err
+		}
+
+		for _, deposit := range findDeposits(block, scripts) {
+			deposit.BlockHash = hash
+			deposit.BlockHeight = h
+			recorded, err := s.repo.RecordDeposit(ctx, deposit)
+			if err != nil {
+				return err
+			}
+			if recorded {
+				logger.Info().
+					Str("user_id", deposit.UserID.String()).
+					Str("txid", deposit.TxID).
+					Int64("amount", deposit.Amount).
+					Int64("height", h).
+					Msg("Deposit detected")
+			}
+		}
+
+		if err := s.repo.SetScanHeight(ctx, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// credit credits the pending deposits with enough confirmations at tip and
+// returns how many were. A deposit whose block is no longer in the chain is
+// dropped and its height searched again.
+func (s *Service) credit(ctx context.Context, tip int64) (int, error) {
+	pending, err := s.repo.ListPending(ctx, tip-s.cfg.Confirmations+1)
+	if err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, deposit := range pending {
+		hash, err := s.chain.GetBlockHash(ctx, deposit.BlockHeight)
+		if err != nil {
+			return credited, err
+		}
+
+		if hash != deposit.BlockHash {
+			logger.Warn().
+				Str("txid", deposit.TxID).
+				Int64("height", deposit.BlockHeight).
+				Msg("Deposit block was reorganized out, searching again")
+			if err := s.repo.DeleteDeposit(ctx, deposit.ID); err != nil {
+				return credited, err
+			}
+			if err := s.repo.RewindScanHeight(ctx, deposit.BlockHeight-1); err != nil {
+				return credited, err
+			}
+			continue
+		}
+
+		err = s.repo.Credit(ctx, deposit.ID, func(tx *sqlx.Tx) error {
+			return s.ledger.CreditDeposit(ctx, tx, deposit.UserID, deposit.Amount, reference(deposit))
+		})
+		if err != nil {
+			return credited, fmt.Errorf("failed to credit deposit %s:%d: %w", deposit.TxID, deposit.Vout, err)
+		}
+		credited++
+	}
+
+	return credited, nil
+}
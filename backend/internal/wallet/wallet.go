@@ -0,0 +1,162 @@
+// internal/wallet/wallet.go
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+
+	"hashhedge/internal/models"
+)
+
+// Config controls deposit addresses and the detection of their funding
+type Config struct {
+	// XPub is the account-level extended public key deposit addresses are
+	// derived from, as m/86'/0'/0' is for BIP-86; empty disables deposits
+	XPub string `yaml:"xpub"`
+	// Interval is how often new blocks are searched for deposits
+	Interval time.Duration `yaml:"interval"`
+	// Confirmations is the depth at which a deposit is credited
+	Confirmations int64 `yaml:"confirmations"`
+	// MaxBlocksPerScan bounds the blocks searched on each interval, so a
+	// long catch-up does not hold up crediting
+	MaxBlocksPerScan int64 `yaml:"max_blocks_per_scan"`
+	// Withdrawals controls paying out users' balances
+	Withdrawals WithdrawalConfig `yaml:"withdrawals"`
+}
+
+// DefaultConfig leaves deposits disabled. Once an xpub is configured, new
+// blocks are searched every minute and deposits credited at three
+// confirmations.
+var DefaultConfig = Config{
+	Interval:         time.Minute,
+	Confirmations:    3,
+	MaxBlocksPerScan: 144,
+	Withdrawals:      DefaultWithdrawalConfig,
+}
+
+// Enabled reports whether users are given deposit addresses
+func (c Config) Enabled() bool {
+	return c.XPub != ""
+}
+
+// Validate checks that the deposit settings are usable
+func (c Config) Validate() error {
+	if err := c.Withdrawals.Validate(); err != nil {
+		return err
+	}
+	if !c.Enabled() {
+		return nil
+	}
+	if _, err := parseXPub(c.XPub); err != nil {
+		return err
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("deposit scan interval must be positive")
+	}
+	if c.Confirmations < 1 {
+		return fmt.Errorf("deposit confirmations must be at least one")
+	}
+	if c.MaxBlocksPerScan < 1 {
+		return fmt.Errorf("deposit scan must cover at least one block")
+	}
+	return nil
+}
+
+// parseXPub parses an extended public key, rejecting private keys so the
+// server never holds the means to spend deposits
+func parseXPub(xpub string) (*hdkeychain.ExtendedKey, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deposit xpub: %w", err)
+	}
+	if key.IsPrivate() {
+		return nil, fmt.Errorf("deposit xpub must be an extended public key")
+	}
+	return key, nil
+}
+
+// externalChain returns the key of the receiving chain of an account key
+func externalChain(account *hdkeychain.ExtendedKey, params *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+	if !account.IsForNet(params) {
+		return nil, fmt.Errorf("deposit xpub is not for %s", params.Name)
+	}
+	chain, err := account.Derive(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive receiving chain: %w", err)
+	}
+	return chain, nil
+}
+
+// deriveAddress returns the key path only taproot address (BIP-86) of a
+// child of the receiving chain
+func deriveAddress(chain *hdkeychain.ExtendedKey, index uint32, params *chaincfg.Params) (*models.DepositAddress, error) {
+	if index >= hdkeychain.HardenedKeyStart {
+		return nil, fmt.Errorf("deposit address index %d is out of range", index)
+	}
+
+	child, err := chain.Derive(index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive deposit key %d: %w", index, err)
+	}
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deposit key %d: %w", index, err)
+	}
+
+	outputKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+	address, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create taproot address: %w", err)
+	}
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deposit script: %w", err)
+	}
+
+	return &models.DepositAddress{
+		DerivationIndex: int64(index),
+		Address:         address.EncodeAddress(),
+		ScriptPubKey:    hex.EncodeToString(script),
+	}, nil
+}
+
+// findDeposits returns the outputs of a block paying one of the deposit
+// scripts, keyed by hex script to the owning user
+func findDeposits(block *wire.MsgBlock, scripts map[string]uuid.UUID) []*models.Deposit {
+	var deposits []*models.Deposit
+	for _, tx := range block.Transactions {
+		var txid chainhash.Hash
+		for vout, out := range tx.TxOut {
+			userID, ok := scripts[hex.EncodeToString(out.PkScript)]
+			if !ok || out.Value <= 0 {
+				continue
+			}
+			if txid == (chainhash.Hash{}) {
+				txid = tx.TxHash()
+			}
+			deposits = append(deposits, &models.Deposit{
+				UserID: userID,
+				TxID:   txid.String(),
+				Vout:   int64(vout),
+				Amount: out.Value,
+			})
+		}
+	}
+	return deposits
+}
+
+// reference is the ledger reference of a deposit, so an output is never
+// credited twice
+func reference(deposit *models.Deposit) string {
+	return fmt.Sprintf("deposit:%s:%d", deposit.TxID, deposit.Vout)
+}
@@ -0,0 +1,85 @@
+// internal/wallet/wallet_test.go
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Account key m/86'/0'/0' of the BIP-86 test vectors
+const bip86XPub = "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ"
+
+func TestDeriveAddress(t *testing.T) {
+	account, err := parseXPub(bip86XPub)
+	require.NoError(t, err)
+	chain, err := externalChain(account, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+
+	first, err := deriveAddress(chain, 0, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", first.Address)
+	assert.Equal(t, int64(0), first.DerivationIndex)
+	assert.Equal(t, "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", first.ScriptPubKey)
+
+	second, err := deriveAddress(chain, 1, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh", second.Address)
+
+	// Hardened children cannot be derived from a public key
+	_, err = deriveAddress(chain, 1<<31, &chaincfg.MainNetParams)
+	assert.Error(t, err)
+
+	// A mainnet key is not used for test networks
+	_, err = externalChain(account, &chaincfg.TestNet3Params)
+	assert.Error(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	cfg := DefaultConfig
+	cfg.XPub = bip86XPub
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Confirmations = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig
+	cfg.XPub = "not an xpub"
+	assert.Error(t, cfg.Validate())
+
+	// The BIP-32 test vector master private key
+	cfg.XPub = "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestFindDeposits(t *testing.T) {
+	userID := uuid.New()
+	script, err := hex.DecodeString("5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c")
+	require.NoError(t, err)
+	scripts := map[string]uuid.UUID{hex.EncodeToString(script): userID}
+
+	funding := wire.NewMsgTx(2)
+	funding.AddTxOut(wire.NewTxOut(1000, []byte{0x51}))
+	funding.AddTxOut(wire.NewTxOut(250_000, script))
+	unrelated := wire.NewMsgTx(2)
+	unrelated.AddTxOut(wire.NewTxOut(5000, []byte{0x51}))
+
+	block := &wire.MsgBlock{Transactions: []*wire.MsgTx{unrelated, funding}}
+	deposits := findDeposits(block, scripts)
+	require.Len(t, deposits, 1)
+	assert.Equal(t, userID, deposits[0].UserID)
+	assert.Equal(t, funding.TxHash().String(), deposits[0].TxID)
+	assert.Equal(t, int64(1), deposits[0].Vout)
+	assert.Equal(t, int64(250_000), deposits[0].Amount)
+
+	assert.Equal(t, "deposit:"+deposits[0].TxID+":1", reference(deposits[0]))
+	assert.Empty(t, findDeposits(&wire.MsgBlock{}, scripts))
+	assert.Nil(t, findDeposits(block, map[string]uuid.UUID{}))
+}
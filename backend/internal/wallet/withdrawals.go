@@ -0,0 +1,331 @@
+// internal/wallet/withdrawals.go
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	arkv1 "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+	"hashhedge/pkg/taproot"
+)
+
+// ErrInvalidWithdrawal is returned for a withdrawal request that cannot be
+// paid out as asked
+var ErrInvalidWithdrawal = errors.New("invalid withdrawal")
+
+// maxArkAddressLength bounds the Ark addresses accepted, as stored
+const maxArkAddressLength = 200
+
+// WithdrawalConfig controls withdrawals of users' balances
+type WithdrawalConfig struct {
+	// Enabled lets users withdraw their balance
+	Enabled bool `yaml:"enabled"`
+	// MinAmount is the smallest withdrawal, keeping payouts above dust
+	MinAmount int64 `yaml:"min_amount"`
+	// ArkMaxAmount is the largest withdrawal paid out of round to an Ark
+	// address while the ASP is available; larger ones go on chain
+	ArkMaxAmount int64 `yaml:"ark_max_amount"`
+	// ApprovalThreshold is the amount above which an operator approves a
+	// withdrawal before it is paid; zero pays every withdrawal directly
+	ApprovalThreshold int64 `yaml:"approval_threshold"`
+}
+
+// DefaultWithdrawalConfig leaves withdrawals disabled. Once enabled,
+// withdrawals of up to 0.01 BTC go over Ark when they can and those above
+// 0.1 BTC wait for approval.
+var DefaultWithdrawalConfig = WithdrawalConfig{
+	MinAmount:         10_000,
+	ArkMaxAmount:      1_000_000,
+	ApprovalThreshold: 10_000_000,
+}
+
+// Validate checks that the withdrawal settings are usable
+func (c WithdrawalConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MinAmount < 1 {
+		return fmt.Errorf("minimum withdrawal must be positive")
+	}
+	if c.ArkMaxAmount < 0 || c.ApprovalThreshold < 0 {
+		return fmt.Errorf("withdrawal limits cannot be negative")
+	}
+	return nil
+}
+
+// needsApproval reports whether a withdrawal of amount waits for an operator
+func (c WithdrawalConfig) needsApproval(amount int64) bool {
+	return c.ApprovalThreshold > 0 && amount > c.ApprovalThreshold
+}
+
+// rail chooses how a withdrawal is paid: over Ark when the user gave an Ark
+// address, the amount is small enough and the ASP is up, otherwise on chain
+func (c WithdrawalConfig) rail(withdrawal *models.Withdrawal, aspAvailable bool) models.WithdrawalRail {
+	if withdrawal.ArkAddress != nil && aspAvailable && withdrawal.Amount <= c.ArkMaxAmount {
+		return models.WithdrawalRailArk
+	}
+	return models.WithdrawalRailOnChain
+}
+
+// HotWallet pays out of the platform's hot wallet
+type HotWallet interface {
+	SendToAddress(ctx context.Context, address btcutil.Address, amount int64) (string, error)
+	SignPSBT(ctx context.Context, packet string) (string, error)
+}
+
+// ASP pays out of round over Ark
+type ASP interface {
+	CheckASPStatus(ctx context.Context) (bool, error)
+	CreateOutOfRoundTransaction(ctx context.Context, senderPSBT string, outputs []*arkv1.Output) (*arkv1.CreateOutOfRoundTransactionResponse, error)
+	SignOutOfRoundTransaction(ctx context.Context, txID string, signedPSBT string) (*arkv1.SignOutOfRoundTransactionResponse, error)
+}
+
+// WithdrawalLedger debits and refunds withdrawals in the transactions
+// recording them
+type WithdrawalLedger interface {
+	DebitWithdrawal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error
+	RefundWithdrawal(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64, reference string) error
+}
+
+// WithdrawalRequest is a user's request to be paid part of their balance.
+// ArkAddress is optional; without it the withdrawal goes on chain.
+type WithdrawalRequest struct {
+	Amount     int64  `json:"amount"`
+	Address    string `json:"address"`
+	ArkAddress string `json:"ark_address,omitempty"`
+}
+
+// Withdrawals debits users' withdrawals from the ledger and pays them out
+// of the hot wallet on chain, or out of round over Ark when that is
+// cheaper and the ASP is up. Large withdrawals wait for an operator's
+// approval; a withdrawal that is rejected or whose payout fails is
+// refunded.
+type Withdrawals struct {
+	repo      *db.WithdrawalRepository
+	ledger    WithdrawalLedger
+	hotWallet HotWallet
+	asp       ASP
+	params    *chaincfg.Params
+	cfg       WithdrawalConfig
+}
+
+// NewWithdrawals creates a new withdrawal service paying out on a network
+func NewWithdrawals(repo *db.WithdrawalRepository, ledger WithdrawalLedger, hotWallet HotWallet, params *chaincfg.Params, cfg WithdrawalConfig) *Withdrawals {
+	return &Withdrawals{
+		repo:      repo,
+		ledger:    ledger,
+		hotWallet: hotWallet,
+		params:    params,
+		cfg:       cfg,
+	}
+}
+
+// WithASP enables paying small withdrawals out of round over Ark
+func (w *Withdrawals) WithASP(asp ASP) *Withdrawals {
+	w.asp = asp
+	return w
+}
+
+// Request debits a withdrawal from a user's balance and pays it out, unless
+// it needs approval first. A failed payout is returned refunded, not as an
+// error.
+func (w *Withdrawals) Request(ctx context.Context, userID uuid.UUID, req WithdrawalRequest) (*models.Withdrawal, error) {
+	withdrawal, err := w.newWithdrawal(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.repo.Create(ctx, withdrawal, func(tx *sqlx.Tx) error {
+		return w.ledger.DebitWithdrawal(ctx, tx, userID, withdrawal.Amount, debitReference(withdrawal.ID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if withdrawal.Status == models.WithdrawalStatusPendingApproval {
+		logger.Info().
+			Str("withdrawal_id", withdrawal.ID.String()).
+			Str("user_id", userID.String()).
+			Int64("amount", withdrawal.Amount).
+			Msg("Withdrawal awaiting approval")
+		return withdrawal, nil
+	}
+
+	return w.pay(ctx, withdrawal)
+}
+
+// Get retrieves a withdrawal
+func (w *Withdrawals) Get(ctx context.Context, id uuid.UUID) (*models.Withdrawal, error) {
+	return w.repo.GetByID(ctx, id)
+}
+
+// List retrieves a page of a user's withdrawals, newest first
+func (w *Withdrawals) List(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.Withdrawal, error) {
+	return w.repo.ListByUser(ctx, userID, limit, offset)
+}
+
+// ListByStatus retrieves a page of the withdrawals in a status, oldest first
+func (w *Withdrawals) ListByStatus(ctx context.Context, status models.WithdrawalStatus, limit, offset int) ([]*models.Withdrawal, error) {
+	return w.repo.ListByStatus(ctx, status, limit, offset)
+}
+
+// Approve pays out a withdrawal awaiting approval, recording the admin who
+// approved it
+func (w *Withdrawals) Approve(ctx context.Context, id, reviewer uuid.UUID) (*models.Withdrawal, error) {
+	if reviewer == uuid.Nil {
+		return nil, fmt.Errorf("%w: approval requires a reviewer", ErrInvalidWithdrawal)
+	}
+	withdrawal, err := w.repo.Transition(ctx, id, models.WithdrawalStatusPendingApproval, models.WithdrawalStatusApproved, &reviewer)
+	if err != nil {
+		return nil, err
+	}
+	return w.pay(ctx, withdrawal)
+}
+
+// Reject refunds a withdrawal awaiting approval, recording the admin who
+// rejected it
+func (w *Withdrawals) Reject(ctx context.Context, id, reviewer uuid.UUID, reason string) (*models.Withdrawal, error) {
+	if reviewer == uuid.Nil {
+		return nil, fmt.Errorf("%w: rejection requires a reviewer", ErrInvalidWithdrawal)
+	}
+	if reason == "" {
+		reason = "rejected by an operator"
+	}
+	return w.refund(ctx, id, models.WithdrawalStatusPendingApproval, models.WithdrawalStatusRejected, reason, &reviewer)
+}
+
+// newWithdrawal validates a withdrawal request
+func (w *Withdrawals) newWithdrawal(userID uuid.UUID, req WithdrawalRequest) (*models.Withdrawal, error) {
+	if req.Amount < w.cfg.MinAmount {
+		return nil, fmt.Errorf("%w: amount must be at least %d sats", ErrInvalidWithdrawal, w.cfg.MinAmount)
+	}
+
+	address, err := taproot.DecodePayoutAddress(req.Address, w.params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWithdrawal, err)
+	}
+
+	withdrawal := &models.Withdrawal{
+		ID:      uuid.New(),
+		UserID:  userID,
+		Amount:  req.Amount,
+		Address: address,
+		Status:  models.WithdrawalStatusApproved,
+	}
+	if req.ArkAddress != "" {
+		if len(req.ArkAddress) > maxArkAddressLength {
+			return nil, fmt.Errorf("%w: Ark address is too long", ErrInvalidWithdrawal)
+		}
+		withdrawal.ArkAddress = &req.ArkAddress
+	}
+	if w.cfg.needsApproval(req.Amount) {
+		withdrawal.Status = models.WithdrawalStatusPendingApproval
+	}
+
+	return withdrawal, nil
+}
+
+// pay pays out an approved withdrawal, refunding it if the payout fails.
+// The withdrawal is marked processing first, so it is never paid twice.
+func (w *Withdrawals) pay(ctx context.Context, withdrawal *models.Withdrawal) (*models.Withdrawal, error) {
+	withdrawal, err := w.repo.Transition(ctx, withdrawal.ID, models.WithdrawalStatusApproved, models.WithdrawalStatusProcessing, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	aspAvailable := false
+	if w.asp != nil && withdrawal.ArkAddress != nil {
+		aspAvailable, _ = w.asp.CheckASPStatus(ctx)
+	}
+	rail := w.cfg.rail(withdrawal, aspAvailable)
+
+	var txID string
+	if rail == models.WithdrawalRailArk {
+		txID, err = w.payArk(ctx, withdrawal)
+	} else {
+		txID, err = w.payOnChain(ctx, withdrawal)
+	}
+
+	log := logger.With().
+		Str("withdrawal_id", withdrawal.ID.String()).
+		Str("rail", string(rail)).
+		Int64("amount", withdrawal.Amount).
+		Logger()
+
+	if err != nil {
+		log.Error().Err(err).Msg("Withdrawal payout failed, refunding")
+		return w.refund(ctx, withdrawal.ID, models.WithdrawalStatusProcessing, models.WithdrawalStatusFailed, err.Error(), nil)
+	}
+
+	log.Info().Str("txid", txID).Msg("Withdrawal paid out")
+	return w.repo.MarkSent(ctx, withdrawal.ID, rail, txID)
+}
+
+// payOnChain sends a withdrawal from the hot wallet
+func (w *Withdrawals) payOnChain(ctx context.Context, withdrawal *models.Withdrawal) (string, error) {
+	address, err := btcutil.DecodeAddress(withdrawal.Address, w.params)
+	if err != nil {
+		return "", fmt.Errorf("invalid withdrawal address: %w", err)
+	}
+	return w.hotWallet.SendToAddress(ctx, address, withdrawal.Amount)
+}
+
+// payArk has the ASP build an out-of-round transaction paying the user's
+// Ark address from the platform's VTXOs, signs the platform's inputs with
+// the hot wallet and submits it
+func (w *Withdrawals) payArk(ctx context.Context, withdrawal *models.Withdrawal) (string, error) {
+	oor, err := w.asp.CreateOutOfRoundTransaction(ctx, "", []*arkv1.Output{{
+		Address: *withdrawal.ArkAddress,
+		Value:   withdrawal.Amount,
+	}})
+	if err != nil {
+		return "", fmt.Errorf("failed to create out-of-round transaction: %w", err)
+	}
+
+	signed, err := w.hotWallet.SignPSBT(ctx, oor.GetSerializedPsbt())
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := w.asp.SignOutOfRoundTransaction(ctx, oor.GetTxId(), signed); err != nil {
+		return "", fmt.Errorf("failed to submit out-of-round transaction: %w", err)
+	}
+
+	return oor.GetTxId(), nil
+}
+
+// refund moves a withdrawal to a final status and credits back its amount
+func (w *Withdrawals) refund(
+	ctx context.Context,
+	id uuid.UUID,
+	from, to models.WithdrawalStatus,
+	reason string,
+	reviewer *uuid.UUID,
+) (*models.Withdrawal, error) {
+	withdrawal, err := w.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.repo.Refund(ctx, id, from, to, reason, reviewer, func(tx *sqlx.Tx) error {
+		return w.ledger.RefundWithdrawal(ctx, tx, withdrawal.UserID, withdrawal.Amount, refundReference(id))
+	})
+}
+
+// debitReference is the ledger reference of a withdrawal's debit
+func debitReference(id uuid.UUID) string {
+	return "withdrawal:" + id.String()
+}
+
+// refundReference is the ledger reference of a withdrawal's refund
+func refundReference(id uuid.UUID) string {
+	return "withdrawal-refund:" + id.String()
+}
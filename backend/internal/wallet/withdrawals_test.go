@@ -0,0 +1,78 @@
+// internal/wallet/withdrawals_test.go
+package wallet
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func TestWithdrawalConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultWithdrawalConfig.Validate())
+
+	cfg := DefaultWithdrawalConfig
+	cfg.Enabled = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MinAmount = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultWithdrawalConfig
+	cfg.Enabled = true
+	cfg.ApprovalThreshold = -1
+	assert.Error(t, cfg.Validate())
+}
+
+func TestWithdrawalRail(t *testing.T) {
+	cfg := DefaultWithdrawalConfig
+	arkAddress := "tark1example"
+	small := &models.Withdrawal{Amount: cfg.ArkMaxAmount, ArkAddress: &arkAddress}
+	large := &models.Withdrawal{Amount: cfg.ArkMaxAmount + 1, ArkAddress: &arkAddress}
+
+	assert.Equal(t, models.WithdrawalRailArk, cfg.rail(small, true))
+	assert.Equal(t, models.WithdrawalRailOnChain, cfg.rail(small, false), "the ASP is down")
+	assert.Equal(t, models.WithdrawalRailOnChain, cfg.rail(large, true), "too large for Ark")
+	assert.Equal(t, models.WithdrawalRailOnChain, cfg.rail(&models.Withdrawal{Amount: 1000}, true), "no Ark address")
+}
+
+func TestNewWithdrawal(t *testing.T) {
+	cfg := DefaultWithdrawalConfig
+	w := NewWithdrawals(nil, nil, nil, &chaincfg.MainNetParams, cfg)
+	userID := uuid.New()
+	address := "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+
+	withdrawal, err := w.newWithdrawal(userID, WithdrawalRequest{Amount: 50_000, Address: address})
+	require.NoError(t, err)
+	assert.Equal(t, models.WithdrawalStatusApproved, withdrawal.Status)
+	assert.Equal(t, address, withdrawal.Address)
+	assert.Nil(t, withdrawal.ArkAddress)
+
+	// Large withdrawals wait for an operator
+	withdrawal, err = w.newWithdrawal(userID, WithdrawalRequest{Amount: cfg.ApprovalThreshold + 1, Address: address, ArkAddress: "ark1example"})
+	require.NoError(t, err)
+	assert.Equal(t, models.WithdrawalStatusPendingApproval, withdrawal.Status)
+	assert.Equal(t, "ark1example", *withdrawal.ArkAddress)
+
+	_, err = w.newWithdrawal(userID, WithdrawalRequest{Amount: cfg.MinAmount - 1, Address: address})
+	assert.ErrorIs(t, err, ErrInvalidWithdrawal)
+
+	// A testnet address on mainnet
+	_, err = w.newWithdrawal(userID, WithdrawalRequest{Amount: 50_000, Address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"})
+	assert.ErrorIs(t, err, ErrInvalidWithdrawal)
+}
+
+func TestReviewRequiresReviewer(t *testing.T) {
+	w := NewWithdrawals(nil, nil, nil, &chaincfg.MainNetParams, DefaultWithdrawalConfig)
+
+	_, err := w.Approve(context.Background(), uuid.New(), uuid.Nil)
+	assert.ErrorIs(t, err, ErrInvalidWithdrawal)
+
+	_, err = w.Reject(context.Background(), uuid.New(), uuid.Nil, "")
+	assert.ErrorIs(t, err, ErrInvalidWithdrawal)
+}
@@ -0,0 +1,216 @@
+// internal/watchtower/package.go
+package watchtower
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/deadlines"
+	"hashhedge/internal/models"
+)
+
+// FormatVersion is the version of the exit package format and the first byte
+// of every sealed package
+const FormatVersion = 1
+
+// UnsettledGraceBlocks is how long after a contract's end height the
+// participants have to settle cooperatively before a watchtower exits
+const UnsettledGraceBlocks = deadlines.ExitTimelockBlocks
+
+// ConditionUnsettledAtHeight asks the watchtower to broadcast the exit
+// transactions once the chain reaches the trigger height while the contract
+// is still unsettled
+const ConditionUnsettledAtHeight = "unsettled_at_height"
+
+// keyContext separates the package encryption key from any other use of the
+// shared secret
+const keyContext = "hashhedge-watchtower-v1"
+
+// ErrMalformedPackage is returned when a sealed package cannot be opened
+var ErrMalformedPackage = errors.New("malformed exit package")
+
+// ExitTransaction is a pre-signed emergency exit as prepared with the ASP
+type ExitTransaction struct {
+	TxID  string `json:"txid"`
+	TxHex string `json:"tx_hex"`
+}
+
+// Trigger is the condition under which a watchtower broadcasts a package.
+// ExitAvailableHeight is the earliest height the exit path's timelock allows.
+type Trigger struct {
+	Condition           string `json:"condition"`
+	Height              int64  `json:"height"`
+	ExitAvailableHeight int64  `json:"exit_available_height"`
+}
+
+// Package is what a watchtower needs to exit a contract on a participant's
+// behalf
+type Package struct {
+	Version      int               `json:"version"`
+	DelegationID uuid.UUID         `json:"delegation_id"`
+	ContractID   uuid.UUID         `json:"contract_id"`
+	PubKey       string            `json:"pub_key"`
+	Transactions []ExitTransaction `json:"transactions"`
+	Trigger      Trigger           `json:"trigger"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// NewPackage builds the exit package of a contract participant from the
+// contract's emergency exit transactions
+func NewPackage(delegationID uuid.UUID, c *models.Contract, pubKey string, txs []*models.ContractTransaction, now time.Time) (*Package, error) {
+	pkg := &Package{
+		Version:      FormatVersion,
+		DelegationID: delegationID,
+		ContractID:   c.ID,
+		PubKey:       pubKey,
+		Transactions: []ExitTransaction{},
+		Trigger:      triggerFor(c),
+		CreatedAt:    now,
+	}
+
+	for _, tx := range txs {
+		if tx.TxType != "emergency_exit" {
+			continue
+		}
+		pkg.Transactions = append(pkg.Transactions, ExitTransaction{
+			TxID:  tx.TransactionID,
+			TxHex: tx.TxHex,
+		})
+	}
+
+	if len(pkg.Transactions) == 0 {
+		return nil, ErrNoExitTransactions
+	}
+
+	return pkg, nil
+}
+
+// triggerFor returns the trigger of a contract's exit: one grace period past
+// its end height, and never before the exit path is available
+func triggerFor(c *models.Contract) Trigger {
+	exitHeight := c.StartBlockHeight + deadlines.ExitTimelockBlocks
+	height := c.EndBlockHeight + UnsettledGraceBlocks
+	if height < exitHeight {
+		height = exitHeight
+	}
+
+	return Trigger{
+		Condition:           ConditionUnsettledAtHeight,
+		Height:              height,
+		ExitAvailableHeight: exitHeight,
+	}
+}
+
+// Seal encrypts a package to a watchtower's hex X25519 public key. A sealed
+// package is the format version byte, the 32-byte ephemeral X25519 public
+// key, the 12-byte nonce and the AES-256-GCM ciphertext of the package JSON.
+// The AES key is the SHA-256 of the key context, the shared secret, the
+// ephemeral public key and the recipient public key.
+func Seal(pkg *Package, recipientKey string) ([]byte, error) {
+	raw, err := hex.DecodeString(recipientKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	recipient, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	plaintext, err := json.Marshal(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exit package: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	secret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+	aead, err := packageCipher(secret, ephemeral.PublicKey(), recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := []byte{FormatVersion}
+	sealed = append(sealed, ephemeral.PublicKey().Bytes()...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+// Open decrypts a sealed package with the watchtower's private key
+func Open(sealed []byte, key *ecdh.PrivateKey) (*Package, error) {
+	const header = 1 + 32
+
+	if len(sealed) < header || sealed[0] != FormatVersion {
+		return nil, ErrMalformedPackage
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[1:header])
+	if err != nil {
+		return nil, ErrMalformedPackage
+	}
+
+	secret, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, ErrMalformedPackage
+	}
+	aead, err := packageCipher(secret, ephemeral, key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < header+aead.NonceSize() {
+		return nil, ErrMalformedPackage
+	}
+
+	nonce := sealed[header : header+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, sealed[header+aead.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedPackage, err)
+	}
+
+	var pkg Package
+	if err := json.Unmarshal(plaintext, &pkg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedPackage, err)
+	}
+
+	return &pkg, nil
+}
+
+// packageCipher derives the AES-GCM cipher of a package from the X25519
+// shared secret, binding it to the ephemeral and recipient public keys
+func packageCipher(secret []byte, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte(keyContext))
+	h.Write(secret)
+	h.Write(ephemeral.Bytes())
+	h.Write(recipient.Bytes())
+
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Digest returns the hex SHA-256 of a sealed package
+func Digest(sealed []byte) string {
+	sum := sha256.Sum256(sealed)
+	return hex.EncodeToString(sum[:])
+}
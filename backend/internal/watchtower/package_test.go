@@ -0,0 +1,95 @@
+// internal/watchtower/package_test.go
+package watchtower
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func testContract() *models.Contract {
+	return &models.Contract{
+		ID:               uuid.New(),
+		Status:           models.ContractStatusActive,
+		BuyerPubKey:      "02buyer",
+		SellerPubKey:     "03seller",
+		StartBlockHeight: 850000,
+		EndBlockHeight:   852016,
+	}
+}
+
+func TestNewPackage(t *testing.T) {
+	c := testContract()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	txs := []*models.ContractTransaction{
+		{TxType: "setup", TransactionID: "setup", TxHex: "00"},
+		{TxType: "emergency_exit", TransactionID: "exit1", TxHex: "01"},
+		{TxType: "emergency_exit", TransactionID: "exit2", TxHex: "02"},
+	}
+
+	pkg, err := NewPackage(uuid.New(), c, c.BuyerPubKey, txs, now)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, pkg.Version)
+	assert.Equal(t, []ExitTransaction{{TxID: "exit1", TxHex: "01"}, {TxID: "exit2", TxHex: "02"}}, pkg.Transactions)
+	assert.Equal(t, Trigger{
+		Condition:           ConditionUnsettledAtHeight,
+		Height:              852016 + UnsettledGraceBlocks,
+		ExitAvailableHeight: 850144,
+	}, pkg.Trigger)
+
+	_, err = NewPackage(uuid.New(), c, c.BuyerPubKey, txs[:1], now)
+	assert.ErrorIs(t, err, ErrNoExitTransactions)
+}
+
+func TestTriggerNotBeforeExit(t *testing.T) {
+	c := testContract()
+	c.EndBlockHeight = c.StartBlockHeight
+
+	trigger := triggerFor(c)
+	assert.Equal(t, trigger.ExitAvailableHeight, trigger.Height)
+}
+
+func TestSealOpen(t *testing.T) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	c := testContract()
+	pkg, err := NewPackage(uuid.New(), c, c.SellerPubKey, []*models.ContractTransaction{
+		{TxType: "emergency_exit", TransactionID: "exit", TxHex: "cafe"},
+	}, time.Now().UTC().Truncate(time.Second))
+	require.NoError(t, err)
+
+	sealed, err := Seal(pkg, hex.EncodeToString(key.PublicKey().Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, Digest(sealed), 64)
+
+	opened, err := Open(sealed, key)
+	require.NoError(t, err)
+	assert.Equal(t, pkg, opened)
+
+	// Another key cannot open the package
+	other, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = Open(sealed, other)
+	assert.ErrorIs(t, err, ErrMalformedPackage)
+
+	// Tampering is detected
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = Open(tampered, key)
+	assert.ErrorIs(t, err, ErrMalformedPackage)
+
+	_, err = Open(sealed[:10], key)
+	assert.ErrorIs(t, err, ErrMalformedPackage)
+
+	_, err = Seal(pkg, "not a key")
+	assert.Error(t, err)
+}
@@ -0,0 +1,231 @@
+// internal/watchtower/service.go
+package watchtower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/models"
+)
+
+var (
+	// ErrNoExitTransactions is returned when a contract has no emergency exit
+	// transactions to delegate yet
+	ErrNoExitTransactions = errors.New("contract has no emergency exit transactions")
+
+	// ErrNotParticipant is returned when the delegated key is not both the
+	// user's and a party to the contract
+	ErrNotParticipant = errors.New("key is not the user's key on this contract")
+
+	// ErrContractNotActive is returned when delegating a contract that can no
+	// longer be exited
+	ErrContractNotActive = errors.New("only active contracts can be delegated")
+
+	// ErrDigestMismatch is returned when a watchtower acknowledges a package
+	// other than the one delegated to it
+	ErrDigestMismatch = errors.New("package digest does not match the delegation")
+)
+
+// DefaultPollLimit is the most delegations returned by one poll
+const DefaultPollLimit = 100
+
+// Service registers watchtowers and delegates the emergency exits of
+// contract participants to them
+type Service struct {
+	repo      *db.WatchtowerRepository
+	contracts *db.ContractRepository
+	users     *db.UserRepository
+}
+
+// NewService creates a watchtower delegation service
+func NewService(repo *db.WatchtowerRepository, contracts *db.ContractRepository, users *db.UserRepository) *Service {
+	return &Service{
+		repo:      repo,
+		contracts: contracts,
+		users:     users,
+	}
+}
+
+// RegisterWatchtower stores a watchtower and returns its access token, which
+// is shown only once
+func (s *Service) RegisterWatchtower(ctx context.Context, tower *models.Watchtower) (string, error) {
+	if err := tower.Validate(); err != nil {
+		return "", err
+	}
+
+	token, err := tower.GenerateToken()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate watchtower token: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, tower); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ListWatchtowers retrieves every registered watchtower
+func (s *Service) ListWatchtowers(ctx context.Context) ([]*models.Watchtower, error) {
+	return s.repo.List(ctx)
+}
+
+// RevokeWatchtower disables a watchtower along with its delegations
+func (s *Service) RevokeWatchtower(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Authenticate identifies the watchtower holding an access token
+func (s *Service) Authenticate(ctx context.Context, token string) (*models.Watchtower, error) {
+	return s.repo.GetActiveByHash(ctx, models.HashWatchtowerToken(token))
+}
+
+// Delegate seals the exit package of a user's key on an active contract to
+// a watchtower
+func (s *Service) Delegate(ctx context.Context, userID, contractID, watchtowerID uuid.UUID, pubKey string) (*models.WatchtowerDelegation, error) {
+	c, err := s.contracts.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if c.Status != models.ContractStatusActive {
+		return nil, ErrContractNotActive
+	}
+	if err := s.checkParticipant(ctx, userID, c, pubKey); err != nil {
+		return nil, err
+	}
+
+	tower, err := s.repo.GetActive(ctx, watchtowerID)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := s.contracts.GetTransactionsByContractID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := &models.WatchtowerDelegation{
+		ID:           uuid.New(),
+		ContractID:   contractID,
+		UserID:       userID,
+		WatchtowerID: tower.ID,
+		PubKey:       pubKey,
+	}
+
+	pkg, err := NewPackage(delegation.ID, c, pubKey, txs, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	delegation.Package, err = Seal(pkg, tower.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	delegation.PackageDigest = Digest(delegation.Package)
+
+	if err := s.repo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, err
+	}
+
+	return delegation, nil
+}
+
+// checkParticipant checks that pubKey is one of the user's keys and a party
+// to the contract
+func (s *Service) checkParticipant(ctx context.Context, userID uuid.UUID, c *models.Contract, pubKey string) error {
+	if pubKey != c.BuyerPubKey && pubKey != c.SellerPubKey {
+		return ErrNotParticipant
+	}
+
+	keys, err := s.users.GetKeysByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if key.PubKey == pubKey {
+			return nil
+		}
+	}
+	return ErrNotParticipant
+}
+
+// ListDelegations retrieves a user's delegations of a contract
+func (s *Service) ListDelegations(ctx context.Context, userID, contractID uuid.UUID) ([]*models.WatchtowerDelegation, error) {
+	return s.repo.ListDelegationsByContract(ctx, contractID, userID)
+}
+
+// GetDelegation retrieves a delegation of a user, including its sealed package
+func (s *Service) GetDelegation(ctx context.Context, userID, id uuid.UUID) (*models.WatchtowerDelegation, error) {
+	delegation, err := s.repo.GetDelegation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delegation.UserID != userID {
+		return nil, fmt.Errorf("watchtower delegation %s: %w", id, db.ErrNotFound)
+	}
+
+	return delegation, nil
+}
+
+// RevokeDelegation tells the watchtower to stop acting on a user's delegation
+func (s *Service) RevokeDelegation(ctx context.Context, userID, id uuid.UUID) error {
+	return s.repo.RevokeDelegation(ctx, userID, id)
+}
+
+// PolledDelegation is a delegation as a watchtower sees it: with its sealed
+// package and the contract's current status, so the watchtower can stand
+// down once the contract settles
+type PolledDelegation struct {
+	*models.WatchtowerDelegation
+	Package        []byte                `json:"package"`
+	ContractStatus models.ContractStatus `json:"contract_status"`
+}
+
+// Poll retrieves the delegations of a watchtower in a status
+func (s *Service) Poll(ctx context.Context, watchtowerID uuid.UUID, status models.DelegationStatus, limit int) ([]PolledDelegation, error) {
+	if limit <= 0 || limit > DefaultPollLimit {
+		limit = DefaultPollLimit
+	}
+
+	delegations, err := s.repo.ListDelegationsByWatchtower(ctx, watchtowerID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	polled := make([]PolledDelegation, 0, len(delegations))
+	for _, delegation := range delegations {
+		c, err := s.contracts.GetByID(ctx, delegation.ContractID)
+		if err != nil {
+			return nil, err
+		}
+		polled = append(polled, PolledDelegation{
+			WatchtowerDelegation: delegation,
+			Package:              delegation.Package,
+			ContractStatus:       c.Status,
+		})
+	}
+
+	return polled, nil
+}
+
+// Acknowledge records that a watchtower has stored the package with digest
+func (s *Service) Acknowledge(ctx context.Context, watchtowerID, id uuid.UUID, digest string) error {
+	delegation, err := s.repo.GetDelegation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if delegation.WatchtowerID != watchtowerID {
+		return fmt.Errorf("watchtower delegation %s: %w", id, db.ErrNotFound)
+	}
+	if delegation.PackageDigest != digest {
+		return ErrDigestMismatch
+	}
+
+	return s.repo.AcknowledgeDelegation(ctx, watchtowerID, id)
+}
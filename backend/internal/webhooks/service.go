@@ -0,0 +1,272 @@
+// internal/webhooks/service.go
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hashhedge/internal/db"
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+var logger = logging.Component(logging.Webhooks)
+
+// ErrLimitReached is returned when a user already has the maximum number of
+// active webhooks
+var ErrLimitReached = errors.New("webhook limit reached")
+
+// maxErrorBody caps how much of a failed response is kept as the error
+const maxErrorBody = 512
+
+// Service manages webhook registrations, queues trade, fill and settlement
+// events for the webhooks of the affected users, and delivers them signed
+// with each webhook's secret
+type Service struct {
+	repo   *db.WebhookRepository
+	client *http.Client
+	cfg    Config
+}
+
+// NewService creates a new webhook service
+func NewService(repo *db.WebhookRepository, cfg Config) *Service {
+	return &Service{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. with one connecting through
+// a proxy
+func (s *Service) WithHTTPClient(client *http.Client) *Service {
+	s.client = client
+	return s
+}
+
+// Register stores a new webhook for a user with a freshly generated secret,
+// which is returned only here
+func (s *Service) Register(ctx context.Context, webhook *models.Webhook) (string, error) {
+	if err := webhook.Validate(); err != nil {
+		return "", err
+	}
+
+	count, err := s.repo.CountByUser(ctx, webhook.UserID)
+	if err != nil {
+		return "", err
+	}
+	if count >= s.cfg.MaxPerUser {
+		return "", ErrLimitReached
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// List retrieves the active webhooks of a user
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Remove disables a webhook of a user
+func (s *Service) Remove(ctx context.Context, userID, webhookID uuid.UUID) error {
+	return s.repo.Disable(ctx, userID, webhookID)
+}
+
+// ListDeadLetters retrieves the failed deliveries of a user's webhooks
+func (s *Service) ListDeadLetters(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.WebhookDeadLetter, error) {
+	return s.repo.ListDeadLetters(ctx, userID, limit, offset)
+}
+
+// Redeliver queues a failed delivery of a user's webhook to be sent again
+func (s *Service) Redeliver(ctx context.Context, userID, deliveryID uuid.UUID) error {
+	return s.repo.Redeliver(ctx, userID, deliveryID)
+}
+
+// tradePayload is the body of trade.executed and order.filled events, seen
+// from the side of the receiving user's order
+type tradePayload struct {
+	Trade    models.Trade    `json:"trade"`
+	Order    models.Order    `json:"order"`
+	Contract models.Contract `json:"contract"`
+}
+
+// settlementPayload is the body of contract.settled events
+type settlementPayload struct {
+	Contract  models.Contract `json:"contract"`
+	BuyerWins bool            `json:"buyer_wins"`
+}
+
+// OnFill implements orderbook.FillObserver by queueing the trade for both
+// parties, and the fill for each order the trade completed
+func (s *Service) OnFill(ctx context.Context, fill orderbook.Fill) {
+	for _, order := range []models.Order{fill.BuyOrder, fill.SellOrder} {
+		payload, err := json.Marshal(tradePayload{
+			Trade:    fill.Trade,
+			Order:    order,
+			Contract: fill.Contract,
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("trade_id", fill.Trade.ID.String()).Msg("Failed to encode webhook payload")
+			return
+		}
+
+		s.enqueue(ctx, order.UserID, models.WebhookEventTradeExecuted, payload)
+		if order.Status == models.OrderStatusFilled {
+			s.enqueue(ctx, order.UserID, models.WebhookEventOrderFilled, payload)
+		}
+	}
+}
+
+// OnContractSettled implements contract.SettlementObserver by queueing the
+// settlement for both parties
+func (s *Service) OnContractSettled(ctx context.Context, contract *models.Contract, buyerWins bool) {
+	payload, err := json.Marshal(settlementPayload{Contract: *contract, BuyerWins: buyerWins})
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to encode webhook payload")
+		return
+	}
+
+	err = s.repo.EnqueueForContract(ctx, contract.ID, models.WebhookEventContractSettled, payload)
+	if err != nil {
+		logger.Error().Err(err).Str("contract_id", contract.ID.String()).Msg("Failed to queue settlement webhooks")
+	}
+}
+
+func (s *Service) enqueue(ctx context.Context, userID uuid.UUID, eventType models.WebhookEventType, payload []byte) {
+	if err := s.repo.Enqueue(ctx, userID, eventType, payload); err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", userID.String()).
+			Str("event", string(eventType)).
+			Msg("Failed to queue webhooks")
+	}
+}
+
+// Start delivers queued events until the context is cancelled
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.deliverDue(ctx); err != nil {
+					logger.Error().Err(err).Msg("Webhook delivery run failed")
+				}
+			}
+		}
+	}()
+}
+
+// deliverDue sends a batch of due deliveries, rescheduling or dead-lettering
+// those that fail
+func (s *Service) deliverDue(ctx context.Context) error {
+	// Claimed deliveries are leased for longer than a request can take so
+	// that no other instance picks them up mid-flight
+	deliveries, err := s.repo.ClaimDue(ctx, s.cfg.BatchSize, 2*s.cfg.Timeout)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		sendErr := s.send(ctx, delivery, time.Now().UTC())
+		if sendErr == nil {
+			metrics.WebhookDeliveries.WithLabelValues("delivered").Inc()
+			if err := s.repo.Delivered(ctx, delivery.ID); err != nil {
+				logger.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to remove webhook delivery")
+			}
+			continue
+		}
+
+		log := logger.Warn().
+			Err(sendErr).
+			Str("delivery_id", delivery.ID.String()).
+			Str("webhook_id", delivery.WebhookID.String()).
+			Int("attempts", delivery.Attempts)
+
+		if delivery.Attempts >= s.cfg.MaxAttempts {
+			log.Msg("Webhook delivery failed its last attempt")
+			metrics.WebhookDeliveries.WithLabelValues("dead_lettered").Inc()
+			err = s.repo.DeadLetter(ctx, delivery.ID, sendErr.Error())
+		} else {
+			log.Msg("Webhook delivery failed")
+			metrics.WebhookDeliveries.WithLabelValues("retried").Inc()
+			err = s.repo.Retry(ctx, delivery.ID, sendErr.Error(), time.Now().UTC().Add(backoff(s.cfg, delivery.Attempts)))
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("Failed to record webhook delivery failure")
+		}
+	}
+
+	return nil
+}
+
+// envelope is the body posted to webhooks
+type envelope struct {
+	ID        uuid.UUID               `json:"id"`
+	Type      models.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      json.RawMessage         `json:"data"`
+}
+
+// send posts a delivery to its webhook, signed at now
+func (s *Service) send(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) error {
+	body, err := json.Marshal(envelope{
+		ID:        delivery.ID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, now, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, snippet)
+	}
+
+	return nil
+}
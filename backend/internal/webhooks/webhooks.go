@@ -0,0 +1,104 @@
+// internal/webhooks/webhooks.go
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Headers set on every delivery
+const (
+	EventHeader     = "X-HashHedge-Event"
+	DeliveryHeader  = "X-HashHedge-Delivery"
+	TimestampHeader = "X-HashHedge-Timestamp"
+	SignatureHeader = "X-HashHedge-Signature"
+)
+
+// Config controls how queued webhook deliveries are sent and retried
+type Config struct {
+	// Interval is how often the delivery queue is polled
+	Interval time.Duration `yaml:"interval"`
+	// Timeout bounds each delivery request
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxAttempts is how many times a delivery is tried before it is
+	// moved to the dead letters
+	MaxAttempts int `yaml:"max_attempts"`
+	// Backoff is the wait after the first failed attempt, doubling with
+	// each further one up to MaxBackoff
+	Backoff    time.Duration `yaml:"backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+	// BatchSize is how many due deliveries are sent per poll
+	BatchSize int `yaml:"batch_size"`
+	// MaxPerUser caps the active webhooks of each user
+	MaxPerUser int `yaml:"max_per_user"`
+}
+
+// DefaultConfig retries a failed delivery eight times over about four hours
+var DefaultConfig = Config{
+	Interval:    5 * time.Second,
+	Timeout:     10 * time.Second,
+	MaxAttempts: 8,
+	Backoff:     30 * time.Second,
+	MaxBackoff:  2 * time.Hour,
+	BatchSize:   50,
+	MaxPerUser:  10,
+}
+
+// Validate checks that the webhook settings are usable
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("webhook interval must be positive")
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("webhook timeout must be positive")
+	}
+	if c.MaxAttempts <= 0 {
+		return fmt.Errorf("webhook max attempts must be positive")
+	}
+	if c.Backoff <= 0 {
+		return fmt.Errorf("webhook backoff must be positive")
+	}
+	if c.MaxBackoff < c.Backoff {
+		return fmt.Errorf("webhook max backoff must be at least the backoff")
+	}
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("webhook batch size must be positive")
+	}
+	if c.MaxPerUser <= 0 {
+		return fmt.Errorf("webhook max per user must be positive")
+	}
+	return nil
+}
+
+// Sign computes the signature of a delivery body sent at timestamp. The
+// timestamp is signed with the body so a captured delivery cannot be
+// replayed later under a fresh timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at
+// timestamp, as receivers check it
+func Verify(secret string, timestamp time.Time, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// backoff is the wait before retrying a delivery that has failed attempts times
+func backoff(cfg Config, attempts int) time.Duration {
+	wait := cfg.Backoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= cfg.MaxBackoff {
+			return cfg.MaxBackoff
+		}
+	}
+	return wait
+}
@@ -0,0 +1,108 @@
+// internal/webhooks/webhooks_test.go
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"hashhedge/internal/models"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"trade.executed"}`)
+
+	signature := Sign("secret", at, body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("secret", at, body, signature))
+
+	assert.False(t, Verify("other", at, body, signature), "wrong secret")
+	assert.False(t, Verify("secret", at.Add(time.Second), body, signature), "replayed under a new timestamp")
+	assert.False(t, Verify("secret", at, []byte(`{"type":"order.filled"}`), signature), "tampered body")
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := Config{Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+
+	assert.Equal(t, 30*time.Second, backoff(cfg, 1))
+	assert.Equal(t, time.Minute, backoff(cfg, 2))
+	assert.Equal(t, 4*time.Minute, backoff(cfg, 4))
+	assert.Equal(t, 5*time.Minute, backoff(cfg, 5))
+	assert.Equal(t, 5*time.Minute, backoff(cfg, 50))
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultConfig.Validate())
+
+	cfg := DefaultConfig
+	cfg.MaxBackoff = cfg.Backoff / 2
+	assert.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig
+	cfg.MaxAttempts = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	delivery := &models.WebhookDelivery{
+		ID:        uuid.New(),
+		EventType: models.WebhookEventContractSettled,
+		Payload:   json.RawMessage(`{"buyer_wins":true}`),
+		CreatedAt: time.Now().UTC(),
+		Secret:    "secret",
+	}
+
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	delivery.URL = server.URL
+
+	s := NewService(nil, DefaultConfig)
+	now := time.Unix(1700000000, 0)
+	require.NoError(t, s.send(context.Background(), delivery, now))
+
+	assert.Equal(t, string(models.WebhookEventContractSettled), got.Header.Get(EventHeader))
+	assert.Equal(t, delivery.ID.String(), got.Header.Get(DeliveryHeader))
+	assert.Equal(t, strconv.FormatInt(now.Unix(), 10), got.Header.Get(TimestampHeader))
+	assert.True(t, Verify("secret", now, body, got.Header.Get(SignatureHeader)))
+
+	var env envelope
+	require.NoError(t, json.Unmarshal(body, &env))
+	assert.Equal(t, delivery.ID, env.ID)
+	assert.JSONEq(t, `{"buyer_wins":true}`, string(env.Data))
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	delivery := &models.WebhookDelivery{
+		ID:        uuid.New(),
+		EventType: models.WebhookEventTradeExecuted,
+		Payload:   json.RawMessage(`{}`),
+		URL:       server.URL,
+		Secret:    "secret",
+	}
+
+	err := NewService(nil, DefaultConfig).send(context.Background(), delivery, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	assert.Contains(t, err.Error(), "unavailable")
+}
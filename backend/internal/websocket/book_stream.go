@@ -0,0 +1,258 @@
+// internal/websocket/book_stream.go
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+// BookChannelPrefix starts the name of every order book channel
+const BookChannelPrefix = "orderbook"
+
+// Level change actions carried in an order book delta
+const (
+	LevelAdd    = "add"
+	LevelUpdate = "update"
+	LevelRemove = "remove"
+)
+
+// BookChannel names the channel carrying the order book of a market, such as
+// "orderbook:call:350:850000:852016". Subscribing to "orderbook:call:350:**"
+// follows every call market at that strike.
+func BookChannel(key orderbook.OrderKey) string {
+	return strings.Join([]string{
+		BookChannelPrefix,
+		strings.ToLower(string(key.ContractType)),
+		strconv.FormatFloat(key.StrikeHashRate, 'f', -1, 64),
+		strconv.FormatInt(key.StartBlockHeight, 10),
+		strconv.FormatInt(key.EndBlockHeight, 10),
+	}, channelSeparator)
+}
+
+// parseBookChannel returns the market named by an order book channel
+func parseBookChannel(channel string) (orderbook.OrderKey, error) {
+	segments := strings.Split(channel, channelSeparator)
+	if len(segments) != 5 || segments[0] != BookChannelPrefix {
+		return orderbook.OrderKey{}, fmt.Errorf("not an order book channel: %s", channel)
+	}
+
+	contractType := models.ContractType(strings.ToUpper(segments[1]))
+	if contractType != models.ContractTypeCall && contractType != models.ContractTypePut {
+		return orderbook.OrderKey{}, fmt.Errorf("invalid contract type: %s", segments[1])
+	}
+	strike, err := strconv.ParseFloat(segments[2], 64)
+	if err != nil {
+		return orderbook.OrderKey{}, fmt.Errorf("invalid strike hash rate: %w", err)
+	}
+	start, err := strconv.ParseInt(segments[3], 10, 64)
+	if err != nil {
+		return orderbook.OrderKey{}, fmt.Errorf("invalid start block height: %w", err)
+	}
+	end, err := strconv.ParseInt(segments[4], 10, 64)
+	if err != nil {
+		return orderbook.OrderKey{}, fmt.Errorf("invalid end block height: %w", err)
+	}
+
+	return orderbook.OrderKey{
+		ContractType:     contractType,
+		StrikeHashRate:   strike,
+		StartBlockHeight: start,
+		EndBlockHeight:   end,
+	}, nil
+}
+
+// LevelChange is a change to the resting quantity at one price of one side.
+// Quantity and Orders are the new totals of the level, not increments.
+type LevelChange struct {
+	Side     models.OrderSide `json:"side"`
+	Action   string           `json:"action"`
+	Price    int64            `json:"price"`
+	Quantity int              `json:"quantity"`
+	Orders   int              `json:"orders"`
+}
+
+// BookMessage is the payload of an order book snapshot or delta. Seq counts
+// the deltas of a market: a client applies only deltas with a Seq above its
+// snapshot's, and resubscribes for a fresh snapshot when it sees a gap.
+type BookMessage struct {
+	ContractType     models.ContractType    `json:"contract_type"`
+	StrikeHashRate   float64                `json:"strike_hash_rate"`
+	StartBlockHeight int64                  `json:"start_block_height"`
+	EndBlockHeight   int64                  `json:"end_block_height"`
+	Seq              int64                  `json:"seq"`
+	Bids             []orderbook.PriceLevel `json:"bids,omitempty"`
+	Asks             []orderbook.PriceLevel `json:"asks,omitempty"`
+	Changes          []LevelChange          `json:"changes,omitempty"`
+}
+
+// diffLevels returns the changes that turn the levels before into the
+// levels after for one side of a book
+func diffLevels(side models.OrderSide, before, after []orderbook.PriceLevel) []LevelChange {
+	previous := make(map[int64]orderbook.PriceLevel, len(before))
+	for _, level := range before {
+		previous[level.Price] = level
+	}
+
+	var changes []LevelChange
+	for _, level := range after {
+		old, ok := previous[level.Price]
+		delete(previous, level.Price)
+
+		action := LevelAdd
+		if ok {
+			if old.Quantity == level.Quantity && old.Orders == level.Orders {
+				continue
+			}
+			action = LevelUpdate
+		}
+		changes = append(changes, LevelChange{
+			Side:     side,
+			Action:   action,
+			Price:    level.Price,
+			Quantity: level.Quantity,
+			Orders:   level.Orders,
+		})
+	}
+
+	// Removals follow the order of the levels they were in
+	for _, level := range before {
+		if _, ok := previous[level.Price]; !ok {
+			continue
+		}
+		changes = append(changes, LevelChange{
+			Side:   side,
+			Action: LevelRemove,
+			Price:  level.Price,
+		})
+	}
+
+	return changes
+}
+
+// BookSource is the order book streamed to subscribers
+type BookSource interface {
+	Depth(key orderbook.OrderKey, levels int) orderbook.Depth
+	Tickers() []orderbook.MarketSnapshot
+}
+
+// bookState is the last order book published for a market
+type bookState struct {
+	seq   int64
+	depth orderbook.Depth
+}
+
+// BookStream publishes the order book of every market to its channel: a
+// snapshot when a client subscribes, then a delta of the price levels that
+// changed whenever an order is placed, cancelled or matched
+type BookStream struct {
+	book   BookSource
+	server *Server
+
+	mu      sync.Mutex
+	markets map[orderbook.OrderKey]*bookState
+}
+
+// NewBookStream creates a stream of the order book to the server's clients
+func NewBookStream(book BookSource, server *Server) *BookStream {
+	return &BookStream{
+		book:    book,
+		server:  server,
+		markets: make(map[orderbook.OrderKey]*bookState),
+	}
+}
+
+// OnMarketUpdate implements orderbook.MarketObserver. Updates are diffed
+// against the live book rather than the snapshot, so deltas stay consistent
+// when notifications arrive out of order.
+func (b *BookStream) OnMarketUpdate(ctx context.Context, snapshot orderbook.MarketSnapshot) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := snapshot.Key
+	state, ok := b.markets[key]
+	if !ok {
+		state = &bookState{}
+		b.markets[key] = state
+	}
+
+	depth := b.book.Depth(key, 0)
+	changes := append(
+		diffLevels(models.OrderSideBuy, state.depth.Bids, depth.Bids),
+		diffLevels(models.OrderSideSell, state.depth.Asks, depth.Asks)...,
+	)
+	if len(changes) == 0 {
+		return
+	}
+
+	state.seq++
+	state.depth = depth
+
+	payload := bookMessage(key, state.seq)
+	payload.Changes = changes
+	b.server.PublishToChannel(BookChannel(key), map[string]interface{}{
+		"type":    "orderbook_delta",
+		"channel": BookChannel(key),
+		"payload": payload,
+	})
+}
+
+// SendSnapshots implements Snapshotter. An exact channel gets the market it
+// names, even if empty; a wildcard gets every market it matches.
+func (b *BookStream) SendSnapshots(channel string, send func(message interface{})) {
+	if !strings.HasPrefix(channel, BookChannelPrefix+channelSeparator) && !strings.HasPrefix(channel, wildcardSegment) {
+		return
+	}
+
+	var keys []orderbook.OrderKey
+	if isWildcard(channel) {
+		for _, ticker := range b.book.Tickers() {
+			if matchChannel(channel, BookChannel(ticker.Key)) {
+				keys = append(keys, ticker.Key)
+			}
+		}
+	} else {
+		key, err := parseBookChannel(channel)
+		if err != nil {
+			return
+		}
+		keys = append(keys, key)
+	}
+
+	// Holding the lock orders the snapshot before any delta that follows it
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, key := range keys {
+		var seq int64
+		if state, ok := b.markets[key]; ok {
+			seq = state.seq
+		}
+
+		depth := b.book.Depth(key, 0)
+		payload := bookMessage(key, seq)
+		payload.Bids = depth.Bids
+		payload.Asks = depth.Asks
+		send(map[string]interface{}{
+			"type":    "orderbook_snapshot",
+			"channel": BookChannel(key),
+			"payload": payload,
+		})
+	}
+}
+
+// bookMessage starts the payload of a message about a market
+func bookMessage(key orderbook.OrderKey, seq int64) BookMessage {
+	return BookMessage{
+		ContractType:     key.ContractType,
+		StrikeHashRate:   key.StrikeHashRate,
+		StartBlockHeight: key.StartBlockHeight,
+		EndBlockHeight:   key.EndBlockHeight,
+		Seq:              seq,
+	}
+}
@@ -0,0 +1,60 @@
+// internal/websocket/book_stream_test.go
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"hashhedge/internal/models"
+	"hashhedge/internal/orderbook"
+)
+
+func TestBookChannel(t *testing.T) {
+	key := orderbook.OrderKey{
+		ContractType:     models.ContractTypeCall,
+		StrikeHashRate:   350,
+		StartBlockHeight: 850000,
+		EndBlockHeight:   852016,
+	}
+
+	channel := BookChannel(key)
+	assert.Equal(t, "orderbook:call:350:850000:852016", channel)
+	assert.True(t, matchChannel("orderbook:call:350:**", channel))
+
+	parsed, err := parseBookChannel(channel)
+	require.NoError(t, err)
+	assert.Equal(t, key, parsed)
+
+	for _, invalid := range []string{
+		"orderbook:call:350",
+		"liquidity:call:350:850000:852016",
+		"orderbook:straddle:350:850000:852016",
+		"orderbook:put:high:850000:852016",
+	} {
+		_, err := parseBookChannel(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDiffLevels(t *testing.T) {
+	before := []orderbook.PriceLevel{
+		{Price: 120, Quantity: 5, Orders: 1},
+		{Price: 110, Quantity: 3, Orders: 2},
+		{Price: 100, Quantity: 7, Orders: 1},
+	}
+	after := []orderbook.PriceLevel{
+		{Price: 125, Quantity: 2, Orders: 1},
+		{Price: 120, Quantity: 5, Orders: 1},
+		{Price: 110, Quantity: 1, Orders: 1},
+	}
+
+	changes := diffLevels(models.OrderSideBuy, before, after)
+	assert.Equal(t, []LevelChange{
+		{Side: models.OrderSideBuy, Action: LevelAdd, Price: 125, Quantity: 2, Orders: 1},
+		{Side: models.OrderSideBuy, Action: LevelUpdate, Price: 110, Quantity: 1, Orders: 1},
+		{Side: models.OrderSideBuy, Action: LevelRemove, Price: 100},
+	}, changes)
+
+	assert.Empty(t, diffLevels(models.OrderSideSell, after, after))
+}
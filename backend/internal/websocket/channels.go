@@ -0,0 +1,125 @@
+// internal/websocket/channels.go
+package websocket
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Channel names are colon separated segments, such as "contract:<id>:funding".
+// A subscription may use "*" for any single segment and a final "**" for one
+// or more trailing segments.
+const (
+	channelSeparator = ":"
+	wildcardSegment  = "*"
+	wildcardRest     = "**"
+)
+
+// TradesChannel carries every executed trade
+const TradesChannel = "trades"
+
+// HashRateChannel carries the hash rate observation of each new block
+const HashRateChannel = "hashrate"
+
+// ASPStatusChannel carries changes in the availability of the ASP
+const ASPStatusChannel = "asp"
+
+// ContractChannel carries the lifecycle events of one contract: status
+// transitions, transaction confirmations and the settlement outcome
+func ContractChannel(contractID uuid.UUID) string {
+	return "contract:" + contractID.String()
+}
+
+// privateChannels are the channels whose messages concern a single user.
+// Wildcard subscriptions never match them; they must be subscribed to by
+// name so the subscription can be authorized.
+var privateChannels = []string{
+	"alerts:*",
+	"contract:*:funding",
+	"margin:*",
+	"notifications:*",
+}
+
+// Snapshotter sends the current state of a channel to a client that has just
+// subscribed to it, so later messages can be applied as changes to it
+type Snapshotter interface {
+	SendSnapshots(channel string, send func(message interface{}))
+}
+
+// Snapshotters asks each of several snapshotters in turn
+type Snapshotters []Snapshotter
+
+// SendSnapshots implements Snapshotter
+func (s Snapshotters) SendSnapshots(channel string, send func(message interface{})) {
+	for _, snapshotter := range s {
+		snapshotter.SendSnapshots(channel, send)
+	}
+}
+
+// ChannelAuthorizer decides whether a user may subscribe to a private channel
+type ChannelAuthorizer interface {
+	CanSubscribe(ctx context.Context, userID uuid.UUID, channel string) bool
+}
+
+// matchChannel reports whether a subscription pattern matches a channel
+func matchChannel(pattern, channel string) bool {
+	if pattern == channel {
+		return true
+	}
+
+	patternParts := strings.Split(pattern, channelSeparator)
+	channelParts := strings.Split(channel, channelSeparator)
+
+	for i, part := range patternParts {
+		if part == wildcardRest && i == len(patternParts)-1 {
+			return len(channelParts) > i
+		}
+		if i >= len(channelParts) {
+			return false
+		}
+		if part != wildcardSegment && part != channelParts[i] {
+			return false
+		}
+	}
+
+	return len(patternParts) == len(channelParts)
+}
+
+// isWildcard reports whether a subscription pattern contains a wildcard
+func isWildcard(pattern string) bool {
+	for _, part := range strings.Split(pattern, channelSeparator) {
+		if part == wildcardSegment || part == wildcardRest {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrivateChannel reports whether a channel's messages concern a single user
+func isPrivateChannel(channel string) bool {
+	for _, pattern := range privateChannels {
+		if matchChannel(pattern, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribed reports whether any of a client's subscriptions match a channel.
+// Private channels only match a subscription by their exact name.
+func subscribed(subscriptions map[string]bool, channel string) bool {
+	if subscriptions[channel] {
+		return true
+	}
+	if isPrivateChannel(channel) {
+		return false
+	}
+	for pattern := range subscriptions {
+		if matchChannel(pattern, channel) {
+			return true
+		}
+	}
+	return false
+}
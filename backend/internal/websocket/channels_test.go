@@ -0,0 +1,64 @@
+// internal/websocket/channels_test.go
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchChannel(t *testing.T) {
+	tests := []struct {
+		pattern string
+		channel string
+		want    bool
+	}{
+		{"trades", "trades", true},
+		{"trades", "contracts", false},
+		{"orderbook:*", "orderbook:CALL", true},
+		{"orderbook:*", "orderbook:CALL:500", false},
+		{"orderbook:**", "orderbook:CALL:500", true},
+		{"orderbook:**", "orderbook", false},
+		{"*:updates", "contract:updates", true},
+		{"contract:*:status", "contract:abc:status", true},
+		{"contract:*:status", "contract:abc:funding", false},
+		{"*", "trades", true},
+		{"*", "contract:abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.channel, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchChannel(tt.pattern, tt.channel))
+		})
+	}
+}
+
+func TestSubscribed(t *testing.T) {
+	subscriptions := map[string]bool{
+		"trades":      true,
+		"contract:**": true,
+		"alerts:*":    true,
+	}
+
+	assert.True(t, subscribed(subscriptions, "trades"))
+	assert.True(t, subscribed(subscriptions, "contract:abc:status"))
+	assert.False(t, subscribed(subscriptions, "contracts"))
+
+	// Wildcards never match private channels
+	assert.False(t, subscribed(subscriptions, "contract:abc:funding"))
+	assert.False(t, subscribed(subscriptions, "alerts:abc"))
+
+	subscriptions["contract:abc:funding"] = true
+	assert.True(t, subscribed(subscriptions, "contract:abc:funding"))
+}
+
+func TestIsPrivateChannel(t *testing.T) {
+	assert.True(t, isPrivateChannel("alerts:abc"))
+	assert.True(t, isPrivateChannel("contract:abc:funding"))
+	assert.True(t, isPrivateChannel("margin:abc"))
+	assert.True(t, isPrivateChannel("notifications:abc"))
+	assert.False(t, isPrivateChannel("contract:abc"))
+	assert.False(t, isPrivateChannel("trades"))
+	assert.True(t, isWildcard("alerts:*"))
+	assert.False(t, isWildcard("alerts:abc"))
+}
@@ -4,15 +4,32 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
-	"hashhedge/internal/models"
-	"hashhedge/internal/orderbook"
+	"hashhedge/internal/auth"
+	"hashhedge/internal/events"
+	"hashhedge/internal/usage"
+)
+
+const (
+	// writeWait is the time allowed to write a message to a client
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong from a client
+	pongWait = 60 * time.Second
+	// pingPeriod sends pings often enough for a healthy client to answer
+	// within pongWait
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize is the largest message accepted from a client
+	maxMessageSize = 4096
 )
 
 // Client represents a WebSocket client
@@ -20,15 +37,84 @@ type Client struct {
 	conn     *websocket.Conn
 	send     chan interface{}
 	channels map[string]bool
+	// reqCtx carries the values of the upgrade request, such as the authenticated user
+	reqCtx   context.Context
+	// apiKeyID is the API key the connection authenticated with, if any
+	apiKeyID *uuid.UUID
+	// done is closed when the connection is closed for any reason
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// close closes the connection, which ends both the read and write loops
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// userID identifies the user of a client from the access token or API key
+// of its upgrade request
+func (c *Client) userID() (uuid.UUID, bool) {
+	return auth.UserIDFromContext(c.reqCtx)
+}
+
+// channelMessage is a message for the subscribers of a channel
+type channelMessage struct {
+	channel string
+	message interface{}
+}
+
+// SessionObserver is notified when the last connection authenticated with an
+// API key closes
+type SessionObserver interface {
+	OnSessionClosed(ctx context.Context, apiKeyID uuid.UUID)
+}
+
+// BandwidthMeter records the bytes sent to each client
+type BandwidthMeter interface {
+	RecordWebsocketBytes(ctx context.Context, bytes int)
+}
+
+// Stats are the connection counters of the server
+type Stats struct {
+	// Connections is the number of currently connected clients
+	Connections int `json:"connections"`
+	// Subscriptions is the number of channel subscriptions across all clients
+	Subscriptions int `json:"subscriptions"`
+	// TotalConnections is the number of connections accepted since startup
+	TotalConnections int64 `json:"total_connections"`
+	// StaleReaped is the number of clients disconnected for missing a pong
+	// or failing to accept a write before its deadline
+	StaleReaped int64 `json:"stale_reaped"`
+	// SlowDisconnected is the number of clients disconnected because their
+	// send buffer was full
+	SlowDisconnected int64 `json:"slow_disconnected"`
+	// DroppedBroadcasts is the number of broadcasts discarded because the
+	// broadcast queue was full
+	DroppedBroadcasts int64 `json:"dropped_broadcasts"`
 }
 
 // Server manages WebSocket connections and subscriptions
 type Server struct {
-	clients    map[*Client]bool
-	register   chan *Client
-	unregister chan *Client
-	broadcast  chan interface{}
-	mu         sync.RWMutex
+	clients     map[*Client]bool
+	register    chan *Client
+	unregister  chan *Client
+	broadcast   chan channelMessage
+	mu          sync.RWMutex
+	meter       BandwidthMeter
+	authorizer  ChannelAuthorizer
+	snapshotter Snapshotter
+
+	// Open connections per API key, for the session observer
+	sessions        map[uuid.UUID]int
+	sessionObserver SessionObserver
+
+	totalConnections  atomic.Int64
+	staleReaped       atomic.Int64
+	slowDisconnected  atomic.Int64
+	droppedBroadcasts atomic.Int64
 }
 
 // NewWebSocketServer creates a new WebSocket server
@@ -37,38 +123,87 @@ func NewWebSocketServer() *Server {
 		clients:    make(map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
-		broadcast:  make(chan interface{}, 256),
+		broadcast:  make(chan channelMessage, 256),
+		sessions:   make(map[uuid.UUID]int),
 	}
 }
 
-// Run starts the WebSocket server management loop
+// SetBandwidthMeter sets the meter notified of every message sent to a client
+func (s *Server) SetBandwidthMeter(meter BandwidthMeter) {
+	s.meter = meter
+}
+
+// SetAuthorizer sets the authorizer consulted when a client subscribes to a
+// private channel other than its own alerts. Without one such subscriptions
+// are refused.
+func (s *Server) SetAuthorizer(authorizer ChannelAuthorizer) {
+	s.authorizer = authorizer
+}
+
+// SetSnapshotter sets the snapshotter asked for the current state of each
+// channel a client subscribes to
+func (s *Server) SetSnapshotter(snapshotter Snapshotter) {
+	s.snapshotter = snapshotter
+}
+
+// SetSessionObserver sets the observer notified when the last connection of
+// an API key closes
+func (s *Server) SetSessionObserver(observer SessionObserver) {
+	s.sessionObserver = observer
+}
+
+// Stats returns the current connection counters
+func (s *Server) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := Stats{
+		Connections:       len(s.clients),
+		TotalConnections:  s.totalConnections.Load(),
+		StaleReaped:       s.staleReaped.Load(),
+		SlowDisconnected:  s.slowDisconnected.Load(),
+		DroppedBroadcasts: s.droppedBroadcasts.Load(),
+	}
+	for client := range s.clients {
+		stats.Subscriptions += len(client.channels)
+	}
+	return stats
+}
+
+// Run starts the WebSocket server management loop. When ctx is cancelled
+// every remaining connection is closed.
 func (s *Server) Run(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
+			s.mu.Lock()
+			for client := range s.clients {
+				delete(s.clients, client)
+				client.close()
+			}
+			s.mu.Unlock()
 			return
 		case client := <-s.register:
 			s.mu.Lock()
 			s.clients[client] = true
+			if client.apiKeyID != nil {
+				s.sessions[*client.apiKeyID]++
+			}
 			s.mu.Unlock()
+			s.totalConnections.Add(1)
 		case client := <-s.unregister:
 			s.mu.Lock()
-			if _, ok := s.clients[client]; ok {
-				delete(s.clients, client)
-				close(client.send)
-			}
+			// Both loops of a client unregister it, so only the first counts
+			registered := s.clients[client]
+			delete(s.clients, client)
+			closed := registered && client.apiKeyID != nil && s.endSession(*client.apiKeyID)
 			s.mu.Unlock()
-		case message := <-s.broadcast:
-			s.mu.RLock()
-			for client := range s.clients {
-				select {
-				case client.send <- message:
-				default:
-					close(client.send)
-					delete(s.clients, client)
-				}
+
+			if closed && s.sessionObserver != nil {
+				go s.sessionObserver.OnSessionClosed(ctx, *client.apiKeyID)
 			}
-			s.mu.RUnlock()
+		case m := <-s.broadcast:
+			s.PublishToChannel(m.channel, m.message)
 		}
 	}
 }
@@ -90,127 +225,307 @@ func (s *Server) Upgrade(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// The request is done once the handler returns, so keep only its values
 	client := &Client{
 		conn:     conn,
 		send:     make(chan interface{}, 256),
 		channels: make(map[string]bool),
+		reqCtx:   context.WithoutCancel(r.Context()),
+		done:     make(chan struct{}),
+	}
+	if key, ok := usage.APIKeyFromContext(r.Context()); ok {
+		client.apiKeyID = &key.ID
 	}
 
-	s.register <- client
+	select {
+	case s.register <- client:
+	case <-ctx.Done():
+		conn.Close()
+		return
+	}
 
-	go s.handleClient(ctx, client)
+	go s.readLoop(ctx, client)
+	go s.writeLoop(ctx, client)
 }
 
-// handleClient manages individual WebSocket client connections
-func (s *Server) handleClient(ctx context.Context, client *Client) {
-	defer func() {
-		s.unregister <- client
-		client.conn.Close()
-	}()
+// endSession counts a closed connection of an API key, reporting whether it
+// was the key's last. The caller must hold s.mu.
+func (s *Server) endSession(apiKeyID uuid.UUID) bool {
+	s.sessions[apiKeyID]--
+	if s.sessions[apiKeyID] > 0 {
+		return false
+	}
+	delete(s.sessions, apiKeyID)
+	return true
+}
 
-	// Handle incoming messages
-	go func() {
-		for {
-			_, message, err := client.conn.ReadMessage()
-			if err != nil {
-				log.Printf("WebSocket read error: %v", err)
-				return
-			}
+// removeClient unregisters a client and closes its connection
+func (s *Server) removeClient(ctx context.Context, client *Client) {
+	client.close()
+	select {
+	case s.unregister <- client:
+	case <-ctx.Done():
+	}
+}
 
-			var msg struct {
-				Type    string   `json:"type"`
-				Channels []string `json:"channels"`
-			}
+// readLoop handles subscription messages from a client. A client that sends
+// nothing, not even a pong, for pongWait is considered dead.
+func (s *Server) readLoop(ctx context.Context, client *Client) {
+	defer s.removeClient(ctx, client)
 
-			if err := json.Unmarshal(message, &msg); err != nil {
-				log.Printf("WebSocket message parse error: %v", err)
-				continue
-			}
+	client.conn.SetReadLimit(maxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
 
-			switch msg.Type {
-			case "subscribe":
-				s.mu.Lock()
-				for _, channel := range msg.Channels {
-					client.channels[channel] = true
-				}
-				s.mu.Unlock()
-			case "unsubscribe":
-				s.mu.Lock()
-				for _, channel := range msg.Channels {
-					delete(client.channels, channel)
+	for {
+		_, message, err := client.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-client.done:
+				// Closed by the server
+			default:
+				if isTimeout(err) {
+					s.staleReaped.Add(1)
+					log.Printf("WebSocket client missed its pong deadline, closing")
+				} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+					log.Printf("WebSocket read error: %v", err)
 				}
-				s.mu.Unlock()
 			}
+			return
+		}
+		client.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var msg struct {
+			Type     string   `json:"type"`
+			Channels []string `json:"channels"`
+		}
+
+		if err := json.Unmarshal(message, &msg); err != nil {
+			log.Printf("WebSocket message parse error: %v", err)
+			continue
+		}
+
+		switch msg.Type {
+		case "subscribe":
+			s.subscribe(client, msg.Channels)
+		case "unsubscribe":
+			s.mu.Lock()
+			for _, channel := range msg.Channels {
+				delete(client.channels, channel)
+			}
+			s.mu.Unlock()
 		}
+	}
+}
+
+// writeLoop sends queued messages and periodic pings to a client. Every
+// write has a deadline so a half-open connection cannot block it forever.
+func (s *Server) writeLoop(ctx context.Context, client *Client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		s.removeClient(ctx, client)
 	}()
 
-	// Send messages to client
 	for {
 		select {
 		case <-ctx.Done():
+			client.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeWait))
+			return
+		case <-client.done:
 			return
-		case message, ok := <-client.send:
-			if !ok {
-				client.conn.WriteMessage(websocket.CloseMessage, []byte{})
+		case <-ticker.C:
+			if err := client.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				s.writeFailed(client, err)
 				return
 			}
-
-			err := client.conn.WriteJSON(message)
+		case message := <-client.send:
+			data, err := json.Marshal(message)
 			if err != nil {
-				log.Printf("WebSocket write error: %v", err)
+				log.Printf("WebSocket message encode error: %v", err)
+				continue
+			}
+
+			client.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := client.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				s.writeFailed(client, err)
 				return
 			}
+
+			if s.meter != nil {
+				s.meter.RecordWebsocketBytes(client.reqCtx, len(data))
+			}
 		}
 	}
 }
 
+// writeFailed records why a write to a client failed
+func (s *Server) writeFailed(client *Client, err error) {
+	select {
+	case <-client.done:
+		return
+	default:
+	}
+
+	if isTimeout(err) {
+		s.staleReaped.Add(1)
+		log.Printf("WebSocket client missed its write deadline, closing")
+		return
+	}
+	log.Printf("WebSocket write error: %v", err)
+}
+
+// isTimeout reports whether err is a deadline being exceeded
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// enqueue queues a message for a client without blocking. A client whose
+// buffer is full is not keeping up and is disconnected rather than allowed
+// to hold back every other subscriber.
+func (s *Server) enqueue(client *Client, message interface{}) {
+	select {
+	case client.send <- message:
+	case <-client.done:
+	default:
+		s.slowDisconnected.Add(1)
+		log.Printf("WebSocket client buffer full, disconnecting")
+		client.close()
+	}
+}
+
 // BroadcastTradeEvent sends trade events to subscribed clients
-func (s *Server) BroadcastTradeEvent(trade *models.Trade, contract *models.Contract) {
-	event := models.TradeEvent{
-		ID:             trade.ID,
-		ContractID:     contract.ID,
-		ContractType:   contract.ContractType,
-		StrikeHashRate: contract.StrikeHashRate,
-		Price:          trade.Price,
-		Quantity:       trade.Quantity,
-		ExecutedAt:     trade.ExecutedAt,
-	}
-
-	s.broadcast <- map[string]interface{}{
-		"type":    "trade",
-		"payload": event,
-	}
-}
-
-// SetupWebSocketIntegration connects WebSocket server to order book
-func SetupWebSocketIntegration(orderBook *orderbook.OrderBook, wsServer *Server) {
-	// Create a channel for trade events
-	tradeEventChan := make(chan models.TradeEvent, 100)
-	
-	// Set the event publisher in the order book
-	orderBook.SetEventPublisher(tradeEventChan)
-
-	// Start a goroutine to handle trade events
-	go func() {
-		for {
-			select {
-			case tradeEvent := <-tradeEventChan:
-				// Find the corresponding contract (this would typically be done via database lookup)
-				// For this example, we'll create a minimal contract object
-				contract := &models.Contract{
-					ID:             tradeEvent.ContractID,
-					ContractType:   tradeEvent.ContractType,
-					StrikeHashRate: tradeEvent.StrikeHashRate,
-				}
+func (s *Server) BroadcastTradeEvent(event events.TradeEvent) {
+	select {
+	case s.broadcast <- channelMessage{
+		channel: TradesChannel,
+		message: map[string]interface{}{
+			"type":    "trade",
+			"payload": event,
+		},
+	}:
+	default:
+		s.droppedBroadcasts.Add(1)
+		log.Printf("WebSocket broadcast queue full, dropping trade %s", event.ID)
+	}
+}
 
-				wsServer.BroadcastTradeEvent(&models.Trade{
-					ID:           tradeEvent.ID,
-					ContractID:   tradeEvent.ContractID,
-					Price:        tradeEvent.Price,
-					Quantity:     tradeEvent.Quantity,
-					ExecutedAt:   tradeEvent.ExecutedAt.String(),
-				}, contract)
-			}
+// subscribe adds the channels a client is allowed to subscribe to and
+// reports the result back to the client
+func (s *Server) subscribe(client *Client, channels []string) {
+	var accepted []string
+	rejected := make(map[string]string)
+
+	for _, channel := range channels {
+		if err := s.authorize(client, channel); err != nil {
+			rejected[channel] = err.Error()
+			continue
 		}
-	}()
+		accepted = append(accepted, channel)
+	}
+
+	s.mu.Lock()
+	for _, channel := range accepted {
+		client.channels[channel] = true
+	}
+	s.mu.Unlock()
+
+	reply := map[string]interface{}{
+		"type":     "subscribed",
+		"channels": accepted,
+	}
+	if len(rejected) > 0 {
+		reply["rejected"] = rejected
+	}
+
+	s.enqueue(client, reply)
+
+	if s.snapshotter == nil {
+		return
+	}
+	for _, channel := range accepted {
+		s.snapshotter.SendSnapshots(channel, func(message interface{}) {
+			s.enqueue(client, message)
+		})
+	}
+}
+
+// authorize checks that a client may subscribe to a channel. Public channels
+// and wildcards are open to everyone; a private channel needs an API key and
+// either belongs to the key's user or is allowed by the authorizer.
+func (s *Server) authorize(client *Client, channel string) error {
+	if channel == "" {
+		return fmt.Errorf("channel name cannot be empty")
+	}
+	if !isPrivateChannel(channel) || isWildcard(channel) {
+		return nil
+	}
+
+	userID, ok := client.userID()
+	if !ok {
+		return fmt.Errorf("authentication required")
+	}
+
+	if channel == "alerts:"+userID.String() || channel == "margin:"+userID.String() ||
+		channel == "notifications:"+userID.String() {
+		return nil
+	}
+	if s.authorizer != nil && s.authorizer.CanSubscribe(client.reqCtx, userID, channel) {
+		return nil
+	}
+
+	return fmt.Errorf("not permitted")
+}
+
+// PublishToChannel sends a message only to clients subscribed to channel,
+// directly or through a wildcard
+func (s *Server) PublishToChannel(channel string, message interface{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for client := range s.clients {
+		if !subscribed(client.channels, channel) {
+			continue
+		}
+		s.enqueue(client, message)
+	}
+}
+
+// SetupWebSocketIntegration forwards the events of the bus to their
+// websocket channels: trades to the trades channel, contract lifecycle
+// events to each contract's channel, and hash rate and ASP status updates
+// to their own channels
+func SetupWebSocketIntegration(bus *events.Bus, wsServer *Server) {
+	bus.Subscribe(events.TopicTrades, func(event events.Event) {
+		if trade, ok := event.Payload.(events.TradeEvent); ok {
+			wsServer.BroadcastTradeEvent(trade)
+		}
+	})
+
+	bus.Subscribe(events.TopicContracts, func(event events.Event) {
+		if e, ok := event.Payload.(events.ContractEvent); ok {
+			wsServer.PublishToChannel(ContractChannel(e.ContractID), map[string]interface{}{
+				"type":    e.Type,
+				"payload": e,
+			})
+		}
+	})
+
+	bus.Subscribe(events.TopicHashRate, func(event events.Event) {
+		wsServer.PublishToChannel(HashRateChannel, map[string]interface{}{
+			"type":    "hashrate",
+			"payload": event.Payload,
+		})
+	})
+
+	bus.Subscribe(events.TopicASPStatus, func(event events.Event) {
+		wsServer.PublishToChannel(ASPStatusChannel, map[string]interface{}{
+			"type":    "asp_status",
+			"payload": event.Payload,
+		})
+	})
 }
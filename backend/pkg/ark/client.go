@@ -3,26 +3,51 @@ package ark
 
 import (
     "context"
+    "crypto/tls"
     "errors"
     "fmt"
     "io"
+    "net/url"
     "sync"
     "time"
 
     "github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
     "google.golang.org/grpc"
     "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials"
     "google.golang.org/grpc/credentials/insecure"
     "google.golang.org/grpc/status"
-    "github.com/rs/zerolog/log"
+
+    "hashhedge/internal/logging"
+    "hashhedge/internal/metrics"
+    "hashhedge/internal/netproxy"
 )
 
+var logger = logging.Component(logging.Ark)
+
 // RetryConfig defines retry behavior for ASP communications
 type RetryConfig struct {
-    MaxRetries  int
-    InitialBackoff time.Duration
-    MaxBackoff time.Duration
-    BackoffFactor float64
+    MaxRetries     int           `yaml:"max_retries"`
+    InitialBackoff time.Duration `yaml:"initial_backoff"`
+    MaxBackoff     time.Duration `yaml:"max_backoff"`
+    BackoffFactor  float64       `yaml:"backoff_factor"`
+}
+
+// Validate checks that the backoff grows and is capped
+func (r RetryConfig) Validate() error {
+    if r.MaxRetries < 0 {
+        return fmt.Errorf("ASP max retries cannot be negative")
+    }
+    if r.InitialBackoff <= 0 {
+        return fmt.Errorf("ASP initial backoff must be positive")
+    }
+    if r.MaxBackoff < r.InitialBackoff {
+        return fmt.Errorf("ASP max backoff must be at least the initial backoff")
+    }
+    if r.BackoffFactor < 1 {
+        return fmt.Errorf("ASP backoff factor must be at least 1")
+    }
+    return nil
 }
 
 // DefaultRetryConfig provides sensible defaults for retry behavior
@@ -42,11 +67,16 @@ type Client struct {
     streamCancel     context.CancelFunc
     txStream         arkv1.ArkService_GetTransactionsStreamClient
     reconnectStream  chan struct{}
+    retryMutex       sync.RWMutex
     retryConfig      RetryConfig
+    streamRetry      RetryConfig
     host             string
     port             int
     connectTimeout   time.Duration
     requestTimeout   time.Duration
+    proxy            *url.URL
+    tlsConfig        *tls.Config
+    authToken        string
 }
 
 // Config holds the Ark service configuration
@@ -56,6 +86,15 @@ type Config struct {
     ConnectTimeout  time.Duration
     RequestTimeout  time.Duration
     RetryConfig     *RetryConfig
+    // StreamRetryConfig paces reconnects of the transaction stream; nil
+    // uses DefaultRetryConfig
+    StreamRetryConfig *RetryConfig
+    // Proxy is a SOCKS5 proxy to connect through; nil connects directly
+    Proxy           *url.URL
+    // TLS secures the connection; disabled connects in plaintext
+    TLS             TLSConfig
+    // AuthToken is sent as a bearer token with every call, and requires TLS
+    AuthToken       string
 }
 
 // NewClient creates a new Ark protocol client with enhanced reliability
@@ -65,6 +104,22 @@ func NewClient(cfg Config) (*Client, error) {
     if cfg.RetryConfig != nil {
         retryConfig = *cfg.RetryConfig
     }
+    streamRetry := DefaultRetryConfig
+    if cfg.StreamRetryConfig != nil {
+        streamRetry = *cfg.StreamRetryConfig
+    }
+    
+    if cfg.AuthToken != "" && !cfg.TLS.Enabled {
+        return nil, fmt.Errorf("ASP auth token requires TLS")
+    }
+    
+    var tlsConfig *tls.Config
+    if cfg.TLS.Enabled {
+        var err error
+        if tlsConfig, err = cfg.TLS.Load(); err != nil {
+            return nil, err
+        }
+    }
     
     // Create client instance first, connection established in Connect method
     client := &Client{
@@ -73,6 +128,10 @@ func NewClient(cfg Config) (*Client, error) {
         connectTimeout: cfg.ConnectTimeout,
         requestTimeout: cfg.RequestTimeout,
         retryConfig:    retryConfig,
+        streamRetry:    streamRetry,
+        proxy:          cfg.Proxy,
+        tlsConfig:      tlsConfig,
+        authToken:      cfg.AuthToken,
         reconnectStream: make(chan struct{}, 1),
     }
     
@@ -95,12 +154,27 @@ func (c *Client) Connect() error {
     ctx, cancel := context.WithTimeout(context.Background(), c.connectTimeout)
     defer cancel()
     
-    conn, err := grpc.DialContext(
-        ctx,
-        addr,
-        grpc.WithTransportCredentials(insecure.NewCredentials()),
+    transport := insecure.NewCredentials()
+    if c.tlsConfig != nil {
+        transport = credentials.NewTLS(c.tlsConfig)
+    }
+    opts := []grpc.DialOption{
+        grpc.WithTransportCredentials(transport),
         grpc.WithBlock(),
-    )
+        grpc.WithChainUnaryInterceptor(traceUnary),
+    }
+    if c.authToken != "" {
+        opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials{token: c.authToken}))
+    }
+    if c.proxy != nil {
+        dial, err := netproxy.DialContext(c.proxy)
+        if err != nil {
+            return err
+        }
+        opts = append(opts, grpc.WithContextDialer(dial))
+    }
+    
+    conn, err := grpc.DialContext(ctx, addr, opts...)
     if err != nil {
         return fmt.Errorf("failed to connect to Ark service: %w", err)
     }
@@ -125,15 +199,34 @@ func (c *Client) Close() error {
     return nil
 }
 
+// SetRetryConfig replaces the retry policies of calls and of stream
+// reconnects, taking effect from the next call or reconnect
+func (c *Client) SetRetryConfig(retry, stream RetryConfig) {
+    c.retryMutex.Lock()
+    defer c.retryMutex.Unlock()
+    c.retryConfig = retry
+    c.streamRetry = stream
+}
+
+// retryPolicies returns the current retry policies of calls and of stream
+// reconnects
+func (c *Client) retryPolicies() (RetryConfig, RetryConfig) {
+    c.retryMutex.RLock()
+    defer c.retryMutex.RUnlock()
+    return c.retryConfig, c.streamRetry
+}
+
 // withRetry executes the provided function with retry logic
 func (c *Client) withRetry(operation string, f func() error) error {
     var lastErr error
-    backoff := c.retryConfig.InitialBackoff
+    retryConfig, _ := c.retryPolicies()
+    backoff := retryConfig.InitialBackoff
     
-    for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+    for attempt := 0; attempt <= retryConfig.MaxRetries; attempt++ {
         // On any attempt other than the first, log we're retrying
         if attempt > 0 {
-            log.Info().
+            metrics.ArkRetries.WithLabelValues(operation).Inc()
+            logger.Info().
                 Str("operation", operation).
                 Int("attempt", attempt).
                 Dur("backoff", backoff).
@@ -150,7 +243,7 @@ func (c *Client) withRetry(operation string, f func() error) error {
             
             // Check if error is not retriable
             if isNonRetriableError(err) {
-                log.Error().
+                logger.Error().
                     Str("operation", operation).
                     Err(err).
                     Msg("Non-retriable error from ASP")
@@ -158,7 +251,7 @@ func (c *Client) withRetry(operation string, f func() error) error {
             }
             
             // If we've hit max retries, return the error
-            if attempt == c.retryConfig.MaxRetries {
+            if attempt == retryConfig.MaxRetries {
                 break
             }
             
@@ -166,15 +259,15 @@ func (c *Client) withRetry(operation string, f func() error) error {
             time.Sleep(backoff)
             
             // Increase backoff for next attempt, up to max
-            backoff = time.Duration(float64(backoff) * c.retryConfig.BackoffFactor)
-            if backoff > c.retryConfig.MaxBackoff {
-                backoff = c.retryConfig.MaxBackoff
+            backoff = time.Duration(float64(backoff) * retryConfig.BackoffFactor)
+            if backoff > retryConfig.MaxBackoff {
+                backoff = retryConfig.MaxBackoff
             }
         }
     }
     
     return fmt.Errorf("operation %s failed after %d attempts: %w", 
-        operation, c.retryConfig.MaxRetries+1, lastErr)
+        operation, retryConfig.MaxRetries+1, lastErr)
 }
 
 // isNonRetriableError identifies errors that shouldn't be retried
@@ -285,7 +378,7 @@ func (c *Client) SubmitSignedForfeitTxs(
 func (c *Client) manageTransactionStream(ctx context.Context) {
     // Start initial stream
     if err := c.establishTransactionStream(); err != nil {
-        log.Error().Err(err).Msg("Failed to establish initial transaction stream")
+        logger.Error().Err(err).Msg("Failed to establish initial transaction stream")
         // Queue reconnection attempt
         c.queueStreamReconnect()
     }
@@ -299,12 +392,13 @@ func (c *Client) manageTransactionStream(ctx context.Context) {
             
         case <-c.reconnectStream:
             // Attempt to reconnect with backoff
-            backoff := c.retryConfig.InitialBackoff
-            maxAttempts := c.retryConfig.MaxRetries
+            _, retryConfig := c.retryPolicies()
+            backoff := retryConfig.InitialBackoff
+            maxAttempts := retryConfig.MaxRetries
             
             for attempt := 0; attempt <= maxAttempts; attempt++ {
                 if attempt > 0 {
-                    log.Info().
+                    logger.Info().
                         Int("attempt", attempt).
                         Dur("backoff", backoff).
                         Msg("Attempting to reconnect transaction stream")
@@ -318,18 +412,20 @@ func (c *Client) manageTransactionStream(ctx context.Context) {
                     }
                     
                     // Increase backoff for next attempt
-                    backoff = time.Duration(float64(backoff) * c.retryConfig.BackoffFactor)
-                    if backoff > c.retryConfig.MaxBackoff {
-                        backoff = c.retryConfig.MaxBackoff
+                    backoff = time.Duration(float64(backoff) * retryConfig.BackoffFactor)
+                    if backoff > retryConfig.MaxBackoff {
+                        backoff = retryConfig.MaxBackoff
                     }
                 }
                 
                 // Attempt to establish the stream
                 if err := c.establishTransactionStream(); err == nil {
-                    log.Info().Msg("Transaction stream successfully reconnected")
+                    metrics.ArkStreamReconnects.WithLabelValues("success").Inc()
+                    logger.Info().Msg("Transaction stream successfully reconnected")
                     break
                 } else if attempt == maxAttempts {
-                    log.Error().
+                    metrics.ArkStreamReconnects.WithLabelValues("failure").Inc()
+                    logger.Error().
                         Err(err).
                         Int("attempts", attempt+1).
                         Msg("Failed to reconnect transaction stream after multiple attempts")
@@ -371,10 +467,10 @@ func (c *Client) processTransactionStream() {
         if err != nil {
             if err == io.EOF || errors.Is(err, context.Canceled) {
                 // Stream closed normally
-                log.Info().Msg("Transaction stream closed normally")
+                logger.Info().Msg("Transaction stream closed normally")
             } else {
                 // Stream error, queue reconnection
-                log.Error().Err(err).Msg("Error in transaction stream")
+                logger.Error().Err(err).Msg("Error in transaction stream")
                 c.queueStreamReconnect()
             }
             return
@@ -382,7 +478,7 @@ func (c *Client) processTransactionStream() {
         
         // Process the received transaction
         // Here you would typically dispatch this to appropriate handlers
-        log.Info().
+        logger.Info().
             Str("txid", response.GetTxid()).
             Str("type", response.GetType().String()).
             Msg("Received transaction from stream")
@@ -503,7 +599,7 @@ func (c *Client) CheckASPStatus(ctx context.Context) (bool, error) {
     
     _, err := c.GetInfo(ctx)
     if err != nil {
-        log.Error().Err(err).Msg("ASP status check failed")
+        logger.Error().Err(err).Msg("ASP status check failed")
         return false, err
     }
     
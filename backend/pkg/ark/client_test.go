@@ -0,0 +1,41 @@
+// pkg/ark/client_test.go
+package ark
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultRetryConfig.Validate())
+	assert.NoError(t, RetryConfig{InitialBackoff: time.Second, MaxBackoff: time.Second, BackoffFactor: 1}.Validate())
+
+	assert.Error(t, RetryConfig{MaxRetries: -1, InitialBackoff: time.Second, MaxBackoff: time.Second, BackoffFactor: 1}.Validate())
+	assert.Error(t, RetryConfig{MaxRetries: 3, MaxBackoff: time.Second, BackoffFactor: 1}.Validate())
+	assert.Error(t, RetryConfig{MaxRetries: 3, InitialBackoff: time.Minute, MaxBackoff: time.Second, BackoffFactor: 1}.Validate())
+	assert.Error(t, RetryConfig{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute, BackoffFactor: 0.5}.Validate())
+}
+
+func TestSetRetryConfig(t *testing.T) {
+	c := &Client{}
+	retry := RetryConfig{MaxRetries: 1, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, BackoffFactor: 1}
+	c.SetRetryConfig(retry, retry)
+
+	calls := 0
+	failing := func() error {
+		calls++
+		return errors.New("unavailable")
+	}
+	assert.Error(t, c.withRetry("GetInfo", failing))
+	assert.Equal(t, 2, calls)
+
+	// A replaced policy applies from the next call
+	retry.MaxRetries = 3
+	c.SetRetryConfig(retry, retry)
+	calls = 0
+	assert.Error(t, c.withRetry("GetInfo", failing))
+	assert.Equal(t, 4, calls)
+}
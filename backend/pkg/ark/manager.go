@@ -0,0 +1,410 @@
+// pkg/ark/manager.go
+package ark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ark-network/ark/api-spec/protobuf/gen/ark/v1"
+
+	"hashhedge/internal/metrics"
+)
+
+// ErrNoASP is returned when no ASP is connected and healthy
+var ErrNoASP = errors.New("no ASP is available")
+
+// probeTimeout bounds each health probe of an ASP
+const probeTimeout = 5 * time.Second
+
+// latencyWeight is the weight of a new probe in an ASP's average latency
+const latencyWeight = 0.3
+
+// Endpoint is one ASP the manager may route to
+type Endpoint struct {
+	Name   string
+	Config Config
+}
+
+// Migrator moves the VTXOs held by an ASP that is down for good onto
+// another, by exiting them on chain and registering them with the new ASP
+type Migrator interface {
+	MigrateASP(ctx context.Context, from, to string) (int, error)
+}
+
+// ManagerOptions tunes the health checks and failover of a Manager
+type ManagerOptions struct {
+	// CheckInterval is how often every ASP is probed
+	CheckInterval time.Duration
+	// DownAfter is how long a former primary must stay unreachable before
+	// it is deemed down for good and its VTXOs are migrated
+	DownAfter time.Duration
+}
+
+// EndpointStatus is the state of an ASP as reported to operators
+type EndpointStatus struct {
+	Name      string     `json:"name"`
+	Primary   bool       `json:"primary"`
+	Connected bool       `json:"connected"`
+	Healthy   bool       `json:"healthy"`
+	LatencyMs int64      `json:"latency_ms"`
+	DownSince *time.Time `json:"down_since,omitempty"`
+	Migrated  bool       `json:"migrated"`
+}
+
+// endpoint is the connection to one ASP and the outcome of its probes
+type endpoint struct {
+	name      string
+	config    Config
+	client    *Client
+	healthy   bool
+	latency   time.Duration
+	downSince time.Time
+	// served is set once the ASP has been primary, so it may hold VTXOs
+	served   bool
+	migrated bool
+}
+
+// Manager keeps connections to several ASPs and routes every call to the
+// primary. VTXOs are bound to the ASP that issued them, so the primary only
+// changes when it stops answering, to the healthy ASP with the lowest
+// latency. A former primary that stays down is migrated away from.
+type Manager struct {
+	opts ManagerOptions
+
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	primary   *endpoint
+	migrator  Migrator
+}
+
+// NewManager creates a manager over ASPs in order of preference. The first
+// is the primary until it fails; none is connected until Start.
+func NewManager(endpoints []Endpoint, opts ManagerOptions) (*Manager, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no ASP configured")
+	}
+
+	m := &Manager{opts: opts}
+	seen := make(map[string]bool)
+	for _, e := range endpoints {
+		if seen[e.Name] {
+			return nil, fmt.Errorf("duplicate ASP name %q", e.Name)
+		}
+		seen[e.Name] = true
+		m.endpoints = append(m.endpoints, &endpoint{name: e.Name, config: e.Config})
+	}
+	m.primary = m.endpoints[0]
+	m.primary.served = true
+	return m, nil
+}
+
+// WithMigrator sets what migrates the VTXOs of a former primary that is
+// down for good. Without one they stay where they are.
+func (m *Manager) WithMigrator(migrator Migrator) *Manager {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.migrator = migrator
+	return m
+}
+
+// SetRetryConfig replaces the retry policies of calls and stream reconnects
+// on every ASP, including those not yet connected
+func (m *Manager) SetRetryConfig(retry, stream RetryConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.endpoints {
+		e.config.RetryConfig = &retry
+		e.config.StreamRetryConfig = &stream
+		if e.client != nil {
+			e.client.SetRetryConfig(retry, stream)
+		}
+	}
+}
+
+// Start connects to every ASP and probes them on the check interval until
+// ctx is cancelled, then closes the connections
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(m.opts.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			m.check(ctx, time.Now())
+
+			select {
+			case <-ctx.Done():
+				m.close()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// check probes every ASP, fails over from an unhealthy primary and migrates
+// away from a former primary that is down for good
+func (m *Manager) check(ctx context.Context, now time.Time) {
+	m.mu.RLock()
+	endpoints := append([]*endpoint(nil), m.endpoints...)
+	m.mu.RUnlock()
+
+	for _, e := range endpoints {
+		m.probe(ctx, e, now)
+	}
+
+	m.mu.Lock()
+	m.failover()
+	migrations := m.pendingMigrations(now)
+	migrator := m.migrator
+	to := m.primary.name
+	m.mu.Unlock()
+
+	if migrator == nil {
+		return
+	}
+	for _, from := range migrations {
+		logger.Error().Str("from", from).Str("to", to).Dur("down_after", m.opts.DownAfter).
+			Msg("ASP is down for good, migrating its VTXOs")
+		migrated, err := migrator.MigrateASP(ctx, from, to)
+		if err != nil {
+			logger.Error().Err(err).Str("from", from).Int("migrated", migrated).Msg("Failed to migrate VTXOs off ASP")
+			continue
+		}
+		logger.Info().Str("from", from).Str("to", to).Int("migrated", migrated).Msg("Migrated VTXOs off ASP")
+	}
+}
+
+// probe connects to an ASP if needed and times a call to it
+func (m *Manager) probe(ctx context.Context, e *endpoint, now time.Time) {
+	m.mu.RLock()
+	client, config := e.client, e.config
+	m.mu.RUnlock()
+
+	var err error
+	var latency time.Duration
+	if client == nil {
+		client, err = NewClient(config)
+	}
+	if err == nil {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		started := time.Now()
+		_, err = client.client.GetInfo(probeCtx, &arkv1.GetInfoRequest{})
+		latency = time.Since(started)
+		cancel()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e.client == nil && client != nil {
+		e.client = client
+		// The policies may have been replaced while connecting
+		if e.config.RetryConfig != nil && e.config.StreamRetryConfig != nil {
+			client.SetRetryConfig(*e.config.RetryConfig, *e.config.StreamRetryConfig)
+		}
+	}
+	if err != nil {
+		if e.healthy || e.downSince.IsZero() {
+			e.downSince = now
+		}
+		e.healthy = false
+		metrics.ASPUp.WithLabelValues(e.name).Set(0)
+		logger.Warn().Err(err).Str("asp", e.name).Msg("ASP probe failed")
+		return
+	}
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(float64(e.latency)*(1-latencyWeight) + float64(latency)*latencyWeight)
+	}
+	e.healthy = true
+	e.downSince = time.Time{}
+	e.migrated = false
+	metrics.ASPUp.WithLabelValues(e.name).Set(1)
+}
+
+// failover makes the best ranked ASP primary if the primary is unhealthy.
+// The caller must hold m.mu.
+func (m *Manager) failover() {
+	if m.primary.healthy {
+		return
+	}
+
+	ranked := m.ranked()
+	if len(ranked) == 0 || !ranked[0].healthy {
+		return
+	}
+
+	from := m.primary
+	m.primary = ranked[0]
+	m.primary.served = true
+	metrics.ASPFailovers.WithLabelValues(from.name).Inc()
+	logger.Error().Str("from", from.name).Str("to", m.primary.name).Msg("ASP unreachable, failed over")
+}
+
+// ranked orders the ASPs healthy first, then by latency, then in order of
+// preference. The caller must hold m.mu.
+func (m *Manager) ranked() []*endpoint {
+	ranked := append([]*endpoint(nil), m.endpoints...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].healthy != ranked[j].healthy {
+			return ranked[i].healthy
+		}
+		return ranked[i].latency < ranked[j].latency
+	})
+	return ranked
+}
+
+// pendingMigrations returns the former primaries that have been down for
+// longer than DownAfter and not yet migrated away from, marking them
+// migrated. The caller must hold m.mu.
+func (m *Manager) pendingMigrations(now time.Time) []string {
+	if m.opts.DownAfter <= 0 || !m.primary.healthy {
+		return nil
+	}
+
+	var names []string
+	for _, e := range m.endpoints {
+		if e == m.primary || !e.served || e.healthy || e.migrated || e.downSince.IsZero() {
+			continue
+		}
+		if now.Sub(e.downSince) >= m.opts.DownAfter {
+			e.migrated = true
+			names = append(names, e.name)
+		}
+	}
+	return names
+}
+
+// close shuts down every connection
+func (m *Manager) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.endpoints {
+		if e.client != nil {
+			if err := e.client.Close(); err != nil {
+				logger.Warn().Err(err).Str("asp", e.name).Msg("Failed to close ASP connection")
+			}
+			e.client = nil
+		}
+	}
+}
+
+// Primary returns the name of the ASP calls are routed to, which new VTXOs
+// are issued by
+func (m *Manager) Primary() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.primary.name
+}
+
+// Status returns the state of every ASP in order of preference
+func (m *Manager) Status() []EndpointStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]EndpointStatus, 0, len(m.endpoints))
+	for _, e := range m.endpoints {
+		status := EndpointStatus{
+			Name:      e.name,
+			Primary:   e == m.primary,
+			Connected: e.client != nil,
+			Healthy:   e.healthy,
+			LatencyMs: e.latency.Milliseconds(),
+			Migrated:  e.migrated,
+		}
+		if !e.downSince.IsZero() {
+			t := e.downSince
+			status.DownSince = &t
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// client returns the connection to the primary
+func (m *Manager) client() (*Client, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.primary.client == nil {
+		return nil, ErrNoASP
+	}
+	return m.primary.client, nil
+}
+
+// CheckASPStatus verifies if the primary ASP is operational
+func (m *Manager) CheckASPStatus(ctx context.Context) (bool, error) {
+	client, err := m.client()
+	if err != nil {
+		return false, err
+	}
+	return client.CheckASPStatus(ctx)
+}
+
+// GetInfo retrieves information about the primary ASP
+func (m *Manager) GetInfo(ctx context.Context) (*arkv1.GetInfoResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetInfo(ctx)
+}
+
+// RegisterInputsForNextRound registers inputs for the primary's next round
+func (m *Manager) RegisterInputsForNextRound(ctx context.Context, serializedPsbts []string) (*arkv1.RegisterInputsForNextRoundResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.RegisterInputsForNextRound(ctx, serializedPsbts)
+}
+
+// RegisterOutputsForNextRound registers outputs for the primary's next round
+func (m *Manager) RegisterOutputsForNextRound(ctx context.Context, outputs []*arkv1.Output) (*arkv1.RegisterOutputsForNextRoundResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.RegisterOutputsForNextRound(ctx, outputs)
+}
+
+// SubmitSignedForfeitTxs submits signed forfeit transactions to the primary
+func (m *Manager) SubmitSignedForfeitTxs(ctx context.Context, roundID string, serializedPsbts []string) (*arkv1.SubmitSignedForfeitTxsResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.SubmitSignedForfeitTxs(ctx, roundID, serializedPsbts)
+}
+
+// CreateOutOfRoundTransaction creates an out-of-round transaction with the primary
+func (m *Manager) CreateOutOfRoundTransaction(ctx context.Context, senderPSBT string, outputs []*arkv1.Output) (*arkv1.CreateOutOfRoundTransactionResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateOutOfRoundTransaction(ctx, senderPSBT, outputs)
+}
+
+// SignOutOfRoundTransaction submits a signed out-of-round transaction to the primary
+func (m *Manager) SignOutOfRoundTransaction(ctx context.Context, txID string, signedPSBT string) (*arkv1.SignOutOfRoundTransactionResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.SignOutOfRoundTransaction(ctx, txID, signedPSBT)
+}
+
+// GetExitPath generates an exit transaction for a VTXO of the primary
+func (m *Manager) GetExitPath(ctx context.Context, vtxoID string, destinationAddress string, feeRate int64) (*arkv1.GetExitPathResponse, error) {
+	client, err := m.client()
+	if err != nil {
+		return nil, err
+	}
+	return client.GetExitPath(ctx, vtxoID, destinationAddress, feeRate)
+}
@@ -0,0 +1,84 @@
+// pkg/ark/manager_test.go
+package ark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManager(t *testing.T) {
+	_, err := NewManager(nil, ManagerOptions{})
+	assert.Error(t, err)
+
+	_, err = NewManager([]Endpoint{{Name: "primary"}, {Name: "primary"}}, ManagerOptions{})
+	assert.Error(t, err)
+
+	m, err := NewManager([]Endpoint{{Name: "primary"}, {Name: "backup"}}, ManagerOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "primary", m.Primary())
+
+	_, err = m.client()
+	assert.ErrorIs(t, err, ErrNoASP)
+}
+
+func TestFailover(t *testing.T) {
+	m, err := NewManager([]Endpoint{{Name: "primary"}, {Name: "slow"}, {Name: "fast"}}, ManagerOptions{})
+	require.NoError(t, err)
+	primary, slow, fast := m.endpoints[0], m.endpoints[1], m.endpoints[2]
+	slow.healthy, slow.latency = true, 200*time.Millisecond
+	fast.healthy, fast.latency = true, 20*time.Millisecond
+
+	// A healthy primary is kept even when another ASP is faster
+	primary.healthy, primary.latency = true, 100*time.Millisecond
+	m.failover()
+	assert.Equal(t, "primary", m.Primary())
+
+	// An unhealthy primary is replaced by the fastest healthy ASP
+	primary.healthy = false
+	m.failover()
+	assert.Equal(t, "fast", m.Primary())
+	assert.True(t, fast.served)
+
+	// The primary is not switched back once the original recovers
+	primary.healthy = true
+	m.failover()
+	assert.Equal(t, "fast", m.Primary())
+
+	// Without a healthy ASP the primary stays put
+	for _, e := range m.endpoints {
+		e.healthy = false
+	}
+	m.failover()
+	assert.Equal(t, "fast", m.Primary())
+}
+
+func TestPendingMigrations(t *testing.T) {
+	m, err := NewManager([]Endpoint{{Name: "primary"}, {Name: "backup"}, {Name: "standby"}}, ManagerOptions{DownAfter: time.Hour})
+	require.NoError(t, err)
+	primary, backup, standby := m.endpoints[0], m.endpoints[1], m.endpoints[2]
+
+	now := time.Now()
+	primary.downSince = now
+	standby.downSince = now
+	backup.healthy = true
+	m.failover()
+	require.Equal(t, "backup", m.Primary())
+
+	// The former primary is only migrated away from once down long enough
+	assert.Empty(t, m.pendingMigrations(now.Add(time.Minute)))
+
+	// A standby that never issued VTXOs has nothing to migrate
+	assert.Equal(t, []string{"primary"}, m.pendingMigrations(now.Add(time.Hour)))
+	assert.True(t, primary.migrated)
+
+	// Each ASP is migrated away from once
+	assert.Empty(t, m.pendingMigrations(now.Add(2*time.Hour)))
+
+	// Nothing is migrated while the new primary is itself unhealthy
+	m.endpoints[0].migrated = false
+	backup.healthy = false
+	assert.Empty(t, m.pendingMigrations(now.Add(2*time.Hour)))
+}
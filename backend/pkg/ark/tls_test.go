@@ -0,0 +1,57 @@
+// pkg/ark/tls_test.go
+package ark
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfigValidate(t *testing.T) {
+	pin := strings.Repeat("ab", sha256.Size)
+
+	assert.NoError(t, TLSConfig{}.Validate())
+	assert.NoError(t, TLSConfig{Enabled: true, PinnedKeys: []string{pin}}.Validate())
+	assert.NoError(t, TLSConfig{Enabled: true, CertFile: "client.pem", KeyFile: "client.key"}.Validate())
+
+	assert.Error(t, TLSConfig{CAFile: "ca.pem"}.Validate())
+	assert.Error(t, TLSConfig{Enabled: true, CertFile: "client.pem"}.Validate())
+	assert.Error(t, TLSConfig{Enabled: true, PinnedKeys: []string{"abcd"}}.Validate())
+}
+
+func TestVerifyPins(t *testing.T) {
+	newCert := func() *x509.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "asp"},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert
+	}
+	leaf, ca := newCert(), newCert()
+
+	digest := sha256.Sum256(ca.RawSubjectPublicKeyInfo)
+	verify := verifyPins(map[string]bool{hex.EncodeToString(digest[:]): true})
+
+	// A pinned CA anywhere in the chain is accepted
+	assert.NoError(t, verify(nil, [][]*x509.Certificate{{leaf, ca}}))
+	assert.ErrorIs(t, verify(nil, [][]*x509.Certificate{{leaf}}), ErrCertificateNotPinned)
+}
@@ -0,0 +1,60 @@
+// pkg/ark/tracing.go
+package ark
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"hashhedge/internal/logging"
+	"hashhedge/internal/tracing"
+)
+
+var tracer = tracing.Tracer(logging.Ark)
+
+// traceUnary records each call to the ASP as a span of the caller's trace
+// and passes the trace context on to the ASP. The transaction stream lives
+// beyond any one trace and is not traced.
+func traceUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := tracing.StartChild(ctx, tracer, method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method)))
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+
+	err := invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+	tracing.End(span, err)
+	return err
+}
+
+// metadataCarrier lets the trace context be written to gRPC metadata
+type metadataCarrier metadata.MD
+
+// Get implements propagation.TextMapCarrier
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set implements propagation.TextMapCarrier
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys implements propagation.TextMapCarrier
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -5,12 +5,18 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/wire"
+
+	"hashhedge/internal/metrics"
+	"hashhedge/pkg/taproot"
 )
 
 // Block represents a Bitcoin block with the information we need
@@ -20,57 +26,62 @@ type Block struct {
 	Time              time.Time
 	Difficulty        float64
 	PreviousBlockHash string
+	MerkleRoot        string
 }
 
-// Client wraps a Bitcoin RPC client
+// Client wraps pools of RPC connections to a Bitcoin node and its failovers
 type Client struct {
-	rpcClient *rpcclient.Client
+	nodes []*node
+	opts  PoolOptions
+
+	retryMu        sync.RWMutex
+	broadcastRetry BroadcastRetry
 }
 
 // NewClient creates a new Bitcoin client
 func NewClient(host, user, pass string, useTLS bool) (*Client, error) {
-	// Configure RPC connection
-	connCfg := &rpcclient.ConnConfig{
-		Host:         host,
-		User:         user,
-		Pass:         pass,
-		HTTPPostMode: true,
-		DisableTLS:   !useTLS,
-	}
+	return NewProxiedClient(host, user, pass, useTLS, nil)
+}
 
-	client, err := rpcclient.New(connCfg, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Bitcoin RPC client: %w", err)
-	}
+// NewProxiedClient creates a new Bitcoin client connecting through a SOCKS5
+// proxy, or directly when the proxy is nil
+func NewProxiedClient(host, user, pass string, useTLS bool, proxy *url.URL) (*Client, error) {
+	node := Node{Host: host, User: user, Password: pass, UseTLS: useTLS}
+	return NewPooledClient([]Node{node}, DefaultPoolOptions, proxy)
+}
 
-	return &Client{
-		rpcClient: client,
-	}, nil
+// rpcFailed counts a failed RPC call by method
+func rpcFailed(method string) {
+	metrics.BitcoinRPCErrors.WithLabelValues(method).Inc()
 }
 
 // Close shuts down the client
 func (c *Client) Close() {
-	if c.rpcClient != nil {
-		c.rpcClient.Shutdown()
+	for _, n := range c.nodes {
+		n.close()
 	}
 }
 
 // GetBestBlockHash returns the hash of the best block in the longest blockchain
 func (c *Client) GetBestBlockHash(ctx context.Context) (string, error) {
-	hash, err := c.rpcClient.GetBestBlockHashAsync().Receive()
+	result, err := c.call(ctx, "getbestblockhash", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBestBlockHashAsync().Receive()
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get best block hash: %w", err)
 	}
-	return hash.String(), nil
+	return result.(*chainhash.Hash).String(), nil
 }
 
 // GetBlockHash returns the hash of the block at the given height
 func (c *Client) GetBlockHash(ctx context.Context, height int64) (string, error) {
-	hash, err := c.rpcClient.GetBlockHashAsync(height).Receive()
+	result, err := c.call(ctx, "getblockhash", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockHashAsync(height).Receive()
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get block hash at height %d: %w", height, err)
 	}
-	return hash.String(), nil
+	return result.(*chainhash.Hash).String(), nil
 }
 
 // GetBlock retrieves a block by its hash
@@ -80,10 +91,13 @@ func (c *Client) GetBlock(ctx context.Context, hash string) (*Block, error) {
 		return nil, fmt.Errorf("invalid block hash %s: %w", hash, err)
 	}
 
-	blockVerbose, err := c.rpcClient.GetBlockVerboseAsync(blockHash).Receive()
+	result, err := c.call(ctx, "getblock", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockVerboseAsync(blockHash).Receive()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block %s: %w", hash, err)
 	}
+	blockVerbose := result.(*btcjson.GetBlockVerboseResult)
 
 	// Convert Unix timestamp to time.Time
 	blockTime := time.Unix(blockVerbose.Time, 0)
@@ -94,11 +108,29 @@ func (c *Client) GetBlock(ctx context.Context, hash string) (*Block, error) {
 		Time:              blockTime,
 		Difficulty:        blockVerbose.Difficulty,
 		PreviousBlockHash: blockVerbose.PreviousHash,
+		MerkleRoot:        blockVerbose.MerkleRoot,
 	}
 
 	return block, nil
 }
 
+// GetRawBlock retrieves a block by its hash with all of its transactions
+func (c *Client) GetRawBlock(ctx context.Context, hash string) (*wire.MsgBlock, error) {
+	blockHash, err := chainhash.NewHashFromStr(hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block hash %s: %w", hash, err)
+	}
+
+	result, err := c.call(ctx, "getblock", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockAsync(blockHash).Receive()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", hash, err)
+	}
+
+	return result.(*wire.MsgBlock), nil
+}
+
 // GetRawTransaction retrieves the raw transaction with the given hash
 func (c *Client) GetRawTransaction(ctx context.Context, txID string) (string, error) {
 	txHash, err := chainhash.NewHashFromStr(txID)
@@ -106,95 +138,164 @@ func (c *Client) GetRawTransaction(ctx context.Context, txID string) (string, er
 		return "", fmt.Errorf("invalid transaction ID %s: %w", txID, err)
 	}
 
-	tx, err := c.rpcClient.GetRawTransactionAsync(txHash).Receive()
+	result, err := c.call(ctx, "getrawtransaction", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetRawTransactionAsync(txHash).Receive()
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get raw transaction %s: %w", txID, err)
 	}
 
-	return tx.String(), nil
+	return result.(*btcutil.Tx).String(), nil
 }
 
 // GetRawTransactionVerbose retrieves detailed information about a transaction
 func (c *Client) GetRawTransactionVerbose(ctx context.Context, txHash *chainhash.Hash) (*btcjson.TxRawResult, error) {
-	tx, err := c.rpcClient.GetRawTransactionVerboseAsync(txHash).Receive()
+	result, err := c.call(ctx, "getrawtransaction", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetRawTransactionVerboseAsync(txHash).Receive()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get verbose transaction %s: %w", txHash.String(), err)
 	}
 	
-	return tx, nil
+	return result.(*btcjson.TxRawResult), nil
+}
+
+// GetTxOut returns an unspent output, including those of mempool
+// transactions, or nil if it does not exist or is spent
+func (c *Client) GetTxOut(ctx context.Context, txHash *chainhash.Hash, vout uint32) (*wire.TxOut, error) {
+	result, err := c.call(ctx, "gettxout", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetTxOutAsync(txHash, vout, true).Receive()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output %s:%d: %w", txHash.String(), vout, err)
+	}
+
+	out := result.(*btcjson.GetTxOutResult)
+	if out == nil {
+		return nil, nil
+	}
+
+	value, err := btcutil.NewAmount(out.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value of output %s:%d: %w", txHash.String(), vout, err)
+	}
+	pkScript, err := hex.DecodeString(out.ScriptPubKey.Hex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script of output %s:%d: %w", txHash.String(), vout, err)
+	}
+
+	return wire.NewTxOut(int64(value), pkScript), nil
 }
 
 // GetBlockHeaderVerbose retrieves detailed information about a block header
 func (c *Client) GetBlockHeaderVerbose(ctx context.Context, blockHash *chainhash.Hash) (*btcjson.GetBlockHeaderVerboseResult, error) {
-	header, err := c.rpcClient.GetBlockHeaderVerboseAsync(blockHash).Receive()
+	result, err := c.call(ctx, "getblockheader", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockHeaderVerboseAsync(blockHash).Receive()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get block header %s: %w", blockHash.String(), err)
 	}
 	
-	return header, nil
+	return result.(*btcjson.GetBlockHeaderVerboseResult), nil
 }
 
 // GetBlockCount returns the current block height
 func (c *Client) GetBlockCount(ctx context.Context) (int64, error) {
-	count, err := c.rpcClient.GetBlockCountAsync().Receive()
+	result, err := c.call(ctx, "getblockcount", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockCountAsync().Receive()
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get block count: %w", err)
 	}
 	
-	return count, nil
+	return result.(int64), nil
 }
 
 // SendRawTransaction broadcasts a raw transaction to the network
 func (c *Client) SendRawTransaction(ctx context.Context, tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
-	txHash, err := c.rpcClient.SendRawTransactionAsync(tx, allowHighFees).Receive()
+	result, err := c.call(ctx, "sendrawtransaction", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.SendRawTransactionAsync(tx, allowHighFees).Receive()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to broadcast transaction: %w", err)
 	}
 	
-	return txHash, nil
+	return result.(*chainhash.Hash), nil
 }
 
 // BroadcastTransaction broadcasts a raw transaction to the network
 func (c *Client) BroadcastTransaction(ctx context.Context, txHex string) (string, error) {
-	// Decode the transaction hex
-	txBytes, err := hex.DecodeString(txHex)
+	// Decode the transaction within the same limits as a submitted one
+	tx, err := ParseTransactionHex(txHex, DefaultParseLimits)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode transaction hex: %w", err)
+		return "", err
 	}
 
-	// Deserialize the transaction
-	var tx wire.MsgTx
-	if err := tx.Deserialize(hex.NewDecoder(txBytes)); err != nil {
-		return "", fmt.Errorf("failed to deserialize transaction: %w", err)
+	result, err := c.call(ctx, "sendrawtransaction", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.SendRawTransactionAsync(tx, false).Receive()
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
 	}
 
-	txHash, err := c.rpcClient.SendRawTransactionAsync(&tx, false).Receive()
+	return result.(*chainhash.Hash).String(), nil
+}
+
+// SendToAddress pays an address from the node's wallet, which selects the
+// inputs, signs and broadcasts the transaction, and returns its ID
+func (c *Client) SendToAddress(ctx context.Context, address btcutil.Address, amount int64) (string, error) {
+	result, err := c.callWallet(ctx, "sendtoaddress", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.SendToAddressAsync(address, btcutil.Amount(amount)).Receive()
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to broadcast transaction: %w", err)
+		return "", fmt.Errorf("failed to send to %s: %w", address, err)
+	}
+
+	return result.(*chainhash.Hash).String(), nil
+}
+
+// SignPSBT signs the inputs of a base64 PSBT whose keys the node's wallet
+// holds, leaving any other inputs for their owners
+func (c *Client) SignPSBT(ctx context.Context, packet string) (string, error) {
+	sign := true
+	result, err := c.callWallet(ctx, "walletprocesspsbt", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.WalletProcessPsbt(packet, &sign, rpcclient.SigHashAll, nil)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign PSBT: %w", err)
 	}
 
-	return txHash.String(), nil
+	return result.(*btcjson.WalletProcessPsbtResult).Psbt, nil
 }
 
 // GetBlockchainInfo retrieves information about the blockchain
 func (c *Client) GetBlockchainInfo(ctx context.Context) (*btcjson.GetBlockChainInfoResult, error) {
-	info, err := c.rpcClient.GetBlockChainInfoAsync().Receive()
+	result, err := c.call(ctx, "getblockchaininfo", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.GetBlockChainInfoAsync().Receive()
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blockchain info: %w", err)
 	}
 
-	return info, nil
+	return result.(*btcjson.GetBlockChainInfoResult), nil
 }
 
-// EstimateFee estimates the fee for a transaction with the given number of inputs and outputs
+// EstimateFee estimates the fee for a transaction with the given number of
+// taproot key path inputs and P2TR outputs. Builders spending script paths
+// should size their inputs with taproot.LeafSpends instead.
 func (c *Client) EstimateFee(ctx context.Context, numInputs, numOutputs int, feeRate float64) (int64, error) {
-	// Estimate transaction size
-	// P2PKH input: ~148 bytes, P2PKH output: ~34 bytes
-	// Add 10 bytes for version, locktime, etc.
-	txSize := 10 + (numInputs * 148) + (numOutputs * 34)
-	
-	// Calculate fee based on size and fee rate (satoshis per byte)
-	fee := int64(float64(txSize) * feeRate)
+	spends := make([]taproot.Spend, numInputs)
+	for i := range spends {
+		spends[i] = taproot.KeyPathSpend()
+	}
+
+	outputs := make([]int, numOutputs)
+	for i := range outputs {
+		outputs[i] = taproot.P2TROutputScriptSize
+	}
+
+	// Calculate fee based on virtual size and fee rate (satoshis per vbyte)
+	fee := taproot.Fee(taproot.TxWeight(spends, outputs), feeRate)
 	
 	// Ensure minimum relay fee (typically 1000 satoshis)
 	minFee := int64(1000)
@@ -204,3 +305,22 @@ func (c *Client) EstimateFee(ctx context.Context, numInputs, numOutputs int, fee
 	
 	return fee, nil
 }
+
+// EstimateSmartFee returns the node's fee rate estimate in sat/vB for
+// confirmation within confTarget blocks
+func (c *Client) EstimateSmartFee(ctx context.Context, confTarget int64) (float64, error) {
+	estimate, err := c.call(ctx, "estimatesmartfee", func(rpc *rpcclient.Client) (interface{}, error) {
+		return rpc.EstimateSmartFeeAsync(confTarget, nil).Receive()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate smart fee: %w", err)
+	}
+	result := estimate.(*btcjson.EstimateSmartFeeResult)
+
+	if result.FeeRate == nil {
+		return 0, fmt.Errorf("no fee estimate available for %d blocks: %v", confTarget, result.Errors)
+	}
+
+	// The node reports BTC/kvB
+	return *result.FeeRate * 1e8 / 1000, nil
+}
@@ -0,0 +1,117 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// FeeEstimator estimates the fee rate in sat/vB needed to confirm within a
+// number of blocks
+type FeeEstimator interface {
+	EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error)
+}
+
+// EstimateFeeRate returns the node's estimatesmartfee rate for confTarget
+// blocks, so the client can be used as a FeeEstimator
+func (c *Client) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	return c.EstimateSmartFee(ctx, confTarget)
+}
+
+// APIFeeEstimator reads recommended fee rates from an external API serving
+// the mempool.space format: a JSON object of fastestFee, halfHourFee,
+// hourFee and economyFee in sat/vB
+type APIFeeEstimator struct {
+	url    string
+	client *http.Client
+}
+
+// NewAPIFeeEstimator creates a fee estimator backed by an external API
+func NewAPIFeeEstimator(url string) *APIFeeEstimator {
+	return &APIFeeEstimator{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. with one connecting through
+// a proxy
+func (e *APIFeeEstimator) WithHTTPClient(client *http.Client) *APIFeeEstimator {
+	e.client = client
+	return e
+}
+
+// recommendedFees is the response of a mempool.space style fee API
+type recommendedFees struct {
+	FastestFee  float64 `json:"fastestFee"`
+	HalfHourFee float64 `json:"halfHourFee"`
+	HourFee     float64 `json:"hourFee"`
+	EconomyFee  float64 `json:"economyFee"`
+}
+
+// forTarget picks the recommendation closest to a confirmation target,
+// assuming ten minute blocks
+func (f recommendedFees) forTarget(confTarget int64) float64 {
+	switch {
+	case confTarget <= 1:
+		return f.FastestFee
+	case confTarget <= 3:
+		return f.HalfHourFee
+	case confTarget <= 6:
+		return f.HourFee
+	default:
+		return f.EconomyFee
+	}
+}
+
+// EstimateFeeRate returns the API's recommendation for confTarget blocks
+func (e *APIFeeEstimator) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create fee API request: %w", err)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query fee API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fee API returned status %d", resp.StatusCode)
+	}
+
+	var fees recommendedFees
+	if err := json.NewDecoder(resp.Body).Decode(&fees); err != nil {
+		return 0, fmt.Errorf("failed to decode fee API response: %w", err)
+	}
+
+	rate := fees.forTarget(confTarget)
+	if rate <= 0 {
+		return 0, fmt.Errorf("fee API has no estimate for %d blocks", confTarget)
+	}
+	return rate, nil
+}
+
+// FallbackFeeEstimator asks each estimator in turn and returns the first
+// estimate, such as the node's with an external API behind it
+type FallbackFeeEstimator []FeeEstimator
+
+// EstimateFeeRate returns the first estimate any estimator can give
+func (f FallbackFeeEstimator) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	var errs []error
+	for _, estimator := range f {
+		rate, err := estimator.EstimateFeeRate(ctx, confTarget)
+		if err == nil {
+			return rate, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return 0, errors.New("no fee estimators configured")
+	}
+	return 0, errors.Join(errs...)
+}
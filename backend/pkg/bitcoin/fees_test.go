@@ -0,0 +1,59 @@
+package bitcoin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedFee struct {
+	rate float64
+	err  error
+}
+
+func (f fixedFee) EstimateFeeRate(ctx context.Context, confTarget int64) (float64, error) {
+	return f.rate, f.err
+}
+
+func TestAPIFeeEstimator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"fastestFee":40,"halfHourFee":30,"hourFee":20,"economyFee":8,"minimumFee":1}`))
+	}))
+	defer server.Close()
+
+	estimator := NewAPIFeeEstimator(server.URL)
+	for target, want := range map[int64]float64{1: 40, 2: 30, 6: 20, 144: 8} {
+		rate, err := estimator.EstimateFeeRate(context.Background(), target)
+		require.NoError(t, err)
+		assert.Equal(t, want, rate, "target=%d", target)
+	}
+}
+
+func TestAPIFeeEstimatorErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewAPIFeeEstimator(server.URL).EstimateFeeRate(context.Background(), 6)
+	assert.Error(t, err)
+}
+
+func TestFallbackFeeEstimator(t *testing.T) {
+	failing := fixedFee{err: errors.New("node has no estimate")}
+
+	rate, err := FallbackFeeEstimator{failing, fixedFee{rate: 12}}.EstimateFeeRate(context.Background(), 6)
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, rate)
+
+	_, err = FallbackFeeEstimator{failing, failing}.EstimateFeeRate(context.Background(), 6)
+	assert.Error(t, err)
+
+	_, err = FallbackFeeEstimator{}.EstimateFeeRate(context.Background(), 6)
+	assert.Error(t, err)
+}
@@ -0,0 +1,167 @@
+// pkg/bitcoin/parse.go
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrMalformedTransaction is returned when an externally supplied
+// transaction or PSBT is rejected by the parser
+var ErrMalformedTransaction = errors.New("malformed transaction")
+
+// ParseLimits bounds the transactions and PSBTs accepted from clients. The
+// defaults follow Bitcoin Core's standardness rules, so anything that would
+// not relay is refused before it reaches the contract logic.
+type ParseLimits struct {
+	// MaxTxSize caps the serialized size of a transaction in bytes
+	MaxTxSize int
+	// MaxPSBTSize caps the serialized size of a PSBT in bytes
+	MaxPSBTSize int
+	// MaxInputs and MaxOutputs cap the inputs and outputs of a transaction
+	MaxInputs  int
+	MaxOutputs int
+	// MaxSigScriptSize caps the signature script of each input
+	MaxSigScriptSize int
+	// AllowNonStandard accepts outputs with non-standard scripts
+	AllowNonStandard bool
+}
+
+// DefaultParseLimits accepts standard transactions of up to 100kB
+var DefaultParseLimits = ParseLimits{
+	MaxTxSize:        100_000,
+	MaxPSBTSize:      400_000,
+	MaxInputs:        1000,
+	MaxOutputs:       1000,
+	MaxSigScriptSize: 1650,
+}
+
+// ParseTransactionHex decodes a hex encoded transaction and checks it
+// against the limits. Its size is checked before decoding, and the
+// transaction must use the canonical encoding: no trailing bytes and no
+// superfluous witness data.
+func ParseTransactionHex(txHex string, limits ParseLimits) (*wire.MsgTx, error) {
+	if len(txHex) == 0 {
+		return nil, fmt.Errorf("%w: empty transaction", ErrMalformedTransaction)
+	}
+	if len(txHex) > 2*limits.MaxTxSize {
+		return nil, fmt.Errorf("%w: transaction exceeds %d bytes", ErrMalformedTransaction, limits.MaxTxSize)
+	}
+
+	raw, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid hex: %v", ErrMalformedTransaction, err)
+	}
+
+	reader := bytes.NewReader(raw)
+	var tx wire.MsgTx
+	if err := tx.Deserialize(reader); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedTransaction, err)
+	}
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformedTransaction, reader.Len())
+	}
+
+	// Serializing again must give back the same bytes, which rejects a
+	// witness marker on a transaction without witnesses
+	var canonical bytes.Buffer
+	if err := tx.Serialize(&canonical); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedTransaction, err)
+	}
+	if !bytes.Equal(canonical.Bytes(), raw) {
+		return nil, fmt.Errorf("%w: non-canonical encoding", ErrMalformedTransaction)
+	}
+
+	if err := checkTransaction(&tx, limits); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// ParsePSBT decodes a base64 encoded PSBT and checks its unsigned
+// transaction against the limits
+func ParsePSBT(encoded string, limits ParseLimits) (*psbt.Packet, error) {
+	if len(encoded) == 0 {
+		return nil, fmt.Errorf("%w: empty PSBT", ErrMalformedTransaction)
+	}
+	if base64.StdEncoding.DecodedLen(len(encoded)) > limits.MaxPSBTSize {
+		return nil, fmt.Errorf("%w: PSBT exceeds %d bytes", ErrMalformedTransaction, limits.MaxPSBTSize)
+	}
+
+	raw, err := base64.StdEncoding.Strict().DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64: %v", ErrMalformedTransaction, err)
+	}
+
+	reader := bytes.NewReader(raw)
+	packet, err := psbt.NewFromRawBytes(reader, false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedTransaction, err)
+	}
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformedTransaction, reader.Len())
+	}
+
+	if err := checkTransaction(packet.UnsignedTx, limits); err != nil {
+		return nil, err
+	}
+
+	return packet, nil
+}
+
+// checkTransaction applies the limits to a decoded transaction
+func checkTransaction(tx *wire.MsgTx, limits ParseLimits) error {
+	if len(tx.TxIn) == 0 {
+		return fmt.Errorf("%w: no inputs", ErrMalformedTransaction)
+	}
+	if len(tx.TxOut) == 0 {
+		return fmt.Errorf("%w: no outputs", ErrMalformedTransaction)
+	}
+	if len(tx.TxIn) > limits.MaxInputs {
+		return fmt.Errorf("%w: %d inputs exceed the limit of %d", ErrMalformedTransaction, len(tx.TxIn), limits.MaxInputs)
+	}
+	if len(tx.TxOut) > limits.MaxOutputs {
+		return fmt.Errorf("%w: %d outputs exceed the limit of %d", ErrMalformedTransaction, len(tx.TxOut), limits.MaxOutputs)
+	}
+
+	spent := make(map[wire.OutPoint]bool, len(tx.TxIn))
+	for i, in := range tx.TxIn {
+		if spent[in.PreviousOutPoint] {
+			return fmt.Errorf("%w: input %d spends %s twice", ErrMalformedTransaction, i, in.PreviousOutPoint)
+		}
+		spent[in.PreviousOutPoint] = true
+
+		if len(in.SignatureScript) > limits.MaxSigScriptSize {
+			return fmt.Errorf("%w: input %d signature script exceeds %d bytes", ErrMalformedTransaction, i, limits.MaxSigScriptSize)
+		}
+	}
+
+	var total int64
+	for i, out := range tx.TxOut {
+		if out.Value < 0 || out.Value > btcutil.MaxSatoshi {
+			return fmt.Errorf("%w: output %d has invalid value %d", ErrMalformedTransaction, i, out.Value)
+		}
+		total += out.Value
+		if total > btcutil.MaxSatoshi {
+			return fmt.Errorf("%w: outputs exceed the supply", ErrMalformedTransaction)
+		}
+
+		if len(out.PkScript) > txscript.MaxScriptSize {
+			return fmt.Errorf("%w: output %d script exceeds %d bytes", ErrMalformedTransaction, i, txscript.MaxScriptSize)
+		}
+		if !limits.AllowNonStandard && txscript.GetScriptClass(out.PkScript) == txscript.NonStandardTy {
+			return fmt.Errorf("%w: output %d has a non-standard script", ErrMalformedTransaction, i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// p2wpkh is a standard pay to witness public key hash script
+var p2wpkh = append([]byte{0x00, 0x14}, make([]byte, 20)...)
+
+func testTx(inputs int, scripts ...[]byte) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	for i := 0; i < inputs; i++ {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, uint32(i)), nil, nil))
+	}
+	for _, script := range scripts {
+		tx.AddTxOut(wire.NewTxOut(1000, script))
+	}
+	return tx
+}
+
+func serialize(t *testing.T, tx *wire.MsgTx) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, tx.Serialize(&buf))
+	return buf.Bytes()
+}
+
+func TestParseTransactionHex(t *testing.T) {
+	tx := testTx(1, p2wpkh)
+	raw := serialize(t, tx)
+
+	parsed, err := ParseTransactionHex(hex.EncodeToString(raw), DefaultParseLimits)
+	require.NoError(t, err)
+	assert.Equal(t, tx.TxHash(), parsed.TxHash())
+
+	tests := map[string]string{
+		"empty":          "",
+		"not hex":        "zz",
+		"truncated":      hex.EncodeToString(raw[:len(raw)-2]),
+		"trailing bytes": hex.EncodeToString(append(raw, 0x00)),
+		"no outputs":     hex.EncodeToString(serialize(t, testTx(1))),
+		"non-standard":   hex.EncodeToString(serialize(t, testTx(1, []byte{0xff}))),
+	}
+	for name, txHex := range tests {
+		_, err := ParseTransactionHex(txHex, DefaultParseLimits)
+		assert.ErrorIs(t, err, ErrMalformedTransaction, name)
+	}
+
+	// A witness marker without witness data is not the canonical encoding
+	withMarker := append([]byte{}, raw[:4]...)
+	withMarker = append(withMarker, 0x00, 0x01)
+	withMarker = append(withMarker, raw[4:len(raw)-4]...)
+	withMarker = append(withMarker, 0x00)
+	withMarker = append(withMarker, raw[len(raw)-4:]...)
+	_, err = ParseTransactionHex(hex.EncodeToString(withMarker), DefaultParseLimits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+}
+
+func TestParseTransactionLimits(t *testing.T) {
+	limits := DefaultParseLimits
+	limits.MaxInputs = 2
+	limits.MaxOutputs = 2
+
+	_, err := ParseTransactionHex(hex.EncodeToString(serialize(t, testTx(3, p2wpkh))), limits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+
+	_, err = ParseTransactionHex(hex.EncodeToString(serialize(t, testTx(1, p2wpkh, p2wpkh, p2wpkh))), limits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+
+	limits.MaxTxSize = 10
+	_, err = ParseTransactionHex(hex.EncodeToString(serialize(t, testTx(1, p2wpkh))), limits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+
+	// Spending an outpoint twice
+	tx := testTx(1, p2wpkh)
+	tx.AddTxIn(wire.NewTxIn(&tx.TxIn[0].PreviousOutPoint, nil, nil))
+	_, err = ParseTransactionHex(hex.EncodeToString(serialize(t, tx)), DefaultParseLimits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+
+	// Non-standard outputs pass when allowed
+	limits = DefaultParseLimits
+	limits.AllowNonStandard = true
+	_, err = ParseTransactionHex(hex.EncodeToString(serialize(t, testTx(1, []byte{0xff}))), limits)
+	assert.NoError(t, err)
+}
+
+func TestParsePSBT(t *testing.T) {
+	packet, err := psbt.NewFromUnsignedTx(testTx(1, p2wpkh))
+	require.NoError(t, err)
+	encoded, err := packet.B64Encode()
+	require.NoError(t, err)
+
+	parsed, err := ParsePSBT(encoded, DefaultParseLimits)
+	require.NoError(t, err)
+	assert.Equal(t, packet.UnsignedTx.TxHash(), parsed.UnsignedTx.TxHash())
+
+	for name, encoded := range map[string]string{
+		"empty":      "",
+		"not base64": "not base64!",
+		"not a psbt": "aGVsbG8gd29ybGQ=",
+	} {
+		_, err := ParsePSBT(encoded, DefaultParseLimits)
+		assert.ErrorIs(t, err, ErrMalformedTransaction, name)
+	}
+
+	limits := DefaultParseLimits
+	limits.MaxPSBTSize = 10
+	_, err = ParsePSBT(encoded, limits)
+	assert.ErrorIs(t, err, ErrMalformedTransaction)
+}
@@ -0,0 +1,384 @@
+package bitcoin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/rpcclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"hashhedge/internal/logging"
+	"hashhedge/internal/metrics"
+	"hashhedge/internal/tracing"
+)
+
+var tracer = tracing.Tracer(logging.Bitcoin)
+
+// ErrNoHealthyNode is returned when the circuit of every node is open
+var ErrNoHealthyNode = errors.New("no Bitcoin node is available")
+
+// Node is the RPC endpoint of a Bitcoin Core node
+type Node struct {
+	Host     string
+	User     string
+	Password string
+	UseTLS   bool
+}
+
+// PoolOptions tunes the connections to each node
+type PoolOptions struct {
+	// Size is the number of RPC connections opened to each node
+	Size int
+	// CallTimeout bounds every RPC call, on top of the caller's context
+	CallTimeout time.Duration
+	// FailureThreshold is the number of consecutive failures that open a
+	// node's circuit, sending calls to the next node
+	FailureThreshold int
+	// Cooldown is how long an open circuit refuses calls before one is let
+	// through to probe the node
+	Cooldown time.Duration
+}
+
+// DefaultPoolOptions suit a node on the local network
+var DefaultPoolOptions = PoolOptions{
+	Size:             4,
+	CallTimeout:      30 * time.Second,
+	FailureThreshold: 3,
+	Cooldown:         30 * time.Second,
+}
+
+// Circuit states reported by health checks
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// breaker opens a node's circuit after consecutive failures. Once the
+// cooldown has passed a single call probes the node, closing the circuit if
+// it succeeds.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a call may be sent to the node
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Sub(b.openedAt) < b.cooldown || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// success closes the circuit
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+// failure counts a failed call, opening the circuit at the threshold
+func (b *breaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		b.openedAt = now
+	}
+}
+
+// state names the state of the circuit
+func (b *breaker) state(now time.Time) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.failures < b.threshold:
+		return CircuitClosed
+	case now.Sub(b.openedAt) < b.cooldown:
+		return CircuitOpen
+	default:
+		return CircuitHalfOpen
+	}
+}
+
+// NodeHealth is the result of the last health check of a node
+type NodeHealth struct {
+	Name        string    `json:"name"`
+	Healthy     bool      `json:"healthy"`
+	Circuit     string    `json:"circuit"`
+	BlockHeight int64     `json:"block_height,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+// node is a pool of RPC connections to one Bitcoin Core node
+type node struct {
+	name    string
+	conns   []*rpcclient.Client
+	next    uint32
+	breaker breaker
+
+	mu     sync.Mutex
+	health NodeHealth
+}
+
+// conn returns the connections of the pool in turn
+func (n *node) conn() *rpcclient.Client {
+	i := atomic.AddUint32(&n.next, 1)
+	return n.conns[int(i)%len(n.conns)]
+}
+
+// newNode opens a pool of connections to a node
+func newNode(name string, cfg Node, opts PoolOptions, proxy *url.URL) (*node, error) {
+	connCfg := &rpcclient.ConnConfig{
+		Host:         cfg.Host,
+		User:         cfg.User,
+		Pass:         cfg.Password,
+		HTTPPostMode: true,
+		DisableTLS:   !cfg.UseTLS,
+	}
+	if proxy != nil {
+		connCfg.Proxy = proxy.Host
+		if proxy.User != nil {
+			connCfg.ProxyUser = proxy.User.Username()
+			connCfg.ProxyPass, _ = proxy.User.Password()
+		}
+	}
+
+	n := &node{
+		name:    name,
+		breaker: breaker{threshold: opts.FailureThreshold, cooldown: opts.Cooldown},
+		health:  NodeHealth{Name: name, Healthy: true, Circuit: CircuitClosed},
+	}
+	for i := 0; i < opts.Size; i++ {
+		conn, err := rpcclient.New(connCfg, nil)
+		if err != nil {
+			n.close()
+			return nil, fmt.Errorf("failed to create Bitcoin RPC client for %s node: %w", name, err)
+		}
+		n.conns = append(n.conns, conn)
+	}
+	return n, nil
+}
+
+// close shuts down every connection of the pool
+func (n *node) close() {
+	for _, conn := range n.conns {
+		conn.Shutdown()
+	}
+}
+
+// NewPooledClient creates a Bitcoin client keeping a pool of connections to
+// each node. Calls go to the first node whose circuit is closed, so later
+// nodes serve as failovers. The proxy, if not nil, is used for every node.
+func NewPooledClient(nodes []Node, opts PoolOptions, proxy *url.URL) (*Client, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no Bitcoin node configured")
+	}
+	if opts.Size <= 0 {
+		opts.Size = 1
+	}
+
+	c := &Client{opts: opts, broadcastRetry: DefaultBroadcastRetry}
+	for i, cfg := range nodes {
+		name := "primary"
+		if i > 0 {
+			name = fmt.Sprintf("failover-%d", i)
+		}
+		n, err := newNode(name, cfg, opts, proxy)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		c.nodes = append(c.nodes, n)
+	}
+	return c, nil
+}
+
+// rpcCall makes one RPC on a connection. It returns its result rather than
+// setting the caller's variables, since a call given up on may still finish
+// after the caller has moved on to another node.
+type rpcCall func(rpc *rpcclient.Client) (interface{}, error)
+
+// answered reports whether the node answered a call with an error, as
+// opposed to not answering at all. An answered error says nothing about the
+// node's health and would be given by any other node.
+func answered(err error) bool {
+	var rpcErr *btcjson.RPCError
+	return errors.As(err, &rpcErr)
+}
+
+// call runs an RPC on the first node whose circuit admits it, failing over
+// to the next when the node cannot be reached
+func (c *Client) call(ctx context.Context, method string, fn rpcCall) (interface{}, error) {
+	return c.callOn(ctx, method, c.nodes, fn)
+}
+
+// callWallet runs a wallet RPC on the primary node alone: a failover has
+// another wallet, and a call that timed out may still have been made
+func (c *Client) callWallet(ctx context.Context, method string, fn rpcCall) (interface{}, error) {
+	return c.callOn(ctx, method, c.nodes[:1], fn)
+}
+
+func (c *Client) callOn(ctx context.Context, method string, nodes []*node, fn rpcCall) (interface{}, error) {
+	ctx, span := tracing.StartChild(ctx, tracer, "bitcoind "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("rpc.system", "jsonrpc"), attribute.String("rpc.method", method)))
+
+	var result interface{}
+	err := ErrNoHealthyNode
+	for _, n := range nodes {
+		if !n.breaker.allow(time.Now()) {
+			continue
+		}
+
+		result, err = c.do(ctx, n, fn)
+		if err == nil || answered(err) {
+			n.breaker.success()
+			span.SetAttributes(attribute.String("bitcoin.node", n.name))
+			break
+		}
+		if ctx.Err() != nil {
+			// The caller gave up, which says nothing about the node
+			break
+		}
+
+		n.breaker.failure(time.Now())
+		metrics.BitcoinRPCFailovers.WithLabelValues(n.name).Inc()
+		span.AddEvent("failover", trace.WithAttributes(attribute.String("bitcoin.node", n.name)))
+		err = fmt.Errorf("%s node: %w", n.name, err)
+	}
+
+	tracing.End(span, err)
+	if err != nil {
+		rpcFailed(method)
+		return nil, err
+	}
+	return result, nil
+}
+
+// do runs an RPC on a node, giving up after the call timeout. rpcclient
+// ignores contexts, so a call given up on finishes in the background.
+func (c *Client) do(ctx context.Context, n *node, fn rpcCall) (interface{}, error) {
+	if c.opts.CallTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.CallTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan outcome, 1)
+	conn := n.conn()
+	go func() {
+		result, err := fn(conn)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// StartHealthChecks checks every node on interval until ctx is cancelled. A
+// node that answers closes its circuit, so a recovered primary takes calls
+// back without waiting for a probe.
+func (c *Client) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			c.checkNodes(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkNodes asks each node for its block count
+func (c *Client) checkNodes(ctx context.Context) {
+	for _, n := range c.nodes {
+		result, err := c.do(ctx, n, func(rpc *rpcclient.Client) (interface{}, error) {
+			return rpc.GetBlockCountAsync().Receive()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		now := time.Now()
+		var height int64
+		if err == nil {
+			height = result.(int64)
+			n.breaker.success()
+			metrics.BitcoinNodeUp.WithLabelValues(n.name).Set(1)
+		} else {
+			n.breaker.failure(now)
+			metrics.BitcoinNodeUp.WithLabelValues(n.name).Set(0)
+			logger.Warn().Err(err).Str("node", n.name).Msg("Bitcoin node health check failed")
+		}
+
+		n.mu.Lock()
+		n.health = NodeHealth{
+			Name:        n.name,
+			Healthy:     err == nil,
+			Circuit:     n.breaker.state(now),
+			BlockHeight: height,
+			CheckedAt:   now,
+		}
+		n.mu.Unlock()
+	}
+}
+
+// Health returns the last health check of each node, primary first
+func (c *Client) Health() []NodeHealth {
+	health := make([]NodeHealth, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		n.mu.Lock()
+		h := n.health
+		n.mu.Unlock()
+		h.Circuit = n.breaker.state(time.Now())
+		health = append(health, h)
+	}
+	return health
+}
+
+// Healthy reports whether any node passed its last health check
+func (c *Client) Healthy() bool {
+	for _, h := range c.Health() {
+		if h.Healthy {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package bitcoin
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient builds a client over nodes without connections, for calls that
+// never touch the connection
+func testClient(names ...string) *Client {
+	c := &Client{opts: PoolOptions{CallTimeout: 50 * time.Millisecond}}
+	for _, name := range names {
+		c.nodes = append(c.nodes, &node{
+			name:    name,
+			conns:   []*rpcclient.Client{nil},
+			breaker: breaker{threshold: 2, cooldown: time.Minute},
+		})
+	}
+	return c
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := breaker{threshold: 2, cooldown: time.Minute}
+
+	b.failure(now)
+	assert.True(t, b.allow(now))
+	assert.Equal(t, CircuitClosed, b.state(now))
+
+	b.failure(now)
+	assert.False(t, b.allow(now))
+	assert.Equal(t, CircuitOpen, b.state(now))
+
+	// After the cooldown a single call probes the node
+	later := now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, b.state(later))
+	assert.True(t, b.allow(later))
+	assert.False(t, b.allow(later))
+
+	b.success()
+	assert.True(t, b.allow(later))
+	assert.Equal(t, CircuitClosed, b.state(later))
+}
+
+func TestCallFailover(t *testing.T) {
+	c := testClient("primary", "failover-1")
+
+	var tried []string
+	unreachable := func(name string) rpcCall {
+		return func(rpc *rpcclient.Client) (interface{}, error) {
+			tried = append(tried, name)
+			return nil, errors.New("connection refused")
+		}
+	}
+
+	// Unreachable nodes are failed over from until their circuits open
+	_, err := c.call(context.Background(), "getblockcount", unreachable("any"))
+	assert.ErrorContains(t, err, "failover-1 node")
+	assert.Len(t, tried, 2)
+
+	_, err = c.call(context.Background(), "getblockcount", unreachable("any"))
+	assert.Error(t, err)
+	_, err = c.call(context.Background(), "getblockcount", unreachable("any"))
+	assert.ErrorIs(t, err, ErrNoHealthyNode)
+	assert.Len(t, tried, 4)
+
+	// An error the node answers with is not a reason to fail over
+	c = testClient("primary", "failover-1")
+	calls := 0
+	_, err = c.call(context.Background(), "getrawtransaction", func(rpc *rpcclient.Client) (interface{}, error) {
+		calls++
+		return nil, &btcjson.RPCError{Code: btcjson.ErrRPCNoTxInfo, Message: "No such mempool or blockchain transaction"}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, c.nodes[0].breaker.failures)
+}
+
+func TestCallTimeout(t *testing.T) {
+	c := testClient("primary", "failover-1")
+
+	// The primary hangs past the call timeout
+	var calls int32
+	result, err := c.call(context.Background(), "getblockcount", func(rpc *rpcclient.Client) (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(time.Second)
+			return int64(1), nil
+		}
+		return int64(2), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result)
+	assert.Equal(t, 1, c.nodes[0].breaker.failures)
+}
+
+func TestCallWalletStaysOnPrimary(t *testing.T) {
+	c := testClient("primary", "failover-1")
+
+	calls := 0
+	_, err := c.callWallet(context.Background(), "sendtoaddress", func(rpc *rpcclient.Client) (interface{}, error) {
+		calls++
+		return nil, errors.New("connection refused")
+	})
+	assert.ErrorContains(t, err, "primary node")
+	assert.Equal(t, 1, calls)
+}
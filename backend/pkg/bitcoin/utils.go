@@ -13,20 +13,59 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+
+	"hashhedge/internal/logging"
 )
 
+var logger = logging.Component(logging.Bitcoin)
+
+// BroadcastRetry sets how often a broadcast the node rejects is retried
+type BroadcastRetry struct {
+	// Attempts is the number of broadcasts made before giving up
+	Attempts int `yaml:"attempts"`
+	// Backoff is the wait after the first failed attempt, doubling with
+	// each further one
+	Backoff time.Duration `yaml:"backoff"`
+}
+
+// DefaultBroadcastRetry gives a transaction three attempts over three seconds
+var DefaultBroadcastRetry = BroadcastRetry{
+	Attempts: 3,
+	Backoff:  time.Second,
+}
+
+// Validate checks that at least one attempt is made
+func (r BroadcastRetry) Validate() error {
+	if r.Attempts <= 0 {
+		return fmt.Errorf("Bitcoin broadcast attempts must be positive")
+	}
+	if r.Backoff < 0 {
+		return fmt.Errorf("Bitcoin broadcast backoff cannot be negative")
+	}
+	return nil
+}
+
+// SetBroadcastRetry replaces the retry policy of broadcasts, taking effect
+// from the next broadcast
+func (c *Client) SetBroadcastRetry(retry BroadcastRetry) {
+	c.retryMu.Lock()
+	defer c.retryMu.Unlock()
+	c.broadcastRetry = retry
+}
+
+// broadcastRetryPolicy returns the current retry policy of broadcasts
+func (c *Client) broadcastRetryPolicy() BroadcastRetry {
+	c.retryMu.RLock()
+	defer c.retryMu.RUnlock()
+	return c.broadcastRetry
+}
+
 // BroadcastTransactionWithRetry broadcasts a raw transaction to the network with retry logic
 func (c *Client) BroadcastTransactionWithRetry(ctx context.Context, txHex string) (string, error) {
-	// Decode the transaction hex
-	txBytes, err := hex.DecodeString(txHex)
+	// Parse the transaction, refusing anything that would not relay
+	tx, err := ParseTransactionHex(txHex, DefaultParseLimits)
 	if err != nil {
-		return "", fmt.Errorf("failed to decode transaction hex: %w", err)
-	}
-
-	// Deserialize the transaction
-	var tx wire.MsgTx
-	if err := tx.Deserialize(hex.NewDecoder(txBytes)); err != nil {
-		return "", fmt.Errorf("failed to deserialize transaction: %w", err)
+		return "", err
 	}
 
 	// Get transaction ID
@@ -41,13 +80,14 @@ func (c *Client) BroadcastTransactionWithRetry(ctx context.Context, txHex string
 	}
 
 	// Define retry parameters
-	maxRetries := 3
-	retryDelay := 1 * time.Second
+	retry := c.broadcastRetryPolicy()
+	maxRetries := retry.Attempts
+	retryDelay := retry.Backoff
 
 	var lastErr error
 	for i := 0; i < maxRetries; i++ {
 		// Attempt to broadcast
-		txHash, err := c.SendRawTransaction(ctx, &tx, false)
+		txHash, err := c.SendRawTransaction(ctx, tx, false)
 		if err == nil {
 			return txHash.String(), nil
 		}
@@ -59,6 +99,12 @@ func (c *Client) BroadcastTransactionWithRetry(ctx context.Context, txHex string
 		
 		// If there's a different error, retry after delay
 		lastErr = err
+		logger.Debug().
+			Err(err).
+			Str("txid", txid).
+			Int("attempt", i+1).
+			Dur("backoff", retryDelay).
+			Msg("Retrying transaction broadcast")
 		time.Sleep(retryDelay)
 		retryDelay *= 2 // Exponential backoff
 	}
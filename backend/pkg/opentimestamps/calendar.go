@@ -0,0 +1,100 @@
+// pkg/opentimestamps/calendar.go
+package opentimestamps
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotUpgraded is returned when a calendar has not yet committed a
+// message to Bitcoin
+var ErrNotUpgraded = errors.New("timestamp not yet committed to Bitcoin")
+
+// maxResponseSize bounds the timestamps accepted from a calendar
+const maxResponseSize = 10000
+
+// Calendar is a client of an OpenTimestamps calendar server
+type Calendar struct {
+	url    string
+	client *http.Client
+}
+
+// NewCalendar creates a client of the calendar at url
+func NewCalendar(url string) *Calendar {
+	return &Calendar{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// URL returns the calendar's address
+func (c *Calendar) URL() string {
+	return c.url
+}
+
+// Submit asks the calendar to timestamp a digest. The returned timestamp
+// usually ends in a pending attestation to upgrade once the calendar's next
+// transaction confirms.
+func (c *Calendar) Submit(ctx context.Context, digest []byte) (*Timestamp, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/digest", bytes.NewReader(digest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar request: %w", err)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return Deserialize(digest, body)
+}
+
+// Upgrade asks the calendar for the timestamp of a commitment it attested as
+// pending, returning ErrNotUpgraded until it reaches Bitcoin
+func (c *Calendar) Upgrade(ctx context.Context, commitment []byte) (*Timestamp, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/timestamp/"+hex.EncodeToString(commitment), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar request: %w", err)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return Deserialize(commitment, body)
+}
+
+func (c *Calendar) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Accept", "application/vnd.opentimestamps.v1")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calendar %s request failed: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+		return nil, ErrNotUpgraded
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("calendar %s returned status %d", c.url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar %s response: %w", c.url, err)
+	}
+	if len(body) > maxResponseSize {
+		return nil, fmt.Errorf("calendar %s response exceeds %d bytes", c.url, maxResponseSize)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,64 @@
+// pkg/opentimestamps/file.go
+package opentimestamps
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// fileMagic opens every detached timestamp file
+var fileMagic = []byte("\x00OpenTimestamps\x00\x00Proof\x00\xbf\x89\xe2\xe8\x84\xe8\x92\x94")
+
+// fileVersion is the detached timestamp file format version
+const fileVersion = 1
+
+// WriteDetached encodes a timestamp of a SHA-256 digest as a .ots file that
+// the OpenTimestamps client can verify against the original data
+func WriteDetached(ts *Timestamp) ([]byte, error) {
+	if len(ts.Msg) != sha256.Size {
+		return nil, fmt.Errorf("detached timestamps must be of a SHA-256 digest")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(fileMagic)
+	writeVarUint(&buf, fileVersion)
+	buf.WriteByte(opSHA256)
+	buf.Write(ts.Msg)
+	ts.write(&buf)
+	return buf.Bytes(), nil
+}
+
+// ReadDetached decodes a .ots file of a SHA-256 digest
+func ReadDetached(data []byte) (*Timestamp, error) {
+	if !bytes.HasPrefix(data, fileMagic) {
+		return nil, fmt.Errorf("%w: not a timestamp file", ErrMalformed)
+	}
+
+	r := bytes.NewReader(data[len(fileMagic):])
+	version, err := readVarUint(r)
+	if err != nil {
+		return nil, err
+	}
+	if version != fileVersion {
+		return nil, fmt.Errorf("%w: unsupported file version %d", ErrMalformed, version)
+	}
+
+	op, err := readByte(r)
+	if err != nil {
+		return nil, err
+	}
+	if op != opSHA256 {
+		return nil, fmt.Errorf("%w: unsupported file hash 0x%02x", ErrMalformed, op)
+	}
+
+	digest := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, digest); err != nil {
+		return nil, fmt.Errorf("%w: truncated digest", ErrMalformed)
+	}
+
+	rest := make([]byte, r.Len())
+	r.Read(rest)
+	return Deserialize(digest, rest)
+}
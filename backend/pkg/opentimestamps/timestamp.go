@@ -0,0 +1,424 @@
+// pkg/opentimestamps/timestamp.go
+package opentimestamps
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// ErrMalformed is returned when a serialized timestamp cannot be parsed
+var ErrMalformed = errors.New("malformed timestamp")
+
+const (
+	tagAttestation = 0x00
+	tagFork        = 0xff
+
+	opSHA1      = 0x02
+	opRIPEMD160 = 0x03
+	opSHA256    = 0x08
+	opAppend    = 0xf0
+	opPrepend   = 0xf1
+	opReverse   = 0xf2
+	opHexlify   = 0xf3
+
+	// maxMessageLength bounds every message and operation argument
+	maxMessageLength = 4096
+	// maxPayloadLength bounds the payload of an attestation
+	maxPayloadLength = 8192
+	// maxDepth bounds the nesting of operations
+	maxDepth = 256
+)
+
+var (
+	bitcoinTag = [8]byte{0x05, 0x88, 0x96, 0x0d, 0x73, 0xd7, 0x19, 0x01}
+	pendingTag = [8]byte{0x83, 0xdf, 0xe3, 0x0d, 0x2e, 0xf9, 0x0c, 0x8e}
+)
+
+// Op is a commitment operation applied to a message
+type Op struct {
+	Tag byte
+	Arg []byte // Appended or prepended bytes
+}
+
+// SHA256 returns the operation hashing a message with SHA-256
+func SHA256() Op { return Op{Tag: opSHA256} }
+
+// Append returns the operation appending arg to a message
+func Append(arg []byte) Op { return Op{Tag: opAppend, Arg: arg} }
+
+// Prepend returns the operation prepending arg to a message
+func Prepend(arg []byte) Op { return Op{Tag: opPrepend, Arg: arg} }
+
+// Apply returns the result of the operation on a message
+func (o Op) Apply(msg []byte) ([]byte, error) {
+	var result []byte
+	switch o.Tag {
+	case opSHA1:
+		sum := sha1.Sum(msg)
+		result = sum[:]
+	case opRIPEMD160:
+		h := ripemd160.New()
+		h.Write(msg)
+		result = h.Sum(nil)
+	case opSHA256:
+		sum := sha256.Sum256(msg)
+		result = sum[:]
+	case opAppend:
+		result = append(append([]byte{}, msg...), o.Arg...)
+	case opPrepend:
+		result = append(append([]byte{}, o.Arg...), msg...)
+	case opReverse:
+		result = make([]byte, len(msg))
+		for i, b := range msg {
+			result[len(msg)-1-i] = b
+		}
+	case opHexlify:
+		result = []byte(hex.EncodeToString(msg))
+	default:
+		return nil, fmt.Errorf("%w: unsupported operation 0x%02x", ErrMalformed, o.Tag)
+	}
+
+	if len(result) > maxMessageLength {
+		return nil, fmt.Errorf("%w: message exceeds %d bytes", ErrMalformed, maxMessageLength)
+	}
+	return result, nil
+}
+
+func (o Op) equal(other Op) bool {
+	return o.Tag == other.Tag && bytes.Equal(o.Arg, other.Arg)
+}
+
+// Attestation is a claim that a message existed at some time
+type Attestation struct {
+	Tag     [8]byte
+	Payload []byte
+}
+
+// BitcoinAttestation returns an attestation that the message is the merkle
+// root of the Bitcoin block at height
+func BitcoinAttestation(height int64) Attestation {
+	var payload bytes.Buffer
+	writeVarUint(&payload, uint64(height))
+	return Attestation{Tag: bitcoinTag, Payload: payload.Bytes()}
+}
+
+// PendingAttestation returns an attestation that the calendar at uri will
+// later commit the message to Bitcoin
+func PendingAttestation(uri string) Attestation {
+	var payload bytes.Buffer
+	writeVarBytes(&payload, []byte(uri))
+	return Attestation{Tag: pendingTag, Payload: payload.Bytes()}
+}
+
+// BitcoinHeight returns the block height of a Bitcoin attestation
+func (a Attestation) BitcoinHeight() (int64, bool) {
+	if a.Tag != bitcoinTag {
+		return 0, false
+	}
+	height, err := readVarUint(bytes.NewReader(a.Payload))
+	if err != nil || height > 1<<62 {
+		return 0, false
+	}
+	return int64(height), true
+}
+
+// PendingURI returns the calendar of a pending attestation
+func (a Attestation) PendingURI() (string, bool) {
+	if a.Tag != pendingTag {
+		return "", false
+	}
+	uri, err := readVarBytes(bytes.NewReader(a.Payload), maxPayloadLength)
+	if err != nil {
+		return "", false
+	}
+	return string(uri), true
+}
+
+func (a Attestation) equal(other Attestation) bool {
+	return a.Tag == other.Tag && bytes.Equal(a.Payload, other.Payload)
+}
+
+// Branch is an operation and the timestamp of its result
+type Branch struct {
+	Op        Op
+	Timestamp *Timestamp
+}
+
+// Timestamp is a tree of operations proving that Msg existed, with the
+// attestations made about each intermediate message
+type Timestamp struct {
+	Msg          []byte
+	Attestations []Attestation
+	Branches     []Branch
+}
+
+// NewTimestamp returns an empty timestamp of a message
+func NewTimestamp(msg []byte) *Timestamp {
+	return &Timestamp{Msg: msg}
+}
+
+// Add applies op to the timestamp's message and returns the timestamp of the result
+func (ts *Timestamp) Add(op Op) (*Timestamp, error) {
+	result, err := op.Apply(ts.Msg)
+	if err != nil {
+		return nil, err
+	}
+	child := NewTimestamp(result)
+	ts.Branches = append(ts.Branches, Branch{Op: op, Timestamp: child})
+	return child, nil
+}
+
+// Attest records an attestation about the timestamp's message
+func (ts *Timestamp) Attest(a Attestation) {
+	for _, existing := range ts.Attestations {
+		if existing.equal(a) {
+			return
+		}
+	}
+	ts.Attestations = append(ts.Attestations, a)
+}
+
+// Merge adds the attestations and branches of another timestamp of the same
+// message, such as one upgraded by a calendar
+func (ts *Timestamp) Merge(other *Timestamp) error {
+	if !bytes.Equal(ts.Msg, other.Msg) {
+		return errors.New("cannot merge timestamps of different messages")
+	}
+
+	for _, a := range other.Attestations {
+		ts.Attest(a)
+	}
+
+	for _, branch := range other.Branches {
+		merged := false
+		for _, existing := range ts.Branches {
+			if existing.Op.equal(branch.Op) {
+				if err := existing.Timestamp.Merge(branch.Timestamp); err != nil {
+					return err
+				}
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			ts.Branches = append(ts.Branches, branch)
+		}
+	}
+
+	return nil
+}
+
+// BlockAttestation is a Bitcoin attestation and the message it commits
+type BlockAttestation struct {
+	Height int64
+	Msg    []byte
+}
+
+// Matches reports whether the attested message is the block's merkle root
+func (a BlockAttestation) Matches(merkleRoot *chainhash.Hash) bool {
+	return bytes.Equal(a.Msg, merkleRoot[:])
+}
+
+// CalendarAttestation is a pending attestation and the timestamp the
+// calendar will upgrade
+type CalendarAttestation struct {
+	URI       string
+	Timestamp *Timestamp
+}
+
+// BitcoinAttestations returns every Bitcoin attestation in the tree
+func (ts *Timestamp) BitcoinAttestations() []BlockAttestation {
+	var found []BlockAttestation
+	ts.walk(func(node *Timestamp) {
+		for _, a := range node.Attestations {
+			if height, ok := a.BitcoinHeight(); ok {
+				found = append(found, BlockAttestation{Height: height, Msg: node.Msg})
+			}
+		}
+	})
+	return found
+}
+
+// PendingAttestations returns every pending calendar attestation in the tree
+func (ts *Timestamp) PendingAttestations() []CalendarAttestation {
+	var found []CalendarAttestation
+	ts.walk(func(node *Timestamp) {
+		for _, a := range node.Attestations {
+			if uri, ok := a.PendingURI(); ok {
+				found = append(found, CalendarAttestation{URI: uri, Timestamp: node})
+			}
+		}
+	})
+	return found
+}
+
+func (ts *Timestamp) walk(fn func(*Timestamp)) {
+	fn(ts)
+	for _, branch := range ts.Branches {
+		branch.Timestamp.walk(fn)
+	}
+}
+
+// Serialize encodes the timestamp without its message
+func (ts *Timestamp) Serialize() []byte {
+	var buf bytes.Buffer
+	ts.write(&buf)
+	return buf.Bytes()
+}
+
+func (ts *Timestamp) write(buf *bytes.Buffer) {
+	items := len(ts.Attestations) + len(ts.Branches)
+	written := 0
+	// Every item but the last is preceded by a fork
+	next := func() {
+		if written < items-1 {
+			buf.WriteByte(tagFork)
+		}
+		written++
+	}
+
+	for _, a := range ts.Attestations {
+		next()
+		buf.WriteByte(tagAttestation)
+		buf.Write(a.Tag[:])
+		writeVarBytes(buf, a.Payload)
+	}
+
+	for _, branch := range ts.Branches {
+		next()
+		buf.WriteByte(branch.Op.Tag)
+		if branch.Op.Tag == opAppend || branch.Op.Tag == opPrepend {
+			writeVarBytes(buf, branch.Op.Arg)
+		}
+		branch.Timestamp.write(buf)
+	}
+}
+
+// Deserialize decodes a timestamp of msg
+func Deserialize(msg, data []byte) (*Timestamp, error) {
+	r := bytes.NewReader(data)
+	ts, err := readTimestamp(r, msg, 0)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrMalformed, r.Len())
+	}
+	return ts, nil
+}
+
+func readTimestamp(r *bytes.Reader, msg []byte, depth int) (*Timestamp, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("%w: operations nested too deeply", ErrMalformed)
+	}
+
+	ts := NewTimestamp(msg)
+	tag, err := readByte(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for tag == tagFork {
+		item, err := readByte(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := ts.readItem(r, item, depth); err != nil {
+			return nil, err
+		}
+		if tag, err = readByte(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ts.readItem(r, tag, depth); err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+func (ts *Timestamp) readItem(r *bytes.Reader, tag byte, depth int) error {
+	if tag == tagAttestation {
+		var a Attestation
+		if _, err := io.ReadFull(r, a.Tag[:]); err != nil {
+			return fmt.Errorf("%w: truncated attestation", ErrMalformed)
+		}
+		payload, err := readVarBytes(r, maxPayloadLength)
+		if err != nil {
+			return err
+		}
+		a.Payload = payload
+		ts.Attestations = append(ts.Attestations, a)
+		return nil
+	}
+
+	op := Op{Tag: tag}
+	if tag == opAppend || tag == opPrepend {
+		arg, err := readVarBytes(r, maxMessageLength)
+		if err != nil {
+			return err
+		}
+		op.Arg = arg
+	}
+
+	result, err := op.Apply(ts.Msg)
+	if err != nil {
+		return err
+	}
+
+	child, err := readTimestamp(r, result, depth+1)
+	if err != nil {
+		return err
+	}
+	ts.Branches = append(ts.Branches, Branch{Op: op, Timestamp: child})
+	return nil
+}
+
+func readByte(r *bytes.Reader) (byte, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, fmt.Errorf("%w: unexpected end of data", ErrMalformed)
+	}
+	return b, nil
+}
+
+// writeVarUint writes an unsigned LEB128 integer
+func writeVarUint(buf *bytes.Buffer, n uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	buf.Write(scratch[:binary.PutUvarint(scratch[:], n)])
+}
+
+func readVarUint(r *bytes.Reader) (uint64, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid integer", ErrMalformed)
+	}
+	return n, nil
+}
+
+func writeVarBytes(buf *bytes.Buffer, b []byte) {
+	writeVarUint(buf, uint64(len(b)))
+	buf.Write(b)
+}
+
+func readVarBytes(r *bytes.Reader, max int) ([]byte, error) {
+	n, err := readVarUint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(max) || n > uint64(r.Len()) {
+		return nil, fmt.Errorf("%w: invalid length %d", ErrMalformed, n)
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return b, nil
+}
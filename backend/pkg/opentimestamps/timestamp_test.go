@@ -0,0 +1,127 @@
+// pkg/opentimestamps/timestamp_test.go
+package opentimestamps
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// calendarTimestamp builds the shape of proof a calendar returns: the digest
+// salted, hashed and committed to a pending attestation
+func calendarTimestamp(t *testing.T, digest []byte) (*Timestamp, *Timestamp) {
+	ts := NewTimestamp(digest)
+	salted, err := ts.Add(Append([]byte{0xde, 0xad, 0xbe, 0xef}))
+	require.NoError(t, err)
+	hashed, err := salted.Add(SHA256())
+	require.NoError(t, err)
+	hashed.Attest(PendingAttestation("https://calendar.example"))
+	return ts, hashed
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	digest := sha256.Sum256([]byte("settlement evidence"))
+	ts, commitment := calendarTimestamp(t, digest[:])
+
+	expected := sha256.Sum256(append(digest[:], 0xde, 0xad, 0xbe, 0xef))
+	assert.Equal(t, expected[:], commitment.Msg)
+
+	decoded, err := Deserialize(digest[:], ts.Serialize())
+	require.NoError(t, err)
+	assert.Equal(t, ts, decoded)
+
+	pending := decoded.PendingAttestations()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "https://calendar.example", pending[0].URI)
+	assert.Equal(t, commitment.Msg, pending[0].Timestamp.Msg)
+	assert.Empty(t, decoded.BitcoinAttestations())
+}
+
+func TestTimestampForks(t *testing.T) {
+	digest := sha256.Sum256([]byte("forked"))
+	ts := NewTimestamp(digest[:])
+	ts.Attest(PendingAttestation("https://a.example"))
+	ts.Attest(PendingAttestation("https://a.example"))
+	left, err := ts.Add(Prepend([]byte{1}))
+	require.NoError(t, err)
+	left.Attest(BitcoinAttestation(850000))
+	right, err := ts.Add(Append([]byte{2}))
+	require.NoError(t, err)
+	right.Attest(PendingAttestation("https://b.example"))
+
+	decoded, err := Deserialize(digest[:], ts.Serialize())
+	require.NoError(t, err)
+	assert.Equal(t, ts, decoded)
+	assert.Len(t, decoded.PendingAttestations(), 2)
+
+	blocks := decoded.BitcoinAttestations()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(850000), blocks[0].Height)
+	assert.Equal(t, append([]byte{1}, digest[:]...), blocks[0].Msg)
+}
+
+func TestTimestampMerge(t *testing.T) {
+	digest := sha256.Sum256([]byte("upgrade"))
+	ts, commitment := calendarTimestamp(t, digest[:])
+
+	// The calendar's upgrade continues from the pending commitment to a block
+	upgraded := NewTimestamp(commitment.Msg)
+	root, err := upgraded.Add(Prepend([]byte{0xaa}))
+	require.NoError(t, err)
+	root, err = root.Add(SHA256())
+	require.NoError(t, err)
+	root.Attest(BitcoinAttestation(850123))
+
+	require.NoError(t, commitment.Merge(upgraded))
+	assert.Error(t, ts.Merge(upgraded))
+
+	blocks := ts.BitcoinAttestations()
+	require.Len(t, blocks, 1)
+	assert.Equal(t, int64(850123), blocks[0].Height)
+
+	merkleRoot, err := chainhash.NewHash(root.Msg)
+	require.NoError(t, err)
+	assert.True(t, blocks[0].Matches(merkleRoot))
+	assert.False(t, blocks[0].Matches(&chainhash.Hash{}))
+
+	// Merging the same upgrade again changes nothing
+	before := ts.Serialize()
+	require.NoError(t, commitment.Merge(upgraded))
+	assert.Equal(t, before, ts.Serialize())
+}
+
+func TestDeserializeMalformed(t *testing.T) {
+	digest := sha256.Sum256([]byte("malformed"))
+	ts, _ := calendarTimestamp(t, digest[:])
+	data := ts.Serialize()
+
+	_, err := Deserialize(digest[:], data[:len(data)-3])
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	_, err = Deserialize(digest[:], append(data, 0x00))
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	_, err = Deserialize(digest[:], []byte{0x67})
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestDetachedFile(t *testing.T) {
+	digest := sha256.Sum256([]byte("detached"))
+	ts, _ := calendarTimestamp(t, digest[:])
+
+	file, err := WriteDetached(ts)
+	require.NoError(t, err)
+
+	decoded, err := ReadDetached(file)
+	require.NoError(t, err)
+	assert.Equal(t, ts, decoded)
+
+	_, err = ReadDetached(file[1:])
+	assert.ErrorIs(t, err, ErrMalformed)
+
+	_, err = WriteDetached(NewTimestamp([]byte("not a digest")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+// pkg/priceindex/config.go
+package priceindex
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config selects the pairs the index quotes and the exchanges it asks
+type Config struct {
+	// Pairs are the currency pairs quoted, e.g. BTC/USD; empty disables the
+	// index
+	Pairs []string `yaml:"pairs"`
+	// Sources are the exchanges prices are read from
+	Sources      []string      `yaml:"sources"`
+	TTL          time.Duration `yaml:"ttl"`
+	MaxDeviation float64       `yaml:"max_deviation"`
+	MinSources   int           `yaml:"min_sources"`
+}
+
+// DefaultConfig quotes BTC/USD from every known exchange
+var DefaultConfig = Config{
+	Pairs:        []string{"BTC/USD"},
+	Sources:      Exchanges,
+	TTL:          DefaultOptions.TTL,
+	MaxDeviation: DefaultOptions.MaxDeviation,
+	MinSources:   DefaultOptions.MinSources,
+}
+
+// Enabled reports whether any pairs are quoted
+func (c Config) Enabled() bool {
+	return len(c.Pairs) > 0
+}
+
+// Options returns the index options of the config
+func (c Config) Options() Options {
+	return Options{TTL: c.TTL, MaxDeviation: c.MaxDeviation, MinSources: c.MinSources}
+}
+
+// ParsePairs parses the configured pairs
+func (c Config) ParsePairs() ([]Pair, error) {
+	pairs := make([]Pair, 0, len(c.Pairs))
+	for _, s := range c.Pairs {
+		pair, err := ParsePair(s)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// Validate checks the pairs, exchanges and thresholds of an enabled index
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if _, err := c.ParsePairs(); err != nil {
+		return err
+	}
+	for _, name := range c.Sources {
+		if _, err := NewSource(name, nil); err != nil {
+			return err
+		}
+	}
+	if c.MinSources < 1 {
+		return fmt.Errorf("price index min sources must be at least 1")
+	}
+	if len(c.Sources) < c.MinSources {
+		return fmt.Errorf("price index has %d sources but needs %d to agree", len(c.Sources), c.MinSources)
+	}
+	if c.MaxDeviation < 0 {
+		return fmt.Errorf("price index max deviation cannot be negative")
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("price index TTL cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// pkg/priceindex/index.go
+package priceindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotEnoughSources is returned when too few exchanges agree on a price
+var ErrNotEnoughSources = errors.New("not enough price sources")
+
+// Pair is a currency pair, such as BTC/USD: the price of one Base in Quote
+type Pair struct {
+	Base  string
+	Quote string
+}
+
+// ParsePair parses a pair written as BASE/QUOTE
+func ParsePair(s string) (Pair, error) {
+	base, quote, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "/")
+	if !ok || base == "" || quote == "" {
+		return Pair{}, fmt.Errorf("invalid currency pair %q, expected BASE/QUOTE", s)
+	}
+	return Pair{Base: base, Quote: quote}, nil
+}
+
+// String writes the pair as BASE/QUOTE
+func (p Pair) String() string {
+	return p.Base + "/" + p.Quote
+}
+
+// Source fetches the last traded price of a pair from one exchange
+type Source interface {
+	Name() string
+	Price(ctx context.Context, pair Pair) (float64, error)
+}
+
+// Quote is the index price of a pair, with the exchanges it was taken from
+// and those whose prices were rejected as outliers
+type Quote struct {
+	Pair     string    `json:"pair"`
+	Price    float64   `json:"price"`
+	Sources  []string  `json:"sources"`
+	Rejected []string  `json:"rejected,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Options control how the index combines and caches prices
+type Options struct {
+	// TTL is how long a quote is served before the exchanges are asked again
+	TTL time.Duration
+	// MaxDeviation is the largest fraction a price may differ from the
+	// median of all prices before it is rejected as an outlier
+	MaxDeviation float64
+	// MinSources is how many exchanges must agree for a quote to be given
+	MinSources int
+}
+
+// DefaultOptions cache quotes for a minute and reject prices more than 2%
+// from the median, needing two exchanges to agree
+var DefaultOptions = Options{
+	TTL:          time.Minute,
+	MaxDeviation: 0.02,
+	MinSources:   2,
+}
+
+// Index is the median price of pairs across several exchanges. Quotes are
+// cached for the TTL, and so are failures, so exchanges that cannot be
+// reached are not asked again on every call.
+type Index struct {
+	sources []Source
+	opts    Options
+
+	mu     sync.Mutex
+	quotes map[Pair]*cached
+}
+
+// cached is the last answer for a pair and when it was fetched
+type cached struct {
+	quote   *Quote
+	err     error
+	fetched time.Time
+}
+
+// New creates a price index over sources
+func New(sources []Source, opts Options) *Index {
+	return &Index{
+		sources: sources,
+		opts:    opts,
+		quotes:  make(map[Pair]*cached),
+	}
+}
+
+// Price returns the index price of a pair, from the cache if it is fresh
+func (i *Index) Price(ctx context.Context, pair Pair) (*Quote, error) {
+	i.mu.Lock()
+	last := i.quotes[pair]
+	i.mu.Unlock()
+	if last != nil && time.Since(last.fetched) < i.opts.TTL {
+		return last.quote, last.err
+	}
+
+	quote, err := i.fetch(ctx, pair)
+
+	i.mu.Lock()
+	i.quotes[pair] = &cached{quote: quote, err: err, fetched: time.Now()}
+	i.mu.Unlock()
+	return quote, err
+}
+
+// sourcePrice is the price one exchange gave
+type sourcePrice struct {
+	source string
+	price  float64
+}
+
+// fetch asks every exchange for the price of a pair at once and combines
+// their answers
+func (i *Index) fetch(ctx context.Context, pair Pair) (*Quote, error) {
+	results := make([]*sourcePrice, len(i.sources))
+	errs := make([]error, len(i.sources))
+
+	var wg sync.WaitGroup
+	for n, source := range i.sources {
+		wg.Add(1)
+		go func(n int, source Source) {
+			defer wg.Done()
+			price, err := source.Price(ctx, pair)
+			if err != nil {
+				errs[n] = fmt.Errorf("%s: %w", source.Name(), err)
+				return
+			}
+			if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+				errs[n] = fmt.Errorf("%s: invalid price %v", source.Name(), price)
+				return
+			}
+			results[n] = &sourcePrice{source: source.Name(), price: price}
+		}(n, source)
+	}
+	wg.Wait()
+
+	var prices []sourcePrice
+	for _, result := range results {
+		if result != nil {
+			prices = append(prices, *result)
+		}
+	}
+
+	quote, err := combine(pair, prices, i.opts)
+	if err != nil {
+		return nil, errors.Join(append([]error{err}, errs...)...)
+	}
+	return quote, nil
+}
+
+// combine rejects prices too far from the median of all prices and returns
+// the median of the rest
+func combine(pair Pair, prices []sourcePrice, opts Options) (*Quote, error) {
+	if len(prices) == 0 || len(prices) < opts.MinSources {
+		return nil, fmt.Errorf("%w for %s: %d of %d needed", ErrNotEnoughSources, pair, len(prices), opts.MinSources)
+	}
+
+	all := make([]float64, len(prices))
+	for n, p := range prices {
+		all[n] = p.price
+	}
+	mid := median(all)
+
+	quote := &Quote{Pair: pair.String(), Time: time.Now().UTC()}
+	var kept []float64
+	for _, p := range prices {
+		if opts.MaxDeviation > 0 && math.Abs(p.price-mid)/mid > opts.MaxDeviation {
+			quote.Rejected = append(quote.Rejected, p.source)
+			continue
+		}
+		kept = append(kept, p.price)
+		quote.Sources = append(quote.Sources, p.source)
+	}
+
+	if len(kept) == 0 || len(kept) < opts.MinSources {
+		return nil, fmt.Errorf("%w for %s: %d agree within %.1f%%, %d needed",
+			ErrNotEnoughSources, pair, len(kept), opts.MaxDeviation*100, opts.MinSources)
+	}
+
+	quote.Price = median(kept)
+	sort.Strings(quote.Sources)
+	sort.Strings(quote.Rejected)
+	return quote, nil
+}
+
+// median returns the middle value of prices, or the mean of the two
+// middle values of an even number
+func median(prices []float64) float64 {
+	sorted := append([]float64(nil), prices...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,106 @@
+// pkg/priceindex/index_test.go
+package priceindex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedSource quotes a fixed price, counting how often it is asked
+type fixedSource struct {
+	name  string
+	price float64
+	err   error
+	calls int
+}
+
+func (s *fixedSource) Name() string { return s.name }
+
+func (s *fixedSource) Price(ctx context.Context, pair Pair) (float64, error) {
+	s.calls++
+	return s.price, s.err
+}
+
+func TestParsePair(t *testing.T) {
+	pair, err := ParsePair("btc/eur")
+	require.NoError(t, err)
+	assert.Equal(t, Pair{Base: "BTC", Quote: "EUR"}, pair)
+	assert.Equal(t, "BTC/EUR", pair.String())
+
+	_, err = ParsePair("BTCUSD")
+	assert.Error(t, err)
+}
+
+func TestIndexRejectsOutliers(t *testing.T) {
+	btcusd := Pair{Base: "BTC", Quote: "USD"}
+	sources := []*fixedSource{
+		{name: "a", price: 100_000},
+		{name: "b", price: 100_400},
+		{name: "c", price: 120_000},
+		{name: "d", err: errors.New("down")},
+	}
+	index := New([]Source{sources[0], sources[1], sources[2], sources[3]}, DefaultOptions)
+
+	quote, err := index.Price(context.Background(), btcusd)
+	require.NoError(t, err)
+	assert.Equal(t, 100_200.0, quote.Price)
+	assert.Equal(t, []string{"a", "b"}, quote.Sources)
+	assert.Equal(t, []string{"c"}, quote.Rejected)
+
+	// Fresh quotes are served from the cache
+	_, err = index.Price(context.Background(), btcusd)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sources[0].calls)
+}
+
+func TestIndexNeedsAgreement(t *testing.T) {
+	a := &fixedSource{name: "a", price: 100_000}
+	index := New([]Source{
+		a,
+		&fixedSource{name: "b", price: 150_000},
+	}, Options{TTL: time.Minute, MaxDeviation: 0.02, MinSources: 2})
+
+	_, err := index.Price(context.Background(), Pair{Base: "BTC", Quote: "USD"})
+	assert.ErrorIs(t, err, ErrNotEnoughSources)
+
+	// Failures are cached too
+	_, err = index.Price(context.Background(), Pair{Base: "BTC", Quote: "USD"})
+	assert.ErrorIs(t, err, ErrNotEnoughSources)
+	assert.Equal(t, 1, a.calls)
+}
+
+func TestExchangeTickers(t *testing.T) {
+	responses := map[string]string{
+		"/v2/prices/BTC-USD/spot": `{"data":{"amount":"100001.5","base":"BTC","currency":"USD"}}`,
+		"/0/public/Ticker":        `{"error":[],"result":{"XXBTZUSD":{"a":["1"],"c":["100002.0","0.1"]}}}`,
+		"/api/v2/ticker/btcusd/":  `{"last":"100003","bid":"1"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/Ticker" {
+			assert.Equal(t, "XBTUSD", r.URL.Query().Get("pair"))
+		}
+		w.Write([]byte(responses[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	expected := map[string]float64{Coinbase: 100_001.5, Kraken: 100_002, Bitstamp: 100_003}
+	for _, name := range Exchanges {
+		source, err := NewSource(name, server.Client())
+		require.NoError(t, err)
+		source.(*exchange).url = server.URL
+
+		price, err := source.Price(context.Background(), Pair{Base: "BTC", Quote: "USD"})
+		require.NoError(t, err, name)
+		assert.Equal(t, expected[name], price, name)
+	}
+
+	_, err := NewSource("mtgox", nil)
+	assert.Error(t, err)
+}
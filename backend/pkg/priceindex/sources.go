@@ -0,0 +1,136 @@
+// pkg/priceindex/sources.go
+package priceindex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exchanges the index can read prices from
+const (
+	Coinbase = "coinbase"
+	Kraken   = "kraken"
+	Bitstamp = "bitstamp"
+)
+
+// Exchanges lists every exchange NewSource knows
+var Exchanges = []string{Coinbase, Kraken, Bitstamp}
+
+// NewSource creates the source of a named exchange, using client for its
+// requests
+func NewSource(name string, client *http.Client) (Source, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	switch name {
+	case Coinbase:
+		return &exchange{name: name, url: "https://api.coinbase.com", client: client, ticker: coinbaseTicker}, nil
+	case Kraken:
+		return &exchange{name: name, url: "https://api.kraken.com", client: client, ticker: krakenTicker}, nil
+	case Bitstamp:
+		return &exchange{name: name, url: "https://www.bitstamp.net", client: client, ticker: bitstampTicker}, nil
+	default:
+		return nil, fmt.Errorf("unknown price source %q", name)
+	}
+}
+
+// exchange is a source reading the public ticker of an exchange's REST API
+type exchange struct {
+	name   string
+	url    string
+	client *http.Client
+	// ticker returns the path of a pair's ticker and decodes its response
+	ticker func(pair Pair) (string, func(body *json.Decoder) (float64, error))
+}
+
+// Name returns the exchange's name
+func (e *exchange) Name() string {
+	return e.name
+}
+
+// Price returns the exchange's last price of a pair
+func (e *exchange) Price(ctx context.Context, pair Pair) (float64, error) {
+	path, decode := e.ticker(pair)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url+path, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create ticker request: %w", err)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query ticker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("ticker returned status %d", resp.StatusCode)
+	}
+
+	price, err := decode(json.NewDecoder(resp.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode ticker for %s: %w", pair, err)
+	}
+	return price, nil
+}
+
+// coinbaseTicker reads the spot price, e.g. /v2/prices/BTC-USD/spot
+func coinbaseTicker(pair Pair) (string, func(*json.Decoder) (float64, error)) {
+	return "/v2/prices/" + pair.Base + "-" + pair.Quote + "/spot", func(body *json.Decoder) (float64, error) {
+		var ticker struct {
+			Data struct {
+				Amount string `json:"amount"`
+			} `json:"data"`
+		}
+		if err := body.Decode(&ticker); err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(ticker.Data.Amount, 64)
+	}
+}
+
+// krakenTicker reads the last trade price, e.g. /0/public/Ticker?pair=XBTUSD.
+// Kraken names bitcoin XBT and keys the result by its own pair name.
+func krakenTicker(pair Pair) (string, func(*json.Decoder) (float64, error)) {
+	base := pair.Base
+	if base == "BTC" {
+		base = "XBT"
+	}
+	return "/0/public/Ticker?pair=" + base + pair.Quote, func(body *json.Decoder) (float64, error) {
+		var ticker struct {
+			Error  []string `json:"error"`
+			Result map[string]struct {
+				Last []string `json:"c"`
+			} `json:"result"`
+		}
+		if err := body.Decode(&ticker); err != nil {
+			return 0, err
+		}
+		if len(ticker.Error) > 0 {
+			return 0, fmt.Errorf("%s", strings.Join(ticker.Error, ", "))
+		}
+		for _, result := range ticker.Result {
+			if len(result.Last) > 0 {
+				return strconv.ParseFloat(result.Last[0], 64)
+			}
+		}
+		return 0, fmt.Errorf("no ticker in response")
+	}
+}
+
+// bitstampTicker reads the last price, e.g. /api/v2/ticker/btcusd/
+func bitstampTicker(pair Pair) (string, func(*json.Decoder) (float64, error)) {
+	return "/api/v2/ticker/" + strings.ToLower(pair.Base+pair.Quote) + "/", func(body *json.Decoder) (float64, error) {
+		var ticker struct {
+			Last string `json:"last"`
+		}
+		if err := body.Decode(&ticker); err != nil {
+			return 0, err
+		}
+		return strconv.ParseFloat(ticker.Last, 64)
+	}
+}
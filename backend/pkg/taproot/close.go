@@ -0,0 +1,32 @@
+// pkg/taproot/close.go
+package taproot
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// closeMessageTag domain-separates cooperative close intents from other
+// signatures made with a contract key
+const closeMessageTag = "hashhedge/cooperative-close"
+
+// CloseMessageHash is the hash a contract party signs to agree that the
+// contract closes cooperatively at a block height or Unix time, with the
+// contract size split into buyerAmount and sellerAmount. The unused
+// schedule field is zero.
+func CloseMessageHash(contractID string, closeHeight, closeTime, buyerAmount, sellerAmount int64) [32]byte {
+	message := fmt.Sprintf("%s\n%s\n%d\n%d\n%d\n%d",
+		closeMessageTag, contractID, closeHeight, closeTime, buyerAmount, sellerAmount)
+	return sha256.Sum256([]byte(message))
+}
+
+// VerifyCloseSignature checks a hex encoded BIP-340 signature by pubKey over
+// the cooperative close message of a contract
+func VerifyCloseSignature(
+	pubKey, contractID string,
+	closeHeight, closeTime, buyerAmount, sellerAmount int64,
+	signature string,
+) error {
+	hash := CloseMessageHash(contractID, closeHeight, closeTime, buyerAmount, sellerAmount)
+	return verifySignature(pubKey, hash, signature)
+}
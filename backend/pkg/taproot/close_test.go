@@ -0,0 +1,37 @@
+// pkg/taproot/close_test.go
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCloseSignature(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := hex.EncodeToString(privKey.PubKey().SerializeCompressed())
+
+	contractID := "6f1c2a3e-0000-4000-8000-000000000001"
+
+	hash := CloseMessageHash(contractID, 850000, 0, 60000, 40000)
+	sig, err := schnorr.Sign(privKey, hash[:])
+	require.NoError(t, err)
+	signature := hex.EncodeToString(sig.Serialize())
+
+	assert.NoError(t, VerifyCloseSignature(pubKey, contractID, 850000, 0, 60000, 40000, signature))
+
+	// The signature commits to the contract, the schedule and the split
+	assert.Error(t, VerifyCloseSignature(pubKey, "6f1c2a3e-0000-4000-8000-000000000002", 850000, 0, 60000, 40000, signature))
+	assert.Error(t, VerifyCloseSignature(pubKey, contractID, 850001, 0, 60000, 40000, signature))
+	assert.Error(t, VerifyCloseSignature(pubKey, contractID, 0, 850000, 60000, 40000, signature))
+	assert.Error(t, VerifyCloseSignature(pubKey, contractID, 850000, 0, 40000, 60000, signature))
+
+	// Close intents cannot be replayed as payout signatures
+	payoutHash := PayoutMessageHash(contractID, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr")
+	assert.NotEqual(t, hash, payoutHash)
+}
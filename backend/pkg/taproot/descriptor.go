@@ -0,0 +1,92 @@
+// pkg/taproot/descriptor.go
+package taproot
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// descriptorInputCharset and descriptorChecksumCharset are the character
+// sets of output script descriptors and their checksums (BIP-380)
+const (
+	descriptorInputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// descriptorPolyMod updates a descriptor checksum with one 5 bit value
+func descriptorPolyMod(c uint64, val int) uint64 {
+	generator := [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+	c0 := c >> 35
+	c = ((c & 0x7ffffffff) << 5) ^ uint64(val)
+	for i, g := range generator {
+		if (c0>>uint(i))&1 != 0 {
+			c ^= g
+		}
+	}
+	return c
+}
+
+// DescriptorChecksum returns the 8 character checksum of a descriptor
+func DescriptorChecksum(desc string) (string, error) {
+	c := uint64(1)
+	class, classCount := 0, 0
+	for _, ch := range desc {
+		pos := strings.IndexRune(descriptorInputCharset, ch)
+		if pos < 0 {
+			return "", fmt.Errorf("invalid descriptor character %q", ch)
+		}
+		c = descriptorPolyMod(c, pos&31)
+		class = class*3 + pos>>5
+		classCount++
+		if classCount == 3 {
+			c = descriptorPolyMod(c, class)
+			class, classCount = 0, 0
+		}
+	}
+	if classCount > 0 {
+		c = descriptorPolyMod(c, class)
+	}
+	for i := 0; i < 8; i++ {
+		c = descriptorPolyMod(c, 0)
+	}
+	c ^= 1
+
+	checksum := make([]byte, 8)
+	for i := range checksum {
+		checksum[i] = descriptorChecksumCharset[(c>>(5*uint(7-i)))&31]
+	}
+	return string(checksum), nil
+}
+
+// withChecksum appends the checksum to a descriptor
+func withChecksum(desc string) (string, error) {
+	checksum, err := DescriptorChecksum(desc)
+	if err != nil {
+		return "", err
+	}
+	return desc + "#" + checksum, nil
+}
+
+// KeyPathDescriptor returns the tr() descriptor of the key-path P2TR output
+// built by KeyPathAddress, so wallets can watch and spend it
+func KeyPathDescriptor(pubKey string) (string, error) {
+	key, err := ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return withChecksum("tr(" + hex.EncodeToString(schnorr.SerializePubKey(key)) + ")")
+}
+
+// AddressDescriptor returns the addr() descriptor of a payout address on a network
+func AddressDescriptor(address string, params *chaincfg.Params) (string, error) {
+	canonical, err := DecodePayoutAddress(address, params)
+	if err != nil {
+		return "", err
+	}
+	return withChecksum("addr(" + canonical + ")")
+}
@@ -0,0 +1,51 @@
+// pkg/taproot/descriptor_test.go
+package taproot
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptorChecksum(t *testing.T) {
+	// BIP-380 test vector
+	checksum, err := DescriptorChecksum("raw(deadbeef)")
+	require.NoError(t, err)
+	assert.Equal(t, "89f8spxm", checksum)
+
+	_, err = DescriptorChecksum("raw(é)")
+	assert.Error(t, err)
+}
+
+func TestKeyPathDescriptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		pubKey string
+	}{
+		{"x-only", "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"},
+		// The same key with an even Y coordinate prefix
+		{"compressed", "02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, err := KeyPathDescriptor(tt.pubKey)
+			require.NoError(t, err)
+			assert.Equal(t, "tr(cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115)#7s05a9nk", desc)
+		})
+	}
+
+	_, err := KeyPathDescriptor("not hex")
+	assert.Error(t, err)
+}
+
+func TestAddressDescriptor(t *testing.T) {
+	desc, err := AddressDescriptor("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "addr(1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2)#wdnlkpe8", desc)
+
+	_, err = AddressDescriptor("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", &chaincfg.MainNetParams)
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+// pkg/taproot/network.go
+package taproot
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// DefaultNetwork is the network contracts are built for unless configured otherwise
+const DefaultNetwork = "mainnet"
+
+// ParseNetwork returns the chain parameters of a network by name: mainnet,
+// testnet, signet or regtest
+func ParseNetwork(name string) (*chaincfg.Params, error) {
+	switch name {
+	case "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet":
+		return &chaincfg.TestNet3Params, nil
+	case "signet":
+		return &chaincfg.SigNetParams, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("unknown bitcoin network %q", name)
+	}
+}
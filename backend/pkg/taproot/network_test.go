@@ -0,0 +1,28 @@
+// pkg/taproot/network_test.go
+package taproot
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetwork(t *testing.T) {
+	tests := map[string]*chaincfg.Params{
+		"mainnet": &chaincfg.MainNetParams,
+		"testnet": &chaincfg.TestNet3Params,
+		"signet":  &chaincfg.SigNetParams,
+		"regtest": &chaincfg.RegressionNetParams,
+	}
+
+	for name, want := range tests {
+		params, err := ParseNetwork(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, params, name)
+	}
+
+	_, err := ParseNetwork("testnet4")
+	assert.Error(t, err)
+}
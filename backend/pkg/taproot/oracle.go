@@ -0,0 +1,248 @@
+// pkg/taproot/oracle.go
+package taproot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// oracleMessageTag domain-separates oracle attestations from other signatures
+const oracleMessageTag = "hashhedge/oracle-attestation"
+
+// OracleOutcome is the outcome of a contract as attested by an oracle
+type OracleOutcome string
+
+const (
+	// OracleOutcomeHigh attests that the hash rate was at or above the strike
+	OracleOutcomeHigh OracleOutcome = "HIGH"
+	// OracleOutcomeLow attests that the hash rate was below the strike
+	OracleOutcomeLow OracleOutcome = "LOW"
+)
+
+// OracleEvent is an oracle's announcement of the event a contract settles
+// on: its x-only public key and the x-only nonce it will sign the outcome
+// with. Committing to the nonce in advance lets the contract lock each
+// outcome to the key the oracle's signature will reveal, as in a DLC.
+type OracleEvent struct {
+	PubKey  string
+	Nonce   string
+	EventID string
+}
+
+// OracleMessageHash is the canonical message an oracle signs to attest the
+// outcome of an event
+func OracleMessageHash(eventID string, outcome OracleOutcome) [32]byte {
+	return sha256.Sum256([]byte(oracleMessageTag + "\n" + eventID + "\n" + string(outcome)))
+}
+
+// parseXOnly parses a hex encoded x-only public key or nonce
+func parseXOnly(name, value string) (*btcec.PublicKey, error) {
+	raw, err := hex.DecodeString(value)
+	if err != nil || len(raw) != schnorr.PubKeyBytesLen {
+		return nil, fmt.Errorf("oracle %s must be a 32 byte x-only key", name)
+	}
+	key, err := schnorr.ParsePubKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid oracle %s: %w", name, err)
+	}
+	return key, nil
+}
+
+// Validate checks that the oracle key and nonce are valid x-only points
+func (e OracleEvent) Validate() error {
+	if e.EventID == "" {
+		return fmt.Errorf("oracle event ID cannot be empty")
+	}
+	if _, err := parseXOnly("public key", e.PubKey); err != nil {
+		return err
+	}
+	if _, err := parseXOnly("nonce", e.Nonce); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AttestationPoint returns the point s*G of the signature the oracle will
+// publish for an outcome, R + e*P, where e is the BIP-340 challenge of the
+// announced nonce, the oracle key and the outcome message. It is computed
+// before the oracle signs, so contracts can lock funds to it.
+func AttestationPoint(event OracleEvent, outcome OracleOutcome) (*btcec.PublicKey, error) {
+	oracleKey, err := parseXOnly("public key", event.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := parseXOnly("nonce", event.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	message := OracleMessageHash(event.EventID, outcome)
+	challenge := chainhash.TaggedHash(chainhash.TagBIP0340Challenge,
+		schnorr.SerializePubKey(nonce), schnorr.SerializePubKey(oracleKey), message[:])
+
+	var e btcec.ModNScalar
+	e.SetByteSlice(challenge[:])
+
+	var r, p, eP, point btcec.JacobianPoint
+	nonce.AsJacobian(&r)
+	oracleKey.AsJacobian(&p)
+	btcec.ScalarMultNonConst(&e, &p, &eP)
+	btcec.AddNonConst(&r, &eP, &point)
+	point.ToAffine()
+
+	return btcec.NewPublicKey(&point.X, &point.Y), nil
+}
+
+// VerifyAttestation checks a hex encoded BIP-340 signature by the oracle
+// over the outcome of an event. The signature must use the announced
+// nonce, or its scalar would not unlock the outcome's leaf.
+func VerifyAttestation(event OracleEvent, outcome OracleOutcome, signature string) error {
+	if outcome != OracleOutcomeHigh && outcome != OracleOutcomeLow {
+		return fmt.Errorf("invalid oracle outcome %q", outcome)
+	}
+
+	oracleKey, err := parseXOnly("public key", event.PubKey)
+	if err != nil {
+		return err
+	}
+	nonce, err := parseXOnly("nonce", event.Nonce)
+	if err != nil {
+		return err
+	}
+
+	raw, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	sig, err := schnorr.ParseSignature(raw)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !bytes.Equal(raw[:schnorr.PubKeyBytesLen], schnorr.SerializePubKey(nonce)) {
+		return fmt.Errorf("signature does not use the announced nonce")
+	}
+
+	message := OracleMessageHash(event.EventID, outcome)
+	if !sig.Verify(message[:], oracleKey) {
+		return fmt.Errorf("signature does not match the oracle key")
+	}
+
+	return nil
+}
+
+// OracleLeaf returns the tapscript leaf the winner of an outcome spends: a
+// signature check against the winner's key plus the outcome's attestation
+// point. Only once the oracle attests the outcome does the winner learn the
+// scalar that, added to their own key, signs for it.
+func OracleLeaf(winnerPubKey string, point *btcec.PublicKey) ([]byte, error) {
+	winner, err := ParsePubKey(winnerPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid winner public key: %w", err)
+	}
+
+	var w, s, sum btcec.JacobianPoint
+	winner.AsJacobian(&w)
+	point.AsJacobian(&s)
+	btcec.AddNonConst(&w, &s, &sum)
+	sum.ToAffine()
+	key := btcec.NewPublicKey(&sum.X, &sum.Y)
+
+	script, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(key)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build oracle script: %w", err)
+	}
+	return script, nil
+}
+
+// OracleLeaves returns the leaves of the high and low outcomes of an oracle
+// event, each paying the party that wins it
+func (b *ScriptBuilder) OracleLeaves(buyerPubKey, sellerPubKey string, isCall bool, event OracleEvent) ([][]byte, error) {
+	highWinner, lowWinner := buyerPubKey, sellerPubKey
+	if !isCall {
+		highWinner, lowWinner = sellerPubKey, buyerPubKey
+	}
+
+	leaves := make([][]byte, 0, 2)
+	for _, outcome := range []struct {
+		outcome OracleOutcome
+		winner  string
+	}{
+		{OracleOutcomeHigh, highWinner},
+		{OracleOutcomeLow, lowWinner},
+	} {
+		point, err := AttestationPoint(event, outcome.outcome)
+		if err != nil {
+			return nil, err
+		}
+		leaf, err := OracleLeaf(outcome.winner, point)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+// OracleFinalLeaves returns the final leaves followed by the high and low
+// outcome leaves of an oracle event
+func (b *ScriptBuilder) OracleFinalLeaves(
+	buyerPubKey string,
+	sellerPubKey string,
+	endBlockHeight int64,
+	targetTimestamp time.Time,
+	isCall bool,
+	event OracleEvent,
+) ([][]byte, error) {
+	leaves, err := b.FinalLeaves(buyerPubKey, sellerPubKey, endBlockHeight, targetTimestamp, isCall)
+	if err != nil {
+		return nil, err
+	}
+
+	oracleLeaves, err := b.OracleLeaves(buyerPubKey, sellerPubKey, isCall, event)
+	if err != nil {
+		return nil, err
+	}
+	return append(leaves, oracleLeaves...), nil
+}
+
+// BuildOracleFinalScript creates the address of a final output that can
+// also be spent by the winner of an oracle attested outcome
+func (b *ScriptBuilder) BuildOracleFinalScript(
+	buyerPubKey string,
+	sellerPubKey string,
+	endBlockHeight int64,
+	targetTimestamp time.Time,
+	isCall bool,
+	event OracleEvent,
+) (string, error) {
+	if endBlockHeight <= 0 {
+		return "", fmt.Errorf("invalid end block height: %d", endBlockHeight)
+	}
+	if targetTimestamp.IsZero() {
+		return "", fmt.Errorf("target timestamp cannot be zero")
+	}
+
+	leaves, err := b.OracleFinalLeaves(buyerPubKey, sellerPubKey, endBlockHeight, targetTimestamp, isCall, event)
+	if err != nil {
+		return "", err
+	}
+
+	// The buyer's key is the internal key, as for the other final outputs
+	spender, err := NewSpendBuilder(buyerPubKey, leaves)
+	if err != nil {
+		return "", err
+	}
+
+	return spender.Address(b.params)
+}
@@ -0,0 +1,131 @@
+// pkg/taproot/oracle_test.go
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evenScalar returns the private scalar of a key, negated if needed so its
+// point has an even Y coordinate as BIP-340 requires
+func evenScalar(key *btcec.PrivateKey) btcec.ModNScalar {
+	scalar := key.Key
+	if key.PubKey().SerializeCompressed()[0] == 0x03 {
+		scalar.Negate()
+	}
+	return scalar
+}
+
+// attest signs an outcome as an oracle would, with the nonce it announced
+func attest(oracleKey, nonce *btcec.PrivateKey, eventID string, outcome OracleOutcome) string {
+	x := evenScalar(oracleKey)
+	k := evenScalar(nonce)
+
+	message := OracleMessageHash(eventID, outcome)
+	rx := schnorr.SerializePubKey(nonce.PubKey())
+	challenge := chainhash.TaggedHash(chainhash.TagBIP0340Challenge,
+		rx, schnorr.SerializePubKey(oracleKey.PubKey()), message[:])
+
+	var e, s btcec.ModNScalar
+	e.SetByteSlice(challenge[:])
+	s.Mul2(&e, &x).Add(&k)
+
+	sBytes := s.Bytes()
+	return hex.EncodeToString(append(rx, sBytes[:]...))
+}
+
+// testOracle returns an oracle key, a nonce and the event announcing them
+func testOracle(t *testing.T) (*btcec.PrivateKey, *btcec.PrivateKey, OracleEvent) {
+	oracleKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	nonce, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	event := OracleEvent{
+		PubKey:  hex.EncodeToString(schnorr.SerializePubKey(oracleKey.PubKey())),
+		Nonce:   hex.EncodeToString(schnorr.SerializePubKey(nonce.PubKey())),
+		EventID: "6f1c2a3e-0000-4000-8000-000000000001",
+	}
+	require.NoError(t, event.Validate())
+	return oracleKey, nonce, event
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	oracleKey, nonce, event := testOracle(t)
+	signature := attest(oracleKey, nonce, event.EventID, OracleOutcomeHigh)
+
+	assert.NoError(t, VerifyAttestation(event, OracleOutcomeHigh, signature))
+	assert.Error(t, VerifyAttestation(event, OracleOutcomeLow, signature))
+	assert.Error(t, VerifyAttestation(event, "MAYBE", signature))
+
+	other := event
+	other.EventID = "6f1c2a3e-0000-4000-8000-000000000002"
+	assert.Error(t, VerifyAttestation(other, OracleOutcomeHigh, signature))
+
+	// A valid signature with another nonce would not unlock the leaf
+	message := OracleMessageHash(event.EventID, OracleOutcomeHigh)
+	sig, err := schnorr.Sign(oracleKey, message[:])
+	require.NoError(t, err)
+	err = VerifyAttestation(event, OracleOutcomeHigh, hex.EncodeToString(sig.Serialize()))
+	assert.ErrorContains(t, err, "announced nonce")
+}
+
+func TestAttestationUnlocksOracleLeaf(t *testing.T) {
+	oracleKey, nonce, event := testOracle(t)
+
+	point, err := AttestationPoint(event, OracleOutcomeLow)
+	require.NoError(t, err)
+
+	// The attested signature's scalar is the discrete log of the point
+	raw, err := hex.DecodeString(attest(oracleKey, nonce, event.EventID, OracleOutcomeLow))
+	require.NoError(t, err)
+	var s btcec.ModNScalar
+	s.SetByteSlice(raw[32:])
+
+	var sG btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&s, &sG)
+	sG.ToAffine()
+	assert.True(t, btcec.NewPublicKey(&sG.X, &sG.Y).IsEqual(point))
+
+	// Adding it to the winner's key gives the key of the leaf
+	winner, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	leaf, err := OracleLeaf(hex.EncodeToString(winner.PubKey().SerializeCompressed()), point)
+	require.NoError(t, err)
+
+	sum := winner.Key
+	sum.Add(&s)
+	spendKey := btcec.PrivKeyFromScalar(&sum)
+	assert.Equal(t, schnorr.SerializePubKey(spendKey.PubKey()), leaf[1:33])
+}
+
+func TestOracleLeaves(t *testing.T) {
+	_, _, event := testOracle(t)
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	buyerPubKey := hex.EncodeToString(buyer.PubKey().SerializeCompressed())
+	sellerPubKey := hex.EncodeToString(seller.PubKey().SerializeCompressed())
+
+	b := NewScriptBuilder()
+	call, err := b.OracleLeaves(buyerPubKey, sellerPubKey, true, event)
+	require.NoError(t, err)
+	put, err := b.OracleLeaves(buyerPubKey, sellerPubKey, false, event)
+	require.NoError(t, err)
+	require.Len(t, call, 2)
+
+	// A put pays the high outcome to the seller, the reverse of a call
+	highPoint, err := AttestationPoint(event, OracleOutcomeHigh)
+	require.NoError(t, err)
+	sellerHigh, err := OracleLeaf(sellerPubKey, highPoint)
+	require.NoError(t, err)
+	assert.Equal(t, sellerHigh, put[0])
+	assert.NotEqual(t, call[0], put[0])
+}
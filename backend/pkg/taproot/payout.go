@@ -0,0 +1,99 @@
+// pkg/taproot/payout.go
+package taproot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// payoutMessageTag domain-separates payout address signatures from other
+// signatures made with a contract key
+const payoutMessageTag = "hashhedge/payout-address"
+
+// ParsePubKey parses a hex encoded compressed or x-only public key
+func ParsePubKey(pubKey string) (*btcec.PublicKey, error) {
+	raw, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(raw) == schnorr.PubKeyBytesLen {
+		return schnorr.ParsePubKey(raw)
+	}
+	return btcec.ParsePubKey(raw)
+}
+
+// KeyPathAddress returns the P2TR address on a network of a public key
+// spendable only by the key path, with no script tree committed (BIP-86)
+func KeyPathAddress(pubKey string, params *chaincfg.Params) (string, error) {
+	key, err := ParsePubKey(pubKey)
+	if err != nil {
+		return "", err
+	}
+
+	outputKey := txscript.ComputeTaprootKeyNoScript(key)
+	address, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), params)
+	if err != nil {
+		return "", fmt.Errorf("failed to create taproot address: %w", err)
+	}
+
+	return address.String(), nil
+}
+
+// DecodePayoutAddress validates a payout address on a network and returns it
+// in canonical form
+func DecodePayoutAddress(address string, params *chaincfg.Params) (string, error) {
+	addr, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		return "", fmt.Errorf("invalid payout address: %w", err)
+	}
+	if !addr.IsForNet(params) {
+		return "", fmt.Errorf("payout address is not for this network")
+	}
+	if _, err := txscript.PayToAddrScript(addr); err != nil {
+		return "", fmt.Errorf("unsupported payout address: %w", err)
+	}
+
+	return addr.String(), nil
+}
+
+// PayoutMessageHash is the hash a contract party signs to direct its payout
+// from a contract to an address
+func PayoutMessageHash(contractID, address string) [32]byte {
+	return sha256.Sum256([]byte(payoutMessageTag + "\n" + contractID + "\n" + address))
+}
+
+// VerifyPayoutSignature checks a hex encoded BIP-340 signature by pubKey over
+// the payout message of a contract and address
+func VerifyPayoutSignature(pubKey, contractID, address, signature string) error {
+	return verifySignature(pubKey, PayoutMessageHash(contractID, address), signature)
+}
+
+// verifySignature checks a hex encoded BIP-340 signature by pubKey over hash
+func verifySignature(pubKey string, hash [32]byte, signature string) error {
+	key, err := ParsePubKey(pubKey)
+	if err != nil {
+		return err
+	}
+
+	raw, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	sig, err := schnorr.ParseSignature(raw)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if !sig.Verify(hash[:], key) {
+		return fmt.Errorf("signature does not match the contract key")
+	}
+
+	return nil
+}
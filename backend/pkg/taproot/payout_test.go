@@ -0,0 +1,83 @@
+// pkg/taproot/payout_test.go
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPathAddress(t *testing.T) {
+	// BIP-86 test vector, m/86'/0'/0'/0/0
+	address, err := KeyPathAddress("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", address)
+
+	_, err = KeyPathAddress("not hex", &chaincfg.MainNetParams)
+	assert.Error(t, err)
+
+	// The same key on regtest
+	address, err = KeyPathAddress("cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115", &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bcrt1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqvg32hk", address)
+}
+
+func TestDecodePayoutAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		params  *chaincfg.Params
+		valid   bool
+	}{
+		{"p2tr", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", &chaincfg.MainNetParams, true},
+		{"p2pkh", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", &chaincfg.MainNetParams, true},
+		{"testnet on mainnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", &chaincfg.MainNetParams, false},
+		{"testnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", &chaincfg.TestNet3Params, true},
+		{"mainnet on testnet", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", &chaincfg.TestNet3Params, false},
+		{"mainnet p2pkh on regtest", "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", &chaincfg.RegressionNetParams, false},
+		{"bad checksum", "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcx", &chaincfg.MainNetParams, false},
+		{"empty", "", &chaincfg.MainNetParams, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := DecodePayoutAddress(tt.address, tt.params)
+			if !tt.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.address, canonical)
+		})
+	}
+}
+
+func TestVerifyPayoutSignature(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := hex.EncodeToString(privKey.PubKey().SerializeCompressed())
+
+	contractID := "6f1c2a3e-0000-4000-8000-000000000001"
+	address := "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+
+	hash := PayoutMessageHash(contractID, address)
+	sig, err := schnorr.Sign(privKey, hash[:])
+	require.NoError(t, err)
+	signature := hex.EncodeToString(sig.Serialize())
+
+	assert.NoError(t, VerifyPayoutSignature(pubKey, contractID, address, signature))
+
+	// x-only keys are accepted too
+	xOnly := hex.EncodeToString(schnorr.SerializePubKey(privKey.PubKey()))
+	assert.NoError(t, VerifyPayoutSignature(xOnly, contractID, address, signature))
+
+	// The signature commits to both the contract and the address
+	assert.Error(t, VerifyPayoutSignature(pubKey, "6f1c2a3e-0000-4000-8000-000000000002", address, signature))
+	assert.Error(t, VerifyPayoutSignature(pubKey, contractID, "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", signature))
+	assert.Error(t, VerifyPayoutSignature(pubKey, contractID, address, "zz"))
+}
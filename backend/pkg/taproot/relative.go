@@ -0,0 +1,112 @@
+// pkg/taproot/relative.go
+package taproot
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// RelativeBlockSequence returns the BIP-68 sequence of a relative lock of
+// a number of blocks
+func RelativeBlockSequence(blocks int64) (uint32, error) {
+	if blocks <= 0 || blocks > wire.SequenceLockTimeMask {
+		return 0, fmt.Errorf("relative lock of %d blocks out of range", blocks)
+	}
+	return uint32(blocks), nil
+}
+
+// RelativeTimeSequence returns the BIP-68 sequence of a relative lock of at
+// least seconds, which are rounded up to units of 512 seconds
+func RelativeTimeSequence(seconds int64) (uint32, error) {
+	const unit = 1 << wire.SequenceLockTimeGranularity
+	units := (seconds + unit - 1) / unit
+	if seconds <= 0 || units > wire.SequenceLockTimeMask {
+		return 0, fmt.Errorf("relative lock of %d seconds out of range", seconds)
+	}
+	return wire.SequenceLockTimeIsSeconds | uint32(units), nil
+}
+
+// RelativeSetupLeaves returns the tapscript leaves of the setup output of a
+// contract timed from its setup confirmation: the cooperative path, the high
+// hash rate path spendable blocks after the setup confirms, and the low hash
+// rate path spendable seconds after it confirms
+func (b *ScriptBuilder) RelativeSetupLeaves(
+	buyerPubKey string,
+	sellerPubKey string,
+	blocks int64,
+	seconds int64,
+) ([][]byte, error) {
+	buyerPK, err := xOnlyKey(buyerPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid buyer public key: %w", err)
+	}
+	sellerPK, err := xOnlyKey(sellerPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seller public key: %w", err)
+	}
+
+	blockSequence, err := RelativeBlockSequence(blocks)
+	if err != nil {
+		return nil, err
+	}
+	timeSequence, err := RelativeTimeSequence(seconds)
+	if err != nil {
+		return nil, err
+	}
+
+	// The same 2-of-2 between buyer and seller as absolute contracts
+	cooperativeScript, err := addMultisig(txscript.NewScriptBuilder(), 2, buyerPK, sellerPK).Script()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cooperative script: %w", err)
+	}
+
+	highHashRateScript, err := relativeLeaf(blockSequence, buyerPK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build high hash rate script: %w", err)
+	}
+	lowHashRateScript, err := relativeLeaf(timeSequence, sellerPK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build low hash rate script: %w", err)
+	}
+
+	return [][]byte{cooperativeScript, highHashRateScript, lowHashRateScript}, nil
+}
+
+// BuildRelativeSetupScript creates the address of the setup output of a
+// contract timed from its setup confirmation
+func (b *ScriptBuilder) BuildRelativeSetupScript(
+	buyerPubKey string,
+	sellerPubKey string,
+	blocks int64,
+	seconds int64,
+) (string, error) {
+	if buyerPubKey == "" || sellerPubKey == "" {
+		return "", fmt.Errorf("buyer and seller public keys cannot be empty")
+	}
+
+	leaves, err := b.RelativeSetupLeaves(buyerPubKey, sellerPubKey, blocks, seconds)
+	if err != nil {
+		return "", err
+	}
+
+	// The buyer's key is the internal key, as for absolute setup outputs
+	spender, err := NewSpendBuilder(buyerPubKey, leaves)
+	if err != nil {
+		return "", err
+	}
+
+	return spender.Address(b.params)
+}
+
+// relativeLeaf is <sequence> OP_CHECKSEQUENCEVERIFY OP_DROP <key> OP_CHECKSIG
+func relativeLeaf(sequence uint32, pubKey []byte) ([]byte, error) {
+	return txscript.NewScriptBuilder().
+		AddInt64(int64(sequence)).
+		AddOp(txscript.OP_CHECKSEQUENCEVERIFY).
+		AddOp(txscript.OP_DROP).
+		AddData(pubKey).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+}
@@ -0,0 +1,113 @@
+// pkg/taproot/relative_test.go
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelativeSequences(t *testing.T) {
+	blocks, err := RelativeBlockSequence(144)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(144), blocks)
+
+	// Seconds round up to 512 second units and set the type flag
+	seconds, err := RelativeTimeSequence(86_400)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(wire.SequenceLockTimeIsSeconds|169), seconds)
+
+	_, err = RelativeBlockSequence(0)
+	assert.Error(t, err)
+	_, err = RelativeBlockSequence(0x10000)
+	assert.Error(t, err)
+	_, err = RelativeTimeSequence(0xffff*512 + 1)
+	assert.Error(t, err)
+}
+
+func TestRelativeSetupLeaves(t *testing.T) {
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	buyerPubKey := hex.EncodeToString(buyer.PubKey().SerializeCompressed())
+	sellerPubKey := hex.EncodeToString(seller.PubKey().SerializeCompressed())
+
+	b := NewScriptBuilder()
+	leaves, err := b.RelativeSetupLeaves(buyerPubKey, sellerPubKey, 1_008, 7*86_400)
+	require.NoError(t, err)
+	require.Len(t, leaves, 3)
+
+	spender, err := NewSpendBuilder(buyerPubKey, leaves)
+	require.NoError(t, err)
+	address, err := b.BuildRelativeSetupScript(buyerPubKey, sellerPubKey, 1_008, 7*86_400)
+	require.NoError(t, err)
+	expected, err := spender.Address(b.Params())
+	require.NoError(t, err)
+	assert.Equal(t, expected, address)
+
+	// The outcome paths carry relative locks and no absolute one
+	high, err := spender.Path(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1_008), high.Sequence)
+	assert.Zero(t, high.LockTime)
+	low, err := spender.Path(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(wire.SequenceLockTimeIsSeconds|1_182), low.Sequence)
+
+	_, err = b.RelativeSetupLeaves(buyerPubKey, sellerPubKey, 0, 7*86_400)
+	assert.Error(t, err)
+}
+
+func TestRelativePathVerifies(t *testing.T) {
+	internal, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	winner, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	leaf, err := relativeLeaf(144, schnorr.SerializePubKey(winner.PubKey()))
+	require.NoError(t, err)
+	spender, err := NewSpendBuilder(hex.EncodeToString(internal.PubKey().SerializeCompressed()), [][]byte{checksigLeaf(1), leaf})
+	require.NoError(t, err)
+	pkScript, err := spender.PkScript()
+	require.NoError(t, err)
+
+	const value = 100_000
+	prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, value)
+	sign := func(path *SpendPath, tx *wire.MsgTx) error {
+		sigHash, err := path.SigHash(tx, 0, prevOuts)
+		require.NoError(t, err)
+		sig, err := schnorr.Sign(winner, sigHash)
+		require.NoError(t, err)
+		tx.TxIn[0].Witness = path.Witness(sig.Serialize())
+
+		engine, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags,
+			nil, txscript.NewTxSigHashes(tx, prevOuts), value, prevOuts)
+		require.NoError(t, err)
+		return engine.Execute()
+	}
+
+	path, err := spender.Path(1)
+	require.NoError(t, err)
+
+	tx := spendingTx(value)
+	require.NoError(t, path.PrepareInput(tx, 0))
+	assert.Equal(t, uint32(144), tx.TxIn[0].Sequence)
+	assert.NoError(t, sign(path, tx))
+
+	// A shorter relative lock does not satisfy the leaf
+	tx = spendingTx(value)
+	tx.TxIn[0].Sequence = 143
+	assert.Error(t, sign(path, tx))
+
+	// Version 1 transactions do not enforce relative locks
+	tx = spendingTx(value)
+	tx.Version = 1
+	assert.Error(t, path.PrepareInput(tx, 0))
+}
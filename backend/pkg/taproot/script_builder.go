@@ -2,8 +2,6 @@
 package taproot
 
 import (
-    "bytes"
-    "encoding/hex"
     "fmt"
     "time"
 
@@ -16,16 +14,29 @@ import (
 // ScriptBuilder creates Taproot scripts for hash rate contracts
 type ScriptBuilder struct{
     ASPPubKey string // Ark Service Provider public key
+    params    *chaincfg.Params
 }
 
-// NewScriptBuilder creates a new ScriptBuilder
+// NewScriptBuilder creates a new ScriptBuilder for mainnet
 func NewScriptBuilder() *ScriptBuilder {
     // Default ASP key - should be configured in a real implementation
     return &ScriptBuilder{
         ASPPubKey: "0250929b74c1a04954b78b4b6035e97a5e078a5a0f28ec96d547bfee9ace803ac0",
+        params:    &chaincfg.MainNetParams,
     }
 }
 
+// WithNetwork sets the network the builder encodes and accepts addresses for
+func (b *ScriptBuilder) WithNetwork(params *chaincfg.Params) *ScriptBuilder {
+    b.params = params
+    return b
+}
+
+// Params returns the chain parameters of the builder's network
+func (b *ScriptBuilder) Params() *chaincfg.Params {
+    return b.params
+}
+
 // WithASPPubKey sets a custom ASP public key
 func (b *ScriptBuilder) WithASPPubKey(pubKey string) *ScriptBuilder {
     b.ASPPubKey = pubKey
@@ -54,29 +65,46 @@ func (b *ScriptBuilder) BuildSetupScript(
         return "", fmt.Errorf("target timestamp must be in the future")
     }
 
+    leaves, err := b.SetupLeaves(buyerPubKey, sellerPubKey, endBlockHeight, targetTimestamp)
+    if err != nil {
+        return "", err
+    }
+
+    // The buyer's key is the internal key, and the leaves are assembled
+    // exactly as they are when the outputs are spent
+    spender, err := NewSpendBuilder(buyerPubKey, leaves)
+    if err != nil {
+        return "", err
+    }
+
+    return spender.Address(b.params)
+}
+
+// SetupLeaves returns the tapscript leaves of the setup output: the
+// cooperative path, the high hash rate path and the low hash rate path
+func (b *ScriptBuilder) SetupLeaves(
+    buyerPubKey string,
+    sellerPubKey string,
+    endBlockHeight int64,
+    targetTimestamp time.Time,
+) ([][]byte, error) {
     // Decode the buyer's public key
-    buyerPK, err := hex.DecodeString(buyerPubKey)
+    buyerPK, err := xOnlyKey(buyerPubKey)
     if err != nil {
-        return "", fmt.Errorf("invalid buyer public key: %w", err)
+        return nil, fmt.Errorf("invalid buyer public key: %w", err)
     }
 
     // Decode the seller's public key
-    sellerPK, err := hex.DecodeString(sellerPubKey)
+    sellerPK, err := xOnlyKey(sellerPubKey)
     if err != nil {
-        return "", fmt.Errorf("invalid seller public key: %w", err)
+        return nil, fmt.Errorf("invalid seller public key: %w", err)
     }
 
-    // Create a cooperative spend path (key path)
+    // Create a cooperative spend path
     // This is a 2-of-2 multisig between buyer and seller
-    cooperativeScript, err := txscript.NewScriptBuilder().
-        AddOp(txscript.OP_2).                   // 2 signatures required
-        AddData(buyerPK).                       // Buyer's public key
-        AddData(sellerPK).                      // Seller's public key
-        AddOp(txscript.OP_2).                   // 2 public keys total
-        AddOp(txscript.OP_CHECKMULTISIG).       // Check the multisig
-        Script()
+    cooperativeScript, err := addMultisig(txscript.NewScriptBuilder(), 2, buyerPK, sellerPK).Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build cooperative script: %w", err)
+        return nil, fmt.Errorf("failed to build cooperative script: %w", err)
     }
 
     // Create the high hash rate path (if block height is reached first)
@@ -88,7 +116,7 @@ func (b *ScriptBuilder) BuildSetupScript(
         AddOp(txscript.OP_CHECKSIG).            // Check signature
         Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build high hash rate script: %w", err)
+        return nil, fmt.Errorf("failed to build high hash rate script: %w", err)
     }
 
     // Create the low hash rate path (if timestamp is reached first)
@@ -101,38 +129,10 @@ func (b *ScriptBuilder) BuildSetupScript(
         AddOp(txscript.OP_CHECKSIG).            // Check signature
         Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build low hash rate script: %w", err)
-    }
-
-    // Create Taproot script tree with the different spend paths
-    internalKey, err := txscript.NewTaprootInternalKey(buyerPK)
-    if err != nil {
-        return "", fmt.Errorf("failed to create taproot internal key: %w", err)
-    }
-
-    scriptTree := txscript.NewBaseTapscriptTree()
-    scriptTree.AddLeaf(cooperativeScript)
-    scriptTree.AddLeaf(highHashRateScript)
-    scriptTree.AddLeaf(lowHashRateScript)
-
-    tapscript := scriptTree.ScriptTree
-
-    // Calculate the taproot output key
-    outputKey, err := txscript.ComputeTaprootOutputKey(internalKey, tapscript.RootNode.TapHash())
-    if err != nil {
-        return "", fmt.Errorf("failed to compute taproot output key: %w", err)
-    }
-
-    // Convert to a P2TR address
-    address, err := btcutil.NewAddressTaproot(
-        schnorr.SerializePubKey(outputKey),
-        &chaincfg.MainNetParams,
-    )
-    if err != nil {
-        return "", fmt.Errorf("failed to create taproot address: %w", err)
+        return nil, fmt.Errorf("failed to build low hash rate script: %w", err)
     }
 
-    return address.String(), nil
+    return [][]byte{cooperativeScript, highHashRateScript, lowHashRateScript}, nil
 }
 
 // BuildFinalScript creates the script for the final transaction
@@ -156,16 +156,40 @@ func (b *ScriptBuilder) BuildFinalScript(
         return "", fmt.Errorf("target timestamp cannot be zero")
     }
 
+    leaves, err := b.FinalLeaves(buyerPubKey, sellerPubKey, endBlockHeight, targetTimestamp, isCall)
+    if err != nil {
+        return "", err
+    }
+
+    // The buyer's key is the internal key, and the leaves are assembled
+    // exactly as they are when the outputs are spent
+    spender, err := NewSpendBuilder(buyerPubKey, leaves)
+    if err != nil {
+        return "", err
+    }
+
+    return spender.Address(b.params)
+}
+
+// FinalLeaves returns the tapscript leaves of the final output: the high
+// hash rate path, the low hash rate path and the dispute resolution path
+func (b *ScriptBuilder) FinalLeaves(
+    buyerPubKey string,
+    sellerPubKey string,
+    endBlockHeight int64,
+    targetTimestamp time.Time,
+    isCall bool,
+) ([][]byte, error) {
     // Decode the buyer's public key
-    buyerPK, err := hex.DecodeString(buyerPubKey)
+    buyerPK, err := xOnlyKey(buyerPubKey)
     if err != nil {
-        return "", fmt.Errorf("invalid buyer public key: %w", err)
+        return nil, fmt.Errorf("invalid buyer public key: %w", err)
     }
 
     // Decode the seller's public key
-    sellerPK, err := hex.DecodeString(sellerPubKey)
+    sellerPK, err := xOnlyKey(sellerPubKey)
     if err != nil {
-        return "", fmt.Errorf("invalid seller public key: %w", err)
+        return nil, fmt.Errorf("invalid seller public key: %w", err)
     }
 
     // Determine the winner's public key for each outcome based on contract type
@@ -189,7 +213,7 @@ func (b *ScriptBuilder) BuildFinalScript(
         AddOp(txscript.OP_CHECKSIG).            // Check signature
         Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build high hash rate script: %w", err)
+        return nil, fmt.Errorf("failed to build high hash rate script: %w", err)
     }
 
     // Create the low hash rate path (if timestamp is reached first)
@@ -202,95 +226,46 @@ func (b *ScriptBuilder) BuildFinalScript(
         AddOp(txscript.OP_CHECKSIG).            // Check signature
         Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build low hash rate script: %w", err)
+        return nil, fmt.Errorf("failed to build low hash rate script: %w", err)
     }
 
     // Create a dispute resolution path that requires 2-of-3 signatures
     // (buyer, seller, and ASP can resolve a dispute)
     // This is for cases where settlement is disputed
-    aspPK, err := hex.DecodeString(b.ASPPubKey)
+    aspPK, err := xOnlyKey(b.ASPPubKey)
     if err != nil {
-        return "", fmt.Errorf("invalid ASP public key: %w", err)
+        return nil, fmt.Errorf("invalid ASP public key: %w", err)
     }
     
-    disputeScript, err := txscript.NewScriptBuilder().
-        AddOp(txscript.OP_2).                   // 2 signatures required
-        AddData(buyerPK).                       // Buyer's public key
-        AddData(sellerPK).                      // Seller's public key
-        AddData(aspPK).                         // ASP's public key
-        AddOp(txscript.OP_3).                   // 3 public keys total
-        AddOp(txscript.OP_CHECKMULTISIG).       // Check the multisig
-        Script()
+    disputeScript, err := addMultisig(txscript.NewScriptBuilder(), 2, buyerPK, sellerPK, aspPK).Script()
     if err != nil {
-        return "", fmt.Errorf("failed to build dispute resolution script: %w", err)
+        return nil, fmt.Errorf("failed to build dispute resolution script: %w", err)
     }
 
-    // Create Taproot script tree with the different spend paths
-    internalKey, err := txscript.NewTaprootInternalKey(buyerPK)
-    if err != nil {
-        return "", fmt.Errorf("failed to create taproot internal key: %w", err)
-    }
-
-    scriptTree := txscript.NewBaseTapscriptTree()
-    scriptTree.AddLeaf(highHashRateScript)
-    scriptTree.AddLeaf(lowHashRateScript)
-    scriptTree.AddLeaf(disputeScript)
-
-    tapscript := scriptTree.ScriptTree
-
-    // Calculate the taproot output key
-    outputKey, err := txscript.ComputeTaprootOutputKey(internalKey, tapscript.RootNode.TapHash())
-    if err != nil {
-        return "", fmt.Errorf("failed to compute taproot output key: %w", err)
-    }
-
-    // Convert to a P2TR address
-    address, err := btcutil.NewAddressTaproot(
-        schnorr.SerializePubKey(outputKey),
-        &chaincfg.MainNetParams,
-    )
-    if err != nil {
-        return "", fmt.Errorf("failed to create taproot address: %w", err)
-    }
-
-    return address.String(), nil
+    return [][]byte{highHashRateScript, lowHashRateScript, disputeScript}, nil
 }
 
-// BuildSettlementScript creates the script for the settlement transaction
+// BuildSettlementScript returns the address the settlement transaction pays
+// the winner: payoutAddress if the winner chose one, otherwise a key-path
+// P2TR output of the winner's contract key
 func (b *ScriptBuilder) BuildSettlementScript(
     winnerPubKey string,
+    payoutAddress string,
 ) (string, error) {
+    if payoutAddress != "" {
+        return DecodePayoutAddress(payoutAddress, b.params)
+    }
+
     if winnerPubKey == "" {
         return "", fmt.Errorf("winner public key cannot be empty")
     }
 
-    // Decode the winner's public key
-    winnerPK, err := hex.DecodeString(winnerPubKey)
+    address, err := KeyPathAddress(winnerPubKey, b.params)
     if err != nil {
         return "", fmt.Errorf("invalid winner public key: %w", err)
     }
 
-    // Create a simple P2PKH script for the winner
-    pkHash := btcutil.Hash160(winnerPK)
-    
-    settlementScript, err := txscript.NewScriptBuilder().
-        AddOp(txscript.OP_DUP).
-        AddOp(txscript.OP_HASH160).
-        AddData(pkHash).
-        AddOp(txscript.OP_EQUALVERIFY).
-        AddOp(txscript.OP_CHECKSIG).
-        Script()
-    if err != nil {
-        return "", fmt.Errorf("failed to build settlement script: %w", err)
-    }
-
-    // Convert to a P2PKH address
-    address, err := btcutil.NewAddressPubKeyHash(pkHash, &chaincfg.MainNetParams)
-    if err != nil {
-        return "", fmt.Errorf("failed to create P2PKH address: %w", err)
-    }
-
-    return address.String(), nil
+    return address, nil
 }
 
 // BuildSwapScript creates a script for transferring a contract to a new participant
@@ -310,56 +285,44 @@ func (b *ScriptBuilder) BuildSwapScript(
     }
 
     // Decode the public keys
-    currentPK, err := hex.DecodeString(currentPubKey)
+    currentPK, err := xOnlyKey(currentPubKey)
     if err != nil {
         return "", fmt.Errorf("invalid current public key: %w", err)
     }
 
-    newPK, err := hex.DecodeString(newPubKey)
+    newPK, err := xOnlyKey(newPubKey)
     if err != nil {
         return "", fmt.Errorf("invalid new public key: %w", err)
     }
 
-    aspPK, err := hex.DecodeString(aspPubKey)
+    aspPK, err := xOnlyKey(aspPubKey)
     if err != nil {
         return "", fmt.Errorf("invalid ASP public key: %w", err)
     }
 
     // Create a script that requires signatures from the current participant,
     // the new participant, and the ASP to authorize the swap
-    swapScript, err := txscript.NewScriptBuilder().
-        AddOp(txscript.OP_3).                   // 3 signatures required
-        AddData(currentPK).                     // Current participant's public key
-        AddData(newPK).                         // New participant's public key
-        AddData(aspPK).                         // ASP's public key
-        AddOp(txscript.OP_3).                   // 3 public keys total
-        AddOp(txscript.OP_CHECKMULTISIG).       // Check the multisig
-        Script()
+    swapScript, err := addMultisig(txscript.NewScriptBuilder(), 3, currentPK, newPK, aspPK).Script()
     if err != nil {
         return "", fmt.Errorf("failed to build swap script: %w", err)
     }
 
     // Create a Taproot script with the swap path
-    internalKey, err := txscript.NewTaprootInternalKey(currentPK)
+    internalKey, err := ParsePubKey(currentPubKey)
     if err != nil {
         return "", fmt.Errorf("failed to create taproot internal key: %w", err)
     }
 
-    scriptTree := txscript.NewBaseTapscriptTree()
-    scriptTree.AddLeaf(swapScript)
-
-    tapscript := scriptTree.ScriptTree
+    tree := txscript.AssembleTaprootScriptTree(txscript.NewBaseTapLeaf(swapScript))
 
     // Calculate the taproot output key
-    outputKey, err := txscript.ComputeTaprootOutputKey(internalKey, tapscript.RootNode.TapHash())
-    if err != nil {
-        return "", fmt.Errorf("failed to compute taproot output key: %w", err)
-    }
+    root := tree.RootNode.TapHash()
+    outputKey := txscript.ComputeTaprootOutputKey(internalKey, root[:])
 
     // Convert to a P2TR address
     address, err := btcutil.NewAddressTaproot(
         schnorr.SerializePubKey(outputKey),
-        &chaincfg.MainNetParams,
+        b.params,
     )
     if err != nil {
         return "", fmt.Errorf("failed to create taproot address: %w", err)
@@ -385,53 +348,43 @@ func (b *ScriptBuilder) BuildExitPathScript(
     }
 
     // Decode the buyer's public key
-    buyerPK, err := hex.DecodeString(buyerPubKey)
+    buyerPK, err := xOnlyKey(buyerPubKey)
     if err != nil {
         return "", fmt.Errorf("invalid buyer public key: %w", err)
     }
 
     // Decode the seller's public key
-    sellerPK, err := hex.DecodeString(sellerPubKey)
+    sellerPK, err := xOnlyKey(sellerPubKey)
     if err != nil {
         return "", fmt.Errorf("invalid seller public key: %w", err)
     }
 
     // Create a timeout path that allows 2-of-2 multisig after timeout
-    exitScript, err := txscript.NewScriptBuilder().
+    exitScript, err := addMultisig(txscript.NewScriptBuilder().
         AddInt64(timeoutBlocks).                // Timeout in blocks
         AddOp(txscript.OP_CHECKSEQUENCEVERIFY). // Check if enough time has elapsed
-        AddOp(txscript.OP_DROP).                // Remove timeout from stack
-        AddOp(txscript.OP_2).                   // 2 signatures required
-        AddData(buyerPK).                       // Buyer's public key
-        AddData(sellerPK).                      // Seller's public key
-        AddOp(txscript.OP_2).                   // 2 public keys total
-        AddOp(txscript.OP_CHECKMULTISIG).       // Check the multisig
-        Script()
+        AddOp(txscript.OP_DROP),                // Remove timeout from stack
+        2, buyerPK, sellerPK).Script()
     if err != nil {
         return "", fmt.Errorf("failed to build exit path script: %w", err)
     }
 
     // Create a Taproot script with the exit path
-    internalKey, err := txscript.NewTaprootInternalKey(buyerPK)
+    internalKey, err := ParsePubKey(buyerPubKey)
     if err != nil {
         return "", fmt.Errorf("failed to create taproot internal key: %w", err)
     }
 
-    scriptTree := txscript.NewBaseTapscriptTree()
-    scriptTree.AddLeaf(exitScript)
-
-    tapscript := scriptTree.ScriptTree
+    tree := txscript.AssembleTaprootScriptTree(txscript.NewBaseTapLeaf(exitScript))
 
     // Calculate the taproot output key
-    outputKey, err := txscript.ComputeTaprootOutputKey(internalKey, tapscript.RootNode.TapHash())
-    if err != nil {
-        return "", fmt.Errorf("failed to compute taproot output key: %w", err)
-    }
+    root := tree.RootNode.TapHash()
+    outputKey := txscript.ComputeTaprootOutputKey(internalKey, root[:])
 
     // Convert to a P2TR address
     address, err := btcutil.NewAddressTaproot(
         schnorr.SerializePubKey(outputKey),
-        &chaincfg.MainNetParams,
+        b.params,
     )
     if err != nil {
         return "", fmt.Errorf("failed to create taproot address: %w", err)
@@ -439,3 +392,30 @@ func (b *ScriptBuilder) BuildExitPathScript(
 
     return address.String(), nil
 }
+
+// xOnlyKey decodes a hex encoded compressed or x-only public key into the
+// 32 byte x-only form tapscript signature checks expect (BIP-340)
+func xOnlyKey(pubKey string) ([]byte, error) {
+    key, err := ParsePubKey(pubKey)
+    if err != nil {
+        return nil, err
+    }
+    return schnorr.SerializePubKey(key), nil
+}
+
+// addMultisig appends a threshold-of-n multisig to a tapscript, where
+// OP_CHECKMULTISIG is disabled (BIP-342):
+// <key1> OP_CHECKSIG <key2> OP_CHECKSIGADD ... <threshold> OP_NUMEQUAL.
+// It is satisfied by a signature or an empty push for every key, in reverse
+// key order, so the first key's signature is on top of the stack.
+func addMultisig(builder *txscript.ScriptBuilder, threshold int64, keys ...[]byte) *txscript.ScriptBuilder {
+    for i, key := range keys {
+        builder.AddData(key)
+        if i == 0 {
+            builder.AddOp(txscript.OP_CHECKSIG)
+        } else {
+            builder.AddOp(txscript.OP_CHECKSIGADD)
+        }
+    }
+    return builder.AddInt64(threshold).AddOp(txscript.OP_NUMEQUAL)
+}
@@ -0,0 +1,227 @@
+// pkg/taproot/spend.go
+package taproot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ErrMixedLockTimes is returned when a transaction would need both a block
+// height and a Unix time lock, which a single lock time cannot satisfy
+var ErrMixedLockTimes = errors.New("cannot mix block height and time locks in one transaction")
+
+// SpendBuilder builds script path spends of a taproot output committing to
+// a tree of leaf scripts under an internal key
+type SpendBuilder struct {
+	internalKey *btcec.PublicKey
+	leaves      [][]byte
+	tree        *txscript.IndexedTapScriptTree
+}
+
+// NewSpendBuilder assembles the script tree of leaves under internalKey. The
+// tree is built with txscript.AssembleTaprootScriptTree, as for every contract
+// output, so the control blocks it produces match the output's address.
+func NewSpendBuilder(internalKey string, leaves [][]byte) (*SpendBuilder, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("script tree has no leaves")
+	}
+
+	key, err := ParsePubKey(internalKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid internal key: %w", err)
+	}
+
+	tapLeaves := make([]txscript.TapLeaf, len(leaves))
+	for i, leaf := range leaves {
+		tapLeaves[i] = txscript.NewBaseTapLeaf(leaf)
+	}
+
+	return &SpendBuilder{
+		internalKey: key,
+		leaves:      leaves,
+		tree:        txscript.AssembleTaprootScriptTree(tapLeaves...),
+	}, nil
+}
+
+// OutputKey returns the internal key tweaked by the root of the script tree
+func (b *SpendBuilder) OutputKey() *btcec.PublicKey {
+	root := b.tree.RootNode.TapHash()
+	return txscript.ComputeTaprootOutputKey(b.internalKey, root[:])
+}
+
+// PkScript returns the P2TR output script of the tree
+func (b *SpendBuilder) PkScript() ([]byte, error) {
+	return txscript.PayToTaprootScript(b.OutputKey())
+}
+
+// Address returns the P2TR address of the tree on a network
+func (b *SpendBuilder) Address(params *chaincfg.Params) (string, error) {
+	address, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(b.OutputKey()), params)
+	if err != nil {
+		return "", fmt.Errorf("failed to create taproot address: %w", err)
+	}
+	return address.String(), nil
+}
+
+// Path returns the spend of the leaf at index, in the order the leaves were given
+func (b *SpendBuilder) Path(index int) (*SpendPath, error) {
+	if index < 0 || index >= len(b.leaves) {
+		return nil, fmt.Errorf("leaf index %d out of range, script tree has %d leaves", index, len(b.leaves))
+	}
+
+	controlBlock := b.tree.LeafMerkleProofs[index].ToControlBlock(b.internalKey)
+	controlBlockBytes, err := controlBlock.ToBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize control block: %w", err)
+	}
+
+	path := &SpendPath{
+		LeafIndex:    index,
+		Script:       b.leaves[index],
+		ControlBlock: controlBlockBytes,
+	}
+
+	op, lock, err := leafLock(b.leaves[index])
+	if err != nil {
+		return nil, err
+	}
+	switch op {
+	case txscript.OP_CHECKLOCKTIMEVERIFY:
+		path.LockTime = lock
+	case txscript.OP_CHECKSEQUENCEVERIFY:
+		path.Sequence = lock
+	}
+
+	return path, nil
+}
+
+// SpendPath is a script path spend of one leaf of a script tree
+type SpendPath struct {
+	// LeafIndex is the position of the leaf in the tree's leaves
+	LeafIndex int
+	// Script is the leaf script revealed by the spend
+	Script []byte
+	// ControlBlock proves the leaf is committed to by the output key
+	ControlBlock []byte
+	// LockTime is the block height or Unix time the leaf's
+	// OP_CHECKLOCKTIMEVERIFY requires, zero if it has none
+	LockTime uint32
+	// Sequence is the BIP-68 relative lock the leaf's
+	// OP_CHECKSEQUENCEVERIFY requires, zero if it has none
+	Sequence uint32
+}
+
+// TapLeaf returns the leaf of the path
+func (p *SpendPath) TapLeaf() txscript.TapLeaf {
+	return txscript.NewBaseTapLeaf(p.Script)
+}
+
+// PrepareInput sets the transaction lock time and the input sequence the
+// leaf's lock needs to pass OP_CHECKLOCKTIMEVERIFY or OP_CHECKSEQUENCEVERIFY.
+// It must be called for every input before any of them is signed, as both
+// are committed to by the signature hash.
+func (p *SpendPath) PrepareInput(tx *wire.MsgTx, inputIndex int) error {
+	if inputIndex < 0 || inputIndex >= len(tx.TxIn) {
+		return fmt.Errorf("input index %d out of range", inputIndex)
+	}
+
+	if p.Sequence != 0 {
+		// Relative locks are only enforced from version 2
+		if tx.Version < 2 {
+			return fmt.Errorf("relative lock needs a version 2 transaction, got version %d", tx.Version)
+		}
+		tx.TxIn[inputIndex].Sequence = p.Sequence
+		return nil
+	}
+	if p.LockTime == 0 {
+		return nil
+	}
+
+	isTime := p.LockTime >= txscript.LockTimeThreshold
+	if tx.LockTime != 0 && (tx.LockTime >= txscript.LockTimeThreshold) != isTime {
+		return ErrMixedLockTimes
+	}
+	if p.LockTime > tx.LockTime {
+		tx.LockTime = p.LockTime
+	}
+
+	// A final sequence disables the lock time
+	tx.TxIn[inputIndex].Sequence = wire.MaxTxInSequenceNum - 1
+	return nil
+}
+
+// SigHash returns the BIP-341 signature hash, with the default sighash type,
+// that the leaf's keys sign to spend input inputIndex of tx. prevOuts must
+// return the output spent by every input of tx.
+func (p *SpendPath) SigHash(tx *wire.MsgTx, inputIndex int, prevOuts txscript.PrevOutputFetcher) ([]byte, error) {
+	sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
+	hash, err := txscript.CalcTapscriptSignaturehash(
+		sigHashes, txscript.SigHashDefault, tx, inputIndex, prevOuts, p.TapLeaf())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute signature hash: %w", err)
+	}
+	return hash, nil
+}
+
+// Witness returns the witness stack spending the leaf: the signatures in
+// stack order, followed by the leaf script and its control block
+func (p *SpendPath) Witness(signatures ...[]byte) wire.TxWitness {
+	witness := make(wire.TxWitness, 0, len(signatures)+2)
+	witness = append(witness, signatures...)
+	return append(witness, p.Script, p.ControlBlock)
+}
+
+// leafLock returns the lock opcode and value of a leaf starting with
+// <n> OP_CHECKLOCKTIMEVERIFY or <n> OP_CHECKSEQUENCEVERIFY, or a zero opcode
+// for leaves without one
+func leafLock(script []byte) (byte, uint32, error) {
+	tokenizer := txscript.MakeScriptTokenizer(0, script)
+	if !tokenizer.Next() {
+		return 0, 0, tokenizer.Err()
+	}
+	op, data := tokenizer.Opcode(), tokenizer.Data()
+	if !tokenizer.Next() {
+		return 0, 0, nil
+	}
+	lockOp := tokenizer.Opcode()
+	if lockOp != txscript.OP_CHECKLOCKTIMEVERIFY && lockOp != txscript.OP_CHECKSEQUENCEVERIFY {
+		return 0, 0, nil
+	}
+
+	var lock int64
+	switch {
+	case op >= txscript.OP_1 && op <= txscript.OP_16:
+		lock = int64(op-txscript.OP_1) + 1
+	case len(data) > 0 && len(data) <= 5:
+		lock = scriptNum(data)
+	default:
+		return 0, 0, fmt.Errorf("invalid lock push in leaf script")
+	}
+
+	if lock <= 0 || lock > int64(^uint32(0)) {
+		return 0, 0, fmt.Errorf("lock %d out of range", lock)
+	}
+	return lockOp, uint32(lock), nil
+}
+
+// scriptNum decodes a little-endian script number with a sign bit
+func scriptNum(data []byte) int64 {
+	var n int64
+	for i, b := range data {
+		n |= int64(b) << (8 * i)
+	}
+
+	last := len(data) - 1
+	if data[last]&0x80 != 0 {
+		n &^= int64(0x80) << (8 * last)
+		return -n
+	}
+	return n
+}
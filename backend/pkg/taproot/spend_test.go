@@ -0,0 +1,208 @@
+// pkg/taproot/spend_test.go
+package taproot
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spendingTx returns a transaction spending one output of value to a P2TR output
+func spendingTx(value int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value-500, make([]byte, P2TROutputScriptSize)))
+	return tx
+}
+
+func TestSpendPathVerifies(t *testing.T) {
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	asp, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	buyerPubKey := hex.EncodeToString(buyer.PubKey().SerializeCompressed())
+	sellerPubKey := hex.EncodeToString(seller.PubKey().SerializeCompressed())
+	target := time.Unix(1_900_000_000, 0)
+
+	b := NewScriptBuilder().WithASPPubKey(hex.EncodeToString(asp.PubKey().SerializeCompressed()))
+	setupLeaves, err := b.SetupLeaves(buyerPubKey, sellerPubKey, 850_000, target)
+	require.NoError(t, err)
+	finalLeaves, err := b.FinalLeaves(buyerPubKey, sellerPubKey, 850_000, target, true)
+	require.NoError(t, err)
+	relativeLeaves, err := b.RelativeSetupLeaves(buyerPubKey, sellerPubKey, 144, 86_400)
+	require.NoError(t, err)
+
+	const value = 100_000
+
+	for _, tc := range []struct {
+		name   string
+		leaves [][]byte
+		leaf   int
+		// signers are the keys signing each witness item in stack order,
+		// nil for an empty signature
+		signers []*btcec.PrivateKey
+	}{
+		{"setup cooperative", setupLeaves, 0, []*btcec.PrivateKey{seller, buyer}},
+		{"setup high hash rate", setupLeaves, 1, []*btcec.PrivateKey{buyer}},
+		{"setup low hash rate", setupLeaves, 2, []*btcec.PrivateKey{seller}},
+		{"final high hash rate", finalLeaves, 0, []*btcec.PrivateKey{buyer}},
+		{"final low hash rate", finalLeaves, 1, []*btcec.PrivateKey{seller}},
+		{"final dispute by buyer and seller", finalLeaves, 2, []*btcec.PrivateKey{nil, seller, buyer}},
+		{"final dispute by seller and ASP", finalLeaves, 2, []*btcec.PrivateKey{asp, seller, nil}},
+		{"relative cooperative", relativeLeaves, 0, []*btcec.PrivateKey{seller, buyer}},
+		{"relative high hash rate", relativeLeaves, 1, []*btcec.PrivateKey{buyer}},
+		{"relative low hash rate", relativeLeaves, 2, []*btcec.PrivateKey{seller}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spender, err := NewSpendBuilder(buyerPubKey, tc.leaves)
+			require.NoError(t, err)
+			pkScript, err := spender.PkScript()
+			require.NoError(t, err)
+			prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, value)
+
+			path, err := spender.Path(tc.leaf)
+			require.NoError(t, err)
+
+			// execute signs tx with signers and runs the script engine on it
+			execute := func(tx *wire.MsgTx, signers []*btcec.PrivateKey) error {
+				sigHash, err := path.SigHash(tx, 0, prevOuts)
+				require.NoError(t, err)
+				sigs := make([][]byte, len(signers))
+				for i, key := range signers {
+					sigs[i] = []byte{}
+					if key != nil {
+						sig, err := schnorr.Sign(key, sigHash)
+						require.NoError(t, err)
+						sigs[i] = sig.Serialize()
+					}
+				}
+				tx.TxIn[0].Witness = path.Witness(sigs...)
+
+				engine, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags,
+					nil, txscript.NewTxSigHashes(tx, prevOuts), value, prevOuts)
+				require.NoError(t, err)
+				return engine.Execute()
+			}
+
+			tx := spendingTx(value)
+			require.NoError(t, path.PrepareInput(tx, 0))
+			assert.NoError(t, execute(tx, tc.signers))
+
+			// One signature short of the threshold does not satisfy the leaf
+			short := append([]*btcec.PrivateKey{}, tc.signers...)
+			for i := range short {
+				if short[i] != nil {
+					short[i] = nil
+					break
+				}
+			}
+			assert.Error(t, execute(tx, short))
+
+			// Nor does a lock earlier than the leaf's
+			switch {
+			case path.LockTime != 0:
+				assert.Equal(t, uint32(wire.MaxTxInSequenceNum-1), tx.TxIn[0].Sequence)
+				tx.LockTime = path.LockTime - 1
+				assert.Error(t, execute(tx, tc.signers))
+			case path.Sequence != 0:
+				tx.TxIn[0].Sequence = path.Sequence - 1
+				assert.Error(t, execute(tx, tc.signers))
+			}
+		})
+	}
+
+	spender, err := NewSpendBuilder(buyerPubKey, setupLeaves)
+	require.NoError(t, err)
+
+	t.Run("wrong control block", func(t *testing.T) {
+		pkScript, err := spender.PkScript()
+		require.NoError(t, err)
+		prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, value)
+		path, err := spender.Path(1)
+		require.NoError(t, err)
+		other, err := spender.Path(2)
+		require.NoError(t, err)
+
+		tx := spendingTx(value)
+		require.NoError(t, path.PrepareInput(tx, 0))
+		sigHash, err := path.SigHash(tx, 0, prevOuts)
+		require.NoError(t, err)
+		sig, err := schnorr.Sign(buyer, sigHash)
+		require.NoError(t, err)
+
+		path.ControlBlock = other.ControlBlock
+		tx.TxIn[0].Witness = path.Witness(sig.Serialize())
+		engine, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags,
+			nil, txscript.NewTxSigHashes(tx, prevOuts), value, prevOuts)
+		require.NoError(t, err)
+		assert.Error(t, engine.Execute())
+	})
+
+	t.Run("leaf out of range", func(t *testing.T) {
+		_, err := spender.Path(len(setupLeaves))
+		assert.Error(t, err)
+	})
+}
+
+func TestPrepareInputRejectsMixedLocks(t *testing.T) {
+	tx := spendingTx(100_000)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{2}, Index: 0}, nil, nil))
+
+	height := &SpendPath{LockTime: 850_000}
+	unixTime := &SpendPath{LockTime: 1_900_000_000}
+	require.NoError(t, height.PrepareInput(tx, 0))
+	assert.ErrorIs(t, unixTime.PrepareInput(tx, 1), ErrMixedLockTimes)
+
+	// A path without a lock leaves the input final
+	require.NoError(t, (&SpendPath{}).PrepareInput(tx, 1))
+	assert.Equal(t, uint32(wire.MaxTxInSequenceNum), tx.TxIn[1].Sequence)
+}
+
+func TestContractOutputsMatchSpendBuilder(t *testing.T) {
+	buyer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	seller, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	buyerPubKey := hex.EncodeToString(buyer.PubKey().SerializeCompressed())
+	sellerPubKey := hex.EncodeToString(seller.PubKey().SerializeCompressed())
+	target := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	b := NewScriptBuilder()
+	setupAddress, err := b.BuildSetupScript(buyerPubKey, sellerPubKey, 840_000, 850_000, target, true)
+	require.NoError(t, err)
+	setupLeaves, err := b.SetupLeaves(buyerPubKey, sellerPubKey, 850_000, target)
+	require.NoError(t, err)
+	spender, err := NewSpendBuilder(buyerPubKey, setupLeaves)
+	require.NoError(t, err)
+	address, err := spender.Address(b.Params())
+	require.NoError(t, err)
+	assert.Equal(t, setupAddress, address)
+
+	// The high and low hash rate paths lock to the end height and target time
+	highPath, err := spender.Path(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(850_000), highPath.LockTime)
+	lowPath, err := spender.Path(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(target.Unix()), lowPath.LockTime)
+
+	finalAddress, err := b.BuildFinalScript(buyerPubKey, sellerPubKey, 850_000, target, true)
+	require.NoError(t, err)
+	finalLeaves, err := b.FinalLeaves(buyerPubKey, sellerPubKey, 850_000, target, true)
+	require.NoError(t, err)
+	spender, err = NewSpendBuilder(buyerPubKey, finalLeaves)
+	require.NoError(t, err)
+	address, err = spender.Address(b.Params())
+	require.NoError(t, err)
+	assert.Equal(t, finalAddress, address)
+}
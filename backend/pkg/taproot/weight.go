@@ -0,0 +1,209 @@
+// pkg/taproot/weight.go
+package taproot
+
+import (
+	"math"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// Serialized sizes in bytes used for weight estimation
+const (
+	// SchnorrSigSize is a BIP-340 signature with the default sighash type
+	SchnorrSigSize = 64
+	// ECDSASigSize is a worst case DER signature including the sighash byte
+	ECDSASigSize = 72
+	// CompressedPubKeySize is a compressed secp256k1 public key
+	CompressedPubKeySize = 33
+	// ControlBlockBaseSize is the leaf version byte plus the x-only internal key
+	ControlBlockBaseSize = 33
+	// MerkleBranchSize is one hash of a control block's inclusion proof
+	MerkleBranchSize = 32
+
+	// Output script sizes
+	P2TROutputScriptSize   = 34
+	P2WSHOutputScriptSize  = 34
+	P2WPKHOutputScriptSize = 22
+	P2SHOutputScriptSize   = 23
+	P2PKHOutputScriptSize  = 25
+
+	// P2PKHScriptSigSize is a signature push and a compressed public key push
+	P2PKHScriptSigSize = 1 + ECDSASigSize + 1 + CompressedPubKeySize
+
+	// WitnessScaleFactor is the weight of a non-witness byte
+	WitnessScaleFactor = 4
+
+	// txOverhead is the version and lock time
+	txOverhead = 4 + 4
+	// segwitMarkerSize is the marker and flag bytes of a witness transaction
+	segwitMarkerSize = 2
+	// inputOverhead is the outpoint and sequence of an input
+	inputOverhead = 36 + 4
+	// outputValueSize is the amount of an output
+	outputValueSize = 8
+)
+
+// Spend describes how a transaction input is satisfied
+type Spend struct {
+	// ScriptSigSize is the size of the input's scriptSig, zero for segwit spends
+	ScriptSigSize int
+	// Witness holds the size of each witness stack item
+	Witness []int
+}
+
+// KeyPathSpend is a taproot key path spend with a single Schnorr signature
+func KeyPathSpend() Spend {
+	return Spend{Witness: []int{SchnorrSigSize}}
+}
+
+// ScriptPathSpend is a taproot script path spend of a leaf at the given depth
+// of the script tree, satisfied by numSigs Schnorr signatures
+func ScriptPathSpend(script []byte, numSigs, depth int) Spend {
+	witness := make([]int, 0, numSigs+2)
+	for i := 0; i < numSigs; i++ {
+		witness = append(witness, SchnorrSigSize)
+	}
+	witness = append(witness, len(script), ControlBlockBaseSize+depth*MerkleBranchSize)
+	return Spend{Witness: witness}
+}
+
+// P2WPKHSpend is a native segwit v0 key hash spend
+func P2WPKHSpend() Spend {
+	return Spend{Witness: []int{ECDSASigSize, CompressedPubKeySize}}
+}
+
+// P2PKHSpend is a legacy key hash spend
+func P2PKHSpend() Spend {
+	return Spend{ScriptSigSize: P2PKHScriptSigSize}
+}
+
+// LeafSpends returns a script path spend for every leaf, in the order given.
+// The leaves are assembled with txscript.AssembleTaprootScriptTree, so each
+// spend carries the control block of the leaf's actual depth.
+func LeafSpends(leaves [][]byte) []Spend {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	tapLeaves := make([]txscript.TapLeaf, len(leaves))
+	for i, script := range leaves {
+		tapLeaves[i] = txscript.NewBaseTapLeaf(script)
+	}
+	tree := txscript.AssembleTaprootScriptTree(tapLeaves...)
+
+	spends := make([]Spend, len(leaves))
+	for i, script := range leaves {
+		proof := tree.LeafMerkleProofs[i]
+		depth := len(proof.InclusionProof) / MerkleBranchSize
+		spends[i] = ScriptPathSpend(script, CountSignatures(script), depth)
+	}
+	return spends
+}
+
+// CountSignatures returns the number of signatures needed to satisfy a
+// script: one per CHECKSIG, CHECKSIGVERIFY or CHECKSIGADD, and m for an
+// m-of-n CHECKMULTISIG
+func CountSignatures(script []byte) int {
+	count := 0
+	// The first small integer since the last multisig is its threshold m
+	threshold := -1
+
+	tokenizer := txscript.MakeScriptTokenizer(0, script)
+	for tokenizer.Next() {
+		op := tokenizer.Opcode()
+		switch {
+		case op == txscript.OP_CHECKSIG || op == txscript.OP_CHECKSIGVERIFY || op == txscript.OP_CHECKSIGADD:
+			count++
+		case op == txscript.OP_CHECKMULTISIG || op == txscript.OP_CHECKMULTISIGVERIFY:
+			if threshold > 0 {
+				count += threshold
+			}
+			threshold = -1
+		case op >= txscript.OP_1 && op <= txscript.OP_16:
+			if threshold < 0 {
+				threshold = int(op-txscript.OP_1) + 1
+			}
+		}
+	}
+	return count
+}
+
+// MaxSpend returns the heaviest of the given spends, for estimating a fee
+// before it is known which path will be used
+func MaxSpend(spends ...Spend) Spend {
+	var heaviest Spend
+	for i, s := range spends {
+		if i == 0 || s.Weight() > heaviest.Weight() {
+			heaviest = s
+		}
+	}
+	return heaviest
+}
+
+// Weight returns the weight of the input, including its outpoint and sequence
+func (s Spend) Weight() int64 {
+	nonWitness := inputOverhead + varIntSize(s.ScriptSigSize) + s.ScriptSigSize
+	return int64(nonWitness*WitnessScaleFactor + s.witnessSize())
+}
+
+// witnessSize returns the serialized size of the witness stack, which is a
+// single zero byte for inputs without a witness
+func (s Spend) witnessSize() int {
+	size := varIntSize(len(s.Witness))
+	for _, item := range s.Witness {
+		size += varIntSize(item) + item
+	}
+	return size
+}
+
+// TxWeight returns the weight of a transaction spending the given inputs
+// to outputs with the given script sizes
+func TxWeight(spends []Spend, outputScriptSizes []int) int64 {
+	nonWitness := txOverhead + varIntSize(len(spends)) + varIntSize(len(outputScriptSizes))
+	for _, size := range outputScriptSizes {
+		nonWitness += outputValueSize + varIntSize(size) + size
+	}
+	weight := int64(nonWitness * WitnessScaleFactor)
+
+	hasWitness := false
+	for _, s := range spends {
+		weight += s.Weight()
+		if len(s.Witness) > 0 {
+			hasWitness = true
+		}
+	}
+
+	if !hasWitness {
+		// Without any witness the marker, flag and empty stacks are not serialized
+		for _, s := range spends {
+			weight -= int64(s.witnessSize())
+		}
+		return weight
+	}
+	return weight + segwitMarkerSize
+}
+
+// VSize converts a weight to virtual bytes, rounding up
+func VSize(weight int64) int64 {
+	return (weight + WitnessScaleFactor - 1) / WitnessScaleFactor
+}
+
+// Fee returns the fee in satoshis for a transaction of the given weight at a
+// fee rate in sat/vB, rounded up
+func Fee(weight int64, feeRate float64) int64 {
+	return int64(math.Ceil(float64(VSize(weight)) * feeRate))
+}
+
+// varIntSize returns the size of a Bitcoin compact size integer
+func varIntSize(n int) int {
+	switch {
+	case n < 0xfd:
+		return 1
+	case n <= 0xffff:
+		return 3
+	case n <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
@@ -0,0 +1,139 @@
+// pkg/taproot/weight_test.go
+package taproot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/stretchr/testify/assert"
+)
+
+// checksigLeaf is a 34 byte tapscript: <32 byte x-only key> OP_CHECKSIG
+func checksigLeaf(fill byte) []byte {
+	script := append([]byte{txscript.OP_DATA_32}, bytes.Repeat([]byte{fill}, 32)...)
+	return append(script, txscript.OP_CHECKSIG)
+}
+
+func TestInputWeights(t *testing.T) {
+	tests := []struct {
+		name   string
+		spend  Spend
+		weight int64
+	}{
+		// 41 non-witness bytes, witness: count + length + 64 byte signature
+		{"taproot key path", KeyPathSpend(), 164 + 66},
+		// witness: count + (1+72) signature + (1+33) key
+		{"p2wpkh", P2WPKHSpend(), 164 + 108},
+		// 148 non-witness bytes, empty witness counted as one byte
+		{"p2pkh", P2PKHSpend(), 148*4 + 1},
+		// witness: count + (1+64) signature + (1+34) script + (1+33) control block
+		{"script path at root", ScriptPathSpend(checksigLeaf(1), 1, 0), 164 + 135},
+		// each level of the tree adds a 32 byte branch to the control block
+		{"script path at depth 2", ScriptPathSpend(checksigLeaf(1), 1, 2), 164 + 135 + 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.weight, tt.spend.Weight())
+		})
+	}
+}
+
+func TestTxWeight(t *testing.T) {
+	tests := []struct {
+		name    string
+		spends  []Spend
+		outputs []int
+		weight  int64
+		vsize   int64
+	}{
+		{
+			name:    "p2tr key path, one p2tr output",
+			spends:  []Spend{KeyPathSpend()},
+			outputs: []int{P2TROutputScriptSize},
+			weight:  444,
+			vsize:   111,
+		},
+		{
+			name:    "p2tr key path, two p2tr outputs",
+			spends:  []Spend{KeyPathSpend()},
+			outputs: []int{P2TROutputScriptSize, P2TROutputScriptSize},
+			weight:  616,
+			vsize:   154,
+		},
+		{
+			name:    "p2wpkh, two p2wpkh outputs",
+			spends:  []Spend{P2WPKHSpend()},
+			outputs: []int{P2WPKHOutputScriptSize, P2WPKHOutputScriptSize},
+			weight:  562,
+			vsize:   141,
+		},
+		{
+			name:    "legacy p2pkh has no witness overhead",
+			spends:  []Spend{P2PKHSpend()},
+			outputs: []int{P2PKHOutputScriptSize},
+			weight:  192 * 4,
+			vsize:   192,
+		},
+		{
+			name:    "two p2tr key path inputs, one p2tr output",
+			spends:  []Spend{KeyPathSpend(), KeyPathSpend()},
+			outputs: []int{P2TROutputScriptSize},
+			weight:  674,
+			vsize:   169,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weight := TxWeight(tt.spends, tt.outputs)
+			assert.Equal(t, tt.weight, weight)
+			assert.Equal(t, tt.vsize, VSize(weight))
+		})
+	}
+}
+
+func TestCountSignatures(t *testing.T) {
+	key := append([]byte{txscript.OP_DATA_32}, bytes.Repeat([]byte{2}, 32)...)
+	height := []byte{0x03, 0x40, 0x0d, 0x03} // push of 200000
+
+	tests := []struct {
+		name   string
+		script []byte
+		want   int
+	}{
+		{"checksig", checksigLeaf(1), 1},
+		{"timelocked checksig", append(append(append(height, txscript.OP_CHECKLOCKTIMEVERIFY, txscript.OP_DROP), key...), txscript.OP_CHECKSIG), 1},
+		{"2-of-2 checksigadd", append(append(append(append([]byte{}, key...), txscript.OP_CHECKSIG), key...), txscript.OP_CHECKSIGADD, txscript.OP_1+1, 0x87), 2},
+		{"2-of-3 checkmultisig", append(append(append(append([]byte{txscript.OP_1 + 1}, key...), key...), key...), txscript.OP_1+2, txscript.OP_CHECKMULTISIG), 2},
+		{"no signatures", []byte{txscript.OP_1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CountSignatures(tt.script))
+		})
+	}
+}
+
+func TestLeafSpends(t *testing.T) {
+	leaves := [][]byte{checksigLeaf(1), checksigLeaf(2), checksigLeaf(3)}
+
+	spends := LeafSpends(leaves)
+	assert.Len(t, spends, 3)
+
+	// Three leaves assemble into a tree with two leaves at depth 2 and one at depth 1
+	assert.Equal(t, ScriptPathSpend(leaves[0], 1, 2), spends[0])
+	assert.Equal(t, ScriptPathSpend(leaves[1], 1, 2), spends[1])
+	assert.Equal(t, ScriptPathSpend(leaves[2], 1, 1), spends[2])
+
+	assert.Equal(t, spends[0], MaxSpend(spends[2], spends[0]))
+	assert.Len(t, LeafSpends(nil), 0)
+}
+
+func TestFee(t *testing.T) {
+	assert.Equal(t, int64(555), Fee(444, 5))
+	assert.Equal(t, int64(167), Fee(444, 1.5))
+	assert.Equal(t, int64(112), VSize(445))
+}